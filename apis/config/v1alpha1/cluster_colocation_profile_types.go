@@ -74,6 +74,13 @@ type ClusterColocationProfileSpec struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 
+	// NUMATopologyPolicy describes how to align resource allocation according to the NUMA topology.
+	// The value will be injected into Pod as annotation scheduling.koordinator.sh/numa-topology-spec
+	// if the Pod does not specify it.
+	// +kubebuilder:validation:Enum=BestEffort;Restricted;SingleNUMANode
+	// +optional
+	NUMATopologyPolicy string `json:"numaTopologyPolicy,omitempty"`
+
 	// If specified, the pod will be dispatched by specified scheduler.
 	// +optional
 	SchedulerName string `json:"schedulerName,omitempty"`
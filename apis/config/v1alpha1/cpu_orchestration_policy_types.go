@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CPUOrchestrationPolicySpec describes how to orchestrate the CPUs of the Pods referencing the policy.
+// The Pods reference the policy by the label koordinator.sh/cpu-orchestration-policy, and the policy
+// will be injected into the Pod as annotations scheduling.koordinator.sh/resource-spec and
+// scheduling.koordinator.sh/numa-topology-spec if the Pod does not specify them.
+type CPUOrchestrationPolicySpec struct {
+	// CPUBindPolicy describes how to bind the logical CPUs of the Pod.
+	// +kubebuilder:validation:Enum=Default;FullPCPUs;SpreadByPCPUs;ConstrainedBurst
+	// +optional
+	CPUBindPolicy string `json:"cpuBindPolicy,omitempty"`
+
+	// RequiredCPUBindPolicy indicates that the CPU must be allocated strictly according to the CPUBindPolicy,
+	// otherwise the scheduling fails.
+	// +optional
+	RequiredCPUBindPolicy bool `json:"requiredCPUBindPolicy,omitempty"`
+
+	// CPUExclusivePolicy describes the best-effort mutual exclusion between the Pods with the same policy.
+	// +kubebuilder:validation:Enum=None;PCPULevel;NUMANodeLevel
+	// +optional
+	CPUExclusivePolicy string `json:"cpuExclusivePolicy,omitempty"`
+
+	// NUMATopologyPolicy describes how to align resource allocation according to the NUMA topology.
+	// +kubebuilder:validation:Enum=BestEffort;Restricted;SingleNUMANode
+	// +optional
+	NUMATopologyPolicy string `json:"numaTopologyPolicy,omitempty"`
+
+	// NUMAAllocateStrategy describes the iteration order of the NUMA Nodes when allocating CPUs.
+	// It overrides the strategy of the node and the koord-scheduler configuration.
+	// +kubebuilder:validation:Enum=MostAllocated;LeastAllocated
+	// +optional
+	NUMAAllocateStrategy string `json:"numaAllocateStrategy,omitempty"`
+
+	// IRQSteeringPolicy describes whether koordlet steers the device interrupts away from the bound CPUs.
+	// +kubebuilder:validation:Enum=None;Isolated
+	// +optional
+	IRQSteeringPolicy string `json:"irqSteeringPolicy,omitempty"`
+}
+
+// CPUOrchestrationPolicyStatus represents information about the status of a CPUOrchestrationPolicy.
+type CPUOrchestrationPolicyStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AdoptedPods is the number of non-terminated Pods referencing the policy.
+	// +optional
+	AdoptedPods int32 `json:"adoptedPods,omitempty"`
+
+	// LastUpdateTime is the last time the status was updated.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster,shortName=cpuop
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="BindPolicy",type="string",JSONPath=".spec.cpuBindPolicy"
+// +kubebuilder:printcolumn:name="NUMAPolicy",type="string",JSONPath=".spec.numaTopologyPolicy"
+// +kubebuilder:printcolumn:name="AdoptedPods",type="integer",JSONPath=".status.adoptedPods"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CPUOrchestrationPolicy is the Schema for the CPUOrchestrationPolicy API
+type CPUOrchestrationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CPUOrchestrationPolicySpec   `json:"spec,omitempty"`
+	Status            CPUOrchestrationPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CPUOrchestrationPolicyList contains a list of CPUOrchestrationPolicy
+type CPUOrchestrationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CPUOrchestrationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CPUOrchestrationPolicy{}, &CPUOrchestrationPolicyList{})
+}
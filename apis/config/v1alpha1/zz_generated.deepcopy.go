@@ -27,6 +27,99 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOrchestrationPolicy) DeepCopyInto(out *CPUOrchestrationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOrchestrationPolicy.
+func (in *CPUOrchestrationPolicy) DeepCopy() *CPUOrchestrationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOrchestrationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CPUOrchestrationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOrchestrationPolicyList) DeepCopyInto(out *CPUOrchestrationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CPUOrchestrationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOrchestrationPolicyList.
+func (in *CPUOrchestrationPolicyList) DeepCopy() *CPUOrchestrationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOrchestrationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CPUOrchestrationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOrchestrationPolicySpec) DeepCopyInto(out *CPUOrchestrationPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOrchestrationPolicySpec.
+func (in *CPUOrchestrationPolicySpec) DeepCopy() *CPUOrchestrationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOrchestrationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOrchestrationPolicyStatus) DeepCopyInto(out *CPUOrchestrationPolicyStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOrchestrationPolicyStatus.
+func (in *CPUOrchestrationPolicyStatus) DeepCopy() *CPUOrchestrationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOrchestrationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterColocationProfile) DeepCopyInto(out *ClusterColocationProfile) {
 	*out = *in
@@ -214,6 +214,14 @@ type ColocationStrategy struct {
 	MetricReportIntervalSeconds    *int64                               `json:"metricReportIntervalSeconds,omitempty" validate:"omitempty,min=1"`
 	MetricAggregatePolicy          *slov1alpha1.AggregatePolicy         `json:"metricAggregatePolicy,omitempty"`
 	MetricMemoryCollectPolicy      *slov1alpha1.NodeMemoryCollectPolicy `json:"metricMemoryCollectPolicy,omitempty"`
+	// MetricHealthIndexWeights defines the weights of the interference signals composing the node health index.
+	MetricHealthIndexWeights *slov1alpha1.HealthIndexWeights `json:"metricHealthIndexWeights,omitempty"`
+	// MetricColdMemoryReportIntervalSeconds defines the period to report the node cold memory in NodeMetric.
+	// The cold memory is not reported if it is nil or zero.
+	MetricColdMemoryReportIntervalSeconds *int64 `json:"metricColdMemoryReportIntervalSeconds,omitempty" validate:"omitempty,min=0"`
+	// MetricCPUStealAnomalyThresholdPercent defines the CPU steal percent from which the node reports the CPUStealAnomaly
+	// condition in NodeMetric.
+	MetricCPUStealAnomalyThresholdPercent *int64 `json:"metricCPUStealAnomalyThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 
 	CPUReclaimThresholdPercent    *int64           `json:"cpuReclaimThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 	MemoryReclaimThresholdPercent *int64           `json:"memoryReclaimThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
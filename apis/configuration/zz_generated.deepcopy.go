@@ -154,6 +154,21 @@ func (in *ColocationStrategy) DeepCopyInto(out *ColocationStrategy) {
 		*out = new(v1alpha1.NodeMemoryCollectPolicy)
 		**out = **in
 	}
+	if in.MetricHealthIndexWeights != nil {
+		in, out := &in.MetricHealthIndexWeights, &out.MetricHealthIndexWeights
+		*out = new(v1alpha1.HealthIndexWeights)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricColdMemoryReportIntervalSeconds != nil {
+		in, out := &in.MetricColdMemoryReportIntervalSeconds, &out.MetricColdMemoryReportIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MetricCPUStealAnomalyThresholdPercent != nil {
+		in, out := &in.MetricCPUStealAnomalyThresholdPercent, &out.MetricCPUStealAnomalyThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.CPUReclaimThresholdPercent != nil {
 		in, out := &in.CPUReclaimThresholdPercent, &out.CPUReclaimThresholdPercent
 		*out = new(int64)
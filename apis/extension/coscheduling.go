@@ -49,6 +49,10 @@ const (
 	// The annotation is added by the scheduler when the gang times out
 	AnnotationGangTimeout = AnnotationGangPrefix + "/timeout"
 
+	// AnnotationGangQuotaExceeded means that the gang can never be scheduled since its min resources exceed the max of its quota
+	// The annotation is added by the PodGroupController of the scheduler with the detailed reason and removed once the quota can hold the gang
+	AnnotationGangQuotaExceeded = AnnotationGangPrefix + "/quota-exceeded"
+
 	GangModeStrict    = "Strict"
 	GangModeNonStrict = "NonStrict"
 
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+)
+
+const (
+	// AnnotationNodeHousekeepingCPUs describes the housekeeping CPUs of the node.
+	// It is configured on the Node and reported to the NodeResourceTopology by koordlet.
+	AnnotationNodeHousekeepingCPUs = NodeDomainPrefix + "/housekeeping-cpus"
+)
+
+// HousekeepingCPUs describes the CPUs reserved for the kernel threads, IRQs and the per-cpu kthreads,
+// e.g. the CPUs out of the `nohz_full` and `rcu_nocbs` which take the rcu callbacks and timer ticks.
+// The housekeeping CPUs are excluded from the exclusive allocations of the LSE/LSR Pods and the LS share pool,
+// but they can be still used by the BE Pods.
+//
+//	 annotations:
+//	   node.koordinator.sh/housekeeping-cpus: >-
+//		    {"cpuset":"0-1"}
+type HousekeepingCPUs struct {
+	// CPU cores used for housekeeping, format should follow Linux CPU list
+	// See: http://man7.org/linux/man-pages/man7/cpuset.7.html#FORMATS
+	CPUSet string `json:"cpuset,omitempty"`
+}
+
+// GetHousekeepingCPUs parses HousekeepingCPUs from annotations.
+// It returns nil without an error when the annotation is missing.
+func GetHousekeepingCPUs(annotations map[string]string) (*HousekeepingCPUs, error) {
+	data, ok := annotations[AnnotationNodeHousekeepingCPUs]
+	if !ok {
+		return nil, nil
+	}
+	housekeepingCPUs := &HousekeepingCPUs{}
+	if err := json.Unmarshal([]byte(data), housekeepingCPUs); err != nil {
+		return nil, err
+	}
+	return housekeepingCPUs, nil
+}
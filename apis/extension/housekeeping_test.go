@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHousekeepingCPUs(t *testing.T) {
+	tests := []struct {
+		name    string
+		anno    map[string]string
+		want    *HousekeepingCPUs
+		wantErr bool
+	}{
+		{
+			name: "annotation key not exist",
+			anno: map[string]string{},
+			want: nil,
+		},
+		{
+			name: "bad json format",
+			anno: map[string]string{
+				AnnotationNodeHousekeepingCPUs: "bad-format-str",
+			},
+			wantErr: true,
+		},
+		{
+			name: "parse format succeed",
+			anno: map[string]string{
+				AnnotationNodeHousekeepingCPUs: `{"cpuset":"0-1"}`,
+			},
+			want: &HousekeepingCPUs{
+				CPUSet: "0-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetHousekeepingCPUs(tt.anno)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
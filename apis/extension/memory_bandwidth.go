@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ResourceMemoryBandwidth is the extended resource of the memory bandwidth in MB/s.
+	// koordlet reports it for each NUMA node in the NodeResourceTopology, koord-scheduler allocates it in the
+	// NUMANodeResources of the pod, and koordlet limits the pods with the MBA (Memory Bandwidth Allocation) in best effort.
+	ResourceMemoryBandwidth corev1.ResourceName = DomainPrefix + "memory-bandwidth"
+
+	// AnnotationNodeMemoryBandwidth describes the measured maximum memory bandwidth (MB/s) of each NUMA node.
+	// e.g. `{"0": 20000, "1": 20000}`
+	AnnotationNodeMemoryBandwidth = NodeDomainPrefix + "/memory-bandwidth"
+)
+
+// NUMAMemoryBandwidth is the maximum memory bandwidth in MB/s indexed by the NUMA node id.
+type NUMAMemoryBandwidth map[int]int64
+
+// Total returns the sum of the memory bandwidth of all NUMA nodes.
+func (b NUMAMemoryBandwidth) Total() int64 {
+	var total int64
+	for _, bandwidth := range b {
+		total += bandwidth
+	}
+	return total
+}
+
+func GetNodeMemoryBandwidth(annotations map[string]string) (NUMAMemoryBandwidth, error) {
+	data, ok := annotations[AnnotationNodeMemoryBandwidth]
+	if !ok {
+		return nil, nil
+	}
+	bandwidth := NUMAMemoryBandwidth{}
+	if err := json.Unmarshal([]byte(data), &bandwidth); err != nil {
+		return nil, err
+	}
+	return bandwidth, nil
+}
+
+// GetPodMemoryBandwidthRequest returns the memory bandwidth in MB/s requested by the containers of the pod.
+func GetPodMemoryBandwidthRequest(pod *corev1.Pod) int64 {
+	var request int64
+	for i := range pod.Spec.Containers {
+		if q, ok := pod.Spec.Containers[i].Resources.Requests[ResourceMemoryBandwidth]; ok {
+			request += q.Value()
+		}
+	}
+	return request
+}
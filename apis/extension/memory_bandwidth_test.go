@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetNodeMemoryBandwidth(t *testing.T) {
+	tests := []struct {
+		name    string
+		anno    map[string]string
+		want    NUMAMemoryBandwidth
+		wantErr bool
+	}{
+		{
+			name: "annotation not exist",
+			anno: map[string]string{},
+			want: nil,
+		},
+		{
+			name: "bad json format",
+			anno: map[string]string{
+				AnnotationNodeMemoryBandwidth: "bad-format-str",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "parse format succeed",
+			anno: map[string]string{
+				AnnotationNodeMemoryBandwidth: `{"0": 20000, "1": 18000}`,
+			},
+			want: NUMAMemoryBandwidth{
+				0: 20000,
+				1: 18000,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNodeMemoryBandwidth(tt.anno)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Equal(t, int64(38000), NUMAMemoryBandwidth{0: 20000, 1: 18000}.Total())
+}
+
+func TestGetPodMemoryBandwidthRequest(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "a",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:      resource.MustParse("2"),
+							ResourceMemoryBandwidth: resource.MustParse("1000"),
+						},
+					},
+				},
+				{
+					Name: "b",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							ResourceMemoryBandwidth: resource.MustParse("500"),
+						},
+					},
+				},
+				{
+					Name: "c",
+				},
+			},
+		},
+	}
+	assert.Equal(t, int64(1500), GetPodMemoryBandwidthRequest(pod))
+	assert.Equal(t, int64(0), GetPodMemoryBandwidthRequest(&corev1.Pod{}))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ResourceDRAM is the extended resource of the memory on the NUMA nodes with CPUs, i.e. the fast memory tier.
+	ResourceDRAM corev1.ResourceName = DomainPrefix + "dram"
+	// ResourceSlowMemory is the extended resource of the memory on the CPU-less NUMA nodes, e.g. the PMEM and the
+	// CXL memory expanders, i.e. the slow memory tier.
+	// koordlet reports the memory tiers for each NUMA node in the NodeResourceTopology, koord-scheduler allocates
+	// them in the NUMANodeResources of the pod, and koordlet binds the memory of the pod to the allocated NUMA nodes.
+	ResourceSlowMemory corev1.ResourceName = DomainPrefix + "slow-memory"
+)
+
+// IsMemoryTierResource returns whether the resource is one of the heterogeneous memory tiers.
+func IsMemoryTierResource(resourceName corev1.ResourceName) bool {
+	return resourceName == ResourceDRAM || resourceName == ResourceSlowMemory
+}
+
+// GetMemoryTierNUMANodes returns the sorted NUMA nodes where the memory tiers are allocated to the pod.
+// It returns nil if the pod requests no memory tier.
+func GetMemoryTierNUMANodes(status *ResourceStatus) []int {
+	if status == nil {
+		return nil
+	}
+	var nodes []int
+	for _, numaNodeResource := range status.NUMANodeResources {
+		for resourceName, quantity := range numaNodeResource.Resources {
+			if IsMemoryTierResource(resourceName) && !quantity.IsZero() {
+				nodes = append(nodes, int(numaNodeResource.Node))
+				break
+			}
+		}
+	}
+	sort.Ints(nodes)
+	return nodes
+}
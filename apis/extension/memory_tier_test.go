@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetMemoryTierNUMANodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *ResourceStatus
+		want   []int
+	}{
+		{
+			name:   "nil status",
+			status: nil,
+			want:   nil,
+		},
+		{
+			name: "no memory tier requested",
+			status: &ResourceStatus{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("4"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "dram and slow memory allocated",
+			status: &ResourceStatus{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 2,
+						Resources: corev1.ResourceList{
+							ResourceSlowMemory: resource.MustParse("16Gi"),
+						},
+					},
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+							ResourceDRAM:       resource.MustParse("8Gi"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+							ResourceDRAM:       resource.MustParse("0"),
+						},
+					},
+				},
+			},
+			want: []int{0, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetMemoryTierNUMANodes(tt.status)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+)
+
+const (
+	// AnnotationNodeNICs describes the physical NICs of the node with their NUMA Nodes, speeds and SR-IOV capabilities.
+	// It is reported to the NodeResourceTopology by koordlet.
+	AnnotationNodeNICs = NodeDomainPrefix + "/nics"
+
+	// LabelNICName is the interface name of the NIC in the Device.
+	LabelNICName = NodeDomainPrefix + "/nic-name"
+	// LabelNICSpeed is the link speed of the NIC in Mbps in the Device.
+	LabelNICSpeed = NodeDomainPrefix + "/nic-speed-mbps"
+)
+
+// NICDevice describes a physical NIC of the node.
+type NICDevice struct {
+	// Name is the interface name, e.g. eth0
+	Name string `json:"name"`
+	// BusID is the PCI address of the NIC, e.g. 0000:3b:00.0
+	BusID string `json:"busID"`
+	// NUMANode is the NUMA Node that the NIC attaches to, or -1 if unknown.
+	NUMANode int32 `json:"numaNode"`
+	// SpeedMbps is the link speed in Mbps, or -1 if unknown (e.g. the link is down).
+	SpeedMbps int64 `json:"speedMbps"`
+	// OperState is the operational state of the link, e.g. up, down
+	OperState string `json:"operState,omitempty"`
+	// TotalVFs is the max number of the VFs if the NIC is SR-IOV capable.
+	TotalVFs int32 `json:"totalVFs,omitempty"`
+	// NumVFs is the number of the VFs enabled on the NIC.
+	NumVFs int32 `json:"numVFs,omitempty"`
+}
+
+// IsLinkUp checks whether the link of the NIC is up.
+func (d *NICDevice) IsLinkUp() bool {
+	return d.OperState == "up"
+}
+
+// GetNICDevices parses the NICs from annotations.
+// It returns nil without an error when the annotation is missing.
+func GetNICDevices(annotations map[string]string) ([]NICDevice, error) {
+	data, ok := annotations[AnnotationNodeNICs]
+	if !ok {
+		return nil, nil
+	}
+	var devices []NICDevice
+	if err := json.Unmarshal([]byte(data), &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
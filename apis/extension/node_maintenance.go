@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LabelNodeCoordinatedDrain marks the node is drained for maintenance in the coordinated mode. It is set by the
+	// NodeMaintenance controller in koord-manager together with cordoning the node.
+	// When the node is also cordoned, koord-scheduler stops allocating the NUMA resources and CPUs on the node and frees
+	// the NUMA state after the node is drained, koord-descheduler migrates the bound pods in the ReservationFirst mode,
+	// and koordlet reports the final allocation of the node.
+	LabelNodeCoordinatedDrain = NodeDomainPrefix + "/coordinated-drain"
+
+	// AnnotationNodeDrainedNUMANodes lists the NUMA Nodes of the node drained for maintenance, e.g. replacing a socket or
+	// a DIMM, in the format of a JSON array like `[1]`. The rest of the node keeps serving.
+	// koord-scheduler excludes the CPUs and the NUMA resources of the drained NUMA Nodes, koord-descheduler migrates
+	// the pods bound to them, and koordlet moves the cpuset.mems of the shared pods away from them.
+	AnnotationNodeDrainedNUMANodes = NodeDomainPrefix + "/drained-numa-nodes"
+)
+
+// IsNodeCoordinatedDraining checks whether the node is cordoned and drained in the coordinated mode.
+func IsNodeCoordinatedDraining(node *corev1.Node) bool {
+	if node == nil || !node.Spec.Unschedulable {
+		return false
+	}
+	return node.Labels[LabelNodeCoordinatedDrain] == "true"
+}
+
+// IsPodResourceBound checks whether the pod is allocated with the exclusive CPUs or the NUMA resources.
+func IsPodResourceBound(pod *corev1.Pod) bool {
+	resourceStatus, err := GetResourceStatus(pod.Annotations)
+	if err != nil {
+		return false
+	}
+	return resourceStatus.CPUSet != "" || len(resourceStatus.NUMANodeResources) > 0
+}
+
+// GetNodeDrainedNUMANodes returns the NUMA Nodes drained for maintenance on the node, or nil if not drained.
+func GetNodeDrainedNUMANodes(annotations map[string]string) ([]int, error) {
+	data, ok := annotations[AnnotationNodeDrainedNUMANodes]
+	if !ok || data == "" {
+		return nil, nil
+	}
+	var numaNodes []int
+	if err := json.Unmarshal([]byte(data), &numaNodes); err != nil {
+		return nil, err
+	}
+	for _, numaNode := range numaNodes {
+		if numaNode < 0 {
+			return nil, fmt.Errorf("invalid drained NUMA Node %d", numaNode)
+		}
+	}
+	return numaNodes, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsNodeCoordinatedDraining(t *testing.T) {
+	tests := []struct {
+		name string
+		node *corev1.Node
+		want bool
+	}{
+		{
+			name: "nil node",
+			node: nil,
+			want: false,
+		},
+		{
+			name: "labeled but not cordoned",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{LabelNodeCoordinatedDrain: "true"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "cordoned but not labeled",
+			node: &corev1.Node{
+				Spec: corev1.NodeSpec{Unschedulable: true},
+			},
+			want: false,
+		},
+		{
+			name: "cordoned and labeled",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{LabelNodeCoordinatedDrain: "true"},
+				},
+				Spec: corev1.NodeSpec{Unschedulable: true},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsNodeCoordinatedDraining(tt.node))
+		})
+	}
+}
+
+func TestIsPodResourceBound(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "no resource status",
+			want: false,
+		},
+		{
+			name: "bound cpuset",
+			annotations: map[string]string{
+				AnnotationResourceStatus: `{"cpuset":"0-3"}`,
+			},
+			want: true,
+		},
+		{
+			name: "bound NUMA resources",
+			annotations: map[string]string{
+				AnnotationResourceStatus: `{"numaNodeResources":[{"node":0,"resources":{"cpu":"4"}}]}`,
+			},
+			want: true,
+		},
+		{
+			name: "invalid resource status",
+			annotations: map[string]string{
+				AnnotationResourceStatus: `{`,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tt.annotations,
+				},
+			}
+			assert.Equal(t, tt.want, IsPodResourceBound(pod))
+		})
+	}
+}
+
+func TestGetNodeDrainedNUMANodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []int
+		wantErr     bool
+	}{
+		{
+			name: "not drained",
+			want: nil,
+		},
+		{
+			name:        "empty annotation",
+			annotations: map[string]string{AnnotationNodeDrainedNUMANodes: ""},
+			want:        nil,
+		},
+		{
+			name:        "drained NUMA Nodes",
+			annotations: map[string]string{AnnotationNodeDrainedNUMANodes: "[1,3]"},
+			want:        []int{1, 3},
+		},
+		{
+			name:        "invalid format",
+			annotations: map[string]string{AnnotationNodeDrainedNUMANodes: "1"},
+			wantErr:     true,
+		},
+		{
+			name:        "negative NUMA Node",
+			annotations: map[string]string{AnnotationNodeDrainedNUMANodes: "[-1]"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNodeDrainedNUMANodes(tt.annotations)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+const (
+	// LabelNodePool is the node pool which the node belongs to, e.g. the pools of different machine types or usages.
+	// The scheduling outcomes are aggregated by the node pools.
+	LabelNodePool = NodeDomainPrefix + "/pool"
+)
+
+// GetNodePool returns the node pool in the labels, or empty if the node doesn't belong to any pool.
+func GetNodePool(labels map[string]string) string {
+	return labels[LabelNodePool]
+}
@@ -35,6 +35,10 @@ const (
 
 	// AnnotationNodeRawAllocatable denotes the un-amplified raw allocatable of the node.
 	AnnotationNodeRawAllocatable = NodeDomainPrefix + "/raw-allocatable"
+
+	// AnnotationNodeCPUAmplificationCFSQuotaOptOut denotes the QoS classes whose cfs quota is not scaled by the cpu
+	// amplification ratio of the node, e.g. `["LS"]`.
+	AnnotationNodeCPUAmplificationCFSQuotaOptOut = NodeDomainPrefix + "/cpu-amplification-cfs-quota-opt-out"
 )
 
 // Ratio is a float64 wrapper which will always be json marshalled with precision 2.
@@ -75,6 +79,21 @@ func GetNodeResourceAmplificationRatio(annotations map[string]string, resource c
 	return ratio, nil
 }
 
+// GetNodeCPUAmplificationCFSQuotaOptOut gets the QoS classes opted out of the cfs quota amplification from annotations.
+func GetNodeCPUAmplificationCFSQuotaOptOut(annotations map[string]string) ([]QoSClass, error) {
+	s, ok := annotations[AnnotationNodeCPUAmplificationCFSQuotaOptOut]
+	if !ok {
+		return nil, nil
+	}
+
+	var qosClasses []QoSClass
+	if err := json.Unmarshal([]byte(s), &qosClasses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal node cpu amplification cfs quota opt-out: %w", err)
+	}
+
+	return qosClasses, nil
+}
+
 // SetNodeResourceAmplificationRatios sets the node annotation according to the resource amplification ratios.
 // NOTE: The ratio will be converted to string with the precision 2. e.g. 3.1415926 -> 3.14.
 func SetNodeResourceAmplificationRatios(node *corev1.Node, ratios map[corev1.ResourceName]Ratio) {
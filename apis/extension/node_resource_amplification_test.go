@@ -179,6 +179,45 @@ func TestGetNodeResourceAmplificationRatio(t *testing.T) {
 	}
 }
 
+func TestGetNodeCPUAmplificationCFSQuotaOptOut(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []QoSClass
+		wantErr     bool
+	}{
+		{
+			name:        "node has no annotation",
+			annotations: nil,
+			want:        nil,
+			wantErr:     false,
+		},
+		{
+			name: "node has valid opt-out annotation",
+			annotations: map[string]string{
+				AnnotationNodeCPUAmplificationCFSQuotaOptOut: `["LS",""]`,
+			},
+			want:    []QoSClass{QoSLS, QoSNone},
+			wantErr: false,
+		},
+		{
+			name: "node has invalid opt-out annotation",
+			annotations: map[string]string{
+				AnnotationNodeCPUAmplificationCFSQuotaOptOut: "LS",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetNodeCPUAmplificationCFSQuotaOptOut(tt.annotations)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+		})
+	}
+}
+
 func TestSetNodeResourceAmplificationRatios(t *testing.T) {
 	type args struct {
 		node   *corev1.Node
@@ -18,6 +18,8 @@ package extension
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +34,31 @@ const (
 	// AnnotationResourceStatus represents resource allocation result.
 	// koord-scheduler patch Pod with the annotation before binding to node.
 	AnnotationResourceStatus = SchedulingDomainPrefix + "/resource-status"
+	// AnnotationNUMATopologySpec represents the NUMA topology requirements of the Pod.
+	// It takes precedence over the NUMA topology policy of the node if the node does not specify one.
+	AnnotationNUMATopologySpec = SchedulingDomainPrefix + "/numa-topology-spec"
+	// AnnotationIntraNodeSpreadSpec represents how the Pod spreads from its replicas across the CPU topology domains
+	// within a node, e.g. the L3 cache domains. It only takes effect on the Pods binding CPUs.
+	AnnotationIntraNodeSpreadSpec = SchedulingDomainPrefix + "/intra-node-spread-spec"
+
+	// LabelCPUOrchestrationPolicy references the CPUOrchestrationPolicy that the Pod adopts.
+	// koord-manager injects the policy into the Pod as AnnotationResourceSpec and AnnotationNUMATopologySpec.
+	LabelCPUOrchestrationPolicy = DomainPrefix + "cpu-orchestration-policy"
+)
+
+// Defines the pod conditions reported by koordlet
+const (
+	// PodConditionCPUSetBound indicates whether the cpuset allocated in AnnotationResourceStatus is actually enforced
+	// on the cgroups of all the containers of the Pod.
+	PodConditionCPUSetBound corev1.PodConditionType = DomainPrefix + "CPUSetBound"
+
+	// ReasonCPUSetBound is the reason when the cpuset of all containers matches the allocation.
+	ReasonCPUSetBound = "CPUSetBound"
+	// ReasonCPUSetMismatch is the reason when the cpuset of any container differs from the allocation or is unknown.
+	ReasonCPUSetMismatch = "CPUSetMismatch"
+	// ReasonCPUSetInsufficient is the reason when the allocated cpuset has fewer CPUs than the Pod requests,
+	// e.g. the CPUs are allocated by the amplified requests on the node with the CPU amplification ratio.
+	ReasonCPUSetInsufficient = "CPUSetInsufficient"
 )
 
 // Defines the node level annotations and labels
@@ -40,20 +67,45 @@ const (
 	AnnotationNodeCPUTopology = NodeDomainPrefix + "/cpu-topology"
 	// AnnotationNodeCPUAllocs describes K8s Guaranteed Pods.
 	AnnotationNodeCPUAllocs = NodeDomainPrefix + "/pod-cpu-allocs"
+	// AnnotationNodeMemoryAllocs describes the memory of K8s Guaranteed Pods pinned by the kubelet Memory Manager.
+	AnnotationNodeMemoryAllocs = NodeDomainPrefix + "/pod-memory-allocs"
 	// AnnotationNodeCPUSharedPools describes the CPU Shared Pool defined by Koordinator.
 	// The shared pool is mainly used by Koordinator LS Pods or K8s Burstable Pods.
 	AnnotationNodeCPUSharedPools = NodeDomainPrefix + "/cpu-shared-pools"
 	// AnnotationNodeBECPUSharedPools describes the CPU Shared Pool defined by Koordinator.
 	// The shared pool is mainly used by Koordinator BE Pods or K8s Besteffort Pods.
 	AnnotationNodeBECPUSharedPools = NodeDomainPrefix + "/be-cpu-shared-pools"
+	// AnnotationNodeReservedFullCores indicates the number of free physical cores that koord-scheduler holds back
+	// for Pods requiring FullPCPUs, e.g. LSR Pods. It overrides the NodeNUMAResource plugin args.
+	AnnotationNodeReservedFullCores = NodeDomainPrefix + "/reserved-full-cores"
+	// AnnotationNodeMaxBoundPods limits the number of the cpuset-bound Pods on the node.
+	// It overrides the NodeNUMAResource plugin args, and zero means unlimited.
+	AnnotationNodeMaxBoundPods = NodeDomainPrefix + "/max-bound-pods"
+	// AnnotationNodeMaxBoundPodsPerNUMANode limits the number of the cpuset-bound Pods on each NUMA Node of the node.
+	// It overrides the NodeNUMAResource plugin args, and zero means unlimited.
+	AnnotationNodeMaxBoundPodsPerNUMANode = NodeDomainPrefix + "/max-bound-pods-per-numa-node"
+	// AnnotationNodeKernelCPUIsolation describes the CPU isolation flags of the kernel cmdline
+	// and the CPU vulnerability mitigation states reported by koordlet.
+	AnnotationNodeKernelCPUIsolation = NodeDomainPrefix + "/kernel-cpu-isolation"
+	// AnnotationNodeNUMACacheLocality describes the NUMA Nodes where the local storage or caches preloading the images
+	// are attached, e.g. the NVMe disks caching the large models. koord-scheduler prefers the NUMA Nodes local to
+	// the caches of the Pod's images.
+	AnnotationNodeNUMACacheLocality = NodeDomainPrefix + "/numa-cache-locality"
 
 	// LabelNodeCPUBindPolicy constrains how to bind CPU logical CPUs when scheduling.
 	LabelNodeCPUBindPolicy = NodeDomainPrefix + "/cpu-bind-policy"
 	// LabelNodeNUMAAllocateStrategy indicates how to choose satisfied NUMA Nodes when scheduling.
 	LabelNodeNUMAAllocateStrategy = NodeDomainPrefix + "/numa-allocate-strategy"
+	// LabelNodeNUMAHintAllocateOrder indicates in which order the NUMA Nodes of a multi-NUMA-node hint are filled.
+	// It overrides the NodeNUMAResource plugin args.
+	LabelNodeNUMAHintAllocateOrder = NodeDomainPrefix + "/numa-hint-allocate-order"
 
 	// LabelNUMATopologyPolicy represents that how to align resource allocation according to the NUMA topology
 	LabelNUMATopologyPolicy = NodeDomainPrefix + "/numa-topology-policy"
+
+	// LabelNodeVirtualTopology indicates the node has no real CPU and NUMA topology,
+	// e.g. the virtual-kubelet node or the elastic container instance node.
+	LabelNodeVirtualTopology = NodeDomainPrefix + "/virtual-topology"
 )
 
 // ResourceSpec describes extra attributes of the resource requirements.
@@ -65,15 +117,102 @@ type ResourceSpec struct {
 	PreferredCPUBindPolicy CPUBindPolicy `json:"preferredCPUBindPolicy,omitempty"`
 	// PreferredCPUExclusivePolicy represents best-effort CPU exclusive policy.
 	PreferredCPUExclusivePolicy CPUExclusivePolicy `json:"preferredCPUExclusivePolicy,omitempty"`
+	// PreferredNUMAAllocateStrategy overrides the iteration order of NUMA Nodes when allocating CPUs.
+	PreferredNUMAAllocateStrategy NUMAAllocateStrategy `json:"preferredNUMAAllocateStrategy,omitempty"`
+	// IRQSteeringPolicy indicates whether koordlet steers the device interrupts away from the bound CPUs.
+	IRQSteeringPolicy IRQSteeringPolicy `json:"irqSteeringPolicy,omitempty"`
+	// CPUBindMode indicates whether the CPUs are bound as a hard or a soft constraint.
+	// Only LS Pods support the Soft mode, and only LSE/LSR Pods support the BestEffort mode.
+	CPUBindMode CPUBindMode `json:"cpuBindMode,omitempty"`
+	// MemoryPolicy indicates how the memory of the Pod is placed on the allocated NUMA Nodes.
+	MemoryPolicy MemoryPolicy `json:"memoryPolicy,omitempty"`
+	// Containers specifies the CPU bind policies of the containers in the multi-container Pod, so some containers are
+	// bound to the exclusive CPUs while the others run in the CPU Shared Pool. It conflicts with the CPU bind policies
+	// and the CPUBindMode of the Pod.
+	Containers []ContainerResourceSpec `json:"containers,omitempty"`
 }
 
+// ContainerResourceSpec describes the CPU bind policy of a container.
+type ContainerResourceSpec struct {
+	// Name is the name of the container.
+	Name string `json:"name"`
+	// RequiredCPUBindPolicy indicates that the CPUs of the container are allocated strictly according to the policy.
+	// The container runs in the CPU Shared Pool if not specified.
+	RequiredCPUBindPolicy CPUBindPolicy `json:"requiredCPUBindPolicy,omitempty"`
+}
+
+// NUMATopologySpec describes the NUMA topology requirements of the Pod.
+type NUMATopologySpec struct {
+	// NUMATopologyPolicy represents that how to align resource allocation according to the NUMA topology.
+	NUMATopologyPolicy NUMATopologyPolicy `json:"numaTopologyPolicy,omitempty"`
+}
+
+// IntraNodeSpreadSpec describes how the replicas on the same node spread across the CPU topology domains.
+type IntraNodeSpreadSpec struct {
+	// Domain is the CPU topology domain that the replicas spread across. Only L3 is supported now.
+	Domain IntraNodeSpreadDomain `json:"domain,omitempty"`
+	// LabelSelector selects the replicas in the same namespace.
+	// The Pods owned by the same controller are regarded as the replicas if not specified.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// WhenUnsatisfiable indicates how to deal with the Pod if it can't occupy the domains apart from its replicas.
+	WhenUnsatisfiable IntraNodeSpreadUnsatisfiableAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// IntraNodeSpreadDomain defines the CPU topology domain that the replicas spread across
+type IntraNodeSpreadDomain string
+
+const (
+	// IntraNodeSpreadDomainL3 spreads the replicas across the L3 cache domains, e.g. the CCDs of the AMD CPUs.
+	IntraNodeSpreadDomainL3 IntraNodeSpreadDomain = "L3"
+)
+
+// IntraNodeSpreadUnsatisfiableAction defines how to deal with the Pod not satisfying the intra-node spread constraint
+type IntraNodeSpreadUnsatisfiableAction string
+
+const (
+	// IntraNodeSpreadDoNotSchedule only allocates the CPUs in the domains not occupied by the replicas,
+	// the node is filtered if the CPUs are not enough.
+	IntraNodeSpreadDoNotSchedule IntraNodeSpreadUnsatisfiableAction = "DoNotSchedule"
+	// IntraNodeSpreadScheduleAnyway prefers the CPUs in the domains not occupied by the replicas,
+	// and falls back to the other CPUs if they are not enough.
+	IntraNodeSpreadScheduleAnyway IntraNodeSpreadUnsatisfiableAction = "ScheduleAnyway"
+)
+
 // ResourceStatus describes resource allocation result, such as how to bind CPU.
 type ResourceStatus struct {
 	// CPUSet represents the allocated CPUs. It is Linux CPU list formatted string.
 	// When LSE/LSR Pod requested, koord-scheduler will update the field.
 	CPUSet string `json:"cpuset,omitempty"`
+	// PreferredCPUSet represents the CPUs preferred by the LS Pod in the Soft CPUBindMode.
+	// koordlet applies it as a soft constraint and widens it to the CPU Shared Pool under pressure.
+	PreferredCPUSet string `json:"preferredCPUSet,omitempty"`
 	// NUMANodeResources indicates that the Pod is constrained to run on the specified NUMA Node.
 	NUMANodeResources []NUMANodeResource `json:"numaNodeResources,omitempty"`
+	// CPUBindDegraded indicates that the Pod in the BestEffort CPUBindMode is scheduled without binding the CPUs,
+	// so koordlet does not enforce the cpuset.
+	CPUBindDegraded bool `json:"cpuBindDegraded,omitempty"`
+	// ContainerCPUSets represents the CPUs allocated to the containers bound individually, whose union is the CPUSet.
+	// The containers not listed run in the CPU Shared Pool.
+	ContainerCPUSets []ContainerCPUSet `json:"containerCPUSets,omitempty"`
+}
+
+type ContainerCPUSet struct {
+	Name   string `json:"name"`
+	CPUSet string `json:"cpuset,omitempty"`
+}
+
+// GetContainerCPUSet returns the CPUs allocated to the container individually, and whether the Pod has
+// the per-container allocations.
+func (s *ResourceStatus) GetContainerCPUSet(containerName string) (string, bool) {
+	if s == nil || len(s.ContainerCPUSets) == 0 {
+		return "", false
+	}
+	for _, containerCPUSet := range s.ContainerCPUSets {
+		if containerCPUSet.Name == containerName {
+			return containerCPUSet.CPUSet, true
+		}
+	}
+	return "", true
 }
 
 type NUMANodeResource struct {
@@ -95,6 +234,33 @@ const (
 	CPUBindPolicyConstrainedBurst CPUBindPolicy = "ConstrainedBurst"
 )
 
+// CPUBindMode defines how strictly the Pod is bound to the allocated CPUs
+type CPUBindMode string
+
+const (
+	// CPUBindModeHard binds the Pod to the allocated CPUs. It is the default mode of LSE/LSR Pods.
+	CPUBindModeHard CPUBindMode = "Hard"
+	// CPUBindModeSoft applies the allocated CPUs to the LS Pod as a preferred cpuset, which doesn't consume
+	// the CPUs that can be bound by LSE/LSR Pods and can be widened to the CPU Shared Pool by koordlet.
+	CPUBindModeSoft CPUBindMode = "Soft"
+	// CPUBindModeBestEffort binds the LSE/LSR Pod to the CPUs if possible, otherwise the Pod is scheduled anyway
+	// and runs in the CPU Shared Pool. The degraded binding is reported in the ResourceStatus.
+	CPUBindModeBestEffort CPUBindMode = "BestEffort"
+)
+
+// MemoryPolicy defines the memory placement policy of the Pod on the allocated NUMA Nodes
+type MemoryPolicy string
+
+const (
+	// MemoryPolicyDefault keeps the default memory placement of the kernel.
+	MemoryPolicyDefault MemoryPolicy = "Default"
+	// MemoryPolicyInterleave interleaves the memory of the Pod across all the allocated NUMA Nodes, like the
+	// `numactl --interleave`, which trades the locality for the bandwidth of the memory-intensive workloads.
+	// The scheduler only prefers spreading the Pod over the NUMA Nodes under the None and BestEffort NUMA Topology
+	// Policy, and the memory policy is only applied by the OCI hook in the NRI mode of koordlet.
+	MemoryPolicyInterleave MemoryPolicy = "Interleave"
+)
+
 type CPUExclusivePolicy string
 
 const (
@@ -106,6 +272,16 @@ const (
 	CPUExclusivePolicyNUMANodeLevel CPUExclusivePolicy = "NUMANodeLevel"
 )
 
+// IRQSteeringPolicy defines how to steer the device interrupts for the bound CPUs
+type IRQSteeringPolicy string
+
+const (
+	// IRQSteeringPolicyNone does not change the IRQ affinity
+	IRQSteeringPolicyNone IRQSteeringPolicy = "None"
+	// IRQSteeringPolicyIsolated steers the device interrupts away from the CPUs bound by the Pod
+	IRQSteeringPolicyIsolated IRQSteeringPolicy = "Isolated"
+)
+
 type NodeCPUBindPolicy string
 
 const (
@@ -135,6 +311,20 @@ const (
 	NodeNUMAAllocateStrategyMostAllocated  = NUMAMostAllocated
 )
 
+// NUMAHintAllocateOrder indicates in which order the NUMA Nodes of a multi-NUMA-node hint are filled.
+type NUMAHintAllocateOrder string
+
+const (
+	// NUMAHintAllocateOrderNodeID fills the NUMA Nodes by the order of the NUMA Node ID.
+	NUMAHintAllocateOrderNodeID NUMAHintAllocateOrder = "NodeID"
+	// NUMAHintAllocateOrderMostAllocatedFirst fills the NUMA Node with the least ratio of available resources first,
+	// which packs the Pod into fewer NUMA Nodes.
+	NUMAHintAllocateOrderMostAllocatedFirst NUMAHintAllocateOrder = "MostAllocatedFirst"
+	// NUMAHintAllocateOrderLeastAllocatedFirst fills the NUMA Node with the most ratio of available resources first,
+	// which balances the allocated resources across the NUMA Nodes.
+	NUMAHintAllocateOrderLeastAllocatedFirst NUMAHintAllocateOrder = "LeastAllocatedFirst"
+)
+
 type NUMATopologyPolicy string
 
 const (
@@ -163,6 +353,8 @@ type CPUInfo struct {
 	Core   int32 `json:"core"`
 	Socket int32 `json:"socket"`
 	Node   int32 `json:"node"`
+	// L3 is the L3 cache ID of the CPU. It is not reported by the legacy koordlet.
+	L3 *int32 `json:"l3,omitempty"`
 }
 
 type PodCPUAlloc struct {
@@ -175,6 +367,18 @@ type PodCPUAlloc struct {
 
 type PodCPUAllocs []PodCPUAlloc
 
+// PodMemoryAlloc describes the memory pinned on each NUMA Node for the Pod.
+// The memory block of kubelet spanning multiple NUMA Nodes is divided evenly among them.
+type PodMemoryAlloc struct {
+	Namespace         string             `json:"namespace,omitempty"`
+	Name              string             `json:"name,omitempty"`
+	UID               types.UID          `json:"uid,omitempty"`
+	NUMANodeResources []NUMANodeResource `json:"numaNodeResources,omitempty"`
+	ManagedByKubelet  bool               `json:"managedByKubelet,omitempty"`
+}
+
+type PodMemoryAllocs []PodMemoryAlloc
+
 type CPUSharedPool struct {
 	Socket int32  `json:"socket"`
 	Node   int32  `json:"node"`
@@ -187,6 +391,22 @@ type KubeletCPUManagerPolicy struct {
 	ReservedCPUs string            `json:"reservedCPUs,omitempty"`
 }
 
+// KernelCPUIsolation describes the isolation-relevant kernel cmdline flags and the CPU vulnerabilities.
+type KernelCPUIsolation struct {
+	// IsolCPUs is the cpuset of the isolcpus flag. The kernel scheduler does not balance tasks onto them.
+	IsolCPUs string `json:"isolCPUs,omitempty"`
+	// NohzFullCPUs is the cpuset of the nohz_full flag.
+	NohzFullCPUs string `json:"nohzFullCPUs,omitempty"`
+	// RCUNoCBsCPUs is the cpuset of the rcu_nocbs flag.
+	RCUNoCBsCPUs string `json:"rcuNoCBsCPUs,omitempty"`
+	// Vulnerabilities maps the vulnerability name to its mitigation state,
+	// e.g. "spectre_v2": "Mitigation: Retpolines".
+	Vulnerabilities map[string]string `json:"vulnerabilities,omitempty"`
+}
+
+// NUMACacheLocality maps the image preloaded in the local caches to the NUMA Nodes where the caches are attached.
+type NUMACacheLocality map[string][]int
+
 // GetResourceSpec parses ResourceSpec from annotations
 func GetResourceSpec(annotations map[string]string) (*ResourceSpec, error) {
 	resourceSpec := &ResourceSpec{}
@@ -215,37 +435,61 @@ func SetResourceSpec(obj metav1.Object, spec *ResourceSpec) error {
 	return nil
 }
 
-// GetResourceStatus parses ResourceStatus from annotations
-func GetResourceStatus(annotations map[string]string) (*ResourceStatus, error) {
-	resourceStatus := &ResourceStatus{}
-	data, ok := annotations[AnnotationResourceStatus]
+// GetNUMATopologySpec parses NUMATopologySpec from annotations
+func GetNUMATopologySpec(annotations map[string]string) (*NUMATopologySpec, error) {
+	numaTopologySpec := &NUMATopologySpec{}
+	data, ok := annotations[AnnotationNUMATopologySpec]
 	if !ok {
-		return resourceStatus, nil
+		return numaTopologySpec, nil
 	}
-	err := json.Unmarshal([]byte(data), resourceStatus)
+	err := json.Unmarshal([]byte(data), numaTopologySpec)
 	if err != nil {
 		return nil, err
 	}
-	return resourceStatus, nil
+	return numaTopologySpec, nil
 }
 
-func SetResourceStatus(obj metav1.Object, status *ResourceStatus) error {
-	if obj == nil {
-		return nil
+func SetNUMATopologySpec(obj metav1.Object, spec *NUMATopologySpec) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
 	}
 	annotations := obj.GetAnnotations()
 	if annotations == nil {
 		annotations = map[string]string{}
 	}
-	data, err := json.Marshal(status)
-	if err != nil {
-		return err
-	}
-	annotations[AnnotationResourceStatus] = string(data)
+	annotations[AnnotationNUMATopologySpec] = string(data)
 	obj.SetAnnotations(annotations)
 	return nil
 }
 
+// GetIntraNodeSpreadSpec parses the IntraNodeSpreadSpec from the annotation. It returns nil if not specified.
+func GetIntraNodeSpreadSpec(annotations map[string]string) (*IntraNodeSpreadSpec, error) {
+	data, ok := annotations[AnnotationIntraNodeSpreadSpec]
+	if !ok {
+		return nil, nil
+	}
+	spec := &IntraNodeSpreadSpec{}
+	if err := json.Unmarshal([]byte(data), spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// GetResourceStatus parses ResourceStatus from annotations, which accepts both the V1 and the V2 formats.
+func GetResourceStatus(annotations map[string]string) (*ResourceStatus, error) {
+	data, ok := annotations[AnnotationResourceStatus]
+	if !ok {
+		return &ResourceStatus{}, nil
+	}
+	return UnmarshalResourceStatus(data)
+}
+
+// SetResourceStatus sets the annotation AnnotationResourceStatus in the V1 format.
+func SetResourceStatus(obj metav1.Object, status *ResourceStatus) error {
+	return SetResourceStatusWithFormat(obj, status, ResourceStatusFormatV1)
+}
+
 func GetCPUTopology(annotations map[string]string) (*CPUTopology, error) {
 	topology := &CPUTopology{}
 	data, ok := annotations[AnnotationNodeCPUTopology]
@@ -272,6 +516,19 @@ func GetPodCPUAllocs(annotations map[string]string) (PodCPUAllocs, error) {
 	return allocs, nil
 }
 
+func GetPodMemoryAllocs(annotations map[string]string) (PodMemoryAllocs, error) {
+	var allocs PodMemoryAllocs
+	data, ok := annotations[AnnotationNodeMemoryAllocs]
+	if !ok {
+		return allocs, nil
+	}
+	err := json.Unmarshal([]byte(data), &allocs)
+	if err != nil {
+		return nil, err
+	}
+	return allocs, nil
+}
+
 func GetNodeCPUSharePools(nodeTopoAnnotations map[string]string) ([]CPUSharedPool, error) {
 	var cpuSharePools []CPUSharedPool
 	data, ok := nodeTopoAnnotations[AnnotationNodeCPUSharedPools]
@@ -324,10 +581,94 @@ func GetNodeCPUBindPolicy(nodeLabels map[string]string, kubeletCPUPolicy *Kubele
 	return NodeCPUBindPolicyNone
 }
 
+func GetNodeReservedFullCores(annotations map[string]string) (int, bool, error) {
+	data, ok := annotations[AnnotationNodeReservedFullCores]
+	if !ok {
+		return 0, false, nil
+	}
+	numCores, err := strconv.Atoi(data)
+	if err != nil {
+		return 0, false, err
+	}
+	if numCores < 0 {
+		return 0, false, fmt.Errorf("invalid reserved full cores %d", numCores)
+	}
+	return numCores, true, nil
+}
+
+// GetNodeMaxBoundPods returns the maximum number of the cpuset-bound Pods on the node specified by the annotation.
+func GetNodeMaxBoundPods(annotations map[string]string) (int, bool, error) {
+	return getNodeBoundPodsLimit(annotations, AnnotationNodeMaxBoundPods)
+}
+
+// GetNodeMaxBoundPodsPerNUMANode returns the maximum number of the cpuset-bound Pods on each NUMA Node
+// specified by the annotation.
+func GetNodeMaxBoundPodsPerNUMANode(annotations map[string]string) (int, bool, error) {
+	return getNodeBoundPodsLimit(annotations, AnnotationNodeMaxBoundPodsPerNUMANode)
+}
+
+func getNodeBoundPodsLimit(annotations map[string]string, key string) (int, bool, error) {
+	data, ok := annotations[key]
+	if !ok {
+		return 0, false, nil
+	}
+	numPods, err := strconv.Atoi(data)
+	if err != nil {
+		return 0, false, err
+	}
+	if numPods < 0 {
+		return 0, false, fmt.Errorf("invalid %s %d", key, numPods)
+	}
+	return numPods, true, nil
+}
+
+// GetNodeNUMAHintAllocateOrder returns the NUMA hint allocate order specified by the node label.
+// The empty value is returned if the label is missing or unknown.
+func GetNodeNUMAHintAllocateOrder(nodeLabels map[string]string) NUMAHintAllocateOrder {
+	order := NUMAHintAllocateOrder(nodeLabels[LabelNodeNUMAHintAllocateOrder])
+	switch order {
+	case NUMAHintAllocateOrderNodeID, NUMAHintAllocateOrderMostAllocatedFirst, NUMAHintAllocateOrderLeastAllocatedFirst:
+		return order
+	}
+	return ""
+}
+
+// GetKernelCPUIsolation parses KernelCPUIsolation from the node-level annotations.
+// It returns nil without an error when the annotation is missing.
+func GetKernelCPUIsolation(annotations map[string]string) (*KernelCPUIsolation, error) {
+	data, ok := annotations[AnnotationNodeKernelCPUIsolation]
+	if !ok {
+		return nil, nil
+	}
+	isolation := &KernelCPUIsolation{}
+	if err := json.Unmarshal([]byte(data), isolation); err != nil {
+		return nil, err
+	}
+	return isolation, nil
+}
+
+// GetNUMACacheLocality parses NUMACacheLocality from the node-level annotations.
+// It returns nil without an error when the annotation is missing.
+func GetNUMACacheLocality(annotations map[string]string) (NUMACacheLocality, error) {
+	data, ok := annotations[AnnotationNodeNUMACacheLocality]
+	if !ok {
+		return nil, nil
+	}
+	var locality NUMACacheLocality
+	if err := json.Unmarshal([]byte(data), &locality); err != nil {
+		return nil, err
+	}
+	return locality, nil
+}
+
 func GetNodeNUMATopologyPolicy(labels map[string]string) NUMATopologyPolicy {
 	return NUMATopologyPolicy(labels[LabelNUMATopologyPolicy])
 }
 
+func IsNodeVirtualTopology(labels map[string]string) bool {
+	return labels[LabelNodeVirtualTopology] == "true"
+}
+
 func SetNodeNUMATopologyPolicy(obj metav1.Object, policy NUMATopologyPolicy) {
 	labels := obj.GetLabels()
 	if labels == nil {
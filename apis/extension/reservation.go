@@ -38,8 +38,22 @@ const (
 
 	// AnnotationReservationAffinity represents the constraints of Pod selection Reservation
 	AnnotationReservationAffinity = SchedulingDomainPrefix + "/reservation-affinity"
+
+	// AnnotationReservationFirstUpdate indicates the workload (Deployment or StatefulSet) expects the Reservations of
+	// its new pod template to be pre-created before the old pods are terminated during rolling updates.
+	AnnotationReservationFirstUpdate = SchedulingDomainPrefix + "/reservation-first-update"
+
+	// LabelReservationPreallocationOwner is the UID of the workload that the Reservation is pre-allocated for.
+	LabelReservationPreallocationOwner = SchedulingDomainPrefix + "/reservation-preallocation-owner"
+	// LabelReservationPreallocationRevision is the pod template revision of the workload that the Reservation is pre-allocated for.
+	LabelReservationPreallocationRevision = SchedulingDomainPrefix + "/reservation-preallocation-revision"
 )
 
+// IsReservationFirstUpdate checks whether the workload enables the reservation-first update.
+func IsReservationFirstUpdate(annotations map[string]string) bool {
+	return annotations[AnnotationReservationFirstUpdate] == "true"
+}
+
 type ReservationAllocated struct {
 	Name string    `json:"name,omitempty"`
 	UID  types.UID `json:"uid,omitempty"`
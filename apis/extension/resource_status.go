@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceStatusFormat describes how the ResourceStatus is encoded in the annotation AnnotationResourceStatus.
+type ResourceStatusFormat string
+
+const (
+	// ResourceStatusFormatV1 is the original JSON schema of the ResourceStatus.
+	ResourceStatusFormatV1 ResourceStatusFormat = "V1"
+	// ResourceStatusFormatV2 is the compact JSON schema with the short field names.
+	ResourceStatusFormatV2 ResourceStatusFormat = "V2"
+	// ResourceStatusFormatV2Compressed is the V2 schema compressed by gzip and encoded in base64 with the
+	// ResourceStatusCompressedPrefix, which is preferred by the large multi-container allocations.
+	ResourceStatusFormatV2Compressed ResourceStatusFormat = "V2Compressed"
+)
+
+const (
+	// ResourceStatusVersionV2 is the version declared in the V2 schema of the ResourceStatus.
+	ResourceStatusVersionV2 = 2
+	// ResourceStatusCompressedPrefix marks the compressed ResourceStatus, since the JSON object always starts with '{'.
+	ResourceStatusCompressedPrefix = "gzip:"
+)
+
+// resourceStatusV2 is the compact schema of the ResourceStatus, e.g.
+// {"v":2,"c":"0-3","n":[{"i":0,"r":{"cpu":"4","memory":"8Gi"}}]}
+type resourceStatusV2 struct {
+	Version           int                  `json:"v"`
+	CPUSet            string               `json:"c,omitempty"`
+	PreferredCPUSet   string               `json:"p,omitempty"`
+	NUMANodeResources []numaNodeResourceV2 `json:"n,omitempty"`
+	CPUBindDegraded   bool                 `json:"d,omitempty"`
+	ContainerCPUSets  []containerCPUSetV2  `json:"cc,omitempty"`
+}
+
+type numaNodeResourceV2 struct {
+	Node      int32               `json:"i"`
+	Resources corev1.ResourceList `json:"r,omitempty"`
+}
+
+type containerCPUSetV2 struct {
+	Name   string `json:"n"`
+	CPUSet string `json:"c,omitempty"`
+}
+
+// DecodeResourceStatusData decompresses the annotation AnnotationResourceStatus if needed, and returns the JSON data
+// and whether it is in the V2 schema.
+func DecodeResourceStatusData(data string) ([]byte, bool, error) {
+	raw := []byte(data)
+	if strings.HasPrefix(data, ResourceStatusCompressedPrefix) {
+		compressed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(data, ResourceStatusCompressedPrefix))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to decode compressed resource status, err: %w", err)
+		}
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to decompress resource status, err: %w", err)
+		}
+		defer reader.Close()
+		if raw, err = io.ReadAll(reader); err != nil {
+			return nil, false, fmt.Errorf("failed to decompress resource status, err: %w", err)
+		}
+	}
+
+	var versioned struct {
+		Version int `json:"v"`
+	}
+	if err := json.Unmarshal(raw, &versioned); err != nil {
+		return nil, false, err
+	}
+	if versioned.Version != 0 && versioned.Version != ResourceStatusVersionV2 {
+		return nil, false, fmt.Errorf("unsupported resource status version %d", versioned.Version)
+	}
+	return raw, versioned.Version == ResourceStatusVersionV2, nil
+}
+
+// UnmarshalResourceStatus parses the ResourceStatus in any format from the annotation value.
+func UnmarshalResourceStatus(data string) (*ResourceStatus, error) {
+	raw, isV2, err := DecodeResourceStatusData(data)
+	if err != nil {
+		return nil, err
+	}
+	if !isV2 {
+		resourceStatus := &ResourceStatus{}
+		if err = json.Unmarshal(raw, resourceStatus); err != nil {
+			return nil, err
+		}
+		return resourceStatus, nil
+	}
+
+	statusV2 := &resourceStatusV2{}
+	if err = json.Unmarshal(raw, statusV2); err != nil {
+		return nil, err
+	}
+	resourceStatus := &ResourceStatus{
+		CPUSet:          statusV2.CPUSet,
+		PreferredCPUSet: statusV2.PreferredCPUSet,
+		CPUBindDegraded: statusV2.CPUBindDegraded,
+	}
+	for _, numaNodeResource := range statusV2.NUMANodeResources {
+		resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, NUMANodeResource{
+			Node:      numaNodeResource.Node,
+			Resources: numaNodeResource.Resources,
+		})
+	}
+	for _, containerCPUSet := range statusV2.ContainerCPUSets {
+		resourceStatus.ContainerCPUSets = append(resourceStatus.ContainerCPUSets, ContainerCPUSet{
+			Name:   containerCPUSet.Name,
+			CPUSet: containerCPUSet.CPUSet,
+		})
+	}
+	return resourceStatus, nil
+}
+
+// MarshalResourceStatus encodes the ResourceStatus into the annotation value in the specified format.
+func MarshalResourceStatus(status *ResourceStatus, format ResourceStatusFormat) (string, error) {
+	switch format {
+	case "", ResourceStatusFormatV1:
+		data, err := json.Marshal(status)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case ResourceStatusFormatV2, ResourceStatusFormatV2Compressed:
+	default:
+		return "", fmt.Errorf("unsupported resource status format %s", format)
+	}
+
+	statusV2 := &resourceStatusV2{
+		Version:         ResourceStatusVersionV2,
+		CPUSet:          status.CPUSet,
+		PreferredCPUSet: status.PreferredCPUSet,
+		CPUBindDegraded: status.CPUBindDegraded,
+	}
+	for _, numaNodeResource := range status.NUMANodeResources {
+		statusV2.NUMANodeResources = append(statusV2.NUMANodeResources, numaNodeResourceV2{
+			Node:      numaNodeResource.Node,
+			Resources: numaNodeResource.Resources,
+		})
+	}
+	for _, containerCPUSet := range status.ContainerCPUSets {
+		statusV2.ContainerCPUSets = append(statusV2.ContainerCPUSets, containerCPUSetV2{
+			Name:   containerCPUSet.Name,
+			CPUSet: containerCPUSet.CPUSet,
+		})
+	}
+	data, err := json.Marshal(statusV2)
+	if err != nil {
+		return "", err
+	}
+	if format == ResourceStatusFormatV2 {
+		return string(data), nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err = writer.Write(data); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+	return ResourceStatusCompressedPrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// SetResourceStatusWithFormat sets the annotation AnnotationResourceStatus in the specified format.
+func SetResourceStatusWithFormat(obj metav1.Object, status *ResourceStatus, format ResourceStatusFormat) error {
+	if obj == nil {
+		return nil
+	}
+	data, err := MarshalResourceStatus(status, format)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationResourceStatus] = data
+	obj.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceStatusFormats(t *testing.T) {
+	status := &ResourceStatus{
+		CPUSet:          "0-3",
+		PreferredCPUSet: "4-7",
+		NUMANodeResources: []NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		format   ResourceStatusFormat
+		wantData string
+	}{
+		{
+			name:     "v1",
+			format:   ResourceStatusFormatV1,
+			wantData: `{"cpuset":"0-3","preferredCPUSet":"4-7","numaNodeResources":[{"node":0,"resources":{"cpu":"4","memory":"8Gi"}}]}`,
+		},
+		{
+			name:     "v2",
+			format:   ResourceStatusFormatV2,
+			wantData: `{"v":2,"c":"0-3","p":"4-7","n":[{"i":0,"r":{"cpu":"4","memory":"8Gi"}}]}`,
+		},
+		{
+			name:   "v2 compressed",
+			format: ResourceStatusFormatV2Compressed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod"}}
+			assert.NoError(t, SetResourceStatusWithFormat(pod, status, tt.format))
+			data := pod.Annotations[AnnotationResourceStatus]
+			if tt.wantData != "" {
+				assert.Equal(t, tt.wantData, data)
+			} else {
+				assert.Contains(t, data, ResourceStatusCompressedPrefix)
+			}
+
+			got, err := GetResourceStatus(pod.Annotations)
+			assert.NoError(t, err)
+			assert.Equal(t, status.CPUSet, got.CPUSet)
+			assert.Equal(t, status.PreferredCPUSet, got.PreferredCPUSet)
+			assert.Len(t, got.NUMANodeResources, 1)
+			assert.True(t, got.NUMANodeResources[0].Resources.Cpu().Equal(resource.MustParse("4")))
+		})
+	}
+}
+
+func TestResourceStatusContainerCPUSets(t *testing.T) {
+	status := &ResourceStatus{
+		CPUSet: "0-3",
+		ContainerCPUSets: []ContainerCPUSet{
+			{Name: "main", CPUSet: "0-3"},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod"}}
+	assert.NoError(t, SetResourceStatusWithFormat(pod, status, ResourceStatusFormatV2))
+	assert.Equal(t, `{"v":2,"c":"0-3","cc":[{"n":"main","c":"0-3"}]}`, pod.Annotations[AnnotationResourceStatus])
+	got, err := GetResourceStatus(pod.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, status, got)
+
+	cpus, ok := got.GetContainerCPUSet("main")
+	assert.True(t, ok)
+	assert.Equal(t, "0-3", cpus)
+	cpus, ok = got.GetContainerCPUSet("sidecar")
+	assert.True(t, ok)
+	assert.Equal(t, "", cpus)
+	_, ok = (&ResourceStatus{CPUSet: "0-3"}).GetContainerCPUSet("main")
+	assert.False(t, ok)
+}
+
+func TestUnmarshalResourceStatusInvalid(t *testing.T) {
+	_, err := UnmarshalResourceStatus(`{"v":3,"c":"0-3"}`)
+	assert.Error(t, err)
+	_, err = UnmarshalResourceStatus(ResourceStatusCompressedPrefix + "not-base64")
+	assert.Error(t, err)
+	_, err = MarshalResourceStatus(&ResourceStatus{}, "V3")
+	assert.Error(t, err)
+}
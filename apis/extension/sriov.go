@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+)
+
+const (
+	// AnnotationNodeSRIOVDevices describes the SR-IOV capable NICs of the node and their NUMA Nodes.
+	// It is reported to the NodeResourceTopology by koordlet.
+	AnnotationNodeSRIOVDevices = NodeDomainPrefix + "/sriov-devices"
+
+	// AnnotationBindToDeviceNUMA indicates that the CPUs of the Pod should be allocated from the NUMA Nodes of the
+	// SR-IOV NICs whose VFs are requested by the Pod, so that the DMA-heavy traffic through the VFs does not cross
+	// the NUMA Nodes.
+	AnnotationBindToDeviceNUMA = SchedulingDomainPrefix + "/bind-to-device-numa"
+)
+
+// SRIOVDevice describes a SR-IOV physical function (PF) whose virtual functions (VFs) can be allocated to the Pods.
+type SRIOVDevice struct {
+	// BusID is the PCI address of the PF, e.g. 0000:3b:00.0
+	BusID string `json:"busID"`
+	// NUMANode is the NUMA Node that the PF attaches to, which is also the NUMA Node of its VFs.
+	NUMANode int32 `json:"numaNode"`
+	// NumVFs is the number of the VFs enabled on the PF.
+	NumVFs int32 `json:"numVFs,omitempty"`
+	// ResourceNames are the device plugin resources which allocate the VFs of the PF, e.g. intel.com/sriov_netdevice.
+	ResourceNames []string `json:"resourceNames,omitempty"`
+}
+
+// GetSRIOVDevices parses the SR-IOV devices from annotations.
+// It returns nil without an error when the annotation is missing.
+func GetSRIOVDevices(annotations map[string]string) ([]SRIOVDevice, error) {
+	data, ok := annotations[AnnotationNodeSRIOVDevices]
+	if !ok {
+		return nil, nil
+	}
+	var devices []SRIOVDevice
+	if err := json.Unmarshal([]byte(data), &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// IsPodBindToDeviceNUMA checks whether the Pod requires the CPUs on the NUMA Nodes of the SR-IOV NICs.
+func IsPodBindToDeviceNUMA(annotations map[string]string) bool {
+	return annotations[AnnotationBindToDeviceNUMA] == "true"
+}
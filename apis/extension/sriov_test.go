@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSRIOVDevices(t *testing.T) {
+	tests := []struct {
+		name    string
+		anno    map[string]string
+		want    []SRIOVDevice
+		wantErr bool
+	}{
+		{
+			name: "annotation key not exist",
+			anno: map[string]string{},
+			want: nil,
+		},
+		{
+			name: "bad json format",
+			anno: map[string]string{
+				AnnotationNodeSRIOVDevices: "bad-format-str",
+			},
+			wantErr: true,
+		},
+		{
+			name: "parse format succeed",
+			anno: map[string]string{
+				AnnotationNodeSRIOVDevices: `[{"busID":"0000:3b:00.0","numaNode":1,"numVFs":8,"resourceNames":["intel.com/sriov"]}]`,
+			},
+			want: []SRIOVDevice{
+				{BusID: "0000:3b:00.0", NUMANode: 1, NumVFs: 8, ResourceNames: []string{"intel.com/sriov"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSRIOVDevices(tt.anno)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsPodBindToDeviceNUMA(t *testing.T) {
+	assert.False(t, IsPodBindToDeviceNUMA(nil))
+	assert.False(t, IsPodBindToDeviceNUMA(map[string]string{AnnotationBindToDeviceNUMA: "false"}))
+	assert.True(t, IsPodBindToDeviceNUMA(map[string]string{AnnotationBindToDeviceNUMA: "true"}))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSchedulingHintSpec describes which nodes and flavors are summarized into the hint.
+type ClusterSchedulingHintSpec struct {
+	// NodeSelector selects the nodes summarized into the hint. All nodes are selected if not specified.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Flavors are the typical resource requirements whose largest-fit statistics are exported.
+	// +optional
+	Flavors []SchedulingHintFlavor `json:"flavors,omitempty"`
+}
+
+// SchedulingHintFlavor describes a typical resource requirement, e.g. a Pod requesting 8 CPUs and 32Gi memory.
+type SchedulingHintFlavor struct {
+	// Name is the unique name of the flavor.
+	Name string `json:"name"`
+
+	// Resources is the requested resources of the flavor.
+	Resources corev1.ResourceList `json:"resources"`
+}
+
+// ClusterSchedulingHintStatus summarizes the free resources and the fragmentation of the selected nodes,
+// which can be consumed by a global scheduler or queue to choose the cluster.
+type ClusterSchedulingHintStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdateTime is the last time the status was updated.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// Nodes is the number of the selected nodes.
+	// +optional
+	Nodes int32 `json:"nodes,omitempty"`
+
+	// Free is the sum of the unrequested resources of the selected nodes.
+	// +optional
+	Free corev1.ResourceList `json:"free,omitempty"`
+
+	// ColocationHeadroom is the sum of the unrequested batch resources of the selected nodes.
+	// +optional
+	ColocationHeadroom corev1.ResourceList `json:"colocationHeadroom,omitempty"`
+
+	// NUMA summarizes the fragmentation of the free CPUs among the NUMA nodes.
+	// +optional
+	NUMA *NUMAFragmentation `json:"numa,omitempty"`
+
+	// GPU summarizes the fragmentation of the free GPUs among the nodes.
+	// +optional
+	GPU *GPUFragmentation `json:"gpu,omitempty"`
+
+	// Flavors is the largest-fit statistics of the flavors.
+	// +optional
+	Flavors []SchedulingHintFlavorStatus `json:"flavors,omitempty"`
+}
+
+// NUMAFragmentation describes how the free CPUs are scattered among the NUMA nodes.
+type NUMAFragmentation struct {
+	// NUMANodes is the number of the NUMA nodes reported by the selected nodes.
+	NUMANodes int32 `json:"numaNodes,omitempty"`
+	// FreeCPUs is the sum of the unallocated CPUs of the NUMA nodes.
+	FreeCPUs resource.Quantity `json:"freeCPUs,omitempty"`
+	// LargestFreeCPUs is the largest unallocated CPUs of a single NUMA node.
+	LargestFreeCPUs resource.Quantity `json:"largestFreeCPUs,omitempty"`
+	// FragmentationPercent is the percentage of the free CPUs not in the fully free NUMA nodes.
+	FragmentationPercent int64 `json:"fragmentationPercent,omitempty"`
+}
+
+// GPUFragmentation describes how the free GPUs are scattered among the nodes.
+type GPUFragmentation struct {
+	// TotalGPUs is the number of the healthy GPUs of the selected nodes.
+	TotalGPUs int32 `json:"totalGPUs,omitempty"`
+	// FreeGPUs is the number of the healthy GPUs not allocated to any Pod.
+	FreeGPUs int32 `json:"freeGPUs,omitempty"`
+	// LargestFreeGPUs is the largest number of the free GPUs on a single node.
+	LargestFreeGPUs int32 `json:"largestFreeGPUs,omitempty"`
+	// FragmentationPercent is the percentage of the free GPUs on the nodes partially allocated.
+	FragmentationPercent int64 `json:"fragmentationPercent,omitempty"`
+}
+
+// SchedulingHintFlavorStatus is the largest-fit statistics of a flavor.
+type SchedulingHintFlavorStatus struct {
+	// Name is the name of the flavor.
+	Name string `json:"name"`
+	// LargestFit is the largest number of the flavor instances that fit into a single node.
+	LargestFit int64 `json:"largestFit"`
+	// TotalFit is the total number of the flavor instances that fit into the selected nodes.
+	TotalFit int64 `json:"totalFit"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster,shortName=csh
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.nodes"
+// +kubebuilder:printcolumn:name="LastUpdateTime",type="date",JSONPath=".status.lastUpdateTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterSchedulingHint is the Schema for the ClusterSchedulingHint API
+type ClusterSchedulingHint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ClusterSchedulingHintSpec   `json:"spec,omitempty"`
+	Status            ClusterSchedulingHintStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSchedulingHintList contains a list of ClusterSchedulingHint
+type ClusterSchedulingHintList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSchedulingHint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSchedulingHint{}, &ClusterSchedulingHintList{})
+}
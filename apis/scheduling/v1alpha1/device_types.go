@@ -27,6 +27,7 @@ const (
 	GPU  DeviceType = "gpu"
 	FPGA DeviceType = "fpga"
 	RDMA DeviceType = "rdma"
+	NIC  DeviceType = "nic"
 )
 
 type DeviceSpec struct {
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeMaintenanceSpec describes the node to drain for maintenance.
+type NodeMaintenanceSpec struct {
+	// NodeName is the name of the node to drain. It can not be changed once the maintenance is created.
+	NodeName string `json:"nodeName"`
+
+	// Reason is the human-readable reason of the maintenance.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+type NodeMaintenancePhase string
+
+const (
+	// NodeMaintenancePending means the node is not cordoned yet, e.g. the node is not found.
+	NodeMaintenancePending NodeMaintenancePhase = "Pending"
+	// NodeMaintenanceDraining means the node is cordoned and the pods bound to CPUs or NUMA nodes are migrating.
+	NodeMaintenanceDraining NodeMaintenancePhase = "Draining"
+	// NodeMaintenanceDrained means no pod bound to CPUs or NUMA nodes is left on the node.
+	NodeMaintenanceDrained NodeMaintenancePhase = "Drained"
+)
+
+// NodeMaintenanceStatus describes the progress of the coordinated drain.
+type NodeMaintenanceStatus struct {
+	// Phase is the phase of the coordinated drain.
+	// +optional
+	Phase NodeMaintenancePhase `json:"phase,omitempty"`
+
+	// BoundPods is the number of the pods bound to CPUs or NUMA nodes left on the node.
+	// +optional
+	BoundPods int32 `json:"boundPods,omitempty"`
+
+	// Message is the human-readable message of the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastUpdateTime is the last time the status was updated.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster,shortName=nm
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="BoundPods",type="integer",JSONPath=".status.boundPods"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NodeMaintenance is the Schema for the NodeMaintenance API. Creating a NodeMaintenance cordons the node and drains
+// it in the coordinated mode: koord-scheduler stops allocating the CPUs and NUMA resources on the node and frees its
+// NUMA state after drained, koord-descheduler migrates the bound pods in the ReservationFirst mode, and koordlet
+// reports the final allocation of the node. Deleting it uncordons the node.
+type NodeMaintenance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              NodeMaintenanceSpec   `json:"spec,omitempty"`
+	Status            NodeMaintenanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeMaintenanceList contains a list of NodeMaintenance
+type NodeMaintenanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeMaintenance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeMaintenance{}, &NodeMaintenanceList{})
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,6 +46,66 @@ type NodeMetricInfo struct {
 	// AggregatedSystemUsages will report only if there are enough samples
 	// Deleted pods will be excluded during aggregation
 	AggregatedSystemUsages []AggregatedUsage `json:"aggregatedSystemUsages,omitempty"`
+	// MemoryLocality is the memory locality aggregated from the pods bound to CPUs on the node
+	MemoryLocality *MemoryLocality `json:"memoryLocality,omitempty"`
+	// HealthIndex is the composite index of the node interference, reported only if the NodeHealthIndex is enabled
+	HealthIndex *NodeHealthIndex `json:"healthIndex,omitempty"`
+	// ColdMemory is the cold memory info of the node, reported only if the ColdMemoryReportIntervalSeconds is set
+	// and the cold page collector is running
+	ColdMemory *NodeColdMemoryInfo `json:"coldMemory,omitempty"`
+	// CPUSteal is the CPU time stolen by the hypervisor, reported only if the CPUStealAnomaly is enabled
+	CPUSteal *NodeCPUSteal `json:"cpuSteal,omitempty"`
+}
+
+// NodeCPUSteal describes the CPU time which the node running in a VM is ready to run but stolen by the hypervisor,
+// e.g. for serving the co-tenant VMs.
+type NodeCPUSteal struct {
+	// StealPercent is the average percentage of the CPU time stolen during the aggregation window, in the range [0, 100]
+	StealPercent *int64 `json:"stealPercent,omitempty"`
+}
+
+// NodeColdMemoryInfo describes the cold (idle) memory of the node detected by the cold page collector.
+type NodeColdMemoryInfo struct {
+	// Total is the total memory of the node
+	Total *resource.Quantity `json:"total,omitempty"`
+	// Cold is the size of the cold pages which are not accessed recently
+	Cold *resource.Quantity `json:"cold,omitempty"`
+	// HotUsage is the memory usage including the hot page cache, i.e. excluding the cold pages
+	HotUsage *resource.Quantity `json:"hotUsage,omitempty"`
+	// UpdateTime is the last time the cold memory info was collected
+	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
+}
+
+// NodeHealthIndex summarizes the interference signals of the node into a scalar.
+// All the values are in the range [0, 100], and a higher value means a heavier interference.
+type NodeHealthIndex struct {
+	// Index is the weighted average of the component scores
+	Index *int64 `json:"index,omitempty"`
+	// PSI is the score of the pressure stall information of cpu, memory and io
+	PSI *int64 `json:"psi,omitempty"`
+	// CPUThrottled is the score of the cfs throttling of the pods
+	CPUThrottled *int64 `json:"cpuThrottled,omitempty"`
+	// ColdPageChurn is the score of the cold page size variation relative to the memory capacity
+	ColdPageChurn *int64 `json:"coldPageChurn,omitempty"`
+	// RunQueueLatency is the score of the average time the tasks wait on the cpu run queues
+	RunQueueLatency *int64 `json:"runQueueLatency,omitempty"`
+}
+
+// HealthIndexWeights defines the weights of the component scores to compute the NodeHealthIndex.
+// A nil weight means the default, and a zero weight excludes the component.
+type HealthIndexWeights struct {
+	PSI             *int64 `json:"psi,omitempty"`
+	CPUThrottled    *int64 `json:"cpuThrottled,omitempty"`
+	ColdPageChurn   *int64 `json:"coldPageChurn,omitempty"`
+	RunQueueLatency *int64 `json:"runQueueLatency,omitempty"`
+}
+
+// MemoryLocality describes how the memory is distributed between the NUMA nodes local to the bound CPUs and the remote ones
+type MemoryLocality struct {
+	// LocalRatio is the percentage of the memory on the local NUMA nodes, in the range [0, 100]
+	LocalRatio *int64 `json:"localRatio,omitempty"`
+	// RemoteRatio is the percentage of the memory on the remote NUMA nodes, in the range [0, 100]
+	RemoteRatio *int64 `json:"remoteRatio,omitempty"`
 }
 
 type AggregatedUsage struct {
@@ -56,10 +117,24 @@ type PodMetricInfo struct {
 	Name      string      `json:"name,omitempty"`
 	Namespace string      `json:"namespace,omitempty"`
 	PodUsage  ResourceMap `json:"podUsage,omitempty"`
+	// MemoryLocality is reported only if the pod is bound to CPUs
+	MemoryLocality *MemoryLocality `json:"memoryLocality,omitempty"`
 	// Third party extensions for PodMetric
 	Extensions *ExtensionsMap `json:"extensions,omitempty"`
 }
 
+// HostApplicationMetricInfo is the resource usage of the host application running out of the pods
+type HostApplicationMetricInfo struct {
+	// Name of the host application
+	Name string `json:"name,omitempty"`
+	// Usage is the resource usage of the host application
+	Usage ResourceMap `json:"usage,omitempty"`
+	// Priority class of the host application
+	Priority apiext.PriorityClass `json:"priority,omitempty"`
+	// QoS class of the host application
+	QoS apiext.QoSClass `json:"qos,omitempty"`
+}
+
 // NodeMetricSpec defines the desired state of NodeMetric
 type NodeMetricSpec struct {
 	// CollectPolicy defines the Metric collection policy
@@ -76,6 +151,14 @@ type NodeMetricCollectPolicy struct {
 	NodeAggregatePolicy *AggregatePolicy `json:"nodeAggregatePolicy,omitempty"`
 	// NodeMemoryPolicy represents apply which method collect memory info
 	NodeMemoryCollectPolicy *NodeMemoryCollectPolicy `json:"nodeMemoryCollectPolicy,omitempty"`
+	// HealthIndexWeights represents the weights to compute the node health index
+	HealthIndexWeights *HealthIndexWeights `json:"healthIndexWeights,omitempty"`
+	// ColdMemoryReportIntervalSeconds represents the period in seconds to report the node cold memory.
+	// The cold memory is not reported if it is nil or zero.
+	ColdMemoryReportIntervalSeconds *int64 `json:"coldMemoryReportIntervalSeconds,omitempty"`
+	// CPUStealAnomalyThresholdPercent represents the CPU steal percent from which the node reports the
+	// CPUStealAnomaly condition. The default threshold is used if it is nil.
+	CPUStealAnomalyThresholdPercent *int64 `json:"cpuStealAnomalyThresholdPercent,omitempty"`
 }
 
 type AggregatePolicy struct {
@@ -101,8 +184,55 @@ type NodeMetricStatus struct {
 
 	// ProdReclaimableMetric is the indicator statistics of Prod type resources reclaimable
 	ProdReclaimableMetric *ReclaimableMetric `json:"prodReclaimableMetric,omitempty"`
+
+	// HostApplicationMetric contains the metrics of out-of-band applications on node.
+	HostApplicationMetric []*HostApplicationMetricInfo `json:"hostApplicationMetric,omitempty"`
+
+	// ColocationReadiness is the compliance of the node OS and kernel for the colocation strategies.
+	ColocationReadiness *ColocationReadiness `json:"colocationReadiness,omitempty"`
+
+	// Conditions are the anomalies detected on the node.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ColocationReadiness describes whether the node OS image and the kernel parameters satisfy the colocation strategies.
+type ColocationReadiness struct {
+	// Score is the percent of the passed checks, in the range [0, 100].
+	Score int64 `json:"score"`
+	// Checks are the results of the readiness checks.
+	Checks []ColocationReadinessCheck `json:"checks,omitempty"`
+}
+
+type ColocationReadinessCheck struct {
+	// Name is the name of the check, e.g. KernelVersion.
+	Name string `json:"name"`
+	// Passed indicates whether the node passes the check.
+	Passed bool `json:"passed"`
+	// Message is the detail of the check result.
+	Message string `json:"message,omitempty"`
 }
 
+const (
+	// ColocationReadinessCheckKernelVersion checks whether the kernel version is new enough for the colocation.
+	ColocationReadinessCheckKernelVersion = "KernelVersion"
+	// ColocationReadinessCheckCgroupControllers checks whether the cgroup controllers required by koordlet are enabled.
+	ColocationReadinessCheckCgroupControllers = "CgroupControllers"
+	// ColocationReadinessCheckSchedFeatures checks whether the kernel supports the CPU QoS sched features,
+	// e.g. the group identity. It is only checked on the Anolis kernel.
+	ColocationReadinessCheckSchedFeatures = "SchedFeatures"
+	// ColocationReadinessCheckMemcgQoS checks whether the memcg supports the memory QoS interfaces,
+	// e.g. memory.min and memory.low.
+	ColocationReadinessCheckMemcgQoS = "MemcgQoS"
+)
+
+const (
+	// NodeMetricConditionCPUStealAnomaly indicates that the CPU steal of the node exceeds the anomaly threshold,
+	// so the node is considered to have a reduced CPU capacity.
+	NodeMetricConditionCPUStealAnomaly = "CPUStealAnomaly"
+	// NodeMetricConditionColocationReady indicates that the node passes all the colocation readiness checks.
+	NodeMetricConditionColocationReady = "ColocationReady"
+)
+
 // +genclient
 // +genclient:nonNamespaced
 // +kubebuilder:object:root=true
@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -27,6 +29,26 @@ import (
 type CPUQOS struct {
 	// group identity value for pods, default = 0
 	GroupIdentity *int64 `json:"groupIdentity,omitempty" validate:"omitempty,min=-1,max=2"`
+	// CFSQuotaFree indicates whether to remove the cfs quota of the pods bound with exclusive cpusets, since the
+	// cpuset already bounds their cpu usage and the quota only brings the throttling. It takes effect in the LSRClass
+	// for the LSE and LSR pods. The quota is restored from the pod spec when disabled or the cpuset binding is lost.
+	// Default: true.
+	CFSQuotaFree *bool `json:"cfsQuotaFree,omitempty"`
+	// PriorityShareWeights scales the cpu shares of the pods by the buckets of the koordinator sub-priority instead of
+	// the flat shares calculated from the cpu requests. It takes effect in the LSClass for the LS pods. A pod uses the
+	// bucket with the largest MinPriority no greater than its sub-priority, and keeps the original shares if no bucket
+	// matches. Disabled if empty.
+	PriorityShareWeights []CPUPriorityShareWeight `json:"priorityShareWeights,omitempty" validate:"omitempty,dive"`
+}
+
+// CPUPriorityShareWeight maps a bucket of the koordinator sub-priority to the weight of the cpu shares.
+type CPUPriorityShareWeight struct {
+	// MinPriority is the lower bound of the sub-priority (label `koordinator.sh/priority`) in the bucket.
+	MinPriority int32 `json:"minPriority"`
+	// WeightPercent is the percentage to scale the cpu shares calculated from the cpu requests,
+	// e.g. 200 doubles the shares.
+	// +kubebuilder:validation:Minimum=1
+	WeightPercent int64 `json:"weightPercent" validate:"min=1"`
 }
 
 // MemoryQOS enables memory qos features.
@@ -224,8 +246,16 @@ type ResourceThresholdStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	CPUSuppressThresholdPercent *int64 `json:"cpuSuppressThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
-	// CPUSuppressPolicy
-	CPUSuppressPolicy CPUSuppressPolicy `json:"cpuSuppressPolicy,omitempty"`
+	// CPUSuppressPolicy defines how to suppress the BE pods when the node is busy.
+	// `cpuset` shrinks the cpuset of the BE cgroups, while `cfsQuota` adjusts the cfs quota of the BE parent cgroup
+	// without changing the cpuset, which avoids the excessive task migrations. Default: `cpuset`.
+	// +kubebuilder:validation:Enum=cpuset;cfsQuota
+	CPUSuppressPolicy CPUSuppressPolicy `json:"cpuSuppressPolicy,omitempty" validate:"omitempty,oneof=cpuset cfsQuota"`
+	// percentage of the CPU time stolen by the hypervisor which is deducted from the node capacity when calculating
+	// the BE suppression, e.g. 100 means the stolen CPUs are totally unavailable. It is skipped if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	CPUStealCapacityDiscountPercent *int64 `json:"cpuStealCapacityDiscountPercent,omitempty" validate:"omitempty,min=0,max=100"`
 
 	// upper: memory evict threshold percentage (0,100), default = 70
 	// +kubebuilder:validation:Maximum=100
@@ -235,6 +265,38 @@ type ResourceThresholdStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	MemoryEvictLowerPercent *int64 `json:"memoryEvictLowerPercent,omitempty" validate:"omitempty,min=0,max=100,ltfield=MemoryEvictThresholdPercent"`
+	// memory throttle threshold percentage (0,100). When the node memory usage exceeds it, the memory.high of the
+	// BE pods is tightened step by step to trigger the memory reclaim before the eviction; and it is relaxed step by
+	// step when the usage falls below the threshold. The throttling is skipped if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryThrottleThresholdPercent *int64 `json:"memoryThrottleThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// percentage of the BE pod memory usage to tighten the memory.high for each throttle step, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	MemoryThrottleStepPercent *int64 `json:"memoryThrottleStepPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// percentage of the memory limit of each BE pod (or the node memory capacity if the pod has no memory limit)
+	// that the page cache of the pod can use, which prevents the cache-hungry batch jobs from evicting the hot pages
+	// of the LS pods. The memcg page cache limit is used on the supportive kernels, otherwise the memory.high is set
+	// to the anonymous memory usage plus the limit. The limit is skipped if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	BEPageCacheLimitPercent *int64 `json:"bePageCacheLimitPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// node memory usage percentage at which the cold pages of the BE pods start to be swapped out proactively by the
+	// memory.reclaim on the swap-enabled nodes, which enlarges the reclaimable headroom before the memory pressure
+	// spikes. It should be lower than the memory throttle and evict thresholds. The swap-out is skipped if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	BEColdMemorySwapOutThresholdPercent *int64 `json:"beColdMemorySwapOutThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// swappiness passed to the memory.reclaim when swapping out the cold pages of the BE pods, where the larger one
+	// prefers reclaiming the anonymous pages more. The swappiness of the cgroup is used if not set.
+	// +kubebuilder:validation:Maximum=200
+	// +kubebuilder:validation:Minimum=0
+	BEColdMemorySwapOutSwappiness *int64 `json:"beColdMemorySwapOutSwappiness,omitempty" validate:"omitempty,min=0,max=200"`
+	// upper bound of the swap IO rate of the node in pages per second (pswpin + pswpout), which bounds the pages
+	// swapped out in each round and pauses the swap-out when the BE pods are thrashing. It is unlimited if not set.
+	// +kubebuilder:validation:Minimum=1
+	BEColdMemorySwapOutMaxIOPagesPerSecond *int64 `json:"beColdMemorySwapOutMaxIOPagesPerSecond,omitempty" validate:"omitempty,min=1"`
 
 	// be.satisfactionRate = be.CPURealLimit/be.CPURequest
 	// if be.satisfactionRate > CPUEvictBESatisfactionUpperPercent/100, then stop to evict.
@@ -251,6 +313,22 @@ type ResourceThresholdStrategy struct {
 	// CPUEvictPolicy defines the policy for the BECPUEvict feature.
 	// Default: `evictByRealLimit`.
 	CPUEvictPolicy CPUEvictPolicy `json:"cpuEvictPolicy,omitempty"`
+
+	// Exemptions select the BE pods which are never evicted or throttled by the strategies, e.g. the critical backup
+	// jobs. A pod is exempted if it matches any of the exemptions.
+	// The cpu suppression keeps the cpuset of the exempted pods unsuppressed, or reserves their batch cpu limits in the
+	// cfs quota of the BE QoS cgroup.
+	Exemptions []ResourceThresholdExemption `json:"exemptions,omitempty"`
+}
+
+// ResourceThresholdExemption selects the pods exempted from the resource threshold strategy by namespaces and labels.
+// The exemption specifying neither namespaces nor pod selector is ignored.
+type ResourceThresholdExemption struct {
+	// Namespaces are the namespaces of the exempted pods. The pods of all namespaces are selected if empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// PodSelector selects the exempted pods by the labels, e.g. the labels of a workload.
+	// All pods in the namespaces are selected if nil.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
 }
 
 // ResctrlQOSCfg stores node-level config of resctrl qos
@@ -313,8 +391,20 @@ type SystemStrategy struct {
 	WatermarkScaleFactor *int64 `json:"watermarkScaleFactor,omitempty" validate:"omitempty,gt=0,max=400"`
 	// /sys/kernel/mm/memcg_reaper/reap_background
 	MemcgReapBackGround *int64 `json:"memcgReapBackGround,omitempty" validate:"omitempty,min=0,max=1"`
+	// ReservedCPUs are the exclusive cpus reserved on every node for the daemon pods like the network agents, which
+	// should be System QoS pods. The other pods cannot use them, and the scheduler excludes them when allocating cpus.
+	// The format follows the Linux CPU list, e.g. "0-1". It is overridden by the node annotation
+	// node.koordinator.sh/system-qos-resource.
+	ReservedCPUs *string `json:"reservedCPUs,omitempty"`
 }
 
+const (
+	// AnnotationNodeSLOSchemaVersion is the schema version of the NodeSLO spec written by the slo-controller, so the
+	// koordlet of another version can convert the spec during the rolling upgrade. The NodeSLO without the annotation
+	// is regarded as the first version.
+	AnnotationNodeSLOSchemaVersion = apiext.DomainPrefix + "nodeslo-schema-version"
+)
+
 // NodeSLOSpec defines the desired state of NodeSLO
 type NodeSLOSpec struct {
 	// BE pods will be limited if node resource usage overload
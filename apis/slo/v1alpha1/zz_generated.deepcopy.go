@@ -186,6 +186,21 @@ func (in *CPUBurstStrategy) DeepCopy() *CPUBurstStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUPriorityShareWeight) DeepCopyInto(out *CPUPriorityShareWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPriorityShareWeight.
+func (in *CPUPriorityShareWeight) DeepCopy() *CPUPriorityShareWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUPriorityShareWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUQOS) DeepCopyInto(out *CPUQOS) {
 	*out = *in
@@ -194,6 +209,16 @@ func (in *CPUQOS) DeepCopyInto(out *CPUQOS) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CFSQuotaFree != nil {
+		in, out := &in.CFSQuotaFree, &out.CFSQuotaFree
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PriorityShareWeights != nil {
+		in, out := &in.PriorityShareWeights, &out.PriorityShareWeights
+		*out = make([]CPUPriorityShareWeight, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUQOS.
@@ -242,6 +267,57 @@ func (in *CgroupPath) DeepCopy() *CgroupPath {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationReadiness) DeepCopyInto(out *ColocationReadiness) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ColocationReadinessCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationReadiness.
+func (in *ColocationReadiness) DeepCopy() *ColocationReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationReadinessCheck) DeepCopyInto(out *ColocationReadinessCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationReadinessCheck.
+func (in *ColocationReadinessCheck) DeepCopy() *ColocationReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostApplicationMetricInfo) DeepCopyInto(out *HostApplicationMetricInfo) {
+	*out = *in
+	in.Usage.DeepCopyInto(&out.Usage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostApplicationMetricInfo.
+func (in *HostApplicationMetricInfo) DeepCopy() *HostApplicationMetricInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(HostApplicationMetricInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostApplicationSpec) DeepCopyInto(out *HostApplicationSpec) {
 	*out = *in
@@ -282,6 +358,41 @@ func (in *HostApplicationStrategy) DeepCopy() *HostApplicationStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthIndexWeights) DeepCopyInto(out *HealthIndexWeights) {
+	*out = *in
+	if in.PSI != nil {
+		in, out := &in.PSI, &out.PSI
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPUThrottled != nil {
+		in, out := &in.CPUThrottled, &out.CPUThrottled
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ColdPageChurn != nil {
+		in, out := &in.ColdPageChurn, &out.ColdPageChurn
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RunQueueLatency != nil {
+		in, out := &in.RunQueueLatency, &out.RunQueueLatency
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthIndexWeights.
+func (in *HealthIndexWeights) DeepCopy() *HealthIndexWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthIndexWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IOCfg) DeepCopyInto(out *IOCfg) {
 	*out = *in
@@ -332,6 +443,31 @@ func (in *IOCfg) DeepCopy() *IOCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryLocality) DeepCopyInto(out *MemoryLocality) {
+	*out = *in
+	if in.LocalRatio != nil {
+		in, out := &in.LocalRatio, &out.LocalRatio
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RemoteRatio != nil {
+		in, out := &in.RemoteRatio, &out.RemoteRatio
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryLocality.
+func (in *MemoryLocality) DeepCopy() *MemoryLocality {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryLocality)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryQOS) DeepCopyInto(out *MemoryQOS) {
 	*out = *in
@@ -413,6 +549,100 @@ func (in *MemoryQOSCfg) DeepCopy() *MemoryQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCPUSteal) DeepCopyInto(out *NodeCPUSteal) {
+	*out = *in
+	if in.StealPercent != nil {
+		in, out := &in.StealPercent, &out.StealPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCPUSteal.
+func (in *NodeCPUSteal) DeepCopy() *NodeCPUSteal {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCPUSteal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeColdMemoryInfo) DeepCopyInto(out *NodeColdMemoryInfo) {
+	*out = *in
+	if in.Total != nil {
+		in, out := &in.Total, &out.Total
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Cold != nil {
+		in, out := &in.Cold, &out.Cold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HotUsage != nil {
+		in, out := &in.HotUsage, &out.HotUsage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeColdMemoryInfo.
+func (in *NodeColdMemoryInfo) DeepCopy() *NodeColdMemoryInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeColdMemoryInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeHealthIndex) DeepCopyInto(out *NodeHealthIndex) {
+	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PSI != nil {
+		in, out := &in.PSI, &out.PSI
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPUThrottled != nil {
+		in, out := &in.CPUThrottled, &out.CPUThrottled
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ColdPageChurn != nil {
+		in, out := &in.ColdPageChurn, &out.ColdPageChurn
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RunQueueLatency != nil {
+		in, out := &in.RunQueueLatency, &out.RunQueueLatency
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeHealthIndex.
+func (in *NodeHealthIndex) DeepCopy() *NodeHealthIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeHealthIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMetric) DeepCopyInto(out *NodeMetric) {
 	*out = *in
@@ -463,6 +693,21 @@ func (in *NodeMetricCollectPolicy) DeepCopyInto(out *NodeMetricCollectPolicy) {
 		*out = new(NodeMemoryCollectPolicy)
 		**out = **in
 	}
+	if in.HealthIndexWeights != nil {
+		in, out := &in.HealthIndexWeights, &out.HealthIndexWeights
+		*out = new(HealthIndexWeights)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ColdMemoryReportIntervalSeconds != nil {
+		in, out := &in.ColdMemoryReportIntervalSeconds, &out.ColdMemoryReportIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPUStealAnomalyThresholdPercent != nil {
+		in, out := &in.CPUStealAnomalyThresholdPercent, &out.CPUStealAnomalyThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricCollectPolicy.
@@ -494,6 +739,26 @@ func (in *NodeMetricInfo) DeepCopyInto(out *NodeMetricInfo) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MemoryLocality != nil {
+		in, out := &in.MemoryLocality, &out.MemoryLocality
+		*out = new(MemoryLocality)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthIndex != nil {
+		in, out := &in.HealthIndex, &out.HealthIndex
+		*out = new(NodeHealthIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ColdMemory != nil {
+		in, out := &in.ColdMemory, &out.ColdMemory
+		*out = new(NodeColdMemoryInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUSteal != nil {
+		in, out := &in.CPUSteal, &out.CPUSteal
+		*out = new(NodeCPUSteal)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricInfo.
@@ -586,6 +851,29 @@ func (in *NodeMetricStatus) DeepCopyInto(out *NodeMetricStatus) {
 		*out = new(ReclaimableMetric)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostApplicationMetric != nil {
+		in, out := &in.HostApplicationMetric, &out.HostApplicationMetric
+		*out = make([]*HostApplicationMetricInfo, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(HostApplicationMetricInfo)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ColocationReadiness != nil {
+		in, out := &in.ColocationReadiness, &out.ColocationReadiness
+		*out = new(ColocationReadiness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricStatus.
@@ -738,6 +1026,11 @@ func (in *PodMemoryQOSConfig) DeepCopy() *PodMemoryQOSConfig {
 func (in *PodMetricInfo) DeepCopyInto(out *PodMetricInfo) {
 	*out = *in
 	in.PodUsage.DeepCopyInto(&out.PodUsage)
+	if in.MemoryLocality != nil {
+		in, out := &in.MemoryLocality, &out.MemoryLocality
+		*out = new(MemoryLocality)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Extensions != nil {
 		in, out := &in.Extensions, &out.Extensions
 		*out = (*in).DeepCopy()
@@ -925,6 +1218,31 @@ func (in *ResourceQOSStrategy) DeepCopy() *ResourceQOSStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceThresholdExemption) DeepCopyInto(out *ResourceThresholdExemption) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceThresholdExemption.
+func (in *ResourceThresholdExemption) DeepCopy() *ResourceThresholdExemption {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceThresholdExemption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy) {
 	*out = *in
@@ -938,6 +1256,11 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CPUStealCapacityDiscountPercent != nil {
+		in, out := &in.CPUStealCapacityDiscountPercent, &out.CPUStealCapacityDiscountPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.MemoryEvictThresholdPercent != nil {
 		in, out := &in.MemoryEvictThresholdPercent, &out.MemoryEvictThresholdPercent
 		*out = new(int64)
@@ -948,6 +1271,36 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MemoryThrottleThresholdPercent != nil {
+		in, out := &in.MemoryThrottleThresholdPercent, &out.MemoryThrottleThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryThrottleStepPercent != nil {
+		in, out := &in.MemoryThrottleStepPercent, &out.MemoryThrottleStepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEPageCacheLimitPercent != nil {
+		in, out := &in.BEPageCacheLimitPercent, &out.BEPageCacheLimitPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEColdMemorySwapOutThresholdPercent != nil {
+		in, out := &in.BEColdMemorySwapOutThresholdPercent, &out.BEColdMemorySwapOutThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEColdMemorySwapOutSwappiness != nil {
+		in, out := &in.BEColdMemorySwapOutSwappiness, &out.BEColdMemorySwapOutSwappiness
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BEColdMemorySwapOutMaxIOPagesPerSecond != nil {
+		in, out := &in.BEColdMemorySwapOutMaxIOPagesPerSecond, &out.BEColdMemorySwapOutMaxIOPagesPerSecond
+		*out = new(int64)
+		**out = **in
+	}
 	if in.CPUEvictBESatisfactionUpperPercent != nil {
 		in, out := &in.CPUEvictBESatisfactionUpperPercent, &out.CPUEvictBESatisfactionUpperPercent
 		*out = new(int64)
@@ -968,6 +1321,13 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Exemptions != nil {
+		in, out := &in.Exemptions, &out.Exemptions
+		*out = make([]ResourceThresholdExemption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceThresholdStrategy.
@@ -998,6 +1358,11 @@ func (in *SystemStrategy) DeepCopyInto(out *SystemStrategy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ReservedCPUs != nil {
+		in, out := &in.ReservedCPUs, &out.ReservedCPUs
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemStrategy.
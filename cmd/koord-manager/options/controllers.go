@@ -21,19 +21,30 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/koordinator-sh/koordinator/pkg/node-maintenance-controller/nodemaintenance"
+	"github.com/koordinator-sh/koordinator/pkg/node-topology-gc-controller/nodetopologygc"
 	"github.com/koordinator-sh/koordinator/pkg/quota-controller/profile"
+	"github.com/koordinator-sh/koordinator/pkg/reservation-controller/preallocation"
+	"github.com/koordinator-sh/koordinator/pkg/scheduling-hint-controller/clusterhint"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/cpuorchestrationpolicy"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodeslo"
 )
 
 var controllerInitFlags = map[string]func(*flag.FlagSet){
-	noderesource.Name: noderesource.InitFlags,
+	noderesource.Name:   noderesource.InitFlags,
+	nodetopologygc.Name: nodetopologygc.InitFlags,
 }
 
 var controllerAddFuncs = map[string]func(manager.Manager) error{
-	nodemetric.Name:   nodemetric.Add,
-	noderesource.Name: noderesource.Add,
-	nodeslo.Name:      nodeslo.Add,
-	profile.Name:      profile.Add,
+	clusterhint.Name:            clusterhint.Add,
+	cpuorchestrationpolicy.Name: cpuorchestrationpolicy.Add,
+	nodemetric.Name:             nodemetric.Add,
+	nodemaintenance.Name:        nodemaintenance.Add,
+	noderesource.Name:           noderesource.Add,
+	nodetopologygc.Name:         nodetopologygc.Add,
+	nodeslo.Name:                nodeslo.Add,
+	preallocation.Name:          preallocation.Add,
+	profile.Name:                profile.Add,
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+var (
+	output             string
+	asPrometheusRule   bool
+	prometheusRuleName string
+	prometheusRuleNS   string
+)
+
+// prometheusRule is the PrometheusRule of the prometheus-operator which the ServiceMonitor comes along with.
+type prometheusRule struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Metadata   prometheusRuleMeta  `json:"metadata"`
+	Spec       registry.RuleGroups `json:"spec"`
+}
+
+type prometheusRuleMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func main() {
+	flag.StringVar(&output, "output", "", "the file to write the rules to, the stdout if empty")
+	flag.BoolVar(&asPrometheusRule, "prometheus-rule", false, "wrap the rules into a PrometheusRule of the prometheus-operator")
+	flag.StringVar(&prometheusRuleName, "prometheus-rule-name", "koordinator-rules", "the name of the PrometheusRule")
+	flag.StringVar(&prometheusRuleNS, "prometheus-rule-namespace", "", "the namespace of the PrometheusRule")
+
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	var rules interface{} = registry.GenerateRuleGroups()
+	if asPrometheusRule {
+		rules = &prometheusRule{
+			APIVersion: "monitoring.coreos.com/v1",
+			Kind:       "PrometheusRule",
+			Metadata: prometheusRuleMeta{
+				Name:      prometheusRuleName,
+				Namespace: prometheusRuleNS,
+			},
+			Spec: *registry.GenerateRuleGroups(),
+		}
+	}
+	data, err := yaml.Marshal(rules)
+	if err != nil {
+		klog.Fatalf("failed to marshal the rules: %v", err)
+	}
+
+	if output == "" {
+		if _, err = os.Stdout.Write(data); err != nil {
+			klog.Fatalf("failed to write the rules: %v", err)
+		}
+		return
+	}
+	if err = os.WriteFile(output, data, 0644); err != nil {
+		klog.Fatalf("failed to write the rules to %s: %v", output, err)
+	}
+}
@@ -60,9 +60,11 @@ import (
 
 	schedulerserverconfig "github.com/koordinator-sh/koordinator/cmd/koord-scheduler/app/config"
 	"github.com/koordinator-sh/koordinator/cmd/koord-scheduler/app/options"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/defaultprofile"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/eventhandlers"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/featuregates"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
 	utilroutes "github.com/koordinator-sh/koordinator/pkg/util/routes"
 	"github.com/koordinator-sh/koordinator/pkg/util/transformer"
@@ -108,6 +110,7 @@ for cost reduction and efficiency enhancement.
 	verflag.AddFlags(nfs.FlagSet("global"))
 	globalflag.AddGlobalFlags(nfs.FlagSet("global"), cmd.Name(), logs.SkipLoggingConfigurationFlags())
 	frameworkext.AddFlags(nfs.FlagSet("extend"))
+	featuregates.AddFlags(nfs.FlagSet("extend"))
 	fs := cmd.Flags()
 	for _, f := range nfs.FlagSets {
 		fs.AddFlagSet(f)
@@ -202,6 +205,9 @@ func Run(ctx context.Context, cc *schedulerserverconfig.CompletedConfig, sched *
 		}
 	}
 
+	// Reload the dynamic feature gates from the configmap.
+	featuregates.NewWatcher(cc.Client, utilfeature.DefaultMutableFeatureGate, features.DynamicSchedulerFeatures).Start(ctx.Done())
+
 	// Start all informers.
 	cc.InformerFactory.Start(ctx.Done())
 	// DynInformerFactory can be nil in tests.
@@ -222,6 +228,15 @@ func Run(ctx context.Context, cc *schedulerserverconfig.CompletedConfig, sched *
 	if cc.LeaderElection != nil {
 		cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
+				if utilfeature.DefaultFeatureGate.Enabled(features.LeaderHandoff) {
+					// the previous leader may still be binding, wait for it with the renew deadline
+					barrier := frameworkext.NewHandoffBarrier(cc.Client, cc.ComponentConfig.LeaderElection.ResourceNamespace,
+						cc.ComponentConfig.LeaderElection.ResourceName, cc.LeaderElection.Lock.Identity(),
+						cc.ComponentConfig.LeaderElection.RenewDeadline.Duration)
+					if err := extenderFactory.Handoff(ctx, barrier); err != nil {
+						klog.ErrorS(err, "Failed to hand off from the previous leader")
+					}
+				}
 				close(waitingForLeader)
 				go extenderFactory.Run()
 				sched.Run(ctx)
@@ -414,6 +429,8 @@ func Setup(ctx context.Context, opts *options.Options, outOfTreeRegistryOptions
 	schedAdapter := frameworkExtenderFactory.Scheduler()
 
 	eventhandlers.AddScheduleEventHandler(sched, schedAdapter, frameworkExtenderFactory.KoordinatorSharedInformerFactory())
+	// record the failures before the reservation errors are intercepted
+	frameworkExtenderFactory.RegisterErrorHandlerFilters(frameworkext.MakeSchedulingOutcomeErrorHandler(cc.InformerFactory.Core().V1().Nodes().Lister()), nil)
 	reservationErrorHandler := eventhandlers.MakeReservationErrorHandler(
 		sched,
 		schedAdapter,
@@ -28,6 +28,7 @@ import (
 
 type ConfigV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CPUOrchestrationPoliciesGetter
 	ClusterColocationProfilesGetter
 }
 
@@ -36,6 +37,10 @@ type ConfigV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *ConfigV1alpha1Client) CPUOrchestrationPolicies() CPUOrchestrationPolicyInterface {
+	return newCPUOrchestrationPolicies(c)
+}
+
 func (c *ConfigV1alpha1Client) ClusterColocationProfiles() ClusterColocationProfileInterface {
 	return newClusterColocationProfiles(c)
 }
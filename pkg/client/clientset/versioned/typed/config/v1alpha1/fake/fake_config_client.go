@@ -28,6 +28,10 @@ type FakeConfigV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeConfigV1alpha1) CPUOrchestrationPolicies() v1alpha1.CPUOrchestrationPolicyInterface {
+	return &FakeCPUOrchestrationPolicies{c}
+}
+
 func (c *FakeConfigV1alpha1) ClusterColocationProfiles() v1alpha1.ClusterColocationProfileInterface {
 	return &FakeClusterColocationProfiles{c}
 }
@@ -28,10 +28,18 @@ type FakeSchedulingV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeSchedulingV1alpha1) ClusterSchedulingHints() v1alpha1.ClusterSchedulingHintInterface {
+	return &FakeClusterSchedulingHints{c}
+}
+
 func (c *FakeSchedulingV1alpha1) Devices() v1alpha1.DeviceInterface {
 	return &FakeDevices{c}
 }
 
+func (c *FakeSchedulingV1alpha1) NodeMaintenances() v1alpha1.NodeMaintenanceInterface {
+	return &FakeNodeMaintenances{c}
+}
+
 func (c *FakeSchedulingV1alpha1) PodMigrationJobs() v1alpha1.PodMigrationJobInterface {
 	return &FakePodMigrationJobs{c}
 }
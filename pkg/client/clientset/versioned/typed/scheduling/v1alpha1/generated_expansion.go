@@ -18,8 +18,12 @@ limitations under the License.
 
 package v1alpha1
 
+type ClusterSchedulingHintExpansion interface{}
+
 type DeviceExpansion interface{}
 
+type NodeMaintenanceExpansion interface{}
+
 type PodMigrationJobExpansion interface{}
 
 type ReservationExpansion interface{}
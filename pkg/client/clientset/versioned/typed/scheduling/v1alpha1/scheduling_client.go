@@ -28,7 +28,9 @@ import (
 
 type SchedulingV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ClusterSchedulingHintsGetter
 	DevicesGetter
+	NodeMaintenancesGetter
 	PodMigrationJobsGetter
 	ReservationsGetter
 }
@@ -38,10 +40,18 @@ type SchedulingV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SchedulingV1alpha1Client) ClusterSchedulingHints() ClusterSchedulingHintInterface {
+	return newClusterSchedulingHints(c)
+}
+
 func (c *SchedulingV1alpha1Client) Devices() DeviceInterface {
 	return newDevices(c)
 }
 
+func (c *SchedulingV1alpha1Client) NodeMaintenances() NodeMaintenanceInterface {
+	return newNodeMaintenances(c)
+}
+
 func (c *SchedulingV1alpha1Client) PodMigrationJobs() PodMigrationJobInterface {
 	return newPodMigrationJobs(c)
 }
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// CPUOrchestrationPolicies returns a CPUOrchestrationPolicyInformer.
+	CPUOrchestrationPolicies() CPUOrchestrationPolicyInformer
 	// ClusterColocationProfiles returns a ClusterColocationProfileInformer.
 	ClusterColocationProfiles() ClusterColocationProfileInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// CPUOrchestrationPolicies returns a CPUOrchestrationPolicyInformer.
+func (v *version) CPUOrchestrationPolicies() CPUOrchestrationPolicyInformer {
+	return &cPUOrchestrationPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterColocationProfiles returns a ClusterColocationProfileInformer.
 func (v *version) ClusterColocationProfiles() ClusterColocationProfileInformer {
 	return &clusterColocationProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
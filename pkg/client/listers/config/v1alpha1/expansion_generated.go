@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+// CPUOrchestrationPolicyListerExpansion allows custom methods to be added to
+// CPUOrchestrationPolicyLister.
+type CPUOrchestrationPolicyListerExpansion interface{}
+
 // ClusterColocationProfileListerExpansion allows custom methods to be added to
 // ClusterColocationProfileLister.
 type ClusterColocationProfileListerExpansion interface{}
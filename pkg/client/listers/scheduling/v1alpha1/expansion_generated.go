@@ -18,10 +18,18 @@ limitations under the License.
 
 package v1alpha1
 
+// ClusterSchedulingHintListerExpansion allows custom methods to be added to
+// ClusterSchedulingHintLister.
+type ClusterSchedulingHintListerExpansion interface{}
+
 // DeviceListerExpansion allows custom methods to be added to
 // DeviceLister.
 type DeviceListerExpansion interface{}
 
+// NodeMaintenanceListerExpansion allows custom methods to be added to
+// NodeMaintenanceLister.
+type NodeMaintenanceListerExpansion interface{}
+
 // PodMigrationJobListerExpansion allows custom methods to be added to
 // PodMigrationJobLister.
 type PodMigrationJobListerExpansion interface{}
@@ -75,6 +75,10 @@ type LowNodeLoadArgs struct {
 	// it is determined that the node is abnormal, and the Pods need to be migrated to reduce the load.
 	AnomalyCondition *LoadAnomalyCondition
 
+	// HealthIndexThreshold indicates the threshold of the node health index reported in NodeMetric.
+	// The nodes whose health index reaches the threshold are not considered as the destination of the migrated Pods.
+	HealthIndexThreshold *int64
+
 	// NodePools supports multiple different types of batch nodes to configure different strategies
 	NodePools []LowNodeLoadNodePool
 }
@@ -103,6 +107,10 @@ type LowNodeLoadNodePool struct {
 	// the default is 5 consecutive times exceeding HighThresholds,
 	// it is determined that the node is abnormal, and the Pods need to be migrated to reduce the load.
 	AnomalyCondition *LoadAnomalyCondition
+
+	// HealthIndexThreshold indicates the threshold of the node health index reported in NodeMetric.
+	// The nodes whose health index reaches the threshold are not considered as the destination of the migrated Pods.
+	HealthIndexThreshold *int64
 }
 
 type LowNodeLoadPodSelector struct {
@@ -60,6 +60,10 @@ type MigrationControllerArgs struct {
 	// including NodeAffinity, TaintTolerance, and whether resources are sufficient.
 	NodeFit bool
 
+	// CheckNUMACapacity if enabled, the Pods bound to CPUs are only migrated when another node
+	// can host them with an equivalent CPU bind policy according to the NUMA capacity.
+	CheckNUMACapacity bool
+
 	// NodeSelector for a set of nodes to operate over
 	NodeSelector string
 
@@ -35,6 +35,7 @@ func Convert_v1alpha2_LowNodeLoadArgs_To_config_LowNodeLoadArgs(in *LowNodeLoadA
 		LowThresholds:          out.LowThresholds,
 		ResourceWeights:        out.ResourceWeights,
 		AnomalyCondition:       out.AnomalyCondition,
+		HealthIndexThreshold:   out.HealthIndexThreshold,
 	}
 	out.NodePools = append([]config.LowNodeLoadNodePool{pool}, out.NodePools...)
 	out.NodeSelector = nil
@@ -43,5 +44,6 @@ func Convert_v1alpha2_LowNodeLoadArgs_To_config_LowNodeLoadArgs(in *LowNodeLoadA
 	out.LowThresholds = nil
 	out.ResourceWeights = nil
 	out.AnomalyCondition = nil
+	out.HealthIndexThreshold = nil
 	return nil
 }
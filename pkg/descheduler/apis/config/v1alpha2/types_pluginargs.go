@@ -62,6 +62,10 @@ type MigrationControllerArgs struct {
 	// including NodeAffinity, TaintTolerance, and whether resources are sufficient.
 	NodeFit bool `json:"nodeFit,omitempty"`
 
+	// CheckNUMACapacity if enabled, the Pods bound to CPUs are only migrated when another node
+	// can host them with an equivalent CPU bind policy according to the NUMA capacity.
+	CheckNUMACapacity bool `json:"checkNUMACapacity,omitempty"`
+
 	// NodeSelector for a set of nodes to operate over
 	NodeSelector string `json:"nodeSelector,omitempty"`
 
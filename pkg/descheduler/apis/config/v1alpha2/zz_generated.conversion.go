@@ -388,6 +388,7 @@ func autoConvert_v1alpha2_LowNodeLoadArgs_To_config_LowNodeLoadArgs(in *LowNodeL
 	} else {
 		out.AnomalyCondition = nil
 	}
+	out.HealthIndexThreshold = (*int64)(unsafe.Pointer(in.HealthIndexThreshold))
 	if in.NodePools != nil {
 		in, out := &in.NodePools, &out.NodePools
 		*out = make([]config.LowNodeLoadNodePool, len(*in))
@@ -433,6 +434,7 @@ func autoConvert_config_LowNodeLoadArgs_To_v1alpha2_LowNodeLoadArgs(in *config.L
 	} else {
 		out.AnomalyCondition = nil
 	}
+	out.HealthIndexThreshold = (*int64)(unsafe.Pointer(in.HealthIndexThreshold))
 	if in.NodePools != nil {
 		in, out := &in.NodePools, &out.NodePools
 		*out = make([]LowNodeLoadNodePool, len(*in))
@@ -468,6 +470,7 @@ func autoConvert_v1alpha2_LowNodeLoadNodePool_To_config_LowNodeLoadNodePool(in *
 	} else {
 		out.AnomalyCondition = nil
 	}
+	out.HealthIndexThreshold = (*int64)(unsafe.Pointer(in.HealthIndexThreshold))
 	return nil
 }
 
@@ -492,6 +495,7 @@ func autoConvert_config_LowNodeLoadNodePool_To_v1alpha2_LowNodeLoadNodePool(in *
 	} else {
 		out.AnomalyCondition = nil
 	}
+	out.HealthIndexThreshold = (*int64)(unsafe.Pointer(in.HealthIndexThreshold))
 	return nil
 }
 
@@ -535,6 +539,7 @@ func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerA
 	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
 	out.Namespaces = (*config.Namespaces)(unsafe.Pointer(in.Namespaces))
 	out.NodeFit = in.NodeFit
+	out.CheckNUMACapacity = in.CheckNUMACapacity
 	out.NodeSelector = in.NodeSelector
 	out.MaxMigratingPerNode = (*int32)(unsafe.Pointer(in.MaxMigratingPerNode))
 	out.MaxMigratingPerNamespace = (*int32)(unsafe.Pointer(in.MaxMigratingPerNamespace))
@@ -575,6 +580,7 @@ func autoConvert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerA
 	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
 	out.Namespaces = (*Namespaces)(unsafe.Pointer(in.Namespaces))
 	out.NodeFit = in.NodeFit
+	out.CheckNUMACapacity = in.CheckNUMACapacity
 	out.NodeSelector = in.NodeSelector
 	out.MaxMigratingPerNode = (*int32)(unsafe.Pointer(in.MaxMigratingPerNode))
 	out.MaxMigratingPerNamespace = (*int32)(unsafe.Pointer(in.MaxMigratingPerNamespace))
@@ -64,6 +64,10 @@ func ValidateLowLoadUtilizationArgs(path *field.Path, args *deschedulerconfig.Lo
 			}
 		}
 
+		if nodePool.HealthIndexThreshold != nil && (*nodePool.HealthIndexThreshold < 0 || *nodePool.HealthIndexThreshold > 100) {
+			allErrs = append(allErrs, field.Invalid(nodePoolPath.Child("healthIndexThreshold"), *nodePool.HealthIndexThreshold, "healthIndexThreshold must be in [0, 100]"))
+		}
+
 		if nodePool.AnomalyCondition.ConsecutiveAbnormalities <= 0 {
 			fieldPath := nodePoolPath.Child("anomalyDetectionThresholds").Child("consecutiveAbnormalities")
 			allErrs = append(allErrs, field.Invalid(fieldPath, nodePool.AnomalyCondition.ConsecutiveAbnormalities, "consecutiveAbnormalities must be greater than 0"))
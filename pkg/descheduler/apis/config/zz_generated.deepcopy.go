@@ -208,6 +208,11 @@ func (in *LowNodeLoadArgs) DeepCopyInto(out *LowNodeLoadArgs) {
 		*out = new(LoadAnomalyCondition)
 		**out = **in
 	}
+	if in.HealthIndexThreshold != nil {
+		in, out := &in.HealthIndexThreshold, &out.HealthIndexThreshold
+		*out = new(int64)
+		**out = **in
+	}
 	if in.NodePools != nil {
 		in, out := &in.NodePools, &out.NodePools
 		*out = make([]LowNodeLoadNodePool, len(*in))
@@ -270,6 +275,11 @@ func (in *LowNodeLoadNodePool) DeepCopyInto(out *LowNodeLoadNodePool) {
 		*out = new(LoadAnomalyCondition)
 		**out = **in
 	}
+	if in.HealthIndexThreshold != nil {
+		in, out := &in.HealthIndexThreshold, &out.HealthIndexThreshold
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
 	gocache "github.com/patrickmn/go-cache"
 	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 	k8spodutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
@@ -66,6 +68,9 @@ type filter struct {
 
 	arbitratedPodMigrationJobs map[types.UID]bool
 	arbitratedMapLock          sync.Mutex
+
+	nodeLister corelisters.NodeLister
+	nrtLister  nrtlisters.NodeResourceTopologyLister
 }
 
 func newFilter(args *deschedulerconfig.MigrationControllerArgs, handle framework.Handle) (*filter, error) {
@@ -79,6 +84,14 @@ func newFilter(args *deschedulerconfig.MigrationControllerArgs, handle framework
 		controllerFinder:           controllerFinder,
 		clock:                      clock.RealClock{},
 		arbitratedPodMigrationJobs: map[types.UID]bool{},
+		nodeLister:                 handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
+	}
+	if args.CheckNUMACapacity {
+		nrtLister, err := newNRTLister(handle)
+		if err != nil {
+			return nil, err
+		}
+		f.nrtLister = nrtLister
 	}
 	if err := f.initFilters(args, handle); err != nil {
 		return nil, err
@@ -133,6 +146,7 @@ func (f *filter) initFilters(args *deschedulerconfig.MigrationControllerArgs, ha
 		f.filterMaxMigratingPerNode,
 		f.filterMaxMigratingPerNamespace,
 		f.filterMaxMigratingOrUnavailablePerWorkload,
+		f.filterNUMACapacity,
 	)
 	f.retryablePodFilter = func(pod *corev1.Pod) bool {
 		return evictionsutil.HaveEvictAnnotation(pod) || retriablePodFilters(pod)
@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"context"
+	"fmt"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/fieldindex"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils"
+	pkgutil "github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// newNRTLister starts a NodeResourceTopology informer and waits for its cache, so the NUMA capacity check
+// reads the topologies from memory instead of the apiserver.
+func newNRTLister(handle framework.Handle) (nrtlisters.NodeResourceTopologyLister, error) {
+	nrtClient, ok := handle.(nrtclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		var err error
+		nrtClient, err = nrtclientset.NewForConfig(&kubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nrtInformerFactory := nrtinformers.NewSharedInformerFactory(nrtClient, 0)
+	nrtInformer := nrtInformerFactory.Topology().V1alpha1().NodeResourceTopologies()
+	nrtInformer.Informer()
+	nrtInformerFactory.Start(context.TODO().Done())
+	nrtInformerFactory.WaitForCacheSync(context.TODO().Done())
+	return nrtInformer.Lister(), nil
+}
+
+// cpuBindRequirement describes how the CPU-bound Pod expects to be bound on the target node.
+type cpuBindRequirement struct {
+	numCPUs            int
+	cpuBindPolicy      apiext.CPUBindPolicy
+	numaTopologyPolicy apiext.NUMATopologyPolicy
+}
+
+// getCPUBindRequirement returns nil if the Pod is not bound to CPUs, so it can't be degraded by the migration.
+func getCPUBindRequirement(pod *corev1.Pod) (*cpuBindRequirement, error) {
+	resourceStatus, err := apiext.GetResourceStatus(pod.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if resourceStatus.CPUSet == "" {
+		return nil, nil
+	}
+	cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+	if err != nil {
+		return nil, err
+	}
+	if cpus.IsEmpty() {
+		return nil, nil
+	}
+	resourceSpec, err := apiext.GetResourceSpec(pod.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	cpuBindPolicy := resourceSpec.RequiredCPUBindPolicy
+	if cpuBindPolicy == "" {
+		cpuBindPolicy = resourceSpec.PreferredCPUBindPolicy
+	}
+	numaTopologySpec, err := apiext.GetNUMATopologySpec(pod.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	return &cpuBindRequirement{
+		numCPUs:            cpus.Size(),
+		cpuBindPolicy:      cpuBindPolicy,
+		numaTopologyPolicy: numaTopologySpec.NUMATopologyPolicy,
+	}, nil
+}
+
+// filterNUMACapacity checks whether another node can host the CPU-bound Pod with an equivalent CPU bind policy,
+// avoiding the evictions that land the Pod in a degraded non-bound state.
+func (f *filter) filterNUMACapacity(pod *corev1.Pod) bool {
+	if !f.args.CheckNUMACapacity {
+		return true
+	}
+	requirement, err := getCPUBindRequirement(pod)
+	if err != nil {
+		klog.V(4).Infof("Pod %q fails to check NUMA capacity because of invalid CPU bind status, err: %v", klog.KObj(pod), err)
+		return false
+	}
+	if requirement == nil {
+		return true
+	}
+
+	nodeSelector := labels.Everything()
+	if f.args.NodeSelector != "" {
+		nodeSelector, err = labels.Parse(f.args.NodeSelector)
+		if err != nil {
+			klog.Errorf("Failed to parse nodeSelector %q, err: %v", f.args.NodeSelector, err)
+			return false
+		}
+	}
+	nodes, err := f.nodeLister.List(nodeSelector)
+	if err != nil {
+		klog.Errorf("Failed to list nodes, err: %v", err)
+		return false
+	}
+	for _, node := range nodes {
+		if node.Name == pod.Spec.NodeName || !nodeutil.IsReady(node) || nodeutil.IsNodeUnschedulable(node) {
+			continue
+		}
+		if err := f.nodeFitsCPUBindRequirement(pod, node, requirement); err != nil {
+			klog.V(5).Infof("Pod %q can not be bound on Node %q with equivalent CPU bind policy, err: %v", klog.KObj(pod), node.Name, err)
+			continue
+		}
+		return true
+	}
+	klog.V(4).Infof("Pod %q fails to check NUMA capacity because no other Node can host its %d bound CPUs", klog.KObj(pod), requirement.numCPUs)
+	return false
+}
+
+func (f *filter) nodeFitsCPUBindRequirement(pod *corev1.Pod, node *corev1.Node, requirement *cpuBindRequirement) error {
+	if ok, err := utils.PodMatchNodeSelector(pod, node); err != nil || !ok {
+		return fmt.Errorf("pod node selector does not match the node label")
+	}
+	if !utils.TolerationsTolerateTaintsWithFilter(pod.Spec.Tolerations, node.Spec.Taints, func(taint *corev1.Taint) bool {
+		return taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute
+	}) {
+		return fmt.Errorf("pod does not tolerate taints on the node")
+	}
+
+	nrt, err := f.nrtLister.Get(node.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get NodeResourceTopology, err: %v", err)
+	}
+	cpuTopology, err := apiext.GetCPUTopology(nrt.Annotations)
+	if err != nil {
+		return fmt.Errorf("invalid cpu topology, err: %v", err)
+	}
+	if cpuTopology == nil || len(cpuTopology.Detail) == 0 {
+		return fmt.Errorf("missing cpu topology")
+	}
+	kubeletCPUPolicy, err := apiext.GetKubeletCPUManagerPolicy(nrt.Annotations)
+	if err != nil {
+		return fmt.Errorf("invalid kubelet cpu manager policy, err: %v", err)
+	}
+
+	cpusPerCore := map[int32]int{}
+	for _, info := range cpuTopology.Detail {
+		cpusPerCore[info.Core]++
+	}
+	threadsPerCore := len(cpuTopology.Detail) / len(cpusPerCore)
+	if threadsPerCore <= 0 {
+		threadsPerCore = 1
+	}
+	fullPCPUs := requirement.cpuBindPolicy == apiext.CPUBindPolicyFullPCPUs
+	if apiext.GetNodeCPUBindPolicy(node.Labels, kubeletCPUPolicy) == apiext.NodeCPUBindPolicyFullPCPUsOnly {
+		if requirement.numCPUs%threadsPerCore != 0 {
+			return fmt.Errorf("node requires full physical cores but the pod is bound to %d cpus", requirement.numCPUs)
+		}
+		fullPCPUs = true
+	}
+
+	allocated, err := f.getAllocatedCPUs(node, nrt, kubeletCPUPolicy)
+	if err != nil {
+		return err
+	}
+
+	// count the free cpus by NUMA node, only the fully free cores are counted for FullPCPUs
+	freeCPUsByCore := map[int32]int{}
+	coreNUMANode := map[int32]int32{}
+	for _, info := range cpuTopology.Detail {
+		coreNUMANode[info.Core] = info.Node
+		if !allocated.Contains(int(info.ID)) {
+			freeCPUsByCore[info.Core]++
+		}
+	}
+	freeCPUsByNUMANode := map[int32]int{}
+	totalFreeCPUs := 0
+	for core, free := range freeCPUsByCore {
+		if fullPCPUs && free < cpusPerCore[core] {
+			continue
+		}
+		freeCPUsByNUMANode[coreNUMANode[core]] += free
+		totalFreeCPUs += free
+	}
+
+	numaTopologyPolicy := requirement.numaTopologyPolicy
+	if numaTopologyPolicy == "" {
+		numaTopologyPolicy = apiext.GetNodeNUMATopologyPolicy(node.Labels)
+	}
+	if numaTopologyPolicy == apiext.NUMATopologyPolicySingleNUMANode {
+		for _, free := range freeCPUsByNUMANode {
+			if free >= requirement.numCPUs {
+				return nil
+			}
+		}
+		return fmt.Errorf("no single NUMA node has %d free cpus", requirement.numCPUs)
+	}
+	if totalFreeCPUs < requirement.numCPUs {
+		return fmt.Errorf("insufficient free cpus, requested %d, free %d", requirement.numCPUs, totalFreeCPUs)
+	}
+	return nil
+}
+
+// getAllocatedCPUs returns the CPUs which can't be allocated to the migrated Pod on the node, including the
+// reserved CPUs and the CPUs bound by the existing Pods.
+func (f *filter) getAllocatedCPUs(node *corev1.Node, nrt *topologyv1alpha1.NodeResourceTopology, kubeletCPUPolicy *apiext.KubeletCPUManagerPolicy) (cpuset.CPUSet, error) {
+	builder := cpuset.NewCPUSetBuilder()
+	addCPUSet := func(cpus string) {
+		if cpus == "" {
+			return
+		}
+		if set, err := cpuset.Parse(cpus); err == nil {
+			builder.Add(set.ToSlice()...)
+		}
+	}
+
+	reservedCPUs, _ := apiext.GetReservedCPUs(node.Annotations)
+	addCPUSet(reservedCPUs)
+	if kubeletCPUPolicy != nil {
+		addCPUSet(kubeletCPUPolicy.ReservedCPUs)
+	}
+	podCPUAllocs, err := apiext.GetPodCPUAllocs(nrt.Annotations)
+	if err != nil {
+		return cpuset.CPUSet{}, fmt.Errorf("invalid pod cpu allocs, err: %v", err)
+	}
+	for _, alloc := range podCPUAllocs {
+		if alloc.ManagedByKubelet {
+			addCPUSet(alloc.CPUSet)
+		}
+	}
+
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{FieldSelector: fields.OneTermEqualSelector(fieldindex.IndexPodByNodeName, node.Name)}
+	if err := f.client.List(context.TODO(), podList, listOpts, utilclient.DisableDeepCopy); err != nil {
+		return cpuset.CPUSet{}, fmt.Errorf("failed to list pods, err: %v", err)
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName != node.Name || pkgutil.IsPodTerminated(pod) {
+			continue
+		}
+		resourceStatus, err := apiext.GetResourceStatus(pod.Annotations)
+		if err != nil {
+			continue
+		}
+		addCPUSet(resourceStatus.CPUSet)
+	}
+	return builder.Result(), nil
+}
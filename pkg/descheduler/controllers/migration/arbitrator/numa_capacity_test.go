@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitrator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+)
+
+func TestFilterNUMACapacity(t *testing.T) {
+	// 2 NUMA nodes, 4 cores per NUMA node, 2 threads per core
+	cpuTopology := &apiext.CPUTopology{}
+	for i := 0; i < 16; i++ {
+		core := int32(i / 2)
+		cpuTopology.Detail = append(cpuTopology.Detail, apiext.CPUInfo{
+			ID:   int32(i),
+			Core: core,
+			Node: core / 4,
+		})
+	}
+	cpuTopologyData, err := json.Marshal(cpuTopology)
+	assert.NoError(t, err)
+
+	newPod := func(name, nodeName, cpus string, spec *apiext.ResourceSpec) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				UID:         uuid.NewUUID(),
+				Annotations: map[string]string{},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		}
+		if cpus != "" {
+			assert.NoError(t, apiext.SetResourceStatus(pod, &apiext.ResourceStatus{CPUSet: cpus}))
+		}
+		if spec != nil {
+			assert.NoError(t, apiext.SetResourceSpec(pod, spec))
+		}
+		return pod
+	}
+	newNode := func(name string, labels map[string]string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+			},
+			Spec: corev1.NodeSpec{
+				Unschedulable: unschedulable,
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+	newNRT := func(name string) *topologyv1alpha1.NodeResourceTopology {
+		return &topologyv1alpha1.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					apiext.AnnotationNodeCPUTopology: string(cpuTopologyData),
+				},
+			},
+		}
+	}
+	fullPCPUsSpec := &apiext.ResourceSpec{PreferredCPUBindPolicy: apiext.CPUBindPolicyFullPCPUs}
+
+	tests := []struct {
+		name              string
+		checkNUMACapacity bool
+		pod               *corev1.Pod
+		numaPolicy        apiext.NUMATopologyPolicy
+		nodes             []*corev1.Node
+		nrts              []*topologyv1alpha1.NodeResourceTopology
+		existingPods      []*corev1.Pod
+		want              bool
+	}{
+		{
+			name: "check disabled",
+			pod:  newPod("test-pod", "test-node-0", "0-3", fullPCPUsSpec),
+			want: true,
+		},
+		{
+			name:              "pod not bound to cpus",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "", nil),
+			want:              true,
+		},
+		{
+			name:              "no other node",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-3", fullPCPUsSpec),
+			nodes:             []*corev1.Node{newNode("test-node-0", nil, false)},
+			nrts:              []*topologyv1alpha1.NodeResourceTopology{newNRT("test-node-0")},
+			want:              false,
+		},
+		{
+			name:              "target node without cpu topology",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-3", fullPCPUsSpec),
+			nodes:             []*corev1.Node{newNode("test-node-1", nil, false)},
+			want:              false,
+		},
+		{
+			name:              "target node has enough free cpus",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-3", fullPCPUsSpec),
+			nodes:             []*corev1.Node{newNode("test-node-1", nil, false)},
+			nrts:              []*topologyv1alpha1.NodeResourceTopology{newNRT("test-node-1")},
+			existingPods: []*corev1.Pod{
+				newPod("test-pod-1", "test-node-1", "0-11", fullPCPUsSpec),
+			},
+			want: true,
+		},
+		{
+			name:              "unschedulable target node",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-3", fullPCPUsSpec),
+			nodes:             []*corev1.Node{newNode("test-node-1", nil, true)},
+			nrts:              []*topologyv1alpha1.NodeResourceTopology{newNRT("test-node-1")},
+			want:              false,
+		},
+		{
+			name:              "target node has no enough full physical cores",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-3", fullPCPUsSpec),
+			nodes:             []*corev1.Node{newNode("test-node-1", nil, false)},
+			nrts:              []*topologyv1alpha1.NodeResourceTopology{newNRT("test-node-1")},
+			existingPods: []*corev1.Pod{
+				newPod("test-pod-1", "test-node-1", "0-10,12,14", &apiext.ResourceSpec{PreferredCPUBindPolicy: apiext.CPUBindPolicySpreadByPCPUs}),
+			},
+			want: false,
+		},
+		{
+			name:              "target node has free cpus across NUMA nodes but requires single NUMA node",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-5", fullPCPUsSpec),
+			numaPolicy:        apiext.NUMATopologyPolicySingleNUMANode,
+			nodes:             []*corev1.Node{newNode("test-node-1", nil, false)},
+			nrts:              []*topologyv1alpha1.NodeResourceTopology{newNRT("test-node-1")},
+			existingPods: []*corev1.Pod{
+				newPod("test-pod-1", "test-node-1", "0-3,8-11", fullPCPUsSpec),
+			},
+			want: false,
+		},
+		{
+			name:              "FullPCPUsOnly target node rejects odd bound cpus",
+			checkNUMACapacity: true,
+			pod:               newPod("test-pod", "test-node-0", "0-2", &apiext.ResourceSpec{PreferredCPUBindPolicy: apiext.CPUBindPolicySpreadByPCPUs}),
+			nodes: []*corev1.Node{
+				newNode("test-node-1", map[string]string{apiext.LabelNodeCPUBindPolicy: string(apiext.NodeCPUBindPolicyFullPCPUsOnly)}, false),
+			},
+			nrts: []*topologyv1alpha1.NodeResourceTopology{newNRT("test-node-1")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+			_ = clientgoscheme.AddToScheme(scheme)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, node := range tt.nodes {
+				assert.NoError(t, nodeIndexer.Add(node))
+			}
+			nrtIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, nrt := range tt.nrts {
+				assert.NoError(t, nrtIndexer.Add(nrt))
+			}
+			for _, pod := range tt.existingPods {
+				assert.NoError(t, fakeClient.Create(context.TODO(), pod))
+			}
+			if tt.numaPolicy != "" {
+				assert.NoError(t, apiext.SetNUMATopologySpec(tt.pod, &apiext.NUMATopologySpec{NUMATopologyPolicy: tt.numaPolicy}))
+			}
+
+			f := &filter{
+				client:     fakeClient,
+				args:       &config.MigrationControllerArgs{CheckNUMACapacity: tt.checkNUMACapacity},
+				nodeLister: corelisters.NewNodeLister(nodeIndexer),
+				nrtLister:  nrtlisters.NewNodeResourceTopologyLister(nrtIndexer),
+			}
+			got := f.filterNUMACapacity(tt.pod)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -166,7 +166,7 @@ func (pl *LowNodeLoad) processOneNodePool(ctx context.Context, nodePool *desched
 	resourceNames := getResourceNames(lowThresholds)
 	nodeUsages := getNodeUsage(nodes, resourceNames, pl.nodeMetricLister, pl.handle.GetPodsAssignedToNodeFunc())
 	nodeThresholds := getNodeThresholds(nodeUsages, lowThresholds, highThresholds, resourceNames, nodePool.UseDeviationThresholds)
-	lowNodes, sourceNodes := classifyNodes(nodeUsages, nodeThresholds, lowThresholdFilter, highThresholdFilter)
+	lowNodes, sourceNodes := classifyNodes(nodeUsages, nodeThresholds, newLowThresholdFilter(nodePool.HealthIndexThreshold), highThresholdFilter)
 
 	logUtilizationCriteria(nodePool.Name, "Criteria for nodes under low thresholds and above high thresholds", lowThresholds, highThresholds, len(lowNodes), len(sourceNodes), len(nodes))
 
@@ -321,6 +321,21 @@ func lowThresholdFilter(usage *NodeUsage, threshold NodeThresholds) bool {
 	return isNodeUnderutilized(usage.usage, threshold.lowResourceThreshold)
 }
 
+// newLowThresholdFilter returns the lowThresholdFilter which additionally excludes
+// the nodes whose health index reaches the healthIndexThreshold.
+func newLowThresholdFilter(healthIndexThreshold *int64) func(usage *NodeUsage, threshold NodeThresholds) bool {
+	if healthIndexThreshold == nil || *healthIndexThreshold <= 0 {
+		return lowThresholdFilter
+	}
+	return func(usage *NodeUsage, threshold NodeThresholds) bool {
+		if usage.healthIndex != nil && *usage.healthIndex >= *healthIndexThreshold {
+			klog.V(4).InfoS("Node is unhealthy, thus not considered as underutilized", "node", klog.KObj(usage.node), "healthIndex", *usage.healthIndex)
+			return false
+		}
+		return lowThresholdFilter(usage, threshold)
+	}
+}
+
 func highThresholdFilter(usage *NodeUsage, threshold NodeThresholds) bool {
 	_, overutilized := isNodeOverutilized(usage.usage, threshold.highResourceThreshold)
 	return overutilized
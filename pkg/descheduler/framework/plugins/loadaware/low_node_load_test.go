@@ -34,6 +34,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	coretesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/pointer"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	koordinatorclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
@@ -1219,6 +1220,55 @@ func Test_filterNodes(t *testing.T) {
 	}
 }
 
+func Test_newLowThresholdFilter(t *testing.T) {
+	threshold := NodeThresholds{
+		lowResourceThreshold: map[corev1.ResourceName]*resource.Quantity{
+			corev1.ResourceCPU: resource.NewMilliQuantity(2000, resource.DecimalSI),
+		},
+	}
+	tests := []struct {
+		name                 string
+		healthIndexThreshold *int64
+		healthIndex          *int64
+		want                 bool
+	}{
+		{
+			name: "health index threshold not set",
+			want: true,
+		},
+		{
+			name:                 "node without health index",
+			healthIndexThreshold: pointer.Int64(60),
+			want:                 true,
+		},
+		{
+			name:                 "healthy node",
+			healthIndexThreshold: pointer.Int64(60),
+			healthIndex:          pointer.Int64(30),
+			want:                 true,
+		},
+		{
+			name:                 "unhealthy node",
+			healthIndexThreshold: pointer.Int64(60),
+			healthIndex:          pointer.Int64(60),
+			want:                 false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := &NodeUsage{
+				node: test.BuildTestNode("test-node", 4000, 3000, 10, nil),
+				usage: map[corev1.ResourceName]*resource.Quantity{
+					corev1.ResourceCPU: resource.NewMilliQuantity(1000, resource.DecimalSI),
+				},
+				healthIndex: tt.healthIndex,
+			}
+			filter := newLowThresholdFilter(tt.healthIndexThreshold)
+			assert.Equal(t, tt.want, filter(usage, threshold))
+		})
+	}
+}
+
 func Test_resetNodesAsNormal(t *testing.T) {
 	node := NodeInfo{
 		NodeUsage: &NodeUsage{
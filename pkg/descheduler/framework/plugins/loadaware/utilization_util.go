@@ -39,10 +39,11 @@ type Percentage = deschedulerconfig.Percentage
 type ResourceThresholds = deschedulerconfig.ResourceThresholds
 
 type NodeUsage struct {
-	node       *corev1.Node
-	allPods    []*corev1.Pod
-	usage      map[corev1.ResourceName]*resource.Quantity
-	podMetrics map[types.NamespacedName]*slov1alpha1.ResourceMap
+	node        *corev1.Node
+	allPods     []*corev1.Pod
+	usage       map[corev1.ResourceName]*resource.Quantity
+	podMetrics  map[types.NamespacedName]*slov1alpha1.ResourceMap
+	healthIndex *int64
 }
 
 type NodeThresholds struct {
@@ -175,11 +176,17 @@ func getNodeUsage(nodes []*corev1.Node, resourceNames []corev1.ResourceName, nod
 			podMetrics[types.NamespacedName{Namespace: podMetric.Namespace, Name: podMetric.Name}] = podMetric.PodUsage.DeepCopy()
 		}
 
+		var healthIndex *int64
+		if nodeMetric.Status.NodeMetric.HealthIndex != nil {
+			healthIndex = nodeMetric.Status.NodeMetric.HealthIndex.Index
+		}
+
 		nodeUsages[v.Name] = &NodeUsage{
-			node:       v,
-			allPods:    pods,
-			usage:      usage,
-			podMetrics: podMetrics,
+			node:        v,
+			allPods:     pods,
+			usage:       usage,
+			podMetrics:  podMetrics,
+			healthIndex: healthIndex,
 		}
 	}
 
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedrain
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+)
+
+const (
+	CoordinatedDrainName = "CoordinatedDrain"
+)
+
+var _ framework.DeschedulePlugin = &CoordinatedDrain{}
+
+// CoordinatedDrain migrates the pods bound to CPUs or NUMA nodes away from the nodes
+// which are cordoned and drained for maintenance. The pods are migrated in ReservationFirst mode,
+// so that the resources on the target node are reserved before the pods are evicted.
+type CoordinatedDrain struct {
+	handle    framework.Handle
+	podFilter framework.FilterFunc
+}
+
+// NewCoordinatedDrain builds plugin from its arguments while passing a handle
+func NewCoordinatedDrain(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	podFilter, err := podutil.NewOptions().
+		WithFilter(podutil.WrapFilterFuncs(extension.IsPodResourceBound, handle.Evictor().Filter)).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+	return &CoordinatedDrain{
+		handle:    handle,
+		podFilter: podFilter,
+	}, nil
+}
+
+// Name retrieves the plugin name
+func (pl *CoordinatedDrain) Name() string {
+	return CoordinatedDrainName
+}
+
+// Deschedule extension point implementation for the plugin
+func (pl *CoordinatedDrain) Deschedule(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	ctx = migration.WithContext(ctx, &migration.JobContext{
+		Mode: sev1alpha1.PodMigrationJobModeReservationFirst,
+	})
+	for _, node := range nodes {
+		if !extension.IsNodeCoordinatedDraining(node) {
+			continue
+		}
+		pods, err := podutil.ListPodsOnANode(node.Name, pl.handle.GetPodsAssignedToNodeFunc(), pl.podFilter)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list pods on the draining node", "node", klog.KObj(node))
+			continue
+		}
+		klog.V(4).InfoS("Migrating the bound pods on the draining node", "node", klog.KObj(node), "pods", len(pods))
+		for _, pod := range pods {
+			evictOptions := framework.EvictOptions{
+				PluginName: CoordinatedDrainName,
+				Reason:     "node is draining for maintenance",
+			}
+			if !pl.handle.Evictor().Evict(ctx, pod, evictOptions) {
+				klog.InfoS("Failed to Evict Pod", "pod", klog.KObj(pod), "node", klog.KObj(node))
+				continue
+			}
+			klog.InfoS("Evicted Pod", "pod", klog.KObj(pod), "node", klog.KObj(node))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedrain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+)
+
+type fakeEvictor struct {
+	evicted []string
+	modes   []sev1alpha1.PodMigrationJobMode
+}
+
+func (f *fakeEvictor) Filter(pod *corev1.Pod) bool {
+	return true
+}
+
+func (f *fakeEvictor) PreEvictionFilter(pod *corev1.Pod) bool {
+	return true
+}
+
+func (f *fakeEvictor) Evict(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions) bool {
+	f.evicted = append(f.evicted, pod.Name)
+	if jobCtx := migration.FromContext(ctx); jobCtx != nil {
+		f.modes = append(f.modes, jobCtx.Mode)
+	}
+	return true
+}
+
+type fakeFrameworkHandle struct {
+	framework.Handle
+	evictor *fakeEvictor
+	pods    []*corev1.Pod
+}
+
+func (f *fakeFrameworkHandle) Evictor() framework.Evictor {
+	return f.evictor
+}
+
+func (f *fakeFrameworkHandle) GetPodsAssignedToNodeFunc() framework.GetPodsAssignedToNodeFunc {
+	return func(nodeName string, filter framework.FilterFunc) ([]*corev1.Pod, error) {
+		var pods []*corev1.Pod
+		for _, pod := range f.pods {
+			if pod.Spec.NodeName == nodeName && (filter == nil || filter(pod)) {
+				pods = append(pods, pod)
+			}
+		}
+		return pods, nil
+	}
+}
+
+func newTestPod(t *testing.T, name, nodeName string, status *extension.ResourceStatus) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+	if status != nil {
+		assert.NoError(t, extension.SetResourceStatus(pod, status))
+	}
+	return pod
+}
+
+func TestCoordinatedDrain(t *testing.T) {
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "draining-node",
+				Labels: map[string]string{
+					extension.LabelNodeCoordinatedDrain: "true",
+				},
+			},
+			Spec: corev1.NodeSpec{
+				Unschedulable: true,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cordoned-node",
+			},
+			Spec: corev1.NodeSpec{
+				Unschedulable: true,
+			},
+		},
+	}
+	pods := []*corev1.Pod{
+		newTestPod(t, "cpuset-pod", "draining-node", &extension.ResourceStatus{CPUSet: "0-3"}),
+		newTestPod(t, "numa-pod", "draining-node", &extension.ResourceStatus{
+			NUMANodeResources: []extension.NUMANodeResource{{Node: 0}},
+		}),
+		newTestPod(t, "shared-pod", "draining-node", nil),
+		newTestPod(t, "other-node-pod", "cordoned-node", &extension.ResourceStatus{CPUSet: "0-3"}),
+	}
+	handle := &fakeFrameworkHandle{
+		evictor: &fakeEvictor{},
+		pods:    pods,
+	}
+	pl, err := NewCoordinatedDrain(nil, handle)
+	assert.NoError(t, err)
+	assert.Nil(t, pl.(framework.DeschedulePlugin).Deschedule(context.TODO(), nodes))
+	assert.Equal(t, []string{"cpuset-pod", "numa-pod"}, handle.evictor.evicted)
+	assert.Equal(t, []sev1alpha1.PodMigrationJobMode{
+		sev1alpha1.PodMigrationJobModeReservationFirst,
+		sev1alpha1.PodMigrationJobModeReservationFirst,
+	}, handle.evictor.modes)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedrain
+
+import (
+	"context"
+	"fmt"
+
+	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	NUMADrainName = "NUMADrain"
+)
+
+var _ framework.DeschedulePlugin = &NUMADrain{}
+
+// NUMADrain migrates the pods bound to the NUMA Nodes drained for maintenance, while the other pods keep running
+// on the node. The pods are migrated in ReservationFirst mode like the CoordinatedDrain.
+type NUMADrain struct {
+	handle    framework.Handle
+	podFilter framework.FilterFunc
+	// getCPUTopology returns the CPU topology of the node, which maps the bound CPUs to the NUMA Nodes.
+	getCPUTopology func(nodeName string) (*extension.CPUTopology, error)
+}
+
+// NewNUMADrain builds plugin from its arguments while passing a handle
+func NewNUMADrain(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	nrtLister, err := newNRTLister(handle)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing NodeResourceTopology lister: %v", err)
+	}
+	getCPUTopology := func(nodeName string) (*extension.CPUTopology, error) {
+		nrt, err := nrtLister.Get(nodeName)
+		if err != nil {
+			return nil, err
+		}
+		return extension.GetCPUTopology(nrt.Annotations)
+	}
+	pl, err := newNUMADrain(handle, getCPUTopology)
+	if err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+// newNRTLister starts a NodeResourceTopology informer and waits for its cache. The CRD is not served in protobuf,
+// so the client requests JSON regardless of the content type of the descheduler kubeconfig.
+func newNRTLister(handle framework.Handle) (nrtlisters.NodeResourceTopologyLister, error) {
+	nrtClient, ok := handle.(nrtclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		var err error
+		nrtClient, err = nrtclientset.NewForConfig(&kubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nrtInformerFactory := nrtinformers.NewSharedInformerFactory(nrtClient, 0)
+	nrtInformer := nrtInformerFactory.Topology().V1alpha1().NodeResourceTopologies()
+	nrtInformer.Informer()
+	nrtInformerFactory.Start(context.TODO().Done())
+	nrtInformerFactory.WaitForCacheSync(context.TODO().Done())
+	return nrtInformer.Lister(), nil
+}
+
+func newNUMADrain(handle framework.Handle, getCPUTopology func(nodeName string) (*extension.CPUTopology, error)) (*NUMADrain, error) {
+	podFilter, err := podutil.NewOptions().
+		WithFilter(podutil.WrapFilterFuncs(extension.IsPodResourceBound, handle.Evictor().Filter)).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+	return &NUMADrain{
+		handle:         handle,
+		podFilter:      podFilter,
+		getCPUTopology: getCPUTopology,
+	}, nil
+}
+
+// Name retrieves the plugin name
+func (pl *NUMADrain) Name() string {
+	return NUMADrainName
+}
+
+// Deschedule extension point implementation for the plugin
+func (pl *NUMADrain) Deschedule(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	ctx = migration.WithContext(ctx, &migration.JobContext{
+		Mode: sev1alpha1.PodMigrationJobModeReservationFirst,
+	})
+	for _, node := range nodes {
+		drainedNUMANodes, err := extension.GetNodeDrainedNUMANodes(node.Annotations)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get the drained NUMA Nodes", "node", klog.KObj(node))
+			continue
+		}
+		if len(drainedNUMANodes) == 0 {
+			continue
+		}
+		pods, err := podutil.ListPodsOnANode(node.Name, pl.handle.GetPodsAssignedToNodeFunc(), pl.podFilter)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list pods on the node with drained NUMA Nodes", "node", klog.KObj(node))
+			continue
+		}
+		resolver := &cpuNUMANodeResolver{nodeName: node.Name, getCPUTopology: pl.getCPUTopology}
+		for _, pod := range pods {
+			bound, err := isPodBoundToNUMANodes(pod, drainedNUMANodes, resolver)
+			if err != nil {
+				klog.ErrorS(err, "Failed to check whether the pod is bound to the drained NUMA Nodes",
+					"pod", klog.KObj(pod), "node", klog.KObj(node))
+				continue
+			}
+			if !bound {
+				continue
+			}
+			evictOptions := framework.EvictOptions{
+				PluginName: NUMADrainName,
+				Reason:     fmt.Sprintf("NUMA Nodes %v are draining for maintenance", drainedNUMANodes),
+			}
+			if !pl.handle.Evictor().Evict(ctx, pod, evictOptions) {
+				klog.InfoS("Failed to Evict Pod", "pod", klog.KObj(pod), "node", klog.KObj(node))
+				continue
+			}
+			klog.InfoS("Evicted Pod", "pod", klog.KObj(pod), "node", klog.KObj(node))
+		}
+	}
+	return nil
+}
+
+// cpuNUMANodeResolver maps the CPUs of the node to the NUMA Nodes. The CPU topology is loaded once when the first
+// pod bound to the CPUs without the NUMA Node resources is checked, and a failed load is not retried in the cycle.
+type cpuNUMANodeResolver struct {
+	nodeName       string
+	getCPUTopology func(nodeName string) (*extension.CPUTopology, error)
+	cpuNUMANodes   map[int]int
+	err            error
+}
+
+func (r *cpuNUMANodeResolver) getNUMANode(cpu int) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.cpuNUMANodes == nil {
+		cpuTopology, err := r.getCPUTopology(r.nodeName)
+		if err == nil && (cpuTopology == nil || len(cpuTopology.Detail) == 0) {
+			err = fmt.Errorf("missing cpu topology")
+		}
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		cpuNUMANodes := make(map[int]int, len(cpuTopology.Detail))
+		for _, cpuInfo := range cpuTopology.Detail {
+			cpuNUMANodes[int(cpuInfo.ID)] = int(cpuInfo.Node)
+		}
+		r.cpuNUMANodes = cpuNUMANodes
+	}
+	numaNode, ok := r.cpuNUMANodes[cpu]
+	if !ok {
+		return 0, fmt.Errorf("cpu %d not found in cpu topology", cpu)
+	}
+	return numaNode, nil
+}
+
+// isPodBoundToNUMANodes checks whether the pod is allocated with the resources or the CPUs of the NUMA Nodes.
+func isPodBoundToNUMANodes(pod *corev1.Pod, numaNodes []int, resolver *cpuNUMANodeResolver) (bool, error) {
+	resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+	if err != nil {
+		return false, err
+	}
+	if len(resourceStatus.NUMANodeResources) > 0 {
+		// the bound CPUs are in the allocated NUMA Nodes
+		for _, numaNodeResource := range resourceStatus.NUMANodeResources {
+			if containsNUMANode(numaNodes, int(numaNodeResource.Node)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	if resourceStatus.CPUSet == "" {
+		return false, nil
+	}
+	cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+	if err != nil {
+		return false, err
+	}
+	for _, cpu := range cpus.ToSliceNoSort() {
+		numaNode, err := resolver.getNUMANode(cpu)
+		if err != nil {
+			return false, err
+		}
+		if containsNUMANode(numaNodes, numaNode) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func containsNUMANode(numaNodes []int, numaNode int) bool {
+	for _, v := range numaNodes {
+		if v == numaNode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedrain
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestNUMADrain(t *testing.T) {
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "numa-drained-node",
+				Annotations: map[string]string{
+					extension.AnnotationNodeDrainedNUMANodes: "[1]",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "normal-node",
+			},
+		},
+	}
+	pods := []*corev1.Pod{
+		newTestPod(t, "numa-0-pod", "numa-drained-node", &extension.ResourceStatus{
+			CPUSet:            "0-1",
+			NUMANodeResources: []extension.NUMANodeResource{{Node: 0}},
+		}),
+		newTestPod(t, "numa-1-pod", "numa-drained-node", &extension.ResourceStatus{
+			NUMANodeResources: []extension.NUMANodeResource{{Node: 1}},
+		}),
+		newTestPod(t, "cpuset-0-pod", "numa-drained-node", &extension.ResourceStatus{CPUSet: "2-3"}),
+		newTestPod(t, "cpuset-1-pod", "numa-drained-node", &extension.ResourceStatus{CPUSet: "3-4"}),
+		newTestPod(t, "shared-pod", "numa-drained-node", nil),
+		newTestPod(t, "other-node-pod", "normal-node", &extension.ResourceStatus{
+			NUMANodeResources: []extension.NUMANodeResource{{Node: 1}},
+		}),
+	}
+	handle := &fakeFrameworkHandle{
+		evictor: &fakeEvictor{},
+		pods:    pods,
+	}
+	// CPUs 0-3 are on NUMA Node 0, and CPUs 4-7 are on NUMA Node 1
+	cpuTopology := &extension.CPUTopology{}
+	for i := int32(0); i < 8; i++ {
+		cpuTopology.Detail = append(cpuTopology.Detail, extension.CPUInfo{ID: i, Core: i / 2, Node: i / 4, Socket: i / 4})
+	}
+	var topologyRequests []string
+	pl, err := newNUMADrain(handle, func(nodeName string) (*extension.CPUTopology, error) {
+		topologyRequests = append(topologyRequests, nodeName)
+		return cpuTopology, nil
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, pl.Deschedule(context.TODO(), nodes))
+	assert.Equal(t, []string{"numa-1-pod", "cpuset-1-pod"}, handle.evictor.evicted)
+	assert.Equal(t, []sev1alpha1.PodMigrationJobMode{
+		sev1alpha1.PodMigrationJobModeReservationFirst,
+		sev1alpha1.PodMigrationJobModeReservationFirst,
+	}, handle.evictor.modes)
+	assert.Equal(t, []string{"numa-drained-node"}, topologyRequests, "the cpu topology should be loaded once")
+}
+
+func TestNUMADrainWithoutCPUTopology(t *testing.T) {
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "numa-drained-node",
+				Annotations: map[string]string{
+					extension.AnnotationNodeDrainedNUMANodes: "[1]",
+				},
+			},
+		},
+	}
+	pods := []*corev1.Pod{
+		newTestPod(t, "cpuset-0-pod", "numa-drained-node", &extension.ResourceStatus{CPUSet: "2-3"}),
+		newTestPod(t, "cpuset-1-pod", "numa-drained-node", &extension.ResourceStatus{CPUSet: "4-5"}),
+		newTestPod(t, "numa-1-pod", "numa-drained-node", &extension.ResourceStatus{
+			NUMANodeResources: []extension.NUMANodeResource{{Node: 1}},
+		}),
+	}
+	handle := &fakeFrameworkHandle{
+		evictor: &fakeEvictor{},
+		pods:    pods,
+	}
+	var topologyRequests []string
+	pl, err := newNUMADrain(handle, func(nodeName string) (*extension.CPUTopology, error) {
+		topologyRequests = append(topologyRequests, nodeName)
+		return nil, fmt.Errorf("not found")
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, pl.Deschedule(context.TODO(), nodes))
+	assert.Equal(t, []string{"numa-1-pod"}, handle.evictor.evicted)
+	assert.Equal(t, []string{"numa-drained-node"}, topologyRequests, "the failed cpu topology should not be reloaded")
+}
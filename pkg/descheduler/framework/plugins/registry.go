@@ -19,12 +19,15 @@ package plugins
 import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/kubernetes"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/loadaware"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/nodedrain"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/runtime"
 )
 
 func NewInTreeRegistry() runtime.Registry {
 	registry := runtime.Registry{
-		loadaware.LowNodeLoadName: loadaware.NewLowNodeLoad,
+		loadaware.LowNodeLoadName:      loadaware.NewLowNodeLoad,
+		nodedrain.CoordinatedDrainName: nodedrain.NewCoordinatedDrain,
+		nodedrain.NUMADrainName:        nodedrain.NewNUMADrain,
 	}
 	kubernetes.SetupK8sDeschedulerPlugins(registry)
 	return registry
@@ -39,9 +39,15 @@ const (
 	// NodeValidatingWebhook enables validating webhook for Node Creation or updates
 	NodeValidatingWebhook featuregate.Feature = "NodeValidatingWebhook"
 
+	// NodeMutatingWebhook enables mutating webhook for Node Creation or updates
+	NodeMutatingWebhook featuregate.Feature = "NodeMutatingWebhook"
+
 	// ConfigMapValidatingWebhook enables validating webhook for configmap Creation or updates
 	ConfigMapValidatingWebhook featuregate.Feature = "ConfigMapValidatingWebhook"
 
+	// CPUOrchestrationPolicyValidatingWebhook enables validating webhook for CPUOrchestrationPolicy creations or updates
+	CPUOrchestrationPolicyValidatingWebhook featuregate.Feature = "CPUOrchestrationPolicyValidatingWebhook"
+
 	// ColocationProfileSkipMutatingResources config whether to update resourceName according to priority by default
 	ColocationProfileSkipMutatingResources featuregate.Feature = "ColocationProfileSkipMutatingResources"
 
@@ -61,21 +67,39 @@ const (
 
 	// DisableDefaultQuota disable default quota.
 	DisableDefaultQuota featuregate.Feature = "DisableDefaultQuota"
+
+	// ExtensionAnnotationValidatingWebhook enables validating the extension annotations of pods strictly against their
+	// schemas, e.g. the resource-spec and the resource-status
+	ExtensionAnnotationValidatingWebhook featuregate.Feature = "ExtensionAnnotationValidatingWebhook"
+
+	// NodeSLOColocationReadiness holds back the NodeSLO strategies which are unsupported by the node according to the
+	// colocation readiness reported in the NodeMetric status. The batch resources are reset if the node fails the
+	// kernel version or the cgroup controllers check.
+	NodeSLOColocationReadiness featuregate.Feature = "NodeSLOColocationReadiness"
+
+	// NodeSLOThresholdSimulation serves the what-if API on the metrics address, which estimates how often the proposed
+	// NodeSLO thresholds would have triggered the suppression and the eviction by the NodeMetric history.
+	NodeSLOThresholdSimulation featuregate.Feature = "NodeSLOThresholdSimulation"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-	PodMutatingWebhook:                     {Default: true, PreRelease: featuregate.Beta},
-	PodValidatingWebhook:                   {Default: true, PreRelease: featuregate.Beta},
-	ElasticQuotaMutatingWebhook:            {Default: true, PreRelease: featuregate.Beta},
-	ElasticQuotaValidatingWebhook:          {Default: true, PreRelease: featuregate.Beta},
-	NodeValidatingWebhook:                  {Default: false, PreRelease: featuregate.Alpha},
-	ConfigMapValidatingWebhook:             {Default: false, PreRelease: featuregate.Alpha},
-	WebhookFramework:                       {Default: true, PreRelease: featuregate.Beta},
-	ColocationProfileSkipMutatingResources: {Default: false, PreRelease: featuregate.Alpha},
-	MultiQuotaTree:                         {Default: false, PreRelease: featuregate.Alpha},
-	ElasticQuotaIgnorePodOverhead:          {Default: false, PreRelease: featuregate.Alpha},
-	ElasticQuotaGuaranteeUsage:             {Default: false, PreRelease: featuregate.Alpha},
-	DisableDefaultQuota:                    {Default: false, PreRelease: featuregate.Alpha},
+	PodMutatingWebhook:                      {Default: true, PreRelease: featuregate.Beta},
+	PodValidatingWebhook:                    {Default: true, PreRelease: featuregate.Beta},
+	ElasticQuotaMutatingWebhook:             {Default: true, PreRelease: featuregate.Beta},
+	ElasticQuotaValidatingWebhook:           {Default: true, PreRelease: featuregate.Beta},
+	NodeValidatingWebhook:                   {Default: false, PreRelease: featuregate.Alpha},
+	NodeMutatingWebhook:                     {Default: false, PreRelease: featuregate.Alpha},
+	ConfigMapValidatingWebhook:              {Default: false, PreRelease: featuregate.Alpha},
+	CPUOrchestrationPolicyValidatingWebhook: {Default: false, PreRelease: featuregate.Alpha},
+	WebhookFramework:                        {Default: true, PreRelease: featuregate.Beta},
+	ColocationProfileSkipMutatingResources:  {Default: false, PreRelease: featuregate.Alpha},
+	MultiQuotaTree:                          {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuotaIgnorePodOverhead:           {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuotaGuaranteeUsage:              {Default: false, PreRelease: featuregate.Alpha},
+	DisableDefaultQuota:                     {Default: false, PreRelease: featuregate.Alpha},
+	ExtensionAnnotationValidatingWebhook:    {Default: false, PreRelease: featuregate.Alpha},
+	NodeSLOColocationReadiness:              {Default: false, PreRelease: featuregate.Alpha},
+	NodeSLOThresholdSimulation:              {Default: false, PreRelease: featuregate.Alpha},
 }
 
 const (
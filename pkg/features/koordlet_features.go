@@ -64,6 +64,24 @@ const (
 	// BEMemoryEvict evict best-effort pod based on node memory usage.
 	BEMemoryEvict featuregate.Feature = "BEMemoryEvict"
 
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// BEMemoryThrottle tightens the memory.high of best-effort pods step by step based on node memory usage.
+	BEMemoryThrottle featuregate.Feature = "BEMemoryThrottle"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// BEPageCacheLimit bounds the page cache of best-effort pods.
+	BEPageCacheLimit featuregate.Feature = "BEPageCacheLimit"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// BEColdMemorySwapOut proactively swaps out the cold pages of best-effort pods on the swap-enabled nodes.
+	BEColdMemorySwapOut featuregate.Feature = "BEColdMemorySwapOut"
+
 	// owner: @saintube @zwzhang0107
 	// alpha: v0.2
 	// beta: v1.1
@@ -133,6 +151,136 @@ const (
 	//
 	// ColdPageCollector enables coldPageCollector feature of koordlet.
 	ColdPageCollector featuregate.Feature = "ColdPageCollector"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// IRQSteering steers the device interrupts away from the CPUs bound by pods whose IRQSteeringPolicy is Isolated.
+	IRQSteering featuregate.Feature = "IRQSteering"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// NodeHealthIndex collects the node interference signals and reports the composite health index in the NodeMetric.
+	NodeHealthIndex featuregate.Feature = "NodeHealthIndex"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CgroupUpdateVerification reads back the cgroup files after the resource executor updates them, and checks the
+	// tasks' CPU affinity after updating cpuset.cpus, to detect the updates not applied by the kernel.
+	CgroupUpdateVerification featuregate.Feature = "CgroupUpdateVerification"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// PreferredCPUSet applies the preferred cpuset to the LS pods in the Soft CPUBindMode, and widens it to the
+	// CPU share pool when the pod usage is close to the capacity of the preferred cpuset.
+	PreferredCPUSet featuregate.Feature = "PreferredCPUSet"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// SchedStatCollector collects the task migrations and context switches of the containers, to measure the task
+	// thrash caused by the cpuset changes.
+	SchedStatCollector featuregate.Feature = "SchedStatCollector"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CoordinatedDrain reports the final CPU and NUMA allocations of the pods as a node event when the node is cordoned
+	// and drained for maintenance in the coordinated mode.
+	CoordinatedDrain featuregate.Feature = "CoordinatedDrain"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// HeterogeneousMemory reports the memory of the NUMA nodes with CPUs as DRAM and the memory of the CPU-less NUMA
+	// nodes (e.g. PMEM, CXL) as slow memory, and binds the memory of the pods to the allocated NUMA nodes of each tier.
+	HeterogeneousMemory featuregate.Feature = "HeterogeneousMemory"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// SelfResourceGovernance pins and limits the cgroup of koordlet itself according to the configuration, reports the
+	// koordlet overhead, and excludes the pinned CPUs from the best-effort CPU suppression.
+	SelfResourceGovernance featuregate.Feature = "SelfResourceGovernance"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CPUSetVerification verifies the cpuset of the containers of the LSE/LSR pods against the allocated cpuset, and
+	// reports the result as the pod condition, to tell whether the CPU binding is actually enforced.
+	CPUSetVerification featuregate.Feature = "CPUSetVerification"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CgroupRestartRecovery rebuilds the intended cgroups of the pods after koordlet restarts, and only updates the
+	// drifted ones in the order of the pod priority instead of rewriting all the cgroups.
+	CgroupRestartRecovery featuregate.Feature = "CgroupRestartRecovery"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// ResctrlTaskWatcher watches the container creations and adds the tasks of the new or restarted containers into
+	// the resctrl groups promptly, instead of waiting for the periodic resctrl reconciliation.
+	ResctrlTaskWatcher featuregate.Feature = "ResctrlTaskWatcher"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// HostApplicationCollector collects the resource usage of the host applications declared in the NodeSLO.
+	HostApplicationCollector featuregate.Feature = "HostApplicationCollector"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CPUStealAnomaly collects the CPU steal of the node running in a VM, reports it in the NodeMetric, and sets the
+	// CPUStealAnomaly condition when the steal exceeds the threshold, so the node is considered as reduced-capacity.
+	CPUStealAnomaly featuregate.Feature = "CPUStealAnomaly"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CPUAffinityObservation compares the allowed CPUs of the container main processes of the LSE/LSR pods with the
+	// allocated cpuset, and reports the drift caused by the applications overriding the affinity, e.g. by taskset.
+	CPUAffinityObservation featuregate.Feature = "CPUAffinityObservation"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// ColocationReadiness checks the kernel version, cgroup controllers, sched features and memcg QoS interfaces of
+	// the node, and reports the readiness score and the ColocationReady condition in the NodeMetric status.
+	ColocationReadiness featuregate.Feature = "ColocationReadiness"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// CPUSetPropagationCheck measures how long the kernel takes to propagate the cpuset.cpus written to a parent cgroup
+	// to its children, and re-writes the children which are not propagated in time.
+	CPUSetPropagationCheck featuregate.Feature = "CPUSetPropagationCheck"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// GPUOverQuotaEvict evicts the pods using more GPU memory than allocated on the shared GPUs, and reports the GPU
+	// usage of the pods against their allocations.
+	GPUOverQuotaEvict featuregate.Feature = "GPUOverQuotaEvict"
+
+	// owner: @saintube
+	// alpha: v1.4
+	//
+	// SharedPoolCompaction compacts the share pool cpuset applied by the cpuset runtime hook to the non-bound pods, which
+	// shrinks the share pool at once as the exclusive cpus grow and expands it with the rate limit.
+	SharedPoolCompaction featuregate.Feature = "SharedPoolCompaction"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// NICTopologyReport reports the physical NICs with their NUMA Nodes, speeds and SR-IOV capabilities to the
+	// Device and the NodeResourceTopology, and refreshes them on the link changes.
+	NICTopologyReport featuregate.Feature = "NICTopologyReport"
 )
 
 func init() {
@@ -144,23 +292,45 @@ var (
 	DefaultKoordletFeatureGate        featuregate.FeatureGate        = DefaultMutableKoordletFeatureGate
 
 	defaultKoordletFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-		AuditEvents:            {Default: false, PreRelease: featuregate.Alpha},
-		AuditEventsHTTPHandler: {Default: false, PreRelease: featuregate.Alpha},
-		BECPUSuppress:          {Default: true, PreRelease: featuregate.Beta},
-		BECPUManager:           {Default: false, PreRelease: featuregate.Alpha},
-		BECPUEvict:             {Default: false, PreRelease: featuregate.Alpha},
-		BEMemoryEvict:          {Default: false, PreRelease: featuregate.Alpha},
-		CPUBurst:               {Default: true, PreRelease: featuregate.Beta},
-		SystemConfig:           {Default: false, PreRelease: featuregate.Alpha},
-		RdtResctrl:             {Default: true, PreRelease: featuregate.Beta},
-		CgroupReconcile:        {Default: false, PreRelease: featuregate.Alpha},
-		NodeTopologyReport:     {Default: true, PreRelease: featuregate.Beta},
-		Accelerators:           {Default: false, PreRelease: featuregate.Alpha},
-		CPICollector:           {Default: false, PreRelease: featuregate.Alpha},
-		Libpfm4:                {Default: false, PreRelease: featuregate.Alpha},
-		PSICollector:           {Default: false, PreRelease: featuregate.Alpha},
-		BlkIOReconcile:         {Default: false, PreRelease: featuregate.Alpha},
-		ColdPageCollector:      {Default: false, PreRelease: featuregate.Alpha},
+		AuditEvents:              {Default: false, PreRelease: featuregate.Alpha},
+		AuditEventsHTTPHandler:   {Default: false, PreRelease: featuregate.Alpha},
+		BECPUSuppress:            {Default: true, PreRelease: featuregate.Beta},
+		BECPUManager:             {Default: false, PreRelease: featuregate.Alpha},
+		BECPUEvict:               {Default: false, PreRelease: featuregate.Alpha},
+		BEMemoryEvict:            {Default: false, PreRelease: featuregate.Alpha},
+		BEMemoryThrottle:         {Default: false, PreRelease: featuregate.Alpha},
+		BEPageCacheLimit:         {Default: false, PreRelease: featuregate.Alpha},
+		BEColdMemorySwapOut:      {Default: false, PreRelease: featuregate.Alpha},
+		CPUBurst:                 {Default: true, PreRelease: featuregate.Beta},
+		SystemConfig:             {Default: false, PreRelease: featuregate.Alpha},
+		RdtResctrl:               {Default: true, PreRelease: featuregate.Beta},
+		CgroupReconcile:          {Default: false, PreRelease: featuregate.Alpha},
+		NodeTopologyReport:       {Default: true, PreRelease: featuregate.Beta},
+		Accelerators:             {Default: false, PreRelease: featuregate.Alpha},
+		CPICollector:             {Default: false, PreRelease: featuregate.Alpha},
+		Libpfm4:                  {Default: false, PreRelease: featuregate.Alpha},
+		PSICollector:             {Default: false, PreRelease: featuregate.Alpha},
+		BlkIOReconcile:           {Default: false, PreRelease: featuregate.Alpha},
+		ColdPageCollector:        {Default: false, PreRelease: featuregate.Alpha},
+		IRQSteering:              {Default: false, PreRelease: featuregate.Alpha},
+		NodeHealthIndex:          {Default: false, PreRelease: featuregate.Alpha},
+		CgroupUpdateVerification: {Default: false, PreRelease: featuregate.Alpha},
+		PreferredCPUSet:          {Default: false, PreRelease: featuregate.Alpha},
+		SchedStatCollector:       {Default: false, PreRelease: featuregate.Alpha},
+		CoordinatedDrain:         {Default: false, PreRelease: featuregate.Alpha},
+		HeterogeneousMemory:      {Default: false, PreRelease: featuregate.Alpha},
+		SelfResourceGovernance:   {Default: false, PreRelease: featuregate.Alpha},
+		CPUSetVerification:       {Default: false, PreRelease: featuregate.Alpha},
+		CgroupRestartRecovery:    {Default: false, PreRelease: featuregate.Alpha},
+		ResctrlTaskWatcher:       {Default: false, PreRelease: featuregate.Alpha},
+		HostApplicationCollector: {Default: false, PreRelease: featuregate.Alpha},
+		CPUStealAnomaly:          {Default: false, PreRelease: featuregate.Alpha},
+		CPUAffinityObservation:   {Default: false, PreRelease: featuregate.Alpha},
+		ColocationReadiness:      {Default: false, PreRelease: featuregate.Alpha},
+		CPUSetPropagationCheck:   {Default: false, PreRelease: featuregate.Alpha},
+		GPUOverQuotaEvict:        {Default: false, PreRelease: featuregate.Alpha},
+		SharedPoolCompaction:     {Default: false, PreRelease: featuregate.Alpha},
+		NICTopologyReport:        {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
@@ -172,7 +342,7 @@ func IsFeatureDisabled(nodeSLO *slov1alpha1.NodeSLO, feature featuregate.Feature
 
 	spec := nodeSLO.Spec
 	switch feature {
-	case BECPUSuppress, BEMemoryEvict, BECPUEvict:
+	case BECPUSuppress, BEMemoryEvict, BEMemoryThrottle, BEPageCacheLimit, BEColdMemorySwapOut, BECPUEvict:
 		if spec.ResourceUsedThresholdWithBE == nil || spec.ResourceUsedThresholdWithBE.Enable == nil {
 			return true, fmt.Errorf("cannot parse feature config for invalid nodeSLO %v", nodeSLO)
 		}
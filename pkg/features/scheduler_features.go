@@ -57,8 +57,83 @@ const (
 	//
 	// ResizePod is used to enable resize pod feature
 	ResizePod featuregate.Feature = "ResizePod"
+
+	// owner: @koordinator-sh
+	// beta: v1.4
+	//
+	// AmplifiedCPUsFilter is used to filter the nodes by the amplified CPUs in NodeNUMAResource
+	// when the CPU amplification ratio of the node is set.
+	AmplifiedCPUsFilter featuregate.Feature = "AmplifiedCPUsFilter"
+
+	// owner: @koordinator-sh
+	// beta: v1.4
+	//
+	// RequiredFullPCPUsPolicy is used to reject the Pods not requiring FullPCPUs
+	// on the nodes whose CPU bind policy is FullPCPUsOnly in NodeNUMAResource.
+	RequiredFullPCPUsPolicy featuregate.Feature = "RequiredFullPCPUsPolicy"
+
+	// owner: @koordinator-sh
+	// beta: v1.4
+	//
+	// LoadAwareUsageThresholdsFilter is used to filter the nodes whose usage exceeds the thresholds in LoadAwareScheduling.
+	LoadAwareUsageThresholdsFilter featuregate.Feature = "LoadAwareUsageThresholdsFilter"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// BrokenNodeTopologyFallback disables the topology-aware handling of NodeNUMAResource on the nodes
+	// whose NodeResourceTopology is inconsistent with the node, and schedules the Pods without CPU binding.
+	BrokenNodeTopologyFallback featuregate.Feature = "BrokenNodeTopologyFallback"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// InconsistentNodeTopologyQuarantine rejects the Pods requiring the CPU bind policy on the nodes whose NUMA Node
+	// allocatable reported by the NodeResourceTopology is inconsistent with the node allocatable until it is resolved.
+	InconsistentNodeTopologyQuarantine featuregate.Feature = "InconsistentNodeTopologyQuarantine"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// DeviceUnavailableMigration creates the PodMigrationJobs for the Pods allocated on the devices
+	// which are reported unhealthy or removed in the Device by koordlet.
+	DeviceUnavailableMigration featuregate.Feature = "DeviceUnavailableMigration"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// ResourceStatusV2 writes the annotation resource-status in the compact V2 schema. The readers accept both
+	// schemas, so it should be enabled after all the koordlets and the schedulers are upgraded.
+	ResourceStatusV2 featuregate.Feature = "ResourceStatusV2"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// ResourceStatusCompression compresses the annotation resource-status in the V2 schema by gzip and base64.
+	// It only takes effect when ResourceStatusV2 is enabled.
+	ResourceStatusCompression featuregate.Feature = "ResourceStatusCompression"
+
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	//
+	// LeaderHandoff holds the new leader back until the in-flight binds of the previous leader have landed, and
+	// reconciles the allocations rebuilt from the annotations to resolve the conflicts before scheduling.
+	LeaderHandoff featuregate.Feature = "LeaderHandoff"
 )
 
+// DynamicSchedulerFeatures are the scheduler features which can be reloaded at runtime
+// without restarting the scheduler.
+var DynamicSchedulerFeatures = []featuregate.Feature{
+	AmplifiedCPUsFilter,
+	RequiredFullPCPUsPolicy,
+	LoadAwareUsageThresholdsFilter,
+	BrokenNodeTopologyFallback,
+	InconsistentNodeTopologyQuarantine,
+	DeviceUnavailableMigration,
+	ResourceStatusV2,
+	ResourceStatusCompression,
+}
+
 var defaultSchedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	CompatibleCSIStorageCapacity:       {Default: false, PreRelease: featuregate.Alpha},
 	DisableCSIStorageCapacityInformer:  {Default: false, PreRelease: featuregate.Alpha},
@@ -69,6 +144,15 @@ var defaultSchedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSp
 	ElasticQuotaIgnorePodOverhead:      {Default: false, PreRelease: featuregate.Alpha},
 	ElasticQuotaGuaranteeUsage:         {Default: false, PreRelease: featuregate.Alpha},
 	DisableDefaultQuota:                {Default: false, PreRelease: featuregate.Alpha},
+	AmplifiedCPUsFilter:                {Default: true, PreRelease: featuregate.Beta},
+	RequiredFullPCPUsPolicy:            {Default: true, PreRelease: featuregate.Beta},
+	LoadAwareUsageThresholdsFilter:     {Default: true, PreRelease: featuregate.Beta},
+	BrokenNodeTopologyFallback:         {Default: false, PreRelease: featuregate.Alpha},
+	InconsistentNodeTopologyQuarantine: {Default: false, PreRelease: featuregate.Alpha},
+	DeviceUnavailableMigration:         {Default: false, PreRelease: featuregate.Alpha},
+	ResourceStatusV2:                   {Default: false, PreRelease: featuregate.Alpha},
+	ResourceStatusCompression:          {Default: false, PreRelease: featuregate.Alpha},
+	LeaderHandoff:                      {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
@@ -32,6 +32,7 @@ import (
 	qmframework "github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/selfgovernance"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
@@ -54,6 +55,7 @@ type Configuration struct {
 	RuntimeHookConf    *runtimehooks.Config
 	AuditConf          *audit.Config
 	PredictionConf     *prediction.Config
+	SelfGovernanceConf *selfgovernance.Config
 
 	FeatureGates map[string]bool
 }
@@ -69,6 +71,7 @@ func NewConfiguration() *Configuration {
 		RuntimeHookConf:    runtimehooks.NewDefaultConfig(),
 		AuditConf:          audit.NewDefaultConfig(),
 		PredictionConf:     prediction.NewDefaultConfig(),
+		SelfGovernanceConf: selfgovernance.NewDefaultConfig(),
 	}
 }
 
@@ -83,6 +86,7 @@ func (c *Configuration) InitFlags(fs *flag.FlagSet) {
 	c.RuntimeHookConf.InitFlags(fs)
 	c.AuditConf.InitFlags(fs)
 	c.PredictionConf.InitFlags(fs)
+	c.SelfGovernanceConf.InitFlags(fs)
 	resourceexecutor.Conf.InitFlags(fs)
 	fs.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features. "+
 		"Options are:\n"+strings.Join(features.DefaultKoordletFeatureGate.KnownFeatures(), "\n"))
@@ -24,6 +24,7 @@ import (
 	topologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
@@ -31,13 +32,16 @@ import (
 
 	clientsetbeta1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	"github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/selfgovernance"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
@@ -63,6 +67,7 @@ type daemon struct {
 	qosManager     qosmanager.QOSManager
 	runtimeHook    runtimehooks.RuntimeHook
 	predictServer  prediction.PredictServer
+	selfGovernor   selfgovernance.SelfGovernor
 }
 
 func NewDaemon(config *config.Configuration) (Daemon, error) {
@@ -73,6 +78,8 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	}
 	klog.Infof("NODE_NAME is %v, start time %v", nodeName, float64(time.Now().Unix()))
 	metrics.RecordKoordletStartTime(nodeName, float64(time.Now().Unix()))
+	resourceexecutor.SetDiscrepancyRecorder(metrics.RecordCgroupUpdateDiscrepancy)
+	resourceexecutor.SetCPUSetPropagationRecorder(metrics.RecordCPUSetPropagation)
 
 	klog.Infof("sysconf: %+v, agentMode: %v", system.Conf, system.AgentMode)
 	klog.Infof("kernel version INFO: %+v", system.HostSystemInfo)
@@ -81,6 +88,7 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	crdClient := clientsetbeta1.NewForConfigOrDie(config.KubeRestConf)
 	topologyClient := topologyclientset.NewForConfigOrDie(config.KubeRestConf)
 	schedulingClient := v1alpha1.NewForConfigOrDie(config.KubeRestConf)
+	dynamicClient := dynamic.NewForConfigOrDie(config.KubeRestConf)
 
 	metricCache, err := metriccache.NewMetricCache(config.MetricCacheConf)
 	if err != nil {
@@ -89,7 +97,7 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	predictServer := prediction.NewPeakPredictServer(config.PredictionConf)
 	predictorFactory := prediction.NewPredictorFactory(predictServer, config.PredictionConf.ColdStartDuration, config.PredictionConf.SafetyMarginPercent)
 
-	statesInformer := statesinformerimpl.NewStatesInformer(config.StatesInformerConf, kubeClient, crdClient, topologyClient, metricCache, nodeName, schedulingClient, predictorFactory)
+	statesInformer := statesinformerimpl.NewStatesInformer(config.StatesInformerConf, kubeClient, crdClient, topologyClient, dynamicClient, metricCache, nodeName, schedulingClient, predictorFactory)
 
 	cgroupDriver := system.GetCgroupDriver()
 	system.SetupCgroupPathFormatter(cgroupDriver)
@@ -115,6 +123,7 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 		qosManager:     qosManager,
 		runtimeHook:    runtimeHook,
 		predictServer:  predictServer,
+		selfGovernor:   selfgovernance.NewSelfGovernor(config.SelfGovernanceConf),
 	}
 
 	return d, nil
@@ -124,6 +133,15 @@ func (d *daemon) Run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	klog.Infof("Starting daemon")
 
+	// pin and limit koordlet itself before starting the other modules
+	if features.DefaultKoordletFeatureGate.Enabled(features.SelfResourceGovernance) {
+		if err := d.selfGovernor.Setup(); err != nil {
+			klog.Errorf("Unable to setup the self governor, err: %s", err)
+		} else if err = d.selfGovernor.Run(stopCh); err != nil {
+			klog.Errorf("Unable to run the self governor, err: %s", err)
+		}
+	}
+
 	go func() {
 		if err := d.metricCache.Run(stopCh); err != nil {
 			klog.Fatal("Unable to run the metric cache: ", err)
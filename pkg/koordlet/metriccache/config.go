@@ -39,6 +39,15 @@ type Config struct {
 	TSDBMinBlockDuration          time.Duration
 	TSDBMaxBlockDuration          time.Duration
 	TSDBHeadChunksWriteBufferSize int
+
+	// sinks export the appended metrics to a third-party storage, disabled if the address is empty
+	SinkRemoteWriteURL      string
+	SinkOTLPEndpoint        string
+	SinkPushInterval        time.Duration
+	SinkTimeout             time.Duration
+	SinkIncludeMetricsRegex string
+	SinkExcludeMetricsRegex string
+	SinkMaxPendingSamples   int
 }
 
 func NewDefaultConfig() *Config {
@@ -57,6 +66,10 @@ func NewDefaultConfig() *Config {
 		TSDBMinBlockDuration:          30 * time.Minute, // 30 minutes
 		TSDBMaxBlockDuration:          30 * time.Minute, // 30 minutes
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,      // 1 MB
+
+		SinkPushInterval:      30 * time.Second,
+		SinkTimeout:           10 * time.Second,
+		SinkMaxPendingSamples: 100000,
 	}
 }
 
@@ -76,4 +89,12 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.TSDBMaxBlockDuration, "tsdb-max-block-duration", c.TSDBMaxBlockDuration, "The maximum timestamp range of compacted blocks, recommend >= 1h or this will cause chunks_head leak.")
 	fs.IntVar(&c.TSDBHeadChunksWriteBufferSize, "tsdb-head-chunks-write-buffer-size", c.TSDBHeadChunksWriteBufferSize, "Write buffer size used by the head chunks mapper.")
 
+	fs.StringVar(&c.SinkRemoteWriteURL, "metric-sink-remote-write-url", c.SinkRemoteWriteURL, "The Prometheus remote-write URL to export metrics, e.g. http://prometheus:9090/api/v1/write. Disabled if empty.")
+	fs.StringVar(&c.SinkOTLPEndpoint, "metric-sink-otlp-endpoint", c.SinkOTLPEndpoint, "The OTLP/HTTP metrics endpoint to export metrics, e.g. http://otel-collector:4318/v1/metrics. Disabled if empty.")
+	fs.DurationVar(&c.SinkPushInterval, "metric-sink-push-interval", c.SinkPushInterval, "The interval to push the pending metrics to the sinks.")
+	fs.DurationVar(&c.SinkTimeout, "metric-sink-timeout", c.SinkTimeout, "The timeout of each push to the sinks.")
+	fs.StringVar(&c.SinkIncludeMetricsRegex, "metric-sink-include-metrics-regex", c.SinkIncludeMetricsRegex, "Only the metrics whose kind matches the regex are exported, e.g. node_.*|pod_cpu_usage. All metrics are exported if empty.")
+	fs.StringVar(&c.SinkExcludeMetricsRegex, "metric-sink-exclude-metrics-regex", c.SinkExcludeMetricsRegex, "The metrics whose kind matches the regex are not exported, which takes precedence over the include regex.")
+	fs.IntVar(&c.SinkMaxPendingSamples, "metric-sink-max-pending-samples", c.SinkMaxPendingSamples, "The max number of samples pending to push, the oldest samples are dropped when exceeded.")
+
 }
@@ -41,6 +41,10 @@ func Test_NewDefaultConfig(t *testing.T) {
 		TSDBMinBlockDuration:          30 * time.Minute,
 		TSDBMaxBlockDuration:          30 * time.Minute,
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,
+
+		SinkPushInterval:      30 * time.Second,
+		SinkTimeout:           10 * time.Second,
+		SinkMaxPendingSamples: 100000,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -63,6 +67,14 @@ func Test_InitFlags(t *testing.T) {
 		"--tsdb-min-block-duration=10m",
 		"--tsdb-max-block-duration=20m",
 		"--tsdb-head-chunks-write-buffer-size=512",
+
+		"--metric-sink-remote-write-url=http://prometheus:9090/api/v1/write",
+		"--metric-sink-otlp-endpoint=http://otel-collector:4318/v1/metrics",
+		"--metric-sink-push-interval=1m",
+		"--metric-sink-timeout=5s",
+		"--metric-sink-include-metrics-regex=node_.*",
+		"--metric-sink-exclude-metrics-regex=node_gpu_.*",
+		"--metric-sink-max-pending-samples=1000",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -81,6 +93,14 @@ func Test_InitFlags(t *testing.T) {
 		TSDBMinBlockDuration          time.Duration
 		TSDBMaxBlockDuration          time.Duration
 		TSDBHeadChunksWriteBufferSize int
+
+		SinkRemoteWriteURL      string
+		SinkOTLPEndpoint        string
+		SinkPushInterval        time.Duration
+		SinkTimeout             time.Duration
+		SinkIncludeMetricsRegex string
+		SinkExcludeMetricsRegex string
+		SinkMaxPendingSamples   int
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -105,6 +125,13 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          10 * time.Minute,
 				TSDBMaxBlockDuration:          20 * time.Minute,
 				TSDBHeadChunksWriteBufferSize: 512,
+				SinkRemoteWriteURL:            "http://prometheus:9090/api/v1/write",
+				SinkOTLPEndpoint:              "http://otel-collector:4318/v1/metrics",
+				SinkPushInterval:              time.Minute,
+				SinkTimeout:                   5 * time.Second,
+				SinkIncludeMetricsRegex:       "node_.*",
+				SinkExcludeMetricsRegex:       "node_gpu_.*",
+				SinkMaxPendingSamples:         1000,
 			},
 			args: args{fs: fs},
 		},
@@ -126,6 +153,14 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          tt.fields.TSDBMinBlockDuration,
 				TSDBMaxBlockDuration:          tt.fields.TSDBMaxBlockDuration,
 				TSDBHeadChunksWriteBufferSize: tt.fields.TSDBHeadChunksWriteBufferSize,
+
+				SinkRemoteWriteURL:      tt.fields.SinkRemoteWriteURL,
+				SinkOTLPEndpoint:        tt.fields.SinkOTLPEndpoint,
+				SinkPushInterval:        tt.fields.SinkPushInterval,
+				SinkTimeout:             tt.fields.SinkTimeout,
+				SinkIncludeMetricsRegex: tt.fields.SinkIncludeMetricsRegex,
+				SinkExcludeMetricsRegex: tt.fields.SinkExcludeMetricsRegex,
+				SinkMaxPendingSamples:   tt.fields.SinkMaxPendingSamples,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
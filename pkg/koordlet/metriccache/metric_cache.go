@@ -63,6 +63,7 @@ type metricCache struct {
 	config *Config
 	TSDBStorage
 	KVStorage
+	sinkManager *sinkManager
 }
 
 func NewMetricCache(cfg *Config) (MetricCache, error) {
@@ -70,15 +71,30 @@ func NewMetricCache(cfg *Config) (MetricCache, error) {
 	if err != nil {
 		return nil, err
 	}
+	sinkManager, err := newSinkManager(cfg)
+	if err != nil {
+		tsdb.Close()
+		return nil, err
+	}
+	if sinkManager != nil {
+		tsdb = &sinkTSDBStorage{
+			TSDBStorage: tsdb,
+			manager:     sinkManager,
+		}
+	}
 	kvdb := NewMemoryStorage()
 	return &metricCache{
 		config:      cfg,
 		TSDBStorage: tsdb,
 		KVStorage:   kvdb,
+		sinkManager: sinkManager,
 	}, nil
 }
 
 func (m *metricCache) Run(stopCh <-chan struct{}) error {
+	if m.sinkManager != nil {
+		go m.sinkManager.run(stopCh)
+	}
 	<-stopCh
 	m.Close()
 	return nil
@@ -26,16 +26,28 @@ var (
 	NodeGPUMemUsageMetric  = defaultMetricFactory.New(NodeMetricGPUMemUsage).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	NodeGPUMemTotalMetric  = defaultMetricFactory.New(NodeMetricGPUMemTotal).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 
+	// node health metrics
+	NodePSIMetric             = defaultMetricFactory.New(NodeMetricPSI).withPropertySchema(MetricPropertyPSIResource)
+	NodeRunQueueLatencyMetric = defaultMetricFactory.New(NodeMetricRunQueueLatency)
+	NodeCPUStealMetric        = defaultMetricFactory.New(NodeMetricCPUSteal)
+
 	// define system resource usage as independent metric, although this can be calculate by node-sum(pod), but the time series are
 	// unaligned across different type of metric, which makes it hard to aggregate.
 	SystemCPUUsageMetric    = defaultMetricFactory.New(SysMetricCPUUsage)
 	SystemMemoryUsageMetric = defaultMetricFactory.New(SysMetricMemoryUsage)
 
+	// host application resource usage
+	HostAppCPUUsageMetric    = defaultMetricFactory.New(HostAppMetricCPUUsage).withPropertySchema(MetricPropertyHostAppName)
+	HostAppMemoryUsageMetric = defaultMetricFactory.New(HostAppMetricMemoryUsage).withPropertySchema(MetricPropertyHostAppName)
+
 	PodCPUUsageMetric     = defaultMetricFactory.New(PodMetricCPUUsage).withPropertySchema(MetricPropertyPodUID)
 	PodMemUsageMetric     = defaultMetricFactory.New(PodMetricMemoryUsage).withPropertySchema(MetricPropertyPodUID)
 	PodCPUThrottledMetric = defaultMetricFactory.New(PodMetricCPUThrottled).withPropertySchema(MetricPropertyPodUID)
 	PodGPUCoreUsageMetric = defaultMetricFactory.New(PodMetricGPUCoreUsage).withPropertySchema(MetricPropertyPodUID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	PodGPUMemUsageMetric  = defaultMetricFactory.New(PodMetricGPUMemUsage).withPropertySchema(MetricPropertyPodUID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	// memory locality metrics
+	PodMemoryLocalPagesMetric  = defaultMetricFactory.New(PodMetricMemoryLocalPages).withPropertySchema(MetricPropertyPodUID)
+	PodMemoryRemotePagesMetric = defaultMetricFactory.New(PodMetricMemoryRemotePages).withPropertySchema(MetricPropertyPodUID)
 
 	ContainerCPUUsageMetric     = defaultMetricFactory.New(ContainerMetricCPUUsage).withPropertySchema(MetricPropertyContainerID)
 	ContainerMemUsageMetric     = defaultMetricFactory.New(ContainerMetricMemoryUsage).withPropertySchema(MetricPropertyContainerID)
@@ -49,10 +61,14 @@ var (
 	NodeMemoryColdPageSizeMetric          = defaultMetricFactory.New(NodeMemoryColdPageSize)
 	PodMemoryColdPageSizeMetric           = defaultMetricFactory.New(PodMemoryColdPageSize).withPropertySchema(MetricPropertyPodUID)
 	ContainerMemoryColdPageSizeMetric     = defaultMetricFactory.New(ContainerMemoryColdPageSize).withPropertySchema(MetricPropertyContainerID)
+	PodMemoryShmemSizeMetric              = defaultMetricFactory.New(PodMemoryShmemSize).withPropertySchema(MetricPropertyPodUID)
 
 	// CPI
 	ContainerCPI = defaultMetricFactory.New(ContainerMetricCPI).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyCPIResource)
 
+	// SchedStat
+	ContainerSchedStatMetric = defaultMetricFactory.New(ContainerMetricSchedStat).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertySchedStatResource)
+
 	// PSI
 	ContainerPSIMetric                 = defaultMetricFactory.New(ContainerMetricPSI).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyPSIResource, MetricPropertyPSIPrecision, MetricPropertyPSIDegree)
 	ContainerPSICPUFullSupportedMetric = defaultMetricFactory.New(ContainerMetricPSICPUFullSupported).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID)
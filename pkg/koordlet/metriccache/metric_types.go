@@ -42,6 +42,13 @@ const (
 	NodeMetricGPUMemUsage  MetricKind = "node_gpu_memory_usage"
 	NodeMetricGPUMemTotal  MetricKind = "node_gpu_memory_total"
 
+	// NodeMetricPSI is the "some" avg10 pressure of the node
+	NodeMetricPSI MetricKind = "node_psi"
+	// NodeMetricRunQueueLatency is the average time a timeslice waits on the run queue in microseconds
+	NodeMetricRunQueueLatency MetricKind = "node_run_queue_latency"
+	// NodeMetricCPUSteal is the ratio of the cpu time stolen by the hypervisor
+	NodeMetricCPUSteal MetricKind = "node_cpu_steal"
+
 	SysMetricCPUUsage    MetricKind = "sys_cpu_usage"
 	SysMetricMemoryUsage MetricKind = "sys_memory_usage"
 
@@ -52,10 +59,17 @@ const (
 	PriorityMetricCPURealLimit MetricKind = "priority_cpu_real_limit"
 	PriorityMetricCPURequest   MetricKind = "priority_cpu_request"
 
+	HostAppMetricCPUUsage    MetricKind = "host_application_cpu_usage"
+	HostAppMetricMemoryUsage MetricKind = "host_application_memory_usage"
+
 	PodMetricCPUUsage     MetricKind = "pod_cpu_usage"
 	PodMetricMemoryUsage  MetricKind = "pod_memory_usage"
 	PodMetricGPUCoreUsage MetricKind = "pod_gpu_core_usage"
 	PodMetricGPUMemUsage  MetricKind = "pod_gpu_memory_usage"
+	// PodMetricMemoryLocalPages and PodMetricMemoryRemotePages are the memory pages on the NUMA nodes local or
+	// remote to the CPUs bound by the pod
+	PodMetricMemoryLocalPages  MetricKind = "pod_memory_local_pages"
+	PodMetricMemoryRemotePages MetricKind = "pod_memory_remote_pages"
 	// PodMetricGPUMemTotal       MetricKind = "pod_gpu_memory_total"
 
 	ContainerMetricCPUUsage     MetricKind = "container_cpu_usage"
@@ -70,6 +84,9 @@ const (
 	// CPI
 	ContainerMetricCPI MetricKind = "container_cpi"
 
+	// ContainerMetricSchedStat is the per-second rate of the scheduler statistics summed over the container tasks
+	ContainerMetricSchedStat MetricKind = "container_sched_stat"
+
 	// PSI
 	ContainerMetricPSI                 MetricKind = "container_psi"
 	ContainerMetricPSICPUFullSupported MetricKind = "container_psi_cpu_full_supported"
@@ -83,6 +100,7 @@ const (
 	NodeMemoryColdPageSize          MetricKind = "node_memory_cold_page_size"
 	PodMemoryColdPageSize           MetricKind = "pod_memory_cold_page_size"
 	ContainerMemoryColdPageSize     MetricKind = "container_memory_cold_page_size"
+	PodMemoryShmemSize              MetricKind = "pod_memory_shmem_size"
 )
 
 // MetricProperty is the property of metric
@@ -97,12 +115,16 @@ const (
 
 	MetricPropertyCPIResource MetricProperty = "cpi_resource"
 
+	MetricPropertySchedStatResource MetricProperty = "sched_stat_resource"
+
 	MetricPropertyPSIResource  MetricProperty = "psi_resource"
 	MetricPropertyPSIPrecision MetricProperty = "psi_precision"
 	MetricPropertyPSIDegree    MetricProperty = "psi_degree"
 
 	MetricPropertyBEResource   MetricProperty = "be_resource"
 	MetricPropertyBEAllocation MetricProperty = "be_allocation"
+
+	MetricPropertyHostAppName MetricProperty = "host_application_name"
 )
 
 // MetricPropertyValue is the property value
@@ -112,6 +134,10 @@ const (
 	CPIResourceCycle       MetricPropertyValue = "cycle"
 	CPIResourceInstruction MetricPropertyValue = "instruction"
 
+	SchedStatResourceMigrations          MetricPropertyValue = "migrations"
+	SchedStatResourceVoluntarySwitches   MetricPropertyValue = "voluntary_switches"
+	SchedStatResourceInvoluntarySwitches MetricPropertyValue = "involuntary_switches"
+
 	PSIResourceCPU  MetricPropertyValue = "cpu"
 	PSIResourceMem  MetricPropertyValue = "mem"
 	PSIResourceIO   MetricPropertyValue = "io"
@@ -134,11 +160,14 @@ var MetricPropertiesFunc = struct {
 	GPU                 func(string, string) map[MetricProperty]string
 	PSICPUFullSupported func(string, string) map[MetricProperty]string
 	ContainerCPI        func(string, string, string) map[MetricProperty]string
+	ContainerSchedStat  func(string, string, string) map[MetricProperty]string
 	PodPSI              func(string, string, string, string) map[MetricProperty]string
 	ContainerPSI        func(string, string, string, string, string) map[MetricProperty]string
 	PodGPU              func(string, string, string) map[MetricProperty]string
 	ContainerGPU        func(string, string, string) map[MetricProperty]string
 	NodeBE              func(string, string) map[MetricProperty]string
+	NodePSI             func(string) map[MetricProperty]string
+	HostApplication     func(string) map[MetricProperty]string
 }{
 	Pod: func(podUID string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID}
@@ -155,6 +184,9 @@ var MetricPropertiesFunc = struct {
 	ContainerCPI: func(podUID, containerID, cpiResource string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertyCPIResource: cpiResource}
 	},
+	ContainerSchedStat: func(podUID, containerID, schedStatResource string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertySchedStatResource: schedStatResource}
+	},
 	PodPSI: func(podUID, psiResource, psiPrecision, psiDegree string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyPSIResource: psiResource, MetricPropertyPSIPrecision: psiPrecision, MetricPropertyPSIDegree: psiDegree}
 	},
@@ -170,6 +202,12 @@ var MetricPropertiesFunc = struct {
 	NodeBE: func(beResource, beResourceAllocation string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyBEResource: beResource, MetricPropertyBEAllocation: beResourceAllocation}
 	},
+	NodePSI: func(psiResource string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPSIResource: psiResource}
+	},
+	HostApplication: func(appName string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyHostAppName: appName}
+	},
 }
 
 // point is the struct to describe metric
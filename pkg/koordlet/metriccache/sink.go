@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// sinkMetricPrefix is the prefix of the exported metric name, e.g. koordlet_node_cpu_usage
+	sinkMetricPrefix = "koordlet_"
+	// sinkNodeLabel is the label of node name attached to all exported samples
+	sinkNodeLabel = "node"
+)
+
+// SinkSample is a metric sample exported to the sinks.
+type SinkSample struct {
+	// Name is the exported metric name.
+	Name string
+	// Labels are the properties of the metric.
+	Labels map[string]string
+	// Timestamp is the ts of the sample in milliseconds.
+	Timestamp int64
+	Value     float64
+}
+
+// MetricSink exports the samples to a third-party storage.
+type MetricSink interface {
+	Name() string
+	// Push sends a batch of samples. The samples are dropped if it returns an error.
+	Push(ctx context.Context, samples []SinkSample) error
+}
+
+// sampleFilter decides if the metric kind should be exported.
+type sampleFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newSampleFilter(includeRegex, excludeRegex string) (*sampleFilter, error) {
+	f := &sampleFilter{}
+	var err error
+	if len(includeRegex) > 0 {
+		if f.include, err = regexp.Compile("^(?:" + includeRegex + ")$"); err != nil {
+			return nil, fmt.Errorf("invalid include metrics regex %q, err: %w", includeRegex, err)
+		}
+	}
+	if len(excludeRegex) > 0 {
+		if f.exclude, err = regexp.Compile("^(?:" + excludeRegex + ")$"); err != nil {
+			return nil, fmt.Errorf("invalid exclude metrics regex %q, err: %w", excludeRegex, err)
+		}
+	}
+	return f, nil
+}
+
+func (f *sampleFilter) match(kind string) bool {
+	if f.exclude != nil && f.exclude.MatchString(kind) {
+		return false
+	}
+	return f.include == nil || f.include.MatchString(kind)
+}
+
+// sinkManager buffers the committed samples and pushes them to the sinks periodically.
+type sinkManager struct {
+	sinks        []MetricSink
+	filter       *sampleFilter
+	nodeName     string
+	interval     time.Duration
+	timeout      time.Duration
+	maxPending   int
+	lock         sync.Mutex
+	pending      []SinkSample
+	droppedCount int
+}
+
+// newSinkManager returns nil if no sink is configured.
+func newSinkManager(cfg *Config) (*sinkManager, error) {
+	var sinks []MetricSink
+	client := &http.Client{Timeout: cfg.SinkTimeout}
+	if len(cfg.SinkRemoteWriteURL) > 0 {
+		sinks = append(sinks, newRemoteWriteSink(cfg.SinkRemoteWriteURL, client))
+	}
+	if len(cfg.SinkOTLPEndpoint) > 0 {
+		sinks = append(sinks, newOTLPSink(cfg.SinkOTLPEndpoint, client))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	filter, err := newSampleFilter(cfg.SinkIncludeMetricsRegex, cfg.SinkExcludeMetricsRegex)
+	if err != nil {
+		return nil, err
+	}
+	return &sinkManager{
+		sinks:      sinks,
+		filter:     filter,
+		nodeName:   os.Getenv("NODE_NAME"),
+		interval:   cfg.SinkPushInterval,
+		timeout:    cfg.SinkTimeout,
+		maxPending: cfg.SinkMaxPendingSamples,
+	}, nil
+}
+
+func (m *sinkManager) add(samples []MetricSample) {
+	exported := make([]SinkSample, 0, len(samples))
+	for _, s := range samples {
+		if !m.filter.match(s.GetKind()) {
+			continue
+		}
+		labels := make(map[string]string, len(s.GetProperties())+1)
+		for k, v := range s.GetProperties() {
+			if k == metricLabelName {
+				continue
+			}
+			labels[k] = v
+		}
+		if len(m.nodeName) > 0 {
+			labels[sinkNodeLabel] = m.nodeName
+		}
+		exported = append(exported, SinkSample{
+			Name:      sinkMetricPrefix + s.GetKind(),
+			Labels:    labels,
+			Timestamp: s.timestamp(),
+			Value:     s.value(),
+		})
+	}
+	if len(exported) == 0 {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.pending = append(m.pending, exported...)
+	if m.maxPending > 0 && len(m.pending) > m.maxPending {
+		dropped := len(m.pending) - m.maxPending
+		m.droppedCount += dropped
+		m.pending = m.pending[dropped:]
+	}
+}
+
+func (m *sinkManager) run(stopCh <-chan struct{}) {
+	klog.V(4).Infof("start pushing metrics to %v sinks every %v", len(m.sinks), m.interval)
+	wait.Until(m.flush, m.interval, stopCh)
+	// push the remaining samples before exiting
+	m.flush()
+}
+
+func (m *sinkManager) flush() {
+	m.lock.Lock()
+	samples, dropped := m.pending, m.droppedCount
+	m.pending, m.droppedCount = nil, 0
+	m.lock.Unlock()
+
+	if dropped > 0 {
+		klog.Warningf("dropped %v metric samples since the pending samples exceed the limit %v", dropped, m.maxPending)
+	}
+	if len(samples) == 0 {
+		return
+	}
+	for _, sink := range m.sinks {
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		err := sink.Push(ctx, samples)
+		cancel()
+		if err != nil {
+			klog.Warningf("failed to push %v metric samples to sink %s, err: %v", len(samples), sink.Name(), err)
+			continue
+		}
+		klog.V(5).Infof("pushed %v metric samples to sink %s", len(samples), sink.Name())
+	}
+}
+
+var _ TSDBStorage = &sinkTSDBStorage{}
+
+// sinkTSDBStorage passes the committed samples of the TSDBStorage to the sinkManager.
+type sinkTSDBStorage struct {
+	TSDBStorage
+	manager *sinkManager
+}
+
+func (s *sinkTSDBStorage) Appender() Appender {
+	return &sinkAppender{
+		Appender: s.TSDBStorage.Appender(),
+		manager:  s.manager,
+	}
+}
+
+var _ Appender = &sinkAppender{}
+
+type sinkAppender struct {
+	Appender
+	manager *sinkManager
+	samples []MetricSample
+}
+
+func (a *sinkAppender) Append(samples []MetricSample) error {
+	if err := a.Appender.Append(samples); err != nil {
+		// the appender rolls back all samples appended so far
+		a.samples = nil
+		return err
+	}
+	a.samples = append(a.samples, samples...)
+	return nil
+}
+
+func (a *sinkAppender) Commit() error {
+	if err := a.Appender.Commit(); err != nil {
+		return err
+	}
+	a.manager.add(a.samples)
+	a.samples = nil
+	return nil
+}
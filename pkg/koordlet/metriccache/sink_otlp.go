@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	otlpSinkName = "OTLP"
+	// otlpScopeName is the instrumentation scope of the exported metrics
+	otlpScopeName = "koordlet"
+)
+
+var _ MetricSink = &otlpSink{}
+
+// otlpSink pushes the samples as gauges to an OTLP/HTTP endpoint with the JSON encoding.
+type otlpSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOTLPSink(endpoint string, client *http.Client) MetricSink {
+	return &otlpSink{
+		endpoint: endpoint,
+		client:   client,
+	}
+}
+
+func (s *otlpSink) Name() string {
+	return otlpSinkName
+}
+
+func (s *otlpSink) Push(ctx context.Context, samples []SinkSample) error {
+	data, err := json.Marshal(buildOTLPMetricsRequest(samples))
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics request, err: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("otlp endpoint returns status %s, body: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// The following types are the JSON encoding of the OTLP ExportMetricsServiceRequest.
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/metrics/v1/metrics.proto
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name  string    `json:"name"`
+	Gauge otlpGauge `json:"gauge"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	// TimeUnixNano is a fixed64 which is encoded as a decimal string in JSON
+	TimeUnixNano string  `json:"timeUnixNano"`
+	AsDouble     float64 `json:"asDouble"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// buildOTLPMetricsRequest groups the samples into gauges by metric name. The node label is
+// reported as the resource attribute `k8s.node.name` instead of the data point attribute.
+func buildOTLPMetricsRequest(samples []SinkSample) *otlpMetricsRequest {
+	var resource otlpResource
+	metricMap := map[string]*otlpMetric{}
+	var names []string
+	for _, sample := range samples {
+		metric, ok := metricMap[sample.Name]
+		if !ok {
+			metric = &otlpMetric{Name: sample.Name}
+			metricMap[sample.Name] = metric
+			names = append(names, sample.Name)
+		}
+		var attributes []otlpKeyValue
+		for k, v := range sample.Labels {
+			if k == sinkNodeLabel {
+				if len(resource.Attributes) == 0 {
+					resource.Attributes = []otlpKeyValue{newOTLPKeyValue("k8s.node.name", v)}
+				}
+				continue
+			}
+			attributes = append(attributes, newOTLPKeyValue(k, v))
+		}
+		sort.Slice(attributes, func(i, j int) bool {
+			return attributes[i].Key < attributes[j].Key
+		})
+		metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpNumberDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: strconv.FormatInt(sample.Timestamp*1e6, 10),
+			AsDouble:     sample.Value,
+		})
+	}
+
+	sort.Strings(names)
+	scopeMetrics := otlpScopeMetrics{
+		Scope:   otlpScope{Name: otlpScopeName},
+		Metrics: make([]otlpMetric, 0, len(names)),
+	}
+	for _, name := range names {
+		scopeMetrics.Metrics = append(scopeMetrics.Metrics, *metricMap[name])
+	}
+	return &otlpMetricsRequest{
+		ResourceMetrics: []otlpResourceMetrics{
+			{
+				Resource:     resource,
+				ScopeMetrics: []otlpScopeMetrics{scopeMetrics},
+			},
+		},
+	}
+}
+
+func newOTLPKeyValue(key, value string) otlpKeyValue {
+	return otlpKeyValue{
+		Key:   key,
+		Value: otlpAnyValue{StringValue: value},
+	}
+}
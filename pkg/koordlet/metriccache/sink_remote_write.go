@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+const remoteWriteSinkName = "RemoteWrite"
+
+var _ MetricSink = &remoteWriteSink{}
+
+// remoteWriteSink pushes the samples with the Prometheus remote-write protocol.
+type remoteWriteSink struct {
+	url    string
+	client *http.Client
+}
+
+func newRemoteWriteSink(url string, client *http.Client) MetricSink {
+	return &remoteWriteSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (s *remoteWriteSink) Name() string {
+	return remoteWriteSinkName
+}
+
+func (s *remoteWriteSink) Push(ctx context.Context, samples []SinkSample) error {
+	data, err := buildWriteRequest(samples).Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal write request, err: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote write returns status %s, body: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// buildWriteRequest groups the samples into series ordered by timestamp.
+func buildWriteRequest(samples []SinkSample) *prompb.WriteRequest {
+	seriesMap := map[string]*prompb.TimeSeries{}
+	var keys []string
+	for _, sample := range samples {
+		lbs := make(map[string]string, len(sample.Labels)+1)
+		for k, v := range sample.Labels {
+			lbs[k] = v
+		}
+		lbs[labels.MetricName] = sample.Name
+		seriesLabels := labels.FromMap(lbs)
+		key := seriesLabels.String()
+		series, ok := seriesMap[key]
+		if !ok {
+			series = &prompb.TimeSeries{}
+			for _, l := range seriesLabels {
+				series.Labels = append(series.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+			}
+			seriesMap[key] = series
+			keys = append(keys, key)
+		}
+		series.Samples = append(series.Samples, prompb.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+	}
+
+	sort.Strings(keys)
+	req := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(keys))}
+	for _, key := range keys {
+		series := seriesMap[key]
+		sort.SliceStable(series.Samples, func(i, j int) bool {
+			return series.Samples[i].Timestamp < series.Samples[j].Timestamp
+		})
+		req.Timeseries = append(req.Timeseries, *series)
+	}
+	return req
+}
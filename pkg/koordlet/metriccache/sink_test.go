@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMetricSink struct {
+	lock    sync.Mutex
+	samples []SinkSample
+}
+
+func (f *fakeMetricSink) Name() string {
+	return "fake"
+}
+
+func (f *fakeMetricSink) Push(ctx context.Context, samples []SinkSample) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.samples = append(f.samples, samples...)
+	return nil
+}
+
+func Test_sampleFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		include string
+		exclude string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "match all by default",
+			want: map[string]bool{
+				"node_cpu_usage": true,
+				"pod_cpu_usage":  true,
+			},
+		},
+		{
+			name:    "include node metrics except memory",
+			include: "node_.*",
+			exclude: "node_memory_.*",
+			want: map[string]bool{
+				"node_cpu_usage":    true,
+				"node_memory_usage": false,
+				"pod_cpu_usage":     false,
+			},
+		},
+		{
+			name:    "regex matches the whole kind",
+			include: "cpu_usage",
+			want: map[string]bool{
+				"node_cpu_usage": false,
+			},
+		},
+		{
+			name:    "invalid regex",
+			include: "node_(",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newSampleFilter(tt.include, tt.exclude)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if err != nil {
+				return
+			}
+			for kind, want := range tt.want {
+				assert.Equal(t, want, f.match(kind), kind)
+			}
+		})
+	}
+}
+
+func Test_newSinkManager(t *testing.T) {
+	cfg := NewDefaultConfig()
+	m, err := newSinkManager(cfg)
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+
+	cfg.SinkRemoteWriteURL = "http://prometheus:9090/api/v1/write"
+	cfg.SinkOTLPEndpoint = "http://otel-collector:4318/v1/metrics"
+	m, err = newSinkManager(cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, m)
+	assert.Len(t, m.sinks, 2)
+
+	cfg.SinkExcludeMetricsRegex = "node_("
+	_, err = newSinkManager(cfg)
+	assert.Error(t, err)
+}
+
+func Test_sinkTSDBStorage(t *testing.T) {
+	t.Setenv("NODE_NAME", "test-node")
+	dir := t.TempDir()
+	cfg := NewDefaultConfig()
+	cfg.TSDBPath = dir
+	cfg.TSDBEnablePromMetrics = false
+	cfg.SinkRemoteWriteURL = "http://prometheus:9090/api/v1/write"
+	cfg.SinkExcludeMetricsRegex = "node_memory_.*"
+	cfg.SinkMaxPendingSamples = 2
+	mc, err := NewMetricCache(cfg)
+	assert.NoError(t, err)
+	defer mc.(*metricCache).Close()
+
+	fakeSink := &fakeMetricSink{}
+	manager := mc.(*metricCache).sinkManager
+	assert.NotNil(t, manager)
+	manager.sinks = []MetricSink{fakeSink}
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	nodeCPU, err := NodeCPUUsageMetric.GenerateSample(nil, now, 4)
+	assert.NoError(t, err)
+	nodeMemory, err := NodeMemoryUsageMetric.GenerateSample(nil, now, 1024)
+	assert.NoError(t, err)
+	podCPU, err := PodCPUUsageMetric.GenerateSample(map[MetricProperty]string{MetricPropertyPodUID: "test-pod-uid"}, now, 1)
+	assert.NoError(t, err)
+
+	// samples are not exported before committed
+	appender := mc.Appender()
+	assert.NoError(t, appender.Append([]MetricSample{nodeCPU, nodeMemory}))
+	manager.flush()
+	assert.Empty(t, fakeSink.samples)
+	assert.NoError(t, appender.Commit())
+
+	appender = mc.Appender()
+	assert.NoError(t, appender.Append([]MetricSample{podCPU}))
+	assert.NoError(t, appender.Commit())
+	manager.flush()
+	expected := []SinkSample{
+		{
+			Name:      "koordlet_node_cpu_usage",
+			Labels:    map[string]string{sinkNodeLabel: "test-node"},
+			Timestamp: now.UnixMilli(),
+			Value:     4,
+		},
+		{
+			Name:      "koordlet_pod_cpu_usage",
+			Labels:    map[string]string{sinkNodeLabel: "test-node", string(MetricPropertyPodUID): "test-pod-uid"},
+			Timestamp: now.UnixMilli(),
+			Value:     1,
+		},
+	}
+	assert.Equal(t, expected, fakeSink.samples)
+
+	// the oldest samples are dropped when the pending samples exceed the limit
+	fakeSink.samples = nil
+	appender = mc.Appender()
+	assert.NoError(t, appender.Append([]MetricSample{nodeCPU, podCPU, podCPU}))
+	assert.NoError(t, appender.Commit())
+	manager.flush()
+	assert.Equal(t, []SinkSample{expected[1], expected[1]}, fakeSink.samples)
+}
+
+func Test_remoteWriteSink(t *testing.T) {
+	var got prompb.WriteRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		compressed, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		assert.NoError(t, err)
+		assert.NoError(t, got.Unmarshal(data))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	samples := []SinkSample{
+		{Name: "koordlet_pod_cpu_usage", Labels: map[string]string{"pod_uid": "uid-1"}, Timestamp: 2000, Value: 2},
+		{Name: "koordlet_node_cpu_usage", Labels: map[string]string{}, Timestamp: 1000, Value: 4},
+		{Name: "koordlet_pod_cpu_usage", Labels: map[string]string{"pod_uid": "uid-1"}, Timestamp: 1000, Value: 1},
+	}
+	s := newRemoteWriteSink(server.URL, server.Client())
+	assert.NoError(t, s.Push(context.TODO(), samples))
+
+	expected := []prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "koordlet_node_cpu_usage"}},
+			Samples: []prompb.Sample{{Value: 4, Timestamp: 1000}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "koordlet_pod_cpu_usage"}, {Name: "pod_uid", Value: "uid-1"}},
+			Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}, {Value: 2, Timestamp: 2000}},
+		},
+	}
+	if assert.Len(t, got.Timeseries, len(expected)) {
+		for i := range expected {
+			assert.Equal(t, expected[i].Labels, got.Timeseries[i].Labels)
+			assert.Equal(t, expected[i].Samples, got.Timeseries[i].Samples)
+		}
+	}
+
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failServer.Close()
+	s = newRemoteWriteSink(failServer.URL, failServer.Client())
+	assert.Error(t, s.Push(context.TODO(), samples))
+}
+
+func Test_otlpSink(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	samples := []SinkSample{
+		{Name: "koordlet_pod_cpu_usage", Labels: map[string]string{"pod_uid": "uid-1", sinkNodeLabel: "test-node"}, Timestamp: 1000, Value: 1},
+		{Name: "koordlet_node_cpu_usage", Labels: map[string]string{sinkNodeLabel: "test-node"}, Timestamp: 1000, Value: 4},
+	}
+	s := newOTLPSink(server.URL, server.Client())
+	assert.NoError(t, s.Push(context.TODO(), samples))
+
+	expectedJSON := `{
+  "resourceMetrics": [{
+    "resource": {"attributes": [{"key": "k8s.node.name", "value": {"stringValue": "test-node"}}]},
+    "scopeMetrics": [{
+      "scope": {"name": "koordlet"},
+      "metrics": [
+        {"name": "koordlet_node_cpu_usage", "gauge": {"dataPoints": [{"timeUnixNano": "1000000000", "asDouble": 4}]}},
+        {"name": "koordlet_pod_cpu_usage", "gauge": {"dataPoints": [{
+          "attributes": [{"key": "pod_uid", "value": {"stringValue": "uid-1"}}],
+          "timeUnixNano": "1000000000", "asDouble": 1}]}}
+      ]
+    }]
+  }]
+}`
+	var expected map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(expectedJSON), &expected))
+	assert.Equal(t, expected, got)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DiscrepancyTypeKey = "type"
+)
+
+var (
+	CgroupUpdateDiscrepancy = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "cgroup_update_discrepancy_total",
+		Help:      "Number of the cgroup updates not applied by the kernel, found by reading back the cgroup files and the tasks",
+	}, []string{NodeKey, ResourceKey, DiscrepancyTypeKey})
+
+	CPUSetPropagationLatency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "cpuset_propagation_latency_seconds",
+		Help:      "Latency of the cpuset.cpus written to a parent cgroup propagated to its children by the kernel, observed last time",
+	}, []string{NodeKey})
+
+	CPUSetPropagationDelay = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "cpuset_propagation_delay_total",
+		Help:      "Number of the child cgroups whose cpuset is not propagated from the parent in time and re-written",
+	}, []string{NodeKey})
+
+	CgroupUpdateVerifyCollector = []prometheus.Collector{
+		CgroupUpdateDiscrepancy,
+		CPUSetPropagationLatency,
+		CPUSetPropagationDelay,
+	}
+)
+
+func RecordCgroupUpdateDiscrepancy(resource string, discrepancyType string, count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resource
+	labels[DiscrepancyTypeKey] = discrepancyType
+	CgroupUpdateDiscrepancy.With(labels).Add(float64(count))
+}
+
+func RecordCPUSetPropagation(latency time.Duration, delayedChildren int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	CPUSetPropagationLatency.With(labels).Set(latency.Seconds())
+	if delayedChildren > 0 {
+		CPUSetPropagationDelay.With(labels).Add(float64(delayedChildren))
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	BEColdMemorySwapOutBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_cold_memory_swap_out_bytes",
+		Help:      "Bytes of the cold pages of the BE pods requested to swap out by koordlet in the last round",
+	}, []string{NodeKey})
+
+	NodeSwapIOPagesPerSecond = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_swap_io_pages_per_second",
+		Help:      "Pages swapped in and out per second on the node, observed by the cold memory swap-out",
+	}, []string{NodeKey})
+
+	ColdMemorySwapOutCollector = []prometheus.Collector{
+		BEColdMemorySwapOutBytes,
+		NodeSwapIOPagesPerSecond,
+	}
+)
+
+func RecordBEColdMemorySwapOutBytes(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEColdMemorySwapOutBytes.With(labels).Set(value)
+}
+
+func RecordNodeSwapIOPagesPerSecond(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	NodeSwapIOPagesPerSecond.With(labels).Set(value)
+}
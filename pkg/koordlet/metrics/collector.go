@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	CollectorKey = "collector"
+)
+
+var (
+	MetricsCollectorEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metrics_collector_enabled",
+		Help:      "Whether the metrics collector is enabled, by the collector. 1 means enabled and 0 means disabled",
+	}, []string{NodeKey, CollectorKey})
+
+	MetricsCollectorHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metrics_collector_healthy",
+		Help:      "Whether the last round of the metrics collector succeeded, by the collector. 1 means succeeded and 0 means failed",
+	}, []string{NodeKey, CollectorKey})
+
+	MetricsCollectorLastSuccessTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metrics_collector_last_success_timestamp_seconds",
+		Help:      "Unix timestamp of the last succeeded round of the metrics collector, by the collector",
+	}, []string{NodeKey, CollectorKey})
+
+	MetricsCollectorRunStatus = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metrics_collector_run_status",
+		Help:      "the count of the metrics collector rounds, by the collector, by the status",
+	}, []string{NodeKey, CollectorKey, StatusKey})
+
+	MetricsCollectorCollectors = []prometheus.Collector{
+		MetricsCollectorEnabled,
+		MetricsCollectorHealthy,
+		MetricsCollectorLastSuccessTime,
+		MetricsCollectorRunStatus,
+	}
+)
+
+func RecordMetricsCollectorEnabled(collector string, enabled bool) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CollectorKey] = collector
+	value := 0.0
+	if enabled {
+		value = 1.0
+	}
+	MetricsCollectorEnabled.With(labels).Set(value)
+}
+
+func RecordMetricsCollectorRun(collector string, err error) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CollectorKey] = collector
+	if err != nil {
+		MetricsCollectorHealthy.With(labels).Set(0)
+		labels[StatusKey] = StatusFailed
+		MetricsCollectorRunStatus.With(labels).Inc()
+		return
+	}
+	MetricsCollectorHealthy.With(labels).Set(1)
+	MetricsCollectorLastSuccessTime.With(labels).Set(float64(time.Now().Unix()))
+	labels[StatusKey] = StatusSucceed
+	MetricsCollectorRunStatus.With(labels).Inc()
+}
@@ -65,6 +65,24 @@ var (
 		Help:      "Number of cpu cores used by node in realtime",
 	}, []string{NodeKey})
 
+	HousekeepingUsedCPU = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "housekeeping_used_cpu_cores",
+		Help:      "Number of cpu cores used on the housekeeping cpus of node in realtime",
+	}, []string{NodeKey})
+
+	HousekeepingCPUUsageRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "housekeeping_cpu_usage_ratio",
+		Help:      "Ratio of the used cpu cores to the total housekeeping cpus of node, which indicates the housekeeping cpu pressure",
+	}, []string{NodeKey})
+
+	NodeCPUStealRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_cpu_steal_ratio",
+		Help:      "Ratio of the cpu time of node stolen by the hypervisor in realtime",
+	}, []string{NodeKey})
+
 	CommonCollectors = []prometheus.Collector{
 		KoordletStartTime,
 		CollectNodeCPUInfoStatus,
@@ -73,6 +91,9 @@ var (
 		PodEviction,
 		PodEvictionDetail.GetCounterVec(),
 		NodeUsedCPU,
+		HousekeepingUsedCPU,
+		HousekeepingCPUUsageRatio,
+		NodeCPUStealRatio,
 	}
 )
 
@@ -141,6 +162,30 @@ func RecordNodeUsedCPU(value float64) {
 	NodeUsedCPU.With(labels).Set(value)
 }
 
+func RecordHousekeepingUsedCPU(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	HousekeepingUsedCPU.With(labels).Set(value)
+}
+
+func RecordHousekeepingCPUUsageRatio(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	HousekeepingCPUUsageRatio.With(labels).Set(value)
+}
+
+func RecordNodeCPUStealRatio(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	NodeCPUStealRatio.With(labels).Set(value)
+}
+
 func labelsClone(labels prometheus.Labels) prometheus.Labels {
 	copyLabels := prometheus.Labels{}
 	for key, value := range labels {
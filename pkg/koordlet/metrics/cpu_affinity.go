@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	ContainerCPUAffinityDriftCPUs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "container_cpu_affinity_drift_cpus",
+		Help:      "The number of CPUs differing between the allowed CPUs of the container main process and the allocated cpuset",
+	}, []string{NodeKey, PodNamespace, PodName, ContainerID, ContainerName})
+
+	CPUAffinityCollectors = []prometheus.Collector{
+		ContainerCPUAffinityDriftCPUs,
+	}
+)
+
+func RecordContainerCPUAffinityDriftCPUs(podNS, podName, containerID, containerName string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = podNS
+	labels[PodName] = podName
+	labels[ContainerID] = containerID
+	labels[ContainerName] = containerName
+	ContainerCPUAffinityDriftCPUs.With(labels).Set(value)
+}
+
+func ResetContainerCPUAffinityDriftCPUs() {
+	ContainerCPUAffinityDriftCPUs.Reset()
+}
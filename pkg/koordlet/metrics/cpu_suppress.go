@@ -16,18 +16,22 @@ limitations under the License.
 
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
 
 var (
 	BESuppressCPU = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
-		Name:      "be_suppress_cpu_cores",
+		Name:      registry.BESuppressCPUCores.Name,
 		Help:      "Number of cores suppress by koordlet",
 	}, []string{NodeKey, BESuppressTypeKey})
 
 	BESuppressLSUsedCPU = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
-		Name:      "be_suppress_ls_used_cpu_cores",
+		Name:      registry.BESuppressLSUsedCPUCores.Name,
 		Help:      "Number of cpu cores used by LS. We consider non-BE pods and podMeta-missing pods as LS.",
 	}, []string{NodeKey})
 
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DryRunKey = "dry_run"
+)
+
+var (
+	EvictionManagerVictims = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "eviction_manager_victims",
+		Help:      "Number of pods picked as the eviction victims by the koordlet eviction manager",
+	}, []string{NodeKey, EvictionReasonKey, DryRunKey})
+
+	EvictionManagerReleasedResource = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "eviction_manager_released_resource",
+		Help:      "Amount of resource expected to be released by the eviction victims of the koordlet eviction manager",
+	}, []string{NodeKey, EvictionReasonKey, ResourceKey, DryRunKey})
+
+	EvictionManagerCollectors = []prometheus.Collector{
+		EvictionManagerVictims,
+		EvictionManagerReleasedResource,
+	}
+)
+
+func RecordEvictionManagerVictims(reason string, dryRun bool, count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[EvictionReasonKey] = reason
+	labels[DryRunKey] = strconv.FormatBool(dryRun)
+	EvictionManagerVictims.With(labels).Add(float64(count))
+}
+
+func RecordEvictionManagerReleasedResource(reason string, resourceName string, dryRun bool, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[EvictionReasonKey] = reason
+	labels[ResourceKey] = resourceName
+	labels[DryRunKey] = strconv.FormatBool(dryRun)
+	EvictionManagerReleasedResource.With(labels).Add(value)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	GPUMinorKey = "gpu_minor"
+)
+
+var (
+	PodGPUUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_gpu_usage",
+		Help:      "the gpu usage of the pod attributed by the processes on each gpu, e.g. the gpu memory and the sm utilization",
+	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, GPUMinorKey})
+
+	PodGPUAllocated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_gpu_allocated",
+		Help:      "the gpu resources allocated to the pod on each gpu",
+	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, GPUMinorKey})
+
+	GPUCollectors = []prometheus.Collector{
+		PodGPUUsage,
+		PodGPUAllocated,
+	}
+)
+
+func RecordPodGPUUsage(resourceName string, unit string, pod *corev1.Pod, minor string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resourceName
+	labels[UnitKey] = unit
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	labels[GPUMinorKey] = minor
+	PodGPUUsage.With(labels).Set(value)
+}
+
+func ResetPodGPUUsage() {
+	PodGPUUsage.Reset()
+}
+
+func RecordPodGPUAllocated(resourceName string, unit string, pod *corev1.Pod, minor string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resourceName
+	labels[UnitKey] = unit
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	labels[GPUMinorKey] = minor
+	PodGPUAllocated.With(labels).Set(value)
+}
+
+func ResetPodGPUAllocated() {
+	PodGPUAllocated.Reset()
+}
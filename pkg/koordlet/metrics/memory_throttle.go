@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+var (
+	BEMemoryThrottleLevel = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      registry.BEMemoryThrottleLevel.Name,
+		Help:      "Current step of the memory.high throttling applied on BE pods, 0 means not throttled",
+	}, []string{NodeKey})
+
+	BEMemoryThrottledPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_memory_throttled_pods",
+		Help:      "Number of BE pods whose memory.high is tightened by koordlet",
+	}, []string{NodeKey})
+
+	BEMemoryThrottleHighBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_memory_throttle_high_bytes",
+		Help:      "Sum of the memory.high set on the throttled BE pods in bytes",
+	}, []string{NodeKey})
+
+	MemoryThrottleCollector = []prometheus.Collector{
+		BEMemoryThrottleLevel,
+		BEMemoryThrottledPods,
+		BEMemoryThrottleHighBytes,
+	}
+)
+
+func RecordBEMemoryThrottleLevel(level float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEMemoryThrottleLevel.With(labels).Set(level)
+}
+
+func RecordBEMemoryThrottledPods(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEMemoryThrottledPods.With(labels).Set(value)
+}
+
+func RecordBEMemoryThrottleHighBytes(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEMemoryThrottleHighBytes.With(labels).Set(value)
+}
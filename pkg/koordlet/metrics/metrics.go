@@ -28,10 +28,24 @@ func init() {
 	prometheus.MustRegister(CommonCollectors...)
 	prometheus.MustRegister(ResourceSummaryCollectors...)
 	prometheus.MustRegister(CPICollectors...)
+	prometheus.MustRegister(SchedStatCollectors...)
 	prometheus.MustRegister(PSICollectors...)
 	prometheus.MustRegister(CPUSuppressCollector...)
+	prometheus.MustRegister(MemoryThrottleCollector...)
+	prometheus.MustRegister(PageCacheLimitCollector...)
+	prometheus.MustRegister(ColdMemorySwapOutCollector...)
 	prometheus.MustRegister(CPUBurstCollector...)
 	prometheus.MustRegister(PredictionCollectors...)
+	prometheus.MustRegister(CgroupUpdateVerifyCollector...)
+	prometheus.MustRegister(SelfGovernanceCollectors...)
+	prometheus.MustRegister(EvictionManagerCollectors...)
+	prometheus.MustRegister(RuntimeHooksRecoveryCollectors...)
+	prometheus.MustRegister(NodeSLOCollectors...)
+	prometheus.MustRegister(ResctrlCollectors...)
+	prometheus.MustRegister(MetricsCollectorCollectors...)
+	prometheus.MustRegister(CPUAffinityCollectors...)
+	prometheus.MustRegister(GPUCollectors...)
+	prometheus.MustRegister(NodeTopologyCollectors...)
 }
 
 const (
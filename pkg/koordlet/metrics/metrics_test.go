@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -29,6 +30,7 @@ import (
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
 )
 
 func TestGenNodeLabels(t *testing.T) {
@@ -137,12 +139,28 @@ func TestCommonCollectors(t *testing.T) {
 		RecordCollectNodeLocalStorageInfoStatus(nil)
 		RecordBESuppressCores("cfsQuota", float64(1000))
 		RecordBESuppressLSUsedCPU(1.0)
+		RecordBEMemoryThrottleLevel(2)
+		RecordBEMemoryThrottledPods(3)
+		RecordBEMemoryThrottleHighBytes(1 << 30)
+		RecordBEPageCacheLimitedPods("memoryHigh", 2)
+		RecordBEPageCacheBytes(1 << 30)
+		RecordBEColdMemorySwapOutBytes(1 << 30)
+		RecordNodeSwapIOPagesPerSecond(100)
+		RecordCgroupUpdateDiscrepancy("cpuset.cpus", "task", 2)
+		RecordCPUSetPropagation(20*time.Millisecond, 1)
 		RecordNodeUsedCPU(2.0)
+		RecordNodeCPUStealRatio(0.1)
+		RecordHousekeepingUsedCPU(0.5)
+		RecordHousekeepingCPUUsageRatio(0.25)
 		RecordContainerScaledCFSBurstUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
 		RecordContainerScaledCFSQuotaUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
+		ResetContainerCPUAffinityDriftCPUs()
+		RecordContainerCPUAffinityDriftCPUs(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 2)
 		RecordPodEviction(testingPod.Namespace, testingPod.Name, "evictByCPU")
 		ResetContainerCPI()
 		RecordContainerCPI(testingContainer, testingPod, 1, 1)
+		ResetContainerSchedStat()
+		RecordContainerSchedStat(testingContainer, testingPod, 1, 10, 2)
 		ResetContainerPSI()
 		RecordContainerPSI(testingContainer, testingPod, testingPSI)
 		ResetPodPSI()
@@ -248,9 +266,11 @@ func TestResourceSummaryCollectors(t *testing.T) {
 		RecordContainerResourceRequests(string(apiext.BatchMemory), UnitByte, &testingBatchPod.Status.ContainerStatuses[0], testingBatchPod, float64(util.QuantityPtr(testingBatchPod.Spec.Containers[0].Resources.Requests[apiext.BatchMemory]).Value()))
 		RecordContainerResourceLimits(string(apiext.BatchCPU), UnitInteger, &testingBatchPod.Status.ContainerStatuses[0], testingBatchPod, float64(util.QuantityPtr(testingBatchPod.Spec.Containers[0].Resources.Limits[apiext.BatchCPU]).Value()))
 		RecordContainerResourceLimits(string(apiext.BatchMemory), UnitByte, &testingBatchPod.Status.ContainerStatuses[0], testingBatchPod, float64(util.QuantityPtr(testingBatchPod.Spec.Containers[0].Resources.Limits[apiext.BatchMemory]).Value()))
+		RecordPodSandboxOverhead(string(corev1.ResourceCPU), UnitCore, testingBatchPod, 0.25)
 
 		ResetContainerResourceRequests()
 		ResetContainerResourceLimits()
+		ResetPodSandboxOverhead()
 	})
 }
 
@@ -287,3 +307,165 @@ func TestPredictorCollectors(t *testing.T) {
 		RecordNodePredictedResourceReclaimable(string(corev1.ResourceMemory), UnitByte, "testPredictor", float64(testNodeReclaimable.Memory().Value()))
 	})
 }
+
+func TestSelfGovernanceCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordKoordletSelfCPUUsage(0.5)
+		RecordKoordletSelfMemoryUsage(200 * 1024 * 1024)
+		RecordKoordletSelfResourceLimit(string(corev1.ResourceCPU), UnitCore, 1)
+		RecordKoordletSelfResourceLimit(string(corev1.ResourceMemory), UnitByte, 512*1024*1024)
+	})
+}
+
+func TestEvictionManagerCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordEvictionManagerVictims("evictByMemory", false, 2)
+		RecordEvictionManagerVictims("evictByMemory", true, 1)
+		RecordEvictionManagerReleasedResource("evictByMemory", string(corev1.ResourceMemory), false, 1024)
+	})
+}
+
+func TestRuntimeHooksRecoveryCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordRuntimeHooksRecoveryDuration(0.5)
+		RecordRuntimeHooksRecoveryChanges("cpuset.cpus", 2)
+	})
+}
+
+func TestNodeSLOCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordNodeSLOSchemaWarnings(2, 3)
+	})
+}
+
+func TestNodeTopologyCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordNodeTopologyReportConflicts(2)
+		RecordNodeTopologyReportConflictRounds(1)
+		RecordNodeTopologyReportConflictRounds(0)
+	})
+}
+
+func TestResctrlCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordResctrlTaskAssignLag("LS", 0.5)
+		RecordResctrlTaskAssignTasks("LS", 3)
+	})
+}
+
+func TestMetricsCollectorCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordMetricsCollectorEnabled("NodeInfoCollector", true)
+		RecordMetricsCollectorRun("NodeInfoCollector", nil)
+		RecordMetricsCollectorRun("NodeInfoCollector", fmt.Errorf("expected error"))
+	})
+}
+
+func TestGPUCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+	testingPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test_pod",
+			Namespace: "test_pod_namespace",
+			UID:       "test01",
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordPodGPUUsage("gpu-memory", UnitByte, testingPod, "0", 1024)
+		RecordPodGPUUsage("gpu-core", UnitInteger, testingPod, "0", 30)
+		RecordPodGPUAllocated("gpu-memory", UnitByte, testingPod, "0", 2048)
+		ResetPodGPUUsage()
+		ResetPodGPUAllocated()
+	})
+}
+
+func TestRegistryDefinitions(t *testing.T) {
+	tests := []struct {
+		collector  prometheus.Collector
+		definition *registry.Definition
+	}{
+		{collector: BESuppressCPU, definition: registry.BESuppressCPUCores},
+		{collector: BESuppressLSUsedCPU, definition: registry.BESuppressLSUsedCPUCores},
+		{collector: BEMemoryThrottleLevel, definition: registry.BEMemoryThrottleLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.definition.Name, func(t *testing.T) {
+			ch := make(chan *prometheus.Desc, 1)
+			tt.collector.Describe(ch)
+			desc := (<-ch).String()
+			assert.Contains(t, desc, fmt.Sprintf("fqName: %q", tt.definition.FQName()))
+			assert.Contains(t, desc, fmt.Sprintf("variableLabels: %v", tt.definition.Labels))
+		})
+	}
+}
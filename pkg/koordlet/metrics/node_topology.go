@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	NodeTopologyReportConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_topology_report_conflicts_total",
+		Help:      "Number of the conflicts met when koordlet reports the NodeResourceTopology",
+	}, []string{NodeKey})
+
+	NodeTopologyReportConflictRounds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_topology_report_conflict_rounds",
+		Help:      "Number of the consecutive rounds koordlet fails to report the NodeResourceTopology due to the conflicts, a persistent nonzero value indicates the writer contention",
+	}, []string{NodeKey})
+
+	NodeTopologyCollectors = []prometheus.Collector{
+		NodeTopologyReportConflicts,
+		NodeTopologyReportConflictRounds,
+	}
+)
+
+func RecordNodeTopologyReportConflicts(count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	NodeTopologyReportConflicts.With(labels).Add(float64(count))
+}
+
+func RecordNodeTopologyReportConflictRounds(rounds int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	NodeTopologyReportConflictRounds.With(labels).Set(float64(rounds))
+}
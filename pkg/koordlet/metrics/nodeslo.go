@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	SchemaVersionKey = "schema_version"
+)
+
+var (
+	NodeSLOSchemaWarnings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "nodeslo_schema_warnings_total",
+		Help:      "Number of the warnings found when converting the NodeSLO of another schema version, e.g. the unknown fields",
+	}, []string{NodeKey, SchemaVersionKey})
+
+	NodeSLOCollectors = []prometheus.Collector{
+		NodeSLOSchemaWarnings,
+	}
+)
+
+func RecordNodeSLOSchemaWarnings(version int, count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[SchemaVersionKey] = strconv.Itoa(version)
+	NodeSLOSchemaWarnings.With(labels).Add(float64(count))
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	BEPageCacheLimitedPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_page_cache_limited_pods",
+		Help:      "Number of BE pods whose page cache is limited by koordlet, by the limit interface",
+	}, []string{NodeKey, "interface"})
+
+	BEPageCacheBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_page_cache_bytes",
+		Help:      "Sum of the page cache used by the BE pods in bytes",
+	}, []string{NodeKey})
+
+	PageCacheLimitCollector = []prometheus.Collector{
+		BEPageCacheLimitedPods,
+		BEPageCacheBytes,
+	}
+)
+
+func RecordBEPageCacheLimitedPods(limitInterface string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels["interface"] = limitInterface
+	BEPageCacheLimitedPods.With(labels).Set(value)
+}
+
+func RecordBEPageCacheBytes(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEPageCacheBytes.With(labels).Set(value)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	ResctrlGroupKey = "resctrl_group"
+)
+
+var (
+	ResctrlTaskAssignLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_task_assign_lag_seconds",
+		Help:      "Lag between the container cgroup created and its tasks added into the resctrl group",
+	}, []string{NodeKey, ResctrlGroupKey})
+
+	ResctrlTaskAssignTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_task_assign_tasks_total",
+		Help:      "Number of the tasks of the created containers added into the resctrl group by the task watcher",
+	}, []string{NodeKey, ResctrlGroupKey})
+
+	ResctrlCollectors = []prometheus.Collector{
+		ResctrlTaskAssignLag,
+		ResctrlTaskAssignTasks,
+	}
+)
+
+func RecordResctrlTaskAssignLag(group string, seconds float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlGroupKey] = group
+	ResctrlTaskAssignLag.With(labels).Set(seconds)
+}
+
+func RecordResctrlTaskAssignTasks(group string, count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlGroupKey] = group
+	ResctrlTaskAssignTasks.With(labels).Add(float64(count))
+}
@@ -47,11 +47,18 @@ var (
 		Help:      "the container limits of resources updated by koordinator",
 	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, ContainerID, ContainerName})
 
+	PodSandboxOverhead = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_sandbox_overhead",
+		Help:      "the resources overhead of the pod sandbox, e.g. the VM of the secure container",
+	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace})
+
 	ResourceSummaryCollectors = []prometheus.Collector{
 		NodeResourceAllocatable,
 		NodeResourcePriorityReclaimable,
 		ContainerResourceRequests,
 		ContainerResourceLimits,
+		PodSandboxOverhead,
 	}
 )
 
@@ -113,3 +120,20 @@ func RecordContainerResourceLimits(resourceName string, unit string, status *cor
 func ResetContainerResourceLimits() {
 	ContainerResourceLimits.Reset()
 }
+
+func RecordPodSandboxOverhead(resourceName string, unit string, pod *corev1.Pod, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resourceName
+	labels[UnitKey] = unit
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	PodSandboxOverhead.With(labels).Set(value)
+}
+
+func ResetPodSandboxOverhead() {
+	PodSandboxOverhead.Reset()
+}
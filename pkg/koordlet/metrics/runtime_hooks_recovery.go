@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	RuntimeHooksRecoveryDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "runtime_hooks_recovery_duration_seconds",
+		Help:      "Duration of recovering the pod cgroups after koordlet restarts",
+	}, []string{NodeKey})
+
+	RuntimeHooksRecoveryChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "runtime_hooks_recovery_changes_total",
+		Help:      "Number of the drifted cgroups updated by the recovery after koordlet restarts",
+	}, []string{NodeKey, ResourceKey})
+
+	RuntimeHooksRecoveryCollectors = []prometheus.Collector{
+		RuntimeHooksRecoveryDuration,
+		RuntimeHooksRecoveryChanges,
+	}
+)
+
+func RecordRuntimeHooksRecoveryDuration(seconds float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	RuntimeHooksRecoveryDuration.With(labels).Set(seconds)
+}
+
+func RecordRuntimeHooksRecoveryChanges(resource string, count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resource
+	RuntimeHooksRecoveryChanges.With(labels).Add(float64(count))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	SchedStatField = "sched_stat_field"
+
+	Migrations          = "migrations"
+	VoluntarySwitches   = "voluntary_switches"
+	InvoluntarySwitches = "involuntary_switches"
+)
+
+var (
+	ContainerSchedStat = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "container_sched_stat",
+		Help:      "Container task migrations and context switches per second collected by koordlet",
+	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace, SchedStatField})
+
+	SchedStatCollectors = []prometheus.Collector{
+		ContainerSchedStat,
+	}
+)
+
+func ResetContainerSchedStat() {
+	ContainerSchedStat.Reset()
+}
+
+func RecordContainerSchedStat(status *corev1.ContainerStatus, pod *corev1.Pod, migrations, voluntarySwitches, involuntarySwitches float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ContainerID] = status.ContainerID
+	labels[ContainerName] = status.Name
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	labels[SchedStatField] = Migrations
+	ContainerSchedStat.With(labels).Set(migrations)
+
+	labels[SchedStatField] = VoluntarySwitches
+	ContainerSchedStat.With(labels).Set(voluntarySwitches)
+
+	labels[SchedStatField] = InvoluntarySwitches
+	ContainerSchedStat.With(labels).Set(involuntarySwitches)
+}
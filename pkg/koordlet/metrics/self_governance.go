@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	KoordletSelfCPUUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "self_cpu_used_cores",
+		Help:      "Number of cpu cores used by koordlet itself",
+	}, []string{NodeKey})
+
+	KoordletSelfMemoryUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "self_memory_used_bytes",
+		Help:      "Number of memory bytes used by koordlet itself",
+	}, []string{NodeKey})
+
+	KoordletSelfResourceLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "self_resource_limit",
+		Help:      "Resource limit applied to koordlet itself by the self resource governance",
+	}, []string{NodeKey, ResourceKey, UnitKey})
+
+	SelfGovernanceCollectors = []prometheus.Collector{
+		KoordletSelfCPUUsage,
+		KoordletSelfMemoryUsage,
+		KoordletSelfResourceLimit,
+	}
+)
+
+func RecordKoordletSelfCPUUsage(cores float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	KoordletSelfCPUUsage.With(labels).Set(cores)
+}
+
+func RecordKoordletSelfMemoryUsage(bytes float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	KoordletSelfMemoryUsage.With(labels).Set(bytes)
+}
+
+func RecordKoordletSelfResourceLimit(resourceName string, unit string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resourceName
+	labels[UnitKey] = unit
+	KoordletSelfResourceLimit.With(labels).Set(value)
+}
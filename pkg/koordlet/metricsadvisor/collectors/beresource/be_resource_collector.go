@@ -21,7 +21,7 @@ import (
 
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -39,22 +39,24 @@ const (
 )
 
 type beResourceCollector struct {
-	collectInterval time.Duration
-	started         *atomic.Bool
-	metricCache     metriccache.MetricCache
-	statesInformer  statesinformer.StatesInformer
-	cgroupReader    resourceexecutor.CgroupReader
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	metricCache      metriccache.MetricCache
+	statesInformer   statesinformer.StatesInformer
+	cgroupReader     resourceexecutor.CgroupReader
 
 	lastBECPUStat *framework.CPUStat
 }
 
 func New(opt *framework.Options) framework.Collector {
 	return &beResourceCollector{
-		collectInterval: opt.Config.CollectResUsedInterval,
-		started:         atomic.NewBool(false),
-		metricCache:     opt.MetricCache,
-		statesInformer:  opt.StatesInformer,
-		cgroupReader:    opt.CgroupReader,
+		collectInterval:  opt.Config.CollectResUsedInterval,
+		collectorConfigs: opt.CollectorConfigs,
+		started:          atomic.NewBool(false),
+		metricCache:      opt.MetricCache,
+		statesInformer:   opt.StatesInformer,
+		cgroupReader:     opt.CgroupReader,
 	}
 }
 
@@ -71,20 +73,20 @@ func (b *beResourceCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for states informer caches to sync")
 	}
-	go wait.Until(b.collectBECPUResourceMetric, b.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, b.collectorConfigs, b.collectInterval, b.collectBECPUResourceMetric, stopCh)
 }
 
 func (b *beResourceCollector) Started() bool {
 	return b.started.Load()
 }
 
-func (b *beResourceCollector) collectBECPUResourceMetric() {
+func (b *beResourceCollector) collectBECPUResourceMetric() error {
 	klog.V(6).Info("collectBECPUResourceMetric start")
 
 	realMilliLimit, err := b.getBECPURealMilliLimit()
 	if err != nil {
 		klog.Errorf("getBECPURealMilliLimit failed, error: %v", err)
-		return
+		return err
 	}
 
 	beCPUMilliRequest := b.getBECPURequestMilliCores()
@@ -92,7 +94,7 @@ func (b *beResourceCollector) collectBECPUResourceMetric() {
 	beCPUUsageMilliCores, err := b.getBECPUUsageMilliCores()
 	if err != nil {
 		klog.Errorf("getBECPUUsageCores failed, error: %v", err)
-		return
+		return err
 	}
 
 	collectTime := time.Now()
@@ -105,7 +107,7 @@ func (b *beResourceCollector) collectBECPUResourceMetric() {
 
 	if err01 != nil || err02 != nil || err03 != nil {
 		klog.Errorf("failed to collect node BECPU, beLimitGenerateSampleErr: %v, beRequestGenerateSampleErr: %v, beUsageGenerateSampleErr: %v", err01, err02, err03)
-		return
+		return utilerrors.NewAggregate([]error{err01, err02, err03})
 	}
 
 	beMetrics := make([]metriccache.MetricSample, 0)
@@ -114,16 +116,17 @@ func (b *beResourceCollector) collectBECPUResourceMetric() {
 	appender := b.metricCache.Appender()
 	if err := appender.Append(beMetrics); err != nil {
 		klog.ErrorS(err, "Append node BECPUResource metrics error")
-		return
+		return err
 	}
 
 	if err := appender.Commit(); err != nil {
 		klog.ErrorS(err, "Commit node BECPUResouce metrics failed")
-		return
+		return err
 	}
 
 	b.started.Store(true)
 	klog.V(6).Info("collectBECPUResourceMetric finished")
+	return nil
 }
 
 func (b *beResourceCollector) getBECPURealMilliLimit() (int, error) {
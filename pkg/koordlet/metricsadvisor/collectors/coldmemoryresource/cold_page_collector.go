@@ -34,9 +34,10 @@ func New(opt *framework.Options) framework.Collector {
 	// check whether support kidled cold page info collector
 	if system.IsKidledSupport() {
 		return &kidledcoldPageCollector{
-			collectInterval: opt.Config.ColdPageCollectorInterval,
-			cgroupReader:    opt.CgroupReader,
-			statesInformer:  opt.StatesInformer,
+			collectInterval:  opt.Config.ColdPageCollectorInterval,
+			collectorConfigs: opt.CollectorConfigs,
+			cgroupReader:     opt.CgroupReader,
+			statesInformer:   opt.StatesInformer,
 			// TODO(BUPT-wxq): implement podFilter for the VM-based pods and containers
 			podFilter:    framework.DefaultPodFilter,
 			appendableDB: opt.MetricCache,
@@ -75,13 +75,14 @@ func Test_NewColdPageCollector(t *testing.T) {
 				},
 			},
 			want: &kidledcoldPageCollector{
-				collectInterval: opt.Config.ColdPageCollectorInterval,
-				cgroupReader:    opt.CgroupReader,
-				statesInformer:  opt.StatesInformer,
-				podFilter:       framework.DefaultPodFilter,
-				appendableDB:    opt.MetricCache,
-				metricDB:        opt.MetricCache,
-				started:         atomic.NewBool(false),
+				collectInterval:  opt.Config.ColdPageCollectorInterval,
+				collectorConfigs: opt.CollectorConfigs,
+				cgroupReader:     opt.CgroupReader,
+				statesInformer:   opt.StatesInformer,
+				podFilter:        framework.DefaultPodFilter,
+				appendableDB:     opt.MetricCache,
+				metricDB:         opt.MetricCache,
+				started:          atomic.NewBool(false),
 			},
 		},
 	}
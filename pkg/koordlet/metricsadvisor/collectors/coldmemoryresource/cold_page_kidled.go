@@ -22,7 +22,6 @@ import (
 
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -36,17 +35,18 @@ import (
 )
 
 type kidledcoldPageCollector struct {
-	collectInterval time.Duration
-	started         *atomic.Bool
-	cgroupReader    resourceexecutor.CgroupReader
-	statesInformer  statesinformer.StatesInformer
-	podFilter       framework.PodFilter
-	appendableDB    metriccache.Appendable
-	metricDB        metriccache.MetricCache
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	cgroupReader     resourceexecutor.CgroupReader
+	statesInformer   statesinformer.StatesInformer
+	podFilter        framework.PodFilter
+	appendableDB     metriccache.Appendable
+	metricDB         metriccache.MetricCache
 }
 
 func (k *kidledcoldPageCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(k.collectColdPageInfo, k.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, k.collectorConfigs, k.collectInterval, k.collectColdPageInfo, stopCh)
 }
 
 func (k *kidledcoldPageCollector) Started() bool {
@@ -75,9 +75,9 @@ func (k *kidledcoldPageCollector) Enabled() bool {
 
 func (k *kidledcoldPageCollector) Setup(c1 *framework.Context) {}
 
-func (k *kidledcoldPageCollector) collectColdPageInfo() {
+func (k *kidledcoldPageCollector) collectColdPageInfo() error {
 	if k.statesInformer == nil {
-		return
+		return nil
 	}
 	coldPageMetrics := make([]metriccache.MetricSample, 0)
 
@@ -96,15 +96,16 @@ func (k *kidledcoldPageCollector) collectColdPageInfo() {
 	appender := k.appendableDB.Appender()
 	if err := appender.Append(coldPageMetrics); err != nil {
 		klog.ErrorS(err, "Append node metrics error")
-		return
+		return err
 	}
 
 	if err := appender.Commit(); err != nil {
 		klog.Warningf("Commit node metrics failed, reason: %v", err)
-		return
+		return err
 	}
 
 	k.started.Store(true)
+	return nil
 }
 
 func (k *kidledcoldPageCollector) collectNodeColdPageInfo() ([]metriccache.MetricSample, error) {
@@ -114,6 +115,10 @@ func (k *kidledcoldPageCollector) collectNodeColdPageInfo() ([]metriccache.Metri
 	if err != nil {
 		return nil, err
 	}
+	nodeColdSwapBackedPageBytes, err := k.cgroupReader.ReadMemoryColdSwapBackedPageUsage("")
+	if err != nil {
+		return nil, err
+	}
 	nodeColdPageBytesValue := float64(nodeColdPageBytes)
 	nodeColdPageMetrics, err := metriccache.NodeMemoryColdPageSizeMetric.GenerateSample(nil, collectTime, nodeColdPageBytesValue)
 	if err != nil {
@@ -121,7 +126,7 @@ func (k *kidledcoldPageCollector) collectNodeColdPageInfo() ([]metriccache.Metri
 	}
 	coldPageMetrics = append(coldPageMetrics, nodeColdPageMetrics)
 
-	memUsageWithHotPageBytes, err := koordletutil.GetNodeMemUsageWithHotPage(nodeColdPageBytes)
+	memUsageWithHotPageBytes, err := koordletutil.GetNodeMemUsageWithHotPage(nodeColdPageBytes, nodeColdSwapBackedPageBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +165,11 @@ func (k *kidledcoldPageCollector) collectPodsColdPageInfo() ([]metriccache.Metri
 			}
 			continue
 		}
+		podColdSwapBackedPageBytes, err := k.cgroupReader.ReadMemoryColdSwapBackedPageUsage(podCgroupDir)
+		if err != nil {
+			klog.Warningf("can not get swap-backed cold page info from memory.idle_page_stats file for pod %s/%s", pod.Namespace, pod.Name)
+			continue
+		}
 		podColdPageBytesValue := float64(podColdPageBytes)
 		podColdPageMetrics, err := metriccache.PodMemoryColdPageSizeMetric.GenerateSample(metriccache.MetricPropertiesFunc.Pod(uid), collectTime, podColdPageBytesValue)
 		if err != nil {
@@ -167,7 +177,19 @@ func (k *kidledcoldPageCollector) collectPodsColdPageInfo() ([]metriccache.Metri
 		}
 		coldMetrics = append(coldMetrics, podColdPageMetrics)
 
-		podMemUsageWithHotPageBytes, err := koordletutil.GetPodMemUsageWithHotPage(k.cgroupReader, podCgroupDir, podColdPageBytes)
+		// the shmem/tmpfs pages are unreclaimable, so they are reported separately from the cold pages
+		podMemStat, err := k.cgroupReader.ReadMemoryStat(podCgroupDir)
+		if err != nil {
+			klog.Warningf("failed to collect pod shmem size, err: %s pod: %s/%s", err, pod.Namespace, pod.Name)
+			continue
+		}
+		podShmemMetrics, err := metriccache.PodMemoryShmemSizeMetric.GenerateSample(metriccache.MetricPropertiesFunc.Pod(uid), collectTime, float64(podMemStat.Shmem))
+		if err != nil {
+			return nil, err
+		}
+		coldMetrics = append(coldMetrics, podShmemMetrics)
+
+		podMemUsageWithHotPageBytes, err := koordletutil.GetPodMemUsageWithHotPage(k.cgroupReader, podCgroupDir, podColdPageBytes, podColdSwapBackedPageBytes)
 		if err != nil {
 			klog.Warningf("failed to collect pod usage for Memory err: %s pod: %s/%s", err, pod.Namespace, pod.Name)
 			continue
@@ -213,6 +235,11 @@ func (k *kidledcoldPageCollector) collectContainersColdPageInfo(meta *statesinfo
 			klog.Warningf("can not get cold page info from memory.idle_page_stats file for container %s", containerKey)
 			continue
 		}
+		containerColdSwapBackedPageBytes, err := k.cgroupReader.ReadMemoryColdSwapBackedPageUsage(containerCgroupDir)
+		if err != nil {
+			klog.Warningf("can not get swap-backed cold page info from memory.idle_page_stats file for container %s", containerKey)
+			continue
+		}
 		containerColdPageBytesValue := float64(containerColdPageBytes)
 		containerColdPageMetrics, err := metriccache.ContainerMemoryColdPageSizeMetric.GenerateSample(metriccache.MetricPropertiesFunc.Container(containerStat.ContainerID), collectTime, containerColdPageBytesValue)
 		if err != nil {
@@ -220,7 +247,7 @@ func (k *kidledcoldPageCollector) collectContainersColdPageInfo(meta *statesinfo
 		}
 		coldMetrics = append(coldMetrics, containerColdPageMetrics)
 
-		containerMemUsageWithHotPageBytes, err := koordletutil.GetContainerMemUsageWithHotPage(k.cgroupReader, containerCgroupDir, containerColdPageBytes)
+		containerMemUsageWithHotPageBytes, err := koordletutil.GetContainerMemUsageWithHotPage(k.cgroupReader, containerCgroupDir, containerColdPageBytes, containerColdSwapBackedPageBytes)
 		if err != nil {
 			return nil, err
 		}
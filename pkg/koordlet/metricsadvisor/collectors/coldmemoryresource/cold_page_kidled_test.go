@@ -565,6 +565,7 @@ func Test_collectPodColdPageInfo(t *testing.T) {
 	total_inactive_file 104857600
 	total_active_file 0
 	total_unevictable 0
+	total_shmem 4096
 	`
 	testPod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -726,9 +727,18 @@ func Test_collectPodColdPageInfo(t *testing.T) {
 				metricDB:        metricCache,
 				started:         atomic.NewBool(false),
 			}
+			var got []metriccache.MetricSample
 			assert.NotPanics(t, func() {
-				c.collectPodsColdPageInfo()
+				got, err = c.collectPodsColdPageInfo()
 			})
+			assert.NoError(t, err)
+			var gotShmemMetric bool
+			for _, sample := range got {
+				if sample.GetKind() == string(metriccache.PodMemoryShmemSize) {
+					gotShmemMetric = true
+				}
+			}
+			assert.Equal(t, tt.want.podResourceMetric, gotShmemMetric)
 		})
 	}
 
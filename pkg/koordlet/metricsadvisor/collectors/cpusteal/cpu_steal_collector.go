@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusteal
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	CollectorName = "CPUStealCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// cpuStealCollector collects the ratio of the cpu time stolen by the hypervisor, which indicates the contention with
+// the co-tenant VMs on the cloud.
+type cpuStealCollector struct {
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+
+	lastStat *koordletutil.CPUStealStat
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &cpuStealCollector{
+		collectInterval:  opt.Config.CollectResUsedInterval,
+		collectorConfigs: opt.CollectorConfigs,
+		started:          atomic.NewBool(false),
+		appendableDB:     opt.MetricCache,
+	}
+}
+
+func (c *cpuStealCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.CPUStealAnomaly)
+}
+
+func (c *cpuStealCollector) Setup(ctx *framework.Context) {}
+
+func (c *cpuStealCollector) Run(stopCh <-chan struct{}) {
+	go framework.RunCollector(CollectorName, c.collectorConfigs, c.collectInterval, c.collectCPUSteal, stopCh)
+}
+
+func (c *cpuStealCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *cpuStealCollector) collectCPUSteal() error {
+	klog.V(6).Info("collectCPUSteal start")
+	collectTime := timeNow()
+	stat, err := koordletutil.GetCPUStealStat()
+	if err != nil {
+		klog.Warningf("failed to read node cpu steal, err: %v", err)
+		return err
+	}
+	lastStat := c.lastStat
+	c.lastStat = stat
+	if lastStat == nil {
+		klog.V(6).Infof("ignore the first cpu steal collection")
+		return nil
+	}
+	if stat.Total <= lastStat.Total || stat.Steal < lastStat.Steal {
+		klog.V(4).Infof("ignore the cpu steal collection since the stat is reset, last %+v, current %+v", lastStat, stat)
+		return nil
+	}
+
+	stealRatio := float64(stat.Steal-lastStat.Steal) / float64(stat.Total-lastStat.Total)
+	metrics.RecordNodeCPUStealRatio(stealRatio)
+	sample, err := metriccache.NodeCPUStealMetric.GenerateSample(nil, collectTime, stealRatio)
+	if err != nil {
+		klog.Warningf("generate node cpu steal metrics failed, err %v", err)
+		return err
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append([]metriccache.MetricSample{sample}); err != nil {
+		klog.ErrorS(err, "Append node cpu steal metrics error")
+		return err
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("Commit node cpu steal metrics failed, reason: %v", err)
+		return err
+	}
+
+	c.started.Store(true)
+	klog.V(4).Infof("collectCPUSteal finished, steal ratio %v", stealRatio)
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusteal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_cpuStealCollector_collectCPUSteal(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		err = metricCache.Close()
+		assert.NoError(t, err)
+	}()
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+
+	// format: cpu $user $nice $system $idle $iowait $irq $softirq $steal
+	helper.WriteProcSubFileContents(system.ProcStatName, "cpu  300 0 100 1400 0 0 0 200 0 0\n")
+
+	c := New(&framework.Options{
+		Config: &framework.Config{
+			CollectResUsedInterval: 1 * time.Second,
+		},
+		MetricCache: metricCache,
+	})
+	collector := c.(*cpuStealCollector)
+	// the first collection is ignored
+	collector.collectCPUSteal()
+	assert.False(t, collector.Started())
+
+	collector.lastStat = &koordletutil.CPUStealStat{
+		Steal: 100,
+		Total: 1000,
+	}
+	collector.collectCPUSteal()
+	assert.True(t, collector.Started())
+
+	// (200 - 100) / (2000 - 1000) = 0.1
+	querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+	assert.NoError(t, err)
+	queryMeta, err := metriccache.NodeCPUStealMetric.BuildQueryMeta(nil)
+	assert.NoError(t, err)
+	result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	got, err := result.Value(metriccache.AggregationTypeLast)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, got)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostapplication
+
+import (
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	CollectorName = "HostApplicationCollector"
+)
+
+// hostAppCollector collects the resource usage of the host applications declared in the NodeSLO,
+// so that the usage of the non-BE ones can be excluded from the system usage and attributed to their QoS classes.
+// The BE host applications are not throttled by the BE cgroups, so they are kept in the system usage.
+type hostAppCollector struct {
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+	statesInformer   statesinformer.StatesInformer
+	cgroupReader     resourceexecutor.CgroupReader
+	lastAppCPUStat   *gocache.Cache
+	sharedState      *framework.SharedState
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	return &hostAppCollector{
+		collectInterval:  collectInterval,
+		collectorConfigs: opt.CollectorConfigs,
+		started:          atomic.NewBool(false),
+		appendableDB:     opt.MetricCache,
+		statesInformer:   opt.StatesInformer,
+		cgroupReader:     opt.CgroupReader,
+		lastAppCPUStat:   gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.Collector = &hostAppCollector{}
+
+func (h *hostAppCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.HostApplicationCollector)
+}
+
+func (h *hostAppCollector) Setup(c *framework.Context) {
+	h.sharedState = c.State
+}
+
+func (h *hostAppCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, h.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go framework.RunCollector(CollectorName, h.collectorConfigs, h.collectInterval, h.collectHostAppResUsed, stopCh)
+}
+
+func (h *hostAppCollector) Started() bool {
+	return h.started.Load()
+}
+
+func (h *hostAppCollector) collectHostAppResUsed() error {
+	klog.V(6).Info("start collectHostAppResUsed")
+	var hostApps []slov1alpha1.HostApplicationSpec
+	if nodeSLO := h.statesInformer.GetNodeSLO(); nodeSLO != nil {
+		hostApps = nodeSLO.Spec.HostApplications
+	}
+
+	if len(hostApps) <= 0 {
+		// reset the shared usage in case the host applications are all removed
+		now := time.Now()
+		h.sharedState.UpdateHostAppUsage(metriccache.Point{Timestamp: now}, metriccache.Point{Timestamp: now})
+		h.started.Store(true)
+		klog.V(6).Info("skip collectHostAppResUsed, no host application")
+		return nil
+	}
+
+	count := 0
+	metrics := make([]metriccache.MetricSample, 0)
+	nonBECPUUsageCores := metriccache.Point{Timestamp: time.Now(), Value: 0}
+	nonBEMemoryUsage := metriccache.Point{Timestamp: time.Now(), Value: 0}
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		collectTime := time.Now()
+		cgroupDir := koordletutil.GetHostAppCgroupRelativePath(hostApp)
+
+		currentCPUUsage, err0 := h.cgroupReader.ReadCPUAcctUsage(cgroupDir)
+		memStat, err1 := h.cgroupReader.ReadMemoryStat(cgroupDir)
+		if err0 != nil || err1 != nil {
+			klog.V(4).Infof("failed to collect host application usage for %s, CPU err: %s, Memory err: %s",
+				hostApp.Name, err0, err1)
+			continue
+		}
+
+		lastCPUStatValue, ok := h.lastAppCPUStat.Get(hostApp.Name)
+		h.lastAppCPUStat.Set(hostApp.Name, framework.CPUStat{
+			CPUUsage:  currentCPUUsage,
+			Timestamp: collectTime,
+		}, gocache.DefaultExpiration)
+		if !ok {
+			klog.V(4).Infof("ignore the first cpu stat collection for host application %s", hostApp.Name)
+			continue
+		}
+		lastCPUStat := lastCPUStatValue.(framework.CPUStat)
+		// do subtraction and division first to avoid overflow
+		cpuUsageValue := float64(currentCPUUsage-lastCPUStat.CPUUsage) / float64(collectTime.Sub(lastCPUStat.Timestamp))
+
+		cpuUsageMetric, err := metriccache.HostAppCPUUsageMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name), collectTime, cpuUsageValue)
+		if err != nil {
+			klog.V(4).Infof("failed to generate cpu metrics for host application %s, err %v", hostApp.Name, err)
+			continue
+		}
+
+		memUsageValue := memStat.Usage()
+		memUsageMetric, err := metriccache.HostAppMemoryUsageMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name), collectTime, float64(memUsageValue))
+		if err != nil {
+			klog.V(4).Infof("failed to generate memory metrics for host application %s, err %v", hostApp.Name, err)
+			continue
+		}
+
+		metrics = append(metrics, cpuUsageMetric, memUsageMetric)
+		klog.V(6).Infof("collect host application %s finished, metric %+v", hostApp.Name, metrics)
+
+		count++
+		if hostApp.QoS != apiext.QoSBE {
+			nonBECPUUsageCores.Value += cpuUsageValue
+			nonBEMemoryUsage.Value += float64(memUsageValue)
+		}
+	}
+
+	appender := h.appendableDB.Appender()
+	if err := appender.Append(metrics); err != nil {
+		klog.Warningf("Append host application metrics error: %v", err)
+		return err
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("Commit host application metrics failed, error: %v", err)
+		return err
+	}
+
+	h.sharedState.UpdateHostAppUsage(nonBECPUUsageCores, nonBEMemoryUsage)
+
+	// update collect time
+	h.started.Store(true)
+	klog.V(4).Infof("collectHostAppResUsed finished, host application num %d, collected %d",
+		len(hostApps), count)
+	return nil
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostapplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_hostAppCollector_Enabled(t *testing.T) {
+	collector := New(&framework.Options{
+		Config: framework.NewDefaultConfig(),
+	})
+	defer func() {
+		assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+			string(features.HostApplicationCollector): false,
+		}))
+	}()
+	assert.False(t, collector.Enabled())
+	assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+		string(features.HostApplicationCollector): true,
+	}))
+	assert.True(t, collector.Enabled())
+}
+
+func Test_hostAppCollector_collectHostAppResUsed(t *testing.T) {
+	testNow := time.Now()
+	testHostApp := slov1alpha1.HostApplicationSpec{
+		Name: "test-app",
+		QoS:  apiext.QoSLS,
+	}
+	testAppParentDir := "/host-latency-sensitive/test-app"
+	type fields struct {
+		nodeSLO        *slov1alpha1.NodeSLO
+		initAppLastCPU func(lastState *gocache.Cache)
+		SetSysUtil     func(helper *system.FileTestUtil)
+	}
+	type wants struct {
+		cpu    *float64
+		memory *float64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		wants  wants
+	}{
+		{
+			name: "no node slo",
+			fields: fields{
+				nodeSLO: nil,
+			},
+			wants: wants{
+				cpu:    nil,
+				memory: nil,
+			},
+		},
+		{
+			name: "cgroup of host application not exist",
+			fields: fields{
+				nodeSLO: &slov1alpha1.NodeSLO{
+					Spec: slov1alpha1.NodeSLOSpec{
+						HostApplications: []slov1alpha1.HostApplicationSpec{testHostApp},
+					},
+				},
+			},
+			wants: wants{
+				cpu:    nil,
+				memory: nil,
+			},
+		},
+		{
+			name: "ignore the first collection",
+			fields: fields{
+				nodeSLO: &slov1alpha1.NodeSLO{
+					Spec: slov1alpha1.NodeSLOSpec{
+						HostApplications: []slov1alpha1.HostApplicationSpec{testHostApp},
+					},
+				},
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					helper.WriteCgroupFileContents(testAppParentDir, system.CPUAcctUsage, `
+1000000000
+`)
+					helper.WriteCgroupFileContents(testAppParentDir, system.MemoryStat, `
+total_cache 104857600
+total_rss 104857600
+total_inactive_anon 104857600
+total_active_anon 0
+total_inactive_file 104857600
+total_active_file 0
+total_unevictable 0
+`)
+				},
+			},
+			wants: wants{
+				cpu:    nil,
+				memory: nil,
+			},
+		},
+		{
+			name: "collect host application usage",
+			fields: fields{
+				nodeSLO: &slov1alpha1.NodeSLO{
+					Spec: slov1alpha1.NodeSLOSpec{
+						HostApplications: []slov1alpha1.HostApplicationSpec{testHostApp},
+					},
+				},
+				initAppLastCPU: func(lastState *gocache.Cache) {
+					lastState.Set(testHostApp.Name, framework.CPUStat{
+						CPUUsage:  0,
+						Timestamp: testNow.Add(-time.Second),
+					}, gocache.DefaultExpiration)
+				},
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					helper.WriteCgroupFileContents(testAppParentDir, system.CPUAcctUsage, `
+1000000000
+`)
+					helper.WriteCgroupFileContents(testAppParentDir, system.MemoryStat, `
+total_cache 104857600
+total_rss 104857600
+total_inactive_anon 104857600
+total_active_anon 0
+total_inactive_file 104857600
+total_active_file 0
+total_unevictable 0
+`)
+				},
+			},
+			wants: wants{
+				cpu:    pointer.Float64(1),
+				memory: pointer.Float64(104857600),
+			},
+		},
+		{
+			name: "exclude be host application usage from the shared usage",
+			fields: fields{
+				nodeSLO: &slov1alpha1.NodeSLO{
+					Spec: slov1alpha1.NodeSLOSpec{
+						HostApplications: []slov1alpha1.HostApplicationSpec{
+							testHostApp,
+							{
+								Name: "test-be-app",
+								QoS:  apiext.QoSBE,
+							},
+						},
+					},
+				},
+				initAppLastCPU: func(lastState *gocache.Cache) {
+					for _, name := range []string{testHostApp.Name, "test-be-app"} {
+						lastState.Set(name, framework.CPUStat{
+							CPUUsage:  0,
+							Timestamp: testNow.Add(-time.Second),
+						}, gocache.DefaultExpiration)
+					}
+				},
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					for _, dir := range []string{testAppParentDir, "/host-best-effort/test-be-app"} {
+						helper.WriteCgroupFileContents(dir, system.CPUAcctUsage, `
+1000000000
+`)
+						helper.WriteCgroupFileContents(dir, system.MemoryStat, `
+total_cache 104857600
+total_rss 104857600
+total_inactive_anon 104857600
+total_active_anon 0
+total_inactive_file 104857600
+total_active_file 0
+total_unevictable 0
+`)
+					}
+				},
+			},
+			wants: wants{
+				cpu:    pointer.Float64(1),
+				memory: pointer.Float64(104857600),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.fields.SetSysUtil != nil {
+				tt.fields.SetSysUtil(helper)
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              t.TempDir(),
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				metricCache.Close()
+			}()
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			statesInformer.EXPECT().GetNodeSLO().Return(tt.fields.nodeSLO).Times(1)
+
+			collector := New(&framework.Options{
+				Config: &framework.Config{
+					CollectResUsedInterval: 1 * time.Second,
+				},
+				StatesInformer: statesInformer,
+				MetricCache:    metricCache,
+				CgroupReader:   resourceexecutor.NewCgroupReader(),
+			})
+			collector.Setup(&framework.Context{
+				State: framework.NewSharedState(),
+			})
+			c := collector.(*hostAppCollector)
+			if tt.fields.initAppLastCPU != nil {
+				tt.fields.initAppLastCPU(c.lastAppCPUStat)
+			}
+
+			assert.NotPanics(t, func() {
+				c.collectHostAppResUsed()
+			})
+			assert.True(t, c.Started())
+
+			gotCPU, gotMemory := c.sharedState.GetHostAppUsage()
+			assert.NotNil(t, gotCPU)
+			assert.NotNil(t, gotMemory)
+			if tt.wants.cpu == nil {
+				assert.Equal(t, float64(0), gotCPU.Value)
+				assert.Equal(t, float64(0), gotMemory.Value)
+				return
+			}
+			assert.InDelta(t, *tt.wants.cpu, gotCPU.Value, 0.1)
+			assert.Equal(t, *tt.wants.memory, gotMemory.Value)
+
+			querier, err := metricCache.Querier(testNow.Add(-time.Minute), time.Now().Add(time.Minute))
+			assert.NoError(t, err)
+			queryMeta, err := metriccache.HostAppMemoryUsageMetric.BuildQueryMeta(
+				metriccache.MetricPropertiesFunc.HostApplication(testHostApp.Name))
+			assert.NoError(t, err)
+			result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+			assert.NoError(t, querier.Query(queryMeta, nil, result))
+			memValue, err := result.Value(metriccache.AggregationTypeLast)
+			assert.NoError(t, err)
+			assert.Equal(t, *tt.wants.memory, memValue)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	CollectorName = "NodeHealthCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// nodeHealthCollector collects the node-level interference signals which are not covered by the other collectors,
+// including the node PSI and the run queue latency. They are composed into the node health index in the NodeMetric.
+type nodeHealthCollector struct {
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+
+	lastSchedStat *koordletutil.SchedStat
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &nodeHealthCollector{
+		collectInterval:  opt.Config.CollectResUsedInterval,
+		collectorConfigs: opt.CollectorConfigs,
+		started:          atomic.NewBool(false),
+		appendableDB:     opt.MetricCache,
+	}
+}
+
+func (n *nodeHealthCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.NodeHealthIndex)
+}
+
+func (n *nodeHealthCollector) Setup(c *framework.Context) {}
+
+func (n *nodeHealthCollector) Run(stopCh <-chan struct{}) {
+	go framework.RunCollector(CollectorName, n.collectorConfigs, n.collectInterval, n.collectNodeHealth, stopCh)
+}
+
+func (n *nodeHealthCollector) Started() bool {
+	return n.started.Load()
+}
+
+func (n *nodeHealthCollector) collectNodeHealth() error {
+	klog.V(6).Info("collectNodeHealth start")
+	collectTime := timeNow()
+	var nodeMetrics []metriccache.MetricSample
+	nodeMetrics = append(nodeMetrics, n.collectNodePSI(collectTime)...)
+	nodeMetrics = append(nodeMetrics, n.collectRunQueueLatency(collectTime)...)
+	if len(nodeMetrics) == 0 {
+		return nil
+	}
+
+	appender := n.appendableDB.Appender()
+	if err := appender.Append(nodeMetrics); err != nil {
+		klog.ErrorS(err, "Append node health metrics error")
+		return err
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("Commit node health metrics failed, reason: %v", err)
+		return err
+	}
+
+	n.started.Store(true)
+	klog.V(4).Infof("collectNodeHealth finished, count %v", len(nodeMetrics))
+	return nil
+}
+
+func (n *nodeHealthCollector) collectNodePSI(collectTime time.Time) []metriccache.MetricSample {
+	psi, err := resourceexecutor.ReadNodePSI()
+	if err != nil {
+		klog.V(4).Infof("failed to read node psi, err: %v", err)
+		return nil
+	}
+	var samples []metriccache.MetricSample
+	for resource, stats := range map[metriccache.MetricPropertyValue]resourceexecutor.PSIStats{
+		metriccache.PSIResourceCPU: psi.CPU,
+		metriccache.PSIResourceMem: psi.Mem,
+		metriccache.PSIResourceIO:  psi.IO,
+	} {
+		if stats.Some == nil {
+			continue
+		}
+		sample, err := metriccache.NodePSIMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.NodePSI(string(resource)), collectTime, stats.Some.Avg10)
+		if err != nil {
+			klog.Warningf("generate node psi metrics failed, err %v", err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+func (n *nodeHealthCollector) collectRunQueueLatency(collectTime time.Time) []metriccache.MetricSample {
+	schedStat, err := koordletutil.GetSchedStat()
+	if err != nil {
+		klog.V(4).Infof("failed to read node schedstat, err: %v", err)
+		return nil
+	}
+	lastSchedStat := n.lastSchedStat
+	n.lastSchedStat = schedStat
+	if lastSchedStat == nil {
+		klog.V(6).Infof("ignore the first schedstat collection")
+		return nil
+	}
+	if schedStat.Timeslices <= lastSchedStat.Timeslices || schedStat.RunDelay < lastSchedStat.RunDelay {
+		return nil
+	}
+
+	// average run queue latency of the timeslices in microseconds
+	latency := float64(schedStat.RunDelay-lastSchedStat.RunDelay) / float64(schedStat.Timeslices-lastSchedStat.Timeslices) / float64(time.Microsecond)
+	sample, err := metriccache.NodeRunQueueLatencyMetric.GenerateSample(nil, collectTime, latency)
+	if err != nil {
+		klog.Warningf("generate node run queue latency metrics failed, err %v", err)
+		return nil
+	}
+	return []metriccache.MetricSample{sample}
+}
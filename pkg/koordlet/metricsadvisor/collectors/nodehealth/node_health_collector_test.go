@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodehealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_nodeHealthCollector_collectNodeHealth(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		err = metricCache.Close()
+		assert.NoError(t, err)
+	}()
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+
+	helper.WriteProcSubFileContents("pressure/cpu", `some avg10=12.50 avg60=10.00 avg300=5.00 total=100
+full avg10=0.00 avg60=0.00 avg300=0.00 total=0`)
+	helper.WriteProcSubFileContents("pressure/memory", `some avg10=3.00 avg60=2.00 avg300=1.00 total=100
+full avg10=1.00 avg60=1.00 avg300=1.00 total=50`)
+	helper.WriteProcSubFileContents("pressure/io", `some avg10=0.50 avg60=0.40 avg300=0.30 total=100
+full avg10=0.10 avg60=0.10 avg300=0.10 total=50`)
+	// format: cpu0 $yld_count $legacy $sched_count $sched_goidle $ttwu_count $ttwu_local $rq_cpu_time $run_delay $pcount
+	helper.WriteProcSubFileContents(system.ProcSchedStatName, `version 15
+timestamp 4295041592
+cpu0 0 0 0 0 0 0 1000000000 300000000 1000
+domain0 3 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0
+cpu1 0 0 0 0 0 0 1000000000 100000000 1000`)
+
+	c := New(&framework.Options{
+		Config: &framework.Config{
+			CollectResUsedInterval: 1 * time.Second,
+		},
+		MetricCache: metricCache,
+	})
+	collector := c.(*nodeHealthCollector)
+	collector.lastSchedStat = &koordletutil.SchedStat{
+		RunDelay:   200000000,
+		Timeslices: 1000,
+	}
+	collector.collectNodeHealth()
+	assert.True(t, collector.Started())
+
+	querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+	assert.NoError(t, err)
+	for resource, expected := range map[metriccache.MetricPropertyValue]float64{
+		metriccache.PSIResourceCPU: 12.5,
+		metriccache.PSIResourceMem: 3,
+		metriccache.PSIResourceIO:  0.5,
+	} {
+		queryMeta, err := metriccache.NodePSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.NodePSI(string(resource)))
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got, resource)
+	}
+	// (400ms - 200ms) / (2000 - 1000) = 200us
+	queryMeta, err := metriccache.NodeRunQueueLatencyMetric.BuildQueryMeta(nil)
+	assert.NoError(t, err)
+	result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	got, err := result.Value(metriccache.AggregationTypeLast)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(200), got)
+}
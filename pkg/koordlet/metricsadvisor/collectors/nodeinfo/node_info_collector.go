@@ -20,7 +20,6 @@ import (
 	"time"
 
 	"go.uber.org/atomic"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
@@ -35,16 +34,20 @@ const (
 
 // TODO more ut is needed for this plugin
 type nodeInfoCollector struct {
-	collectInterval time.Duration
-	storage         metriccache.KVStorage
-	started         *atomic.Bool
+	collectInterval    time.Duration
+	collectorConfigs   *framework.CollectorConfigs
+	staticTopologyFile string
+	storage            metriccache.KVStorage
+	started            *atomic.Bool
 }
 
 func New(opt *framework.Options) framework.Collector {
 	return &nodeInfoCollector{
-		collectInterval: opt.Config.CollectNodeCPUInfoInterval,
-		storage:         opt.MetricCache,
-		started:         atomic.NewBool(false),
+		collectInterval:    opt.Config.CollectNodeCPUInfoInterval,
+		collectorConfigs:   opt.CollectorConfigs,
+		staticTopologyFile: opt.Config.StaticTopologyFile,
+		storage:            opt.MetricCache,
+		started:            atomic.NewBool(false),
 	}
 }
 
@@ -55,36 +58,54 @@ func (n *nodeInfoCollector) Enabled() bool {
 func (n *nodeInfoCollector) Setup(s *framework.Context) {}
 
 func (n *nodeInfoCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(n.collectNodeInfo, n.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, n.collectorConfigs, n.collectInterval, n.collectNodeInfo, stopCh)
 }
 
 func (n *nodeInfoCollector) Started() bool {
 	return n.started.Load()
 }
 
-func (n *nodeInfoCollector) collectNodeInfo() {
+func (n *nodeInfoCollector) collectNodeInfo() error {
 	started := time.Now()
 
-	err := n.collectNodeCPUInfo()
+	// the static topology file is reloaded in each round, so the updates of the ConfigMap can take effect
+	var staticTopology *koordletutil.StaticTopologySpec
+	if len(n.staticTopologyFile) > 0 {
+		var err error
+		staticTopology, err = koordletutil.LoadStaticTopologySpec(n.staticTopologyFile)
+		if err != nil {
+			klog.Warningf("failed to load static topology, err: %s", err)
+			return err
+		}
+	}
+
+	err := n.collectNodeCPUInfo(staticTopology)
 	if err != nil {
 		klog.Warningf("failed to collect node CPU info, err: %s", err)
-		return
+		return err
 	}
 
-	err = n.collectNodeNUMAInfo()
+	err = n.collectNodeNUMAInfo(staticTopology)
 	if err != nil {
 		klog.Warningf("failed to collect node NUMA info, err: %s", err)
-		return
+		return err
 	}
 
 	n.started.Store(true)
 	klog.V(4).Infof("collect node info finished, elapsed %s", time.Since(started).String())
+	return nil
 }
 
-func (n *nodeInfoCollector) collectNodeCPUInfo() error {
+func (n *nodeInfoCollector) collectNodeCPUInfo(staticTopology *koordletutil.StaticTopologySpec) error {
 	klog.V(6).Info("start collect node cpu info")
 
-	localCPUInfo, err := koordletutil.GetLocalCPUInfo()
+	var localCPUInfo *koordletutil.LocalCPUInfo
+	var err error
+	if staticTopology != nil {
+		localCPUInfo, err = staticTopology.GetLocalCPUInfo()
+	} else {
+		localCPUInfo, err = koordletutil.GetLocalCPUInfo()
+	}
 	if err != nil {
 		metrics.RecordCollectNodeCPUInfoStatus(err)
 		return err
@@ -103,13 +124,20 @@ func (n *nodeInfoCollector) collectNodeCPUInfo() error {
 	return nil
 }
 
-func (n *nodeInfoCollector) collectNodeNUMAInfo() error {
+func (n *nodeInfoCollector) collectNodeNUMAInfo(staticTopology *koordletutil.StaticTopologySpec) error {
 	klog.V(6).Info("start collect node NUMA info")
 
-	nodeNUMAInfo, err := koordletutil.GetNodeNUMAInfo()
-	if err != nil {
-		metrics.RecordCollectNodeNUMAInfoStatus(err)
-		return err
+	var nodeNUMAInfo *koordletutil.NodeNUMAInfo
+	if staticTopology != nil {
+		nodeNUMAInfo = staticTopology.GetNodeNUMAInfo()
+	}
+	if nodeNUMAInfo == nil {
+		var err error
+		nodeNUMAInfo, err = koordletutil.GetNodeNUMAInfo()
+		if err != nil {
+			metrics.RecordCollectNodeNUMAInfoStatus(err)
+			return err
+		}
 	}
 	klog.V(6).Infof("collect NUMA info successfully, info %+v", nodeNUMAInfo)
 
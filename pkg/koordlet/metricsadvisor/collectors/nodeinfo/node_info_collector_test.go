@@ -17,6 +17,7 @@ limitations under the License.
 package nodeinfo
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -203,7 +204,7 @@ Node 1 HugePages_Surp:        0`
 		}
 
 		// test collect successfully
-		err = c.collectNodeNUMAInfo()
+		err = c.collectNodeNUMAInfo(nil)
 		assert.NoError(t, err)
 		nodeNUMAInfoRaw, ok := c.storage.Get(metriccache.NodeNUMAInfoKey)
 		assert.True(t, ok)
@@ -213,7 +214,61 @@ Node 1 HugePages_Surp:        0`
 
 		// test collect failed when sys files are missing
 		helper.Cleanup()
-		err = c.collectNodeNUMAInfo()
+		err = c.collectNodeNUMAInfo(nil)
 		assert.Error(t, err)
 	})
 }
+
+func Test_collectNodeInfoWithStaticTopology(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		err = metricCache.Close()
+		assert.NoError(t, err)
+	}()
+
+	topologyFile := filepath.Join(helper.TempDir, "topology.yaml")
+	helper.WriteFileContents(topologyFile, `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+- {cpu: 1, core: 0, socket: 0, node: 0}
+- {cpu: 2, core: 1, socket: 1, node: 1}
+- {cpu: 3, core: 1, socket: 1, node: 1}
+numaNodes:
+- {node: 0, memory: 4Gi}
+- {node: 1, memory: 4Gi}
+`)
+	c := &nodeInfoCollector{
+		collectInterval:    60 * time.Second,
+		staticTopologyFile: topologyFile,
+		storage:            metricCache,
+		started:            atomic.NewBool(false),
+	}
+	err = c.collectNodeInfo()
+	assert.NoError(t, err)
+	assert.True(t, c.Started())
+
+	nodeCPUInfoRaw, ok := c.storage.Get(metriccache.NodeCPUInfoKey)
+	assert.True(t, ok)
+	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
+	assert.True(t, ok)
+	assert.Equal(t, int32(4), nodeCPUInfo.TotalInfo.NumberCPUs)
+	assert.Equal(t, 2, len(nodeCPUInfo.TotalInfo.NodeToCPU))
+	assert.True(t, nodeCPUInfo.BasicInfo.HyperThreadEnabled)
+
+	nodeNUMAInfoRaw, ok := c.storage.Get(metriccache.NodeNUMAInfoKey)
+	assert.True(t, ok)
+	nodeNUMAInfo, ok := nodeNUMAInfoRaw.(*koordletutil.NodeNUMAInfo)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(nodeNUMAInfo.NUMAInfos))
+	assert.Equal(t, uint64(4<<30), nodeNUMAInfo.MemInfoMap[1].MemTotalBytes())
+
+	// invalid static topology is not reported
+	helper.WriteFileContents(topologyFile, `cpus: []`)
+	err = c.collectNodeInfo()
+	assert.Error(t, err)
+}
@@ -20,15 +20,18 @@ import (
 	"time"
 
 	"go.uber.org/atomic"
-	"k8s.io/apimachinery/pkg/util/wait"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 const (
@@ -41,12 +44,16 @@ var (
 
 // TODO more ut is needed for this plugin
 type nodeResourceCollector struct {
-	collectInterval time.Duration
-	started         *atomic.Bool
-	appendableDB    metriccache.Appendable
-	metricDB        metriccache.MetricCache
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+	metricDB         metriccache.MetricCache
+	statesInformer   statesinformer.StatesInformer
 
-	lastNodeCPUStat *framework.CPUStat
+	lastNodeCPUStat         *framework.CPUStat
+	lastHousekeepingCPUs    cpuset.CPUSet
+	lastHousekeepingCPUStat *framework.CPUStat
 
 	sharedState      *framework.SharedState
 	deviceCollectors map[string]framework.DeviceCollector
@@ -54,10 +61,12 @@ type nodeResourceCollector struct {
 
 func New(opt *framework.Options) framework.Collector {
 	return &nodeResourceCollector{
-		collectInterval: opt.Config.CollectResUsedInterval,
-		started:         atomic.NewBool(false),
-		appendableDB:    opt.MetricCache,
-		metricDB:        opt.MetricCache,
+		collectInterval:  opt.Config.CollectResUsedInterval,
+		collectorConfigs: opt.CollectorConfigs,
+		started:          atomic.NewBool(false),
+		appendableDB:     opt.MetricCache,
+		metricDB:         opt.MetricCache,
+		statesInformer:   opt.StatesInformer,
 	}
 }
 
@@ -78,14 +87,14 @@ func (n *nodeResourceCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for devices to sync")
 	}
-	go wait.Until(n.collectNodeResUsed, n.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, n.collectorConfigs, n.collectInterval, n.collectNodeResUsed, stopCh)
 }
 
 func (n *nodeResourceCollector) Started() bool {
 	return n.started.Load()
 }
 
-func (n *nodeResourceCollector) collectNodeResUsed() {
+func (n *nodeResourceCollector) collectNodeResUsed() error {
 	klog.V(6).Info("collectNodeResUsed start")
 	nodeMetrics := make([]metriccache.MetricSample, 0)
 	collectTime := timeNow()
@@ -96,14 +105,14 @@ func (n *nodeResourceCollector) collectNodeResUsed() {
 	memInfo, err1 := koordletutil.GetMemInfo()
 	if err0 != nil || err1 != nil {
 		klog.Warningf("failed to collect node usage, CPU err: %s, Memory err: %s", err0, err1)
-		return
+		return utilerrors.NewAggregate([]error{err0, err1})
 	}
 
 	memUsageValue := float64(memInfo.MemUsageBytes())
 	memUsageMetrics, err := metriccache.NodeMemoryUsageMetric.GenerateSample(nil, collectTime, memUsageValue)
 	if err != nil {
 		klog.Warningf("generate node cpu metrics failed, err %v", err)
-		return
+		return err
 	}
 	nodeMetrics = append(nodeMetrics, memUsageMetrics)
 
@@ -114,7 +123,7 @@ func (n *nodeResourceCollector) collectNodeResUsed() {
 	}
 	if lastCPUStat == nil {
 		klog.V(6).Infof("ignore the first cpu stat collection")
-		return
+		return nil
 	}
 	// 1 jiffy can be 10ms by default.
 	// NOTE: do subtraction and division first to avoid overflow
@@ -122,7 +131,7 @@ func (n *nodeResourceCollector) collectNodeResUsed() {
 	cpuUsageMetrics, err := metriccache.NodeCPUUsageMetric.GenerateSample(nil, collectTime, cpuUsageValue)
 	if err != nil {
 		klog.Warningf("generate node cpu metrics failed, err %v", err)
-		return
+		return err
 	}
 	nodeMetrics = append(nodeMetrics, cpuUsageMetrics)
 
@@ -138,12 +147,12 @@ func (n *nodeResourceCollector) collectNodeResUsed() {
 	appender := n.appendableDB.Appender()
 	if err := appender.Append(nodeMetrics); err != nil {
 		klog.ErrorS(err, "Append node metrics error")
-		return
+		return err
 	}
 
 	if err := appender.Commit(); err != nil {
 		klog.Warningf("Commit node metrics failed, reason: %v", err)
-		return
+		return err
 	}
 
 	n.sharedState.UpdateNodeUsage(metriccache.Point{Timestamp: collectTime, Value: cpuUsageValue},
@@ -152,7 +161,63 @@ func (n *nodeResourceCollector) collectNodeResUsed() {
 	// update collect time
 	n.started.Store(true)
 	metrics.RecordNodeUsedCPU(cpuUsageValue) // in cpu cores
+	n.collectHousekeepingCPUUsed(collectTime)
 
 	klog.V(4).Infof("collectNodeResUsed finished, count %v, cpu[%v], mem[%v]",
 		len(nodeMetrics), cpuUsageValue, memUsageValue)
+	return nil
+}
+
+// collectHousekeepingCPUUsed records the cpu usage of the housekeeping cpus, which indicates the pressure of
+// the kernel threads, IRQs and the BE pods running on them.
+func (n *nodeResourceCollector) collectHousekeepingCPUUsed(collectTime time.Time) {
+	housekeepingCPUs := n.getHousekeepingCPUs()
+	lastCPUs, lastCPUStat := n.lastHousekeepingCPUs, n.lastHousekeepingCPUStat
+	n.lastHousekeepingCPUs, n.lastHousekeepingCPUStat = housekeepingCPUs, nil
+	if housekeepingCPUs.IsEmpty() {
+		return
+	}
+
+	currentCPUTick, err := koordletutil.GetCPUsStatUsageTicks(housekeepingCPUs)
+	if err != nil {
+		klog.V(4).Infof("failed to collect housekeeping cpu usage, cpus %s, err: %s", housekeepingCPUs, err)
+		return
+	}
+	n.lastHousekeepingCPUStat = &framework.CPUStat{
+		CPUTick:   currentCPUTick,
+		Timestamp: collectTime,
+	}
+	if lastCPUStat == nil || !lastCPUs.Equals(housekeepingCPUs) {
+		klog.V(6).Infof("ignore the first housekeeping cpu stat collection, cpus %s", housekeepingCPUs)
+		return
+	}
+	if currentCPUTick < lastCPUStat.CPUTick {
+		// the ticks of a cpu going offline are missing in the current stat
+		klog.V(4).Infof("ignore the decreased housekeeping cpu stat, cpus %s", housekeepingCPUs)
+		return
+	}
+
+	usedCPU := float64(currentCPUTick-lastCPUStat.CPUTick) / system.GetPeriodTicks(lastCPUStat.Timestamp, collectTime)
+	metrics.RecordHousekeepingUsedCPU(usedCPU)
+	metrics.RecordHousekeepingCPUUsageRatio(usedCPU / float64(housekeepingCPUs.Size()))
+	klog.V(5).Infof("collect housekeeping cpu usage finished, cpus %s, used %v", housekeepingCPUs, usedCPU)
+}
+
+func (n *nodeResourceCollector) getHousekeepingCPUs() cpuset.CPUSet {
+	if n.statesInformer == nil {
+		return cpuset.CPUSet{}
+	}
+	nodeTopo := n.statesInformer.GetNodeTopo()
+	if nodeTopo == nil {
+		return cpuset.CPUSet{}
+	}
+	housekeepingCPUs, err := extension.GetHousekeepingCPUs(nodeTopo.Annotations)
+	if err != nil || housekeepingCPUs == nil {
+		return cpuset.CPUSet{}
+	}
+	cpus, err := cpuset.Parse(housekeepingCPUs.CPUSet)
+	if err != nil {
+		return cpuset.CPUSet{}
+	}
+	return cpus
 }
@@ -21,15 +21,21 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 func Test_nodeResourceCollector(t *testing.T) {
@@ -183,6 +189,47 @@ DirectMap1G:           0 kB`)
 	assert.False(t, c.Started())
 }
 
+func Test_nodeResourceCollector_collectHousekeepingCPUUsed(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testNow := time.Now()
+	testUserTicks := 0.5 * float64(time.Second) / system.Jiffies
+	// format: cpu $user $nice $system $idle $iowait $irq $softirq
+	helper.WriteProcSubFileContents(system.ProcStatName, fmt.Sprintf(`cpu  %v 0 0 0 0 0 0 0 0 0
+cpu0 %v 0 0 0 0 0 0 0 0 0
+cpu1 0 0 0 0 0 0 0 0 0 0`, int(testUserTicks), int(testUserTicks)))
+	nodeTopo := &topov1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				apiext.AnnotationNodeHousekeepingCPUs: `{"cpuset":"0-1"}`,
+			},
+		},
+	}
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetNodeTopo().Return(nodeTopo).AnyTimes()
+
+	c := &nodeResourceCollector{
+		statesInformer: mockStatesInformer,
+	}
+	// ignore the first collection
+	c.collectHousekeepingCPUUsed(testNow.Add(-time.Second))
+	assert.Equal(t, cpuset.NewCPUSet(0, 1), c.lastHousekeepingCPUs)
+	assert.Equal(t, uint64(int(testUserTicks)), c.lastHousekeepingCPUStat.CPUTick)
+
+	c.lastHousekeepingCPUStat.CPUTick = 0
+	c.collectHousekeepingCPUUsed(testNow)
+	assert.Equal(t, uint64(int(testUserTicks)), c.lastHousekeepingCPUStat.CPUTick)
+
+	// housekeeping cpus not found
+	helper.WriteProcSubFileContents(system.ProcStatName, `cpu  0 0 0 0 0 0 0 0 0 0`)
+	c.collectHousekeepingCPUUsed(testNow)
+	assert.Nil(t, c.lastHousekeepingCPUStat)
+}
+
 type fakeDeviceCollector struct {
 	framework.DeviceCollector
 }
@@ -20,7 +20,6 @@ import (
 	"time"
 
 	"go.uber.org/atomic"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -35,16 +34,18 @@ const (
 )
 
 type nodeInfoCollector struct {
-	collectInterval time.Duration
-	storage         metriccache.KVStorage
-	started         *atomic.Bool
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	storage          metriccache.KVStorage
+	started          *atomic.Bool
 }
 
 func New(opt *framework.Options) framework.Collector {
 	return &nodeInfoCollector{
-		collectInterval: opt.Config.CollectNodeStorageInfoInterval,
-		storage:         opt.MetricCache,
-		started:         atomic.NewBool(false),
+		collectInterval:  opt.Config.CollectNodeStorageInfoInterval,
+		collectorConfigs: opt.CollectorConfigs,
+		storage:          opt.MetricCache,
+		started:          atomic.NewBool(false),
 	}
 }
 
@@ -55,21 +56,21 @@ func (n *nodeInfoCollector) Enabled() bool {
 func (n *nodeInfoCollector) Setup(s *framework.Context) {}
 
 func (n *nodeInfoCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(n.collectNodeLocalStorageInfo, n.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, n.collectorConfigs, n.collectInterval, n.collectNodeLocalStorageInfo, stopCh)
 }
 
 func (n *nodeInfoCollector) Started() bool {
 	return n.started.Load()
 }
 
-func (n *nodeInfoCollector) collectNodeLocalStorageInfo() {
+func (n *nodeInfoCollector) collectNodeLocalStorageInfo() error {
 	klog.V(6).Info("start collect node local storage info")
 
 	localStorageInfo, err := koordletutil.GetLocalStorageInfo()
 	if err != nil {
 		klog.Warningf("failed to collect node local storage info, err: %s", err)
 		metrics.RecordCollectNodeLocalStorageInfoStatus(err)
-		return
+		return err
 	}
 
 	nodeLocalStorageInfo := &metriccache.NodeLocalStorageInfo{}
@@ -84,4 +85,5 @@ func (n *nodeInfoCollector) collectNodeLocalStorageInfo() {
 	n.storage.Set(metriccache.NodeLocalStorageInfoKey, nodeLocalStorageInfo)
 	n.started.Store(true)
 	metrics.RecordCollectNodeLocalStorageInfoStatus(nil)
+	return nil
 }
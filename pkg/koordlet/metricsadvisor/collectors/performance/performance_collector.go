@@ -18,4 +18,8 @@ package performance
 
 const (
 	CollectorName = "PerformanceCollector"
+	// CPICollectorName and PSICollectorName are the names to configure the CPI and PSI collecting loops
+	// of the performance collector respectively.
+	CPICollectorName = "CPICollector"
+	PSICollectorName = "PSICollector"
 )
@@ -20,12 +20,13 @@ limitations under the License.
 package performance
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -46,6 +47,7 @@ type performanceCollector struct {
 	cpiCollectInterval        time.Duration
 	psiCollectInterval        time.Duration
 	collectTimeWindowDuration time.Duration
+	collectorConfigs          *framework.CollectorConfigs
 
 	started        *atomic.Bool
 	statesInformer statesinformer.StatesInformer
@@ -58,6 +60,7 @@ func New(opt *framework.Options) framework.Collector {
 		cpiCollectInterval:        opt.Config.CPICollectorInterval,
 		psiCollectInterval:        opt.Config.PSICollectorInterval,
 		collectTimeWindowDuration: opt.Config.CPICollectorTimeWindow,
+		collectorConfigs:          opt.CollectorConfigs,
 
 		started:        atomic.NewBool(false),
 		statesInformer: opt.StatesInformer,
@@ -99,7 +102,7 @@ func (p *performanceCollector) Run(stopCh <-chan struct{}) {
 		}
 
 		if features.DefaultKoordletFeatureGate.Enabled(features.CPICollector) {
-			go wait.Until(p.collectContainerCPI, p.cpiCollectInterval, stopCh)
+			go framework.RunCollector(CPICollectorName, p.collectorConfigs, p.cpiCollectInterval, p.collectContainerCPI, stopCh)
 		}
 	}
 }
@@ -116,7 +119,7 @@ func (p *performanceCollector) Started() bool {
 	return p.started.Load()
 }
 
-func (p *performanceCollector) collectContainerCPI() {
+func (p *performanceCollector) collectContainerCPI() error {
 	klog.V(6).Infof("start collectContainerCPI")
 	timeWindow := time.Now()
 	containerStatusesMap := map[*corev1.ContainerStatus]*statesinformer.PodMeta{}
@@ -130,18 +133,18 @@ func (p *performanceCollector) collectContainerCPI() {
 	}
 	// get container CPI collectors for each container
 	collectors := sync.Map{}
-	var wg sync.WaitGroup
-	wg.Add(len(containerStatusesMap))
 	nodeCPUInfoRaw, exist := p.metricCache.Get(metriccache.NodeCPUInfoKey)
 	if !exist {
 		klog.Error("failed to get node cpu info : not exist")
-		return
+		return fmt.Errorf("node cpu info not exist")
 	}
 	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
 	if !ok {
 		klog.Fatalf("type error, expect %T, but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
 	cpuNumber := nodeCPUInfo.TotalInfo.NumberCPUs
+	var wg sync.WaitGroup
+	wg.Add(len(containerStatusesMap))
 	for containerStatus, parentPod := range containerStatusesMap {
 		go func(status *corev1.ContainerStatus, parent string) {
 			defer wg.Done()
@@ -179,11 +182,14 @@ func (p *performanceCollector) collectContainerCPI() {
 	wg1.Wait()
 
 	// save container CPI metric to tsdb
-	p.saveMetric(cpiMetrics)
+	if err := p.saveMetric(cpiMetrics); err != nil {
+		return err
+	}
 
 	p.started.Store(true)
 	klog.V(5).Infof("collectContainerCPI for time window %s finished at %s, container num %d",
 		timeWindow, time.Now(), len(containerStatusesMap))
+	return nil
 }
 
 func (p *performanceCollector) getAndStartCollectorOnSingleContainer(podParentCgroupDir string, containerStatus *corev1.ContainerStatus, number int32, events []string) (perf.Collector, error) {
@@ -232,7 +238,7 @@ func (p *performanceCollector) profileCPIOnSingleContainer(status *corev1.Contai
 	return cpiMetrics
 }
 
-func (p *performanceCollector) collectContainerPSI() {
+func (p *performanceCollector) collectContainerPSI() error {
 	klog.V(6).Infof("start collectContainerPSI")
 	timeWindow := time.Now()
 	containerStatusesMap := map[*corev1.ContainerStatus]*statesinformer.PodMeta{}
@@ -265,11 +271,12 @@ func (p *performanceCollector) collectContainerPSI() {
 	wg.Wait()
 
 	// save container's psi metrics to tsdb
-	p.saveMetric(psiMetrics)
+	err := p.saveMetric(psiMetrics)
 
 	p.started.Store(true)
 	klog.V(5).Infof("collectContainerPSI for time window %s finished at %s, container num %d",
 		timeWindow, time.Now(), len(containerStatusesMap))
+	return err
 }
 
 func (p *performanceCollector) collectSingleContainerPSI(podParentCgroupDir string, containerStatus *corev1.ContainerStatus, pod *corev1.Pod) []metriccache.MetricSample {
@@ -317,7 +324,7 @@ func (p *performanceCollector) collectSingleContainerPSI(podParentCgroupDir stri
 	return psiMetrics
 }
 
-func (p *performanceCollector) collectPodPSI() {
+func (p *performanceCollector) collectPodPSI() error {
 	klog.V(6).Infof("start collectPodPSI")
 	timeWindow := time.Now()
 	podMetas := p.statesInformer.GetAllPods()
@@ -341,11 +348,12 @@ func (p *performanceCollector) collectPodPSI() {
 	wg.Wait()
 
 	// save pod psi metrics to tsdb
-	p.saveMetric(psiMetrics)
+	err := p.saveMetric(psiMetrics)
 
 	p.started.Store(true)
 	klog.V(5).Infof("collectPodPSI for time window %s finished at %s, pod num %d",
 		timeWindow, time.Now(), len(podMetas))
+	return err
 }
 
 func (p *performanceCollector) collectSinglePodPSI(pod *corev1.Pod, podCgroupDir string) []metriccache.MetricSample {
@@ -400,10 +408,11 @@ func (p *performanceCollector) collectPSI(stopCh <-chan struct{}) {
 			return
 		}
 	}
-	go wait.Until(func() {
-		p.collectContainerPSI()
-		p.collectPodPSI()
-	}, p.psiCollectInterval, stopCh)
+	go framework.RunCollector(PSICollectorName, p.collectorConfigs, p.psiCollectInterval, func() error {
+		containerErr := p.collectContainerPSI()
+		podErr := p.collectPodPSI()
+		return utilerrors.NewAggregate([]error{containerErr, podErr})
+	}, stopCh)
 }
 
 func (p *performanceCollector) saveMetric(samples []metriccache.MetricSample) error {
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podresource
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+// getCPUNUMANodes returns the NUMA node id of each cpu on the node.
+func (p *podResourceCollector) getCPUNUMANodes() (map[int32]int32, error) {
+	if p.metricCache == nil {
+		return nil, fmt.Errorf("metric cache is not initialized")
+	}
+	nodeCPUInfoRaw, exist := p.metricCache.Get(metriccache.NodeCPUInfoKey)
+	if !exist {
+		return nil, fmt.Errorf("node cpu info not exist")
+	}
+	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
+	if !ok || nodeCPUInfo == nil {
+		return nil, fmt.Errorf("node cpu info is illegal, %v", nodeCPUInfoRaw)
+	}
+	cpuNUMANodes := make(map[int32]int32, len(nodeCPUInfo.ProcessorInfos))
+	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		cpuNUMANodes[processor.CPUID] = processor.NodeID
+	}
+	return cpuNUMANodes, nil
+}
+
+// collectPodMemoryLocality collects the memory pages of the pod bound to cpus, splitting them into the pages on
+// the NUMA nodes of the bound cpus (local) and the pages on the other NUMA nodes (remote).
+// The pods not bound to cpus are skipped since their memory locality is undefined.
+func (p *podResourceCollector) collectPodMemoryLocality(meta *statesinformer.PodMeta, cpuNUMANodes map[int32]int32,
+	collectTime time.Time) ([]metriccache.MetricSample, error) {
+	pod := meta.Pod
+	resourceStatus, err := apiext.GetResourceStatus(pod.Annotations)
+	if err != nil || resourceStatus.CPUSet == "" {
+		return nil, nil
+	}
+	cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+	if err != nil {
+		return nil, fmt.Errorf("parse cpuset %s failed, err: %w", resourceStatus.CPUSet, err)
+	}
+	localNUMANodes := map[int]bool{}
+	for _, cpu := range cpus.ToSlice() {
+		numaNode, ok := cpuNUMANodes[int32(cpu)]
+		if !ok {
+			return nil, fmt.Errorf("numa node of cpu %d not found", cpu)
+		}
+		localNUMANodes[int(numaNode)] = true
+	}
+
+	numaStats, err := p.cgroupReader.ReadMemoryNumaStat(meta.CgroupDir)
+	if err != nil {
+		return nil, fmt.Errorf("read memory numa stat failed, err: %w", err)
+	}
+	var localPages, remotePages uint64
+	for _, numaStat := range numaStats {
+		if localNUMANodes[numaStat.NumaId] {
+			localPages += numaStat.PagesNum
+		} else {
+			remotePages += numaStat.PagesNum
+		}
+	}
+
+	uid := string(pod.UID)
+	localMetric, err := metriccache.PodMemoryLocalPagesMetric.GenerateSample(
+		metriccache.MetricPropertiesFunc.Pod(uid), collectTime, float64(localPages))
+	if err != nil {
+		return nil, err
+	}
+	remoteMetric, err := metriccache.PodMemoryRemotePagesMetric.GenerateSample(
+		metriccache.MetricPropertiesFunc.Pod(uid), collectTime, float64(remotePages))
+	if err != nil {
+		return nil, err
+	}
+	return []metriccache.MetricSample{localMetric, remoteMetric}, nil
+}
@@ -23,7 +23,6 @@ import (
 	gocache "github.com/patrickmn/go-cache"
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -41,8 +40,10 @@ const (
 
 type podResourceCollector struct {
 	collectInterval      time.Duration
+	collectorConfigs     *framework.CollectorConfigs
 	started              *atomic.Bool
 	appendableDB         metriccache.Appendable
+	metricCache          metriccache.MetricCache
 	statesInformer       statesinformer.StatesInformer
 	cgroupReader         resourceexecutor.CgroupReader
 	podFilter            framework.PodFilter
@@ -61,8 +62,10 @@ func New(opt *framework.Options) framework.Collector {
 	}
 	return &podResourceCollector{
 		collectInterval:      collectInterval,
+		collectorConfigs:     opt.CollectorConfigs,
 		started:              atomic.NewBool(false),
 		appendableDB:         opt.MetricCache,
+		metricCache:          opt.MetricCache,
 		statesInformer:       opt.StatesInformer,
 		cgroupReader:         opt.CgroupReader,
 		podFilter:            podFilter,
@@ -90,7 +93,7 @@ func (p *podResourceCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for states informer caches to sync")
 	}
-	go wait.Until(p.collectPodResUsed, p.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, p.collectorConfigs, p.collectInterval, p.collectPodResUsed, stopCh)
 }
 
 func (p *podResourceCollector) Started() bool {
@@ -101,13 +104,17 @@ func (p *podResourceCollector) FilterPod(meta *statesinformer.PodMeta) (bool, st
 	return p.podFilter.FilterPod(meta)
 }
 
-func (p *podResourceCollector) collectPodResUsed() {
+func (p *podResourceCollector) collectPodResUsed() error {
 	klog.V(6).Info("start collectPodResUsed")
 	podMetas := p.statesInformer.GetAllPods()
 	count := 0
 	metrics := make([]metriccache.MetricSample, 0)
 	allCPUUsageCores := metriccache.Point{Timestamp: time.Now(), Value: 0}
 	allMemoryUsage := metriccache.Point{Timestamp: time.Now(), Value: 0}
+	cpuNUMANodes, err := p.getCPUNUMANodes()
+	if err != nil {
+		klog.V(5).Infof("skip collecting pod memory locality, get cpu numa nodes failed, err: %v", err)
+	}
 	for _, meta := range podMetas {
 		pod := meta.Pod
 		uid := string(pod.UID) // types.UID
@@ -159,10 +166,17 @@ func (p *podResourceCollector) collectPodResUsed() {
 			metriccache.MetricPropertiesFunc.Pod(uid), collectTime, float64(memUsageValue))
 		if err != nil {
 			klog.V(4).Infof("failed to generate pod mem metrics for pod %s , err %v", podKey, err)
-			return
+			return err
 		}
 
 		metrics = append(metrics, cpuUsageMetric, memUsageMetric)
+		if cpuNUMANodes != nil {
+			if localityMetrics, err := p.collectPodMemoryLocality(meta, cpuNUMANodes, collectTime); err != nil {
+				klog.V(4).Infof("failed to collect memory locality for pod %s, err: %v", podKey, err)
+			} else {
+				metrics = append(metrics, localityMetrics...)
+			}
+		}
 		for deviceName, deviceCollector := range p.deviceCollectors {
 			if deviceMetrics, err := deviceCollector.GetPodMetric(uid, meta.CgroupDir, pod.Status.ContainerStatuses); err != nil {
 				klog.V(4).Infof("get pod %s device usage failed for %v, error: %v", podKey, deviceName, err)
@@ -183,12 +197,12 @@ func (p *podResourceCollector) collectPodResUsed() {
 	appender := p.appendableDB.Appender()
 	if err := appender.Append(metrics); err != nil {
 		klog.Warningf("Append pod metrics error: %v", err)
-		return
+		return err
 	}
 
 	if err := appender.Commit(); err != nil {
 		klog.Warningf("Commit pod metrics failed, error: %v", err)
-		return
+		return err
 	}
 
 	p.sharedState.UpdatePodUsage(CollectorName, allCPUUsageCores, allMemoryUsage)
@@ -196,6 +210,7 @@ func (p *podResourceCollector) collectPodResUsed() {
 	// update collect time
 	p.started.Store(true)
 	klog.V(4).Infof("collectPodResUsed finished, pod num %d, collected %d", len(podMetas), count)
+	return nil
 }
 
 func (p *podResourceCollector) collectContainerResUsed(meta *statesinformer.PodMeta) []metriccache.MetricSample {
@@ -27,11 +27,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -317,6 +319,128 @@ total_unevictable 0
 	}
 }
 
+func Test_podResourceCollector_collectPodMemoryLocality(t *testing.T) {
+	testNow := time.Now()
+	testPodMetaDir := "kubepods.slice/kubepods-podxxxxxxxx.slice"
+	testPodParentDir := "/kubepods.slice/kubepods-podxxxxxxxx.slice"
+	testNUMAStat := `total=400 N0=300 N1=100
+file=200 N0=150 N1=50
+anon=200 N0=150 N1=50
+unevictable=0 N0=0 N1=0
+hierarchical_total=400 N0=300 N1=100
+hierarchical_file=200 N0=150 N1=50
+hierarchical_anon=200 N0=150 N1=50
+hierarchical_unevictable=0 N0=0 N1=0`
+	cpuNUMANodes := map[int32]int32{0: 0, 1: 0, 2: 1, 3: 1}
+	newTestPod := func(cpuset string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-pod",
+				Namespace:   "test",
+				UID:         "xxxxxxxx",
+				Annotations: map[string]string{},
+			},
+		}
+		if cpuset != "" {
+			pod.Annotations[apiext.AnnotationResourceStatus] = `{"cpuset": "` + cpuset + `"}`
+		}
+		return pod
+	}
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantLocal  float64
+		wantRemote float64
+		wantSkip   bool
+		wantErr    bool
+	}{
+		{
+			name:     "skip pod not bound to cpus",
+			pod:      newTestPod(""),
+			wantSkip: true,
+		},
+		{
+			name:       "pod bound to numa node 0",
+			pod:        newTestPod("0-1"),
+			wantLocal:  300,
+			wantRemote: 100,
+		},
+		{
+			name:       "pod bound to numa node 1",
+			pod:        newTestPod("2"),
+			wantLocal:  100,
+			wantRemote: 300,
+		},
+		{
+			name:       "pod bound to all numa nodes",
+			pod:        newTestPod("1-2"),
+			wantLocal:  400,
+			wantRemote: 0,
+		},
+		{
+			name:    "cpu not found in node cpu info",
+			pod:     newTestPod("8"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteCgroupFileContents(testPodParentDir, system.MemoryNumaStat, testNUMAStat)
+
+			c := &podResourceCollector{
+				cgroupReader: resourceexecutor.NewCgroupReader(),
+			}
+			got, err := c.collectPodMemoryLocality(&statesinformer.PodMeta{
+				CgroupDir: testPodMetaDir,
+				Pod:       tt.pod,
+			}, cpuNUMANodes, testNow)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if tt.wantErr {
+				return
+			}
+			if tt.wantSkip {
+				assert.Nil(t, got)
+				return
+			}
+			wantLocalMetric, err := metriccache.PodMemoryLocalPagesMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.Pod(string(tt.pod.UID)), testNow, tt.wantLocal)
+			assert.NoError(t, err)
+			wantRemoteMetric, err := metriccache.PodMemoryRemotePagesMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.Pod(string(tt.pod.UID)), testNow, tt.wantRemote)
+			assert.NoError(t, err)
+			assert.Equal(t, []metriccache.MetricSample{wantLocalMetric, wantRemoteMetric}, got)
+		})
+	}
+}
+
+func Test_podResourceCollector_getCPUNUMANodes(t *testing.T) {
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+	c := &podResourceCollector{
+		metricCache: metricCache,
+	}
+	_, err = c.getCPUNUMANodes()
+	assert.Error(t, err)
+
+	metricCache.Set(metriccache.NodeCPUInfoKey, &metriccache.NodeCPUInfo{
+		ProcessorInfos: []koordletutil.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 1, SocketID: 1, NodeID: 1},
+		},
+	})
+	got, err := c.getCPUNUMANodes()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]int32{0: 0, 1: 1}, got)
+}
+
 func Test_podResourceCollector_Run(t *testing.T) {
 	helper := system.NewFileTestUtil(t)
 	defer helper.Cleanup()
@@ -22,7 +22,6 @@ import (
 	gocache "github.com/patrickmn/go-cache"
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -41,12 +40,13 @@ const (
 
 // TODO more ut is needed for this plugin
 type podThrottledCollector struct {
-	collectInterval time.Duration
-	started         *atomic.Bool
-	appendableDB    metriccache.Appendable
-	statesInformer  statesinformer.StatesInformer
-	cgroupReader    resourceexecutor.CgroupReader
-	podFilter       framework.PodFilter
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+	statesInformer   statesinformer.StatesInformer
+	cgroupReader     resourceexecutor.CgroupReader
+	podFilter        framework.PodFilter
 
 	lastPodCPUThrottled       *gocache.Cache
 	lastContainerCPUThrottled *gocache.Cache
@@ -60,6 +60,7 @@ func New(opt *framework.Options) framework.Collector {
 	}
 	return &podThrottledCollector{
 		collectInterval:           collectInterval,
+		collectorConfigs:          opt.CollectorConfigs,
 		started:                   atomic.NewBool(false),
 		appendableDB:              opt.MetricCache,
 		statesInformer:            opt.StatesInformer,
@@ -83,7 +84,7 @@ func (c *podThrottledCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for states informer caches to sync")
 	}
-	go wait.Until(c.collectPodThrottledInfo, c.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, c.collectorConfigs, c.collectInterval, c.collectPodThrottledInfo, stopCh)
 }
 
 func (c *podThrottledCollector) Started() bool {
@@ -94,7 +95,7 @@ func (c *podThrottledCollector) FilterPod(meta *statesinformer.PodMeta) (bool, s
 	return c.podFilter.FilterPod(meta)
 }
 
-func (c *podThrottledCollector) collectPodThrottledInfo() {
+func (c *podThrottledCollector) collectPodThrottledInfo() error {
 	klog.V(6).Info("start collectPodThrottledInfo")
 	podMetas := c.statesInformer.GetAllPods()
 	podAndContainerMetrics := make([]metriccache.MetricSample, 0)
@@ -149,14 +150,15 @@ func (c *podThrottledCollector) collectPodThrottledInfo() {
 	appender := c.appendableDB.Appender()
 	if err := appender.Append(podAndContainerMetrics); err != nil {
 		klog.Warningf("append pods throttled metrics failed, reason: %v", err)
-		return
+		return err
 	}
 	if err := appender.Commit(); err != nil {
 		klog.Warningf("append pods throttled metrics failed, reason: %v", err)
-		return
+		return err
 	}
 	c.started.Store(true)
 	klog.V(5).Infof("collectPodThrottledInfo finished, pod num %d", len(podMetas))
+	return nil
 }
 
 func (c *podThrottledCollector) collectContainerThrottledInfo(podMeta *statesinformer.PodMeta) []metriccache.MetricSample {
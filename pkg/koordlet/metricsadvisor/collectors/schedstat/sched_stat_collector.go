@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedstat
+
+import (
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	CollectorName = "SchedStatCollector"
+)
+
+// schedStatCollector collects the task migrations and context switches of the containers. The involuntary
+// migrations and switches are raised by the cpuset churn, e.g. the cpu suppression resizing the BE cpuset,
+// and they help to tune the suppress policies to avoid the excessive task thrash.
+type schedStatCollector struct {
+	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
+	started          *atomic.Bool
+	appendableDB     metriccache.Appendable
+	statesInformer   statesinformer.StatesInformer
+	cgroupReader     resourceexecutor.CgroupReader
+	podFilter        framework.PodFilter
+
+	lastContainerSchedStat *gocache.Cache
+}
+
+type schedStatPoint struct {
+	stat      *koordletutil.TaskSchedStat
+	timestamp time.Time
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &schedStatCollector{
+		collectInterval:        collectInterval,
+		collectorConfigs:       opt.CollectorConfigs,
+		started:                atomic.NewBool(false),
+		appendableDB:           opt.MetricCache,
+		statesInformer:         opt.StatesInformer,
+		cgroupReader:           opt.CgroupReader,
+		podFilter:              podFilter,
+		lastContainerSchedStat: gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.PodCollector = &schedStatCollector{}
+
+func (c *schedStatCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.SchedStatCollector)
+}
+
+func (c *schedStatCollector) Setup(ctx *framework.Context) {}
+
+func (c *schedStatCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go framework.RunCollector(CollectorName, c.collectorConfigs, c.collectInterval, c.collectContainerSchedStat, stopCh)
+}
+
+func (c *schedStatCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *schedStatCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return c.podFilter.FilterPod(meta)
+}
+
+func (c *schedStatCollector) collectContainerSchedStat() error {
+	klog.V(6).Info("start collectContainerSchedStat")
+	podMetas := c.statesInformer.GetAllPods()
+	containerMetrics := make([]metriccache.MetricSample, 0)
+	metrics.ResetContainerSchedStat()
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		if filtered, msg := c.FilterPod(meta); filtered {
+			klog.V(5).Infof("skip collect pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+			continue
+		}
+		containerMetrics = append(containerMetrics, c.collectPodContainersSchedStat(meta)...)
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append(containerMetrics); err != nil {
+		klog.Warningf("append containers sched stat metrics failed, reason: %v", err)
+		return err
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("append containers sched stat metrics failed, reason: %v", err)
+		return err
+	}
+	c.started.Store(true)
+	klog.V(5).Infof("collectContainerSchedStat finished, pod num %d", len(podMetas))
+	return nil
+}
+
+func (c *schedStatCollector) collectPodContainersSchedStat(podMeta *statesinformer.PodMeta) []metriccache.MetricSample {
+	pod := podMeta.Pod
+	containersMetric := make([]metriccache.MetricSample, 0, 3*len(pod.Status.ContainerStatuses))
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		if len(containerStat.ContainerID) == 0 || containerStat.State.Running == nil {
+			klog.V(6).Infof("container %s/%s/%s is not running, skip this round",
+				pod.Namespace, pod.Name, containerStat.Name)
+			continue
+		}
+
+		containerCgroupDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(4).Infof("collect container %s/%s/%s sched stat failed, cannot get container cgroup, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		tasks, err := c.cgroupReader.ReadCPUTasks(containerCgroupDir)
+		if err != nil {
+			klog.V(4).Infof("collect container %s/%s/%s sched stat failed, cannot get tasks, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		collectTime := time.Now()
+		currentStat, err := koordletutil.GetTasksSchedStat(tasks)
+		if err != nil {
+			klog.V(4).Infof("collect container %s/%s/%s sched stat failed, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+
+		lastPointValue, ok := c.lastContainerSchedStat.Get(containerStat.ContainerID)
+		c.lastContainerSchedStat.Set(containerStat.ContainerID, &schedStatPoint{stat: currentStat, timestamp: collectTime}, gocache.DefaultExpiration)
+		if !ok {
+			klog.V(6).Infof("collect container %s/%s/%s sched stat first point",
+				pod.Namespace, pod.Name, containerStat.Name)
+			continue
+		}
+		lastPoint := lastPointValue.(*schedStatPoint)
+		seconds := collectTime.Sub(lastPoint.timestamp).Seconds()
+		if seconds <= 0 {
+			continue
+		}
+		migrations := calcRate(currentStat.Migrations, lastPoint.stat.Migrations, seconds)
+		voluntarySwitches := calcRate(currentStat.VoluntarySwitches, lastPoint.stat.VoluntarySwitches, seconds)
+		involuntarySwitches := calcRate(currentStat.InvoluntarySwitches, lastPoint.stat.InvoluntarySwitches, seconds)
+		metrics.RecordContainerSchedStat(containerStat, pod, migrations, voluntarySwitches, involuntarySwitches)
+
+		for resource, value := range map[metriccache.MetricPropertyValue]float64{
+			metriccache.SchedStatResourceMigrations:          migrations,
+			metriccache.SchedStatResourceVoluntarySwitches:   voluntarySwitches,
+			metriccache.SchedStatResourceInvoluntarySwitches: involuntarySwitches,
+		} {
+			sample, err := metriccache.ContainerSchedStatMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.ContainerSchedStat(string(pod.UID), containerStat.ContainerID, string(resource)),
+				collectTime, value)
+			if err != nil {
+				klog.Warningf("generate container %s/%s/%s sched stat metrics failed, err %v",
+					pod.Namespace, pod.Name, containerStat.Name, err)
+				continue
+			}
+			containersMetric = append(containersMetric, sample)
+		}
+	}
+	return containersMetric
+}
+
+// calcRate returns the per-second rate of a counter summed over the tasks.
+// The sum can decrease when some tasks exit, and the rate is considered zero then.
+func calcRate(current, last uint64, seconds float64) float64 {
+	if current <= last {
+		return 0
+	}
+	return float64(current-last) / seconds
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedstat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_schedStatCollector_collectContainerSchedStat(t *testing.T) {
+	testContainerID := "containerd://testContainerUID"
+	testPodMetaDir := "kubepods.slice/kubepods-podtest-pod-uid.slice"
+	testContainerParentDir := "/kubepods.slice/kubepods-podtest-pod-uid.slice/cri-containerd-testContainerUID.scope"
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: testContainerID,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		initLastStat func(lastState *gocache.Cache)
+		wantSamples  int
+	}{
+		{
+			name:        "first point",
+			wantSamples: 0,
+		},
+		{
+			name: "collect rates",
+			initLastStat: func(lastState *gocache.Cache) {
+				lastState.Set(testContainerID, &schedStatPoint{
+					stat:      &koordletutil.TaskSchedStat{Migrations: 1, VoluntarySwitches: 10, InvoluntarySwitches: 2},
+					timestamp: time.Now().Add(-time.Second),
+				}, gocache.DefaultExpiration)
+			},
+			wantSamples: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteCgroupFileContents(testContainerParentDir, system.CPUTasks, "1000\n1001\n")
+			helper.WriteProcSubFileContents("1000/sched", "se.nr_migrations : 3\nnr_voluntary_switches : 10\nnr_involuntary_switches : 5\n")
+			helper.WriteProcSubFileContents("1001/sched", "se.nr_migrations : 2\nnr_voluntary_switches : 20\nnr_involuntary_switches : 1\n")
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              helper.TempDir,
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				metricCache.Close()
+			}()
+			podMeta := &statesinformer.PodMeta{
+				CgroupDir: testPodMetaDir,
+				Pod:       testPod,
+			}
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+			statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+
+			collector := New(&framework.Options{
+				Config: &framework.Config{
+					CollectResUsedInterval: time.Second,
+				},
+				StatesInformer: statesInformer,
+				MetricCache:    metricCache,
+				CgroupReader:   resourceexecutor.NewCgroupReader(),
+			})
+			c := collector.(*schedStatCollector)
+			assert.False(t, c.Enabled())
+			if tt.initLastStat != nil {
+				tt.initLastStat(c.lastContainerSchedStat)
+			}
+			samples := c.collectPodContainersSchedStat(podMeta)
+			assert.Equal(t, tt.wantSamples, len(samples))
+			assert.NotPanics(t, func() {
+				c.collectContainerSchedStat()
+			})
+			assert.True(t, c.Started())
+		})
+	}
+}
+
+func Test_calcRate(t *testing.T) {
+	assert.Equal(t, float64(5), calcRate(20, 10, 2))
+	assert.Equal(t, float64(0), calcRate(10, 20, 2))
+	assert.Equal(t, float64(0), calcRate(10, 10, 2))
+}
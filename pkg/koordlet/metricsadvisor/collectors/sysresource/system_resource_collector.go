@@ -21,7 +21,6 @@ import (
 	"time"
 
 	"go.uber.org/atomic"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -40,6 +39,7 @@ var (
 
 type systemResourceCollector struct {
 	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
 	outdatedInterval time.Duration
 	started          *atomic.Bool
 	appendableDB     metriccache.Appendable
@@ -49,6 +49,7 @@ type systemResourceCollector struct {
 func New(opt *framework.Options) framework.Collector {
 	return &systemResourceCollector{
 		collectInterval:  opt.Config.CollectResUsedInterval,
+		collectorConfigs: opt.CollectorConfigs,
 		outdatedInterval: opt.Config.CollectSysMetricOutdatedInterval,
 		started:          atomic.NewBool(false),
 		appendableDB:     opt.MetricCache,
@@ -76,14 +77,14 @@ func (s *systemResourceCollector) Run(stopCh <-chan struct{}) {
 	if !cache.WaitForCacheSync(stopCh, dependencyStarted) {
 		klog.Fatal("time out waiting for other collector started")
 	}
-	go wait.Until(s.collectSysResUsed, s.collectInterval, stopCh)
+	go framework.RunCollector(CollectorName, s.collectorConfigs, s.collectInterval, s.collectSysResUsed, stopCh)
 }
 
 func (s *systemResourceCollector) Started() bool {
 	return s.started.Load()
 }
 
-func (s *systemResourceCollector) collectSysResUsed() {
+func (s *systemResourceCollector) collectSysResUsed() error {
 	klog.V(6).Info("collectSysResUsed start")
 
 	// get node resource usage
@@ -91,49 +92,54 @@ func (s *systemResourceCollector) collectSysResUsed() {
 	nodeCPU, nodeMemory := s.sharedState.GetNodeUsage()
 	if nodeCPU == nil || nodeMemory == nil {
 		klog.Warningf("node resource cpu %v or memory %v is empty during collect system usage", nodeCPU, nodeMemory)
-		return
+		return fmt.Errorf("node resource usage is empty")
 	}
 	if nodeCPU.Timestamp.Before(validTime) || nodeMemory.Timestamp.Before(validTime) {
 		klog.Warningf("node resource metric is timeout, valid time %v, metric time is %v and %v",
 			validTime.String(), nodeCPU.Timestamp.String(), nodeMemory.Timestamp.String())
-		return
+		return fmt.Errorf("node resource usage is outdated")
 	}
 
 	// get all pod resource usage
 	podsCPUUsage, podsMemoryUsage, err := s.getAllPodsResourceUsage()
 	if err != nil {
 		klog.Warningf("get all pods resource usage failed, error %v", err)
-		return
+		return err
 	}
 
+	// get the non-BE host application resource usage, which is attributed to the host applications rather than the
+	// system, while the BE host applications are kept in the system usage and counted against the BE budget
+	hostAppCPUUsage, hostAppMemoryUsage := s.getHostAppResourceUsage()
+
 	// calculate system resource usage
 	collectTime := timeNow()
-	systemCPUUsage := util.MaxFloat64(nodeCPU.Value-podsCPUUsage, 0)
-	systemMemoryUsage := util.MaxFloat64(nodeMemory.Value-podsMemoryUsage, 0)
+	systemCPUUsage := util.MaxFloat64(nodeCPU.Value-podsCPUUsage-hostAppCPUUsage, 0)
+	systemMemoryUsage := util.MaxFloat64(nodeMemory.Value-podsMemoryUsage-hostAppMemoryUsage, 0)
 	systemCPUMetric, err := metriccache.SystemCPUUsageMetric.GenerateSample(nil, collectTime, systemCPUUsage)
 	if err != nil {
 		klog.Warningf("generate system cpu metric failed, err %v", err)
-		return
+		return err
 	}
 	systemMemoryMetric, err := metriccache.SystemMemoryUsageMetric.GenerateSample(nil, collectTime, systemMemoryUsage)
 	if err != nil {
 		klog.Warningf("generate system memory metric failed, err %v", err)
-		return
+		return err
 	}
 
 	// commit metric sample
 	appender := s.appendableDB.Appender()
 	if err := appender.Append([]metriccache.MetricSample{systemCPUMetric, systemMemoryMetric}); err != nil {
 		klog.ErrorS(err, "append system metrics error")
-		return
+		return err
 	}
 	if err := appender.Commit(); err != nil {
 		klog.ErrorS(err, "commit system metrics error")
-		return
+		return err
 	}
 
 	klog.V(4).Infof("collect system resource usage finished, cpu %v, memory %v", systemCPUUsage, systemMemoryUsage)
 	s.started.Store(true)
+	return nil
 }
 
 func (s *systemResourceCollector) getAllPodsResourceUsage() (cpuCore float64, memory float64, err error) {
@@ -161,3 +167,19 @@ func (s *systemResourceCollector) getAllPodsResourceUsage() (cpuCore float64, me
 	}
 	return
 }
+
+// getHostAppResourceUsage returns the resource usage of the non-BE host applications.
+// The usage is regarded as zero when there is no host application or the metric is outdated.
+func (s *systemResourceCollector) getHostAppResourceUsage() (cpuCore float64, memory float64) {
+	validTime := timeNow().Add(-s.outdatedInterval)
+	hostAppCPU, hostAppMemory := s.sharedState.GetHostAppUsage()
+	if hostAppCPU == nil || hostAppMemory == nil {
+		return 0, 0
+	}
+	if hostAppCPU.Timestamp.Before(validTime) || hostAppMemory.Timestamp.Before(validTime) {
+		klog.V(5).Infof("host application resource metric is timeout, valid time %v, metric time is %v and %v",
+			validTime.String(), hostAppCPU.Timestamp.String(), hostAppMemory.Timestamp.String())
+		return 0, 0
+	}
+	return hostAppCPU.Value, hostAppMemory.Value
+}
@@ -20,7 +20,6 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -35,13 +34,15 @@ const (
 type gpuCollector struct {
 	enabled          bool
 	collectInterval  time.Duration
+	collectorConfigs *framework.CollectorConfigs
 	gpuDeviceManager GPUDeviceManager
 }
 
 func New(opt *framework.Options) framework.DeviceCollector {
 	return &gpuCollector{
-		enabled:         features.DefaultKoordletFeatureGate.Enabled(features.Accelerators),
-		collectInterval: opt.Config.CollectResUsedInterval,
+		enabled:          features.DefaultKoordletFeatureGate.Enabled(features.Accelerators),
+		collectInterval:  opt.Config.CollectResUsedInterval,
+		collectorConfigs: opt.CollectorConfigs,
 	}
 }
 
@@ -60,11 +61,12 @@ func (g *gpuCollector) Setup(fra *framework.Context) {
 }
 
 func (g *gpuCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(g.gpuDeviceManager.collectGPUUsage, g.collectInterval, stopCh)
+	go framework.RunCollector(DeviceCollectorName, g.collectorConfigs, g.collectInterval, g.gpuDeviceManager.collectGPUUsage, stopCh)
 }
 
 func (g *gpuCollector) Started() bool {
-	return g.gpuDeviceManager.started()
+	// the collector disabled at runtime does not block the collectors depending on it
+	return !g.collectorConfigs.IsEnabled(DeviceCollectorName) || g.gpuDeviceManager.started()
 }
 
 func (g *gpuCollector) Infos() metriccache.Devices {
@@ -72,21 +74,30 @@ func (g *gpuCollector) Infos() metriccache.Devices {
 }
 
 func (g *gpuCollector) GetNodeMetric() ([]metriccache.MetricSample, error) {
+	if !g.collectorConfigs.IsEnabled(DeviceCollectorName) {
+		return nil, nil
+	}
 	return g.gpuDeviceManager.getNodeGPUUsage(), nil
 }
 
 func (g *gpuCollector) GetPodMetric(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	if !g.collectorConfigs.IsEnabled(DeviceCollectorName) {
+		return nil, nil
+	}
 	return g.gpuDeviceManager.getPodGPUUsage(uid, podParentDir, cs)
 }
 
 func (g *gpuCollector) GetContainerMetric(ContainerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	if !g.collectorConfigs.IsEnabled(DeviceCollectorName) {
+		return nil, nil
+	}
 	return g.gpuDeviceManager.getContainerGPUUsage(ContainerID, podParentDir, c)
 }
 
 type GPUDeviceManager interface {
 	started() bool
 	deviceInfos() metriccache.Devices
-	collectGPUUsage()
+	collectGPUUsage() error
 	getNodeGPUUsage() []metriccache.MetricSample
 	getPodGPUUsage(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error)
 	getContainerGPUUsage(containerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error)
@@ -103,7 +114,9 @@ func (d *dummyDeviceManager) deviceInfos() metriccache.Devices {
 	return nil
 }
 
-func (d *dummyDeviceManager) collectGPUUsage() {}
+func (d *dummyDeviceManager) collectGPUUsage() error {
+	return nil
+}
 
 func (d *dummyDeviceManager) getNodeGPUUsage() []metriccache.MetricSample {
 	return nil
@@ -19,13 +19,13 @@ package gpu
 import (
 	"errors"
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -276,56 +276,68 @@ func (g *gpuDeviceManager) getContainerGPUUsage(containerID, podParentDir string
 	return g.getPodOrContinerTotalGPUUsageOfPIDs(containerID, false, currentPIDs), nil
 }
 
-func (g *gpuDeviceManager) collectGPUUsage() {
+func (g *gpuDeviceManager) collectGPUUsage() error {
+	var errs []error
 	processesGPUUsages := make(map[uint32][]*rawGPUMetric)
 	for deviceIndex, gpuDevice := range g.devices {
 		processesInfos, ret := gpuDevice.Device.GetComputeRunningProcesses()
 		if ret != nvml.SUCCESS {
 			klog.Warningf("Unable to get process info for device at index %d: %v", deviceIndex, nvml.ErrorString(ret))
+			errs = append(errs, fmt.Errorf("failed to get process info for device at index %d: %v", deviceIndex, nvml.ErrorString(ret)))
 			continue
 		}
 		processUtilizations, ret := gpuDevice.Device.GetProcessUtilization(1024)
-		if ret != nvml.SUCCESS {
+		if ret == nvml.ERROR_NOT_FOUND {
+			// no process is sampled in the period, still attribute the memory of the processes
+			processUtilizations = nil
+		} else if ret != nvml.SUCCESS {
 			klog.Warningf("Unable to get process utilization for device at index %d: %v", deviceIndex, nvml.ErrorString(ret))
+			errs = append(errs, fmt.Errorf("failed to get process utilization for device at index %d: %v", deviceIndex, nvml.ErrorString(ret)))
 			continue
 		}
 
-		// Sort by pid.
-		sort.Slice(processesInfos, func(i, j int) bool {
-			return processesInfos[i].Pid < processesInfos[j].Pid
-		})
-		sort.Slice(processUtilizations, func(i, j int) bool {
-			return processUtilizations[i].Pid < processUtilizations[j].Pid
-		})
-
 		klog.V(3).Infof("Found %d processes on device %d\n", len(processesInfos), deviceIndex)
-		for _, info := range processesInfos {
-			var utilization *nvml.ProcessUtilizationSample
-			for i := range processUtilizations {
-				if processUtilizations[i].Pid == info.Pid {
-					utilization = &processUtilizations[i]
-					break
-				}
-			}
-			if utilization == nil {
-				continue
-			}
-			if _, ok := processesGPUUsages[info.Pid]; !ok {
-				// pid not exist.
-				// init processes gpu metric array.
-				processesGPUUsages[info.Pid] = make([]*rawGPUMetric, g.deviceCount)
-			}
-			processesGPUUsages[info.Pid][deviceIndex] = &rawGPUMetric{
-				SMUtil:     utilization.SmUtil,
-				MemoryUsed: info.UsedGpuMemory,
-			}
-		}
+		attributeProcessGPUUsage(processesGPUUsages, deviceIndex, g.deviceCount, processesInfos, processUtilizations)
 	}
 	g.Lock()
 	g.processesMetrics = processesGPUUsages
 	g.collectTime = time.Now()
 	g.start.Store(true)
 	g.Unlock()
+	return utilerrors.NewAggregate(errs)
+}
+
+// attributeProcessGPUUsage attributes the GPU usage of the device to the processes running on it, so the usage of the
+// pods sharing one GPU can be summed up by their processes.
+// The memory of a process is attributed even if it has no utilization sample in the period, e.g. an idle process
+// holding the memory. If a process has multiple utilization samples, the latest one is used.
+func attributeProcessGPUUsage(processesGPUUsages map[uint32][]*rawGPUMetric, deviceIndex, deviceCount int,
+	processesInfos []nvml.ProcessInfo, processUtilizations []nvml.ProcessUtilizationSample) {
+	latestUtilizations := make(map[uint32]*nvml.ProcessUtilizationSample, len(processUtilizations))
+	for i := range processUtilizations {
+		sample := &processUtilizations[i]
+		if latest, ok := latestUtilizations[sample.Pid]; !ok || sample.TimeStamp > latest.TimeStamp {
+			latestUtilizations[sample.Pid] = sample
+		}
+	}
+
+	for _, info := range processesInfos {
+		if _, ok := processesGPUUsages[info.Pid]; !ok {
+			// pid not exist.
+			// init processes gpu metric array.
+			processesGPUUsages[info.Pid] = make([]*rawGPUMetric, deviceCount)
+		}
+		metric := processesGPUUsages[info.Pid][deviceIndex]
+		if metric == nil {
+			metric = &rawGPUMetric{}
+			processesGPUUsages[info.Pid][deviceIndex] = metric
+			if utilization := latestUtilizations[info.Pid]; utilization != nil {
+				metric.SMUtil = utilization.SmUtil
+			}
+		}
+		// a process may be listed more than once on the same device, e.g. with multiple MIG instances
+		metric.MemoryUsed += info.UsedGpuMemory
+	}
 }
 
 func (g *gpuDeviceManager) started() bool {
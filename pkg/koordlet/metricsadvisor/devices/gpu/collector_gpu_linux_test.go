@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 
 	"github.com/stretchr/testify/assert"
@@ -859,3 +860,28 @@ func Test_gpuDeviceManager_deviceInfos(t *testing.T) {
 		})
 	}
 }
+
+func Test_attributeProcessGPUUsage(t *testing.T) {
+	processesGPUUsages := map[uint32][]*rawGPUMetric{
+		100: {{SMUtil: 30, MemoryUsed: 1000}, nil},
+	}
+	processesInfos := []nvml.ProcessInfo{
+		{Pid: 100, UsedGpuMemory: 2000},
+		{Pid: 200, UsedGpuMemory: 3000},
+		{Pid: 300, UsedGpuMemory: 500},
+		{Pid: 300, UsedGpuMemory: 700},
+	}
+	processUtilizations := []nvml.ProcessUtilizationSample{
+		{Pid: 100, TimeStamp: 2, SmUtil: 40},
+		{Pid: 100, TimeStamp: 1, SmUtil: 10},
+		{Pid: 300, TimeStamp: 1, SmUtil: 20},
+	}
+	attributeProcessGPUUsage(processesGPUUsages, 1, 2, processesInfos, processUtilizations)
+
+	want := map[uint32][]*rawGPUMetric{
+		100: {{SMUtil: 30, MemoryUsed: 1000}, {SMUtil: 40, MemoryUsed: 2000}},
+		200: {nil, {SMUtil: 0, MemoryUsed: 3000}},
+		300: {nil, {SMUtil: 20, MemoryUsed: 1200}},
+	}
+	assert.Equal(t, want, processesGPUUsages)
+}
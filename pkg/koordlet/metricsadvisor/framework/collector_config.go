@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// CollectorConfig is the runtime config of a collector. The unset fields fall back to the defaults of the collector.
+type CollectorConfig struct {
+	Enabled  *bool            `json:"enabled,omitempty"`
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// CollectorConfigs holds the configs of the collectors by their names, which can be reloaded at runtime.
+// A nil CollectorConfigs keeps all collectors running with their default intervals.
+type CollectorConfigs struct {
+	lock    sync.RWMutex
+	configs map[string]CollectorConfig
+}
+
+func NewCollectorConfigs() *CollectorConfigs {
+	return &CollectorConfigs{
+		configs: map[string]CollectorConfig{},
+	}
+}
+
+// ParseCollectorConfigs parses the collector configs formatted in yaml or json, e.g.
+//
+//	NodeInfoCollector:
+//	  interval: 120s
+//	ColdPageCollector:
+//	  enabled: false
+func ParseCollectorConfigs(data []byte) (map[string]CollectorConfig, error) {
+	configs := map[string]CollectorConfig{}
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal collector configs, err: %w", err)
+	}
+	for name, config := range configs {
+		if config.Interval != nil && config.Interval.Duration <= 0 {
+			return nil, fmt.Errorf("invalid interval %v of collector %s", config.Interval.Duration, name)
+		}
+	}
+	return configs, nil
+}
+
+// Set replaces all the collector configs.
+func (c *CollectorConfigs) Set(configs map[string]CollectorConfig) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.configs = configs
+}
+
+func (c *CollectorConfigs) Get(name string) CollectorConfig {
+	if c == nil {
+		return CollectorConfig{}
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.configs[name]
+}
+
+// IsEnabled returns whether the collector is enabled. The collectors are enabled by default.
+func (c *CollectorConfigs) IsEnabled(name string) bool {
+	config := c.Get(name)
+	return config.Enabled == nil || *config.Enabled
+}
+
+// GetInterval returns the collecting interval of the collector.
+func (c *CollectorConfigs) GetInterval(name string, defaultInterval time.Duration) time.Duration {
+	config := c.Get(name)
+	if config.Interval == nil || config.Interval.Duration <= 0 {
+		return defaultInterval
+	}
+	return config.Interval.Duration
+}
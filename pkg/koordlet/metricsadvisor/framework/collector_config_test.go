@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestParseCollectorConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]CollectorConfig
+		wantErr bool
+	}{
+		{
+			name: "parse yaml",
+			data: `
+NodeInfoCollector:
+  interval: 120s
+ColdPageCollector:
+  enabled: false
+`,
+			want: map[string]CollectorConfig{
+				"NodeInfoCollector": {Interval: &metav1.Duration{Duration: 120 * time.Second}},
+				"ColdPageCollector": {Enabled: pointer.Bool(false)},
+			},
+		},
+		{
+			name: "parse json",
+			data: `{"PSICollector":{"enabled":true,"interval":"5s"}}`,
+			want: map[string]CollectorConfig{
+				"PSICollector": {Enabled: pointer.Bool(true), Interval: &metav1.Duration{Duration: 5 * time.Second}},
+			},
+		},
+		{
+			name:    "invalid interval",
+			data:    `{"PSICollector":{"interval":"-5s"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			data:    `[PSICollector]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCollectorConfigs([]byte(tt.data))
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCollectorConfigs(t *testing.T) {
+	var nilConfigs *CollectorConfigs
+	assert.True(t, nilConfigs.IsEnabled("NodeInfoCollector"))
+	assert.Equal(t, time.Second, nilConfigs.GetInterval("NodeInfoCollector", time.Second))
+
+	configs := NewCollectorConfigs()
+	assert.True(t, configs.IsEnabled("NodeInfoCollector"))
+	configs.Set(map[string]CollectorConfig{
+		"NodeInfoCollector": {Enabled: pointer.Bool(false), Interval: &metav1.Duration{Duration: time.Minute}},
+		"PSICollector":      {Enabled: pointer.Bool(true)},
+	})
+	assert.False(t, configs.IsEnabled("NodeInfoCollector"))
+	assert.Equal(t, time.Minute, configs.GetInterval("NodeInfoCollector", time.Second))
+	assert.True(t, configs.IsEnabled("PSICollector"))
+	assert.Equal(t, 10*time.Second, configs.GetInterval("PSICollector", 10*time.Second))
+}
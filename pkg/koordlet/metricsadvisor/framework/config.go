@@ -35,6 +35,10 @@ type Config struct {
 	PSICollectorInterval             time.Duration
 	CPICollectorTimeWindow           time.Duration
 	ColdPageCollectorInterval        time.Duration
+	// CollectorConfigFile is the path of the per-collector configs, which is reloaded when it changes.
+	CollectorConfigFile string
+	// StaticTopologyFile is the path of the static topology spec, which takes precedence over the auto-detection.
+	StaticTopologyFile string
 }
 
 func NewDefaultConfig() *Config {
@@ -59,4 +63,6 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.PSICollectorInterval, "psi-collector-interval", c.PSICollectorInterval, "Collect psi interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.DurationVar(&c.CPICollectorTimeWindow, "collect-cpi-timewindow", c.CPICollectorTimeWindow, "Collect cpi time window. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.DurationVar(&c.ColdPageCollectorInterval, "coldpage-collector-interval", c.PSICollectorInterval, "Collect cold page interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.StringVar(&c.CollectorConfigFile, "collector-config-file", c.CollectorConfigFile, "The path of the yaml file to enable or disable each collector and override its interval by the collector name, e.g. 'NodeInfoCollector: {interval: 120s}'. The file is reloaded at runtime when it changes.")
+	fs.StringVar(&c.StaticTopologyFile, "static-topology-file", c.StaticTopologyFile, "The path of the yaml or json file which specifies the cpu topology and the NUMA memory of the node, e.g. mounted from a ConfigMap or hostPath. It takes precedence over the auto-detection by lscpu and sysfs, which is useful for the nodes whose topology cannot be detected.")
 }
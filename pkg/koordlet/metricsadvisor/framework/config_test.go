@@ -50,6 +50,8 @@ func Test_InitFlags(t *testing.T) {
 		"--psi-collector-interval=5s",
 		"--collect-cpi-timewindow=15s",
 		"--coldpage-collector-interval=15s",
+		"--collector-config-file=/etc/koordlet/collectors.yaml",
+		"--static-topology-file=/etc/koordlet/topology.yaml",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -62,6 +64,8 @@ func Test_InitFlags(t *testing.T) {
 		PSICollectorInterval             time.Duration
 		CPICollectorTimeWindow           time.Duration
 		ColdPageCollectorInterval        time.Duration
+		CollectorConfigFile              string
+		StaticTopologyFile               string
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -82,6 +86,8 @@ func Test_InitFlags(t *testing.T) {
 				PSICollectorInterval:             5 * time.Second,
 				CPICollectorTimeWindow:           15 * time.Second,
 				ColdPageCollectorInterval:        15 * time.Second,
+				CollectorConfigFile:              "/etc/koordlet/collectors.yaml",
+				StaticTopologyFile:               "/etc/koordlet/topology.yaml",
 			},
 			args: args{fs: fs},
 		},
@@ -97,6 +103,8 @@ func Test_InitFlags(t *testing.T) {
 				PSICollectorInterval:             tt.fields.PSICollectorInterval,
 				CPICollectorTimeWindow:           tt.fields.CPICollectorTimeWindow,
 				ColdPageCollectorInterval:        tt.fields.ColdPageCollectorInterval,
+				CollectorConfigFile:              tt.fields.CollectorConfigFile,
+				StaticTopologyFile:               tt.fields.StaticTopologyFile,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -130,3 +130,18 @@ func TestSharedState_UpdatePodUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestSharedState_UpdateHostAppUsage(t *testing.T) {
+	now := time.Now()
+	r := NewSharedState()
+	gotCPU, gotMemory := r.GetHostAppUsage()
+	assert.Nil(t, gotCPU)
+	assert.Nil(t, gotMemory)
+
+	cpu := metriccache.Point{Timestamp: now, Value: 2}
+	memory := metriccache.Point{Timestamp: now, Value: 2048}
+	r.UpdateHostAppUsage(cpu, memory)
+	gotCPU, gotMemory = r.GetHostAppUsage()
+	assert.Equal(t, cpu, *gotCPU)
+	assert.Equal(t, memory, *gotMemory)
+}
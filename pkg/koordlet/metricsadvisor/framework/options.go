@@ -28,4 +28,6 @@ type Options struct {
 	MetricCache    metriccache.MetricCache
 	CgroupReader   resourceexecutor.CgroupReader
 	PodFilters     map[string]PodFilter
+	// CollectorConfigs are the runtime configs of the collectors driven by RunCollector.
+	CollectorConfigs *CollectorConfigs
 }
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+// RunCollector runs the collect function of the named collector periodically until the stopCh is closed.
+// The enablement and the interval of the collector are looked up in the configs before every round, so that
+// the reloaded configs take effect without restarting the collector. The health of each round is recorded
+// in the metrics.
+func RunCollector(name string, configs *CollectorConfigs, defaultInterval time.Duration, collect func() error, stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		enabled := configs.IsEnabled(name)
+		metrics.RecordMetricsCollectorEnabled(name, enabled)
+		if enabled {
+			err := collect()
+			if err != nil {
+				klog.V(4).Infof("collector %s failed to collect, err: %v", name, err)
+			}
+			metrics.RecordMetricsCollectorRun(name, err)
+		}
+
+		timer := time.NewTimer(configs.GetInterval(name, defaultInterval))
+		select {
+		case <-stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	"k8s.io/utils/pointer"
+)
+
+func TestRunCollector(t *testing.T) {
+	configs := NewCollectorConfigs()
+	rounds := atomic.NewInt32(0)
+	collect := func() error {
+		if rounds.Inc()%2 == 0 {
+			return fmt.Errorf("expected error")
+		}
+		return nil
+	}
+
+	stopCh := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		RunCollector("TestCollector", configs, 10*time.Millisecond, collect, stopCh)
+		close(stopped)
+	}()
+	assert.Eventually(t, func() bool {
+		return rounds.Load() >= 3
+	}, time.Second, 5*time.Millisecond)
+
+	// the collector disabled at runtime stops collecting
+	configs.Set(map[string]CollectorConfig{
+		"TestCollector": {Enabled: pointer.Bool(false)},
+	})
+	time.Sleep(30 * time.Millisecond)
+	disabledRounds := rounds.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, disabledRounds, rounds.Load())
+
+	// the collector enabled again resumes collecting
+	configs.Set(nil)
+	assert.Eventually(t, func() bool {
+		return rounds.Load() > disabledRounds
+	}, time.Second, 5*time.Millisecond)
+
+	close(stopCh)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("collector is not stopped")
+	}
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package metricsadvisor
 
 import (
+	"bytes"
+	"os"
 	"time"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
@@ -28,6 +31,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 )
 
+// collectorConfigReloadInterval is the interval to check whether the collector config file changes.
+const collectorConfigReloadInterval = 30 * time.Second
+
 type MetricAdvisor interface {
 	Run(stopCh <-chan struct{}) error
 	HasSynced() bool
@@ -36,15 +42,18 @@ type MetricAdvisor interface {
 type metricAdvisor struct {
 	options *framework.Options
 	context *framework.Context
+	// lastCollectorConfigData is the content of the collector config file last loaded.
+	lastCollectorConfigData []byte
 }
 
 func NewMetricAdvisor(cfg *framework.Config, statesInformer statesinformer.StatesInformer, metricCache metriccache.MetricCache) MetricAdvisor {
 	opt := &framework.Options{
-		Config:         cfg,
-		StatesInformer: statesInformer,
-		MetricCache:    metricCache,
-		CgroupReader:   resourceexecutor.NewCgroupReader(),
-		PodFilters:     podFilters,
+		Config:           cfg,
+		StatesInformer:   statesInformer,
+		MetricCache:      metricCache,
+		CgroupReader:     resourceexecutor.NewCgroupReader(),
+		PodFilters:       podFilters,
+		CollectorConfigs: framework.NewCollectorConfigs(),
 	}
 	ctx := &framework.Context{
 		DeviceCollectors: make(map[string]framework.DeviceCollector, len(devicePlugins)),
@@ -66,7 +75,14 @@ func NewMetricAdvisor(cfg *framework.Config, statesInformer statesinformer.State
 }
 
 func (m *metricAdvisor) HasSynced() bool {
-	return framework.CollectorsHasStarted(m.context.Collectors)
+	// the collectors disabled at runtime do not block the syncing
+	collectors := make(map[string]framework.Collector, len(m.context.Collectors))
+	for name, collector := range m.context.Collectors {
+		if m.options.CollectorConfigs.IsEnabled(name) {
+			collectors[name] = collector
+		}
+	}
+	return framework.CollectorsHasStarted(collectors)
 }
 
 func (m *metricAdvisor) Run(stopCh <-chan struct{}) error {
@@ -80,6 +96,10 @@ func (m *metricAdvisor) Run(stopCh <-chan struct{}) error {
 	defer m.shutdown()
 	m.setup()
 
+	if len(m.options.Config.CollectorConfigFile) > 0 {
+		go wait.Until(m.reloadCollectorConfigs, collectorConfigReloadInterval, stopCh)
+	}
+
 	defer klog.Info("shutting down metric advisor")
 	klog.Info("Starting collector for NodeMetric")
 
@@ -109,6 +129,9 @@ func (m *metricAdvisor) Run(stopCh <-chan struct{}) error {
 }
 
 func (m *metricAdvisor) setup() {
+	if len(m.options.Config.CollectorConfigFile) > 0 {
+		m.reloadCollectorConfigs()
+	}
 	for _, device := range m.context.DeviceCollectors {
 		device.Setup(m.context)
 	}
@@ -122,3 +145,24 @@ func (m *metricAdvisor) shutdown() {
 		dc.Shutdown()
 	}
 }
+
+// reloadCollectorConfigs loads the collector configs from the config file if it changes.
+// The previous configs are kept if the file is invalid.
+func (m *metricAdvisor) reloadCollectorConfigs() {
+	data, err := os.ReadFile(m.options.Config.CollectorConfigFile)
+	if err != nil {
+		klog.Warningf("failed to read collector config file %s, err: %v", m.options.Config.CollectorConfigFile, err)
+		return
+	}
+	if m.lastCollectorConfigData != nil && bytes.Equal(data, m.lastCollectorConfigData) {
+		return
+	}
+	configs, err := framework.ParseCollectorConfigs(data)
+	if err != nil {
+		klog.Warningf("failed to parse collector config file %s, err: %v", m.options.Config.CollectorConfigFile, err)
+		return
+	}
+	m.options.CollectorConfigs.Set(configs)
+	m.lastCollectorConfigData = data
+	klog.V(4).Infof("collector configs reloaded from %s, %d collectors configured", m.options.Config.CollectorConfigFile, len(configs))
+}
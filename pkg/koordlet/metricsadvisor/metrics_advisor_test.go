@@ -17,6 +17,8 @@ limitations under the License.
 package metricsadvisor
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -240,3 +242,29 @@ unevictable 0
 		})
 	}
 }
+
+func Test_metricAdvisor_reloadCollectorConfigs(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "collectors.yaml")
+	cfg := framework.NewDefaultConfig()
+	cfg.CollectorConfigFile = configFile
+	m := NewMetricAdvisor(cfg, nil, nil).(*metricAdvisor)
+
+	// the file is not found
+	m.reloadCollectorConfigs()
+	assert.True(t, m.options.CollectorConfigs.IsEnabled("NodeInfoCollector"))
+
+	assert.NoError(t, os.WriteFile(configFile, []byte("NodeInfoCollector: {enabled: false, interval: 2m}"), 0644))
+	m.reloadCollectorConfigs()
+	assert.False(t, m.options.CollectorConfigs.IsEnabled("NodeInfoCollector"))
+	assert.Equal(t, 2*time.Minute, m.options.CollectorConfigs.GetInterval("NodeInfoCollector", time.Minute))
+
+	// the invalid configs are ignored
+	assert.NoError(t, os.WriteFile(configFile, []byte("NodeInfoCollector: {interval: -1s}"), 0644))
+	m.reloadCollectorConfigs()
+	assert.False(t, m.options.CollectorConfigs.IsEnabled("NodeInfoCollector"))
+
+	assert.NoError(t, os.WriteFile(configFile, []byte("ColdPageCollector: {enabled: false}"), 0644))
+	m.reloadCollectorConfigs()
+	assert.True(t, m.options.CollectorConfigs.IsEnabled("NodeInfoCollector"))
+	assert.False(t, m.options.CollectorConfigs.IsEnabled("ColdPageCollector"))
+}
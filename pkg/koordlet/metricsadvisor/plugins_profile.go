@@ -19,12 +19,16 @@ package metricsadvisor
 import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/beresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/coldmemoryresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/cpusteal"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/hostapplication"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/nodehealth"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/nodeinfo"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/noderesource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/nodestorageinfo"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/performance"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podthrottled"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/schedstat"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/sysresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
@@ -47,10 +51,15 @@ var (
 		performance.CollectorName:        performance.New,
 		sysresource.CollectorName:        sysresource.New,
 		coldmemoryresource.CollectorName: coldmemoryresource.New,
+		nodehealth.CollectorName:         nodehealth.New,
+		schedstat.CollectorName:          schedstat.New,
+		hostapplication.CollectorName:    hostapplication.New,
+		cpusteal.CollectorName:           cpusteal.New,
 	}
 
 	podFilters = map[string]framework.PodFilter{
 		podresource.CollectorName:  framework.DefaultPodFilter,
 		podthrottled.CollectorName: framework.DefaultPodFilter,
+		schedstat.CollectorName:    framework.DefaultPodFilter,
 	}
 )
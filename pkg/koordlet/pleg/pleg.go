@@ -18,6 +18,7 @@ package pleg
 
 import (
 	"errors"
+	"os"
 	"path/filepath"
 	"sync"
 
@@ -157,6 +158,7 @@ func (p *pleg) Run(stopCh <-chan struct{}) error {
 				klog.Errorf("failed to remove watch path %v, err %v", cgroupPath, err1)
 			}
 		}()
+		p.watchExistingPods(cgroupPath)
 	}
 
 	go p.runEventHandler(stopCh)
@@ -231,6 +233,30 @@ func (p *pleg) Run(stopCh <-chan struct{}) error {
 	}
 }
 
+// watchExistingPods registers the container watchers for the pods created before the pleg runs, so that the
+// container restarts of these pods are also observed.
+func (p *pleg) watchExistingPods(qosCgroupPath string) {
+	entries, err := os.ReadDir(qosCgroupPath)
+	if err != nil {
+		klog.V(4).Infof("failed to list existing pods in path %v, err %v", qosCgroupPath, err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err = koordletutil.ParsePodID(entry.Name()); err != nil {
+			continue
+		}
+		podPath := filepath.Join(qosCgroupPath, entry.Name())
+		if err = p.containerWatcher.AddWatch(podPath); err != nil {
+			klog.V(4).Infof("failed to watch containers of existing pod path %v, err %v", podPath, err)
+			continue
+		}
+		klog.V(5).Infof("add container watch path %v of existing pod in pleg", podPath)
+	}
+}
+
 func (p *pleg) runEventHandler(stopCh <-chan struct{}) {
 	for {
 		select {
@@ -17,6 +17,8 @@ limitations under the License.
 package pleg
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -35,6 +37,7 @@ func NewTestWatcher() (Watcher, error) {
 
 type testWatcher struct {
 	events chan *inotify.Event
+	paths  []string
 }
 
 func (w *testWatcher) Close() error {
@@ -42,6 +45,7 @@ func (w *testWatcher) Close() error {
 }
 
 func (w *testWatcher) AddWatch(path string) error {
+	w.paths = append(w.paths, path)
 	return nil
 }
 
@@ -215,6 +219,21 @@ func TestPlegHandleContainerEvents(t *testing.T) {
 	}
 }
 
+func TestPlegWatchExistingPods(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	qosCgroupPath := filepath.Join(helper.TempDir, "kubepods-burstable.slice")
+	assert.NoError(t, os.MkdirAll(filepath.Join(qosCgroupPath, "kubepods-burstable-pod12345.slice"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(qosCgroupPath, "not-a-pod"), 0755))
+
+	pg, err := NewPLEG(helper.TempDir)
+	assert.NoError(t, err)
+	containerWatcher, _ := NewTestWatcher()
+	pg.(*pleg).containerWatcher = containerWatcher
+	pg.(*pleg).watchExistingPods(qosCgroupPath)
+	assert.Equal(t, []string{filepath.Join(qosCgroupPath, "kubepods-burstable-pod12345.slice")}, containerWatcher.(*testWatcher).paths)
+}
+
 func Test_getWatchCgroupPath(t *testing.T) {
 	type args struct {
 		cgroupRootDir string
@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/pleg"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 )
 
@@ -63,6 +64,10 @@ func (m *mockStatesInformer) GetVolumeName(pvcNamespace, pvcName string) string
 func (m *mockStatesInformer) RegisterCallbacks(objType statesinformer.RegisterType, name, description string, callbackFn statesinformer.UpdateCbFn) {
 }
 
+func (m *mockStatesInformer) RegisterPodLifeCycleHandler(handler pleg.PodLifeCycleHandler) pleg.HandlerID {
+	return 0
+}
+
 func TestInformer(t *testing.T) {
 	pod1 := &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "pod1"}}
 	pod2 := &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "pod2"}}
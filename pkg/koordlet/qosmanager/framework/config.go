@@ -27,6 +27,10 @@ type Config struct {
 	MemoryEvictIntervalSeconds int
 	MemoryEvictCoolTimeSeconds int
 	CPUEvictCoolTimeSeconds    int
+	GPUEvictIntervalSeconds    int
+	GPUEvictCoolTimeSeconds    int
+	GPUEvictTolerancePercent   int
+	EvictionDryRun             bool
 	QOSExtensionCfg            *QOSExtensionConfig
 }
 
@@ -38,6 +42,9 @@ func NewDefaultConfig() *Config {
 		MemoryEvictIntervalSeconds: 1,
 		MemoryEvictCoolTimeSeconds: 4,
 		CPUEvictCoolTimeSeconds:    20,
+		GPUEvictIntervalSeconds:    1,
+		GPUEvictCoolTimeSeconds:    20,
+		GPUEvictTolerancePercent:   10,
 		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
 }
@@ -49,5 +56,9 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.MemoryEvictIntervalSeconds, "memory-evict-interval-seconds", c.MemoryEvictIntervalSeconds, "evict be pod(memory) interval by seconds")
 	fs.IntVar(&c.MemoryEvictCoolTimeSeconds, "memory-evict-cool-time-seconds", c.MemoryEvictCoolTimeSeconds, "cooling time: memory next evict time should after lastEvictTime + MemoryEvictCoolTimeSeconds")
 	fs.IntVar(&c.CPUEvictCoolTimeSeconds, "cpu-evict-cool-time-seconds", c.CPUEvictCoolTimeSeconds, "cooltime: CPU next evict time should after lastEvictTime + CPUEvictCoolTimeSeconds")
+	fs.IntVar(&c.GPUEvictIntervalSeconds, "gpu-evict-interval-seconds", c.GPUEvictIntervalSeconds, "evict the pods using gpu memory over quota interval by seconds")
+	fs.IntVar(&c.GPUEvictCoolTimeSeconds, "gpu-evict-cool-time-seconds", c.GPUEvictCoolTimeSeconds, "cooling time: gpu next evict time should after lastEvictTime + GPUEvictCoolTimeSeconds")
+	fs.IntVar(&c.GPUEvictTolerancePercent, "gpu-evict-tolerance-percent", c.GPUEvictTolerancePercent, "the percent of the allocated gpu memory a pod can use beyond its allocation before it is evicted")
+	fs.BoolVar(&c.EvictionDryRun, "eviction-dry-run", c.EvictionDryRun, "only pick and report the victims of the eviction without killing or evicting them")
 	c.QOSExtensionCfg.InitFlags(fs)
 }
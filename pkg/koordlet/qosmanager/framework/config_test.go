@@ -31,6 +31,9 @@ func Test_NewDefaultConfig(t *testing.T) {
 		MemoryEvictIntervalSeconds: 1,
 		MemoryEvictCoolTimeSeconds: 4,
 		CPUEvictCoolTimeSeconds:    20,
+		GPUEvictIntervalSeconds:    1,
+		GPUEvictCoolTimeSeconds:    20,
+		GPUEvictTolerancePercent:   10,
 		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
 	defaultConfig := NewDefaultConfig()
@@ -46,6 +49,9 @@ func Test_InitFlags(t *testing.T) {
 		"--memory-evict-interval-seconds=2",
 		"--memory-evict-cool-time-seconds=8",
 		"--cpu-evict-cool-time-seconds=40",
+		"--gpu-evict-interval-seconds=2",
+		"--gpu-evict-cool-time-seconds=40",
+		"--gpu-evict-tolerance-percent=20",
 		"--qos-extension-plugins=test-plugin=true",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
@@ -57,6 +63,9 @@ func Test_InitFlags(t *testing.T) {
 		MemoryEvictIntervalSeconds int
 		MemoryEvictCoolTimeSeconds int
 		CPUEvictCoolTimeSeconds    int
+		GPUEvictIntervalSeconds    int
+		GPUEvictCoolTimeSeconds    int
+		GPUEvictTolerancePercent   int
 		QOSExtensionCfg            *QOSExtensionConfig
 	}
 	type args struct {
@@ -76,6 +85,9 @@ func Test_InitFlags(t *testing.T) {
 				MemoryEvictIntervalSeconds: 2,
 				MemoryEvictCoolTimeSeconds: 8,
 				CPUEvictCoolTimeSeconds:    40,
+				GPUEvictIntervalSeconds:    2,
+				GPUEvictCoolTimeSeconds:    40,
+				GPUEvictTolerancePercent:   20,
 				QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
 			},
 			args: args{fs: fs},
@@ -90,6 +102,9 @@ func Test_InitFlags(t *testing.T) {
 				MemoryEvictIntervalSeconds: tt.fields.MemoryEvictIntervalSeconds,
 				MemoryEvictCoolTimeSeconds: tt.fields.MemoryEvictCoolTimeSeconds,
 				CPUEvictCoolTimeSeconds:    tt.fields.CPUEvictCoolTimeSeconds,
+				GPUEvictIntervalSeconds:    tt.fields.GPUEvictIntervalSeconds,
+				GPUEvictCoolTimeSeconds:    tt.fields.GPUEvictCoolTimeSeconds,
+				GPUEvictTolerancePercent:   tt.fields.GPUEvictTolerancePercent,
 				QOSExtensionCfg:            tt.fields.QOSExtensionCfg,
 			}
 			c := NewDefaultConfig()
@@ -35,8 +35,9 @@ import (
 )
 
 type Context struct {
-	Evictor    *Evictor
-	Strategies map[string]QOSStrategy
+	Evictor         *Evictor
+	EvictionManager *EvictionManager
+	Strategies      map[string]QOSStrategy
 }
 
 type Evictor struct {
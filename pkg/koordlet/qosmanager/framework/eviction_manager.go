@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
+)
+
+// EvictionCandidate is a pod which can be evicted to release the resource.
+type EvictionCandidate struct {
+	Pod *corev1.Pod
+	// Usage is the resource usage of the pod, in the unit of the released resource, e.g. bytes or milli-cores.
+	Usage int64
+	// Request is the resource request of the pod in the same unit. Zero means the pod does not request it.
+	Request int64
+	// Release is the amount of the resource expected to be released by evicting the pod.
+	Release int64
+	// NUMANodes are the NUMA nodes allocated to the pod.
+	NUMANodes []int
+	// NUMAUsage is the resource usage of the pod on each NUMA node in the same unit as Usage, e.g. the memory residing
+	// on the NUMA nodes. It takes precedence over the NUMANodes to rank the pods when some NUMA nodes are under pressure.
+	NUMAUsage map[int]int64
+}
+
+// NewEvictionCandidate creates an EvictionCandidate and fills the NUMA nodes allocated to the pod.
+func NewEvictionCandidate(pod *corev1.Pod, usage, request, release int64) *EvictionCandidate {
+	candidate := &EvictionCandidate{
+		Pod:     pod,
+		Usage:   usage,
+		Request: request,
+		Release: release,
+	}
+	resourceStatus, err := annotation.LenientParser.ParseResourceStatus(pod.Annotations)
+	if err != nil {
+		klog.V(5).Infof("failed to parse resource status of pod %s, err: %s", util.GetPodKey(pod), err)
+		return candidate
+	}
+	for _, numaNodeResource := range resourceStatus.NUMANodeResources {
+		candidate.NUMANodes = append(candidate.NUMANodes, int(numaNodeResource.Node))
+	}
+	return candidate
+}
+
+// EvictionRequest is a request of a QoS strategy to release some resource by evicting the pods.
+type EvictionRequest struct {
+	// Reason is the reason of the eviction, e.g. EvictPodByNodeMemoryUsage.
+	Reason  string
+	Message string
+	// ResourceName is the resource to release.
+	ResourceName corev1.ResourceName
+	// ToRelease is the amount of the resource to release.
+	ToRelease int64
+	// NeededNUMANodes are the NUMA nodes under pressure. The pods allocated on them are preferred to evict.
+	// Empty means any NUMA node.
+	NeededNUMANodes []int
+	Candidates      []*EvictionCandidate
+	// Exemptions are the pods never evicted, e.g. the critical BE jobs.
+	Exemptions helpers.PodExemptions
+}
+
+// EvictionManager picks the victims for the eviction requests of the QoS strategies in a consistent order, and
+// kills and evicts them unless in the dry-run mode.
+type EvictionManager struct {
+	evictor *Evictor
+	dryRun  bool
+}
+
+func NewEvictionManager(evictor *Evictor, dryRun bool) *EvictionManager {
+	return &EvictionManager{
+		evictor: evictor,
+		dryRun:  dryRun,
+	}
+}
+
+func (m *EvictionManager) DryRun() bool {
+	return m.dryRun
+}
+
+// Evict ranks the candidates and picks the victims until the requested resource is released. It returns the victims
+// and the amount expected to be released.
+func (m *EvictionManager) Evict(node *corev1.Node, req *EvictionRequest) ([]*corev1.Pod, int64) {
+	RankEvictionCandidates(req.Candidates, req.NeededNUMANodes)
+
+	var victims []*corev1.Pod
+	released := int64(0)
+	for _, candidate := range req.Candidates {
+		if released >= req.ToRelease {
+			break
+		}
+		if req.Exemptions.IsExempted(candidate.Pod) {
+			klog.V(4).Infof("%s, skip evicting the exempted pod %s", req.Message, util.GetPodKey(candidate.Pod))
+			_ = audit.V(2).Pod(candidate.Pod.Namespace, candidate.Pod.Name).Reason(req.Reason).Message("exempted from eviction").Do()
+			continue
+		}
+		victims = append(victims, candidate.Pod)
+		released += candidate.Release
+	}
+	if len(victims) <= 0 {
+		return nil, 0
+	}
+
+	metrics.RecordEvictionManagerVictims(req.Reason, m.dryRun, len(victims))
+	metrics.RecordEvictionManagerReleasedResource(req.Reason, string(req.ResourceName), m.dryRun, float64(released))
+	if m.dryRun {
+		for _, pod := range victims {
+			klog.Infof("%s, dry-run evict pod %s, reason %s", req.Message, util.GetPodKey(pod), req.Reason)
+		}
+		return victims, released
+	}
+
+	for _, pod := range victims {
+		helpers.KillContainers(pod, fmt.Sprintf("%s, kill pod: %s", req.Message, util.GetPodKey(pod)))
+	}
+	m.evictor.EvictPodsIfNotEvicted(victims, node, req.Reason, req.Message)
+	return victims, released
+}
+
+// qosEvictionRank is the order of evicting the pods of the QoS classes. The pods of the lower rank are evicted first.
+var qosEvictionRank = map[apiext.QoSClass]int{
+	apiext.QoSBE:     0,
+	apiext.QoSNone:   1,
+	apiext.QoSLS:     2,
+	apiext.QoSLSR:    3,
+	apiext.QoSLSE:    4,
+	apiext.QoSSystem: 5,
+}
+
+// RankEvictionCandidates sorts the candidates in the order of eviction:
+// 1. the pod of the lower priority;
+// 2. the pod of the lower QoS class, e.g. BE < LS < LSR;
+// 3. the pod allocated on the needed NUMA nodes, or whose usage predominantly resides on the needed NUMA nodes;
+// 4. the pod of the higher usage on the needed NUMA nodes;
+// 5. the pod of the higher usage over request;
+// 6. the pod of the higher usage;
+// 7. the pod of the larger name.
+func RankEvictionCandidates(candidates []*EvictionCandidate, neededNUMANodes []int) {
+	needed := map[int]bool{}
+	for _, numaNode := range neededNUMANodes {
+		needed[numaNode] = true
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Pod.Spec.Priority != nil && b.Pod.Spec.Priority != nil && *a.Pod.Spec.Priority != *b.Pod.Spec.Priority {
+			return *a.Pod.Spec.Priority < *b.Pod.Spec.Priority
+		}
+		if qosA, qosB := qosEvictionRank[apiext.GetPodQoSClassRaw(a.Pod)], qosEvictionRank[apiext.GetPodQoSClassRaw(b.Pod)]; qosA != qosB {
+			return qosA < qosB
+		}
+		if len(needed) > 0 {
+			if freeA, freeB := isOnNUMANodes(a, needed), isOnNUMANodes(b, needed); freeA != freeB {
+				return freeA
+			}
+			if usageA, usageB := usageOnNUMANodes(a, needed), usageOnNUMANodes(b, needed); usageA != usageB {
+				return usageA > usageB
+			}
+		}
+		if ratioA, ratioB := usageOverRequest(a), usageOverRequest(b); ratioA != ratioB {
+			return ratioA > ratioB
+		}
+		if a.Usage != b.Usage {
+			return a.Usage > b.Usage
+		}
+		return a.Pod.Name > b.Pod.Name
+	})
+}
+
+// isOnNUMANodes returns whether more than half of the NUMA usage of the pod resides on the NUMA nodes if the usage is
+// known, otherwise whether the pod is allocated on the NUMA nodes.
+func isOnNUMANodes(candidate *EvictionCandidate, numaNodes map[int]bool) bool {
+	if len(candidate.NUMAUsage) > 0 {
+		total := int64(0)
+		for _, usage := range candidate.NUMAUsage {
+			total += usage
+		}
+		return total > 0 && usageOnNUMANodes(candidate, numaNodes)*2 > total
+	}
+	for _, numaNode := range candidate.NUMANodes {
+		if numaNodes[numaNode] {
+			return true
+		}
+	}
+	return false
+}
+
+// usageOnNUMANodes returns the usage of the pod on the NUMA nodes, which is zero if the NUMA usage is unknown.
+func usageOnNUMANodes(candidate *EvictionCandidate, numaNodes map[int]bool) int64 {
+	usage := int64(0)
+	for numaNode, v := range candidate.NUMAUsage {
+		if numaNodes[numaNode] {
+			usage += v
+		}
+	}
+	return usage
+}
+
+func usageOverRequest(candidate *EvictionCandidate) float64 {
+	if candidate.Request > 0 {
+		return float64(candidate.Usage) / float64(candidate.Request)
+	}
+	// the pod uses the resource without requesting it
+	if candidate.Usage > 0 {
+		return math.Inf(1)
+	}
+	return 0
+}
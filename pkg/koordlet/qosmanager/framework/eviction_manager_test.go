@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func mockCandidatePod(name string, qosClass apiext.QoSClass, priority int32, numaNodes ...int32) *corev1.Pod {
+	pod := testutil.MockTestPod(qosClass, name)
+	pod.Namespace = "default"
+	pod.Spec.Priority = pointer.Int32(priority)
+	if len(numaNodes) > 0 {
+		status := &apiext.ResourceStatus{}
+		for _, numaNode := range numaNodes {
+			status.NUMANodeResources = append(status.NUMANodeResources, apiext.NUMANodeResource{Node: numaNode})
+		}
+		_ = apiext.SetResourceStatus(pod, status)
+	}
+	return pod
+}
+
+func candidateNames(candidates []*EvictionCandidate) []string {
+	var names []string
+	for _, candidate := range candidates {
+		names = append(names, candidate.Pod.Name)
+	}
+	return names
+}
+
+func TestNewEvictionCandidate(t *testing.T) {
+	candidate := NewEvictionCandidate(mockCandidatePod("pod", apiext.QoSBE, 0, 0, 1), 1, 2, 3)
+	assert.Equal(t, []int{0, 1}, candidate.NUMANodes)
+	assert.Equal(t, int64(1), candidate.Usage)
+	assert.Equal(t, int64(2), candidate.Request)
+	assert.Equal(t, int64(3), candidate.Release)
+
+	candidate = NewEvictionCandidate(mockCandidatePod("pod", apiext.QoSBE, 0), 1, 2, 3)
+	assert.Nil(t, candidate.NUMANodes)
+}
+
+func TestRankEvictionCandidates(t *testing.T) {
+	tests := []struct {
+		name            string
+		candidates      []*EvictionCandidate
+		neededNUMANodes []int
+		want            []string
+	}{
+		{
+			name: "lower priority first",
+			candidates: []*EvictionCandidate{
+				NewEvictionCandidate(mockCandidatePod("pod-a", apiext.QoSBE, 100), 10, 10, 10),
+				NewEvictionCandidate(mockCandidatePod("pod-b", apiext.QoSBE, 10), 1, 10, 1),
+			},
+			want: []string{"pod-b", "pod-a"},
+		},
+		{
+			name: "lower qos first",
+			candidates: []*EvictionCandidate{
+				NewEvictionCandidate(mockCandidatePod("pod-ls", apiext.QoSLS, 100), 10, 10, 10),
+				NewEvictionCandidate(mockCandidatePod("pod-none", apiext.QoSNone, 100), 10, 10, 10),
+				NewEvictionCandidate(mockCandidatePod("pod-be", apiext.QoSBE, 100), 1, 10, 1),
+			},
+			want: []string{"pod-be", "pod-none", "pod-ls"},
+		},
+		{
+			name: "pod on the needed numa node first",
+			candidates: []*EvictionCandidate{
+				NewEvictionCandidate(mockCandidatePod("pod-numa-0", apiext.QoSBE, 100, 0), 10, 10, 10),
+				NewEvictionCandidate(mockCandidatePod("pod-numa-1", apiext.QoSBE, 100, 1), 1, 10, 1),
+			},
+			neededNUMANodes: []int{1},
+			want:            []string{"pod-numa-1", "pod-numa-0"},
+		},
+		{
+			name: "pod whose usage predominantly resides on the needed numa node first",
+			candidates: []*EvictionCandidate{
+				withNUMAUsage(NewEvictionCandidate(mockCandidatePod("pod-a", apiext.QoSBE, 100), 10, 0, 10), map[int]int64{0: 8, 1: 2}),
+				withNUMAUsage(NewEvictionCandidate(mockCandidatePod("pod-b", apiext.QoSBE, 100), 5, 0, 5), map[int]int64{0: 1, 1: 4}),
+				withNUMAUsage(NewEvictionCandidate(mockCandidatePod("pod-c", apiext.QoSBE, 100), 8, 0, 8), map[int]int64{0: 2, 1: 6}),
+			},
+			neededNUMANodes: []int{1},
+			want:            []string{"pod-c", "pod-b", "pod-a"},
+		},
+		{
+			name: "numa usage takes precedence over the allocated numa nodes",
+			candidates: []*EvictionCandidate{
+				withNUMAUsage(NewEvictionCandidate(mockCandidatePod("pod-numa-0", apiext.QoSBE, 100, 0), 10, 0, 10), map[int]int64{0: 2, 1: 8}),
+				withNUMAUsage(NewEvictionCandidate(mockCandidatePod("pod-numa-1", apiext.QoSBE, 100, 1), 10, 0, 10), map[int]int64{0: 9, 1: 1}),
+			},
+			neededNUMANodes: []int{1},
+			want:            []string{"pod-numa-0", "pod-numa-1"},
+		},
+		{
+			name: "higher usage over request first",
+			candidates: []*EvictionCandidate{
+				NewEvictionCandidate(mockCandidatePod("pod-a", apiext.QoSBE, 100), 10, 20, 10),
+				NewEvictionCandidate(mockCandidatePod("pod-b", apiext.QoSBE, 100), 8, 8, 8),
+				NewEvictionCandidate(mockCandidatePod("pod-c", apiext.QoSBE, 100), 0, 0, 0),
+				NewEvictionCandidate(mockCandidatePod("pod-d", apiext.QoSBE, 100), 1, 0, 1),
+			},
+			want: []string{"pod-d", "pod-b", "pod-a", "pod-c"},
+		},
+		{
+			name: "higher usage and then larger name first",
+			candidates: []*EvictionCandidate{
+				NewEvictionCandidate(mockCandidatePod("pod-a", apiext.QoSBE, 100), 10, 0, 10),
+				NewEvictionCandidate(mockCandidatePod("pod-b", apiext.QoSBE, 100), 20, 0, 20),
+				NewEvictionCandidate(mockCandidatePod("pod-c", apiext.QoSBE, 100), 10, 0, 10),
+			},
+			want: []string{"pod-b", "pod-c", "pod-a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RankEvictionCandidates(tt.candidates, tt.neededNUMANodes)
+			assert.Equal(t, tt.want, candidateNames(tt.candidates))
+		})
+	}
+}
+
+func TestEvictionManager_Evict(t *testing.T) {
+	tests := []struct {
+		name         string
+		dryRun       bool
+		toRelease    int64
+		exemptions   []slov1alpha1.ResourceThresholdExemption
+		wantVictims  []string
+		wantReleased int64
+	}{
+		{
+			name:         "evict until released",
+			toRelease:    15,
+			wantVictims:  []string{"pod-b", "pod-c"},
+			wantReleased: 20,
+		},
+		{
+			name:         "dry run",
+			dryRun:       true,
+			toRelease:    5,
+			wantVictims:  []string{"pod-b"},
+			wantReleased: 10,
+		},
+		{
+			name:      "nothing to release",
+			toRelease: 0,
+		},
+		{
+			name:      "skip the exempted pods",
+			toRelease: 15,
+			exemptions: []slov1alpha1.ResourceThresholdExemption{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "backup"}}},
+			},
+			wantVictims:  []string{"pod-c", "pod-a"},
+			wantReleased: 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := clientsetfake.NewSimpleClientset()
+			evictor := NewEvictor(client, &testutil.FakeRecorder{}, policyv1beta1.SchemeGroupVersion.Version)
+			stop := make(chan struct{})
+			assert.NoError(t, evictor.Start(stop))
+			defer close(stop)
+
+			pods := []*corev1.Pod{
+				mockCandidatePod("pod-a", apiext.QoSBE, 100),
+				mockCandidatePod("pod-b", apiext.QoSBE, 10),
+				mockCandidatePod("pod-c", apiext.QoSBE, 100),
+			}
+			pods[1].Labels["app"] = "backup"
+			var candidates []*EvictionCandidate
+			for _, pod := range pods {
+				_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				assert.NoError(t, err)
+				candidates = append(candidates, NewEvictionCandidate(pod, 10, 10, 10))
+			}
+
+			m := NewEvictionManager(evictor, tt.dryRun)
+			victims, released := m.Evict(testutil.MockTestNode("80", "120G"), &EvictionRequest{
+				Reason:       "test",
+				ResourceName: corev1.ResourceCPU,
+				ToRelease:    tt.toRelease,
+				Candidates:   candidates,
+				Exemptions:   helpers.NewPodExemptions(&slov1alpha1.ResourceThresholdStrategy{Exemptions: tt.exemptions}),
+			})
+			var victimNames []string
+			for _, victim := range victims {
+				victimNames = append(victimNames, victim.Name)
+			}
+			assert.Equal(t, tt.wantVictims, victimNames)
+			assert.Equal(t, tt.wantReleased, released)
+
+			for _, pod := range pods {
+				obj, err := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
+				assert.NoError(t, err)
+				_, isEviction := obj.(*policyv1beta1.Eviction)
+				evicted := !tt.dryRun && contains(tt.wantVictims, pod.Name)
+				assert.Equal(t, evicted, isEviction, "pod %s", pod.Name)
+			}
+		})
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func withNUMAUsage(candidate *EvictionCandidate, numaUsage map[int]int64) *EvictionCandidate {
+	candidate.NUMAUsage = numaUsage
+	return candidate
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// PodExemptions matches the pods exempted from the eviction and throttling of the resource threshold strategies.
+type PodExemptions []podExemption
+
+type podExemption struct {
+	namespaces sets.String
+	selector   labels.Selector
+}
+
+// NewPodExemptions parses the exemptions of the resource threshold strategy. The invalid exemptions are ignored.
+func NewPodExemptions(strategy *slov1alpha1.ResourceThresholdStrategy) PodExemptions {
+	if strategy == nil {
+		return nil
+	}
+	var result PodExemptions
+	for i := range strategy.Exemptions {
+		exemption := &strategy.Exemptions[i]
+		if len(exemption.Namespaces) == 0 && exemption.PodSelector == nil {
+			continue
+		}
+		e := podExemption{
+			namespaces: sets.NewString(exemption.Namespaces...),
+			selector:   labels.Everything(),
+		}
+		if exemption.PodSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(exemption.PodSelector)
+			if err != nil {
+				klog.Warningf("ignore the invalid pod selector of the exemption %v, err: %v", exemption, err)
+				continue
+			}
+			e.selector = selector
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// IsExempted returns true if the pod matches any of the exemptions.
+func (e PodExemptions) IsExempted(pod *corev1.Pod) bool {
+	for _, exemption := range e {
+		if exemption.namespaces.Len() > 0 && !exemption.namespaces.Has(pod.Namespace) {
+			continue
+		}
+		if exemption.selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
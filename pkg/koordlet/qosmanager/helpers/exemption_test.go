@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestPodExemptions(t *testing.T) {
+	backupPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ops",
+			Name:      "backup",
+			Labels:    map[string]string{"app": "backup"},
+		},
+	}
+	tests := []struct {
+		name     string
+		strategy *slov1alpha1.ResourceThresholdStrategy
+		want     bool
+	}{
+		{
+			name: "nil strategy",
+		},
+		{
+			name:     "no exemptions",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{},
+		},
+		{
+			name: "exempted by namespace",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				Exemptions: []slov1alpha1.ResourceThresholdExemption{
+					{Namespaces: []string{"default"}},
+					{Namespaces: []string{"ops"}},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "exempted by pod selector",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				Exemptions: []slov1alpha1.ResourceThresholdExemption{
+					{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "backup"}}},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "pod selector in other namespaces",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				Exemptions: []slov1alpha1.ResourceThresholdExemption{
+					{
+						Namespaces:  []string{"default"},
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "backup"}},
+					},
+				},
+			},
+		},
+		{
+			name: "ignore the empty exemption",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				Exemptions: []slov1alpha1.ResourceThresholdExemption{{}},
+			},
+		},
+		{
+			name: "ignore the invalid pod selector",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				Exemptions: []slov1alpha1.ResourceThresholdExemption{
+					{
+						PodSelector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "app", Operator: "Unknown"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPodExemptions(tt.strategy).IsExempted(backupPod)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
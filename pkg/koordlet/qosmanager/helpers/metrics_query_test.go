@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
@@ -132,6 +133,53 @@ func Test_collectAllPodMetricsLast(t *testing.T) {
 	}
 }
 
+func Test_collectAllHostAppMetricsLast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	timeNow = func() time.Time {
+		return now
+	}
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	hostApps := []slov1alpha1.HostApplicationSpec{
+		{
+			Name: "test-app",
+			QoS:  extension.QoSLS,
+		},
+		{
+			Name: "test-app-no-metric",
+			QoS:  extension.QoSBE,
+		},
+	}
+
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctrl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	mockQuerier := mock_metriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+
+	cpuResult := mock_metriccache.NewMockAggregateResult(ctrl)
+	cpuResult.EXPECT().Value(metriccache.AggregationTypeLast).Return(float64(2), nil).AnyTimes()
+	cpuResult.EXPECT().Count().Return(1).AnyTimes()
+	cpuQueryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("test-app"))
+	assert.NoError(t, err)
+	mockResultFactory.EXPECT().New(cpuQueryMeta).Return(cpuResult).AnyTimes()
+	mockQuerier.EXPECT().Query(cpuQueryMeta, gomock.Any(), cpuResult).SetArg(2, *cpuResult).Return(nil).AnyTimes()
+
+	emptyResult := mock_metriccache.NewMockAggregateResult(ctrl)
+	emptyResult.EXPECT().Count().Return(0).AnyTimes()
+	emptyQueryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("test-app-no-metric"))
+	assert.NoError(t, err)
+	mockResultFactory.EXPECT().New(emptyQueryMeta).Return(emptyResult).AnyTimes()
+	mockQuerier.EXPECT().Query(emptyQueryMeta, gomock.Any(), emptyResult).SetArg(2, *emptyResult).Return(nil).AnyTimes()
+
+	got := CollectAllHostAppMetricsLast(hostApps, mockMetricCache, metriccache.HostAppCPUUsageMetric, 60*time.Second)
+	assert.Equal(t, map[string]float64{"test-app": 2}, got)
+}
+
 func Test_resmanager_collectorNodeMetricLast(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
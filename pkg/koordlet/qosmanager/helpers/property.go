@@ -24,12 +24,19 @@ import (
 )
 
 func GetPodResourceQoSByQoSClass(pod *corev1.Pod, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOS {
+	if strategy == nil {
+		return nil
+	}
+	return GetResourceQoSByQoSClass(apiext.GetPodQoSClassWithDefault(pod), strategy)
+}
+
+// GetResourceQoSByQoSClass gets the config of the koordinator qos class, e.g. the qos of a host application.
+func GetResourceQoSByQoSClass(qosClass apiext.QoSClass, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOS {
 	if strategy == nil {
 		return nil
 	}
 	var resourceQoS *slov1alpha1.ResourceQOS
-	podQoS := apiext.GetPodQoSClassWithDefault(pod)
-	switch podQoS {
+	switch qosClass {
 	case apiext.QoSLSE:
 		// currently LSE pods use the same strategy with LSR
 		resourceQoS = strategy.LSRClass
@@ -130,10 +130,13 @@ func (m *cgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alp
 	// calculate qos-level, pod-level and container-level resources
 	qosResources, podResources, containerResources := m.calculateResources(nodeSLO.Spec.ResourceQOSStrategy, node, podMetas)
 
+	// calculate host-application-level resources
+	hostAppResources := m.calculateHostAppResources(nodeSLO.Spec.ResourceQOSStrategy, nodeSLO.Spec.HostApplications)
+
 	// to make sure the hierarchical cgroup resources are correctly updated, we simply update the resources by
 	// cgroup-level order.
 	// e.g. /kubepods.slice/memory.min, /kubepods.slice-podxxx/memory.min, /kubepods.slice-podxxx/docker-yyy/memory.min
-	leveledResources := [][]resourceexecutor.ResourceUpdater{qosResources, podResources, containerResources}
+	leveledResources := [][]resourceexecutor.ResourceUpdater{qosResources, podResources, containerResources, hostAppResources}
 	m.executor.LeveledUpdateBatch(leveledResources)
 }
 
@@ -350,6 +353,34 @@ func (m *cgroupResourcesReconcile) calculateContainerResources(container *corev1
 	return makeCgroupResources(parentDir, summary)
 }
 
+// calculateHostAppResources calculates the resources of host applications with the config of their qos classes.
+// Only the static memory qos knobs are applied since the host applications have no request and limit.
+func (m *cgroupResourcesReconcile) calculateHostAppResources(nodeCfg *slov1alpha1.ResourceQOSStrategy,
+	hostApps []slov1alpha1.HostApplicationSpec) []resourceexecutor.ResourceUpdater {
+	var resources []resourceexecutor.ResourceUpdater
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		appCfg := helpers.GetResourceQoSByQoSClass(hostApp.QoS, nodeCfg)
+		if appCfg == nil || appCfg.MemoryQOS == nil {
+			klog.V(5).Infof("skip calculate resources for host application %s, qos %s has no memory qos config",
+				hostApp.Name, hostApp.QoS)
+			continue
+		}
+
+		summary := &cgroupResourceSummary{
+			memoryWmarkRatio:       appCfg.MemoryQOS.WmarkRatio,
+			memoryWmarkScaleFactor: appCfg.MemoryQOS.WmarkScalePermill,
+			memoryWmarkMinAdj:      appCfg.MemoryQOS.WmarkMinAdj,
+			memoryUsePriorityOom:   appCfg.MemoryQOS.PriorityEnable,
+			memoryPriority:         appCfg.MemoryQOS.Priority,
+			memoryOomKillGroup:     appCfg.MemoryQOS.OomKillGroup,
+		}
+		appDir := koordletutil.GetHostAppCgroupRelativePath(hostApp)
+		resources = append(resources, makeCgroupResources(appDir, summary)...)
+	}
+	return resources
+}
+
 // getMergedPodResourceQoS returns a merged ResourceQOS for the pod (i.e. a pod-level qos config).
 // 1. merge pod-level cfg with node-level cfg if pod annotation of advanced qos config exists;
 // 2. calculates and finally returns the pod-level cfg with each feature cfg (e.g. pod-level memory qos config).
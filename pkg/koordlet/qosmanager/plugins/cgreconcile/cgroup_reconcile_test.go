@@ -1182,6 +1182,47 @@ func Test_makeCgroupResources(t *testing.T) {
 	}
 }
 
+func TestCgroupResourcesReconcile_calculateHostAppResources(t *testing.T) {
+	testQOSStrategy := &slov1alpha1.ResourceQOSStrategy{
+		BEClass: &slov1alpha1.ResourceQOS{
+			MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+				MemoryQOS: slov1alpha1.MemoryQOS{
+					WmarkRatio:        pointer.Int64(80),
+					WmarkScalePermill: pointer.Int64(20),
+					WmarkMinAdj:       pointer.Int64(50),
+				},
+			},
+		},
+	}
+	hostApps := []slov1alpha1.HostApplicationSpec{
+		{
+			Name: "test-be-app",
+			QoS:  apiext.QoSBE,
+		},
+		{
+			Name: "test-ls-app",
+			QoS:  apiext.QoSLS,
+		},
+	}
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldIsAnolisOS := system.HostSystemInfo.IsAnolisOS
+	system.HostSystemInfo.IsAnolisOS = true
+	defer func() {
+		system.HostSystemInfo.IsAnolisOS = oldIsAnolisOS
+	}()
+
+	m := &cgroupResourcesReconcile{}
+	got := m.calculateHostAppResources(testQOSStrategy, hostApps)
+	appDir := koordletutil.GetHostAppCgroupRelativePath(&hostApps[0])
+	want := []resourceexecutor.ResourceUpdater{
+		createCgroupResourceUpdater(t, system.MemoryWmarkRatioName, appDir, "80", false),
+		createCgroupResourceUpdater(t, system.MemoryWmarkScaleFactorName, appDir, "20", false),
+		createCgroupResourceUpdater(t, system.MemoryWmarkMinAdjName, appDir, "50", false),
+	}
+	assertCgroupResourceEqual(t, want, got)
+}
+
 func newTestCgroupResourcesReconcile(opt *framework.Options) *cgroupResourcesReconcile {
 	return &cgroupResourcesReconcile{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
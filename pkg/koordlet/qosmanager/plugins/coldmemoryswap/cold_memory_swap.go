@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coldmemoryswap
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	ColdMemorySwapOutName = "coldMemorySwapOut"
+)
+
+var _ framework.QOSStrategy = &coldMemorySwapper{}
+
+// coldMemorySwapper swaps out the cold pages of the BE pods with the memory.reclaim when the node memory usage
+// exceeds the threshold, so that the idle anonymous memory of the batch jobs is moved to the swap before the memory
+// pressure spikes, and the memory throttle and eviction have more reclaimable headroom. The pages swapped out in each
+// round are bounded by the free swap and the swap IO rate of the node.
+type coldMemorySwapper struct {
+	swapInterval          time.Duration
+	metricCollectInterval time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	cgroupReader          resourceexecutor.CgroupReader
+	executor              resourceexecutor.ResourceUpdateExecutor
+
+	// lastSwapIOPages and lastSwapIOTime are the swap IO pages of the node observed in the last round, which are the
+	// base to calculate the swap IO rate.
+	lastSwapIOPages uint64
+	lastSwapIOTime  time.Time
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &coldMemorySwapper{
+		swapInterval:          time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		cgroupReader:          resourceexecutor.NewCgroupReader(),
+		executor:              resourceexecutor.NewResourceUpdateExecutor(),
+	}
+}
+
+func (s *coldMemorySwapper) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEColdMemorySwapOut) && s.swapInterval > 0
+}
+
+func (s *coldMemorySwapper) Setup(ctx *framework.Context) {
+}
+
+func (s *coldMemorySwapper) Run(stopCh <-chan struct{}) {
+	s.executor.Run(stopCh)
+	go wait.Until(s.coldMemorySwapOut, s.swapInterval, stopCh)
+}
+
+func (s *coldMemorySwapper) coldMemorySwapOut() {
+	klog.V(5).Infof("starting cold memory swap-out process")
+	defer klog.V(5).Infof("cold memory swap-out process completed")
+
+	if sysutil.GetCurrentCgroupVersion() != sysutil.CgroupVersionV2 {
+		klog.V(5).Infof("skip cold memory swap-out, memory.reclaim requires cgroups v2")
+		return
+	}
+
+	nodeSLO := s.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEColdMemorySwapOut); err != nil {
+		klog.Errorf("failed to acquire cold memory swap-out feature-gate, error: %v", err)
+		return
+	} else if disabled {
+		klog.V(4).Infof("skip cold memory swap-out, disabled in NodeSLO")
+		return
+	}
+
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	thresholdPercent := thresholdConfig.BEColdMemorySwapOutThresholdPercent
+	if thresholdPercent == nil {
+		klog.V(5).Infof("skip cold memory swap-out, threshold percent is nil")
+		return
+	} else if *thresholdPercent < 0 {
+		klog.Warningf("skip cold memory swap-out, threshold percent(%v) should greater than 0", *thresholdPercent)
+		return
+	}
+
+	// the swap IO rate is tracked every round, so the rate is fresh when the memory usage reaches the threshold
+	swapIORate, ok := s.updateSwapIORate(time.Now())
+	if !ok {
+		klog.V(5).Infof("skip cold memory swap-out, swap IO rate is not observed yet")
+		return
+	}
+	metrics.RecordNodeSwapIOPagesPerSecond(swapIORate)
+
+	memInfo, err := koordletutil.GetMemInfo()
+	if err != nil {
+		klog.Warningf("skip cold memory swap-out, get meminfo failed, error: %v", err)
+		return
+	}
+	if memInfo.SwapTotal == 0 {
+		klog.V(5).Infof("skip cold memory swap-out, swap is not enabled on the node")
+		return
+	}
+
+	node := s.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip cold memory swap-out, Node is nil")
+		return
+	}
+	memoryCapacity := node.Status.Capacity.Memory().Value()
+	if memoryCapacity <= 0 {
+		klog.Warningf("skip cold memory swap-out, memory capacity(%v) should greater than 0", memoryCapacity)
+		return
+	}
+	queryMeta, err := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("skip cold memory swap-out, get node query failed, error: %v", err)
+		return
+	}
+	nodeMemoryUsed, err := helpers.CollectorNodeMetricLast(s.metricCache, queryMeta, s.metricCollectInterval)
+	if err != nil {
+		klog.Warningf("skip cold memory swap-out, get node metrics error: %v", err)
+		return
+	}
+	nodeMemoryUsage := int64(nodeMemoryUsed) * 100 / memoryCapacity
+	if nodeMemoryUsage < *thresholdPercent {
+		klog.V(5).Infof("skip cold memory swap-out, node memory usage(%v) is below the threshold(%v)",
+			nodeMemoryUsage, *thresholdPercent)
+		metrics.RecordBEColdMemorySwapOutBytes(0)
+		return
+	}
+
+	budget := calculateSwapOutBudget(int64(memInfo.SwapFree*1024), swapIORate,
+		thresholdConfig.BEColdMemorySwapOutMaxIOPagesPerSecond, s.swapInterval, int64(os.Getpagesize()))
+	if budget <= 0 {
+		klog.V(4).Infof("skip cold memory swap-out, no budget, swap free %v KiB, swap IO rate %.2f pages/s",
+			memInfo.SwapFree, swapIORate)
+		metrics.RecordBEColdMemorySwapOutBytes(0)
+		return
+	}
+	klog.V(4).Infof("node MemoryUsage(%v): %.2f, swapOutThresholdUsage: %.2f, start cold memory swap-out with budget %v",
+		nodeMemoryUsed, float64(nodeMemoryUsage)/100, float64(*thresholdPercent)/100, budget)
+	s.swapOutBEPods(budget, thresholdConfig.BEColdMemorySwapOutSwappiness, helpers.NewPodExemptions(thresholdConfig))
+}
+
+// updateSwapIORate returns the pages swapped in and out per second since the last round. It returns false if the
+// rate can not be calculated, e.g. in the first round.
+func (s *coldMemorySwapper) updateSwapIORate(now time.Time) (float64, bool) {
+	swapIOPages, err := sysutil.GetSwapIOPages()
+	if err != nil {
+		klog.V(4).Infof("failed to get swap IO pages, err: %v", err)
+		return 0, false
+	}
+	lastPages, lastTime := s.lastSwapIOPages, s.lastSwapIOTime
+	s.lastSwapIOPages, s.lastSwapIOTime = swapIOPages, now
+	if lastTime.IsZero() || !now.After(lastTime) || swapIOPages < lastPages {
+		return 0, false
+	}
+	return float64(swapIOPages-lastPages) / now.Sub(lastTime).Seconds(), true
+}
+
+// calculateSwapOutBudget returns the bytes allowed to swap out in one round. It is bounded by the free swap, and by
+// the swap IO headroom under maxIOPagesPerSecond in the interval if specified, so the swap-out pauses while the
+// swap IO is already saturated, e.g. the BE pods are thrashing on the swapped pages.
+func calculateSwapOutBudget(swapFreeBytes int64, swapIORate float64, maxIOPagesPerSecond *int64, interval time.Duration, pageSize int64) int64 {
+	budget := swapFreeBytes
+	if maxIOPagesPerSecond == nil {
+		return budget
+	}
+	headroom := float64(*maxIOPagesPerSecond) - swapIORate
+	if headroom <= 0 {
+		return 0
+	}
+	if ioBudget := int64(headroom*interval.Seconds()) * pageSize; ioBudget < budget {
+		budget = ioBudget
+	}
+	return budget
+}
+
+type swapOutCandidate struct {
+	podMeta   *statesinformer.PodMeta
+	coldBytes int64
+}
+
+// swapOutBEPods requests the memory.reclaim of the BE pods to swap out their cold swap-backed pages, the coldest pods
+// first, until the budget is used up. The exempted pods and the pods whose swap is disabled are skipped.
+func (s *coldMemorySwapper) swapOutBEPods(budget int64, swappiness *int64, exemptions helpers.PodExemptions) {
+	var candidates []swapOutCandidate
+	for _, podMeta := range s.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+			continue
+		}
+		if exemptions.IsExempted(pod) {
+			_ = audit.V(3).Pod(pod.Namespace, pod.Name).Reason(ColdMemorySwapOutName).Message("exempted from cold memory swap-out").Do()
+			continue
+		}
+		swapLimit, err := s.cgroupReader.ReadMemorySwapLimit(podMeta.CgroupDir)
+		if err != nil {
+			klog.V(5).Infof("skip cold memory swap-out for pod %s/%s, read swap limit failed, err: %v",
+				pod.Namespace, pod.Name, err)
+			continue
+		}
+		if swapLimit == 0 {
+			klog.V(6).Infof("skip cold memory swap-out for pod %s/%s, swap is disabled", pod.Namespace, pod.Name)
+			continue
+		}
+		coldBytes, err := s.cgroupReader.ReadMemoryColdSwapBackedPageUsage(podMeta.CgroupDir)
+		if err != nil {
+			klog.V(5).Infof("skip cold memory swap-out for pod %s/%s, read cold page usage failed, err: %v",
+				pod.Namespace, pod.Name, err)
+			continue
+		}
+		if coldBytes == 0 {
+			continue
+		}
+		candidates = append(candidates, swapOutCandidate{podMeta: podMeta, coldBytes: int64(coldBytes)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].coldBytes > candidates[j].coldBytes
+	})
+
+	var resources []resourceexecutor.ResourceUpdater
+	var totalBytes int64
+	for _, candidate := range candidates {
+		if budget <= 0 {
+			break
+		}
+		swapOutBytes := candidate.coldBytes
+		if swapOutBytes > budget {
+			swapOutBytes = budget
+		}
+		pod := candidate.podMeta.Pod
+		eventHelper := audit.V(3).Pod(pod.Namespace, pod.Name).Reason(ColdMemorySwapOutName).
+			Message("swap out %d bytes of cold pages", swapOutBytes)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.MemoryReclaimName, candidate.podMeta.CgroupDir,
+			formatMemoryReclaim(swapOutBytes, swappiness), eventHelper)
+		if err != nil {
+			klog.V(5).Infof("skip cold memory swap-out for pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		resources = append(resources, updater)
+		budget -= swapOutBytes
+		totalBytes += swapOutBytes
+	}
+
+	s.executor.UpdateBatch(false, resources...)
+
+	metrics.RecordBEColdMemorySwapOutBytes(float64(totalBytes))
+}
+
+// formatMemoryReclaim generates the memory.reclaim request, e.g. "1048576 swappiness=200".
+func formatMemoryReclaim(bytes int64, swappiness *int64) string {
+	if swappiness == nil {
+		return fmt.Sprintf("%d", bytes)
+	}
+	return fmt.Sprintf("%d swappiness=%d", bytes, *swappiness)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coldmemoryswap
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func Test_calculateSwapOutBudget(t *testing.T) {
+	tests := []struct {
+		name                string
+		swapFreeBytes       int64
+		swapIORate          float64
+		maxIOPagesPerSecond *int64
+		want                int64
+	}{
+		{
+			name:          "bounded by the free swap",
+			swapFreeBytes: 1 << 30,
+			swapIORate:    1000,
+			want:          1 << 30,
+		},
+		{
+			name:                "bounded by the swap IO headroom",
+			swapFreeBytes:       1 << 30,
+			swapIORate:          100,
+			maxIOPagesPerSecond: pointer.Int64(200),
+			want:                100 * 30 * 4096,
+		},
+		{
+			name:                "free swap is less than the swap IO headroom",
+			swapFreeBytes:       4096,
+			swapIORate:          100,
+			maxIOPagesPerSecond: pointer.Int64(200),
+			want:                4096,
+		},
+		{
+			name:                "pause when the swap IO is saturated",
+			swapFreeBytes:       1 << 30,
+			swapIORate:          300,
+			maxIOPagesPerSecond: pointer.Int64(200),
+			want:                0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateSwapOutBudget(tt.swapFreeBytes, tt.swapIORate, tt.maxIOPagesPerSecond, 30*time.Second, 4096)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_formatMemoryReclaim(t *testing.T) {
+	assert.Equal(t, "1048576", formatMemoryReclaim(1048576, nil))
+	assert.Equal(t, "1048576 swappiness=200", formatMemoryReclaim(1048576, pointer.Int64(200)))
+}
+
+func Test_updateSwapIORate(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	s := &coldMemorySwapper{}
+	now := time.Now()
+	// vmstat not exist
+	_, ok := s.updateSwapIORate(now)
+	assert.False(t, ok)
+
+	// the first observation has no base
+	helper.WriteProcSubFileContents(sysutil.ProcVMStatName, "pswpin 100\npswpout 100\n")
+	_, ok = s.updateSwapIORate(now)
+	assert.False(t, ok)
+
+	helper.WriteProcSubFileContents(sysutil.ProcVMStatName, "pswpin 400\npswpout 800\n")
+	got, ok := s.updateSwapIORate(now.Add(10 * time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, float64(100), got)
+}
+
+func Test_swapOutBEPods(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	coldPod := createColdMemorySwapTestPod("test_cold_be_pod", apiext.QoSBE)
+	warmPod := createColdMemorySwapTestPod("test_warm_be_pod", apiext.QoSBE)
+	noSwapPod := createColdMemorySwapTestPod("test_no_swap_be_pod", apiext.QoSBE)
+	backupPod := createColdMemorySwapTestPod("test_backup_pod", apiext.QoSBE)
+	backupPod.Namespace = "backup"
+	lsPod := createColdMemorySwapTestPod("test_ls_pod", apiext.QoSLS)
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{coldPod, warmPod, noSwapPod, backupPod, lsPod})
+	coldBytes := []uint64{8192, 4096, 8192, 8192, 8192}
+	swapLimits := []string{"max", "max", "0", "max", "max"}
+	helper.WriteCgroupFileContents(sysutil.CgroupPathFormatter.ParentDir, sysutil.MemoryIdlePageStatsV2, newMemoryIdlePageStats(0))
+	helper.WriteCgroupFileContents(sysutil.CgroupPathFormatter.ParentDir, sysutil.MemorySwapMaxV2, "max")
+	helper.WriteCgroupFileContents(sysutil.CgroupPathFormatter.ParentDir, sysutil.MemoryReclaimV2, "")
+	for i, podMeta := range podMetas {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemoryIdlePageStatsV2, newMemoryIdlePageStats(coldBytes[i]))
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemorySwapMaxV2, swapLimits[i])
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemoryReclaimV2, "")
+	}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	s := &coldMemorySwapper{
+		statesInformer: mockStatesInformer,
+		cgroupReader:   resourceexecutor.NewCgroupReader(),
+		executor:       resourceexecutor.NewTestResourceExecutor(),
+	}
+	exemptions := helpers.NewPodExemptions(&slov1alpha1.ResourceThresholdStrategy{
+		Exemptions: []slov1alpha1.ResourceThresholdExemption{
+			{Namespaces: []string{"backup"}},
+		},
+	})
+
+	// the coldest pod is swapped out first, and the budget left is given to the next one
+	s.swapOutBEPods(10240, pointer.Int64(200), exemptions)
+	assert.Equal(t, "8192 swappiness=200", helper.ReadCgroupFileContents(podMetas[0].CgroupDir, sysutil.MemoryReclaimV2))
+	assert.Equal(t, "2048 swappiness=200", helper.ReadCgroupFileContents(podMetas[1].CgroupDir, sysutil.MemoryReclaimV2))
+	// the pod with the swap disabled, the exempted pod and the LS pod are not swapped out
+	assert.Equal(t, "", helper.ReadCgroupFileContents(podMetas[2].CgroupDir, sysutil.MemoryReclaimV2))
+	assert.Equal(t, "", helper.ReadCgroupFileContents(podMetas[3].CgroupDir, sysutil.MemoryReclaimV2))
+	assert.Equal(t, "", helper.ReadCgroupFileContents(podMetas[4].CgroupDir, sysutil.MemoryReclaimV2))
+}
+
+func createColdMemorySwapTestPod(name string, qosClass apiext.QoSClass) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(qosClass),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBurstable,
+		},
+	}
+}
+
+func newMemoryIdlePageStats(coldBytes uint64) string {
+	return fmt.Sprintf(`# version: 1.0
+# page_scans: 24
+# slab_scans: 0
+# scan_period_in_seconds: 120
+# use_hierarchy: 1
+# buckets: 1,2,5,15,30,60,120,240
+#
+#   _-----=> clean/dirty
+#  / _----=> swap/file
+# | / _---=> evict/unevict
+# || / _--=> inactive/active
+# ||| / _-=> slab
+# |||| /
+# |||||             [1,2)          [2,5)         [5,15)        [15,30)        [30,60)       [60,120)      [120,240)     [240,+inf)
+  csei     %d              0              0              0              0              0              0              0
+  dsei                  0              0              0              0              0              0              0              0
+  cfei                  0              0              0              0              0              0              0              0
+  dfei                  0              0              0              0              0              0              0              0
+  csui                  0              0              0              0              0              0              0              0
+  dsui                  0              0              0              0              0              0              0              0
+  cfui                  0              0              0              0              0              0              0              0
+  dfui                  0              0              0              0              0              0              0              0
+  csea                  0              0              0              0              0              0              0              0
+  dsea                  0              0              0              0              0              0              0              0
+  cfea                  0              0              0              0              0              0              0              0
+  dfea                  0              0              0              0              0              0              0              0
+  csua                  0              0              0              0              0              0              0              0
+  dsua                  0              0              0              0              0              0              0              0
+  cfua                  0              0              0              0              0              0              0              0
+  dfua                  0              0              0              0              0              0              0              0
+  slab                  0              0              0              0              0              0              0              0`, coldBytes)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coordinateddrain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CoordinatedDrainName = "CoordinatedDrain"
+
+	// AllocationReportReason is the reason of the node event reporting the final allocations of the draining node.
+	AllocationReportReason = "CoordinatedDrainAllocationReport"
+)
+
+type coordinatedDrain struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	eventRecorder     record.EventRecorder
+	// reported indicates whether the allocation report is emitted in the current drain.
+	reported bool
+}
+
+var _ framework.QOSStrategy = &coordinatedDrain{}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &coordinatedDrain{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		eventRecorder:     opt.EventRecorder,
+	}
+}
+
+func (c *coordinatedDrain) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.CoordinatedDrain) && c.reconcileInterval > 0
+}
+
+func (c *coordinatedDrain) Setup(context *framework.Context) {
+}
+
+func (c *coordinatedDrain) Run(stopCh <-chan struct{}) {
+	go wait.Until(c.reconcile, c.reconcileInterval, stopCh)
+}
+
+func (c *coordinatedDrain) reconcile() {
+	node := c.statesInformer.GetNode()
+	if node == nil {
+		klog.V(5).Infof("node is nil, skip reconcile coordinated drain")
+		return
+	}
+	if !apiext.IsNodeCoordinatedDraining(node) {
+		// the drain is finished or canceled, report again in the next drain
+		c.reported = false
+		return
+	}
+	if c.reported {
+		return
+	}
+
+	report := generateAllocationReport(c.statesInformer.GetAllPods())
+	c.eventRecorder.Eventf(node, corev1.EventTypeNormal, AllocationReportReason, "final allocations before drain: %s", report)
+	c.reported = true
+	klog.V(4).Infof("emit the final allocation report of the draining node %s: %s", node.Name, report)
+}
+
+// generateAllocationReport summarizes the cpuset and NUMA nodes bound by the pods, e.g.
+// "default/pod-a: cpuset=0-3, numa=[0]; default/pod-b: numa=[0,1]".
+func generateAllocationReport(podMetas []*statesinformer.PodMeta) string {
+	var allocations []string
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		resourceStatus, err := apiext.GetResourceStatus(pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to get resource status of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		var fields []string
+		if resourceStatus.CPUSet != "" {
+			fields = append(fields, fmt.Sprintf("cpuset=%s", resourceStatus.CPUSet))
+		}
+		if len(resourceStatus.NUMANodeResources) > 0 {
+			numaNodes := make([]string, 0, len(resourceStatus.NUMANodeResources))
+			for _, numaNodeRes := range resourceStatus.NUMANodeResources {
+				numaNodes = append(numaNodes, fmt.Sprint(numaNodeRes.Node))
+			}
+			fields = append(fields, fmt.Sprintf("numa=[%s]", strings.Join(numaNodes, ",")))
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		allocations = append(allocations, fmt.Sprintf("%s/%s: %s", pod.Namespace, pod.Name, strings.Join(fields, ", ")))
+	}
+	if len(allocations) == 0 {
+		return "none"
+	}
+	sort.Strings(allocations)
+	return strings.Join(allocations, "; ")
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coordinateddrain
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+func newTestPodMeta(t *testing.T, name string, status *apiext.ResourceStatus) *statesinformer.PodMeta {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	if status != nil {
+		assert.NoError(t, apiext.SetResourceStatus(pod, status))
+	}
+	return &statesinformer.PodMeta{Pod: pod}
+}
+
+func Test_generateAllocationReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		podMetas []*statesinformer.PodMeta
+		want     string
+	}{
+		{
+			name: "no bound pods",
+			podMetas: []*statesinformer.PodMeta{
+				nil,
+				newTestPodMeta(t, "shared-pod", nil),
+			},
+			want: "none",
+		},
+		{
+			name: "report cpuset and numa nodes of bound pods",
+			podMetas: []*statesinformer.PodMeta{
+				newTestPodMeta(t, "pod-b", &apiext.ResourceStatus{
+					NUMANodeResources: []apiext.NUMANodeResource{{Node: 0}, {Node: 1}},
+				}),
+				newTestPodMeta(t, "shared-pod", nil),
+				newTestPodMeta(t, "pod-a", &apiext.ResourceStatus{
+					CPUSet:            "0-3",
+					NUMANodeResources: []apiext.NUMANodeResource{{Node: 0}},
+				}),
+			},
+			want: "default/pod-a: cpuset=0-3, numa=[0]; default/pod-b: numa=[0,1]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, generateAllocationReport(tt.podMetas))
+		})
+	}
+}
+
+func Test_coordinatedDrain_reconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Labels: map[string]string{
+				apiext.LabelNodeCoordinatedDrain: "true",
+			},
+		},
+	}
+	podMetas := []*statesinformer.PodMeta{
+		newTestPodMeta(t, "pod-a", &apiext.ResourceStatus{CPUSet: "0-3"}),
+	}
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+	recorder := record.NewFakeRecorder(10)
+	c := &coordinatedDrain{
+		statesInformer: si,
+		eventRecorder:  recorder,
+	}
+
+	// not draining until the node is cordoned
+	si.EXPECT().GetNode().Return(node).Times(1)
+	c.reconcile()
+	assert.Len(t, recorder.Events, 0)
+
+	// emit the report only once in a drain
+	drainingNode := node.DeepCopy()
+	drainingNode.Spec.Unschedulable = true
+	si.EXPECT().GetNode().Return(drainingNode).Times(2)
+	c.reconcile()
+	c.reconcile()
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal CoordinatedDrainAllocationReport final allocations before drain: default/pod-a: cpuset=0-3", <-recorder.Events)
+
+	// report again in the next drain
+	si.EXPECT().GetNode().Return(node).Times(1)
+	c.reconcile()
+	si.EXPECT().GetNode().Return(drainingNode).Times(1)
+	c.reconcile()
+	assert.Len(t, recorder.Events, 1)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuaffinity
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	CPUAffinityObserveName = "CPUAffinityObserve"
+
+	// ReasonCPUAffinityDrifted is the event reason when the allowed CPUs of the container main process differs from
+	// the allocated cpuset, e.g. the application overrides its affinity by taskset.
+	ReasonCPUAffinityDrifted = "CPUAffinityDrifted"
+)
+
+type cpuAffinityObserve struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	cgroupReader      resourceexecutor.CgroupReader
+	eventRecorder     record.EventRecorder
+	// driftedContainers records the drifted allowed CPUs of the containers by the container id, so the event is only
+	// sent when the drift changes.
+	driftedContainers map[string]string
+}
+
+var _ framework.QOSStrategy = &cpuAffinityObserve{}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &cpuAffinityObserve{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		cgroupReader:      opt.CgroupReader,
+		eventRecorder:     opt.EventRecorder,
+		driftedContainers: map[string]string{},
+	}
+}
+
+func (c *cpuAffinityObserve) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.CPUAffinityObservation) && c.reconcileInterval > 0
+}
+
+func (c *cpuAffinityObserve) Setup(context *framework.Context) {
+}
+
+func (c *cpuAffinityObserve) Run(stopCh <-chan struct{}) {
+	go wait.Until(c.reconcile, c.reconcileInterval, stopCh)
+}
+
+func (c *cpuAffinityObserve) reconcile() {
+	// reset the drift metrics to clean up the exited containers
+	metrics.ResetContainerCPUAffinityDriftCPUs()
+	driftedContainers := map[string]string{}
+	for _, podMeta := range c.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		if qosClass := apiext.GetPodQoSClassRaw(pod); qosClass != apiext.QoSLSE && qosClass != apiext.QoSLSR {
+			continue
+		}
+		resourceStatus, err := annotation.LenientParser.ParseResourceStatus(pod.Annotations)
+		if err != nil || resourceStatus.CPUSet == "" {
+			continue
+		}
+		allocated, err := cpuset.Parse(resourceStatus.CPUSet)
+		if err != nil {
+			klog.V(4).Infof("failed to parse the allocated cpuset %s of pod %s, err: %v",
+				resourceStatus.CPUSet, util.GetPodKey(pod), err)
+			continue
+		}
+
+		for i := range pod.Status.ContainerStatuses {
+			containerStat := &pod.Status.ContainerStatuses[i]
+			if containerStat.ContainerID == "" || containerStat.State.Running == nil {
+				continue
+			}
+			expected := allocated
+			if containerCPUSet, ok := resourceStatus.GetContainerCPUSet(containerStat.Name); ok {
+				if containerCPUSet == "" {
+					// the container runs in the share pool
+					continue
+				}
+				if expected, err = cpuset.Parse(containerCPUSet); err != nil {
+					klog.V(4).Infof("failed to parse the allocated cpuset %s of container %s/%s, err: %v",
+						containerCPUSet, util.GetPodKey(pod), containerStat.Name, err)
+					continue
+				}
+			}
+			allowed, err := c.observeContainer(podMeta, containerStat)
+			if err != nil {
+				klog.V(5).Infof("failed to observe cpu affinity of container %s/%s, err: %v",
+					util.GetPodKey(pod), containerStat.Name, err)
+				continue
+			}
+			drift := allowed.Difference(expected).Union(expected.Difference(*allowed))
+			metrics.RecordContainerCPUAffinityDriftCPUs(pod.Namespace, pod.Name, containerStat.ContainerID,
+				containerStat.Name, float64(drift.Size()))
+			if drift.IsEmpty() {
+				continue
+			}
+
+			driftedContainers[containerStat.ContainerID] = allowed.String()
+			if c.driftedContainers[containerStat.ContainerID] == allowed.String() {
+				continue
+			}
+			c.eventRecorder.Eventf(pod, corev1.EventTypeWarning, ReasonCPUAffinityDrifted,
+				"container %s: main process is allowed on cpus %s, differs from the allocated cpuset %s",
+				containerStat.Name, allowed.String(), expected.String())
+			klog.V(4).Infof("cpu affinity of container %s/%s drifts, allowed %s, allocated %s",
+				util.GetPodKey(pod), containerStat.Name, allowed.String(), expected.String())
+		}
+	}
+	c.driftedContainers = driftedContainers
+}
+
+// observeContainer returns the allowed CPUs of the main process of the container, which is the task with the lowest
+// pid in the container cgroup.
+func (c *cpuAffinityObserve) observeContainer(podMeta *statesinformer.PodMeta, containerStat *corev1.ContainerStatus) (*cpuset.CPUSet, error) {
+	containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cgroup dir, err: %w", err)
+	}
+	tasks, err := c.cgroupReader.ReadCPUTasks(containerDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks, err: %w", err)
+	}
+	if len(tasks) <= 0 {
+		return nil, fmt.Errorf("no task found")
+	}
+	mainTask := tasks[0]
+	for _, task := range tasks[1:] {
+		if task < mainTask {
+			mainTask = task
+		}
+	}
+	return resourceexecutor.ReadTaskCPUsAllowed(mainTask)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuaffinity
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func Test_cpuAffinityObserve_reconcile(t *testing.T) {
+	newPod := func(qos apiext.QoSClass, status string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod-1",
+				UID:       types.UID("pod-1-uid"),
+				Labels: map[string]string{
+					apiext.LabelPodQoS: string(qos),
+				},
+				Annotations: map[string]string{
+					apiext.AnnotationResourceStatus: status,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSGuaranteed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "main",
+						ContainerID: "containerd://pod-1-main",
+						State: corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name              string
+		pod               *corev1.Pod
+		tasks             string
+		taskStatus        map[string]string
+		driftedContainers map[string]string
+		wantDrifted       map[string]string
+		wantEvent         string
+	}{
+		{
+			name:  "affinity matches the allocated cpuset",
+			pod:   newPod(apiext.QoSLSR, `{"cpuset":"0-3"}`),
+			tasks: "100\n101\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t0-3\n",
+				"101/status": "Name:\ttest\nCpus_allowed_list:\t2\n",
+			},
+			wantDrifted: map[string]string{},
+		},
+		{
+			name:  "affinity of the main process drifts",
+			pod:   newPod(apiext.QoSLSE, `{"cpuset":"0-3"}`),
+			tasks: "101\n100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t2\n",
+				"101/status": "Name:\ttest\nCpus_allowed_list:\t0-3\n",
+			},
+			wantDrifted: map[string]string{
+				"containerd://pod-1-main": "2",
+			},
+			wantEvent: ReasonCPUAffinityDrifted,
+		},
+		{
+			name:  "no event for the unchanged drift",
+			pod:   newPod(apiext.QoSLSE, `{"cpuset":"0-3"}`),
+			tasks: "100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t2\n",
+			},
+			driftedContainers: map[string]string{
+				"containerd://pod-1-main": "2",
+			},
+			wantDrifted: map[string]string{
+				"containerd://pod-1-main": "2",
+			},
+		},
+		{
+			name:  "affinity matches the cpuset allocated to the container",
+			pod:   newPod(apiext.QoSLSR, `{"cpuset":"0-3","containerCPUSets":[{"name":"main","cpuset":"2"}]}`),
+			tasks: "100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t2\n",
+			},
+			wantDrifted: map[string]string{},
+		},
+		{
+			name:  "affinity drifts from the cpuset allocated to the container",
+			pod:   newPod(apiext.QoSLSR, `{"cpuset":"0-3","containerCPUSets":[{"name":"main","cpuset":"2"}]}`),
+			tasks: "100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t0-3\n",
+			},
+			wantDrifted: map[string]string{
+				"containerd://pod-1-main": "0-3",
+			},
+			wantEvent: ReasonCPUAffinityDrifted,
+		},
+		{
+			name:  "ignore the container in the share pool",
+			pod:   newPod(apiext.QoSLSR, `{"cpuset":"0-3","containerCPUSets":[{"name":"sidecar","cpuset":"0-3"}]}`),
+			tasks: "100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t0-15\n",
+			},
+			wantDrifted: map[string]string{},
+		},
+		{
+			name:  "ignore pod without allocated cpuset",
+			pod:   newPod(apiext.QoSLSR, `{}`),
+			tasks: "100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t2\n",
+			},
+			wantDrifted: map[string]string{},
+		},
+		{
+			name:  "ignore LS pod",
+			pod:   newPod(apiext.QoSLS, `{"cpuset":"0-3"}`),
+			tasks: "100\n",
+			taskStatus: map[string]string{
+				"100/status": "Name:\ttest\nCpus_allowed_list:\t2\n",
+			},
+			wantDrifted: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+
+			podMeta := &statesinformer.PodMeta{
+				Pod:       tt.pod,
+				CgroupDir: koordletutil.GetPodCgroupParentDir(tt.pod),
+			}
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, &tt.pod.Status.ContainerStatuses[0])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir, sysutil.CPUTasks, tt.tasks)
+			for file, content := range tt.taskStatus {
+				helper.WriteProcSubFileContents(file, content)
+			}
+
+			si := mock_statesinformer.NewMockStatesInformer(ctrl)
+			si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+			recorder := &testutil.FakeRecorder{}
+			driftedContainers := tt.driftedContainers
+			if driftedContainers == nil {
+				driftedContainers = map[string]string{}
+			}
+
+			c := &cpuAffinityObserve{
+				statesInformer:    si,
+				cgroupReader:      resourceexecutor.NewCgroupReader(),
+				eventRecorder:     recorder,
+				driftedContainers: driftedContainers,
+			}
+			c.reconcile()
+
+			assert.Equal(t, tt.wantDrifted, c.driftedContainers)
+			assert.Equal(t, tt.wantEvent, recorder.EventReason)
+		})
+	}
+}
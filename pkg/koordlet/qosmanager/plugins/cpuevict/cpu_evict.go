@@ -35,6 +35,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -56,7 +57,8 @@ type cpuEvictor struct {
 	metricCollectInterval time.Duration
 	statesInformer        statesinformer.StatesInformer
 	metricCache           metriccache.MetricCache
-	evictor               *framework.Evictor
+	cgroupReader          resourceexecutor.CgroupReader
+	evictionManager       *framework.EvictionManager
 	lastEvictTime         time.Time
 }
 
@@ -67,6 +69,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
 		statesInformer:        opt.StatesInformer,
 		metricCache:           opt.MetricCache,
+		cgroupReader:          resourceexecutor.NewCgroupReader(),
 		lastEvictTime:         time.Now(),
 	}
 }
@@ -76,7 +79,7 @@ func (c *cpuEvictor) Enabled() bool {
 }
 
 func (c *cpuEvictor) Setup(ctx *framework.Context) {
-	c.evictor = ctx.Evictor
+	c.evictionManager = ctx.EvictionManager
 }
 
 func (c *cpuEvictor) Run(stopCh <-chan struct{}) {
@@ -283,39 +286,92 @@ func (c *cpuEvictor) evictByResourceSatisfaction(node *corev1.Node, thresholdCon
 	milliRelease := c.calculateMilliRelease(thresholdConfig, windowSeconds)
 	if milliRelease > 0 {
 		bePodInfos := c.getPodEvictInfoAndSort()
-		c.killAndEvictBEPodsRelease(node, bePodInfos, milliRelease)
+		neededNUMANodes := c.getPressuredNUMANodes(bePodInfos, *thresholdConfig.CPUEvictBESatisfactionLowerPercent)
+		c.killAndEvictBEPodsRelease(node, bePodInfos, milliRelease, neededNUMANodes, helpers.NewPodExemptions(thresholdConfig))
 	}
 }
 
-func (c *cpuEvictor) killAndEvictBEPodsRelease(node *corev1.Node, bePodInfos []*podEvictCPUInfo, cpuNeedMilliRelease int64) {
-	message := fmt.Sprintf("killAndEvictBEPodsRelease for node(%s), need release milli CPU: %v",
-		node.Name, cpuNeedMilliRelease)
+// getPressuredNUMANodes returns the NUMA nodes whose BE cpu satisfaction is no more than the lower percent, where the
+// satisfaction is the BE cpus on the NUMA node divided by the requests of the BE pods allocated on it.
+func (c *cpuEvictor) getPressuredNUMANodes(bePodInfos []*podEvictCPUInfo, satisfactionLowerPercent int64) []int {
+	nodeCPUInfoRaw, exist := c.metricCache.Get(metriccache.NodeCPUInfoKey)
+	if !exist {
+		klog.V(5).Infof("node cpu info not found, evict the BE pods on any NUMA node")
+		return nil
+	}
+	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
+	if !ok || nodeCPUInfo == nil {
+		klog.Warningf("type error, expect %T, but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
+		return nil
+	}
+	beCPUSet, err := c.cgroupReader.ReadCPUSet(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort))
+	if err != nil {
+		klog.V(4).Infof("failed to read be cpuset, evict the BE pods on any NUMA node, err: %v", err)
+		return nil
+	}
 
-	cpuMilliReleased := int64(0)
-	var killedPods []*corev1.Pod
-	for _, bePod := range bePodInfos {
-		if cpuMilliReleased >= cpuNeedMilliRelease {
-			break
+	numaMilliCPUs := map[int]int64{}
+	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		if beCPUSet.Contains(int(processor.CPUID)) {
+			numaMilliCPUs[int(processor.NodeID)] += 1000
+		}
+	}
+	numaMilliRequests := map[int]int64{}
+	for _, candidate := range toEvictionCandidates(bePodInfos) {
+		if len(candidate.NUMANodes) <= 0 {
+			continue
+		}
+		// the request of the pod spanning multiple NUMA nodes is considered evenly distributed
+		for _, numaNode := range candidate.NUMANodes {
+			numaMilliRequests[numaNode] += candidate.Request / int64(len(candidate.NUMANodes))
 		}
-
-		podKillMsg := fmt.Sprintf("%s, kill pod: %s", message, util.GetPodKey(bePod.pod))
-		helpers.KillContainers(bePod.pod, podKillMsg)
-
-		killedPods = append(killedPods, bePod.pod)
-		cpuMilliReleased = cpuMilliReleased + bePod.milliRequest
-
-		klog.V(5).Infof("cpuEvict pick pod %s/%s to evict", util.GetPodKey(bePod.pod))
 	}
 
-	c.evictor.EvictPodsIfNotEvicted(killedPods, node, resourceexecutor.EvictPodByBECPUSatisfaction, message)
+	var numaNodes []int
+	for numaNode, milliRequest := range numaMilliRequests {
+		if milliRequest <= 0 {
+			continue
+		}
+		if numaMilliCPUs[numaNode]*100 <= milliRequest*satisfactionLowerPercent {
+			numaNodes = append(numaNodes, numaNode)
+		}
+	}
+	sort.Ints(numaNodes)
+	if len(numaNodes) > 0 {
+		klog.V(4).Infof("be cpu satisfaction of NUMA nodes %v is below the lower percent(%v)", numaNodes, satisfactionLowerPercent)
+	}
+	return numaNodes
+}
 
-	if len(killedPods) > 0 {
+func (c *cpuEvictor) killAndEvictBEPodsRelease(node *corev1.Node, bePodInfos []*podEvictCPUInfo, cpuNeedMilliRelease int64,
+	neededNUMANodes []int, exemptions helpers.PodExemptions) {
+	req := &framework.EvictionRequest{
+		Reason: resourceexecutor.EvictPodByBECPUSatisfaction,
+		Message: fmt.Sprintf("killAndEvictBEPodsRelease for node(%s), need release milli CPU: %v",
+			node.Name, cpuNeedMilliRelease),
+		ResourceName:    apiext.BatchCPU,
+		ToRelease:       cpuNeedMilliRelease,
+		NeededNUMANodes: neededNUMANodes,
+		Candidates:      toEvictionCandidates(bePodInfos),
+		Exemptions:      exemptions,
+	}
+	victims, cpuMilliReleased := c.evictionManager.Evict(node, req)
+
+	if len(victims) > 0 {
 		c.lastEvictTime = time.Now()
 	}
 	klog.V(5).Infof("killAndEvictBEPodsRelease finished! cpuNeedMilliRelease(%d) cpuMilliReleased(%d)",
 		cpuNeedMilliRelease, cpuMilliReleased)
 }
 
+func toEvictionCandidates(bePodInfos []*podEvictCPUInfo) []*framework.EvictionCandidate {
+	candidates := make([]*framework.EvictionCandidate, 0, len(bePodInfos))
+	for _, bePod := range bePodInfos {
+		candidates = append(candidates, framework.NewEvictionCandidate(bePod.pod, bePod.milliUsedCores, bePod.milliRequest, bePod.milliRequest))
+	}
+	return candidates
+}
+
 func (c *cpuEvictor) getPodEvictInfoAndSort() []*podEvictCPUInfo {
 	var bePodInfos []*podEvictCPUInfo
 
@@ -349,13 +405,15 @@ func (c *cpuEvictor) getPodEvictInfoAndSort() []*podEvictCPUInfo {
 		}
 	}
 
-	sort.Slice(bePodInfos, func(i, j int) bool {
-		if bePodInfos[i].pod.Spec.Priority == nil || bePodInfos[j].pod.Spec.Priority == nil ||
-			*bePodInfos[i].pod.Spec.Priority == *bePodInfos[j].pod.Spec.Priority {
-			return bePodInfos[i].cpuUsage > bePodInfos[j].cpuUsage
-		}
-		return *bePodInfos[i].pod.Spec.Priority < *bePodInfos[j].pod.Spec.Priority
-	})
+	infoByPod := make(map[*corev1.Pod]*podEvictCPUInfo, len(bePodInfos))
+	for _, bePodInfo := range bePodInfos {
+		infoByPod[bePodInfo.pod] = bePodInfo
+	}
+	candidates := toEvictionCandidates(bePodInfos)
+	framework.RankEvictionCandidates(candidates, nil)
+	for i, candidate := range candidates {
+		bePodInfos[i] = infoByPod[candidate.Pod]
+	}
 	return bePodInfos
 }
 
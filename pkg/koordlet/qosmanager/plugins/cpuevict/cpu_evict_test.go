@@ -40,16 +40,151 @@ import (
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
 
-// TODO: unit test for cpuEvict() to improve coverage
-func Test_cpuEvict(t *testing.T) {}
+func Test_cpuEvict(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	// NUMA node 0 has 4 BE cpus while NUMA node 1 has only 1 BE cpu
+	helper.WriteCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet, "0-4")
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		ProcessorInfos: []koordletutil.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 2, CoreID: 2, SocketID: 0, NodeID: 0},
+			{CPUID: 3, CoreID: 3, SocketID: 0, NodeID: 0},
+			{CPUID: 4, CoreID: 4, SocketID: 1, NodeID: 1},
+			{CPUID: 5, CoreID: 5, SocketID: 1, NodeID: 1},
+			{CPUID: 6, CoreID: 6, SocketID: 1, NodeID: 1},
+			{CPUID: 7, CoreID: 7, SocketID: 1, NodeID: 1},
+		},
+	}
+	// the pod on NUMA node 0 uses more cpu, but the pod on the pressured NUMA node 1 is preferred to evict
+	podOnNUMA0 := mockBEPodForCPUEvictOnNUMANode("pod_be_numa0", 4*1000, 100, 0)
+	podOnNUMA1 := mockBEPodForCPUEvictOnNUMANode("pod_be_numa1", 4*1000, 100, 1)
+	pods := []*corev1.Pod{podOnNUMA0, podOnNUMA1}
+	thresholdConfig := &slov1alpha1.ResourceThresholdStrategy{
+		Enable:                             pointer.Bool(true),
+		CPUEvictBESatisfactionLowerPercent: pointer.Int64(40),
+		CPUEvictBESatisfactionUpperPercent: pointer.Int64(50),
+		CPUEvictBEUsageThresholdPercent:    pointer.Int64(90),
+	}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(thresholdConfig)).AnyTimes()
+	mockStatesInformer.EXPECT().GetNode().Return(testutil.MockTestNode("8", "32G")).AnyTimes()
+	mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas(pods)).AnyTimes()
+
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	mockMetricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(nodeCPUInfo, true).AnyTimes()
+	mockQuerier := mock_metriccache.NewMockQuerier(ctl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	// BE satisfaction = 3 / 8, need to release 8 * (0.5 - 0.375) = 1 core
+	beMetrics := map[metriccache.MetricPropertyValue]float64{
+		metriccache.BEResouceAllocationUsage:     2.9 * 1000,
+		metriccache.BEResouceAllocationRequest:   8 * 1000,
+		metriccache.BEResouceAllocationRealLimit: 3 * 1000,
+	}
+	for allocation, value := range beMetrics {
+		queryMeta, err := metriccache.NodeBEMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.NodeBE(string(metriccache.BEResourceCPU), string(allocation)))
+		assert.NoError(t, err)
+		result := buildMockQueryResultAndCount(ctl, mockQuerier, mockResultFactory, queryMeta)
+		result.EXPECT().Value(gomock.Any()).Return(value, nil).AnyTimes()
+		result.EXPECT().Count().Return(60).AnyTimes()
+	}
+	for uid, cpuUsed := range map[string]float64{"pod_be_numa0": 3, "pod_be_numa1": 1} {
+		podQueryMeta, err := metriccache.PodCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod(uid))
+		assert.NoError(t, err)
+		buildMockQueryResult(ctl, mockQuerier, mockResultFactory, podQueryMeta, cpuUsed)
+	}
+
+	fakeRecorder := &testutil.FakeRecorder{}
+	client := clientsetfake.NewSimpleClientset()
+	stop := make(chan struct{})
+	evictor := framework.NewEvictor(client, fakeRecorder, policyv1beta1.SchemeGroupVersion.Version)
+	evictor.Start(stop)
+	defer func() { stop <- struct{}{} }()
+
+	runtime.DockerHandler = handler.NewFakeRuntimeHandler()
+	var containers []*critesting.FakeContainer
+	for _, pod := range pods {
+		_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			_, containerId, _ := util.ParseContainerId(containerStatus.ContainerID)
+			containers = append(containers, &critesting.FakeContainer{
+				SandboxID:       string(pod.UID),
+				ContainerStatus: runtimeapi.ContainerStatus{Id: containerId},
+			})
+		}
+	}
+	runtime.DockerHandler.(*handler.FakeRuntimeHandler).SetFakeContainers(containers)
+
+	c := New(&framework.Options{
+		StatesInformer:      mockStatesInformer,
+		MetricCache:         mockMetricCache,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}).(*cpuEvictor)
+	c.Setup(&framework.Context{Evictor: evictor, EvictionManager: framework.NewEvictionManager(evictor, false)})
+	c.lastEvictTime = time.Now().Add(-5 * time.Minute)
+	c.cpuEvict()
+
+	getEvictObject, err := client.Tracker().Get(testutil.PodsResource, podOnNUMA1.Namespace, podOnNUMA1.Name)
+	assert.NoError(t, err)
+	assert.IsType(t, &policyv1beta1.Eviction{}, getEvictObject, "evictPod: %s Fail", podOnNUMA1.Name)
+	getNotEvictObject, err := client.Tracker().Get(testutil.PodsResource, podOnNUMA0.Namespace, podOnNUMA0.Name)
+	assert.NoError(t, err)
+	assert.IsType(t, &corev1.Pod{}, getNotEvictObject, "no need evict", podOnNUMA0.Name)
+}
+
+func Test_cpuEvictor_getPressuredNUMANodes(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet, "0-2,4")
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		ProcessorInfos: []koordletutil.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 2, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 3, CoreID: 3, SocketID: 1, NodeID: 1},
+			{CPUID: 4, CoreID: 4, SocketID: 2, NodeID: 2},
+			{CPUID: 5, CoreID: 5, SocketID: 2, NodeID: 2},
+		},
+	}
+	bePodInfos := []*podEvictCPUInfo{
+		{pod: mockBEPodForCPUEvictOnNUMANode("pod_numa0", 2*1000, 100, 0), milliRequest: 2 * 1000},
+		{pod: mockBEPodForCPUEvictOnNUMANode("pod_numa1", 4*1000, 100, 1), milliRequest: 4 * 1000},
+		{pod: mockBEPodForCPUEvictOnNUMANode("pod_numa1_2", 6*1000, 100, 1, 2), milliRequest: 6 * 1000},
+		{pod: mockBEPodForCPUEvict("pod_no_numa", 8*1000, 100), milliRequest: 8 * 1000},
+	}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	mockMetricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(nodeCPUInfo, true).AnyTimes()
+	c := &cpuEvictor{
+		metricCache:  mockMetricCache,
+		cgroupReader: resourceexecutor.NewCgroupReader(),
+	}
+	// NUMA node 0: 2 cpus, 2 requested; NUMA node 1: 1 cpu, 7 requested; NUMA node 2: 1 cpu, 3 requested
+	assert.Equal(t, []int{1, 2}, c.getPressuredNUMANodes(bePodInfos, 40))
+	assert.Equal(t, []int{1}, c.getPressuredNUMANodes(bePodInfos, 20))
+	assert.Nil(t, c.getPressuredNUMANodes(bePodInfos, 10))
+}
 
 func Test_CPUEvict_calculateMilliRelease(t *testing.T) {
 	testNode := &corev1.Node{
@@ -568,11 +703,11 @@ func Test_killAndEvictBEPodsRelease(t *testing.T) {
 	runtime.DockerHandler.(*handler.FakeRuntimeHandler).SetFakeContainers(containers)
 
 	cpuEvictor := &cpuEvictor{
-		evictor:       evictor,
-		lastEvictTime: time.Now().Add(-5 * time.Minute),
+		evictionManager: framework.NewEvictionManager(evictor, false),
+		lastEvictTime:   time.Now().Add(-5 * time.Minute),
 	}
 
-	cpuEvictor.killAndEvictBEPodsRelease(node, podEvictInfosSorted, 18*1000)
+	cpuEvictor.killAndEvictBEPodsRelease(node, podEvictInfosSorted, 18*1000, nil, nil)
 
 	getEvictObject, err := client.Tracker().Get(testutil.PodsResource, podEvictInfosSorted[0].pod.Namespace, podEvictInfosSorted[0].pod.Name)
 	assert.NotNil(t, getEvictObject, "evictPod Fail, err: %v", err)
@@ -715,3 +850,13 @@ func buildMockQueryResultAndCount(ctrl *gomock.Controller, querier *mock_metricc
 
 	return result
 }
+
+func mockBEPodForCPUEvictOnNUMANode(name string, request int64, priority int32, numaNodes ...int32) *corev1.Pod {
+	pod := mockBEPodForCPUEvict(name, request, priority)
+	resourceStatus := &apiext.ResourceStatus{}
+	for _, numaNode := range numaNodes {
+		resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, apiext.NUMANodeResource{Node: numaNode})
+	}
+	_ = apiext.SetResourceStatus(pod, resourceStatus)
+	return pod
+}
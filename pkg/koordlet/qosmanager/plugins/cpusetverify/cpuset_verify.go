@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusetverify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	CPUSetVerifyName = "CPUSetVerify"
+)
+
+type cpusetVerify struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	cgroupReader      resourceexecutor.CgroupReader
+	kubeClient        clientset.Interface
+	eventRecorder     record.EventRecorder
+}
+
+var _ framework.QOSStrategy = &cpusetVerify{}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &cpusetVerify{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		cgroupReader:      opt.CgroupReader,
+		kubeClient:        opt.KubeClient,
+		eventRecorder:     opt.EventRecorder,
+	}
+}
+
+func (c *cpusetVerify) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.CPUSetVerification) && c.reconcileInterval > 0
+}
+
+func (c *cpusetVerify) Setup(context *framework.Context) {
+}
+
+func (c *cpusetVerify) Run(stopCh <-chan struct{}) {
+	go wait.Until(c.reconcile, c.reconcileInterval, stopCh)
+}
+
+func (c *cpusetVerify) reconcile() {
+	for _, podMeta := range c.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		if qosClass := apiext.GetPodQoSClassRaw(pod); qosClass != apiext.QoSLSE && qosClass != apiext.QoSLSR {
+			continue
+		}
+		resourceStatus, err := annotation.LenientParser.ParseResourceStatus(pod.Annotations)
+		if err != nil || resourceStatus.CPUSet == "" {
+			continue
+		}
+
+		condition := verifyAllocatedCPUs(pod, resourceStatus)
+		if condition == nil {
+			condition = c.verifyPod(podMeta, resourceStatus.CPUSet)
+		}
+		if condition == nil {
+			continue
+		}
+		c.updatePodCondition(pod, condition)
+	}
+}
+
+// verifyAllocatedCPUs checks that the allocated cpuset holds the CPUs the Pod requests, which are bound physically
+// even if the node amplifies the CPUs. It returns nil if the allocation is sufficient or can't be verified by the
+// requests, e.g. only some containers are bound individually.
+func verifyAllocatedCPUs(pod *corev1.Pod, resourceStatus *apiext.ResourceStatus) *corev1.PodCondition {
+	if len(resourceStatus.ContainerCPUSets) > 0 {
+		return nil
+	}
+	allocated, err := cpuset.Parse(resourceStatus.CPUSet)
+	if err != nil {
+		return nil
+	}
+	requests := util.GetPodRequest(pod, corev1.ResourceCPU)
+	numCPUsRequested := int(requests.Cpu().MilliValue() / 1000)
+	if allocated.Size() >= numCPUsRequested {
+		return nil
+	}
+	return newCondition(corev1.ConditionFalse, apiext.ReasonCPUSetInsufficient,
+		fmt.Sprintf("allocated cpuset %s has %d CPUs, less than the %d CPUs requested",
+			allocated.String(), allocated.Size(), numCPUsRequested))
+}
+
+// verifyPod compares the cpuset of the running containers with the allocated cpuset, and returns the condition of the
+// result. It returns nil if no container is running.
+func (c *cpusetVerify) verifyPod(podMeta *statesinformer.PodMeta, allocated string) *corev1.PodCondition {
+	pod := podMeta.Pod
+	expected, err := cpuset.Parse(allocated)
+	if err != nil {
+		return newCondition(corev1.ConditionFalse, apiext.ReasonCPUSetMismatch,
+			fmt.Sprintf("failed to parse the allocated cpuset %s, err: %v", allocated, err))
+	}
+
+	verified := 0
+	var mismatches []string
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		if containerStat.ContainerID == "" || containerStat.State.Running == nil {
+			continue
+		}
+		verified++
+		containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("container %s: failed to get cgroup dir, err: %v", containerStat.Name, err))
+			continue
+		}
+		actual, err := c.cgroupReader.ReadCPUSet(containerDir)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("container %s: failed to read cpuset, err: %v", containerStat.Name, err))
+			continue
+		}
+		if !actual.Equals(expected) {
+			mismatches = append(mismatches, fmt.Sprintf("container %s: expected cpuset %s, actual %s",
+				containerStat.Name, expected.String(), actual.String()))
+		}
+	}
+	if verified <= 0 {
+		return nil
+	}
+	if len(mismatches) > 0 {
+		return newCondition(corev1.ConditionFalse, apiext.ReasonCPUSetMismatch, strings.Join(mismatches, "; "))
+	}
+	return newCondition(corev1.ConditionTrue, apiext.ReasonCPUSetBound,
+		fmt.Sprintf("cpuset %s is enforced on %d containers", expected.String(), verified))
+}
+
+func (c *cpusetVerify) updatePodCondition(pod *corev1.Pod, condition *corev1.PodCondition) {
+	oldCondition := getPodCondition(pod, condition.Type)
+	if oldCondition != nil && oldCondition.Status == condition.Status &&
+		oldCondition.Reason == condition.Reason && oldCondition.Message == condition.Message {
+		return
+	}
+	if oldCondition != nil && oldCondition.Status == condition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+
+	newPod := pod.DeepCopy()
+	setPodCondition(newPod, condition)
+	if _, err := util.PatchPodStatus(context.TODO(), c.kubeClient, pod, newPod); err != nil {
+		klog.Warningf("failed to patch cpuset condition of pod %s, err: %v", util.GetPodKey(pod), err)
+		return
+	}
+	if condition.Status == corev1.ConditionFalse {
+		c.eventRecorder.Eventf(pod, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	klog.V(4).Infof("update cpuset condition of pod %s, status %s, message: %s",
+		util.GetPodKey(pod), condition.Status, condition.Message)
+}
+
+func newCondition(status corev1.ConditionStatus, reason, message string) *corev1.PodCondition {
+	return &corev1.PodCondition{
+		Type:               apiext.PodConditionCPUSetBound,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+func getPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func setPodCondition(pod *corev1.Pod, condition *corev1.PodCondition) {
+	if oldCondition := getPodCondition(pod, condition.Type); oldCondition != nil {
+		*oldCondition = *condition
+		return
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, *condition)
+}
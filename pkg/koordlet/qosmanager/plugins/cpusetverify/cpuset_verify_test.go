@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusetverify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func Test_cpusetVerify_reconcile(t *testing.T) {
+	newPod := func(qos apiext.QoSClass, status string, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod-1",
+				UID:       types.UID("pod-1-uid"),
+				Labels: map[string]string{
+					apiext.LabelPodQoS: string(qos),
+				},
+				Annotations: map[string]string{
+					apiext.AnnotationResourceStatus: status,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSGuaranteed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "main",
+						ContainerID: "containerd://pod-1-main",
+						State: corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{},
+						},
+					},
+				},
+				Conditions: conditions,
+			},
+		}
+	}
+	withCPURequests := func(pod *corev1.Pod, cpu string) *corev1.Pod {
+		pod.Spec.Containers = []corev1.Container{
+			{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			},
+		}
+		return pod
+	}
+	tests := []struct {
+		name          string
+		pod           *corev1.Pod
+		cgroupCPUSet  string
+		wantCondition *corev1.PodCondition
+		wantEvent     string
+	}{
+		{
+			name:         "cpuset is enforced",
+			pod:          newPod(apiext.QoSLSR, `{"cpuset":"0-3"}`),
+			cgroupCPUSet: "0-3",
+			wantCondition: &corev1.PodCondition{
+				Type:    apiext.PodConditionCPUSetBound,
+				Status:  corev1.ConditionTrue,
+				Reason:  apiext.ReasonCPUSetBound,
+				Message: "cpuset 0-3 is enforced on 1 containers",
+			},
+		},
+		{
+			name:         "cpuset mismatches",
+			pod:          newPod(apiext.QoSLSE, `{"cpuset":"0-3"}`),
+			cgroupCPUSet: "0-15",
+			wantCondition: &corev1.PodCondition{
+				Type:    apiext.PodConditionCPUSetBound,
+				Status:  corev1.ConditionFalse,
+				Reason:  apiext.ReasonCPUSetMismatch,
+				Message: "container main: expected cpuset 0-3, actual 0-15",
+			},
+			wantEvent: apiext.ReasonCPUSetMismatch,
+		},
+		{
+			name: "cpuset is fixed",
+			pod: newPod(apiext.QoSLSR, `{"cpuset":"0-3"}`, corev1.PodCondition{
+				Type:    apiext.PodConditionCPUSetBound,
+				Status:  corev1.ConditionFalse,
+				Reason:  apiext.ReasonCPUSetMismatch,
+				Message: "container main: expected cpuset 0-3, actual 0-15",
+			}),
+			cgroupCPUSet: "0-3",
+			wantCondition: &corev1.PodCondition{
+				Type:    apiext.PodConditionCPUSetBound,
+				Status:  corev1.ConditionTrue,
+				Reason:  apiext.ReasonCPUSetBound,
+				Message: "cpuset 0-3 is enforced on 1 containers",
+			},
+		},
+		{
+			name:         "cpuset holds the requested CPUs",
+			pod:          withCPURequests(newPod(apiext.QoSLSR, `{"cpuset":"0-3"}`), "4"),
+			cgroupCPUSet: "0-3",
+			wantCondition: &corev1.PodCondition{
+				Type:    apiext.PodConditionCPUSetBound,
+				Status:  corev1.ConditionTrue,
+				Reason:  apiext.ReasonCPUSetBound,
+				Message: "cpuset 0-3 is enforced on 1 containers",
+			},
+		},
+		{
+			name:         "cpuset has fewer CPUs than requested",
+			pod:          withCPURequests(newPod(apiext.QoSLSR, `{"cpuset":"0-1"}`), "4"),
+			cgroupCPUSet: "0-1",
+			wantCondition: &corev1.PodCondition{
+				Type:    apiext.PodConditionCPUSetBound,
+				Status:  corev1.ConditionFalse,
+				Reason:  apiext.ReasonCPUSetInsufficient,
+				Message: "allocated cpuset 0-1 has 2 CPUs, less than the 4 CPUs requested",
+			},
+			wantEvent: apiext.ReasonCPUSetInsufficient,
+		},
+		{
+			name:         "ignore pod without allocated cpuset",
+			pod:          newPod(apiext.QoSLSR, `{}`),
+			cgroupCPUSet: "0-15",
+		},
+		{
+			name:         "ignore LS pod",
+			pod:          newPod(apiext.QoSLS, `{"cpuset":"0-3"}`),
+			cgroupCPUSet: "0-15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+
+			podMeta := &statesinformer.PodMeta{
+				Pod:       tt.pod,
+				CgroupDir: koordletutil.GetPodCgroupParentDir(tt.pod),
+			}
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, &tt.pod.Status.ContainerStatuses[0])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir, sysutil.CPUSet, tt.cgroupCPUSet)
+
+			si := mock_statesinformer.NewMockStatesInformer(ctrl)
+			si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+			client := clientsetfake.NewSimpleClientset(tt.pod)
+			recorder := &testutil.FakeRecorder{}
+
+			c := &cpusetVerify{
+				statesInformer: si,
+				cgroupReader:   resourceexecutor.NewCgroupReader(),
+				kubeClient:     client,
+				eventRecorder:  recorder,
+			}
+			c.reconcile()
+
+			got, err := client.CoreV1().Pods(tt.pod.Namespace).Get(context.TODO(), tt.pod.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			gotCondition := getPodCondition(got, apiext.PodConditionCPUSetBound)
+			if tt.wantCondition == nil {
+				assert.Nil(t, gotCondition)
+			} else {
+				assert.NotNil(t, gotCondition)
+				assert.Equal(t, tt.wantCondition.Status, gotCondition.Status)
+				assert.Equal(t, tt.wantCondition.Reason, gotCondition.Reason)
+				assert.Equal(t, tt.wantCondition.Message, gotCondition.Message)
+			}
+			assert.Equal(t, tt.wantEvent, recorder.EventReason)
+		})
+	}
+}
+
+func Test_cpusetVerify_updatePodCondition(t *testing.T) {
+	transitionTime := metav1.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               apiext.PodConditionCPUSetBound,
+					Status:             corev1.ConditionTrue,
+					Reason:             apiext.ReasonCPUSetBound,
+					Message:            "cpuset 0-3 is enforced on 1 containers",
+					LastTransitionTime: transitionTime,
+				},
+			},
+		},
+	}
+	client := clientsetfake.NewSimpleClientset(pod)
+	c := &cpusetVerify{
+		kubeClient:    client,
+		eventRecorder: &testutil.FakeRecorder{},
+	}
+
+	// keep the transition time if the status is unchanged
+	c.updatePodCondition(pod, newCondition(corev1.ConditionTrue, apiext.ReasonCPUSetBound, "cpuset 0-3 is enforced on 2 containers"))
+	got, err := client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	gotCondition := getPodCondition(got, apiext.PodConditionCPUSetBound)
+	assert.Equal(t, "cpuset 0-3 is enforced on 2 containers", gotCondition.Message)
+	assert.True(t, transitionTime.Equal(&gotCondition.LastTransitionTime))
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/selfgovernance"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
@@ -138,10 +139,12 @@ func (r *CPUSuppress) writeBECgroupsCPUSet(paths []string, cpusetStr string, isR
 
 // calculateBESuppressCPU calculates the quantity of cpuset cpus for suppressing be pods
 func (r *CPUSuppress) calculateBESuppressCPU(node *corev1.Node, nodeMetric float64,
-	podMetrics map[string]float64, podMetas []*statesinformer.PodMeta, beCPUUsedThreshold int64) *resource.Quantity {
+	podMetrics map[string]float64, podMetas []*statesinformer.PodMeta, hostAppMetrics map[string]float64,
+	hostApps []slov1alpha1.HostApplicationSpec, beCPUUsedThreshold int64) *resource.Quantity {
 	// node, nodeMetric, podMetric should not be nil
 	podAllUsedCPU := *resource.NewMilliQuantity(0, resource.DecimalSI)
 	podNoneBEUsedCPU := *resource.NewMilliQuantity(0, resource.DecimalSI)
+	hostAppNoneBEUsedCPU := *resource.NewMilliQuantity(0, resource.DecimalSI)
 	nodeUsedCPU := *resource.NewMilliQuantity(int64(nodeMetric*1000), resource.DecimalSI)
 
 	podMetaMap := map[string]*statesinformer.PodMeta{}
@@ -162,8 +165,21 @@ func (r *CPUSuppress) calculateBESuppressCPU(node *corev1.Node, nodeMetric float
 		}
 	}
 
+	for _, hostApp := range hostApps {
+		hostAppMetric, ok := hostAppMetrics[hostApp.Name]
+		if !ok {
+			continue
+		}
+		if hostApp.QoS != apiext.QoSBE {
+			// NOTE: consider non-BE host applications as LS, while the BE host applications are not throttled by the
+			// BE cgroups, so they are kept in the system usage and counted against the BE budget
+			hostAppNoneBEUsedCPU.Add(*resource.NewMilliQuantity(int64(hostAppMetric*1000), resource.DecimalSI))
+		}
+	}
+
 	systemUsedCPU := nodeUsedCPU.DeepCopy()
 	systemUsedCPU.Sub(podAllUsedCPU)
+	systemUsedCPU.Sub(hostAppNoneBEUsedCPU)
 	if systemUsedCPU.Value() < 0 {
 		// set systemUsedCPU always no less than 0
 		systemUsedCPU = *resource.NewMilliQuantity(0, resource.DecimalSI)
@@ -177,22 +193,24 @@ func (r *CPUSuppress) calculateBESuppressCPU(node *corev1.Node, nodeMetric float
 	systemUsed = quotav1.Max(quotav1.Max(systemUsed, nodeAnnoReserved), nodeKubeletReserved)
 	systemUsedCPU = systemUsed[corev1.ResourceCPU]
 
-	// suppress(BE) := node.Capacity * SLOPercent - pod(non-be).Used - max(system.Used, node.anno.reserved, node.kubelet.reserved)
+	// suppress(BE) := node.Capacity * SLOPercent - pod(non-be).Used - hostApp(non-be).Used
+	//                 - max(system.Used, node.anno.reserved, node.kubelet.reserved)
 	// NOTE: valid milli-cpu values should not larger than 2^20, so there is no overflow during the calculation
 	nodeBESuppressCPU := resource.NewMilliQuantity(node.Status.Capacity.Cpu().MilliValue()*beCPUUsedThreshold/100,
 		node.Status.Allocatable.Cpu().Format)
 	nodeBESuppressCPU.Sub(podNoneBEUsedCPU)
+	nodeBESuppressCPU.Sub(hostAppNoneBEUsedCPU)
 	nodeBESuppressCPU.Sub(systemUsedCPU)
 
 	metrics.RecordBESuppressLSUsedCPU(float64(podNoneBEUsedCPU.MilliValue()) / 1000)
-	klog.Infof("nodeSuppressBE[CPU(Core)]:%v = node.Total:%v * SLOPercent:%v%% - systemUsage:%v - podLSUsed:%v, upper to %v\n",
+	klog.Infof("nodeSuppressBE[CPU(Core)]:%v = node.Total:%v * SLOPercent:%v%% - systemUsage:%v - podLSUsed:%v - hostAppLSUsed:%v, upper to %v\n",
 		nodeBESuppressCPU.AsApproximateFloat64(), node.Status.Allocatable.Cpu().AsApproximateFloat64(), beCPUUsedThreshold, systemUsedCPU.AsApproximateFloat64(),
-		podNoneBEUsedCPU.AsApproximateFloat64(), nodeBESuppressCPU.Value())
+		podNoneBEUsedCPU.AsApproximateFloat64(), hostAppNoneBEUsedCPU.AsApproximateFloat64(), nodeBESuppressCPU.Value())
 
 	return nodeBESuppressCPU
 }
 
-func (r *CPUSuppress) applyBESuppressCPUSet(beCPUSet []int32, oldCPUSet []int32) error {
+func (r *CPUSuppress) applyBESuppressCPUSet(beCPUSet []int32, oldCPUSet []int32, exemptedPods []*statesinformer.PodMeta) error {
 	nodeTopo := r.statesInformer.GetNodeTopo()
 	if nodeTopo == nil {
 		return errors.New("NodeTopo is nil")
@@ -204,9 +222,9 @@ func (r *CPUSuppress) applyBESuppressCPUSet(beCPUSet []int32, oldCPUSet []int32)
 	}
 	if kubeletPolicy.Policy == apiext.KubeletCPUManagerPolicyStatic {
 		r.recoverCPUSetIfNeed(koordletutil.PodCgroupPathRelativeDepth)
-		err = r.applyCPUSetWithStaticPolicy(beCPUSet)
+		err = r.applyCPUSetWithStaticPolicy(beCPUSet, exemptedPods)
 	} else {
-		err = r.applyCPUSetWithNonePolicy(beCPUSet, oldCPUSet)
+		err = r.applyCPUSetWithNonePolicy(beCPUSet, oldCPUSet, exemptedPods)
 	}
 	if err != nil {
 		return fmt.Errorf("failed with kubelet policy %v, %w", kubeletPolicy.Policy, err)
@@ -215,7 +233,7 @@ func (r *CPUSuppress) applyBESuppressCPUSet(beCPUSet []int32, oldCPUSet []int32)
 }
 
 // applyCPUSetWithNonePolicy applies the be suppress policy by writing best-effort cgroups
-func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32) error {
+func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32, exemptedPods []*statesinformer.PodMeta) error {
 	// 1. get current be cgroups cpuset
 	// 2. temporarily write with a union of old cpuset and new cpuset from upper to lower, to avoid cgroup conflicts
 	// 3. write with the new cpuset from lower to upper to apply the real policy
@@ -229,6 +247,8 @@ func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32)
 		klog.Warningf("applyCPUSetWithNonePolicy failed to get be cgroup cpuset paths, err: %s", err)
 		return fmt.Errorf("apply be suppress policy failed, err: %s", err)
 	}
+	// the exempted pods and the BE QoS cgroup keep the unsuppressed cpuset
+	cpusetCgroupPaths = r.recoverExemptedBECgroupsCPUSet(cpusetCgroupPaths, exemptedPods, true)
 
 	// write a loose cpuset for all be cgroups before applying the real policy
 	mergedCPUSet := cpuset.MergeCPUSet(oldCPUSet, cpus)
@@ -246,7 +266,7 @@ func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32)
 	return nil
 }
 
-func (r *CPUSuppress) applyCPUSetWithStaticPolicy(cpus []int32) error {
+func (r *CPUSuppress) applyCPUSetWithStaticPolicy(cpus []int32, exemptedPods []*statesinformer.PodMeta) error {
 	if len(cpus) <= 0 {
 		klog.Warningf("applyCPUSetWithStaticPolicy skipped due to the empty cpuset")
 		return nil
@@ -257,6 +277,7 @@ func (r *CPUSuppress) applyCPUSetWithStaticPolicy(cpus []int32) error {
 		klog.Warningf("applyCPUSetWithStaticPolicy failed to get be cgroup cpuset paths, err: %s", err)
 		return fmt.Errorf("apply be suppress policy failed, err: %s", err)
 	}
+	containerPaths = r.recoverExemptedBECgroupsCPUSet(containerPaths, exemptedPods, false)
 
 	cpusetStr := cpuset.GenerateCPUSetStr(cpus)
 	klog.V(6).Infof("applyCPUSetWithStaticPolicy writes suppressed cpuset to containers, cpuset %v", cpus)
@@ -306,6 +327,8 @@ func (r *CPUSuppress) suppressBECPU() {
 		klog.Warningf("suppressBECPU failed, got empty pod metas %v", podMetas)
 		return
 	}
+	exemptedPods, _ := splitBEPodsByExemptions(podMetas,
+		helpers.NewPodExemptions(nodeSLO.Spec.ResourceUsedThresholdWithBE))
 
 	podMetrics := helpers.CollectAllPodMetricsLast(r.statesInformer, r.metricCache, metriccache.PodCPUUsageMetric, r.metricCollectInterval)
 	if podMetrics == nil {
@@ -323,8 +346,14 @@ func (r *CPUSuppress) suppressBECPU() {
 		return
 	}
 
-	suppressCPUQuantity := r.calculateBESuppressCPU(node, value, podMetrics, podMetas,
+	hostApps := nodeSLO.Spec.HostApplications
+	hostAppMetrics := helpers.CollectAllHostAppMetricsLast(hostApps, r.metricCache, metriccache.HostAppCPUUsageMetric, r.metricCollectInterval)
+
+	suppressCPUQuantity := r.calculateBESuppressCPU(node, value, podMetrics, podMetas, hostAppMetrics, hostApps,
 		*nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+	if discount := nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUStealCapacityDiscountPercent; discount != nil && *discount > 0 {
+		suppressCPUQuantity = r.applyCPUStealDiscount(suppressCPUQuantity, node, *discount)
+	}
 
 	// Step 2.
 	nodeCPUInfoRaw, exist := r.metricCache.Get(metriccache.NodeCPUInfoKey)
@@ -337,17 +366,18 @@ func (r *CPUSuppress) suppressBECPU() {
 		klog.Fatalf("type error, expect %T， but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
 	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
-		r.adjustByCfsQuota(suppressCPUQuantity, node)
+		r.adjustByCfsQuota(suppressCPUQuantity, node, exemptedPods)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
 		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
 	} else {
-		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo)
+		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo, exemptedPods)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyUsing
 		r.recoverCFSQuotaIfNeed()
 	}
 }
 
-func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo) {
+func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo,
+	exemptedPods []*statesinformer.PodMeta) {
 	rootCgroupParentDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
 	oldCPUS, err := r.cgroupReader.ReadCPUSet(rootCgroupParentDir)
 	if err != nil {
@@ -395,12 +425,16 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 		klog.Warningf("get system qos exclusive cpuset failed, error: %v", err)
 	}
 
+	// the cpus koordlet pinned on
+	koordletCPUSet := selfgovernance.GetPinnedCPUs()
+
 	var lsrCpus []koordletutil.ProcessorInfo
 	var lsCpus []koordletutil.ProcessorInfo
 	// FIXME: be pods might be starved since lse pods can run out of all cpus
 	for _, processor := range nodeCPUInfo.ProcessorInfos {
 		cpuCoreID := cpuset.NewCPUSet(int(processor.CPUID))
-		if cpuCoreID.IsSubsetOf(cpusetReserved) || cpuCoreID.IsSubsetOf(exclusiveSystemQOSCPUSet) {
+		if cpuCoreID.IsSubsetOf(cpusetReserved) || cpuCoreID.IsSubsetOf(exclusiveSystemQOSCPUSet) ||
+			cpuCoreID.IsSubsetOf(koordletCPUSet) {
 			continue
 		}
 
@@ -435,7 +469,7 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 	// the new be suppress always need to apply since:
 	// - for a reduce of BE cpuset, we should make effort to protecting LS no matter how huge the decrease is;
 	// - for a enlargement of BE cpuset, it is welcome and costless for BE processes.
-	err = r.applyBESuppressCPUSet(beCPUSet, oldCPUSet)
+	err = r.applyBESuppressCPUSet(beCPUSet, oldCPUSet, exemptedPods)
 	if err != nil {
 		klog.Warningf("suppressBECPU failed to apply be cpu suppress policy, err: %s", err)
 		return
@@ -562,9 +596,14 @@ func (r *CPUSuppress) calcBECPUSet() (*cpuset.CPUSet, error) {
 		return nil, fmt.Errorf("node topo is nil")
 	}
 
-	// LSE pod, reserved cpu and system qos exclusive
+	// LSE pod, reserved cpu, system qos exclusive and koordlet pinned
 	exclusiveCPUID := make(map[int]bool)
 
+	// exclude the cpuset koordlet pinned on
+	for _, cpuID := range selfgovernance.GetPinnedCPUs().ToSliceNoSort() {
+		exclusiveCPUID[cpuID] = true
+	}
+
 	// System pod, exclude cpuset if exclusive
 	exclusiveSystemQOSCPUSet, err := getSystemQOSExclusiveCPU(topo.Annotations)
 	if err != nil {
@@ -613,9 +652,13 @@ func (r *CPUSuppress) calcBECPUSet() (*cpuset.CPUSet, error) {
 	return &beCPUSet, nil
 }
 
-func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *corev1.Node) {
+func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *corev1.Node,
+	exemptedPods []*statesinformer.PodMeta) {
 	newBeQuota := cpuQuantity.MilliValue() * cfsPeriod / 1000
 	newBeQuota = int64(math.Max(float64(newBeQuota), float64(beMinQuota)))
+	// the quota of the BE QoS cgroup is shared by all BE pods, so enlarge it with the exempted pods' quota to keep
+	// them out of the suppression
+	newBeQuota += getExemptedPodsCFSQuota(exemptedPods)
 
 	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
 	// read current offline quota
@@ -650,7 +693,9 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		return
 	}
 	metrics.RecordBESuppressCores(string(slov1alpha1.CPUCfsQuotaPolicy), float64(newBeQuota)/float64(cfsPeriod))
-	_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota).Do()
+	if isUpdated {
+		_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota).Do()
+	}
 	klog.Infof("suppressBECPU: succeeded to write cfs_quota_us for offline pods, isUpdated %v, new value: %d", isUpdated, newBeQuota)
 }
 
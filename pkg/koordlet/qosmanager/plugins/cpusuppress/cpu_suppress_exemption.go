@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// splitBEPodsByExemptions splits the pods in the BE QoS cgroup into the exempted pods and the pods to suppress.
+func splitBEPodsByExemptions(podMetas []*statesinformer.PodMeta, exemptions helpers.PodExemptions) (exemptedPods, suppressedPods []*statesinformer.PodMeta) {
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || util.GetKubeQosClass(podMeta.Pod) != corev1.PodQOSBestEffort {
+			continue
+		}
+		if exemptions.IsExempted(podMeta.Pod) {
+			exemptedPods = append(exemptedPods, podMeta)
+		} else {
+			suppressedPods = append(suppressedPods, podMeta)
+		}
+	}
+	return exemptedPods, suppressedPods
+}
+
+func isUnderPodCgroups(path string, podMetas []*statesinformer.PodMeta) bool {
+	for _, podMeta := range podMetas {
+		podDir := filepath.Clean(podMeta.CgroupDir)
+		if path == podDir || strings.HasPrefix(path, podDir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// recoverExemptedBECgroupsCPUSet writes the unsuppressed BE cpuset to the cgroups of the exempted pods, and the BE QoS
+// cgroup if includeQoSDir is true. It returns the rest paths to suppress.
+func (r *CPUSuppress) recoverExemptedBECgroupsCPUSet(paths []string, exemptedPods []*statesinformer.PodMeta, includeQoSDir bool) []string {
+	if len(exemptedPods) <= 0 {
+		return paths
+	}
+	beCPUSet, err := r.calcBECPUSet()
+	if err != nil || beCPUSet == nil {
+		klog.Warningf("failed to get be cpuset for the exempted pods, suppress all be cgroups, err: %v", err)
+		return paths
+	}
+
+	beQoSDir := filepath.Clean(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort))
+	var exemptedPaths, suppressedPaths []string
+	for _, path := range paths {
+		cleanPath := filepath.Clean(path)
+		if (includeQoSDir && cleanPath == beQoSDir) || isUnderPodCgroups(cleanPath, exemptedPods) {
+			exemptedPaths = append(exemptedPaths, path)
+		} else {
+			suppressedPaths = append(suppressedPaths, path)
+		}
+	}
+
+	klog.V(6).Infof("recover cpuset for the exempted be cgroups, cpuset %v, paths %v", beCPUSet.String(), exemptedPaths)
+	r.writeBECgroupsCPUSet(exemptedPaths, beCPUSet.String(), false)
+	return suppressedPaths
+}
+
+// getExemptedPodsCFSQuota returns the cfs quota reserved in the BE QoS cgroup for the exempted pods, so that they are
+// moved out of the suppressed budget. An exempted pod is reserved its batch cpu limit, or its batch cpu request if it
+// is unlimited.
+func getExemptedPodsCFSQuota(exemptedPods []*statesinformer.PodMeta) int64 {
+	var milliCPU int64
+	for _, podMeta := range exemptedPods {
+		if limit := util.GetPodBEMilliCPULimit(podMeta.Pod); limit > 0 {
+			milliCPU += limit
+		} else if request := util.GetPodBEMilliCPURequest(podMeta.Pod); request > 0 {
+			milliCPU += request
+		}
+	}
+	return milliCPU * cfsPeriod / 1000
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func testingBEPodMeta(name, namespace string, labels map[string]string, batchMilliCPURequest, batchMilliCPULimit int64) *statesinformer.PodMeta {
+	container := corev1.Container{Name: "main"}
+	if batchMilliCPURequest > 0 {
+		container.Resources.Requests = corev1.ResourceList{
+			apiext.BatchCPU: *resource.NewQuantity(batchMilliCPURequest, resource.DecimalSI),
+		}
+	}
+	if batchMilliCPULimit > 0 {
+		container.Resources.Limits = corev1.ResourceList{
+			apiext.BatchCPU: *resource.NewQuantity(batchMilliCPULimit, resource.DecimalSI),
+		}
+	}
+	return &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       types.UID("uid-" + name),
+				Labels:    labels,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{container},
+			},
+			Status: corev1.PodStatus{
+				QOSClass: corev1.PodQOSBestEffort,
+			},
+		},
+		CgroupDir: filepath.Join(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), name),
+	}
+}
+
+func Test_splitBEPodsByExemptions(t *testing.T) {
+	podA := testingBEPodMeta("pod-a", "backup", nil, 1000, 2000)
+	podB := testingBEPodMeta("pod-b", "default", map[string]string{"app": "critical"}, 1000, 2000)
+	podC := testingBEPodMeta("pod-c", "default", nil, 1000, 2000)
+	lsPod := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-ls", Namespace: "backup"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "main",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{QOSClass: corev1.PodQOSBurstable},
+		},
+	}
+	podMetas := []*statesinformer.PodMeta{podA, podB, podC, lsPod, nil}
+
+	exemptions := helpers.NewPodExemptions(&slov1alpha1.ResourceThresholdStrategy{
+		Exemptions: []slov1alpha1.ResourceThresholdExemption{
+			{Namespaces: []string{"backup"}},
+			{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "critical"}}},
+		},
+	})
+	gotExempted, gotSuppressed := splitBEPodsByExemptions(podMetas, exemptions)
+	assert.Equal(t, []*statesinformer.PodMeta{podA, podB}, gotExempted)
+	assert.Equal(t, []*statesinformer.PodMeta{podC}, gotSuppressed)
+
+	gotExempted, gotSuppressed = splitBEPodsByExemptions(podMetas, nil)
+	assert.Nil(t, gotExempted)
+	assert.Equal(t, []*statesinformer.PodMeta{podA, podB, podC}, gotSuppressed)
+}
+
+func TestCPUSuppress_applyBESuppressCPUSetWithExemptions(t *testing.T) {
+	mockNodeInfo := &metriccache.NodeCPUInfo{
+		ProcessorInfos: []koordletutil.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 2, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 3, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 4, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 5, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 6, CoreID: 3, SocketID: 1, NodeID: 1},
+			{CPUID: 7, CoreID: 3, SocketID: 1, NodeID: 1},
+		},
+	}
+	exemptedPod := testingBEPodMeta("pod1", "backup", nil, 1000, 2000)
+	suppressedPod := testingBEPodMeta("pod2", "default", nil, 1000, 2000)
+	beQoSDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+
+	tests := []struct {
+		name                   string
+		cpuPolicy              *apiext.KubeletCPUManagerPolicy
+		wantBEDirCPUSet        string
+		wantExemptedCPUSet     string
+		wantSuppressedCPUSet   string
+		wantExemptedContainer  string
+		wantSuppressedContaner string
+	}{
+		{
+			name:                   "apply with none policy",
+			wantBEDirCPUSet:        "0-7",
+			wantExemptedCPUSet:     "0-7",
+			wantSuppressedCPUSet:   "0-3",
+			wantExemptedContainer:  "0-7",
+			wantSuppressedContaner: "0-3",
+		},
+		{
+			name: "apply with static policy",
+			cpuPolicy: &apiext.KubeletCPUManagerPolicy{
+				Policy: apiext.KubeletCPUManagerPolicyStatic,
+			},
+			wantBEDirCPUSet:        "0-7",
+			wantExemptedCPUSet:     "0-7",
+			wantSuppressedCPUSet:   "0-7",
+			wantExemptedContainer:  "0-7",
+			wantSuppressedContaner: "0-3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			testingPrepareBEContainerCgroupData(helper, []string{"pod1", "pod1/container1", "pod2", "pod2/container2"}, "0-7")
+
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			nodeTopo := &topov1alpha1.NodeResourceTopology{}
+			if tt.cpuPolicy != nil {
+				cpuPolicyStr, _ := json.Marshal(tt.cpuPolicy)
+				nodeTopo.Annotations = map[string]string{
+					apiext.AnnotationKubeletCPUManagerPolicy: string(cpuPolicyStr),
+				}
+			}
+			si := mockstatesinformer.NewMockStatesInformer(ctl)
+			si.EXPECT().GetNodeTopo().Return(nodeTopo).AnyTimes()
+			si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{exemptedPod, suppressedPod}).AnyTimes()
+			mc := mockmetriccache.NewMockMetricCache(ctl)
+			mc.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(mockNodeInfo, true).AnyTimes()
+			r := newTestCPUSuppress(&framework.Options{
+				StatesInformer:      si,
+				MetricCache:         mc,
+				Config:              framework.NewDefaultConfig(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			})
+			stopCh := make(chan struct{})
+			r.executor.Run(stopCh)
+			defer close(stopCh)
+
+			err := r.applyBESuppressCPUSet([]int32{0, 1, 2, 3}, []int32{0, 1, 2, 3, 4, 5, 6, 7}, []*statesinformer.PodMeta{exemptedPod})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBEDirCPUSet, helper.ReadCgroupFileContents(beQoSDir, system.CPUSet))
+			assert.Equal(t, tt.wantExemptedCPUSet, helper.ReadCgroupFileContents(exemptedPod.CgroupDir, system.CPUSet))
+			assert.Equal(t, tt.wantSuppressedCPUSet, helper.ReadCgroupFileContents(suppressedPod.CgroupDir, system.CPUSet))
+			assert.Equal(t, tt.wantExemptedContainer, helper.ReadCgroupFileContents(filepath.Join(exemptedPod.CgroupDir, "container1"), system.CPUSet))
+			assert.Equal(t, tt.wantSuppressedContaner, helper.ReadCgroupFileContents(filepath.Join(suppressedPod.CgroupDir, "container2"), system.CPUSet))
+		})
+	}
+}
+
+func Test_getExemptedPodsCFSQuota(t *testing.T) {
+	assert.Equal(t, int64(0), getExemptedPodsCFSQuota(nil))
+	// the limit is reserved for the limited pod, and the request for the unlimited pod
+	assert.Equal(t, 6*cfsPeriod, getExemptedPodsCFSQuota([]*statesinformer.PodMeta{
+		testingBEPodMeta("pod1", "backup", nil, 1000, 4000),
+		testingBEPodMeta("pod2", "backup", nil, 2000, 0),
+		testingBEPodMeta("pod3", "backup", nil, 0, 0),
+	}))
+}
+
+func TestCPUSuppress_adjustByCfsQuotaWithExemptions(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	beQoSDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	exemptedPod := testingBEPodMeta("pod1", "backup", nil, 1000, 4000)
+	suppressedPod := testingBEPodMeta("pod2", "default", nil, 1000, 0)
+	allPods := []*statesinformer.PodMeta{exemptedPod, suppressedPod}
+	helper.WriteCgroupFileContents(beQoSDir, system.CPUCFSQuota, strconv.FormatInt(10*cfsPeriod, 10))
+	for _, podMeta := range allPods {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, system.CPUCFSQuota, "-1")
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node0",
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("80"),
+			},
+		},
+	}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	si := mockstatesinformer.NewMockStatesInformer(ctl)
+	si.EXPECT().GetAllPods().Return(allPods).AnyTimes()
+	r := newTestCPUSuppress(&framework.Options{
+		StatesInformer:      si,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	})
+	stopCh := make(chan struct{})
+	r.executor.Run(stopCh)
+	defer close(stopCh)
+
+	// the BE QoS cgroup is enlarged with the quota of the exempted pod, and the pod-level quota is untouched
+	r.adjustByCfsQuota(resource.NewQuantity(2, resource.DecimalSI), node, []*statesinformer.PodMeta{exemptedPod})
+	assert.Equal(t, strconv.FormatInt(6*cfsPeriod, 10), helper.ReadCgroupFileContents(beQoSDir, system.CPUCFSQuota))
+	assert.Equal(t, "-1", helper.ReadCgroupFileContents(exemptedPod.CgroupDir, system.CPUCFSQuota))
+	assert.Equal(t, "-1", helper.ReadCgroupFileContents(suppressedPod.CgroupDir, system.CPUCFSQuota))
+
+	// no pod is exempted
+	r.adjustByCfsQuota(resource.NewQuantity(2, resource.DecimalSI), node, nil)
+	assert.Equal(t, strconv.FormatInt(2*cfsPeriod, 10), helper.ReadCgroupFileContents(beQoSDir, system.CPUCFSQuota))
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+)
+
+// applyCPUStealDiscount deducts the CPU stolen by the hypervisor from the BE suppress cpu, since the stolen CPU time
+// is not counted in the node usage but is unavailable for the pods. It skips if the cpu steal is not collected.
+func (r *CPUSuppress) applyCPUStealDiscount(suppressCPU *resource.Quantity, node *corev1.Node, discountPercent int64) *resource.Quantity {
+	queryMeta, err := metriccache.NodeCPUStealMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("build node cpu steal query meta failed, error: %v", err)
+		return suppressCPU
+	}
+	stealRatio, err := helpers.CollectorNodeMetricLast(r.metricCache, queryMeta, r.metricCollectInterval)
+	if err != nil {
+		klog.V(5).Infof("query node cpu steal metrics failed, skip the discount, error: %v", err)
+		return suppressCPU
+	}
+	return discountCPUStealCapacity(suppressCPU, node, stealRatio, discountPercent)
+}
+
+// discountCPUStealCapacity returns suppress(BE) - node.Capacity * stealRatio * discountPercent / 100.
+func discountCPUStealCapacity(suppressCPU *resource.Quantity, node *corev1.Node, stealRatio float64, discountPercent int64) *resource.Quantity {
+	if stealRatio <= 0 || discountPercent <= 0 {
+		return suppressCPU
+	}
+	stealMilliCPU := int64(float64(node.Status.Capacity.Cpu().MilliValue()) * stealRatio * float64(discountPercent) / 100)
+	discounted := suppressCPU.DeepCopy()
+	discounted.Sub(*resource.NewMilliQuantity(stealMilliCPU, resource.DecimalSI))
+	klog.V(4).Infof("nodeSuppressBE[CPU(Core)]:%v = %v - node.Total:%v * stealRatio:%v * discount:%v%%",
+		discounted.AsApproximateFloat64(), suppressCPU.AsApproximateFloat64(), node.Status.Capacity.Cpu().AsApproximateFloat64(),
+		stealRatio, discountPercent)
+	return &discounted
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_discountCPUStealCapacity(t *testing.T) {
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("20"),
+			},
+		},
+	}
+	tests := []struct {
+		name            string
+		suppressCPU     resource.Quantity
+		stealRatio      float64
+		discountPercent int64
+		want            int64
+	}{
+		{
+			name:            "no steal",
+			suppressCPU:     resource.MustParse("10"),
+			stealRatio:      0,
+			discountPercent: 100,
+			want:            10000,
+		},
+		{
+			name:            "discount disabled",
+			suppressCPU:     resource.MustParse("10"),
+			stealRatio:      0.2,
+			discountPercent: 0,
+			want:            10000,
+		},
+		{
+			name:            "deduct all the stolen cpus",
+			suppressCPU:     resource.MustParse("10"),
+			stealRatio:      0.2,
+			discountPercent: 100,
+			want:            6000,
+		},
+		{
+			name:            "deduct half of the stolen cpus",
+			suppressCPU:     resource.MustParse("10"),
+			stealRatio:      0.2,
+			discountPercent: 50,
+			want:            8000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := discountCPUStealCapacity(&tt.suppressCPU, node, tt.stealRatio, tt.discountPercent)
+			assert.Equal(t, tt.want, got.MilliValue())
+		})
+	}
+}
@@ -790,6 +790,8 @@ func Test_cpuSuppress_calculateBESuppressCPU(t *testing.T) {
 		nodeUsedCPU        float64
 		podMetrics         map[string]float64
 		podMetas           []*statesinformer.PodMeta
+		hostAppMetrics     map[string]float64
+		hostApps           []slov1alpha1.HostApplicationSpec
 		beCPUUsedThreshold int64
 	}
 	tests := []struct {
@@ -953,6 +955,83 @@ func Test_cpuSuppress_calculateBESuppressCPU(t *testing.T) {
 			},
 			want: resource.NewQuantity(4, resource.DecimalSI),
 		},
+		{
+			name: "calculate be suppress cpus correctly with host applications",
+			args: args{
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node0",
+					},
+					Status: corev1.NodeStatus{
+						Allocatable: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("20"),
+							corev1.ResourceMemory: resource.MustParse("40G"),
+						},
+						Capacity: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("20"),
+							corev1.ResourceMemory: resource.MustParse("40G"),
+						},
+					},
+				},
+				nodeUsedCPU: 12,
+				podMetrics:  map[string]float64{"abc": 6, "def": 2},
+				podMetas: []*statesinformer.PodMeta{
+					{
+						Pod: &corev1.Pod{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "podA",
+								UID:  "abc",
+								Labels: map[string]string{
+									apiext.LabelPodQoS: string(apiext.QoSLS),
+								},
+							},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Resources: corev1.ResourceRequirements{
+											Requests: corev1.ResourceList{
+												corev1.ResourceCPU: resource.MustParse("6"),
+											},
+										},
+									},
+								},
+							},
+							Status: corev1.PodStatus{
+								Phase: corev1.PodRunning,
+							},
+						},
+					},
+					{
+						Pod: &corev1.Pod{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "podB",
+								UID:  "def",
+								Labels: map[string]string{
+									apiext.LabelPodQoS: string(apiext.QoSBE),
+								},
+							},
+							Status: corev1.PodStatus{
+								Phase: corev1.PodRunning,
+							},
+						},
+					},
+				},
+				hostAppMetrics: map[string]float64{"ls-app": 2, "be-app": 1, "unknown-app": 4},
+				hostApps: []slov1alpha1.HostApplicationSpec{
+					{
+						Name: "ls-app",
+						QoS:  apiext.QoSLS,
+					},
+					{
+						Name: "be-app",
+						QoS:  apiext.QoSBE,
+					},
+				},
+				beCPUUsedThreshold: 70,
+			},
+			// the be host application is not throttled by the be cgroups, so it is kept in the system usage
+			want: resource.NewQuantity(4, resource.DecimalSI),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -962,7 +1041,7 @@ func Test_cpuSuppress_calculateBESuppressCPU(t *testing.T) {
 			}
 			cpuSuppress := newTestCPUSuppress(opt)
 			got := cpuSuppress.calculateBESuppressCPU(tt.args.node, tt.args.nodeUsedCPU, tt.args.podMetrics, tt.args.podMetas,
-				tt.args.beCPUUsedThreshold)
+				tt.args.hostAppMetrics, tt.args.hostApps, tt.args.beCPUUsedThreshold)
 			assert.Equal(t, tt.want.MilliValue(), got.MilliValue())
 		})
 	}
@@ -1335,7 +1414,7 @@ func Test_cpuSuppress_applyCPUSetWithNonePolicy(t *testing.T) {
 		r.init(stop)
 	})
 
-	err = r.applyCPUSetWithNonePolicy(cpuset, oldCPUSet)
+	err = r.applyCPUSetWithNonePolicy(cpuset, oldCPUSet, nil)
 	assert.NoError(t, err)
 	gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 	assert.Equal(t, wantCPUSetStr, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1436,7 +1515,7 @@ func Test_cpuSuppress_adjustByCPUSet(t *testing.T) {
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo, nil)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1548,7 +1627,7 @@ func Test_cpuSuppress_adjustByCPUSet_withCPUsReservedFromNodeAnno(t *testing.T)
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo, nil)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1650,7 +1729,7 @@ func Test_cpuSuppress_adjustByCPUSet_withSystemQOSResourceFromNodeAnno(t *testin
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo, nil)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1727,7 +1806,7 @@ func Test_cpuSuppress_adjustByCfsQuota(t *testing.T) {
 			assert.NotPanics(t, func() {
 				r.init(stop)
 			})
-			r.adjustByCfsQuota(tt.cpuQuantity, node)
+			r.adjustByCfsQuota(tt.cpuQuantity, node, nil)
 			gotBECfsQuota := helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
 			if gotBECfsQuota != strconv.FormatInt(tt.wantBECfsQuota, 10) {
 				t.Errorf("failed to adjustByCfsQuota, want file %v cfs_quota %v, got %v", system.GetCgroupFilePath(beQosDir, system.CPUCFSQuota), tt.wantBECfsQuota,
@@ -1978,7 +2057,7 @@ func TestCPUSuppress_applyBESuppressCPUSet(t *testing.T) {
 				close(stopCh)
 			}()
 
-			err := r.applyBESuppressCPUSet(tt.args.beCPUSet, tt.args.oldCPUSet)
+			err := r.applyBESuppressCPUSet(tt.args.beCPUSet, tt.args.oldCPUSet, nil)
 
 			assert.NoError(t, err)
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuevict
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	GPUEvictName = "gpuEvict"
+
+	// fullGPUCore is the gpu-core of a whole GPU. The GPU allocated partially is shared with other pods.
+	fullGPUCore = 100
+)
+
+var _ framework.QOSStrategy = &gpuEvictor{}
+
+// gpuEvictor evicts the pods using more GPU memory than allocated on the shared GPUs. The GPU usage of a pod is
+// attributed by the processes running on the GPU, so the over-quota consumer can be told apart from its neighbors.
+type gpuEvictor struct {
+	evictInterval         time.Duration
+	evictCoolingInterval  time.Duration
+	metricCollectInterval time.Duration
+	tolerancePercent      int64
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	evictionManager       *framework.EvictionManager
+	lastEvictTime         time.Time
+}
+
+// podGPUUsage is the GPU usage of a pod on one GPU.
+type podGPUUsage struct {
+	memoryUsed float64
+	coreUsed   float64
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &gpuEvictor{
+		evictInterval:         time.Duration(opt.Config.GPUEvictIntervalSeconds) * time.Second,
+		evictCoolingInterval:  time.Duration(opt.Config.GPUEvictCoolTimeSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		tolerancePercent:      int64(opt.Config.GPUEvictTolerancePercent),
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+	}
+}
+
+func (g *gpuEvictor) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.GPUOverQuotaEvict) &&
+		features.DefaultKoordletFeatureGate.Enabled(features.Accelerators) && g.evictInterval > 0
+}
+
+func (g *gpuEvictor) Setup(ctx *framework.Context) {
+	g.evictionManager = ctx.EvictionManager
+}
+
+func (g *gpuEvictor) Run(stopCh <-chan struct{}) {
+	go wait.Until(g.gpuEvict, g.evictInterval, stopCh)
+}
+
+func (g *gpuEvictor) gpuEvict() {
+	klog.V(5).Infof("starting gpu evict process")
+	defer klog.V(5).Infof("gpu evict process completed")
+
+	gpus := g.getGPUDevices()
+	if len(gpus) <= 0 {
+		klog.V(5).Infof("skip gpu evict, no gpu device found")
+		return
+	}
+
+	metrics.ResetPodGPUUsage()
+	metrics.ResetPodGPUAllocated()
+	var candidates []*framework.EvictionCandidate
+	toRelease := int64(0)
+	for _, podMeta := range g.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		allocations, err := apiext.GetDeviceAllocations(pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to parse device allocations of pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		gpuAllocations := allocations[schedulingv1alpha1.GPU]
+		if len(gpuAllocations) <= 0 {
+			continue
+		}
+		usages := g.getPodGPUUsages(string(pod.UID), gpus)
+		if candidate, overused := checkPodGPUQuota(pod, gpuAllocations, gpus, usages, g.tolerancePercent); candidate != nil {
+			candidates = append(candidates, candidate)
+			toRelease += overused
+		}
+	}
+	if len(candidates) <= 0 {
+		return
+	}
+
+	if time.Now().Before(g.lastEvictTime.Add(g.evictCoolingInterval)) {
+		klog.V(5).Infof("skip gpu evict process, still in evict cooling time")
+		return
+	}
+	node := g.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip gpu evict, Node is nil")
+		return
+	}
+	var exemptions helpers.PodExemptions
+	if nodeSLO := g.statesInformer.GetNodeSLO(); nodeSLO != nil {
+		exemptions = helpers.NewPodExemptions(nodeSLO.Spec.ResourceUsedThresholdWithBE)
+	}
+
+	req := &framework.EvictionRequest{
+		Reason:       resourceexecutor.EvictPodByGPUMemoryOverQuota,
+		Message:      fmt.Sprintf("evict the pods using gpu memory over quota, need to release gpu memory: %v", toRelease),
+		ResourceName: apiext.ResourceGPUMemory,
+		ToRelease:    toRelease,
+		Candidates:   candidates,
+		Exemptions:   exemptions,
+	}
+	_, released := g.evictionManager.Evict(node, req)
+
+	g.lastEvictTime = time.Now()
+	klog.Infof("gpu evict completed, gpuMemoryNeedRelease(%v) gpuMemoryReleased(%v)", toRelease, released)
+}
+
+func (g *gpuEvictor) getGPUDevices() map[int32]koordletutil.GPUDeviceInfo {
+	value, exist := g.metricCache.Get(koordletutil.GPUDeviceType)
+	if !exist {
+		return nil
+	}
+	devices, ok := value.(koordletutil.GPUDevices)
+	if !ok {
+		klog.Errorf("value type error, expect: %T, got %T", koordletutil.GPUDevices{}, value)
+		return nil
+	}
+	gpus := make(map[int32]koordletutil.GPUDeviceInfo, len(devices))
+	for _, device := range devices {
+		gpus[device.Minor] = device
+	}
+	return gpus
+}
+
+// getPodGPUUsages returns the latest GPU usage of the pod on each GPU, keyed by the minor.
+func (g *gpuEvictor) getPodGPUUsages(podUID string, gpus map[int32]koordletutil.GPUDeviceInfo) map[int32]podGPUUsage {
+	usages := map[int32]podGPUUsage{}
+	for minor, gpu := range gpus {
+		properties := metriccache.MetricPropertiesFunc.PodGPU(podUID, strconv.Itoa(int(minor)), gpu.UUID)
+		memQueryMeta, err := metriccache.PodGPUMemUsageMetric.BuildQueryMeta(properties)
+		if err != nil {
+			klog.V(5).Infof("failed to build gpu memory query of pod %s, err: %v", podUID, err)
+			continue
+		}
+		memoryUsed, err := helpers.CollectPodMetricLast(g.metricCache, memQueryMeta, g.metricCollectInterval)
+		if err != nil {
+			// the pod has no process on the gpu
+			continue
+		}
+		usage := podGPUUsage{memoryUsed: memoryUsed}
+		if coreQueryMeta, err := metriccache.PodGPUCoreUsageMetric.BuildQueryMeta(properties); err == nil {
+			usage.coreUsed, _ = helpers.CollectPodMetricLast(g.metricCache, coreQueryMeta, g.metricCollectInterval)
+		}
+		usages[minor] = usage
+	}
+	return usages
+}
+
+// checkPodGPUQuota records the GPU usage of the pod against its allocations, and returns the eviction candidate if
+// the pod uses more GPU memory than allocated plus the tolerance on any shared GPU. It also returns the overused
+// GPU memory in bytes.
+func checkPodGPUQuota(pod *corev1.Pod, allocations []*apiext.DeviceAllocation, gpus map[int32]koordletutil.GPUDeviceInfo,
+	usages map[int32]podGPUUsage, tolerancePercent int64) (*framework.EvictionCandidate, int64) {
+	usedTotal, allocatedTotal, overusedTotal := int64(0), int64(0), int64(0)
+	for _, allocation := range allocations {
+		gpu, ok := gpus[allocation.Minor]
+		if !ok {
+			continue
+		}
+		minor := strconv.Itoa(int(allocation.Minor))
+		allocatedMemory := getAllocatedGPUMemory(allocation, gpu)
+		usage := usages[allocation.Minor]
+		metrics.RecordPodGPUAllocated(string(apiext.ResourceGPUMemory), metrics.UnitByte, pod, minor, float64(allocatedMemory))
+		metrics.RecordPodGPUUsage(string(apiext.ResourceGPUMemory), metrics.UnitByte, pod, minor, usage.memoryUsed)
+		metrics.RecordPodGPUUsage(string(apiext.ResourceGPUCore), metrics.UnitInteger, pod, minor, usage.coreUsed)
+		if gpuCore, ok := allocation.Resources[apiext.ResourceGPUCore]; ok {
+			metrics.RecordPodGPUAllocated(string(apiext.ResourceGPUCore), metrics.UnitInteger, pod, minor, float64(gpuCore.Value()))
+		}
+
+		if !isSharedGPUAllocation(allocation) || allocatedMemory <= 0 {
+			continue
+		}
+		usedMemory := int64(usage.memoryUsed)
+		usedTotal += usedMemory
+		allocatedTotal += allocatedMemory
+		if overused := usedMemory - allocatedMemory*(100+tolerancePercent)/100; overused > 0 {
+			klog.V(4).Infof("pod %s uses gpu memory %v over quota %v on gpu %s", util.GetPodKey(pod), usedMemory, allocatedMemory, minor)
+			overusedTotal += usedMemory - allocatedMemory
+		}
+	}
+	if overusedTotal <= 0 {
+		return nil, 0
+	}
+	return framework.NewEvictionCandidate(pod, usedTotal, allocatedTotal, overusedTotal), overusedTotal
+}
+
+// getAllocatedGPUMemory returns the GPU memory allocated to the pod in bytes, which is specified by the gpu-memory or
+// the gpu-memory-ratio of the GPU.
+func getAllocatedGPUMemory(allocation *apiext.DeviceAllocation, gpu koordletutil.GPUDeviceInfo) int64 {
+	if memory, ok := allocation.Resources[apiext.ResourceGPUMemory]; ok && !memory.IsZero() {
+		return memory.Value()
+	}
+	if ratio, ok := allocation.Resources[apiext.ResourceGPUMemoryRatio]; ok && !ratio.IsZero() {
+		return int64(gpu.MemoryTotal) * ratio.Value() / 100
+	}
+	return 0
+}
+
+// isSharedGPUAllocation returns whether the pod is allocated a part of the GPU, so the GPU is shared with other pods.
+func isSharedGPUAllocation(allocation *apiext.DeviceAllocation) bool {
+	if gpuCore, ok := allocation.Resources[apiext.ResourceGPUCore]; ok {
+		return gpuCore.Value() < fullGPUCore
+	}
+	if ratio, ok := allocation.Resources[apiext.ResourceGPUMemoryRatio]; ok {
+		return ratio.Value() < fullGPUCore
+	}
+	return true
+}
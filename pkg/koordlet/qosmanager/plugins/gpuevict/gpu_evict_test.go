@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuevict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+func Test_checkPodGPUQuota(t *testing.T) {
+	gpus := map[int32]koordletutil.GPUDeviceInfo{
+		0: {UUID: "gpu-0", Minor: 0, MemoryTotal: 16000},
+		1: {UUID: "gpu-1", Minor: 1, MemoryTotal: 16000},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "xxxxxx",
+		},
+	}
+	tests := []struct {
+		name             string
+		allocations      []*apiext.DeviceAllocation
+		usages           map[int32]podGPUUsage
+		tolerancePercent int64
+		wantCandidate    bool
+		wantOverused     int64
+	}{
+		{
+			name: "gpu memory within quota",
+			allocations: []*apiext.DeviceAllocation{
+				{
+					Minor: 0,
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUCore:   *resource.NewQuantity(50, resource.DecimalSI),
+						apiext.ResourceGPUMemory: *resource.NewQuantity(8000, resource.BinarySI),
+					},
+				},
+			},
+			usages: map[int32]podGPUUsage{
+				0: {memoryUsed: 7000, coreUsed: 40},
+			},
+			tolerancePercent: 10,
+		},
+		{
+			name: "gpu memory over quota within tolerance",
+			allocations: []*apiext.DeviceAllocation{
+				{
+					Minor: 0,
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUCore:   *resource.NewQuantity(50, resource.DecimalSI),
+						apiext.ResourceGPUMemory: *resource.NewQuantity(8000, resource.BinarySI),
+					},
+				},
+			},
+			usages: map[int32]podGPUUsage{
+				0: {memoryUsed: 8500},
+			},
+			tolerancePercent: 10,
+		},
+		{
+			name: "gpu memory over quota",
+			allocations: []*apiext.DeviceAllocation{
+				{
+					Minor: 0,
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUCore:   *resource.NewQuantity(50, resource.DecimalSI),
+						apiext.ResourceGPUMemory: *resource.NewQuantity(8000, resource.BinarySI),
+					},
+				},
+			},
+			usages: map[int32]podGPUUsage{
+				0: {memoryUsed: 10000},
+			},
+			tolerancePercent: 10,
+			wantCandidate:    true,
+			wantOverused:     2000,
+		},
+		{
+			name: "gpu memory ratio over quota",
+			allocations: []*apiext.DeviceAllocation{
+				{
+					Minor: 1,
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUCore:        *resource.NewQuantity(25, resource.DecimalSI),
+						apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(25, resource.DecimalSI),
+					},
+				},
+			},
+			usages: map[int32]podGPUUsage{
+				1: {memoryUsed: 6000},
+			},
+			wantCandidate: true,
+			wantOverused:  2000,
+		},
+		{
+			name: "whole gpu is not shared",
+			allocations: []*apiext.DeviceAllocation{
+				{
+					Minor: 0,
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUCore:        *resource.NewQuantity(100, resource.DecimalSI),
+						apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(100, resource.DecimalSI),
+						apiext.ResourceGPUMemory:      *resource.NewQuantity(8000, resource.BinarySI),
+					},
+				},
+			},
+			usages: map[int32]podGPUUsage{
+				0: {memoryUsed: 16000},
+			},
+		},
+		{
+			name: "gpu not found",
+			allocations: []*apiext.DeviceAllocation{
+				{
+					Minor: 2,
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUMemory: *resource.NewQuantity(8000, resource.BinarySI),
+					},
+				},
+			},
+			usages: map[int32]podGPUUsage{
+				2: {memoryUsed: 16000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate, overused := checkPodGPUQuota(pod, tt.allocations, gpus, tt.usages, tt.tolerancePercent)
+			assert.Equal(t, tt.wantCandidate, candidate != nil)
+			assert.Equal(t, tt.wantOverused, overused)
+			if candidate != nil {
+				assert.Equal(t, pod, candidate.Pod)
+				assert.Equal(t, tt.wantOverused, candidate.Release)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package irqsteering
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	IRQSteeringName = "IRQSteering"
+)
+
+type irqSteering struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	executor          resourceexecutor.ResourceUpdateExecutor
+	// steeredIRQs records the affinity of each steered irq before the steering, so it can be restored
+	// after the cpus are no longer isolated or the feature is disabled.
+	steeredIRQs map[string]cpuset.CPUSet
+}
+
+var _ framework.QOSStrategy = &irqSteering{}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &irqSteering{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		steeredIRQs:       map[string]cpuset.CPUSet{},
+	}
+}
+
+// Enabled does not check the feature gate, so the steered irqs can be restored after the feature is disabled.
+func (r *irqSteering) Enabled() bool {
+	return r.reconcileInterval > 0
+}
+
+func (r *irqSteering) Setup(context *framework.Context) {
+}
+
+func (r *irqSteering) Run(stopCh <-chan struct{}) {
+	r.executor.Run(stopCh)
+	go wait.Until(r.reconcile, r.reconcileInterval, stopCh)
+}
+
+func (r *irqSteering) reconcile() {
+	isolatedCPUs := cpuset.NewCPUSet()
+	if features.DefaultKoordletFeatureGate.Enabled(features.IRQSteering) {
+		isolatedCPUs = r.getIsolatedCPUs()
+	}
+	if isolatedCPUs.IsEmpty() && len(r.steeredIRQs) <= 0 {
+		klog.V(5).Infof("no cpus need to isolate from irqs, skip reconcile irq steering")
+		return
+	}
+
+	resources := r.calculateIRQAffinities(isolatedCPUs)
+	r.executor.UpdateBatch(false, resources...)
+	klog.V(5).Infof("finish to reconcile irq steering, isolated cpus %s, updated irqs %d", isolatedCPUs.String(), len(resources))
+}
+
+// getIsolatedCPUs returns the union of the cpus bound by the running pods which require isolating from the irqs.
+func (r *irqSteering) getIsolatedCPUs() cpuset.CPUSet {
+	builder := cpuset.NewCPUSetBuilder()
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		resourceSpec, err := apiext.GetResourceSpec(pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to get resource spec of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		if resourceSpec.IRQSteeringPolicy != apiext.IRQSteeringPolicyIsolated {
+			continue
+		}
+		resourceStatus, err := apiext.GetResourceStatus(pod.Annotations)
+		if err != nil || resourceStatus.CPUSet == "" {
+			continue
+		}
+		cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+		if err != nil {
+			klog.V(4).Infof("failed to parse cpuset %s of pod %s/%s, err: %v", resourceStatus.CPUSet, pod.Namespace, pod.Name, err)
+			continue
+		}
+		builder.Add(cpus.ToSlice()...)
+	}
+	return builder.Result()
+}
+
+// calculateIRQAffinities generates the updaters to remove the isolated cpus from the affinity list of each irq.
+// The irqs whose affinity only contains the isolated cpus are kept unchanged since the kernel rejects an empty mask.
+// The steered irqs get back the cpus which are no longer isolated, and the original affinity is restored once none
+// of its cpus is isolated.
+func (r *irqSteering) calculateIRQAffinities(isolatedCPUs cpuset.CPUSet) []resourceexecutor.ResourceUpdater {
+	entries, err := os.ReadDir(sysutil.GetProcIRQDir())
+	if err != nil {
+		klog.Warningf("failed to read irq dir %s, err: %v", sysutil.GetProcIRQDir(), err)
+		return nil
+	}
+
+	// forget the irqs which are freed
+	existing := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		existing[entry.Name()] = entry.IsDir()
+	}
+	for irq := range r.steeredIRQs {
+		if !existing[irq] {
+			delete(r.steeredIRQs, irq)
+		}
+	}
+
+	var resources []resourceexecutor.ResourceUpdater
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		irq := entry.Name()
+		file := sysutil.GetIRQSMPAffinityListPath(irq)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			klog.V(5).Infof("failed to read smp_affinity_list of irq %s, err: %v", irq, err)
+			continue
+		}
+		current, err := cpuset.Parse(strings.TrimSpace(string(content)))
+		if err != nil {
+			klog.V(4).Infof("failed to parse smp_affinity_list of irq %s, err: %v", irq, err)
+			continue
+		}
+		original, steered := r.steeredIRQs[irq]
+		if !steered {
+			original = current
+		}
+		if original.Intersection(isolatedCPUs).IsEmpty() {
+			if steered {
+				delete(r.steeredIRQs, irq)
+			}
+			if current.Equals(original) {
+				continue
+			}
+		}
+		target := original.Difference(isolatedCPUs)
+		if target.IsEmpty() {
+			klog.V(4).Infof("irq %s is only affine to the isolated cpus %s, skip steering", irq, original.String())
+			continue
+		}
+		if !steered {
+			r.steeredIRQs[irq] = original
+		}
+		if target.Equals(current) {
+			continue
+		}
+		valueStr := target.String()
+		eventHelper := audit.V(3).Node().Reason("irqSteering reconcile").Message("update irq %s smp_affinity_list to : %v", irq, valueStr)
+		resource, err := resourceexecutor.NewCommonDefaultUpdater(file, file, valueStr, eventHelper)
+		if err != nil {
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
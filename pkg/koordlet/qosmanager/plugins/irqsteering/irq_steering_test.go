@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package irqsteering
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func newTestPodMeta(name string, spec, status string, phase corev1.PodPhase) *statesinformer.PodMeta {
+	return &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Annotations: map[string]string{
+					apiext.AnnotationResourceSpec:   spec,
+					apiext.AnnotationResourceStatus: status,
+				},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		},
+	}
+}
+
+func Test_irqSteering_reconcile(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.IRQSteering, true)()
+	newPod := newTestPodMeta
+	tests := []struct {
+		name     string
+		pods     []*statesinformer.PodMeta
+		irqs     map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "no isolated pods",
+			pods: []*statesinformer.PodMeta{
+				newPod("pod-1", `{"preferredCPUBindPolicy":"FullPCPUs"}`, `{"cpuset":"0-3"}`, corev1.PodRunning),
+			},
+			irqs: map[string]string{
+				"10": "0-7",
+			},
+			expected: map[string]string{
+				"10": "0-7",
+			},
+		},
+		{
+			name: "steer irqs away from isolated cpus",
+			pods: []*statesinformer.PodMeta{
+				newPod("pod-1", `{"preferredCPUBindPolicy":"FullPCPUs","irqSteeringPolicy":"Isolated"}`, `{"cpuset":"0-1"}`, corev1.PodRunning),
+				newPod("pod-2", `{"preferredCPUBindPolicy":"FullPCPUs","irqSteeringPolicy":"Isolated"}`, `{"cpuset":"4"}`, corev1.PodRunning),
+				newPod("pod-3", `{"preferredCPUBindPolicy":"FullPCPUs","irqSteeringPolicy":"Isolated"}`, `{"cpuset":"6"}`, corev1.PodSucceeded),
+			},
+			irqs: map[string]string{
+				"10": "0-7",
+				"11": "2-3",
+				"12": "0-1",
+				"13": "1,3-4,6",
+			},
+			expected: map[string]string{
+				"10": "2-3,5-7",
+				"11": "2-3",
+				"12": "0-1",
+				"13": "3,6",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			for irq, affinity := range tt.irqs {
+				helper.WriteProcSubFileContents("irq/"+irq+"/"+sysutil.IRQSMPAffinityList, affinity)
+			}
+			// a non-directory entry must be ignored
+			helper.WriteProcSubFileContents("irq/default_smp_affinity", "ff")
+
+			si := mock_statesinformer.NewMockStatesInformer(ctrl)
+			si.EXPECT().GetAllPods().Return(tt.pods).AnyTimes()
+
+			r := &irqSteering{
+				statesInformer: si,
+				executor:       resourceexecutor.NewResourceUpdateExecutor(),
+				steeredIRQs:    map[string]cpuset.CPUSet{},
+			}
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			r.executor.Run(stopCh)
+			r.reconcile()
+
+			for irq, want := range tt.expected {
+				got := helper.ReadProcSubFileContents("irq/" + irq + "/" + sysutil.IRQSMPAffinityList)
+				assert.Equal(t, want, got, "irq %s", irq)
+			}
+		})
+	}
+}
+
+func Test_irqSteering_restore(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents("irq/10/"+sysutil.IRQSMPAffinityList, "0-7")
+	helper.WriteProcSubFileContents("irq/11/"+sysutil.IRQSMPAffinityList, "0-3")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pods := []*statesinformer.PodMeta{
+		newTestPodMeta("pod-1", `{"irqSteeringPolicy":"Isolated"}`, `{"cpuset":"0-1"}`, corev1.PodRunning),
+		newTestPodMeta("pod-2", `{"irqSteeringPolicy":"Isolated"}`, `{"cpuset":"4-5"}`, corev1.PodRunning),
+	}
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().DoAndReturn(func() []*statesinformer.PodMeta { return pods }).AnyTimes()
+
+	r := &irqSteering{
+		statesInformer: si,
+		executor:       resourceexecutor.NewResourceUpdateExecutor(),
+		steeredIRQs:    map[string]cpuset.CPUSet{},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	r.executor.Run(stopCh)
+	readAffinity := func(irq string) string {
+		return helper.ReadProcSubFileContents("irq/" + irq + "/" + sysutil.IRQSMPAffinityList)
+	}
+
+	disableFeature := featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.IRQSteering, true)
+	r.reconcile()
+	assert.Equal(t, "2-3,6-7", readAffinity("10"))
+	assert.Equal(t, "2-3", readAffinity("11"))
+
+	// the cpus of the terminated pod are given back
+	pods = pods[:1]
+	r.reconcile()
+	assert.Equal(t, "2-7", readAffinity("10"))
+	assert.Equal(t, "2-3", readAffinity("11"))
+
+	// the original affinities are restored after the feature is disabled
+	disableFeature()
+	r.reconcile()
+	assert.Equal(t, "0-7", readAffinity("10"))
+	assert.Equal(t, "0-3", readAffinity("11"))
+	assert.Empty(t, r.steeredIRQs)
+}
@@ -18,7 +18,6 @@ package memoryevict
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +31,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 const (
@@ -48,15 +50,11 @@ type memoryEvictor struct {
 	metricCollectInterval time.Duration
 	statesInformer        statesinformer.StatesInformer
 	metricCache           metriccache.MetricCache
-	evictor               *framework.Evictor
+	cgroupReader          resourceexecutor.CgroupReader
+	evictionManager       *framework.EvictionManager
 	lastEvictTime         time.Time
 }
 
-type podInfo struct {
-	pod     *corev1.Pod
-	memUsed float64
-}
-
 func New(opt *framework.Options) framework.QOSStrategy {
 	return &memoryEvictor{
 		evictInterval:         time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
@@ -64,6 +62,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
 		statesInformer:        opt.StatesInformer,
 		metricCache:           opt.MetricCache,
+		cgroupReader:          opt.CgroupReader,
 	}
 }
 
@@ -72,7 +71,7 @@ func (m *memoryEvictor) Enabled() bool {
 }
 
 func (m *memoryEvictor) Setup(ctx *framework.Context) {
-	m.evictor = ctx.Evictor
+	m.evictionManager = ctx.EvictionManager
 }
 
 func (m *memoryEvictor) Run(stopCh <-chan struct{}) {
@@ -157,63 +156,96 @@ func (m *memoryEvictor) memoryEvict() {
 	)
 
 	memoryNeedRelease := memoryCapacity * (nodeMemoryUsage - lowerPercent) / 100
-	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease)
+	neededNUMANodes := m.getPressuredNUMANodes(*thresholdPercent)
+	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease, neededNUMANodes, helpers.NewPodExemptions(thresholdConfig))
 }
 
-func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[string]float64, memoryNeedRelease int64) {
-	bePodInfos := m.getSortedBEPodInfos(podMetrics)
-	message := fmt.Sprintf("killAndEvictBEPods for node, need to release memory: %v", memoryNeedRelease)
-	memoryReleased := int64(0)
+// getPressuredNUMANodes returns the NUMA nodes whose memory usage without the page cache reaches the threshold.
+func (m *memoryEvictor) getPressuredNUMANodes(thresholdPercent int64) []int {
+	nodeNUMAInfoRaw, exist := m.metricCache.Get(metriccache.NodeNUMAInfoKey)
+	if !exist {
+		klog.V(5).Infof("node NUMA info not found, evict the BE pods on any NUMA node")
+		return nil
+	}
+	nodeNUMAInfo, ok := nodeNUMAInfoRaw.(*koordletutil.NodeNUMAInfo)
+	if !ok || nodeNUMAInfo == nil {
+		klog.Warningf("type error, expect %T, but got %T", koordletutil.NodeNUMAInfo{}, nodeNUMAInfoRaw)
+		return nil
+	}
 
-	var killedPods []*corev1.Pod
-	for _, bePod := range bePodInfos {
-		if memoryReleased >= memoryNeedRelease {
-			break
+	var numaNodes []int
+	for _, numaInfo := range nodeNUMAInfo.NUMAInfos {
+		memInfo := numaInfo.MemInfo
+		if memInfo == nil || memInfo.MemTotal <= 0 {
+			continue
 		}
-
-		killMsg := fmt.Sprintf("%v, kill pod: %v", message, bePod.pod.Name)
-		helpers.KillContainers(bePod.pod, killMsg)
-		killedPods = append(killedPods, bePod.pod)
-		if bePod.memUsed != 0 {
-			memoryReleased += int64(bePod.memUsed)
+		// the per-NUMA meminfo has no MemAvailable, so count the file pages as available
+		available := memInfo.MemFree + memInfo.ActiveFile + memInfo.InactiveFile
+		if available >= memInfo.MemTotal {
+			continue
 		}
+		usagePercent := int64((memInfo.MemTotal - available) * 100 / memInfo.MemTotal)
+		if usagePercent >= thresholdPercent {
+			numaNodes = append(numaNodes, int(numaInfo.NUMANodeID))
+		}
+	}
+	if len(numaNodes) > 0 {
+		klog.V(4).Infof("memory of NUMA nodes %v reaches the threshold(%v)", numaNodes, thresholdPercent)
 	}
+	return numaNodes
+}
 
-	m.evictor.EvictPodsIfNotEvicted(killedPods, node, resourceexecutor.EvictPodByNodeMemoryUsage, message)
+func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[string]float64, memoryNeedRelease int64,
+	neededNUMANodes []int, exemptions helpers.PodExemptions) {
+	req := &framework.EvictionRequest{
+		Reason:          resourceexecutor.EvictPodByNodeMemoryUsage,
+		Message:         fmt.Sprintf("killAndEvictBEPods for node, need to release memory: %v", memoryNeedRelease),
+		ResourceName:    corev1.ResourceMemory,
+		ToRelease:       memoryNeedRelease,
+		NeededNUMANodes: neededNUMANodes,
+		Candidates:      m.getBEPodCandidates(podMetrics, len(neededNUMANodes) > 0),
+		Exemptions:      exemptions,
+	}
+	_, memoryReleased := m.evictionManager.Evict(node, req)
 
 	m.lastEvictTime = time.Now()
 	klog.Infof("killAndEvictBEPods completed, memoryNeedRelease(%v) memoryReleased(%v)", memoryNeedRelease, memoryReleased)
 }
 
-func (m *memoryEvictor) getSortedBEPodInfos(podMetricMap map[string]float64) []*podInfo {
-
-	var bePodInfos []*podInfo
+// getBEPodCandidates returns the BE pods as the eviction candidates. The memory request is not counted since the BE
+// pods request the batch memory, so the ones using more memory are evicted first within the same priority.
+// If some NUMA nodes are under pressure, the memory of the pods on each NUMA node is read from the memory.numa_stat,
+// so the pods whose pages reside on the pressured NUMA nodes are evicted first.
+func (m *memoryEvictor) getBEPodCandidates(podMetricMap map[string]float64, numaPressured bool) []*framework.EvictionCandidate {
+	var candidates []*framework.EvictionCandidate
 	for _, podMeta := range m.statesInformer.GetAllPods() {
 		pod := podMeta.Pod
 		if extension.GetPodQoSClassRaw(pod) == extension.QoSBE {
-			info := &podInfo{
-				pod:     pod,
-				memUsed: podMetricMap[string(pod.UID)],
+			memUsed := int64(podMetricMap[string(pod.UID)])
+			candidate := framework.NewEvictionCandidate(pod, memUsed, 0, memUsed)
+			if numaPressured {
+				candidate.NUMAUsage = m.getPodNUMAMemoryUsage(podMeta)
 			}
-			bePodInfos = append(bePodInfos, info)
+			candidates = append(candidates, candidate)
 		}
 	}
+	return candidates
+}
 
-	sort.Slice(bePodInfos, func(i, j int) bool {
-		// TODO: https://github.com/koordinator-sh/koordinator/pull/65#discussion_r849048467
-		// compare priority > podMetric > name
-		if bePodInfos[i].pod.Spec.Priority != nil && bePodInfos[j].pod.Spec.Priority != nil && *bePodInfos[i].pod.Spec.Priority != *bePodInfos[j].pod.Spec.Priority {
-			return *bePodInfos[i].pod.Spec.Priority < *bePodInfos[j].pod.Spec.Priority
-		}
-		if bePodInfos[i].memUsed != 0 && bePodInfos[j].memUsed != 0 {
-			//return bePodInfos[i].podMetric.MemoryUsed.MemoryWithoutCache.Value() > bePodInfos[j].podMetric.MemoryUsed.MemoryWithoutCache.Value()
-			return bePodInfos[i].memUsed > bePodInfos[j].memUsed
-		} else if bePodInfos[i].memUsed == 0 && bePodInfos[j].memUsed == 0 {
-
-			return bePodInfos[i].pod.Name > bePodInfos[j].pod.Name
-		}
-		return bePodInfos[j].memUsed == 0
-	})
-
-	return bePodInfos
+// getPodNUMAMemoryUsage returns the memory bytes of the pod residing on each NUMA node. It returns nil if the
+// memory.numa_stat is unavailable.
+func (m *memoryEvictor) getPodNUMAMemoryUsage(podMeta *statesinformer.PodMeta) map[int]int64 {
+	if m.cgroupReader == nil || len(podMeta.CgroupDir) <= 0 {
+		return nil
+	}
+	numaStats, err := m.cgroupReader.ReadMemoryNumaStat(podMeta.CgroupDir)
+	if err != nil {
+		klog.V(5).Infof("failed to read memory numa stat of pod %s, err: %v", util.GetPodKey(podMeta.Pod), err)
+		return nil
+	}
+	numaUsage := make(map[int]int64, len(numaStats))
+	for _, numaStat := range numaStats {
+		numaUsage[numaStat.NumaId] = int64(numaStat.PagesNum) * system.PageSize
+	}
+	return numaUsage
 }
@@ -40,9 +40,13 @@ import (
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -60,6 +64,7 @@ func Test_memoryEvict(t *testing.T) {
 		podMetrics         []podMemSample
 		pods               []*corev1.Pod
 		thresholdConfig    *slov1alpha1.ResourceThresholdStrategy
+		nodeNUMAInfo       *koordletutil.NodeNUMAInfo
 		expectEvictPods    []*corev1.Pod
 		expectNotEvictPods []*corev1.Pod
 	}
@@ -335,6 +340,45 @@ func Test_memoryEvict(t *testing.T) {
 				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
 			},
 		},
+		{
+			name: "test_memoryevict_prefer_pods_on_pressured_NUMA_node",
+			node: testutil.MockTestNode("80", "120G"),
+			pods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPodOnNUMANode("test_be_pod_numa0", 100, 0),
+				createMemoryEvictTestPodOnNUMANode("test_be_pod_numa1", 100, 1),
+			},
+			nodeMemUsed: resource.MustParse("100G"),
+			podMetrics: []podMemSample{
+				{UID: "test_ls_pod", MemUsed: resource.MustParse("80G")},
+				{UID: "test_be_pod_numa0", MemUsed: resource.MustParse("10G")},
+				{UID: "test_be_pod_numa1", MemUsed: resource.MustParse("5G")},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      pointer.Bool(true),
+				MemoryEvictThresholdPercent: pointer.Int64(82),
+				MemoryEvictLowerPercent:     pointer.Int64(80),
+			}, // >96G
+			nodeNUMAInfo: &koordletutil.NodeNUMAInfo{
+				NUMAInfos: []koordletutil.NUMAInfo{
+					{
+						NUMANodeID: 0,
+						MemInfo:    &koordletutil.MemInfo{MemTotal: 60 * 1024 * 1024, MemFree: 20 * 1024 * 1024},
+					},
+					{
+						NUMANodeID: 1,
+						MemInfo:    &koordletutil.MemInfo{MemTotal: 60 * 1024 * 1024, MemFree: 1024 * 1024},
+					},
+				},
+			},
+			expectEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPodOnNUMANode("test_be_pod_numa1", 100, 1),
+			},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPodOnNUMANode("test_be_pod_numa0", 100, 0),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -361,6 +405,11 @@ func Test_memoryEvict(t *testing.T) {
 			mockQuerier := mock_metriccache.NewMockQuerier(ctl)
 			mockQuerier.EXPECT().Query(nodeMemQueryMeta, gomock.Any(), gomock.Any()).SetArg(2, *result).Return(nil).AnyTimes()
 			mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+			if tt.nodeNUMAInfo != nil {
+				mockMetricCache.EXPECT().Get(metriccache.NodeNUMAInfoKey).Return(tt.nodeNUMAInfo, true).AnyTimes()
+			} else {
+				mockMetricCache.EXPECT().Get(metriccache.NodeNUMAInfoKey).Return(nil, false).AnyTimes()
+			}
 
 			for _, podMetric := range tt.podMetrics {
 				result := mock_metriccache.NewMockAggregateResult(ctl)
@@ -406,7 +455,7 @@ func Test_memoryEvict(t *testing.T) {
 			}
 			m := New(opt)
 			memoryEvictor := m.(*memoryEvictor)
-			memoryEvictor.Setup(&framework.Context{Evictor: evictor})
+			memoryEvictor.Setup(&framework.Context{Evictor: evictor, EvictionManager: framework.NewEvictionManager(evictor, false)})
 			memoryEvictor.lastEvictTime = time.Now().Add(-30 * time.Second)
 			memoryEvictor.memoryEvict()
 
@@ -457,3 +506,33 @@ func createMemoryEvictTestPod(name string, qosClass apiext.QoSClass, priority in
 		},
 	}
 }
+
+func createMemoryEvictTestPodOnNUMANode(name string, priority int32, numaNode int32) *corev1.Pod {
+	pod := createMemoryEvictTestPod(name, apiext.QoSBE, priority)
+	_ = apiext.SetResourceStatus(pod, &apiext.ResourceStatus{
+		NUMANodeResources: []apiext.NUMANodeResource{{Node: numaNode}},
+	})
+	return pod
+}
+
+func Test_getPodNUMAMemoryUsage(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+	podCgroupDir := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod123.slice"
+	helper.WriteCgroupFileContents(podCgroupDir, system.MemoryNumaStat, "total=300 N0=100 N1=200\nfile=0 N0=0 N1=0\n")
+
+	pod := createMemoryEvictTestPod("test_be_pod", apiext.QoSBE, 100)
+	m := &memoryEvictor{
+		cgroupReader: resourceexecutor.NewCgroupReader(),
+	}
+	got := m.getPodNUMAMemoryUsage(&statesinformer.PodMeta{Pod: pod, CgroupDir: podCgroupDir})
+	assert.Equal(t, map[int]int64{0: 100 * system.PageSize, 1: 200 * system.PageSize}, got)
+
+	// the numa usage is unknown if the memory.numa_stat is unavailable
+	got = m.getPodNUMAMemoryUsage(&statesinformer.PodMeta{Pod: pod, CgroupDir: "kubepods.slice/not-exist"})
+	assert.Nil(t, got)
+	m.cgroupReader = nil
+	got = m.getPodNUMAMemoryUsage(&statesinformer.PodMeta{Pod: pod, CgroupDir: podCgroupDir})
+	assert.Nil(t, got)
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottle
+
+import (
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	MemoryThrottleName = "memoryThrottle"
+
+	// memoryRelaxBufferPercent is the buffer under the threshold to relax the throttling, which avoids the oscillation.
+	memoryRelaxBufferPercent = 2
+	// defaultThrottleStepPercent is the percentage of the pod memory to tighten for each throttle step.
+	defaultThrottleStepPercent = 10
+	// minMemoryHighPercent is the lower bound of the memory.high against the pod memory when the throttling begins.
+	minMemoryHighPercent = 10
+)
+
+var _ framework.QOSStrategy = &memoryThrottler{}
+
+// memoryThrottler tightens the memory.high of the BE pods step by step when the node memory usage exceeds the
+// threshold, so that the kernel reclaims and throttles the BE pods before the memory eviction takes place.
+// The memory.high is relaxed step by step when the node memory usage falls below the threshold.
+type memoryThrottler struct {
+	throttleInterval      time.Duration
+	metricCollectInterval time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	executor              resourceexecutor.ResourceUpdateExecutor
+
+	// throttleLevel is the current throttle step, 0 means not throttled.
+	throttleLevel int64
+	// throttledPods records the memory usage of each throttled pod when the throttling begins, which is the base to
+	// calculate the memory.high of every step. It is keyed by the pod uid.
+	throttledPods map[string]int64
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &memoryThrottler{
+		throttleInterval:      time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		executor:              resourceexecutor.NewResourceUpdateExecutor(),
+		throttledPods:         map[string]int64{},
+	}
+}
+
+func (m *memoryThrottler) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEMemoryThrottle) && m.throttleInterval > 0
+}
+
+func (m *memoryThrottler) Setup(ctx *framework.Context) {
+}
+
+func (m *memoryThrottler) Run(stopCh <-chan struct{}) {
+	m.executor.Run(stopCh)
+	go wait.Until(m.memoryThrottle, m.throttleInterval, stopCh)
+}
+
+func (m *memoryThrottler) memoryThrottle() {
+	klog.V(5).Infof("starting memory throttle process")
+	defer klog.V(5).Infof("memory throttle process completed")
+
+	if sysutil.GetCurrentCgroupVersion() != sysutil.CgroupVersionV2 {
+		klog.V(5).Infof("skip memory throttle, memory.high throttling requires cgroups v2")
+		return
+	}
+
+	nodeSLO := m.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEMemoryThrottle); err != nil {
+		klog.Errorf("failed to acquire memory throttle feature-gate, error: %v", err)
+		return
+	} else if disabled {
+		klog.V(4).Infof("skip memory throttle, disabled in NodeSLO")
+		m.relaxAll()
+		return
+	}
+
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	thresholdPercent := thresholdConfig.MemoryThrottleThresholdPercent
+	if thresholdPercent == nil {
+		klog.V(5).Infof("skip memory throttle, threshold percent is nil")
+		m.relaxAll()
+		return
+	} else if *thresholdPercent < 0 {
+		klog.Warningf("skip memory throttle, threshold percent(%v) should greater than 0", *thresholdPercent)
+		return
+	}
+	stepPercent := int64(defaultThrottleStepPercent)
+	if thresholdConfig.MemoryThrottleStepPercent != nil && *thresholdConfig.MemoryThrottleStepPercent > 0 {
+		stepPercent = *thresholdConfig.MemoryThrottleStepPercent
+	}
+
+	node := m.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip memory throttle, Node is nil")
+		return
+	}
+	memoryCapacity := node.Status.Capacity.Memory().Value()
+	if memoryCapacity <= 0 {
+		klog.Warningf("skip memory throttle, memory capacity(%v) should greater than 0", memoryCapacity)
+		return
+	}
+
+	queryMeta, err := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("skip memory throttle, get node query failed, error: %v", err)
+		return
+	}
+	nodeMemoryUsed, err := helpers.CollectorNodeMetricLast(m.metricCache, queryMeta, m.metricCollectInterval)
+	if err != nil {
+		klog.Warningf("skip memory throttle, get node metrics error: %v", err)
+		return
+	}
+	nodeMemoryUsage := int64(nodeMemoryUsed) * 100 / memoryCapacity
+
+	oldLevel := m.throttleLevel
+	m.throttleLevel = calculateThrottleLevel(m.throttleLevel, nodeMemoryUsage, *thresholdPercent, stepPercent)
+	if oldLevel != m.throttleLevel {
+		klog.Infof("node MemoryUsage(%v): %.2f, throttleThresholdUsage: %.2f, change memory throttle level from %v to %v",
+			nodeMemoryUsed, float64(nodeMemoryUsage)/100, float64(*thresholdPercent)/100, oldLevel, m.throttleLevel)
+	}
+
+	podMetrics := helpers.CollectAllPodMetricsLast(m.statesInformer, m.metricCache, metriccache.PodMemUsageMetric, m.metricCollectInterval)
+	m.throttleBEPods(podMetrics, stepPercent, helpers.NewPodExemptions(thresholdConfig))
+}
+
+// calculateThrottleLevel tightens one step when the node memory usage reaches the threshold, and relaxes one step
+// when the usage is below the threshold minus the buffer. The level keeps unchanged between them.
+func calculateThrottleLevel(level, nodeMemoryUsage, thresholdPercent, stepPercent int64) int64 {
+	maxLevel := (100 - minMemoryHighPercent) / stepPercent
+	if nodeMemoryUsage >= thresholdPercent {
+		level++
+	} else if nodeMemoryUsage < thresholdPercent-memoryRelaxBufferPercent {
+		level--
+	}
+	if level > maxLevel {
+		level = maxLevel
+	}
+	if level < 0 {
+		level = 0
+	}
+	return level
+}
+
+// throttleBEPods updates the memory.high of the BE pods according to the current throttle level.
+// The exempted pods are never throttled.
+func (m *memoryThrottler) throttleBEPods(podMetrics map[string]float64, stepPercent int64, exemptions helpers.PodExemptions) {
+	var resources []resourceexecutor.ResourceUpdater
+	alivePods := map[string]struct{}{}
+	var totalMemoryHigh int64
+	for _, podMeta := range m.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+			continue
+		}
+		podUID := string(pod.UID)
+		alivePods[podUID] = struct{}{}
+
+		baseMemory, throttled := m.throttledPods[podUID]
+		exempted := m.throttleLevel > 0 && exemptions.IsExempted(pod)
+		if exempted {
+			_ = audit.V(3).Pod(pod.Namespace, pod.Name).Reason(MemoryThrottleName).Message("exempted from memory throttle").Do()
+		}
+		if m.throttleLevel <= 0 || exempted {
+			if throttled {
+				// relax to unlimited, the memory.high set by the memory qos is recovered by the cgroup reconcile
+				resources = appendMemoryHighUpdater(resources, podMeta.CgroupDir, sysutil.CgroupMaxValueStr,
+					"relax memory.high of pod %s/%s", pod.Namespace, pod.Name)
+				delete(m.throttledPods, podUID)
+			}
+			continue
+		}
+
+		if !throttled {
+			baseMemory = int64(podMetrics[podUID])
+			if baseMemory <= 0 {
+				klog.V(5).Infof("skip memory throttle for pod %s/%s, memory usage is unknown", pod.Namespace, pod.Name)
+				continue
+			}
+			m.throttledPods[podUID] = baseMemory
+		}
+		memoryHigh := baseMemory * (100 - m.throttleLevel*stepPercent) / 100
+		totalMemoryHigh += memoryHigh
+		resources = appendMemoryHighUpdater(resources, podMeta.CgroupDir, strconv.FormatInt(memoryHigh, 10),
+			"throttle memory.high of pod %s/%s at level %v", pod.Namespace, pod.Name, m.throttleLevel)
+	}
+	for podUID := range m.throttledPods {
+		if _, ok := alivePods[podUID]; !ok {
+			delete(m.throttledPods, podUID)
+		}
+	}
+
+	m.executor.UpdateBatch(false, resources...)
+
+	metrics.RecordBEMemoryThrottleLevel(float64(m.throttleLevel))
+	metrics.RecordBEMemoryThrottledPods(float64(len(m.throttledPods)))
+	metrics.RecordBEMemoryThrottleHighBytes(float64(totalMemoryHigh))
+}
+
+// relaxAll removes the throttling of all the BE pods immediately, e.g. when the strategy is disabled.
+func (m *memoryThrottler) relaxAll() {
+	if m.throttleLevel == 0 && len(m.throttledPods) == 0 {
+		return
+	}
+	m.throttleLevel = 0
+	m.throttleBEPods(nil, defaultThrottleStepPercent, nil)
+}
+
+func appendMemoryHighUpdater(resources []resourceexecutor.ResourceUpdater, cgroupDir, value string,
+	format string, args ...interface{}) []resourceexecutor.ResourceUpdater {
+	eventHelper := audit.V(3).Reason(MemoryThrottleName).Message(format, args...)
+	updater, err := resourceexecutor.NewCommonCgroupUpdater(sysutil.MemoryHighName, cgroupDir, value, eventHelper)
+	if err != nil {
+		klog.V(5).Infof("skip updating memory.high for cgroup %s, err: %v", cgroupDir, err)
+		return resources
+	}
+	return append(resources, updater)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottle
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func Test_calculateThrottleLevel(t *testing.T) {
+	tests := []struct {
+		name            string
+		level           int64
+		nodeMemoryUsage int64
+		want            int64
+	}{
+		{
+			name:            "start to throttle",
+			level:           0,
+			nodeMemoryUsage: 70,
+			want:            1,
+		},
+		{
+			name:            "tighten one more step",
+			level:           3,
+			nodeMemoryUsage: 80,
+			want:            4,
+		},
+		{
+			name:            "keep the max level",
+			level:           9,
+			nodeMemoryUsage: 90,
+			want:            9,
+		},
+		{
+			name:            "keep the level within the buffer",
+			level:           3,
+			nodeMemoryUsage: 69,
+			want:            3,
+		},
+		{
+			name:            "relax one step",
+			level:           3,
+			nodeMemoryUsage: 60,
+			want:            2,
+		},
+		{
+			name:            "not throttled",
+			level:           0,
+			nodeMemoryUsage: 50,
+			want:            0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateThrottleLevel(tt.level, tt.nodeMemoryUsage, 70, 10)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_throttleBEPods(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	bePod := createMemoryThrottleTestPod("test_be_pod", apiext.QoSBE)
+	lsPod := createMemoryThrottleTestPod("test_ls_pod", apiext.QoSLS)
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{bePod, lsPod})
+	for _, podMeta := range podMetas {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemoryHighV2, sysutil.CgroupMaxSymbolStr)
+	}
+	bePodDir, lsPodDir := podMetas[0].CgroupDir, podMetas[1].CgroupDir
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	m := &memoryThrottler{
+		statesInformer: mockStatesInformer,
+		executor:       resourceexecutor.NewTestResourceExecutor(),
+		throttledPods:  map[string]int64{},
+	}
+	podMetrics := map[string]float64{
+		string(bePod.UID): 1000,
+		string(lsPod.UID): 2000,
+	}
+
+	// tighten the BE pod based on its usage when the throttling begins
+	m.throttleLevel = 1
+	m.throttleBEPods(podMetrics, 10, nil)
+	assert.Equal(t, "900", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, sysutil.CgroupMaxSymbolStr, helper.ReadCgroupFileContents(lsPodDir, sysutil.MemoryHighV2))
+
+	podMetrics[string(bePod.UID)] = 900
+	m.throttleLevel = 2
+	m.throttleBEPods(podMetrics, 10, nil)
+	assert.Equal(t, "800", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, int64(1000), m.throttledPods[string(bePod.UID)])
+
+	// relax when the level returns to zero
+	m.throttleLevel = 0
+	m.throttleBEPods(podMetrics, 10, nil)
+	assert.Equal(t, sysutil.CgroupMaxValueStr, helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Empty(t, m.throttledPods)
+}
+
+func Test_throttleBEPodsWithExemptions(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	bePod := createMemoryThrottleTestPod("test_be_pod", apiext.QoSBE)
+	backupPod := createMemoryThrottleTestPod("test_backup_pod", apiext.QoSBE)
+	backupPod.Namespace = "backup"
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{bePod, backupPod})
+	for _, podMeta := range podMetas {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemoryHighV2, sysutil.CgroupMaxSymbolStr)
+	}
+	bePodDir, backupPodDir := podMetas[0].CgroupDir, podMetas[1].CgroupDir
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	m := &memoryThrottler{
+		statesInformer: mockStatesInformer,
+		executor:       resourceexecutor.NewTestResourceExecutor(),
+		throttledPods:  map[string]int64{},
+		throttleLevel:  1,
+	}
+	podMetrics := map[string]float64{
+		string(bePod.UID):     1000,
+		string(backupPod.UID): 1000,
+	}
+
+	// the throttled pod is relaxed once it is exempted
+	m.throttleBEPods(podMetrics, 10, nil)
+	assert.Equal(t, "900", helper.ReadCgroupFileContents(backupPodDir, sysutil.MemoryHighV2))
+
+	exemptions := helpers.NewPodExemptions(&slov1alpha1.ResourceThresholdStrategy{
+		Exemptions: []slov1alpha1.ResourceThresholdExemption{
+			{Namespaces: []string{"backup"}},
+		},
+	})
+	m.throttleBEPods(podMetrics, 10, exemptions)
+	assert.Equal(t, "900", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, sysutil.CgroupMaxValueStr, helper.ReadCgroupFileContents(backupPodDir, sysutil.MemoryHighV2))
+	assert.NotContains(t, m.throttledPods, string(backupPod.UID))
+}
+
+func createMemoryThrottleTestPod(name string, qosClass apiext.QoSClass) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(qosClass),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBurstable,
+		},
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pagecachelimit
+
+import (
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	PageCacheLimitName = "pageCacheLimit"
+
+	// limitInterfacePageCache limits the page cache with the memcg page cache limit of the supportive kernels.
+	limitInterfacePageCache = "pagecache_limit"
+	// limitInterfaceMemoryHigh limits the page cache by setting the memory.high to the anonymous usage plus the limit.
+	limitInterfaceMemoryHigh = "memory_high"
+)
+
+var _ framework.QOSStrategy = &pageCacheLimiter{}
+
+// pageCacheLimiter bounds the page cache of the BE pods, so that the cache-hungry batch jobs cannot evict the hot
+// pages of the LS pods. The limit is removed when the strategy is disabled or the pod is exempted.
+type pageCacheLimiter struct {
+	limitInterval  time.Duration
+	statesInformer statesinformer.StatesInformer
+	cgroupReader   resourceexecutor.CgroupReader
+	executor       resourceexecutor.ResourceUpdateExecutor
+
+	// limitedPods records the interface used to limit each pod, which is keyed by the pod uid.
+	limitedPods map[string]string
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &pageCacheLimiter{
+		limitInterval:  time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
+		statesInformer: opt.StatesInformer,
+		cgroupReader:   resourceexecutor.NewCgroupReader(),
+		executor:       resourceexecutor.NewResourceUpdateExecutor(),
+		limitedPods:    map[string]string{},
+	}
+}
+
+func (p *pageCacheLimiter) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEPageCacheLimit) && p.limitInterval > 0
+}
+
+func (p *pageCacheLimiter) Setup(ctx *framework.Context) {
+}
+
+func (p *pageCacheLimiter) Run(stopCh <-chan struct{}) {
+	p.executor.Run(stopCh)
+	go wait.Until(p.pageCacheLimit, p.limitInterval, stopCh)
+}
+
+func (p *pageCacheLimiter) pageCacheLimit() {
+	klog.V(5).Infof("starting page cache limit process")
+	defer klog.V(5).Infof("page cache limit process completed")
+
+	nodeSLO := p.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEPageCacheLimit); err != nil {
+		klog.Errorf("failed to acquire page cache limit feature-gate, error: %v", err)
+		return
+	} else if disabled {
+		klog.V(4).Infof("skip page cache limit, disabled in NodeSLO")
+		p.relaxAll()
+		return
+	}
+
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	limitPercent := thresholdConfig.BEPageCacheLimitPercent
+	if limitPercent == nil {
+		klog.V(5).Infof("skip page cache limit, limit percent is nil")
+		p.relaxAll()
+		return
+	} else if *limitPercent <= 0 || *limitPercent > 100 {
+		klog.Warningf("skip page cache limit, limit percent(%v) should be in (0, 100]", *limitPercent)
+		return
+	}
+
+	node := p.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip page cache limit, Node is nil")
+		return
+	}
+	memoryCapacity := node.Status.Capacity.Memory().Value()
+	if memoryCapacity <= 0 {
+		klog.Warningf("skip page cache limit, memory capacity(%v) should greater than 0", memoryCapacity)
+		return
+	}
+
+	// the memory throttle also tunes the memory.high of the BE pods, so the memory.high fallback gives way to it
+	memoryHighFallback := !features.DefaultKoordletFeatureGate.Enabled(features.BEMemoryThrottle) ||
+		thresholdConfig.MemoryThrottleThresholdPercent == nil
+	p.limitBEPods(*limitPercent, memoryCapacity, memoryHighFallback, helpers.NewPodExemptions(thresholdConfig))
+}
+
+// limitBEPods limits the page cache of each BE pod to the percentage of its memory limit, and relaxes the pods
+// limited before but exempted now. A zero limitPercent relaxes all the pods.
+func (p *pageCacheLimiter) limitBEPods(limitPercent, memoryCapacity int64, memoryHighFallback bool, exemptions helpers.PodExemptions) {
+	var resources []resourceexecutor.ResourceUpdater
+	alivePods := map[string]struct{}{}
+	limitedCount := map[string]int{}
+	var totalPageCache int64
+	for _, podMeta := range p.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+			continue
+		}
+		podUID := string(pod.UID)
+		alivePods[podUID] = struct{}{}
+
+		limitInterface, limited := p.limitedPods[podUID]
+		exempted := limitPercent > 0 && exemptions.IsExempted(pod)
+		if exempted {
+			_ = audit.V(3).Pod(pod.Namespace, pod.Name).Reason(PageCacheLimitName).Message("exempted from page cache limit").Do()
+		}
+		if limitPercent <= 0 || exempted {
+			if limited {
+				resources = appendRelaxUpdater(resources, podMeta.CgroupDir, limitInterface,
+					"relax page cache limit of pod %s/%s", pod.Namespace, pod.Name)
+				delete(p.limitedPods, podUID)
+			}
+			continue
+		}
+
+		memoryStat, err := p.cgroupReader.ReadMemoryStat(podMeta.CgroupDir)
+		if err != nil {
+			klog.V(5).Infof("skip page cache limit for pod %s/%s, read memory.stat failed, err: %v",
+				pod.Namespace, pod.Name, err)
+			continue
+		}
+		totalPageCache += memoryStat.Cache
+
+		podMemoryLimit := util.GetPodBEMemoryByteLimit(pod)
+		if podMemoryLimit <= 0 {
+			podMemoryLimit = memoryCapacity
+		}
+		pageCacheLimit := podMemoryLimit * limitPercent / 100
+
+		if isPageCacheLimitSupported(podMeta.CgroupDir) {
+			resources = appendUpdater(resources, sysutil.MemoryPagecacheLimitEnableName, podMeta.CgroupDir, "1",
+				"enable page cache limit of pod %s/%s", pod.Namespace, pod.Name)
+			resources = appendUpdater(resources, sysutil.MemoryPagecacheLimitSizeName, podMeta.CgroupDir,
+				strconv.FormatInt(pageCacheLimit, 10), "limit page cache of pod %s/%s", pod.Namespace, pod.Name)
+			limitInterface = limitInterfacePageCache
+		} else if memoryHighFallback {
+			memoryHigh := memoryStat.Usage() + pageCacheLimit
+			if memoryHigh > podMemoryLimit {
+				memoryHigh = podMemoryLimit
+			}
+			resources = appendUpdater(resources, sysutil.MemoryHighName, podMeta.CgroupDir,
+				strconv.FormatInt(memoryHigh, 10), "limit page cache of pod %s/%s by memory.high", pod.Namespace, pod.Name)
+			limitInterface = limitInterfaceMemoryHigh
+		} else {
+			klog.V(5).Infof("skip page cache limit for pod %s/%s, the memory.high is tuned by the memory throttle",
+				pod.Namespace, pod.Name)
+			continue
+		}
+		p.limitedPods[podUID] = limitInterface
+		limitedCount[limitInterface]++
+	}
+	for podUID := range p.limitedPods {
+		if _, ok := alivePods[podUID]; !ok {
+			delete(p.limitedPods, podUID)
+		}
+	}
+
+	p.executor.UpdateBatch(false, resources...)
+
+	metrics.RecordBEPageCacheLimitedPods(limitInterfacePageCache, float64(limitedCount[limitInterfacePageCache]))
+	metrics.RecordBEPageCacheLimitedPods(limitInterfaceMemoryHigh, float64(limitedCount[limitInterfaceMemoryHigh]))
+	metrics.RecordBEPageCacheBytes(float64(totalPageCache))
+}
+
+// relaxAll removes the page cache limit of all the BE pods immediately, e.g. when the strategy is disabled.
+func (p *pageCacheLimiter) relaxAll() {
+	if len(p.limitedPods) == 0 {
+		return
+	}
+	p.limitBEPods(0, 0, false, nil)
+}
+
+// isPageCacheLimitSupported checks if the memcg page cache limit is available, which is only provided by the
+// cgroups v1 of the supportive kernels, e.g. Anolis OS.
+func isPageCacheLimitSupported(cgroupDir string) bool {
+	if sysutil.GetCurrentCgroupVersion() != sysutil.CgroupVersionV1 {
+		return false
+	}
+	supported, msg := sysutil.MemoryPagecacheLimitSize.IsSupported(cgroupDir)
+	if !supported {
+		klog.V(6).Infof("memcg page cache limit is unsupported, msg: %s", msg)
+	}
+	return supported
+}
+
+func appendRelaxUpdater(resources []resourceexecutor.ResourceUpdater, cgroupDir, limitInterface string,
+	format string, args ...interface{}) []resourceexecutor.ResourceUpdater {
+	if limitInterface == limitInterfacePageCache {
+		return appendUpdater(resources, sysutil.MemoryPagecacheLimitEnableName, cgroupDir, "0", format, args...)
+	}
+	// relax to unlimited, the memory.high set by the memory qos is recovered by the cgroup reconcile
+	return appendUpdater(resources, sysutil.MemoryHighName, cgroupDir, sysutil.CgroupMaxValueStr, format, args...)
+}
+
+func appendUpdater(resources []resourceexecutor.ResourceUpdater, resourceType sysutil.ResourceType, cgroupDir, value string,
+	format string, args ...interface{}) []resourceexecutor.ResourceUpdater {
+	eventHelper := audit.V(3).Reason(PageCacheLimitName).Message(format, args...)
+	updater, err := resourceexecutor.NewCommonCgroupUpdater(resourceType, cgroupDir, value, eventHelper)
+	if err != nil {
+		klog.V(5).Infof("skip updating %s for cgroup %s, err: %v", resourceType, cgroupDir, err)
+		return resources
+	}
+	return append(resources, updater)
+}
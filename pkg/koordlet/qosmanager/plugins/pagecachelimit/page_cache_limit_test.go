@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pagecachelimit
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func Test_limitBEPodsWithMemoryHigh(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	bePod := createPageCacheLimitTestPod("test_be_pod", apiext.QoSBE)
+	backupPod := createPageCacheLimitTestPod("test_backup_pod", apiext.QoSBE)
+	backupPod.Namespace = "backup"
+	lsPod := createPageCacheLimitTestPod("test_ls_pod", apiext.QoSLS)
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{bePod, backupPod, lsPod})
+	for _, podMeta := range podMetas {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemoryHighV2, sysutil.CgroupMaxSymbolStr)
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, sysutil.MemoryStatV2,
+			"anon 3000\nfile 2000\ninactive_anon 1000\nactive_anon 2000\ninactive_file 1500\nactive_file 500\nunevictable 0\n")
+	}
+	bePodDir, backupPodDir, lsPodDir := podMetas[0].CgroupDir, podMetas[1].CgroupDir, podMetas[2].CgroupDir
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	p := &pageCacheLimiter{
+		statesInformer: mockStatesInformer,
+		cgroupReader:   resourceexecutor.NewCgroupReader(),
+		executor:       resourceexecutor.NewTestResourceExecutor(),
+		limitedPods:    map[string]string{},
+	}
+
+	// the pods without memory limit are limited against the node memory capacity: 3000 + 10000 * 10%
+	p.limitBEPods(10, 10000, true, nil)
+	assert.Equal(t, "4000", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, "4000", helper.ReadCgroupFileContents(backupPodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, sysutil.CgroupMaxSymbolStr, helper.ReadCgroupFileContents(lsPodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, limitInterfaceMemoryHigh, p.limitedPods[string(bePod.UID)])
+
+	// the limited pod is relaxed once it is exempted
+	exemptions := helpers.NewPodExemptions(&slov1alpha1.ResourceThresholdStrategy{
+		Exemptions: []slov1alpha1.ResourceThresholdExemption{
+			{Namespaces: []string{"backup"}},
+		},
+	})
+	p.limitBEPods(10, 10000, true, exemptions)
+	assert.Equal(t, "4000", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Equal(t, sysutil.CgroupMaxValueStr, helper.ReadCgroupFileContents(backupPodDir, sysutil.MemoryHighV2))
+	assert.NotContains(t, p.limitedPods, string(backupPod.UID))
+
+	// relax all when the strategy is disabled
+	p.relaxAll()
+	assert.Equal(t, sysutil.CgroupMaxValueStr, helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryHighV2))
+	assert.Empty(t, p.limitedPods)
+}
+
+func Test_limitBEPodsWithPageCacheLimit(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+
+	bePod := createPageCacheLimitTestPod("test_be_pod", apiext.QoSBE)
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{bePod})
+	bePodDir := podMetas[0].CgroupDir
+	for _, dir := range []string{sysutil.CgroupPathFormatter.ParentDir, bePodDir} {
+		helper.WriteCgroupFileContents(dir, sysutil.MemoryPagecacheLimitEnable, "0")
+		helper.WriteCgroupFileContents(dir, sysutil.MemoryPagecacheLimitSize, "0")
+	}
+	helper.WriteCgroupFileContents(bePodDir, sysutil.MemoryStat,
+		"total_cache 2000\ntotal_rss 3000\ntotal_inactive_file 1500\ntotal_active_file 500\n"+
+			"total_inactive_anon 1000\ntotal_active_anon 2000\ntotal_unevictable 0\n")
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	p := &pageCacheLimiter{
+		statesInformer: mockStatesInformer,
+		cgroupReader:   resourceexecutor.NewCgroupReader(),
+		executor:       resourceexecutor.NewTestResourceExecutor(),
+		limitedPods:    map[string]string{},
+	}
+
+	p.limitBEPods(20, 10000, true, nil)
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryPagecacheLimitEnable))
+	assert.Equal(t, "2000", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryPagecacheLimitSize))
+	assert.Equal(t, limitInterfacePageCache, p.limitedPods[string(bePod.UID)])
+
+	p.relaxAll()
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(bePodDir, sysutil.MemoryPagecacheLimitEnable))
+	assert.Empty(t, p.limitedPods)
+}
+
+func createPageCacheLimitTestPod(name string, qosClass apiext.QoSClass) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(qosClass),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBurstable,
+		},
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preferredcpuset
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	PreferredCPUSetName = "PreferredCPUSet"
+
+	// usageWindowSeconds is the window to average the pod cpu usage.
+	usageWindowSeconds = 10
+	// widenUsageThresholdRatio is the ratio of the pod usage to the preferred cpus, above which the cpuset of the
+	// pod is widened to the share pool.
+	widenUsageThresholdRatio = 0.8
+	// narrowUsageThresholdRatio is the ratio of the pod usage to the preferred cpus, below which the widened cpuset
+	// of the pod is narrowed back to the preferred cpus.
+	narrowUsageThresholdRatio = 0.5
+)
+
+type preferredCPUSet struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	metricCache       metriccache.MetricCache
+	executor          resourceexecutor.ResourceUpdateExecutor
+	// widenedPods records the uid of the pods whose cpuset is widened to the share pool.
+	widenedPods map[string]bool
+}
+
+var _ framework.QOSStrategy = &preferredCPUSet{}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &preferredCPUSet{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		metricCache:       opt.MetricCache,
+		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		widenedPods:       map[string]bool{},
+	}
+}
+
+func (p *preferredCPUSet) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.PreferredCPUSet) && p.reconcileInterval > 0
+}
+
+func (p *preferredCPUSet) Setup(context *framework.Context) {
+}
+
+func (p *preferredCPUSet) Run(stopCh <-chan struct{}) {
+	p.executor.Run(stopCh)
+	go wait.Until(p.reconcile, p.reconcileInterval, stopCh)
+}
+
+func (p *preferredCPUSet) reconcile() {
+	nodeTopo := p.statesInformer.GetNodeTopo()
+	if nodeTopo == nil {
+		klog.V(5).Infof("node topology is nil, skip reconcile preferred cpuset")
+		return
+	}
+	sharePools, err := apiext.GetNodeCPUSharePools(nodeTopo.Annotations)
+	if err != nil {
+		klog.Warningf("failed to get cpu share pools, err: %v", err)
+		return
+	}
+	if len(sharePools) == 0 {
+		klog.V(5).Infof("cpu share pools are empty, skip reconcile preferred cpuset")
+		return
+	}
+
+	queryParam := helpers.GenerateQueryParamsAvg(usageWindowSeconds)
+	podsUsage := helpers.CollectAllPodMetrics(p.statesInformer, p.metricCache, *queryParam, metriccache.PodCPUUsageMetric)
+
+	var resources []resourceexecutor.ResourceUpdater
+	widenedPods := map[string]bool{}
+	for _, podMeta := range p.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		pod := podMeta.Pod
+		if apiext.GetPodQoSClassRaw(pod) != apiext.QoSLS {
+			continue
+		}
+		resourceStatus, err := apiext.GetResourceStatus(pod.Annotations)
+		if err != nil || resourceStatus.PreferredCPUSet == "" {
+			continue
+		}
+		preferredCPUs, err := cpuset.Parse(resourceStatus.PreferredCPUSet)
+		if err != nil {
+			klog.V(4).Infof("failed to parse preferred cpuset %s of pod %s/%s, err: %v",
+				resourceStatus.PreferredCPUSet, pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		podUID := string(pod.UID)
+		sharePoolCPUs := getSharePoolCPUs(sharePools, resourceStatus)
+		// the preferred cpus bound by the LSE/LSR pods afterwards are no longer in the share pool
+		preferredCPUs = preferredCPUs.Intersection(sharePoolCPUs)
+		widened := p.widenedPods[podUID]
+		if usage, ok := podsUsage[podUID]; ok {
+			widened = isWidened(widened, usage, preferredCPUs.Size())
+		}
+		target := sharePoolCPUs
+		if !widened && !preferredCPUs.IsEmpty() {
+			target = preferredCPUs
+		}
+		if widened {
+			widenedPods[podUID] = true
+		}
+		if target.IsEmpty() {
+			continue
+		}
+		resources = append(resources, generateContainerCPUSetUpdaters(podMeta, target.String())...)
+	}
+	p.widenedPods = widenedPods
+
+	p.executor.UpdateBatch(true, resources...)
+	klog.V(5).Infof("finish to reconcile preferred cpuset, widened pods %d, updated cgroups %d", len(widenedPods), len(resources))
+}
+
+// isWidened decides whether the cpuset of the pod is widened to the share pool by comparing the pod usage with the
+// preferred cpus. The widened cpuset is narrowed back only when the usage drops far below, to avoid the jitter.
+func isWidened(widened bool, usage float64, numPreferredCPUs int) bool {
+	if numPreferredCPUs <= 0 {
+		return false
+	}
+	if widened {
+		return usage >= float64(numPreferredCPUs)*narrowUsageThresholdRatio
+	}
+	return usage >= float64(numPreferredCPUs)*widenUsageThresholdRatio
+}
+
+// getSharePoolCPUs returns the cpus of the share pools that the pod can use,
+// which are restricted to the allocated NUMA nodes if any.
+func getSharePoolCPUs(sharePools []apiext.CPUSharedPool, resourceStatus *apiext.ResourceStatus) cpuset.CPUSet {
+	numaNodes := map[int32]bool{}
+	for _, numaNodeRes := range resourceStatus.NUMANodeResources {
+		numaNodes[numaNodeRes.Node] = true
+	}
+	builder := cpuset.NewCPUSetBuilder()
+	for _, sharePool := range sharePools {
+		if len(numaNodes) > 0 && !numaNodes[sharePool.Node] {
+			continue
+		}
+		cpus, err := cpuset.Parse(sharePool.CPUSet)
+		if err != nil {
+			klog.V(4).Infof("failed to parse cpuset %s of share pool, err: %v", sharePool.CPUSet, err)
+			continue
+		}
+		builder.Add(cpus.ToSlice()...)
+	}
+	return builder.Result()
+}
+
+func generateContainerCPUSetUpdaters(podMeta *statesinformer.PodMeta, cpusetStr string) []resourceexecutor.ResourceUpdater {
+	pod := podMeta.Pod
+	var resources []resourceexecutor.ResourceUpdater
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(4).Infof("failed to get cgroup dir of container %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		eventHelper := audit.V(3).Container(containerStat.Name).Reason("PreferredCPUSet").Message("update container cpuset: %v", cpusetStr)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, containerDir, cpusetStr, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to create cpuset updater for container %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		resources = append(resources, updater)
+	}
+	return resources
+}
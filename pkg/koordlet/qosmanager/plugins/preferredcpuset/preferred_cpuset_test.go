@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preferredcpuset
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func Test_isWidened(t *testing.T) {
+	tests := []struct {
+		name             string
+		widened          bool
+		usage            float64
+		numPreferredCPUs int
+		want             bool
+	}{
+		{
+			name:             "no preferred cpus",
+			usage:            4,
+			numPreferredCPUs: 0,
+			want:             false,
+		},
+		{
+			name:             "usage below the widen threshold",
+			usage:            1.5,
+			numPreferredCPUs: 2,
+			want:             false,
+		},
+		{
+			name:             "usage above the widen threshold",
+			usage:            1.6,
+			numPreferredCPUs: 2,
+			want:             true,
+		},
+		{
+			name:             "keep widened above the narrow threshold",
+			widened:          true,
+			usage:            1.2,
+			numPreferredCPUs: 2,
+			want:             true,
+		},
+		{
+			name:             "narrow below the narrow threshold",
+			widened:          true,
+			usage:            0.8,
+			numPreferredCPUs: 2,
+			want:             false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isWidened(tt.widened, tt.usage, tt.numPreferredCPUs))
+		})
+	}
+}
+
+func Test_preferredCPUSet_reconcile(t *testing.T) {
+	newPod := func(name string, qos apiext.QoSClass, status string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				UID:       types.UID(name + "-uid"),
+				Labels: map[string]string{
+					apiext.LabelPodQoS: string(qos),
+				},
+				Annotations: map[string]string{
+					apiext.AnnotationResourceStatus: status,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSBurstable,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "main",
+						ContainerID: "containerd://" + name + "-main",
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		sharePools  string
+		pod         *corev1.Pod
+		usage       float64
+		widenedPods map[string]bool
+		want        string
+		wantWidened bool
+	}{
+		{
+			name:       "apply preferred cpus",
+			sharePools: `[{"socket":0,"node":0,"cpuset":"0-7"}]`,
+			pod:        newPod("pod-1", apiext.QoSLS, `{"preferredCPUSet":"2-3"}`),
+			usage:      1,
+			want:       "2-3",
+		},
+		{
+			name:        "widen to share pool under pressure",
+			sharePools:  `[{"socket":0,"node":0,"cpuset":"0-7"}]`,
+			pod:         newPod("pod-1", apiext.QoSLS, `{"preferredCPUSet":"2-3"}`),
+			usage:       1.8,
+			want:        "0-7",
+			wantWidened: true,
+		},
+		{
+			name:        "keep widened until the usage drops",
+			sharePools:  `[{"socket":0,"node":0,"cpuset":"0-7"}]`,
+			pod:         newPod("pod-1", apiext.QoSLS, `{"preferredCPUSet":"2-3"}`),
+			usage:       1.2,
+			widenedPods: map[string]bool{"pod-1-uid": true},
+			want:        "0-7",
+			wantWidened: true,
+		},
+		{
+			name:       "narrow back to preferred cpus",
+			sharePools: `[{"socket":0,"node":0,"cpuset":"0-7"}]`,
+			pod:        newPod("pod-1", apiext.QoSLS, `{"preferredCPUSet":"2-3"}`),
+			usage:      0.5,
+			want:       "2-3",
+		},
+		{
+			name:       "use share pool if preferred cpus are bound by others",
+			sharePools: `[{"socket":0,"node":0,"cpuset":"4-7"}]`,
+			pod:        newPod("pod-1", apiext.QoSLS, `{"preferredCPUSet":"2-3"}`),
+			usage:      0.5,
+			want:       "4-7",
+		},
+		{
+			name:        "widen to share pool of the allocated NUMA node",
+			sharePools:  `[{"socket":0,"node":0,"cpuset":"0-7"},{"socket":1,"node":1,"cpuset":"8-15"}]`,
+			pod:         newPod("pod-1", apiext.QoSLS, `{"preferredCPUSet":"8-9","numaNodeResources":[{"node":1}]}`),
+			usage:       2,
+			want:        "8-15",
+			wantWidened: true,
+		},
+		{
+			name:       "ignore non-LS pod",
+			sharePools: `[{"socket":0,"node":0,"cpuset":"0-7"}]`,
+			pod:        newPod("pod-1", apiext.QoSBE, `{"preferredCPUSet":"2-3"}`),
+			usage:      1,
+			want:       "0-15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+
+			podMeta := &statesinformer.PodMeta{
+				Pod:       tt.pod,
+				CgroupDir: koordletutil.GetPodCgroupParentDir(tt.pod),
+			}
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, &tt.pod.Status.ContainerStatuses[0])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir, sysutil.CPUSet, "0-15")
+
+			si := mock_statesinformer.NewMockStatesInformer(ctrl)
+			si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+			si.EXPECT().GetNodeTopo().Return(&topov1alpha1.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						apiext.AnnotationNodeCPUSharedPools: tt.sharePools,
+					},
+				},
+			}).AnyTimes()
+
+			mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
+			mockQuerier := mock_metriccache.NewMockQuerier(ctrl)
+			mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctrl)
+			oldFactory := metriccache.DefaultAggregateResultFactory
+			metriccache.DefaultAggregateResultFactory = mockResultFactory
+			defer func() {
+				metriccache.DefaultAggregateResultFactory = oldFactory
+			}()
+			mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+			podQueryMeta, err := metriccache.PodCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod(string(tt.pod.UID)))
+			assert.NoError(t, err)
+			testutil.BuildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podQueryMeta, tt.usage)
+
+			p := &preferredCPUSet{
+				statesInformer: si,
+				metricCache:    mockMetricCache,
+				executor:       resourceexecutor.NewResourceUpdateExecutor(),
+				widenedPods:    tt.widenedPods,
+			}
+			if p.widenedPods == nil {
+				p.widenedPods = map[string]bool{}
+			}
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			p.executor.Run(stopCh)
+			p.reconcile()
+
+			assert.Equal(t, tt.want, helper.ReadCgroupFileContents(containerDir, sysutil.CPUSet))
+			assert.Equal(t, tt.wantWidened, p.widenedPods[string(tt.pod.UID)])
+		})
+	}
+}
@@ -20,10 +20,19 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/blkio"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cgreconcile"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/coldmemoryswap"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/coordinateddrain"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuaffinity"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuburst"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusetverify"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/gpuevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/irqsteering"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memoryevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memorythrottle"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/pagecachelimit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/preferredcpuset"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/sysreconcile"
 )
@@ -32,10 +41,19 @@ var (
 	StrategyPlugins = map[string]framework.QOSStrategyFactory{
 		blkio.BlkIOReconcileName:               blkio.New,
 		cgreconcile.CgroupReconcileName:        cgreconcile.New,
+		coldmemoryswap.ColdMemorySwapOutName:   coldmemoryswap.New,
+		coordinateddrain.CoordinatedDrainName:  coordinateddrain.New,
+		cpuaffinity.CPUAffinityObserveName:     cpuaffinity.New,
 		cpuburst.CPUBurstName:                  cpuburst.New,
 		cpuevict.CPUEvictName:                  cpuevict.New,
+		cpusetverify.CPUSetVerifyName:          cpusetverify.New,
 		cpusuppress.CPUSuppressName:            cpusuppress.New,
+		gpuevict.GPUEvictName:                  gpuevict.New,
+		irqsteering.IRQSteeringName:            irqsteering.New,
 		memoryevict.MemoryEvictName:            memoryevict.New,
+		memorythrottle.MemoryThrottleName:      memorythrottle.New,
+		pagecachelimit.PageCacheLimitName:      pagecachelimit.New,
+		preferredcpuset.PreferredCPUSetName:    preferredcpuset.New,
 		resctrl.ResctrlReconcileName:           resctrl.New,
 		sysreconcile.SystemConfigReconcileName: sysreconcile.New,
 	}
@@ -0,0 +1,186 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestResctrlReconcileWithInjectedFaults(t *testing.T) {
+	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
+	testingBESchemata := "    L3:0=f;1=f\n    MB:0=100;1=100"
+	testingNodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+						Enable: pointer.Bool(true),
+						ResctrlQOS: slov1alpha1.ResctrlQOS{
+							CATRangeStartPercent: pointer.Int64(0),
+							CATRangeEndPercent:   pointer.Int64(30),
+						},
+					},
+				},
+			},
+		},
+	}
+	testingPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod0",
+				UID:  "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "container0",
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container0",
+						ContainerID: "containerd://c0",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/p0",
+	}
+	testingNodeCPUInfo := &metriccache.NodeCPUInfo{
+		BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "7ff"},
+		TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
+	}
+
+	tests := []struct {
+		name   string
+		point  faultinjection.Point
+		fault  faultinjection.Fault
+		verify func(t *testing.T, resctrlDir string)
+	}{
+		{
+			name: "reconcile without faults",
+			verify: func(t *testing.T, resctrlDir string) {
+				schemata, err := os.ReadFile(filepath.Join(resctrlDir, BEResctrlGroup, system.ResctrlSchemataName))
+				assert.NoError(t, err)
+				assert.NotEqual(t, testingBESchemata, string(schemata))
+				tasks, err := os.ReadFile(filepath.Join(resctrlDir, BEResctrlGroup, system.ResctrlTasksName))
+				assert.NoError(t, err)
+				assert.NotEmpty(t, string(tasks))
+			},
+		},
+		{
+			name:  "skip reconciling when resctrl is missing",
+			point: faultinjection.ResctrlMissing,
+			fault: faultinjection.Fault{Err: fmt.Errorf("resctrl is not mounted")},
+			verify: func(t *testing.T, resctrlDir string) {
+				schemata, err := os.ReadFile(filepath.Join(resctrlDir, BEResctrlGroup, system.ResctrlSchemataName))
+				assert.NoError(t, err)
+				assert.Equal(t, testingBESchemata, string(schemata))
+			},
+		},
+		{
+			name:  "skip the container whose tasks can not be read",
+			point: faultinjection.CgroupRead,
+			fault: faultinjection.Fault{TargetContains: testingContainerParentDir, Err: fmt.Errorf("injected read error")},
+			verify: func(t *testing.T, resctrlDir string) {
+				tasks, err := os.ReadFile(filepath.Join(resctrlDir, BEResctrlGroup, system.ResctrlTasksName))
+				assert.NoError(t, err)
+				assert.Empty(t, string(tasks))
+			},
+		},
+		{
+			name:  "tolerate resctrl write errors",
+			point: faultinjection.ResctrlWrite,
+			fault: faultinjection.Fault{Err: fmt.Errorf("injected write error")},
+			verify: func(t *testing.T, resctrlDir string) {
+				schemata, err := os.ReadFile(filepath.Join(resctrlDir, BEResctrlGroup, system.ResctrlSchemataName))
+				assert.NoError(t, err)
+				assert.Equal(t, testingBESchemata, string(schemata))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			defer faultinjection.Reset()
+
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			metricCache := mock_metriccache.NewMockMetricCache(ctrl)
+			statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingPodMeta}).AnyTimes()
+			statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
+			statesInformer.EXPECT().GetNode().Return(nil).AnyTimes()
+			metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(testingNodeCPUInfo, true).AnyTimes()
+			opt := &framework.Options{
+				StatesInformer: statesInformer,
+				MetricCache:    metricCache,
+				Config:         framework.NewDefaultConfig(),
+			}
+
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			sysFSRootDirName := "resctrlReconcile"
+			helper.MkDirAll(sysFSRootDirName)
+			system.Conf.SysFSRootDir = filepath.Join(helper.TempDir, sysFSRootDirName)
+			system.CommonRootDir = ""
+			helper.WriteProcSubFileContents("cpuinfo", "flags		: fpu vme de pse cat_l3 mba")
+			helper.WriteProcSubFileContents("cmdline", "BOOT_IMAGE=/boot/vmlinuz rdt=l3cat,mba")
+			testingPrepareContainerCgroupCPUTasks(t, helper, testingContainerParentDir, "122450\n122454")
+			testingPrepareResctrlL3CatGroups(t, "7ff", "L3:0=7ff;1=7ff\nMB:0=100;1=100", testingBESchemata)
+
+			r := newTestResctrlReconcile(opt)
+			stop := make(chan struct{})
+			r.init(stop)
+			defer close(stop)
+
+			if len(tt.point) > 0 {
+				faultinjection.Inject(tt.point, tt.fault)
+			}
+			assert.NotPanics(t, r.reconcile)
+			tt.verify(t, filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir))
+		})
+	}
+}
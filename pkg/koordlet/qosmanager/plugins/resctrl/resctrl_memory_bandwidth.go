@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// memoryBandwidthResctrlGroupSuffix is the suffix of the resctrl groups for the pods requesting the memory bandwidth.
+// e.g. the LS pods requesting the memory bandwidth are moved into the group "LS-MB", which keeps the l3 cat policy of
+// the LS group and limits the MBA percentage by the total bandwidth requested by the pods in the group.
+const memoryBandwidthResctrlGroupSuffix = "-MB"
+
+func getMemoryBandwidthResctrlGroup(group string) string {
+	return group + memoryBandwidthResctrlGroupSuffix
+}
+
+// getNodeMemoryBandwidth returns the measured maximum memory bandwidth of each NUMA node in MB/s.
+func (r *resctrlReconcile) getNodeMemoryBandwidth() extension.NUMAMemoryBandwidth {
+	node := r.statesInformer.GetNode()
+	if node == nil {
+		return nil
+	}
+	memoryBandwidth, err := extension.GetNodeMemoryBandwidth(node.Annotations)
+	if err != nil {
+		klog.V(4).Infof("failed to parse memory bandwidth of node %s, err: %s", node.Name, err)
+		return nil
+	}
+	return memoryBandwidth
+}
+
+// getL3MemoryBandwidth splits the memory bandwidth of the NUMA nodes into the L3 cache domains by the share of the
+// CPUs of each NUMA node in the domain, since the MBA percentage applies to each domain separately.
+// The node memory bandwidth is divided evenly if the CPUs of the domains are unknown.
+func getL3MemoryBandwidth(cpuTotalInfo koordletutil.CPUTotalInfo, nodeMemoryBandwidth extension.NUMAMemoryBandwidth, l3Num int) []int64 {
+	l3MemoryBandwidth := make([]int64, l3Num)
+	var total int64
+	for l3, processors := range cpuTotalInfo.L3ToCPU {
+		if l3 < 0 || int(l3) >= l3Num {
+			continue
+		}
+		numaNodeCPUs := map[int32]int64{}
+		for _, processor := range processors {
+			numaNodeCPUs[processor.NodeID]++
+		}
+		for numaNode, numCPUs := range numaNodeCPUs {
+			numNUMANodeCPUs := int64(len(cpuTotalInfo.NodeToCPU[numaNode]))
+			if numNUMANodeCPUs <= 0 {
+				continue
+			}
+			l3MemoryBandwidth[l3] += nodeMemoryBandwidth[int(numaNode)] * numCPUs / numNUMANodeCPUs
+		}
+		total += l3MemoryBandwidth[l3]
+	}
+	if total <= 0 && l3Num > 0 {
+		for i := range l3MemoryBandwidth {
+			l3MemoryBandwidth[i] = nodeMemoryBandwidth.Total() / int64(l3Num)
+		}
+	}
+	return l3MemoryBandwidth
+}
+
+// calculateMbaPercentForMemoryBandwidth returns the percentage of the requested memory bandwidth to the memory
+// bandwidth of an L3 cache domain, which is rounded up and no more than 100.
+func calculateMbaPercentForMemoryBandwidth(requested, total int64) int64 {
+	if requested <= 0 || total <= 0 {
+		return 100
+	}
+	percent := (requested*100 + total - 1) / total
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// calculateAndApplyMemoryBandwidthMbPolicyForGroup limits the group in each L3 cache domain by the requested memory
+// bandwidth against the memory bandwidth of the domain, since the pods of the group can run on any of the domains.
+func (r *resctrlReconcile) calculateAndApplyMemoryBandwidthMbPolicyForGroup(group string, requested int64, l3MemoryBandwidth []int64, cpuBasicInfo extension.CPUBasicInfo) error {
+	memBwPercents := make([]string, len(l3MemoryBandwidth))
+	for i, bandwidth := range l3MemoryBandwidth {
+		mbaPercent := calculateMbaPercentForMemoryBandwidth(requested, bandwidth)
+		memBwPercents[i] = calculateMbaPercentForGroup(group, &mbaPercent, cpuBasicInfo)
+		if memBwPercents[i] == "" {
+			return nil
+		}
+	}
+	resource := resourceexecutor.NewResctrlMbSchemataResourceByDomain(group, memBwPercents)
+	isUpdated, err := r.executor.Update(true, resource)
+	if err != nil {
+		klog.Warningf("failed to write mba policy on schemata for group %s, err: %s", group, err)
+		return err
+	}
+	klog.V(5).Infof("apply mba policy for group %s finished, schemata %v, isUpdated %v", group, memBwPercents, isUpdated)
+	return nil
+}
+
+func (r *resctrlReconcile) reconcileMemoryBandwidthGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy, nodeMemoryBandwidth extension.NUMAMemoryBandwidth) {
+	// 1. sum the memory bandwidth requests of the pods for each memory bandwidth group
+	// 2. apply the l3 cat policy of the QoS group and the MBA percentage of the requests onto the group
+	// 3. add the task ids of the pods in the group
+	if nodeMemoryBandwidth.Total() <= 0 {
+		return
+	}
+
+	requests := map[string]int64{}
+	podMetas := map[string][]*statesinformer.PodMeta{}
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		request := extension.GetPodMemoryBandwidthRequest(pod)
+		if request <= 0 {
+			continue
+		}
+		if group := getPodResctrlGroupIfEnabled(pod, qosStrategy); group != UnknownResctrlGroup {
+			group = getMemoryBandwidthResctrlGroup(group)
+			requests[group] += request
+			podMetas[group] = append(podMetas[group], podMeta)
+			klog.V(6).Infof("pod %v apply to group %s with memory bandwidth %v", util.GetPodKey(pod), group, request)
+		}
+	}
+	if len(podMetas) <= 0 {
+		return
+	}
+
+	nodeCPUInfo, cbm, l3Num, ok := r.getCatL3Info()
+	if !ok {
+		return
+	}
+	l3MemoryBandwidth := getL3MemoryBandwidth(nodeCPUInfo.TotalInfo, nodeMemoryBandwidth, l3Num)
+
+	for _, qosGroup := range resctrlGroupList {
+		group := getMemoryBandwidthResctrlGroup(qosGroup)
+		groupPodMetas, ok := podMetas[group]
+		if !ok {
+			continue
+		}
+
+		if updated, err := initCatGroupIfNotExist(group); err != nil {
+			klog.Warningf("init cat group dir %v failed, err: %v", group, err)
+			continue
+		} else if updated {
+			klog.V(4).Infof("create cat dir for group %v successfully", group)
+		}
+
+		err := r.calculateAndApplyCatL3PolicyForGroup(group, cbm, l3Num, getResourceQOSForResctrlGroup(qosStrategy, qosGroup))
+		if err != nil {
+			klog.Warningf("failed to apply l3 cat policy for group %v, err: %v", group, err)
+		}
+		err = r.calculateAndApplyMemoryBandwidthMbPolicyForGroup(group, requests[group], l3MemoryBandwidth, nodeCPUInfo.BasicInfo)
+		if err != nil {
+			klog.Warningf("failed to apply cat MB policy for group %v, err: %v", group, err)
+		}
+
+		curTaskMap, err := system.ReadResctrlTasksMap(group)
+		if err != nil {
+			klog.Warningf("failed to read Cat L3 tasks for resctrl group %s, err: %s", group, err)
+		}
+		var taskIds []int32
+		for _, podMeta := range groupPodMetas {
+			taskIds = append(taskIds, r.getPodCgroupNewTaskIds(podMeta, curTaskMap)...)
+		}
+		err = r.calculateAndApplyCatL3GroupTasks(group, taskIds)
+		if err != nil {
+			klog.Warningf("failed to apply l3 cat tasks for group %s, err %s", group, err)
+		}
+	}
+}
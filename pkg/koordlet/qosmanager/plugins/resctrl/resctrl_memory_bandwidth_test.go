@@ -0,0 +1,283 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func Test_calculateMbaPercentForMemoryBandwidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int64
+		total     int64
+		want      int64
+	}{
+		{
+			name:      "no limit for invalid total",
+			requested: 1000,
+			total:     0,
+			want:      100,
+		},
+		{
+			name:      "round up the percentage",
+			requested: 1000,
+			total:     30000,
+			want:      4,
+		},
+		{
+			name:      "exact percentage",
+			requested: 6000,
+			total:     20000,
+			want:      30,
+		},
+		{
+			name:      "no more than 100",
+			requested: 40000,
+			total:     20000,
+			want:      100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, calculateMbaPercentForMemoryBandwidth(tt.requested, tt.total))
+		})
+	}
+}
+
+func TestResctrlReconcile_getNodeMemoryBandwidth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	r := newTestResctrlReconcile(&framework.Options{
+		StatesInformer: statesInformer,
+		Config:         framework.NewDefaultConfig(),
+	})
+
+	statesInformer.EXPECT().GetNode().Return(nil).Times(1)
+	assert.Nil(t, r.getNodeMemoryBandwidth())
+
+	statesInformer.EXPECT().GetNode().Return(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				extension.AnnotationNodeMemoryBandwidth: "invalid",
+			},
+		},
+	}).Times(1)
+	assert.Nil(t, r.getNodeMemoryBandwidth())
+
+	statesInformer.EXPECT().GetNode().Return(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				extension.AnnotationNodeMemoryBandwidth: `{"0": 20000, "1": 20000}`,
+			},
+		},
+	}).Times(1)
+	assert.Equal(t, extension.NUMAMemoryBandwidth{0: 20000, 1: 20000}, r.getNodeMemoryBandwidth())
+}
+
+func Test_getL3MemoryBandwidth(t *testing.T) {
+	tests := []struct {
+		name                string
+		cpuTotalInfo        koordletutil.CPUTotalInfo
+		nodeMemoryBandwidth extension.NUMAMemoryBandwidth
+		l3Num               int
+		want                []int64
+	}{
+		{
+			name: "an L3 cache domain per NUMA node",
+			cpuTotalInfo: koordletutil.CPUTotalInfo{
+				NodeToCPU: map[int32][]koordletutil.ProcessorInfo{
+					0: {{CPUID: 0, NodeID: 0, L3: 0}, {CPUID: 1, NodeID: 0, L3: 0}},
+					1: {{CPUID: 2, NodeID: 1, L3: 1}, {CPUID: 3, NodeID: 1, L3: 1}},
+				},
+				L3ToCPU: map[int32][]koordletutil.ProcessorInfo{
+					0: {{CPUID: 0, NodeID: 0, L3: 0}, {CPUID: 1, NodeID: 0, L3: 0}},
+					1: {{CPUID: 2, NodeID: 1, L3: 1}, {CPUID: 3, NodeID: 1, L3: 1}},
+				},
+			},
+			nodeMemoryBandwidth: extension.NUMAMemoryBandwidth{0: 20000, 1: 40000},
+			l3Num:               2,
+			want:                []int64{20000, 40000},
+		},
+		{
+			name: "an L3 cache domain across the sub-NUMA nodes",
+			cpuTotalInfo: koordletutil.CPUTotalInfo{
+				NodeToCPU: map[int32][]koordletutil.ProcessorInfo{
+					0: {{CPUID: 0, NodeID: 0, L3: 0}},
+					1: {{CPUID: 1, NodeID: 1, L3: 0}},
+				},
+				L3ToCPU: map[int32][]koordletutil.ProcessorInfo{
+					0: {{CPUID: 0, NodeID: 0, L3: 0}, {CPUID: 1, NodeID: 1, L3: 0}},
+				},
+			},
+			nodeMemoryBandwidth: extension.NUMAMemoryBandwidth{0: 20000, 1: 20000},
+			l3Num:               1,
+			want:                []int64{40000},
+		},
+		{
+			name: "L3 cache domains sharing a NUMA node",
+			cpuTotalInfo: koordletutil.CPUTotalInfo{
+				NodeToCPU: map[int32][]koordletutil.ProcessorInfo{
+					0: {{CPUID: 0, NodeID: 0, L3: 0}, {CPUID: 1, NodeID: 0, L3: 1}},
+				},
+				L3ToCPU: map[int32][]koordletutil.ProcessorInfo{
+					0: {{CPUID: 0, NodeID: 0, L3: 0}},
+					1: {{CPUID: 1, NodeID: 0, L3: 1}},
+				},
+			},
+			nodeMemoryBandwidth: extension.NUMAMemoryBandwidth{0: 40000},
+			l3Num:               2,
+			want:                []int64{20000, 20000},
+		},
+		{
+			name: "divide evenly for the unknown CPUs",
+			cpuTotalInfo: koordletutil.CPUTotalInfo{
+				L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}},
+			},
+			nodeMemoryBandwidth: extension.NUMAMemoryBandwidth{0: 20000, 1: 20000},
+			l3Num:               2,
+			want:                []int64{20000, 20000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getL3MemoryBandwidth(tt.cpuTotalInfo, tt.nodeMemoryBandwidth, tt.l3Num))
+		})
+	}
+}
+
+func TestResctrlReconcile_reconcileMemoryBandwidthGroups(t *testing.T) {
+	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
+	testingContainerTasksStr := "122450\n122454"
+	testingPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod0",
+				UID:  "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSLS),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "container0",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								extension.ResourceMemoryBandwidth: resource.MustParse("10000"),
+							},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container0",
+						ContainerID: "containerd://c0",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/p0",
+	}
+	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
+	testQOSStrategy.LSClass.ResctrlQOS.Enable = pointer.Bool(true)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	metricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingPodMeta}).AnyTimes()
+	metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
+		BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "7ff"},
+		TotalInfo: koordletutil.CPUTotalInfo{
+			NodeToCPU: map[int32][]koordletutil.ProcessorInfo{
+				0: {{CPUID: 0, NodeID: 0, L3: 0}},
+				1: {{CPUID: 1, NodeID: 1, L3: 1}},
+			},
+			L3ToCPU: map[int32][]koordletutil.ProcessorInfo{
+				0: {{CPUID: 0, NodeID: 0, L3: 0}},
+				1: {{CPUID: 1, NodeID: 1, L3: 1}},
+			},
+		},
+	}, true).AnyTimes()
+	r := newTestResctrlReconcile(&framework.Options{
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		Config:         framework.NewDefaultConfig(),
+	})
+	stop := make(chan struct{})
+	r.init(stop)
+	defer func() { stop <- struct{}{} }()
+
+	testingPrepareResctrlL3CatGroups(t, "7ff", "L3:0=7ff;1=7ff\n")
+	testingPrepareContainerCgroupCPUTasks(t, helper, testingContainerParentDir, testingContainerTasksStr)
+
+	// skip if the node memory bandwidth is not measured
+	r.reconcileMemoryBandwidthGroups(testQOSStrategy, nil)
+	group := getMemoryBandwidthResctrlGroup(LSResctrlGroup)
+	_, err := os.Stat(system.GetResctrlGroupRootDirPath(group))
+	assert.True(t, os.IsNotExist(err))
+
+	// the kernel creates the schemata and tasks files for the new group
+	groupDir := system.GetResctrlGroupRootDirPath(group)
+	assert.NoError(t, os.MkdirAll(groupDir, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(groupDir, system.ResctrlSchemataName), []byte("    L3:0=7ff;1=7ff\n    MB:0=100;1=100"), 0666))
+	assert.NoError(t, os.WriteFile(filepath.Join(groupDir, system.ResctrlTasksName), []byte{}, 0666))
+
+	// move the pod into the memory bandwidth group, which is limited against the bandwidth of each L3 cache domain
+	r.reconcileMemoryBandwidthGroups(testQOSStrategy, extension.NUMAMemoryBandwidth{0: 20000, 1: 40000})
+	out, err := os.ReadFile(system.ResctrlTasks.Path(group))
+	assert.NoError(t, err)
+	assert.Equal(t, "122450122454", string(out))
+	out, err = os.ReadFile(filepath.Join(groupDir, system.ResctrlSchemataName))
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "MB:0=50;1=30;")
+
+	// the QoS group skips the pod in the memory bandwidth group
+	r.reconcileResctrlGroups(testQOSStrategy, nil, 40000)
+	out, err = os.ReadFile(system.ResctrlTasks.Path(LSResctrlGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(out))
+}
@@ -99,6 +99,9 @@ func (r *resctrlReconcile) Setup(context *framework.Context) {
 
 func (r *resctrlReconcile) Run(stopCh <-chan struct{}) {
 	r.init(stopCh)
+	if features.DefaultKoordletFeatureGate.Enabled(features.ResctrlTaskWatcher) {
+		newResctrlTaskWatcher(r).Run(stopCh)
+	}
 	go wait.Until(r.reconcile, r.reconcileInterval, stopCh)
 }
 
@@ -107,8 +110,11 @@ func (r *resctrlReconcile) init(stopCh <-chan struct{}) {
 }
 
 func getPodResctrlGroup(pod *corev1.Pod) string {
-	podQoS := extension.GetPodQoSClassWithDefault(pod)
-	switch podQoS {
+	return getResctrlGroupByQoSClass(extension.GetPodQoSClassWithDefault(pod))
+}
+
+func getResctrlGroupByQoSClass(qosClass extension.QoSClass) string {
+	switch qosClass {
 	case extension.QoSLSE:
 		return LSRResctrlGroup
 	case extension.QoSLSR:
@@ -384,18 +390,12 @@ func (r *resctrlReconcile) calculateAndApplyCatL3GroupTasks(group string, taskId
 	return nil
 }
 
-func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
-	// 1. retrieve rdt configs from nodeSLOSpec
-	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
-	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
-	// 3. apply the policies onto resctrl groups
-
-	// read cat l3 cbm
-
+// getCatL3Info returns the node cpu info, the cat l3 cbm and the number of l3 caches.
+func (r *resctrlReconcile) getCatL3Info() (*metriccache.NodeCPUInfo, uint, int, bool) {
 	nodeCPUInfoRaw, exist := r.metricCache.Get(metriccache.NodeCPUInfoKey)
 	if !exist {
 		klog.Warning("failed to get nodeCPUInfo, not exist")
-		return
+		return nil, 0, 0, false
 	}
 	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
 	if !ok {
@@ -403,31 +403,44 @@ func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.Re
 	}
 	if nodeCPUInfo == nil {
 		klog.Warning("failed to get nodeCPUInfo, the value is nil")
-		return
+		return nil, 0, 0, false
 	}
 	cbmStr := nodeCPUInfo.BasicInfo.CatL3CbmMask
 	if len(cbmStr) <= 0 {
 		klog.Warning("failed to get cat l3 cbm, cbm is empty")
-		return
+		return nil, 0, 0, false
 	}
 	cbmValue, err := strconv.ParseUint(cbmStr, 16, 32)
 	if err != nil {
 		klog.Warningf("failed to parse cat l3 cbm %s, err: %v", cbmStr, err)
-		return
+		return nil, 0, 0, false
 	}
-	cbm := uint(cbmValue)
 
 	// get the number of l3 caches; it is larger than 0
 	l3Num := len(nodeCPUInfo.TotalInfo.L3ToCPU)
 	if l3Num <= 0 {
 		klog.Warningf("failed to get the number of l3 caches, invalid value %v", l3Num)
+		return nil, 0, 0, false
+	}
+	return nodeCPUInfo, uint(cbmValue), l3Num, true
+}
+
+func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+	// 1. retrieve rdt configs from nodeSLOSpec
+	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
+	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
+	// 3. apply the policies onto resctrl groups
+
+	// read cat l3 cbm
+	nodeCPUInfo, cbm, l3Num, ok := r.getCatL3Info()
+	if !ok {
 		return
 	}
 
 	// calculate and apply l3 cat policy for each group
 	for _, group := range resctrlGroupList {
 		resQoSStrategy := getResourceQOSForResctrlGroup(qosStrategy, group)
-		err = r.calculateAndApplyCatL3PolicyForGroup(group, cbm, l3Num, resQoSStrategy)
+		err := r.calculateAndApplyCatL3PolicyForGroup(group, cbm, l3Num, resQoSStrategy)
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat policy for group %v, err: %v", group, err)
 		}
@@ -438,8 +451,40 @@ func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.Re
 	}
 }
 
-func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
-	// 1. retrieve task ids for each slo by reading cgroup task file of every pod container
+// getPodResctrlGroupIfEnabled returns the resctrl group of the pod if the pod is running or pending and enables the
+// resctrl, otherwise UnknownResctrlGroup.
+func getPodResctrlGroupIfEnabled(pod *corev1.Pod, qosStrategy *slov1alpha1.ResourceQOSStrategy) string {
+	// only Running and Pending pods are considered
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+		return UnknownResctrlGroup
+	}
+
+	// only extension-QoS-specified pod are considered
+	podQoSCfg := helpers.GetPodResourceQoSByQoSClass(pod, qosStrategy)
+	if podQoSCfg.ResctrlQOS.Enable == nil || !(*podQoSCfg.ResctrlQOS.Enable) {
+		klog.V(5).Infof("pod %v with qos %v disabled resctrl", util.GetPodKey(pod), extension.GetPodQoSClassRaw(pod))
+		return UnknownResctrlGroup
+	}
+
+	return getPodResctrlGroup(pod)
+}
+
+// getHostAppResctrlGroupIfEnabled returns the resctrl group of the host application if its qos class enables the
+// resctrl, otherwise UnknownResctrlGroup.
+func getHostAppResctrlGroupIfEnabled(hostApp *slov1alpha1.HostApplicationSpec, qosStrategy *slov1alpha1.ResourceQOSStrategy) string {
+	appQoSCfg := helpers.GetResourceQoSByQoSClass(hostApp.QoS, qosStrategy)
+	if appQoSCfg == nil || appQoSCfg.ResctrlQOS == nil ||
+		appQoSCfg.ResctrlQOS.Enable == nil || !(*appQoSCfg.ResctrlQOS.Enable) {
+		klog.V(5).Infof("host application %v with qos %v disabled resctrl", hostApp.Name, hostApp.QoS)
+		return UnknownResctrlGroup
+	}
+
+	return getResctrlGroupByQoSClass(hostApp.QoS)
+}
+
+func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	hostApps []slov1alpha1.HostApplicationSpec, nodeMemoryBandwidth int64) {
+	// 1. retrieve task ids for each slo by reading cgroup task file of every pod container and host application
 	// 2. add the related task ids in resctrl groups
 
 	// NOTE: pid_max can be found in `/proc/sys/kernel/pid_max` on linux.
@@ -459,26 +504,34 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	podsMeta := r.statesInformer.GetAllPods()
 	for _, podMeta := range podsMeta {
 		pod := podMeta.Pod
-		// only Running and Pending pods are considered
-		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
-			continue
-		}
-
-		// only extension-QoS-specified pod are considered
-		podQoSCfg := helpers.GetPodResourceQoSByQoSClass(pod, qosStrategy)
-		if podQoSCfg.ResctrlQOS.Enable == nil || !(*podQoSCfg.ResctrlQOS.Enable) {
-			klog.V(5).Infof("pod %v with qos %v disabled resctrl", util.GetPodKey(pod), extension.GetPodQoSClassRaw(pod))
+		// the pods requesting the memory bandwidth are reconciled in the memory bandwidth groups
+		if nodeMemoryBandwidth > 0 && extension.GetPodMemoryBandwidthRequest(pod) > 0 {
 			continue
 		}
 
 		// TODO https://github.com/koordinator-sh/koordinator/pull/94#discussion_r858779795
-		if group := getPodResctrlGroup(pod); group != UnknownResctrlGroup {
+		if group := getPodResctrlGroupIfEnabled(pod, qosStrategy); group != UnknownResctrlGroup {
 			ids := r.getPodCgroupNewTaskIds(podMeta, curTaskMaps[group])
 			taskIds[group] = append(taskIds[group], ids...)
 			klog.V(6).Infof("pod %v apply to group %s with %v tasks", util.GetPodKey(pod), group, len(ids))
 		}
 	}
 
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		group := getHostAppResctrlGroupIfEnabled(hostApp, qosStrategy)
+		if group == UnknownResctrlGroup {
+			continue
+		}
+		ids, err := r.getContainerCgroupNewTaskIds(koordletutil.GetHostAppCgroupRelativePath(hostApp), curTaskMaps[group])
+		if err != nil {
+			klog.Warningf("failed to get cgroup task ids for host application %s, err: %s", hostApp.Name, err)
+			continue
+		}
+		taskIds[group] = append(taskIds[group], ids...)
+		klog.V(6).Infof("host application %v apply to group %s with %v tasks", hostApp.Name, group, len(ids))
+	}
+
 	// write Cat L3 tasks for each resctrl group
 	for _, group := range resctrlGroupList {
 		err = r.calculateAndApplyCatL3GroupTasks(group, taskIds[group])
@@ -518,6 +571,8 @@ func (r *resctrlReconcile) reconcile() {
 		klog.V(4).Infof("resctrlReconcile failed, cannot initialize cat resctrl group, err: %s", err)
 		return
 	}
+	nodeMemoryBandwidth := r.getNodeMemoryBandwidth()
 	r.reconcileCatResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
-	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy)
+	r.reconcileMemoryBandwidthGroups(nodeSLO.Spec.ResourceQOSStrategy, nodeMemoryBandwidth)
+	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy, nodeSLO.Spec.HostApplications, nodeMemoryBandwidth.Total())
 }
@@ -1294,7 +1294,7 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		testingPrepareContainerCgroupCPUTasks(t, helper, testingContainer1ParentDir, testingContainer1TasksStr)
 
 		// run reconcileResctrlGroups for BE & LSE tasks not exist
-		r.reconcileResctrlGroups(testQOSStrategy)
+		r.reconcileResctrlGroups(testQOSStrategy, nil, 0)
 
 		// check if the reconciliation is a success
 		out, err := os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
@@ -1310,7 +1310,7 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		assert.NoError(t, err)
 
 		// run reconcileResctrlGroups
-		r.reconcileResctrlGroups(testQOSStrategy)
+		r.reconcileResctrlGroups(testQOSStrategy, nil, 0)
 
 		// check if the reconciliation is a success
 		out, err = os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
@@ -1319,6 +1319,56 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 	})
 }
 
+func TestResctrlReconcile_reconcileResctrlGroupsForHostApps(t *testing.T) {
+	// preparing
+	testingHostApps := []slov1alpha1.HostApplicationSpec{
+		{
+			Name: "test-be-app",
+			QoS:  extension.QoSBE,
+		},
+		{
+			Name: "test-ls-app",
+			QoS:  extension.QoSLS,
+		},
+	}
+	testingBEAppTasksStr := "2001\n2002"
+	testingLSAppTasksStr := "3001\n3002"
+	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
+	testQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(true)
+	testQOSStrategy.LSClass.ResctrlQOS.Enable = pointer.Bool(false)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	opt := &framework.Options{
+		StatesInformer: statesInformer,
+		Config:         framework.NewDefaultConfig(),
+	}
+	r := newTestResctrlReconcile(opt)
+	stop := make(chan struct{})
+	r.init(stop)
+	defer func() { stop <- struct{}{} }()
+
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{}).AnyTimes()
+
+	testingPrepareResctrlL3CatGroups(t, "", "")
+	testingPrepareContainerCgroupCPUTasks(t, helper, koordletutil.GetHostAppCgroupRelativePath(&testingHostApps[0]), testingBEAppTasksStr)
+	testingPrepareContainerCgroupCPUTasks(t, helper, koordletutil.GetHostAppCgroupRelativePath(&testingHostApps[1]), testingLSAppTasksStr)
+
+	r.reconcileResctrlGroups(testQOSStrategy, testingHostApps, 0)
+
+	out, err := os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "20012002", string(out))
+
+	out, err = os.ReadFile(system.ResctrlTasks.Path(LSResctrlGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(out))
+}
+
 func TestResctrlReconcile_reconcile(t *testing.T) {
 	// preparing
 	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
@@ -1395,6 +1445,7 @@ func TestResctrlReconcile_reconcile(t *testing.T) {
 		metricCache := mock_metriccache.NewMockMetricCache(ctrl)
 		statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingPodMeta}).AnyTimes()
 		statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
+		statesInformer.EXPECT().GetNode().Return(nil).AnyTimes()
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(testingNodeCPUInfo, true).AnyTimes()
 		opt := &framework.Options{
 			StatesInformer: statesInformer,
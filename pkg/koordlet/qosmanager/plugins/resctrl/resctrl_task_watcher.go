@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/pleg"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	// taskWatcherRetryInterval is the interval to recheck the container whose tasks have not started yet or whose pod
+	// has not been synced from the kubelet yet.
+	taskWatcherRetryInterval = 200 * time.Millisecond
+	// taskWatcherMaxRetries limits the rechecks of a container, the rest is left to the periodic reconciliation.
+	taskWatcherMaxRetries = 25
+)
+
+type containerCreatedEvent struct {
+	podUID      string
+	containerID string
+	createTime  time.Time
+	retries     int
+}
+
+// resctrlTaskWatcher watches the container creations with the PLEG of the states informer, and adds the tasks of the
+// new or restarted containers into the resctrl groups of their pods promptly. The restarted container gets the new task
+// ids which are not in any resctrl group, so they run without the resctrl policy until the next periodic reconciliation
+// otherwise.
+type resctrlTaskWatcher struct {
+	reconciler *resctrlReconcile
+	queue      workqueue.DelayingInterface
+}
+
+func newResctrlTaskWatcher(reconciler *resctrlReconcile) *resctrlTaskWatcher {
+	return &resctrlTaskWatcher{
+		reconciler: reconciler,
+		queue:      workqueue.NewNamedDelayingQueue("resctrl-task-watcher"),
+	}
+}
+
+func (w *resctrlTaskWatcher) Run(stopCh <-chan struct{}) {
+	// the events after the queue shut down are dropped, and the PLEG stops with the states informer
+	w.reconciler.statesInformer.RegisterPodLifeCycleHandler(pleg.PodLifeCycleHandlerFuncs{
+		ContainerAddedFunc: func(podID, containerID string) {
+			w.queue.Add(containerCreatedEvent{podUID: podID, containerID: containerID, createTime: time.Now()})
+		},
+	})
+	go wait.Until(w.worker, time.Second, stopCh)
+	go func() {
+		<-stopCh
+		w.queue.ShutDown()
+	}()
+	klog.V(4).Infof("resctrl task watcher started")
+}
+
+func (w *resctrlTaskWatcher) worker() {
+	for w.processNextEvent() {
+	}
+}
+
+func (w *resctrlTaskWatcher) processNextEvent() bool {
+	item, quit := w.queue.Get()
+	if quit {
+		return false
+	}
+	defer w.queue.Done(item)
+
+	evt := item.(containerCreatedEvent)
+	if !w.handleContainerCreated(evt) {
+		return true
+	}
+	if evt.retries >= taskWatcherMaxRetries {
+		klog.V(4).Infof("give up adding tasks of container %s of pod %s into resctrl group, leave it to reconcile",
+			evt.containerID, evt.podUID)
+		return true
+	}
+	evt.retries++
+	w.queue.AddAfter(evt, taskWatcherRetryInterval)
+	return true
+}
+
+// handleContainerCreated adds the tasks of the created container into the resctrl group of its pod.
+// It returns true if the container should be rechecked later.
+func (w *resctrlTaskWatcher) handleContainerCreated(evt containerCreatedEvent) bool {
+	r := w.reconciler
+	nodeSLO := r.statesInformer.GetNodeSLO()
+	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
+		return false
+	}
+
+	podMeta := w.getPodMeta(evt.podUID)
+	if podMeta == nil {
+		klog.V(6).Infof("pod %s of container %s not found, wait for the pods synced", evt.podUID, evt.containerID)
+		return true
+	}
+	pod := podMeta.Pod
+	// the pods requesting the memory bandwidth are reconciled in the memory bandwidth groups
+	if r.getNodeMemoryBandwidth().Total() > 0 && extension.GetPodMemoryBandwidthRequest(pod) > 0 {
+		return false
+	}
+	group := getPodResctrlGroupIfEnabled(pod, nodeSLO.Spec.ResourceQOSStrategy)
+	if group == UnknownResctrlGroup {
+		return false
+	}
+
+	containerDir, err := findContainerCgroupDir(podMeta.CgroupDir, evt.containerID)
+	if err != nil {
+		klog.V(5).Infof("failed to find cgroup dir of container %s of pod %s, err: %s", evt.containerID, evt.podUID, err)
+		return false
+	}
+	containerTaskIds, err := r.cgroupReader.ReadCPUTasks(containerDir)
+	if err != nil && resourceexecutor.IsCgroupDirErr(err) {
+		// the container has been removed
+		return false
+	} else if err != nil {
+		klog.V(4).Infof("failed to read task ids of container %s of pod %s, err: %s", evt.containerID, evt.podUID, err)
+		return false
+	}
+	if len(containerTaskIds) <= 0 {
+		// the container process has not started yet
+		return true
+	}
+
+	curTaskMap, err := system.ReadResctrlTasksMap(group)
+	if err != nil {
+		klog.V(4).Infof("failed to read Cat L3 tasks for resctrl group %s, err: %s", group, err)
+		return false
+	}
+	var taskIds []int32
+	for _, id := range containerTaskIds {
+		if _, ok := curTaskMap[id]; !ok {
+			taskIds = append(taskIds, id)
+		}
+	}
+	if err = r.calculateAndApplyCatL3GroupTasks(group, taskIds); err != nil {
+		klog.Warningf("failed to add tasks of container %s of pod %s into resctrl group %s, err: %s",
+			evt.containerID, evt.podUID, group, err)
+		return false
+	}
+
+	lag := time.Since(evt.createTime)
+	metrics.RecordResctrlTaskAssignLag(group, lag.Seconds())
+	metrics.RecordResctrlTaskAssignTasks(group, len(taskIds))
+	klog.V(5).Infof("add %v tasks of container %s of pod %s into resctrl group %s, lag %v",
+		len(taskIds), evt.containerID, evt.podUID, group, lag)
+	return false
+}
+
+func (w *resctrlTaskWatcher) getPodMeta(podUID string) *statesinformer.PodMeta {
+	for _, podMeta := range w.reconciler.statesInformer.GetAllPods() {
+		if podMeta.Pod != nil && string(podMeta.Pod.UID) == podUID {
+			return podMeta
+		}
+	}
+	return nil
+}
+
+// findContainerCgroupDir returns the cgroup dir of the container by looking up the container id under the pod cgroup
+// dir, since the restarted container is not in the pod status until the next sync with the kubelet.
+func findContainerCgroupDir(podCgroupDir string, containerID string) (string, error) {
+	podDir := filepath.Join(system.GetRootCgroupSubfsDir(system.CgroupCPUDir), podCgroupDir)
+	entries, err := os.ReadDir(podDir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if id, err := koordletutil.ParseContainerID(entry.Name()); err == nil && id == containerID {
+			return filepath.Join(podCgroupDir, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("container %s not found in %s", containerID, podDir)
+}
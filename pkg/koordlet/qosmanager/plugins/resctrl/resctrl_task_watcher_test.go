@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func Test_resctrlTaskWatcher_handleContainerCreated(t *testing.T) {
+	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
+	testQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(true)
+	testingPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod0",
+				UID:  "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+		CgroupDir: "kubepods.slice/p0",
+	}
+	testingContainerDir := "kubepods.slice/p0/cri-containerd-c0.scope"
+	type fields struct {
+		pods           []*statesinformer.PodMeta
+		containerTasks *string
+		groupTasks     string
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		wantRetry      bool
+		wantGroupTasks string
+	}{
+		{
+			name: "pod not synced yet",
+			fields: fields{
+				pods: nil,
+			},
+			wantRetry:      true,
+			wantGroupTasks: "",
+		},
+		{
+			name: "container not started yet",
+			fields: fields{
+				pods:           []*statesinformer.PodMeta{testingPodMeta},
+				containerTasks: pointer.String(""),
+			},
+			wantRetry:      true,
+			wantGroupTasks: "",
+		},
+		{
+			name: "container already removed",
+			fields: fields{
+				pods: []*statesinformer.PodMeta{testingPodMeta},
+			},
+			wantRetry:      false,
+			wantGroupTasks: "",
+		},
+		{
+			name: "add new tasks of the restarted container",
+			fields: fields{
+				pods:           []*statesinformer.PodMeta{testingPodMeta},
+				containerTasks: pointer.String("122450\n122454\n123111"),
+				groupTasks:     "122450",
+			},
+			wantRetry:      false,
+			wantGroupTasks: "122450122454123111",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+
+			statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+			statesInformer.EXPECT().GetNodeSLO().Return(&slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceQOSStrategy: testQOSStrategy,
+				},
+			}).AnyTimes()
+			statesInformer.EXPECT().GetAllPods().Return(tt.fields.pods).AnyTimes()
+			statesInformer.EXPECT().GetNode().Return(nil).AnyTimes()
+			r := newTestResctrlReconcile(&framework.Options{
+				StatesInformer: statesInformer,
+				Config:         framework.NewDefaultConfig(),
+			})
+			stop := make(chan struct{})
+			r.init(stop)
+			defer close(stop)
+
+			testingPrepareResctrlL3CatGroups(t, "", "")
+			helper.MkDirAll(filepath.Join(system.GetRootCgroupSubfsDir(system.CgroupCPUDir), testingPodMeta.CgroupDir))
+			if tt.fields.containerTasks != nil {
+				testingPrepareContainerCgroupCPUTasks(t, helper, testingContainerDir, *tt.fields.containerTasks)
+			}
+			if tt.fields.groupTasks != "" {
+				err := os.WriteFile(system.ResctrlTasks.Path(BEResctrlGroup), []byte(tt.fields.groupTasks), 0666)
+				assert.NoError(t, err)
+			}
+
+			w := newResctrlTaskWatcher(r)
+			gotRetry := w.handleContainerCreated(containerCreatedEvent{
+				podUID:      "p0",
+				containerID: "c0",
+				createTime:  time.Now(),
+			})
+			assert.Equal(t, tt.wantRetry, gotRetry)
+			out, err := os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantGroupTasks, string(out))
+		})
+	}
+}
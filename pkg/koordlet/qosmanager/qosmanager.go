@@ -68,8 +68,9 @@ func NewQOSManager(cfg *framework.Config, schema *apiruntime.Scheme, kubeClient
 	}
 
 	ctx := &framework.Context{
-		Evictor:    evictor,
-		Strategies: make(map[string]framework.QOSStrategy, len(plugins.StrategyPlugins)),
+		Evictor:         evictor,
+		EvictionManager: framework.NewEvictionManager(evictor, cfg.EvictionDryRun),
+		Strategies:      make(map[string]framework.QOSStrategy, len(plugins.StrategyPlugins)),
 	}
 
 	for name, strategyFn := range plugins.StrategyPlugins {
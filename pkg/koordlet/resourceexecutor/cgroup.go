@@ -26,6 +26,7 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
@@ -86,6 +87,9 @@ func cgroupFileWrite(cgroupTaskDir string, r sysutil.Resource, value string) err
 
 	filePath := r.Path(cgroupTaskDir)
 	klog.V(5).Infof("write %s [%s]", filePath, value)
+	if err := faultinjection.Trigger(faultinjection.CgroupWrite, filePath); err != nil {
+		return err
+	}
 
 	return os.WriteFile(filePath, []byte(value), 0644)
 }
@@ -127,6 +131,9 @@ func cgroupFileRead(cgroupTaskDir string, r sysutil.Resource) (string, error) {
 
 	filePath := r.Path(cgroupTaskDir)
 	klog.V(6).Infof("read %s", filePath)
+	if err := faultinjection.Trigger(faultinjection.CgroupRead, filePath); err != nil {
+		return "", err
+	}
 
 	data, err := os.ReadFile(filePath)
 	return strings.Trim(string(data), "\n"), err
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+var (
+	// cpusetPropagationTimeout is how long to wait for the kernel propagating the parent cpuset to the children.
+	cpusetPropagationTimeout = 100 * time.Millisecond
+	// cpusetPropagationInterval is the interval to re-check the cpuset of the children.
+	cpusetPropagationInterval = 10 * time.Millisecond
+)
+
+// CPUSetPropagationRecorder records the latency of the cpuset propagation to the child cgroups, and the number of
+// the children not propagated in time which are re-written.
+type CPUSetPropagationRecorder func(latency time.Duration, delayedChildren int)
+
+var recordCPUSetPropagation CPUSetPropagationRecorder = func(time.Duration, int) {}
+
+// SetCPUSetPropagationRecorder sets the recorder of the cpuset propagation check, e.g. the metrics.
+func SetCPUSetPropagationRecorder(recorder CPUSetPropagationRecorder) {
+	if recorder != nil {
+		recordCPUSetPropagation = recorder
+	}
+}
+
+// cpusetPropagationChecking records the cgroups whose cpuset propagation is being checked.
+var cpusetPropagationChecking sync.Map
+
+// checkCPUSetPropagation checks whether the cpuset.cpus written to the cgroup is propagated to its descendant cgroups.
+// On some kernels the descendants keep the CPUs out of the shrunk parent cpuset for a while, so it polls them in the
+// background until the timeout, records the latency, and re-writes the ones still not propagated.
+func checkCPUSetPropagation(updater ResourceUpdater) {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.CPUSetPropagationCheck) {
+		return
+	}
+	c, ok := updater.(*CgroupResourceUpdater)
+	if !ok || c.ResourceType() != sysutil.CPUSetCPUSName {
+		return
+	}
+	// the running check reads the latest parent cpuset, so the newer update needs no more check
+	if _, loaded := cpusetPropagationChecking.LoadOrStore(c.Path(), struct{}{}); loaded {
+		return
+	}
+	go func() {
+		defer cpusetPropagationChecking.Delete(c.Path())
+		waitCPUSetPropagation(c.parentDir, c.file, filepath.Dir(c.Path()))
+	}()
+}
+
+// waitCPUSetPropagation polls the descendants of the cgroup until they are all in the parent cpuset or the timeout,
+// and re-writes the descendants still not propagated with the CPUs in their parents.
+func waitCPUSetPropagation(parentDir string, file sysutil.Resource, absDir string) {
+	reader := NewCgroupReader()
+	parentCPUs, err := reader.ReadCPUSet(parentDir)
+	if err != nil {
+		klog.V(5).Infof("failed to check cpuset propagation of %s, read err: %v", parentDir, err)
+		return
+	}
+	descendants, err := getDescendantCgroupDirs(parentDir, absDir)
+	if err != nil {
+		klog.V(5).Infof("failed to check cpuset propagation of %s, list descendants err: %v", parentDir, err)
+		return
+	}
+	if len(descendants) <= 0 {
+		return
+	}
+
+	start := time.Now()
+	stale := getCPUSetStaleChildren(reader, descendants, *parentCPUs)
+	if len(stale) <= 0 {
+		return
+	}
+	for len(stale) > 0 && time.Since(start) < cpusetPropagationTimeout {
+		time.Sleep(cpusetPropagationInterval)
+		if parentCPUs, err = reader.ReadCPUSet(parentDir); err != nil {
+			klog.V(5).Infof("failed to check cpuset propagation of %s, read err: %v", parentDir, err)
+			return
+		}
+		stale = getCPUSetStaleChildren(reader, stale, *parentCPUs)
+	}
+	latency := time.Since(start)
+	if len(stale) <= 0 {
+		klog.V(5).Infof("cpuset %s of %s propagated to the descendants in %v", parentCPUs.String(), parentDir, latency)
+		recordCPUSetPropagation(latency, 0)
+		return
+	}
+
+	klog.Warningf("cpuset %s of %s not propagated to the descendants %v in %v, re-write them", parentCPUs.String(), parentDir, stale, latency)
+	recordCPUSetPropagation(latency, len(stale))
+	// the stale descendants are in the pre-order, so each one is re-written after its parent
+	for _, child := range stale {
+		if err := rewriteChildCPUSet(reader, file, child); err != nil {
+			klog.V(4).Infof("failed to re-write cpuset of the child cgroup %s, err: %v", child, err)
+		}
+	}
+}
+
+// getDescendantCgroupDirs returns the relative dirs of all the descendant cgroups under the cgroup dir in the pre-order.
+func getDescendantCgroupDirs(parentDir string, absDir string) ([]string, error) {
+	var descendants []string
+	err := filepath.WalkDir(absDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the descendant cgroup may be removed
+			if path != absDir && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() || path == absDir {
+			return nil
+		}
+		rel, err := filepath.Rel(absDir, path)
+		if err != nil {
+			return err
+		}
+		descendants = append(descendants, filepath.Join(parentDir, rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return descendants, nil
+}
+
+// getCPUSetStaleChildren returns the child cgroups whose cpuset is not the subset of the parent cpuset.
+func getCPUSetStaleChildren(reader CgroupReader, children []string, parentCPUs cpuset.CPUSet) []string {
+	var stale []string
+	for _, child := range children {
+		cpus, err := reader.ReadCPUSet(child)
+		if err != nil {
+			// the child cgroup may be removed
+			klog.V(6).Infof("failed to read cpuset of the child cgroup %s, err: %v", child, err)
+			continue
+		}
+		if !cpus.IsSubsetOf(parentCPUs) {
+			stale = append(stale, child)
+		}
+	}
+	return stale
+}
+
+// rewriteChildCPUSet writes the CPUs of the child cgroup in its parent cpuset, or the parent cpuset if none is left.
+func rewriteChildCPUSet(reader CgroupReader, file sysutil.Resource, child string) error {
+	parentCPUs, err := reader.ReadCPUSet(filepath.Dir(child))
+	if err != nil {
+		return err
+	}
+	cpus, err := reader.ReadCPUSet(child)
+	if err != nil {
+		return err
+	}
+	newCPUs := cpus.Intersection(*parentCPUs)
+	if newCPUs.IsEmpty() {
+		newCPUs = *parentCPUs
+	}
+	return cgroupFileWrite(child, file, newCPUs.String())
+}
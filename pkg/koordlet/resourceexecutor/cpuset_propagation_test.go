@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_checkCPUSetPropagation(t *testing.T) {
+	testParentDir := "kubepods.slice/kubepods-besteffort.slice"
+	type record struct {
+		delayedChildren int
+	}
+	tests := []struct {
+		name          string
+		children      map[string]string
+		wantRecords   []record
+		wantCPUSets   map[string]string
+		disableChecks bool
+	}{
+		{
+			name: "children propagated",
+			children: map[string]string{
+				"pod1": "0-1",
+				"pod2": "2-3",
+			},
+			wantCPUSets: map[string]string{
+				"pod1": "0-1",
+				"pod2": "2-3",
+			},
+		},
+		{
+			name: "children not propagated and re-written",
+			children: map[string]string{
+				"pod1": "0-7",
+				"pod2": "4-5",
+				"pod3": "2",
+			},
+			wantRecords: []record{
+				{delayedChildren: 2},
+			},
+			wantCPUSets: map[string]string{
+				"pod1": "0-3",
+				"pod2": "0-3",
+				"pod3": "2",
+			},
+		},
+		{
+			name: "descendants not propagated and re-written",
+			children: map[string]string{
+				"pod1":            "0-7",
+				"pod1/container1": "4-7",
+				"pod1/container2": "1-5",
+				"pod2":            "1-2",
+			},
+			wantRecords: []record{
+				{delayedChildren: 3},
+			},
+			wantCPUSets: map[string]string{
+				"pod1":            "0-3",
+				"pod1/container1": "0-3",
+				"pod1/container2": "1-3",
+				"pod2":            "1-2",
+			},
+		},
+		{
+			name: "check disabled",
+			children: map[string]string{
+				"pod1": "0-7",
+			},
+			wantCPUSets: map[string]string{
+				"pod1": "0-7",
+			},
+			disableChecks: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.CPUSetPropagationCheck, !tt.disableChecks)()
+			oldTimeout := cpusetPropagationTimeout
+			cpusetPropagationTimeout = 0
+			defer func() {
+				cpusetPropagationTimeout = oldTimeout
+			}()
+
+			helper.WriteCgroupFileContents(testParentDir, sysutil.CPUSet, "0-3")
+			for child, cpus := range tt.children {
+				helper.WriteCgroupFileContents(testParentDir+"/"+child, sysutil.CPUSet, cpus)
+			}
+
+			var got []record
+			SetCPUSetPropagationRecorder(func(latency time.Duration, delayedChildren int) {
+				got = append(got, record{delayedChildren: delayedChildren})
+			})
+			defer SetCPUSetPropagationRecorder(func(time.Duration, int) {})
+
+			updater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, testParentDir, "0-3", &audit.EventHelper{})
+			assert.NoError(t, err)
+			checkCPUSetPropagation(updater)
+			assert.Eventually(t, func() bool {
+				_, checking := cpusetPropagationChecking.Load(updater.Path())
+				return !checking
+			}, time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.wantRecords, got)
+			for child, cpus := range tt.wantCPUSets {
+				assert.Equal(t, cpus, helper.ReadCgroupFileContents(testParentDir+"/"+child, sysutil.CPUSet))
+			}
+		})
+	}
+}
@@ -39,6 +39,10 @@ type ResourceUpdateExecutor interface {
 	// 2. update each cgroup resource by the order of layers: firstly update resources from upper to lower by merging
 	//    the new value with old value; then update resources from lower to upper with the new value.
 	LeveledUpdateBatch(updaters [][]ResourceUpdater)
+	// RecoverBatch is to rebuild the cache after the restart. It cacheable updates the resources whose current values
+	// drift from the given ones, and caches the others as updated without writing. It returns the drifted updaters.
+	// The resources which cannot be read back and compared are regarded as unchanged.
+	RecoverBatch(updaters ...ResourceUpdater) []ResourceUpdater
 	Run(stopCh <-chan struct{})
 }
 
@@ -141,6 +145,7 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 
 			if mergedUpdater == nil {
 				skipMerge[updater.Key()] = true
+				verifyUpdate(updater)
 			} else {
 				updater = mergedUpdater
 			}
@@ -175,6 +180,7 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 				continue
 			}
 			klog.V(6).Infof("successfully update resource %s to %v", updater.Key(), updater.Value())
+			verifyUpdate(updater)
 
 			updater.UpdateLastUpdateTimestamp(time.Now())
 			err = e.ResourceCache.SetDefault(updater.Key(), updater)
@@ -186,6 +192,29 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 	}
 }
 
+func (e *ResourceUpdateExecutorImpl) RecoverBatch(updaters ...ResourceUpdater) []ResourceUpdater {
+	if !e.gcStarted {
+		klog.Error("failed to recover resources, err: cache GC is not started")
+		return nil
+	}
+
+	var drifted []ResourceUpdater
+	for _, updater := range updaters {
+		if isResourceDrifted(updater) {
+			drifted = append(drifted, updater)
+			if _, err := e.updateByCache(updater); err != nil {
+				klog.V(4).Infof("failed to recover resource %s to %v, err: %v", updater.Key(), updater.Value(), err)
+			}
+			continue
+		}
+		updater.UpdateLastUpdateTimestamp(time.Now())
+		if err := e.ResourceCache.SetDefault(updater.Key(), updater); err != nil {
+			klog.V(5).Infof("failed to SetDefault in resourceCache for resource %s, err: %v", updater.Key(), err)
+		}
+	}
+	return drifted
+}
+
 // Run runs the ResourceUpdateExecutor.
 // TODO: run single executor when the qos manager starts.
 func (e *ResourceUpdateExecutorImpl) Run(stopCh <-chan struct{}) {
@@ -231,6 +260,7 @@ func (e *ResourceUpdateExecutorImpl) update(updater ResourceUpdater) error {
 		return err
 	}
 	klog.V(6).Infof("successfully update resource %s to %v", updater.Key(), updater.Value())
+	verifyUpdate(updater)
 	return nil
 }
 
@@ -245,6 +275,7 @@ func (e *ResourceUpdateExecutorImpl) updateByCache(updater ResourceUpdater) (boo
 			klog.V(5).Infof("failed to cacheable update resource %s to %v, err: %v", updater.Key(), updater.Value(), err)
 			return false, err
 		}
+		verifyUpdate(updater)
 		updater.UpdateLastUpdateTimestamp(time.Now())
 		err = e.ResourceCache.SetDefault(updater.Key(), updater)
 		if err != nil {
@@ -246,3 +246,37 @@ func TestResourceUpdateExecutor_UpdateBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceUpdateExecutor_RecoverBatch(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteCgroupFileContents("test", sysutil.CPUCFSQuota, "-1")
+	helper.WriteCgroupFileContents("test", sysutil.MemoryLimit, "2097152")
+
+	unchangedUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "test", "-1", &audit.EventHelper{})
+	assert.NoError(t, err)
+	driftedUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.MemoryLimitName, "test", "1048576", &audit.EventHelper{})
+	assert.NoError(t, err)
+	// the cgroup file does not exist, so it cannot be read back and compared
+	unreadableUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.MemoryLimitName, "missing", "1048576", &audit.EventHelper{})
+	assert.NoError(t, err)
+
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	// abort when GC is not started
+	assert.Nil(t, e.RecoverBatch(unchangedUpdater, driftedUpdater))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+
+	got := e.RecoverBatch(unchangedUpdater, driftedUpdater, unreadableUpdater)
+	assert.Equal(t, []ResourceUpdater{driftedUpdater}, got)
+	assert.Equal(t, "1048576", helper.ReadCgroupFileContents("test", sysutil.MemoryLimit))
+	// both resources are cached, so the next cacheable update skips them
+	assert.False(t, e.needUpdate(unchangedUpdater))
+	assert.False(t, e.needUpdate(driftedUpdater))
+	assert.False(t, e.needUpdate(unreadableUpdater))
+}
@@ -0,0 +1,93 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestCgroupReaderWithInjectedFaults(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	defer faultinjection.Reset()
+	helper.WriteCgroupFileContents("kubepods.slice", sysutil.CPUCFSQuota, "-1")
+
+	r := NewCgroupReader()
+	faultinjection.Inject(faultinjection.CgroupRead, faultinjection.Fault{
+		TargetContains: sysutil.CPUCFSQuotaName,
+		Err:            fmt.Errorf("injected read error"),
+		Times:          1,
+	})
+	_, err := r.ReadCPUQuota("kubepods.slice")
+	assert.Error(t, err)
+
+	// the delayed read still succeeds
+	faultinjection.Inject(faultinjection.CgroupRead, faultinjection.Fault{
+		TargetContains: sysutil.CPUCFSQuotaName,
+		Delay:          10 * time.Millisecond,
+	})
+	start := time.Now()
+	got, err := r.ReadCPUQuota("kubepods.slice")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), got)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestResourceUpdateExecutorWithInjectedFaults(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	defer faultinjection.Reset()
+
+	updater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods.slice", "100000", &audit.EventHelper{})
+	assert.NoError(t, err)
+	helper.WriteFileContents(updater.Path(), "-1")
+
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+
+	faultinjection.Inject(faultinjection.CgroupWrite, faultinjection.Fault{
+		Err:   fmt.Errorf("injected write error"),
+		Times: 1,
+	})
+	updated, err := e.Update(true, updater)
+	assert.Error(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, "-1", helper.ReadFileContents(updater.Path()))
+
+	// the failed update is not cached, so it is retried successfully
+	updated, err = e.Update(true, updater)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, "100000", helper.ReadFileContents(updater.Path()))
+}
@@ -22,9 +22,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"k8s.io/klog/v2"
+
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 const psiLineFormat = "avg10=%f avg60=%f avg300=%f total=%d"
@@ -113,6 +116,16 @@ func getPSIByResource(paths PSIPath) (*PSIByResource, error) {
 	}, nil
 }
 
+// ReadNodePSI reads the pressure stall information of the whole node from the procfs.
+func ReadNodePSI() (*PSIByResource, error) {
+	pressureDir := sysutil.GetProcFilePath(sysutil.ProcPressureSubDir)
+	return getPSIByResource(PSIPath{
+		CPU: filepath.Join(pressureDir, "cpu"),
+		Mem: filepath.Join(pressureDir, "memory"),
+		IO:  filepath.Join(pressureDir, "io"),
+	})
+}
+
 func readPSI(pressureFilePath string) (PSIStats, error) {
 	fileContents, err := os.ReadFile(pressureFilePath)
 	if err != nil {
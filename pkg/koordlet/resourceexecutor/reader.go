@@ -19,6 +19,10 @@ package resourceexecutor
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
 
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
@@ -39,6 +43,8 @@ type CgroupReader interface {
 	ReadCPUTasks(parentDir string) ([]int32, error)
 	ReadPSI(parentDir string) (*PSIByResource, error)
 	ReadMemoryColdPageUsage(parentDir string) (uint64, error)
+	ReadMemoryColdSwapBackedPageUsage(parentDir string) (uint64, error)
+	ReadMemorySwapLimit(parentDir string) (int64, error)
 }
 
 var _ CgroupReader = &CgroupV1Reader{}
@@ -215,6 +221,29 @@ func (r *CgroupV1Reader) ReadMemoryColdPageUsage(parentDir string) (uint64, erro
 	return v.GetColdPageTotalBytes(), nil
 }
 
+// ReadMemoryColdSwapBackedPageUsage reads the cold bytes of the swap-backed pages (anon and shmem/tmpfs).
+func (r *CgroupV1Reader) ReadMemoryColdSwapBackedPageUsage(parentDir string) (uint64, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV1, sysutil.MemoryIdlePageStatsName)
+	if !ok {
+		return 0, ErrResourceNotRegistered
+	}
+	s, err := cgroupFileRead(parentDir, resource)
+	if err != nil {
+		return 0, err
+	}
+	v, err := sysutil.ParseMemoryIdlePageStats(s)
+	if err != nil {
+		return 0, err
+	}
+	return v.GetColdSwapBackedBytes(), nil
+}
+
+// ReadMemorySwapLimit is not supported on cgroups v1, where the memory.memsw.limit_in_bytes limits the memory and
+// the swap together.
+func (r *CgroupV1Reader) ReadMemorySwapLimit(parentDir string) (int64, error) {
+	return -1, ErrResourceNotRegistered
+}
+
 var _ CgroupReader = &CgroupV2Reader{}
 
 type CgroupV2Reader struct{}
@@ -404,7 +433,65 @@ func (r *CgroupV2Reader) ReadPSI(parentDir string) (*PSIByResource, error) {
 
 // cgroup v2 has not implemented yet
 func (r *CgroupV2Reader) ReadMemoryColdPageUsage(parentDir string) (uint64, error) {
-	return 0, ErrResourceNotRegistered
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV2, sysutil.MemoryIdlePageStatsName)
+	if !ok {
+		return 0, ErrResourceNotRegistered
+	}
+	return readMemoryColdPageUsageV2(parentDir, resource, (*sysutil.ColdPageInfoByKidled).GetColdPageTotalBytes)
+}
+
+// ReadMemoryColdSwapBackedPageUsage reads the cold bytes of the swap-backed pages (anon and shmem/tmpfs).
+func (r *CgroupV2Reader) ReadMemoryColdSwapBackedPageUsage(parentDir string) (uint64, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV2, sysutil.MemoryIdlePageStatsName)
+	if !ok {
+		return 0, ErrResourceNotRegistered
+	}
+	return readMemoryColdPageUsageV2(parentDir, resource, (*sysutil.ColdPageInfoByKidled).GetColdSwapBackedBytes)
+}
+
+// ReadMemorySwapLimit reads the memory.swap.max of the cgroup. It returns -1 if the swap is unlimited.
+func (r *CgroupV2Reader) ReadMemorySwapLimit(parentDir string) (int64, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV2, sysutil.MemorySwapMaxName)
+	if !ok {
+		return -1, ErrResourceNotRegistered
+	}
+	return readCgroupAndParseInt64(parentDir, resource)
+}
+
+// readMemoryColdPageUsageV2 reads the cold page bytes of the cgroup on the unified hierarchy.
+// When kidled does not account the pages hierarchically, the memory.idle_page_stats of a cgroup only covers the pages
+// charged to itself, so the child cgroups (e.g. the containers and the sandbox of a pod) are rolled up recursively.
+func readMemoryColdPageUsageV2(parentDir string, resource sysutil.Resource, getBytes func(*sysutil.ColdPageInfoByKidled) uint64) (uint64, error) {
+	s, err := cgroupFileRead(parentDir, resource)
+	if err != nil {
+		return 0, err
+	}
+	v, err := sysutil.ParseMemoryIdlePageStats(s)
+	if err != nil {
+		return 0, err
+	}
+	total := getBytes(v)
+	if v.UseHierarchy != 0 {
+		return total, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(resource.Path(parentDir)))
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		childDir := filepath.Join(parentDir, entry.Name())
+		childUsage, err := readMemoryColdPageUsageV2(childDir, resource, getBytes)
+		if err != nil {
+			klog.V(5).Infof("failed to read cold page usage of child cgroup %s, err: %v", childDir, err)
+			continue
+		}
+		total += childUsage
+	}
+	return total, nil
 }
 
 func NewCgroupReader() CgroupReader {
@@ -17,6 +17,8 @@ limitations under the License.
 package resourceexecutor
 
 import (
+	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1344,8 +1346,9 @@ func TestCgroupReader_ReadPSI(t *testing.T) {
 
 func TestCgroupReader_ReadColdPageUsage(t *testing.T) {
 	type fields struct {
-		UseCgroupsV2             bool
-		MemoryIdlePageStatsValue string
+		UseCgroupsV2                   bool
+		MemoryIdlePageStatsValue       string
+		ChildMemoryIdlePageStatsValues map[string]string
 	}
 	type args struct {
 		parentDir string
@@ -1421,7 +1424,7 @@ func TestCgroupReader_ReadColdPageUsage(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "cgroup v2 not registered",
+			name: "v2 path not exist",
 			fields: fields{
 				UseCgroupsV2: true,
 			},
@@ -1431,14 +1434,55 @@ func TestCgroupReader_ReadColdPageUsage(t *testing.T) {
 			want:    uint64(0),
 			wantErr: true,
 		},
+		{
+			name: "parse v2 hierarchical value successfully",
+			fields: fields{
+				UseCgroupsV2:             true,
+				MemoryIdlePageStatsValue: newMemoryIdlePageStats(1, 4096),
+				ChildMemoryIdlePageStatsValues: map[string]string{
+					"cri-containerd-xxx.scope": newMemoryIdlePageStats(1, 8192),
+				},
+			},
+			args: args{
+				parentDir: "/kubepods.slice/kubepods-podxxx.slice",
+			},
+			want:    uint64(4096),
+			wantErr: false,
+		},
+		{
+			name: "roll up v2 child cgroups when not hierarchical",
+			fields: fields{
+				UseCgroupsV2:             true,
+				MemoryIdlePageStatsValue: newMemoryIdlePageStats(0, 4096),
+				ChildMemoryIdlePageStatsValues: map[string]string{
+					"cri-containerd-sandbox.scope": newMemoryIdlePageStats(0, 1024),
+					"cri-containerd-aaa.scope":     newMemoryIdlePageStats(0, 8192),
+					"cri-containerd-bbb.scope":     newMemoryIdlePageStats(0, 16384),
+					"cri-containerd-ccc.scope":     `abc`,
+				},
+			},
+			args: args{
+				parentDir: "/kubepods.slice/kubepods-podxxx.slice",
+			},
+			want:    uint64(4096 + 1024 + 8192 + 16384),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			helper := sysutil.NewFileTestUtil(t)
 			defer helper.Cleanup()
 			helper.SetCgroupsV2(tt.fields.UseCgroupsV2)
+			resource := sysutil.MemoryIdlePageStats
+			if tt.fields.UseCgroupsV2 {
+				resource = sysutil.MemoryIdlePageStatsV2
+			}
 			if tt.fields.MemoryIdlePageStatsValue != "" {
-				helper.WriteCgroupFileContents(tt.args.parentDir, sysutil.MemoryIdlePageStats, tt.fields.MemoryIdlePageStatsValue)
+				helper.SetResourcesSupported(true, resource)
+				helper.WriteCgroupFileContents(tt.args.parentDir, resource, tt.fields.MemoryIdlePageStatsValue)
+			}
+			for child, value := range tt.fields.ChildMemoryIdlePageStatsValues {
+				helper.WriteCgroupFileContents(filepath.Join(tt.args.parentDir, child), resource, value)
 			}
 			got, gotErr := NewCgroupReader().ReadMemoryColdPageUsage(tt.args.parentDir)
 			assert.Equal(t, tt.wantErr, gotErr != nil)
@@ -1446,3 +1490,154 @@ func TestCgroupReader_ReadColdPageUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestCgroupReader_ReadColdSwapBackedPageUsage(t *testing.T) {
+	tests := []struct {
+		name                           string
+		useCgroupsV2                   bool
+		memoryIdlePageStatsValue       string
+		childMemoryIdlePageStatsValues map[string]string
+		want                           uint64
+		wantErr                        bool
+	}{
+		{
+			name:                     "parse v1 value successfully",
+			memoryIdlePageStatsValue: newMemoryIdlePageStats(1, 4096),
+			want:                     uint64(4096),
+			wantErr:                  false,
+		},
+		{
+			name:    "v1 path not exist",
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name:                     "parse v1 value failed",
+			memoryIdlePageStatsValue: `abc`,
+			want:                     0,
+			wantErr:                  true,
+		},
+		{
+			name:                     "roll up v2 child cgroups when not hierarchical",
+			useCgroupsV2:             true,
+			memoryIdlePageStatsValue: newMemoryIdlePageStats(0, 4096),
+			childMemoryIdlePageStatsValues: map[string]string{
+				"cri-containerd-aaa.scope": newMemoryIdlePageStats(0, 8192),
+			},
+			want:    uint64(4096 + 8192),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.useCgroupsV2)
+			resource := sysutil.MemoryIdlePageStats
+			if tt.useCgroupsV2 {
+				resource = sysutil.MemoryIdlePageStatsV2
+			}
+			parentDir := "/kubepods.slice/kubepods-podxxx.slice"
+			if tt.memoryIdlePageStatsValue != "" {
+				helper.WriteCgroupFileContents(parentDir, resource, tt.memoryIdlePageStatsValue)
+			}
+			for child, value := range tt.childMemoryIdlePageStatsValues {
+				helper.WriteCgroupFileContents(filepath.Join(parentDir, child), resource, value)
+			}
+			got, gotErr := NewCgroupReader().ReadMemoryColdSwapBackedPageUsage(parentDir)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCgroupReader_ReadMemorySwapLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCgroupsV2 bool
+		swapMaxValue string
+		want         int64
+		wantErr      bool
+	}{
+		{
+			name:    "v1 not supported",
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name:         "v2 path not exist",
+			useCgroupsV2: true,
+			want:         -1,
+			wantErr:      true,
+		},
+		{
+			name:         "parse v2 value successfully",
+			useCgroupsV2: true,
+			swapMaxValue: "1048576",
+			want:         1048576,
+			wantErr:      false,
+		},
+		{
+			name:         "parse v2 unlimited value",
+			useCgroupsV2: true,
+			swapMaxValue: "max",
+			want:         -1,
+			wantErr:      false,
+		},
+		{
+			name:         "parse v2 swap disabled",
+			useCgroupsV2: true,
+			swapMaxValue: "0",
+			want:         0,
+			wantErr:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.useCgroupsV2)
+			parentDir := "/kubepods.slice/kubepods-podxxx.slice"
+			if tt.swapMaxValue != "" {
+				helper.WriteCgroupFileContents(parentDir, sysutil.MemorySwapMaxV2, tt.swapMaxValue)
+			}
+			got, gotErr := NewCgroupReader().ReadMemorySwapLimit(parentDir)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newMemoryIdlePageStats(useHierarchy int, coldBytes uint64) string {
+	return fmt.Sprintf(`# version: 1.0
+# page_scans: 24
+# slab_scans: 0
+# scan_period_in_seconds: 120
+# use_hierarchy: %d
+# buckets: 1,2,5,15,30,60,120,240
+#
+#   _-----=> clean/dirty
+#  / _----=> swap/file
+# | / _---=> evict/unevict
+# || / _--=> inactive/active
+# ||| / _-=> slab
+# |||| /
+# |||||             [1,2)          [2,5)         [5,15)        [15,30)        [30,60)       [60,120)      [120,240)     [240,+inf)
+  csei     %d              0              0              0              0              0              0              0
+  dsei                  0              0              0              0              0              0              0              0
+  cfei                  0              0              0              0              0              0              0              0
+  dfei                  0              0              0              0              0              0              0              0
+  csui                  0              0              0              0              0              0              0              0
+  dsui                  0              0              0              0              0              0              0              0
+  cfui                  0              0              0              0              0              0              0              0
+  dfui                  0              0              0              0              0              0              0              0
+  csea                  0              0              0              0              0              0              0              0
+  dsea                  0              0              0              0              0              0              0              0
+  cfea                  0              0              0              0              0              0              0              0
+  dfea                  0              0              0              0              0              0              0              0
+  csua                  0              0              0              0              0              0              0              0
+  dsua                  0              0              0              0              0              0              0              0
+  cfua                  0              0              0              0              0              0              0              0
+  dfua                  0              0              0              0              0              0              0              0
+  slab                  0              0              0              0              0              0              0              0`, useHierarchy, coldBytes)
+}
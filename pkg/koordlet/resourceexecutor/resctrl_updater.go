@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -87,6 +88,25 @@ func NewResctrlMbSchemataResource(group, schemataDelta string, l3Num int) Resour
 	}
 }
 
+// NewResctrlMbSchemataResourceByDomain generates the mba schemata resource with the value or percent of each L3 domain.
+func NewResctrlMbSchemataResourceByDomain(group string, schemataDeltas []string) ResourceUpdater {
+	schemataFile := sysutil.ResctrlSchemata.Path(group)
+	mbSchemataKey := sysutil.MbSchemataPrefix + ":" + schemataFile
+	schemata := sysutil.NewResctrlSchemataRaw().WithMBs(schemataDeltas)
+	klog.V(6).Infof("generate new resctrl mba schemata resource by domain, file %s, key %s, value %s",
+		schemataFile, mbSchemataKey, schemata.MBString())
+
+	return &ResctrlSchemataResourceUpdater{
+		DefaultResourceUpdater: DefaultResourceUpdater{
+			key:        mbSchemataKey,
+			file:       schemataFile,
+			value:      schemata.MBString(),
+			updateFunc: UpdateResctrlSchemataFunc,
+		},
+		schemataRaw: schemata,
+	}
+}
+
 func CalculateResctrlL3TasksResource(group string, taskIds []int32) (ResourceUpdater, error) {
 	// join ids into updater value and make the id updates one by one
 	tasksPath := sysutil.GetResctrlTasksFilePath(group)
@@ -136,6 +156,9 @@ func UpdateResctrlSchemataFunc(u ResourceUpdater) error {
 	// L3:0=03f;1=03f
 	// MB:0=100;1=100
 	_ = audit.V(3).Reason(ReasonUpdateResctrl).Message("update %v to %v", u.Key(), u.Value()).Do()
+	if err := faultinjection.Trigger(faultinjection.ResctrlWrite, u.Path()); err != nil {
+		return err
+	}
 	return sysutil.CommonFileWrite(u.Path(), u.Value())
 }
 
@@ -158,6 +181,9 @@ func UpdateResctrlTasksFunc(resource ResourceUpdater) error {
 		_ = audit.V(5).Reason(ReasonUpdateResctrl).Message("update %v to %v", resource.Key(), resource.Value()).Do()
 	}
 
+	if err := faultinjection.Trigger(faultinjection.ResctrlWrite, resource.Path()); err != nil {
+		return err
+	}
 	f, err := os.OpenFile(resource.Path(), os.O_RDWR|os.O_APPEND, 0644)
 	if err != nil {
 		return err
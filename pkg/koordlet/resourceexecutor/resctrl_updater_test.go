@@ -112,3 +112,20 @@ func TestNewResctrlMbSchemataResource(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestNewResctrlMbSchemataResourceByDomain(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+
+		sysFSRootDirName := "NewResctrlMbSchemataResourceByDomain"
+		helper.MkDirAll(sysFSRootDirName)
+		system.Conf.SysFSRootDir = filepath.Join(helper.TempDir, sysFSRootDirName)
+
+		testingPrepareResctrlL3CatGroups(t, "7ff", "    L3:0=ff;1=ff\n    MB:0=100;1=100")
+		updater := NewResctrlMbSchemataResourceByDomain("BE", []string{"50", "30"})
+		assert.Equal(t, updater.Value(), "MB:0=50;1=30;\n")
+		err := updater.update()
+		assert.NoError(t, err)
+	})
+}
@@ -67,7 +67,10 @@ func init() {
 	)
 	DefaultCgroupUpdaterFactory.Register(NewMergeableCgroupUpdaterWithConditionFunc(CommonCgroupUpdateFunc, MergeConditionIfCPUSetIsLooser),
 		sysutil.CPUSetCPUSName,
+		sysutil.CPUSetMemsName,
 	)
+	// the write-only files which trigger an action on each write
+	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupWriteOnlyUpdateFunc), sysutil.MemoryReclaimName)
 	DefaultCgroupUpdaterFactory.Register(NewBlkIOResourceUpdater,
 		sysutil.BlkioTRIopsName,
 		sysutil.BlkioTRBpsName,
@@ -375,6 +378,21 @@ func CgroupUpdateWithUnlimitedFunc(resource ResourceUpdater) error {
 	return cgroupWriteIfDifferentWithLog(c)
 }
 
+// CgroupWriteOnlyUpdateFunc writes the value without comparing it with the current one, which is for the write-only
+// cgroup files that trigger an action on each write and can not be read, e.g. the memory.reclaim.
+func CgroupWriteOnlyUpdateFunc(resource ResourceUpdater) error {
+	c := resource.(*CgroupResourceUpdater)
+	if err := cgroupFileWrite(c.parentDir, c.file, c.value); err != nil {
+		return err
+	}
+	if c.eventHelper != nil {
+		_ = c.eventHelper.Do()
+	} else {
+		_ = audit.V(3).Reason(ReasonUpdateCgroups).Message("update %v to %v", c.Path(), c.Value()).Do()
+	}
+	return nil
+}
+
 func CgroupUpdateCPUSharesFunc(resource ResourceUpdater) error {
 	c := resource.(*CgroupResourceUpdater)
 	// convert values in `cpu.shares` (v1) into values in `cpu.weight` (v2)
@@ -179,6 +179,26 @@ func TestCgroupResourceUpdater_Update(t *testing.T) {
 	}
 }
 
+func TestCgroupWriteOnlyUpdateFunc(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	parentDir := "/kubepods.slice/kubepods.slice-podxxx"
+	helper.WriteCgroupFileContents(sysutil.CgroupPathFormatter.ParentDir, sysutil.MemoryReclaimV2, "")
+	helper.WriteCgroupFileContents(parentDir, sysutil.MemoryReclaimV2, "")
+
+	u, err := DefaultCgroupUpdaterFactory.New(sysutil.MemoryReclaimName, parentDir, "1048576 swappiness=200", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, u.update())
+	assert.Equal(t, "1048576 swappiness=200", helper.ReadCgroupFileContents(parentDir, sysutil.MemoryReclaimV2))
+
+	// the write-only file is written again without comparing the current value
+	helper.WriteCgroupFileContents(parentDir, sysutil.MemoryReclaimV2, "")
+	assert.NoError(t, u.update())
+	assert.Equal(t, "1048576 swappiness=200", helper.ReadCgroupFileContents(parentDir, sysutil.MemoryReclaimV2))
+}
+
 func TestDefaultResourceUpdater_Update(t *testing.T) {
 	type fields struct {
 		initialValue string
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	// DiscrepancyTypeValue means the value read back from the cgroup file differs from the written one.
+	DiscrepancyTypeValue = "value"
+	// DiscrepancyTypeTask means some tasks of the cgroup are still allowed to run on the CPUs out of the cpuset.
+	DiscrepancyTypeTask = "task"
+
+	procStatusCPUsAllowedListKey = "Cpus_allowed_list:"
+)
+
+// verifiableCgroupResources are the cgroup resources whose values read back can be compared with the written ones.
+// The resources like blkio throttles which contain multiple records are not verified.
+var verifiableCgroupResources = map[sysutil.ResourceType]bool{
+	sysutil.CPUSetCPUSName:  true,
+	sysutil.CPUCFSQuotaName: true,
+	sysutil.CPUSharesName:   true,
+	sysutil.MemoryLimitName: true,
+	sysutil.MemoryMinName:   true,
+	sysutil.MemoryLowName:   true,
+	sysutil.MemoryHighName:  true,
+	sysutil.CPUBurstName:    true,
+}
+
+// DiscrepancyRecorder records the number of discrepancies found for the cgroup resource.
+type DiscrepancyRecorder func(resource string, discrepancyType string, count int)
+
+var recordDiscrepancy DiscrepancyRecorder = func(string, string, int) {}
+
+// SetDiscrepancyRecorder sets the recorder of the discrepancies found by the update verification, e.g. the metrics.
+func SetDiscrepancyRecorder(recorder DiscrepancyRecorder) {
+	if recorder != nil {
+		recordDiscrepancy = recorder
+	}
+}
+
+// verifyUpdate checks whether the kernel applied the cgroup update by reading back the cgroup file, and whether no task
+// escaped the updated cpuset. The discrepancies are only logged and recorded, and the update is not retried here since
+// the executor will force update the resource in the next round.
+func verifyUpdate(updater ResourceUpdater) {
+	checkCPUSetPropagation(updater)
+	if !features.DefaultKoordletFeatureGate.Enabled(features.CgroupUpdateVerification) {
+		return
+	}
+	c, ok := updater.(*CgroupResourceUpdater)
+	if !ok || !verifiableCgroupResources[c.ResourceType()] {
+		return
+	}
+
+	current, err := cgroupFileRead(c.parentDir, c.file)
+	if err != nil {
+		klog.V(5).Infof("failed to verify cgroup update %s, read err: %v", c.Path(), err)
+		return
+	}
+	if !isCgroupValueApplied(c.ResourceType(), c.value, current) {
+		klog.Warningf("cgroup update %s not applied, expect %s, current %s", c.Path(), c.value, current)
+		recordDiscrepancy(string(c.ResourceType()), DiscrepancyTypeValue, 1)
+		return
+	}
+
+	if c.ResourceType() != sysutil.CPUSetCPUSName {
+		return
+	}
+	escaped, err := getCPUSetEscapedTasks(c.parentDir, c.value)
+	if err != nil {
+		klog.V(5).Infof("failed to verify tasks of cgroup update %s, err: %v", c.Path(), err)
+		return
+	}
+	if len(escaped) > 0 {
+		klog.Warningf("cgroup update %s applied but tasks %v escaped the cpuset %s", c.Path(), escaped, c.value)
+		recordDiscrepancy(string(c.ResourceType()), DiscrepancyTypeTask, len(escaped))
+	}
+}
+
+// isResourceDrifted returns whether the current value of the resource differs from the value of the updater. The
+// resources which cannot be read back and compared are regarded as unchanged, so they are not rewritten repeatedly.
+func isResourceDrifted(updater ResourceUpdater) bool {
+	c, ok := updater.(*CgroupResourceUpdater)
+	if !ok || !verifiableCgroupResources[c.ResourceType()] {
+		klog.V(6).Infof("resource %s is not verifiable, regard it as unchanged", updater.Key())
+		return false
+	}
+	current, err := cgroupFileRead(c.parentDir, c.file)
+	if err != nil {
+		klog.V(4).Infof("failed to read cgroup %s, regard it as unchanged, err: %v", c.Path(), err)
+		return false
+	}
+	return !isCgroupValueApplied(c.ResourceType(), c.value, current)
+}
+
+// isCgroupValueApplied compares the expected value with the current value read from the cgroup file.
+// e.g. the kernel normalizes the cpuset list, appends the period to the cgroups-v2 `cpu.max`, and rounds the memory
+// bytes by the page size.
+func isCgroupValueApplied(resourceType sysutil.ResourceType, expected, current string) bool {
+	if resourceType == sysutil.CPUSetCPUSName {
+		return cpuset.IsEqualStrCpus(expected, current)
+	}
+	fields := strings.Fields(current)
+	if len(fields) <= 0 {
+		return expected == ""
+	}
+	current = fields[0]
+	if expected == current {
+		return true
+	}
+	if isUnlimitedValue(expected) && isUnlimitedValue(current) {
+		return true
+	}
+	if !strings.HasPrefix(string(resourceType), "memory.") {
+		return false
+	}
+	expectedBytes, err := strconv.ParseInt(expected, 10, 64)
+	if err != nil {
+		return false
+	}
+	currentBytes, err := strconv.ParseInt(current, 10, 64)
+	if err != nil {
+		return false
+	}
+	diff := expectedBytes - currentBytes
+	return diff >= 0 && diff < int64(os.Getpagesize())
+}
+
+func isUnlimitedValue(value string) bool {
+	if value == sysutil.CgroupMaxSymbolStr || value == sysutil.CgroupUnlimitedSymbolStr {
+		return true
+	}
+	v, err := strconv.ParseInt(value, 10, 64)
+	// e.g. the v1 `memory.limit_in_bytes` reads 9223372036854771712 for the unlimited
+	return err == nil && v > math.MaxInt64-int64(os.Getpagesize())
+}
+
+// getCPUSetEscapedTasks returns the tasks of the cgroup whose allowed CPUs are not the subset of the cpuset.
+func getCPUSetEscapedTasks(parentDir string, cpus string) ([]int32, error) {
+	cpuSet, err := cpuset.Parse(cpus)
+	if err != nil {
+		return nil, fmt.Errorf("parse cpuset %s failed, err: %w", cpus, err)
+	}
+	tasks, err := NewCgroupReader().ReadCPUTasks(parentDir)
+	if err != nil {
+		return nil, err
+	}
+	var escaped []int32
+	for _, task := range tasks {
+		allowed, err := ReadTaskCPUsAllowed(task)
+		if err != nil {
+			// the task may exit
+			klog.V(6).Infof("failed to read allowed cpus of task %d, err: %v", task, err)
+			continue
+		}
+		if !allowed.IsSubsetOf(cpuSet) {
+			escaped = append(escaped, task)
+		}
+	}
+	return escaped, nil
+}
+
+// ReadTaskCPUsAllowed reads the allowed CPUs of the task from the `Cpus_allowed_list` of its proc status.
+func ReadTaskCPUsAllowed(task int32) (*cpuset.CPUSet, error) {
+	content, err := os.ReadFile(sysutil.GetProcFilePath(fmt.Sprintf("%d/status", task)))
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, procStatusCPUsAllowedListKey) {
+			continue
+		}
+		allowed, err := cpuset.Parse(strings.TrimSpace(strings.TrimPrefix(line, procStatusCPUsAllowedListKey)))
+		if err != nil {
+			return nil, err
+		}
+		return &allowed, nil
+	}
+	return nil, fmt.Errorf("%s not found", procStatusCPUsAllowedListKey)
+}
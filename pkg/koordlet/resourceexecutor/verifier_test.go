@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_isCgroupValueApplied(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType sysutil.ResourceType
+		expected     string
+		current      string
+		want         bool
+	}{
+		{
+			name:         "cpuset normalized",
+			resourceType: sysutil.CPUSetCPUSName,
+			expected:     "0,1,2,3",
+			current:      "0-3",
+			want:         true,
+		},
+		{
+			name:         "cpuset not applied",
+			resourceType: sysutil.CPUSetCPUSName,
+			expected:     "0-3",
+			current:      "0-7",
+			want:         false,
+		},
+		{
+			name:         "cgroups-v2 cpu.max with period",
+			resourceType: sysutil.CPUCFSQuotaName,
+			expected:     "50000",
+			current:      "50000 100000",
+			want:         true,
+		},
+		{
+			name:         "unlimited quota",
+			resourceType: sysutil.CPUCFSQuotaName,
+			expected:     "-1",
+			current:      "max 100000",
+			want:         true,
+		},
+		{
+			name:         "quota not applied",
+			resourceType: sysutil.CPUCFSQuotaName,
+			expected:     "50000",
+			current:      "-1",
+			want:         false,
+		},
+		{
+			name:         "memory rounded by page size",
+			resourceType: sysutil.MemoryLimitName,
+			expected:     "1048577",
+			current:      "1048576",
+			want:         true,
+		},
+		{
+			name:         "unlimited memory",
+			resourceType: sysutil.MemoryLimitName,
+			expected:     "-1",
+			current:      "9223372036854771712",
+			want:         true,
+		},
+		{
+			name:         "memory not applied",
+			resourceType: sysutil.MemoryHighName,
+			expected:     "2097152",
+			current:      "1048576",
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isCgroupValueApplied(tt.resourceType, tt.expected, tt.current))
+		})
+	}
+}
+
+func Test_verifyUpdate(t *testing.T) {
+	testParentDir := "kubepods.slice/test-pod"
+	type fields struct {
+		currentCPUSet string
+		tasks         string
+		taskStatus    map[string]string
+	}
+	type discrepancy struct {
+		discrepancyType string
+		count           int
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []discrepancy
+	}{
+		{
+			name: "cpuset applied",
+			fields: fields{
+				currentCPUSet: "0-3",
+				tasks:         "100\n101\n",
+				taskStatus: map[string]string{
+					"100/status": "Name:\ttest\nCpus_allowed_list:\t0-3\n",
+					"101/status": "Name:\ttest\nCpus_allowed_list:\t1\n",
+				},
+			},
+		},
+		{
+			name: "cpuset not applied",
+			fields: fields{
+				currentCPUSet: "0-7",
+				tasks:         "100\n",
+				taskStatus: map[string]string{
+					"100/status": "Name:\ttest\nCpus_allowed_list:\t0-7\n",
+				},
+			},
+			want: []discrepancy{
+				{discrepancyType: DiscrepancyTypeValue, count: 1},
+			},
+		},
+		{
+			name: "task escaped the cpuset",
+			fields: fields{
+				currentCPUSet: "0-3",
+				tasks:         "100\n101\n102\n",
+				taskStatus: map[string]string{
+					"100/status": "Name:\ttest\nCpus_allowed_list:\t0-7\n",
+					"101/status": "Name:\ttest\nCpus_allowed_list:\t0-3\n",
+					"102/status": "Name:\ttest\nCpus_allowed_list:\t4\n",
+				},
+			},
+			want: []discrepancy{
+				{discrepancyType: DiscrepancyTypeTask, count: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.CgroupUpdateVerification, true)()
+			helper.WriteCgroupFileContents(testParentDir, sysutil.CPUSet, tt.fields.currentCPUSet)
+			helper.WriteCgroupFileContents(testParentDir, sysutil.CPUTasks, tt.fields.tasks)
+			for file, content := range tt.fields.taskStatus {
+				helper.WriteProcSubFileContents(file, content)
+			}
+
+			var got []discrepancy
+			SetDiscrepancyRecorder(func(resource string, discrepancyType string, count int) {
+				assert.Equal(t, sysutil.CPUSetCPUSName, resource)
+				got = append(got, discrepancy{discrepancyType: discrepancyType, count: count})
+			})
+			defer SetDiscrepancyRecorder(func(string, string, int) {})
+
+			updater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, testParentDir, "0,1,2,3", &audit.EventHelper{})
+			assert.NoError(t, err)
+			verifyUpdate(updater)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -26,10 +26,13 @@ import (
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/batchresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpuamplification"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpunormalization"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpupriorityshares"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpuset"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/groupidentity"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/kata"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -66,6 +69,27 @@ const (
 	// owner: @saintube @zwzhang0107
 	// alpha: v1.4
 	CPUNormalization featuregate.Feature = "CPUNormalization"
+
+	// CPUAmplification scales cpu cfs quota by the node cpu amplification ratio for non-bound pods.
+	// It cannot be enabled together with CPUNormalization since both of them adjust the cfs quota of LS pods.
+	//
+	// owner: @saintube
+	// alpha: v1.4
+	CPUAmplification featuregate.Feature = "CPUAmplification"
+
+	// KataTopologyPassthrough passes the cpuset and NUMA allocation of the kata pods through to the vCPU pinning of
+	// the sandbox VM via the annotations understood by the kata runtime.
+	//
+	// owner: @saintube
+	// alpha: v1.4
+	KataTopologyPassthrough featuregate.Feature = "KataTopologyPassthrough"
+
+	// CPUPriorityShares scales the cpu shares of LS pods by the buckets of the koordinator sub-priority configured in
+	// the NodeSLO instead of the flat shares calculated from the cpu requests.
+	//
+	// owner: @koordinator-sh
+	// alpha: v1.4
+	CPUPriorityShares featuregate.Feature = "CPUPriorityShares"
 )
 
 var (
@@ -75,6 +99,10 @@ var (
 		GPUEnvInject:     {Default: false, PreRelease: featuregate.Alpha},
 		BatchResource:    {Default: true, PreRelease: featuregate.Beta},
 		CPUNormalization: {Default: false, PreRelease: featuregate.Alpha},
+		CPUAmplification: {Default: false, PreRelease: featuregate.Alpha},
+
+		KataTopologyPassthrough: {Default: false, PreRelease: featuregate.Alpha},
+		CPUPriorityShares:       {Default: false, PreRelease: featuregate.Alpha},
 	}
 
 	runtimeHookPlugins = map[featuregate.Feature]HookPlugin{
@@ -83,6 +111,10 @@ var (
 		GPUEnvInject:     gpu.Object(),
 		BatchResource:    batchresource.Object(),
 		CPUNormalization: cpunormalization.Object(),
+		CPUAmplification: cpuamplification.Object(),
+
+		KataTopologyPassthrough: kata.Object(),
+		CPUPriorityShares:       cpupriorityshares.Object(),
 	}
 )
 
@@ -97,6 +129,7 @@ type Config struct {
 	RuntimeHooksNRI                 bool
 	RuntimeHooksNRISocketPath       string
 	RuntimeHookReconcileInterval    time.Duration
+	RuntimeHookMemoryPolicyHookPath string
 }
 
 func NewDefaultConfig() *Config {
@@ -124,6 +157,7 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.Var(cliflag.NewStringSlice(&c.RuntimeHookDisableStages), "runtime-hooks-disable-stages", "disable stages for runtime hooks")
 	fs.BoolVar(&c.RuntimeHooksNRI, "enable-nri-runtime-hook", c.RuntimeHooksNRI, "enable/disable runtime hooks nri mode")
 	fs.DurationVar(&c.RuntimeHookReconcileInterval, "runtime-hooks-reconcile-interval", c.RuntimeHookReconcileInterval, "reconcile interval for each plugins")
+	fs.StringVar(&c.RuntimeHookMemoryPolicyHookPath, "runtime-hooks-mempolicy-hook-path", c.RuntimeHookMemoryPolicyHookPath, "path of the OCI hook injected by NRI to apply the memory policy of the containers, e.g. interleave, disabled if empty. It only works in the NRI mode")
 }
 
 func init() {
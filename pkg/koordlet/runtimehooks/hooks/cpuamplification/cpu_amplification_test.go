@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuamplification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		p := Object()
+		assert.NotNil(t, p)
+	})
+}
+
+func TestPlugin_Register(t *testing.T) {
+	t.Run("test not panic", func(t *testing.T) {
+		p := newPlugin()
+		p.Register(hooks.Options{})
+	})
+}
+
+func TestPluginAdjustPodCFSQuota(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         *Rule
+		arg          protocol.HooksProtocol
+		wantErr      bool
+		wantCFSQuota *int64
+	}{
+		{
+			name:    "nil input",
+			rule:    newRule(),
+			arg:     (*protocol.PodContext)(nil),
+			wantErr: true,
+		},
+		{
+			name: "no resources to adjust",
+			rule: newRule(),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "skip non-cpushare pod",
+			rule: &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString()},
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSBE),
+					},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(100000),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rule disabled",
+			rule: newRule(),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(100000),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "skip unlimited pod",
+			rule: &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString()},
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(-1),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "adjust correctly",
+			rule: &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString()},
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(100000),
+					},
+				},
+			},
+			wantErr:      false,
+			wantCFSQuota: pointer.Int64(50000),
+		},
+		{
+			name: "restore the opted-out qos",
+			rule: &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString(string(extension.QoSLS))},
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(100000),
+					},
+				},
+			},
+			wantErr:      false,
+			wantCFSQuota: pointer.Int64(100000),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPlugin()
+			p.rule = tt.rule
+			gotErr := p.AdjustPodCFSQuota(tt.arg)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			if podCtx := tt.arg.(*protocol.PodContext); podCtx != nil {
+				assert.Equal(t, tt.wantCFSQuota, podCtx.Response.Resources.CFSQuota)
+			}
+		})
+	}
+}
+
+func TestPluginAdjustContainerCFSQuota(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         *Rule
+		arg          protocol.HooksProtocol
+		wantErr      bool
+		wantCFSQuota *int64
+	}{
+		{
+			name:    "nil input",
+			rule:    newRule(),
+			arg:     (*protocol.ContainerContext)(nil),
+			wantErr: true,
+		},
+		{
+			name: "skip cpuset pod",
+			rule: &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString()},
+			arg: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						extension.AnnotationResourceStatus: `{"cpuset": "0-1"}`,
+					},
+					PodLabels: map[string]string{},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(200000),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "adjust correctly",
+			rule: &Rule{enable: true, curRatio: 1.5, optOutQOS: sets.NewString(string(extension.QoSNone))},
+			arg: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodLabels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Resources: &protocol.Resources{
+						CFSQuota: pointer.Int64(100000),
+					},
+				},
+			},
+			wantErr:      false,
+			wantCFSQuota: pointer.Int64(66667),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPlugin()
+			p.rule = tt.rule
+			gotErr := p.AdjustContainerCFSQuota(tt.arg)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			if containerCtx := tt.arg.(*protocol.ContainerContext); containerCtx != nil {
+				assert.Equal(t, tt.wantCFSQuota, containerCtx.Response.Resources.CFSQuota)
+			}
+		})
+	}
+}
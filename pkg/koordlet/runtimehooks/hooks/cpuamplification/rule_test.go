@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuamplification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestRule(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		r := newRule()
+		assert.False(t, r.IsEnabled())
+		assert.Equal(t, float64(-1), r.GetCPUAmplificationRatio(extension.QoSLS))
+		isUpdated := r.UpdateRule(2.0, sets.NewString())
+		assert.True(t, isUpdated)
+		assert.True(t, r.IsEnabled())
+		assert.Equal(t, 2.0, r.GetCPUAmplificationRatio(extension.QoSLS))
+		isUpdated = r.UpdateRule(2.0, sets.NewString())
+		assert.False(t, isUpdated)
+		isUpdated = r.UpdateRule(2.0, sets.NewString(string(extension.QoSLS)))
+		assert.True(t, isUpdated)
+		assert.Equal(t, 1.0, r.GetCPUAmplificationRatio(extension.QoSLS))
+		assert.Equal(t, 2.0, r.GetCPUAmplificationRatio(extension.QoSNone))
+	})
+}
+
+func TestPlugin_parseRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		arg           interface{}
+		want          bool
+		wantErr       bool
+		wantRatio     float64
+		wantOptOutQOS sets.String
+	}{
+		{
+			name:          "got invalid type of input",
+			arg:           &slov1alpha1.NodeSLOSpec{},
+			want:          false,
+			wantErr:       true,
+			wantRatio:     -1,
+			wantOptOutQOS: sets.NewString(),
+		},
+		{
+			name:          "got nil input",
+			arg:           (*corev1.Node)(nil),
+			want:          false,
+			wantErr:       true,
+			wantRatio:     -1,
+			wantOptOutQOS: sets.NewString(),
+		},
+		{
+			name: "parse ratio failed",
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						extension.AnnotationNodeResourceAmplificationRatio: "[}",
+					},
+				},
+			},
+			want:          false,
+			wantErr:       true,
+			wantRatio:     -1,
+			wantOptOutQOS: sets.NewString(),
+		},
+		{
+			name: "parse opt-out failed",
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						extension.AnnotationNodeResourceAmplificationRatio:     `{"cpu": 2}`,
+						extension.AnnotationNodeCPUAmplificationCFSQuotaOptOut: "LS",
+					},
+				},
+			},
+			want:          false,
+			wantErr:       true,
+			wantRatio:     -1,
+			wantOptOutQOS: sets.NewString(),
+		},
+		{
+			name: "no cpu amplification ratio",
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						extension.AnnotationNodeResourceAmplificationRatio: `{"memory": 2}`,
+					},
+				},
+			},
+			want:          false,
+			wantErr:       false,
+			wantRatio:     -1,
+			wantOptOutQOS: sets.NewString(),
+		},
+		{
+			name: "update new ratio and opt-out",
+			arg: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						extension.AnnotationNodeResourceAmplificationRatio:     `{"cpu": 2}`,
+						extension.AnnotationNodeCPUAmplificationCFSQuotaOptOut: `["LS"]`,
+					},
+				},
+			},
+			want:          true,
+			wantErr:       false,
+			wantRatio:     2.0,
+			wantOptOutQOS: sets.NewString(string(extension.QoSLS)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPlugin()
+			got, gotErr := p.parseRule(tt.arg)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.wantRatio, p.rule.curRatio)
+			assert.Equal(t, tt.wantOptOutQOS, p.rule.optOutQOS)
+		})
+	}
+}
+
+func TestPlugin_ruleUpdateCb(t *testing.T) {
+	testLSPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ls-pod",
+			UID:  "abc123",
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test-ls-container",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-ls-container",
+					ContainerID: "containerd://testxxx",
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		rule         *Rule
+		cfsQuota     string
+		wantCFSQuota string
+		wantErr      bool
+	}{
+		{
+			name:    "rule is uninitialized",
+			rule:    nil,
+			wantErr: false,
+		},
+		{
+			name:         "scale the cfs quota",
+			rule:         &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString()},
+			cfsQuota:     "100000",
+			wantCFSQuota: "50000",
+			wantErr:      false,
+		},
+		{
+			name:         "restore the cfs quota of the opted-out qos",
+			rule:         &Rule{enable: true, curRatio: 2.0, optOutQOS: sets.NewString(string(extension.QoSLS))},
+			cfsQuota:     "50000",
+			wantCFSQuota: "100000",
+			wantErr:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			system.SetupCgroupPathFormatter(system.Systemd)
+			helper.WriteCgroupFileContents("kubepods.slice/kubepods-podabc123.slice", system.CPUCFSQuota, tt.cfsQuota)
+			helper.WriteCgroupFileContents("kubepods.slice/kubepods-podabc123.slice/cri-containerd-testxxx.scope", system.CPUCFSQuota, tt.cfsQuota)
+
+			p := newPlugin()
+			p.executor = resourceexecutor.NewTestResourceExecutor()
+			p.executor.Run(stopCh)
+			p.rule = tt.rule
+			target := &statesinformer.CallbackTarget{
+				Pods: []*statesinformer.PodMeta{
+					{
+						CgroupDir: "/kubepods.slice/kubepods-podabc123.slice/",
+						Pod:       testLSPod,
+					},
+				},
+			}
+			gotErr := p.ruleUpdateCb(target)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			if tt.rule != nil {
+				podCFSQuota := helper.ReadCgroupFileContents("kubepods.slice/kubepods-podabc123.slice", system.CPUCFSQuota)
+				assert.Equal(t, tt.wantCFSQuota, podCFSQuota)
+				containerCFSQuota := helper.ReadCgroupFileContents("kubepods.slice/kubepods-podabc123.slice/cri-containerd-testxxx.scope", system.CPUCFSQuota)
+				assert.Equal(t, tt.wantCFSQuota, containerCFSQuota)
+			}
+		})
+	}
+}
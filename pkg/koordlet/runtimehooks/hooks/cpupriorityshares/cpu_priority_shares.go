@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpupriorityshares
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	name        = "CPUPriorityShares"
+	description = "scale cpu shares by the koordinator sub-priority for LS pod"
+)
+
+var podQOSConditions = []string{string(extension.QoSLS)}
+
+type Plugin struct {
+	rule     *Rule
+	executor resourceexecutor.ResourceUpdateExecutor
+}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = newPlugin()
+	}
+	return singleton
+}
+
+func newPlugin() *Plugin {
+	return &Plugin{
+		rule: newRule(),
+	}
+}
+
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule),
+		rule.WithUpdateCallback(p.ruleUpdateCb))
+	// the shares are calculated from the pod spec, which is only provided in the reconciler mode
+	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.CPUShares, description+" (pod cpu shares)",
+		p.AdjustPodCPUShares, reconciler.PodQOSFilter(), podQOSConditions...)
+	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUShares, description+" (container cpu shares)",
+		p.AdjustContainerCPUShares, reconciler.PodQOSFilter(), podQOSConditions...)
+	p.executor = op.Executor
+}
+
+func (p *Plugin) AdjustPodCPUShares(proto protocol.HooksProtocol) error {
+	podCtx := proto.(*protocol.PodContext)
+	if podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	if podCtx.Request.Resources == nil { // currently only reconciler mode provides Resources in ctx
+		return nil
+	}
+
+	cpuShares, err := p.getCPUShares(podCtx.Request.Labels, podCtx.Request.Resources.CPUShares)
+	if err != nil {
+		return fmt.Errorf("failed to get cpu shares for pod %s/%s, err: %w",
+			podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name, err)
+	}
+	if cpuShares == nil {
+		return nil
+	}
+	klog.V(6).Infof("plugin %s adjusts pod %s/%s cpu shares from %d to %d",
+		name, podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name, *podCtx.Request.Resources.CPUShares, *cpuShares)
+	podCtx.Response.Resources.CPUShares = cpuShares
+	return nil
+}
+
+func (p *Plugin) AdjustContainerCPUShares(proto protocol.HooksProtocol) error {
+	containerCtx := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin %s", name)
+	}
+	if containerCtx.Request.Resources == nil { // currently only reconciler mode provides Resources in ctx
+		return nil
+	}
+
+	cpuShares, err := p.getCPUShares(containerCtx.Request.PodLabels, containerCtx.Request.Resources.CPUShares)
+	if err != nil {
+		return fmt.Errorf("failed to get cpu shares for container %s/%s/%s, err: %w",
+			containerCtx.Request.PodMeta.Namespace, containerCtx.Request.PodMeta.Name,
+			containerCtx.Request.ContainerMeta.Name, err)
+	}
+	if cpuShares == nil {
+		return nil
+	}
+	klog.V(6).Infof("plugin %s adjusts container %s/%s/%s cpu shares from %d to %d", name,
+		containerCtx.Request.PodMeta.Namespace, containerCtx.Request.PodMeta.Name,
+		containerCtx.Request.ContainerMeta.Name, *containerCtx.Request.Resources.CPUShares, *cpuShares)
+	containerCtx.Response.Resources.CPUShares = cpuShares
+	return nil
+}
+
+// getCPUShares returns the cpu shares scaled by the weight of the sub-priority bucket. The original shares are
+// returned if the pod matches no bucket or the rule is disabled, so the scaled shares are restored.
+// It returns nil if the rule is not parsed yet.
+func (p *Plugin) getCPUShares(podLabels map[string]string, originalCPUShares *int64) (*int64, error) {
+	if originalCPUShares == nil || !p.rule.IsParsed() {
+		return nil, nil
+	}
+	subPriority, err := extension.GetPodSubPriority(podLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	cpuShares := *originalCPUShares
+	if weightPercent, ok := p.rule.GetWeightPercent(subPriority); ok {
+		cpuShares = scaleCPUShares(cpuShares, weightPercent)
+	}
+	return &cpuShares, nil
+}
+
+func scaleCPUShares(cpuShares int64, weightPercent int64) int64 {
+	scaled := cpuShares * weightPercent / 100
+	if scaled < sysutil.CPUSharesMinValue {
+		return sysutil.CPUSharesMinValue
+	}
+	if scaled > sysutil.CPUSharesMaxValue {
+		return sysutil.CPUSharesMaxValue
+	}
+	return scaled
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpupriorityshares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		p := Object()
+		assert.NotNil(t, p)
+	})
+}
+
+func TestPlugin_Register(t *testing.T) {
+	t.Run("test not panic", func(t *testing.T) {
+		p := newPlugin()
+		p.Register(hooks.Options{})
+	})
+}
+
+func newTestRule(weights ...slov1alpha1.CPUPriorityShareWeight) *Rule {
+	r := newRule()
+	r.UpdateRule(weights)
+	return r
+}
+
+func TestPluginAdjustPodCPUShares(t *testing.T) {
+	testWeights := []slov1alpha1.CPUPriorityShareWeight{
+		{MinPriority: 0, WeightPercent: 100},
+		{MinPriority: 100, WeightPercent: 200},
+	}
+	tests := []struct {
+		name          string
+		rule          *Rule
+		arg           *protocol.PodContext
+		wantErr       bool
+		wantCPUShares *int64
+	}{
+		{
+			name: "no resources to adjust",
+			rule: newTestRule(testWeights...),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{},
+			},
+		},
+		{
+			name: "rule not parsed",
+			rule: newRule(),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Resources: &protocol.Resources{CPUShares: pointer.Int64(1024)},
+				},
+			},
+		},
+		{
+			name: "restore the shares when rule disabled",
+			rule: newTestRule(),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodPriority: "200",
+					},
+					Resources: &protocol.Resources{CPUShares: pointer.Int64(1024)},
+				},
+			},
+			wantCPUShares: pointer.Int64(1024),
+		},
+		{
+			name: "scale the shares by the matched bucket",
+			rule: newTestRule(testWeights...),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodPriority: "200",
+					},
+					Resources: &protocol.Resources{CPUShares: pointer.Int64(1024)},
+				},
+			},
+			wantCPUShares: pointer.Int64(2048),
+		},
+		{
+			name: "keep the shares when no bucket matched",
+			rule: newTestRule(testWeights...),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodPriority: "-1",
+					},
+					Resources: &protocol.Resources{CPUShares: pointer.Int64(1024)},
+				},
+			},
+			wantCPUShares: pointer.Int64(1024),
+		},
+		{
+			name: "invalid sub-priority",
+			rule: newTestRule(testWeights...),
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						extension.LabelPodPriority: "invalid",
+					},
+					Resources: &protocol.Resources{CPUShares: pointer.Int64(1024)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{rule: tt.rule}
+			err := p.AdjustPodCPUShares(tt.arg)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantCPUShares, tt.arg.Response.Resources.CPUShares)
+		})
+	}
+
+	p := &Plugin{rule: newTestRule(testWeights...)}
+	assert.Error(t, p.AdjustPodCPUShares((*protocol.PodContext)(nil)))
+}
+
+func TestPluginAdjustContainerCPUShares(t *testing.T) {
+	p := &Plugin{rule: newTestRule(slov1alpha1.CPUPriorityShareWeight{MinPriority: 0, WeightPercent: 50})}
+	assert.Error(t, p.AdjustContainerCPUShares((*protocol.ContainerContext)(nil)))
+
+	containerCtx := &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodLabels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+		},
+	}
+	assert.NoError(t, p.AdjustContainerCPUShares(containerCtx))
+	assert.Nil(t, containerCtx.Response.Resources.CPUShares)
+
+	containerCtx.Request.Resources = &protocol.Resources{CPUShares: pointer.Int64(1024)}
+	assert.NoError(t, p.AdjustContainerCPUShares(containerCtx))
+	assert.Equal(t, pointer.Int64(512), containerCtx.Response.Resources.CPUShares)
+}
+
+func Test_scaleCPUShares(t *testing.T) {
+	assert.Equal(t, int64(1536), scaleCPUShares(1024, 150))
+	assert.Equal(t, int64(2), scaleCPUShares(2, 50))
+	assert.Equal(t, int64(262144), scaleCPUShares(262144, 200))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpupriorityshares
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+type Rule struct {
+	lock   sync.RWMutex
+	parsed bool
+	// weights are the sub-priority buckets sorted by the MinPriority in the descending order
+	weights []slov1alpha1.CPUPriorityShareWeight
+}
+
+func newRule() *Rule {
+	return &Rule{}
+}
+
+func (r *Rule) IsParsed() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.parsed
+}
+
+// GetWeightPercent returns the weight of the bucket with the largest MinPriority no greater than the sub-priority.
+func (r *Rule) GetWeightPercent(subPriority int32) (int64, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, weight := range r.weights {
+		if weight.MinPriority <= subPriority {
+			return weight.WeightPercent, true
+		}
+	}
+	return 0, false
+}
+
+func (r *Rule) UpdateRule(weights []slov1alpha1.CPUPriorityShareWeight) bool {
+	sorted := make([]slov1alpha1.CPUPriorityShareWeight, 0, len(weights))
+	for _, weight := range weights {
+		if weight.WeightPercent <= 0 {
+			klog.V(4).Infof("ignore invalid cpu priority share weight %+v for %s", weight, name)
+			continue
+		}
+		sorted = append(sorted, weight)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].MinPriority > sorted[j].MinPriority
+	})
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.parsed && reflect.DeepEqual(r.weights, sorted) {
+		return false
+	}
+	r.parsed = true
+	r.weights = sorted
+	klog.V(6).Infof("update %s rule to weights %+v", name, sorted)
+	return true
+}
+
+func (p *Plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO, ok := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	if !ok {
+		return false, fmt.Errorf("parse format for hook plugin %v failed, expect: %v, got: %T",
+			name, "*slov1alpha1.NodeSLOSpec", mergedNodeSLOIf)
+	}
+
+	// the weights take effect only when the cpu qos of the LS class is enabled
+	var weights []slov1alpha1.CPUPriorityShareWeight
+	if strategy := mergedNodeSLO.ResourceQOSStrategy; strategy != nil && strategy.LSClass != nil &&
+		strategy.LSClass.CPUQOS != nil && strategy.LSClass.CPUQOS.Enable != nil && *strategy.LSClass.CPUQOS.Enable {
+		weights = strategy.LSClass.CPUQOS.PriorityShareWeights
+	}
+
+	isUpdated := p.rule.UpdateRule(weights)
+	if isUpdated {
+		klog.V(4).Infof("runtime hook plugin %s update rule, priority share weights %+v", name, weights)
+	}
+	return isUpdated, nil
+}
+
+func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
+	if target == nil {
+		klog.Warningf("callback target is nil")
+		return nil
+	}
+	filter := reconciler.PodQOSFilter()
+	for _, podMeta := range target.Pods {
+		if qos := extension.QoSClass(filter.Filter(podMeta)); qos != extension.QoSLS {
+			continue
+		}
+		if !podMeta.IsRunningOrPending() {
+			continue
+		}
+
+		// pod-level
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		if err := p.AdjustPodCPUShares(podCtx); err != nil {
+			klog.V(4).Infof("failed to adjust pod cpu shares during callback %s, pod %s, err: %s",
+				name, podMeta.Key(), err)
+			continue
+		}
+		podCtx.ReconcilerDone(p.executor)
+
+		// container-level
+		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+			containerCtx := &protocol.ContainerContext{}
+			containerCtx.FromReconciler(podMeta, containerStat.Name, false)
+			if err := p.AdjustContainerCPUShares(containerCtx); err != nil {
+				klog.V(4).Infof("failed to adjust container cpu shares during callback %s, container %s/%s, err: %s",
+					name, podMeta.Key(), containerStat.Name, err)
+				continue
+			}
+			containerCtx.ReconcilerDone(p.executor)
+		}
+	}
+	return nil
+}
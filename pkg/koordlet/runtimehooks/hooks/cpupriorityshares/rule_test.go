@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpupriorityshares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestRule(t *testing.T) {
+	r := newRule()
+	assert.False(t, r.IsParsed())
+	_, ok := r.GetWeightPercent(0)
+	assert.False(t, ok)
+
+	weights := []slov1alpha1.CPUPriorityShareWeight{
+		{MinPriority: 0, WeightPercent: 100},
+		{MinPriority: 1000, WeightPercent: 400},
+		{MinPriority: 500, WeightPercent: 200},
+		{MinPriority: 2000, WeightPercent: 0},
+	}
+	assert.True(t, r.UpdateRule(weights))
+	assert.True(t, r.IsParsed())
+	assert.False(t, r.UpdateRule(weights))
+
+	for subPriority, want := range map[int32]int64{0: 100, 499: 100, 500: 200, 1000: 400, 3000: 400} {
+		got, ok := r.GetWeightPercent(subPriority)
+		assert.True(t, ok)
+		assert.Equal(t, want, got, "sub-priority %d", subPriority)
+	}
+	_, ok = r.GetWeightPercent(-1)
+	assert.False(t, ok)
+
+	assert.True(t, r.UpdateRule(nil))
+	_, ok = r.GetWeightPercent(1000)
+	assert.False(t, ok)
+}
+
+func TestPlugin_parseRule(t *testing.T) {
+	newNodeSLOSpec := func(enable bool, weights ...slov1alpha1.CPUPriorityShareWeight) *slov1alpha1.NodeSLOSpec {
+		return &slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				LSClass: &slov1alpha1.ResourceQOS{
+					CPUQOS: &slov1alpha1.CPUQOSCfg{
+						Enable: pointer.Bool(enable),
+						CPUQOS: slov1alpha1.CPUQOS{
+							PriorityShareWeights: weights,
+						},
+					},
+				},
+			},
+		}
+	}
+	testWeight := slov1alpha1.CPUPriorityShareWeight{MinPriority: 100, WeightPercent: 200}
+
+	p := newPlugin()
+	_, err := p.parseRule(&slov1alpha1.NodeSLO{})
+	assert.Error(t, err)
+
+	got, err := p.parseRule(newNodeSLOSpec(false, testWeight))
+	assert.NoError(t, err)
+	assert.True(t, got)
+	_, ok := p.rule.GetWeightPercent(100)
+	assert.False(t, ok)
+
+	got, err = p.parseRule(newNodeSLOSpec(true, testWeight))
+	assert.NoError(t, err)
+	assert.True(t, got)
+	weightPercent, ok := p.rule.GetWeightPercent(100)
+	assert.True(t, ok)
+	assert.Equal(t, int64(200), weightPercent)
+
+	got, err = p.parseRule(newNodeSLOSpec(true, testWeight))
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestPlugin_ruleUpdateCb(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	system.SetupCgroupPathFormatter(system.Systemd)
+	defer system.SetupCgroupPathFormatter(system.Cgroupfs)
+	podDir := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podabc123.slice"
+	containerDir := podDir + "/cri-containerd-testxxx.scope"
+	helper.WriteCgroupFileContents(podDir, system.CPUShares, "1024")
+	helper.WriteCgroupFileContents(containerDir, system.CPUShares, "1024")
+
+	p := newPlugin()
+	p.executor = resourceexecutor.NewTestResourceExecutor()
+	p.executor.Run(stopCh)
+	p.rule.UpdateRule([]slov1alpha1.CPUPriorityShareWeight{
+		{MinPriority: 0, WeightPercent: 100},
+		{MinPriority: 100, WeightPercent: 300},
+	})
+
+	assert.NoError(t, p.ruleUpdateCb(nil))
+	pods := []*statesinformer.PodMeta{
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-be-pod",
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSBE),
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+		{
+			CgroupDir: "/" + podDir + "/",
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ls-pod",
+					UID:  "abc123",
+					Labels: map[string]string{
+						extension.LabelPodQoS:      string(extension.QoSLS),
+						extension.LabelPodPriority: "200",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "test-ls-container",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("1"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase:    corev1.PodRunning,
+					QOSClass: corev1.PodQOSBurstable,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name:        "test-ls-container",
+							ContainerID: "containerd://testxxx",
+						},
+					},
+				},
+			},
+		},
+	}
+	err := p.ruleUpdateCb(&statesinformer.CallbackTarget{Pods: pods})
+	assert.NoError(t, err)
+	assert.Equal(t, "3072", helper.ReadCgroupFileContents(podDir, system.CPUShares))
+	assert.Equal(t, "3072", helper.ReadCgroupFileContents(containerDir, system.CPUShares))
+
+	// restore the shares when the rule is disabled
+	p.rule.UpdateRule(nil)
+	err = p.ruleUpdateCb(&statesinformer.CallbackTarget{Pods: pods})
+	assert.NoError(t, err)
+	assert.Equal(t, "1024", helper.ReadCgroupFileContents(podDir, system.CPUShares))
+	assert.Equal(t, "1024", helper.ReadCgroupFileContents(containerDir, system.CPUShares))
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
@@ -33,17 +34,31 @@ import (
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 const (
 	name        = "CPUSetAllocator"
 	description = "set cpuset value by pod allocation"
+
+	ruleNameForNodeSLO  = name + " (nodeSLO)"
+	ruleNameForNodeMeta = name + " (nodeMeta)"
 )
 
 type cpusetPlugin struct {
 	rule        *cpusetRule
 	ruleRWMutex sync.RWMutex
-	executor    resourceexecutor.ResourceUpdateExecutor
+	// cfsQuotaFree indicates whether the cfs quota of the cpuset pods is removed, nil means the NodeSLO not parsed
+	cfsQuotaFree *bool
+	// drainedNUMANodes are the NUMA nodes drained for maintenance, which the shared pods move the memory away from
+	drainedNUMANodes []int
+	executor         resourceexecutor.ResourceUpdateExecutor
+	// memoryPolicyHookPath is the OCI hook applying the interleave memory policy, which is skipped if empty.
+	// The hook is only injected in the NRI mode, while the proxy mode and the reconciler only set the cpuset.mems.
+	memoryPolicyHookPath string
+	// sharePoolCompactor rate-limits the expansion of the share pools if the SharedPoolCompaction is enabled.
+	sharePoolCompactor sharePoolCompactor
 }
 
 var (
@@ -55,10 +70,16 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	klog.V(5).Infof("register hook %v", name)
 	hooks.Register(rmconfig.PreCreateContainer, name, description, p.SetContainerCPUSetAndUnsetCFS)
 	hooks.Register(rmconfig.PreUpdateContainerResources, name, description, p.SetContainerCPUSetAndUnsetCFS)
-	hooks.Register(rmconfig.PreRunPodSandbox, name, "unset pod cpu quota if needed", UnsetPodCPUQuota)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, "unset pod cpu quota if needed", p.UnsetPodCPUQuota)
 	rule.Register(name, description,
 		rule.WithParseFunc(statesinformer.RegisterTypeNodeTopology, p.parseRule),
 		rule.WithUpdateCallback(p.ruleUpdateCb))
+	rule.Register(ruleNameForNodeSLO, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRuleForNodeSLO),
+		rule.WithUpdateCallback(p.ruleUpdateCbForNodeSLO))
+	rule.Register(ruleNameForNodeMeta, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeMetadata, p.parseRuleForNodeMeta),
+		rule.WithUpdateCallback(p.ruleUpdateCbForNodeMeta))
 
 	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUSet,
 		"set container cpuset and unset container cpu quota if needed for cpuset pod",
@@ -67,7 +88,7 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 		"set sandbox container cpuset and unset container cpu quota if needed for cpuset pod",
 		p.SetContainerCPUSetAndUnsetCFS, reconciler.PodQOSFilter(), cpusetPodQOSConditions...)
 	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.CPUCFSQuota,
-		"unset pod cpu quota if needed for cpuset pod", UnsetPodCPUQuota,
+		"unset pod cpu quota if needed for cpuset pod", p.UnsetPodCPUQuota,
 		reconciler.PodQOSFilter(), cpusetPodQOSConditions...)
 
 	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUSet,
@@ -80,6 +101,7 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	reconciler.RegisterHostAppReconciler(sysutil.CPUSet, "set host application cpuset",
 		p.SetHostAppCPUSet, &reconciler.ReconcilerOption{})
 	p.executor = op.Executor
+	p.memoryPolicyHookPath = op.MemoryPolicyHookPath
 }
 
 var singleton *cpusetPlugin
@@ -99,7 +121,7 @@ func (p *cpusetPlugin) SetContainerCPUSetAndUnsetCFS(proto protocol.HooksProtoco
 	}
 
 	// unset container-level cpu.cfs_quota_us if needed
-	return UnsetContainerCPUQuota(proto)
+	return p.UnsetContainerCPUQuota(proto)
 }
 
 func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
@@ -110,6 +132,15 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 	containerReq := containerCtx.Request
 	klog.V(5).Infof("getting container cpuset for %v/%v", containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
 
+	if err := p.setContainerMems(containerCtx, false); err != nil {
+		return err
+	}
+
+	// cpuset of the container bound individually in the multi-container pod
+	if ok, err := p.setContainerCPUSetByContainerAllocation(containerCtx); err != nil || ok {
+		return err
+	}
+
 	// cpuset from pod annotation (LSE, LSR)
 	if cpusetVal, err := util.GetCPUSetFromPod(containerReq.PodAnnotations); err != nil {
 		return err
@@ -142,6 +173,160 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 	return nil
 }
 
+// setContainerCPUSetByContainerAllocation sets the cpuset of the container in the pod whose containers are bound
+// individually. The containers not bound run in the share pool, while the sandbox keeps the cpuset of the pod.
+// It returns whether the cpuset is handled.
+func (p *cpusetPlugin) setContainerCPUSetByContainerAllocation(containerCtx *protocol.ContainerContext) (bool, error) {
+	containerReq := containerCtx.Request
+	if containerReq.ContainerMeta.Sandbox {
+		return false, nil
+	}
+	resourceStatus, err := annotation.LenientParser.ParseResourceStatus(containerReq.PodAnnotations)
+	if err != nil {
+		return false, err
+	}
+	cpusetVal, ok := resourceStatus.GetContainerCPUSet(containerReq.ContainerMeta.Name)
+	if !ok {
+		return false, nil
+	}
+	if cpusetVal == "" {
+		r := p.getRule()
+		if r == nil {
+			klog.V(5).Infof("hook plugin rule is nil, skip cpuset of shared container %v/%v",
+				containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+			return true, nil
+		}
+		if cpusetVal = r.getSharePoolCPUSet(resourceStatus); cpusetVal == "" {
+			klog.V(5).Infof("share pool is empty, skip cpuset of shared container %v/%v",
+				containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+			return true, nil
+		}
+	}
+	containerCtx.Response.Resources.CPUSet = pointer.String(cpusetVal)
+	klog.V(5).Infof("get cpuset %v for container %v/%v from container allocation", cpusetVal,
+		containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+	return true, nil
+}
+
+// setContainerMems sets the cpuset.mems of the container by the interleaved memory policy, the memory tiers and the
+// drained NUMA nodes in order. If restore is set, the cpuset.mems of the shared container is reset to all the NUMA
+// nodes even if no NUMA node is drained.
+func (p *cpusetPlugin) setContainerMems(containerCtx *protocol.ContainerContext, restore bool) error {
+	// cpuset.mems spread over all the allocated NUMA nodes for the interleaved memory policy
+	interleaved, err := p.setContainerInterleaveMems(containerCtx)
+	if err != nil {
+		return err
+	}
+	// cpuset.mems from the NUMA nodes of the allocated memory tiers
+	if !interleaved && features.DefaultKoordletFeatureGate.Enabled(features.HeterogeneousMemory) {
+		if err = setContainerCPUSetMems(containerCtx); err != nil {
+			return err
+		}
+	}
+	// cpuset.mems out of the NUMA nodes drained for maintenance
+	return p.setContainerDrainedMems(containerCtx, restore)
+}
+
+// setContainerDrainedMems moves the cpuset.mems of the shared container away from the NUMA nodes drained for
+// maintenance. The containers bound to the CPUs or the NUMA nodes are migrated by koord-descheduler instead, and the
+// cpuset.mems already set by the memory policies is kept.
+func (p *cpusetPlugin) setContainerDrainedMems(containerCtx *protocol.ContainerContext, restore bool) error {
+	if containerCtx.Response.Resources.CPUSetMems != nil {
+		return nil
+	}
+	r := p.getRule()
+	drainedNUMANodes := p.getDrainedNUMANodes()
+	if r == nil || len(r.numaNodes) == 0 || len(drainedNUMANodes) == 0 && !restore {
+		return nil
+	}
+	containerReq := containerCtx.Request
+	resourceStatus, err := annotation.LenientParser.ParseResourceStatus(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	if resourceStatus.CPUSet != "" || len(resourceStatus.NUMANodeResources) > 0 {
+		return nil
+	}
+	numaNodes := make([]int, 0, len(r.numaNodes))
+	for _, numaNode := range r.numaNodes {
+		if !containsNUMANode(drainedNUMANodes, numaNode) {
+			numaNodes = append(numaNodes, numaNode)
+		}
+	}
+	if len(numaNodes) == 0 {
+		klog.V(4).Infof("all NUMA nodes are drained, keep cpuset mems for container %v/%v",
+			containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+		return nil
+	}
+	mems := cpuset.NewCPUSet(numaNodes...).String()
+	containerCtx.Response.Resources.CPUSetMems = pointer.String(mems)
+	klog.V(5).Infof("get cpuset mems %v for container %v/%v out of drained NUMA nodes %v", mems,
+		containerReq.PodMeta.String(), containerReq.ContainerMeta.Name, drainedNUMANodes)
+	return nil
+}
+
+func containsNUMANode(numaNodes []int, numaNode int) bool {
+	for _, v := range numaNodes {
+		if v == numaNode {
+			return true
+		}
+	}
+	return false
+}
+
+// setContainerCPUSetMems binds the memory of the container to the NUMA nodes where the DRAM and the slow memory are
+// allocated to the pod, like the `numactl --membind`, and keeps the cpuset.mems unchanged if no memory tier requested.
+func setContainerCPUSetMems(containerCtx *protocol.ContainerContext) error {
+	resourceStatus, err := annotation.LenientParser.ParseResourceStatus(containerCtx.Request.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	numaNodes := apiext.GetMemoryTierNUMANodes(resourceStatus)
+	if len(numaNodes) <= 0 {
+		return nil
+	}
+	mems := cpuset.NewCPUSet(numaNodes...).String()
+	containerCtx.Response.Resources.CPUSetMems = pointer.String(mems)
+	klog.V(5).Infof("get cpuset mems %v for container %v/%v from pod memory tiers", mems,
+		containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+	return nil
+}
+
+// setContainerInterleaveMems spreads the cpuset.mems of the container over all the NUMA nodes allocated to the pod
+// requesting the interleave memory policy, and injects the OCI hook to interleave the memory like the
+// `numactl --interleave` if configured. It returns whether the cpuset.mems is set.
+func (p *cpusetPlugin) setContainerInterleaveMems(containerCtx *protocol.ContainerContext) (bool, error) {
+	resourceSpec, err := annotation.LenientParser.ParseResourceSpec(containerCtx.Request.PodAnnotations)
+	if err != nil {
+		return false, err
+	}
+	if resourceSpec.MemoryPolicy != apiext.MemoryPolicyInterleave {
+		return false, nil
+	}
+	resourceStatus, err := annotation.LenientParser.ParseResourceStatus(containerCtx.Request.PodAnnotations)
+	if err != nil {
+		return false, err
+	}
+	if len(resourceStatus.NUMANodeResources) <= 0 {
+		return false, nil
+	}
+	numaNodes := make([]int, 0, len(resourceStatus.NUMANodeResources))
+	for _, numaNode := range resourceStatus.NUMANodeResources {
+		numaNodes = append(numaNodes, int(numaNode.Node))
+	}
+	mems := cpuset.NewCPUSet(numaNodes...).String()
+	containerCtx.Response.Resources.CPUSetMems = pointer.String(mems)
+	if p.memoryPolicyHookPath != "" {
+		containerCtx.Response.AddContainerHooks = append(containerCtx.Response.AddContainerHooks, protocol.OCIHook{
+			Path: p.memoryPolicyHookPath,
+			Args: []string{p.memoryPolicyHookPath, "--mode=interleave", "--nodes=" + mems},
+		})
+	}
+	klog.V(5).Infof("get cpuset mems %v for container %v/%v with interleave memory policy", mems,
+		containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+	return true, nil
+}
+
 func (p *cpusetPlugin) SetHostAppCPUSet(proto protocol.HooksProtocol) error {
 	hostAppCtx, _ := proto.(*protocol.HostAppContext)
 	if hostAppCtx == nil {
@@ -168,7 +353,7 @@ func (p *cpusetPlugin) SetHostAppCPUSet(proto protocol.HooksProtocol) error {
 	return nil
 }
 
-func UnsetPodCPUQuota(proto protocol.HooksProtocol) error {
+func (p *cpusetPlugin) UnsetPodCPUQuota(proto protocol.HooksProtocol) error {
 	podCtx := proto.(*protocol.PodContext)
 	if podCtx == nil {
 		return fmt.Errorf("pod protocol is nil for plugin %v", name)
@@ -180,32 +365,55 @@ func UnsetPodCPUQuota(proto protocol.HooksProtocol) error {
 	// https://github.com/koordinator-sh/koordinator/issues/489
 	if needUnset, err := util.IsPodCfsQuotaNeedUnset(req.Annotations); err != nil {
 		return err
-	} else if needUnset {
+	} else if needUnset && p.isCFSQuotaFree() {
 		podCtx.Response.Resources.CFSQuota = pointer.Int64(-1)
 		return nil
 	}
 
+	// restore the cfs quota from the pod spec in case the quota was unset before, e.g. the cpuset binding is lost
+	if req.Resources != nil && req.Resources.CFSQuota != nil {
+		podCtx.Response.Resources.CFSQuota = pointer.Int64(*req.Resources.CFSQuota)
+		return nil
+	}
+
 	// do nothing for cpushare pod
 	return nil
 }
 
-func UnsetContainerCPUQuota(proto protocol.HooksProtocol) error {
+func (p *cpusetPlugin) UnsetContainerCPUQuota(proto protocol.HooksProtocol) error {
 	containerCtx := proto.(*protocol.ContainerContext)
 	if containerCtx == nil {
 		return fmt.Errorf("container protocol is nil for plugin %v", name)
 	}
 	containerReq := containerCtx.Request
 
+	// the containers running in the share pool of the multi-container pod keep the cfs quota
+	if !containerReq.ContainerMeta.Sandbox {
+		resourceStatus, err := annotation.LenientParser.ParseResourceStatus(containerReq.PodAnnotations)
+		if err != nil {
+			return err
+		}
+		if cpusetVal, ok := resourceStatus.GetContainerCPUSet(containerReq.ContainerMeta.Name); ok && cpusetVal == "" {
+			return nil
+		}
+	}
+
 	// cpuset from pod annotation (LSE, LSR)
 	// NOTE: unset cfs quota for cpuset pods to avoid unexpected throttles.
 	// https://github.com/koordinator-sh/koordinator/issues/489
 	if needUnset, err := util.IsPodCfsQuotaNeedUnset(containerReq.PodAnnotations); err != nil {
 		return err
-	} else if needUnset {
+	} else if needUnset && p.isCFSQuotaFree() {
 		containerCtx.Response.Resources.CFSQuota = pointer.Int64(-1)
 		return nil
 	}
 
+	// restore the cfs quota from the container spec in case the quota was unset before
+	if containerReq.Resources != nil && containerReq.Resources.CFSQuota != nil {
+		containerCtx.Response.Resources.CFSQuota = pointer.Int64(*containerReq.Resources.CFSQuota)
+		return nil
+	}
+
 	// do nothing for cpushare pod
 	return nil
 }
@@ -21,9 +21,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
@@ -39,6 +42,14 @@ func getCPUSet(dirWithKube string, helper *system.FileTestUtil) string {
 	return helper.ReadCgroupFileContents(dirWithKube, system.CPUSet)
 }
 
+func initCPUSetMems(dirWithKube string, value string, helper *system.FileTestUtil) {
+	helper.WriteCgroupFileContents(dirWithKube, system.CPUSetMems, value)
+}
+
+func getCPUSetMems(dirWithKube string, helper *system.FileTestUtil) string {
+	return helper.ReadCgroupFileContents(dirWithKube, system.CPUSetMems)
+}
+
 func initCPUQuota(dirWithKube string, value string, helper *system.FileTestUtil) {
 	helper.WriteCgroupFileContents(dirWithKube, system.CPUCFSQuota, value)
 }
@@ -299,6 +310,91 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 			wantErr:    false,
 			wantCPUSet: pointer.String("0-7"),
 		},
+		{
+			name: "set cpu by container allocated",
+			fields: fields{
+				rule: nil,
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-5",
+					ContainerCPUSets: []ext.ContainerCPUSet{
+						{Name: "test-container", CPUSet: "2-3"},
+						{Name: "other-container", CPUSet: "4-5"},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						ContainerMeta: protocol.ContainerMeta{Name: "test-container"},
+						CgroupParent:  "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:    false,
+			wantCPUSet: pointer.String("2-3"),
+		},
+		{
+			name: "set cpu by share pool for container not allocated",
+			fields: fields{
+				rule: &cpusetRule{
+					sharePools: []ext.CPUSharedPool{
+						{
+							Socket: 0,
+							Node:   0,
+							CPUSet: "0-1,6-7",
+						},
+						{
+							Socket: 1,
+							Node:   1,
+							CPUSet: "8-15",
+						},
+					},
+				},
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-5",
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 0,
+						},
+					},
+					ContainerCPUSets: []ext.ContainerCPUSet{
+						{Name: "other-container", CPUSet: "2-5"},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						ContainerMeta: protocol.ContainerMeta{Name: "test-container"},
+						CgroupParent:  "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:    false,
+			wantCPUSet: pointer.String("0-1,6-7"),
+		},
+		{
+			name: "set cpu by pod allocated for sandbox of container allocated",
+			fields: fields{
+				rule: nil,
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-5",
+					ContainerCPUSets: []ext.ContainerCPUSet{
+						{Name: "other-container", CPUSet: "2-5"},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						ContainerMeta: protocol.ContainerMeta{Sandbox: true},
+						CgroupParent:  "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:    false,
+			wantCPUSet: pointer.String("2-5"),
+		},
 		{
 			name: "set cpu for origin besteffort pod",
 			fields: fields{
@@ -373,6 +469,281 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 	}
 }
 
+func Test_cpusetPlugin_SetContainerCPUSetMems(t *testing.T) {
+	tests := []struct {
+		name                string
+		heterogeneousMemory bool
+		podAlloc            *ext.ResourceStatus
+		wantCPUSet          *string
+		wantCPUSetMems      *string
+	}{
+		{
+			name:                "skip cpuset mems when feature disabled",
+			heterogeneousMemory: false,
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "2-4",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("3"),
+							ext.ResourceDRAM:   resource.MustParse("4Gi"),
+						},
+					},
+				},
+			},
+			wantCPUSet:     pointer.String("2-4"),
+			wantCPUSetMems: nil,
+		},
+		{
+			name:                "skip cpuset mems when no memory tier allocated",
+			heterogeneousMemory: true,
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "2-4",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("3"),
+						},
+					},
+				},
+			},
+			wantCPUSet:     pointer.String("2-4"),
+			wantCPUSetMems: nil,
+		},
+		{
+			name:                "set cpuset mems by allocated memory tiers",
+			heterogeneousMemory: true,
+			podAlloc: &ext.ResourceStatus{
+				CPUSet: "2-4",
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("3"),
+							ext.ResourceDRAM:   resource.MustParse("4Gi"),
+						},
+					},
+					{
+						Node: 2,
+						Resources: corev1.ResourceList{
+							ext.ResourceSlowMemory: resource.MustParse("16Gi"),
+						},
+					},
+				},
+			},
+			wantCPUSet:     pointer.String("2-4"),
+			wantCPUSetMems: pointer.String("0,2"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			defer testHelper.Cleanup()
+			enabled := features.DefaultKoordletFeatureGate.Enabled(features.HeterogeneousMemory)
+			err := features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+				string(features.HeterogeneousMemory): tt.heterogeneousMemory,
+			})
+			assert.NoError(t, err)
+			defer func() {
+				err = features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+					string(features.HeterogeneousMemory): enabled,
+				})
+				assert.NoError(t, err)
+			}()
+
+			p := &cpusetPlugin{
+				executor: resourceexecutor.NewResourceUpdateExecutor(),
+			}
+			containerCtx := &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					CgroupParent: "kubepods/test-pod/test-container/",
+					PodAnnotations: map[string]string{
+						ext.AnnotationResourceStatus: util.DumpJSON(tt.podAlloc),
+					},
+				},
+			}
+			initCPUSet(containerCtx.Request.CgroupParent, "", testHelper)
+			initCPUSetMems(containerCtx.Request.CgroupParent, "0-3", testHelper)
+
+			err = p.SetContainerCPUSet(containerCtx)
+			assert.NoError(t, err)
+			stop := make(chan struct{})
+			defer close(stop)
+			p.executor.Run(stop)
+
+			assert.Equal(t, tt.wantCPUSet, containerCtx.Response.Resources.CPUSet)
+			assert.Equal(t, tt.wantCPUSetMems, containerCtx.Response.Resources.CPUSetMems)
+			containerCtx.ReconcilerDone(p.executor)
+			if tt.wantCPUSetMems == nil {
+				assert.Equal(t, "0-3", getCPUSetMems(containerCtx.Request.CgroupParent, testHelper))
+			} else {
+				assert.Equal(t, *tt.wantCPUSetMems, getCPUSetMems(containerCtx.Request.CgroupParent, testHelper))
+			}
+		})
+	}
+}
+
+func Test_cpusetPlugin_SetContainerInterleaveMems(t *testing.T) {
+	podAlloc := &ext.ResourceStatus{
+		CPUSet: "2-4",
+		NUMANodeResources: []ext.NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+			{
+				Node: 1,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name                 string
+		memoryPolicy         ext.MemoryPolicy
+		memoryPolicyHookPath string
+		wantCPUSetMems       *string
+		wantHooks            []protocol.OCIHook
+	}{
+		{
+			name:           "skip cpuset mems without interleave memory policy",
+			memoryPolicy:   ext.MemoryPolicyDefault,
+			wantCPUSetMems: nil,
+		},
+		{
+			name:           "spread cpuset mems over all allocated NUMA nodes",
+			memoryPolicy:   ext.MemoryPolicyInterleave,
+			wantCPUSetMems: pointer.String("0-1"),
+		},
+		{
+			name:                 "inject mempolicy hook if configured",
+			memoryPolicy:         ext.MemoryPolicyInterleave,
+			memoryPolicyHookPath: "/usr/local/bin/mempolicy-hook",
+			wantCPUSetMems:       pointer.String("0-1"),
+			wantHooks: []protocol.OCIHook{
+				{
+					Path: "/usr/local/bin/mempolicy-hook",
+					Args: []string{"/usr/local/bin/mempolicy-hook", "--mode=interleave", "--nodes=0-1"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			defer testHelper.Cleanup()
+
+			p := &cpusetPlugin{
+				executor:             resourceexecutor.NewResourceUpdateExecutor(),
+				memoryPolicyHookPath: tt.memoryPolicyHookPath,
+			}
+			containerCtx := &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					CgroupParent: "kubepods/test-pod/test-container/",
+					PodAnnotations: map[string]string{
+						ext.AnnotationResourceSpec:   util.DumpJSON(&ext.ResourceSpec{MemoryPolicy: tt.memoryPolicy}),
+						ext.AnnotationResourceStatus: util.DumpJSON(podAlloc),
+					},
+				},
+			}
+			initCPUSet(containerCtx.Request.CgroupParent, "", testHelper)
+			initCPUSetMems(containerCtx.Request.CgroupParent, "0-3", testHelper)
+
+			err := p.SetContainerCPUSet(containerCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, pointer.String("2-4"), containerCtx.Response.Resources.CPUSet)
+			assert.Equal(t, tt.wantCPUSetMems, containerCtx.Response.Resources.CPUSetMems)
+			assert.Equal(t, tt.wantHooks, containerCtx.Response.AddContainerHooks)
+		})
+	}
+}
+
+func Test_cpusetPlugin_SetContainerDrainedMems(t *testing.T) {
+	testRule := &cpusetRule{
+		sharePools: []ext.CPUSharedPool{
+			{Socket: 0, Node: 0, CPUSet: "0-7"},
+			{Socket: 1, Node: 1, CPUSet: "8-15"},
+		},
+		numaNodes: []int{0, 1},
+	}
+	tests := []struct {
+		name             string
+		drainedNUMANodes []int
+		restore          bool
+		podAlloc         *ext.ResourceStatus
+		wantCPUSetMems   *string
+	}{
+		{
+			name:           "keep cpuset mems if no NUMA node drained",
+			wantCPUSetMems: nil,
+		},
+		{
+			name:           "restore cpuset mems to all NUMA nodes",
+			restore:        true,
+			wantCPUSetMems: pointer.String("0-1"),
+		},
+		{
+			name:             "move cpuset mems of shared pod away from drained NUMA node",
+			drainedNUMANodes: []int{1},
+			wantCPUSetMems:   pointer.String("0"),
+		},
+		{
+			name:             "keep cpuset mems if all NUMA nodes drained",
+			drainedNUMANodes: []int{0, 1},
+			wantCPUSetMems:   nil,
+		},
+		{
+			name:             "skip cpuset bound pod",
+			drainedNUMANodes: []int{1},
+			podAlloc:         &ext.ResourceStatus{CPUSet: "8-9"},
+			wantCPUSetMems:   nil,
+		},
+		{
+			name:             "skip NUMA bound pod",
+			drainedNUMANodes: []int{1},
+			podAlloc: &ext.ResourceStatus{
+				NUMANodeResources: []ext.NUMANodeResource{{Node: 1}},
+			},
+			wantCPUSetMems: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			defer testHelper.Cleanup()
+
+			p := &cpusetPlugin{
+				rule:             testRule,
+				drainedNUMANodes: tt.drainedNUMANodes,
+				executor:         resourceexecutor.NewResourceUpdateExecutor(),
+			}
+			containerCtx := &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					CgroupParent: "kubepods/test-pod/test-container/",
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLS),
+					},
+					PodAnnotations: map[string]string{},
+				},
+			}
+			if tt.podAlloc != nil {
+				containerCtx.Request.PodAnnotations[ext.AnnotationResourceStatus] = util.DumpJSON(tt.podAlloc)
+			}
+
+			err := p.setContainerMems(containerCtx, tt.restore)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCPUSetMems, containerCtx.Response.Resources.CPUSetMems)
+		})
+	}
+}
+
 func TestUnsetPodCPUQuota(t *testing.T) {
 	type args struct {
 		podAlloc *ext.ResourceStatus
@@ -483,7 +854,8 @@ func TestUnsetPodCPUQuota(t *testing.T) {
 				}
 			}
 
-			err := UnsetPodCPUQuota(podCtx)
+			p := &cpusetPlugin{}
+			err := p.UnsetPodCPUQuota(podCtx)
 			assert.Equal(t, err != nil, tt.wantErr)
 
 			if podCtx == nil {
@@ -510,6 +882,73 @@ func TestUnsetPodCPUQuota(t *testing.T) {
 	}
 }
 
+func TestUnsetCPUQuotaRestore(t *testing.T) {
+	cpusetAnnotations := map[string]string{
+		ext.AnnotationResourceStatus: util.DumpJSON(&ext.ResourceStatus{CPUSet: "2-5"}),
+	}
+	tests := []struct {
+		name         string
+		cfsQuotaFree *bool
+		annotations  map[string]string
+		specQuota    *int64
+		wantCPUQuota *int64
+	}{
+		{
+			name:         "unset cfs quota of cpuset pod by default",
+			annotations:  cpusetAnnotations,
+			specQuota:    pointer.Int64(400000),
+			wantCPUQuota: pointer.Int64(-1),
+		},
+		{
+			name:         "keep cfs quota of cpuset pod when cfs quota free disabled",
+			cfsQuotaFree: pointer.Bool(false),
+			annotations:  cpusetAnnotations,
+			specQuota:    pointer.Int64(400000),
+			wantCPUQuota: pointer.Int64(400000),
+		},
+		{
+			name:         "restore cfs quota when cpuset binding is lost",
+			cfsQuotaFree: pointer.Bool(true),
+			specQuota:    pointer.Int64(400000),
+			wantCPUQuota: pointer.Int64(400000),
+		},
+		{
+			name:         "not change cfs quota without spec in hook",
+			cfsQuotaFree: pointer.Bool(false),
+			annotations:  cpusetAnnotations,
+			wantCPUQuota: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &cpusetPlugin{cfsQuotaFree: tt.cfsQuotaFree}
+
+			podCtx := &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Annotations:  tt.annotations,
+					CgroupParent: "kubepods/pod-guaranteed-test-uid/",
+				},
+			}
+			containerCtx := &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					ContainerMeta:  protocol.ContainerMeta{Name: "test-container"},
+					PodAnnotations: tt.annotations,
+					CgroupParent:   "kubepods/pod-guaranteed-test-uid/test-container/",
+				},
+			}
+			if tt.specQuota != nil {
+				podCtx.Request.Resources = &protocol.Resources{CFSQuota: pointer.Int64(*tt.specQuota)}
+				containerCtx.Request.Resources = &protocol.Resources{CFSQuota: pointer.Int64(*tt.specQuota)}
+			}
+
+			assert.NoError(t, p.UnsetPodCPUQuota(podCtx))
+			assert.Equal(t, tt.wantCPUQuota, podCtx.Response.Resources.CFSQuota)
+			assert.NoError(t, p.UnsetContainerCPUQuota(containerCtx))
+			assert.Equal(t, tt.wantCPUQuota, containerCtx.Response.Resources.CFSQuota)
+		})
+	}
+}
+
 func TestUnsetContainerCPUQuota(t *testing.T) {
 	type args struct {
 		podAlloc *ext.ResourceStatus
@@ -578,6 +1017,25 @@ func TestUnsetContainerCPUQuota(t *testing.T) {
 			wantErr:      false,
 			wantCPUQuota: nil,
 		},
+		{
+			name: "not change cfs quota for container not allocated",
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-5",
+					ContainerCPUSets: []ext.ContainerCPUSet{
+						{Name: "other-container", CPUSet: "2-5"},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						ContainerMeta: protocol.ContainerMeta{Name: "test-container"},
+						CgroupParent:  "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:      false,
+			wantCPUQuota: nil,
+		},
 		{
 			name: "not change cfs quota for origin besteffort pod",
 			args: args{
@@ -607,7 +1065,8 @@ func TestUnsetContainerCPUQuota(t *testing.T) {
 				}
 			}
 
-			err := UnsetContainerCPUQuota(containerCtx)
+			p := &cpusetPlugin{}
+			err := p.UnsetContainerCPUQuota(containerCtx)
 			assert.Equal(t, err != nil, tt.wantErr)
 
 			if containerCtx == nil {
@@ -19,7 +19,9 @@ package cpuset
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -27,10 +29,12 @@ import (
 	"k8s.io/utils/pointer"
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
@@ -39,6 +43,8 @@ type cpusetRule struct {
 	sharePools      []ext.CPUSharedPool
 	beSharePools    []ext.CPUSharedPool
 	systemQOSCPUSet string
+	// numaNodes are the NUMA nodes of the node in the reported CPU topology
+	numaNodes []int
 }
 
 func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest) (*string, error) {
@@ -54,12 +60,18 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	}
 	podAnnotations := containerReq.PodAnnotations
 	podLabels := containerReq.PodLabels
-	podAlloc, err := ext.GetResourceStatus(podAnnotations)
+	podAlloc, err := annotation.LenientParser.ParseResourceStatus(podAnnotations)
 	if err != nil {
 		return nil, err
 	}
 
 	podQOSClass := ext.GetQoSClassByAttrs(podLabels, podAnnotations)
+	if podQOSClass == ext.QoSLS && podAlloc.PreferredCPUSet != "" && features.DefaultKoordletFeatureGate.Enabled(features.PreferredCPUSet) {
+		// LS pods in the Soft CPUBindMode, the cpuset is managed by the PreferredCPUSet strategy of qosmanager
+		klog.V(6).Infof("skip cpuset of preferred cpuset pod for container %v/%v",
+			containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+		return nil, nil
+	}
 	if len(podAlloc.NUMANodeResources) != 0 {
 		getCPUFromSharePoolByAllocFn := func(sharePools []ext.CPUSharedPool, alloc *ext.ResourceStatus) string {
 			cpusetList := make([]string, 0, len(alloc.NUMANodeResources))
@@ -96,14 +108,14 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 		klog.V(6).Infof("get cpuset from system qos rule for container %v/%v",
 			containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
 		return pointer.String(r.systemQOSCPUSet), nil
-	} else if podQOSClass == ext.QoSLS {
-		// LS pods use all share pool
+	} else if podQOSClass == ext.QoSLS || podAlloc.CPUBindDegraded {
+		// LS pods and the best-effort bound pods scheduled without CPU binding use all share pool
 		klog.V(6).Infof("get cpuset from all share pool for container %v/%v",
 			containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
 		return pointer.String(strings.Join(allSharePoolCPUs, ",")), nil
 	}
 
-	kubeQOS := util.GetKubeQoSByCgroupParent(containerReq.CgroupParent)
+	kubeQOS := util.GetPodKubeQoS(containerReq.CgroupParent, containerReq.PodKubeQOS, podQOSClass)
 	if kubeQOS == corev1.PodQOSBestEffort {
 		// besteffort pods including QoS=BE, clear cpuset of BE container to avoid conflict with kubelet static policy,
 		// which will pass cpuset in StartContainerRequest of CRI
@@ -125,6 +137,22 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	}
 }
 
+// getSharePoolCPUSet returns the share pool of the allocated NUMA nodes, or all the share pool if not allocated.
+func (r *cpusetRule) getSharePoolCPUSet(alloc *ext.ResourceStatus) string {
+	numaNodes := map[int32]bool{}
+	for _, numaNode := range alloc.NUMANodeResources {
+		numaNodes[numaNode.Node] = true
+	}
+	cpusetList := make([]string, 0, len(r.sharePools))
+	for _, nodeSharePool := range r.sharePools {
+		if len(numaNodes) > 0 && !numaNodes[nodeSharePool.Node] {
+			continue
+		}
+		cpusetList = append(cpusetList, nodeSharePool.CPUSet)
+	}
+	return strings.Join(cpusetList, ",")
+}
+
 func (r *cpusetRule) getHostAppCpuset(hostAppReq *protocol.HostAppRequest) (*string, error) {
 	if hostAppReq == nil {
 		return nil, nil
@@ -158,6 +186,10 @@ func (p *cpusetPlugin) parseRule(nodeTopoIf interface{}) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	cpuTopology, err := ext.GetCPUTopology(nodeTopo.Annotations)
+	if err != nil {
+		return false, err
+	}
 
 	systemQOSCPUSet := ""
 	systemQOSRes, err := ext.GetSystemQOSResource(nodeTopo.Annotations)
@@ -177,11 +209,29 @@ func (p *cpusetPlugin) parseRule(nodeTopoIf interface{}) (bool, error) {
 		sharePools:      cpuSharePools,
 		beSharePools:    beCPUSharePools,
 		systemQOSCPUSet: systemQOSCPUSet,
+		numaNodes:       getNUMANodes(cpuTopology),
 	}
 	updated := p.updateRule(newRule)
 	return updated, nil
 }
 
+// getNUMANodes returns the sorted NUMA nodes of the CPU topology, or nil if the topology is not reported.
+func getNUMANodes(cpuTopology *ext.CPUTopology) []int {
+	if cpuTopology == nil || len(cpuTopology.Detail) == 0 {
+		return nil
+	}
+	nodeSet := map[int]struct{}{}
+	for _, cpuInfo := range cpuTopology.Detail {
+		nodeSet[int(cpuInfo.Node)] = struct{}{}
+	}
+	numaNodes := make([]int, 0, len(nodeSet))
+	for numaNode := range nodeSet {
+		numaNodes = append(numaNodes, numaNode)
+	}
+	sort.Ints(numaNodes)
+	return numaNodes
+}
+
 func (p *cpusetPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 	if target == nil {
 		klog.Warningf("callback target is nil")
@@ -228,6 +278,9 @@ func (p *cpusetPlugin) getRule() *cpusetRule {
 		return nil
 	}
 	rule := *p.rule
+	if features.DefaultKoordletFeatureGate.Enabled(features.SharedPoolCompaction) {
+		rule.sharePools = p.sharePoolCompactor.compact(rule.sharePools, time.Now())
+	}
 	return &rule
 }
 
@@ -240,3 +293,137 @@ func (p *cpusetPlugin) updateRule(newRule *cpusetRule) bool {
 	}
 	return false
 }
+
+// parseRuleForNodeSLO parses whether the cfs quota of the cpuset pods is removed, which is configured in the LSRClass.
+func (p *cpusetPlugin) parseRuleForNodeSLO(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO, ok := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	if !ok {
+		return false, fmt.Errorf("parse format for hook plugin %v failed, expect: %v, got: %T",
+			ruleNameForNodeSLO, "*slov1alpha1.NodeSLOSpec", mergedNodeSLOIf)
+	}
+	cfsQuotaFree := true
+	if strategy := mergedNodeSLO.ResourceQOSStrategy; strategy != nil && strategy.LSRClass != nil &&
+		strategy.LSRClass.CPUQOS != nil && strategy.LSRClass.CPUQOS.CFSQuotaFree != nil {
+		cfsQuotaFree = *strategy.LSRClass.CPUQOS.CFSQuotaFree
+	}
+
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	// the quota is removed by default before the NodeSLO is parsed
+	updated := p.cfsQuotaFree == nil && !cfsQuotaFree || p.cfsQuotaFree != nil && *p.cfsQuotaFree != cfsQuotaFree
+	p.cfsQuotaFree = pointer.Bool(cfsQuotaFree)
+	if updated {
+		klog.V(4).Infof("runtime hook plugin %s update rule, cfs quota free %v", ruleNameForNodeSLO, cfsQuotaFree)
+	}
+	return updated, nil
+}
+
+// ruleUpdateCbForNodeSLO unsets or restores the cfs quota of the cpuset pods when the cfs quota free is switched.
+func (p *cpusetPlugin) ruleUpdateCbForNodeSLO(target *statesinformer.CallbackTarget) error {
+	if target == nil {
+		klog.Warningf("callback target is nil")
+		return nil
+	}
+	for _, podMeta := range target.Pods {
+		podQOS := ext.GetPodQoSClassRaw(podMeta.Pod)
+		if podQOS != ext.QoSLSE && podQOS != ext.QoSLSR {
+			continue
+		}
+
+		// pod-level first, since the container-level quota can not exceed the pod-level one
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		if err := p.UnsetPodCPUQuota(podCtx); err != nil {
+			klog.V(4).Infof("failed to set pod cfs quota during callback %v, err: %v", ruleNameForNodeSLO, err)
+			continue
+		}
+		podCtx.ReconcilerDone(p.executor)
+
+		// container-level
+		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+			containerCtx := &protocol.ContainerContext{}
+			containerCtx.FromReconciler(podMeta, containerStat.Name, false)
+			if err := p.UnsetContainerCPUQuota(containerCtx); err != nil {
+				klog.V(4).Infof("failed to set container cfs quota during callback %v, container %v, err: %v",
+					ruleNameForNodeSLO, containerStat.Name, err)
+				continue
+			}
+			containerCtx.ReconcilerDone(p.executor)
+		}
+	}
+	return nil
+}
+
+// parseRuleForNodeMeta parses the NUMA nodes drained for maintenance from the node annotation.
+func (p *cpusetPlugin) parseRuleForNodeMeta(nodeIf interface{}) (bool, error) {
+	node, ok := nodeIf.(*corev1.Node)
+	if !ok {
+		return false, fmt.Errorf("parse format for hook plugin %v failed, expect: %v, got: %T",
+			ruleNameForNodeMeta, "*corev1.Node", nodeIf)
+	}
+	drainedNUMANodes, err := ext.GetNodeDrainedNUMANodes(node.Annotations)
+	if err != nil {
+		return false, err
+	}
+	if len(drainedNUMANodes) == 0 {
+		drainedNUMANodes = nil
+	}
+	sort.Ints(drainedNUMANodes)
+
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	updated := !reflect.DeepEqual(p.drainedNUMANodes, drainedNUMANodes)
+	p.drainedNUMANodes = drainedNUMANodes
+	if updated {
+		klog.V(4).Infof("runtime hook plugin %s update rule, drained NUMA nodes %v", ruleNameForNodeMeta, drainedNUMANodes)
+	}
+	return updated, nil
+}
+
+// ruleUpdateCbForNodeMeta moves the cpuset.mems of the shared containers away from the drained NUMA nodes, or restores
+// it to all the NUMA nodes when the drain is finished.
+func (p *cpusetPlugin) ruleUpdateCbForNodeMeta(target *statesinformer.CallbackTarget) error {
+	if target == nil {
+		klog.Warningf("callback target is nil")
+		return nil
+	}
+	for _, podMeta := range target.Pods {
+		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+			containerCtx := &protocol.ContainerContext{}
+			containerCtx.FromReconciler(podMeta, containerStat.Name, false)
+			if err := p.setContainerMems(containerCtx, true); err != nil {
+				klog.V(4).Infof("failed to set container cpuset mems during callback %v, container %v, err: %v",
+					ruleNameForNodeMeta, containerStat.Name, err)
+				continue
+			}
+			if containerCtx.Response.Resources.CPUSetMems == nil {
+				continue
+			}
+			containerCtx.ReconcilerDone(p.executor)
+		}
+
+		sandboxContainerCtx := &protocol.ContainerContext{}
+		sandboxContainerCtx.FromReconciler(podMeta, "", true)
+		if err := p.setContainerMems(sandboxContainerCtx, true); err != nil {
+			klog.V(4).Infof("failed to set sandbox cpuset mems during callback %v, pod %v, err: %v",
+				ruleNameForNodeMeta, sandboxContainerCtx.Request.PodMeta.String(), err)
+			continue
+		}
+		if sandboxContainerCtx.Response.Resources.CPUSetMems != nil {
+			sandboxContainerCtx.ReconcilerDone(p.executor)
+		}
+	}
+	return nil
+}
+
+func (p *cpusetPlugin) getDrainedNUMANodes() []int {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	return p.drainedNUMANodes
+}
+
+func (p *cpusetPlugin) isCFSQuotaFree() bool {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	return p.cfsQuotaFree == nil || *p.cfsQuotaFree
+}
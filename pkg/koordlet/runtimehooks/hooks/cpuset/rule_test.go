@@ -24,6 +24,7 @@ import (
 	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
@@ -45,9 +46,10 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 		beSharePools []ext.CPUSharedPool
 	}
 	type args struct {
-		podAlloc            *ext.ResourceStatus
-		containerReq        *protocol.ContainerRequest
-		beCPUManagerEnabled bool
+		podAlloc               *ext.ResourceStatus
+		containerReq           *protocol.ContainerRequest
+		beCPUManagerEnabled    bool
+		preferredCPUSetEnabled bool
 	}
 	tests := []struct {
 		name    string
@@ -196,6 +198,63 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 			want:    pointer.String("0-7,8-15"),
 			wantErr: false,
 		},
+		{
+			name: "skip preferred cpuset ls pod",
+			fields: fields{
+				sharePools: []ext.CPUSharedPool{
+					{
+						Socket: 0,
+						Node:   0,
+						CPUSet: "0-7",
+					},
+				},
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					PreferredCPUSet: "2-3",
+				},
+				containerReq: &protocol.ContainerRequest{
+					PodMeta:       protocol.PodMeta{},
+					ContainerMeta: protocol.ContainerMeta{},
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLS),
+					},
+					PodAnnotations: map[string]string{},
+					CgroupParent:   "burstable/test-pod/test-container",
+				},
+				preferredCPUSetEnabled: true,
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "get all share pools for preferred cpuset ls pod when feature disabled",
+			fields: fields{
+				sharePools: []ext.CPUSharedPool{
+					{
+						Socket: 0,
+						Node:   0,
+						CPUSet: "0-7",
+					},
+				},
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					PreferredCPUSet: "2-3",
+				},
+				containerReq: &protocol.ContainerRequest{
+					PodMeta:       protocol.PodMeta{},
+					ContainerMeta: protocol.ContainerMeta{},
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLS),
+					},
+					PodAnnotations: map[string]string{},
+					CgroupParent:   "burstable/test-pod/test-container",
+				},
+			},
+			want:    pointer.String("0-7"),
+			wantErr: false,
+		},
 		{
 			name: "get all share pools for origin burstable pod under none policy",
 			fields: fields{
@@ -254,6 +313,40 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "get all share pools for degraded LSR pod under static policy",
+			fields: fields{
+				kubeletPoicy: ext.KubeletCPUManagerPolicyStatic,
+				sharePools: []ext.CPUSharedPool{
+					{
+						Socket: 0,
+						Node:   0,
+						CPUSet: "0-7",
+					},
+					{
+						Socket: 1,
+						Node:   1,
+						CPUSet: "8-15",
+					},
+				},
+			},
+			args: args{
+				containerReq: &protocol.ContainerRequest{
+					PodMeta:       protocol.PodMeta{},
+					ContainerMeta: protocol.ContainerMeta{},
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLSR),
+					},
+					PodAnnotations: map[string]string{},
+					CgroupParent:   "burstable/test-pod/test-container",
+				},
+				podAlloc: &ext.ResourceStatus{
+					CPUBindDegraded: true,
+				},
+			},
+			want:    pointer.String("0-7,8-15"),
+			wantErr: false,
+		},
 		{
 			name: "empty string for origin besteffort pod",
 			fields: fields{
@@ -297,7 +390,10 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 				tt.args.containerReq.PodAnnotations[ext.AnnotationResourceStatus] = podAllocJson
 			}
 			features.DefaultMutableKoordletFeatureGate.SetFromMap(
-				map[string]bool{string(features.BECPUManager): tt.args.beCPUManagerEnabled})
+				map[string]bool{
+					string(features.BECPUManager):    tt.args.beCPUManagerEnabled,
+					string(features.PreferredCPUSet): tt.args.preferredCPUSetEnabled,
+				})
 			got, err := r.getContainerCPUSet(tt.args.containerReq)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getCPUSet() error = %v, wantErr %v", err, tt.wantErr)
@@ -754,6 +850,155 @@ func Test_cpusetPlugin_ruleUpdateCbForPods(t *testing.T) {
 	}
 }
 
+func Test_cpusetPlugin_parseRuleForNodeSLO(t *testing.T) {
+	newNodeSLOSpec := func(cfsQuotaFree *bool) *slov1alpha1.NodeSLOSpec {
+		return &slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				LSRClass: &slov1alpha1.ResourceQOS{
+					CPUQOS: &slov1alpha1.CPUQOSCfg{
+						CPUQOS: slov1alpha1.CPUQOS{
+							CFSQuotaFree: cfsQuotaFree,
+						},
+					},
+				},
+			},
+		}
+	}
+	p := &cpusetPlugin{}
+	_, err := p.parseRuleForNodeSLO(&slov1alpha1.NodeSLO{})
+	assert.Error(t, err)
+	assert.True(t, p.isCFSQuotaFree())
+
+	steps := []struct {
+		nodeSLO          *slov1alpha1.NodeSLOSpec
+		wantUpdated      bool
+		wantCFSQuotaFree bool
+	}{
+		{nodeSLO: &slov1alpha1.NodeSLOSpec{}, wantUpdated: false, wantCFSQuotaFree: true},
+		{nodeSLO: newNodeSLOSpec(nil), wantUpdated: false, wantCFSQuotaFree: true},
+		{nodeSLO: newNodeSLOSpec(pointer.Bool(false)), wantUpdated: true, wantCFSQuotaFree: false},
+		{nodeSLO: newNodeSLOSpec(pointer.Bool(false)), wantUpdated: false, wantCFSQuotaFree: false},
+		{nodeSLO: newNodeSLOSpec(pointer.Bool(true)), wantUpdated: true, wantCFSQuotaFree: true},
+	}
+	for i, step := range steps {
+		updated, err := p.parseRuleForNodeSLO(step.nodeSLO)
+		assert.NoError(t, err)
+		assert.Equal(t, step.wantUpdated, updated, "step %d", i)
+		assert.Equal(t, step.wantCFSQuotaFree, p.isCFSQuotaFree(), "step %d", i)
+	}
+}
+
+func Test_cpusetPlugin_parseRuleForNodeMeta(t *testing.T) {
+	newNode := func(drainedNUMANodes string) *corev1.Node {
+		node := &corev1.Node{}
+		if drainedNUMANodes != "" {
+			node.Annotations = map[string]string{ext.AnnotationNodeDrainedNUMANodes: drainedNUMANodes}
+		}
+		return node
+	}
+	p := &cpusetPlugin{}
+	_, err := p.parseRuleForNodeMeta(&slov1alpha1.NodeSLOSpec{})
+	assert.Error(t, err)
+	_, err = p.parseRuleForNodeMeta(newNode("invalid"))
+	assert.Error(t, err)
+
+	steps := []struct {
+		node                 *corev1.Node
+		wantUpdated          bool
+		wantDrainedNUMANodes []int
+	}{
+		{node: newNode(""), wantUpdated: false, wantDrainedNUMANodes: nil},
+		{node: newNode("[]"), wantUpdated: false, wantDrainedNUMANodes: nil},
+		{node: newNode("[1,0]"), wantUpdated: true, wantDrainedNUMANodes: []int{0, 1}},
+		{node: newNode("[0,1]"), wantUpdated: false, wantDrainedNUMANodes: []int{0, 1}},
+		{node: newNode(""), wantUpdated: true, wantDrainedNUMANodes: nil},
+	}
+	for i, step := range steps {
+		updated, err := p.parseRuleForNodeMeta(step.node)
+		assert.NoError(t, err)
+		assert.Equal(t, step.wantUpdated, updated, "step %d", i)
+		assert.Equal(t, step.wantDrainedNUMANodes, p.getDrainedNUMANodes(), "step %d", i)
+	}
+}
+
+func Test_getNUMANodes(t *testing.T) {
+	assert.Nil(t, getNUMANodes(nil))
+	assert.Nil(t, getNUMANodes(&ext.CPUTopology{}))
+	cpuTopology := &ext.CPUTopology{
+		Detail: []ext.CPUInfo{
+			{ID: 0, Node: 1},
+			{ID: 1, Node: 0},
+			{ID: 2, Node: 1},
+		},
+	}
+	assert.Equal(t, []int{0, 1}, getNUMANodes(cpuTopology))
+}
+
+func Test_cpusetPlugin_ruleUpdateCbForNodeSLO(t *testing.T) {
+	testHelper := system.NewFileTestUtil(t)
+	defer testHelper.Cleanup()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: "lse-pod-uid",
+			Labels: map[string]string{
+				ext.LabelPodQoS: string(ext.QoSLSE),
+			},
+			Annotations: map[string]string{
+				ext.AnnotationResourceStatus: util.DumpJSON(&ext.ResourceStatus{CPUSet: "2-5"}),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test-container",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			QOSClass: corev1.PodQOSGuaranteed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://test-container-id",
+				},
+			},
+		},
+	}
+	podMeta := &statesinformer.PodMeta{
+		Pod:       pod,
+		CgroupDir: koordletutil.GetPodCgroupParentDir(pod),
+	}
+	containerDir, err := koordletutil.GetContainerCgroupParentDirByID(podMeta.CgroupDir, "containerd://test-container-id")
+	assert.NoError(t, err)
+	initCPUQuota(podMeta.CgroupDir, "-1", testHelper)
+	initCPUQuota(containerDir, "-1", testHelper)
+
+	p := &cpusetPlugin{
+		executor:     resourceexecutor.NewResourceUpdateExecutor(),
+		cfsQuotaFree: pointer.Bool(false),
+	}
+	stop := make(chan struct{})
+	defer func() { close(stop) }()
+	p.executor.Run(stop)
+
+	assert.NoError(t, p.ruleUpdateCbForNodeSLO(nil))
+	err = p.ruleUpdateCbForNodeSLO(&statesinformer.CallbackTarget{
+		Pods: []*statesinformer.PodMeta{podMeta},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "400000", getCPUQuota(podMeta.CgroupDir, testHelper))
+	assert.Equal(t, "400000", getCPUQuota(containerDir, testHelper))
+}
+
 func Test_cpusetRule_getHostAppCpuset(t *testing.T) {
 	type fields struct {
 		sharePools []ext.CPUSharedPool
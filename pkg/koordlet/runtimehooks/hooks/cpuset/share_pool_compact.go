@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// poolExpandInterval is the min interval between the expansions of the share pool. The share pool is shrunk at once
+// since the exclusive cpus must not be shared, but the expansion can wait to avoid the churn when the exclusive pods
+// come and go.
+const poolExpandInterval = 30 * time.Second
+
+// sharePoolCompactor compacts the share pools applied to the containers as the exclusive allocations grow. The share
+// pools reported exclude the cpus bound by the pods, so the cpus turned exclusive are removed from the applied share
+// pools at once, while the cpus turned shareable are added back with the rate limit.
+type sharePoolCompactor struct {
+	lock sync.Mutex
+	// sharePool is the share pool cpuset applied to the containers.
+	sharePool      cpuset.CPUSet
+	lastExpandTime time.Time
+}
+
+// compact returns the share pools restricted to the compacted share pool. The share pools turned empty are dropped.
+func (c *sharePoolCompactor) compact(sharePools []ext.CPUSharedPool, now time.Time) []ext.CPUSharedPool {
+	poolCPUs := make([]cpuset.CPUSet, len(sharePools))
+	builder := cpuset.NewCPUSetBuilder()
+	for i, sharePool := range sharePools {
+		cpus, err := cpuset.Parse(sharePool.CPUSet)
+		if err != nil {
+			klog.V(4).Infof("failed to parse cpuset %s of share pool, skip compacting, err: %v", sharePool.CPUSet, err)
+			return sharePools
+		}
+		poolCPUs[i] = cpus
+		builder.Add(cpus.ToSliceNoSort()...)
+	}
+	target := builder.Result()
+	if target.IsEmpty() {
+		return sharePools
+	}
+
+	c.lock.Lock()
+	compacted := c.nextSharePool(target, now)
+	c.lock.Unlock()
+
+	result := make([]ext.CPUSharedPool, 0, len(sharePools))
+	for i, sharePool := range sharePools {
+		cpus := poolCPUs[i].Intersection(compacted)
+		if cpus.IsEmpty() {
+			continue
+		}
+		sharePool.CPUSet = cpus.String()
+		result = append(result, sharePool)
+	}
+	return result
+}
+
+// nextSharePool returns the share pool to apply according to the target. The cpus turned exclusive are removed at once,
+// while the cpus turned shareable are added only if the share pool has not expanded within the poolExpandInterval.
+func (c *sharePoolCompactor) nextSharePool(target cpuset.CPUSet, now time.Time) cpuset.CPUSet {
+	switch {
+	case c.sharePool.IsEmpty() || target.IsSubsetOf(c.sharePool):
+		c.sharePool = target
+	case now.Sub(c.lastExpandTime) >= poolExpandInterval:
+		c.sharePool = target
+		c.lastExpandTime = now
+	default:
+		if shrunk := c.sharePool.Intersection(target); !shrunk.IsEmpty() {
+			c.sharePool = shrunk
+		} else {
+			c.sharePool = target
+			c.lastExpandTime = now
+		}
+	}
+	return c.sharePool
+}
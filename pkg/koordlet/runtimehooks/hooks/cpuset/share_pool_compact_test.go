@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func Test_sharePoolCompactor_nextSharePool(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		sharePool      cpuset.CPUSet
+		lastExpandTime time.Time
+		target         cpuset.CPUSet
+		want           cpuset.CPUSet
+		wantExpanded   bool
+	}{
+		{
+			name:   "initialize share pool",
+			target: cpuset.MustParse("0-7"),
+			want:   cpuset.MustParse("0-7"),
+		},
+		{
+			name:           "shrink share pool at once",
+			sharePool:      cpuset.MustParse("0-7"),
+			lastExpandTime: now,
+			target:         cpuset.MustParse("4-7"),
+			want:           cpuset.MustParse("4-7"),
+		},
+		{
+			name:           "expand share pool after the interval",
+			sharePool:      cpuset.MustParse("4-7"),
+			lastExpandTime: now.Add(-poolExpandInterval),
+			target:         cpuset.MustParse("0-7"),
+			want:           cpuset.MustParse("0-7"),
+			wantExpanded:   true,
+		},
+		{
+			name:           "defer expanding share pool within the interval",
+			sharePool:      cpuset.MustParse("4-7"),
+			lastExpandTime: now.Add(-time.Second),
+			target:         cpuset.MustParse("0-5"),
+			want:           cpuset.MustParse("4-5"),
+		},
+		{
+			name:           "move share pool at once if no cpu is kept",
+			sharePool:      cpuset.MustParse("4-7"),
+			lastExpandTime: now.Add(-time.Second),
+			target:         cpuset.MustParse("0-3"),
+			want:           cpuset.MustParse("0-3"),
+			wantExpanded:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &sharePoolCompactor{
+				sharePool:      tt.sharePool,
+				lastExpandTime: tt.lastExpandTime,
+			}
+			got := c.nextSharePool(tt.target, now)
+			assert.Equal(t, tt.want.String(), got.String())
+			assert.Equal(t, tt.wantExpanded, c.lastExpandTime.Equal(now))
+		})
+	}
+}
+
+func Test_sharePoolCompactor_compact(t *testing.T) {
+	now := time.Now()
+	c := &sharePoolCompactor{}
+	sharePools := []ext.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "0-3"},
+		{Socket: 1, Node: 1, CPUSet: "4-7"},
+	}
+	assert.Equal(t, sharePools, c.compact(sharePools, now))
+
+	// the cpus bound exclusively are removed at once
+	shrunkSharePools := []ext.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "0-1"},
+		{Socket: 1, Node: 1, CPUSet: "4-7"},
+	}
+	assert.Equal(t, shrunkSharePools, c.compact(shrunkSharePools, now.Add(time.Second)))
+
+	// the cpus released are added back at most once in the interval
+	assert.Equal(t, sharePools, c.compact(sharePools, now.Add(2*time.Second)))
+	assert.Equal(t, shrunkSharePools, c.compact(shrunkSharePools, now.Add(3*time.Second)))
+	assert.Equal(t, shrunkSharePools, c.compact(sharePools, now.Add(4*time.Second)))
+	assert.Equal(t, sharePools, c.compact(sharePools, now.Add(2*time.Second+poolExpandInterval)))
+
+	// the share pool turned empty is dropped
+	c = &sharePoolCompactor{
+		sharePool:      cpuset.MustParse("4-7"),
+		lastExpandTime: now,
+	}
+	assert.Equal(t, []ext.CPUSharedPool{{Socket: 1, Node: 1, CPUSet: "4-7"}}, c.compact(sharePools, now.Add(time.Second)))
+}
@@ -57,6 +57,8 @@ func (b *bvtPlugin) Register(op hooks.Options) {
 		rule.WithSystemSupported(b.SystemSupported))
 	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.CPUBVTWarpNs, "reconcile pod level cpu bvt value",
 		b.SetPodBvtValue, reconciler.NoneFilter())
+	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUBVTWarpNs, "reconcile container level cpu bvt value",
+		b.SetContainerBvtValue, reconciler.NoneFilter())
 	reconciler.RegisterCgroupReconciler(reconciler.KubeQOSLevel, sysutil.CPUBVTWarpNs, "reconcile kubeqos level cpu bvt value",
 		b.SetKubeQOSBvtValue, reconciler.NoneFilter())
 	reconciler.RegisterHostAppReconciler(sysutil.CPUBVTWarpNs, "reconcile host application cpu bvt value",
@@ -33,12 +33,26 @@ func (b *bvtPlugin) SetPodBvtValue(p protocol.HooksProtocol) error {
 	podCtx := p.(*protocol.PodContext)
 	req := podCtx.Request
 	podQOS := ext.GetQoSClassByAttrs(req.Labels, req.Annotations)
-	podKubeQOS := util.GetKubeQoSByCgroupParent(req.CgroupParent)
+	podKubeQOS := util.GetPodKubeQoS(req.CgroupParent, req.KubeQOS, podQOS)
 	podBvt := r.getPodBvtValue(podQOS, podKubeQOS)
 	podCtx.Response.Resources.CPUBvt = pointer.Int64(podBvt)
 	return nil
 }
 
+func (b *bvtPlugin) SetContainerBvtValue(p protocol.HooksProtocol) error {
+	r := b.prepare()
+	if r == nil {
+		return nil
+	}
+	containerCtx := p.(*protocol.ContainerContext)
+	req := containerCtx.Request
+	podQOS := ext.GetQoSClassByAttrs(req.PodLabels, req.PodAnnotations)
+	podKubeQOS := util.GetPodKubeQoS(req.CgroupParent, req.PodKubeQOS, podQOS)
+	containerBvt := r.getPodBvtValue(podQOS, podKubeQOS)
+	containerCtx.Response.Resources.CPUBvt = pointer.Int64(containerBvt)
+	return nil
+}
+
 func (b *bvtPlugin) SetKubeQOSBvtValue(p protocol.HooksProtocol) error {
 	r := b.prepare()
 	if r == nil {
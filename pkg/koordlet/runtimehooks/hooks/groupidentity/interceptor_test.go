@@ -21,12 +21,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
 	runtimeapi "github.com/koordinator-sh/koordinator/apis/runtime/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
@@ -283,6 +285,108 @@ func Test_bvtPlugin_SetPodBvtValue_Proxy(t *testing.T) {
 	}
 }
 
+func Test_bvtPlugin_SetContainerBvtValue_Reconciler(t *testing.T) {
+	defaultRule := &bvtRule{
+		enable: true,
+		podQOSParams: map[ext.QoSClass]int64{
+			ext.QoSLSR: 2,
+			ext.QoSLS:  2,
+			ext.QoSBE:  -1,
+		},
+		kubeQOSDirParams: map[corev1.PodQOSClass]int64{
+			corev1.PodQOSGuaranteed: 0,
+			corev1.PodQOSBurstable:  2,
+			corev1.PodQOSBestEffort: -1,
+		},
+		kubeQOSPodParams: map[corev1.PodQOSClass]int64{
+			corev1.PodQOSGuaranteed: 2,
+			corev1.PodQOSBurstable:  2,
+			corev1.PodQOSBestEffort: -1,
+		},
+	}
+	tests := []struct {
+		name      string
+		rule      *bvtRule
+		podLabels map[string]string
+		cgroupDir string
+		want      *int64
+	}{
+		{
+			name:      "set ls container bvt",
+			rule:      defaultRule,
+			podLabels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+			cgroupDir: "kubepods/burstable/pod-ls-test-uid/",
+			want:      pointer.Int64(2),
+		},
+		{
+			name:      "set be container bvt",
+			rule:      defaultRule,
+			podLabels: map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+			cgroupDir: "kubepods/besteffort/pod-be-test-uid/",
+			want:      pointer.Int64(-1),
+		},
+		{
+			name:      "set container bvt by kube qos",
+			rule:      defaultRule,
+			cgroupDir: "kubepods/besteffort/pod-besteffort-test-uid/",
+			want:      pointer.Int64(-1),
+		},
+		{
+			name:      "skip since rule is nil",
+			podLabels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+			cgroupDir: "kubepods/burstable/pod-ls-test-uid/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			podMeta := &statesinformer.PodMeta{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-pod",
+						Namespace: "default",
+						Labels:    tt.podLabels,
+					},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name:        "test-container",
+								ContainerID: "containerd://test-container-id",
+							},
+						},
+					},
+				},
+				CgroupDir: tt.cgroupDir,
+			}
+			containerDir, err := util.GetContainerCgroupParentDirByID(tt.cgroupDir, "containerd://test-container-id")
+			assert.NoError(t, err)
+			initCPUBvt(containerDir, 0, testHelper)
+
+			b := &bvtPlugin{
+				rule:             tt.rule,
+				sysSupported:     pointer.Bool(true),
+				hasKernelEnabled: pointer.Bool(false),
+				executor:         resourceexecutor.NewResourceUpdateExecutor(),
+			}
+			stop := make(chan struct{})
+			defer close(stop)
+			b.executor.Run(stop)
+
+			ctx := protocol.HooksProtocolBuilder.Container(podMeta, "test-container").(*protocol.ContainerContext)
+			err = b.SetContainerBvtValue(ctx)
+			assert.NoError(t, err)
+			ctx.ReconcilerDone(b.executor)
+
+			if tt.want == nil {
+				assert.Nil(t, ctx.Response.Resources.CPUBvt, "bvt value should be nil")
+			} else {
+				assert.Equal(t, *tt.want, *ctx.Response.Resources.CPUBvt, "container bvt in response should be equal")
+				assert.Equal(t, *tt.want, getPodCPUBvt(containerDir, testHelper), "container bvt should be equal")
+			}
+		})
+	}
+}
+
 func Test_bvtPlugin_SetKubeQOSBvtValue_Reconciler(t *testing.T) {
 	defaultRule := &bvtRule{
 		enable: true,
@@ -82,10 +82,10 @@ func (b *bvtPlugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
 		*mergedNodeSLO.ResourceQOSStrategy.LSClass.CPUQOS.Enable ||
 		*mergedNodeSLO.ResourceQOSStrategy.BEClass.CPUQOS.Enable
 
-	// setting pod rule by qos config
-	lsrValue := *mergedNodeSLO.ResourceQOSStrategy.LSRClass.CPUQOS.CPUQOS.GroupIdentity
-	lsValue := *mergedNodeSLO.ResourceQOSStrategy.LSClass.CPUQOS.GroupIdentity
-	beValue := *mergedNodeSLO.ResourceQOSStrategy.BEClass.CPUQOS.GroupIdentity
+	// setting pod rule by qos config, the bvt of the qos class is reset to none when its cpu qos is disabled
+	lsrValue := getQOSBvtValue(mergedNodeSLO.ResourceQOSStrategy.LSRClass.CPUQOS)
+	lsValue := getQOSBvtValue(mergedNodeSLO.ResourceQOSStrategy.LSClass.CPUQOS)
+	beValue := getQOSBvtValue(mergedNodeSLO.ResourceQOSStrategy.BEClass.CPUQOS)
 
 	// setting besteffort according to BE
 	besteffortDirVal := beValue
@@ -130,6 +130,13 @@ func (b *bvtPlugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
 	return updated, nil
 }
 
+func getQOSBvtValue(cpuQOS *slov1alpha1.CPUQOSCfg) int64 {
+	if cpuQOS.Enable == nil || !*cpuQOS.Enable || cpuQOS.GroupIdentity == nil {
+		return *sloconfig.NoneCPUQOS().GroupIdentity
+	}
+	return *cpuQOS.GroupIdentity
+}
+
 func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 	if !b.SystemSupported() {
 		klog.V(5).Infof("plugin %s is not supported by system", name)
@@ -170,6 +177,14 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 			klog.Infof("update pod %s cpu bvt failed, dir %v, error %v",
 				util.GetPodKey(podMeta.Pod), podCgroupPath, err)
 		}
+		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+			containerCtx := protocol.HooksProtocolBuilder.Container(podMeta, containerStat.Name)
+			if err := b.SetContainerBvtValue(containerCtx); err != nil {
+				klog.Warningf("set container %s/%s bvt value failed, error %v", util.GetPodKey(podMeta.Pod), containerStat.Name, err)
+			} else {
+				containerCtx.ReconcilerDone(b.executor)
+			}
+		}
 	}
 	for _, hostApp := range target.HostApplications {
 		hostCtx := protocol.HooksProtocolBuilder.HostApp(&hostApp)
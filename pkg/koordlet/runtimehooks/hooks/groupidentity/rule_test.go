@@ -326,6 +326,60 @@ func Test_bvtPlugin_parseRule(t *testing.T) {
 			want:    true,
 			wantErr: false,
 		},
+		{
+			name: "reset bvt of the disabled qos classes",
+			args: args{
+				mergedNodeSLO: &slov1alpha1.NodeSLOSpec{
+					ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+						LSRClass: &slov1alpha1.ResourceQOS{
+							CPUQOS: &slov1alpha1.CPUQOSCfg{
+								Enable: pointer.Bool(false),
+								CPUQOS: slov1alpha1.CPUQOS{
+									GroupIdentity: pointer.Int64(2),
+								},
+							},
+						},
+						LSClass: &slov1alpha1.ResourceQOS{
+							CPUQOS: &slov1alpha1.CPUQOSCfg{
+								Enable: pointer.Bool(false),
+								CPUQOS: slov1alpha1.CPUQOS{
+									GroupIdentity: pointer.Int64(2),
+								},
+							},
+						},
+						BEClass: &slov1alpha1.ResourceQOS{
+							CPUQOS: &slov1alpha1.CPUQOSCfg{
+								Enable: pointer.Bool(true),
+								CPUQOS: slov1alpha1.CPUQOS{
+									GroupIdentity: pointer.Int64(-1),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantRule: bvtRule{
+				enable: true,
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLSE: 0,
+					ext.QoSLSR: 0,
+					ext.QoSLS:  0,
+					ext.QoSBE:  -1,
+				},
+				kubeQOSDirParams: map[corev1.PodQOSClass]int64{
+					corev1.PodQOSGuaranteed: 0,
+					corev1.PodQOSBurstable:  0,
+					corev1.PodQOSBestEffort: -1,
+				},
+				kubeQOSPodParams: map[corev1.PodQOSClass]int64{
+					corev1.PodQOSGuaranteed: 0,
+					corev1.PodQOSBurstable:  0,
+					corev1.PodQOSBestEffort: -1,
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
 		{
 			name: "parse same normal rules",
 			args: args{
@@ -35,6 +35,8 @@ type Hook struct {
 
 type Options struct {
 	Executor resourceexecutor.ResourceUpdateExecutor
+	// MemoryPolicyHookPath is the path of the OCI hook which applies the memory policy of the containers.
+	MemoryPolicyHookPath string
 }
 
 type HookFn func(protocol.HooksProtocol) error
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	name        = "KataTopologyPassthrough"
+	description = "pass the cpuset and NUMA allocation through to the vCPUs of the kata sandbox"
+
+	// kataRuntimeHandlerPrefix is the prefix of the runtime handlers of the kata containers, e.g. kata, kata-qemu, kata-clh.
+	kataRuntimeHandlerPrefix = "kata"
+
+	// AnnotationKataDefaultVCPUs is the number of vCPUs the sandbox VM boots with.
+	AnnotationKataDefaultVCPUs = "io.katacontainers.config.hypervisor.default_vcpus"
+	// AnnotationKataEnableVCPUsPinning makes the kata runtime pin the vCPU threads 1:1 to the CPUs of the sandbox cpuset.
+	AnnotationKataEnableVCPUsPinning = "io.katacontainers.config.hypervisor.enable_vcpus_pinning"
+)
+
+type Plugin struct{}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = &Plugin{}
+	}
+	return singleton
+}
+
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, description, p.SetSandboxTopology)
+}
+
+// SetSandboxTopology translates the cpuset and NUMA nodes allocated by the koord-scheduler into the sandbox config of
+// the kata pod, so that the vCPUs are pinned on the allocated CPUs and the guest memory is bound to the NUMA nodes.
+// The containers of the kata pod run inside the VM, so the container-level cpuset does not take effect.
+func (p *Plugin) SetSandboxTopology(proto protocol.HooksProtocol) error {
+	podCtx, _ := proto.(*protocol.PodContext)
+	if podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %v", name)
+	}
+	if !IsKataRuntimeHandler(podCtx.Request.RuntimeHandler) {
+		return nil
+	}
+
+	resourceStatus, err := annotation.LenientParser.ParseResourceStatus(podCtx.Request.Annotations)
+	if err != nil {
+		return err
+	}
+	if resourceStatus.CPUSet != "" {
+		cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+		if err != nil {
+			return err
+		}
+		if cpus.Size() > 0 {
+			podCtx.Response.Resources.CPUSet = pointer.String(cpus.String())
+			if podCtx.Response.Annotations == nil {
+				podCtx.Response.Annotations = map[string]string{}
+			}
+			podCtx.Response.Annotations[AnnotationKataDefaultVCPUs] = strconv.Itoa(cpus.Size())
+			podCtx.Response.Annotations[AnnotationKataEnableVCPUsPinning] = "true"
+		}
+	}
+	if len(resourceStatus.NUMANodeResources) > 0 {
+		numaNodes := make([]int, 0, len(resourceStatus.NUMANodeResources))
+		for _, numaNode := range resourceStatus.NUMANodeResources {
+			numaNodes = append(numaNodes, int(numaNode.Node))
+		}
+		podCtx.Response.Resources.CPUSetMems = pointer.String(cpuset.NewCPUSet(numaNodes...).String())
+	}
+	klog.V(5).Infof("set sandbox topology for kata pod %v, cpuset %v, annotations %v",
+		podCtx.Request.PodMeta.String(), resourceStatus.CPUSet, podCtx.Response.Annotations)
+	return nil
+}
+
+// IsKataRuntimeHandler returns whether the runtime handler runs the pod in a kata sandbox.
+func IsKataRuntimeHandler(runtimeHandler string) bool {
+	return strings.HasPrefix(runtimeHandler, kataRuntimeHandlerPrefix)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		p := Object()
+		assert.NotNil(t, p)
+	})
+}
+
+func TestPluginSetSandboxTopology(t *testing.T) {
+	tests := []struct {
+		name            string
+		arg             protocol.HooksProtocol
+		wantErr         bool
+		wantCPUSet      *string
+		wantCPUSetMems  *string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:    "nil input",
+			arg:     (*protocol.PodContext)(nil),
+			wantErr: true,
+		},
+		{
+			name: "skip non-kata pod",
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					RuntimeHandler: "runc",
+					Annotations: map[string]string{
+						extension.AnnotationResourceStatus: `{"cpuset":"0-3"}`,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "skip kata pod without allocation",
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					RuntimeHandler: "kata-qemu",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pass cpuset and NUMA nodes through to kata sandbox",
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					RuntimeHandler: "kata-clh",
+					Annotations: map[string]string{
+						extension.AnnotationResourceStatus: `{"cpuset":"0-3","numaNodeResources":[{"node":1},{"node":0}]}`,
+					},
+				},
+			},
+			wantErr:        false,
+			wantCPUSet:     pointer.String("0-3"),
+			wantCPUSetMems: pointer.String("0-1"),
+			wantAnnotations: map[string]string{
+				AnnotationKataDefaultVCPUs:       "4",
+				AnnotationKataEnableVCPUsPinning: "true",
+			},
+		},
+		{
+			name: "failed to parse cpuset",
+			arg: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					RuntimeHandler: "kata",
+					Annotations: map[string]string{
+						extension.AnnotationResourceStatus: `{"cpuset":"invalid"}`,
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{}
+			gotErr := p.SetSandboxTopology(tt.arg)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			podCtx, _ := tt.arg.(*protocol.PodContext)
+			if podCtx == nil {
+				return
+			}
+			assert.Equal(t, tt.wantCPUSet, podCtx.Response.Resources.CPUSet)
+			assert.Equal(t, tt.wantCPUSetMems, podCtx.Response.Resources.CPUSetMems)
+			if tt.wantAnnotations != nil {
+				assert.Equal(t, tt.wantAnnotations, podCtx.Response.Annotations)
+			}
+		})
+	}
+}
+
+func TestIsKataRuntimeHandler(t *testing.T) {
+	assert.True(t, IsKataRuntimeHandler("kata"))
+	assert.True(t, IsKataRuntimeHandler("kata-qemu"))
+	assert.False(t, IsKataRuntimeHandler("runc"))
+	assert.False(t, IsKataRuntimeHandler(""))
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/containerd/nri/pkg/api"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -53,11 +54,13 @@ func (c *ContainerMeta) FromProxy(containerMeta *runtimeapi.ContainerMetadata, p
 }
 
 type ContainerRequest struct {
-	PodMeta           PodMeta
-	ContainerMeta     ContainerMeta
-	PodLabels         map[string]string
-	PodAnnotations    map[string]string
-	CgroupParent      string
+	PodMeta        PodMeta
+	ContainerMeta  ContainerMeta
+	PodLabels      map[string]string
+	PodAnnotations map[string]string
+	CgroupParent   string
+	// PodKubeQOS is the kube QoS class of the pod, which is only known in the reconciler mode.
+	PodKubeQOS        corev1.PodQOSClass
 	ContainerEnvs     map[string]string
 	Resources         *Resources // TODO: support proxy & nri mode
 	ExtendedResources *apiext.ExtendedResourceContainerSpec
@@ -162,6 +165,7 @@ func (c *ContainerRequest) FromReconciler(podMeta *statesinformer.PodMeta, conta
 	}
 	c.PodLabels = podMeta.Pod.Labels
 	c.PodAnnotations = podMeta.Pod.Annotations
+	c.PodKubeQOS = util.GetKubeQosClass(podMeta.Pod)
 	c.CgroupParent, _ = koordletutil.GetContainerCgroupParentDirByID(podMeta.CgroupDir, c.ContainerMeta.ID)
 	// retrieve ExtendedResources from container spec and pod annotations (prefer container spec)
 	specFromAnnotations, err := apiext.GetExtendedResourceSpec(podMeta.Pod.Annotations)
@@ -181,6 +185,15 @@ func (c *ContainerRequest) FromReconciler(podMeta *statesinformer.PodMeta, conta
 type ContainerResponse struct {
 	Resources        Resources
 	AddContainerEnvs map[string]string
+	// AddContainerHooks are the OCI hooks run before the process of the container starts.
+	// They only take effect in the NRI mode.
+	AddContainerHooks []OCIHook
+}
+
+// OCIHook is an OCI hook injected into the container.
+type OCIHook struct {
+	Path string
+	Args []string
 }
 
 func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResponse) {
@@ -191,6 +204,9 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 	if c.Resources.CPUSet != nil {
 		resp.ContainerResources.CpusetCpus = *c.Resources.CPUSet
 	}
+	if c.Resources.CPUSetMems != nil {
+		resp.ContainerResources.CpusetMems = *c.Resources.CPUSetMems
+	}
 	if c.Resources.CFSQuota != nil {
 		resp.ContainerResources.CpuQuota = *c.Resources.CFSQuota
 	}
@@ -244,6 +260,11 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 		update.SetLinuxCPUSetCPUs(*c.Response.Resources.CPUSet)
 	}
 
+	if c.Response.Resources.CPUSetMems != nil {
+		adjust.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+		update.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+	}
+
 	if c.Response.Resources.CFSQuota != nil {
 		adjust.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
 		update.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
@@ -265,6 +286,17 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 		}
 	}
 
+	if len(c.Response.AddContainerHooks) > 0 {
+		ociHooks := &api.Hooks{}
+		for _, hook := range c.Response.AddContainerHooks {
+			ociHooks.StartContainer = append(ociHooks.StartContainer, &api.Hook{
+				Path: hook.Path,
+				Args: hook.Args,
+			})
+		}
+		adjust.AddHooks(ociHooks)
+	}
+
 	c.Update()
 
 	return adjust, update, nil
@@ -334,6 +366,20 @@ func (c *ContainerContext) injectForOrigin() {
 				*c.Response.Resources.CPUSet, c.Request.CgroupParent)
 		}
 	}
+	// If CPUSetMems is not nil and is not an empty string, set container cpuset mems
+	if c.Response.Resources.CPUSetMems != nil && *c.Response.Resources.CPUSetMems != "" {
+		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message("set container cpuset mems to %v", *c.Response.Resources.CPUSetMems)
+		updater, err := injectCPUSetMems(c.Request.CgroupParent, *c.Response.Resources.CPUSetMems, eventHelper, c.executor)
+		if err != nil {
+			klog.Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSetMems, c.Request.CgroupParent, err)
+		} else {
+			c.updaters = append(c.updaters, updater)
+			klog.V(5).Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v",
+				c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+				*c.Response.Resources.CPUSetMems, c.Request.CgroupParent)
+		}
+	}
 	// If CFSQuota is not nil, set container cfs quota
 	if c.Response.Resources.CFSQuota != nil {
 		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
@@ -364,6 +410,21 @@ func (c *ContainerContext) injectForOrigin() {
 				*c.Response.Resources.MemoryLimit, c.Request.CgroupParent)
 		}
 	}
+	// If CPUBvt is not nil, set container bvt
+	if c.Response.Resources.CPUBvt != nil {
+		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
+			"set container bvt to %v", *c.Response.Resources.CPUBvt)
+		updater, err := injectCPUBvt(c.Request.CgroupParent, *c.Response.Resources.CPUBvt, eventHelper, c.executor)
+		if err != nil {
+			klog.Infof("set container %v/%v/%v bvt %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUBvt, c.Request.CgroupParent, err)
+		} else {
+			c.updaters = append(c.updaters, updater)
+			klog.V(5).Infof("set container %v/%v/%v bvt %v on cgroup parent %v",
+				c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+				*c.Response.Resources.CPUBvt, c.Request.CgroupParent)
+		}
+	}
 	// TODO other fields
 }
 
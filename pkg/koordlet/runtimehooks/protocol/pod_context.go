@@ -21,6 +21,7 @@ import (
 
 	"github.com/containerd/nri/pkg/api"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
@@ -61,10 +62,13 @@ func (p *PodMeta) FromReconciler(meta metav1.ObjectMeta) {
 }
 
 type PodRequest struct {
-	PodMeta           PodMeta
-	Labels            map[string]string
-	Annotations       map[string]string
-	CgroupParent      string
+	PodMeta      PodMeta
+	Labels       map[string]string
+	Annotations  map[string]string
+	CgroupParent string
+	// KubeQOS is the kube QoS class of the pod, which is only known in the reconciler mode.
+	KubeQOS           corev1.PodQOSClass
+	RuntimeHandler    string
 	Resources         *Resources // TODO: support proxy & nri mode
 	ExtendedResources *apiext.ExtendedResourceSpec
 }
@@ -74,6 +78,7 @@ func (p *PodRequest) FromNri(pod *api.PodSandbox) {
 	p.Labels = pod.GetLabels()
 	p.Annotations = pod.GetAnnotations()
 	p.CgroupParent = pod.GetLinux().GetCgroupParent()
+	p.RuntimeHandler = pod.GetRuntimeHandler()
 	// retrieve ExtendedResources from pod annotations
 	spec, err := apiext.GetExtendedResourceSpec(pod.GetAnnotations())
 	if err != nil {
@@ -90,6 +95,7 @@ func (p *PodRequest) FromProxy(req *runtimeapi.PodSandboxHookRequest) {
 	p.Labels = req.GetLabels()
 	p.Annotations = req.GetAnnotations()
 	p.CgroupParent = req.GetCgroupParent()
+	p.RuntimeHandler = req.GetRuntimeHandler()
 	// retrieve ExtendedResources from pod annotations
 	spec, err := apiext.GetExtendedResourceSpec(req.GetAnnotations())
 	if err != nil {
@@ -106,6 +112,11 @@ func (p *PodRequest) FromReconciler(podMeta *statesinformer.PodMeta) {
 	p.Labels = podMeta.Pod.Labels
 	p.Annotations = podMeta.Pod.Annotations
 	p.CgroupParent = podMeta.CgroupDir
+	p.KubeQOS = util.GetKubeQosClass(podMeta.Pod)
+	// the runtime handler is usually named after the RuntimeClass
+	if podMeta.Pod.Spec.RuntimeClassName != nil {
+		p.RuntimeHandler = *podMeta.Pod.Spec.RuntimeClassName
+	}
 	p.Resources = &Resources{}
 	p.Resources.FromPod(podMeta.Pod)
 	// retrieve ExtendedResources from pod spec and pod annotations (prefer pod spec)
@@ -124,6 +135,9 @@ func (p *PodRequest) FromReconciler(podMeta *statesinformer.PodMeta) {
 
 type PodResponse struct {
 	Resources Resources
+	// Annotations are injected into the sandbox config, e.g. the hints understood by the secure container runtime.
+	// Only the proxy mode supports injecting annotations.
+	Annotations map[string]string
 }
 
 type PodContext struct {
@@ -141,6 +155,9 @@ func (p *PodResponse) ProxyDone(resp *runtimeapi.PodSandboxHookResponse) {
 	if p.Resources.CPUSet != nil {
 		resp.Resources.CpusetCpus = *p.Resources.CPUSet
 	}
+	if p.Resources.CPUSetMems != nil {
+		resp.Resources.CpusetMems = *p.Resources.CPUSetMems
+	}
 	if p.Resources.CPUShares != nil {
 		resp.Resources.CpuShares = *p.Resources.CPUShares
 	}
@@ -150,6 +167,14 @@ func (p *PodResponse) ProxyDone(resp *runtimeapi.PodSandboxHookResponse) {
 	if p.Resources.MemoryLimit != nil {
 		resp.Resources.MemoryLimitInBytes = *p.Resources.MemoryLimit
 	}
+	if len(p.Annotations) > 0 {
+		if resp.Annotations == nil {
+			resp.Annotations = map[string]string{}
+		}
+		for k, v := range p.Annotations {
+			resp.Annotations[k] = v
+		}
+	}
 }
 
 func (p *PodContext) FromNri(pod *api.PodSandbox) {
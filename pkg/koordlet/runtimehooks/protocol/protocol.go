@@ -30,6 +30,7 @@ import (
 )
 
 type HooksProtocol interface {
+	ReconcilerProcess(executor resourceexecutor.ResourceUpdateExecutor)
 	ReconcilerDone(executor resourceexecutor.ResourceUpdateExecutor)
 	Update()
 	GetUpdaters() []resourceexecutor.ResourceUpdater
@@ -76,6 +77,7 @@ type Resources struct {
 	CPUShares   *int64
 	CFSQuota    *int64
 	CPUSet      *string
+	CPUSetMems  *string
 	MemoryLimit *int64
 
 	// extended resources
@@ -83,7 +85,7 @@ type Resources struct {
 }
 
 func (r *Resources) IsOriginResSet() bool {
-	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.MemoryLimit != nil
+	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.CPUSetMems != nil || r.MemoryLimit != nil
 }
 
 func (r *Resources) FromPod(pod *corev1.Pod) {
@@ -140,6 +142,14 @@ func injectCPUSet(cgroupParent string, cpuset string, a *audit.EventHelper, e re
 	return updater, nil
 }
 
+func injectCPUSetMems(cgroupParent string, mems string, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUSetMemsName, cgroupParent, mems, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
 func injectCPUQuota(cgroupParent string, cpuQuota int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
 	cpuQuotaStr := strconv.FormatInt(cpuQuota, 10)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, cgroupParent, cpuQuotaStr, a)
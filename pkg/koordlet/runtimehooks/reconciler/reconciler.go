@@ -17,6 +17,7 @@ limitations under the License.
 package reconciler
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -24,6 +25,8 @@ import (
 	"k8s.io/klog/v2"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
@@ -238,6 +241,8 @@ type reconciler struct {
 	podUpdated        chan struct{}
 	executor          resourceexecutor.ResourceUpdateExecutor
 	reconcileInterval time.Duration
+	// recovered indicates whether the pod cgroups are recovered after the restart.
+	recovered bool
 }
 
 func (c *reconciler) Run(stopCh <-chan struct{}) error {
@@ -312,60 +317,14 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 		select {
 		case <-c.podUpdated:
 			podsMeta := c.getPodsMeta()
+			if !c.recovered && features.DefaultKoordletFeatureGate.Enabled(features.CgroupRestartRecovery) {
+				c.recoverPodCgroup(podsMeta)
+				c.recovered = true
+				continue
+			}
 			for _, podMeta := range podsMeta {
-				for _, r := range globalCgroupReconcilers.podLevel {
-					reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
-					if !ok {
-						klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
-							r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
-						continue
-					}
-
-					podCtx := protocol.HooksProtocolBuilder.Pod(podMeta)
-					if err := reconcileFn(podCtx); err != nil {
-						klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
-					} else {
-						podCtx.ReconcilerDone(c.executor)
-						klog.V(5).Infof("calling reconcile function %v for pod %v finished",
-							r.description, util.GetPodKey(podMeta.Pod))
-					}
-				}
-
-				for _, r := range globalCgroupReconcilers.sandboxContainerLevel {
-					reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
-					if !ok {
-						klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
-							r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
-						continue
-					}
-					sandboxContainerCtx := protocol.HooksProtocolBuilder.Sandbox(podMeta)
-					if err := reconcileFn(sandboxContainerCtx); err != nil {
-						klog.Warningf("calling reconcile function %v failed for sandbox, error %v", r.description, err)
-					} else {
-						sandboxContainerCtx.ReconcilerDone(c.executor)
-						klog.V(5).Infof("calling reconcile function %v for pod sandbox %v finished",
-							r.description, util.GetPodKey(podMeta.Pod))
-					}
-				}
-
-				for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
-					for _, r := range globalCgroupReconcilers.containerLevel {
-						reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
-						if !ok {
-							klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
-								r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
-							continue
-						}
-
-						containerCtx := protocol.HooksProtocolBuilder.Container(podMeta, containerStat.Name)
-						if err := reconcileFn(containerCtx); err != nil {
-							klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
-						} else {
-							containerCtx.ReconcilerDone(c.executor)
-							klog.V(5).Infof("calling reconcile function %v for container %v/%v finish",
-								r.description, util.GetPodKey(podMeta.Pod), containerStat.Name)
-						}
-					}
+				for _, hooksCtx := range generatePodCgroupContexts(podMeta) {
+					hooksCtx.ReconcilerDone(c.executor)
 				}
 			}
 		case <-stopCh:
@@ -374,3 +333,123 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 		}
 	}
 }
+
+// recoverPodCgroup rebuilds the intended cgroups of the pods from the pod annotations and the rules (e.g. NodeSLO) after
+// koordlet restarts. It compares them with the current cgroups and only updates the drifted ones, in the order of the
+// pod QoS and priority, so the pods of higher priority are recovered first and the unchanged cgroups are not rewritten.
+func (c *reconciler) recoverPodCgroup(podsMeta []*statesinformer.PodMeta) {
+	start := time.Now()
+	sortPodsByRecoveryOrder(podsMeta)
+
+	checked := 0
+	changes := map[string]int{}
+	for _, podMeta := range podsMeta {
+		var updaters []resourceexecutor.ResourceUpdater
+		for _, hooksCtx := range generatePodCgroupContexts(podMeta) {
+			hooksCtx.ReconcilerProcess(c.executor)
+			updaters = append(updaters, hooksCtx.GetUpdaters()...)
+		}
+		checked += len(updaters)
+		for _, updater := range c.executor.RecoverBatch(updaters...) {
+			changes[string(updater.ResourceType())]++
+		}
+	}
+
+	duration := time.Since(start)
+	metrics.RecordRuntimeHooksRecoveryDuration(duration.Seconds())
+	drifted := 0
+	for resource, count := range changes {
+		metrics.RecordRuntimeHooksRecoveryChanges(resource, count)
+		drifted += count
+	}
+	klog.Infof("recover pod cgroups finished, pods %d, checked cgroups %d, drifted cgroups %d, duration %v",
+		len(podsMeta), checked, drifted, duration)
+}
+
+// recoveryQOSOrder is the order of recovering the pods of the QoS classes. The pods of the lower order are recovered first.
+var recoveryQOSOrder = map[apiext.QoSClass]int{
+	apiext.QoSSystem: 0,
+	apiext.QoSLSE:    1,
+	apiext.QoSLSR:    2,
+	apiext.QoSLS:     3,
+	apiext.QoSNone:   4,
+	apiext.QoSBE:     5,
+}
+
+func sortPodsByRecoveryOrder(podsMeta []*statesinformer.PodMeta) {
+	sort.SliceStable(podsMeta, func(i, j int) bool {
+		a, b := podsMeta[i].Pod, podsMeta[j].Pod
+		if orderA, orderB := recoveryQOSOrder[apiext.GetPodQoSClassRaw(a)], recoveryQOSOrder[apiext.GetPodQoSClassRaw(b)]; orderA != orderB {
+			return orderA < orderB
+		}
+		priorityA, priorityB := int32(0), int32(0)
+		if a.Spec.Priority != nil {
+			priorityA = *a.Spec.Priority
+		}
+		if b.Spec.Priority != nil {
+			priorityB = *b.Spec.Priority
+		}
+		return priorityA > priorityB
+	})
+}
+
+// generatePodCgroupContexts calls the registered reconcile functions of the pod, and returns the contexts of the pod,
+// the sandbox and the containers in order, whose updaters are not applied yet.
+func generatePodCgroupContexts(podMeta *statesinformer.PodMeta) []protocol.HooksProtocol {
+	var hooksCtxs []protocol.HooksProtocol
+	for _, r := range globalCgroupReconcilers.podLevel {
+		reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
+		if !ok {
+			klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
+				r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
+			continue
+		}
+
+		podCtx := protocol.HooksProtocolBuilder.Pod(podMeta)
+		if err := reconcileFn(podCtx); err != nil {
+			klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
+		} else {
+			hooksCtxs = append(hooksCtxs, podCtx)
+			klog.V(5).Infof("calling reconcile function %v for pod %v finished",
+				r.description, util.GetPodKey(podMeta.Pod))
+		}
+	}
+
+	for _, r := range globalCgroupReconcilers.sandboxContainerLevel {
+		reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
+		if !ok {
+			klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
+				r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
+			continue
+		}
+		sandboxContainerCtx := protocol.HooksProtocolBuilder.Sandbox(podMeta)
+		if err := reconcileFn(sandboxContainerCtx); err != nil {
+			klog.Warningf("calling reconcile function %v failed for sandbox, error %v", r.description, err)
+		} else {
+			hooksCtxs = append(hooksCtxs, sandboxContainerCtx)
+			klog.V(5).Infof("calling reconcile function %v for pod sandbox %v finished",
+				r.description, util.GetPodKey(podMeta.Pod))
+		}
+	}
+
+	for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+		for _, r := range globalCgroupReconcilers.containerLevel {
+			reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
+			if !ok {
+				klog.V(5).Infof("calling reconcile function %v aborted for pod %v, condition %s not registered",
+					r.description, util.GetPodKey(podMeta.Pod), r.filter.Filter(podMeta))
+				continue
+			}
+
+			containerCtx := protocol.HooksProtocolBuilder.Container(podMeta, containerStat.Name)
+			if err := reconcileFn(containerCtx); err != nil {
+				klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
+			} else {
+				hooksCtxs = append(hooksCtxs, containerCtx)
+				klog.V(5).Infof("calling reconcile function %v for container %v/%v finish",
+					r.description, util.GetPodKey(podMeta.Pod), containerStat.Name)
+			}
+		}
+	}
+	return hooksCtxs
+}
@@ -24,11 +24,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -192,6 +198,99 @@ func Test_reconciler_reconcilePodCgroup(t *testing.T) {
 	})
 }
 
+func Test_reconciler_recoverPodCgroup(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.CgroupRestartRecovery, true)()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	containerReconcilerFn := func(proto protocol.HooksProtocol) error {
+		containerCtx := proto.(*protocol.ContainerContext)
+		containerCtx.Response.Resources.CPUSet = pointer.String("0-3")
+		return nil
+	}
+	RegisterCgroupReconciler(ContainerLevel, system.CPUSet, "set container cpuset", containerReconcilerFn, NoneFilter())
+
+	newPodMeta := func(name string) *statesinformer.PodMeta {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-ns",
+				Name:      name,
+				UID:       types.UID(name + "-uid"),
+			},
+			Status: corev1.PodStatus{
+				QOSClass: corev1.PodQOSBurstable,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "main",
+						ContainerID: "containerd://" + name + "-main",
+					},
+				},
+			},
+		}
+		return &statesinformer.PodMeta{
+			Pod:       pod,
+			CgroupDir: koordletutil.GetPodCgroupParentDir(pod),
+		}
+	}
+	unchangedPod := newPodMeta("unchanged-pod")
+	unchangedDir, err := koordletutil.GetContainerCgroupParentDir(unchangedPod.CgroupDir, &unchangedPod.Pod.Status.ContainerStatuses[0])
+	assert.NoError(t, err)
+	helper.WriteCgroupFileContents(unchangedDir, system.CPUSet, "0-3")
+	driftedPod := newPodMeta("drifted-pod")
+	driftedDir, err := koordletutil.GetContainerCgroupParentDir(driftedPod.CgroupDir, &driftedPod.Pod.Status.ContainerStatuses[0])
+	assert.NoError(t, err)
+	helper.WriteCgroupFileContents(driftedDir, system.CPUSet, "0-7")
+
+	c := &reconciler{
+		podsMeta:   []*statesinformer.PodMeta{unchangedPod, driftedPod},
+		podUpdated: make(chan struct{}, 1),
+		executor:   resourceexecutor.NewTestResourceExecutor(),
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.executor.Run(stopCh)
+
+	c.recoverPodCgroup(c.getPodsMeta())
+	assert.Equal(t, "0-3", helper.ReadCgroupFileContents(unchangedDir, system.CPUSet))
+	assert.Equal(t, "0-3", helper.ReadCgroupFileContents(driftedDir, system.CPUSet))
+
+	// the unchanged cgroup is cached and not rewritten by the following reconciliation
+	helper.WriteCgroupFileContents(unchangedDir, system.CPUSet, "0-1")
+	for _, hooksCtx := range generatePodCgroupContexts(unchangedPod) {
+		hooksCtx.ReconcilerDone(c.executor)
+	}
+	assert.Equal(t, "0-1", helper.ReadCgroupFileContents(unchangedDir, system.CPUSet))
+}
+
+func Test_sortPodsByRecoveryOrder(t *testing.T) {
+	newPodMeta := func(name string, qos apiext.QoSClass, priority int32) *statesinformer.PodMeta {
+		return &statesinformer.PodMeta{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{apiext.LabelPodQoS: string(qos)},
+				},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(priority),
+				},
+			},
+		}
+	}
+	podsMeta := []*statesinformer.PodMeta{
+		newPodMeta("be", apiext.QoSBE, 5000),
+		newPodMeta("ls-low", apiext.QoSLS, 100),
+		newPodMeta("lsr", apiext.QoSLSR, 9000),
+		newPodMeta("ls-high", apiext.QoSLS, 9000),
+		newPodMeta("none", apiext.QoSNone, 9000),
+	}
+	sortPodsByRecoveryOrder(podsMeta)
+	var got []string
+	for _, podMeta := range podsMeta {
+		got = append(got, podMeta.Pod.Name)
+	}
+	assert.Equal(t, []string{"lsr", "ls-high", "ls-low", "none", "be"}, got)
+}
+
 func Test_reconciler_podRefreshCallback(t *testing.T) {
 	type args struct {
 		podsMeta []*statesinformer.PodMeta
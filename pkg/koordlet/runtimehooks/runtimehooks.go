@@ -123,7 +123,8 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config) (RuntimeHook,
 	}
 
 	newPluginOptions := hooks.Options{
-		Executor: e,
+		Executor:             e,
+		MemoryPolicyHookPath: cfg.RuntimeHookMemoryPolicyHookPath,
 	}
 
 	if err != nil {
@@ -157,6 +158,10 @@ func registerPlugins(op hooks.Options) {
 	klog.V(5).Infof("start register plugins for runtime hook")
 	for hookFeature, hookPlugin := range runtimeHookPlugins {
 		enabled := features.DefaultKoordletFeatureGate.Enabled(hookFeature)
+		if enabled && hookFeature == CPUAmplification && features.DefaultKoordletFeatureGate.Enabled(CPUNormalization) {
+			klog.Warningf("runtime hook plugin %s is skipped since it conflicts with %s", CPUAmplification, CPUNormalization)
+			enabled = false
+		}
 		if enabled {
 			hookPlugin.Register(op)
 		}
@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfgovernance
+
+import (
+	"flag"
+	"time"
+)
+
+type Config struct {
+	// CPUSet is the cpuset to pin koordlet on, e.g. "0-1". Empty means no pinning.
+	CPUSet string
+	// CPULimitMilli is the cfs quota of koordlet in milli-cores. Non-positive means no limit.
+	CPULimitMilli int64
+	// MemoryLimitBytes is the memory limit of koordlet in bytes. Non-positive means no limit.
+	MemoryLimitBytes int64
+	// ReconcileInterval is the interval to re-apply the limits and report the overhead of koordlet.
+	ReconcileInterval time.Duration
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		ReconcileInterval: time.Minute,
+	}
+}
+
+func (c *Config) InitFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.CPUSet, "self-cpuset", c.CPUSet, "The cpuset to pin koordlet itself on, e.g. \"0-1\". Empty means no pinning. Only works with the SelfResourceGovernance feature.")
+	fs.Int64Var(&c.CPULimitMilli, "self-cpu-limit-milli", c.CPULimitMilli, "The cpu limit of koordlet itself in milli-cores. Non-positive means no limit. Only works with the SelfResourceGovernance feature.")
+	fs.Int64Var(&c.MemoryLimitBytes, "self-memory-limit-bytes", c.MemoryLimitBytes, "The memory limit of koordlet itself in bytes. Non-positive means no limit. Only works with the SelfResourceGovernance feature.")
+	fs.DurationVar(&c.ReconcileInterval, "self-governance-interval", c.ReconcileInterval, "The interval to re-apply the self resource limits and report the overhead of koordlet.")
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfgovernance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const reasonSelfResourceGovernance = "SelfResourceGovernance"
+
+var (
+	pinnedCPUs     cpuset.CPUSet
+	pinnedCPUsLock sync.RWMutex
+)
+
+// GetPinnedCPUs returns the CPUs koordlet is pinned on. It is empty when koordlet is not pinned.
+func GetPinnedCPUs() cpuset.CPUSet {
+	pinnedCPUsLock.RLock()
+	defer pinnedCPUsLock.RUnlock()
+	return pinnedCPUs.Clone()
+}
+
+func setPinnedCPUs(cpus cpuset.CPUSet) {
+	pinnedCPUsLock.Lock()
+	defer pinnedCPUsLock.Unlock()
+	pinnedCPUs = cpus
+}
+
+// SelfGovernor pins and limits the cgroup of koordlet itself, and reports the overhead of koordlet.
+type SelfGovernor interface {
+	Setup() error
+	Run(stopCh <-chan struct{}) error
+}
+
+type selfGovernor struct {
+	config    *Config
+	cpus      cpuset.CPUSet
+	cgroupDir string
+	executor  resourceexecutor.ResourceUpdateExecutor
+	reader    resourceexecutor.CgroupReader
+
+	lastCPUUsage     uint64
+	lastCPUUsageTime time.Time
+}
+
+func NewSelfGovernor(config *Config) SelfGovernor {
+	return &selfGovernor{
+		config:   config,
+		executor: resourceexecutor.NewResourceUpdateExecutor(),
+		reader:   resourceexecutor.NewCgroupReader(),
+	}
+}
+
+// Setup resolves the cgroup of koordlet and applies the configured limits to it.
+func (s *selfGovernor) Setup() error {
+	cpus, err := cpuset.Parse(s.config.CPUSet)
+	if err != nil {
+		return fmt.Errorf("failed to parse self cpuset %s, err: %w", s.config.CPUSet, err)
+	}
+	s.cpus = cpus
+
+	cgroupDir, err := getSelfCgroupDir()
+	if err != nil {
+		return err
+	}
+	s.cgroupDir = cgroupDir
+	klog.V(4).Infof("koordlet self cgroup dir is %s", s.cgroupDir)
+
+	if err = s.apply(); err != nil {
+		return err
+	}
+	setPinnedCPUs(s.cpus)
+	return nil
+}
+
+func (s *selfGovernor) Run(stopCh <-chan struct{}) error {
+	klog.Info("starting self governor")
+	go wait.Until(s.reconcile, s.config.ReconcileInterval, stopCh)
+	return nil
+}
+
+func (s *selfGovernor) reconcile() {
+	// the limits can be reset by others (e.g. the cpuset reconciled by the kubelet cpu manager), so re-apply them
+	if err := s.apply(); err != nil {
+		klog.Warningf("failed to apply self resource limits, err: %s", err)
+	}
+	s.reportOverhead()
+}
+
+func (s *selfGovernor) apply() error {
+	if !s.cpus.IsEmpty() {
+		cpusetStr := s.cpus.String()
+		eventHelper := audit.V(3).Reason(reasonSelfResourceGovernance).Message("update koordlet cgroup to cpuset: %v", cpusetStr)
+		if err := s.update(system.CPUSetCPUSName, cpusetStr, eventHelper); err != nil {
+			return err
+		}
+		metrics.RecordKoordletSelfResourceLimit("cpuset", metrics.UnitCore, float64(s.cpus.Size()))
+	}
+	if s.config.CPULimitMilli > 0 {
+		quotaStr := strconv.FormatInt(system.MilliCPUToQuota(s.config.CPULimitMilli), 10)
+		eventHelper := audit.V(3).Reason(reasonSelfResourceGovernance).Message("update koordlet cgroup to cfs_quota: %v", quotaStr)
+		if err := s.update(system.CPUCFSQuotaName, quotaStr, eventHelper); err != nil {
+			return err
+		}
+		metrics.RecordKoordletSelfResourceLimit(string(corev1.ResourceCPU), metrics.UnitCore, float64(s.config.CPULimitMilli)/1000)
+	}
+	if s.config.MemoryLimitBytes > 0 {
+		limitStr := strconv.FormatInt(s.config.MemoryLimitBytes, 10)
+		eventHelper := audit.V(3).Reason(reasonSelfResourceGovernance).Message("update koordlet cgroup to memory limit: %v", limitStr)
+		if err := s.update(system.MemoryLimitName, limitStr, eventHelper); err != nil {
+			return err
+		}
+		metrics.RecordKoordletSelfResourceLimit(string(corev1.ResourceMemory), metrics.UnitByte, float64(s.config.MemoryLimitBytes))
+	}
+	return nil
+}
+
+func (s *selfGovernor) update(resourceType system.ResourceType, value string, eventHelper *audit.EventHelper) error {
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(resourceType, s.cgroupDir, value, eventHelper)
+	if err != nil {
+		return fmt.Errorf("failed to get updater %s for koordlet cgroup %s, err: %w", resourceType, s.cgroupDir, err)
+	}
+	if _, err = s.executor.Update(false, updater); err != nil {
+		return fmt.Errorf("failed to update %s for koordlet cgroup %s, err: %w", resourceType, s.cgroupDir, err)
+	}
+	return nil
+}
+
+func (s *selfGovernor) reportOverhead() {
+	now := time.Now()
+	cpuUsage, err := s.reader.ReadCPUAcctUsage(s.cgroupDir)
+	if err != nil {
+		klog.V(4).Infof("failed to read koordlet cpu usage, err: %s", err)
+	} else {
+		if !s.lastCPUUsageTime.IsZero() && cpuUsage >= s.lastCPUUsage {
+			cores := float64(cpuUsage-s.lastCPUUsage) / float64(now.Sub(s.lastCPUUsageTime).Nanoseconds())
+			metrics.RecordKoordletSelfCPUUsage(cores)
+		}
+		s.lastCPUUsage, s.lastCPUUsageTime = cpuUsage, now
+	}
+
+	memStat, err := s.reader.ReadMemoryStat(s.cgroupDir)
+	if err != nil {
+		klog.V(4).Infof("failed to read koordlet memory usage, err: %s", err)
+		return
+	}
+	metrics.RecordKoordletSelfMemoryUsage(float64(memStat.Usage()))
+}
+
+// getSelfCgroupDir parses the cgroup dir of koordlet relative to the cgroup root, e.g.
+// "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podxxx.slice/cri-containerd-xxx.scope".
+func getSelfCgroupDir() (string, error) {
+	cgroupFile := filepath.Join(system.Conf.ProcRootDir, "self", "cgroup")
+	content, err := os.ReadFile(cgroupFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s, err: %w", cgroupFile, err)
+	}
+
+	// cgroup v1: "4:cpu,cpuacct:/kubepods.slice/...", cgroup v2: "0::/kubepods.slice/..."
+	var cgroupPath string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+			if fields[0] == "0" && fields[1] == "" {
+				cgroupPath = fields[2]
+				break
+			}
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			if controller == "cpu" {
+				cgroupPath = fields[2]
+				break
+			}
+		}
+		if len(cgroupPath) > 0 {
+			break
+		}
+	}
+
+	cgroupDir := strings.Trim(cgroupPath, "/")
+	if len(cgroupDir) == 0 {
+		// the cgroup namespace of koordlet is private or koordlet runs in the root cgroup
+		return "", fmt.Errorf("failed to get koordlet cgroup dir from %s, path %q", cgroupFile, cgroupPath)
+	}
+	return cgroupDir, nil
+}
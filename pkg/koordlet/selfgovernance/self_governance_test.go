@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfgovernance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func Test_getSelfCgroupDir(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCgroupsV2 bool
+		content      string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:    "cgroup file not exist",
+			wantErr: true,
+		},
+		{
+			name: "parse cgroup v1",
+			content: `12:memory:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope
+4:cpu,cpuacct:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope
+1:name=systemd:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope
+`,
+			want: "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope",
+		},
+		{
+			name:         "parse cgroup v2",
+			useCgroupsV2: true,
+			content:      "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope\n",
+			want:         "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope",
+		},
+		{
+			name:    "failed for the private cgroup namespace",
+			content: "4:cpu,cpuacct:/\n",
+			wantErr: true,
+		},
+		{
+			name:    "failed for missing cpu controller",
+			content: "12:memory:/kubepods.slice\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.useCgroupsV2)
+			if len(tt.content) > 0 {
+				helper.WriteProcSubFileContents("self/cgroup", tt.content)
+			}
+
+			got, gotErr := getSelfCgroupDir()
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_selfGovernor(t *testing.T) {
+	testCgroupDir := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123.slice/cri-containerd-abc.scope"
+	tests := []struct {
+		name          string
+		config        *Config
+		wantErr       bool
+		wantCPUSet    string
+		wantCFSQuota  string
+		wantMemLimit  string
+		wantPinnedCPU cpuset.CPUSet
+	}{
+		{
+			name: "failed to parse cpuset",
+			config: &Config{
+				CPUSet: "invalid",
+			},
+			wantErr:       true,
+			wantCPUSet:    "0-7",
+			wantCFSQuota:  "-1",
+			wantMemLimit:  "9223372036854771712",
+			wantPinnedCPU: cpuset.NewCPUSet(),
+		},
+		{
+			name:          "nothing to apply",
+			config:        NewDefaultConfig(),
+			wantCPUSet:    "0-7",
+			wantCFSQuota:  "-1",
+			wantMemLimit:  "9223372036854771712",
+			wantPinnedCPU: cpuset.NewCPUSet(),
+		},
+		{
+			name: "apply cpuset, cpu limit and memory limit",
+			config: &Config{
+				CPUSet:            "0-1",
+				CPULimitMilli:     500,
+				MemoryLimitBytes:  512 * 1024 * 1024,
+				ReconcileInterval: time.Minute,
+			},
+			wantCPUSet:    "0-1",
+			wantCFSQuota:  "50000",
+			wantMemLimit:  "536870912",
+			wantPinnedCPU: cpuset.NewCPUSet(0, 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			defer setPinnedCPUs(cpuset.NewCPUSet())
+			helper.WriteProcSubFileContents("self/cgroup", "4:cpu,cpuacct:/"+testCgroupDir+"\n")
+			helper.WriteCgroupFileContents(testCgroupDir, system.CPUSet, "0-7")
+			helper.WriteCgroupFileContents(testCgroupDir, system.CPUCFSQuota, "-1")
+			helper.WriteCgroupFileContents(testCgroupDir, system.MemoryLimit, "9223372036854771712")
+			helper.WriteCgroupFileContents(testCgroupDir, system.CPUAcctUsage, "1000000000")
+
+			s := NewSelfGovernor(tt.config).(*selfGovernor)
+			s.reader = resourceexecutor.NewCgroupReader()
+			gotErr := s.Setup()
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.wantCPUSet, helper.ReadCgroupFileContents(testCgroupDir, system.CPUSet))
+			assert.Equal(t, tt.wantCFSQuota, helper.ReadCgroupFileContents(testCgroupDir, system.CPUCFSQuota))
+			assert.Equal(t, tt.wantMemLimit, helper.ReadCgroupFileContents(testCgroupDir, system.MemoryLimit))
+			assert.Equal(t, tt.wantPinnedCPU, GetPinnedCPUs())
+			if tt.wantErr {
+				return
+			}
+
+			// the cpuset reset by others should be re-applied
+			helper.WriteCgroupFileContents(testCgroupDir, system.CPUSet, "0-7")
+			s.reconcile()
+			assert.Equal(t, tt.wantCPUSet, helper.ReadCgroupFileContents(testCgroupDir, system.CPUSet))
+			assert.Equal(t, uint64(1000000000), s.lastCPUUsage)
+		})
+	}
+}
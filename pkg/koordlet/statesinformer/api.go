@@ -21,6 +21,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/pleg"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -96,4 +97,5 @@ type StatesInformer interface {
 	GetVolumeName(pvcNamespace, pvcName string) string
 
 	RegisterCallbacks(objType RegisterType, name, description string, callbackFn UpdateCbFn)
+	RegisterPodLifeCycleHandler(handler pleg.PodLifeCycleHandler) pleg.HandlerID
 }
@@ -33,6 +33,7 @@ type Config struct {
 	DisableQueryKubeletConfig   bool
 	EnableNodeMetricReport      bool
 	MetricReportInterval        time.Duration // Deprecated
+	NodeTopologyApplyEnabled    bool
 }
 
 func NewDefaultConfig() *Config {
@@ -58,4 +59,5 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.DisableQueryKubeletConfig, "disable-query-kubelet-config", c.DisableQueryKubeletConfig, "Disables querying the kubelet configuration from kubelet. Flag must be set to true if kubelet-insecure-tls=true is configured")
 	fs.DurationVar(&c.MetricReportInterval, "report-interval", c.MetricReportInterval, "Deprecated since v1.1, use ColocationStrategy.MetricReportIntervalSeconds in config map of slo-controller")
 	fs.BoolVar(&c.EnableNodeMetricReport, "enable-node-metric-report", c.EnableNodeMetricReport, "Enable status update of node metric crd.")
+	fs.BoolVar(&c.NodeTopologyApplyEnabled, "node-topology-apply-enabled", c.NodeTopologyApplyEnabled, "Report the node topology with the server-side apply, so koordlet only owns the fields it reports and does not conflict with the other writers of the node topology crd.")
 }
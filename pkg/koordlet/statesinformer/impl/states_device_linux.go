@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -32,7 +33,9 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	koordletuti "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -43,14 +46,20 @@ func (s *statesInformer) reportDevice() {
 		return
 	}
 	gpuDevices := s.buildGPUDevice()
-	if len(gpuDevices) == 0 {
+	var nicDevices []schedulingv1alpha1.DeviceInfo
+	if features.DefaultKoordletFeatureGate.Enabled(features.NICTopologyReport) {
+		nicDevices = s.buildNICDevice()
+	}
+	if len(gpuDevices) == 0 && len(nicDevices) == 0 {
 		return
 	}
 
-	gpuModel, gpuDriverVer := s.getGPUDriverAndModelFunc()
-
 	device := s.buildBasicDevice(node)
-	s.fillGPUDevice(device, gpuDevices, gpuModel, gpuDriverVer)
+	if len(gpuDevices) > 0 {
+		gpuModel, gpuDriverVer := s.getGPUDriverAndModelFunc()
+		s.fillGPUDevice(device, gpuDevices, gpuModel, gpuDriverVer)
+	}
+	device.Spec.Devices = append(device.Spec.Devices, nicDevices...)
 
 	err := s.updateDevice(device)
 	if err == nil {
@@ -114,6 +123,9 @@ func (s *statesInformer) createDevice(device *schedulingv1alpha1.Device) error {
 func (s *statesInformer) updateDevice(device *schedulingv1alpha1.Device) error {
 	sorter := func(devices []schedulingv1alpha1.DeviceInfo) {
 		sort.Slice(devices, func(i, j int) bool {
+			if devices[i].Type != devices[j].Type {
+				return devices[i].Type < devices[j].Type
+			}
 			return *(devices[i].Minor) < *(devices[j].Minor)
 		})
 	}
@@ -177,6 +189,46 @@ func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
 	return deviceInfos
 }
 
+// buildNICDevice builds the physical NICs in the order of the interface name, and the minor is the interface index,
+// which keeps the minor of a NIC unchanged when other NICs are added or removed. A NIC is healthy if its link is up, so the devices are refreshed in the next report when the links change.
+func (s *statesInformer) buildNICDevice() []schedulingv1alpha1.DeviceInfo {
+	nics, err := system.GetNetworkInterfaces()
+	if err != nil {
+		klog.V(4).Infof("failed to get network interfaces, err: %v", err)
+		return nil
+	}
+
+	var deviceInfos []schedulingv1alpha1.DeviceInfo
+	for i := range nics {
+		nic := nics[i]
+		if nic.IfIndex <= 0 {
+			klog.V(4).Infof("failed to get interface index of NIC %s, skip it", nic.Name)
+			continue
+		}
+		minor := nic.IfIndex
+		labels := map[string]string{
+			extension.LabelNICName: nic.Name,
+		}
+		if nic.SpeedMbps > 0 {
+			labels[extension.LabelNICSpeed] = strconv.FormatInt(nic.SpeedMbps, 10)
+		}
+		deviceInfos = append(deviceInfos, schedulingv1alpha1.DeviceInfo{
+			UUID:   nic.BusID,
+			Minor:  &minor,
+			Type:   schedulingv1alpha1.NIC,
+			Health: nic.OperState == "up",
+			Labels: labels,
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				SocketID: -1,
+				NodeID:   nic.NUMANode,
+				PCIEID:   -1,
+				BusID:    nic.BusID,
+			},
+		})
+	}
+	return deviceInfos
+}
+
 func (s *statesInformer) initGPU() bool {
 	if ret := nvml.Init(); ret != nvml.SUCCESS {
 		if ret == nvml.ERROR_LIBRARY_NOT_FOUND {
@@ -18,6 +18,8 @@ package impl
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
@@ -27,12 +29,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	schedulingfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 func Test_reportGPUDevice(t *testing.T) {
@@ -119,3 +124,67 @@ func Test_reportGPUDevice(t *testing.T) {
 	assert.Equal(t, device.Labels[extension.LabelGPUModel], "A100")
 	assert.Equal(t, device.Labels[extension.LabelGPUDriverVersion], "470")
 }
+
+func Test_reportNICDevice(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.NICTopologyReport, true)()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	deviceDir := filepath.Join(system.GetSysPCIDevicesDir(), "0000:3b:00.0")
+	interfaceDir := filepath.Join(system.GetSysClassNetDir(), "eth0")
+	helper.WriteFileContents(filepath.Join(deviceDir, "numa_node"), "1")
+	helper.WriteFileContents(filepath.Join(interfaceDir, "ifindex"), "3")
+	helper.WriteFileContents(filepath.Join(interfaceDir, "speed"), "25000")
+	helper.WriteFileContents(filepath.Join(interfaceDir, "operstate"), "up")
+	assert.NoError(t, os.Symlink(deviceDir, filepath.Join(interfaceDir, "device")))
+
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	fakeClient := schedulingfake.NewSimpleClientset().SchedulingV1alpha1().Devices()
+	ctl := gomock.NewController(t)
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	mockMetricCache.EXPECT().Get(koordletutil.GPUDeviceType).Return(nil, false).AnyTimes()
+	r := &statesInformer{
+		deviceClient: fakeClient,
+		metricsCache: mockMetricCache,
+		states: &PluginState{
+			informerPlugins: map[PluginName]informerPlugin{
+				nodeInformerName: &nodeInformer{
+					node: testNode,
+				},
+			},
+		},
+	}
+	r.reportDevice()
+	expectedDevices := []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:   "0000:3b:00.0",
+			Minor:  pointer.Int32(3),
+			Type:   schedulingv1alpha1.NIC,
+			Health: true,
+			Labels: map[string]string{
+				extension.LabelNICName:  "eth0",
+				extension.LabelNICSpeed: "25000",
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				SocketID: -1,
+				NodeID:   1,
+				PCIEID:   -1,
+				BusID:    "0000:3b:00.0",
+			},
+		},
+	}
+	device, err := fakeClient.Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDevices, device.Spec.Devices)
+
+	// the link goes down
+	helper.WriteFileContents(filepath.Join(interfaceDir, "operstate"), "down")
+	r.reportDevice()
+	expectedDevices[0].Health = false
+	device, err = fakeClient.Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDevices, device.Spec.Devices)
+}
@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -36,6 +37,7 @@ import (
 	schedv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/pleg"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 )
@@ -59,16 +61,18 @@ type StatesInformer interface {
 	GetVolumeName(pvcNamespace, pvcName string) string
 
 	RegisterCallbacks(objType statesinformer.RegisterType, name, description string, callbackFn statesinformer.UpdateCbFn)
+	RegisterPodLifeCycleHandler(handler pleg.PodLifeCycleHandler) pleg.HandlerID
 }
 
 type PluginName string
 
 type PluginOption struct {
-	config      *Config
-	KubeClient  clientset.Interface
-	KoordClient koordclientset.Interface
-	TopoClient  topologyclientset.Interface
-	NodeName    string
+	config        *Config
+	KubeClient    clientset.Interface
+	KoordClient   koordclientset.Interface
+	TopoClient    topologyclientset.Interface
+	DynamicClient dynamic.Interface
+	NodeName      string
 }
 
 type PluginState struct {
@@ -103,13 +107,15 @@ type informerPlugin interface {
 
 // TODO merge all clients into one struct
 func NewStatesInformer(config *Config, kubeClient clientset.Interface, crdClient koordclientset.Interface, topologyClient topologyclientset.Interface,
-	metricsCache metriccache.MetricCache, nodeName string, schedulingClient schedv1alpha1.SchedulingV1alpha1Interface, predictorFactory prediction.PredictorFactory) StatesInformer {
+	dynamicClient dynamic.Interface, metricsCache metriccache.MetricCache, nodeName string, schedulingClient schedv1alpha1.SchedulingV1alpha1Interface,
+	predictorFactory prediction.PredictorFactory) StatesInformer {
 	opt := &PluginOption{
-		config:      config,
-		KubeClient:  kubeClient,
-		KoordClient: crdClient,
-		TopoClient:  topologyClient,
-		NodeName:    nodeName,
+		config:        config,
+		KubeClient:    kubeClient,
+		KoordClient:   crdClient,
+		TopoClient:    topologyClient,
+		DynamicClient: dynamicClient,
+		NodeName:      nodeName,
 	}
 	stat := &PluginState{
 		metricCache:      metricsCache,
@@ -162,8 +168,11 @@ func (s *statesInformer) Run(stopCh <-chan struct{}) error {
 		return fmt.Errorf("timed out waiting for states informer caches to sync")
 	}
 
-	if features.DefaultKoordletFeatureGate.Enabled(features.Accelerators) {
+	if features.DefaultKoordletFeatureGate.Enabled(features.Accelerators) ||
+		features.DefaultKoordletFeatureGate.Enabled(features.NICTopologyReport) {
 		go wait.Until(s.reportDevice, s.config.NodeTopologySyncInterval, stopCh)
+	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.Accelerators) {
 		// check is nvml is available
 		if s.initGPU() {
 			go s.gpuHealCheck(stopCh)
@@ -253,3 +262,14 @@ func (s *statesInformer) GetVolumeName(pvcNamespace, pvcName string) string {
 func (s *statesInformer) RegisterCallbacks(rType statesinformer.RegisterType, name, description string, callbackFn statesinformer.UpdateCbFn) {
 	s.states.callbackRunner.RegisterCallbacks(rType, name, description, callbackFn)
 }
+
+// RegisterPodLifeCycleHandler registers the handler on the PLEG of the pods informer, so the consumers can watch the
+// pod and container lifecycle events without running another PLEG.
+func (s *statesInformer) RegisterPodLifeCycleHandler(handler pleg.PodLifeCycleHandler) pleg.HandlerID {
+	podsInformerIf := s.states.informerPlugins[podsInformerName]
+	podsInformer, ok := podsInformerIf.(*podsInformer)
+	if !ok {
+		klog.Fatalf("pods informer format error")
+	}
+	return podsInformer.pleg.AddHandler(handler)
+}
@@ -274,6 +274,7 @@ func Test_statesInformer_Run(t *testing.T) {
 			kubeClient.CoreV1().Nodes().Create(context.TODO(), &tt.fields.node, metav1.CreateOptions{})
 			koordClient := fakekoordclientset.NewSimpleClientset()
 			topoClient := faketopologyclientset.NewSimpleClientset()
+			dynamicClient := newFakeNodeSLODynamicClient()
 			ctrl := gomock.NewController(t)
 			metricCache := mock_metriccache.NewMockMetricCache(ctrl)
 			//metricCache.EXPECT().GetNodeResourceMetric(gomock.Any()).Return(metriccache.NodeResourceQueryResult{
@@ -282,7 +283,7 @@ func Test_statesInformer_Run(t *testing.T) {
 			//}).AnyTimes()
 			nodeName := tt.fields.node.Name
 			schedClient := &fakeschedv1alpha1.FakeSchedulingV1alpha1{}
-			si := NewStatesInformer(tt.fields.config, kubeClient, koordClient, topoClient, metricCache, nodeName, schedClient, prediction.NewEmptyPredictorFactory())
+			si := NewStatesInformer(tt.fields.config, kubeClient, koordClient, topoClient, dynamicClient, metricCache, nodeName, schedClient, prediction.NewEmptyPredictorFactory())
 			s := si.(*statesInformer)
 			s.states.informerPlugins = tt.fields.pluginRegistry
 			stopChannel := make(chan struct{}, 1)
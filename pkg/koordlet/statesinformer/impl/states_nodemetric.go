@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"sync"
 	"time"
@@ -45,6 +46,7 @@ import (
 	clientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	clientsetv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	listerv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
@@ -97,11 +99,15 @@ type nodeMetricInformer struct {
 	statusUpdater      *statusUpdater
 
 	podsInformer     *podsInformer
+	nodeSLOInformer  *nodeSLOInformer
 	metricCache      metriccache.MetricCache
 	predictorFactory prediction.PredictorFactory
 
 	rwMutex    sync.RWMutex
 	nodeMetric *slov1alpha1.NodeMetric
+
+	// lastColdMemory is the cold memory info reported last time, which is refreshed once per report interval
+	lastColdMemory *slov1alpha1.NodeColdMemoryInfo
 }
 
 func NewNodeMetricInformer() *nodeMetricInformer {
@@ -139,6 +145,12 @@ func (r *nodeMetricInformer) Setup(ctx *PluginOption, state *PluginState) {
 		klog.Fatalf("pods informer format error")
 	}
 	r.podsInformer = podsInformer
+	nodeSLOInformerIf := state.informerPlugins[nodeSLOInformerName]
+	nodeSLOInformer, ok := nodeSLOInformerIf.(*nodeSLOInformer)
+	if !ok {
+		klog.Fatalf("node slo informer format error")
+	}
+	r.nodeSLOInformer = nodeSLOInformer
 	r.predictorFactory = state.predictorFactory
 
 	r.nodeMetricInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -251,7 +263,12 @@ func (r *nodeMetricInformer) sync() {
 		NodeMetric:            nodeMetricInfo,
 		PodsMetric:            podMetricInfo,
 		ProdReclaimableMetric: prodReclaimableMetric,
+		HostApplicationMetric: r.collectHostAppMetric(),
+	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.ColocationReadiness) {
+		newStatus.ColocationReadiness = checkColocationReadiness()
 	}
+	cpuStealAnomalyThreshold := r.getNodeMetricSpec().CollectPolicy.CPUStealAnomalyThresholdPercent
 	retErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		nodeMetric, err := r.nodeMetricLister.Get(r.nodeName)
 		if errors.IsNotFound(err) {
@@ -261,6 +278,8 @@ func (r *nodeMetricInformer) sync() {
 			klog.Warningf("failed to get %s nodeMetric: %v", r.nodeName, err)
 			return err
 		}
+		newStatus.Conditions = generateNodeMetricConditions(nodeMetric.Status.Conditions, nodeMetricInfo.CPUSteal, cpuStealAnomalyThreshold)
+		newStatus.Conditions = generateColocationReadyCondition(newStatus.Conditions, newStatus.ColocationReadiness)
 		err = r.statusUpdater.updateStatus(nodeMetric, newStatus)
 		return err
 	})
@@ -350,6 +369,7 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		End:       &endTime,
 	}
 	prodPredictor := r.predictorFactory.New(prediction.ProdReclaimablePredictor)
+	var nodeLocalPages, nodeRemotePages float64
 	for _, podMeta := range podsMeta {
 		podMetric, err := r.collectPodMetric(podMeta, podQueryParam)
 		if err != nil {
@@ -366,8 +386,23 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		if len(gpus) > 0 {
 			r.fillGPUMetrics(podQueryParam, podMetric, string(podMeta.Pod.UID), gpus)
 		}
+		if localPages, remotePages, err := r.collectPodMemoryLocality(podQueryParam, string(podMeta.Pod.UID)); err != nil {
+			klog.V(4).Infof("query pod memory locality failed, pod %s, error %v", genPodMetaKey(podMeta), err)
+		} else {
+			podMetric.MemoryLocality = newMemoryLocality(localPages, remotePages)
+			nodeLocalPages += localPages
+			nodeRemotePages += remotePages
+		}
 		podsMetricInfo = append(podsMetricInfo, podMetric)
 	}
+	nodeMetricInfo.MemoryLocality = newMemoryLocality(nodeLocalPages, nodeRemotePages)
+	if features.DefaultKoordletFeatureGate.Enabled(features.NodeHealthIndex) {
+		nodeMetricInfo.HealthIndex = r.collectNodeHealthIndex(podQueryParam, podsMeta, spec.CollectPolicy.HealthIndexWeights)
+	}
+	nodeMetricInfo.ColdMemory = r.collectNodeColdMemory(startTime, endTime, spec.CollectPolicy.ColdMemoryReportIntervalSeconds)
+	if features.DefaultKoordletFeatureGate.Enabled(features.CPUStealAnomaly) {
+		nodeMetricInfo.CPUSteal = r.collectNodeCPUSteal(podQueryParam)
+	}
 	prodReclaimable := &slov1alpha1.ReclaimableMetric{}
 	if p, err := prodPredictor.GetResult(); err != nil {
 		klog.Errorf("failed to get prediction, err %v", err)
@@ -727,6 +762,49 @@ func (r *nodeMetricInformer) fillGPUMetrics(queryparam metriccache.QueryParam, i
 	info.PodUsage.Devices = podGPUMetrics
 }
 
+// collectPodMemoryLocality returns the memory pages of the pod on the NUMA nodes local and remote to its bound cpus.
+// Both are zero if the pod is not bound to cpus.
+func (r *nodeMetricInformer) collectPodMemoryLocality(queryparam metriccache.QueryParam, uid string) (float64, float64, error) {
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	properties := metriccache.MetricPropertiesFunc.Pod(uid)
+	localAggregateResult, err := doQuery(querier, metriccache.PodMemoryLocalPagesMetric, properties)
+	if err != nil {
+		return 0, 0, err
+	}
+	remoteAggregateResult, err := doQuery(querier, metriccache.PodMemoryRemotePagesMetric, properties)
+	if err != nil {
+		return 0, 0, err
+	}
+	if localAggregateResult.Count() == 0 || remoteAggregateResult.Count() == 0 {
+		return 0, 0, nil
+	}
+	localPages, err := localAggregateResult.Value(queryparam.Aggregate)
+	if err != nil {
+		return 0, 0, err
+	}
+	remotePages, err := remoteAggregateResult.Value(queryparam.Aggregate)
+	if err != nil {
+		return 0, 0, err
+	}
+	return localPages, remotePages, nil
+}
+
+// newMemoryLocality returns the memory locality of the given pages, or nil if there is no page.
+func newMemoryLocality(localPages, remotePages float64) *slov1alpha1.MemoryLocality {
+	totalPages := localPages + remotePages
+	if totalPages <= 0 {
+		return nil
+	}
+	localRatio := int64(math.Round(localPages * 100 / totalPages))
+	return &slov1alpha1.MemoryLocality{
+		LocalRatio:  pointer.Int64(localRatio),
+		RemoteRatio: pointer.Int64(100 - localRatio),
+	}
+}
+
 const (
 	statusUpdateQPS   = 0.1
 	statusUpdateBurst = 2
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// collectNodeColdMemory returns the cold memory info of the node, which is refreshed once per report interval.
+// The last collected info is reported between the refreshes, and it returns nil if the report is disabled.
+func (r *nodeMetricInformer) collectNodeColdMemory(start, end time.Time, reportIntervalSeconds *int64) *slov1alpha1.NodeColdMemoryInfo {
+	if reportIntervalSeconds == nil || *reportIntervalSeconds <= 0 || !system.GetIsStartColdMemory() {
+		r.lastColdMemory = nil
+		return nil
+	}
+	if !isColdMemoryExpired(r.lastColdMemory, end, time.Duration(*reportIntervalSeconds)*time.Second) {
+		return r.lastColdMemory.DeepCopy()
+	}
+
+	querier, err := r.metricCache.Querier(start, end)
+	if err != nil {
+		klog.V(4).Infof("failed to get querier for node cold memory, err: %v", err)
+		return r.lastColdMemory.DeepCopy()
+	}
+	coldPageBytes, okCold := queryHealthMetric(querier, metriccache.NodeMemoryColdPageSizeMetric, nil, metriccache.AggregationTypeLast)
+	hotUsageBytes, okHot := queryHealthMetric(querier, metriccache.NodeMemoryWithHotPageUsageMetric, nil, metriccache.AggregationTypeLast)
+	if !okCold || !okHot {
+		klog.V(5).Infof("node cold memory metrics are not ready, cold page %v, hot usage %v", okCold, okHot)
+		return r.lastColdMemory.DeepCopy()
+	}
+	memInfo, err := koordletutil.GetMemInfo()
+	if err != nil {
+		klog.V(4).Infof("failed to get meminfo for node cold memory, err: %v", err)
+		return r.lastColdMemory.DeepCopy()
+	}
+
+	r.lastColdMemory = newNodeColdMemoryInfo(int64(memInfo.MemTotalBytes()), int64(coldPageBytes), int64(hotUsageBytes), end)
+	return r.lastColdMemory.DeepCopy()
+}
+
+// isColdMemoryExpired checks if the last collected cold memory info should be refreshed.
+func isColdMemoryExpired(lastColdMemory *slov1alpha1.NodeColdMemoryInfo, now time.Time, reportInterval time.Duration) bool {
+	if lastColdMemory == nil || lastColdMemory.UpdateTime == nil {
+		return true
+	}
+	return !now.Before(lastColdMemory.UpdateTime.Add(reportInterval))
+}
+
+func newNodeColdMemoryInfo(totalBytes, coldPageBytes, hotUsageBytes int64, updateTime time.Time) *slov1alpha1.NodeColdMemoryInfo {
+	return &slov1alpha1.NodeColdMemoryInfo{
+		Total:      resource.NewQuantity(totalBytes, resource.BinarySI),
+		Cold:       resource.NewQuantity(coldPageBytes, resource.BinarySI),
+		HotUsage:   resource.NewQuantity(hotUsageBytes, resource.BinarySI),
+		UpdateTime: &metav1.Time{Time: updateTime},
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_isColdMemoryExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		lastColdMemory *slov1alpha1.NodeColdMemoryInfo
+		want           bool
+	}{
+		{
+			name: "never collected",
+			want: true,
+		},
+		{
+			name:           "missing update time",
+			lastColdMemory: &slov1alpha1.NodeColdMemoryInfo{},
+			want:           true,
+		},
+		{
+			name: "within the report interval",
+			lastColdMemory: &slov1alpha1.NodeColdMemoryInfo{
+				UpdateTime: &metav1.Time{Time: now.Add(-30 * time.Second)},
+			},
+			want: false,
+		},
+		{
+			name: "reach the report interval",
+			lastColdMemory: &slov1alpha1.NodeColdMemoryInfo{
+				UpdateTime: &metav1.Time{Time: now.Add(-60 * time.Second)},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isColdMemoryExpired(tt.lastColdMemory, now, 60*time.Second))
+		})
+	}
+}
+
+func Test_newNodeColdMemoryInfo(t *testing.T) {
+	now := time.Now()
+	got := newNodeColdMemoryInfo(16<<30, 2<<30, 10<<30, now)
+	assert.Equal(t, "16Gi", got.Total.String())
+	assert.Equal(t, "2Gi", got.Cold.String())
+	assert.Equal(t, "10Gi", got.HotUsage.String())
+	assert.Equal(t, now, got.UpdateTime.Time)
+}
+
+func Test_collectNodeColdMemory(t *testing.T) {
+	now := time.Now()
+	lastColdMemory := newNodeColdMemoryInfo(16<<30, 2<<30, 10<<30, now.Add(-10*time.Second))
+	r := &nodeMetricInformer{
+		lastColdMemory: lastColdMemory,
+	}
+
+	// report the last collected info within the report interval
+	system.SetIsStartColdMemory(true)
+	defer system.SetIsStartColdMemory(false)
+	got := r.collectNodeColdMemory(now.Add(-60*time.Second), now, pointer.Int64(60))
+	assert.Equal(t, lastColdMemory, got)
+
+	// disabled if the report interval is not set
+	got = r.collectNodeColdMemory(now.Add(-60*time.Second), now, nil)
+	assert.Nil(t, got)
+	assert.Nil(t, r.lastColdMemory)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+)
+
+const (
+	// defaultCPUStealAnomalyThresholdPercent is the CPU steal percent from which the node is considered anomalous
+	// if the threshold is not specified in the collect policy.
+	defaultCPUStealAnomalyThresholdPercent = 10
+
+	cpuStealAnomalyReasonExceedThreshold = "CPUStealExceedThreshold"
+	cpuStealAnomalyReasonBelowThreshold  = "CPUStealBelowThreshold"
+)
+
+// collectNodeCPUSteal returns the average CPU steal of the node during the aggregation window.
+// It returns nil if the CPU steal is not collected.
+func (r *nodeMetricInformer) collectNodeCPUSteal(queryParam metriccache.QueryParam) *slov1alpha1.NodeCPUSteal {
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		klog.V(4).Infof("failed to get querier for node cpu steal, err: %v", err)
+		return nil
+	}
+	aggregateResult, err := doQuery(querier, metriccache.NodeCPUStealMetric, nil)
+	if err != nil || aggregateResult.Count() == 0 {
+		klog.V(4).Infof("failed to query node cpu steal, err: %v", err)
+		return nil
+	}
+	value, err := aggregateResult.Value(queryParam.Aggregate)
+	if err != nil {
+		klog.V(4).Infof("failed to aggregate node cpu steal, err: %v", err)
+		return nil
+	}
+	stealPercent := int64(math.Round(math.Max(0, math.Min(100, value*100))))
+	return &slov1alpha1.NodeCPUSteal{StealPercent: pointer.Int64(stealPercent)}
+}
+
+// generateNodeMetricConditions updates the CPUStealAnomaly condition in the old conditions and keeps the last
+// transition time if the status is unchanged. The condition is removed if the CPU steal is not reported.
+func generateNodeMetricConditions(oldConditions []metav1.Condition, cpuSteal *slov1alpha1.NodeCPUSteal,
+	thresholdPercent *int64) []metav1.Condition {
+	conditions := make([]metav1.Condition, len(oldConditions))
+	for i := range oldConditions {
+		oldConditions[i].DeepCopyInto(&conditions[i])
+	}
+	if cpuSteal == nil || cpuSteal.StealPercent == nil {
+		meta.RemoveStatusCondition(&conditions, slov1alpha1.NodeMetricConditionCPUStealAnomaly)
+	} else {
+		threshold := int64(defaultCPUStealAnomalyThresholdPercent)
+		if thresholdPercent != nil {
+			threshold = *thresholdPercent
+		}
+		condition := metav1.Condition{
+			Type:    slov1alpha1.NodeMetricConditionCPUStealAnomaly,
+			Status:  metav1.ConditionFalse,
+			Reason:  cpuStealAnomalyReasonBelowThreshold,
+			Message: fmt.Sprintf("cpu steal %d%% is below the threshold %d%%", *cpuSteal.StealPercent, threshold),
+		}
+		if *cpuSteal.StealPercent >= threshold {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = cpuStealAnomalyReasonExceedThreshold
+			condition.Message = fmt.Sprintf("cpu steal %d%% exceeds the threshold %d%%", *cpuSteal.StealPercent, threshold)
+		}
+		meta.SetStatusCondition(&conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	return conditions
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func Test_generateNodeMetricConditions(t *testing.T) {
+	lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	anomalyCondition := metav1.Condition{
+		Type:               slov1alpha1.NodeMetricConditionCPUStealAnomaly,
+		Status:             metav1.ConditionTrue,
+		Reason:             cpuStealAnomalyReasonExceedThreshold,
+		Message:            "cpu steal 30% exceeds the threshold 10%",
+		LastTransitionTime: lastTransitionTime,
+	}
+	tests := []struct {
+		name          string
+		oldConditions []metav1.Condition
+		cpuSteal      *slov1alpha1.NodeCPUSteal
+		threshold     *int64
+		wantStatus    *metav1.ConditionStatus
+		wantKeepTime  bool
+	}{
+		{
+			name:       "no cpu steal reported",
+			cpuSteal:   nil,
+			wantStatus: nil,
+		},
+		{
+			name:          "remove the condition if cpu steal is not reported",
+			oldConditions: []metav1.Condition{anomalyCondition},
+			cpuSteal:      nil,
+			wantStatus:    nil,
+		},
+		{
+			name:       "below the default threshold",
+			cpuSteal:   &slov1alpha1.NodeCPUSteal{StealPercent: pointer.Int64(5)},
+			wantStatus: conditionStatusPtr(metav1.ConditionFalse),
+		},
+		{
+			name:       "exceed the specified threshold",
+			cpuSteal:   &slov1alpha1.NodeCPUSteal{StealPercent: pointer.Int64(5)},
+			threshold:  pointer.Int64(5),
+			wantStatus: conditionStatusPtr(metav1.ConditionTrue),
+		},
+		{
+			name:          "keep the transition time if the status is unchanged",
+			oldConditions: []metav1.Condition{anomalyCondition},
+			cpuSteal:      &slov1alpha1.NodeCPUSteal{StealPercent: pointer.Int64(20)},
+			wantStatus:    conditionStatusPtr(metav1.ConditionTrue),
+			wantKeepTime:  true,
+		},
+		{
+			name:          "recover from the anomaly",
+			oldConditions: []metav1.Condition{anomalyCondition},
+			cpuSteal:      &slov1alpha1.NodeCPUSteal{StealPercent: pointer.Int64(2)},
+			wantStatus:    conditionStatusPtr(metav1.ConditionFalse),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateNodeMetricConditions(tt.oldConditions, tt.cpuSteal, tt.threshold)
+			if tt.wantStatus == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Len(t, got, 1)
+			assert.Equal(t, slov1alpha1.NodeMetricConditionCPUStealAnomaly, got[0].Type)
+			assert.Equal(t, *tt.wantStatus, got[0].Status)
+			assert.Equal(t, tt.wantKeepTime, got[0].LastTransitionTime.Equal(&lastTransitionTime))
+		})
+	}
+	// the old conditions are not modified
+	assert.Equal(t, lastTransitionTime, anomalyCondition.LastTransitionTime)
+}
+
+func conditionStatusPtr(status metav1.ConditionStatus) *metav1.ConditionStatus {
+	return &status
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"math"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	// runQueueLatencyFullScoreMicroseconds is the run queue latency which scores 100.
+	runQueueLatencyFullScoreMicroseconds = 1000
+	// coldPageChurnFullScorePercent is the variation of the cold page size relative to the memory capacity which scores 100.
+	coldPageChurnFullScorePercent = 10
+)
+
+var defaultHealthIndexWeights = slov1alpha1.HealthIndexWeights{
+	PSI:             pointer.Int64(40),
+	CPUThrottled:    pointer.Int64(20),
+	ColdPageChurn:   pointer.Int64(10),
+	RunQueueLatency: pointer.Int64(30),
+}
+
+// collectNodeHealthIndex composes the node interference signals into the health index.
+// A component is skipped if its metric is missing, and it returns nil if all the components are missing.
+func (r *nodeMetricInformer) collectNodeHealthIndex(queryParam metriccache.QueryParam, podsMeta []*statesinformer.PodMeta,
+	weights *slov1alpha1.HealthIndexWeights) *slov1alpha1.NodeHealthIndex {
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		klog.V(4).Infof("failed to get querier for node health index, err: %v", err)
+		return nil
+	}
+
+	healthIndex := &slov1alpha1.NodeHealthIndex{}
+	// the pressure of the most stalled resource
+	for _, resource := range []metriccache.MetricPropertyValue{metriccache.PSIResourceCPU, metriccache.PSIResourceMem, metriccache.PSIResourceIO} {
+		if value, ok := queryHealthMetric(querier, metriccache.NodePSIMetric,
+			metriccache.MetricPropertiesFunc.NodePSI(string(resource)), queryParam.Aggregate); ok {
+			healthIndex.PSI = maxScore(healthIndex.PSI, value)
+		}
+	}
+	// the average throttled ratio of the pods
+	var throttledSum float64
+	var throttledCount int
+	for _, podMeta := range podsMeta {
+		if value, ok := queryHealthMetric(querier, metriccache.PodCPUThrottledMetric,
+			metriccache.MetricPropertiesFunc.Pod(string(podMeta.Pod.UID)), queryParam.Aggregate); ok {
+			throttledSum += value
+			throttledCount++
+		}
+	}
+	if throttledCount > 0 {
+		healthIndex.CPUThrottled = maxScore(nil, throttledSum/float64(throttledCount)*100)
+	}
+	// the spread of the cold page size during the aggregation window
+	if memInfo, err := koordletutil.GetMemInfo(); err == nil && memInfo.MemTotal > 0 {
+		p99, ok99 := queryHealthMetric(querier, metriccache.NodeMemoryColdPageSizeMetric, nil, metriccache.AggregationTypeP99)
+		p50, ok50 := queryHealthMetric(querier, metriccache.NodeMemoryColdPageSizeMetric, nil, metriccache.AggregationTypeP50)
+		if ok99 && ok50 {
+			// NOTE: the MemTotal is in kilobytes
+			churnPercent := math.Abs(p99-p50) * 100 / float64(memInfo.MemTotal*1024)
+			healthIndex.ColdPageChurn = maxScore(nil, churnPercent*100/coldPageChurnFullScorePercent)
+		}
+	}
+	if value, ok := queryHealthMetric(querier, metriccache.NodeRunQueueLatencyMetric, nil, queryParam.Aggregate); ok {
+		healthIndex.RunQueueLatency = maxScore(nil, value*100/runQueueLatencyFullScoreMicroseconds)
+	}
+
+	healthIndex.Index = calculateHealthIndex(healthIndex, weights)
+	if healthIndex.Index == nil {
+		return nil
+	}
+	return healthIndex
+}
+
+// calculateHealthIndex returns the weighted average of the reported component scores.
+func calculateHealthIndex(healthIndex *slov1alpha1.NodeHealthIndex, weights *slov1alpha1.HealthIndexWeights) *int64 {
+	if weights == nil {
+		weights = &defaultHealthIndexWeights
+	}
+	var weightedSum, weightSum int64
+	for _, c := range []struct {
+		score, weight, defaultWeight *int64
+	}{
+		{healthIndex.PSI, weights.PSI, defaultHealthIndexWeights.PSI},
+		{healthIndex.CPUThrottled, weights.CPUThrottled, defaultHealthIndexWeights.CPUThrottled},
+		{healthIndex.ColdPageChurn, weights.ColdPageChurn, defaultHealthIndexWeights.ColdPageChurn},
+		{healthIndex.RunQueueLatency, weights.RunQueueLatency, defaultHealthIndexWeights.RunQueueLatency},
+	} {
+		if c.score == nil {
+			continue
+		}
+		weight := *c.defaultWeight
+		if c.weight != nil {
+			weight = *c.weight
+		}
+		if weight <= 0 {
+			continue
+		}
+		weightedSum += *c.score * weight
+		weightSum += weight
+	}
+	if weightSum <= 0 {
+		return nil
+	}
+	return pointer.Int64(int64(math.Round(float64(weightedSum) / float64(weightSum))))
+}
+
+func queryHealthMetric(querier metriccache.Querier, resource metriccache.MetricResource,
+	properties map[metriccache.MetricProperty]string, aggregateType metriccache.AggregationType) (float64, bool) {
+	aggregateResult, err := doQuery(querier, resource, properties)
+	if err != nil || aggregateResult.Count() == 0 {
+		return 0, false
+	}
+	value, err := aggregateResult.Value(aggregateType)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// maxScore returns the larger one of the current score and the value clamped into [0, 100].
+func maxScore(current *int64, value float64) *int64 {
+	score := int64(math.Round(math.Max(0, math.Min(100, value))))
+	if current != nil && *current > score {
+		return current
+	}
+	return pointer.Int64(score)
+}
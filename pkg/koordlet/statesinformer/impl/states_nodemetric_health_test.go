@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func Test_calculateHealthIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		healthIndex *slov1alpha1.NodeHealthIndex
+		weights     *slov1alpha1.HealthIndexWeights
+		want        *int64
+	}{
+		{
+			name:        "no component reported",
+			healthIndex: &slov1alpha1.NodeHealthIndex{},
+			want:        nil,
+		},
+		{
+			name: "default weights",
+			healthIndex: &slov1alpha1.NodeHealthIndex{
+				PSI:             pointer.Int64(50),
+				CPUThrottled:    pointer.Int64(10),
+				ColdPageChurn:   pointer.Int64(0),
+				RunQueueLatency: pointer.Int64(20),
+			},
+			// (50*40 + 10*20 + 0*10 + 20*30) / 100
+			want: pointer.Int64(28),
+		},
+		{
+			name: "skip missing components",
+			healthIndex: &slov1alpha1.NodeHealthIndex{
+				PSI:          pointer.Int64(50),
+				CPUThrottled: pointer.Int64(20),
+			},
+			// (50*40 + 20*20) / 60
+			want: pointer.Int64(40),
+		},
+		{
+			name: "custom weights and zero weight excludes the component",
+			healthIndex: &slov1alpha1.NodeHealthIndex{
+				PSI:             pointer.Int64(50),
+				CPUThrottled:    pointer.Int64(10),
+				ColdPageChurn:   pointer.Int64(100),
+				RunQueueLatency: pointer.Int64(20),
+			},
+			weights: &slov1alpha1.HealthIndexWeights{
+				PSI:           pointer.Int64(1),
+				ColdPageChurn: pointer.Int64(0),
+			},
+			// (50*1 + 10*20 + 20*30) / 51
+			want: pointer.Int64(17),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateHealthIndex(tt.healthIndex, tt.weights)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_maxScore(t *testing.T) {
+	assert.Equal(t, pointer.Int64(100), maxScore(nil, 250))
+	assert.Equal(t, pointer.Int64(0), maxScore(nil, -1))
+	assert.Equal(t, pointer.Int64(30), maxScore(pointer.Int64(30), 12.4))
+	assert.Equal(t, pointer.Int64(13), maxScore(pointer.Int64(10), 12.5))
+}
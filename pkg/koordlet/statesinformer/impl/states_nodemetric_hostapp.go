@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+)
+
+// getHostApplications returns the host applications declared in the NodeSLO.
+func (r *nodeMetricInformer) getHostApplications() []slov1alpha1.HostApplicationSpec {
+	if r.nodeSLOInformer == nil {
+		return nil
+	}
+	nodeSLO := r.nodeSLOInformer.GetNodeSLO()
+	if nodeSLO == nil {
+		return nil
+	}
+	return nodeSLO.Spec.HostApplications
+}
+
+// collectHostAppMetric returns the average resource usage of the host applications during the aggregate duration.
+// The host applications whose metrics are not ready are skipped.
+func (r *nodeMetricInformer) collectHostAppMetric() []*slov1alpha1.HostApplicationMetricInfo {
+	hostApps := r.getHostApplications()
+	if len(hostApps) <= 0 {
+		return nil
+	}
+	spec := r.getNodeMetricSpec()
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(*spec.CollectPolicy.AggregateDurationSeconds) * time.Second)
+	queryParam := metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		Start:     &startTime,
+		End:       &endTime,
+	}
+
+	hostAppMetrics := make([]*slov1alpha1.HostApplicationMetricInfo, 0, len(hostApps))
+	for i := range hostApps {
+		hostApp := &hostApps[i]
+		appMetric, err := r.collectSingleHostAppMetric(hostApp, queryParam)
+		if err != nil {
+			klog.V(4).Infof("query host application metric failed, name %s, error %v", hostApp.Name, err)
+			continue
+		}
+		hostAppMetrics = append(hostAppMetrics, appMetric)
+	}
+	return hostAppMetrics
+}
+
+func (r *nodeMetricInformer) collectSingleHostAppMetric(hostApp *slov1alpha1.HostApplicationSpec,
+	queryParam metriccache.QueryParam) (*slov1alpha1.HostApplicationMetricInfo, error) {
+	if r.metricCache == nil {
+		return nil, fmt.Errorf("metric cache is not initialized")
+	}
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		return nil, err
+	}
+
+	cpuAggregateResult, err := doQuery(querier, metriccache.HostAppCPUUsageMetric, metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name))
+	if err != nil {
+		return nil, err
+	}
+	cpuUsed, err := cpuAggregateResult.Value(queryParam.Aggregate)
+	if err != nil {
+		return nil, err
+	}
+	memAggregateResult, err := doQuery(querier, metriccache.HostAppMemoryUsageMetric, metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name))
+	if err != nil {
+		return nil, err
+	}
+	memUsed, err := memAggregateResult.Value(queryParam.Aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slov1alpha1.HostApplicationMetricInfo{
+		Name: hostApp.Name,
+		Usage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(cpuUsed*1000), resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(int64(memUsed), resource.BinarySI),
+			},
+		},
+		Priority: hostApp.Priority,
+		QoS:      hostApp.QoS,
+	}, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+)
+
+func Test_nodeMetricInformer_collectHostAppMetric(t *testing.T) {
+	testHostApp := slov1alpha1.HostApplicationSpec{
+		Name:     "test-app",
+		Priority: apiext.PriorityProd,
+		QoS:      apiext.QoSLS,
+	}
+	tests := []struct {
+		name     string
+		nodeSLO  *slov1alpha1.NodeSLO
+		cpuUsed  float64
+		memUsed  float64
+		expected []*slov1alpha1.HostApplicationMetricInfo
+	}{
+		{
+			name:     "no node slo",
+			nodeSLO:  nil,
+			expected: nil,
+		},
+		{
+			name: "no host application",
+			nodeSLO: &slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{},
+			},
+			expected: nil,
+		},
+		{
+			name: "report host application usage",
+			nodeSLO: &slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{
+					HostApplications: []slov1alpha1.HostApplicationSpec{testHostApp},
+				},
+			},
+			cpuUsed: 1.5,
+			memUsed: 1024 * 1024 * 1024,
+			expected: []*slov1alpha1.HostApplicationMetricInfo{
+				{
+					Name: "test-app",
+					Usage: slov1alpha1.ResourceMap{
+						ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    *resource.NewMilliQuantity(1500, resource.DecimalSI),
+							corev1.ResourceMemory: *resource.NewQuantity(1024*1024*1024, resource.BinarySI),
+						},
+					},
+					Priority: apiext.PriorityProd,
+					QoS:      apiext.QoSLS,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			oldFactory := metriccache.DefaultAggregateResultFactory
+			defer func() {
+				metriccache.DefaultAggregateResultFactory = oldFactory
+			}()
+			mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+			mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+			metriccache.DefaultAggregateResultFactory = mockResultFactory
+			mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+			mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+
+			cpuQueryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication(testHostApp.Name))
+			assert.NoError(t, err)
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, cpuQueryMeta, tt.cpuUsed, time.Minute)
+			memQueryMeta, err := metriccache.HostAppMemoryUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication(testHostApp.Name))
+			assert.NoError(t, err)
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, memQueryMeta, tt.memUsed, time.Minute)
+
+			r := &nodeMetricInformer{
+				metricCache: mockMetricCache,
+				nodeSLOInformer: &nodeSLOInformer{
+					nodeSLO: tt.nodeSLO,
+				},
+			}
+			got := r.collectHostAppMetric()
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
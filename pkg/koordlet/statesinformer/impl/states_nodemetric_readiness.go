@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	// minColocationKernelMajor and minColocationKernelMinor is the minimal kernel version for the colocation, which
+	// supports the PSI and the cgroup interfaces used by koordlet.
+	minColocationKernelMajor = 4
+	minColocationKernelMinor = 19
+
+	kernelOSReleaseRelativePath = "sys/kernel/osrelease"
+	cgroupV2ControllersFileName = "cgroup.controllers"
+
+	colocationReadyReasonAllPassed    = "AllChecksPassed"
+	colocationReadyReasonChecksFailed = "ChecksFailed"
+)
+
+var (
+	kernelVersionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)`)
+
+	colocationCgroupV1Controllers = []string{system.CgroupCPUDir, system.CgroupCPUSetDir, system.CgroupCPUAcctDir, system.CgroupMemDir}
+	colocationCgroupV2Controllers = []string{"cpu", "cpuset", "memory"}
+)
+
+// checkColocationReadiness checks whether the node OS image and the kernel parameters satisfy the colocation
+// strategies, and scores the node by the percent of the passed checks.
+func checkColocationReadiness() *slov1alpha1.ColocationReadiness {
+	checks := []slov1alpha1.ColocationReadinessCheck{
+		checkKernelVersion(),
+		checkCgroupControllers(),
+	}
+	// the group identity is only provided by the Anolis kernel, so the other kernels are not scored down for it
+	if system.HostSystemInfo.IsAnolisOS {
+		checks = append(checks, checkSchedFeatures())
+	}
+	checks = append(checks, checkMemcgQoS())
+	passed := 0
+	for _, check := range checks {
+		if check.Passed {
+			passed++
+		}
+	}
+	return &slov1alpha1.ColocationReadiness{
+		Score:  int64(passed * 100 / len(checks)),
+		Checks: checks,
+	}
+}
+
+func checkKernelVersion() slov1alpha1.ColocationReadinessCheck {
+	check := slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckKernelVersion}
+	content, err := os.ReadFile(system.GetProcFilePath(kernelOSReleaseRelativePath))
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to read kernel release, err: %v", err)
+		return check
+	}
+	release := strings.TrimSpace(string(content))
+	matches := kernelVersionRegexp.FindStringSubmatch(release)
+	if len(matches) != 3 {
+		check.Message = fmt.Sprintf("failed to parse kernel release %s", release)
+		return check
+	}
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	if major < minColocationKernelMajor || (major == minColocationKernelMajor && minor < minColocationKernelMinor) {
+		check.Message = fmt.Sprintf("kernel %s is older than %d.%d", release, minColocationKernelMajor, minColocationKernelMinor)
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("kernel %s", release)
+	return check
+}
+
+func checkCgroupControllers() slov1alpha1.ColocationReadinessCheck {
+	check := slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckCgroupControllers}
+	var missing []string
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		content, err := os.ReadFile(filepath.Join(system.Conf.CgroupRootDir, cgroupV2ControllersFileName))
+		if err != nil {
+			check.Message = fmt.Sprintf("failed to read cgroup controllers, err: %v", err)
+			return check
+		}
+		enabled := strings.Fields(string(content))
+		for _, controller := range colocationCgroupV2Controllers {
+			if !containsString(enabled, controller) {
+				missing = append(missing, controller)
+			}
+		}
+	} else {
+		for _, subfs := range colocationCgroupV1Controllers {
+			if exists, _ := system.PathExists(filepath.Join(system.Conf.CgroupRootDir, subfs)); !exists {
+				missing = append(missing, strings.TrimSuffix(subfs, "/"))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		check.Message = fmt.Sprintf("cgroup controllers %v are not enabled", missing)
+		return check
+	}
+	check.Passed = true
+	return check
+}
+
+func checkSchedFeatures() slov1alpha1.ColocationReadinessCheck {
+	check := slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckSchedFeatures}
+	bvtPath := filepath.Join(system.GetRootCgroupSubfsDir(system.CgroupCPUDir), system.CPUBVTWarpNsName)
+	bvtExists, _ := system.PathExists(bvtPath)
+	if !bvtExists && !system.FileExists(system.GetProcSysFilePath(system.KernelSchedGroupIdentityEnable)) {
+		check.Message = fmt.Sprintf("%s not found, the group identity is unsupported", system.CPUBVTWarpNsName)
+		return check
+	}
+	check.Passed = true
+	return check
+}
+
+func checkMemcgQoS() slov1alpha1.ColocationReadinessCheck {
+	check := slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS}
+	var unsupported []string
+	for _, resourceType := range []system.ResourceType{system.MemoryMinName, system.MemoryLowName} {
+		r, err := system.GetCgroupResource(resourceType)
+		if err != nil {
+			unsupported = append(unsupported, string(resourceType))
+			continue
+		}
+		if supported, _ := r.IsSupported(""); !supported {
+			unsupported = append(unsupported, string(resourceType))
+		}
+	}
+	if len(unsupported) > 0 {
+		check.Message = fmt.Sprintf("memcg interfaces %v are unsupported", unsupported)
+		return check
+	}
+	check.Passed = true
+	return check
+}
+
+// generateColocationReadyCondition updates the ColocationReady condition according to the readiness.
+// The condition is removed if the readiness is not checked.
+func generateColocationReadyCondition(conditions []metav1.Condition, readiness *slov1alpha1.ColocationReadiness) []metav1.Condition {
+	if readiness == nil {
+		meta.RemoveStatusCondition(&conditions, slov1alpha1.NodeMetricConditionColocationReady)
+	} else {
+		var failed []string
+		for _, check := range readiness.Checks {
+			if !check.Passed {
+				failed = append(failed, check.Name)
+			}
+		}
+		condition := metav1.Condition{
+			Type:    slov1alpha1.NodeMetricConditionColocationReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  colocationReadyReasonAllPassed,
+			Message: fmt.Sprintf("readiness score %d", readiness.Score),
+		}
+		if len(failed) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = colocationReadyReasonChecksFailed
+			condition.Message = fmt.Sprintf("readiness score %d, failed checks: %s", readiness.Score, strings.Join(failed, ", "))
+		}
+		meta.SetStatusCondition(&conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	return conditions
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_checkColocationReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		anolisOS   bool
+		prepareFn  func(helper *system.FileTestUtil)
+		wantScore  int64
+		wantPassed map[string]bool
+	}{
+		{
+			name:     "all checks passed on cgroups v1",
+			anolisOS: true,
+			prepareFn: func(helper *system.FileTestUtil) {
+				helper.WriteProcSubFileContents("sys/kernel/osrelease", "5.10.134-13.an8.x86_64\n")
+				for _, subfs := range []string{system.CgroupCPUDir, system.CgroupCPUSetDir, system.CgroupCPUAcctDir, system.CgroupMemDir} {
+					helper.MkDirAll(subfs)
+				}
+				helper.WriteCgroupFileContents("", system.CPUBVTWarpNs, "0")
+				helper.SetResourcesSupported(true, system.MemoryMin, system.MemoryLow)
+			},
+			wantScore: 100,
+			wantPassed: map[string]bool{
+				slov1alpha1.ColocationReadinessCheckKernelVersion:     true,
+				slov1alpha1.ColocationReadinessCheckCgroupControllers: true,
+				slov1alpha1.ColocationReadinessCheckSchedFeatures:     true,
+				slov1alpha1.ColocationReadinessCheckMemcgQoS:          true,
+			},
+		},
+		{
+			name: "old kernel without group identity and memcg qos",
+			prepareFn: func(helper *system.FileTestUtil) {
+				helper.WriteProcSubFileContents("sys/kernel/osrelease", "3.10.0-1160.el7.x86_64\n")
+				for _, subfs := range []string{system.CgroupCPUDir, system.CgroupCPUSetDir, system.CgroupCPUAcctDir, system.CgroupMemDir} {
+					helper.MkDirAll(subfs)
+				}
+				helper.SetResourcesSupported(false, system.MemoryMin, system.MemoryLow)
+			},
+			wantScore: 33,
+			wantPassed: map[string]bool{
+				slov1alpha1.ColocationReadinessCheckKernelVersion:     false,
+				slov1alpha1.ColocationReadinessCheckCgroupControllers: true,
+				slov1alpha1.ColocationReadinessCheckMemcgQoS:          false,
+			},
+		},
+		{
+			name:     "missing cpuset controller and kernel release",
+			anolisOS: true,
+			prepareFn: func(helper *system.FileTestUtil) {
+				for _, subfs := range []string{system.CgroupCPUDir, system.CgroupCPUAcctDir, system.CgroupMemDir} {
+					helper.MkDirAll(subfs)
+				}
+				helper.WriteCgroupFileContents("", system.CPUBVTWarpNs, "0")
+				helper.SetResourcesSupported(true, system.MemoryMin, system.MemoryLow)
+			},
+			wantScore: 50,
+			wantPassed: map[string]bool{
+				slov1alpha1.ColocationReadinessCheckKernelVersion:     false,
+				slov1alpha1.ColocationReadinessCheckCgroupControllers: false,
+				slov1alpha1.ColocationReadinessCheckSchedFeatures:     true,
+				slov1alpha1.ColocationReadinessCheckMemcgQoS:          true,
+			},
+		},
+		{
+			name:     "anolis kernel without group identity",
+			anolisOS: true,
+			prepareFn: func(helper *system.FileTestUtil) {
+				helper.WriteProcSubFileContents("sys/kernel/osrelease", "5.10.134-13.an8.x86_64\n")
+				for _, subfs := range []string{system.CgroupCPUDir, system.CgroupCPUSetDir, system.CgroupCPUAcctDir, system.CgroupMemDir} {
+					helper.MkDirAll(subfs)
+				}
+				helper.SetResourcesSupported(true, system.MemoryMin, system.MemoryLow)
+			},
+			wantScore: 75,
+			wantPassed: map[string]bool{
+				slov1alpha1.ColocationReadinessCheckKernelVersion:     true,
+				slov1alpha1.ColocationReadinessCheckCgroupControllers: true,
+				slov1alpha1.ColocationReadinessCheckSchedFeatures:     false,
+				slov1alpha1.ColocationReadinessCheckMemcgQoS:          true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(false)
+			oldHostSystemInfo := system.HostSystemInfo
+			system.HostSystemInfo.IsAnolisOS = tt.anolisOS
+			defer func() {
+				system.HostSystemInfo = oldHostSystemInfo
+			}()
+			tt.prepareFn(helper)
+
+			got := checkColocationReadiness()
+			assert.Equal(t, tt.wantScore, got.Score)
+			gotPassed := map[string]bool{}
+			for _, check := range got.Checks {
+				gotPassed[check.Name] = check.Passed
+			}
+			assert.Equal(t, tt.wantPassed, gotPassed)
+		})
+	}
+}
+
+func Test_generateColocationReadyCondition(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldConditions []metav1.Condition
+		readiness     *slov1alpha1.ColocationReadiness
+		wantStatus    *metav1.ConditionStatus
+		wantMessage   string
+	}{
+		{
+			name:       "readiness not checked",
+			readiness:  nil,
+			wantStatus: nil,
+		},
+		{
+			name: "remove the condition if readiness is not checked",
+			oldConditions: []metav1.Condition{
+				{
+					Type:   slov1alpha1.NodeMetricConditionColocationReady,
+					Status: metav1.ConditionTrue,
+					Reason: colocationReadyReasonAllPassed,
+				},
+			},
+			readiness:  nil,
+			wantStatus: nil,
+		},
+		{
+			name: "all checks passed",
+			readiness: &slov1alpha1.ColocationReadiness{
+				Score: 100,
+				Checks: []slov1alpha1.ColocationReadinessCheck{
+					{Name: slov1alpha1.ColocationReadinessCheckKernelVersion, Passed: true},
+					{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS, Passed: true},
+				},
+			},
+			wantStatus:  conditionStatusPtr(metav1.ConditionTrue),
+			wantMessage: "readiness score 100",
+		},
+		{
+			name: "some checks failed",
+			readiness: &slov1alpha1.ColocationReadiness{
+				Score: 50,
+				Checks: []slov1alpha1.ColocationReadinessCheck{
+					{Name: slov1alpha1.ColocationReadinessCheckKernelVersion, Passed: true},
+					{Name: slov1alpha1.ColocationReadinessCheckSchedFeatures, Passed: false},
+					{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS, Passed: false},
+					{Name: slov1alpha1.ColocationReadinessCheckCgroupControllers, Passed: true},
+				},
+			},
+			wantStatus:  conditionStatusPtr(metav1.ConditionFalse),
+			wantMessage: "readiness score 50, failed checks: SchedFeatures, MemcgQoS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateColocationReadyCondition(tt.oldConditions, tt.readiness)
+			condition := meta.FindStatusCondition(got, slov1alpha1.NodeMetricConditionColocationReady)
+			if tt.wantStatus == nil {
+				assert.Nil(t, condition)
+				return
+			}
+			assert.NotNil(t, condition)
+			assert.Equal(t, *tt.wantStatus, condition.Status)
+			assert.Equal(t, tt.wantMessage, condition.Message)
+		})
+	}
+}
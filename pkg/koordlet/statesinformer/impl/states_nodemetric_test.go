@@ -178,6 +178,7 @@ func Test_reporter_sync_with_single_node_metric(t *testing.T) {
 		wantNodeResource   slov1alpha1.ResourceMap
 		wantSystemResource slov1alpha1.ResourceMap
 		wantPodsMetric     []*slov1alpha1.PodMetricInfo
+		wantMemoryLocality *slov1alpha1.MemoryLocality
 		wantErr            bool
 	}{
 		{
@@ -294,6 +295,12 @@ func Test_reporter_sync_with_single_node_metric(t *testing.T) {
 						metriccache.MetricPropertiesFunc.PodGPU("test-pod", "1", "2"))
 					assert.NoError(t, err)
 					buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podGPU2Mem, 50, endTime.Sub(startTime))
+					podLocalPages, err := metriccache.PodMemoryLocalPagesMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod"))
+					assert.NoError(t, err)
+					buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podLocalPages, 300, duration)
+					podRemotePages, err := metriccache.PodMemoryRemotePagesMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod"))
+					assert.NoError(t, err)
+					buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podRemotePages, 100, duration)
 					return mockMetricCache
 				},
 				podsInformer: &podsInformer{
@@ -373,8 +380,16 @@ func Test_reporter_sync_with_single_node_metric(t *testing.T) {
 							}},
 						},
 					},
+					MemoryLocality: &slov1alpha1.MemoryLocality{
+						LocalRatio:  pointer.Int64(75),
+						RemoteRatio: pointer.Int64(25),
+					},
 				},
 			},
+			wantMemoryLocality: &slov1alpha1.MemoryLocality{
+				LocalRatio:  pointer.Int64(75),
+				RemoteRatio: pointer.Int64(25),
+			},
 			wantErr: false,
 		},
 		{
@@ -466,6 +481,7 @@ func Test_reporter_sync_with_single_node_metric(t *testing.T) {
 					assert.Equal(t, tt.wantNodeResource, nodeMetric.Status.NodeMetric.NodeUsage)
 					assert.Equal(t, tt.wantSystemResource, nodeMetric.Status.NodeMetric.SystemUsage)
 					assert.Equal(t, tt.wantPodsMetric, nodeMetric.Status.PodsMetric)
+					assert.Equal(t, tt.wantMemoryLocality, nodeMetric.Status.NodeMetric.MemoryLocality)
 				}
 			}
 		})
@@ -683,7 +699,8 @@ func Test_nodeMetricInformer_NewAndSetup(t *testing.T) {
 				state: &PluginState{
 					metricCache: mockmetriccache.NewMockMetricCache(ctrl),
 					informerPlugins: map[PluginName]informerPlugin{
-						podsInformerName: NewPodsInformer(),
+						podsInformerName:    NewPodsInformer(),
+						nodeSLOInformerName: NewNodeSLOInformer(),
 					},
 				},
 			},
@@ -36,6 +36,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
@@ -44,6 +45,8 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager"
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/state"
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/topology"
+	devicecheckpoint "k8s.io/kubernetes/pkg/kubelet/cm/devicemanager/checkpoint"
+	memorystate "k8s.io/kubernetes/pkg/kubelet/cm/memorymanager/state"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -51,6 +54,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/kubelet"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
@@ -114,9 +118,13 @@ type nodeTopoInformer struct {
 	nodeResourceTopologyInformer cache.SharedIndexInformer
 	nodeResourceTopologyLister   topologylister.NodeResourceTopologyLister
 
-	kubelet      KubeletStub
-	nodeInformer *nodeInformer
-	podsInformer *podsInformer
+	kubelet         KubeletStub
+	nodeInformer    *nodeInformer
+	nodeSLOInformer *nodeSLOInformer
+	podsInformer    *podsInformer
+
+	// reportConflictRounds is the number of the consecutive report rounds failed due to the conflicts.
+	reportConflictRounds int
 }
 
 func NewNodeTopoInformer() *nodeTopoInformer {
@@ -145,6 +153,13 @@ func (s *nodeTopoInformer) Setup(ctx *PluginOption, state *PluginState) {
 	}
 	s.nodeInformer = nodeInformer
 
+	nodeSLOInformerIf := state.informerPlugins[nodeSLOInformerName]
+	nodeSLOInformer, ok := nodeSLOInformerIf.(*nodeSLOInformer)
+	if !ok {
+		klog.Fatalf("node slo informer format error")
+	}
+	s.nodeSLOInformer = nodeSLOInformer
+
 	podsInformerIf := state.informerPlugins[podsInformerName]
 	podsInformer, ok := podsInformerIf.(*podsInformer)
 	if !ok {
@@ -310,15 +325,56 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 
 	// handle cpus allocated for system qos of node
 	systemQOSRes, err := extension.GetSystemQOSResource(node.Annotations)
-	// TODO consider define in NodeSLO for system qos, annotation on node is provided as "Syntactic Sugar", which overlaps the NodeSLO for custom-definition
 	if err != nil {
 		return nil, fmt.Errorf("failed to get system qos resource from node annotation, error: %v", err)
 	}
+	if systemQOSRes == nil || systemQOSRes.CPUSet == "" {
+		// the cpus reserved for the system qos pods in cluster-level take effect if the node does not specify
+		if reservedCPUs := s.getNodeSLOReservedCPUs(); reservedCPUs != "" {
+			systemQOSRes = &extension.SystemQOSResource{CPUSet: reservedCPUs}
+		}
+	}
 	systemQOSJson, err := json.Marshal(systemQOSRes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal system qos resource, error %v", err)
 	}
 
+	// handle cpus for housekeeping of node
+	housekeepingCPUs, err := getNodeHousekeepingCPUs(topo, node.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get housekeeping cpus from node annotation, error: %v", err)
+	}
+	var housekeepingJSON []byte
+	if housekeepingCPUs != nil {
+		housekeepingJSON, err = json.Marshal(housekeepingCPUs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal housekeeping cpus, error: %v", err)
+		}
+	}
+
+	// report the NUMA Nodes of the SR-IOV NICs, so the scheduler can bind the CPUs of the Pods to the device NUMA Nodes
+	var sriovDevicesJSON []byte
+	deviceCheckpointData, err := os.ReadFile(kubelet.GetDeviceManagerCheckpointFilePath("/var/lib/kubelet"))
+	if err != nil && !os.IsNotExist(err) {
+		klog.V(4).Infof("failed to read device manager checkpoint file, err: %v", err)
+	}
+	if sriovDevices := getNodeSRIOVDevices(string(deviceCheckpointData)); len(sriovDevices) > 0 {
+		sriovDevicesJSON, err = json.Marshal(sriovDevices)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal sriov devices, error: %v", err)
+		}
+	}
+	// report the NUMA Nodes and the link states of the NICs, which refresh in the next sync when the links change
+	var nicDevicesJSON []byte
+	if features.DefaultKoordletFeatureGate.Enabled(features.NICTopologyReport) {
+		if nicDevices := getNodeNICDevices(); len(nicDevices) > 0 {
+			nicDevicesJSON, err = json.Marshal(nicDevices)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal nic devices, error: %v", err)
+			}
+		}
+	}
+
 	// Users can specify the kubelet RootDirectory on the host in the koordlet DaemonSet,
 	// but inside koordlet it is always mounted to the path /var/lib/kubelet
 	stateFilePath := kubelet.GetCPUManagerStateFilePath("/var/lib/kubelet")
@@ -343,6 +399,27 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 		}
 	}
 
+	// report the memory pinned by the kubelet Memory Manager, so the scheduler does not double book it
+	memoryStateData, err := os.ReadFile(kubelet.GetMemoryManagerStateFilePath("/var/lib/kubelet"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read memory manager state file, err: %v", err)
+		}
+	}
+	var podMemoryAllocsJSON []byte
+	if len(memoryStateData) > 0 {
+		podMemoryAllocs, err := s.calKubeletPinnedMemory(string(memoryStateData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to cal kubelet pinned memory, err: %v", err)
+		}
+		if len(podMemoryAllocs) != 0 {
+			podMemoryAllocsJSON, err = json.Marshal(podMemoryAllocs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal pod memory allocs, err: %v", err)
+			}
+		}
+	}
+
 	cpuTopologyJSON, err := json.Marshal(cpuTopology)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal cpu topology of node, err: %v", err)
@@ -358,6 +435,21 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 	// remove cpus that exclusive for system qos from annotation
 	lsSharePools = removeSystemQOSCPUs(lsSharePools, systemQOSRes)
 	beSharePools = removeSystemQOSCPUs(beSharePools, systemQOSRes)
+
+	// remove cpus isolated by the kernel cmdline since the kernel does not balance the shared pods onto them
+	kernelCPUIsolation := &nodeCPUInfo.KernelCPUIsolation
+	if isolCPUs, err := cpuset.Parse(kernelCPUIsolation.IsolCPUs); err == nil && !isolCPUs.IsEmpty() {
+		lsSharePools = removeNodeReservedCPUs(lsSharePools, isolCPUs)
+		beSharePools = removeNodeReservedCPUs(beSharePools, isolCPUs)
+	}
+	// remove cpus for housekeeping from the LS share pools, the BE pods can still use them
+	if housekeepingCPUs != nil {
+		lsSharePools = removeNodeReservedCPUs(lsSharePools, cpuset.MustParse(housekeepingCPUs.CPUSet))
+	}
+	kernelCPUIsolationJSON, err := json.Marshal(kernelCPUIsolation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal kernel cpu isolation, err: %v", err)
+	}
 	lsCPUSharePoolsJSON, err := json.Marshal(lsSharePools)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal cpushare pools of node, err: %v", err)
@@ -373,16 +465,29 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 		extension.AnnotationNodeCPUSharedPools:      string(lsCPUSharePoolsJSON),
 		extension.AnnotationKubeletCPUManagerPolicy: string(cpuManagerPolicyJSON),
 		extension.AnnotationNodeBECPUSharedPools:    string(beCPUSharePoolsJSON),
+		extension.AnnotationNodeKernelCPUIsolation:  string(kernelCPUIsolationJSON),
 	}
 	if len(podAllocsJSON) != 0 {
 		annotations[extension.AnnotationNodeCPUAllocs] = string(podAllocsJSON)
 	}
+	if len(podMemoryAllocsJSON) != 0 {
+		annotations[extension.AnnotationNodeMemoryAllocs] = string(podMemoryAllocsJSON)
+	}
 	if len(reservedJson) != 0 {
 		annotations[extension.AnnotationNodeReservation] = string(reservedJson)
 	}
 	if len(systemQOSJson) != 0 {
 		annotations[extension.AnnotationNodeSystemQOSResource] = string(systemQOSJson)
 	}
+	if len(housekeepingJSON) != 0 {
+		annotations[extension.AnnotationNodeHousekeepingCPUs] = string(housekeepingJSON)
+	}
+	if len(sriovDevicesJSON) != 0 {
+		annotations[extension.AnnotationNodeSRIOVDevices] = string(sriovDevicesJSON)
+	}
+	if len(nicDevicesJSON) != 0 {
+		annotations[extension.AnnotationNodeNICs] = string(nicDevicesJSON)
+	}
 	nodeTopoStatus.Annotations = annotations
 
 	klog.V(6).Infof("calculate node topology status: %+v", nodeTopoStatus)
@@ -451,6 +556,121 @@ func getNodeReserved(cpuTopology *topology.CPUTopology, nodeAnnotations map[stri
 	return reserved
 }
 
+// getNodeHousekeepingCPUs returns the housekeeping cpus configured by annotation of node.
+// The cpus not belonging to the node are dropped.
+func getNodeHousekeepingCPUs(cpuTopology *topology.CPUTopology, nodeAnnotations map[string]string) (*extension.HousekeepingCPUs, error) {
+	housekeepingCPUs, err := extension.GetHousekeepingCPUs(nodeAnnotations)
+	if err != nil || housekeepingCPUs == nil {
+		return nil, err
+	}
+	cpus, err := cpuset.Parse(housekeepingCPUs.CPUSet)
+	if err != nil {
+		return nil, err
+	}
+	builder := cpuset.NewCPUSetBuilder()
+	for _, cpu := range cpus.ToSliceNoSort() {
+		if _, ok := cpuTopology.CPUDetails[cpu]; ok {
+			builder.Add(cpu)
+		}
+	}
+	cpus = builder.Result()
+	if cpus.IsEmpty() {
+		return nil, nil
+	}
+	return &extension.HousekeepingCPUs{CPUSet: cpus.String()}, nil
+}
+
+// getNodeSRIOVDevices returns the SR-IOV PFs of the node with their NUMA Nodes, and the device plugin resources
+// allocating their VFs according to the kubelet device manager checkpoint.
+// The detection failure is ignored to not block the other topology reporting.
+func getNodeSRIOVDevices(deviceCheckpointJSON string) []extension.SRIOVDevice {
+	pfs, err := system.GetSRIOVPhysicalFunctions()
+	if err != nil {
+		klog.V(4).Infof("failed to get sriov physical functions, err: %v", err)
+		return nil
+	}
+	deviceResources := getRegisteredDeviceResources(deviceCheckpointJSON)
+	var devices []extension.SRIOVDevice
+	for _, pf := range pfs {
+		resourceNames := sets.NewString()
+		for _, vf := range pf.VFBusIDs {
+			if resourceName, ok := deviceResources[vf]; ok {
+				resourceNames.Insert(resourceName)
+			}
+		}
+		device := extension.SRIOVDevice{
+			BusID:    pf.BusID,
+			NUMANode: pf.NUMANode,
+			NumVFs:   pf.NumVFs,
+		}
+		if resourceNames.Len() > 0 {
+			device.ResourceNames = resourceNames.List()
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// getRegisteredDeviceResources returns the device plugin resource names of the devices registered to the kubelet.
+func getRegisteredDeviceResources(deviceCheckpointJSON string) map[string]string {
+	if deviceCheckpointJSON == "" {
+		return nil
+	}
+	checkpoint := &devicecheckpoint.Data{}
+	if err := json.Unmarshal([]byte(deviceCheckpointJSON), checkpoint); err != nil {
+		klog.V(4).Infof("failed to parse device manager checkpoint, err: %v", err)
+		return nil
+	}
+	_, registeredDevices := checkpoint.GetDataInLatestFormat()
+	deviceResources := map[string]string{}
+	for resourceName, deviceIDs := range registeredDevices {
+		for _, deviceID := range deviceIDs {
+			deviceResources[deviceID] = resourceName
+		}
+	}
+	return deviceResources
+}
+
+// getNodeNICDevices returns the physical NICs of the node with their NUMA Nodes and link states.
+// The detection failure is ignored to not block the other topology reporting.
+func getNodeNICDevices() []extension.NICDevice {
+	nics, err := system.GetNetworkInterfaces()
+	if err != nil {
+		klog.V(4).Infof("failed to get network interfaces, err: %v", err)
+		return nil
+	}
+	var devices []extension.NICDevice
+	for _, nic := range nics {
+		devices = append(devices, extension.NICDevice{
+			Name:      nic.Name,
+			BusID:     nic.BusID,
+			NUMANode:  nic.NUMANode,
+			SpeedMbps: nic.SpeedMbps,
+			OperState: nic.OperState,
+			TotalVFs:  nic.TotalVFs,
+			NumVFs:    nic.NumVFs,
+		})
+	}
+	return devices
+}
+
+// getNodeSLOReservedCPUs returns the cpus reserved for the system qos pods by the SystemStrategy of NodeSLO.
+func (s *nodeTopoInformer) getNodeSLOReservedCPUs() string {
+	if s.nodeSLOInformer == nil {
+		return ""
+	}
+	nodeSLO := s.nodeSLOInformer.GetNodeSLO()
+	if nodeSLO == nil || nodeSLO.Spec.SystemStrategy == nil || nodeSLO.Spec.SystemStrategy.ReservedCPUs == nil {
+		return ""
+	}
+	reservedCPUs := *nodeSLO.Spec.SystemStrategy.ReservedCPUs
+	if _, err := cpuset.Parse(reservedCPUs); err != nil {
+		klog.Warningf("failed to parse reserved cpus %s of NodeSLO, err: %v", reservedCPUs, err)
+		return ""
+	}
+	return reservedCPUs
+}
+
 func (s *nodeTopoInformer) calGuaranteedCpu(usedCPUs map[int32]*extension.CPUInfo, stateJSON string) ([]extension.PodCPUAlloc, error) {
 	if stateJSON == "" {
 		return nil, fmt.Errorf("empty state file")
@@ -521,6 +741,87 @@ func (s *nodeTopoInformer) calGuaranteedCpu(usedCPUs map[int32]*extension.CPUInf
 	return podAllocs, nil
 }
 
+// calKubeletPinnedMemory parses the checkpoint of the kubelet Memory Manager, and returns the memory pinned on each NUMA
+// Node for the Pods not managed by koordinator. The memory block spanning multiple NUMA Nodes is divided evenly.
+func (s *nodeTopoInformer) calKubeletPinnedMemory(stateJSON string) ([]extension.PodMemoryAlloc, error) {
+	if stateJSON == "" {
+		return nil, fmt.Errorf("empty state file")
+	}
+	checkpoint := &memorystate.MemoryManagerCheckpoint{}
+	err := json.Unmarshal([]byte(stateJSON), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make(map[types.UID]*statesinformer.PodMeta)
+	managedPods := make(map[types.UID]struct{})
+	for _, podMeta := range s.podsInformer.GetAllPods() {
+		pods[podMeta.Pod.UID] = podMeta
+		// the NUMA resources of the pod are already accounted by koord-scheduler
+		resourceStatus, err := extension.GetResourceStatus(podMeta.Pod.Annotations)
+		if err == nil && len(resourceStatus.NUMANodeResources) > 0 {
+			managedPods[podMeta.Pod.UID] = struct{}{}
+		}
+	}
+
+	var podAllocs []extension.PodMemoryAlloc
+	for podUID := range checkpoint.Entries {
+		if _, ok := managedPods[types.UID(podUID)]; ok {
+			continue
+		}
+		numaResources := map[int]corev1.ResourceList{}
+		for _, blocks := range checkpoint.Entries[podUID] {
+			for _, block := range blocks {
+				if len(block.NUMAAffinity) <= 0 || block.Size <= 0 {
+					continue
+				}
+				numaNodes := append([]int{}, block.NUMAAffinity...)
+				sort.Ints(numaNodes)
+				size := int64(block.Size) / int64(len(numaNodes))
+				remainder := int64(block.Size) % int64(len(numaNodes))
+				for i, numaNode := range numaNodes {
+					value := size
+					if int64(i) < remainder {
+						value++
+					}
+					if numaResources[numaNode] == nil {
+						numaResources[numaNode] = corev1.ResourceList{}
+					}
+					quantity := numaResources[numaNode][block.Type]
+					quantity.Add(*resource.NewQuantity(value, resource.BinarySI))
+					numaResources[numaNode][block.Type] = quantity
+				}
+			}
+		}
+		if len(numaResources) <= 0 {
+			continue
+		}
+
+		podMemoryAlloc := extension.PodMemoryAlloc{
+			UID:              types.UID(podUID),
+			ManagedByKubelet: true,
+		}
+		for numaNode, resources := range numaResources {
+			podMemoryAlloc.NUMANodeResources = append(podMemoryAlloc.NUMANodeResources, extension.NUMANodeResource{
+				Node:      int32(numaNode),
+				Resources: resources,
+			})
+		}
+		sort.Slice(podMemoryAlloc.NUMANodeResources, func(i, j int) bool {
+			return podMemoryAlloc.NUMANodeResources[i].Node < podMemoryAlloc.NUMANodeResources[j].Node
+		})
+		if podMeta := pods[types.UID(podUID)]; podMeta != nil {
+			podMemoryAlloc.Namespace = podMeta.Pod.Namespace
+			podMemoryAlloc.Name = podMeta.Pod.Name
+		}
+		podAllocs = append(podAllocs, podMemoryAlloc)
+	}
+	sort.Slice(podAllocs, func(i, j int) bool {
+		return string(podAllocs[i].UID) < string(podAllocs[j].UID)
+	})
+	return podAllocs, nil
+}
+
 func (s *nodeTopoInformer) reportNodeTopology() {
 	klog.V(4).Info("start to report node topology")
 	// do not CREATE if reporting is disabled,
@@ -541,10 +842,14 @@ func (s *nodeTopoInformer) reportNodeTopology() {
 	}
 
 	node := s.nodeInformer.GetNode()
-	err = util.RetryOnConflictOrTooManyRequests(func() error {
+	ctx, cancel := context.WithTimeout(context.Background(), nodeTopologyReportTimeout)
+	defer cancel()
+	// the lister can lag behind the other writers of the NRT, so get the latest one from the apiserver after a conflict
+	refreshFromAPIServer := false
+	conflicts, err := retryOnNodeTopologyConflict(ctx, nodeTopologyReportBackoff, func() error {
 		var curNodeResourceTopology *v1alpha1.NodeResourceTopology
 		if isReportEnabled {
-			curNodeResourceTopology, err = s.nodeResourceTopologyLister.Get(node.Name)
+			curNodeResourceTopology, err = s.getNodeTopologyForReport(ctx, node.Name, refreshFromAPIServer)
 			if err != nil {
 				klog.Errorf("failed to get node topology, node %s, err: %v", node.Name, err)
 				return err
@@ -579,8 +884,9 @@ func (s *nodeTopoInformer) reportNodeTopology() {
 		} else {
 			klog.V(4).Infof("need to update node topology, node %s, reason: %s", node.Name, msg)
 		}
-		_, err = s.topologyClient.TopologyV1alpha1().NodeResourceTopologies().Update(context.TODO(), newNodeResourceTopology, metav1.UpdateOptions{})
+		err = s.writeNodeTopology(ctx, newNodeResourceTopology, nodeTopoResult)
 		if err != nil {
+			refreshFromAPIServer = refreshFromAPIServer || errors.IsConflict(err)
 			klog.Errorf("failed to report node topology, node %s, err: %v", node.Name, err)
 			return err
 		}
@@ -588,8 +894,9 @@ func (s *nodeTopoInformer) reportNodeTopology() {
 		klog.V(6).Infof("update NodeResourceTopology successfully, %+v", newNodeResourceTopology)
 		return nil
 	})
+	s.recordNodeTopologyReportConflicts(conflicts, err)
 	if err != nil {
-		klog.Errorf("failed to update NodeResourceTopology, err: %v", err)
+		klog.Errorf("failed to update NodeResourceTopology, conflicts %d, err: %v", conflicts, err)
 	}
 }
 
@@ -611,7 +918,8 @@ func isEqualNRTZones(oldZones, newZones v1alpha1.ZoneList) (bool, string) {
 		}
 	}
 
-	if !util.IsZoneListResourceEqual(oldZones, newZones, string(corev1.ResourceCPU), string(corev1.ResourceMemory)) {
+	if !util.IsZoneListResourceEqual(oldZones, newZones, string(corev1.ResourceCPU), string(corev1.ResourceMemory),
+		string(extension.ResourceDRAM), string(extension.ResourceSlowMemory)) {
 		return false, "resources"
 	}
 
@@ -631,8 +939,13 @@ func isEqualNRTAnnotations(oldAnno, newAnno map[string]string) (bool, string) {
 		extension.AnnotationNodeBECPUSharedPools,
 		extension.AnnotationNodeCPUTopology,
 		extension.AnnotationNodeCPUAllocs,
+		extension.AnnotationNodeMemoryAllocs,
 		extension.AnnotationNodeReservation,
 		extension.AnnotationNodeSystemQOSResource,
+		extension.AnnotationNodeKernelCPUIsolation,
+		extension.AnnotationNodeHousekeepingCPUs,
+		extension.AnnotationNodeSRIOVDevices,
+		extension.AnnotationNodeNICs,
 	}
 	for _, key := range keys {
 		oldValue, oldExist := oldAnno[key]
@@ -743,11 +1056,13 @@ func (s *nodeTopoInformer) calCPUTopology() (*metriccache.NodeCPUInfo, *extensio
 	cpus := make(map[int32]*extension.CPUInfo)
 	cpuTopology := &extension.CPUTopology{}
 	for _, cpu := range nodeCPUInfo.ProcessorInfos {
+		l3 := cpu.L3
 		info := extension.CPUInfo{
 			ID:     cpu.CPUID,
 			Core:   cpu.CoreID,
 			Socket: cpu.SocketID,
 			Node:   cpu.NodeID,
+			L3:     &l3,
 		}
 		cpuTopology.Detail = append(cpuTopology.Detail, info)
 		cpus[cpu.CPUID] = &info
@@ -770,12 +1085,16 @@ func (s *nodeTopoInformer) calTopologyZoneList(nodeCPUInfo *metriccache.NodeCPUI
 	}
 	nodeNum := len(nodeNUMAInfo.NUMAInfos)
 
-	if nodeNumFromCPUInfo := len(nodeCPUInfo.TotalInfo.NodeToCPU); nodeNumFromCPUInfo != nodeNum {
+	// the CPU-less NUMA nodes of the slow memory tier are not shown in the cpu info
+	isHeterogeneousMemoryEnabled := features.DefaultKoordletFeatureGate.Enabled(features.HeterogeneousMemory)
+	if nodeNumFromCPUInfo := len(nodeCPUInfo.TotalInfo.NodeToCPU); nodeNumFromCPUInfo != nodeNum &&
+		(!isHeterogeneousMemoryEnabled || nodeNumFromCPUInfo > nodeNum) {
 		klog.Warningf("failed to align cpu info with NUMA info, err: node number unmatched, cpu %v, NUMA %v",
 			nodeNumFromCPUInfo, nodeNum)
 		return nil, fmt.Errorf("NUMA node number not matched")
 	}
 
+	memoryBandwidth := s.getNodeMemoryBandwidth()
 	zoneResourceList := map[string]corev1.ResourceList{}
 	for i := 0; i < nodeNum; i++ {
 		var cpuQuant resource.Quantity
@@ -797,12 +1116,41 @@ func (s *nodeTopoInformer) calTopologyZoneList(nodeCPUInfo *metriccache.NodeCPUI
 			corev1.ResourceCPU:    cpuQuant,
 			corev1.ResourceMemory: memQuant,
 		}
+		if bandwidth, ok := memoryBandwidth[i]; ok && bandwidth > 0 {
+			zoneResourceList[zoneName][extension.ResourceMemoryBandwidth] = *resource.NewQuantity(bandwidth, resource.DecimalSI)
+		}
+		if isHeterogeneousMemoryEnabled {
+			// the memory of the NUMA nodes with CPUs is DRAM, and the memory of the CPU-less NUMA nodes is slow memory
+			// such as PMEM and CXL memory
+			if len(cpuInfos) > 0 {
+				zoneResourceList[zoneName][extension.ResourceDRAM] = memQuant.DeepCopy()
+			} else {
+				zoneResourceList[zoneName][extension.ResourceSlowMemory] = memQuant.DeepCopy()
+			}
+		}
 	}
 	zoneList := util.ZoneResourceListToZoneList(zoneResourceList)
 
 	return zoneList, nil
 }
 
+// getNodeMemoryBandwidth returns the measured maximum memory bandwidth of each NUMA node from the node annotation.
+func (s *nodeTopoInformer) getNodeMemoryBandwidth() extension.NUMAMemoryBandwidth {
+	if s.nodeInformer == nil {
+		return nil
+	}
+	node := s.nodeInformer.GetNode()
+	if node == nil {
+		return nil
+	}
+	memoryBandwidth, err := extension.GetNodeMemoryBandwidth(node.Annotations)
+	if err != nil {
+		klog.Warningf("failed to parse memory bandwidth of node %s, err: %v", node.Name, err)
+		return nil
+	}
+	return memoryBandwidth
+}
+
 func (s *nodeTopoInformer) updateNodeTopo(newTopo *v1alpha1.NodeResourceTopology) {
 	s.setNodeTopo(newTopo)
 	klog.V(5).Infof("local node topology info updated %v", newTopo)
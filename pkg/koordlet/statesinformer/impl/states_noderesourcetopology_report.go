@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+const (
+	// nodeTopologyFieldManager is the field manager of the NRT fields reported by koordlet with the server-side apply.
+	nodeTopologyFieldManager = "koordlet"
+	// nodeTopologyReportTimeout bounds a report round of the NRT including the retries.
+	nodeTopologyReportTimeout = 30 * time.Second
+)
+
+// nodeTopologyReportBackoff is the backoff of retrying the NRT report on the conflicts and the throttling.
+// The NRT is also written by koord-scheduler and koord-manager, so the retries are jittered to keep the writers from
+// colliding in lockstep.
+var nodeTopologyReportBackoff = wait.Backoff{
+	Steps:    8,
+	Duration: 20 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.5,
+	Cap:      2 * time.Second,
+}
+
+// retryOnNodeTopologyConflict retries fn with the backoff while it fails with a conflict or a throttling, until it
+// succeeds, the retries are used up or the context is done. It returns the number of the conflicts met and the last error.
+func retryOnNodeTopologyConflict(ctx context.Context, backoff wait.Backoff, fn func() error) (int, error) {
+	conflicts := 0
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func() (bool, error) {
+		lastErr = fn()
+		switch {
+		case lastErr == nil:
+			return true, nil
+		case errors.IsConflict(lastErr):
+			conflicts++
+			return false, nil
+		case errors.IsTooManyRequests(lastErr):
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	})
+	if err != nil && lastErr != nil {
+		return conflicts, lastErr
+	}
+	return conflicts, err
+}
+
+// getNodeTopologyForReport gets the current NRT from the lister, or from the apiserver if the lister is known stale.
+func (s *nodeTopoInformer) getNodeTopologyForReport(ctx context.Context, nodeName string, fromAPIServer bool) (*v1alpha1.NodeResourceTopology, error) {
+	if !fromAPIServer {
+		return s.nodeResourceTopologyLister.Get(nodeName)
+	}
+	return s.topologyClient.TopologyV1alpha1().NodeResourceTopologies().Get(ctx, nodeName, metav1.GetOptions{})
+}
+
+// writeNodeTopology writes the reported NRT to the apiserver. With the server-side apply, koordlet only owns the fields
+// it reports, so the writes of the other components neither conflict with it nor get overwritten.
+func (s *nodeTopoInformer) writeNodeTopology(ctx context.Context, nrt *v1alpha1.NodeResourceTopology, nodeTopoResult *nodeTopologyStatus) error {
+	if s.config == nil || !s.config.NodeTopologyApplyEnabled {
+		_, err := s.topologyClient.TopologyV1alpha1().NodeResourceTopologies().Update(ctx, nrt, metav1.UpdateOptions{})
+		return err
+	}
+	data, err := json.Marshal(newNodeTopologyApplyObject(nrt, nodeTopoResult))
+	if err != nil {
+		return err
+	}
+	_, err = s.topologyClient.TopologyV1alpha1().NodeResourceTopologies().Patch(ctx, nrt.Name, types.ApplyPatchType, data,
+		metav1.PatchOptions{FieldManager: nodeTopologyFieldManager, Force: pointer.Bool(true)})
+	return err
+}
+
+// newNodeTopologyApplyObject returns the NRT only containing the fields reported by koordlet for the server-side apply.
+func newNodeTopologyApplyObject(nrt *v1alpha1.NodeResourceTopology, nodeTopoResult *nodeTopologyStatus) *v1alpha1.NodeResourceTopology {
+	annotations := make(map[string]string, len(nodeTopoResult.Annotations))
+	for k, v := range nodeTopoResult.Annotations {
+		annotations[k] = v
+	}
+	return &v1alpha1.NodeResourceTopology{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "NodeResourceTopology",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nrt.Name,
+			Annotations: annotations,
+		},
+		TopologyPolicies: nrt.TopologyPolicies,
+		Zones:            nrt.Zones,
+	}
+}
+
+// recordNodeTopologyReportConflicts records the conflicts of a report round, and counts the consecutive rounds failed
+// due to the conflicts to reveal the persistent writer contention.
+func (s *nodeTopoInformer) recordNodeTopologyReportConflicts(conflicts int, err error) {
+	if conflicts > 0 {
+		metrics.RecordNodeTopologyReportConflicts(conflicts)
+	}
+	if err != nil && errors.IsConflict(err) {
+		s.reportConflictRounds++
+		klog.Warningf("failed to report node topology due to the conflicts for %d rounds", s.reportConflictRounds)
+	} else if err == nil {
+		s.reportConflictRounds = 0
+	}
+	metrics.RecordNodeTopologyReportConflictRounds(s.reportConflictRounds)
+}
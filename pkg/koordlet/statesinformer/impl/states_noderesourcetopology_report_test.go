@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	faketopologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coretesting "k8s.io/client-go/testing"
+)
+
+func Test_retryOnNodeTopologyConflict(t *testing.T) {
+	testBackoff := wait.Backoff{
+		Steps:    3,
+		Duration: time.Millisecond,
+		Factor:   1.0,
+	}
+	conflictErr := errors.NewConflict(schema.GroupResource{Resource: "noderesourcetopologies"}, "test-node", fmt.Errorf("the object has been modified"))
+	throttleErr := errors.NewTooManyRequests("too many requests", 1)
+	tests := []struct {
+		name          string
+		errs          []error
+		wantCalls     int
+		wantConflicts int
+		wantErr       func(error) bool
+	}{
+		{
+			name:          "succeed at once",
+			errs:          []error{nil},
+			wantCalls:     1,
+			wantConflicts: 0,
+		},
+		{
+			name:          "succeed after conflicts and throttling",
+			errs:          []error{conflictErr, throttleErr, nil},
+			wantCalls:     3,
+			wantConflicts: 1,
+		},
+		{
+			name:          "persistent conflicts",
+			errs:          []error{conflictErr, conflictErr, conflictErr},
+			wantCalls:     3,
+			wantConflicts: 3,
+			wantErr:       errors.IsConflict,
+		},
+		{
+			name:          "abort on non-retriable error",
+			errs:          []error{conflictErr, errors.NewNotFound(schema.GroupResource{Resource: "noderesourcetopologies"}, "test-node")},
+			wantCalls:     2,
+			wantConflicts: 1,
+			wantErr:       errors.IsNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			conflicts, err := retryOnNodeTopologyConflict(context.TODO(), testBackoff, func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantConflicts, conflicts)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, tt.wantErr(err), err)
+			}
+		})
+	}
+}
+
+func Test_writeNodeTopology(t *testing.T) {
+	testNRT := &topologyv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				"node.koordinator.sh/cpu-topology": "{}",
+				"scheduler.koordinator.sh/other":   "owned-by-others",
+			},
+		},
+		TopologyPolicies: []string{string(topologyv1alpha1.None)},
+	}
+	testStatus := &nodeTopologyStatus{
+		Annotations: map[string]string{
+			"node.koordinator.sh/cpu-topology": "{}",
+		},
+		TopologyPolicy: topologyv1alpha1.None,
+	}
+
+	t.Run("update", func(t *testing.T) {
+		client := faketopologyclientset.NewSimpleClientset(testNRT.DeepCopy())
+		s := &nodeTopoInformer{
+			config:         NewDefaultConfig(),
+			topologyClient: client,
+		}
+		err := s.writeNodeTopology(context.TODO(), testNRT.DeepCopy(), testStatus)
+		assert.NoError(t, err)
+		assert.Len(t, client.Actions(), 1)
+		assert.Equal(t, "update", client.Actions()[0].GetVerb())
+	})
+
+	t.Run("server-side apply", func(t *testing.T) {
+		client := faketopologyclientset.NewSimpleClientset(testNRT.DeepCopy())
+		var gotPatch coretesting.PatchActionImpl
+		client.PrependReactor("patch", "noderesourcetopologies", func(action coretesting.Action) (bool, runtime.Object, error) {
+			gotPatch = action.(coretesting.PatchActionImpl)
+			return true, testNRT.DeepCopy(), nil
+		})
+		config := NewDefaultConfig()
+		config.NodeTopologyApplyEnabled = true
+		s := &nodeTopoInformer{
+			config:         config,
+			topologyClient: client,
+		}
+		err := s.writeNodeTopology(context.TODO(), testNRT.DeepCopy(), testStatus)
+		assert.NoError(t, err)
+		assert.Equal(t, types.ApplyPatchType, gotPatch.GetPatchType())
+		assert.Equal(t, "test-node", gotPatch.GetName())
+
+		var applied topologyv1alpha1.NodeResourceTopology
+		assert.NoError(t, json.Unmarshal(gotPatch.GetPatch(), &applied))
+		assert.Equal(t, "NodeResourceTopology", applied.Kind)
+		assert.Equal(t, topologyv1alpha1.SchemeGroupVersion.String(), applied.APIVersion)
+		// the annotations of the other writers are not owned by koordlet
+		assert.Equal(t, testStatus.Annotations, applied.Annotations)
+		assert.Equal(t, testNRT.TopologyPolicies, applied.TopologyPolicies)
+	})
+}
+
+func Test_getNodeTopologyForReport(t *testing.T) {
+	staleNRT := &topologyv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node", ResourceVersion: "1"},
+	}
+	latestNRT := &topologyv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node", ResourceVersion: "2"},
+	}
+	s := &nodeTopoInformer{
+		topologyClient: faketopologyclientset.NewSimpleClientset(latestNRT),
+		nodeResourceTopologyLister: &fakeNodeResourceTopologyLister{
+			nodeResourceTopologys: staleNRT,
+		},
+	}
+	got, err := s.getNodeTopologyForReport(context.TODO(), "test-node", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", got.ResourceVersion)
+	got, err = s.getNodeTopologyForReport(context.TODO(), "test-node", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", got.ResourceVersion)
+}
+
+func Test_recordNodeTopologyReportConflicts(t *testing.T) {
+	conflictErr := errors.NewConflict(schema.GroupResource{Resource: "noderesourcetopologies"}, "test-node", fmt.Errorf("the object has been modified"))
+	s := &nodeTopoInformer{}
+	s.recordNodeTopologyReportConflicts(3, conflictErr)
+	s.recordNodeTopologyReportConflicts(3, conflictErr)
+	assert.Equal(t, 2, s.reportConflictRounds)
+	s.recordNodeTopologyReportConflicts(0, fmt.Errorf("expected error"))
+	assert.Equal(t, 2, s.reportConflictRounds)
+	s.recordNodeTopologyReportConflicts(1, nil)
+	assert.Equal(t, 0, s.reportConflictRounds)
+}
@@ -19,6 +19,8 @@ package impl
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -40,12 +42,14 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	fakekoordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -118,8 +122,9 @@ func Test_nodeResourceTopology_NewAndSetup(t *testing.T) {
 				state: &PluginState{
 					metricCache: mock_metriccache.NewMockMetricCache(ctrl),
 					informerPlugins: map[PluginName]informerPlugin{
-						podsInformerName: NewPodsInformer(),
-						nodeInformerName: NewNodeInformer(),
+						podsInformerName:    NewPodsInformer(),
+						nodeInformerName:    NewNodeInformer(),
+						nodeSLOInformerName: NewNodeSLOInformer(),
 					},
 				},
 			},
@@ -306,6 +311,140 @@ func Test_calGuaranteedCpu(t *testing.T) {
 	}
 }
 
+func Test_calKubeletPinnedMemory(t *testing.T) {
+	testCases := []struct {
+		name              string
+		podMap            map[string]*statesinformer.PodMeta
+		checkpointContent string
+		expectedError     bool
+		expectedPodAllocs []extension.PodMemoryAlloc
+	}{
+		{
+			name:              "Restore non-existing checkpoint",
+			checkpointContent: "",
+			expectedError:     true,
+			expectedPodAllocs: nil,
+		},
+		{
+			name:              "Restore checkpoint with invalid JSON",
+			checkpointContent: `{`,
+			expectedError:     true,
+			expectedPodAllocs: nil,
+		},
+		{
+			name: "Restore empty entry",
+			checkpointContent: `{
+				"policyName": "Static",
+				"machineState": {},
+				"entries": {},
+				"checksum": 354655845
+			}`,
+			expectedError:     false,
+			expectedPodAllocs: nil,
+		},
+		{
+			name: "Restore checkpoint with the single and multiple NUMA blocks",
+			checkpointContent: `{
+				"policyName": "Static",
+				"machineState": {},
+				"entries": {
+					"pod": {
+						"container1": [
+							{"numaAffinity": [0], "type": "memory", "size": 1073741824},
+							{"numaAffinity": [0], "type": "hugepages-1Gi", "size": 1073741824}
+						],
+						"container2": [
+							{"numaAffinity": [1, 0], "type": "memory", "size": 2147483648}
+						]
+					}
+				},
+				"checksum": 962272150
+			}`,
+			podMap: map[string]*statesinformer.PodMeta{
+				"pod": {
+					Pod: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-pod",
+							UID:       types.UID("pod"),
+						},
+					},
+				},
+			},
+			expectedError: false,
+			expectedPodAllocs: []extension.PodMemoryAlloc{
+				{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       "pod",
+					NUMANodeResources: []extension.NUMANodeResource{
+						{
+							Node: 0,
+							Resources: corev1.ResourceList{
+								corev1.ResourceMemory:                *resource.NewQuantity(2<<30, resource.BinarySI),
+								corev1.ResourceName("hugepages-1Gi"): *resource.NewQuantity(1<<30, resource.BinarySI),
+							},
+						},
+						{
+							Node: 1,
+							Resources: corev1.ResourceList{
+								corev1.ResourceMemory: *resource.NewQuantity(1<<30, resource.BinarySI),
+							},
+						},
+					},
+					ManagedByKubelet: true,
+				},
+			},
+		},
+		{
+			name: "Filter the pods allocated NUMA resources by koordinator",
+			checkpointContent: `{
+				"policyName": "Static",
+				"machineState": {},
+				"entries": {
+					"LSRPod": {
+						"container1": [
+							{"numaAffinity": [0], "type": "memory", "size": 1073741824}
+						]
+					}
+				},
+				"checksum": 962272150
+			}`,
+			podMap: map[string]*statesinformer.PodMeta{
+				"LSRPod": {
+					Pod: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "test-lsr-pod",
+							UID:       types.UID("LSRPod"),
+							Labels: map[string]string{
+								extension.LabelPodQoS: string(extension.QoSLSR),
+							},
+							Annotations: map[string]string{
+								extension.AnnotationResourceStatus: `{"cpuset": "4-5", "numaNodeResources": [{"node": 0, "resources": {"memory": "1Gi"}}]}`,
+							},
+						},
+					},
+				},
+			},
+			expectedError:     false,
+			expectedPodAllocs: nil,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &nodeTopoInformer{
+				podsInformer: &podsInformer{
+					podMap: tt.podMap,
+				},
+			}
+			podAllocs, err := s.calKubeletPinnedMemory(tt.checkpointContent)
+			assert.Equal(t, tt.expectedError, err != nil)
+			assert.Equal(t, tt.expectedPodAllocs, podAllocs)
+		})
+	}
+}
+
 func Test_reportNodeTopology(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
@@ -330,10 +469,10 @@ func Test_reportNodeTopology(t *testing.T) {
 			{CPUID: 1, CoreID: 0, NodeID: 0, SocketID: 0},
 			{CPUID: 2, CoreID: 1, NodeID: 0, SocketID: 0},
 			{CPUID: 3, CoreID: 1, NodeID: 0, SocketID: 0},
-			{CPUID: 4, CoreID: 2, NodeID: 1, SocketID: 1},
-			{CPUID: 5, CoreID: 2, NodeID: 1, SocketID: 1},
-			{CPUID: 6, CoreID: 3, NodeID: 1, SocketID: 1},
-			{CPUID: 7, CoreID: 3, NodeID: 1, SocketID: 1},
+			{CPUID: 4, CoreID: 2, NodeID: 1, SocketID: 1, L3: 1},
+			{CPUID: 5, CoreID: 2, NodeID: 1, SocketID: 1, L3: 1},
+			{CPUID: 6, CoreID: 3, NodeID: 1, SocketID: 1, L3: 1},
+			{CPUID: 7, CoreID: 3, NodeID: 1, SocketID: 1, L3: 1},
 		},
 		TotalInfo: koordletutil.CPUTotalInfo{
 			NumberCPUs: 8,
@@ -475,7 +614,7 @@ func Test_reportNodeTopology(t *testing.T) {
 
 	expectedCPUSharedPool := `[{"socket":0,"node":0,"cpuset":"0-2"},{"socket":1,"node":1,"cpuset":"6-7"}]`
 	expectedBECPUSharedPool := `[{"socket":0,"node":0,"cpuset":"0-2,3-4"},{"socket":1,"node":1,"cpuset":"6-7"}]`
-	expectedCPUTopology := `{"detail":[{"id":0,"core":0,"socket":0,"node":0},{"id":1,"core":0,"socket":0,"node":0},{"id":2,"core":1,"socket":0,"node":0},{"id":3,"core":1,"socket":0,"node":0},{"id":4,"core":2,"socket":1,"node":1},{"id":5,"core":2,"socket":1,"node":1},{"id":6,"core":3,"socket":1,"node":1},{"id":7,"core":3,"socket":1,"node":1}]}`
+	expectedCPUTopology := `{"detail":[{"id":0,"core":0,"socket":0,"node":0,"l3":0},{"id":1,"core":0,"socket":0,"node":0,"l3":0},{"id":2,"core":1,"socket":0,"node":0,"l3":0},{"id":3,"core":1,"socket":0,"node":0,"l3":0},{"id":4,"core":2,"socket":1,"node":1,"l3":1},{"id":5,"core":2,"socket":1,"node":1,"l3":1},{"id":6,"core":3,"socket":1,"node":1,"l3":1},{"id":7,"core":3,"socket":1,"node":1,"l3":1}]}`
 	expectedCPUBasicInfoBytes, err := json.Marshal(mockNodeCPUInfo.BasicInfo)
 	assert.NoError(t, err)
 
@@ -1207,16 +1346,18 @@ func Test_nodeTopology_isChanged(t *testing.T) {
 func Test_calTopologyZoneList(t *testing.T) {
 	type fields struct {
 		metricCache func(ctrl *gomock.Controller) metriccache.MetricCache
+		node        *corev1.Node
 	}
 	type args struct {
 		nodeCPUInfo *metriccache.NodeCPUInfo
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    topologyv1alpha1.ZoneList
-		wantErr bool
+		name                string
+		fields              fields
+		args                args
+		heterogeneousMemory bool
+		want                topologyv1alpha1.ZoneList
+		wantErr             bool
 	}{
 		{
 			name: "err when numa info not exist",
@@ -1473,14 +1614,212 @@ func Test_calTopologyZoneList(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "calculate numa node with memory bandwidth",
+			fields: fields{
+				metricCache: func(ctrl *gomock.Controller) metriccache.MetricCache {
+					mc := mock_metriccache.NewMockMetricCache(ctrl)
+					mc.EXPECT().Get(metriccache.NodeNUMAInfoKey).Return(&koordletutil.NodeNUMAInfo{
+						NUMAInfos: []koordletutil.NUMAInfo{
+							{
+								NUMANodeID: 0,
+								MemInfo: &koordletutil.MemInfo{
+									MemTotal: 1024000,
+								},
+							},
+						},
+						MemInfoMap: map[int32]*koordletutil.MemInfo{
+							0: {
+								MemTotal: 1024000,
+							},
+						},
+					}, true).Times(1)
+					return mc
+				},
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node",
+						Annotations: map[string]string{
+							extension.AnnotationNodeMemoryBandwidth: `{"0": 20000}`,
+						},
+					},
+				},
+			},
+			args: args{
+				nodeCPUInfo: &metriccache.NodeCPUInfo{
+					TotalInfo: koordletutil.CPUTotalInfo{
+						NodeToCPU: map[int32][]koordletutil.ProcessorInfo{
+							0: {
+								{
+									CPUID:    0,
+									CoreID:   0,
+									SocketID: 0,
+									NodeID:   0,
+								},
+								{
+									CPUID:    1,
+									CoreID:   1,
+									SocketID: 0,
+									NodeID:   0,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: topologyv1alpha1.ZoneList{
+				{
+					Name: "node-0",
+					Type: util.NodeZoneType,
+					Resources: topologyv1alpha1.ResourceInfoList{
+						{
+							Name:        "cpu",
+							Capacity:    *resource.NewQuantity(2, resource.DecimalSI),
+							Allocatable: *resource.NewQuantity(2, resource.DecimalSI),
+							Available:   *resource.NewQuantity(2, resource.DecimalSI),
+						},
+						{
+							Name:        string(extension.ResourceMemoryBandwidth),
+							Capacity:    *resource.NewQuantity(20000, resource.DecimalSI),
+							Allocatable: *resource.NewQuantity(20000, resource.DecimalSI),
+							Available:   *resource.NewQuantity(20000, resource.DecimalSI),
+						},
+						{
+							Name:        "memory",
+							Capacity:    *resource.NewQuantity(1048576000, resource.BinarySI),
+							Allocatable: *resource.NewQuantity(1048576000, resource.BinarySI),
+							Available:   *resource.NewQuantity(1048576000, resource.BinarySI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "calculate memory tiers of numa nodes with and without cpus",
+			fields: fields{
+				metricCache: func(ctrl *gomock.Controller) metriccache.MetricCache {
+					mc := mock_metriccache.NewMockMetricCache(ctrl)
+					mc.EXPECT().Get(metriccache.NodeNUMAInfoKey).Return(&koordletutil.NodeNUMAInfo{
+						NUMAInfos: []koordletutil.NUMAInfo{
+							{
+								NUMANodeID: 0,
+								MemInfo: &koordletutil.MemInfo{
+									MemTotal: 1024000,
+								},
+							},
+							{
+								NUMANodeID: 1,
+								MemInfo: &koordletutil.MemInfo{
+									MemTotal: 4096000,
+								},
+							},
+						},
+						MemInfoMap: map[int32]*koordletutil.MemInfo{
+							0: {
+								MemTotal: 1024000,
+							},
+							1: {
+								MemTotal: 4096000,
+							},
+						},
+					}, true).Times(1)
+					return mc
+				},
+			},
+			args: args{
+				nodeCPUInfo: &metriccache.NodeCPUInfo{
+					TotalInfo: koordletutil.CPUTotalInfo{
+						NodeToCPU: map[int32][]koordletutil.ProcessorInfo{
+							0: {
+								{
+									CPUID:    0,
+									CoreID:   0,
+									SocketID: 0,
+									NodeID:   0,
+								},
+								{
+									CPUID:    1,
+									CoreID:   1,
+									SocketID: 0,
+									NodeID:   0,
+								},
+							},
+						},
+					},
+				},
+			},
+			heterogeneousMemory: true,
+			want: topologyv1alpha1.ZoneList{
+				{
+					Name: "node-0",
+					Type: util.NodeZoneType,
+					Resources: topologyv1alpha1.ResourceInfoList{
+						{
+							Name:        "cpu",
+							Capacity:    *resource.NewQuantity(2, resource.DecimalSI),
+							Allocatable: *resource.NewQuantity(2, resource.DecimalSI),
+							Available:   *resource.NewQuantity(2, resource.DecimalSI),
+						},
+						{
+							Name:        string(extension.ResourceDRAM),
+							Capacity:    *resource.NewQuantity(1048576000, resource.BinarySI),
+							Allocatable: *resource.NewQuantity(1048576000, resource.BinarySI),
+							Available:   *resource.NewQuantity(1048576000, resource.BinarySI),
+						},
+						{
+							Name:        "memory",
+							Capacity:    *resource.NewQuantity(1048576000, resource.BinarySI),
+							Allocatable: *resource.NewQuantity(1048576000, resource.BinarySI),
+							Available:   *resource.NewQuantity(1048576000, resource.BinarySI),
+						},
+					},
+				},
+				{
+					Name: "node-1",
+					Type: util.NodeZoneType,
+					Resources: topologyv1alpha1.ResourceInfoList{
+						{
+							Name:        "cpu",
+							Capacity:    resource.MustParse("0"),
+							Allocatable: resource.MustParse("0"),
+							Available:   resource.MustParse("0"),
+						},
+						{
+							Name:        string(extension.ResourceSlowMemory),
+							Capacity:    *resource.NewQuantity(4194304000, resource.BinarySI),
+							Allocatable: *resource.NewQuantity(4194304000, resource.BinarySI),
+							Available:   *resource.NewQuantity(4194304000, resource.BinarySI),
+						},
+						{
+							Name:        "memory",
+							Capacity:    *resource.NewQuantity(4194304000, resource.BinarySI),
+							Allocatable: *resource.NewQuantity(4194304000, resource.BinarySI),
+							Available:   *resource.NewQuantity(4194304000, resource.BinarySI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
+			enabled := features.DefaultKoordletFeatureGate.Enabled(features.HeterogeneousMemory)
+			testFeatureGates := map[string]bool{string(features.HeterogeneousMemory): tt.heterogeneousMemory}
+			err := features.DefaultMutableKoordletFeatureGate.SetFromMap(testFeatureGates)
+			assert.NoError(t, err)
+			defer func() {
+				testFeatureGates[string(features.HeterogeneousMemory)] = enabled
+				err = features.DefaultMutableKoordletFeatureGate.SetFromMap(testFeatureGates)
+				assert.NoError(t, err)
+			}()
 
 			s := &nodeTopoInformer{
-				metricCache: tt.fields.metricCache(ctrl),
+				metricCache:  tt.fields.metricCache(ctrl),
+				nodeInformer: &nodeInformer{node: tt.fields.node},
 			}
 			got, gotErr := s.calTopologyZoneList(tt.args.nodeCPUInfo)
 			assert.Equal(t, tt.want, got)
@@ -1617,6 +1956,157 @@ func Test_getNodeReserved(t *testing.T) {
 	}
 }
 
+func Test_getNodeHousekeepingCPUs(t *testing.T) {
+	fakeTopo := topology.CPUTopology{
+		NumCPUs:    4,
+		NumSockets: 1,
+		NumCores:   2,
+		CPUDetails: map[int]topology.CPUInfo{
+			0: {CoreID: 0, SocketID: 0, NUMANodeID: 0},
+			1: {CoreID: 1, SocketID: 0, NUMANodeID: 0},
+			2: {CoreID: 0, SocketID: 0, NUMANodeID: 0},
+			3: {CoreID: 1, SocketID: 0, NUMANodeID: 0},
+		},
+	}
+	tests := []struct {
+		name    string
+		anno    map[string]string
+		want    *extension.HousekeepingCPUs
+		wantErr bool
+	}{
+		{
+			name: "node.annotation is nil",
+			want: nil,
+		},
+		{
+			name: "bad housekeeping cpus",
+			anno: map[string]string{
+				extension.AnnotationNodeHousekeepingCPUs: `{"cpuset":"a-b"}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "housekeeping cpus not belonging to the node",
+			anno: map[string]string{
+				extension.AnnotationNodeHousekeepingCPUs: `{"cpuset":"8-9"}`,
+			},
+			want: nil,
+		},
+		{
+			name: "get housekeeping cpus",
+			anno: map[string]string{
+				extension.AnnotationNodeHousekeepingCPUs: `{"cpuset":"0,2,8"}`,
+			},
+			want: &extension.HousekeepingCPUs{CPUSet: "0,2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getNodeHousekeepingCPUs(&fakeTopo, tt.anno)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_getNodeSRIOVDevices(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	assert.Nil(t, getNodeSRIOVDevices(""))
+
+	devicesDir := system.GetSysPCIDevicesDir()
+	helper.WriteFileContents(filepath.Join(devicesDir, "0000:3b:00.0", "numa_node"), "1")
+	helper.WriteFileContents(filepath.Join(devicesDir, "0000:3b:02.0", "numa_node"), "1")
+	err := os.Symlink("../0000:3b:00.0", filepath.Join(devicesDir, "0000:3b:02.0", "physfn"))
+	assert.NoError(t, err)
+
+	want := []extension.SRIOVDevice{
+		{BusID: "0000:3b:00.0", NUMANode: 1, NumVFs: 1},
+	}
+	assert.Equal(t, want, getNodeSRIOVDevices(""))
+
+	deviceCheckpoint := `{"Data":{"PodDeviceEntries":null,"RegisteredDevices":{"intel.com/sriov":["0000:3b:02.0"],"nvidia.com/gpu":["GPU-0"]}},"Checksum":0}`
+	want = []extension.SRIOVDevice{
+		{BusID: "0000:3b:00.0", NUMANode: 1, NumVFs: 1, ResourceNames: []string{"intel.com/sriov"}},
+	}
+	assert.Equal(t, want, getNodeSRIOVDevices(deviceCheckpoint))
+}
+
+func Test_getNodeNICDevices(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	assert.Nil(t, getNodeNICDevices())
+
+	deviceDir := filepath.Join(system.GetSysPCIDevicesDir(), "0000:3b:00.0")
+	interfaceDir := filepath.Join(system.GetSysClassNetDir(), "eth0")
+	helper.WriteFileContents(filepath.Join(deviceDir, "numa_node"), "1")
+	helper.WriteFileContents(filepath.Join(deviceDir, "sriov_totalvfs"), "64")
+	helper.WriteFileContents(filepath.Join(deviceDir, "sriov_numvfs"), "4")
+	helper.WriteFileContents(filepath.Join(interfaceDir, "speed"), "100000")
+	helper.WriteFileContents(filepath.Join(interfaceDir, "operstate"), "up")
+	err := os.Symlink(deviceDir, filepath.Join(interfaceDir, "device"))
+	assert.NoError(t, err)
+
+	want := []extension.NICDevice{
+		{Name: "eth0", BusID: "0000:3b:00.0", NUMANode: 1, SpeedMbps: 100000, OperState: "up", TotalVFs: 64, NumVFs: 4},
+	}
+	assert.Equal(t, want, getNodeNICDevices())
+}
+
+func Test_getNodeSLOReservedCPUs(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodeSLO *slov1alpha1.NodeSLO
+		want    string
+	}{
+		{
+			name: "nodeSLO is nil",
+			want: "",
+		},
+		{
+			name: "reserved cpus not set",
+			nodeSLO: &slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{
+					SystemStrategy: &slov1alpha1.SystemStrategy{},
+				},
+			},
+			want: "",
+		},
+		{
+			name: "bad reserved cpus",
+			nodeSLO: &slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{
+					SystemStrategy: &slov1alpha1.SystemStrategy{
+						ReservedCPUs: pointer.String("a-b"),
+					},
+				},
+			},
+			want: "",
+		},
+		{
+			name: "get reserved cpus",
+			nodeSLO: &slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{
+					SystemStrategy: &slov1alpha1.SystemStrategy{
+						ReservedCPUs: pointer.String("0-1"),
+					},
+				},
+			},
+			want: "0-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &nodeTopoInformer{
+				nodeSLOInformer: &nodeSLOInformer{
+					nodeSLO: tt.nodeSLO,
+				},
+			}
+			assert.Equal(t, tt.want, s.getNodeSLOReservedCPUs())
+		})
+	}
+}
+
 func Test_removeSystemQOSCPUs(t *testing.T) {
 	originCPUSharePool := []extension.CPUSharedPool{
 		{
@@ -17,20 +17,22 @@ limitations under the License.
 package impl
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
-	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
@@ -59,26 +61,32 @@ func (s *nodeSLOInformer) GetNodeSLO() *slov1alpha1.NodeSLO {
 }
 
 func (s *nodeSLOInformer) Setup(ctx *PluginOption, state *PluginState) {
-	s.nodeSLOInformer = newNodeSLOInformer(ctx.KoordClient, ctx.NodeName)
+	s.nodeSLOInformer = newNodeSLOInformer(ctx.DynamicClient, ctx.NodeName)
 	s.nodeSLOInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			nodeSLO, ok := obj.(*slov1alpha1.NodeSLO)
-			if ok {
-				s.updateNodeSLOSpec(nodeSLO)
-				klog.Infof("create NodeSLO %v", util.DumpJSON(nodeSLO))
-			} else {
-				klog.Errorf("node slo informer add func parse nodeSLO failed")
+			nodeSLO, err := convertNodeSLO(obj)
+			if err != nil {
+				klog.Errorf("node slo informer add func parse nodeSLO failed, keep the last one, err: %v", err)
+				return
 			}
+			s.updateNodeSLOSpec(nodeSLO)
+			klog.Infof("create NodeSLO %v", util.DumpJSON(nodeSLO))
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			oldNodeSLO, oldOK := oldObj.(*slov1alpha1.NodeSLO)
-			newNodeSLO, newOK := newObj.(*slov1alpha1.NodeSLO)
+			oldUnstructured, oldOK := oldObj.(*unstructured.Unstructured)
+			newUnstructured, newOK := newObj.(*unstructured.Unstructured)
 			if !oldOK || !newOK {
-				klog.Errorf("unable to convert object to *slov1alpha1.NodeSLO, old %T, new %T", oldObj, newObj)
+				klog.Errorf("unable to convert object to *unstructured.Unstructured, old %T, new %T", oldObj, newObj)
+				return
+			}
+			if reflect.DeepEqual(oldUnstructured.Object["spec"], newUnstructured.Object["spec"]) &&
+				reflect.DeepEqual(oldUnstructured.GetAnnotations(), newUnstructured.GetAnnotations()) {
+				klog.V(5).Infof("find NodeSLO spec %s has not changed", newUnstructured.GetName())
 				return
 			}
-			if reflect.DeepEqual(oldNodeSLO.Spec, newNodeSLO.Spec) {
-				klog.V(5).Infof("find NodeSLO spec %s has not changed", newNodeSLO.Name)
+			newNodeSLO, err := convertNodeSLO(newUnstructured)
+			if err != nil {
+				klog.Errorf("node slo informer update func parse nodeSLO failed, keep the last one, err: %v", err)
 				return
 			}
 			klog.Infof("update NodeSLO spec %v", util.DumpJSON(newNodeSLO.Spec))
@@ -108,6 +116,51 @@ func (s *nodeSLOInformer) updateNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
 	s.callbackRunner.SendCallback(statesinformer.RegisterTypeNodeSLOSpec)
 }
 
+// convertNodeSLO converts the NodeSLO in the informer cache into the current schema version. The cache keeps the
+// unstructured objects, so the spec written by another slo-controller version, e.g. the fields unknown to this
+// version during the rolling upgrade, is converted without fetching the object again.
+func convertNodeSLO(obj interface{}) (*slov1alpha1.NodeSLO, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+	// decode the fields except the spec, which is converted according to its schema version
+	object := make(map[string]interface{}, len(u.Object))
+	for k, v := range u.Object {
+		if k != "spec" {
+			object[k] = v
+		}
+	}
+	nodeSLO := &slov1alpha1.NodeSLO{}
+	if err := apiruntime.DefaultUnstructuredConverter.FromUnstructured(object, nodeSLO); err != nil {
+		return nil, fmt.Errorf("failed to decode NodeSLO %s, err: %w", u.GetName(), err)
+	}
+	version, err := sloconfig.GetNodeSLOSchemaVersion(nodeSLO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version of NodeSLO %s, err: %w", u.GetName(), err)
+	}
+	rawSpec, err := json.Marshal(u.Object["spec"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec of NodeSLO %s, err: %w", u.GetName(), err)
+	}
+
+	spec, warnings, err := sloconfig.ConvertNodeSLOSpec(rawSpec, version)
+	for _, w := range warnings {
+		klog.Warningf("NodeSLO %s of schema version %d: %s", u.GetName(), version, w)
+	}
+	if len(warnings) > 0 {
+		metrics.RecordNodeSLOSchemaWarnings(version, len(warnings))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert NodeSLO %s from schema version %d, err: %w", u.GetName(), version, err)
+	}
+	nodeSLO.Spec = *spec
+	if version != sloconfig.CurrentNodeSLOSchemaVersion {
+		klog.V(4).Infof("NodeSLO %s converted from schema version %d to %d", u.GetName(), version, sloconfig.CurrentNodeSLOSchemaVersion)
+	}
+	return nodeSLO, nil
+}
+
 func (s *nodeSLOInformer) setNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
 	s.nodeSLORWMutex.Lock()
 	defer s.nodeSLORWMutex.Unlock()
@@ -171,25 +224,19 @@ func (s *nodeSLOInformer) mergeNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
 
 }
 
-func newNodeSLOInformer(client koordclientset.Interface, nodeName string) cache.SharedIndexInformer {
+func newNodeSLOInformer(client dynamic.Interface, nodeName string) cache.SharedIndexInformer {
 	tweakListOptionFunc := func(opt *metav1.ListOptions) {
 		opt.FieldSelector = "metadata.name=" + nodeName
 	}
-	return cache.NewSharedIndexInformer(
-		&cache.ListWatch{
-			ListFunc: func(options metav1.ListOptions) (apiruntime.Object, error) {
-				tweakListOptionFunc(&options)
-				return client.SloV1alpha1().NodeSLOs().List(context.TODO(), options)
-			},
-			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				tweakListOptionFunc(&options)
-				return client.SloV1alpha1().NodeSLOs().Watch(context.TODO(), options)
-			},
-		},
-		&slov1alpha1.NodeSLO{},
+	// use the unstructured objects to keep the fields unknown to this version for the schema conversion
+	return dynamicinformer.NewFilteredDynamicInformer(
+		client,
+		slov1alpha1.GroupVersion.WithResource("nodeslos"),
+		metav1.NamespaceAll,
 		time.Hour*12,
 		cache.Indexers{},
-	)
+		tweakListOptionFunc,
+	).Informer()
 }
 
 // mergeSLOSpecResourceUsedThresholdWithBE merges the nodeSLO ResourceUsedThresholdWithBE with default configs
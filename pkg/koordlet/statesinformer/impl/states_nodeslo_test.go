@@ -18,8 +18,13 @@ package impl
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -254,6 +259,141 @@ func Test_updateNodeSLOSpec(t *testing.T) {
 	assert.Equal(t, testingUpdatedNodeSLO, r.nodeSLO)
 }
 
+func newFakeNodeSLODynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		slov1alpha1.GroupVersion.WithResource("nodeslos"): "NodeSLOList",
+	}, objects...)
+}
+
+func newTestingUnstructuredNodeSLO(annotations map[string]interface{}, spec map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name": "test-node",
+	}
+	if annotations != nil {
+		metadata["annotations"] = annotations
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": slov1alpha1.GroupVersion.String(),
+		"kind":       "NodeSLO",
+		"metadata":   metadata,
+		"spec":       spec,
+	}}
+}
+
+func Test_convertNodeSLO(t *testing.T) {
+	currentVersion := map[string]interface{}{
+		slov1alpha1.AnnotationNodeSLOSchemaVersion: "2",
+	}
+	tests := []struct {
+		name    string
+		obj     interface{}
+		want    *slov1alpha1.NodeSLOSpec
+		wantErr bool
+	}{
+		{
+			name: "current version",
+			obj: newTestingUnstructuredNodeSLO(currentVersion, map[string]interface{}{
+				"cpuBurstStrategy": map[string]interface{}{"cpuBurstPercent": int64(100)},
+			}),
+			want: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{CPUBurstPercent: pointer.Int64(100)},
+				},
+			},
+		},
+		{
+			name: "newer version with unknown fields",
+			obj: newTestingUnstructuredNodeSLO(map[string]interface{}{
+				slov1alpha1.AnnotationNodeSLOSchemaVersion: "3",
+			}, map[string]interface{}{
+				"cpuBurstStrategy": map[string]interface{}{"cpuBurstPercent": int64(100), "newField": true},
+				"newStrategy":      map[string]interface{}{"enable": true},
+			}),
+			want: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{CPUBurstPercent: pointer.Int64(100)},
+				},
+			},
+		},
+		{
+			name: "first version without annotation converted",
+			obj: newTestingUnstructuredNodeSLO(nil, map[string]interface{}{
+				"resourceUsedThresholdWithBE": map[string]interface{}{"cpuSuppressPolicy": "unknown"},
+			}),
+			want: &slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					CPUSuppressPolicy: slov1alpha1.CPUSetPolicy,
+				},
+			},
+		},
+		{
+			name: "no spec",
+			obj:  newTestingUnstructuredNodeSLO(currentVersion, nil),
+			want: &slov1alpha1.NodeSLOSpec{},
+		},
+		{
+			name: "invalid schema version",
+			obj: newTestingUnstructuredNodeSLO(map[string]interface{}{
+				slov1alpha1.AnnotationNodeSLOSchemaVersion: "v2",
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name: "invalid spec",
+			obj: newTestingUnstructuredNodeSLO(currentVersion, map[string]interface{}{
+				"cpuBurstStrategy": map[string]interface{}{"cpuBurstPercent": "100"},
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "unexpected type",
+			obj:     &slov1alpha1.NodeSLO{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertNodeSLO(tt.obj)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, "test-node", got.Name)
+			assert.Equal(t, tt.want, &got.Spec)
+		})
+	}
+}
+
+func Test_nodeSLOInformer_convertFromCache(t *testing.T) {
+	// the NodeSLO written by the older slo-controller without the schema version, and a field unknown to this version
+	nodeSLO := newTestingUnstructuredNodeSLO(nil, map[string]interface{}{
+		"resourceUsedThresholdWithBE": map[string]interface{}{
+			"enable":                      true,
+			"cpuSuppressThresholdPercent": int64(55),
+			"cpuSuppressPolicy":           "cfsquota",
+			"newField":                    int64(1),
+		},
+	})
+	s := NewNodeSLOInformer()
+	s.Setup(&PluginOption{
+		DynamicClient: newFakeNodeSLODynamicClient(nodeSLO),
+		NodeName:      "test-node",
+	}, &PluginState{
+		callbackRunner: NewCallbackRunner(),
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	s.Start(stopCh)
+
+	assert.Eventually(t, func() bool {
+		return s.HasSynced() && s.GetNodeSLO() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	got := s.GetNodeSLO()
+	assert.Equal(t, pointer.Bool(true), got.Spec.ResourceUsedThresholdWithBE.Enable)
+	assert.Equal(t, pointer.Int64(55), got.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+	assert.Equal(t, slov1alpha1.CPUSetPolicy, got.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy)
+}
+
 func Test_mergeSLOSpecResourceUsedThresholdWithBE(t *testing.T) {
 	testingDefaultSpec := sloconfig.DefaultResourceThresholdStrategy()
 	testingNewSpec := &slov1alpha1.ResourceThresholdStrategy{
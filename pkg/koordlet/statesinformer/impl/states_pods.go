@@ -251,6 +251,7 @@ func genPodMetaKey(podMeta *statesinformer.PodMeta) string {
 func resetPodMetrics() {
 	metrics.ResetContainerResourceRequests()
 	metrics.ResetContainerResourceLimits()
+	metrics.ResetPodSandboxOverhead()
 }
 
 func recordPodResourceMetrics(podMeta *statesinformer.PodMeta) {
@@ -277,6 +278,14 @@ func recordPodResourceMetrics(podMeta *statesinformer.PodMeta) {
 		recordContainerResourceMetrics(c, containerStatus, pod)
 	}
 
+	// record the sandbox overhead, e.g. the VM of the kata pod, which is not accounted in the container requests
+	if q, ok := pod.Spec.Overhead[corev1.ResourceCPU]; ok {
+		metrics.RecordPodSandboxOverhead(string(corev1.ResourceCPU), metrics.UnitCore, pod, float64(q.MilliValue())/1000)
+	}
+	if q, ok := pod.Spec.Overhead[corev1.ResourceMemory]; ok {
+		metrics.RecordPodSandboxOverhead(string(corev1.ResourceMemory), metrics.UnitByte, pod, float64(q.Value()))
+	}
+
 	klog.V(6).Infof("record pod prometheus metrics successfully, pod %s/%s", pod.Namespace, pod.Name)
 }
 
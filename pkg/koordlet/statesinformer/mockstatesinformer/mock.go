@@ -27,6 +27,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	v1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	v1alpha10 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	pleg "github.com/koordinator-sh/koordinator/pkg/koordlet/pleg"
 	statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	impl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
 	v1 "k8s.io/api/core/v1"
@@ -151,6 +152,20 @@ func (mr *MockStatesInformerMockRecorder) RegisterCallbacks(objType, name, descr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCallbacks", reflect.TypeOf((*MockStatesInformer)(nil).RegisterCallbacks), objType, name, description, callbackFn)
 }
 
+// RegisterPodLifeCycleHandler mocks base method.
+func (m *MockStatesInformer) RegisterPodLifeCycleHandler(handler pleg.PodLifeCycleHandler) pleg.HandlerID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPodLifeCycleHandler", handler)
+	ret0, _ := ret[0].(pleg.HandlerID)
+	return ret0
+}
+
+// RegisterPodLifeCycleHandler indicates an expected call of RegisterPodLifeCycleHandler.
+func (mr *MockStatesInformerMockRecorder) RegisterPodLifeCycleHandler(handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPodLifeCycleHandler", reflect.TypeOf((*MockStatesInformer)(nil).RegisterPodLifeCycleHandler), handler)
+}
+
 // Run mocks base method.
 func (m *MockStatesInformer) Run(stopCh <-chan struct{}) error {
 	m.ctrl.T.Helper()
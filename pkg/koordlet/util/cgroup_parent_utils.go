@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -40,7 +41,12 @@ func GetPodCgroupParentDir(pod *corev1.Pod) string {
 	)
 }
 
+// GetKubeQoSByCgroupParent gets the kube QoS class of the cgroup parent according to the cgroup layout.
+// It returns empty if the cgroup layout has no QoS-level cgroups, e.g. the flat layout.
 func GetKubeQoSByCgroupParent(cgroupDir string) corev1.PodQOSClass {
+	if system.CgroupPathFormatter.KubeQOSParser != nil {
+		return system.CgroupPathFormatter.KubeQOSParser(cgroupDir)
+	}
 	if strings.Contains(cgroupDir, "besteffort") {
 		return corev1.PodQOSBestEffort
 	} else if strings.Contains(cgroupDir, "burstable") {
@@ -49,6 +55,24 @@ func GetKubeQoSByCgroupParent(cgroupDir string) corev1.PodQOSClass {
 	return corev1.PodQOSGuaranteed
 }
 
+// GetPodKubeQoS gets the kube QoS class of the pod by the cgroup parent. If the cgroup layout has no QoS-level cgroups,
+// it falls back to the kube QoS class of the pod if known, otherwise to the one implied by the koordinator QoS class.
+func GetPodKubeQoS(cgroupDir string, podKubeQOS corev1.PodQOSClass, podQOS apiext.QoSClass) corev1.PodQOSClass {
+	if kubeQOS := GetKubeQoSByCgroupParent(cgroupDir); len(kubeQOS) > 0 {
+		return kubeQOS
+	}
+	if len(podKubeQOS) > 0 {
+		return podKubeQOS
+	}
+	switch podQOS {
+	case apiext.QoSBE:
+		return corev1.PodQOSBestEffort
+	case apiext.QoSLSE, apiext.QoSLSR:
+		return corev1.PodQOSGuaranteed
+	}
+	return corev1.PodQOSBurstable
+}
+
 // @return like kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/
 // /sys/fs/cgroup/blkio/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice
 func GetPodCgroupBlkIOAbsolutePath(podParentDir string) string {
@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -144,6 +145,56 @@ func Test_GetKubeQoSByCgroupParent(t *testing.T) {
 	}
 }
 
+func Test_GetPodKubeQoS(t *testing.T) {
+	system.Conf = system.NewDsModeConfig()
+	system.Conf.CgroupQOSLayout = string(system.CgroupQOSLayoutFlat)
+	system.SetupCgroupPathFormatter(system.Cgroupfs)
+	defer func() {
+		system.Conf = system.NewDsModeConfig()
+		system.SetupCgroupPathFormatter(system.Cgroupfs)
+	}()
+
+	testCases := []struct {
+		name       string
+		path       string
+		podKubeQOS corev1.PodQOSClass
+		podQOS     apiext.QoSClass
+		want       corev1.PodQOSClass
+	}{
+		{
+			name:       "use the pod kube qos in the flat layout",
+			path:       "kubepods/poduid1",
+			podKubeQOS: corev1.PodQOSBurstable,
+			podQOS:     apiext.QoSLSR,
+			want:       corev1.PodQOSBurstable,
+		},
+		{
+			name:   "use the koord qos of BE in the flat layout",
+			path:   "kubepods/poduid1",
+			podQOS: apiext.QoSBE,
+			want:   corev1.PodQOSBestEffort,
+		},
+		{
+			name:   "use the koord qos of LSR in the flat layout",
+			path:   "kubepods/poduid1",
+			podQOS: apiext.QoSLSR,
+			want:   corev1.PodQOSGuaranteed,
+		},
+		{
+			name:   "use the koord qos of LS in the flat layout",
+			path:   "kubepods/poduid1",
+			podQOS: apiext.QoSLS,
+			want:   corev1.PodQOSBurstable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, GetPodKubeQoS(tc.path, tc.podKubeQOS, tc.podQOS))
+		})
+	}
+}
+
 func TestGetContainerCgroupParentDir_SystemdDriver(t *testing.T) {
 	system.SetupCgroupPathFormatter(system.Systemd)
 	defer system.SetupCgroupPathFormatter(system.Systemd)
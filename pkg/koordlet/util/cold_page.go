@@ -17,28 +17,59 @@ package util
 
 import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
-func GetNodeMemUsageWithHotPage(coldPageUsage uint64) (uint64, error) {
+// GetNodeMemUsageWithHotPage returns the node memory usage excluding the reclaimable cold pages.
+// The cold shmem/tmpfs pages are kept in the usage since they can not be reclaimed.
+func GetNodeMemUsageWithHotPage(coldPageUsage, coldSwapBackedUsage uint64) (uint64, error) {
 	memInfo, err := GetMemInfo()
 	if err != nil {
 		return 0, err
 	}
-	return memInfo.MemTotal*1024 - memInfo.MemFree*1024 - coldPageUsage, nil
+	reclaimableColdPageUsage := getReclaimableColdPageUsage(coldPageUsage, coldSwapBackedUsage, memInfo.Shmem*1024)
+	return memInfo.MemTotal*1024 - memInfo.MemFree*1024 - reclaimableColdPageUsage, nil
 }
 
-func GetPodMemUsageWithHotPage(cgroupReader resourceexecutor.CgroupReader, parentDir string, coldPageUsage uint64) (uint64, error) {
+func GetPodMemUsageWithHotPage(cgroupReader resourceexecutor.CgroupReader, parentDir string, coldPageUsage, coldSwapBackedUsage uint64) (uint64, error) {
 	memStat, err := cgroupReader.ReadMemoryStat(parentDir)
 	if err != nil {
 		return 0, err
 	}
-	return uint64(memStat.Usage()) + uint64(memStat.ActiveFile+memStat.InactiveFile) - coldPageUsage, nil
+	return getMemUsageWithHotPage(memStat, coldPageUsage, coldSwapBackedUsage), nil
 }
 
-func GetContainerMemUsageWithHotPage(cgroupReader resourceexecutor.CgroupReader, parentDir string, coldPageUsage uint64) (uint64, error) {
+func GetContainerMemUsageWithHotPage(cgroupReader resourceexecutor.CgroupReader, parentDir string, coldPageUsage, coldSwapBackedUsage uint64) (uint64, error) {
 	memStat, err := cgroupReader.ReadMemoryStat(parentDir)
 	if err != nil {
 		return 0, err
 	}
-	return uint64(memStat.Usage()) + uint64(memStat.ActiveFile+memStat.InactiveFile) - coldPageUsage, nil
+	return getMemUsageWithHotPage(memStat, coldPageUsage, coldSwapBackedUsage), nil
+}
+
+// getMemUsageWithHotPage excludes the reclaimable cold pages from the memory usage including the page cache.
+// The cold pages rolled up from the child cgroups can be slightly larger than the usage sampled at a different time,
+// so the result is limited to non-negative.
+func getMemUsageWithHotPage(memStat *sysutil.MemoryStatRaw, coldPageUsage, coldSwapBackedUsage uint64) uint64 {
+	usage := uint64(memStat.Usage()) + uint64(memStat.ActiveFile+memStat.InactiveFile)
+	reclaimableColdPageUsage := getReclaimableColdPageUsage(coldPageUsage, coldSwapBackedUsage, uint64(memStat.Shmem))
+	if usage < reclaimableColdPageUsage {
+		return 0
+	}
+	return usage - reclaimableColdPageUsage
+}
+
+// getReclaimableColdPageUsage excludes the cold shmem/tmpfs pages from the cold pages, since they are unreclaimable
+// (e.g. the emptyDir of medium Memory) and a tmpfs-heavy pod should not be judged as reclaimable.
+// Kidled reports the shmem pages together with the anonymous pages as swap-backed, so the cold shmem is conservatively
+// estimated as the smaller one of the shmem size and the cold swap-backed pages.
+func getReclaimableColdPageUsage(coldPageUsage, coldSwapBackedUsage, shmemUsage uint64) uint64 {
+	coldShmemUsage := shmemUsage
+	if coldSwapBackedUsage < coldShmemUsage {
+		coldShmemUsage = coldSwapBackedUsage
+	}
+	if coldPageUsage < coldShmemUsage {
+		return 0
+	}
+	return coldPageUsage - coldShmemUsage
 }
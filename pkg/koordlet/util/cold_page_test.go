@@ -106,7 +106,7 @@ DirectMap1G:    261095424 kB`
 			if tt.fields.SetSysUtil != nil {
 				tt.fields.SetSysUtil(helper)
 			}
-			got, err := GetNodeMemUsageWithHotPage(100)
+			got, err := GetNodeMemUsageWithHotPage(100, 0)
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Equal(t, tt.want, got)
 		})
@@ -119,10 +119,12 @@ func Test_GetPodMemUsageWithHotPage(t *testing.T) {
 		SetSysUtil func(helper *system.FileTestUtil)
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		want    uint64
-		wantErr bool
+		name                string
+		fields              fields
+		coldPageUsage       uint64
+		coldSwapBackedUsage uint64
+		want                uint64
+		wantErr             bool
 	}{
 		{
 			name: "read legal podMemUsageWithHotPage",
@@ -139,8 +141,9 @@ total_unevictable 0
 `)
 				},
 			},
-			want:    uint64(209715200) - 100,
-			wantErr: false,
+			coldPageUsage: 100,
+			want:          uint64(209715200) - 100,
+			wantErr:       false,
 		},
 		{
 			name: "read illegal podMemUsageWithHotPage",
@@ -157,8 +160,92 @@ total_unevictable 0
 `)
 				},
 			},
-			want:    uint64(0),
-			wantErr: true,
+			coldPageUsage: 100,
+			want:          uint64(0),
+			wantErr:       true,
+		},
+		{
+			name: "read cgroup v2 podMemUsageWithHotPage",
+			fields: fields{
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					helper.SetCgroupsV2(true)
+					helper.WriteCgroupFileContents(testPodParentDir, system.MemoryStatV2, `
+anon 104857600
+file 104857600
+inactive_anon 104857600
+active_anon 0
+inactive_file 104857600
+active_file 0
+unevictable 0
+`)
+				},
+			},
+			coldPageUsage: 100,
+			want:          uint64(209715200) - 100,
+			wantErr:       false,
+		},
+		{
+			name: "cold pages rolled up larger than usage",
+			fields: fields{
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					helper.SetCgroupsV2(true)
+					helper.WriteCgroupFileContents(testPodParentDir, system.MemoryStatV2, `
+anon 104857600
+file 104857600
+inactive_anon 104857600
+active_anon 0
+inactive_file 104857600
+active_file 0
+unevictable 0
+`)
+				},
+			},
+			coldPageUsage: 209715200 + 4096,
+			want:          uint64(0),
+			wantErr:       false,
+		},
+		{
+			name: "cold tmpfs pages are not reclaimable",
+			fields: fields{
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					helper.SetCgroupsV2(true)
+					helper.WriteCgroupFileContents(testPodParentDir, system.MemoryStatV2, `
+anon 0
+file 209715200
+inactive_anon 104857600
+active_anon 0
+inactive_file 104857600
+active_file 0
+unevictable 0
+shmem 104857600
+`)
+				},
+			},
+			coldPageUsage:       104857600 + 100,
+			coldSwapBackedUsage: 104857600,
+			want:                uint64(209715200) - 100,
+			wantErr:             false,
+		},
+		{
+			name: "cold swap-backed pages more than tmpfs pages",
+			fields: fields{
+				SetSysUtil: func(helper *system.FileTestUtil) {
+					helper.WriteCgroupFileContents(testPodParentDir, system.MemoryStat, `
+total_cache 157286400
+total_rss 52428800
+total_inactive_anon 104857600
+total_active_anon 0
+total_inactive_file 104857600
+total_active_file 0
+total_unevictable 0
+total_shmem 52428800
+`)
+				},
+			},
+			coldPageUsage:       104857600 + 100,
+			coldSwapBackedUsage: 104857600,
+			want:                uint64(209715200) - 52428800 - 100,
+			wantErr:             false,
 		},
 	}
 	for _, tt := range tests {
@@ -169,13 +256,58 @@ total_unevictable 0
 				tt.fields.SetSysUtil(helper)
 			}
 			cgroupReader := resourceexecutor.NewCgroupReader()
-			got, err := GetPodMemUsageWithHotPage(cgroupReader, testPodParentDir, 100)
+			got, err := GetPodMemUsageWithHotPage(cgroupReader, testPodParentDir, tt.coldPageUsage, tt.coldSwapBackedUsage)
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func Test_getReclaimableColdPageUsage(t *testing.T) {
+	tests := []struct {
+		name                string
+		coldPageUsage       uint64
+		coldSwapBackedUsage uint64
+		shmemUsage          uint64
+		want                uint64
+	}{
+		{
+			name:                "no shmem",
+			coldPageUsage:       4096,
+			coldSwapBackedUsage: 1024,
+			shmemUsage:          0,
+			want:                4096,
+		},
+		{
+			name:                "shmem less than cold swap-backed pages",
+			coldPageUsage:       4096,
+			coldSwapBackedUsage: 2048,
+			shmemUsage:          1024,
+			want:                3072,
+		},
+		{
+			name:                "shmem more than cold swap-backed pages",
+			coldPageUsage:       4096,
+			coldSwapBackedUsage: 2048,
+			shmemUsage:          8192,
+			want:                2048,
+		},
+		{
+			name:                "cold swap-backed pages sampled more than cold pages",
+			coldPageUsage:       1024,
+			coldSwapBackedUsage: 2048,
+			shmemUsage:          8192,
+			want:                0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getReclaimableColdPageUsage(tt.coldPageUsage, tt.coldSwapBackedUsage, tt.shmemUsage)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_GetContainerMemUsageWithHotPage(t *testing.T) {
 	testContainerParentDir := "/kubepods.slice/kubepods-podxxxxxxxx.slice/cri-containerd-123abc.scope"
 	type fields struct {
@@ -232,7 +364,7 @@ total_unevictable 0
 				tt.fields.SetSysUtil(helper)
 			}
 			cgroupReader := resourceexecutor.NewCgroupReader()
-			got, err := GetContainerMemUsageWithHotPage(cgroupReader, testContainerParentDir, 100)
+			got, err := GetContainerMemUsageWithHotPage(cgroupReader, testContainerParentDir, 100, 0)
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Equal(t, tt.want, got)
 		})
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,7 +31,9 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 const cpuCmdTimeout = 5 * time.Second // maybe run slowly on some platforms
@@ -71,6 +74,8 @@ type LocalCPUInfo struct {
 	ProcessorInfos []ProcessorInfo `json:"processorInfos,omitempty"`
 	// TotalInfo stores the numbers of cpu processors, cores, sockets and nodes
 	TotalInfo CPUTotalInfo `json:"totalInfo,omitempty"`
+	// KernelCPUIsolation describes the isolated CPUs and the CPU vulnerability mitigations of the kernel
+	KernelCPUIsolation extension.KernelCPUIsolation `json:"kernelCPUIsolation,omitempty"`
 }
 
 // getCPUModel gets the Model name of the CPU.
@@ -173,6 +178,85 @@ func getCPUBasicInfo() (*extension.CPUBasicInfo, error) {
 	return cpuBasicInfo, nil
 }
 
+// parseKernelCmdlineCPUList parses the cpu-list of the kernel cmdline flag, e.g. `isolcpus=domain,managed_irq,2-5,8`.
+// The non-numeric flags before the cpu-list are ignored.
+func parseKernelCmdlineCPUList(value string) (cpuset.CPUSet, error) {
+	var cpuList []string
+	for _, item := range strings.Split(value, ",") {
+		if len(item) > 0 && item[0] >= '0' && item[0] <= '9' {
+			cpuList = append(cpuList, item)
+		}
+	}
+	return cpuset.Parse(strings.Join(cpuList, ","))
+}
+
+// getKernelCPUIsolationFlags gets the cpu-list of the isolcpus, nohz_full and rcu_nocbs flags from the kernel cmdline.
+func getKernelCPUIsolationFlags(isolation *extension.KernelCPUIsolation) error {
+	cmdlinePath := system.GetKernelCmdlinePath()
+	out, err := os.ReadFile(cmdlinePath)
+	if err != nil {
+		return fmt.Errorf("read %s failed, err: %w", cmdlinePath, err)
+	}
+	for _, arg := range strings.Fields(string(out)) {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		var target *string
+		switch kv[0] {
+		case "isolcpus":
+			target = &isolation.IsolCPUs
+		case "nohz_full":
+			target = &isolation.NohzFullCPUs
+		case "rcu_nocbs":
+			target = &isolation.RCUNoCBsCPUs
+		default:
+			continue
+		}
+		cpus, err := parseKernelCmdlineCPUList(kv[1])
+		if err != nil {
+			klog.V(4).Infof("failed to parse kernel cmdline flag %s, err: %v", arg, err)
+			continue
+		}
+		*target = cpus.String()
+	}
+	return nil
+}
+
+// getCPUVulnerabilities gets the mitigation states of the CPU vulnerabilities, e.g. `spectre_v2: Mitigation: Retpolines`.
+func getCPUVulnerabilities() (map[string]string, error) {
+	vulnerabilitiesDir := system.GetSysCPUVulnerabilitiesDir()
+	entries, err := os.ReadDir(vulnerabilitiesDir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir %s failed, err: %w", vulnerabilitiesDir, err)
+	}
+	vulnerabilities := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		out, err := os.ReadFile(filepath.Join(vulnerabilitiesDir, entry.Name()))
+		if err != nil {
+			klog.V(5).Infof("read cpu vulnerability %s failed, err: %v", entry.Name(), err)
+			continue
+		}
+		vulnerabilities[entry.Name()] = strings.TrimSpace(string(out))
+	}
+	return vulnerabilities, nil
+}
+
+func getKernelCPUIsolation() *extension.KernelCPUIsolation {
+	isolation := &extension.KernelCPUIsolation{}
+	if err := getKernelCPUIsolationFlags(isolation); err != nil {
+		klog.V(4).Infof("get kernel cpu isolation flags error: %v", err)
+	}
+	var err error
+	if isolation.Vulnerabilities, err = getCPUVulnerabilities(); err != nil {
+		klog.V(5).Infof("get cpu vulnerabilities error: %v", err)
+	}
+	return isolation
+}
+
 func lsCPU(option string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), cpuCmdTimeout)
 	defer cancel()
@@ -185,7 +269,7 @@ func lsCPU(option string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to exec command %s, err: %v", executable, err)
 	}
-	return string(output), nil
+	return faultinjection.TruncateOutput(faultinjection.LSCPUOutput, option, string(output)), nil
 }
 
 func getProcessorInfos(lsCPUStr string) ([]ProcessorInfo, error) {
@@ -232,8 +316,14 @@ func getProcessorInfos(lsCPUStr string) ([]ProcessorInfo, error) {
 		return nil, fmt.Errorf("no valid processor info")
 	}
 
-	// sorted by cpu topology
-	// NOTE: in some cases, max(cpuId[...]) can be not equal to len(processors)
+	sortProcessorInfos(processorInfos)
+
+	return processorInfos, nil
+}
+
+// sortProcessorInfos sorts the processors by the cpu topology.
+// NOTE: in some cases, max(cpuId[...]) can be not equal to len(processors)
+func sortProcessorInfos(processorInfos []ProcessorInfo) {
 	sort.Slice(processorInfos, func(i, j int) bool {
 		a, b := processorInfos[i], processorInfos[j]
 		if a.NodeID != b.NodeID {
@@ -247,8 +337,6 @@ func getProcessorInfos(lsCPUStr string) ([]ProcessorInfo, error) {
 		}
 		return a.CPUID < b.CPUID
 	})
-
-	return processorInfos, nil
 }
 
 func calculateCPUTotalInfo(processorInfos []ProcessorInfo) *CPUTotalInfo {
@@ -273,25 +361,3 @@ func calculateCPUTotalInfo(processorInfos []ProcessorInfo) *CPUTotalInfo {
 		L3ToCPU:     l3Map,
 	}
 }
-
-// GetLocalCPUInfo returns the local cpu info for cpuset allocation, NUMA-aware scheduling
-func GetLocalCPUInfo() (*LocalCPUInfo, error) {
-	lsCPUStr, err := lsCPU("-e=CPU,NODE,SOCKET,CORE,CACHE,ONLINE")
-	if err != nil {
-		return nil, err
-	}
-	processorInfos, err := getProcessorInfos(lsCPUStr)
-	if err != nil {
-		return nil, err
-	}
-	totalInfo := calculateCPUTotalInfo(processorInfos)
-	basicInfo, err := getCPUBasicInfo()
-	if err != nil {
-		return nil, err
-	}
-	return &LocalCPUInfo{
-		BasicInfo:      *basicInfo,
-		ProcessorInfos: processorInfos,
-		TotalInfo:      *totalInfo,
-	}, nil
-}
@@ -0,0 +1,50 @@
+//go:build linux && faultinjection
+// +build linux,faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
+)
+
+func TestGetLocalCPUInfoWithPartialLSCPUOutput(t *testing.T) {
+	if _, err := exec.LookPath("lscpu"); err != nil {
+		t.Skipf("lscpu not found, err: %v", err)
+	}
+	defer faultinjection.Reset()
+
+	// only the header is reported
+	faultinjection.Inject(faultinjection.LSCPUOutput, faultinjection.Fault{MaxLines: 1, Times: 1})
+	got, err := GetLocalCPUInfo()
+	assert.Error(t, err)
+	assert.Nil(t, got)
+
+	// only the first processor is reported
+	faultinjection.Inject(faultinjection.LSCPUOutput, faultinjection.Fault{MaxLines: 2, Times: 1})
+	got, err = GetLocalCPUInfo()
+	if err == nil {
+		assert.Len(t, got.ProcessorInfos, 1)
+		assert.Equal(t, int32(1), got.TotalInfo.NumberCPUs)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"testing"
@@ -669,3 +670,54 @@ power management:
 		assert.Equal(t, expectBasicInfo, basicInfo)
 	})
 }
+
+func Test_getKernelCPUIsolation(t *testing.T) {
+	tests := []struct {
+		name            string
+		cmdline         string
+		vulnerabilities map[string]string
+		want            *extension.KernelCPUIsolation
+	}{
+		{
+			name:    "no isolation flags",
+			cmdline: "BOOT_IMAGE=/vmlinuz root=/dev/vda1 ro console=ttyS0",
+			want:    &extension.KernelCPUIsolation{},
+		},
+		{
+			name:    "parse isolation flags and vulnerabilities",
+			cmdline: "BOOT_IMAGE=/vmlinuz ro isolcpus=domain,managed_irq,2-5,8 nohz_full=2-5 rcu_nocbs=2-5,8 quiet",
+			vulnerabilities: map[string]string{
+				"spectre_v2": "Mitigation: Retpolines",
+				"meltdown":   "Not affected",
+			},
+			want: &extension.KernelCPUIsolation{
+				IsolCPUs:     "2-5,8",
+				NohzFullCPUs: "2-5",
+				RCUNoCBsCPUs: "2-5,8",
+				Vulnerabilities: map[string]string{
+					"spectre_v2": "Mitigation: Retpolines",
+					"meltdown":   "Not affected",
+				},
+			},
+		},
+		{
+			name:    "ignore invalid cpu-list",
+			cmdline: "isolcpus=nohz,3-a nohz_full=1",
+			want: &extension.KernelCPUIsolation{
+				NohzFullCPUs: "1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteProcSubFileContents(system.KernelCmdlineFileName, tt.cmdline)
+			for name, state := range tt.vulnerabilities {
+				helper.WriteFileContents(filepath.Join(system.GetSysCPUVulnerabilitiesDir(), name), state+"\n")
+			}
+			got := getKernelCPUIsolation()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faultinjection provides the hooks to inject faults (e.g. delayed cgroup reads, write errors, partial lscpu
+// output and missing resctrl) into the koordlet for the tests of the graceful degradation.
+// The faults only take effect in the test builds with the build tag `faultinjection`. Otherwise, the hooks are no-op.
+package faultinjection
+
+import (
+	"time"
+)
+
+// Point is the place where the faults are injected.
+type Point string
+
+const (
+	// CgroupRead is triggered before reading a cgroup file. The target is the file path.
+	CgroupRead Point = "CgroupRead"
+	// CgroupWrite is triggered before writing a cgroup file. The target is the file path.
+	CgroupWrite Point = "CgroupWrite"
+	// LSCPUOutput truncates the output of the lscpu command. The target is the command option.
+	LSCPUOutput Point = "LSCPUOutput"
+	// ResctrlWrite is triggered before writing a resctrl file. The target is the file path.
+	ResctrlWrite Point = "ResctrlWrite"
+	// ResctrlMissing is triggered when checking if the resctrl is supported. The target is the resctrl root dir.
+	ResctrlMissing Point = "ResctrlMissing"
+)
+
+// Fault describes the fault injected into a Point.
+type Fault struct {
+	// TargetContains selects the targets containing the substring. All targets are selected if it is empty.
+	TargetContains string
+	// Delay blocks the operation for the duration.
+	Delay time.Duration
+	// Err is returned by the operation.
+	Err error
+	// MaxLines truncates the output to the first lines. The output is kept if it is not positive.
+	MaxLines int
+	// Times limits the number of the injections. The fault is always injected if it is not positive.
+	Times int
+}
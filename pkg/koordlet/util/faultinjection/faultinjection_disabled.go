@@ -0,0 +1,41 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+// Enabled returns if the faults can be injected in the build.
+func Enabled() bool {
+	return false
+}
+
+// Inject is no-op without the build tag `faultinjection`.
+func Inject(point Point, fault Fault) {}
+
+// Reset is no-op without the build tag `faultinjection`.
+func Reset() {}
+
+// Trigger always returns nil without the build tag `faultinjection`.
+func Trigger(point Point, target string) error {
+	return nil
+}
+
+// TruncateOutput always returns the original output without the build tag `faultinjection`.
+func TruncateOutput(point Point, target string, output string) string {
+	return output
+}
@@ -0,0 +1,36 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisabled(t *testing.T) {
+	assert.False(t, Enabled())
+	Inject(CgroupRead, Fault{Err: fmt.Errorf("injected error")})
+	Inject(LSCPUOutput, Fault{MaxLines: 1})
+	defer Reset()
+	assert.NoError(t, Trigger(CgroupRead, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+	assert.Equal(t, "a\nb\n", TruncateOutput(LSCPUOutput, "-e", "a\nb\n"))
+}
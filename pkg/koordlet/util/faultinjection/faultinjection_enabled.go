@@ -0,0 +1,101 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+type injectedFault struct {
+	Fault
+	injected int
+}
+
+var (
+	lock   sync.Mutex
+	faults = map[Point][]*injectedFault{}
+)
+
+// Enabled returns if the faults can be injected in the build.
+func Enabled() bool {
+	return true
+}
+
+// Inject adds a fault to the point.
+func Inject(point Point, fault Fault) {
+	lock.Lock()
+	defer lock.Unlock()
+	faults[point] = append(faults[point], &injectedFault{Fault: fault})
+}
+
+// Reset removes all injected faults.
+func Reset() {
+	lock.Lock()
+	defer lock.Unlock()
+	faults = map[Point][]*injectedFault{}
+}
+
+// Trigger applies the delay of the matched fault and returns its error.
+func Trigger(point Point, target string) error {
+	fault := match(point, target)
+	if fault == nil {
+		return nil
+	}
+	if fault.Delay > 0 {
+		time.Sleep(fault.Delay)
+	}
+	return fault.Err
+}
+
+// TruncateOutput returns the output truncated by the matched fault.
+func TruncateOutput(point Point, target string, output string) string {
+	fault := match(point, target)
+	if fault == nil || fault.MaxLines <= 0 {
+		return output
+	}
+	lines := strings.SplitAfter(output, "\n")
+	if len(lines) <= fault.MaxLines {
+		return output
+	}
+	return strings.Join(lines[:fault.MaxLines], "")
+}
+
+// match returns the first fault of the point selecting the target and counts the injection.
+func match(point Point, target string) *Fault {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, f := range faults[point] {
+		if len(f.TargetContains) > 0 && !strings.Contains(target, f.TargetContains) {
+			continue
+		}
+		if f.Times > 0 && f.injected >= f.Times {
+			continue
+		}
+		f.injected++
+		klog.V(4).Infof("inject fault into %s, target %s", point, target)
+		fault := f.Fault
+		return &fault
+	}
+	return nil
+}
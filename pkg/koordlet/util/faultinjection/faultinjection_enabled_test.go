@@ -0,0 +1,60 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrigger(t *testing.T) {
+	defer Reset()
+	assert.True(t, Enabled())
+	assert.NoError(t, Trigger(CgroupRead, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+
+	testErr := fmt.Errorf("injected error")
+	Inject(CgroupRead, Fault{TargetContains: "cpu.cfs_quota_us", Err: testErr, Times: 1})
+	Inject(CgroupRead, Fault{Delay: 10 * time.Millisecond})
+	assert.NoError(t, Trigger(CgroupWrite, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+	// the first fault is injected once
+	assert.Equal(t, testErr, Trigger(CgroupRead, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+	start := time.Now()
+	assert.NoError(t, Trigger(CgroupRead, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	Reset()
+	assert.NoError(t, Trigger(CgroupRead, "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"))
+}
+
+func TestTruncateOutput(t *testing.T) {
+	defer Reset()
+	output := "CPU NODE SOCKET CORE L1d:L1i:L2:L3 ONLINE\n0 0 0 0 0:0:0:0 yes\n1 0 0 1 1:1:1:0 yes\n"
+	assert.Equal(t, output, TruncateOutput(LSCPUOutput, "-e", output))
+
+	Inject(LSCPUOutput, Fault{TargetContains: "-y", MaxLines: 1})
+	assert.Equal(t, output, TruncateOutput(LSCPUOutput, "-e", output))
+	Inject(LSCPUOutput, Fault{MaxLines: 2})
+	assert.Equal(t, "CPU NODE SOCKET CORE L1d:L1i:L2:L3 ONLINE\n0 0 0 0 0:0:0:0 yes\n", TruncateOutput(LSCPUOutput, "-e", output))
+	Inject(LSCPUOutput, Fault{MaxLines: 10})
+	assert.Equal(t, "Thread(s) per core: 2\n", TruncateOutput(LSCPUOutput, "-y", "Thread(s) per core: 2\nCore(s) per socket: 4\n"))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	HostLSCgroupDir = "host-latency-sensitive"
+	HostBECgroupDir = "host-best-effort"
+)
+
+// GetHostAppCgroupRelativePath gets the relative cgroup dir of the host application.
+// If the cgroup path is not specified, the application is placed under the default dir of its QoS class.
+// e.g. host-latency-sensitive/nginx, kubepods.slice/kubepods-besteffort.slice/host-be-app/spark
+func GetHostAppCgroupRelativePath(hostAppSpec *slov1alpha1.HostApplicationSpec) string {
+	if hostAppSpec == nil {
+		return ""
+	}
+	if hostAppSpec.CgroupPath == nil {
+		cgroupBaseDir := ""
+		switch hostAppSpec.QoS {
+		case apiext.QoSLSE, apiext.QoSLSR, apiext.QoSLS:
+			cgroupBaseDir = HostLSCgroupDir
+		case apiext.QoSBE:
+			cgroupBaseDir = HostBECgroupDir
+			// empty string for QoSNone as default
+		}
+		return filepath.Join(cgroupBaseDir, hostAppSpec.Name)
+	}
+	cgroupBaseDir := ""
+	switch hostAppSpec.CgroupPath.Base {
+	case slov1alpha1.CgroupBaseTypeKubepods:
+		cgroupBaseDir = GetPodQoSRelativePath(corev1.PodQOSGuaranteed)
+	case slov1alpha1.CgroupBaseTypeKubeBurstable:
+		cgroupBaseDir = GetPodQoSRelativePath(corev1.PodQOSBurstable)
+	case slov1alpha1.CgroupBaseTypeKubeBesteffort:
+		cgroupBaseDir = GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+		// empty string for CgroupBaseTypeRoot as default
+	}
+	return filepath.Join(cgroupBaseDir, hostAppSpec.CgroupPath.ParentDir, hostAppSpec.CgroupPath.RelativePath)
+}
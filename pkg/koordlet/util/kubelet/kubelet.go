@@ -263,3 +263,11 @@ func hardEvictionReservation(thresholds []evictionapi.Threshold, capacity corev1
 func GetCPUManagerStateFilePath(rootDirectory string) string {
 	return filepath.Join(rootDirectory, "cpu_manager_state")
 }
+
+func GetMemoryManagerStateFilePath(rootDirectory string) string {
+	return filepath.Join(rootDirectory, "memory_manager_state")
+}
+
+func GetDeviceManagerCheckpointFilePath(rootDirectory string) string {
+	return filepath.Join(rootDirectory, "device-plugins", "kubelet_internal_checkpoint")
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+)
+
+// The relationships of the SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX entries returned by the Windows API
+// GetLogicalProcessorInformationEx.
+// See: https://learn.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-system_logical_processor_information_ex
+const (
+	relationProcessorCore    = 0
+	relationNumaNode         = 1
+	relationCache            = 2
+	relationProcessorPackage = 3
+	relationAll              = 0xffff
+
+	// logicalProcessorInfoHeaderSize is the size of the Relationship and the Size fields.
+	logicalProcessorInfoHeaderSize = 8
+	// groupAffinitySize is the size of GROUP_AFFINITY on the 64-bit Windows.
+	groupAffinitySize = 16
+	// maxCPUsPerGroup is the number of logical processors that a processor group can contain at most.
+	maxCPUsPerGroup = 64
+)
+
+// parseLogicalProcessorInformationEx parses the buffer filled by GetLogicalProcessorInformationEx(RelationAll)
+// into the processor infos sorted by the cpu topology. The logical processor k in the processor group g is
+// numbered as the cpu g*64+k. The ids of the cores, sockets and L3 caches are numbered in the order they appear.
+func parseLogicalProcessorInformationEx(buf []byte) ([]ProcessorInfo, error) {
+	processors := map[int32]*ProcessorInfo{}
+	getProcessor := func(cpu int32) *ProcessorInfo {
+		p, ok := processors[cpu]
+		if !ok {
+			p = &ProcessorInfo{CPUID: cpu, Online: "yes"}
+			processors[cpu] = p
+		}
+		return p
+	}
+
+	var numCores, numSockets, numL3 int32
+	for offset := 0; offset < len(buf); {
+		if offset+logicalProcessorInfoHeaderSize > len(buf) {
+			return nil, fmt.Errorf("truncated logical processor info at offset %d", offset)
+		}
+		relationship := binary.LittleEndian.Uint32(buf[offset:])
+		size := int(binary.LittleEndian.Uint32(buf[offset+4:]))
+		if size <= logicalProcessorInfoHeaderSize || offset+size > len(buf) {
+			return nil, fmt.Errorf("invalid logical processor info size %d at offset %d", size, offset)
+		}
+		body := buf[offset+logicalProcessorInfoHeaderSize : offset+size]
+		offset += size
+
+		switch relationship {
+		case relationProcessorCore:
+			// PROCESSOR_RELATIONSHIP: Flags, EfficiencyClass, Reserved[20], GroupCount, GroupMask[]
+			cpus, err := parseGroupAffinities(body, 22, 24)
+			if err != nil {
+				return nil, err
+			}
+			for _, cpu := range cpus {
+				p := getProcessor(cpu)
+				p.CoreID = numCores
+				p.L1dl1il2 = strconv.Itoa(int(numCores))
+			}
+			numCores++
+		case relationProcessorPackage:
+			cpus, err := parseGroupAffinities(body, 22, 24)
+			if err != nil {
+				return nil, err
+			}
+			for _, cpu := range cpus {
+				getProcessor(cpu).SocketID = numSockets
+			}
+			numSockets++
+		case relationNumaNode:
+			// NUMA_NODE_RELATIONSHIP: NodeNumber, Reserved[18], GroupCount, GroupMask[]
+			if len(body) < 4 {
+				return nil, fmt.Errorf("invalid NUMA node relationship size %d", len(body))
+			}
+			nodeID := int32(binary.LittleEndian.Uint32(body))
+			cpus, err := parseGroupAffinities(body, 22, 24)
+			if err != nil {
+				return nil, err
+			}
+			for _, cpu := range cpus {
+				getProcessor(cpu).NodeID = nodeID
+			}
+		case relationCache:
+			// CACHE_RELATIONSHIP: Level, Associativity, LineSize, CacheSize, Type, Reserved[18], GroupCount, GroupMask[]
+			if len(body) < 1 || body[0] != 3 {
+				continue
+			}
+			cpus, err := parseGroupAffinities(body, 30, 32)
+			if err != nil {
+				return nil, err
+			}
+			for _, cpu := range cpus {
+				getProcessor(cpu).L3 = numL3
+			}
+			numL3++
+		}
+	}
+	if len(processors) <= 0 {
+		return nil, fmt.Errorf("no valid processor info")
+	}
+
+	processorInfos := make([]ProcessorInfo, 0, len(processors))
+	for _, p := range processors {
+		processorInfos = append(processorInfos, *p)
+	}
+	sort.Slice(processorInfos, func(i, j int) bool {
+		a, b := processorInfos[i], processorInfos[j]
+		if a.NodeID != b.NodeID {
+			return a.NodeID < b.NodeID
+		}
+		if a.SocketID != b.SocketID {
+			return a.SocketID < b.SocketID
+		}
+		if a.CoreID != b.CoreID {
+			return a.CoreID < b.CoreID
+		}
+		return a.CPUID < b.CPUID
+	})
+	return processorInfos, nil
+}
+
+// parseGroupAffinities parses the GROUP_AFFINITY array into the cpu ids.
+// The GroupCount can be zero on the Windows before Windows Server 2022, which means there is a single GroupMask.
+func parseGroupAffinities(body []byte, groupCountOffset, groupMaskOffset int) ([]int32, error) {
+	if len(body) < groupCountOffset+2 {
+		return nil, fmt.Errorf("invalid relationship size %d", len(body))
+	}
+	groupCount := int(binary.LittleEndian.Uint16(body[groupCountOffset:]))
+	if groupCount == 0 {
+		groupCount = 1
+	}
+	if len(body) < groupMaskOffset+groupCount*groupAffinitySize {
+		return nil, fmt.Errorf("invalid relationship size %d for %d groups", len(body), groupCount)
+	}
+	var cpus []int32
+	for i := 0; i < groupCount; i++ {
+		// GROUP_AFFINITY: Mask, Group, Reserved[3]
+		affinity := body[groupMaskOffset+i*groupAffinitySize:]
+		mask := binary.LittleEndian.Uint64(affinity)
+		group := int32(binary.LittleEndian.Uint16(affinity[8:]))
+		for mask != 0 {
+			k := bits.TrailingZeros64(mask)
+			cpus = append(cpus, group*maxCPUsPerGroup+int32(k))
+			mask &^= 1 << k
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testGroupAffinity struct {
+	mask  uint64
+	group uint16
+}
+
+func newTestLogicalProcessorInfo(relationship uint32, head []byte, groupCountOffset int, affinities []testGroupAffinity) []byte {
+	body := make([]byte, groupCountOffset+2, groupCountOffset+2+len(affinities)*groupAffinitySize+8)
+	copy(body, head)
+	binary.LittleEndian.PutUint16(body[groupCountOffset:], uint16(len(affinities)))
+	// align the GroupMask array to 8 bytes
+	for len(body)%8 != 0 {
+		body = append(body, 0)
+	}
+	for _, a := range affinities {
+		affinity := make([]byte, groupAffinitySize)
+		binary.LittleEndian.PutUint64(affinity, a.mask)
+		binary.LittleEndian.PutUint16(affinity[8:], a.group)
+		body = append(body, affinity...)
+	}
+	header := make([]byte, logicalProcessorInfoHeaderSize)
+	binary.LittleEndian.PutUint32(header, relationship)
+	binary.LittleEndian.PutUint32(header[4:], uint32(logicalProcessorInfoHeaderSize+len(body)))
+	return append(header, body...)
+}
+
+func Test_parseLogicalProcessorInformationEx(t *testing.T) {
+	var buf []byte
+	// 2 sockets, 2 cores per socket, 2 threads per core, the second socket is in the processor group 1
+	buf = append(buf, newTestLogicalProcessorInfo(relationProcessorCore, nil, 22, []testGroupAffinity{{mask: 0x3}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationProcessorCore, nil, 22, []testGroupAffinity{{mask: 0xc}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationProcessorCore, nil, 22, []testGroupAffinity{{mask: 0x3, group: 1}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationProcessorCore, nil, 22, []testGroupAffinity{{mask: 0xc, group: 1}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationProcessorPackage, nil, 22, []testGroupAffinity{{mask: 0xf}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationProcessorPackage, nil, 22, []testGroupAffinity{{mask: 0xf, group: 1}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationNumaNode, []byte{0, 0, 0, 0}, 22, []testGroupAffinity{{mask: 0xf}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationNumaNode, []byte{1, 0, 0, 0}, 22, []testGroupAffinity{{mask: 0xf, group: 1}})...)
+	// the L2 cache is ignored
+	buf = append(buf, newTestLogicalProcessorInfo(relationCache, []byte{2}, 30, []testGroupAffinity{{mask: 0x3}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationCache, []byte{3}, 30, []testGroupAffinity{{mask: 0xf}})...)
+	buf = append(buf, newTestLogicalProcessorInfo(relationCache, []byte{3}, 30, []testGroupAffinity{{mask: 0xf, group: 1}})...)
+
+	got, err := parseLogicalProcessorInformationEx(buf)
+	assert.NoError(t, err)
+	expected := []ProcessorInfo{
+		{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0, L1dl1il2: "0", L3: 0, Online: "yes"},
+		{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0, L1dl1il2: "0", L3: 0, Online: "yes"},
+		{CPUID: 2, CoreID: 1, SocketID: 0, NodeID: 0, L1dl1il2: "1", L3: 0, Online: "yes"},
+		{CPUID: 3, CoreID: 1, SocketID: 0, NodeID: 0, L1dl1il2: "1", L3: 0, Online: "yes"},
+		{CPUID: 64, CoreID: 2, SocketID: 1, NodeID: 1, L1dl1il2: "2", L3: 1, Online: "yes"},
+		{CPUID: 65, CoreID: 2, SocketID: 1, NodeID: 1, L1dl1il2: "2", L3: 1, Online: "yes"},
+		{CPUID: 66, CoreID: 3, SocketID: 1, NodeID: 1, L1dl1il2: "3", L3: 1, Online: "yes"},
+		{CPUID: 67, CoreID: 3, SocketID: 1, NodeID: 1, L1dl1il2: "3", L3: 1, Online: "yes"},
+	}
+	assert.Equal(t, expected, got)
+
+	totalInfo := calculateCPUTotalInfo(got)
+	assert.Equal(t, int32(8), totalInfo.NumberCPUs)
+	assert.Len(t, totalInfo.CoreToCPU, 4)
+	assert.Len(t, totalInfo.NodeToCPU, 2)
+
+	// truncated buffer
+	_, err = parseLogicalProcessorInformationEx(buf[:len(buf)-4])
+	assert.Error(t, err)
+	// empty buffer
+	_, err = parseLogicalProcessorInformationEx(nil)
+	assert.Error(t, err)
+}
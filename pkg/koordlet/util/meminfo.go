@@ -17,16 +17,10 @@ limitations under the License.
 package util
 
 import (
-	"fmt"
 	"os"
 	"reflect"
-	"sort"
 	"strconv"
 	"strings"
-
-	"k8s.io/klog/v2"
-
-	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 // MemInfo is the content of system /proc/meminfo.
@@ -142,15 +136,6 @@ func readMemInfo(path string, isNUMA bool) (*MemInfo, error) {
 	return &info, nil
 }
 
-func GetMemInfo() (*MemInfo, error) {
-	memInfoPath := system.GetProcFilePath(system.ProcMemInfoName)
-	memInfo, err := readMemInfo(memInfoPath, false)
-	if err != nil {
-		return nil, err
-	}
-	return memInfo, nil
-}
-
 type NUMAInfo struct {
 	NUMANodeID int32    `json:"numaNodeID,omitempty"`
 	MemInfo    *MemInfo `json:"memInfo,omitempty"`
@@ -162,64 +147,3 @@ type NodeNUMAInfo struct {
 	NUMAInfos  []NUMAInfo         `json:"numaInfos,omitempty"`
 	MemInfoMap map[int32]*MemInfo `json:"memInfoMap,omitempty"` // NUMANodeID -> MemInfo
 }
-
-// GetNodeNUMAInfo gets the node NUMA information with the pre-configured sysfs path.
-func GetNodeNUMAInfo() (*NodeNUMAInfo, error) {
-	numaNodeParentDir := system.GetSysNUMADir()
-	nodeDirs, err := os.ReadDir(numaNodeParentDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read NUMA dir, err: %w", err)
-	}
-
-	result := &NodeNUMAInfo{
-		MemInfoMap: map[int32]*MemInfo{},
-	}
-	maxNodeID := int32(-1)
-	for _, n := range nodeDirs {
-		dirName := n.Name() // assert string pattern `nodeX`
-		if len(dirName) < 4 || dirName[:4] != "node" {
-			klog.V(4).Infof("failed to get node NUMA info, err: invalid dir name %s", dirName)
-			continue
-		}
-
-		nodeIDRaw, err := strconv.ParseInt(dirName[4:], 10, 32)
-		if err != nil {
-			klog.V(4).Infof("failed to parse NUMA ID, err: invalid dir name %s, err %v", dirName, err)
-			continue
-		}
-		nodeID := int32(nodeIDRaw)
-
-		numaMemInfoPath := system.GetNUMAMemInfoPath(dirName)
-		memInfo, err := readMemInfo(numaMemInfoPath, true)
-		if err != nil {
-			klog.V(4).Infof("failed to read NUMA info, dir %s, err: %v", dirName, err)
-			continue
-		}
-
-		numaInfo := NUMAInfo{
-			NUMANodeID: nodeID,
-			MemInfo:    memInfo,
-		}
-		result.NUMAInfos = append(result.NUMAInfos, numaInfo)
-		result.MemInfoMap[nodeID] = memInfo
-		if nodeID > maxNodeID {
-			maxNodeID = nodeID
-		}
-	}
-
-	if len(nodeDirs) != len(result.NUMAInfos) {
-		return nil, fmt.Errorf("invalid number of NUMA meminfo, dir %v, parsed %v",
-			len(nodeDirs), len(result.NUMAInfos))
-	}
-	if len(result.NUMAInfos) != int(maxNodeID+1) {
-		return nil, fmt.Errorf("unexpected number of NUMA node, max ID %v, parsed %v",
-			maxNodeID, len(result.NUMAInfos))
-	}
-
-	// sort NUMA infos by the order of node id
-	sort.Slice(result.NUMAInfos, func(i, j int) bool {
-		return result.NUMAInfos[i].NUMANodeID < result.NUMAInfos[j].NUMANodeID
-	})
-
-	return result, nil
-}
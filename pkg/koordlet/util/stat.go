@@ -23,12 +23,30 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/perf"
 	perfgroup "github.com/koordinator-sh/koordinator/pkg/koordlet/util/perf_group"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
+func parseCPUStatUsageTicks(statPath string, fieldStat []string) (uint64, error) {
+	if len(fieldStat) <= 7 {
+		return 0, fmt.Errorf("%s is illegally formatted", statPath)
+	}
+	var total uint64 = 0
+	// format: cpu $user $nice $system $idle $iowait $irq $softirq
+	for _, i := range []int{1, 2, 3, 6, 7} {
+		v, err := strconv.ParseUint(fieldStat[i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse node stat %s, err: %s", strings.Join(fieldStat, " "), err)
+		}
+		total += v
+	}
+	return total, nil
+}
+
 func readTotalCPUStat(statPath string) (uint64, error) {
 	// stat usage: $user + $nice + $system + $irq + $softirq
 	rawStats, err := os.ReadFile(statPath)
@@ -39,30 +57,226 @@ func readTotalCPUStat(statPath string) (uint64, error) {
 	for _, stat := range stats {
 		fieldStat := strings.Fields(stat)
 		if len(fieldStat) > 0 && fieldStat[0] == "cpu" {
-			if len(fieldStat) <= 7 {
-				return 0, fmt.Errorf("%s is illegally formatted", statPath)
-			}
-			var total uint64 = 0
-			// format: cpu $user $nice $system $idle $iowait $irq $softirq
-			for _, i := range []int{1, 2, 3, 6, 7} {
-				v, err := strconv.ParseUint(fieldStat[i], 10, 64)
-				if err != nil {
-					return 0, fmt.Errorf("failed to parse node stat %s, err: %s", stat, err)
-				}
-				total += v
-			}
-			return total, nil
+			return parseCPUStatUsageTicks(statPath, fieldStat)
 		}
 	}
 	return 0, fmt.Errorf("%s is illegally formatted", statPath)
 }
 
+// readCPUsStat sums the usage ticks of the specified cpus. The offline cpus are missing in the stat and skipped.
+func readCPUsStat(statPath string, cpus cpuset.CPUSet) (uint64, error) {
+	rawStats, err := os.ReadFile(statPath)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64 = 0
+	found := cpuset.NewCPUSetBuilder()
+	for _, stat := range strings.Split(string(rawStats), "\n") {
+		fieldStat := strings.Fields(stat)
+		// format: cpu0 $user $nice $system $idle $iowait $irq $softirq
+		if len(fieldStat) == 0 || len(fieldStat[0]) <= 3 || !strings.HasPrefix(fieldStat[0], "cpu") {
+			continue
+		}
+		cpu, err := strconv.Atoi(fieldStat[0][3:])
+		if err != nil || !cpus.Contains(cpu) {
+			continue
+		}
+		v, err := parseCPUStatUsageTicks(statPath, fieldStat)
+		if err != nil {
+			return 0, err
+		}
+		total += v
+		found.Add(cpu)
+	}
+	foundCPUs := found.Result()
+	if foundCPUs.IsEmpty() {
+		return 0, fmt.Errorf("%s has no stat of the cpus %s", statPath, cpus.String())
+	}
+	if missingCPUs := cpus.Difference(foundCPUs); !missingCPUs.IsEmpty() {
+		klog.V(4).Infof("%s has no stat of the offline cpus %s, skip them", statPath, missingCPUs.String())
+	}
+	return total, nil
+}
+
 // GetCPUStatUsageTicks returns the node's CPU usage ticks
 func GetCPUStatUsageTicks() (uint64, error) {
 	statPath := system.GetProcFilePath(system.ProcStatName)
 	return readTotalCPUStat(statPath)
 }
 
+// GetCPUsStatUsageTicks returns the CPU usage ticks of the specified cpus
+func GetCPUsStatUsageTicks(cpus cpuset.CPUSet) (uint64, error) {
+	statPath := system.GetProcFilePath(system.ProcStatName)
+	return readCPUsStat(statPath, cpus)
+}
+
+// SchedStat is the run queue statistics summed over all the cpus.
+type SchedStat struct {
+	// RunDelay is the time spent by the tasks waiting on the run queues in nanoseconds
+	RunDelay uint64
+	// Timeslices is the number of the timeslices run on the cpus
+	Timeslices uint64
+}
+
+func readSchedStat(schedStatPath string) (*SchedStat, error) {
+	rawStats, err := os.ReadFile(schedStatPath)
+	if err != nil {
+		return nil, err
+	}
+	stat := &SchedStat{}
+	found := 0
+	for _, line := range strings.Split(string(rawStats), "\n") {
+		fieldStat := strings.Fields(line)
+		// format: cpu0 $yld_count $legacy $sched_count $sched_goidle $ttwu_count $ttwu_local $rq_cpu_time $run_delay $pcount
+		if len(fieldStat) == 0 || len(fieldStat[0]) <= 3 || !strings.HasPrefix(fieldStat[0], "cpu") {
+			continue
+		}
+		if len(fieldStat) < 10 {
+			return nil, fmt.Errorf("%s is illegally formatted", schedStatPath)
+		}
+		runDelay, err := strconv.ParseUint(fieldStat[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schedstat %s, err: %s", line, err)
+		}
+		timeslices, err := strconv.ParseUint(fieldStat[9], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse schedstat %s, err: %s", line, err)
+		}
+		stat.RunDelay += runDelay
+		stat.Timeslices += timeslices
+		found++
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("%s has no cpu stat", schedStatPath)
+	}
+	return stat, nil
+}
+
+// GetSchedStat returns the node's run queue statistics
+func GetSchedStat() (*SchedStat, error) {
+	schedStatPath := system.GetProcFilePath(system.ProcSchedStatName)
+	return readSchedStat(schedStatPath)
+}
+
+// CPUStealStat is the stolen ticks and the total ticks of the node cpus.
+type CPUStealStat struct {
+	// Steal is the ticks stolen by the hypervisor
+	Steal uint64
+	// Total is the ticks of all the cpu states, including the idle and the steal
+	Total uint64
+}
+
+func readCPUStealStat(statPath string) (*CPUStealStat, error) {
+	rawStats, err := os.ReadFile(statPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(rawStats), "\n") {
+		fieldStat := strings.Fields(line)
+		if len(fieldStat) == 0 || fieldStat[0] != "cpu" {
+			continue
+		}
+		// format: cpu $user $nice $system $idle $iowait $irq $softirq $steal
+		if len(fieldStat) <= 8 {
+			return nil, fmt.Errorf("%s is illegally formatted", statPath)
+		}
+		stat := &CPUStealStat{}
+		for i := 1; i <= 8; i++ {
+			v, err := strconv.ParseUint(fieldStat[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse node stat %s, err: %s", line, err)
+			}
+			stat.Total += v
+			if i == 8 {
+				stat.Steal = v
+			}
+		}
+		return stat, nil
+	}
+	return nil, fmt.Errorf("%s is illegally formatted", statPath)
+}
+
+// GetCPUStealStat returns the node's CPU steal ticks
+func GetCPUStealStat() (*CPUStealStat, error) {
+	statPath := system.GetProcFilePath(system.ProcStatName)
+	return readCPUStealStat(statPath)
+}
+
+// TaskSchedStat is the scheduler statistics of tasks, which can be summed over the tasks of a container.
+type TaskSchedStat struct {
+	// Migrations is the number of times the tasks migrated between the cpus
+	Migrations uint64
+	// VoluntarySwitches is the number of context switches since the tasks gave up the cpus, e.g. blocking on I/O
+	VoluntarySwitches uint64
+	// InvoluntarySwitches is the number of context switches since the tasks were preempted
+	InvoluntarySwitches uint64
+}
+
+// Add accumulates the statistics of another task.
+func (s *TaskSchedStat) Add(other *TaskSchedStat) {
+	s.Migrations += other.Migrations
+	s.VoluntarySwitches += other.VoluntarySwitches
+	s.InvoluntarySwitches += other.InvoluntarySwitches
+}
+
+func readTaskSchedStat(taskSchedPath string) (*TaskSchedStat, error) {
+	rawStats, err := os.ReadFile(taskSchedPath)
+	if err != nil {
+		return nil, err
+	}
+	stat := &TaskSchedStat{}
+	found := 0
+	for _, line := range strings.Split(string(rawStats), "\n") {
+		// format: $key : $value
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		var field *uint64
+		switch strings.TrimSpace(kv[0]) {
+		case "se.nr_migrations":
+			field = &stat.Migrations
+		case "nr_voluntary_switches":
+			field = &stat.VoluntarySwitches
+		case "nr_involuntary_switches":
+			field = &stat.InvoluntarySwitches
+		default:
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse task sched %s, err: %s", line, err)
+		}
+		*field = v
+		found++
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("%s has no task sched stat", taskSchedPath)
+	}
+	return stat, nil
+}
+
+// GetTasksSchedStat returns the scheduler statistics summed over the tasks.
+// The tasks exited during the reading are skipped.
+func GetTasksSchedStat(tasks []int32) (*TaskSchedStat, error) {
+	stat := &TaskSchedStat{}
+	var lastErr error
+	read := 0
+	for _, task := range tasks {
+		taskStat, err := readTaskSchedStat(system.GetProcFilePath(fmt.Sprintf("%d/%s", task, system.ProcPIDSchedName)))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		stat.Add(taskStat)
+		read++
+	}
+	if read == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return stat, nil
+}
+
 func GetContainerPerfGroupCollector(podCgroupDir string, c *corev1.ContainerStatus, number int32, events []string) (*perfgroup.PerfGroupCollector, error) {
 	cpus := make([]int, number)
 	for i := range cpus {
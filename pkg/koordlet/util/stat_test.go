@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 func Test_readTotalCPUStat(t *testing.T) {
@@ -81,6 +82,52 @@ func Test_readTotalCPUStat(t *testing.T) {
 	}
 }
 
+func Test_readCPUsStat(t *testing.T) {
+	tempDir := t.TempDir()
+	tempStatPath := filepath.Join(tempDir, "stat")
+	statContentStr := "cpu  514003 37519 593580 1706155242 5134 45033 38832 0 0 0\n" +
+		"cpu0 9755 845 15540 26635869 3021 2312 9724 0 0 0\n" +
+		"cpu1 10075 664 10790 26653871 214 973 1163 0 0 0\n" +
+		"intr 574218032 193 0 0 0 4209 0 0 225 131056 131080 130910 130673 130935 130681 130682 130949 131048\n" +
+		"ctxt 701110258\n"
+	err := os.WriteFile(tempStatPath, []byte(statContentStr), 0666)
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		cpus    cpuset.CPUSet
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "read stat of one cpu",
+			cpus: cpuset.NewCPUSet(1),
+			want: 23665,
+		},
+		{
+			name: "read stat of all cpus",
+			cpus: cpuset.NewCPUSet(0, 1),
+			want: 61841,
+		},
+		{
+			name: "read stat with an offline cpu",
+			cpus: cpuset.NewCPUSet(1, 2),
+			want: 23665,
+		},
+		{
+			name:    "read stat of missing cpus",
+			cpus:    cpuset.NewCPUSet(2, 3),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCPUsStat(tempStatPath, tt.cpus)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_GetCPUStatUsageTicks(t *testing.T) {
 	if runtime.GOOS != "linux" {
 		t.Log("Ignore non-Linux environment")
@@ -111,3 +158,83 @@ func Test_GetContainerPerfCollector(t *testing.T) {
 	_, err := GetContainerPerfGroupCollector(tempDir, wrongContainerStatus, 1, []string{"cycles", "instructions"})
 	assert.NotNil(t, err)
 }
+
+func Test_readSchedStat(t *testing.T) {
+	tempDir := t.TempDir()
+	schedStatPath := filepath.Join(tempDir, "schedstat")
+	_, err := readSchedStat(schedStatPath)
+	assert.Error(t, err)
+
+	err = os.WriteFile(schedStatPath, []byte("version 15\n"+
+		"timestamp 4295041592\n"+
+		"cpu0 0 0 0 0 0 0 1000000000 300000000 1000\n"+
+		"domain0 3 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n"+
+		"cpu1 0 0 0 0 0 0 1000000000 100000000 2000\n"), 0666)
+	assert.NoError(t, err)
+	got, err := readSchedStat(schedStatPath)
+	assert.NoError(t, err)
+	assert.Equal(t, &SchedStat{RunDelay: 400000000, Timeslices: 3000}, got)
+
+	err = os.WriteFile(schedStatPath, []byte("version 15\ncpu0 0 0 0\n"), 0666)
+	assert.NoError(t, err)
+	_, err = readSchedStat(schedStatPath)
+	assert.Error(t, err)
+}
+
+func Test_readCPUStealStat(t *testing.T) {
+	tempDir := t.TempDir()
+	statPath := filepath.Join(tempDir, "stat")
+	_, err := readCPUStealStat(statPath)
+	assert.Error(t, err)
+
+	err = os.WriteFile(statPath, []byte("cpu  100 0 100 700 0 0 0 100 0 0\n"+
+		"cpu0 100 0 100 700 0 0 0 100 0 0\n"+
+		"ctxt 701110258\n"), 0666)
+	assert.NoError(t, err)
+	got, err := readCPUStealStat(statPath)
+	assert.NoError(t, err)
+	assert.Equal(t, &CPUStealStat{Steal: 100, Total: 1000}, got)
+
+	err = os.WriteFile(statPath, []byte("cpu  100 0 100 700 0 0 0\n"), 0666)
+	assert.NoError(t, err)
+	_, err = readCPUStealStat(statPath)
+	assert.Error(t, err)
+}
+
+func Test_readTaskSchedStat(t *testing.T) {
+	tempDir := t.TempDir()
+	taskSchedPath := filepath.Join(tempDir, "sched")
+	_, err := readTaskSchedStat(taskSchedPath)
+	assert.Error(t, err)
+
+	err = os.WriteFile(taskSchedPath, []byte("bash (1234, #threads: 1)\n"+
+		"-------------------------------------------------------------------\n"+
+		"se.exec_start                                :        1234567.890123\n"+
+		"se.nr_migrations                             :                   42\n"+
+		"nr_switches                                  :                  100\n"+
+		"nr_voluntary_switches                        :                   80\n"+
+		"nr_involuntary_switches                      :                   20\n"), 0666)
+	assert.NoError(t, err)
+	got, err := readTaskSchedStat(taskSchedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, &TaskSchedStat{Migrations: 42, VoluntarySwitches: 80, InvoluntarySwitches: 20}, got)
+
+	err = os.WriteFile(taskSchedPath, []byte("se.nr_migrations : abc\n"), 0666)
+	assert.NoError(t, err)
+	_, err = readTaskSchedStat(taskSchedPath)
+	assert.Error(t, err)
+}
+
+func Test_GetTasksSchedStat(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := GetTasksSchedStat([]int32{1000})
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents("1000/sched", "se.nr_migrations : 3\nnr_voluntary_switches : 10\nnr_involuntary_switches : 5\n")
+	helper.WriteProcSubFileContents("1001/sched", "se.nr_migrations : 2\nnr_voluntary_switches : 20\nnr_involuntary_switches : 1\n")
+	got, err := GetTasksSchedStat([]int32{1000, 1001, 1002})
+	assert.NoError(t, err)
+	assert.Equal(t, &TaskSchedStat{Migrations: 5, VoluntarySwitches: 30, InvoluntarySwitches: 6}, got)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// StaticTopologySpec is the manually specified topology of the node, which takes precedence over the auto-detection
+// by lscpu and sysfs. It is useful for the air-gapped nodes or the exotic hardware whose topology cannot be parsed.
+// e.g.
+//
+//	cpus:
+//	- {cpu: 0, core: 0, socket: 0, node: 0}
+//	- {cpu: 1, core: 0, socket: 0, node: 0}
+//	numaNodes:
+//	- {node: 0, memory: 64Gi}
+type StaticTopologySpec struct {
+	// CPUs are the logical processors of the node.
+	CPUs []StaticCPUInfo `json:"cpus"`
+	// NUMANodes are the memory capacities of the NUMA nodes. The NUMA info is still detected from the sysfs if empty.
+	NUMANodes []StaticNUMANodeInfo `json:"numaNodes,omitempty"`
+}
+
+type StaticCPUInfo struct {
+	CPUID    int32 `json:"cpu"`
+	CoreID   int32 `json:"core"`
+	SocketID int32 `json:"socket"`
+	NodeID   int32 `json:"node"`
+	// L3 is the L3 cache ID of the processor. It defaults to the socket ID.
+	L3 *int32 `json:"l3,omitempty"`
+}
+
+type StaticNUMANodeInfo struct {
+	NodeID int32             `json:"node"`
+	Memory resource.Quantity `json:"memory"`
+}
+
+// LoadStaticTopologySpec reads the static topology spec formatted in yaml or json from the file and validates it.
+func LoadStaticTopologySpec(path string) (*StaticTopologySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static topology file %s, err: %w", path, err)
+	}
+	return ParseStaticTopologySpec(data)
+}
+
+// ParseStaticTopologySpec parses the static topology spec formatted in yaml or json. The unknown fields are rejected.
+func ParseStaticTopologySpec(data []byte) (*StaticTopologySpec, error) {
+	spec := &StaticTopologySpec{}
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal static topology, err: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid static topology, err: %w", err)
+	}
+	return spec, nil
+}
+
+// Validate checks the static topology is complete and self-consistent.
+func (s *StaticTopologySpec) Validate() error {
+	if len(s.CPUs) == 0 {
+		return fmt.Errorf("no cpu specified")
+	}
+	type coreLocation struct {
+		socketID int32
+		nodeID   int32
+	}
+	cpuIDs := map[int32]struct{}{}
+	coreLocations := map[int32]coreLocation{}
+	for _, cpu := range s.CPUs {
+		if cpu.CPUID < 0 || cpu.CoreID < 0 || cpu.SocketID < 0 || cpu.NodeID < 0 || (cpu.L3 != nil && *cpu.L3 < 0) {
+			return fmt.Errorf("negative id of cpu %d", cpu.CPUID)
+		}
+		if _, ok := cpuIDs[cpu.CPUID]; ok {
+			return fmt.Errorf("duplicate cpu %d", cpu.CPUID)
+		}
+		cpuIDs[cpu.CPUID] = struct{}{}
+		location := coreLocation{socketID: cpu.SocketID, nodeID: cpu.NodeID}
+		if old, ok := coreLocations[cpu.CoreID]; ok && old != location {
+			return fmt.Errorf("core %d spans multiple sockets or NUMA nodes", cpu.CoreID)
+		}
+		coreLocations[cpu.CoreID] = location
+	}
+
+	if len(s.NUMANodes) == 0 {
+		return nil
+	}
+	nodeIDs := map[int32]struct{}{}
+	for _, node := range s.NUMANodes {
+		if node.NodeID < 0 || int(node.NodeID) >= len(s.NUMANodes) {
+			return fmt.Errorf("NUMA node %d out of range [0, %d)", node.NodeID, len(s.NUMANodes))
+		}
+		if _, ok := nodeIDs[node.NodeID]; ok {
+			return fmt.Errorf("duplicate NUMA node %d", node.NodeID)
+		}
+		if node.Memory.Sign() <= 0 {
+			return fmt.Errorf("non-positive memory of NUMA node %d", node.NodeID)
+		}
+		nodeIDs[node.NodeID] = struct{}{}
+	}
+	for _, cpu := range s.CPUs {
+		if _, ok := nodeIDs[cpu.NodeID]; !ok {
+			return fmt.Errorf("NUMA node %d of cpu %d not specified", cpu.NodeID, cpu.CPUID)
+		}
+	}
+	return nil
+}
+
+// GetLocalCPUInfo returns the local cpu info according to the static topology. The basic info and the kernel
+// isolation are still detected from the node.
+func (s *StaticTopologySpec) GetLocalCPUInfo() (*LocalCPUInfo, error) {
+	processorInfos := make([]ProcessorInfo, 0, len(s.CPUs))
+	cpusPerCore := map[int32]int{}
+	for _, cpu := range s.CPUs {
+		l3 := cpu.SocketID
+		if cpu.L3 != nil {
+			l3 = *cpu.L3
+		}
+		processorInfos = append(processorInfos, ProcessorInfo{
+			CPUID:    cpu.CPUID,
+			CoreID:   cpu.CoreID,
+			SocketID: cpu.SocketID,
+			NodeID:   cpu.NodeID,
+			L1dl1il2: fmt.Sprint(cpu.CoreID),
+			L3:       l3,
+			Online:   "yes",
+		})
+		cpusPerCore[cpu.CoreID]++
+	}
+	sortProcessorInfos(processorInfos)
+
+	basicInfo, err := getCPUBasicInfo()
+	if err != nil {
+		return nil, err
+	}
+	basicInfo.HyperThreadEnabled = false
+	for _, num := range cpusPerCore {
+		if num > 1 {
+			basicInfo.HyperThreadEnabled = true
+			break
+		}
+	}
+	return &LocalCPUInfo{
+		BasicInfo:          *basicInfo,
+		ProcessorInfos:     processorInfos,
+		TotalInfo:          *calculateCPUTotalInfo(processorInfos),
+		KernelCPUIsolation: *getKernelCPUIsolation(),
+	}, nil
+}
+
+// GetNodeNUMAInfo returns the node NUMA info according to the static topology. It returns nil if no NUMA node is
+// specified. The memory usage is not tracked, so the free memory equals to the capacity.
+func (s *StaticTopologySpec) GetNodeNUMAInfo() *NodeNUMAInfo {
+	if len(s.NUMANodes) == 0 {
+		return nil
+	}
+	result := &NodeNUMAInfo{
+		MemInfoMap: map[int32]*MemInfo{},
+	}
+	for _, node := range s.NUMANodes {
+		memTotalKB := uint64(node.Memory.Value()) / 1024
+		memInfo := &MemInfo{
+			MemTotal:     memTotalKB,
+			MemFree:      memTotalKB,
+			MemAvailable: memTotalKB,
+		}
+		result.NUMAInfos = append(result.NUMAInfos, NUMAInfo{
+			NUMANodeID: node.NodeID,
+			MemInfo:    memInfo,
+		})
+		result.MemInfoMap[node.NodeID] = memInfo
+	}
+	sort.Slice(result.NUMAInfos, func(i, j int) bool {
+		return result.NUMAInfos[i].NUMANodeID < result.NUMAInfos[j].NUMANodeID
+	})
+	return result
+}
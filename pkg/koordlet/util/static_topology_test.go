@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStaticTopologySpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid yaml",
+			data: `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+- {cpu: 1, core: 0, socket: 0, node: 0, l3: 0}
+numaNodes:
+- {node: 0, memory: 8Gi}
+`,
+		},
+		{
+			name: "valid json without NUMA nodes",
+			data: `{"cpus": [{"cpu": 0, "core": 0, "socket": 0, "node": 0}, {"cpu": 1, "core": 1, "socket": 0, "node": 1}]}`,
+		},
+		{
+			name:    "unknown field",
+			data:    `{"cpus": [{"cpu": 0, "core": 0, "socket": 0, "node": 0, "thread": 0}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "no cpu",
+			data:    `cpus: []`,
+			wantErr: true,
+		},
+		{
+			name: "duplicate cpu",
+			data: `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+- {cpu: 0, core: 1, socket: 0, node: 0}
+`,
+			wantErr: true,
+		},
+		{
+			name: "core spans sockets",
+			data: `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+- {cpu: 1, core: 0, socket: 1, node: 0}
+`,
+			wantErr: true,
+		},
+		{
+			name: "NUMA node of cpu not specified",
+			data: `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+- {cpu: 1, core: 1, socket: 0, node: 1}
+numaNodes:
+- {node: 0, memory: 8Gi}
+`,
+			wantErr: true,
+		},
+		{
+			name: "NUMA node out of range",
+			data: `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+numaNodes:
+- {node: 1, memory: 8Gi}
+`,
+			wantErr: true,
+		},
+		{
+			name: "zero memory",
+			data: `cpus:
+- {cpu: 0, core: 0, socket: 0, node: 0}
+numaNodes:
+- {node: 0, memory: "0"}
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStaticTopologySpec([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+}
+
+func TestStaticTopologySpec(t *testing.T) {
+	spec, err := ParseStaticTopologySpec([]byte(`cpus:
+- {cpu: 3, core: 1, socket: 1, node: 1}
+- {cpu: 0, core: 0, socket: 0, node: 0}
+- {cpu: 2, core: 1, socket: 1, node: 1, l3: 5}
+- {cpu: 1, core: 0, socket: 0, node: 0}
+numaNodes:
+- {node: 1, memory: 2Gi}
+- {node: 0, memory: 1Gi}
+`))
+	assert.NoError(t, err)
+
+	localCPUInfo, err := spec.GetLocalCPUInfo()
+	assert.NoError(t, err)
+	var cpuIDs []int32
+	for _, p := range localCPUInfo.ProcessorInfos {
+		cpuIDs = append(cpuIDs, p.CPUID)
+	}
+	assert.Equal(t, []int32{0, 1, 2, 3}, cpuIDs)
+	assert.Equal(t, int32(1), localCPUInfo.ProcessorInfos[3].L3)
+	assert.Equal(t, int32(5), localCPUInfo.ProcessorInfos[2].L3)
+	assert.Equal(t, int32(4), localCPUInfo.TotalInfo.NumberCPUs)
+	assert.Equal(t, 2, len(localCPUInfo.TotalInfo.SocketToCPU))
+	assert.True(t, localCPUInfo.BasicInfo.HyperThreadEnabled)
+
+	nodeNUMAInfo := spec.GetNodeNUMAInfo()
+	assert.NotNil(t, nodeNUMAInfo)
+	assert.Equal(t, int32(0), nodeNUMAInfo.NUMAInfos[0].NUMANodeID)
+	assert.Equal(t, uint64(1<<30), nodeNUMAInfo.NUMAInfos[0].MemInfo.MemTotalBytes())
+	assert.Equal(t, uint64(2<<30), nodeNUMAInfo.MemInfoMap[1].MemTotalBytes())
+	assert.Equal(t, uint64(0), nodeNUMAInfo.MemInfoMap[1].MemUsageBytes())
+
+	assert.Nil(t, (&StaticTopologySpec{CPUs: spec.CPUs}).GetNodeNUMAInfo())
+}
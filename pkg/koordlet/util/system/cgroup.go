@@ -57,6 +57,9 @@ type MemoryStatRaw struct {
 	InactiveAnon int64
 	ActiveAnon   int64
 	Unevictable  int64
+	// Shmem is the shmem/tmpfs pages which are accounted in the anon LRU and can not be reclaimed without swap.
+	// It is optional in the memory.stat since some kernels do not report it.
+	Shmem int64
 	// add more fields
 }
 
@@ -159,10 +162,26 @@ func ParseMemoryStatRaw(content string) (*MemoryStatRaw, error) {
 		}
 		*t.value = v
 	}
+	if err := parseMemoryStatOptionalField(m, "total_shmem", &memoryStatRaw.Shmem); err != nil {
+		return nil, fmt.Errorf("parse memory.stat failed, raw content %s, err: %v", content, err)
+	}
 
 	return memoryStatRaw, nil
 }
 
+func parseMemoryStatOptionalField(m map[string]string, key string, value *int64) error {
+	valueStr, ok := m[key]
+	if !ok {
+		return nil
+	}
+	v, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("field %s, err: %v", key, err)
+	}
+	*value = v
+	return nil
+}
+
 func ParseMemoryNumaStat(content string) ([]NumaMemoryPages, error) {
 	stat := []NumaMemoryPages{}
 	parseErr := errors.New("parse cgroup memory numa stat err")
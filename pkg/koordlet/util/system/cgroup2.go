@@ -229,6 +229,9 @@ func ParseMemoryStatRawV2(content string) (*MemoryStatRaw, error) {
 		}
 		*t.value = v
 	}
+	if err := parseMemoryStatOptionalField(m, "shmem", &memoryStatRaw.Shmem); err != nil {
+		return nil, fmt.Errorf("parse memory.stat failed, raw content %s, err: %v", content, err)
+	}
 
 	return memoryStatRaw, nil
 }
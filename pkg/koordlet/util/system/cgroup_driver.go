@@ -65,6 +65,8 @@ type Formatter struct {
 
 	PodIDParser       func(basename string) (string, error)
 	ContainerIDParser func(basename string) (string, error)
+	// KubeQOSParser parses the kube QoS class from the pod or container cgroup dir.
+	KubeQOSParser func(cgroupDir string) corev1.PodQOSClass
 }
 
 var cgroupPathFormatterInSystemd = Formatter{
@@ -156,6 +158,9 @@ var cgroupPathFormatterInSystemd = Formatter{
 		}
 		return "", fmt.Errorf("fail to parse container id: %v", basename)
 	},
+	KubeQOSParser: func(cgroupDir string) corev1.PodQOSClass {
+		return NewDefaultCgroupLayoutConfig().parseKubeQOS(cgroupDir)
+	},
 }
 
 var cgroupPathFormatterInCgroupfs = Formatter{
@@ -196,6 +201,9 @@ var cgroupPathFormatterInCgroupfs = Formatter{
 	ContainerIDParser: func(basename string) (string, error) {
 		return basename, nil
 	},
+	KubeQOSParser: func(cgroupDir string) corev1.PodQOSClass {
+		return NewDefaultCgroupLayoutConfig().parseKubeQOS(cgroupDir)
+	},
 }
 
 // CgroupPathFormatter is the cgroup driver formatter.
@@ -212,7 +220,7 @@ func GetCgroupFormatter() Formatter {
 		return GetCgroupPathFormatter(driver)
 	}
 	klog.V(4).Infof("can not guess cgroup driver from 'kubepods' cgroup name")
-	return cgroupPathFormatterInSystemd
+	return NewCgroupPathFormatter(Systemd, Conf.GetCgroupLayoutConfig())
 }
 
 // GetCgroupDriver gets the cgroup driver both from the cgroup directory names and kubelet configs. Check kubelet
@@ -266,25 +274,19 @@ func GetCgroupDriverFromKubelet(nodeName string) (CgroupDriverType, error) {
 	return cgroupDriver, nil
 }
 
+// GetCgroupPathFormatter gets the cgroup path formatter of the cgroup driver with the configured cgroup layout.
 func GetCgroupPathFormatter(driver CgroupDriverType) Formatter {
-	switch driver {
-	case Systemd:
-		return cgroupPathFormatterInSystemd
-	case Cgroupfs:
-		return cgroupPathFormatterInCgroupfs
-	default:
+	if !driver.Validate() {
 		klog.Warningf("cgroup driver formatter not supported: '%s'", string(driver))
-		return cgroupPathFormatterInSystemd
+		driver = Systemd
 	}
+	return NewCgroupPathFormatter(driver, Conf.GetCgroupLayoutConfig())
 }
 
 func SetupCgroupPathFormatter(driver CgroupDriverType) {
-	switch driver {
-	case Systemd:
-		CgroupPathFormatter = cgroupPathFormatterInSystemd
-	case Cgroupfs:
-		CgroupPathFormatter = cgroupPathFormatterInCgroupfs
-	default:
+	if !driver.Validate() {
 		klog.Warningf("cgroup driver formatter not supported: '%s'", string(driver))
+		return
 	}
+	CgroupPathFormatter = NewCgroupPathFormatter(driver, Conf.GetCgroupLayoutConfig())
 }
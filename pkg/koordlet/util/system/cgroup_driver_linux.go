@@ -45,12 +45,13 @@ var (
 )
 
 func GetCgroupDriverFromCgroupName() CgroupDriverType {
-	isSystemd := FileExists(filepath.Join(GetRootCgroupSubfsDir(CgroupCPUDir), KubeRootNameSystemd))
+	layoutCfg := Conf.GetCgroupLayoutConfig()
+	isSystemd := FileExists(filepath.Join(GetRootCgroupSubfsDir(CgroupCPUDir), layoutCfg.GetKubeRootDir(Systemd)))
 	if isSystemd {
 		return Systemd
 	}
 
-	isCgroupfs := FileExists(filepath.Join(GetRootCgroupSubfsDir(CgroupCPUDir), KubeRootNameCgroupfs))
+	isCgroupfs := FileExists(filepath.Join(GetRootCgroupSubfsDir(CgroupCPUDir), layoutCfg.GetKubeRootDir(Cgroupfs)))
 	if isCgroupfs {
 		return Cgroupfs
 	}
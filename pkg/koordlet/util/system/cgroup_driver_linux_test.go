@@ -29,10 +29,11 @@ import (
 
 func Test_GetCgroupDriverFromCgroupName(t *testing.T) {
 	tests := []struct {
-		name       string
-		envSetup   func(cgroupRoot string)
-		isCgroupV2 bool
-		want       CgroupDriverType
+		name         string
+		envSetup     func(cgroupRoot string)
+		isCgroupV2   bool
+		kubeRootName string
+		want         CgroupDriverType
 	}{
 		{
 			name:     "neither 'kubepods' nor 'kubepods.slice' exists",
@@ -69,12 +70,30 @@ func Test_GetCgroupDriverFromCgroupName(t *testing.T) {
 			isCgroupV2: true,
 			want:       Systemd,
 		},
+		{
+			name: "custom kube root 'k8s.slice' exist",
+			envSetup: func(cgroupRoot string) {
+				os.MkdirAll(filepath.Join(cgroupRoot, "cpu", "k8s.slice"), 0755)
+			},
+			kubeRootName: "k8s",
+			want:         Systemd,
+		},
+		{
+			name: "custom kube root not exist",
+			envSetup: func(cgroupRoot string) {
+				os.MkdirAll(filepath.Join(cgroupRoot, "cpu", "kubepods.slice"), 0755)
+			},
+			kubeRootName: "k8s",
+			want:         "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			helper := NewFileTestUtil(t)
 			defer helper.Cleanup()
 			helper.SetCgroupsV2(tt.isCgroupV2)
+			Conf.CgroupKubeRootName = tt.kubeRootName
+			defer func() { Conf.CgroupKubeRootName = DefaultCgroupKubeRootName }()
 			tmpCgroupRoot := helper.TempDir
 			tt.envSetup(tmpCgroupRoot)
 			got := GetCgroupDriverFromCgroupName()
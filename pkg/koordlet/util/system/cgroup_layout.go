@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// CgroupQOSLayout is the structure of the QoS-class cgroup directories under the kube root cgroup.
+type CgroupQOSLayout string
+
+const (
+	// CgroupQOSLayoutNested places the Burstable and BestEffort pods under the QoS-level cgroups, and places the
+	// Guaranteed pods under the kube root cgroup directly, e.g. `kubepods/burstable/pod<uid>`.
+	// It is the default layout of the kubelet with `--cgroups-per-qos=true`.
+	CgroupQOSLayoutNested CgroupQOSLayout = "nested"
+	// CgroupQOSLayoutFlat places the pods of all QoS classes under the kube root cgroup directly,
+	// e.g. `kubepods/pod<uid>`.
+	CgroupQOSLayoutFlat CgroupQOSLayout = "flat"
+
+	DefaultCgroupKubeRootName   = "kubepods"
+	DefaultCgroupBurstableName  = "burstable"
+	DefaultCgroupBestEffortName = "besteffort"
+)
+
+func (l CgroupQOSLayout) Validate() bool {
+	return l == CgroupQOSLayoutNested || l == CgroupQOSLayoutFlat
+}
+
+// CgroupLayoutConfig describes the mapping of the QoS classes to the top-level cgroup directories.
+// The names are the cgroup names without the driver-specific suffix, e.g. "kubepods" for both "kubepods/" in cgroupfs
+// and "kubepods.slice/" in systemd.
+type CgroupLayoutConfig struct {
+	Layout         CgroupQOSLayout
+	KubeRootName   string
+	BurstableName  string
+	BestEffortName string
+}
+
+func NewDefaultCgroupLayoutConfig() CgroupLayoutConfig {
+	return CgroupLayoutConfig{
+		Layout:         CgroupQOSLayoutNested,
+		KubeRootName:   DefaultCgroupKubeRootName,
+		BurstableName:  DefaultCgroupBurstableName,
+		BestEffortName: DefaultCgroupBestEffortName,
+	}
+}
+
+// GetCgroupLayoutConfig returns the cgroup layout config of the koordlet, where the unset or invalid fields are
+// replaced with the defaults.
+func (c *Config) GetCgroupLayoutConfig() CgroupLayoutConfig {
+	cfg := NewDefaultCgroupLayoutConfig()
+	if layout := CgroupQOSLayout(c.CgroupQOSLayout); layout.Validate() {
+		cfg.Layout = layout
+	} else if len(layout) > 0 {
+		klog.Warningf("cgroup qos layout %q is invalid, use %q instead", layout, cfg.Layout)
+	}
+	if len(c.CgroupKubeRootName) > 0 {
+		cfg.KubeRootName = c.CgroupKubeRootName
+	}
+	if len(c.CgroupBurstableName) > 0 {
+		cfg.BurstableName = c.CgroupBurstableName
+	}
+	if len(c.CgroupBestEffortName) > 0 {
+		cfg.BestEffortName = c.CgroupBestEffortName
+	}
+	return cfg
+}
+
+// GetKubeRootDir returns the kube root cgroup directory of the cgroup driver, e.g. "kubepods.slice/" in systemd.
+func (c CgroupLayoutConfig) GetKubeRootDir(driver CgroupDriverType) string {
+	if driver == Cgroupfs {
+		return c.KubeRootName + "/"
+	}
+	return c.KubeRootName + ".slice/"
+}
+
+func (c CgroupLayoutConfig) getQOSName(qos corev1.PodQOSClass) string {
+	if c.Layout == CgroupQOSLayoutFlat {
+		return ""
+	}
+	switch qos {
+	case corev1.PodQOSBurstable:
+		return c.BurstableName
+	case corev1.PodQOSBestEffort:
+		return c.BestEffortName
+	}
+	return ""
+}
+
+// parseKubeQOS returns the QoS class of the cgroup dir according to the QoS-level cgroup names. Both the cgroupfs
+// names (e.g. "burstable") and the systemd slice names (e.g. "kubepods-burstable.slice") are accepted.
+// The flat layout has no QoS-level cgroups, so an empty QoS class is returned and the caller should fall back to the
+// QoS class of the pod.
+func (c CgroupLayoutConfig) parseKubeQOS(cgroupDir string) corev1.PodQOSClass {
+	if c.Layout == CgroupQOSLayoutFlat {
+		return ""
+	}
+	for _, qos := range []corev1.PodQOSClass{corev1.PodQOSBestEffort, corev1.PodQOSBurstable} {
+		name := c.getQOSName(qos)
+		slicePrefix := c.KubeRootName + "-" + name
+		for _, dir := range strings.Split(cgroupDir, "/") {
+			if dir == name || dir == slicePrefix+".slice" || strings.HasPrefix(dir, slicePrefix+"-") {
+				return qos
+			}
+		}
+	}
+	return corev1.PodQOSGuaranteed
+}
+
+// NewCgroupPathFormatter builds the cgroup path formatter of the cgroup driver with the layout config.
+func NewCgroupPathFormatter(driver CgroupDriverType, cfg CgroupLayoutConfig) Formatter {
+	if driver == Cgroupfs {
+		return newCgroupfsPathFormatter(cfg)
+	}
+	return newSystemdPathFormatter(cfg)
+}
+
+func newSystemdPathFormatter(cfg CgroupLayoutConfig) Formatter {
+	// systemd slices are named with the dash-separated path of their parents,
+	// e.g. "kubepods-burstable-pod<uid>.slice" under "kubepods-burstable.slice"
+	slicePrefix := func(qos corev1.PodQOSClass) string {
+		if name := cfg.getQOSName(qos); len(name) > 0 {
+			return cfg.KubeRootName + "-" + name
+		}
+		return cfg.KubeRootName
+	}
+	return Formatter{
+		ParentDir: cfg.GetKubeRootDir(Systemd),
+		QOSDirFn: func(qos corev1.PodQOSClass) string {
+			if name := cfg.getQOSName(qos); len(name) > 0 {
+				return slicePrefix(qos) + ".slice/"
+			}
+			return "/"
+		},
+		PodDirFn: func(qos corev1.PodQOSClass, podUID string) string {
+			id := strings.ReplaceAll(podUID, "-", "_")
+			return fmt.Sprintf("%s-pod%s.slice/", slicePrefix(qos), id)
+		},
+		ContainerDirFn: cgroupPathFormatterInSystemd.ContainerDirFn,
+		PodIDParser: func(basename string) (string, error) {
+			const suffix = ".slice"
+			prefixes := []string{
+				slicePrefix(corev1.PodQOSBestEffort) + "-pod",
+				slicePrefix(corev1.PodQOSBurstable) + "-pod",
+				slicePrefix(corev1.PodQOSGuaranteed) + "-pod",
+			}
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(basename, prefix) && strings.HasSuffix(basename, suffix) {
+					return basename[len(prefix) : len(basename)-len(suffix)], nil
+				}
+			}
+			return "", fmt.Errorf("fail to parse pod id: %v", basename)
+		},
+		ContainerIDParser: cgroupPathFormatterInSystemd.ContainerIDParser,
+		KubeQOSParser: func(cgroupDir string) corev1.PodQOSClass {
+			return cfg.parseKubeQOS(cgroupDir)
+		},
+	}
+}
+
+func newCgroupfsPathFormatter(cfg CgroupLayoutConfig) Formatter {
+	return Formatter{
+		ParentDir: cfg.GetKubeRootDir(Cgroupfs),
+		QOSDirFn: func(qos corev1.PodQOSClass) string {
+			if name := cfg.getQOSName(qos); len(name) > 0 {
+				return name + "/"
+			}
+			return "/"
+		},
+		PodDirFn:          cgroupPathFormatterInCgroupfs.PodDirFn,
+		ContainerDirFn:    cgroupPathFormatterInCgroupfs.ContainerDirFn,
+		PodIDParser:       cgroupPathFormatterInCgroupfs.PodIDParser,
+		ContainerIDParser: cgroupPathFormatterInCgroupfs.ContainerIDParser,
+		KubeQOSParser: func(cgroupDir string) corev1.PodQOSClass {
+			return cfg.parseKubeQOS(cgroupDir)
+		},
+	}
+}
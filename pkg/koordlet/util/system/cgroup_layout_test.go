@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfig_GetCgroupLayoutConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		conf Config
+		want CgroupLayoutConfig
+	}{
+		{
+			name: "use defaults for unset fields",
+			conf: Config{},
+			want: NewDefaultCgroupLayoutConfig(),
+		},
+		{
+			name: "use defaults for invalid layout",
+			conf: Config{CgroupQOSLayout: "unknown"},
+			want: NewDefaultCgroupLayoutConfig(),
+		},
+		{
+			name: "custom layout and names",
+			conf: Config{
+				CgroupQOSLayout:      "flat",
+				CgroupKubeRootName:   "k8s",
+				CgroupBurstableName:  "bu",
+				CgroupBestEffortName: "be",
+			},
+			want: CgroupLayoutConfig{
+				Layout:         CgroupQOSLayoutFlat,
+				KubeRootName:   "k8s",
+				BurstableName:  "bu",
+				BestEffortName: "be",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.conf.GetCgroupLayoutConfig())
+		})
+	}
+}
+
+func TestNewCgroupPathFormatter(t *testing.T) {
+	podUID := "7712555c-ce62-454a-9e18-9ff0217b8941"
+	customNames := CgroupLayoutConfig{
+		Layout:         CgroupQOSLayoutNested,
+		KubeRootName:   "k8s",
+		BurstableName:  "shared",
+		BestEffortName: "batch",
+	}
+	flat := NewDefaultCgroupLayoutConfig()
+	flat.Layout = CgroupQOSLayoutFlat
+	type wantPath struct {
+		qos     corev1.PodQOSClass
+		podDir  string
+		wantQOS corev1.PodQOSClass
+	}
+	tests := []struct {
+		name      string
+		driver    CgroupDriverType
+		cfg       CgroupLayoutConfig
+		wantPaths []wantPath
+	}{
+		{
+			name:   "systemd nested",
+			driver: Systemd,
+			cfg:    NewDefaultCgroupLayoutConfig(),
+			wantPaths: []wantPath{
+				{
+					qos:     corev1.PodQOSGuaranteed,
+					podDir:  "kubepods.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSGuaranteed,
+				},
+				{
+					qos:     corev1.PodQOSBurstable,
+					podDir:  "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSBurstable,
+				},
+				{
+					qos:     corev1.PodQOSBestEffort,
+					podDir:  "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSBestEffort,
+				},
+			},
+		},
+		{
+			name:   "cgroupfs nested",
+			driver: Cgroupfs,
+			cfg:    NewDefaultCgroupLayoutConfig(),
+			wantPaths: []wantPath{
+				{
+					qos:     corev1.PodQOSGuaranteed,
+					podDir:  "kubepods/pod7712555c-ce62-454a-9e18-9ff0217b8941",
+					wantQOS: corev1.PodQOSGuaranteed,
+				},
+				{
+					qos:     corev1.PodQOSBurstable,
+					podDir:  "kubepods/burstable/pod7712555c-ce62-454a-9e18-9ff0217b8941",
+					wantQOS: corev1.PodQOSBurstable,
+				},
+				{
+					qos:     corev1.PodQOSBestEffort,
+					podDir:  "kubepods/besteffort/pod7712555c-ce62-454a-9e18-9ff0217b8941",
+					wantQOS: corev1.PodQOSBestEffort,
+				},
+			},
+		},
+		{
+			name:   "systemd flat",
+			driver: Systemd,
+			cfg:    flat,
+			wantPaths: []wantPath{
+				{
+					qos:     corev1.PodQOSGuaranteed,
+					podDir:  "kubepods.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSGuaranteed,
+				},
+				{
+					qos:     corev1.PodQOSBestEffort,
+					podDir:  "kubepods.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSGuaranteed,
+				},
+			},
+		},
+		{
+			name:   "cgroupfs flat",
+			driver: Cgroupfs,
+			cfg:    flat,
+			wantPaths: []wantPath{
+				{
+					qos:     corev1.PodQOSBurstable,
+					podDir:  "kubepods/pod7712555c-ce62-454a-9e18-9ff0217b8941",
+					wantQOS: "",
+				},
+			},
+		},
+		{
+			name:   "systemd custom names",
+			driver: Systemd,
+			cfg:    customNames,
+			wantPaths: []wantPath{
+				{
+					qos:     corev1.PodQOSGuaranteed,
+					podDir:  "k8s.slice/k8s-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSGuaranteed,
+				},
+				{
+					qos:     corev1.PodQOSBurstable,
+					podDir:  "k8s.slice/k8s-shared.slice/k8s-shared-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSBurstable,
+				},
+				{
+					qos:     corev1.PodQOSBestEffort,
+					podDir:  "k8s.slice/k8s-batch.slice/k8s-batch-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice",
+					wantQOS: corev1.PodQOSBestEffort,
+				},
+			},
+		},
+		{
+			name:   "cgroupfs custom names",
+			driver: Cgroupfs,
+			cfg:    customNames,
+			wantPaths: []wantPath{
+				{
+					qos:     corev1.PodQOSBurstable,
+					podDir:  "k8s/shared/pod7712555c-ce62-454a-9e18-9ff0217b8941",
+					wantQOS: corev1.PodQOSBurstable,
+				},
+				{
+					qos:     corev1.PodQOSBestEffort,
+					podDir:  "k8s/batch/pod7712555c-ce62-454a-9e18-9ff0217b8941",
+					wantQOS: corev1.PodQOSBestEffort,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewCgroupPathFormatter(tt.driver, tt.cfg)
+			for _, p := range tt.wantPaths {
+				podDir := filepath.Join(f.ParentDir, f.QOSDirFn(p.qos), f.PodDirFn(p.qos, podUID))
+				assert.Equal(t, p.podDir, podDir, p.qos)
+				assert.Equal(t, p.wantQOS, f.KubeQOSParser(podDir), p.qos)
+
+				wantUID := podUID
+				if tt.driver == Systemd {
+					wantUID = strings.ReplaceAll(podUID, "-", "_")
+				}
+				gotUID, err := f.PodIDParser(filepath.Base(podDir))
+				assert.NoError(t, err)
+				assert.Equal(t, wantUID, gotUID, p.qos)
+			}
+		})
+	}
+}
@@ -138,6 +138,7 @@ const (
 
 	CPUSetCPUSName          = "cpuset.cpus"
 	CPUSetCPUSEffectiveName = "cpuset.cpus.effective"
+	CPUSetMemsName          = "cpuset.mems"
 
 	CPUAcctStatName           = "cpuacct.stat"
 	CPUAcctUsageName          = "cpuacct.usage"
@@ -161,6 +162,11 @@ const (
 	MemoryUsePriorityOomName   = "memory.use_priority_oom"
 	MemoryOomGroupName         = "memory.oom.group"
 	MemoryIdlePageStatsName    = "memory.idle_page_stats"
+	MemorySwapMaxName          = "memory.swap.max" // cgroups-v2
+	MemoryReclaimName          = "memory.reclaim"  // cgroups-v2
+
+	MemoryPagecacheLimitEnableName = "memory.pagecache_limit.enable" // anolis os
+	MemoryPagecacheLimitSizeName   = "memory.pagecache_limit.size"   // anolis os
 
 	BlkioTRIopsName   = "blkio.throttle.read_iops_device"
 	BlkioTRBpsName    = "blkio.throttle.read_bps_device"
@@ -182,6 +188,7 @@ var (
 	MemoryPriorityValidator                 = &RangeValidator{min: 0, max: 12}
 	MemoryOomGroupValidator                 = &RangeValidator{min: 0, max: 1}
 	MemoryUsePriorityOomValidator           = &RangeValidator{min: 0, max: 1}
+	MemoryPagecacheLimitEnableValidator     = &RangeValidator{min: 0, max: 1}
 	MemoryWmarkMinAdjValidator              = &RangeValidator{min: -25, max: 50}
 	MemoryWmarkScaleFactorFileNameValidator = &RangeValidator{min: 1, max: 1000}
 	BlkioTRIopsValidator                    = &BlkIORangeValidator{min: 0, max: math.MaxInt64, resource: BlkioTRIopsName}
@@ -207,7 +214,8 @@ var (
 	CPUTasks     = DefaultFactory.New(CPUTasksName, CgroupCPUDir)
 	CPUProcs     = DefaultFactory.New(CPUProcsName, CgroupCPUDir)
 
-	CPUSet = DefaultFactory.New(CPUSetCPUSName, CgroupCPUSetDir).WithValidator(CPUSetCPUSValidator)
+	CPUSet     = DefaultFactory.New(CPUSetCPUSName, CgroupCPUSetDir).WithValidator(CPUSetCPUSValidator)
+	CPUSetMems = DefaultFactory.New(CPUSetMemsName, CgroupCPUSetDir).WithValidator(CPUSetCPUSValidator)
 
 	CPUAcctStat           = DefaultFactory.New(CPUAcctStatName, CgroupCPUAcctDir)
 	CPUAcctUsage          = DefaultFactory.New(CPUAcctUsageName, CgroupCPUAcctDir)
@@ -230,6 +238,9 @@ var (
 	MemoryOomGroup         = DefaultFactory.New(MemoryOomGroupName, CgroupMemDir).WithValidator(MemoryOomGroupValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	MemoryIdlePageStats    = DefaultFactory.New(MemoryIdlePageStatsName, CgroupMemDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 
+	MemoryPagecacheLimitEnable = DefaultFactory.New(MemoryPagecacheLimitEnableName, CgroupMemDir).WithValidator(MemoryPagecacheLimitEnableValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	MemoryPagecacheLimitSize   = DefaultFactory.New(MemoryPagecacheLimitSizeName, CgroupMemDir).WithValidator(NaturalInt64Validator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
 	BlkioReadIops  = DefaultFactory.New(BlkioTRIopsName, CgroupBlkioDir).WithValidator(BlkioTRIopsValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	BlkioReadBps   = DefaultFactory.New(BlkioTRBpsName, CgroupBlkioDir).WithValidator(BlkioTRBpsValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	BlkioWriteIops = DefaultFactory.New(BlkioTWIopsName, CgroupBlkioDir).WithValidator(BlkioTWIopsValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
@@ -246,6 +257,7 @@ var (
 		CPUTasks,
 		CPUBVTWarpNs,
 		CPUSet,
+		CPUSetMems,
 		CPUAcctStat,
 		CPUAcctUsage,
 		CPUAcctCPUPressure,
@@ -266,6 +278,8 @@ var (
 		MemoryUsePriorityOom,
 		MemoryOomGroup,
 		MemoryIdlePageStats,
+		MemoryPagecacheLimitEnable,
+		MemoryPagecacheLimitSize,
 		BlkioReadIops,
 		BlkioReadBps,
 		BlkioWriteIops,
@@ -289,6 +303,7 @@ var (
 
 	CPUSetV2                 = DefaultFactory.NewV2(CPUSetCPUSName, CPUSetCPUSName).WithValidator(CPUSetCPUSValidator)
 	CPUSetEffectiveV2        = DefaultFactory.NewV2(CPUSetCPUSEffectiveName, CPUSetCPUSEffectiveName) // TODO: unify the R/W
+	CPUSetMemsV2             = DefaultFactory.NewV2(CPUSetMemsName, CPUSetMemsName).WithValidator(CPUSetCPUSValidator)
 	CPUTasksV2               = DefaultFactory.NewV2(CPUTasksName, CPUThreadsName)
 	CPUProcsV2               = DefaultFactory.NewV2(CPUProcsName, CPUProcsName)
 	MemoryLimitV2            = DefaultFactory.NewV2(MemoryLimitName, MemoryMaxName)
@@ -304,6 +319,9 @@ var (
 	MemoryPriorityV2         = DefaultFactory.NewV2(MemoryPriorityName, MemoryPriorityName).WithValidator(MemoryPriorityValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryUsePriorityOomV2   = DefaultFactory.NewV2(MemoryUsePriorityOomName, MemoryUsePriorityOomName).WithValidator(MemoryUsePriorityOomValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryOomGroupV2         = DefaultFactory.NewV2(MemoryOomGroupName, MemoryOomGroupName).WithValidator(MemoryOomGroupValidator).WithCheckSupported(SupportedIfFileExists)
+	MemoryIdlePageStatsV2    = DefaultFactory.NewV2(MemoryIdlePageStatsName, MemoryIdlePageStatsName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	MemorySwapMaxV2          = DefaultFactory.NewV2(MemorySwapMaxName, MemorySwapMaxName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	MemoryReclaimV2          = DefaultFactory.NewV2(MemoryReclaimName, MemoryReclaimName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 
 	knownCgroupV2Resources = []Resource{
 		CPUCFSQuotaV2,
@@ -319,6 +337,7 @@ var (
 		CPUAcctIOPressureV2,
 		CPUSetV2,
 		CPUSetEffectiveV2,
+		CPUSetMemsV2,
 		CPUTasksV2,
 		CPUProcsV2,
 		MemoryLimitV2,
@@ -334,6 +353,9 @@ var (
 		MemoryPriorityV2,
 		MemoryUsePriorityOomV2,
 		MemoryOomGroupV2,
+		MemoryIdlePageStatsV2,
+		MemorySwapMaxV2,
+		MemoryReclaimV2,
 		BlkioIOWeight,
 		BlkioIOQoS,
 	}
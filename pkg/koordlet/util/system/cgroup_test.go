@@ -99,6 +99,25 @@ func TestParseMemoryStatRaw(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			input: "total_cache 100\ntotal_rss 200\ntotal_inactive_file 50\ntotal_active_file 100\ntotal_inactive_anon 60\ntotal_active_anon 40\ntotal_unevictable 20\ntotal_shmem 30",
+			expected: &MemoryStatRaw{
+				Cache:        100,
+				RSS:          200,
+				InactiveFile: 50,
+				ActiveFile:   100,
+				InactiveAnon: 60,
+				ActiveAnon:   40,
+				Unevictable:  20,
+				Shmem:        30,
+			},
+			wantErr: false,
+		},
+		{
+			input:    "total_cache 100\ntotal_rss 200\ntotal_inactive_file 50\ntotal_active_file 100\ntotal_inactive_anon 60\ntotal_active_anon 40\ntotal_unevictable 20\ntotal_shmem abc", // Invalid optional field
+			expected: nil,
+			wantErr:  true,
+		},
 		{
 			input:    "total_cache 100\ninvalid_field abc", // Invalid field name
 			expected: nil,
@@ -139,6 +158,9 @@ func TestParseMemoryStatRaw(t *testing.T) {
 				if memoryStatRaw.InactiveFile != test.expected.InactiveFile {
 					t.Errorf("For input: %s, got InactiveFile: %d, want: %d", test.input, memoryStatRaw.InactiveFile, test.expected.InactiveFile)
 				}
+				if memoryStatRaw.Shmem != test.expected.Shmem {
+					t.Errorf("For input: %s, got Shmem: %d, want: %d", test.input, memoryStatRaw.Shmem, test.expected.Shmem)
+				}
 			}
 		}
 	}
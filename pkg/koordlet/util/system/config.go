@@ -34,8 +34,15 @@ var AgentMode = DS_MODE
 var UseCgroupsV2 = atomic.NewBool(false)
 
 type Config struct {
-	CgroupRootDir         string
-	CgroupKubePath        string
+	CgroupRootDir string
+	// CgroupKubePath is the kube cgroup dir. It is derived from the CgroupKubeRootName if unset.
+	CgroupKubePath string
+	// CgroupQOSLayout, CgroupKubeRootName, CgroupBurstableName and CgroupBestEffortName configure the mapping of the
+	// QoS classes to the top-level cgroup directories. See CgroupLayoutConfig.
+	CgroupQOSLayout       string
+	CgroupKubeRootName    string
+	CgroupBurstableName   string
+	CgroupBestEffortName  string
 	SysRootDir            string
 	SysFSRootDir          string
 	ProcRootDir           string
@@ -63,7 +70,10 @@ func initSupportConfigs() {
 
 func NewHostModeConfig() *Config {
 	return &Config{
-		CgroupKubePath:        "kubepods/",
+		CgroupQOSLayout:       string(CgroupQOSLayoutNested),
+		CgroupKubeRootName:    DefaultCgroupKubeRootName,
+		CgroupBurstableName:   DefaultCgroupBurstableName,
+		CgroupBestEffortName:  DefaultCgroupBestEffortName,
 		CgroupRootDir:         "/sys/fs/cgroup/",
 		ProcRootDir:           "/proc/",
 		SysRootDir:            "/sys/",
@@ -76,8 +86,11 @@ func NewHostModeConfig() *Config {
 
 func NewDsModeConfig() *Config {
 	return &Config{
-		CgroupKubePath: "kubepods/",
-		CgroupRootDir:  "/host-cgroup/",
+		CgroupQOSLayout:      string(CgroupQOSLayoutNested),
+		CgroupKubeRootName:   DefaultCgroupKubeRootName,
+		CgroupBurstableName:  DefaultCgroupBurstableName,
+		CgroupBestEffortName: DefaultCgroupBestEffortName,
+		CgroupRootDir:        "/host-cgroup/",
 		// some dirs are not covered by ns, or unused with `hostPID` is on
 		ProcRootDir:           "/proc/",
 		SysRootDir:            "/host-sys/",
@@ -92,6 +105,14 @@ func SetConf(config Config) {
 	Conf = &config
 }
 
+// GetCgroupKubePath returns the kube cgroup dir, e.g. "kubepods/".
+func (c *Config) GetCgroupKubePath() string {
+	if len(c.CgroupKubePath) > 0 {
+		return c.CgroupKubePath
+	}
+	return c.GetCgroupLayoutConfig().KubeRootName + "/"
+}
+
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.CgroupRootDir, "cgroup-root-dir", c.CgroupRootDir, "Cgroup root dir")
 	fs.StringVar(&c.SysRootDir, "sys-root-dir", c.SysRootDir, "host /sys dir in container")
@@ -100,7 +121,11 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.VarRunRootDir, "var-run-root-dir", c.VarRunRootDir, "host /var/run dir in container")
 	fs.StringVar(&c.RunRootDir, "run-root-dir", c.RunRootDir, "host /run dir in container")
 
-	fs.StringVar(&c.CgroupKubePath, "cgroup-kube-dir", c.CgroupKubePath, "Cgroup kube dir")
+	fs.StringVar(&c.CgroupKubePath, "cgroup-kube-dir", c.CgroupKubePath, "Cgroup kube dir, derived from the kube root cgroup name if unset")
+	fs.StringVar(&c.CgroupQOSLayout, "cgroup-qos-layout", c.CgroupQOSLayout, "Layout of the QoS-class cgroups under the kube root cgroup, nested or flat")
+	fs.StringVar(&c.CgroupKubeRootName, "cgroup-kube-root-name", c.CgroupKubeRootName, "Name of the kube root cgroup without the driver suffix, e.g. kubepods")
+	fs.StringVar(&c.CgroupBurstableName, "cgroup-burstable-name", c.CgroupBurstableName, "Name of the Burstable QoS cgroup without the driver suffix, e.g. burstable")
+	fs.StringVar(&c.CgroupBestEffortName, "cgroup-besteffort-name", c.CgroupBestEffortName, "Name of the BestEffort QoS cgroup without the driver suffix, e.g. besteffort")
 	fs.StringVar(&c.ContainerdEndPoint, "containerd-endpoint", c.ContainerdEndPoint, "containerd endPoint")
 	fs.StringVar(&c.DockerEndPoint, "docker-endpoint", c.DockerEndPoint, "docker endPoint")
 
@@ -24,7 +24,10 @@ import (
 
 func Test_NewDsModeConfig(t *testing.T) {
 	expectConfig := &Config{
-		CgroupKubePath:        "kubepods/",
+		CgroupQOSLayout:       "nested",
+		CgroupKubeRootName:    "kubepods",
+		CgroupBurstableName:   "burstable",
+		CgroupBestEffortName:  "besteffort",
 		CgroupRootDir:         "/host-cgroup/",
 		ProcRootDir:           "/proc/",
 		SysRootDir:            "/host-sys/",
@@ -39,7 +42,10 @@ func Test_NewDsModeConfig(t *testing.T) {
 
 func Test_NewHostModeConfig(t *testing.T) {
 	expectConfig := &Config{
-		CgroupKubePath:        "kubepods/",
+		CgroupQOSLayout:       "nested",
+		CgroupKubeRootName:    "kubepods",
+		CgroupBurstableName:   "burstable",
+		CgroupBestEffortName:  "besteffort",
 		CgroupRootDir:         "/sys/fs/cgroup/",
 		ProcRootDir:           "/proc/",
 		SysRootDir:            "/sys/",
@@ -58,3 +64,12 @@ func Test_InitFlags(t *testing.T) {
 		assert.NotNil(t, cfg)
 	})
 }
+
+func TestConfig_GetCgroupKubePath(t *testing.T) {
+	cfg := NewDsModeConfig()
+	assert.Equal(t, "kubepods/", cfg.GetCgroupKubePath())
+	cfg.CgroupKubeRootName = "kubepods-custom"
+	assert.Equal(t, "kubepods-custom/", cfg.GetCgroupKubePath())
+	cfg.CgroupKubePath = "kubepods-override/"
+	assert.Equal(t, "kubepods-override/", cfg.GetCgroupKubePath())
+}
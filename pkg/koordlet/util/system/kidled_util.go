@@ -129,16 +129,24 @@ func ParseMemoryIdlePageStats(content string) (*ColdPageInfoByKidled, error) {
 }
 
 func (i *ColdPageInfoByKidled) GetColdPageTotalBytes() uint64 {
-	sum := func(nums ...[]uint64) uint64 {
-		var total uint64
-		for _, v := range nums {
-			for _, num := range v {
-				total += num
-			}
+	return sumColdPageBytes(i.Csei, i.Dsei, i.Cfei, i.Dfei, i.Csui, i.Dsui, i.Cfui, i.Dfui, i.Csea, i.Dsea, i.Cfea, i.Dfea, i.Csua, i.Dsua, i.Cfua, i.Dfua, i.Slab)
+}
+
+// GetColdSwapBackedBytes returns the cold bytes of the swap-backed pages, i.e. the anonymous pages and the shmem/tmpfs
+// pages. Kidled does not tell the shmem pages apart from the anonymous ones, so the caller should bound it with the
+// shmem size in memory.stat to get the cold shmem pages.
+func (i *ColdPageInfoByKidled) GetColdSwapBackedBytes() uint64 {
+	return sumColdPageBytes(i.Csei, i.Dsei, i.Csui, i.Dsui, i.Csea, i.Dsea, i.Csua, i.Dsua)
+}
+
+func sumColdPageBytes(nums ...[]uint64) uint64 {
+	var total uint64
+	for _, v := range nums {
+		for _, num := range v {
+			total += num
 		}
-		return total
 	}
-	return sum(i.Csei, i.Dsei, i.Cfei, i.Dfei, i.Csui, i.Dsui, i.Cfui, i.Dfui, i.Csea, i.Dsea, i.Cfea, i.Dfea, i.Csua, i.Dsua, i.Cfua, i.Dfua, i.Slab)
+	return total
 }
 
 // check kidled and set var isSupportColdSupport
@@ -197,6 +197,8 @@ func Test_GetColdPageTotalBytes(t *testing.T) {
 	assert.NotNil(t, coldPageInfo)
 	got := coldPageInfo.GetColdPageTotalBytes()
 	assert.Equal(t, uint64(1363836928), got)
+	gotSwapBacked := coldPageInfo.GetColdSwapBackedBytes()
+	assert.Equal(t, uint64(456740864), gotSwapBacked)
 }
 
 func Test_SetKidledScanPeriodInSeconds(t *testing.T) {
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	SysClassNetSubDir = "class/net"
+
+	netDeviceLinkName   = "device"
+	netIfIndexName      = "ifindex"
+	netSpeedName        = "speed"
+	netOperStateName    = "operstate"
+	pciSRIOVTotalVFName = "sriov_totalvfs"
+	pciSRIOVNumVFName   = "sriov_numvfs"
+)
+
+// pciBusIDRegexp matches the PCI address of a device, i.e. <domain>:<bus>:<device>.<function>.
+var pciBusIDRegexp = regexp.MustCompile(`^[0-9a-f]{4,}:[0-9a-f]{2}:[0-9a-f]{2}\.[0-7]$`)
+
+// NetworkInterface is a physical NIC of the host.
+type NetworkInterface struct {
+	Name string
+	// IfIndex is the interface index assigned by the kernel, which is unique on the host.
+	IfIndex int32
+	BusID   string
+	// NUMANode is -1 if the NIC does not attach to a NUMA Node.
+	NUMANode int32
+	// SpeedMbps is -1 if the speed is unknown, e.g. the link is down.
+	SpeedMbps int64
+	OperState string
+	// TotalVFs is the max number of the VFs, which is 0 if the NIC is not SR-IOV capable.
+	TotalVFs int32
+	NumVFs   int32
+}
+
+func GetSysClassNetDir() string {
+	return filepath.Join(Conf.SysRootDir, SysClassNetSubDir)
+}
+
+// GetNetworkInterfaces detects the physical NICs of the host, and returns them sorted by the name.
+// The virtual interfaces (e.g. veth, bridge), the non-PCI devices (e.g. virtio) and the VFs of the SR-IOV NICs are
+// ignored.
+func GetNetworkInterfaces() ([]NetworkInterface, error) {
+	netDir := GetSysClassNetDir()
+	entries, err := os.ReadDir(netDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []NetworkInterface
+	for _, entry := range entries {
+		name := entry.Name()
+		deviceLink, err := os.Readlink(filepath.Join(netDir, name, netDeviceLinkName))
+		if err != nil {
+			// not a physical NIC
+			continue
+		}
+		busID := filepath.Base(deviceLink)
+		if !pciBusIDRegexp.MatchString(busID) {
+			// not a PCI device
+			continue
+		}
+		deviceDir := filepath.Join(netDir, name, netDeviceLinkName)
+		if _, err = os.Lstat(filepath.Join(deviceDir, pciPhysFnLinkName)); err == nil {
+			// a VF, which is counted by its PF
+			continue
+		}
+		numaNode, err := readPCIDeviceNUMANode(deviceDir)
+		if errors.Is(err, os.ErrNotExist) {
+			// the kernel without NUMA support does not expose the numa_node
+			numaNode = -1
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read NUMA Node of NIC %s, err: %w", name, err)
+		}
+		nic := NetworkInterface{
+			Name:      name,
+			IfIndex:   int32(readIntFile(filepath.Join(netDir, name, netIfIndexName))),
+			BusID:     busID,
+			NUMANode:  numaNode,
+			SpeedMbps: readNetworkInterfaceSpeed(filepath.Join(netDir, name)),
+			OperState: readTrimmedFile(filepath.Join(netDir, name, netOperStateName)),
+			TotalVFs:  int32(readIntFile(filepath.Join(deviceDir, pciSRIOVTotalVFName))),
+			NumVFs:    int32(readIntFile(filepath.Join(deviceDir, pciSRIOVNumVFName))),
+		}
+		result = append(result, nic)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// readNetworkInterfaceSpeed returns the speed in Mbps. Reading the speed fails with EINVAL when the link is down,
+// and some drivers report -1 or 0 for the unknown speed.
+func readNetworkInterfaceSpeed(interfaceDir string) int64 {
+	content, err := os.ReadFile(filepath.Join(interfaceDir, netSpeedName))
+	if err != nil {
+		return -1
+	}
+	speed, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil || speed <= 0 {
+		return -1
+	}
+	return speed
+}
+
+func readTrimmedFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func readIntFile(path string) int64 {
+	value, err := strconv.ParseInt(readTrimmedFile(path), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
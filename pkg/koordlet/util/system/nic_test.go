@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNetworkInterfaces(t *testing.T) {
+	type fakeNIC struct {
+		name      string
+		ifIndex   string
+		busID     string
+		numaNode  string
+		speed     string
+		operState string
+		totalVFs  string
+		numVFs    string
+		physFn    string
+	}
+	tests := []struct {
+		name    string
+		nics    []fakeNIC
+		want    []NetworkInterface
+		wantErr bool
+	}{
+		{
+			name: "no network interfaces",
+			nics: nil,
+			want: nil,
+		},
+		{
+			name: "ignore virtual interfaces and VFs",
+			nics: []fakeNIC{
+				{name: "lo"},
+				{name: "eth1", ifIndex: "3", busID: "0000:af:00.0", numaNode: "1", speed: "-1", operState: "down"},
+				{name: "eth0", ifIndex: "2", busID: "0000:3b:00.0", numaNode: "0", speed: "25000", operState: "up", totalVFs: "64", numVFs: "8"},
+				{name: "eth0v0", ifIndex: "4", busID: "0000:3b:02.0", numaNode: "0", speed: "25000", operState: "up", physFn: "0000:3b:00.0"},
+			},
+			want: []NetworkInterface{
+				{Name: "eth0", IfIndex: 2, BusID: "0000:3b:00.0", NUMANode: 0, SpeedMbps: 25000, OperState: "up", TotalVFs: 64, NumVFs: 8},
+				{Name: "eth1", IfIndex: 3, BusID: "0000:af:00.0", NUMANode: 1, SpeedMbps: -1, OperState: "down"},
+			},
+		},
+		{
+			name: "ignore non-PCI devices and tolerate the missing NUMA Node",
+			nics: []fakeNIC{
+				{name: "eth0", ifIndex: "2", busID: "virtio0", speed: "-1", operState: "up"},
+				{name: "eth1", ifIndex: "3", busID: "0000:00:05.0", speed: "10000", operState: "up"},
+			},
+			want: []NetworkInterface{
+				{Name: "eth1", IfIndex: 3, BusID: "0000:00:05.0", NUMANode: -1, SpeedMbps: 10000, OperState: "up"},
+			},
+		},
+		{
+			name: "failed to parse NUMA Node",
+			nics: []fakeNIC{
+				{name: "eth0", busID: "0000:3b:00.0", numaNode: "invalid", speed: "25000", operState: "up"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			devicesDir := GetSysPCIDevicesDir()
+			netDir := GetSysClassNetDir()
+			for _, nic := range tt.nics {
+				interfaceDir := filepath.Join(netDir, nic.name)
+				helper.MkDirAll(interfaceDir)
+				if nic.busID == "" {
+					continue
+				}
+				deviceDir := filepath.Join(devicesDir, nic.busID)
+				helper.MkDirAll(deviceDir)
+				if nic.numaNode != "" {
+					helper.WriteFileContents(filepath.Join(deviceDir, pciNUMANodeName), nic.numaNode)
+				}
+				if nic.totalVFs != "" {
+					helper.WriteFileContents(filepath.Join(deviceDir, pciSRIOVTotalVFName), nic.totalVFs)
+					helper.WriteFileContents(filepath.Join(deviceDir, pciSRIOVNumVFName), nic.numVFs)
+				}
+				if nic.physFn != "" {
+					assert.NoError(t, os.Symlink(filepath.Join("..", nic.physFn), filepath.Join(deviceDir, pciPhysFnLinkName)))
+				}
+				assert.NoError(t, os.Symlink(deviceDir, filepath.Join(interfaceDir, netDeviceLinkName)))
+				helper.WriteFileContents(filepath.Join(interfaceDir, netIfIndexName), nic.ifIndex)
+				helper.WriteFileContents(filepath.Join(interfaceDir, netSpeedName), nic.speed)
+				helper.WriteFileContents(filepath.Join(interfaceDir, netOperStateName), nic.operState)
+			}
+
+			got, err := GetNetworkInterfaces()
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"sync"
 
 	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/faultinjection"
 )
 
 const (
@@ -81,6 +83,10 @@ func isKernelSupportResctrl() (bool, error) {
 }
 
 func IsSupportResctrl() (bool, error) {
+	if err := faultinjection.Trigger(faultinjection.ResctrlMissing, GetResctrlSubsystemDirPath()); err != nil {
+		klog.V(4).Infof("resctrl is considered as unsupported, err: %v", err)
+		return false, nil
+	}
 	initLock.Lock()
 	defer initLock.Unlock()
 	if !isInit {
@@ -213,6 +219,20 @@ func (r *ResctrlSchemataRaw) WithMB(valueOrPercent string) *ResctrlSchemataRaw {
 	return r
 }
 
+// WithMBs sets the mba value or percent of each L3 domain, whose number is taken as the L3 number.
+func (r *ResctrlSchemataRaw) WithMBs(valuesOrPercents []string) *ResctrlSchemataRaw {
+	r.L3Num = len(valuesOrPercents)
+	r.MB = make([]int64, r.L3Num)
+	for i, valueOrPercent := range valuesOrPercents {
+		percentValue, err := strconv.ParseInt(strings.TrimSpace(valueOrPercent), 10, 64)
+		if err != nil {
+			klog.V(5).Infof("failed to parse mba %s of domain %d, err: %v", valueOrPercent, i, err)
+		}
+		r.MB[i] = percentValue
+	}
+	return r
+}
+
 func (r *ResctrlSchemataRaw) DeepCopy() *ResctrlSchemataRaw {
 	n := NewResctrlSchemataRaw().WithL3Num(r.L3Num)
 	for i := range r.L3 {
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	SysPCIDevicesSubDir = "bus/pci/devices"
+
+	pciPhysFnLinkName = "physfn"
+	pciNUMANodeName   = "numa_node"
+)
+
+// SRIOVPhysicalFunction is a PCI physical function (PF) with the virtual functions (VFs) enabled.
+type SRIOVPhysicalFunction struct {
+	BusID    string
+	NUMANode int32
+	NumVFs   int32
+	// VFBusIDs are the sorted bus ids of the VFs.
+	VFBusIDs []string
+}
+
+func GetSysPCIDevicesDir() string {
+	return filepath.Join(Conf.SysRootDir, SysPCIDevicesSubDir)
+}
+
+// GetSRIOVPhysicalFunctions detects the PFs of the VFs on the host by resolving the `physfn` links of the VFs, and
+// returns the PFs sorted by the bus id. The PFs without a valid NUMA Node (e.g. the `numa_node` is -1) are ignored
+// since they cannot constrain the CPU allocation.
+func GetSRIOVPhysicalFunctions() ([]SRIOVPhysicalFunction, error) {
+	devicesDir := GetSysPCIDevicesDir()
+	entries, err := os.ReadDir(devicesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	pfs := map[string]*SRIOVPhysicalFunction{}
+	for _, entry := range entries {
+		pfLink, err := os.Readlink(filepath.Join(devicesDir, entry.Name(), pciPhysFnLinkName))
+		if err != nil {
+			// not a VF
+			continue
+		}
+		pfBusID := filepath.Base(pfLink)
+		if pf, ok := pfs[pfBusID]; ok {
+			pf.NumVFs++
+			pf.VFBusIDs = append(pf.VFBusIDs, entry.Name())
+			continue
+		}
+		numaNode, err := readPCIDeviceNUMANode(filepath.Join(devicesDir, pfBusID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NUMA Node of PF %s, err: %w", pfBusID, err)
+		}
+		pfs[pfBusID] = &SRIOVPhysicalFunction{
+			BusID:    pfBusID,
+			NUMANode: numaNode,
+			NumVFs:   1,
+			VFBusIDs: []string{entry.Name()},
+		}
+	}
+
+	result := make([]SRIOVPhysicalFunction, 0, len(pfs))
+	for _, pf := range pfs {
+		if pf.NUMANode < 0 {
+			continue
+		}
+		sort.Strings(pf.VFBusIDs)
+		result = append(result, *pf)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].BusID < result[j].BusID
+	})
+	return result, nil
+}
+
+func readPCIDeviceNUMANode(deviceDir string) (int32, error) {
+	content, err := os.ReadFile(filepath.Join(deviceDir, pciNUMANodeName))
+	if err != nil {
+		return -1, err
+	}
+	numaNode, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 32)
+	if err != nil {
+		return -1, err
+	}
+	return int32(numaNode), nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSRIOVPhysicalFunctions(t *testing.T) {
+	type fakePCIDevice struct {
+		busID    string
+		numaNode string
+		physFn   string
+	}
+	tests := []struct {
+		name    string
+		devices []fakePCIDevice
+		want    []SRIOVPhysicalFunction
+		wantErr bool
+	}{
+		{
+			name:    "no pci devices",
+			devices: nil,
+			want:    nil,
+		},
+		{
+			name: "no sriov devices",
+			devices: []fakePCIDevice{
+				{busID: "0000:3b:00.0", numaNode: "0"},
+			},
+			want: []SRIOVPhysicalFunction{},
+		},
+		{
+			name: "detect PFs of the VFs",
+			devices: []fakePCIDevice{
+				{busID: "0000:3b:00.0", numaNode: "0"},
+				{busID: "0000:3b:02.0", numaNode: "0", physFn: "0000:3b:00.0"},
+				{busID: "0000:3b:02.1", numaNode: "0", physFn: "0000:3b:00.0"},
+				{busID: "0000:af:00.0", numaNode: "1"},
+				{busID: "0000:af:02.0", numaNode: "1", physFn: "0000:af:00.0"},
+				{busID: "0000:d8:00.0", numaNode: "1"},
+			},
+			want: []SRIOVPhysicalFunction{
+				{BusID: "0000:3b:00.0", NUMANode: 0, NumVFs: 2, VFBusIDs: []string{"0000:3b:02.0", "0000:3b:02.1"}},
+				{BusID: "0000:af:00.0", NUMANode: 1, NumVFs: 1, VFBusIDs: []string{"0000:af:02.0"}},
+			},
+		},
+		{
+			name: "ignore PF without NUMA Node",
+			devices: []fakePCIDevice{
+				{busID: "0000:3b:00.0", numaNode: "-1"},
+				{busID: "0000:3b:02.0", numaNode: "-1", physFn: "0000:3b:00.0"},
+			},
+			want: []SRIOVPhysicalFunction{},
+		},
+		{
+			name: "failed to parse NUMA Node",
+			devices: []fakePCIDevice{
+				{busID: "0000:3b:00.0", numaNode: "invalid"},
+				{busID: "0000:3b:02.0", numaNode: "0", physFn: "0000:3b:00.0"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			devicesDir := GetSysPCIDevicesDir()
+			for _, d := range tt.devices {
+				helper.WriteFileContents(filepath.Join(devicesDir, d.busID, pciNUMANodeName), d.numaNode)
+				if d.physFn != "" {
+					err := os.Symlink(filepath.Join("..", d.physFn), filepath.Join(devicesDir, d.busID, pciPhysFnLinkName))
+					assert.NoError(t, err)
+				}
+			}
+
+			got, err := GetSRIOVPhysicalFunctions()
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -32,10 +32,16 @@ import (
 
 const (
 	ProcStatName          = "stat"
+	ProcSchedStatName     = "schedstat"
+	ProcPIDSchedName      = "sched"
+	ProcPressureSubDir    = "pressure"
 	ProcMemInfoName       = "meminfo"
+	ProcVMStatName        = "vmstat"
 	SysctlSubDir          = "sys"
 	ProcCPUInfoName       = "cpuinfo"
 	KernelCmdlineFileName = "cmdline"
+	ProcIRQSubDir         = "irq"
+	IRQSMPAffinityList    = "smp_affinity_list"
 
 	KernelSchedGroupIdentityEnable = "kernel/sched_group_identity_enabled"
 
@@ -43,6 +49,7 @@ const (
 
 	SysCPUSMTActiveSubPath       = "devices/system/cpu/smt/active"
 	SysIntelPStateNoTurboSubPath = "devices/system/cpu/intel_pstate/no_turbo"
+	SysCPUVulnerabilitiesSubDir  = "devices/system/cpu/vulnerabilities"
 )
 
 var (
@@ -114,6 +121,22 @@ func GetSysIntelPStateNoTurboPath() string {
 	return filepath.Join(Conf.SysRootDir, SysIntelPStateNoTurboSubPath)
 }
 
+func GetSysCPUVulnerabilitiesDir() string {
+	return filepath.Join(Conf.SysRootDir, SysCPUVulnerabilitiesSubDir)
+}
+
+func GetKernelCmdlinePath() string {
+	return filepath.Join(Conf.ProcRootDir, KernelCmdlineFileName)
+}
+
+func GetProcIRQDir() string {
+	return filepath.Join(Conf.ProcRootDir, ProcIRQSubDir)
+}
+
+func GetIRQSMPAffinityListPath(irq string) string {
+	return filepath.Join(Conf.ProcRootDir, ProcIRQSubDir, irq, IRQSMPAffinityList)
+}
+
 func GetProcSysFilePath(file string) string {
 	return filepath.Join(Conf.ProcRootDir, SysctlSubDir, file)
 }
@@ -166,3 +189,30 @@ func SetSchedGroupIdentity(enable bool) error {
 	klog.V(4).Infof("SetSchedGroupIdentity set sysctl config successfully, value %v", v)
 	return nil
 }
+
+// GetSwapIOPages returns the total pages swapped in and out by the node since boot, i.e. the pswpin plus the pswpout
+// of the /proc/vmstat.
+func GetSwapIOPages() (uint64, error) {
+	data, err := os.ReadFile(GetProcFilePath(ProcVMStatName))
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	found := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || (fields[0] != "pswpin" && fields[0] != "pswpout") {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse vmstat %s, err: %v", line, err)
+		}
+		total += v
+		found++
+	}
+	if found < 2 {
+		return 0, fmt.Errorf("swap io stats not found in %s", ProcVMStatName)
+	}
+	return total, nil
+}
@@ -76,3 +76,44 @@ func TestSetSchedGroupIdentity(t *testing.T) {
 		assert.Equal(t, got, testContent)
 	})
 }
+
+func TestGetSwapIOPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name:    "vmstat not exist",
+			wantErr: true,
+		},
+		{
+			name:    "parse swap io pages",
+			content: "nr_free_pages 1024\npswpin 100\npswpout 200\npgfault 300\n",
+			want:    300,
+		},
+		{
+			name:    "swap io stats missing",
+			content: "nr_free_pages 1024\npgfault 300\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid swap io stats",
+			content: "pswpin abc\npswpout 200\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.content != "" {
+				helper.WriteProcSubFileContents(ProcVMStatName, tt.content)
+			}
+			got, err := GetSwapIOPages()
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// TopologyCollector collects the cpu topology and the memory information of the node, which is implemented
+// separately for each OS, e.g. lscpu and procfs for Linux, GetLogicalProcessorInformationEx for Windows.
+type TopologyCollector interface {
+	// GetLocalCPUInfo returns the local cpu info for cpuset allocation, NUMA-aware scheduling.
+	GetLocalCPUInfo() (*LocalCPUInfo, error)
+	// GetMemInfo returns the memory info of the node.
+	GetMemInfo() (*MemInfo, error)
+	// GetNodeNUMAInfo returns the memory info of each NUMA node.
+	GetNodeNUMAInfo() (*NodeNUMAInfo, error)
+}
+
+var topologyCollector TopologyCollector = newTopologyCollector()
+
+// GetLocalCPUInfo returns the local cpu info for cpuset allocation, NUMA-aware scheduling
+func GetLocalCPUInfo() (*LocalCPUInfo, error) {
+	return topologyCollector.GetLocalCPUInfo()
+}
+
+// GetMemInfo returns the memory info of the node. The unit of each field is KiB.
+func GetMemInfo() (*MemInfo, error) {
+	return topologyCollector.GetMemInfo()
+}
+
+// GetNodeNUMAInfo gets the node NUMA information.
+func GetNodeNUMAInfo() (*NodeNUMAInfo, error) {
+	return topologyCollector.GetNodeNUMAInfo()
+}
@@ -0,0 +1,130 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// linuxTopologyCollector collects the topology with lscpu and the procfs/sysfs.
+type linuxTopologyCollector struct{}
+
+func newTopologyCollector() TopologyCollector {
+	return &linuxTopologyCollector{}
+}
+
+func (c *linuxTopologyCollector) GetLocalCPUInfo() (*LocalCPUInfo, error) {
+	lsCPUStr, err := lsCPU("-e=CPU,NODE,SOCKET,CORE,CACHE,ONLINE")
+	if err != nil {
+		return nil, err
+	}
+	processorInfos, err := getProcessorInfos(lsCPUStr)
+	if err != nil {
+		return nil, err
+	}
+	totalInfo := calculateCPUTotalInfo(processorInfos)
+	basicInfo, err := getCPUBasicInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &LocalCPUInfo{
+		BasicInfo:          *basicInfo,
+		ProcessorInfos:     processorInfos,
+		TotalInfo:          *totalInfo,
+		KernelCPUIsolation: *getKernelCPUIsolation(),
+	}, nil
+}
+
+func (c *linuxTopologyCollector) GetMemInfo() (*MemInfo, error) {
+	memInfoPath := system.GetProcFilePath(system.ProcMemInfoName)
+	memInfo, err := readMemInfo(memInfoPath, false)
+	if err != nil {
+		return nil, err
+	}
+	return memInfo, nil
+}
+
+// GetNodeNUMAInfo gets the node NUMA information with the pre-configured sysfs path.
+func (c *linuxTopologyCollector) GetNodeNUMAInfo() (*NodeNUMAInfo, error) {
+	numaNodeParentDir := system.GetSysNUMADir()
+	nodeDirs, err := os.ReadDir(numaNodeParentDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NUMA dir, err: %w", err)
+	}
+
+	result := &NodeNUMAInfo{
+		MemInfoMap: map[int32]*MemInfo{},
+	}
+	maxNodeID := int32(-1)
+	for _, n := range nodeDirs {
+		dirName := n.Name() // assert string pattern `nodeX`
+		if len(dirName) < 4 || dirName[:4] != "node" {
+			klog.V(4).Infof("failed to get node NUMA info, err: invalid dir name %s", dirName)
+			continue
+		}
+
+		nodeIDRaw, err := strconv.ParseInt(dirName[4:], 10, 32)
+		if err != nil {
+			klog.V(4).Infof("failed to parse NUMA ID, err: invalid dir name %s, err %v", dirName, err)
+			continue
+		}
+		nodeID := int32(nodeIDRaw)
+
+		numaMemInfoPath := system.GetNUMAMemInfoPath(dirName)
+		memInfo, err := readMemInfo(numaMemInfoPath, true)
+		if err != nil {
+			klog.V(4).Infof("failed to read NUMA info, dir %s, err: %v", dirName, err)
+			continue
+		}
+
+		numaInfo := NUMAInfo{
+			NUMANodeID: nodeID,
+			MemInfo:    memInfo,
+		}
+		result.NUMAInfos = append(result.NUMAInfos, numaInfo)
+		result.MemInfoMap[nodeID] = memInfo
+		if nodeID > maxNodeID {
+			maxNodeID = nodeID
+		}
+	}
+
+	if len(nodeDirs) != len(result.NUMAInfos) {
+		return nil, fmt.Errorf("invalid number of NUMA meminfo, dir %v, parsed %v",
+			len(nodeDirs), len(result.NUMAInfos))
+	}
+	if len(result.NUMAInfos) != int(maxNodeID+1) {
+		return nil, fmt.Errorf("unexpected number of NUMA node, max ID %v, parsed %v",
+			maxNodeID, len(result.NUMAInfos))
+	}
+
+	// sort NUMA infos by the order of node id
+	sort.Slice(result.NUMAInfos, func(i, j int) bool {
+		return result.NUMAInfos[i].NUMANodeID < result.NUMAInfos[j].NUMANodeID
+	})
+
+	return result, nil
+}
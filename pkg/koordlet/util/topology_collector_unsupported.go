@@ -0,0 +1,43 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type unsupportedTopologyCollector struct{}
+
+func newTopologyCollector() TopologyCollector {
+	return &unsupportedTopologyCollector{}
+}
+
+func (c *unsupportedTopologyCollector) GetLocalCPUInfo() (*LocalCPUInfo, error) {
+	return nil, fmt.Errorf("collecting cpu info is not supported on %s", runtime.GOOS)
+}
+
+func (c *unsupportedTopologyCollector) GetMemInfo() (*MemInfo, error) {
+	return nil, fmt.Errorf("collecting memory info is not supported on %s", runtime.GOOS)
+}
+
+func (c *unsupportedTopologyCollector) GetNodeNUMAInfo() (*NodeNUMAInfo, error) {
+	return nil, fmt.Errorf("collecting NUMA info is not supported on %s", runtime.GOOS)
+}
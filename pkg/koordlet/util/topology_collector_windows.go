@@ -0,0 +1,172 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const centralProcessorRegistryKey = `HARDWARE\DESCRIPTION\System\CentralProcessor\0`
+
+var (
+	modkernel32                          = windows.NewLazySystemDLL("kernel32.dll")
+	procGetLogicalProcessorInformationEx = modkernel32.NewProc("GetLogicalProcessorInformationEx")
+	procGlobalMemoryStatusEx             = modkernel32.NewProc("GlobalMemoryStatusEx")
+	procGetNumaAvailableMemoryNodeEx     = modkernel32.NewProc("GetNumaAvailableMemoryNodeEx")
+)
+
+// memoryStatusEx is the MEMORYSTATUSEX used by GlobalMemoryStatusEx.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// windowsTopologyCollector collects the topology with the Win32 APIs.
+type windowsTopologyCollector struct{}
+
+func newTopologyCollector() TopologyCollector {
+	return &windowsTopologyCollector{}
+}
+
+func (c *windowsTopologyCollector) GetLocalCPUInfo() (*LocalCPUInfo, error) {
+	buf, err := getLogicalProcessorInformationEx()
+	if err != nil {
+		return nil, err
+	}
+	processorInfos, err := parseLogicalProcessorInformationEx(buf)
+	if err != nil {
+		return nil, err
+	}
+	totalInfo := calculateCPUTotalInfo(processorInfos)
+	return &LocalCPUInfo{
+		BasicInfo:      *getWindowsCPUBasicInfo(totalInfo),
+		ProcessorInfos: processorInfos,
+		TotalInfo:      *totalInfo,
+	}, nil
+}
+
+func (c *windowsTopologyCollector) GetMemInfo() (*MemInfo, error) {
+	status := memoryStatusEx{}
+	status.Length = uint32(unsafe.Sizeof(status))
+	if r, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status))); r == 0 {
+		return nil, fmt.Errorf("GlobalMemoryStatusEx failed, err: %w", err)
+	}
+	return &MemInfo{
+		MemTotal:     status.TotalPhys / 1024,
+		MemFree:      status.AvailPhys / 1024,
+		MemAvailable: status.AvailPhys / 1024,
+		SwapTotal:    (status.TotalPageFile - status.TotalPhys) / 1024,
+		SwapFree:     (status.AvailPageFile - status.AvailPhys) / 1024,
+	}, nil
+}
+
+// GetNodeNUMAInfo gets the available memory of each NUMA node. Since Windows does not expose the total memory of
+// a NUMA node, the total memory of the node is divided among the NUMA nodes by their number of cpus.
+func (c *windowsTopologyCollector) GetNodeNUMAInfo() (*NodeNUMAInfo, error) {
+	cpuInfo, err := c.GetLocalCPUInfo()
+	if err != nil {
+		return nil, err
+	}
+	memInfo, err := c.GetMemInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NodeNUMAInfo{
+		MemInfoMap: map[int32]*MemInfo{},
+	}
+	numCPUs := uint64(cpuInfo.TotalInfo.NumberCPUs)
+	for nodeID := int32(0); nodeID < int32(len(cpuInfo.TotalInfo.NodeToCPU)); nodeID++ {
+		cpus, ok := cpuInfo.TotalInfo.NodeToCPU[nodeID]
+		if !ok {
+			return nil, fmt.Errorf("unexpected NUMA node id, missing node %v", nodeID)
+		}
+		var availableBytes uint64
+		if r, _, err := procGetNumaAvailableMemoryNodeEx.Call(uintptr(nodeID), uintptr(unsafe.Pointer(&availableBytes))); r == 0 {
+			return nil, fmt.Errorf("GetNumaAvailableMemoryNodeEx failed, node %v, err: %w", nodeID, err)
+		}
+		numaMemInfo := &MemInfo{
+			MemTotal:     memInfo.MemTotal * uint64(len(cpus)) / numCPUs,
+			MemFree:      availableBytes / 1024,
+			MemAvailable: availableBytes / 1024,
+		}
+		result.NUMAInfos = append(result.NUMAInfos, NUMAInfo{
+			NUMANodeID: nodeID,
+			MemInfo:    numaMemInfo,
+		})
+		result.MemInfoMap[nodeID] = numaMemInfo
+	}
+	return result, nil
+}
+
+func getLogicalProcessorInformationEx() ([]byte, error) {
+	var length uint32
+	// the first call gets the required buffer length
+	r, _, err := procGetLogicalProcessorInformationEx.Call(relationAll, 0, uintptr(unsafe.Pointer(&length)))
+	if r == 0 && err != windows.ERROR_INSUFFICIENT_BUFFER {
+		return nil, fmt.Errorf("GetLogicalProcessorInformationEx failed, err: %w", err)
+	}
+	if length == 0 {
+		return nil, fmt.Errorf("GetLogicalProcessorInformationEx returns empty buffer")
+	}
+	buf := make([]byte, length)
+	r, _, err = procGetLogicalProcessorInformationEx.Call(relationAll, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&length)))
+	if r == 0 {
+		return nil, fmt.Errorf("GetLogicalProcessorInformationEx failed, err: %w", err)
+	}
+	return buf[:length], nil
+}
+
+func getWindowsCPUBasicInfo(totalInfo *CPUTotalInfo) *extension.CPUBasicInfo {
+	cpuBasicInfo := &extension.CPUBasicInfo{}
+	for _, cpus := range totalInfo.CoreToCPU {
+		if len(cpus) > 1 {
+			cpuBasicInfo.HyperThreadEnabled = true
+			break
+		}
+	}
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, centralProcessorRegistryKey, registry.QUERY_VALUE)
+	if err != nil {
+		klog.V(4).Infof("open registry key %s error: %v", centralProcessorRegistryKey, err)
+		return cpuBasicInfo
+	}
+	defer key.Close()
+	if cpuBasicInfo.CPUModel, _, err = key.GetStringValue("ProcessorNameString"); err != nil {
+		klog.V(4).Infof("get cpu model error: %v", err)
+	}
+	if cpuBasicInfo.VendorID, _, err = key.GetStringValue("VendorIdentifier"); err != nil {
+		klog.V(5).Infof("get cpu vendor error: %v", err)
+	}
+	return cpuBasicInfo
+}
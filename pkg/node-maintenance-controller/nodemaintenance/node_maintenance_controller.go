@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	"github.com/koordinator-sh/koordinator/pkg/util/fieldindex"
+)
+
+const Name = "nodemaintenance"
+
+const (
+	// finalizerNodeMaintenance uncordons the node before the NodeMaintenance is deleted.
+	finalizerNodeMaintenance = "scheduling.koordinator.sh/node-maintenance"
+	// annotationNodeCordoned marks the node is cordoned by the NodeMaintenance, so that the node cordoned by others
+	// is kept unschedulable after the maintenance.
+	annotationNodeCordoned = "scheduling.koordinator.sh/node-cordoned"
+
+	// drainSyncInterval is the interval to refresh the progress since the pods are not watched.
+	drainSyncInterval = 30 * time.Second
+)
+
+// NodeMaintenanceReconciler cordons and labels the node of the NodeMaintenance to drain it in the coordinated mode,
+// tracks the pods bound to CPUs or NUMA nodes left on the node, and uncordons the node when the maintenance is deleted.
+type NodeMaintenanceReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=nodemaintenances,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=nodemaintenances/status,verbs=get;update;patch
+
+func (r *NodeMaintenanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	maintenance := &schedulingv1alpha1.NodeMaintenance{}
+	if err := r.Client.Get(ctx, req.NamespacedName, maintenance); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("failed to find node maintenance %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !maintenance.DeletionTimestamp.IsZero() {
+		if err := r.finishMaintenance(ctx, maintenance); err != nil {
+			klog.Errorf("failed to finish node maintenance %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(maintenance, finalizerNodeMaintenance) {
+		controllerutil.AddFinalizer(maintenance, finalizerNodeMaintenance)
+		if err := r.Client.Update(ctx, maintenance); err != nil {
+			klog.Errorf("failed to add finalizer to node maintenance %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	status, err := r.drainNode(ctx, maintenance)
+	if err != nil {
+		klog.Errorf("failed to drain node for maintenance %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if err := r.updateStatus(ctx, maintenance, status); err != nil {
+		klog.Errorf("failed to update node maintenance %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if status.Phase == schedulingv1alpha1.NodeMaintenanceDrained {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: drainSyncInterval}, nil
+}
+
+// drainNode cordons and labels the node, and counts the bound pods left on the node.
+func (r *NodeMaintenanceReconciler) drainNode(ctx context.Context, maintenance *schedulingv1alpha1.NodeMaintenance) (*schedulingv1alpha1.NodeMaintenanceStatus, error) {
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: maintenance.Spec.NodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return &schedulingv1alpha1.NodeMaintenanceStatus{
+				Phase:   schedulingv1alpha1.NodeMaintenancePending,
+				Message: fmt.Sprintf("node %s is not found", maintenance.Spec.NodeName),
+			}, nil
+		}
+		return nil, err
+	}
+
+	if !extension.IsNodeCoordinatedDraining(node) {
+		if !node.Spec.Unschedulable && maintenance.Annotations[annotationNodeCordoned] != "true" {
+			// remember the node is cordoned by the maintenance before cordoning it
+			patch := client.MergeFrom(maintenance.DeepCopy())
+			if maintenance.Annotations == nil {
+				maintenance.Annotations = map[string]string{}
+			}
+			maintenance.Annotations[annotationNodeCordoned] = "true"
+			if err := r.Client.Patch(ctx, maintenance, patch); err != nil {
+				return nil, fmt.Errorf("failed to mark node cordoned, err: %w", err)
+			}
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Spec.Unschedulable = true
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[extension.LabelNodeCoordinatedDrain] = "true"
+		if err := r.Client.Patch(ctx, node, patch); err != nil {
+			return nil, fmt.Errorf("failed to cordon node, err: %w", err)
+		}
+		klog.V(4).Infof("nodemaintenance-controller cordoned node %s for maintenance %s", node.Name, maintenance.Name)
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.MatchingFields{fieldindex.IndexPodByNodeName: node.Name}, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list pods, err: %w", err)
+	}
+	boundPods := int32(0)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName != node.Name || util.IsPodTerminated(pod) {
+			continue
+		}
+		if extension.IsPodResourceBound(pod) {
+			boundPods++
+		}
+	}
+	if boundPods > 0 {
+		return &schedulingv1alpha1.NodeMaintenanceStatus{
+			Phase:     schedulingv1alpha1.NodeMaintenanceDraining,
+			BoundPods: boundPods,
+			Message:   fmt.Sprintf("%d bound pods are migrating", boundPods),
+		}, nil
+	}
+	return &schedulingv1alpha1.NodeMaintenanceStatus{
+		Phase:   schedulingv1alpha1.NodeMaintenanceDrained,
+		Message: "no bound pod is left",
+	}, nil
+}
+
+func (r *NodeMaintenanceReconciler) updateStatus(ctx context.Context, maintenance *schedulingv1alpha1.NodeMaintenance, status *schedulingv1alpha1.NodeMaintenanceStatus) error {
+	status.LastUpdateTime = maintenance.Status.LastUpdateTime
+	if equality.Semantic.DeepEqual(&maintenance.Status, status) {
+		return nil
+	}
+	status.LastUpdateTime = &metav1.Time{Time: time.Now()}
+	maintenance.Status = *status
+	if err := r.Client.Status().Update(ctx, maintenance); err != nil {
+		return err
+	}
+	klog.V(4).Infof("nodemaintenance-controller updated maintenance %s, phase %s, bound pods %d",
+		maintenance.Name, status.Phase, status.BoundPods)
+	return nil
+}
+
+// finishMaintenance removes the drain label from the node and uncordons it if it is cordoned by the maintenance.
+func (r *NodeMaintenanceReconciler) finishMaintenance(ctx context.Context, maintenance *schedulingv1alpha1.NodeMaintenance) error {
+	if !controllerutil.ContainsFinalizer(maintenance, finalizerNodeMaintenance) {
+		return nil
+	}
+
+	node := &corev1.Node{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: maintenance.Spec.NodeName}, node)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		_, labeled := node.Labels[extension.LabelNodeCoordinatedDrain]
+		uncordon := node.Spec.Unschedulable && maintenance.Annotations[annotationNodeCordoned] == "true"
+		if labeled || uncordon {
+			patch := client.MergeFrom(node.DeepCopy())
+			delete(node.Labels, extension.LabelNodeCoordinatedDrain)
+			if uncordon {
+				node.Spec.Unschedulable = false
+			}
+			if err := r.Client.Patch(ctx, node, patch); err != nil {
+				return fmt.Errorf("failed to uncordon node, err: %w", err)
+			}
+			klog.V(4).Infof("nodemaintenance-controller finished maintenance %s of node %s, uncordon %v",
+				maintenance.Name, node.Name, uncordon)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(maintenance, finalizerNodeMaintenance)
+	return r.Client.Update(ctx, maintenance)
+}
+
+func Add(mgr ctrl.Manager) error {
+	reconciler := NodeMaintenanceReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+func (r *NodeMaintenanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&schedulingv1alpha1.NodeMaintenance{}).
+		Named(Name).
+		Complete(r)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = schedulingv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func newTestPod(name, nodeName string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestNodeMaintenanceReconciler_Reconcile(t *testing.T) {
+	boundAnnotations := map[string]string{
+		extension.AnnotationResourceStatus: `{"cpuset":"0-3"}`,
+	}
+	terminatedPod := newTestPod("pod-3", "node-1", boundAnnotations)
+	terminatedPod.Status.Phase = corev1.PodSucceeded
+	objects := []client.Object{
+		&schedulingv1alpha1.NodeMaintenance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-maintenance"},
+			Spec:       schedulingv1alpha1.NodeMaintenanceSpec{NodeName: "node-1"},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		newTestPod("pod-1", "node-1", boundAnnotations),
+		newTestPod("pod-2", "node-1", nil),
+		terminatedPod,
+		newTestPod("pod-4", "node-2", boundAnnotations),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objects...).Build()
+	r := &NodeMaintenanceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-maintenance"}}
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, drainSyncInterval, result.RequeueAfter)
+
+	node := &corev1.Node{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "node-1"}, node))
+	assert.True(t, extension.IsNodeCoordinatedDraining(node))
+
+	maintenance := &schedulingv1alpha1.NodeMaintenance{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, maintenance))
+	assert.Contains(t, maintenance.Finalizers, finalizerNodeMaintenance)
+	assert.Equal(t, "true", maintenance.Annotations[annotationNodeCordoned])
+	assert.Equal(t, schedulingv1alpha1.NodeMaintenanceDraining, maintenance.Status.Phase)
+	assert.Equal(t, int32(1), maintenance.Status.BoundPods)
+	assert.NotNil(t, maintenance.Status.LastUpdateTime)
+
+	// the bound pod is migrated
+	assert.NoError(t, fakeClient.Delete(context.TODO(), newTestPod("pod-1", "node-1", nil)))
+	result, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, maintenance))
+	assert.Equal(t, schedulingv1alpha1.NodeMaintenanceDrained, maintenance.Status.Phase)
+	assert.Equal(t, int32(0), maintenance.Status.BoundPods)
+}
+
+func TestNodeMaintenanceReconciler_ReconcileNodeNotFound(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(
+		&schedulingv1alpha1.NodeMaintenance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-maintenance"},
+			Spec:       schedulingv1alpha1.NodeMaintenanceSpec{NodeName: "node-1"},
+		},
+	).Build()
+	r := &NodeMaintenanceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-maintenance"}}
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, drainSyncInterval, result.RequeueAfter)
+
+	maintenance := &schedulingv1alpha1.NodeMaintenance{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, maintenance))
+	assert.Equal(t, schedulingv1alpha1.NodeMaintenancePending, maintenance.Status.Phase)
+}
+
+func TestNodeMaintenanceReconciler_ReconcileDeletion(t *testing.T) {
+	tests := []struct {
+		name                  string
+		cordonedByMaintenance bool
+		wantUnschedulable     bool
+	}{
+		{
+			name:                  "uncordon the node cordoned by the maintenance",
+			cordonedByMaintenance: true,
+			wantUnschedulable:     false,
+		},
+		{
+			name:                  "keep the node cordoned by others",
+			cordonedByMaintenance: false,
+			wantUnschedulable:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintenance := &schedulingv1alpha1.NodeMaintenance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-maintenance",
+					Finalizers:        []string{finalizerNodeMaintenance},
+					DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+				},
+				Spec: schedulingv1alpha1.NodeMaintenanceSpec{NodeName: "node-1"},
+			}
+			if tt.cordonedByMaintenance {
+				maintenance.Annotations = map[string]string{annotationNodeCordoned: "true"}
+			}
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-1",
+					Labels: map[string]string{extension.LabelNodeCoordinatedDrain: "true"},
+				},
+				Spec: corev1.NodeSpec{Unschedulable: true},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(maintenance, node).Build()
+			r := &NodeMaintenanceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-maintenance"}}
+			_, err := r.Reconcile(context.TODO(), req)
+			assert.NoError(t, err)
+
+			gotNode := &corev1.Node{}
+			assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "node-1"}, gotNode))
+			assert.Equal(t, tt.wantUnschedulable, gotNode.Spec.Unschedulable)
+			_, labeled := gotNode.Labels[extension.LabelNodeCoordinatedDrain]
+			assert.False(t, labeled)
+
+			gotMaintenance := &schedulingv1alpha1.NodeMaintenance{}
+			err = fakeClient.Get(context.TODO(), req.NamespacedName, gotMaintenance)
+			if err == nil {
+				assert.NotContains(t, gotMaintenance.Finalizers, finalizerNodeMaintenance)
+			}
+		})
+	}
+}
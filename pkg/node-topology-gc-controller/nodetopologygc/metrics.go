@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetopologygc
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	subsystem = "node_topology_gc"
+
+	sourceReconcile = "reconcile"
+	sourceSweep     = "sweep"
+
+	statusSucceeded = "succeeded"
+	statusFailed    = "failed"
+)
+
+var (
+	staleObjectDeletedCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "stale_object_deleted_count",
+		Help:      "the count of the NodeResourceTopology and Device objects deleted since their nodes are gone",
+	}, []string{"kind", "source"})
+
+	ownerReferenceUpdatedCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "owner_reference_updated_count",
+		Help:      "the count of the NodeResourceTopology and Device objects whose node owner reference is aligned",
+	}, []string{"kind"})
+
+	orphanSweepCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "orphan_sweep_count",
+		Help:      "the status count of sweeping the orphaned NodeResourceTopology and Device objects",
+	}, []string{"status"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(staleObjectDeletedCount, ownerReferenceUpdatedCount, orphanSweepCount)
+}
+
+func recordStaleObjectDeleted(kind, source string) {
+	staleObjectDeletedCount.WithLabelValues(kind, source).Inc()
+}
+
+func recordOwnerReferenceUpdated(kind string) {
+	ownerReferenceUpdatedCount.WithLabelValues(kind).Inc()
+}
+
+func recordOrphanSweep(isSucceeded bool) {
+	if isSucceeded {
+		orphanSweepCount.WithLabelValues(statusSucceeded).Inc()
+	} else {
+		orphanSweepCount.WithLabelValues(statusFailed).Inc()
+	}
+}
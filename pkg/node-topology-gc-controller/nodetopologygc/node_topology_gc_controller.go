@@ -0,0 +1,282 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetopologygc
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+const Name = "nodetopologygc"
+
+const (
+	kindNodeResourceTopology = "NodeResourceTopology"
+	kindDevice               = "Device"
+)
+
+var (
+	// OrphanSweepInterval is the interval to sweep the NodeResourceTopology and Device objects whose nodes are gone,
+	// in case that the node deletions are missed by the reconciler, e.g. the manager is down. Zero disables the sweeper.
+	OrphanSweepInterval = 10 * time.Minute
+	// OrphanGracePeriod protects the objects newly created before their nodes are observed by the informer cache.
+	OrphanGracePeriod = time.Minute
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&OrphanSweepInterval, "node-topology-gc-sweep-interval", OrphanSweepInterval,
+		"The interval to sweep the orphaned NodeResourceTopology and Device objects. Zero disables the sweeper.")
+	fs.DurationVar(&OrphanGracePeriod, "node-topology-gc-grace-period", OrphanGracePeriod,
+		"The minimum age of an orphaned NodeResourceTopology or Device object before it is deleted.")
+}
+
+// gcTarget describes a kind of the cluster-scoped objects which are named after their nodes.
+type gcTarget struct {
+	kind      string
+	newObject func() client.Object
+	newList   func() client.ObjectList
+}
+
+var gcTargets = []gcTarget{
+	{
+		kind:      kindNodeResourceTopology,
+		newObject: func() client.Object { return &nrtv1alpha1.NodeResourceTopology{} },
+		newList:   func() client.ObjectList { return &nrtv1alpha1.NodeResourceTopologyList{} },
+	},
+	{
+		kind:      kindDevice,
+		newObject: func() client.Object { return &schedulingv1alpha1.Device{} },
+		newList:   func() client.ObjectList { return &schedulingv1alpha1.DeviceList{} },
+	},
+}
+
+// NodeTopologyGCReconciler keeps the NodeResourceTopology and Device objects owned by the nodes of the same name.
+// It aligns the owner references to the current node UID so that the garbage collector of the cluster deletes them
+// along with the node, and deletes the objects whose node is gone, e.g. the node is deleted or renamed.
+type NodeTopologyGCReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=devices,verbs=get;list;watch;patch;delete
+
+func (r *NodeTopologyGCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, req.NamespacedName, node); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("failed to find node %v, error: %v", req.Name, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		node = nil
+	}
+
+	var requeueAfter time.Duration
+	for _, target := range gcTargets {
+		obj := target.newObject()
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: req.Name}, obj); err != nil {
+			if !errors.IsNotFound(err) {
+				klog.Errorf("failed to find %s %v, error: %v", target.kind, req.Name, err)
+				return ctrl.Result{Requeue: true}, err
+			}
+			continue
+		}
+		after, err := r.syncObject(ctx, target.kind, obj, node, sourceReconcile)
+		if err != nil {
+			klog.Errorf("failed to sync %s %v, error: %v", target.kind, req.Name, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		if after > 0 && (requeueAfter == 0 || after < requeueAfter) {
+			requeueAfter = after
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// syncObject deletes the object if its node is gone, otherwise it makes the object owned by the node.
+// It returns a positive duration if the object is orphaned but still in the grace period.
+func (r *NodeTopologyGCReconciler) syncObject(ctx context.Context, kind string, obj client.Object, node *corev1.Node, source string) (time.Duration, error) {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return 0, nil
+	}
+
+	if node == nil {
+		if age := time.Since(obj.GetCreationTimestamp().Time); age < OrphanGracePeriod {
+			return OrphanGracePeriod - age, nil
+		}
+		// the object may be recreated for a new node of the same name since it is observed
+		uid := obj.GetUID()
+		if err := r.Client.Delete(ctx, obj, client.Preconditions{UID: &uid}); err != nil {
+			if errors.IsNotFound(err) {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to delete orphaned %s, err: %w", kind, err)
+		}
+		recordStaleObjectDeleted(kind, source)
+		klog.V(4).Infof("nodetopologygc-controller deleted orphaned %s %s, source %s", kind, obj.GetName(), source)
+		return 0, nil
+	}
+
+	if !node.DeletionTimestamp.IsZero() {
+		// the garbage collector takes over the dependents of the deleting node
+		return 0, nil
+	}
+	ownerReferences, changed := alignNodeOwnerReference(obj.GetOwnerReferences(), node)
+	if !changed {
+		return 0, nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	obj.SetOwnerReferences(ownerReferences)
+	if err := r.Client.Patch(ctx, obj, patch); err != nil {
+		return 0, fmt.Errorf("failed to update owner references of %s, err: %w", kind, err)
+	}
+	recordOwnerReferenceUpdated(kind)
+	klog.V(4).Infof("nodetopologygc-controller aligned the owner reference of %s %s to node uid %s", kind, obj.GetName(), node.UID)
+	return 0, nil
+}
+
+// alignNodeOwnerReference replaces the node owner references with the one pointing to the current node.
+// The stale one left by a node recreated with the same name is dropped since it makes the garbage collector
+// delete the object of the live node.
+func alignNodeOwnerReference(ownerReferences []metav1.OwnerReference, node *corev1.Node) ([]metav1.OwnerReference, bool) {
+	var aligned []metav1.OwnerReference
+	found, changed := false, false
+	for _, ref := range ownerReferences {
+		if ref.APIVersion != "v1" || ref.Kind != "Node" {
+			aligned = append(aligned, ref)
+			continue
+		}
+		if ref.Name == node.Name && ref.UID == node.UID && !found {
+			found = true
+			aligned = append(aligned, ref)
+			continue
+		}
+		changed = true
+	}
+	if !found {
+		blocker := true
+		aligned = append(aligned, metav1.OwnerReference{
+			APIVersion:         "v1",
+			Kind:               "Node",
+			Name:               node.Name,
+			UID:                node.UID,
+			Controller:         &blocker,
+			BlockOwnerDeletion: &blocker,
+		})
+		changed = true
+	}
+	return aligned, changed
+}
+
+// sweepOrphans deletes the NodeResourceTopology and Device objects whose nodes are not found.
+func (r *NodeTopologyGCReconciler) sweepOrphans(ctx context.Context) error {
+	nodeList := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodeList, utilclient.DisableDeepCopy); err != nil {
+		return fmt.Errorf("failed to list nodes, err: %w", err)
+	}
+	nodeNames := sets.NewString()
+	for i := range nodeList.Items {
+		nodeNames.Insert(nodeList.Items[i].Name)
+	}
+
+	var errs []error
+	for _, target := range gcTargets {
+		list := target.newList()
+		if err := r.Client.List(ctx, list); err != nil {
+			errs = append(errs, fmt.Errorf("failed to list %s, err: %w", target.kind, err))
+			continue
+		}
+		err := meta.EachListItem(list, func(o runtime.Object) error {
+			obj, ok := o.(client.Object)
+			if !ok || nodeNames.Has(obj.GetName()) {
+				return nil
+			}
+			_, err := r.syncObject(ctx, target.kind, obj, nil, sourceSweep)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to sweep orphans, errs: %v", errs)
+	}
+	return nil
+}
+
+func (r *NodeTopologyGCReconciler) runOrphanSweeper(ctx context.Context) error {
+	if OrphanSweepInterval <= 0 {
+		return nil
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := r.sweepOrphans(ctx)
+		recordOrphanSweep(err == nil)
+		if err != nil {
+			klog.Errorf("nodetopologygc-controller failed to sweep orphans, error: %v", err)
+		}
+	}, OrphanSweepInterval)
+	return nil
+}
+
+func Add(mgr ctrl.Manager) error {
+	if err := nrtv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return fmt.Errorf("failed to add scheme for NodeResourceTopology, err: %w", err)
+	}
+	if err := nrtv1alpha1.AddToScheme(clientgoscheme.Scheme); err != nil {
+		return fmt.Errorf("failed to add client go scheme for NodeResourceTopology, err: %w", err)
+	}
+	reconciler := NodeTopologyGCReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	if err := mgr.Add(manager.RunnableFunc(reconciler.runOrphanSweeper)); err != nil {
+		return err
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+func (r *NodeTopologyGCReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the objects are named after their nodes, so that they are reconciled with the node of the same name
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Node{}).
+		Watches(&source.Kind{Type: &nrtv1alpha1.NodeResourceTopology{}}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &schedulingv1alpha1.Device{}}, &handler.EnqueueRequestForObject{}).
+		Named(Name).
+		Complete(r)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetopologygc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = schedulingv1alpha1.AddToScheme(scheme)
+	_ = nrtv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func newTestNodeOwnerReferences(nodeName string, uid types.UID) []metav1.OwnerReference {
+	blocker := true
+	return []metav1.OwnerReference{
+		{
+			APIVersion:         "v1",
+			Kind:               "Node",
+			Name:               nodeName,
+			UID:                uid,
+			Controller:         &blocker,
+			BlockOwnerDeletion: &blocker,
+		},
+	}
+}
+
+func TestNodeTopologyGCReconciler_ReconcileOwnerReferences(t *testing.T) {
+	objects := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "uid-new"}},
+		&nrtv1alpha1.NodeResourceTopology{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&schedulingv1alpha1.Device{ObjectMeta: metav1.ObjectMeta{
+			Name:            "node-1",
+			OwnerReferences: newTestNodeOwnerReferences("node-1", "uid-old"),
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objects...).Build()
+	r := &NodeTopologyGCReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "node-1"}}
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+
+	expected := newTestNodeOwnerReferences("node-1", "uid-new")
+	nrt := &nrtv1alpha1.NodeResourceTopology{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, nrt))
+	assert.Equal(t, expected, nrt.OwnerReferences)
+	device := &schedulingv1alpha1.Device{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, device))
+	assert.Equal(t, expected, device.OwnerReferences)
+
+	// the aligned objects are kept unchanged
+	result, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, device))
+	assert.Equal(t, expected, device.OwnerReferences)
+}
+
+func TestNodeTopologyGCReconciler_ReconcileNodeNotFound(t *testing.T) {
+	objects := []client.Object{
+		&nrtv1alpha1.NodeResourceTopology{ObjectMeta: metav1.ObjectMeta{
+			Name:              "node-1",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * OrphanGracePeriod)),
+		}},
+		&schedulingv1alpha1.Device{ObjectMeta: metav1.ObjectMeta{
+			Name:              "node-1",
+			CreationTimestamp: metav1.Now(),
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objects...).Build()
+	r := &NodeTopologyGCReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "node-1"}}
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= OrphanGracePeriod)
+
+	err = fakeClient.Get(context.TODO(), req.NamespacedName, &nrtv1alpha1.NodeResourceTopology{})
+	assert.True(t, errors.IsNotFound(err))
+	// the device in the grace period is kept
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, &schedulingv1alpha1.Device{}))
+}
+
+func TestNodeTopologyGCReconciler_SweepOrphans(t *testing.T) {
+	objects := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "uid-1"}},
+		&nrtv1alpha1.NodeResourceTopology{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&nrtv1alpha1.NodeResourceTopology{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		&schedulingv1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&schedulingv1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(objects...).Build()
+	r := &NodeTopologyGCReconciler{Client: fakeClient, Scheme: fakeClient.Scheme()}
+
+	assert.NoError(t, r.sweepOrphans(context.TODO()))
+
+	nrtList := &nrtv1alpha1.NodeResourceTopologyList{}
+	assert.NoError(t, fakeClient.List(context.TODO(), nrtList))
+	assert.Len(t, nrtList.Items, 1)
+	assert.Equal(t, "node-1", nrtList.Items[0].Name)
+
+	deviceList := &schedulingv1alpha1.DeviceList{}
+	assert.NoError(t, fakeClient.List(context.TODO(), deviceList))
+	assert.Len(t, deviceList.Items, 1)
+	assert.Equal(t, "node-1", deviceList.Items[0].Name)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preallocation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+const Name = "reservationpreallocation"
+
+// defaultReservationTTL bounds the lifetime of the pre-allocated Reservations, e.g. when the workload is deleted
+// during the rollout.
+const defaultReservationTTL = time.Hour
+
+// ReservationPreallocationReconciler pre-creates the Reservations matching the new pod template of the workloads
+// annotated with reservation-first update, so that the new pods can be placed on the reserved resources (e.g. the
+// NUMA-aligned CPUs) instead of competing with the old pods still terminating.
+type ReservationPreallocationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=reservations,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileWorkload keeps one Reservation for each replica pending the rolling update, and deletes the Reservations
+// of the stale revisions or the finished rollout.
+func (r *ReservationPreallocationReconciler) reconcileWorkload(ctx context.Context, w *rolloutWorkload) error {
+	reservationList := &schedulingv1alpha1.ReservationList{}
+	if err := r.Client.List(ctx, reservationList, client.MatchingLabels{extension.LabelReservationPreallocationOwner: string(w.uid)}); err != nil {
+		return fmt.Errorf("failed to list reservations, err: %w", err)
+	}
+
+	enabled := extension.IsReservationFirstUpdate(w.annotations) && w.pendingReplicas > 0
+	revision := hashPodTemplate(w.template)
+	existing := map[string]bool{}
+	for i := range reservationList.Items {
+		reservation := &reservationList.Items[i]
+		if enabled && reservation.Labels[extension.LabelReservationPreallocationRevision] == revision {
+			existing[reservation.Name] = true
+			continue
+		}
+		if err := r.Client.Delete(ctx, reservation); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete reservation %s, err: %w", reservation.Name, err)
+		}
+		klog.V(4).Infof("reservationpreallocation-controller deleted reservation %s of workload %s/%s",
+			reservation.Name, w.namespace, w.name)
+	}
+	if !enabled {
+		return nil
+	}
+
+	for i := int32(0); i < w.pendingReplicas; i++ {
+		name := getReservationName(w.uid, revision, i)
+		if existing[name] {
+			continue
+		}
+		reservation := newReservation(w, revision, name)
+		if err := r.Client.Create(ctx, reservation); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create reservation %s, err: %w", name, err)
+		}
+		klog.V(4).Infof("reservationpreallocation-controller created reservation %s for workload %s/%s",
+			name, w.namespace, w.name)
+	}
+	return nil
+}
+
+func getReservationName(uid types.UID, revision string, index int32) string {
+	return fmt.Sprintf("%s-%s-%d", uid, revision, index)
+}
+
+func newReservation(w *rolloutWorkload, revision, name string) *schedulingv1alpha1.Reservation {
+	template := w.template.DeepCopy()
+	template.Namespace = w.namespace
+	template.Spec.NodeName = ""
+	return &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				extension.LabelReservationPreallocationOwner:    string(w.uid),
+				extension.LabelReservationPreallocationRevision: revision,
+			},
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: template,
+			Owners: []schedulingv1alpha1.ReservationOwner{
+				{
+					Object:        &corev1.ObjectReference{Namespace: w.namespace},
+					LabelSelector: w.selector.DeepCopy(),
+				},
+			},
+			TTL:          &metav1.Duration{Duration: defaultReservationTTL},
+			AllocateOnce: pointer.Bool(true),
+		},
+	}
+}
+
+type deploymentReconciler struct {
+	*ReservationPreallocationReconciler
+}
+
+func (r *deploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	deploy := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, req.NamespacedName, deploy); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("failed to find deployment %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		// the left reservations are cleaned up when expired
+		return ctrl.Result{}, nil
+	}
+	if err := r.reconcileWorkload(ctx, newDeploymentWorkload(deploy)); err != nil {
+		klog.Errorf("failed to reconcile reservations for deployment %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+type statefulSetReconciler struct {
+	*ReservationPreallocationReconciler
+}
+
+func (r *statefulSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, sts); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("failed to find statefulset %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		// the left reservations are cleaned up when expired
+		return ctrl.Result{}, nil
+	}
+	if err := r.reconcileWorkload(ctx, newStatefulSetWorkload(sts)); err != nil {
+		klog.Errorf("failed to reconcile reservations for statefulset %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func Add(mgr ctrl.Manager) error {
+	reconciler := &ReservationPreallocationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// SetupWithManager sets up the controllers of Deployments and StatefulSets with the Manager.
+func (r *ReservationPreallocationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.Deployment{}).
+		Named(Name + "-deployment").
+		Complete(&deploymentReconciler{ReservationPreallocationReconciler: r}); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.StatefulSet{}).
+		Named(Name + "-statefulset").
+		Complete(&statefulSetReconciler{ReservationPreallocationReconciler: r})
+}
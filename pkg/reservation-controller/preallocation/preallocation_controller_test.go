@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preallocation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestDeployment(annotated bool, replicas, updatedReplicas int32) *appsv1.Deployment {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "test-deploy",
+			UID:        "test-deploy-uid",
+			Generation: 2,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test"},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "test"},
+					Annotations: map[string]string{
+						extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy": "FullPCPUs"}`,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "main",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("4"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    updatedReplicas,
+		},
+	}
+	if annotated {
+		deploy.Annotations = map[string]string{extension.AnnotationReservationFirstUpdate: "true"}
+	}
+	return deploy
+}
+
+func TestDeploymentReconcile(t *testing.T) {
+	tests := []struct {
+		name                 string
+		deploy               *appsv1.Deployment
+		existingReservations []*schedulingv1alpha1.Reservation
+		wantReservations     []string
+	}{
+		{
+			name:             "deployment not annotated",
+			deploy:           newTestDeployment(false, 3, 1),
+			wantReservations: nil,
+		},
+		{
+			name:             "deployment rollout in progress",
+			deploy:           newTestDeployment(true, 3, 1),
+			wantReservations: []string{"0", "1"},
+		},
+		{
+			name: "deployment rollout not observed yet",
+			deploy: func() *appsv1.Deployment {
+				deploy := newTestDeployment(true, 3, 3)
+				deploy.Status.ObservedGeneration = 1
+				return deploy
+			}(),
+			wantReservations: nil,
+		},
+		{
+			name:   "deployment rollout finished",
+			deploy: newTestDeployment(true, 3, 3),
+			existingReservations: []*schedulingv1alpha1.Reservation{
+				newReservation(newDeploymentWorkload(newTestDeployment(true, 3, 1)),
+					hashPodTemplate(&newTestDeployment(true, 3, 1).Spec.Template), "finished"),
+			},
+			wantReservations: nil,
+		},
+		{
+			name:   "delete reservations of stale revision",
+			deploy: newTestDeployment(true, 3, 2),
+			existingReservations: []*schedulingv1alpha1.Reservation{
+				newReservation(newDeploymentWorkload(newTestDeployment(true, 3, 2)), "stale", "stale"),
+			},
+			wantReservations: []string{"0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = schedulingv1alpha1.AddToScheme(scheme)
+			objs := []client.Object{tt.deploy}
+			for _, reservation := range tt.existingReservations {
+				objs = append(objs, reservation)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+			r := &deploymentReconciler{
+				ReservationPreallocationReconciler: &ReservationPreallocationReconciler{Client: c, Scheme: scheme},
+			}
+			_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{
+				Namespace: tt.deploy.Namespace,
+				Name:      tt.deploy.Name,
+			}})
+			assert.NoError(t, err)
+
+			reservationList := &schedulingv1alpha1.ReservationList{}
+			assert.NoError(t, c.List(context.TODO(), reservationList))
+			var gotReservations []string
+			revision := hashPodTemplate(&tt.deploy.Spec.Template)
+			for _, reservation := range reservationList.Items {
+				assert.Equal(t, revision, reservation.Labels[extension.LabelReservationPreallocationRevision])
+				assert.Equal(t, string(tt.deploy.UID), reservation.Labels[extension.LabelReservationPreallocationOwner])
+				assert.Equal(t, tt.deploy.Namespace, reservation.Spec.Template.Namespace)
+				assert.Equal(t, tt.deploy.Spec.Template.Annotations, reservation.Spec.Template.Annotations)
+				gotReservations = append(gotReservations, reservation.Name)
+			}
+			var wantReservations []string
+			for _, index := range tt.wantReservations {
+				wantReservations = append(wantReservations, string(tt.deploy.UID)+"-"+revision+"-"+index)
+			}
+			assert.Equal(t, wantReservations, gotReservations)
+		})
+	}
+}
+
+func TestNewStatefulSetWorkload(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "test-sts",
+			Generation: 2,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: pointer.Int32(4),
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    1,
+			CurrentRevision:    "rev-1",
+			UpdateRevision:     "rev-2",
+		},
+	}
+	assert.Equal(t, int32(3), newStatefulSetWorkload(sts).pendingReplicas)
+
+	sts.Status.CurrentRevision = "rev-2"
+	assert.Equal(t, int32(0), newStatefulSetWorkload(sts).pendingReplicas)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preallocation
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rolloutWorkload is the rolling update state of a Deployment or StatefulSet.
+type rolloutWorkload struct {
+	namespace   string
+	name        string
+	uid         types.UID
+	annotations map[string]string
+	selector    *metav1.LabelSelector
+	template    *corev1.PodTemplateSpec
+	// pendingReplicas is the number of replicas not updated to the new pod template yet.
+	pendingReplicas int32
+}
+
+func newDeploymentWorkload(deploy *appsv1.Deployment) *rolloutWorkload {
+	w := &rolloutWorkload{
+		namespace:   deploy.Namespace,
+		name:        deploy.Name,
+		uid:         deploy.UID,
+		annotations: deploy.Annotations,
+		selector:    deploy.Spec.Selector,
+		template:    &deploy.Spec.Template,
+	}
+	// the status is stale until the deployment controller observes the latest spec
+	if deploy.Spec.Paused || deploy.Status.ObservedGeneration < deploy.Generation {
+		return w
+	}
+	w.pendingReplicas = getReplicas(deploy.Spec.Replicas) - deploy.Status.UpdatedReplicas
+	return w
+}
+
+func newStatefulSetWorkload(sts *appsv1.StatefulSet) *rolloutWorkload {
+	w := &rolloutWorkload{
+		namespace:   sts.Namespace,
+		name:        sts.Name,
+		uid:         sts.UID,
+		annotations: sts.Annotations,
+		selector:    sts.Spec.Selector,
+		template:    &sts.Spec.Template,
+	}
+	// the status is stale until the statefulset controller observes the latest spec
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == sts.Status.CurrentRevision {
+		return w
+	}
+	w.pendingReplicas = getReplicas(sts.Spec.Replicas) - sts.Status.UpdatedReplicas
+	return w
+}
+
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// hashPodTemplate returns the revision of the pod template.
+func hashPodTemplate(template *corev1.PodTemplateSpec) string {
+	data, _ := json.Marshal(template) // assert no error
+	h := fnv.New32a()
+	h.Write(data)
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
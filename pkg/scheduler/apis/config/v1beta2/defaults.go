@@ -48,6 +48,9 @@ var (
 	}
 
 	defaultPreferredCPUBindPolicy = CPUBindPolicyFullPCPUs
+	defaultReservedFullCores      = int32(0)
+	defaultMaxBoundPods           = int32(0)
+	defaultSocketBalanceWeight    = int32(0)
 
 	defaultEnablePreemption = pointer.Bool(false)
 
@@ -118,6 +121,29 @@ func SetDefaults_NodeNUMAResourceArgs(obj *NodeNUMAResourceArgs) {
 			},
 		}
 	}
+	if obj.ReservedFullCores == nil {
+		obj.ReservedFullCores = pointer.Int32(defaultReservedFullCores)
+	}
+	if obj.MaxBoundPodsPerNode == nil {
+		obj.MaxBoundPodsPerNode = pointer.Int32(defaultMaxBoundPods)
+	}
+	if obj.MaxBoundPodsPerNUMANode == nil {
+		obj.MaxBoundPodsPerNUMANode = pointer.Int32(defaultMaxBoundPods)
+	}
+	if obj.SocketBalanceWeight == nil {
+		obj.SocketBalanceWeight = pointer.Int32(defaultSocketBalanceWeight)
+	}
+	if obj.NUMAScoringStrategy != nil {
+		if len(obj.NUMAScoringStrategy.Resources) == 0 {
+			obj.NUMAScoringStrategy.Resources = obj.ScoringStrategy.Resources
+		}
+		if obj.NUMAScoringStrategy.Weight == 0 {
+			obj.NUMAScoringStrategy.Weight = 1
+		}
+		if obj.ScoringStrategy.Weight == 0 {
+			obj.ScoringStrategy.Weight = 1
+		}
+	}
 }
 
 func SetDefaults_ReservationArgs(obj *ReservationArgs) {
@@ -18,6 +18,7 @@ package v1beta2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	schedconfigv1beta2 "k8s.io/kube-scheduler/config/v1beta2"
 
@@ -47,6 +48,17 @@ type LoadAwareSchedulingArgs struct {
 	ProdUsageThresholds map[corev1.ResourceName]int64 `json:"prodUsageThresholds,omitempty"`
 	// ScoreAccordingProdUsage controls whether to score according to the utilization of Prod Pod
 	ScoreAccordingProdUsage *bool `json:"scoreAccordingProdUsage,omitempty"`
+	// ScoreAccordingMemoryLocality controls whether to penalize the nodes with poor memory locality
+	// when scoring the pods bound to CPUs, which are sensitive to the memory bandwidth.
+	ScoreAccordingMemoryLocality *bool `json:"scoreAccordingMemoryLocality,omitempty"`
+	// NodeHealthIndexThreshold indicates the threshold of the node health index reported in NodeMetric.
+	// The nodes whose health index reaches the threshold are filtered. Not enabled by default.
+	NodeHealthIndexThreshold *int64 `json:"nodeHealthIndexThreshold,omitempty"`
+	// ScoreAccordingNodeHealthIndex controls whether to penalize the nodes in proportion to the node health index
+	ScoreAccordingNodeHealthIndex *bool `json:"scoreAccordingNodeHealthIndex,omitempty"`
+	// CPUStealCapacityDiscount indicates the percentage of the CPU time stolen by the hypervisor which is deducted
+	// from the CPU allocatable of the nodes reporting the CPUStealAnomaly condition. Not enabled by default.
+	CPUStealCapacityDiscount *int64 `json:"cpuStealCapacityDiscount,omitempty"`
 	// Estimator indicates the expected Estimator to use
 	Estimator string `json:"estimator,omitempty"`
 	// EstimatedScalingFactors indicates the factor when estimating resource usage.
@@ -90,6 +102,10 @@ type ScoringStrategy struct {
 	// Resources a list of pairs <resource, weight> to be considered while scoring
 	// allowed weights start from 1.
 	Resources []schedconfigv1beta2.ResourceSpec `json:"resources,omitempty"`
+
+	// Weight is the weight of the strategy when it is composed with other strategies,
+	// e.g. the ScoringStrategy and the NUMAScoringStrategy of the NodeNUMAResource plugin.
+	Weight int64 `json:"weight,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -100,6 +116,36 @@ type NodeNUMAResourceArgs struct {
 
 	DefaultCPUBindPolicy *CPUBindPolicy   `json:"defaultCPUBindPolicy,omitempty"`
 	ScoringStrategy      *ScoringStrategy `json:"scoringStrategy,omitempty"`
+	// NUMAScoringStrategy scores the resources of the NUMA Nodes allocated to the Pod. If specified, the score is
+	// the weighted average of it and the ScoringStrategy scoring the full resources of the node, e.g. LeastAllocated
+	// on the node spreads the Pods across the cluster while MostAllocated on the NUMA Nodes packs them within the node.
+	NUMAScoringStrategy *ScoringStrategy `json:"numaScoringStrategy,omitempty"`
+	// ReservedFullCores indicates the number of free physical cores held back on each node for
+	// Pods that require FullPCPUs. It can be overridden by the node annotation node.koordinator.sh/reserved-full-cores.
+	ReservedFullCores *int32 `json:"reservedFullCores,omitempty"`
+	// NUMAHintAllocateOrder indicates in which order the NUMA Nodes of a multi-NUMA-node hint are filled.
+	// The NUMA Nodes are filled by the order of the NUMA Node ID by default.
+	// It can be overridden by the node label node.koordinator.sh/numa-hint-allocate-order.
+	NUMAHintAllocateOrder NUMAHintAllocateOrder `json:"numaHintAllocateOrder,omitempty"`
+	// ReservedCPUsPodSelector selects the System QoS Pods which are allowed to be pinned on the reserved CPUs of the node,
+	// e.g. the node-critical agents. Their CPUs are accounted apart from the CPUs allocated to the workloads.
+	ReservedCPUsPodSelector *metav1.LabelSelector `json:"reservedCPUsPodSelector,omitempty"`
+	// CPUBindOverheadPerContainer is the CPU added per container to the requests when sizing the exclusive cpuset
+	// of the Pod binding FullPCPUs or SpreadByPCPUs, so that a Pod sized exactly at N cores leaves room for its
+	// pause and runtime shim processes, e.g. 100m. The inflated requests are rounded up to the whole CPUs.
+	CPUBindOverheadPerContainer *resource.Quantity `json:"cpuBindOverheadPerContainer,omitempty"`
+	// MaxBoundPodsPerNode limits the number of the cpuset-bound Pods on each node, so that enough shared CPUs are left
+	// for the DaemonSets and the Burstable Pods. Zero means unlimited.
+	// It can be overridden by the node annotation node.koordinator.sh/max-bound-pods.
+	MaxBoundPodsPerNode *int32 `json:"maxBoundPodsPerNode,omitempty"`
+	// MaxBoundPodsPerNUMANode limits the number of the cpuset-bound Pods on each NUMA Node. Zero means unlimited.
+	// It can be overridden by the node annotation node.koordinator.sh/max-bound-pods-per-numa-node.
+	MaxBoundPodsPerNUMANode *int32 `json:"maxBoundPodsPerNUMANode,omitempty"`
+	// SocketBalanceWeight is the percentage of the socket balance score in the final score, which favors the nodes
+	// where the CPUs allocated to the cpuset-bound Pods are balanced across the sockets after the Pod is placed.
+	// It also makes the exclusive CPUs taken from the socket with the fewest allocated CPUs first, so that the
+	// LSR and LSE Pods don't pile up on one socket as the thermal and power hot spot. Zero disables the balancing.
+	SocketBalanceWeight *int32 `json:"socketBalanceWeight,omitempty"`
 }
 
 // CPUBindPolicy defines the CPU binding policy
@@ -139,6 +185,18 @@ const (
 	NUMADistributeEvenly NUMAAllocateStrategy = extension.NUMADistributeEvenly
 )
 
+// NUMAHintAllocateOrder indicates in which order the NUMA Nodes of a multi-NUMA-node hint are filled.
+type NUMAHintAllocateOrder = extension.NUMAHintAllocateOrder
+
+const (
+	// NUMAHintAllocateOrderNodeID fills the NUMA Nodes by the order of the NUMA Node ID.
+	NUMAHintAllocateOrderNodeID NUMAHintAllocateOrder = extension.NUMAHintAllocateOrderNodeID
+	// NUMAHintAllocateOrderMostAllocatedFirst fills the NUMA Node with the least ratio of available resources first.
+	NUMAHintAllocateOrderMostAllocatedFirst NUMAHintAllocateOrder = extension.NUMAHintAllocateOrderMostAllocatedFirst
+	// NUMAHintAllocateOrderLeastAllocatedFirst fills the NUMA Node with the most ratio of available resources first.
+	NUMAHintAllocateOrderLeastAllocatedFirst NUMAHintAllocateOrder = extension.NUMAHintAllocateOrderLeastAllocatedFirst
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ReservationArgs holds arguments used to configure the Reservation plugin.
@@ -27,6 +27,7 @@ import (
 	extension "github.com/koordinator-sh/koordinator/apis/extension"
 	config "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	corev1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -251,6 +252,14 @@ func autoConvert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingAr
 	if err := v1.Convert_Pointer_bool_To_bool(&in.ScoreAccordingProdUsage, &out.ScoreAccordingProdUsage, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.ScoreAccordingMemoryLocality, &out.ScoreAccordingMemoryLocality, s); err != nil {
+		return err
+	}
+	out.NodeHealthIndexThreshold = (*int64)(unsafe.Pointer(in.NodeHealthIndexThreshold))
+	if err := v1.Convert_Pointer_bool_To_bool(&in.ScoreAccordingNodeHealthIndex, &out.ScoreAccordingNodeHealthIndex, s); err != nil {
+		return err
+	}
+	out.CPUStealCapacityDiscount = (*int64)(unsafe.Pointer(in.CPUStealCapacityDiscount))
 	out.Estimator = in.Estimator
 	out.EstimatedScalingFactors = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.EstimatedScalingFactors))
 	if in.Aggregated != nil {
@@ -274,6 +283,14 @@ func autoConvert_config_LoadAwareSchedulingArgs_To_v1beta2_LoadAwareSchedulingAr
 	if err := v1.Convert_bool_To_Pointer_bool(&in.ScoreAccordingProdUsage, &out.ScoreAccordingProdUsage, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.ScoreAccordingMemoryLocality, &out.ScoreAccordingMemoryLocality, s); err != nil {
+		return err
+	}
+	out.NodeHealthIndexThreshold = (*int64)(unsafe.Pointer(in.NodeHealthIndexThreshold))
+	if err := v1.Convert_bool_To_Pointer_bool(&in.ScoreAccordingNodeHealthIndex, &out.ScoreAccordingNodeHealthIndex, s); err != nil {
+		return err
+	}
+	out.CPUStealCapacityDiscount = (*int64)(unsafe.Pointer(in.CPUStealCapacityDiscount))
 	out.Estimator = in.Estimator
 	out.EstimatedScalingFactors = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.EstimatedScalingFactors))
 	if in.Aggregated != nil {
@@ -298,6 +315,22 @@ func autoConvert_v1beta2_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(in
 		return err
 	}
 	out.ScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
+	out.NUMAScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.NUMAScoringStrategy))
+	if err := v1.Convert_Pointer_int32_To_int32(&in.ReservedFullCores, &out.ReservedFullCores, s); err != nil {
+		return err
+	}
+	out.NUMAHintAllocateOrder = in.NUMAHintAllocateOrder
+	out.ReservedCPUsPodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ReservedCPUsPodSelector))
+	out.CPUBindOverheadPerContainer = (*resource.Quantity)(unsafe.Pointer(in.CPUBindOverheadPerContainer))
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxBoundPodsPerNode, &out.MaxBoundPodsPerNode, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxBoundPodsPerNUMANode, &out.MaxBoundPodsPerNUMANode, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.SocketBalanceWeight, &out.SocketBalanceWeight, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -311,6 +344,22 @@ func autoConvert_config_NodeNUMAResourceArgs_To_v1beta2_NodeNUMAResourceArgs(in
 		return err
 	}
 	out.ScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
+	out.NUMAScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.NUMAScoringStrategy))
+	if err := v1.Convert_int32_To_Pointer_int32(&in.ReservedFullCores, &out.ReservedFullCores, s); err != nil {
+		return err
+	}
+	out.NUMAHintAllocateOrder = in.NUMAHintAllocateOrder
+	out.ReservedCPUsPodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ReservedCPUsPodSelector))
+	out.CPUBindOverheadPerContainer = (*resource.Quantity)(unsafe.Pointer(in.CPUBindOverheadPerContainer))
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxBoundPodsPerNode, &out.MaxBoundPodsPerNode, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxBoundPodsPerNUMANode, &out.MaxBoundPodsPerNUMANode, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.SocketBalanceWeight, &out.SocketBalanceWeight, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -342,6 +391,7 @@ func Convert_config_ReservationArgs_To_v1beta2_ReservationArgs(in *config.Reserv
 func autoConvert_v1beta2_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrategy, out *config.ScoringStrategy, s conversion.Scope) error {
 	out.Type = config.ScoringStrategyType(in.Type)
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
+	out.Weight = in.Weight
 	return nil
 }
 
@@ -353,6 +403,7 @@ func Convert_v1beta2_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrate
 func autoConvert_config_ScoringStrategy_To_v1beta2_ScoringStrategy(in *config.ScoringStrategy, out *ScoringStrategy, s conversion.Scope) error {
 	out.Type = ScoringStrategyType(in.Type)
 	out.Resources = *(*[]configv1beta2.ResourceSpec)(unsafe.Pointer(&in.Resources))
+	out.Weight = in.Weight
 	return nil
 }
 
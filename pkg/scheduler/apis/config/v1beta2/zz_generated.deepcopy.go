@@ -23,6 +23,7 @@ package v1beta2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	configv1beta2 "k8s.io/kube-scheduler/config/v1beta2"
@@ -230,6 +231,26 @@ func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ScoreAccordingMemoryLocality != nil {
+		in, out := &in.ScoreAccordingMemoryLocality, &out.ScoreAccordingMemoryLocality
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeHealthIndexThreshold != nil {
+		in, out := &in.NodeHealthIndexThreshold, &out.NodeHealthIndexThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ScoreAccordingNodeHealthIndex != nil {
+		in, out := &in.ScoreAccordingNodeHealthIndex, &out.ScoreAccordingNodeHealthIndex
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CPUStealCapacityDiscount != nil {
+		in, out := &in.CPUStealCapacityDiscount, &out.CPUStealCapacityDiscount
+		*out = new(int64)
+		**out = **in
+	}
 	if in.EstimatedScalingFactors != nil {
 		in, out := &in.EstimatedScalingFactors, &out.EstimatedScalingFactors
 		*out = make(map[corev1.ResourceName]int64, len(*in))
@@ -277,6 +298,41 @@ func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 		*out = new(ScoringStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NUMAScoringStrategy != nil {
+		in, out := &in.NUMAScoringStrategy, &out.NUMAScoringStrategy
+		*out = new(ScoringStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReservedFullCores != nil {
+		in, out := &in.ReservedFullCores, &out.ReservedFullCores
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReservedCPUsPodSelector != nil {
+		in, out := &in.ReservedCPUsPodSelector, &out.ReservedCPUsPodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUBindOverheadPerContainer != nil {
+		in, out := &in.CPUBindOverheadPerContainer, &out.CPUBindOverheadPerContainer
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxBoundPodsPerNode != nil {
+		in, out := &in.MaxBoundPodsPerNode, &out.MaxBoundPodsPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxBoundPodsPerNUMANode != nil {
+		in, out := &in.MaxBoundPodsPerNUMANode, &out.MaxBoundPodsPerNUMANode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SocketBalanceWeight != nil {
+		in, out := &in.SocketBalanceWeight, &out.SocketBalanceWeight
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -21,6 +21,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 
@@ -35,6 +36,14 @@ func ValidateLoadAwareSchedulingArgs(args *config.LoadAwareSchedulingArgs) error
 		allErrs = append(allErrs, field.Invalid(field.NewPath("nodeMetricExpiredSeconds"), *args.NodeMetricExpirationSeconds, "nodeMetricExpiredSeconds should be a positive value"))
 	}
 
+	if args.NodeHealthIndexThreshold != nil && (*args.NodeHealthIndexThreshold < 0 || *args.NodeHealthIndexThreshold > 100) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("nodeHealthIndexThreshold"), *args.NodeHealthIndexThreshold, "nodeHealthIndexThreshold should be in [0, 100]"))
+	}
+
+	if args.CPUStealCapacityDiscount != nil && (*args.CPUStealCapacityDiscount < 0 || *args.CPUStealCapacityDiscount > 100) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("cpuStealCapacityDiscount"), *args.CPUStealCapacityDiscount, "cpuStealCapacityDiscount should be in [0, 100]"))
+	}
+
 	if err := validateResourceWeights(args.ResourceWeights); err != nil {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("resourceWeights"), args.ResourceWeights, err.Error()))
 	}
@@ -163,6 +172,50 @@ func ValidateNodeNUMAResourceArgs(path *field.Path, args *config.NodeNUMAResourc
 
 	if args.ScoringStrategy != nil {
 		allErrs = append(allErrs, validateResources(args.ScoringStrategy.Resources, path.Child("resources"))...)
+		if args.ScoringStrategy.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("scoringStrategy", "weight"), args.ScoringStrategy.Weight, "must be non-negative"))
+		}
+	}
+
+	if args.NUMAScoringStrategy != nil {
+		numaScoringStrategyPath := path.Child("numaScoringStrategy")
+		switch args.NUMAScoringStrategy.Type {
+		case config.LeastAllocated, config.MostAllocated:
+		default:
+			allErrs = append(allErrs, field.Invalid(numaScoringStrategyPath.Child("type"), args.NUMAScoringStrategy.Type, "must specified LeastAllocated or MostAllocated"))
+		}
+		allErrs = append(allErrs, validateResources(args.NUMAScoringStrategy.Resources, numaScoringStrategyPath.Child("resources"))...)
+		if args.NUMAScoringStrategy.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(numaScoringStrategyPath.Child("weight"), args.NUMAScoringStrategy.Weight, "must be non-negative"))
+		}
+	}
+
+	if args.ReservedFullCores < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("reservedFullCores"), args.ReservedFullCores, "must be non-negative"))
+	}
+
+	switch args.NUMAHintAllocateOrder {
+	case "", config.NUMAHintAllocateOrderNodeID, config.NUMAHintAllocateOrderMostAllocatedFirst, config.NUMAHintAllocateOrderLeastAllocatedFirst:
+	default:
+		allErrs = append(allErrs, field.Invalid(path.Child("numaHintAllocateOrder"), args.NUMAHintAllocateOrder, "must specified NodeID, MostAllocatedFirst or LeastAllocatedFirst"))
+	}
+
+	if args.ReservedCPUsPodSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(args.ReservedCPUsPodSelector, path.Child("reservedCPUsPodSelector"))...)
+	}
+
+	if args.CPUBindOverheadPerContainer != nil && args.CPUBindOverheadPerContainer.Sign() < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("cpuBindOverheadPerContainer"), args.CPUBindOverheadPerContainer.String(), "must be non-negative"))
+	}
+
+	if args.MaxBoundPodsPerNode < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxBoundPodsPerNode"), args.MaxBoundPodsPerNode, "must be non-negative"))
+	}
+	if args.MaxBoundPodsPerNUMANode < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxBoundPodsPerNUMANode"), args.MaxBoundPodsPerNUMANode, "must be non-negative"))
+	}
+	if args.SocketBalanceWeight < 0 || args.SocketBalanceWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("socketBalanceWeight"), args.SocketBalanceWeight, "must be in the range [0, 100]"))
 	}
 
 	if len(allErrs) == 0 {
@@ -23,6 +23,7 @@ package config
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apisconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
@@ -212,6 +213,16 @@ func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 			(*out)[key] = val
 		}
 	}
+	if in.NodeHealthIndexThreshold != nil {
+		in, out := &in.NodeHealthIndexThreshold, &out.NodeHealthIndexThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPUStealCapacityDiscount != nil {
+		in, out := &in.CPUStealCapacityDiscount, &out.CPUStealCapacityDiscount
+		*out = new(int64)
+		**out = **in
+	}
 	if in.EstimatedScalingFactors != nil {
 		in, out := &in.EstimatedScalingFactors, &out.EstimatedScalingFactors
 		*out = make(map[corev1.ResourceName]int64, len(*in))
@@ -254,6 +265,21 @@ func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 		*out = new(ScoringStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NUMAScoringStrategy != nil {
+		in, out := &in.NUMAScoringStrategy, &out.NUMAScoringStrategy
+		*out = new(ScoringStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReservedCPUsPodSelector != nil {
+		in, out := &in.ReservedCPUsPodSelector, &out.ReservedCPUsPodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUBindOverheadPerContainer != nil {
+		in, out := &in.CPUBindOverheadPerContainer, &out.CPUBindOverheadPerContainer
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	return
 }
 
@@ -26,6 +26,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
 )
 
 var (
@@ -36,6 +38,8 @@ var (
 func AddFlags(fs *pflag.FlagSet) {
 	fs.IntVarP(&debugTopNScores, "debug-scores", "s", debugTopNScores, "logging topN nodes score and scores for each plugin after running the score extension, disable if set to 0")
 	fs.BoolVarP(&debugFilterFailure, "debug-filters", "f", debugFilterFailure, "logging filter failures")
+	fs.DurationVar(&topologymanager.DefaultHintProviderTimeout, "numa-hint-provider-timeout", topologymanager.DefaultHintProviderTimeout, "the timeout of getting the NUMA topology hints from a plugin, the hints of the other plugins are merged if it times out, disable if set to 0")
+	fs.Var(topologymanager.HintProviderTimeouts, "numa-hint-provider-timeouts", "the timeouts of getting the NUMA topology hints by the plugin name, overriding numa-hint-provider-timeout, e.g. DeviceShare=50ms")
 }
 
 // DebugScoresSetter updates debugTopNScores to specified value
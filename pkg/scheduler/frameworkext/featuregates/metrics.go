@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregates
+
+import (
+	"strconv"
+	"sync"
+
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// SchedulerSubsystem - subsystem name used by koord-scheduler
+	SchedulerSubsystem = "scheduler"
+)
+
+var (
+	DynamicFeatureGateEnabled = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "dynamic_feature_gate_enabled",
+			Help:           "Whether the dynamic feature gate is enabled, by the feature. 1 means enabled and 0 means disabled",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"feature"})
+
+	DynamicFeatureGateToggles = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "dynamic_feature_gate_toggles_total",
+			Help:           "Number of the dynamic feature gate toggles, by the feature, by the toggled state",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"feature", "enabled"})
+
+	metricsList = []metrics.Registerable{
+		DynamicFeatureGateEnabled,
+		DynamicFeatureGateToggles,
+	}
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the metrics of the dynamic feature gates.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
+
+func recordFeatureGateEnabled(feature featuregate.Feature, enabled bool) {
+	value := 0.0
+	if enabled {
+		value = 1.0
+	}
+	DynamicFeatureGateEnabled.WithLabelValues(string(feature)).Set(value)
+}
+
+func recordFeatureGateToggled(feature featuregate.Feature, enabled bool) {
+	recordFeatureGateEnabled(feature, enabled)
+	DynamicFeatureGateToggles.WithLabelValues(string(feature), strconv.FormatBool(enabled)).Inc()
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// FeatureGatesKey is the key of the ConfigMap data, whose value is formatted the same as
+	// the flag --feature-gates, e.g. "AmplifiedCPUsFilter=false,LoadAwareUsageThresholdsFilter=true".
+	FeatureGatesKey = "featureGates"
+)
+
+var (
+	// ConfigNamespace is the namespace of the scheduler feature gates configmap.
+	ConfigNamespace = "koordinator-system"
+	// ConfigMapName is the name of the scheduler feature gates configmap.
+	ConfigMapName = "koord-scheduler-feature-gates"
+)
+
+func AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&ConfigNamespace, "feature-gates-config-namespace", ConfigNamespace, "determines the namespace of the configmap to reload the dynamic feature gates.")
+	fs.StringVar(&ConfigMapName, "feature-gates-config-name", ConfigMapName, "determines the name of the configmap to reload the dynamic feature gates.")
+}
+
+// Watcher watches the feature gates configmap and reloads the dynamic features at runtime.
+// The features missing in the configmap are reset to the values when the watcher was created,
+// i.e. the values set by the command line flags.
+type Watcher struct {
+	client      kubernetes.Interface
+	featureGate featuregate.MutableFeatureGate
+	lock        sync.Mutex
+	// initialized records the values of the dynamic features before reloading.
+	initialized map[featuregate.Feature]bool
+}
+
+func NewWatcher(client kubernetes.Interface, featureGate featuregate.MutableFeatureGate, dynamicFeatures []featuregate.Feature) *Watcher {
+	RegisterMetrics()
+	initialized := make(map[featuregate.Feature]bool, len(dynamicFeatures))
+	for _, feature := range dynamicFeatures {
+		enabled := featureGate.Enabled(feature)
+		initialized[feature] = enabled
+		recordFeatureGateEnabled(feature, enabled)
+	}
+	return &Watcher{
+		client:      client,
+		featureGate: featureGate,
+		initialized: initialized,
+	}
+}
+
+func (w *Watcher) Start(stopCh <-chan struct{}) {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(w.client, 0,
+		informers.WithNamespace(ConfigNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", ConfigMapName).String()
+		}),
+	)
+	informer := informerFactory.Core().V1().ConfigMaps().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if configMap, ok := obj.(*corev1.ConfigMap); ok {
+				w.reload(configMap)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if configMap, ok := newObj.(*corev1.ConfigMap); ok {
+				w.reload(configMap)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			w.reload(nil)
+		},
+	})
+	informerFactory.Start(stopCh)
+	klog.V(4).InfoS("Started to watch the feature gates configmap", "namespace", ConfigNamespace, "name", ConfigMapName)
+}
+
+func (w *Watcher) reload(configMap *corev1.ConfigMap) {
+	var data string
+	if configMap != nil {
+		data = configMap.Data[FeatureGatesKey]
+	}
+	configured, err := parseFeatureGates(data)
+	if err != nil {
+		klog.ErrorS(err, "Failed to parse the feature gates configmap, skip reloading", "namespace", ConfigNamespace, "name", ConfigMapName)
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	desired := make(map[string]bool, len(w.initialized))
+	for feature, enabled := range w.initialized {
+		desired[string(feature)] = enabled
+	}
+	for name, enabled := range configured {
+		if _, ok := w.initialized[featuregate.Feature(name)]; !ok {
+			klog.Warningf("Feature gate %s can not be reloaded dynamically, ignore it", name)
+			continue
+		}
+		desired[name] = enabled
+	}
+
+	changed := map[string]bool{}
+	for name, enabled := range desired {
+		if w.featureGate.Enabled(featuregate.Feature(name)) != enabled {
+			changed[name] = enabled
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if err := w.featureGate.SetFromMap(changed); err != nil {
+		klog.ErrorS(err, "Failed to reload the feature gates", "featureGates", changed)
+		return
+	}
+	for name, enabled := range changed {
+		recordFeatureGateToggled(featuregate.Feature(name), enabled)
+	}
+	klog.InfoS("Reloaded the feature gates", "featureGates", changed)
+}
+
+// parseFeatureGates parses the feature gates formatted as "key1=value1,key2=value2,...".
+func parseFeatureGates(value string) (map[string]bool, error) {
+	m := map[string]bool{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		arr := strings.SplitN(s, "=", 2)
+		if len(arr) != 2 {
+			return nil, fmt.Errorf("missing bool value for %s", s)
+		}
+		k := strings.TrimSpace(arr[0])
+		boolValue, err := strconv.ParseBool(strings.TrimSpace(arr[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s=%s, err: %v", k, arr[1], err)
+		}
+		m[k] = boolValue
+	}
+	return m, nil
+}
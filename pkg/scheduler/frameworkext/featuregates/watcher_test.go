@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	testDynamicFeature featuregate.Feature = "TestDynamicFeature"
+	testStaticFeature  featuregate.Feature = "TestStaticFeature"
+)
+
+func newTestFeatureGate(t *testing.T) featuregate.MutableFeatureGate {
+	featureGate := featuregate.NewFeatureGate()
+	err := featureGate.Add(map[featuregate.Feature]featuregate.FeatureSpec{
+		testDynamicFeature: {Default: true, PreRelease: featuregate.Beta},
+		testStaticFeature:  {Default: false, PreRelease: featuregate.Alpha},
+	})
+	assert.NoError(t, err)
+	return featureGate
+}
+
+func Test_parseFeatureGates(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name:  "empty value",
+			value: "",
+			want:  map[string]bool{},
+		},
+		{
+			name:  "parse feature gates",
+			value: "A=true, B=false,",
+			want:  map[string]bool{"A": true, "B": false},
+		},
+		{
+			name:    "missing value",
+			value:   "A",
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			value:   "A=yes",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFeatureGates(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	featureGate := newTestFeatureGate(t)
+	w := NewWatcher(fake.NewSimpleClientset(), featureGate, []featuregate.Feature{testDynamicFeature})
+
+	w.reload(&corev1.ConfigMap{
+		Data: map[string]string{
+			FeatureGatesKey: "TestDynamicFeature=false,TestStaticFeature=true",
+		},
+	})
+	assert.False(t, featureGate.Enabled(testDynamicFeature))
+	assert.False(t, featureGate.Enabled(testStaticFeature), "static feature should not be reloaded")
+
+	// invalid configmap keeps the current values
+	w.reload(&corev1.ConfigMap{
+		Data: map[string]string{
+			FeatureGatesKey: "TestDynamicFeature",
+		},
+	})
+	assert.False(t, featureGate.Enabled(testDynamicFeature))
+
+	// the features are reset when the configmap is deleted
+	w.reload(nil)
+	assert.True(t, featureGate.Enabled(testDynamicFeature))
+}
+
+func TestWatcherStart(t *testing.T) {
+	featureGate := newTestFeatureGate(t)
+	client := fake.NewSimpleClientset()
+	w := NewWatcher(client, featureGate, []featuregate.Feature{testDynamicFeature})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	w.Start(stopCh)
+
+	_, err := client.CoreV1().ConfigMaps(ConfigNamespace).Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ConfigNamespace,
+			Name:      ConfigMapName,
+		},
+		Data: map[string]string{
+			FeatureGatesKey: "TestDynamicFeature=false",
+		},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return !featureGate.Enabled(testDynamicFeature), nil
+	})
+	assert.NoError(t, err)
+}
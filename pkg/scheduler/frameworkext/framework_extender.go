@@ -19,6 +19,7 @@ package frameworkext
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,6 +38,7 @@ import (
 
 var _ FrameworkExtender = &frameworkExtenderImpl{}
 var _ topologymanager.NUMATopologyHintProviderFactory = &frameworkExtenderImpl{}
+var _ topologymanager.NUMAResourceProviderFactory = &frameworkExtenderImpl{}
 
 type frameworkExtenderImpl struct {
 	framework.Framework
@@ -62,6 +64,7 @@ type frameworkExtenderImpl struct {
 	preBindExtensionsPlugins map[string]PreBindExtensions
 
 	numaTopologyHintProviders []topologymanager.NUMATopologyHintProvider
+	numaResourceProvider      topologymanager.NUMAResourceProvider
 	topologyManager           topologymanager.Interface
 }
 
@@ -128,7 +131,10 @@ func (ext *frameworkExtenderImpl) updatePlugins(pl framework.Plugin) {
 		ext.preBindExtensionsPlugins[p.Name()] = p
 	}
 	if p, ok := pl.(topologymanager.NUMATopologyHintProvider); ok {
-		ext.numaTopologyHintProviders = append(ext.numaTopologyHintProviders, p)
+		ext.numaTopologyHintProviders = append(ext.numaTopologyHintProviders, newInstrumentedNUMATopologyHintProvider(pl.Name(), p))
+	}
+	if p, ok := pl.(topologymanager.NUMAResourceProvider); ok {
+		ext.numaResourceProvider = p
 	}
 }
 
@@ -153,6 +159,8 @@ func (ext *frameworkExtenderImpl) Scheduler() Scheduler {
 
 // RunPreFilterPlugins transforms the PreFilter phase of framework with pre-filter transformers.
 func (ext *frameworkExtenderImpl) RunPreFilterPlugins(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	initNodeResourcesState(cycleState)
+
 	for _, pl := range ext.configuredPlugins.PreFilter.Enabled {
 		transformer := ext.preFilterTransformers[pl.Name]
 		if transformer == nil {
@@ -270,6 +278,17 @@ func (ext *frameworkExtenderImpl) RunPreBindPlugins(ctx context.Context, state *
 	return ext.runPreBindExtensionPlugins(ctx, state, original, reservation)
 }
 
+// RunPostBindPlugins records the scheduling outcome of the Pod bound to the node in the node pool.
+func (ext *frameworkExtenderImpl) RunPostBindPlugins(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	ext.Framework.RunPostBindPlugins(ctx, state, pod, nodeName)
+	node, err := ext.SharedInformerFactory().Core().V1().Nodes().Lister().Get(nodeName)
+	if err != nil {
+		klog.V(5).InfoS("Failed to get the node to record the scheduling outcome", "pod", klog.KObj(pod), "node", nodeName, "err", err)
+		return
+	}
+	recordSchedulingSuccess(pod, node)
+}
+
 func (ext *frameworkExtenderImpl) runPreBindExtensionPlugins(ctx context.Context, cycleState *framework.CycleState, originalObj, modifiedObj metav1.Object) *framework.Status {
 	plugins := ext.configuredPlugins
 	for _, plugin := range plugins.PreBind.Enabled {
@@ -293,7 +312,9 @@ func (ext *frameworkExtenderImpl) runPreBindExtensionPlugins(ctx context.Context
 
 func (ext *frameworkExtenderImpl) RunReservationExtensionPreRestoreReservation(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) *framework.Status {
 	for _, pl := range ext.reservationRestorePlugins {
+		startTime := time.Now()
 		status := pl.PreRestoreReservation(ctx, cycleState, pod)
+		recordPluginExecution(pl.Name(), preRestoreReservation, status, startTime)
 		if !status.IsSuccess() {
 			klog.ErrorS(status.AsError(), "Failed running PreRestoreReservation on plugin", "plugin", pl.Name(), "pod", klog.KObj(pod))
 			return status
@@ -306,7 +327,9 @@ func (ext *frameworkExtenderImpl) RunReservationExtensionPreRestoreReservation(c
 func (ext *frameworkExtenderImpl) RunReservationExtensionRestoreReservation(ctx context.Context, cycleState *framework.CycleState, podToSchedule *corev1.Pod, matched []*ReservationInfo, unmatched []*ReservationInfo, nodeInfo *framework.NodeInfo) (PluginToReservationRestoreStates, *framework.Status) {
 	pluginToRestoreState := PluginToReservationRestoreStates{}
 	for _, pl := range ext.reservationRestorePlugins {
+		startTime := time.Now()
 		state, status := pl.RestoreReservation(ctx, cycleState, podToSchedule, matched, unmatched, nodeInfo)
+		recordPluginExecution(pl.Name(), restoreReservation, status, startTime)
 		if !status.IsSuccess() {
 			klog.ErrorS(status.AsError(), "Failed running RestoreReservation on plugin", "plugin", pl.Name(), "pod", klog.KObj(podToSchedule))
 			return nil, status
@@ -322,7 +345,9 @@ func (ext *frameworkExtenderImpl) RunReservationExtensionFinalRestoreReservation
 		if !ok {
 			continue
 		}
+		startTime := time.Now()
 		status := pl.FinalRestoreReservation(ctx, cycleState, pod, s)
+		recordPluginExecution(pl.Name(), finalRestoreReservation, status, startTime)
 		if !status.IsSuccess() {
 			klog.ErrorS(status.AsError(), "Failed running FinalRestoreReservation on plugin", "plugin", pl.Name(), "pod", klog.KObj(pod))
 			return status
@@ -423,6 +448,10 @@ func (ext *frameworkExtenderImpl) GetNUMATopologyHintProvider() []topologymanage
 	return ext.numaTopologyHintProviders
 }
 
+func (ext *frameworkExtenderImpl) GetNUMAResourceProvider() topologymanager.NUMAResourceProvider {
+	return ext.numaResourceProvider
+}
+
 func (ext *frameworkExtenderImpl) RunReservePluginsReserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
 	if k8sfeature.DefaultFeatureGate.Enabled(features.ResizePod) {
 		if isPodAssumed(cycleState) {
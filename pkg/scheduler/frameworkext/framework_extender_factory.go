@@ -61,6 +61,7 @@ func WithKoordinatorSharedInformerFactory(informerFactory koordinatorinformers.S
 
 type FrameworkExtenderFactory struct {
 	controllerMaps                   *ControllersMap
+	handoffReconcilers               map[string]HandoffReconciler
 	servicesEngine                   *services.Engine
 	koordinatorClientSet             koordinatorclientset.Interface
 	koordinatorSharedInformerFactory koordinatorinformers.SharedInformerFactory
@@ -79,9 +80,11 @@ func NewFrameworkExtenderFactory(options ...Option) (*FrameworkExtenderFactory,
 	if err := indexer.AddIndexers(handleOptions.koordinatorSharedInformerFactory); err != nil {
 		return nil, err
 	}
+	RegisterMetrics()
 
 	return &FrameworkExtenderFactory{
 		controllerMaps:                   NewControllersMap(),
+		handoffReconcilers:               map[string]HandoffReconciler{},
 		servicesEngine:                   handleOptions.servicesEngine,
 		koordinatorClientSet:             handleOptions.koordinatorClientSet,
 		koordinatorSharedInformerFactory: handleOptions.koordinatorSharedInformerFactory,
@@ -187,6 +190,9 @@ func (f *FrameworkExtenderFactory) updatePlugins(pl framework.Plugin) {
 	if f.controllerMaps != nil {
 		f.controllerMaps.RegisterControllers(pl)
 	}
+	if reconciler, ok := pl.(HandoffReconciler); ok && f.handoffReconcilers != nil {
+		f.handoffReconcilers[pl.Name()] = reconciler
+	}
 }
 
 // PluginFactoryProxy is used to proxy the call to the PluginFactory function and pass in the ExtendedHandle for the custom plugin
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	// AnnotationHandoffGeneration is the number of the leader handoffs of the scheduler, which is increased by the
+	// new leader on the leader election lease.
+	AnnotationHandoffGeneration = extension.SchedulingDomainPrefix + "/handoff-generation"
+	// AnnotationHandoffHolder is the identity of the leader taking over the scheduling.
+	AnnotationHandoffHolder = extension.SchedulingDomainPrefix + "/handoff-holder"
+	// AnnotationHandoffTime is the time when the leader took over the scheduling, in RFC3339 format.
+	AnnotationHandoffTime = extension.SchedulingDomainPrefix + "/handoff-time"
+)
+
+// HandoffReconciler is implemented by the plugins which rebuild their state from the annotations of the objects.
+// The state rebuilt by the new leader can miss or conflict with the in-flight binds of the previous leader, so the
+// plugins detect and resolve the conflicting allocations after the handoff barrier.
+type HandoffReconciler interface {
+	ReconcileHandoff(ctx context.Context) error
+}
+
+// HandoffBarrier holds the new leader back until the in-flight binds of the previous leader have landed.
+// It bumps the handoff generation on the leader election lease, and waits for the grace period.
+type HandoffBarrier struct {
+	client         kubernetes.Interface
+	leaseNamespace string
+	leaseName      string
+	identity       string
+	gracePeriod    time.Duration
+}
+
+func NewHandoffBarrier(client kubernetes.Interface, leaseNamespace, leaseName, identity string, gracePeriod time.Duration) *HandoffBarrier {
+	return &HandoffBarrier{
+		client:         client,
+		leaseNamespace: leaseNamespace,
+		leaseName:      leaseName,
+		identity:       identity,
+		gracePeriod:    gracePeriod,
+	}
+}
+
+// Wait records the handoff on the lease and blocks for the grace period. It returns the new handoff generation.
+func (b *HandoffBarrier) Wait(ctx context.Context) (int64, error) {
+	generation, err := b.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	klog.InfoS("Waiting for the in-flight binds of the previous leader", "generation", generation, "gracePeriod", b.gracePeriod)
+	timer := time.NewTimer(b.gracePeriod)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return generation, ctx.Err()
+	case <-timer.C:
+	}
+	return generation, nil
+}
+
+func (b *HandoffBarrier) acquire(ctx context.Context) (int64, error) {
+	var generation int64
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lease, err := b.client.CoordinationV1().Leases(b.leaseNamespace).Get(ctx, b.leaseName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// the leader election doesn't use the lease lock, so the handoff is not recorded
+			generation = 0
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		generation, err = getHandoffGeneration(lease)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get the handoff generation, reset it", "lease", klog.KObj(lease))
+			generation = 0
+		}
+		generation++
+
+		lease = lease.DeepCopy()
+		if lease.Annotations == nil {
+			lease.Annotations = map[string]string{}
+		}
+		lease.Annotations[AnnotationHandoffGeneration] = strconv.FormatInt(generation, 10)
+		lease.Annotations[AnnotationHandoffHolder] = b.identity
+		lease.Annotations[AnnotationHandoffTime] = time.Now().UTC().Format(time.RFC3339)
+		_, err = b.client.CoordinationV1().Leases(b.leaseNamespace).Update(ctx, lease, metav1.UpdateOptions{})
+		return err
+	})
+	return generation, err
+}
+
+// Handoff waits for the handoff barrier, and then reconciles the state of the plugins with the previous leader.
+func (f *FrameworkExtenderFactory) Handoff(ctx context.Context, barrier *HandoffBarrier) error {
+	generation, err := barrier.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	for name, reconciler := range f.handoffReconcilers {
+		if err := reconciler.ReconcileHandoff(ctx); err != nil {
+			klog.ErrorS(err, "Failed to reconcile the handoff", "plugin", name, "generation", generation)
+		}
+	}
+	klog.InfoS("Finished the handoff from the previous leader", "generation", generation)
+	return nil
+}
+
+func getHandoffGeneration(lease *coordinationv1.Lease) (int64, error) {
+	value, ok := lease.Annotations[AnnotationHandoffGeneration]
+	if !ok {
+		return 0, nil
+	}
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || generation < 0 {
+		return 0, fmt.Errorf("invalid handoff generation %q", value)
+	}
+	return generation, nil
+}
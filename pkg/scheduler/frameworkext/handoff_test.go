@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandoffBarrier(t *testing.T) {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-system",
+			Name:      "koord-scheduler",
+		},
+	}
+	client := kubefake.NewSimpleClientset(lease)
+	barrier := NewHandoffBarrier(client, "kube-system", "koord-scheduler", "scheduler-1", 0)
+
+	generation, err := barrier.Wait(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), generation)
+
+	barrier = NewHandoffBarrier(client, "kube-system", "koord-scheduler", "scheduler-2", 0)
+	generation, err = barrier.Wait(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), generation)
+
+	got, err := client.CoordinationV1().Leases("kube-system").Get(context.TODO(), "koord-scheduler", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", got.Annotations[AnnotationHandoffGeneration])
+	assert.Equal(t, "scheduler-2", got.Annotations[AnnotationHandoffHolder])
+	assert.NotEmpty(t, got.Annotations[AnnotationHandoffTime])
+
+	barrier = NewHandoffBarrier(client, "kube-system", "not-found", "scheduler-2", 0)
+	generation, err = barrier.Wait(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), generation)
+}
+
+func TestGetHandoffGeneration(t *testing.T) {
+	lease := &coordinationv1.Lease{}
+	generation, err := getHandoffGeneration(lease)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), generation)
+
+	lease.Annotations = map[string]string{AnnotationHandoffGeneration: "3"}
+	generation, err = getHandoffGeneration(lease)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), generation)
+
+	lease.Annotations[AnnotationHandoffGeneration] = "bad"
+	_, err = getHandoffGeneration(lease)
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+const (
+	// SchedulerSubsystem - subsystem name used by koord-scheduler
+	SchedulerSubsystem = "scheduler"
+)
+
+const (
+	getPodTopologyHints     = "GetPodTopologyHints"
+	allocate                = "Allocate"
+	preRestoreReservation   = "PreRestoreReservation"
+	restoreReservation      = "RestoreReservation"
+	finalRestoreReservation = "FinalRestoreReservation"
+)
+
+var (
+	PluginExecutionDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "frameworkext_plugin_execution_duration_seconds",
+			Help:      "Duration for running a plugin at the extension points of the framework extender, by the plugin, by the extension point, by the status code",
+			// Start with 0.01ms with the last bucket being [~22ms, Inf). Same as the upstream plugin execution duration.
+			Buckets:        metrics.ExponentialBuckets(0.00001, 1.5, 20),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin", "extension_point", "status"})
+
+	PluginExecutionOutcomes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "frameworkext_plugin_execution_total",
+			Help:           "Number of the plugin executions at the extension points of the framework extender, by the plugin, by the extension point, by the status code",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"plugin", "extension_point", "status"})
+
+	NodePoolSchedulingOutcomes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           registry.NodePoolSchedulingOutcomes.Name,
+			Help:           "Number of the scheduling attempts of Pods in the node pools, by the node pool, by the outcome",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"pool", "outcome"})
+
+	NodePoolPodPendingDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      registry.NodePoolPodPendingDuration.Name,
+			Help:      "Duration from the creation to the binding of Pods in the node pools, by the node pool",
+			// Start with 1s with the last bucket being [~2.3h, Inf).
+			Buckets:        metrics.ExponentialBuckets(1, 2, 14),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"pool"})
+
+	metricsList = append([]metrics.Registerable{
+		PluginExecutionDuration,
+		PluginExecutionOutcomes,
+		NodePoolSchedulingOutcomes,
+		NodePoolPodPendingDuration,
+	}, topologymanager.MetricsList...)
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the plugin execution metrics of the framework extender.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
+
+// recordPluginExecution records the duration and the outcome of a plugin execution.
+// The outcome is labeled by the status code only, since the reasons may contain the node names and the quantities.
+func recordPluginExecution(pluginName, extensionPoint string, status *framework.Status, startTime time.Time) {
+	code := status.Code().String()
+	PluginExecutionDuration.WithLabelValues(pluginName, extensionPoint, code).Observe(time.Since(startTime).Seconds())
+	PluginExecutionOutcomes.WithLabelValues(pluginName, extensionPoint, code).Inc()
+}
+
+var _ topologymanager.NUMATopologyHintProvider = &instrumentedNUMATopologyHintProvider{}
+
+// instrumentedNUMATopologyHintProvider records the metrics of the hint generation and the allocation.
+type instrumentedNUMATopologyHintProvider struct {
+	topologymanager.NUMATopologyHintProvider
+	name string
+}
+
+func newInstrumentedNUMATopologyHintProvider(name string, provider topologymanager.NUMATopologyHintProvider) topologymanager.NUMATopologyHintProvider {
+	return &instrumentedNUMATopologyHintProvider{
+		NUMATopologyHintProvider: provider,
+		name:                     name,
+	}
+}
+
+// Name returns the plugin name, which identifies the provider in the timeouts of the topology manager.
+func (p *instrumentedNUMATopologyHintProvider) Name() string {
+	return p.name
+}
+
+func (p *instrumentedNUMATopologyHintProvider) GetPodTopologyHints(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (map[string][]topologymanager.NUMATopologyHint, *framework.Status) {
+	startTime := time.Now()
+	hints, status := p.NUMATopologyHintProvider.GetPodTopologyHints(ctx, cycleState, pod, nodeName)
+	recordPluginExecution(p.name, getPodTopologyHints, status, startTime)
+	return hints, status
+}
+
+func (p *instrumentedNUMATopologyHintProvider) Allocate(ctx context.Context, cycleState *framework.CycleState, affinity topologymanager.NUMATopologyHint, pod *corev1.Pod, nodeName string) *framework.Status {
+	startTime := time.Now()
+	status := p.NUMATopologyHintProvider.Allocate(ctx, cycleState, affinity, pod, nodeName)
+	recordPluginExecution(p.name, allocate, status, startTime)
+	return status
+}
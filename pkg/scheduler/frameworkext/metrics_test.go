@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+)
+
+type fakeMetricsHintProvider struct {
+	hintsStatus    *framework.Status
+	allocateStatus *framework.Status
+}
+
+func (p *fakeMetricsHintProvider) GetPodTopologyHints(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (map[string][]topologymanager.NUMATopologyHint, *framework.Status) {
+	return nil, p.hintsStatus
+}
+
+func (p *fakeMetricsHintProvider) Allocate(ctx context.Context, cycleState *framework.CycleState, affinity topologymanager.NUMATopologyHint, pod *corev1.Pod, nodeName string) *framework.Status {
+	return p.allocateStatus
+}
+
+func TestRecordPluginExecution(t *testing.T) {
+	RegisterMetrics()
+	PluginExecutionDuration.Reset()
+	PluginExecutionOutcomes.Reset()
+
+	startTime := time.Now()
+	recordPluginExecution("fakePlugin", restoreReservation, nil, startTime)
+	recordPluginExecution("fakePlugin", restoreReservation, framework.NewStatus(framework.Error, "failed to restore", "node-1"), startTime)
+
+	count, err := testutil.GetCounterMetricValue(PluginExecutionOutcomes.WithLabelValues("fakePlugin", restoreReservation, "Success"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), count)
+	count, err = testutil.GetCounterMetricValue(PluginExecutionOutcomes.WithLabelValues("fakePlugin", restoreReservation, "Error"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), count)
+
+	observed, err := testutil.GetHistogramMetricCount(PluginExecutionDuration.WithLabelValues("fakePlugin", restoreReservation, "Success"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), observed)
+	observed, err = testutil.GetHistogramMetricCount(PluginExecutionDuration.WithLabelValues("fakePlugin", restoreReservation, "Error"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), observed)
+}
+
+func TestInstrumentedNUMATopologyHintProvider(t *testing.T) {
+	RegisterMetrics()
+	PluginExecutionDuration.Reset()
+	PluginExecutionOutcomes.Reset()
+
+	provider := newInstrumentedNUMATopologyHintProvider("fakePlugin", &fakeMetricsHintProvider{
+		allocateStatus: framework.NewStatus(framework.Unschedulable, "Insufficient NUMA cpu"),
+	})
+	_, status := provider.GetPodTopologyHints(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, "test-node")
+	assert.True(t, status.IsSuccess())
+	status = provider.Allocate(context.TODO(), framework.NewCycleState(), topologymanager.NUMATopologyHint{}, &corev1.Pod{}, "test-node")
+	assert.Equal(t, framework.Unschedulable, status.Code())
+
+	count, err := testutil.GetCounterMetricValue(PluginExecutionOutcomes.WithLabelValues("fakePlugin", getPodTopologyHints, "Success"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), count)
+	count, err = testutil.GetCounterMetricValue(PluginExecutionOutcomes.WithLabelValues("fakePlugin", allocate, "Unschedulable"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), count)
+
+	observed, err := testutil.GetHistogramMetricCount(PluginExecutionDuration.WithLabelValues("fakePlugin", allocate, "Unschedulable"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), observed)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	nodeResourcesStateKey framework.StateKey = "koordinator.sh/node-resources"
+)
+
+// NodeResources is the amplification view of the node shared by the plugins in a scheduling cycle,
+// so that the plugins needing the amplified or raw allocatable do not recompute them and diverge.
+// It MUST be treated as read-only.
+type NodeResources struct {
+	// Allocatable is the allocatable reported in the node status, which is amplified already.
+	Allocatable corev1.ResourceList
+	// RawAllocatable is the allocatable before amplification.
+	RawAllocatable corev1.ResourceList
+	// AmplificationRatios are the resource amplification ratios of the node.
+	AmplificationRatios map[corev1.ResourceName]apiext.Ratio
+
+	resourceVersion string
+}
+
+// NewNodeResources computes the NodeResources of the node.
+func NewNodeResources(node *corev1.Node) (*NodeResources, error) {
+	amplificationRatios, err := apiext.GetNodeResourceAmplificationRatios(node.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeResources{
+		Allocatable:         node.Status.Allocatable,
+		RawAllocatable:      GetNodeRawAllocatable(node),
+		AmplificationRatios: amplificationRatios,
+		resourceVersion:     node.ResourceVersion,
+	}, nil
+}
+
+// GetNodeRawAllocatable returns the allocatable of the node before amplification.
+// The node allocatable is returned if the raw allocatable is not recorded or invalid.
+func GetNodeRawAllocatable(node *corev1.Node) corev1.ResourceList {
+	rawAllocatable, err := apiext.GetNodeRawAllocatable(node.Annotations)
+	if err != nil || len(rawAllocatable) == 0 {
+		return node.Status.Allocatable
+	}
+	if quotav1.Equals(rawAllocatable, node.Status.Allocatable) {
+		return node.Status.Allocatable
+	}
+	allocatableCopy := node.Status.Allocatable.DeepCopy()
+	if allocatableCopy == nil {
+		allocatableCopy = corev1.ResourceList{}
+	}
+	for k, v := range rawAllocatable {
+		allocatableCopy[k] = v
+	}
+	return allocatableCopy
+}
+
+// nodeResourcesState caches the NodeResources of the nodes in a scheduling cycle.
+// It is shared by the clones of the CycleState since the NodeResources are read-only.
+type nodeResourcesState struct {
+	// nodeResources maps the node name to the *nodeResourcesEntry.
+	nodeResources sync.Map
+}
+
+// nodeResourcesEntry serializes the computation of the NodeResources of a node,
+// so that the Filter and Score plugins running in parallel for different nodes do not block each other.
+type nodeResourcesEntry struct {
+	lock          sync.Mutex
+	nodeResources *NodeResources
+}
+
+func (s *nodeResourcesState) Clone() framework.StateData {
+	return s
+}
+
+// initNodeResourcesState prepares the cache of the NodeResources before the PreFilter phase,
+// which runs before the plugins access the CycleState in parallel.
+func initNodeResourcesState(cycleState *framework.CycleState) {
+	cycleState.Write(nodeResourcesStateKey, &nodeResourcesState{})
+}
+
+// GetNodeResources returns the NodeResources of the node, which are computed once per scheduling cycle and node.
+// The NodeResources are recomputed if the node has changed since they were cached.
+func GetNodeResources(cycleState *framework.CycleState, node *corev1.Node) (*NodeResources, error) {
+	if cycleState == nil {
+		return NewNodeResources(node)
+	}
+	value, err := cycleState.Read(nodeResourcesStateKey)
+	if err != nil {
+		return NewNodeResources(node)
+	}
+	state := value.(*nodeResourcesState)
+	actual, _ := state.nodeResources.LoadOrStore(node.Name, &nodeResourcesEntry{})
+	entry := actual.(*nodeResourcesEntry)
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+	if entry.nodeResources != nil && entry.nodeResources.resourceVersion == node.ResourceVersion {
+		return entry.nodeResources, nil
+	}
+
+	nodeResources, err := NewNodeResources(node)
+	if err != nil {
+		return nil, err
+	}
+	entry.nodeResources = nodeResources
+	return nodeResources, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestNewNodeResources(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("64"),
+				corev1.ResourceMemory: resource.MustParse("128Gi"),
+			},
+		},
+	}
+	apiext.SetNodeResourceAmplificationRatios(node, map[corev1.ResourceName]apiext.Ratio{
+		corev1.ResourceCPU: 2,
+	})
+	apiext.SetNodeRawAllocatable(node, corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("32"),
+	})
+
+	nodeResources, err := NewNodeResources(node)
+	assert.NoError(t, err)
+	assert.Equal(t, map[corev1.ResourceName]apiext.Ratio{corev1.ResourceCPU: 2}, nodeResources.AmplificationRatios)
+	assert.Equal(t, node.Status.Allocatable, nodeResources.Allocatable)
+	rawCPU := nodeResources.RawAllocatable[corev1.ResourceCPU]
+	assert.Equal(t, int64(32000), rawCPU.MilliValue())
+	rawMemory := nodeResources.RawAllocatable[corev1.ResourceMemory]
+	assert.Equal(t, resource.MustParse("128Gi"), rawMemory)
+}
+
+func TestGetNodeResources(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-node",
+			ResourceVersion: "1",
+		},
+	}
+	apiext.SetNodeResourceAmplificationRatios(node, map[corev1.ResourceName]apiext.Ratio{
+		corev1.ResourceCPU: 2,
+	})
+
+	cycleState := framework.NewCycleState()
+	nodeResources, err := GetNodeResources(cycleState, node)
+	assert.NoError(t, err)
+	got, err := GetNodeResources(cycleState, node)
+	assert.NoError(t, err)
+	assert.NotSame(t, nodeResources, got, "the NodeResources should not be cached without the state")
+
+	initNodeResourcesState(cycleState)
+	nodeResources, err = GetNodeResources(cycleState, node)
+	assert.NoError(t, err)
+	got, err = GetNodeResources(cycleState.Clone(), node)
+	assert.NoError(t, err)
+	assert.Same(t, nodeResources, got, "the NodeResources should be computed once per cycle")
+
+	node = node.DeepCopy()
+	node.ResourceVersion = "2"
+	apiext.SetNodeResourceAmplificationRatios(node, map[corev1.ResourceName]apiext.Ratio{
+		corev1.ResourceCPU: 3,
+	})
+	got, err = GetNodeResources(cycleState, node)
+	assert.NoError(t, err)
+	assert.Equal(t, apiext.Ratio(3), got.AmplificationRatios[corev1.ResourceCPU])
+
+	node.Annotations[apiext.AnnotationNodeResourceAmplificationRatio] = "invalid"
+	node.ResourceVersion = "3"
+	_, err = GetNodeResources(nil, node)
+	assert.Error(t, err)
+}
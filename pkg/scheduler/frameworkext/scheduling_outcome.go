@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// The outcomes of the scheduling attempts recorded per node pool.
+const (
+	SchedulingOutcomeSuccess      = "success"
+	SchedulingOutcomeNUMAFailure  = "numa_failure"
+	SchedulingOutcomeQuotaFailure = "quota_failure"
+	SchedulingOutcomeLoadFailure  = "load_failure"
+	SchedulingOutcomeOtherFailure = "other_failure"
+)
+
+// pluginFailureOutcomes classifies the failures by the plugins rejecting the Pods.
+// The plugin names are listed here since the plugins depend on the frameworkext.
+var pluginFailureOutcomes = map[string]string{
+	"NodeNUMAResource":    SchedulingOutcomeNUMAFailure,
+	"ElasticQuota":        SchedulingOutcomeQuotaFailure,
+	"LoadAwareScheduling": SchedulingOutcomeLoadFailure,
+}
+
+// getFailureOutcome returns the outcome of the failure by the plugin rejecting the Pod.
+func getFailureOutcome(status *framework.Status) string {
+	if outcome, ok := pluginFailureOutcomes[status.FailedPlugin()]; ok {
+		return outcome
+	}
+	return SchedulingOutcomeOtherFailure
+}
+
+// recordSchedulingSuccess records the Pod bound to the node in the pool of the node.
+func recordSchedulingSuccess(pod *corev1.Pod, node *corev1.Node) {
+	pool := apiext.GetNodePool(node.Labels)
+	NodePoolSchedulingOutcomes.WithLabelValues(pool, SchedulingOutcomeSuccess).Inc()
+	if !pod.CreationTimestamp.IsZero() {
+		NodePoolPodPendingDuration.WithLabelValues(pool).Observe(time.Since(pod.CreationTimestamp.Time).Seconds())
+	}
+}
+
+// recordSchedulingFailure records the failed scheduling attempt once in each node pool that the Pod was tried on.
+// The outcome of a pool is the failure rejecting the most nodes of the pool, which is the dominating constraint.
+func recordSchedulingFailure(fitErr *framework.FitError, nodeLister corelisters.NodeLister) {
+	rejections := map[string]map[string]int{}
+	for nodeName, status := range fitErr.Diagnosis.NodeToStatusMap {
+		pool := ""
+		if node, err := nodeLister.Get(nodeName); err == nil {
+			pool = apiext.GetNodePool(node.Labels)
+		}
+		if rejections[pool] == nil {
+			rejections[pool] = map[string]int{}
+		}
+		rejections[pool][getFailureOutcome(status)]++
+	}
+	for pool, outcomes := range rejections {
+		var dominant string
+		for outcome, count := range outcomes {
+			if count > outcomes[dominant] || (count == outcomes[dominant] && outcome < dominant) {
+				dominant = outcome
+			}
+		}
+		NodePoolSchedulingOutcomes.WithLabelValues(pool, dominant).Inc()
+	}
+}
+
+// MakeSchedulingOutcomeErrorHandler returns the PreErrorHandlerFilter recording the failed scheduling attempts
+// per node pool. It never intercepts the error, so the other handlers still run.
+func MakeSchedulingOutcomeErrorHandler(nodeLister corelisters.NodeLister) PreErrorHandlerFilter {
+	return func(podInfo *framework.QueuedPodInfo, err error) bool {
+		var fitErr *framework.FitError
+		if errors.As(err, &fitErr) {
+			recordSchedulingFailure(fitErr, nodeLister)
+		}
+		return false
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newPoolNode(name, pool string) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if pool != "" {
+		node.Labels = map[string]string{apiext.LabelNodePool: pool}
+	}
+	return node
+}
+
+func TestGetFailureOutcome(t *testing.T) {
+	tests := []struct {
+		status *framework.Status
+		want   string
+	}{
+		{
+			status: framework.NewStatus(framework.Unschedulable, "Insufficient NUMA cpu").WithFailedPlugin("NodeNUMAResource"),
+			want:   SchedulingOutcomeNUMAFailure,
+		},
+		{
+			status: framework.NewStatus(framework.Unschedulable, "Insufficient quota").WithFailedPlugin("ElasticQuota"),
+			want:   SchedulingOutcomeQuotaFailure,
+		},
+		{
+			status: framework.NewStatus(framework.Unschedulable, "node(s) usage exceed threshold").WithFailedPlugin("LoadAwareScheduling"),
+			want:   SchedulingOutcomeLoadFailure,
+		},
+		{
+			status: framework.NewStatus(framework.Unschedulable, "Insufficient cpu").WithFailedPlugin("NodeResourcesFit"),
+			want:   SchedulingOutcomeOtherFailure,
+		},
+		{
+			status: framework.NewStatus(framework.Unschedulable, "unknown"),
+			want:   SchedulingOutcomeOtherFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, getFailureOutcome(tt.status))
+		})
+	}
+}
+
+func TestSchedulingOutcomeErrorHandler(t *testing.T) {
+	RegisterMetrics()
+	NodePoolSchedulingOutcomes.Reset()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range []*corev1.Node{
+		newPoolNode("node-a-1", "pool-a"),
+		newPoolNode("node-a-2", "pool-a"),
+		newPoolNode("node-a-3", "pool-a"),
+		newPoolNode("node-b-1", "pool-b"),
+		newPoolNode("node-c-1", ""),
+	} {
+		assert.NoError(t, indexer.Add(node))
+	}
+	handler := MakeSchedulingOutcomeErrorHandler(corelisters.NewNodeLister(indexer))
+
+	numaStatus := framework.NewStatus(framework.Unschedulable, "Insufficient NUMA cpu").WithFailedPlugin("NodeNUMAResource")
+	loadStatus := framework.NewStatus(framework.Unschedulable, "node(s) usage exceed threshold").WithFailedPlugin("LoadAwareScheduling")
+	fitErr := &framework.FitError{
+		Pod:         &corev1.Pod{},
+		NumAllNodes: 6,
+		Diagnosis: framework.Diagnosis{
+			NodeToStatusMap: framework.NodeToStatusMap{
+				"node-a-1": numaStatus,
+				"node-a-2": numaStatus,
+				"node-a-3": loadStatus,
+				"node-b-1": loadStatus,
+				"node-c-1": numaStatus,
+				// the node deleted during the scheduling is counted without the pool
+				"node-d-1": loadStatus,
+			},
+		},
+	}
+	assert.False(t, handler(&framework.QueuedPodInfo{}, fitErr))
+	assert.False(t, handler(&framework.QueuedPodInfo{}, fmt.Errorf("binding rejected")))
+
+	for _, tt := range []struct {
+		pool    string
+		outcome string
+		want    float64
+	}{
+		{pool: "pool-a", outcome: SchedulingOutcomeNUMAFailure, want: 1},
+		{pool: "pool-a", outcome: SchedulingOutcomeLoadFailure, want: 0},
+		{pool: "pool-b", outcome: SchedulingOutcomeLoadFailure, want: 1},
+		// the tie is broken by the name of the outcome
+		{pool: "", outcome: SchedulingOutcomeLoadFailure, want: 1},
+		{pool: "", outcome: SchedulingOutcomeNUMAFailure, want: 0},
+	} {
+		count, err := testutil.GetCounterMetricValue(NodePoolSchedulingOutcomes.WithLabelValues(tt.pool, tt.outcome))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, count, "pool %q, outcome %s", tt.pool, tt.outcome)
+	}
+}
+
+func TestRecordSchedulingSuccess(t *testing.T) {
+	RegisterMetrics()
+	NodePoolSchedulingOutcomes.Reset()
+	NodePoolPodPendingDuration.Reset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+	}
+	recordSchedulingSuccess(pod, newPoolNode("node-a-1", "pool-a"))
+	recordSchedulingSuccess(&corev1.Pod{}, newPoolNode("node-a-2", "pool-a"))
+
+	count, err := testutil.GetCounterMetricValue(NodePoolSchedulingOutcomes.WithLabelValues("pool-a", SchedulingOutcomeSuccess))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), count)
+	observed, err := testutil.GetHistogramMetricCount(NodePoolPodPendingDuration.WithLabelValues("pool-a"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), observed, "the Pod without the creation timestamp is not observed")
+}
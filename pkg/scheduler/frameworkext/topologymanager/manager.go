@@ -39,6 +39,17 @@ type NUMATopologyHintProvider interface {
 	Allocate(ctx context.Context, cycleState *framework.CycleState, affinity NUMATopologyHint, pod *corev1.Pod, nodeName string) *framework.Status
 }
 
+// NUMAResourceProvider provides the remaining resources of NUMA Nodes, so that other plugins
+// such as DeviceShare can take the NUMA resource headroom into account.
+type NUMAResourceProvider interface {
+	// GetAvailableNUMANodeResources returns the available resources of each NUMA Node on the node.
+	GetAvailableNUMANodeResources(nodeName string) (map[int]corev1.ResourceList, error)
+}
+
+type NUMAResourceProviderFactory interface {
+	GetNUMAResourceProvider() NUMAResourceProvider
+}
+
 var _ Interface = &topologyManager{}
 
 type topologyManager struct {
@@ -92,7 +103,7 @@ func (m *topologyManager) accumulateProvidersHints(ctx context.Context, cycleSta
 	hintProviders := m.hintProviderFactory.GetNUMATopologyHintProvider()
 	for _, provider := range hintProviders {
 		// Get the TopologyHints for a Pod from a provider.
-		hints, _ := provider.GetPodTopologyHints(ctx, cycleState, pod, nodeName)
+		hints := getPodTopologyHintsWithTimeout(ctx, provider, cycleState, pod, nodeName)
 		providersHints = append(providersHints, hints)
 		klog.V(5).Infof("TopologyHints for pod '%v': %v on node: %v", klog.KObj(pod), hints, nodeName)
 	}
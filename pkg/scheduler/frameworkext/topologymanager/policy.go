@@ -21,8 +21,14 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
+	"github.com/koordinator-sh/koordinator/pkg/util/topologyhint"
 )
 
+// NUMATopologyHint is a struct containing the NUMANodeAffinity for a Container.
+// It is defined in the framework-free package topologyhint, so that the hint providers
+// can be reused outside the scheduler framework.
+type NUMATopologyHint = topologyhint.NUMATopologyHint
+
 type Policy interface {
 	// Name returns Policy Name
 	Name() string
@@ -30,35 +36,6 @@ type Policy interface {
 	Merge(providersHints []map[string][]NUMATopologyHint) (NUMATopologyHint, bool)
 }
 
-// NUMATopologyHint is a struct containing the NUMANodeAffinity for a Container
-type NUMATopologyHint struct {
-	NUMANodeAffinity bitmask.BitMask
-	// Preferred is set to true when the NUMANodeAffinity encodes a preferred
-	// allocation for the Pod. It is set to false otherwise.
-	Preferred bool
-}
-
-// IsEqual checks if NUMATopologyHint are equal
-func (th *NUMATopologyHint) IsEqual(topologyHint NUMATopologyHint) bool {
-	if th.Preferred == topologyHint.Preferred {
-		if th.NUMANodeAffinity == nil || topologyHint.NUMANodeAffinity == nil {
-			return th.NUMANodeAffinity == topologyHint.NUMANodeAffinity
-		}
-		return th.NUMANodeAffinity.IsEqual(topologyHint.NUMANodeAffinity)
-	}
-	return false
-}
-
-// LessThan checks if NUMATopologyHint `a` is less than NUMATopologyHint `b`
-// this means that either `a` is a preferred hint and `b` is not
-// or `a` NUMANodeAffinity attribute is narrower than `b` NUMANodeAffinity attribute.
-func (th *NUMATopologyHint) LessThan(other NUMATopologyHint) bool {
-	if th.Preferred != other.Preferred {
-		return th.Preferred
-	}
-	return th.NUMANodeAffinity.IsNarrowerThan(other.NUMANodeAffinity)
-}
-
 // Merge a TopologyHints permutation to a single hint by performing a bitwise-AND
 // of their affinity masks. The hint shall be preferred if all hits in the permutation
 // are preferred.
@@ -85,7 +62,7 @@ func mergePermutation(numaNodes []int, permutation []NUMATopologyHint) NUMATopol
 	mergedAffinity := bitmask.And(defaultAffinity, numaAffinities...)
 	// Build a mergedHint from the merged affinity mask, indicating if an
 	// preferred allocation was used to generate the affinity mask or not.
-	return NUMATopologyHint{mergedAffinity, preferred}
+	return NUMATopologyHint{NUMANodeAffinity: mergedAffinity, Preferred: preferred}
 }
 
 func filterProvidersHints(providersHints []map[string][]NUMATopologyHint) [][]NUMATopologyHint {
@@ -97,7 +74,7 @@ func filterProvidersHints(providersHints []map[string][]NUMATopologyHint) [][]NU
 		// If hints is nil, insert a single, preferred any-numa hint into allProviderHints.
 		if len(hints) == 0 {
 			klog.V(5).Infof("[topologymanager] Hint Provider has no preference for NUMA affinity with any resource")
-			allProviderHints = append(allProviderHints, []NUMATopologyHint{{nil, true}})
+			allProviderHints = append(allProviderHints, []NUMATopologyHint{{Preferred: true}})
 			continue
 		}
 
@@ -105,13 +82,13 @@ func filterProvidersHints(providersHints []map[string][]NUMATopologyHint) [][]NU
 		for resource := range hints {
 			if hints[resource] == nil {
 				klog.V(5).Infof("[topologymanager] Hint Provider has no preference for NUMA affinity with resource '%s'", resource)
-				allProviderHints = append(allProviderHints, []NUMATopologyHint{{nil, true}})
+				allProviderHints = append(allProviderHints, []NUMATopologyHint{{Preferred: true}})
 				continue
 			}
 
 			if len(hints[resource]) == 0 {
 				klog.V(5).Infof("[topologymanager] Hint Provider has no possible NUMA affinities for resource '%s'", resource)
-				allProviderHints = append(allProviderHints, []NUMATopologyHint{{nil, false}})
+				allProviderHints = append(allProviderHints, []NUMATopologyHint{{Preferred: false}})
 				continue
 			}
 
@@ -129,7 +106,7 @@ func mergeFilteredHints(numaNodes []int, filteredHints [][]NUMATopologyHint) NUM
 	// Set the bestHint to return from this function as {nil false}.
 	// This will only be returned if no better hint can be found when
 	// merging hints from each hint provider.
-	bestHint := NUMATopologyHint{defaultAffinity, false}
+	bestHint := NUMATopologyHint{NUMANodeAffinity: defaultAffinity, Preferred: false}
 	iterateAllProviderTopologyHints(filteredHints, func(permutation []NUMATopologyHint) {
 		// Get the NUMANodeAffinity from each hint in the permutation and see if any
 		// of them encode unpreferred allocations.
@@ -70,7 +70,7 @@ func (p *singleNumaNodePolicy) Merge(providersHints []map[string][]NUMATopologyH
 
 	defaultAffinity, _ := bitmask.NewBitMask(p.numaNodes...)
 	if bestHint.NUMANodeAffinity.IsEqual(defaultAffinity) {
-		bestHint = NUMATopologyHint{nil, bestHint.Preferred}
+		bestHint = NUMATopologyHint{Preferred: bestHint.Preferred}
 	}
 
 	admit := p.canAdmitPodResult(&bestHint)
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var (
+	// DefaultHintProviderTimeout is how long the manager waits for the hints of a provider.
+	// If a provider times out, the hints of the other providers are merged as if it has no NUMA preference,
+	// so that a slow provider doesn't block the whole scheduling cycle. Zero means no timeout.
+	DefaultHintProviderTimeout time.Duration
+	// HintProviderTimeouts overrides DefaultHintProviderTimeout by the provider name.
+	HintProviderTimeouts = HintProviderTimeoutsFlag{}
+)
+
+var (
+	HintProviderTimeoutsTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler",
+			Name:           "numa_hint_provider_timeouts_total",
+			Help:           "Number of the timeouts of getting the NUMA topology hints, by the hint provider",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"provider"})
+
+	// MetricsList are the metrics of the topology manager, which are registered by the framework extender.
+	MetricsList = []metrics.Registerable{
+		HintProviderTimeoutsTotal,
+	}
+)
+
+// HintProviderTimeoutsFlag is the flag value of the hint provider timeouts, e.g. "DeviceShare=50ms,NodeNUMAResource=100ms".
+type HintProviderTimeoutsFlag map[string]time.Duration
+
+func (f HintProviderTimeoutsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, timeout := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, timeout))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f HintProviderTimeoutsFlag) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid hint provider timeout %q, expect name=duration", pair)
+		}
+		timeout, err := time.ParseDuration(kv[1])
+		if err != nil {
+			return fmt.Errorf("invalid hint provider timeout %q, err: %w", pair, err)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("invalid hint provider timeout %q, expect non-negative duration", pair)
+		}
+		f[kv[0]] = timeout
+	}
+	return nil
+}
+
+func (f HintProviderTimeoutsFlag) Type() string {
+	return "mapStringDuration"
+}
+
+// hintProviderName returns the name of the provider, e.g. the plugin name.
+func hintProviderName(provider NUMATopologyHintProvider) string {
+	if named, ok := provider.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", provider)
+}
+
+func getHintProviderTimeout(name string) time.Duration {
+	if timeout, ok := HintProviderTimeouts[name]; ok {
+		return timeout
+	}
+	return DefaultHintProviderTimeout
+}
+
+// getPodTopologyHintsWithTimeout returns the hints of the provider, or no hints if the provider times out.
+// The context passed to the provider is cancelled when it times out, so the provider should give up its work then,
+// and the hints it returns after the timeout are discarded.
+func getPodTopologyHintsWithTimeout(ctx context.Context, provider NUMATopologyHintProvider, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) map[string][]NUMATopologyHint {
+	name := hintProviderName(provider)
+	timeout := getHintProviderTimeout(name)
+	if timeout <= 0 {
+		hints, _ := provider.GetPodTopologyHints(ctx, cycleState, pod, nodeName)
+		return hints
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// the result is buffered so that the provider returning after the timeout doesn't leak the goroutine
+	result := make(chan map[string][]NUMATopologyHint, 1)
+	go func() {
+		hints, _ := provider.GetPodTopologyHints(ctx, cycleState, pod, nodeName)
+		result <- hints
+	}()
+
+	select {
+	case hints := <-result:
+		return hints
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			HintProviderTimeoutsTotal.WithLabelValues(name).Inc()
+		}
+		klog.V(4).InfoS("NUMA topology hint provider timed out, merge the hints of the other providers",
+			"provider", name, "pod", klog.KObj(pod), "node", nodeName, "timeout", timeout, "err", ctx.Err())
+		return nil
+	}
+}
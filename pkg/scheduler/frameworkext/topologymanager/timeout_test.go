@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type slowNUMATopologyHintProvider struct {
+	mockNUMATopologyHintProvider
+	name      string
+	delay     time.Duration
+	cancelled chan struct{}
+}
+
+func (m *slowNUMATopologyHintProvider) Name() string {
+	return m.name
+}
+
+func (m *slowNUMATopologyHintProvider) GetPodTopologyHints(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (map[string][]NUMATopologyHint, *framework.Status) {
+	select {
+	case <-time.After(m.delay):
+		return m.th, nil
+	case <-ctx.Done():
+		if m.cancelled != nil {
+			close(m.cancelled)
+		}
+		return nil, framework.AsStatus(ctx.Err())
+	}
+}
+
+type fakeHintProviderFactory []NUMATopologyHintProvider
+
+func (f fakeHintProviderFactory) GetNUMATopologyHintProvider() []NUMATopologyHintProvider {
+	return f
+}
+
+func TestAccumulateProvidersHintsWithTimeout(t *testing.T) {
+	defer func() {
+		DefaultHintProviderTimeout = 0
+		HintProviderTimeouts = HintProviderTimeoutsFlag{}
+	}()
+
+	fastHints := map[string][]NUMATopologyHint{
+		"cpu": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+	}
+	slowHints := map[string][]NUMATopologyHint{
+		"gpu": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+	}
+	slowProvider := &slowNUMATopologyHintProvider{mockNUMATopologyHintProvider: mockNUMATopologyHintProvider{th: slowHints}, name: "slow", delay: 200 * time.Millisecond}
+	m := &topologyManager{
+		hintProviderFactory: fakeHintProviderFactory{
+			&slowNUMATopologyHintProvider{mockNUMATopologyHintProvider: mockNUMATopologyHintProvider{th: fastHints}, name: "fast"},
+			slowProvider,
+		},
+	}
+
+	// no timeout by default
+	got := m.accumulateProvidersHints(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, "test-node")
+	assert.Equal(t, []map[string][]NUMATopologyHint{fastHints, slowHints}, got)
+
+	// the slow provider times out and has no preference
+	DefaultHintProviderTimeout = 10 * time.Millisecond
+	slowProvider.cancelled = make(chan struct{})
+	got = m.accumulateProvidersHints(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, "test-node")
+	assert.Equal(t, []map[string][]NUMATopologyHint{fastHints, nil}, got)
+	select {
+	case <-slowProvider.cancelled:
+	case <-time.After(time.Second):
+		t.Error("the context of the timed out provider is not cancelled")
+	}
+	slowProvider.cancelled = nil
+	bestHint, admit := NewBestEffortPolicy([]int{0, 1}).Merge(got)
+	assert.True(t, admit)
+	assert.Equal(t, NUMATopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}, bestHint)
+
+	// the timeout of the slow provider is overridden
+	assert.NoError(t, HintProviderTimeouts.Set("slow=1s"))
+	got = m.accumulateProvidersHints(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, "test-node")
+	assert.Equal(t, []map[string][]NUMATopologyHint{fastHints, slowHints}, got)
+}
+
+func TestHintProviderTimeoutsFlag(t *testing.T) {
+	f := HintProviderTimeoutsFlag{}
+	assert.NoError(t, f.Set("DeviceShare=50ms,NodeNUMAResource=1s"))
+	assert.Equal(t, HintProviderTimeoutsFlag{"DeviceShare": 50 * time.Millisecond, "NodeNUMAResource": time.Second}, f)
+	assert.Equal(t, "DeviceShare=50ms,NodeNUMAResource=1s", f.String())
+	assert.Error(t, f.Set("DeviceShare"))
+	assert.Error(t, f.Set("DeviceShare=abc"))
+	assert.Error(t, f.Set("DeviceShare=-1s"))
+}
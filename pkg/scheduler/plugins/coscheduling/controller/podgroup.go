@@ -42,6 +42,7 @@ import (
 	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
 	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/core"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
 )
@@ -129,7 +130,11 @@ func (ctrl *PodGroupController) pgAdded(obj interface{}) {
 		return
 	}
 	pg := obj.(*schedv1alpha1.PodGroup)
-	if pg.Status.Phase == schedv1alpha1.PodGroupFinished || pg.Status.Phase == schedv1alpha1.PodGroupFailed {
+	if pg.Status.Phase == schedv1alpha1.PodGroupFinished {
+		return
+	}
+	// the PodGroup failed by the quota can be recovered once the quota is enlarged
+	if _, ok := pg.Annotations[extension.AnnotationGangQuotaExceeded]; pg.Status.Phase == schedv1alpha1.PodGroupFailed && !ok {
 		return
 	}
 	// If startScheduleTime - createTime > 2days, do not enqueue again because pod may have been GCed
@@ -237,6 +242,19 @@ func (ctrl *PodGroupController) syncHandler(key string) error {
 		pods = append(pods, podFromInformer)
 	}
 
+	// the gang can never be admitted if its min resources exceed the max of its quota
+	var quotaExceededMessage string
+	if len(pods) > 0 {
+		quotaExceededMessage = ctrl.pgManager.GetQuotaExceededMessage(pods[0])
+	}
+	if core.SetPodGroupQuotaExceeded(pgCopy, quotaExceededMessage) {
+		err = ctrl.patchPodGroup(pg, pgCopy)
+		if err == nil {
+			ctrl.pgQueue.Forget(pg)
+		}
+		return err
+	}
+
 	switch pgCopy.Status.Phase {
 	case "":
 		pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
@@ -36,6 +37,7 @@ import (
 	pgfake "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
 	schedinformer "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
@@ -263,6 +265,91 @@ func TestFillGroupStatusOccupied(t *testing.T) {
 	}
 }
 
+func Test_QuotaExceeded(t *testing.T) {
+	ctx := context.TODO()
+	const message = "min resources of gang exceed the max of quota quota1 on [cpu]"
+	cases := []struct {
+		name              string
+		pgName            string
+		previousPhase     v1alpha1.PodGroupPhase
+		marked            bool
+		quotaMaxCPU       string
+		desiredGroupPhase v1alpha1.PodGroupPhase
+		desiredAnnotation string
+	}{
+		{
+			name:              "Group failed by quota",
+			pgName:            "pg1",
+			previousPhase:     v1alpha1.PodGroupPending,
+			quotaMaxCPU:       "4",
+			desiredGroupPhase: v1alpha1.PodGroupFailed,
+			desiredAnnotation: message,
+		},
+		{
+			name:              "Group failed by quota recovered after quota enlarged",
+			pgName:            "pg2",
+			previousPhase:     v1alpha1.PodGroupFailed,
+			marked:            true,
+			quotaMaxCPU:       "16",
+			desiredGroupPhase: v1alpha1.PodGroupPreScheduling,
+		},
+		{
+			name:              "Group running not failed by quota",
+			pgName:            "pg3",
+			previousPhase:     v1alpha1.PodGroupScheduled,
+			quotaMaxCPU:       "4",
+			desiredGroupPhase: v1alpha1.PodGroupRunning,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ps := makePods([]string{"pod1", "pod2"}, c.pgName, v1.PodRunning, nil)
+			for _, p := range ps {
+				p.Labels[extension.LabelQuotaName] = "quota1"
+			}
+			kubeClient := fake.NewSimpleClientset(ps[0], ps[1])
+			pg := makePG(c.pgName, 2, c.previousPhase, nil)
+			pg.Spec.MinResources = &v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")}
+			if c.marked {
+				pg.Annotations = map[string]string{extension.AnnotationGangQuotaExceeded: message}
+			}
+			quota := &v1alpha1.ElasticQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota1", Namespace: "default"},
+				Spec: v1alpha1.ElasticQuotaSpec{
+					Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse(c.quotaMaxCPU)},
+				},
+			}
+			pgClient := pgfake.NewSimpleClientset(pg, quota)
+
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+			pgInformerFactory := schedinformer.NewSharedInformerFactory(pgClient, controller.NoResyncPeriodFunc())
+			koordInformerFactory := koordinformers.NewSharedInformerFactory(koordfake.NewSimpleClientset(), 0)
+			args := &config.CoschedulingArgs{DefaultTimeout: &metav1.Duration{Duration: time.Second}}
+			pgMgr := core.NewPodGroupManager(args, pgClient, pgInformerFactory, informerFactory, koordInformerFactory)
+			ctrl := NewPodGroupController(pgInformerFactory.Scheduling().V1alpha1().PodGroups(),
+				informerFactory.Core().V1().Pods(), pgClient, pgMgr, 1)
+
+			go ctrl.Start()
+			err := wait.Poll(200*time.Millisecond, 1*time.Second, func() (done bool, err error) {
+				pg, err := pgClient.SchedulingV1alpha1().PodGroups("default").Get(ctx, c.pgName, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if pg.Status.Phase != c.desiredGroupPhase {
+					return false, fmt.Errorf("want %v, got %v", c.desiredGroupPhase, pg.Status.Phase)
+				}
+				if pg.Annotations[extension.AnnotationGangQuotaExceeded] != c.desiredAnnotation {
+					return false, fmt.Errorf("want annotation %v, got %v", c.desiredAnnotation, pg.Annotations[extension.AnnotationGangQuotaExceeded])
+				}
+				return true, nil
+			})
+			if err != nil {
+				t.Fatal("Unexpected error", err)
+			}
+		})
+	}
+}
+
 func setUp(ctx context.Context, podNames []string, pgName string, podPhase v1.PodPhase, minMember int32, groupPhase v1alpha1.PodGroupPhase, podGroupCreateTime *metav1.Time, podOwnerReference []metav1.OwnerReference) (*PodGroupController, *fake.Clientset, *pgfake.Clientset) {
 	var kubeClient *fake.Clientset
 	if len(podNames) == 0 {
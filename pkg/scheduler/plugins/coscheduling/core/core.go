@@ -73,6 +73,7 @@ type Manager interface {
 	GetGangSummaries() map[string]*GangSummary
 	IsGangMinSatisfied(*corev1.Pod) bool
 	GetChildScheduleCycle(*corev1.Pod) int
+	GetQuotaExceededMessage(*corev1.Pod) string
 }
 
 // PodGroupManager defines the scheduling operation called
@@ -84,6 +85,8 @@ type PodGroupManager struct {
 	pgLister pglister.PodGroupLister
 	// podLister is pod lister
 	podLister listerv1.PodLister
+	// quotaIndexer is elastic quota indexer
+	quotaIndexer cache.Indexer
 	// reserveResourcePercentage is the reserved resource for the max finished group, range (0,100]
 	reserveResourcePercentage int32
 	// cache stores gang info
@@ -100,14 +103,19 @@ func NewPodGroupManager(
 	koordSharedInformerFactory koordinatorinformers.SharedInformerFactory,
 ) *PodGroupManager {
 	pgInformer := pgSharedInformerFactory.Scheduling().V1alpha1().PodGroups()
+	quotaInformer := pgSharedInformerFactory.Scheduling().V1alpha1().ElasticQuotas().Informer()
+	if err := quotaInformer.AddIndexers(cache.Indexers{quotaNameIndex: quotaNameIndexFunc}); err != nil {
+		klog.ErrorS(err, "failed to add quota name indexer")
+	}
 	podInformer := sharedInformerFactory.Core().V1().Pods()
 	gangCache := NewGangCache(args, podInformer.Lister(), pgInformer.Lister(), pgClient)
 	pgMgr := &PodGroupManager{
-		args:      args,
-		pgClient:  pgClient,
-		pgLister:  pgInformer.Lister(),
-		podLister: podInformer.Lister(),
-		cache:     gangCache,
+		args:         args,
+		pgClient:     pgClient,
+		pgLister:     pgInformer.Lister(),
+		podLister:    podInformer.Lister(),
+		quotaIndexer: quotaInformer.GetIndexer(),
+		cache:        gangCache,
 	}
 
 	podGroupEventHandler := cache.ResourceEventHandlerFuncs{
@@ -215,8 +223,9 @@ func (pgMgr *PodGroupManager) ActivateSiblings(pod *corev1.Pod, state *framework
 // i.Check whether children in Gang has met the requirements of minimum number under each Gang, and reject the pod if negative.
 // ii.Check whether the Gang is inited, and reject the pod if positive.
 // iii.Check whether the Gang is OnceResourceSatisfied
-// iv.Check whether the Gang has met the scheduleCycleValid check, and reject the pod if negative(only Strict mode ).
-// v.Try update scheduleCycle, scheduleCycleValid, childrenScheduleRoundMap as mentioned above.
+// iv.Check whether the min resources of the Gang exceed the max of its quota, and reject the pod if positive.
+// v.Check whether the Gang has met the scheduleCycleValid check, and reject the pod if negative(only Strict mode ).
+// vi.Try update scheduleCycle, scheduleCycleValid, childrenScheduleRoundMap as mentioned above.
 func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) error {
 	if !util.IsPodNeedGang(pod) {
 		return nil
@@ -237,6 +246,11 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 		return nil
 	}
 
+	// check whether the gang can ever fit within its quota
+	if err := pgMgr.checkQuotaAdmission(pod); err != nil {
+		return err
+	}
+
 	// check minNum
 	if gang.getChildrenNum() < gang.getGangMinNum() {
 		return fmt.Errorf("gang child pod not collect enough, gangName: %v, podName: %v", gang.Name,
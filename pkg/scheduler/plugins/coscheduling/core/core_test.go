@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
@@ -314,6 +315,229 @@ func TestPlugin_PreFilter(t *testing.T) {
 	}
 }
 
+func TestPreFilterQuotaAdmission(t *testing.T) {
+	minResources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("8"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+	}
+	quotaExceededPg := func() *v1alpha1.PodGroup {
+		pg := makePg("gang1", "ns1", 1, nil, &minResources)
+		pg.Annotations = map[string]string{
+			extension.AnnotationGangQuotaExceeded: "min resources of gang exceed the max of quota quota1 on [cpu]",
+		}
+		pg.Status.Phase = v1alpha1.PodGroupFailed
+		return pg
+	}
+	tests := []struct {
+		name                 string
+		pg                   *v1alpha1.PodGroup
+		quotas               []*v1alpha1.ElasticQuota
+		podQuotaName         string
+		expectedErrorMessage string
+	}{
+		{
+			name:         "podGroup without min resources",
+			pg:           makePg("gang1", "ns1", 1, nil, nil),
+			quotas:       []*v1alpha1.ElasticQuota{makeQuota("quota1", "ns1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})},
+			podQuotaName: "quota1",
+		},
+		{
+			name: "pod without quota",
+			pg:   makePg("gang1", "ns1", 1, nil, &minResources),
+		},
+		{
+			name:         "min resources within quota max",
+			pg:           makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas:       []*v1alpha1.ElasticQuota{makeQuota("quota1", "ns1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")})},
+			podQuotaName: "quota1",
+		},
+		{
+			name:                 "min resources exceed quota max",
+			pg:                   makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas:               []*v1alpha1.ElasticQuota{makeQuota("quota1", "ns1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})},
+			podQuotaName:         "quota1",
+			expectedErrorMessage: "min resources of gang exceed the max of quota quota1 on [cpu], gangName: ns1/gang1, podName: ns1/pod1",
+		},
+		{
+			name: "min resources exceed max of quota in another namespace",
+			pg:   makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas: []*v1alpha1.ElasticQuota{
+				makeQuota("ns1", "ns1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}),
+				makeQuota("quota1", "quota-ns", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+			},
+			podQuotaName:         "quota1",
+			expectedErrorMessage: "min resources of gang exceed the max of quota quota1 on [cpu], gangName: ns1/gang1, podName: ns1/pod1",
+		},
+		{
+			name: "prefer the quota in the namespace of the pod",
+			pg:   makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas: []*v1alpha1.ElasticQuota{
+				makeQuota("quota1", "ns1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}),
+				makeQuota("quota1", "quota-ns", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+			},
+			podQuotaName: "quota1",
+		},
+		{
+			name: "prefer the quota serving the namespace of the pod",
+			pg:   makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas: []*v1alpha1.ElasticQuota{
+				makeQuotaWithNamespaces("quota1", "quota-ns", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}, `["ns1"]`),
+				makeQuota("quota1", "other-ns", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}),
+			},
+			podQuotaName:         "quota1",
+			expectedErrorMessage: "min resources of gang exceed the max of quota quota1 on [cpu], gangName: ns1/gang1, podName: ns1/pod1",
+		},
+		{
+			name: "skip the ambiguous quotas",
+			pg:   makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas: []*v1alpha1.ElasticQuota{
+				makeQuota("quota1", "quota-ns", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+				makeQuota("quota1", "other-ns", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+			},
+			podQuotaName: "quota1",
+		},
+		{
+			name:                 "min resources exceed max of namespace quota",
+			pg:                   makePg("gang1", "ns1", 1, nil, &minResources),
+			quotas:               []*v1alpha1.ElasticQuota{makeQuota("ns1", "ns1", corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")})},
+			expectedErrorMessage: "min resources of gang exceed the max of quota ns1 on [memory], gangName: ns1/gang1, podName: ns1/pod1",
+		},
+		{
+			name:         "admit podGroup failed by quota once quota is enlarged",
+			pg:           quotaExceededPg(),
+			quotas:       []*v1alpha1.ElasticQuota{makeQuota("quota1", "ns1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")})},
+			podQuotaName: "quota1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgClient := fakepgclientset.NewSimpleClientset()
+			_, err := pgClient.SchedulingV1alpha1().PodGroups(tt.pg.Namespace).Create(context.TODO(), tt.pg, metav1.CreateOptions{})
+			assert.NoError(t, err)
+			for _, quota := range tt.quotas {
+				_, err = pgClient.SchedulingV1alpha1().ElasticQuotas(quota.Namespace).Create(context.TODO(), quota, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			pgInformerFactory := pgformers.NewSharedInformerFactory(pgClient, 0)
+			informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+			koordInformerFactory := koordinformers.NewSharedInformerFactory(koordfake.NewSimpleClientset(), 0)
+			args := &config.CoschedulingArgs{DefaultTimeout: &metav1.Duration{Duration: 300 * time.Second}}
+			mgr := NewPodGroupManager(args, pgClient, pgInformerFactory, informerFactory, koordInformerFactory)
+
+			podWrapper := st.MakePod().Name("pod1").UID("pod1").Namespace(tt.pg.Namespace).Label(v1alpha1.PodGroupLabel, tt.pg.Name)
+			if tt.podQuotaName != "" {
+				podWrapper = podWrapper.Label(extension.LabelQuotaName, tt.podQuotaName)
+			}
+			pod := podWrapper.Obj()
+			mgr.cache.onPodAdd(pod)
+			err = mgr.PreFilter(context.TODO(), pod)
+			if tt.expectedErrorMessage == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErrorMessage)
+			}
+
+			// PreFilter never updates the PodGroup, which is synced by the PodGroupController
+			pg, err := pgClient.SchedulingV1alpha1().PodGroups(tt.pg.Namespace).Get(context.TODO(), tt.pg.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.pg, pg)
+		})
+	}
+}
+
+func TestSetPodGroupQuotaExceeded(t *testing.T) {
+	const message = "min resources of gang exceed the max of quota quota1 on [cpu]"
+	tests := []struct {
+		name               string
+		phase              v1alpha1.PodGroupPhase
+		marked             bool
+		message            string
+		expectedFailed     bool
+		expectedPhase      v1alpha1.PodGroupPhase
+		expectedAnnotation string
+	}{
+		{
+			name:               "mark pending podGroup failed",
+			phase:              v1alpha1.PodGroupPending,
+			message:            message,
+			expectedFailed:     true,
+			expectedPhase:      v1alpha1.PodGroupFailed,
+			expectedAnnotation: message,
+		},
+		{
+			name:               "mark preScheduling podGroup failed",
+			phase:              v1alpha1.PodGroupPreScheduling,
+			message:            message,
+			expectedFailed:     true,
+			expectedPhase:      v1alpha1.PodGroupFailed,
+			expectedAnnotation: message,
+		},
+		{
+			name:          "keep scheduled podGroup admitted before the quota changes",
+			phase:         v1alpha1.PodGroupScheduled,
+			message:       message,
+			expectedPhase: v1alpha1.PodGroupScheduled,
+		},
+		{
+			name:          "keep podGroup failed by pods",
+			phase:         v1alpha1.PodGroupFailed,
+			message:       message,
+			expectedPhase: v1alpha1.PodGroupFailed,
+		},
+		{
+			name:               "keep podGroup failed by quota",
+			phase:              v1alpha1.PodGroupFailed,
+			marked:             true,
+			message:            message,
+			expectedFailed:     true,
+			expectedPhase:      v1alpha1.PodGroupFailed,
+			expectedAnnotation: message,
+		},
+		{
+			name:          "recover podGroup failed by quota",
+			phase:         v1alpha1.PodGroupFailed,
+			marked:        true,
+			expectedPhase: v1alpha1.PodGroupPending,
+		},
+		{
+			name:          "clear the mark of podGroup scheduling after recovered",
+			phase:         v1alpha1.PodGroupScheduling,
+			marked:        true,
+			expectedPhase: v1alpha1.PodGroupScheduling,
+		},
+		{
+			name:          "keep podGroup failed by pods without mark",
+			phase:         v1alpha1.PodGroupFailed,
+			expectedPhase: v1alpha1.PodGroupFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := makePg("gang1", "ns1", 1, nil, nil)
+			pg.Status.Phase = tt.phase
+			if tt.marked {
+				pg.Annotations = map[string]string{extension.AnnotationGangQuotaExceeded: message}
+			}
+			assert.Equal(t, tt.expectedFailed, SetPodGroupQuotaExceeded(pg, tt.message))
+			assert.Equal(t, tt.expectedPhase, pg.Status.Phase)
+			assert.Equal(t, tt.expectedAnnotation, pg.Annotations[extension.AnnotationGangQuotaExceeded])
+		})
+	}
+}
+
+func makeQuota(name, namespace string, max corev1.ResourceList) *v1alpha1.ElasticQuota {
+	return &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       v1alpha1.ElasticQuotaSpec{Max: max},
+	}
+}
+
+func makeQuotaWithNamespaces(name, namespace string, max corev1.ResourceList, namespaces string) *v1alpha1.ElasticQuota {
+	quota := makeQuota(name, namespace, max)
+	quota.Annotations = map[string]string{extension.AnnotationQuotaNamespaces: namespaces}
+	return quota
+}
+
 // PostFilter logic test in Coscheduling_test, because without the plugin and framework,we cannot assert the waitingPods
 
 func TestPermit(t *testing.T) {
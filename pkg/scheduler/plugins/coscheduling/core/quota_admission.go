@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+)
+
+const (
+	// quotaNameIndex indexes the ElasticQuotas by name, since the quota-name label of the pod does not carry
+	// the namespace of the quota.
+	quotaNameIndex = "quotaName"
+)
+
+func quotaNameIndexFunc(obj interface{}) ([]string, error) {
+	quota, ok := obj.(*v1alpha1.ElasticQuota)
+	if !ok {
+		return []string{}, nil
+	}
+	return []string{quota.Name}, nil
+}
+
+// checkQuotaAdmission rejects the gang whose min resources exceed the max of its ElasticQuota, since such a gang
+// can never be admitted however the runtime quota changes. The PodGroup is marked failed by the PodGroupController.
+func (pgMgr *PodGroupManager) checkQuotaAdmission(pod *corev1.Pod) error {
+	message := pgMgr.GetQuotaExceededMessage(pod)
+	if message == "" {
+		return nil
+	}
+	return fmt.Errorf("%s, gangName: %v, podName: %v", message, util.GetId(pod.Namespace, util.GetGangNameByPod(pod)),
+		util.GetId(pod.Namespace, pod.Name))
+}
+
+// GetQuotaExceededMessage returns the reason why the min resources of the gang of the pod exceed the max of
+// its ElasticQuota. It returns empty if the gang can fit within the quota or the quota is unknown.
+func (pgMgr *PodGroupManager) GetQuotaExceededMessage(pod *corev1.Pod) string {
+	if pgMgr.quotaIndexer == nil {
+		return ""
+	}
+	_, pg := pgMgr.GetPodGroup(pod)
+	if pg == nil || pg.Spec.MinResources == nil || len(*pg.Spec.MinResources) == 0 {
+		return ""
+	}
+	quota, err := pgMgr.getPodQuota(pod)
+	if err != nil {
+		klog.V(4).InfoS("failed to get quota of the gang", "gang", util.GetId(pg.Namespace, pg.Name), "err", err)
+		return ""
+	}
+	if quota == nil || len(quota.Spec.Max) == 0 {
+		return ""
+	}
+	minResources := quotav1.Mask(*pg.Spec.MinResources, quotav1.ResourceNames(quota.Spec.Max))
+	if satisfied, exceeded := quotav1.LessThanOrEqual(minResources, quota.Spec.Max); !satisfied {
+		return fmt.Sprintf("min resources of gang exceed the max of quota %s on %v", quota.Name, exceeded)
+	}
+	return ""
+}
+
+// getPodQuota returns the ElasticQuota the pod belongs to. The pod is associated with the quota by the quota-name
+// label, otherwise with the quota named after its namespace. It returns nil if no quota is found.
+// Since the quotas of the same name may exist in different namespaces or quota trees, the quota in the namespace of
+// the pod or serving the namespace of the pod is preferred, and it returns an error if the quota is still ambiguous.
+func (pgMgr *PodGroupManager) getPodQuota(pod *corev1.Pod) (*v1alpha1.ElasticQuota, error) {
+	quotaName := extension.GetQuotaName(pod)
+	if quotaName == "" {
+		obj, exists, err := pgMgr.quotaIndexer.GetByKey(util.GetId(pod.Namespace, pod.Namespace))
+		if err != nil || !exists {
+			return nil, err
+		}
+		return obj.(*v1alpha1.ElasticQuota), nil
+	}
+	objs, err := pgMgr.quotaIndexer.ByIndex(quotaNameIndex, quotaName)
+	if err != nil || len(objs) == 0 {
+		return nil, err
+	}
+	if len(objs) == 1 {
+		return objs[0].(*v1alpha1.ElasticQuota), nil
+	}
+
+	var matched []*v1alpha1.ElasticQuota
+	for _, obj := range objs {
+		quota := obj.(*v1alpha1.ElasticQuota)
+		if quota.Namespace == pod.Namespace {
+			matched = append(matched, quota)
+		}
+	}
+	if len(matched) == 0 {
+		for _, obj := range objs {
+			quota := obj.(*v1alpha1.ElasticQuota)
+			for _, namespace := range extension.GetAnnotationQuotaNamespaces(quota) {
+				if namespace == pod.Namespace {
+					matched = append(matched, quota)
+					break
+				}
+			}
+		}
+	}
+	if len(matched) != 1 {
+		var trees []string
+		for _, obj := range objs {
+			quota := obj.(*v1alpha1.ElasticQuota)
+			trees = append(trees, fmt.Sprintf("%s(tree %q)", util.GetId(quota.Namespace, quota.Name), extension.GetQuotaTreeID(quota)))
+		}
+		return nil, fmt.Errorf("ambiguous quota %s for pod %s, candidates: %v", quotaName, util.GetId(pod.Namespace, pod.Name), trees)
+	}
+	return matched[0], nil
+}
+
+// SetPodGroupQuotaExceeded marks the PodGroup failed with the quota exceeded message if it is not scheduled yet,
+// or recovers the PodGroup marked failed by the quota once the message is cleared. It returns whether the PodGroup
+// stays failed for the quota.
+func SetPodGroupQuotaExceeded(pg *v1alpha1.PodGroup, message string) bool {
+	_, marked := pg.Annotations[extension.AnnotationGangQuotaExceeded]
+	if message == "" {
+		if !marked {
+			return false
+		}
+		delete(pg.Annotations, extension.AnnotationGangQuotaExceeded)
+		// only recover the phase failed by the quota, the gang is pending before it is marked
+		if pg.Status.Phase == v1alpha1.PodGroupFailed {
+			pg.Status.Phase = v1alpha1.PodGroupPending
+		}
+		return false
+	}
+
+	switch pg.Status.Phase {
+	case "", v1alpha1.PodGroupPending, v1alpha1.PodGroupPreScheduling:
+	case v1alpha1.PodGroupFailed:
+		if !marked {
+			// failed by the pods, not the quota
+			return false
+		}
+	default:
+		// the gang has been admitted before the quota changes
+		return false
+	}
+	if pg.Annotations == nil {
+		pg.Annotations = map[string]string{}
+	}
+	pg.Annotations[extension.AnnotationGangQuotaExceeded] = message
+	pg.Status.Phase = v1alpha1.PodGroupFailed
+	return true
+}
@@ -19,10 +19,12 @@ package deviceshare
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -47,6 +49,8 @@ type nodeDevice struct {
 	deviceFree  map[schedulingv1alpha1.DeviceType]deviceResources
 	deviceUsed  map[schedulingv1alpha1.DeviceType]deviceResources
 	allocateSet map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]deviceResources
+	// numaNodes records the NUMA Node of each device minor reported in Device topology.
+	numaNodes map[schedulingv1alpha1.DeviceType]map[int]int
 }
 
 func newNodeDevice() *nodeDevice {
@@ -106,12 +110,42 @@ func (n *nodeDevice) resetDeviceTotal(resources map[schedulingv1alpha1.DeviceTyp
 			resources[deviceType] = make(deviceResources)
 		}
 	}
-	n.deviceTotal = resources
-	for deviceType := range resources {
+	for deviceType, total := range resources {
+		// only the changed device types are reconciled
+		if _, ok := n.deviceFree[deviceType]; ok && equality.Semantic.DeepEqual(n.deviceTotal[deviceType], total) {
+			continue
+		}
+		n.deviceTotal[deviceType] = total
 		n.resetDeviceFree(deviceType)
 	}
 }
 
+// getPodsOnUnavailableDevices returns the devices allocated to each pod which are available in the current deviceTotal
+// but become unhealthy or removed in the given resources.
+func (n *nodeDevice) getPodsOnUnavailableDevices(resources map[schedulingv1alpha1.DeviceType]deviceResources) map[types.NamespacedName][]string {
+	var pods map[types.NamespacedName][]string
+	for deviceType, total := range n.deviceTotal {
+		for minor, res := range total {
+			if quotav1.IsZero(res) || !quotav1.IsZero(resources[deviceType][minor]) {
+				continue
+			}
+			for podNamespacedName, allocated := range n.allocateSet[deviceType] {
+				if _, ok := allocated[minor]; !ok {
+					continue
+				}
+				if pods == nil {
+					pods = map[types.NamespacedName][]string{}
+				}
+				pods[podNamespacedName] = append(pods[podNamespacedName], fmt.Sprintf("%s-%d", deviceType, minor))
+			}
+		}
+	}
+	for _, devices := range pods {
+		sort.Strings(devices)
+	}
+	return pods
+}
+
 // updateCacheUsed is used to update deviceUsed when there is a new pod created/deleted
 func (n *nodeDevice) updateCacheUsed(deviceAllocations apiext.DeviceAllocations, pod *corev1.Pod, add bool) {
 	if len(deviceAllocations) > 0 {
@@ -148,6 +182,7 @@ func (n *nodeDevice) getUsed(namespace, name string) map[schedulingv1alpha1.Devi
 
 func (n *nodeDevice) replaceWith(freeDevices map[schedulingv1alpha1.DeviceType]deviceResources) *nodeDevice {
 	nn := newNodeDevice()
+	nn.numaNodes = n.numaNodes
 	usedDevices := map[schedulingv1alpha1.DeviceType]deviceResources{}
 	for deviceType, total := range n.deviceTotal {
 		resources, ok := freeDevices[deviceType]
@@ -485,6 +520,8 @@ type nodeDeviceCache struct {
 	lock sync.Mutex
 	// nodeDeviceInfos stores nodeDevice for each node.
 	nodeDeviceInfos map[string]*nodeDevice
+	// unavailableDeviceHandler handles the pods allocated on the devices which become unhealthy or removed.
+	unavailableDeviceHandler func(nodeName string, pods map[types.NamespacedName][]string)
 }
 
 func newNodeDeviceCache() *nodeDeviceCache {
@@ -535,16 +572,21 @@ func (n *nodeDeviceCache) invalidateNodeDevice(device *schedulingv1alpha1.Device
 	info.resetDeviceTotal(nodeDeviceResource)
 }
 
-func (n *nodeDeviceCache) updateNodeDevice(nodeName string, device *schedulingv1alpha1.Device) {
+// updateNodeDevice updates the devices of the node, and returns the devices allocated to each pod
+// which become unhealthy or removed.
+func (n *nodeDeviceCache) updateNodeDevice(nodeName string, device *schedulingv1alpha1.Device) map[types.NamespacedName][]string {
 	if nodeName == "" || device == nil {
-		return
+		return nil
 	}
 
 	nodeDeviceResource := buildDeviceResources(device)
 	info := n.getNodeDevice(nodeName, true)
 	info.lock.Lock()
 	defer info.lock.Unlock()
+	unavailablePods := info.getPodsOnUnavailableDevices(nodeDeviceResource)
 	info.resetDeviceTotal(nodeDeviceResource)
+	info.numaNodes = buildDeviceNUMANodes(device)
+	return unavailablePods
 }
 
 func buildDeviceResources(device *schedulingv1alpha1.Device) map[schedulingv1alpha1.DeviceType]deviceResources {
@@ -567,6 +609,23 @@ func buildDeviceResources(device *schedulingv1alpha1.Device) map[schedulingv1alp
 	return nodeDeviceResource
 }
 
+func buildDeviceNUMANodes(device *schedulingv1alpha1.Device) map[schedulingv1alpha1.DeviceType]map[int]int {
+	var numaNodes map[schedulingv1alpha1.DeviceType]map[int]int
+	for _, deviceInfo := range device.Spec.Devices {
+		if deviceInfo.Minor == nil || deviceInfo.Topology == nil || deviceInfo.Topology.NodeID < 0 {
+			continue
+		}
+		if numaNodes == nil {
+			numaNodes = map[schedulingv1alpha1.DeviceType]map[int]int{}
+		}
+		if numaNodes[deviceInfo.Type] == nil {
+			numaNodes[deviceInfo.Type] = map[int]int{}
+		}
+		numaNodes[deviceInfo.Type][int(*deviceInfo.Minor)] = int(deviceInfo.Topology.NodeID)
+	}
+	return numaNodes
+}
+
 func (n *nodeDeviceCache) getNodeDeviceSummary(nodeName string) (*NodeDeviceSummary, bool) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
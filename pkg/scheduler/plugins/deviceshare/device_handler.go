@@ -19,6 +19,7 @@ package deviceshare
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -44,8 +45,9 @@ func (n *nodeDeviceCache) onDeviceAdd(obj interface{}) {
 		klog.Errorf("device cache add failed to parse, obj %T", obj)
 		return
 	}
-	n.updateNodeDevice(device.Name, device)
+	unavailablePods := n.updateNodeDevice(device.Name, device)
 	klog.V(4).InfoS("device cache added", "Device", klog.KObj(device))
+	n.handleUnavailableDevices(device.Name, unavailablePods)
 }
 
 func (n *nodeDeviceCache) onDeviceUpdate(oldObj, newObj interface{}) {
@@ -55,8 +57,16 @@ func (n *nodeDeviceCache) onDeviceUpdate(oldObj, newObj interface{}) {
 		klog.Errorf("device cache update failed to parse, oldObj %T, newObj %T", oldObj, newObj)
 		return
 	}
-	n.updateNodeDevice(newD.Name, newD)
+	unavailablePods := n.updateNodeDevice(newD.Name, newD)
 	klog.V(4).InfoS("device cache updated", "Device", klog.KObj(newD))
+	n.handleUnavailableDevices(newD.Name, unavailablePods)
+}
+
+func (n *nodeDeviceCache) handleUnavailableDevices(nodeName string, pods map[types.NamespacedName][]string) {
+	if len(pods) == 0 || n.unavailableDeviceHandler == nil {
+		return
+	}
+	n.unavailableDeviceHandler(nodeName, pods)
 }
 
 func (n *nodeDeviceCache) onDeviceDelete(obj interface{}) {
@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
 
@@ -490,3 +491,60 @@ func generateFakeNodeDeviceInfos() map[string]*nodeDevice {
 		},
 	}
 }
+
+func Test_nodeDeviceCache_onDeviceUpdateUnavailable(t *testing.T) {
+	deviceCache := newNodeDeviceCache()
+	var gotNodeName string
+	var gotPods map[types.NamespacedName][]string
+	deviceCache.unavailableDeviceHandler = func(nodeName string, pods map[types.NamespacedName][]string) {
+		gotNodeName = nodeName
+		gotPods = pods
+	}
+	oldDevice := generateMultipleFakeDevice()
+	deviceCache.onDeviceAdd(oldDevice)
+	assert.Nil(t, gotPods)
+
+	allocations := apiext.DeviceAllocations{
+		schedulingv1alpha1.GPU: []*apiext.DeviceAllocation{
+			{
+				Minor: 0,
+				Resources: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("100"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("100"),
+					apiext.ResourceGPUMemory:      resource.MustParse("16Gi"),
+				},
+			},
+			{
+				Minor: 1,
+				Resources: corev1.ResourceList{
+					apiext.ResourceGPUCore:        resource.MustParse("50"),
+					apiext.ResourceGPUMemoryRatio: resource.MustParse("50"),
+					apiext.ResourceGPUMemory:      resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod-1"}}
+	info := deviceCache.getNodeDevice("test-node-1", false)
+	info.updateCacheUsed(allocations, pod, true)
+
+	// minor 0 becomes unhealthy and minor 1 is removed
+	newDevice := oldDevice.DeepCopy()
+	newDevice.Spec.Devices[0].Health = false
+	newDevice.Spec.Devices = newDevice.Spec.Devices[:1]
+	deviceCache.onDeviceUpdate(oldDevice, newDevice)
+
+	assert.Equal(t, "test-node-1", gotNodeName)
+	expectedPods := map[types.NamespacedName][]string{
+		{Namespace: "default", Name: "test-pod-1"}: {"gpu-0", "gpu-1"},
+	}
+	assert.Equal(t, expectedPods, gotPods)
+	for minor, free := range info.deviceFree[schedulingv1alpha1.GPU] {
+		assert.True(t, quotav1.IsZero(free), "minor %d should not be allocatable", minor)
+	}
+
+	// the devices already unavailable are not reported again
+	gotPods = nil
+	deviceCache.onDeviceUpdate(newDevice, newDevice.DeepCopy())
+	assert.Nil(t, gotPods)
+}
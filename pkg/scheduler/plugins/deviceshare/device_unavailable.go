@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// ReasonDeviceUnavailable is the event reason of the pods allocated on the unhealthy or removed devices.
+	ReasonDeviceUnavailable = "DeviceUnavailable"
+
+	deviceUnavailableMigrationTrigger = "DeviceShare"
+)
+
+// unavailableDeviceHandler notifies the pods allocated on the devices which are reported unhealthy or removed,
+// and migrates them if DeviceUnavailableMigration is enabled. The PodMigrationJobs are created by the workers
+// of the queue, so the Device event handler never blocks on the apiserver.
+type unavailableDeviceHandler struct {
+	podLister      corelisters.PodLister
+	eventRecorder  events.EventRecorder
+	koordClientSet koordclientset.Interface
+	queue          workqueue.RateLimitingInterface
+
+	lock sync.Mutex
+	// migrationReasons records the reason of the pods waiting in the queue to be migrated
+	migrationReasons map[types.NamespacedName]string
+}
+
+func newUnavailableDeviceHandler(podLister corelisters.PodLister, eventRecorder events.EventRecorder, koordClientSet koordclientset.Interface) *unavailableDeviceHandler {
+	return &unavailableDeviceHandler{
+		podLister:        podLister,
+		eventRecorder:    eventRecorder,
+		koordClientSet:   koordClientSet,
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "DeviceUnavailableMigration"),
+		migrationReasons: map[types.NamespacedName]string{},
+	}
+}
+
+func (h *unavailableDeviceHandler) handle(nodeName string, pods map[types.NamespacedName][]string) {
+	for podNamespacedName, devices := range pods {
+		pod, err := h.podLister.Pods(podNamespacedName.Namespace).Get(podNamespacedName.Name)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to get pod allocated on unavailable devices", "pod", podNamespacedName)
+			}
+			continue
+		}
+		if util.IsPodTerminated(pod) {
+			continue
+		}
+
+		message := fmt.Sprintf("devices %s on node %s are unhealthy or removed", strings.Join(devices, ","), nodeName)
+		klog.InfoS("Pod is allocated on unavailable devices", "pod", klog.KObj(pod), "node", nodeName, "devices", devices)
+		h.eventRecorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonDeviceUnavailable, "Allocating", "%s", message)
+
+		if !k8sfeature.DefaultFeatureGate.Enabled(features.DeviceUnavailableMigration) {
+			continue
+		}
+		h.lock.Lock()
+		h.migrationReasons[podNamespacedName] = message
+		h.lock.Unlock()
+		h.queue.Add(podNamespacedName)
+	}
+}
+
+func (h *unavailableDeviceHandler) run(stopCh <-chan struct{}) {
+	defer h.queue.ShutDown()
+	go wait.Until(h.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (h *unavailableDeviceHandler) worker() {
+	for h.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem migrates one pod off the queue. It returns false when it's time to quit.
+func (h *unavailableDeviceHandler) processNextWorkItem() bool {
+	item, quit := h.queue.Get()
+	if quit {
+		return false
+	}
+	defer h.queue.Done(item)
+
+	podNamespacedName := item.(types.NamespacedName)
+	if err := h.migratePod(podNamespacedName); err != nil {
+		klog.ErrorS(err, "Failed to create PodMigrationJob for pod allocated on unavailable devices", "pod", podNamespacedName)
+		h.queue.AddRateLimited(item)
+		return true
+	}
+	h.queue.Forget(item)
+	return true
+}
+
+func (h *unavailableDeviceHandler) migratePod(podNamespacedName types.NamespacedName) error {
+	h.lock.Lock()
+	reason := h.migrationReasons[podNamespacedName]
+	h.lock.Unlock()
+
+	pod, err := h.podLister.Pods(podNamespacedName.Namespace).Get(podNamespacedName.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && !util.IsPodTerminated(pod) {
+		if err := h.createPodMigrationJob(pod, reason); err != nil {
+			return err
+		}
+	}
+
+	h.lock.Lock()
+	if h.migrationReasons[podNamespacedName] == reason {
+		delete(h.migrationReasons, podNamespacedName)
+	}
+	h.lock.Unlock()
+	return nil
+}
+
+func (h *unavailableDeviceHandler) createPodMigrationJob(pod *corev1.Pod, reason string) error {
+	job := &schedulingv1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			// the job is named by the pod UID to avoid migrating the pod repeatedly
+			Name: fmt.Sprintf("device-unavailable-%s", pod.UID),
+			Annotations: map[string]string{
+				evictor.AnnotationEvictReason:  reason,
+				evictor.AnnotationEvictTrigger: deviceUnavailableMigrationTrigger,
+			},
+		},
+		Spec: schedulingv1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				UID:       pod.UID,
+			},
+			Mode: schedulingv1alpha1.PodMigrationJobModeReservationFirst,
+		},
+		Status: schedulingv1alpha1.PodMigrationJobStatus{
+			Phase: schedulingv1alpha1.PodMigrationJobPending,
+		},
+	}
+	_, err := h.koordClientSet.SchedulingV1alpha1().PodMigrationJobs().Create(context.TODO(), job, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
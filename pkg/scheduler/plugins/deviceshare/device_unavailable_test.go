@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func TestUnavailableDeviceHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		enableMigration  bool
+		wantMigrationJob bool
+	}{
+		{
+			name:             "only record event",
+			enableMigration:  false,
+			wantMigrationJob: false,
+		},
+		{
+			name:             "record event and migrate pod",
+			enableMigration:  true,
+			wantMigrationJob: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, k8sfeature.DefaultMutableFeatureGate, features.DeviceUnavailableMigration, tt.enableMigration)()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod-1", UID: "123456"},
+				Spec:       corev1.PodSpec{NodeName: "test-node-1"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			sharedInformerFactory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+			assert.NoError(t, sharedInformerFactory.Core().V1().Pods().Informer().GetStore().Add(pod))
+			fakeRecorder := record.NewFakeRecorder(1024)
+			koordClientSet := koordfake.NewSimpleClientset()
+			h := newUnavailableDeviceHandler(sharedInformerFactory.Core().V1().Pods().Lister(), record.NewEventRecorderAdapter(fakeRecorder), koordClientSet)
+			defer h.queue.ShutDown()
+
+			pods := map[types.NamespacedName][]string{
+				{Namespace: "default", Name: "test-pod-1"}:    {"gpu-0"},
+				{Namespace: "default", Name: "not-found-pod"}: {"gpu-1"},
+			}
+			h.handle("test-node-1", pods)
+			// handle again to make sure the pod is migrated only once
+			h.handle("test-node-1", pods)
+			// the migration jobs are created by the queue workers instead of the event handler
+			jobs, err := koordClientSet.SchedulingV1alpha1().PodMigrationJobs().List(context.TODO(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			assert.Empty(t, jobs.Items)
+			for h.queue.Len() > 0 {
+				assert.True(t, h.processNextWorkItem())
+			}
+
+			assert.Len(t, fakeRecorder.Events, 2)
+			event := <-fakeRecorder.Events
+			assert.Contains(t, event, ReasonDeviceUnavailable)
+			assert.Contains(t, event, "gpu-0")
+
+			jobs, err = koordClientSet.SchedulingV1alpha1().PodMigrationJobs().List(context.TODO(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			if !tt.wantMigrationJob {
+				assert.Empty(t, jobs.Items)
+				return
+			}
+			assert.Len(t, jobs.Items, 1)
+			job := jobs.Items[0]
+			assert.Equal(t, pod.UID, job.Spec.PodRef.UID)
+			assert.Equal(t, schedulingv1alpha1.PodMigrationJobModeReservationFirst, job.Spec.Mode)
+			assert.Equal(t, deviceUnavailableMigrationTrigger, job.Annotations[evictor.AnnotationEvictTrigger])
+		})
+	}
+}
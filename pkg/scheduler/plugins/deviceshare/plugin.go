@@ -81,15 +81,17 @@ type preFilterState struct {
 	skip               bool
 	allocationResult   apiext.DeviceAllocations
 	podRequests        corev1.ResourceList
+	requestedMilliCPU  int64
 	preemptibleDevices map[string]map[schedulingv1alpha1.DeviceType]deviceResources
 	preemptibleInRRs   map[string]map[types.UID]map[schedulingv1alpha1.DeviceType]deviceResources
 }
 
 func (s *preFilterState) Clone() framework.StateData {
 	ns := &preFilterState{
-		skip:             s.skip,
-		allocationResult: s.allocationResult,
-		podRequests:      s.podRequests,
+		skip:              s.skip,
+		allocationResult:  s.allocationResult,
+		podRequests:       s.podRequests,
+		requestedMilliCPU: s.requestedMilliCPU,
 	}
 
 	preemptibleDevices := map[string]map[schedulingv1alpha1.DeviceType]deviceResources{}
@@ -155,6 +157,10 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	if !status.IsSuccess() {
 		return nil, status
 	}
+	if !state.skip {
+		podRequests, _ := resource.PodRequestsAndLimits(pod)
+		state.requestedMilliCPU = podRequests.Cpu().MilliValue()
+	}
 	cycleState.Write(stateKey, state)
 	return nil, nil
 }
@@ -394,7 +400,8 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 	var err error
 	if len(result) == 0 {
 		preemptible = appendAllocated(preemptible, restoreState.mergedMatchedAllocatable)
-		result, err = p.allocator.Allocate(nodeName, pod, state.podRequests, nodeDeviceInfo, nil, nil, nil, preemptible, p.scorer)
+		preferred := p.getDevicesWithCPUHeadroom(nodeName, nodeDeviceInfo, state.requestedMilliCPU)
+		result, err = p.allocator.Allocate(nodeName, pod, state.podRequests, nodeDeviceInfo, nil, preferred, nil, preemptible, p.scorer)
 	}
 	if err != nil || len(result) == 0 {
 		return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
@@ -529,6 +536,9 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	}
 
 	deviceCache := newNodeDeviceCache()
+	unavailableHandler := newUnavailableDeviceHandler(handle.SharedInformerFactory().Core().V1().Pods().Lister(), handle.EventRecorder(), extendedHandle.KoordinatorClientSet())
+	deviceCache.unavailableDeviceHandler = unavailableHandler.handle
+	go unavailableHandler.run(context.TODO().Done())
 	registerDeviceEventHandler(deviceCache, extendedHandle.KoordinatorSharedInformerFactory())
 	registerPodEventHandler(deviceCache, handle.SharedInformerFactory(), extendedHandle.KoordinatorSharedInformerFactory())
 	go deviceCache.gcNodeDevice(context.TODO(), handle.SharedInformerFactory(), defaultGCPeriod)
@@ -68,6 +68,8 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 			"pod", klog.KObj(pod), "reservation", klog.KObj(reservationInfo), "node", nodeName)
 		return 0, framework.AsStatus(err)
 	}
+	devicesWithCPUHeadroom := p.getDevicesWithCPUHeadroom(nodeName, nodeDeviceInfo, state.requestedMilliCPU)
+	score = nodeDeviceInfo.scoreWithCPUHeadroom(score, state.podRequests, devicesWithCPUHeadroom)
 	return score, nil
 }
 
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+)
+
+// getNUMANodesWithCPUHeadroom returns the NUMA Nodes whose remaining CPUs can satisfy the requested CPUs.
+// It returns nil if the NUMA resources of the node are unknown.
+func (p *Plugin) getNUMANodesWithCPUHeadroom(nodeName string, requestedMilliCPU int64) sets.Int {
+	factory, ok := p.handle.(topologymanager.NUMAResourceProviderFactory)
+	if !ok {
+		return nil
+	}
+	provider := factory.GetNUMAResourceProvider()
+	if provider == nil {
+		return nil
+	}
+	available, err := provider.GetAvailableNUMANodeResources(nodeName)
+	if err != nil {
+		klog.V(5).InfoS("Failed to GetAvailableNUMANodeResources", "node", nodeName, "err", err)
+		return nil
+	}
+	if len(available) == 0 {
+		return nil
+	}
+	numaNodes := sets.NewInt()
+	for numaNode, resources := range available {
+		cpu := resources[corev1.ResourceCPU]
+		if cpu.MilliValue() > 0 && cpu.MilliValue() >= requestedMilliCPU {
+			numaNodes.Insert(numaNode)
+		}
+	}
+	return numaNodes
+}
+
+// getDevicesWithCPUHeadroom returns the devices located on the NUMA Nodes with enough CPU headroom.
+// The device types without topology information are not included in the result.
+func (p *Plugin) getDevicesWithCPUHeadroom(nodeName string, n *nodeDevice, requestedMilliCPU int64) map[schedulingv1alpha1.DeviceType]sets.Int {
+	if len(n.numaNodes) == 0 {
+		return nil
+	}
+	numaNodes := p.getNUMANodesWithCPUHeadroom(nodeName, requestedMilliCPU)
+	if numaNodes == nil {
+		return nil
+	}
+	result := map[schedulingv1alpha1.DeviceType]sets.Int{}
+	for deviceType, minors := range n.numaNodes {
+		devices := sets.NewInt()
+		for minor, numaNode := range minors {
+			if numaNodes.Has(numaNode) {
+				devices.Insert(minor)
+			}
+		}
+		result[deviceType] = devices
+	}
+	return result
+}
+
+// scoreWithCPUHeadroom scales the score by the proportion of the free devices
+// that are located on the NUMA Nodes with enough CPU headroom.
+func (n *nodeDevice) scoreWithCPUHeadroom(score int64, podRequest corev1.ResourceList, devicesWithCPUHeadroom map[schedulingv1alpha1.DeviceType]sets.Int) int64 {
+	if devicesWithCPUHeadroom == nil {
+		return score
+	}
+	var total, satisfied int
+	for deviceType, supportedResourceNames := range DeviceResourceNames {
+		if quotav1.IsZero(quotav1.Mask(podRequest, supportedResourceNames)) {
+			continue
+		}
+		devices, ok := devicesWithCPUHeadroom[deviceType]
+		if !ok {
+			continue
+		}
+		for minor, free := range n.deviceFree[deviceType] {
+			if quotav1.IsZero(free) {
+				continue
+			}
+			total++
+			if devices.Has(minor) {
+				satisfied++
+			}
+		}
+	}
+	if total == 0 {
+		return score
+	}
+	return score * int64(satisfied) / int64(total)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+)
+
+type fakeNUMAResourceProvider struct {
+	available map[int]corev1.ResourceList
+}
+
+func (f *fakeNUMAResourceProvider) GetAvailableNUMANodeResources(nodeName string) (map[int]corev1.ResourceList, error) {
+	return f.available, nil
+}
+
+type fakeNUMAResourceProviderHandle struct {
+	framework.Handle
+	provider topologymanager.NUMAResourceProvider
+}
+
+func (f *fakeNUMAResourceProviderHandle) GetNUMAResourceProvider() topologymanager.NUMAResourceProvider {
+	return f.provider
+}
+
+func TestGetDevicesWithCPUHeadroom(t *testing.T) {
+	device := &schedulingv1alpha1.Device{
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{
+					Type:     schedulingv1alpha1.GPU,
+					Minor:    pointer.Int32(0),
+					Health:   true,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 0},
+				},
+				{
+					Type:     schedulingv1alpha1.GPU,
+					Minor:    pointer.Int32(1),
+					Health:   true,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 1},
+				},
+				{
+					Type:   schedulingv1alpha1.RDMA,
+					Minor:  pointer.Int32(0),
+					Health: true,
+				},
+			},
+		},
+	}
+	nd := newNodeDevice()
+	nd.numaNodes = buildDeviceNUMANodes(device)
+
+	tests := []struct {
+		name              string
+		provider          topologymanager.NUMAResourceProvider
+		requestedMilliCPU int64
+		want              map[schedulingv1alpha1.DeviceType]sets.Int
+	}{
+		{
+			name: "missing NUMA resource provider",
+			want: nil,
+		},
+		{
+			name: "prefer devices on NUMA Node with enough CPUs",
+			provider: &fakeNUMAResourceProvider{
+				available: map[int]corev1.ResourceList{
+					0: {corev1.ResourceCPU: resource.MustParse("2")},
+					1: {corev1.ResourceCPU: resource.MustParse("8")},
+				},
+			},
+			requestedMilliCPU: 4000,
+			want: map[schedulingv1alpha1.DeviceType]sets.Int{
+				schedulingv1alpha1.GPU: sets.NewInt(1),
+			},
+		},
+		{
+			name: "all NUMA Nodes exhausted",
+			provider: &fakeNUMAResourceProvider{
+				available: map[int]corev1.ResourceList{
+					0: {corev1.ResourceCPU: resource.MustParse("0")},
+					1: {corev1.ResourceCPU: resource.MustParse("0")},
+				},
+			},
+			want: map[schedulingv1alpha1.DeviceType]sets.Int{
+				schedulingv1alpha1.GPU: sets.NewInt(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{
+				handle: &fakeNUMAResourceProviderHandle{provider: tt.provider},
+			}
+			got := p.getDevicesWithCPUHeadroom("test-node", nd, tt.requestedMilliCPU)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScoreWithCPUHeadroom(t *testing.T) {
+	nd := newNodeDevice()
+	nd.resetDeviceTotal(map[schedulingv1alpha1.DeviceType]deviceResources{
+		schedulingv1alpha1.GPU: {
+			0: corev1.ResourceList{apiext.ResourceGPUCore: resource.MustParse("100")},
+			1: corev1.ResourceList{apiext.ResourceGPUCore: resource.MustParse("100")},
+		},
+	})
+	podRequest := corev1.ResourceList{apiext.ResourceGPUCore: resource.MustParse("50")}
+
+	assert.Equal(t, int64(80), nd.scoreWithCPUHeadroom(80, podRequest, nil))
+	assert.Equal(t, int64(40), nd.scoreWithCPUHeadroom(80, podRequest, map[schedulingv1alpha1.DeviceType]sets.Int{
+		schedulingv1alpha1.GPU: sets.NewInt(1),
+	}))
+	assert.Equal(t, int64(0), nd.scoreWithCPUHeadroom(80, podRequest, map[schedulingv1alpha1.DeviceType]sets.Int{
+		schedulingv1alpha1.GPU: sets.NewInt(),
+	}))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+const (
+	quotaRejectionReasonInsufficientQuota               = "InsufficientQuota"
+	quotaRejectionReasonInsufficientNonPreemptibleQuota = "InsufficientNonPreemptibleQuota"
+	quotaRejectionReasonInsufficientParentQuota         = "InsufficientParentQuota"
+)
+
+var (
+	QuotaRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      frameworkext.SchedulerSubsystem,
+			Name:           registry.ElasticQuotaRejections.Name,
+			Help:           "Number of Pods rejected for the insufficient quota, by the quota, by the reason",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"quota", "reason"})
+
+	metricsList = []metrics.Registerable{
+		QuotaRejections,
+	}
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the quota metrics of the ElasticQuota plugin.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
+
+func recordQuotaRejection(quotaName, reason string) {
+	QuotaRejections.WithLabelValues(quotaName, reason).Inc()
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+func TestRecordQuotaRejection(t *testing.T) {
+	RegisterMetrics()
+	assert.Equal(t, registry.ElasticQuotaRejections.FQName(), QuotaRejections.FQName())
+
+	before, err := testutil.GetCounterMetricValue(QuotaRejections.WithLabelValues("test-quota", quotaRejectionReasonInsufficientQuota))
+	assert.NoError(t, err)
+	recordQuotaRejection("test-quota", quotaRejectionReasonInsufficientQuota)
+	after, err := testutil.GetCounterMetricValue(QuotaRejections.WithLabelValues("test-quota", quotaRejectionReasonInsufficientQuota))
+	assert.NoError(t, err)
+	assert.Equal(t, before+1, after)
+}
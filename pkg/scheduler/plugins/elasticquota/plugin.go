@@ -109,6 +109,8 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		return nil, err
 	}
 
+	RegisterMetrics()
+
 	client, ok := handle.(versioned.Interface)
 	if !ok {
 		kubeConfig := *handle.KubeConfig()
@@ -230,6 +232,7 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	used := quotav1.Add(podRequest, state.used)
 
 	if isLessEqual, exceedDimensions := quotav1.LessThanOrEqual(used, state.runtime); !isLessEqual {
+		recordQuotaRejection(quotaName, quotaRejectionReasonInsufficientQuota)
 		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient quotas, "+
 			"quotaName: %v, runtime: %v, used: %v, pod's request: %v, exceedDimensions: %v",
 			quotaName, printResourceList(state.runtime), printResourceList(state.used), printResourceList(podRequest), exceedDimensions))
@@ -241,6 +244,7 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 		addNonPreemptibleUsed := quotav1.Add(podRequest, nonPreemptibleUsed)
 
 		if isLessEqual, exceedDimensions := quotav1.LessThanOrEqual(addNonPreemptibleUsed, quotaMin); !isLessEqual {
+			recordQuotaRejection(quotaName, quotaRejectionReasonInsufficientNonPreemptibleQuota)
 			return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient non-preemptible quotas, "+
 				"quotaName: %v, min: %v, nonPreemptibleUsed: %v, pod's request: %v, exceedDimensions: %v",
 				quotaName, printResourceList(quotaMin), printResourceList(nonPreemptibleUsed), printResourceList(podRequest), exceedDimensions))
@@ -248,7 +252,11 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	}
 
 	if *g.pluginArgs.EnableCheckParentQuota {
-		return nil, g.checkQuotaRecursive(quotaName, []string{quotaName}, podRequest)
+		status := g.checkQuotaRecursive(quotaName, []string{quotaName}, podRequest)
+		if !status.IsSuccess() {
+			recordQuotaRejection(quotaName, quotaRejectionReasonInsufficientParentQuota)
+		}
+		return nil, status
 	}
 
 	return nil, framework.NewStatus(framework.Success, "")
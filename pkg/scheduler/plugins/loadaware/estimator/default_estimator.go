@@ -21,12 +21,12 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 )
 
 const (
@@ -108,22 +108,5 @@ func estimatedUsedByResource(requests, limits corev1.ResourceList, resourceName
 }
 
 func (e *DefaultEstimator) EstimateNode(node *corev1.Node) (corev1.ResourceList, error) {
-	rawAllocatable, err := extension.GetNodeRawAllocatable(node.Annotations)
-	if err != nil {
-		return node.Status.Allocatable, nil
-	}
-	if len(rawAllocatable) == 0 {
-		return node.Status.Allocatable, nil
-	}
-	if quotav1.Equals(rawAllocatable, node.Status.Allocatable) {
-		return node.Status.Allocatable, nil
-	}
-	allocatableCopy := node.Status.Allocatable.DeepCopy()
-	if allocatableCopy == nil {
-		allocatableCopy = corev1.ResourceList{}
-	}
-	for k, v := range rawAllocatable {
-		allocatableCopy[k] = v
-	}
-	return allocatableCopy, nil
+	return frameworkext.GetNodeRawAllocatable(node), nil
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/util"
@@ -164,11 +166,33 @@ func buildPodMetricMap(podLister corev1listers.PodLister, nodeMetric *slov1alpha
 			continue
 		}
 		name := getPodNamespacedName(podMetric.Namespace, podMetric.Name)
-		podMetrics[name] = podMetric.PodUsage.ResourceList
+		podMetrics[name] = addGPUDeviceUsages(podMetric.PodUsage.ResourceList, podMetric.PodUsage.Devices)
 	}
 	return podMetrics
 }
 
+// addGPUDeviceUsages returns the usages added with the usages of the GPU devices, so the GPU resources in the
+// resource weights are scored by the actual usages of the GPUs, including the GPUs shared by the pods.
+func addGPUDeviceUsages(usages corev1.ResourceList, devices []schedulingv1alpha1.DeviceInfo) corev1.ResourceList {
+	var result corev1.ResourceList
+	for _, device := range devices {
+		if device.Type != schedulingv1alpha1.GPU || len(device.Resources) == 0 {
+			continue
+		}
+		if result == nil {
+			result = usages.DeepCopy()
+			if result == nil {
+				result = corev1.ResourceList{}
+			}
+		}
+		util.AddResourceList(result, device.Resources)
+	}
+	if result == nil {
+		return usages
+	}
+	return result
+}
+
 func sumPodUsages(podMetrics map[string]corev1.ResourceList, estimatedPods sets.String) (podUsages, estimatedPodsUsages corev1.ResourceList) {
 	if len(podMetrics) == 0 {
 		return nil, nil
@@ -194,3 +218,89 @@ func isDaemonSetPod(ownerRefList []metav1.OwnerReference) bool {
 	}
 	return false
 }
+
+// memoryLocalityMaxPenaltyPercent is the max percentage of the score deducted when all the memory is accessed remotely.
+const memoryLocalityMaxPenaltyPercent = 10
+
+// isMemoryBandwidthSensitivePod returns true if the pod would be bound to CPUs,
+// whose performance degrades when its memory is accessed across NUMA nodes.
+func isMemoryBandwidthSensitivePod(pod *corev1.Pod) bool {
+	qosClass := extension.GetPodQoSClassRaw(pod)
+	return (qosClass == extension.QoSLSE || qosClass == extension.QoSLSR) &&
+		extension.GetPodPriorityClassWithDefault(pod) == extension.PriorityProd
+}
+
+// applyMemoryLocalityPenalty deducts the score in proportion to the remote memory access ratio of the node.
+func applyMemoryLocalityPenalty(score int64, nodeMetric *slov1alpha1.NodeMetric) int64 {
+	if nodeMetric.Status.NodeMetric == nil || nodeMetric.Status.NodeMetric.MemoryLocality == nil ||
+		nodeMetric.Status.NodeMetric.MemoryLocality.RemoteRatio == nil {
+		return score
+	}
+	remoteRatio := *nodeMetric.Status.NodeMetric.MemoryLocality.RemoteRatio
+	if remoteRatio <= 0 {
+		return score
+	}
+	if remoteRatio > 100 {
+		remoteRatio = 100
+	}
+	return score * (100*100 - remoteRatio*memoryLocalityMaxPenaltyPercent) / (100 * 100)
+}
+
+func getNodeHealthIndex(nodeMetric *slov1alpha1.NodeMetric) *int64 {
+	if nodeMetric.Status.NodeMetric == nil || nodeMetric.Status.NodeMetric.HealthIndex == nil {
+		return nil
+	}
+	return nodeMetric.Status.NodeMetric.HealthIndex.Index
+}
+
+// isNodeHealthIndexExceedThreshold returns true if the node health index reaches the threshold.
+// A threshold which is not positive disables the check.
+func isNodeHealthIndexExceedThreshold(nodeMetric *slov1alpha1.NodeMetric, threshold int64) bool {
+	if threshold <= 0 {
+		return false
+	}
+	index := getNodeHealthIndex(nodeMetric)
+	return index != nil && *index >= threshold
+}
+
+// applyNodeHealthIndexPenalty deducts the score in proportion to the health index of the node,
+// where the health index 100 means the node suffers the most serious interference.
+func applyNodeHealthIndexPenalty(score int64, nodeMetric *slov1alpha1.NodeMetric) int64 {
+	index := getNodeHealthIndex(nodeMetric)
+	if index == nil || *index <= 0 {
+		return score
+	}
+	if *index >= 100 {
+		return 0
+	}
+	return score * (100 - *index) / 100
+}
+
+// isCPUStealAnomaly checks whether koordlet reports the CPUStealAnomaly condition of the node along with the steal
+// percent. The steal percent alone is reported whenever the collection is enabled, and does not make an anomaly.
+func isCPUStealAnomaly(nodeMetric *slov1alpha1.NodeMetric) bool {
+	if nodeMetric == nil || nodeMetric.Status.NodeMetric == nil || nodeMetric.Status.NodeMetric.CPUSteal == nil ||
+		nodeMetric.Status.NodeMetric.CPUSteal.StealPercent == nil {
+		return false
+	}
+	return meta.IsStatusConditionTrue(nodeMetric.Status.Conditions, slov1alpha1.NodeMetricConditionCPUStealAnomaly)
+}
+
+// applyCPUStealDiscount deducts the CPU allocatable of the node reporting the CPUStealAnomaly condition by the
+// discount percentage of the CPU time stolen by the hypervisor, i.e. the node is considered as reduced-capacity.
+func applyCPUStealDiscount(allocatable corev1.ResourceList, nodeMetric *slov1alpha1.NodeMetric, discount int64) corev1.ResourceList {
+	if discount <= 0 || !isCPUStealAnomaly(nodeMetric) {
+		return allocatable
+	}
+	cpu, ok := allocatable[corev1.ResourceCPU]
+	if !ok {
+		return allocatable
+	}
+	stealPercent := *nodeMetric.Status.NodeMetric.CPUSteal.StealPercent
+	if stealPercent > 100 {
+		stealPercent = 100
+	}
+	discounted := allocatable.DeepCopy()
+	discounted[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpu.MilliValue()*(100*100-stealPercent*discount)/(100*100), cpu.Format)
+	return discounted
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -33,6 +34,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
@@ -45,6 +47,7 @@ const (
 	ErrReasonUsageExceedThreshold           = "node(s) %s usage exceed threshold"
 	ErrReasonAggregatedUsageExceedThreshold = "node(s) %s aggregated usage exceed threshold"
 	ErrReasonFailedEstimatePod
+	ErrReasonNodeHealthIndexExceedThreshold = "node(s) health index exceed threshold"
 )
 
 const (
@@ -146,6 +149,14 @@ func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *c
 		return nil
 	}
 
+	if p.args.NodeHealthIndexThreshold != nil && isNodeHealthIndexExceedThreshold(nodeMetric, *p.args.NodeHealthIndexThreshold) {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonNodeHealthIndexExceedThreshold)
+	}
+
+	if !k8sfeature.DefaultFeatureGate.Enabled(features.LoadAwareUsageThresholdsFilter) {
+		return nil
+	}
+
 	filterProfile := generateUsageThresholdsFilterProfile(node, p.args)
 	if len(filterProfile.ProdUsageThresholds) > 0 && extension.GetPodPriorityClassWithDefault(pod) == extension.PriorityProd {
 		status := p.filterProdUsage(node, nodeMetric, filterProfile.ProdUsageThresholds)
@@ -186,7 +197,7 @@ func (p *Plugin) filterNodeUsage(node *corev1.Node, nodeMetric *slov1alpha1.Node
 		if threshold == 0 {
 			continue
 		}
-		allocatable, err := p.estimator.EstimateNode(node)
+		allocatable, err := p.estimateNode(node, nodeMetric)
 		if err != nil {
 			klog.ErrorS(err, "Failed to EstimateNode", "node", node.Name)
 			return nil
@@ -235,7 +246,7 @@ func (p *Plugin) filterProdUsage(node *corev1.Node, nodeMetric *slov1alpha1.Node
 		if threshold == 0 {
 			continue
 		}
-		allocatable, err := p.estimator.EstimateNode(node)
+		allocatable, err := p.estimateNode(node, nodeMetric)
 		if err != nil {
 			klog.ErrorS(err, "Failed to EstimateNode", "node", node.Name)
 			return nil
@@ -253,6 +264,18 @@ func (p *Plugin) filterProdUsage(node *corev1.Node, nodeMetric *slov1alpha1.Node
 	return nil
 }
 
+// estimateNode estimates the node allocatable, where the CPU stolen by the hypervisor is deducted if configured.
+func (p *Plugin) estimateNode(node *corev1.Node, nodeMetric *slov1alpha1.NodeMetric) (corev1.ResourceList, error) {
+	allocatable, err := p.estimator.EstimateNode(node)
+	if err != nil {
+		return nil, err
+	}
+	if p.args.CPUStealCapacityDiscount != nil {
+		allocatable = applyCPUStealDiscount(allocatable, nodeMetric, *p.args.CPUStealCapacityDiscount)
+	}
+	return allocatable, nil
+}
+
 func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
 	return nil
 }
@@ -313,7 +336,7 @@ func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *co
 				nodeUsage = &nodeMetric.Status.NodeMetric.NodeUsage
 			}
 			if nodeUsage != nil {
-				for resourceName, quantity := range nodeUsage.ResourceList {
+				for resourceName, quantity := range addGPUDeviceUsages(nodeUsage.ResourceList, nodeUsage.Devices) {
 					if q := estimatedPodActualUsages[resourceName]; !q.IsZero() {
 						quantity = quantity.DeepCopy()
 						if quantity.Cmp(q) >= 0 {
@@ -326,11 +349,17 @@ func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *co
 		}
 	}
 
-	allocatable, err := p.estimator.EstimateNode(node)
+	allocatable, err := p.estimateNode(node, nodeMetric)
 	if err != nil {
 		return 0, nil
 	}
 	score := loadAwareSchedulingScorer(p.args.ResourceWeights, estimatedUsed, allocatable)
+	if p.args.ScoreAccordingMemoryLocality && isMemoryBandwidthSensitivePod(pod) {
+		score = applyMemoryLocalityPenalty(score, nodeMetric)
+	}
+	if p.args.ScoreAccordingNodeHealthIndex {
+		score = applyNodeHealthIndexPenalty(score, nodeMetric)
+	}
 	return score, nil
 }
 
@@ -40,6 +40,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
@@ -267,6 +268,8 @@ func TestFilterUsage(t *testing.T) {
 		customUsageThresholds     map[corev1.ResourceName]int64
 		customProdUsageThresholds map[corev1.ResourceName]int64
 		customAggregatedUsage     *extension.CustomAggregatedUsage
+		nodeHealthIndexThreshold  *int64
+		cpuStealCapacityDiscount  *int64
 		nodeName                  string
 		nodeMetric                *slov1alpha1.NodeMetric
 		pods                      []*corev1.Pod
@@ -301,6 +304,146 @@ func TestFilterUsage(t *testing.T) {
 			},
 			wantStatus: nil,
 		},
+		{
+			name:                     "filter healthy node",
+			nodeHealthIndexThreshold: pointer.Int64(60),
+			nodeName:                 "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("30"),
+								corev1.ResourceMemory: resource.MustParse("256Gi"),
+							},
+						},
+						HealthIndex: &slov1alpha1.NodeHealthIndex{
+							Index: pointer.Int64(30),
+						},
+					},
+				},
+			},
+			wantStatus: nil,
+		},
+		{
+			name:                     "filter node health index exceed threshold",
+			nodeHealthIndexThreshold: pointer.Int64(60),
+			nodeName:                 "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("30"),
+								corev1.ResourceMemory: resource.MustParse("256Gi"),
+							},
+						},
+						HealthIndex: &slov1alpha1.NodeHealthIndex{
+							Index: pointer.Int64(60),
+						},
+					},
+				},
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonNodeHealthIndexExceedThreshold),
+		},
+		{
+			name:                     "filter normal usage without cpu steal anomaly",
+			cpuStealCapacityDiscount: pointer.Int64(100),
+			nodeName:                 "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("60"),
+								corev1.ResourceMemory: resource.MustParse("256Gi"),
+							},
+						},
+						CPUSteal: &slov1alpha1.NodeCPUSteal{
+							StealPercent: pointer.Int64(20),
+						},
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   slov1alpha1.NodeMetricConditionCPUStealAnomaly,
+							Status: metav1.ConditionFalse,
+						},
+					},
+				},
+			},
+			wantStatus: nil,
+		},
+		{
+			name:                     "filter usage exceed threshold on the reduced-capacity node with cpu steal anomaly",
+			cpuStealCapacityDiscount: pointer.Int64(100),
+			nodeName:                 "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("60"),
+								corev1.ResourceMemory: resource.MustParse("256Gi"),
+							},
+						},
+						CPUSteal: &slov1alpha1.NodeCPUSteal{
+							StealPercent: pointer.Int64(20),
+						},
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   slov1alpha1.NodeMetricConditionCPUStealAnomaly,
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)),
+		},
 		{
 			name:       "filter node missing NodeMetrics",
 			nodeName:   "test-node-1",
@@ -814,6 +957,8 @@ func TestFilterUsage(t *testing.T) {
 			if tt.aggregated != nil {
 				v1beta2args.Aggregated = tt.aggregated
 			}
+			v1beta2args.NodeHealthIndexThreshold = tt.nodeHealthIndexThreshold
+			v1beta2args.CPUStealCapacityDiscount = tt.cpuStealCapacityDiscount
 			v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
 			var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
 			err := v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &loadAwareSchedulingArgs, nil)
@@ -910,17 +1055,86 @@ func TestFilterUsage(t *testing.T) {
 	}
 }
 
+func TestApplyCPUStealDiscount(t *testing.T) {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100"),
+		corev1.ResourceMemory: resource.MustParse("256Gi"),
+	}
+	newNodeMetric := func(stealPercent *int64, conditions ...metav1.Condition) *slov1alpha1.NodeMetric {
+		nodeMetric := &slov1alpha1.NodeMetric{
+			Status: slov1alpha1.NodeMetricStatus{
+				NodeMetric: &slov1alpha1.NodeMetricInfo{},
+				Conditions: conditions,
+			},
+		}
+		if stealPercent != nil {
+			nodeMetric.Status.NodeMetric.CPUSteal = &slov1alpha1.NodeCPUSteal{StealPercent: stealPercent}
+		}
+		return nodeMetric
+	}
+	anomaly := metav1.Condition{Type: slov1alpha1.NodeMetricConditionCPUStealAnomaly, Status: metav1.ConditionTrue}
+	noAnomaly := metav1.Condition{Type: slov1alpha1.NodeMetricConditionCPUStealAnomaly, Status: metav1.ConditionFalse}
+	tests := []struct {
+		name       string
+		nodeMetric *slov1alpha1.NodeMetric
+		discount   int64
+		wantCPU    string
+	}{
+		{
+			name:       "no discount without the anomaly condition",
+			nodeMetric: newNodeMetric(pointer.Int64(20)),
+			discount:   100,
+			wantCPU:    "100",
+		},
+		{
+			name:       "no discount with the anomaly condition false",
+			nodeMetric: newNodeMetric(pointer.Int64(20), noAnomaly),
+			discount:   100,
+			wantCPU:    "100",
+		},
+		{
+			name:       "no discount without the steal percent",
+			nodeMetric: newNodeMetric(nil, anomaly),
+			discount:   100,
+			wantCPU:    "100",
+		},
+		{
+			name:       "no discount if disabled",
+			nodeMetric: newNodeMetric(pointer.Int64(20), anomaly),
+			discount:   0,
+			wantCPU:    "100",
+		},
+		{
+			name:       "discount with the anomaly condition",
+			nodeMetric: newNodeMetric(pointer.Int64(20), anomaly),
+			discount:   50,
+			wantCPU:    "90",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := applyCPUStealDiscount(allocatable, tt.nodeMetric, tt.discount)
+			wantCPU := resource.MustParse(tt.wantCPU)
+			assert.Equal(t, wantCPU.MilliValue(), got.Cpu().MilliValue())
+			assert.Equal(t, allocatable.Memory().Value(), got.Memory().Value())
+		})
+	}
+}
+
 func TestScore(t *testing.T) {
 	tests := []struct {
-		name                    string
-		pod                     *corev1.Pod
-		assignedPod             []*podAssignInfo
-		nodeName                string
-		nodeMetric              *slov1alpha1.NodeMetric
-		scoreAccordingProdUsage bool
-		aggregatedArgs          *v1beta2.LoadAwareSchedulingAggregatedArgs
-		wantScore               int64
-		wantStatus              *framework.Status
+		name                          string
+		pod                           *corev1.Pod
+		assignedPod                   []*podAssignInfo
+		nodeName                      string
+		nodeMetric                    *slov1alpha1.NodeMetric
+		scoreAccordingProdUsage       bool
+		scoreAccordingMemoryLocality  bool
+		scoreAccordingNodeHealthIndex bool
+		aggregatedArgs                *v1beta2.LoadAwareSchedulingAggregatedArgs
+		resourceWeights               map[corev1.ResourceName]int64
+		wantScore                     int64
+		wantStatus                    *framework.Status
 	}{
 		{
 			name:     "score node with expired nodeMetric",
@@ -1068,6 +1282,181 @@ func TestScore(t *testing.T) {
 			wantScore:  72,
 			wantStatus: nil,
 		},
+		{
+			name:                         "score load node with poor memory locality for pod bound to cpus",
+			scoreAccordingMemoryLocality: true,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLSR),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Name: "test-container",
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			nodeName: "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("32"),
+								corev1.ResourceMemory: resource.MustParse("10Gi"),
+							},
+						},
+						MemoryLocality: &slov1alpha1.MemoryLocality{
+							LocalRatio:  pointer.Int64(50),
+							RemoteRatio: pointer.Int64(50),
+						},
+					},
+				},
+			},
+			wantScore:  68,
+			wantStatus: nil,
+		},
+		{
+			name:                         "score load node with poor memory locality for pod not bound to cpus",
+			scoreAccordingMemoryLocality: true,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Name: "test-container",
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			nodeName: "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("32"),
+								corev1.ResourceMemory: resource.MustParse("10Gi"),
+							},
+						},
+						MemoryLocality: &slov1alpha1.MemoryLocality{
+							LocalRatio:  pointer.Int64(50),
+							RemoteRatio: pointer.Int64(50),
+						},
+					},
+				},
+			},
+			wantScore:  72,
+			wantStatus: nil,
+		},
+		{
+			name:                          "score load node with high health index",
+			scoreAccordingNodeHealthIndex: true,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "test-container",
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			nodeName: "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("32"),
+								corev1.ResourceMemory: resource.MustParse("10Gi"),
+							},
+						},
+						HealthIndex: &slov1alpha1.NodeHealthIndex{
+							Index: pointer.Int64(50),
+						},
+					},
+				},
+			},
+			wantScore:  36,
+			wantStatus: nil,
+		},
 		{
 			name: "score load node with p95",
 			aggregatedArgs: &v1beta2.LoadAwareSchedulingAggregatedArgs{
@@ -1750,14 +2139,85 @@ func TestScore(t *testing.T) {
 			wantScore:  99,
 			wantStatus: nil,
 		},
+		{
+			name: "score node with the GPU usage",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "test-container",
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			nodeName: "test-node-1",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							Devices: []schedulingv1alpha1.DeviceInfo{
+								{
+									Minor: pointer.Int32(0),
+									Type:  schedulingv1alpha1.GPU,
+									Resources: corev1.ResourceList{
+										extension.ResourceGPUCore: resource.MustParse("80"),
+									},
+								},
+								{
+									Minor: pointer.Int32(1),
+									Type:  schedulingv1alpha1.GPU,
+									Resources: corev1.ResourceList{
+										extension.ResourceGPUCore: resource.MustParse("40"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			resourceWeights: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU:        1,
+				extension.ResourceGPUCore: 1,
+			},
+			wantScore:  62,
+			wantStatus: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var v1beta2args v1beta2.LoadAwareSchedulingArgs
 			v1beta2args.ScoreAccordingProdUsage = &tt.scoreAccordingProdUsage
+			v1beta2args.ScoreAccordingMemoryLocality = &tt.scoreAccordingMemoryLocality
+			v1beta2args.ScoreAccordingNodeHealthIndex = &tt.scoreAccordingNodeHealthIndex
 			if tt.aggregatedArgs != nil {
 				v1beta2args.Aggregated = tt.aggregatedArgs
 			}
+			v1beta2args.ResourceWeights = tt.resourceWeights
 			v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
 			var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
 			err := v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &loadAwareSchedulingArgs, nil)
@@ -1781,8 +2241,9 @@ func TestScore(t *testing.T) {
 					},
 					Status: corev1.NodeStatus{
 						Allocatable: corev1.ResourceList{
-							corev1.ResourceCPU:    resource.MustParse("96"),
-							corev1.ResourceMemory: resource.MustParse("512Gi"),
+							corev1.ResourceCPU:        resource.MustParse("96"),
+							corev1.ResourceMemory:     resource.MustParse("512Gi"),
+							extension.ResourceGPUCore: resource.MustParse("200"),
 						},
 					},
 				},
@@ -0,0 +1,288 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// envBenchmarkRegression enables TestNodeNUMAResourceLatencyRegression.
+// The latency depends on the machine, so the check only runs on demand, e.g. `make benchmark-scheduler`.
+const envBenchmarkRegression = "KOORD_BENCHMARK_REGRESSION"
+
+type benchmarkTopology struct {
+	numSockets     int
+	nodesPerSocket int
+	coresPerNode   int
+	cpusPerCore    int
+}
+
+func (t benchmarkTopology) String() string {
+	return fmt.Sprintf("%dSockets-%dNUMA-%dCPUs", t.numSockets, t.numSockets*t.nodesPerSocket, t.numSockets*t.nodesPerSocket*t.coresPerNode*t.cpusPerCore)
+}
+
+// benchmarkTopologies covers 64 to 512 CPUs. GetTopologyHints iterates all NUMA Node combinations,
+// so like the kubelet topology manager the topologies are limited to 8 NUMA Nodes.
+var benchmarkTopologies = []benchmarkTopology{
+	{numSockets: 2, nodesPerSocket: 1, coresPerNode: 16, cpusPerCore: 2},
+	{numSockets: 2, nodesPerSocket: 2, coresPerNode: 16, cpusPerCore: 2},
+	{numSockets: 2, nodesPerSocket: 4, coresPerNode: 16, cpusPerCore: 2},
+	{numSockets: 4, nodesPerSocket: 2, coresPerNode: 16, cpusPerCore: 2},
+	{numSockets: 8, nodesPerSocket: 1, coresPerNode: 16, cpusPerCore: 2},
+	{numSockets: 2, nodesPerSocket: 4, coresPerNode: 32, cpusPerCore: 2},
+	{numSockets: 8, nodesPerSocket: 1, coresPerNode: 32, cpusPerCore: 2},
+}
+
+// benchmarkAllocatedPercents are the ratios of the allocated physical cores per NUMA Node.
+var benchmarkAllocatedPercents = []int{0, 50, 75}
+
+// benchmarkLatencyBudget is the maximum latency per operation on a single node.
+// The scheduling cycle runs Filter against hundreds of nodes, so these budgets stay far below it.
+var benchmarkLatencyBudget = map[string]time.Duration{
+	"GetTopologyHints": 3 * time.Millisecond,
+	"Allocate":         6 * time.Millisecond,
+	"Filter":           10 * time.Millisecond,
+}
+
+const benchmarkNodeName = "test-node-1"
+
+func newBenchmarkNode(topology benchmarkTopology) *corev1.Node {
+	numCPUs := topology.numSockets * topology.nodesPerSocket * topology.coresPerNode * topology.cpusPerCore
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: benchmarkNodeName,
+			Labels: map[string]string{
+				extension.LabelNUMATopologyPolicy: string(extension.NUMATopologyPolicySingleNUMANode),
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewQuantity(int64(numCPUs), resource.DecimalSI),
+				corev1.ResourceMemory: resource.MustParse("2Ti"),
+			},
+		},
+	}
+}
+
+func newBenchmarkTopologyOptions(topology benchmarkTopology) resourcemanager.TopologyOptions {
+	cpuTopology := buildCPUTopologyForTest(topology.numSockets, topology.nodesPerSocket, topology.coresPerNode, topology.cpusPerCore)
+	options := resourcemanager.TopologyOptions{
+		CPUTopology: cpuTopology,
+	}
+	for i := 0; i < cpuTopology.NumNodes; i++ {
+		options.NUMANodeResources = append(options.NUMANodeResources, resourcemanager.NUMANodeResource{
+			Node: i,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewQuantity(int64(cpuTopology.CPUsPerNode()), resource.DecimalSI),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		})
+	}
+	return options
+}
+
+// newBenchmarkAllocation allocates the first allocatedPercent physical cores of every NUMA Node.
+func newBenchmarkAllocation(topologyOptions resourcemanager.TopologyOptions, allocatedPercent int) *resourcemanager.PodAllocation {
+	cpuTopology := topologyOptions.CPUTopology
+	coresPerNode := cpuTopology.NumCores / cpuTopology.NumNodes
+	allocatedCores := coresPerNode * allocatedPercent / 100
+	if allocatedCores == 0 {
+		return nil
+	}
+	allocation := &resourcemanager.PodAllocation{
+		UID:       types.UID("allocated-pod"),
+		Namespace: "default",
+		Name:      "allocated-pod",
+	}
+	builder := cpuset.NewCPUSetBuilder()
+	for _, numaNode := range cpuTopology.CPUDetails.NUMANodes().ToSliceNoSort() {
+		cores := cpuTopology.CPUDetails.CoresInNUMANodes(numaNode).ToSlice()
+		for _, core := range cores[:allocatedCores] {
+			builder.Add(cpuTopology.CPUDetails.CPUsInCores(core).ToSliceNoSort()...)
+		}
+		allocation.NUMANodeResources = append(allocation.NUMANodeResources, resourcemanager.NUMANodeResource{
+			Node: numaNode,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewQuantity(int64(allocatedCores*cpuTopology.CPUsPerCore()), resource.DecimalSI),
+			},
+		})
+	}
+	allocation.CPUSet = builder.Result()
+	return allocation
+}
+
+func newBenchmarkResourceOptions(topologyOptions resourcemanager.TopologyOptions) *resourcemanager.ResourceOptions {
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("2"),
+	}
+	return &resourcemanager.ResourceOptions{
+		NumCPUsNeeded:         2,
+		RequestCPUBind:        true,
+		Requests:              requests,
+		OriginalRequests:      requests.DeepCopy(),
+		RequiredCPUBindPolicy: true,
+		CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+		TopologyOptions:       topologyOptions,
+	}
+}
+
+func newBenchmarkResourceManager(b *testing.B, topology benchmarkTopology, allocatedPercent int) (resourcemanager.ResourceManager, *corev1.Node, *resourcemanager.ResourceOptions) {
+	suit := newPluginTestSuit(b, nil, nil)
+	tom := resourcemanager.NewTopologyOptionsManager()
+	topologyOptions := newBenchmarkTopologyOptions(topology)
+	tom.UpdateTopologyOptions(benchmarkNodeName, func(options *resourcemanager.TopologyOptions) {
+		*options = topologyOptions
+	})
+	resourceManager := NewResourceManager(suit.Handle, schedulingconfig.NUMAMostAllocated, tom)
+	if allocation := newBenchmarkAllocation(topologyOptions, allocatedPercent); allocation != nil {
+		resourceManager.Update(benchmarkNodeName, allocation)
+	}
+
+	node := newBenchmarkNode(topology)
+	options := newBenchmarkResourceOptions(tom.GetTopologyOptions(benchmarkNodeName))
+	assert.NoError(b, resourcemanager.AmplifyNUMANodeResources(node, &options.TopologyOptions))
+	return resourceManager, node, options
+}
+
+func benchmarkGetTopologyHints(b *testing.B, topology benchmarkTopology, allocatedPercent int) {
+	resourceManager, node, options := newBenchmarkResourceManager(b, topology, allocatedPercent)
+	pod := &corev1.Pod{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := resourceManager.GetTopologyHints(node, pod, options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkAllocate(b *testing.B, topology benchmarkTopology, allocatedPercent int) {
+	resourceManager, node, options := newBenchmarkResourceManager(b, topology, allocatedPercent)
+	pod := &corev1.Pod{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := resourceManager.Allocate(node, pod, options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkFilter(b *testing.B, topology benchmarkTopology, allocatedPercent int) {
+	node := newBenchmarkNode(topology)
+	suit := newPluginTestSuit(b, nil, []*corev1.Node{node})
+	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NoError(b, err)
+	plg := p.(*Plugin)
+
+	topologyOptions := newBenchmarkTopologyOptions(topology)
+	plg.topologyOptionsManager.UpdateTopologyOptions(benchmarkNodeName, func(options *resourcemanager.TopologyOptions) {
+		*options = topologyOptions
+	})
+	if allocation := newBenchmarkAllocation(topologyOptions, allocatedPercent); allocation != nil {
+		plg.resourceManager.Update(benchmarkNodeName, allocation)
+	}
+	suit.start()
+
+	nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(benchmarkNodeName)
+	assert.NoError(b, err)
+
+	cycleState := framework.NewCycleState()
+	cycleState.Write(stateKey, &preFilterState{
+		requestCPUBind:         true,
+		requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+		preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+		numCPUsNeeded:          2,
+	})
+	topologymanager.InitStore(cycleState)
+	pod := &corev1.Pod{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if status := plg.Filter(context.TODO(), cycleState, pod, nodeInfo); !status.IsSuccess() {
+			b.Fatal(status.AsError())
+		}
+	}
+}
+
+var benchmarkFuncs = map[string]func(b *testing.B, topology benchmarkTopology, allocatedPercent int){
+	"GetTopologyHints": benchmarkGetTopologyHints,
+	"Allocate":         benchmarkAllocate,
+	"Filter":           benchmarkFilter,
+}
+
+func runBenchmarks(b *testing.B, name string) {
+	for _, topology := range benchmarkTopologies {
+		for _, allocatedPercent := range benchmarkAllocatedPercents {
+			topology, allocatedPercent := topology, allocatedPercent
+			b.Run(fmt.Sprintf("%s-%dAllocated", topology, allocatedPercent), func(b *testing.B) {
+				benchmarkFuncs[name](b, topology, allocatedPercent)
+			})
+		}
+	}
+}
+
+func BenchmarkResourceManagerGetTopologyHints(b *testing.B) {
+	runBenchmarks(b, "GetTopologyHints")
+}
+
+func BenchmarkResourceManagerAllocate(b *testing.B) {
+	runBenchmarks(b, "Allocate")
+}
+
+func BenchmarkPluginFilter(b *testing.B) {
+	runBenchmarks(b, "Filter")
+}
+
+func TestNodeNUMAResourceLatencyRegression(t *testing.T) {
+	if os.Getenv(envBenchmarkRegression) != "true" {
+		t.Skipf("skip the latency regression check, set %s=true to enable it", envBenchmarkRegression)
+	}
+	for name, budget := range benchmarkLatencyBudget {
+		for _, topology := range benchmarkTopologies {
+			for _, allocatedPercent := range benchmarkAllocatedPercents {
+				topology, allocatedPercent := topology, allocatedPercent
+				result := testing.Benchmark(func(b *testing.B) {
+					benchmarkFuncs[name](b, topology, allocatedPercent)
+				})
+				latency := time.Duration(result.NsPerOp())
+				t.Logf("%s %s-%dAllocated: %v", name, topology, allocatedPercent, latency)
+				if latency > budget {
+					t.Errorf("%s %s-%dAllocated takes %v, exceeds the budget %v", name, topology, allocatedPercent, latency, budget)
+				}
+			}
+		}
+	}
+}
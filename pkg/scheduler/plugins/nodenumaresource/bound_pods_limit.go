@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+)
+
+const (
+	ErrTooManyBoundPods            = "node(s) had too many cpuset-bound Pods"
+	ErrTooManyBoundPodsOnNUMANodes = "node(s) had too many cpuset-bound Pods on every NUMA Node"
+)
+
+// boundPodsLimit is the maximum number of the cpuset-bound Pods on the node and on each NUMA Node.
+// Zero means unlimited.
+type boundPodsLimit struct {
+	maxPerNode     int
+	maxPerNUMANode int
+}
+
+// getBoundPodsLimit returns the limits of the plugin args overridden by the node annotations.
+func (p *Plugin) getBoundPodsLimit(node *corev1.Node) (boundPodsLimit, error) {
+	limit := boundPodsLimit{
+		maxPerNode:     int(p.pluginArgs.MaxBoundPodsPerNode),
+		maxPerNUMANode: int(p.pluginArgs.MaxBoundPodsPerNUMANode),
+	}
+	if numPods, ok, err := extension.GetNodeMaxBoundPods(node.Annotations); err != nil {
+		return limit, err
+	} else if ok {
+		limit.maxPerNode = numPods
+	}
+	if numPods, ok, err := extension.GetNodeMaxBoundPodsPerNUMANode(node.Annotations); err != nil {
+		return limit, err
+	} else if ok {
+		limit.maxPerNUMANode = numPods
+	}
+	return limit, nil
+}
+
+// getSaturatedNUMANodes returns the NUMA Nodes which have reached the limit of the cpuset-bound Pods.
+// It also returns whether the node itself has reached the limit.
+func (p *Plugin) getSaturatedNUMANodes(nodeName string, limit boundPodsLimit, cpuTopology *resourcemanager.CPUTopology) (numaNodes []int, nodeSaturated bool) {
+	if limit.maxPerNode <= 0 && limit.maxPerNUMANode <= 0 {
+		return nil, false
+	}
+	numPods, numPodsByNUMANode := p.resourceManager.GetNodeAllocation(nodeName).GetBoundPods(cpuTopology)
+
+	if limit.maxPerNode > 0 && numPods >= limit.maxPerNode {
+		nodeSaturated = true
+	}
+	if limit.maxPerNUMANode > 0 && cpuTopology != nil {
+		for _, numaNode := range cpuTopology.CPUDetails.NUMANodes().ToSlice() {
+			if numPodsByNUMANode[numaNode] >= limit.maxPerNUMANode {
+				numaNodes = append(numaNodes, numaNode)
+			}
+		}
+	}
+	return numaNodes, nodeSaturated
+}
+
+// filterBoundPods checks whether the node and its NUMA Nodes can host one more cpuset-bound Pod,
+// so that enough shared CPUs are kept for the DaemonSets and the Burstable Pods.
+// The Pod allocated from a Reservation reuses the bound slot of the Reservation.
+func (p *Plugin) filterBoundPods(cycleState *framework.CycleState, pod *corev1.Pod, node *corev1.Node, topologyOptions resourcemanager.TopologyOptions) *framework.Status {
+	limit, err := p.getBoundPodsLimit(node)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if limit.maxPerNode <= 0 && limit.maxPerNUMANode <= 0 {
+		return nil
+	}
+	reservationReservedCPUs, err := p.getReservationReservedCPUs(cycleState, pod, node.Name)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if !reservationReservedCPUs.IsEmpty() {
+		return nil
+	}
+
+	saturatedNUMANodes, nodeSaturated := p.getSaturatedNUMANodes(node.Name, limit, topologyOptions.CPUTopology)
+	if nodeSaturated {
+		return framework.NewStatus(framework.Unschedulable, ErrTooManyBoundPods)
+	}
+	if len(saturatedNUMANodes) > 0 && len(saturatedNUMANodes) == topologyOptions.CPUTopology.CPUDetails.NUMANodes().Size() {
+		return framework.NewStatus(framework.Unschedulable, ErrTooManyBoundPodsOnNUMANodes)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func TestPluginBoundPodsLimit(t *testing.T) {
+	tests := []struct {
+		name                    string
+		maxBoundPodsPerNode     int32
+		maxBoundPodsPerNUMANode int32
+		nodeAnnotations         map[string]string
+		boundCPUSets            []cpuset.CPUSet
+		wantFilter              *framework.Status
+		wantErr                 bool
+		wantCPUSet              cpuset.CPUSet
+	}{
+		{
+			name:         "no limit",
+			boundCPUSets: []cpuset.CPUSet{cpuset.NewCPUSet(0, 1)},
+			wantCPUSet:   cpuset.NewCPUSet(2, 3),
+		},
+		{
+			name:                "node has room for one more bound pod",
+			maxBoundPodsPerNode: 2,
+			boundCPUSets:        []cpuset.CPUSet{cpuset.NewCPUSet(0, 1)},
+			wantCPUSet:          cpuset.NewCPUSet(2, 3),
+		},
+		{
+			name:                "node reached the limit of bound pods",
+			maxBoundPodsPerNode: 2,
+			boundCPUSets:        []cpuset.CPUSet{cpuset.NewCPUSet(0, 1), cpuset.NewCPUSet(8, 9)},
+			wantFilter:          framework.NewStatus(framework.Unschedulable, ErrTooManyBoundPods),
+		},
+		{
+			name:                "node annotation overrides the limit of bound pods",
+			maxBoundPodsPerNode: 2,
+			nodeAnnotations: map[string]string{
+				extension.AnnotationNodeMaxBoundPods: "1",
+			},
+			boundCPUSets: []cpuset.CPUSet{cpuset.NewCPUSet(0, 1)},
+			wantFilter:   framework.NewStatus(framework.Unschedulable, ErrTooManyBoundPods),
+		},
+		{
+			name:                    "bind the CPUs on the NUMA Node below the limit",
+			maxBoundPodsPerNUMANode: 1,
+			boundCPUSets:            []cpuset.CPUSet{cpuset.NewCPUSet(0, 1)},
+			wantCPUSet:              cpuset.NewCPUSet(8, 9),
+		},
+		{
+			name: "all NUMA Nodes reached the limit of bound pods",
+			nodeAnnotations: map[string]string{
+				extension.AnnotationNodeMaxBoundPodsPerNUMANode: "1",
+			},
+			boundCPUSets: []cpuset.CPUSet{cpuset.NewCPUSet(0, 1), cpuset.NewCPUSet(8, 9)},
+			wantFilter:   framework.NewStatus(framework.Unschedulable, ErrTooManyBoundPodsOnNUMANodes),
+		},
+		{
+			name: "invalid node annotation",
+			nodeAnnotations: map[string]string{
+				extension.AnnotationNodeMaxBoundPods: "-1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+					Labels: map[string]string{
+						extension.LabelNodeNUMAAllocateStrategy: string(schedulingconfig.NUMAMostAllocated),
+					},
+					Annotations: tt.nodeAnnotations,
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("16"),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			}
+			suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+			suit.nodeNUMAResourceArgs.MaxBoundPodsPerNode = tt.maxBoundPodsPerNode
+			suit.nodeNUMAResourceArgs.MaxBoundPodsPerNUMANode = tt.maxBoundPodsPerNUMANode
+			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+			assert.NoError(t, err)
+			plg := p.(*Plugin)
+			plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+			})
+			for _, cpus := range tt.boundCPUSets {
+				plg.resourceManager.Update(node.Name, &resourcemanager.PodAllocation{
+					UID:                uuid.NewUUID(),
+					CPUSet:             cpus,
+					CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+				})
+			}
+			suit.start()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       uuid.NewUUID(),
+				},
+			}
+			state := &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          2,
+				requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, state)
+
+			nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+			assert.NoError(t, err)
+			status := plg.Filter(context.TODO(), cycleState, pod, nodeInfo)
+			if tt.wantErr {
+				assert.Equal(t, framework.Error, status.Code())
+				return
+			}
+			assert.Equal(t, tt.wantFilter, status)
+			if !status.IsSuccess() {
+				return
+			}
+
+			status = plg.Reserve(context.TODO(), cycleState, pod, node.Name)
+			assert.True(t, status.IsSuccess(), status)
+			assert.Equal(t, tt.wantCPUSet, state.allocation.CPUSet)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func buildNUMANodeResourcesForTest(numaNodes ...int) []resourcemanager.NUMANodeResource {
+	var result []resourcemanager.NUMANodeResource
+	for _, numaNode := range numaNodes {
+		result = append(result, resourcemanager.NUMANodeResource{
+			Node: numaNode,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		})
+	}
+	return result
+}
+
+func Test_validateNodeTopology(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		allocatableCPU  string
+		topologyOptions resourcemanager.TopologyOptions
+		want            string
+	}{
+		{
+			name:           "no CPU topology",
+			allocatableCPU: "16",
+		},
+		{
+			name:           "consistent topology",
+			allocatableCPU: "16",
+			topologyOptions: resourcemanager.TopologyOptions{
+				CPUTopology:       buildCPUTopologyForTest(2, 1, 4, 2),
+				NUMANodeResources: buildNUMANodeResourcesForTest(0, 1),
+			},
+		},
+		{
+			name:           "no NUMA Nodes",
+			allocatableCPU: "16",
+			topologyOptions: resourcemanager.TopologyOptions{
+				CPUTopology: buildCPUTopologyForTest(2, 1, 4, 2),
+			},
+			want: resourcemanager.BrokenTopologyReasonNoNUMANodes,
+		},
+		{
+			name:           "CPUs less than allocatable",
+			allocatableCPU: "32",
+			topologyOptions: resourcemanager.TopologyOptions{
+				CPUTopology:       buildCPUTopologyForTest(2, 1, 4, 2),
+				NUMANodeResources: buildNUMANodeResourcesForTest(0, 1),
+			},
+			want: resourcemanager.BrokenTopologyReasonInsufficientCPUs,
+		},
+		{
+			name:           "amplified allocatable",
+			annotations:    map[string]string{extension.AnnotationNodeRawAllocatable: `{"cpu":"16"}`},
+			allocatableCPU: "32",
+			topologyOptions: resourcemanager.TopologyOptions{
+				CPUTopology:       buildCPUTopologyForTest(2, 1, 4, 2),
+				NUMANodeResources: buildNUMANodeResourcesForTest(0, 1),
+			},
+		},
+		{
+			name:           "NUMA Nodes mismatched",
+			allocatableCPU: "16",
+			topologyOptions: resourcemanager.TopologyOptions{
+				CPUTopology:       buildCPUTopologyForTest(2, 1, 4, 2),
+				NUMANodeResources: buildNUMANodeResourcesForTest(0, 2),
+			},
+			want: resourcemanager.BrokenTopologyReasonMismatchedNUMANode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-node-1",
+					Annotations: tt.annotations,
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse(tt.allocatableCPU),
+					},
+				},
+			}
+			assert.Equal(t, tt.want, resourcemanager.ValidateNodeTopology(node, &tt.topologyOptions))
+		})
+	}
+}
+
+func TestPluginBrokenTopologyFallback(t *testing.T) {
+	tests := []struct {
+		name                  string
+		disableFallback       bool
+		allocatableCPU        string
+		numaNodes             []int
+		requiredCPUBindPolicy schedulingconfig.CPUBindPolicy
+		wantFilter            *framework.Status
+		wantBrokenReason      string
+		wantAllocated         bool
+	}{
+		{
+			name:           "consistent topology",
+			allocatableCPU: "16",
+			numaNodes:      []int{0, 1},
+			wantAllocated:  true,
+		},
+		{
+			name:             "fallback to the scheduling without CPU binding",
+			allocatableCPU:   "32",
+			numaNodes:        []int{0, 1},
+			wantBrokenReason: resourcemanager.BrokenTopologyReasonInsufficientCPUs,
+		},
+		{
+			name:                  "reject the Pod requiring CPU bind policy",
+			allocatableCPU:        "16",
+			requiredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			wantFilter:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrBrokenNodeTopology),
+			wantBrokenReason:      resourcemanager.BrokenTopologyReasonNoNUMANodes,
+		},
+		{
+			name:            "fallback disabled",
+			disableFallback: true,
+			allocatableCPU:  "32",
+			numaNodes:       []int{0, 1},
+			wantAllocated:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, k8sfeature.DefaultMutableFeatureGate, features.BrokenNodeTopologyFallback, !tt.disableFallback)()
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-node-1",
+					Labels: map[string]string{},
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(tt.allocatableCPU),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			}
+			suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+			assert.NoError(t, err)
+			plg := p.(*Plugin)
+			plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+				options.NUMANodeResources = buildNUMANodeResourcesForTest(tt.numaNodes...)
+			})
+			suit.start()
+			resourcemanager.DeleteBrokenNodeTopologyMetrics(node.Name)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       uuid.NewUUID(),
+				},
+			}
+			state := &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          2,
+				requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				requiredCPUBindPolicy:  tt.requiredCPUBindPolicy,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, state)
+
+			nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+			assert.NoError(t, err)
+			status := plg.Filter(context.TODO(), cycleState, pod, nodeInfo)
+			assert.Equal(t, tt.wantFilter, status)
+			if tt.wantBrokenReason != "" {
+				broken, err := testutil.GetGaugeMetricValue(resourcemanager.BrokenNodeTopology.WithLabelValues(node.Name, tt.wantBrokenReason))
+				assert.NoError(t, err)
+				assert.Equal(t, float64(1), broken)
+			}
+			if !status.IsSuccess() {
+				return
+			}
+
+			status = plg.Reserve(context.TODO(), cycleState, pod, node.Name)
+			assert.True(t, status.IsSuccess(), status)
+			assert.Equal(t, tt.wantAllocated, state.allocation != nil)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+)
+
+// preFilterContainerCPUBinds prepares the state of the Pod specifying the CPU bind policies of its containers.
+// The CPUs of the bound containers are allocated as a whole with the same policy, and then split to the containers.
+func (p *Plugin) preFilterContainerCPUBinds(state *preFilterState, pod *corev1.Pod, resourceSpec *extension.ResourceSpec) error {
+	containers := map[string]*corev1.Container{}
+	for i := range pod.Spec.Containers {
+		containers[pod.Spec.Containers[i].Name] = &pod.Spec.Containers[i]
+	}
+
+	var cpuBindPolicy schedulingconfig.CPUBindPolicy
+	var containerCPUBinds []resourcemanager.ContainerCPUBind
+	numCPUsNeeded := 0
+	for _, containerSpec := range resourceSpec.Containers {
+		container := containers[containerSpec.Name]
+		if container == nil {
+			return fmt.Errorf("container %s not found", containerSpec.Name)
+		}
+		policy := schedulingconfig.CPUBindPolicy(containerSpec.RequiredCPUBindPolicy)
+		if policy == schedulingconfig.CPUBindPolicyDefault {
+			policy = p.pluginArgs.DefaultCPUBindPolicy
+		}
+		if policy == "" {
+			// the container runs in the CPU Shared Pool
+			continue
+		}
+		if policy != schedulingconfig.CPUBindPolicyFullPCPUs && policy != schedulingconfig.CPUBindPolicySpreadByPCPUs {
+			return fmt.Errorf("unsupported cpu bind policy %s of container %s", policy, containerSpec.Name)
+		}
+		if cpuBindPolicy != "" && cpuBindPolicy != policy {
+			return fmt.Errorf("the containers must require the same cpu bind policy")
+		}
+		cpuBindPolicy = policy
+
+		requestedCPU := container.Resources.Requests.Cpu().MilliValue()
+		if requestedCPU <= 0 || requestedCPU%1000 != 0 {
+			return fmt.Errorf("the requested CPUs of container %s must be positive integer", containerSpec.Name)
+		}
+		containerCPUBinds = append(containerCPUBinds, resourcemanager.ContainerCPUBind{
+			Name:    containerSpec.Name,
+			NumCPUs: int(requestedCPU / 1000),
+		})
+		numCPUsNeeded += int(requestedCPU / 1000)
+	}
+	if numCPUsNeeded == 0 {
+		return nil
+	}
+
+	intraNodeSpread, err := newIntraNodeSpreadState(pod)
+	if err != nil {
+		return err
+	}
+	state.requestCPUBind = true
+	state.requiredCPUBindPolicy = cpuBindPolicy
+	state.preferredCPUBindPolicy = cpuBindPolicy
+	state.preferredCPUExclusivePolicy = resourceSpec.PreferredCPUExclusivePolicy
+	state.numaAllocateStrategy = resourceSpec.PreferredNUMAAllocateStrategy
+	state.numCPUsNeeded = numCPUsNeeded
+	state.intraNodeSpread = intraNodeSpread
+	state.bindToDeviceNUMA = extension.IsPodBindToDeviceNUMA(pod.Annotations)
+	state.containerCPUBinds = containerCPUBinds
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+)
+
+func TestPlugin_preFilterContainerCPUBinds(t *testing.T) {
+	newPod := func(containerCPUs ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "test-pod",
+			},
+		}
+		for i, cpu := range containerCPUs {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+				Name: []string{"main", "sidecar", "agent"}[i],
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse(cpu),
+					},
+				},
+			})
+		}
+		return pod
+	}
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		containers []extension.ContainerResourceSpec
+		wantState  *preFilterState
+		wantErr    bool
+	}{
+		{
+			name: "bind one container and share the other",
+			pod:  newPod("4", "500m"),
+			containers: []extension.ContainerResourceSpec{
+				{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+				{Name: "sidecar"},
+			},
+			wantState: &preFilterState{
+				requestCPUBind:         true,
+				requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          4,
+				containerCPUBinds: []resourcemanager.ContainerCPUBind{
+					{Name: "main", NumCPUs: 4},
+				},
+			},
+		},
+		{
+			name: "bind containers with the default policy",
+			pod:  newPod("2", "2", "500m"),
+			containers: []extension.ContainerResourceSpec{
+				{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyDefault},
+				{Name: "sidecar", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+			},
+			wantState: &preFilterState{
+				requestCPUBind:         true,
+				requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          4,
+				containerCPUBinds: []resourcemanager.ContainerCPUBind{
+					{Name: "main", NumCPUs: 2},
+					{Name: "sidecar", NumCPUs: 2},
+				},
+			},
+		},
+		{
+			name: "no container bound",
+			pod:  newPod("4", "500m"),
+			containers: []extension.ContainerResourceSpec{
+				{Name: "sidecar"},
+			},
+			wantState: &preFilterState{},
+		},
+		{
+			name: "container not found",
+			pod:  newPod("4"),
+			containers: []extension.ContainerResourceSpec{
+				{Name: "sidecar", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+			},
+			wantErr: true,
+		},
+		{
+			name: "conflicting container policies",
+			pod:  newPod("2", "2"),
+			containers: []extension.ContainerResourceSpec{
+				{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+				{Name: "sidecar", RequiredCPUBindPolicy: extension.CPUBindPolicySpreadByPCPUs},
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-integer container CPUs",
+			pod:  newPod("1500m"),
+			containers: []extension.ContainerResourceSpec{
+				{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{
+				pluginArgs: &schedulingconfig.NodeNUMAResourceArgs{
+					DefaultCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				},
+			}
+			state := &preFilterState{}
+			err := p.preFilterContainerCPUBinds(state, tt.pod, &extension.ResourceSpec{Containers: tt.containers})
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantState, state)
+			}
+		})
+	}
+}
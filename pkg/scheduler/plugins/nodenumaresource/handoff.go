@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+var _ frameworkext.HandoffReconciler = &Plugin{}
+
+// conflictingAllocation is a Pod whose cpuset conflicts with the Pods bound earlier on the node.
+type conflictingAllocation struct {
+	pod          *corev1.Pod
+	conflictCPUs cpuset.CPUSet
+}
+
+// ReconcileHandoff detects the CPUs bound by more Pods than the MaxRefCount, which happens when the previous leader
+// binds the Pods while the new leader is rebuilding the allocations. The Pods bound earlier keep the CPUs and the
+// later Pods are reported. The later Pods not started yet are deleted to be rescheduled by their controllers.
+func (p *Plugin) ReconcileHandoff(ctx context.Context) error {
+	pods, err := p.handle.SharedInformerFactory().Core().V1().Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	conflicts := findConflictingAllocations(pods, func(nodeName string) int {
+		return p.topologyOptionsManager.GetTopologyOptions(nodeName).MaxRefCount
+	})
+	for _, conflict := range conflicts {
+		pod := conflict.pod
+		resourcemanager.HandoffConflictingAllocations.WithLabelValues(pod.Spec.NodeName).Inc()
+		klog.Warningf("cpuset of pod %s conflicts with the pods bound earlier on node %s, conflicting cpus: %s",
+			klog.KObj(pod), pod.Spec.NodeName, conflict.conflictCPUs.String())
+		p.handle.EventRecorder().Eventf(pod, nil, corev1.EventTypeWarning, "CPUSetConflict", "Handoff",
+			"CPUs %s conflict with the Pods bound earlier on node %s", conflict.conflictCPUs.String(), pod.Spec.NodeName)
+		if !isReschedulable(pod) {
+			continue
+		}
+		err = p.handle.ClientSet().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+			Preconditions: metav1.NewUIDPreconditions(string(pod.UID)),
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the pod with conflicting cpuset", "pod", klog.KObj(pod))
+			continue
+		}
+		klog.V(4).InfoS("Deleted the pod with conflicting cpuset to reschedule", "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+	}
+	return nil
+}
+
+// findConflictingAllocations returns the Pods whose cpuset exceeds the MaxRefCount of the CPUs on the node,
+// visiting the Pods of each node in the order they were bound.
+func findConflictingAllocations(pods []*corev1.Pod, getMaxRefCount func(nodeName string) int) []conflictingAllocation {
+	type boundPod struct {
+		pod  *corev1.Pod
+		cpus cpuset.CPUSet
+	}
+	nodeBoundPods := map[string][]boundPod{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || util.IsPodTerminated(pod) {
+			continue
+		}
+		// the System QoS Pods share the reserved CPUs which are accounted apart
+		if extension.GetPodQoSClassRaw(pod) == extension.QoSSystem {
+			continue
+		}
+		resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+		if err != nil || resourceStatus.CPUSet == "" {
+			continue
+		}
+		cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+		if err != nil || cpus.IsEmpty() {
+			continue
+		}
+		nodeBoundPods[pod.Spec.NodeName] = append(nodeBoundPods[pod.Spec.NodeName], boundPod{pod: pod, cpus: cpus})
+	}
+
+	var conflicts []conflictingAllocation
+	nodeNames := make([]string, 0, len(nodeBoundPods))
+	for nodeName := range nodeBoundPods {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		boundPods := nodeBoundPods[nodeName]
+		sort.Slice(boundPods, func(i, j int) bool {
+			ti, tj := getPodBoundTime(boundPods[i].pod), getPodBoundTime(boundPods[j].pod)
+			if !ti.Equal(tj) {
+				return ti.Before(tj)
+			}
+			return boundPods[i].pod.UID < boundPods[j].pod.UID
+		})
+		maxRefCount := getMaxRefCount(nodeName)
+		if maxRefCount <= 0 {
+			maxRefCount = 1
+		}
+		refCounts := map[int]int{}
+		for _, bp := range boundPods {
+			conflictCPUs := bp.cpus.Filter(func(cpu int) bool {
+				return refCounts[cpu] >= maxRefCount
+			})
+			if !conflictCPUs.IsEmpty() {
+				conflicts = append(conflicts, conflictingAllocation{pod: bp.pod, conflictCPUs: conflictCPUs})
+				continue
+			}
+			for _, cpu := range bp.cpus.ToSliceNoSort() {
+				refCounts[cpu]++
+			}
+		}
+	}
+	return conflicts
+}
+
+// getPodBoundTime returns the time when the Pod was bound to the node, or the creation time if unknown.
+func getPodBoundTime(pod *corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// isReschedulable checks whether the Pod can be deleted safely to be recreated and rescheduled by its controller.
+func isReschedulable(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending || metav1.GetControllerOf(pod) == nil {
+		return false
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Running != nil || containerStatus.State.Terminated != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestFindConflictingAllocations(t *testing.T) {
+	now := time.Now()
+	newPod := func(name, nodeName, cpus string, boundAt time.Time) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				UID:         types.UID(name),
+				Annotations: map[string]string{},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:               corev1.PodScheduled,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(boundAt),
+					},
+				},
+			},
+		}
+		if cpus != "" {
+			assert.NoError(t, extension.SetResourceStatus(pod, &extension.ResourceStatus{CPUSet: cpus}))
+		}
+		return pod
+	}
+	tests := []struct {
+		name        string
+		pods        []*corev1.Pod
+		maxRefCount int
+		want        map[string]string
+	}{
+		{
+			name: "no conflicts",
+			pods: []*corev1.Pod{
+				newPod("pod-1", "node-1", "0-1", now),
+				newPod("pod-2", "node-1", "2-3", now.Add(time.Second)),
+				newPod("pod-3", "node-2", "0-1", now),
+				newPod("pod-4", "node-1", "", now),
+			},
+			maxRefCount: 1,
+			want:        map[string]string{},
+		},
+		{
+			name: "the pod bound later conflicts",
+			pods: []*corev1.Pod{
+				newPod("pod-2", "node-1", "1-2", now.Add(time.Second)),
+				newPod("pod-1", "node-1", "0-1", now),
+				newPod("pod-3", "node-1", "2-3", now.Add(2*time.Second)),
+			},
+			maxRefCount: 1,
+			want: map[string]string{
+				"pod-2": "1",
+			},
+		},
+		{
+			name: "shared within the max ref count",
+			pods: []*corev1.Pod{
+				newPod("pod-1", "node-1", "0-1", now),
+				newPod("pod-2", "node-1", "0-1", now.Add(time.Second)),
+				newPod("pod-3", "node-1", "1-2", now.Add(2*time.Second)),
+			},
+			maxRefCount: 2,
+			want: map[string]string{
+				"pod-3": "1",
+			},
+		},
+		{
+			name: "ignore the unassigned and terminated pods",
+			pods: []*corev1.Pod{
+				newPod("pod-1", "node-1", "0-1", now),
+				newPod("pod-2", "", "0-1", now.Add(time.Second)),
+				func() *corev1.Pod {
+					pod := newPod("pod-3", "node-1", "0-1", now.Add(2*time.Second))
+					pod.Status.Phase = corev1.PodSucceeded
+					return pod
+				}(),
+			},
+			maxRefCount: 1,
+			want:        map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := findConflictingAllocations(tt.pods, func(nodeName string) int {
+				return tt.maxRefCount
+			})
+			got := map[string]string{}
+			for _, conflict := range conflicts {
+				got[conflict.pod.Name] = conflict.conflictCPUs.String()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsReschedulable(t *testing.T) {
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "test-rs", Controller: &controller},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+	assert.True(t, isReschedulable(pod))
+
+	started := pod.DeepCopy()
+	started.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	}
+	assert.False(t, isReschedulable(started))
+
+	orphan := pod.DeepCopy()
+	orphan.OwnerReferences = nil
+	assert.False(t, isReschedulable(orphan))
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func Test_validateNUMANodeAllocatable(t *testing.T) {
+	tests := []struct {
+		name                     string
+		annotations              map[string]string
+		allocatable              corev1.ResourceList
+		capacity                 corev1.ResourceList
+		numaNodeTotalAllocatable corev1.ResourceList
+		want                     []corev1.ResourceName
+	}{
+		{
+			name: "no reported NUMA Node allocatable",
+			allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("16"),
+			},
+		},
+		{
+			name: "consistent with the node allocatable",
+			allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			numaNodeTotalAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+		},
+		{
+			name: "the node reservations are in the NUMA Node allocatable",
+			allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("15500m"),
+				corev1.ResourceMemory: resource.MustParse("62Gi"),
+			},
+			capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			numaNodeTotalAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+		},
+		{
+			name: "insufficient NUMA Node allocatable",
+			allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("32"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			numaNodeTotalAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			want: []corev1.ResourceName{corev1.ResourceCPU},
+		},
+		{
+			name: "NUMA Node allocatable exceeds the node capacity",
+			allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("60Gi"),
+			},
+			capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			numaNodeTotalAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("128Gi"),
+			},
+			want: []corev1.ResourceName{corev1.ResourceMemory},
+		},
+		{
+			name: "compare with the allocatable before amplification",
+			annotations: map[string]string{
+				extension.AnnotationNodeRawAllocatable: `{"cpu":"16"}`,
+			},
+			allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("32"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			numaNodeTotalAllocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-node-1",
+					Annotations: tt.annotations,
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: tt.allocatable,
+					Capacity:    tt.capacity,
+				},
+			}
+			topologyOptions := &resourcemanager.TopologyOptions{
+				NUMANodeTotalAllocatable: tt.numaNodeTotalAllocatable,
+			}
+			assert.Equal(t, tt.want, resourcemanager.ValidateNUMANodeAllocatable(node, topologyOptions))
+		})
+	}
+}
+
+func TestPluginInconsistentTopologyQuarantine(t *testing.T) {
+	tests := []struct {
+		name                  string
+		disableQuarantine     bool
+		inconsistentMemory    bool
+		requiredCPUBindPolicy schedulingconfig.CPUBindPolicy
+		wantFilter            *framework.Status
+	}{
+		{
+			name:                  "consistent topology",
+			requiredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+		},
+		{
+			name:                  "quarantine the node from the Pod requiring CPU bind policy",
+			inconsistentMemory:    true,
+			requiredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			wantFilter:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInconsistentNodeTopology),
+		},
+		{
+			name:               "the Pod without required CPU bind policy is not quarantined",
+			inconsistentMemory: true,
+		},
+		{
+			name:                  "quarantine disabled",
+			disableQuarantine:     true,
+			inconsistentMemory:    true,
+			requiredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, k8sfeature.DefaultMutableFeatureGate, features.InconsistentNodeTopologyQuarantine, !tt.disableQuarantine)()
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-node-1",
+					Labels: map[string]string{},
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("16"),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			}
+			numaNodeTotalMemory := resource.MustParse("64Gi")
+			if tt.inconsistentMemory {
+				numaNodeTotalMemory = resource.MustParse("32Gi")
+			}
+			suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+			assert.NoError(t, err)
+			plg := p.(*Plugin)
+			plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+				options.NUMANodeResources = buildNUMANodeResourcesForTest(0, 1)
+				options.NUMANodeTotalAllocatable = corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("16"),
+					corev1.ResourceMemory: numaNodeTotalMemory,
+				}
+			})
+			suit.start()
+			resourcemanager.DeleteInconsistentNodeTopologyMetrics(node.Name)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       uuid.NewUUID(),
+				},
+			}
+			state := &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          2,
+				requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				requiredCPUBindPolicy:  tt.requiredCPUBindPolicy,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, state)
+
+			nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+			assert.NoError(t, err)
+			status := plg.Filter(context.TODO(), cycleState, pod, nodeInfo)
+			assert.Equal(t, tt.wantFilter, status)
+			if tt.wantFilter != nil {
+				inconsistent, err := testutil.GetGaugeMetricValue(resourcemanager.InconsistentNodeTopology.WithLabelValues(node.Name, string(corev1.ResourceMemory)))
+				assert.NoError(t, err)
+				assert.Equal(t, float64(1), inconsistent)
+			}
+		})
+	}
+}
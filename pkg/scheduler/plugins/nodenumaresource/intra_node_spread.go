@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// intraNodeSpreadState is the intra-node spread constraint of the Pod parsed in PreFilter.
+// The replicas are selected by the selector, or by the controller owner if the selector is not specified.
+type intraNodeSpreadState struct {
+	selector labels.Selector
+	ownerUID types.UID
+	required bool
+}
+
+func newIntraNodeSpreadState(pod *corev1.Pod) (*intraNodeSpreadState, error) {
+	spec, err := extension.GetIntraNodeSpreadSpec(pod.Annotations)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+	if spec.Domain != "" && spec.Domain != extension.IntraNodeSpreadDomainL3 {
+		return nil, fmt.Errorf("unsupported intra-node spread domain %s", spec.Domain)
+	}
+
+	state := &intraNodeSpreadState{
+		required: spec.WhenUnsatisfiable == extension.IntraNodeSpreadDoNotSchedule,
+	}
+	if spec.LabelSelector != nil {
+		state.selector, err = metav1.LabelSelectorAsSelector(spec.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labelSelector of intra-node spread, err: %w", err)
+		}
+		return state, nil
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		// the standalone Pod has no replicas to spread from
+		return nil, nil
+	}
+	state.ownerUID = owner.UID
+	return state, nil
+}
+
+func (s *intraNodeSpreadState) isReplica(pod, other *corev1.Pod) bool {
+	if other.UID == pod.UID || other.Namespace != pod.Namespace {
+		return false
+	}
+	if s.selector != nil {
+		return s.selector.Matches(labels.Set(other.Labels))
+	}
+	owner := metav1.GetControllerOf(other)
+	return owner != nil && owner.UID == s.ownerUID
+}
+
+// getSpreadOccupiedCPUs returns the CPUs in the L3 cache domains where the CPUs of the Pod's replicas are bound.
+func (p *Plugin) getSpreadOccupiedCPUs(spreadState *intraNodeSpreadState, pod *corev1.Pod, nodeName string, cpuTopology *resourcemanager.CPUTopology) cpuset.CPUSet {
+	if cpuTopology == nil || !cpuTopology.HasL3Info() {
+		return cpuset.CPUSet{}
+	}
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return cpuset.CPUSet{}
+	}
+
+	l3s := cpuset.NewCPUSetBuilder()
+	for _, podInfo := range nodeInfo.Pods {
+		if !spreadState.isReplica(pod, podInfo.Pod) {
+			continue
+		}
+		cpus, ok := p.resourceManager.GetAllocatedCPUSet(nodeName, podInfo.Pod.UID)
+		if !ok || cpus.IsEmpty() {
+			continue
+		}
+		l3s.Add(cpuTopology.CPUDetails.KeepOnly(cpus).L3s().ToSliceNoSort()...)
+	}
+	return cpuTopology.CPUDetails.CPUsInL3s(l3s.Result().ToSliceNoSort()...)
+}
+
+// filterIntraNodeSpread filters the node if the Pod requiring the intra-node spread can't be allocated enough CPUs
+// in the L3 cache domains not occupied by its replicas.
+func (p *Plugin) filterIntraNodeSpread(cycleState *framework.CycleState, state *preFilterState, pod *corev1.Pod, nodeName string, topologyOptions resourcemanager.TopologyOptions) *framework.Status {
+	if state.intraNodeSpread == nil || !state.intraNodeSpread.required {
+		return nil
+	}
+	if !topologyOptions.CPUTopology.HasL3Info() {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundL3Topology)
+	}
+	occupiedCPUs := p.getSpreadOccupiedCPUs(state.intraNodeSpread, pod, nodeName, topologyOptions.CPUTopology)
+	if occupiedCPUs.IsEmpty() {
+		return nil
+	}
+
+	reservationReservedCPUs, err := p.getReservationReservedCPUs(cycleState, pod, nodeName)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	availableCPUs, _, err := p.resourceManager.GetAvailableCPUs(nodeName, reservationReservedCPUs)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	numCPUsNeeded := state.numCPUsNeededOnNode(topologyOptions.CPUTopology, state.preferredCPUBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs)
+	if availableCPUs.Difference(occupiedCPUs).Size() < numCPUsNeeded {
+		return framework.NewStatus(framework.Unschedulable, ErrIntraNodeSpreadUnsatisfiable)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// buildL3CPUTopologyForTest builds a topology of 1 socket, 1 NUMA node and 8 cores with 2 CPUs per core,
+// where every 2 cores share an L3 cache.
+func buildL3CPUTopologyForTest() *resourcemanager.CPUTopology {
+	builder := resourcemanager.NewCPUTopologyBuilder()
+	for cpuID := 0; cpuID < 16; cpuID++ {
+		builder.AddCPUInfo(0, 0, cpuID/2, cpuID)
+		builder.AddL3Info(cpuID, cpuID/4)
+	}
+	return builder.Result()
+}
+
+func TestCPUTopologyBuilderAddL3Info(t *testing.T) {
+	topology := buildL3CPUTopologyForTest()
+	assert.True(t, topology.HasL3Info())
+	assert.Equal(t, 4, topology.NumL3s)
+	assert.Equal(t, cpuset.NewCPUSet(0, 1, 2, 3), topology.CPUDetails.L3s())
+	assert.Equal(t, cpuset.NewCPUSet(4, 5, 6, 7, 12, 13, 14, 15), topology.CPUDetails.CPUsInL3s(1, 3))
+
+	topology = buildCPUTopologyForTest(2, 1, 4, 2)
+	assert.False(t, topology.HasL3Info())
+}
+
+func Test_newIntraNodeSpreadState(t *testing.T) {
+	controller := true
+	ownerReferences := []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-rs", UID: "test-rs-uid", Controller: &controller},
+	}
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		ownerReferences []metav1.OwnerReference
+		wantState       bool
+		wantRequired    bool
+		wantErr         bool
+	}{
+		{
+			name: "no intra-node spread spec",
+		},
+		{
+			name:            "spread from the Pods of the same controller",
+			annotations:     map[string]string{extension.AnnotationIntraNodeSpreadSpec: `{"domain":"L3"}`},
+			ownerReferences: ownerReferences,
+			wantState:       true,
+		},
+		{
+			name:        "standalone Pod has no replicas",
+			annotations: map[string]string{extension.AnnotationIntraNodeSpreadSpec: `{"domain":"L3"}`},
+		},
+		{
+			name:         "spread from the selected Pods",
+			annotations:  map[string]string{extension.AnnotationIntraNodeSpreadSpec: `{"labelSelector":{"matchLabels":{"app":"test"}},"whenUnsatisfiable":"DoNotSchedule"}`},
+			wantState:    true,
+			wantRequired: true,
+		},
+		{
+			name:        "unsupported domain",
+			annotations: map[string]string{extension.AnnotationIntraNodeSpreadSpec: `{"domain":"Core"}`},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid spec",
+			annotations: map[string]string{extension.AnnotationIntraNodeSpreadSpec: `[]`},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations:     tt.annotations,
+					OwnerReferences: tt.ownerReferences,
+				},
+			}
+			got, err := newIntraNodeSpreadState(pod)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantState, got != nil)
+			if got != nil {
+				assert.Equal(t, tt.wantRequired, got.required)
+			}
+		})
+	}
+}
+
+func TestPluginIntraNodeSpread(t *testing.T) {
+	replica := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-replica",
+			UID:       "test-replica-uid",
+			Labels:    map[string]string{"app": "test"},
+		},
+		Spec: corev1.PodSpec{NodeName: "test-node-1"},
+	}
+	otherPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-other",
+			UID:       "test-other-uid",
+		},
+		Spec: corev1.PodSpec{NodeName: "test-node-1"},
+	}
+	tests := []struct {
+		name              string
+		whenUnsatisfiable extension.IntraNodeSpreadUnsatisfiableAction
+		cpuTopology       *resourcemanager.CPUTopology
+		allocated         map[types.UID]cpuset.CPUSet
+		wantFilter        *framework.Status
+		wantCPUSet        cpuset.CPUSet
+	}{
+		{
+			name:              "prefer the L3 domains apart from the replica",
+			whenUnsatisfiable: extension.IntraNodeSpreadScheduleAnyway,
+			cpuTopology:       buildL3CPUTopologyForTest(),
+			allocated: map[types.UID]cpuset.CPUSet{
+				replica.UID:  cpuset.NewCPUSet(0, 1),
+				otherPod.UID: cpuset.NewCPUSet(4, 5, 6, 7),
+			},
+			wantCPUSet: cpuset.NewCPUSet(8, 9),
+		},
+		{
+			name:              "fallback to the L3 domain of the replica",
+			whenUnsatisfiable: extension.IntraNodeSpreadScheduleAnyway,
+			cpuTopology:       buildL3CPUTopologyForTest(),
+			allocated: map[types.UID]cpuset.CPUSet{
+				replica.UID:  cpuset.NewCPUSet(0, 1),
+				otherPod.UID: cpuset.NewCPUSet(4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15),
+			},
+			wantCPUSet: cpuset.NewCPUSet(2, 3),
+		},
+		{
+			name:              "require the L3 domains apart from the replica",
+			whenUnsatisfiable: extension.IntraNodeSpreadDoNotSchedule,
+			cpuTopology:       buildL3CPUTopologyForTest(),
+			allocated: map[types.UID]cpuset.CPUSet{
+				replica.UID:  cpuset.NewCPUSet(0, 1),
+				otherPod.UID: cpuset.NewCPUSet(4, 5, 6, 7),
+			},
+			wantCPUSet: cpuset.NewCPUSet(8, 9),
+		},
+		{
+			name:              "failed to require the L3 domains apart from the replica",
+			whenUnsatisfiable: extension.IntraNodeSpreadDoNotSchedule,
+			cpuTopology:       buildL3CPUTopologyForTest(),
+			allocated: map[types.UID]cpuset.CPUSet{
+				replica.UID:  cpuset.NewCPUSet(0, 1),
+				otherPod.UID: cpuset.NewCPUSet(4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15),
+			},
+			wantFilter: framework.NewStatus(framework.Unschedulable, ErrIntraNodeSpreadUnsatisfiable),
+		},
+		{
+			name:              "require the L3 domains without L3 topology",
+			whenUnsatisfiable: extension.IntraNodeSpreadDoNotSchedule,
+			cpuTopology:       buildCPUTopologyForTest(1, 1, 8, 2),
+			wantFilter:        framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundL3Topology),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-node-1",
+					Labels: map[string]string{},
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("16"),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			}
+			suit := newPluginTestSuit(t, []*corev1.Pod{replica, otherPod}, []*corev1.Node{node})
+			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+			assert.NoError(t, err)
+			plg := p.(*Plugin)
+			plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+				options.CPUTopology = tt.cpuTopology
+			})
+			for podUID, cpus := range tt.allocated {
+				plg.resourceManager.Update(node.Name, &resourcemanager.PodAllocation{UID: podUID, CPUSet: cpus})
+			}
+			suit.start()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       uuid.NewUUID(),
+					Labels:    map[string]string{"app": "test"},
+					Annotations: map[string]string{
+						extension.AnnotationIntraNodeSpreadSpec: `{"domain":"L3","labelSelector":{"matchLabels":{"app":"test"}},"whenUnsatisfiable":"` + string(tt.whenUnsatisfiable) + `"}`,
+					},
+				},
+			}
+			spreadState, err := newIntraNodeSpreadState(pod)
+			assert.NoError(t, err)
+			state := &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          2,
+				requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				intraNodeSpread:        spreadState,
+			}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, state)
+
+			nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+			assert.NoError(t, err)
+			status := plg.Filter(context.TODO(), cycleState, pod, nodeInfo)
+			assert.Equal(t, tt.wantFilter, status)
+			if !status.IsSuccess() {
+				return
+			}
+
+			status = plg.Reserve(context.TODO(), cycleState, pod, node.Name)
+			assert.True(t, status.IsSuccess(), status)
+			assert.Equal(t, tt.wantCPUSet, state.allocation.CPUSet)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+)
+
+// numaCacheLocalityWeight is the percentage of the NUMA cache locality score in the final score.
+// It is kept small so that the locality only breaks the ties of the nodes with similar resource scores.
+const numaCacheLocalityWeight = 10
+
+// getCacheLocalNUMANodes returns the NUMA Nodes where the caches of the Pod's images are attached.
+func getCacheLocalNUMANodes(node *corev1.Node, pod *corev1.Pod) sets.Int {
+	locality, err := extension.GetNUMACacheLocality(node.Annotations)
+	if err != nil {
+		klog.V(5).ErrorS(err, "failed to get NUMA cache locality", "node", node.Name)
+		return nil
+	}
+	if len(locality) == 0 {
+		return nil
+	}
+	numaNodes := sets.NewInt()
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			numaNodes.Insert(locality[containers[i].Image]...)
+		}
+	}
+	return numaNodes
+}
+
+// getAllocatedNUMANodes returns the NUMA Nodes of the NUMA resources or the CPUs allocated to the Pod.
+func getAllocatedNUMANodes(podAllocation *resourcemanager.PodAllocation, cpuTopology *resourcemanager.CPUTopology) []int {
+	if len(podAllocation.NUMANodeResources) > 0 {
+		numaNodes := make([]int, 0, len(podAllocation.NUMANodeResources))
+		for _, v := range podAllocation.NUMANodeResources {
+			numaNodes = append(numaNodes, v.Node)
+		}
+		return numaNodes
+	}
+	if podAllocation.CPUSet.IsEmpty() || cpuTopology == nil {
+		return nil
+	}
+	return cpuTopology.CPUDetails.KeepOnly(podAllocation.CPUSet).NUMANodes().ToSlice()
+}
+
+// composeNUMACacheLocalityScore composes the score with the percentage of the allocated NUMA Nodes
+// local to the caches of the Pod's images. The score is unchanged if the node declares no cache for the Pod.
+func composeNUMACacheLocalityScore(score int64, node *corev1.Node, pod *corev1.Pod, podAllocation *resourcemanager.PodAllocation, cpuTopology *resourcemanager.CPUTopology) int64 {
+	cacheLocalNUMANodes := getCacheLocalNUMANodes(node, pod)
+	if cacheLocalNUMANodes.Len() == 0 {
+		return score
+	}
+	allocatedNUMANodes := getAllocatedNUMANodes(podAllocation, cpuTopology)
+	if len(allocatedNUMANodes) == 0 {
+		return score
+	}
+	localNUMANodes := 0
+	for _, numaNode := range allocatedNUMANodes {
+		if cacheLocalNUMANodes.Has(numaNode) {
+			localNUMANodes++
+		}
+	}
+	localityScore := int64(localNUMANodes) * framework.MaxNodeScore / int64(len(allocatedNUMANodes))
+	return (score*(100-numaCacheLocalityWeight) + localityScore*numaCacheLocalityWeight) / 100
+}
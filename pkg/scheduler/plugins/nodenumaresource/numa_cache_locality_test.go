@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func TestComposeNUMACacheLocalityScore(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		podAllocation *resourcemanager.PodAllocation
+		want          int64
+	}{
+		{
+			name:          "no cache locality",
+			podAllocation: &resourcemanager.PodAllocation{NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}}},
+			want:          50,
+		},
+		{
+			name:          "invalid cache locality",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: "invalid"},
+			podAllocation: &resourcemanager.PodAllocation{NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}}},
+			want:          50,
+		},
+		{
+			name:          "no cache of the pod image",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: `{"other-image":[0]}`},
+			podAllocation: &resourcemanager.PodAllocation{NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}}},
+			want:          50,
+		},
+		{
+			name:          "NUMA resources aligned with the cache",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: `{"test-image":[0]}`},
+			podAllocation: &resourcemanager.PodAllocation{NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}}},
+			want:          55,
+		},
+		{
+			name:          "NUMA resources not aligned with the cache",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: `{"test-image":[1]}`},
+			podAllocation: &resourcemanager.PodAllocation{NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}}},
+			want:          45,
+		},
+		{
+			name:          "NUMA resources partially aligned with the cache",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: `{"test-image":[1]}`},
+			podAllocation: &resourcemanager.PodAllocation{NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}, {Node: 1}}},
+			want:          50,
+		},
+		{
+			name:          "CPUs aligned with the cache",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: `{"test-image":[1]}`},
+			podAllocation: &resourcemanager.PodAllocation{CPUSet: cpuset.NewCPUSet(8, 9)},
+			want:          55,
+		},
+		{
+			name:          "nothing allocated",
+			annotations:   map[string]string{extension.AnnotationNodeNUMACacheLocality: `{"test-image":[1]}`},
+			podAllocation: &resourcemanager.PodAllocation{},
+			want:          50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node-1", Annotations: tt.annotations}}
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "test-image"}},
+				},
+			}
+			got := composeNUMACacheLocalityScore(50, node, pod, tt.podAllocation, cpuTopology)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
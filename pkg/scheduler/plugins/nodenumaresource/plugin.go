@@ -26,17 +26,24 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
 	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
 )
@@ -47,12 +54,24 @@ const (
 )
 
 const (
-	ErrNotFoundCPUTopology          = "node(s) CPU Topology not found"
-	ErrInvalidCPUTopology           = "node(s) invalid CPU Topology"
+	ErrNotFoundCPUTopology          = resourcemanager.ErrNotFoundCPUTopology
+	ErrInvalidCPUTopology           = resourcemanager.ErrInvalidCPUTopology
 	ErrSMTAlignmentError            = "node(s) requested cpus not multiple cpus per core"
 	ErrRequiredFullPCPUsPolicy      = "node(s) required FullPCPUs policy"
 	ErrInvalidCPUAmplificationRatio = "node(s) invalid CPU amplification ratio"
 	ErrInsufficientAmplifiedCPU     = "Insufficient amplified cpu"
+	ErrNUMATopologyPolicyMismatch   = "node(s) NUMA Topology Policy not match"
+	ErrNodeCoordinatedDraining      = "node(s) are draining for maintenance"
+	ErrNotFoundL3Topology           = "node(s) L3 cache topology not found"
+	ErrIntraNodeSpreadUnsatisfiable = "node(s) didn't have enough CPUs in the L3 domains apart from the replicas"
+	ErrNotFoundDeviceNUMANodes      = "node(s) NUMA Nodes of SR-IOV devices not found"
+	ErrBrokenNodeTopology           = "node(s) NodeResourceTopology is inconsistent with the node"
+	ErrInconsistentNodeTopology     = "node(s) NUMA Node allocatable is inconsistent with the node allocatable"
+	ErrInsufficientReservedCPUs     = "node(s) didn't have enough reserved CPUs"
+	ErrNoFeasibleCPUBindPolicy      = "node(s) didn't support any CPU bind policy for the requested CPUs"
+
+	ErrVirtualTopologyRequiredCPUBind    = "node(s) virtual topology can not satisfy required CPU bind policy"
+	ErrVirtualTopologyNUMATopologyPolicy = "node(s) virtual topology can not satisfy NUMA Topology Policy"
 )
 
 var (
@@ -67,6 +86,7 @@ var (
 	_ frameworkext.ReservationRestorePlugin    = &Plugin{}
 	_ frameworkext.ReservationPreBindPlugin    = &Plugin{}
 	_ topologymanager.NUMATopologyHintProvider = &Plugin{}
+	_ topologymanager.NUMAResourceProvider     = &Plugin{}
 )
 
 type Plugin struct {
@@ -74,30 +94,42 @@ type Plugin struct {
 	pluginArgs      *schedulingconfig.NodeNUMAResourceArgs
 	nrtLister       topologylister.NodeResourceTopologyLister
 	scorer          *resourceAllocationScorer
-	resourceManager ResourceManager
+	numaScorer      *resourceAllocationScorer
+	resourceManager resourcemanager.ResourceManager
+	// reservedCPUsPodSelector selects the System QoS Pods allowed to be pinned on the reserved CPUs.
+	reservedCPUsPodSelector labels.Selector
 
-	topologyOptionsManager TopologyOptionsManager
+	topologyOptionsManager resourcemanager.TopologyOptionsManager
 }
 
 type Option func(*pluginOptions)
 
 type pluginOptions struct {
-	topologyOptionsManager TopologyOptionsManager
-	resourceManager        ResourceManager
+	topologyOptionsManager resourcemanager.TopologyOptionsManager
+	resourceManager        resourcemanager.ResourceManager
 }
 
-func WithTopologyOptionsManager(topologyOptionsManager TopologyOptionsManager) Option {
+func WithTopologyOptionsManager(topologyOptionsManager resourcemanager.TopologyOptionsManager) Option {
 	return func(opts *pluginOptions) {
 		opts.topologyOptionsManager = topologyOptionsManager
 	}
 }
 
-func WithResourceManager(resourceManager ResourceManager) Option {
+func WithResourceManager(resourceManager resourcemanager.ResourceManager) Option {
 	return func(opts *pluginOptions) {
 		opts.resourceManager = resourceManager
 	}
 }
 
+// NewResourceManager creates the ResourceManager with the Node informer of the framework handle.
+func NewResourceManager(
+	handle framework.Handle,
+	defaultNUMAAllocateStrategy schedulingconfig.NUMAAllocateStrategy,
+	topologyOptionsManager resourcemanager.TopologyOptionsManager,
+) resourcemanager.ResourceManager {
+	return resourcemanager.NewResourceManager(handle.SharedInformerFactory().Core().V1().Nodes(), defaultNUMAAllocateStrategy, topologyOptionsManager)
+}
+
 func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option) (framework.Plugin, error) {
 	pluginArgs, ok := args.(*schedulingconfig.NodeNUMAResourceArgs)
 	if !ok {
@@ -109,10 +141,24 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 	if pluginArgs.ScoringStrategy == nil {
 		return nil, fmt.Errorf("scoring strategy not specified")
 	}
-	strategy := pluginArgs.ScoringStrategy.Type
-	scorePlugin, exists := resourceStrategyTypeMap[strategy]
-	if !exists {
-		return nil, fmt.Errorf("scoring strategy %s is not supported", strategy)
+	scorer, err := newResourceAllocationScorer(pluginArgs.ScoringStrategy)
+	if err != nil {
+		return nil, err
+	}
+	var numaScorer *resourceAllocationScorer
+	if pluginArgs.NUMAScoringStrategy != nil {
+		numaScorer, err = newResourceAllocationScorer(pluginArgs.NUMAScoringStrategy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var reservedCPUsPodSelector labels.Selector
+	if pluginArgs.ReservedCPUsPodSelector != nil {
+		reservedCPUsPodSelector, err = metav1.LabelSelectorAsSelector(pluginArgs.ReservedCPUsPodSelector)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	options := &pluginOptions{}
@@ -120,8 +166,10 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 		optFnc(options)
 	}
 
+	resourcemanager.RegisterMetrics()
+
 	if options.topologyOptionsManager == nil {
-		options.topologyOptionsManager = NewTopologyOptionsManager()
+		options.topologyOptionsManager = resourcemanager.NewTopologyOptionsManager()
 	}
 
 	if options.resourceManager == nil {
@@ -133,7 +181,8 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 	if err != nil {
 		return nil, err
 	}
-	if err := registerNodeResourceTopologyEventHandler(nrtInformerFactory, options.topologyOptionsManager); err != nil {
+	nodeLister := handle.SharedInformerFactory().Core().V1().Nodes().Lister()
+	if err := registerNodeResourceTopologyEventHandler(nrtInformerFactory, nodeLister, options.topologyOptionsManager); err != nil {
 		return nil, err
 	}
 	registerPodEventHandler(handle, options.resourceManager)
@@ -141,12 +190,14 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 	nrtLister := nrtInformerFactory.Topology().V1alpha1().NodeResourceTopologies().Lister()
 
 	return &Plugin{
-		handle:                 handle,
-		pluginArgs:             pluginArgs,
-		nrtLister:              nrtLister,
-		scorer:                 scorePlugin(pluginArgs),
-		resourceManager:        options.resourceManager,
-		topologyOptionsManager: options.topologyOptionsManager,
+		handle:                  handle,
+		pluginArgs:              pluginArgs,
+		nrtLister:               nrtLister,
+		scorer:                  scorer,
+		numaScorer:              numaScorer,
+		resourceManager:         options.resourceManager,
+		reservedCPUsPodSelector: reservedCPUsPodSelector,
+		topologyOptionsManager:  options.topologyOptionsManager,
 	}, nil
 }
 
@@ -156,36 +207,69 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 
 func (p *Plugin) Name() string { return Name }
 
-func (p *Plugin) GetResourceManager() ResourceManager {
+func (p *Plugin) GetResourceManager() resourcemanager.ResourceManager {
 	return p.resourceManager
 }
 
-func (p *Plugin) GetTopologyOptionsManager() TopologyOptionsManager {
+func (p *Plugin) GetTopologyOptionsManager() resourcemanager.TopologyOptionsManager {
 	return p.topologyOptionsManager
 }
 
 type preFilterState struct {
 	skip                        bool
 	requestCPUBind              bool
+	requestSoftCPUBind          bool
 	requests                    corev1.ResourceList
 	requiredCPUBindPolicy       schedulingconfig.CPUBindPolicy
 	preferredCPUBindPolicy      schedulingconfig.CPUBindPolicy
 	preferredCPUExclusivePolicy schedulingconfig.CPUExclusivePolicy
+	numaAllocateStrategy        schedulingconfig.NUMAAllocateStrategy
 	numCPUsNeeded               int
-	allocation                  *PodAllocation
+	cpuOverheadInflated         bool
+	podNUMATopologyPolicy       extension.NUMATopologyPolicy
+	intraNodeSpread             *intraNodeSpreadState
+	bindToDeviceNUMA            bool
+	useReservedCPUs             bool
+	interleaveMemory            bool
+	bestEffortCPUBind           bool
+	allocation                  *resourcemanager.PodAllocation
+	// containerCPUBinds are the containers bound individually, the others run in the CPU Shared Pool.
+	containerCPUBinds []resourcemanager.ContainerCPUBind
+	// preemptibleAllocations records the allocations of the victims removed in the preemption simulation, keyed by node.
+	preemptibleAllocations map[string]map[types.UID]resourcemanager.PodAllocation
 }
 
 func (s *preFilterState) Clone() framework.StateData {
 	ns := &preFilterState{
 		skip:                        s.skip,
 		requestCPUBind:              s.requestCPUBind,
+		requestSoftCPUBind:          s.requestSoftCPUBind,
 		requests:                    s.requests,
 		requiredCPUBindPolicy:       s.requiredCPUBindPolicy,
 		preferredCPUBindPolicy:      s.preferredCPUBindPolicy,
 		preferredCPUExclusivePolicy: s.preferredCPUExclusivePolicy,
+		numaAllocateStrategy:        s.numaAllocateStrategy,
 		numCPUsNeeded:               s.numCPUsNeeded,
+		cpuOverheadInflated:         s.cpuOverheadInflated,
+		podNUMATopologyPolicy:       s.podNUMATopologyPolicy,
+		intraNodeSpread:             s.intraNodeSpread,
+		bindToDeviceNUMA:            s.bindToDeviceNUMA,
+		useReservedCPUs:             s.useReservedCPUs,
+		interleaveMemory:            s.interleaveMemory,
+		bestEffortCPUBind:           s.bestEffortCPUBind,
+		containerCPUBinds:           s.containerCPUBinds,
 		allocation:                  s.allocation,
 	}
+	if len(s.preemptibleAllocations) > 0 {
+		ns.preemptibleAllocations = make(map[string]map[types.UID]resourcemanager.PodAllocation, len(s.preemptibleAllocations))
+		for nodeName, allocations := range s.preemptibleAllocations {
+			copied := make(map[types.UID]resourcemanager.PodAllocation, len(allocations))
+			for uid, allocation := range allocations {
+				copied[uid] = allocation
+			}
+			ns.preemptibleAllocations[nodeName] = copied
+		}
+	}
 	return ns
 }
 
@@ -208,11 +292,17 @@ func (p *Plugin) EventsToRegister() []framework.ClusterEvent {
 }
 
 func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
-	resourceSpec, err := extension.GetResourceSpec(pod.Annotations)
+	resourceSpec, err := annotation.LenientParser.ParseResourceSpec(pod.Annotations)
+	if err != nil {
+		return nil, framework.NewStatus(framework.Error, err.Error())
+	}
+	numaTopologySpec, err := annotation.LenientParser.ParseNUMATopologySpec(pod.Annotations)
 	if err != nil {
 		return nil, framework.NewStatus(framework.Error, err.Error())
 	}
 
+	// NOTE: The pod-level resources (PodSpec.Resources of the Kubernetes PodLevelResources feature) are not honored,
+	// since they require k8s.io/api v0.32+ while the replaced v0.24 only has the container-level resources.
 	requests, _ := resourceapi.PodRequestsAndLimits(pod)
 	if quotav1.IsZero(requests) {
 		cycleState.Write(stateKey, &preFilterState{
@@ -221,10 +311,17 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 		return nil, nil
 	}
 	state := &preFilterState{
-		requestCPUBind: false,
-		requests:       requests,
+		requestCPUBind:        false,
+		requests:              requests,
+		podNUMATopologyPolicy: numaTopologySpec.NUMATopologyPolicy,
+		interleaveMemory:      resourceSpec.MemoryPolicy == extension.MemoryPolicyInterleave,
 	}
-	if AllowUseCPUSet(pod) {
+	if AllowUseCPUSet(pod) && len(resourceSpec.Containers) > 0 {
+		// the containers of the Pod require different CPU bind policies
+		if err = p.preFilterContainerCPUBinds(state, pod, resourceSpec); err != nil {
+			return nil, framework.NewStatus(framework.Error, err.Error())
+		}
+	} else if AllowUseCPUSet(pod) {
 		cpuBindPolicy := schedulingconfig.CPUBindPolicy(resourceSpec.PreferredCPUBindPolicy)
 		if cpuBindPolicy == "" || cpuBindPolicy == schedulingconfig.CPUBindPolicyDefault {
 			cpuBindPolicy = p.pluginArgs.DefaultCPUBindPolicy
@@ -239,7 +336,9 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 
 		if cpuBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs ||
 			cpuBindPolicy == schedulingconfig.CPUBindPolicySpreadByPCPUs {
-			requestedCPU := requests.Cpu().MilliValue()
+			// the containers request the whole CPUs, while the Pod overhead of the RuntimeClass inflates the cpuset
+			containerRequests, _ := resourceapi.PodRequestsAndLimitsWithoutOverhead(pod)
+			requestedCPU := containerRequests.Cpu().MilliValue()
 			if requestedCPU%1000 != 0 {
 				return nil, framework.NewStatus(framework.Error, "the requested CPUs must be integer")
 			}
@@ -249,8 +348,42 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 				state.requiredCPUBindPolicy = requiredCPUBindPolicy
 				state.preferredCPUBindPolicy = cpuBindPolicy
 				state.preferredCPUExclusivePolicy = resourceSpec.PreferredCPUExclusivePolicy
+				state.numaAllocateStrategy = resourceSpec.PreferredNUMAAllocateStrategy
 				state.numCPUsNeeded = int(requestedCPU / 1000)
+				if overhead := p.getCPUBindOverhead(pod); overhead > 0 {
+					state.numCPUsNeeded = int((requestedCPU + overhead + 999) / 1000)
+					state.cpuOverheadInflated = true
+				}
+				state.intraNodeSpread, err = newIntraNodeSpreadState(pod)
+				if err != nil {
+					return nil, framework.NewStatus(framework.Error, err.Error())
+				}
+				state.bindToDeviceNUMA = extension.IsPodBindToDeviceNUMA(pod.Annotations)
+				// the required CPU bind policy can't be degraded
+				state.bestEffortCPUBind = resourceSpec.CPUBindMode == extension.CPUBindModeBestEffort && requiredCPUBindPolicy == ""
+			}
+		}
+	} else if resourceSpec.CPUBindMode == extension.CPUBindModeSoft && extension.GetPodQoSClassRaw(pod) == extension.QoSLS {
+		// the LS Pod prefers the CPUs covering its requests, which are not required to be integer
+		requestedCPU := requests.Cpu().MilliValue()
+		if requestedCPU > 0 {
+			cpuBindPolicy := schedulingconfig.CPUBindPolicy(resourceSpec.PreferredCPUBindPolicy)
+			if cpuBindPolicy == "" || cpuBindPolicy == schedulingconfig.CPUBindPolicyDefault {
+				cpuBindPolicy = p.pluginArgs.DefaultCPUBindPolicy
 			}
+			state.requestSoftCPUBind = true
+			state.preferredCPUBindPolicy = cpuBindPolicy
+			state.numaAllocateStrategy = resourceSpec.PreferredNUMAAllocateStrategy
+			state.numCPUsNeeded = int((requestedCPU + 999) / 1000)
+		}
+	} else if p.allowUseReservedCPUs(pod) {
+		// the node-critical agents are pinned on the reserved CPUs intentionally
+		requestedCPU := requests.Cpu().MilliValue()
+		if requestedCPU > 0 && requestedCPU%1000 == 0 {
+			state.requestCPUBind = true
+			state.useReservedCPUs = true
+			state.preferredCPUBindPolicy = p.pluginArgs.DefaultCPUBindPolicy
+			state.numCPUsNeeded = int(requestedCPU / 1000)
 		}
 	}
 
@@ -259,8 +392,50 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	return nil, nil
 }
 
+// getCPUBindOverhead returns the milli CPUs added to the requests of the Pod when sizing its exclusive cpuset.
+// It includes the Pod overhead of the RuntimeClass, e.g. the VMM and the agent of the kata sandbox, which runs
+// in the cgroup of the Pod and so on its bound CPUs.
+func (p *Plugin) getCPUBindOverhead(pod *corev1.Pod) int64 {
+	var overhead int64
+	if quantity, ok := pod.Spec.Overhead[corev1.ResourceCPU]; ok {
+		overhead += quantity.MilliValue()
+	}
+	if p.pluginArgs.CPUBindOverheadPerContainer != nil {
+		overhead += p.pluginArgs.CPUBindOverheadPerContainer.MilliValue() * int64(len(pod.Spec.Containers))
+	}
+	return overhead
+}
+
+// requestsOnNode returns the requests of the Pod accounted by the NUMA Nodes. The CPUs inflated by the overhead
+// are bound exclusively, so the NUMA Nodes account the whole bound CPUs rather than the requested CPUs.
+func (s *preFilterState) requestsOnNode(numCPUsNeeded int) corev1.ResourceList {
+	if !s.requestCPUBind || !s.cpuOverheadInflated {
+		return s.requests
+	}
+	boundCPUs := int64(numCPUsNeeded * 1000)
+	if boundCPUs <= s.requests.Cpu().MilliValue() {
+		return s.requests
+	}
+	requests := s.requests.DeepCopy()
+	requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(boundCPUs, resource.DecimalSI)
+	return requests
+}
+
+// numCPUsNeededOnNode returns the number of CPUs bound to the Pod on the node. The CPUs inflated by the
+// bind overhead are rounded up to the whole physical cores for FullPCPUs, so the overhead never breaks the SMT alignment.
+func (s *preFilterState) numCPUsNeededOnNode(cpuTopology *resourcemanager.CPUTopology, fullPCPUs bool) int {
+	if !s.cpuOverheadInflated || !fullPCPUs || cpuTopology == nil {
+		return s.numCPUsNeeded
+	}
+	cpusPerCore := cpuTopology.CPUsPerCore()
+	if cpusPerCore <= 1 {
+		return s.numCPUsNeeded
+	}
+	return (s.numCPUsNeeded + cpusPerCore - 1) / cpusPerCore * cpusPerCore
+}
+
 func (p *Plugin) PreFilterExtensions() framework.PreFilterExtensions {
-	return nil
+	return p
 }
 
 func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
@@ -269,61 +444,152 @@ func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, p
 		return status
 	}
 
-	if status := p.filterAmplifiedCPUs(state, nodeInfo); !status.IsSuccess() {
-		return status
+	node := nodeInfo.Node()
+	if extension.IsNodeVirtualTopology(node.Labels) {
+		return filterVirtualTopologyNode(state)
+	}
+
+	if k8sfeature.DefaultFeatureGate.Enabled(features.AmplifiedCPUsFilter) {
+		if status := p.filterAmplifiedCPUs(cycleState, state, nodeInfo); !status.IsSuccess() {
+			return status
+		}
 	}
 
-	node := nodeInfo.Node()
 	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(node.Name)
-	numaTopologyPolicy := getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy)
+	if reason := resourcemanager.GetBrokenTopologyReason(node, &topologyOptions); reason != "" {
+		resourcemanager.RecordBrokenNodeTopology(node.Name, reason)
+		// the Pods requiring the CPU bind policy can not run without CPU binding
+		if state.requiredCPUBindPolicy != "" {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrBrokenNodeTopology)
+		}
+		return nil
+	}
+	if state.requiredCPUBindPolicy != "" {
+		// quarantine the node from the Pods requiring the CPU bind policy until the NodeResourceTopology is resolved
+		if inconsistentResources := resourcemanager.GetInconsistentTopologyResources(node, &topologyOptions); len(inconsistentResources) > 0 {
+			resourcemanager.RecordInconsistentNodeTopology(node.Name, inconsistentResources)
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInconsistentNodeTopology)
+		}
+	}
+	numaTopologyPolicy, err := mergeNUMATopologyPolicy(getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy), state.podNUMATopologyPolicy)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNUMATopologyPolicyMismatch)
+	}
 
 	if skipTheNode(state, numaTopologyPolicy) {
 		return nil
 	}
 
+	if extension.IsNodeCoordinatedDraining(node) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNodeCoordinatedDraining)
+	}
+
 	if state.requestCPUBind {
-		if topologyOptions.CPUTopology == nil {
-			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundCPUTopology)
+		if state.useReservedCPUs {
+			// the reserved CPUs are out of the NUMA resources of the workloads
+			return p.filterReservedCPUs(cycleState, state, node, pod, topologyOptions)
+		}
+		if status := p.filterCPUBind(cycleState, state, node, pod, topologyOptions, numaTopologyPolicy); !status.IsSuccess() {
+			if !state.bestEffortCPUBind {
+				return status
+			}
+			// the best-effort bound Pod is scheduled anyway and runs in the CPU Shared Pool
+			klog.V(5).Infof("pod %s/%s is scheduled on node %s without CPU binding, reason: %s",
+				pod.Namespace, pod.Name, node.Name, status.Message())
 		}
+	}
+
+	if numaTopologyPolicy != extension.NUMATopologyPolicyNone {
+		return p.FilterByNUMANode(ctx, cycleState, pod, node.Name, numaTopologyPolicy, topologyOptions)
+	}
 
-		// It's necessary to force node to have NodeResourceTopology and CPUTopology
-		// We must satisfy the user's CPUSet request. Even if some nodes in the cluster have resources,
-		// they cannot be allocated without valid CPU topology.
-		if !topologyOptions.CPUTopology.IsValid() {
-			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidCPUTopology)
+	return nil
+}
+
+// filterCPUBind checks whether the node can bind the CPUs requested by the Pod.
+func (p *Plugin) filterCPUBind(cycleState *framework.CycleState, state *preFilterState, node *corev1.Node, pod *corev1.Pod,
+	topologyOptions resourcemanager.TopologyOptions, numaTopologyPolicy extension.NUMATopologyPolicy) *framework.Status {
+	if topologyOptions.CPUTopology == nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundCPUTopology)
+	}
+
+	// It's necessary to force node to have NodeResourceTopology and CPUTopology
+	// We must satisfy the user's CPUSet request. Even if some nodes in the cluster have resources,
+	// they cannot be allocated without valid CPU topology.
+	if !topologyOptions.CPUTopology.IsValid() {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidCPUTopology)
+	}
+	nodeRequiredFullPCPUsOnly := extension.GetNodeCPUBindPolicy(node.Labels, topologyOptions.Policy) == extension.NodeCPUBindPolicyFullPCPUsOnly
+	if nodeRequiredFullPCPUsOnly || state.requiredCPUBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs {
+		// tell which policies the node supports, so the users can fix the policy of the rejected pod
+		numCPUsNeeded := state.numCPUsNeededOnNode(topologyOptions.CPUTopology, true)
+		feasiblePolicies := getFeasibleCPUBindPolicies(nodeRequiredFullPCPUsOnly, topologyOptions.CPUTopology, numCPUsNeeded)
+		if numCPUsNeeded%topologyOptions.CPUTopology.CPUsPerCore() != 0 {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError,
+				feasibleCPUBindPoliciesReason(feasiblePolicies))
 		}
-		nodeRequiredFullPCPUsOnly := extension.GetNodeCPUBindPolicy(node.Labels, topologyOptions.Policy) == extension.NodeCPUBindPolicyFullPCPUsOnly
-		if nodeRequiredFullPCPUsOnly || state.requiredCPUBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs {
-			if state.numCPUsNeeded%topologyOptions.CPUTopology.CPUsPerCore() != 0 {
+		for _, container := range state.containerCPUBinds {
+			if container.NumCPUs%topologyOptions.CPUTopology.CPUsPerCore() != 0 {
 				return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError)
 			}
-
-			if nodeRequiredFullPCPUsOnly &&
-				(state.requiredCPUBindPolicy != schedulingconfig.CPUBindPolicyFullPCPUs || state.preferredCPUBindPolicy != schedulingconfig.CPUBindPolicyFullPCPUs) {
-				return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy)
-			}
 		}
 
-		if state.requiredCPUBindPolicy != "" && numaTopologyPolicy == extension.NUMATopologyPolicyNone {
-			resourceOptions, err := p.getResourceOptions(cycleState, state, node, pod, topologymanager.NUMATopologyHint{}, topologyOptions)
-			if err != nil {
-				return framework.AsStatus(err)
-			}
-			_, err = p.resourceManager.Allocate(node, pod, resourceOptions)
-			if err != nil {
-				return framework.NewStatus(framework.Unschedulable, err.Error())
-			}
+		if nodeRequiredFullPCPUsOnly && k8sfeature.DefaultFeatureGate.Enabled(features.RequiredFullPCPUsPolicy) &&
+			(state.requiredCPUBindPolicy != schedulingconfig.CPUBindPolicyFullPCPUs || state.preferredCPUBindPolicy != schedulingconfig.CPUBindPolicyFullPCPUs) {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy,
+				feasibleCPUBindPoliciesReason(feasiblePolicies))
 		}
 	}
 
-	if numaTopologyPolicy != extension.NUMATopologyPolicyNone {
-		return p.FilterByNUMANode(ctx, cycleState, pod, node.Name, numaTopologyPolicy, topologyOptions)
+	if status := p.filterBoundPods(cycleState, pod, node, topologyOptions); !status.IsSuccess() {
+		return status
+	}
+	if status := p.filterIntraNodeSpread(cycleState, state, pod, node.Name, topologyOptions); !status.IsSuccess() {
+		return status
+	}
+	if state.bindToDeviceNUMA && len(resourcemanager.GetSRIOVDeviceNUMANodes(pod, topologyOptions.SRIOVDevices)) == 0 {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundDeviceNUMANodes)
 	}
 
+	if state.requiredCPUBindPolicy != "" && numaTopologyPolicy == extension.NUMATopologyPolicyNone {
+		resourceOptions, err := p.getResourceOptions(cycleState, state, node, pod, topologymanager.NUMATopologyHint{}, topologyOptions)
+		if err != nil {
+			return framework.AsStatus(err)
+		}
+		_, err = p.resourceManager.Allocate(node, pod, resourceOptions)
+		if err != nil {
+			return framework.NewStatus(framework.Unschedulable, err.Error())
+		}
+	}
 	return nil
 }
 
-func (p *Plugin) filterAmplifiedCPUs(state *preFilterState, nodeInfo *framework.NodeInfo) *framework.Status {
+func (p *Plugin) filterReservedCPUs(cycleState *framework.CycleState, state *preFilterState, node *corev1.Node, pod *corev1.Pod, topologyOptions resourcemanager.TopologyOptions) *framework.Status {
+	if topologyOptions.CPUTopology == nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundCPUTopology)
+	}
+	if !topologyOptions.CPUTopology.IsValid() {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidCPUTopology)
+	}
+	resourceOptions, err := p.getResourceOptions(cycleState, state, node, pod, topologymanager.NUMATopologyHint{}, topologyOptions)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if _, err = p.resourceManager.Allocate(node, pod, resourceOptions); err != nil {
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientReservedCPUs)
+	}
+	return nil
+}
+
+// allowUseReservedCPUs checks whether the Pod is a System QoS Pod selected to be pinned on the reserved CPUs.
+func (p *Plugin) allowUseReservedCPUs(pod *corev1.Pod) bool {
+	if p.reservedCPUsPodSelector == nil || p.reservedCPUsPodSelector.Empty() {
+		return false
+	}
+	return extension.GetPodQoSClassRaw(pod) == extension.QoSSystem && p.reservedCPUsPodSelector.Matches(labels.Set(pod.Labels))
+}
+
+func (p *Plugin) filterAmplifiedCPUs(cycleState *framework.CycleState, state *preFilterState, nodeInfo *framework.NodeInfo) *framework.Status {
 	quantity := state.requests[corev1.ResourceCPU]
 	podRequestMilliCPU := quantity.MilliValue()
 	if podRequestMilliCPU == 0 {
@@ -331,10 +597,11 @@ func (p *Plugin) filterAmplifiedCPUs(state *preFilterState, nodeInfo *framework.
 	}
 
 	node := nodeInfo.Node()
-	cpuAmplificationRatio, err := extension.GetNodeResourceAmplificationRatio(node.Annotations, corev1.ResourceCPU)
+	nodeResources, err := frameworkext.GetNodeResources(cycleState, node)
 	if err != nil {
 		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidCPUAmplificationRatio)
 	}
+	cpuAmplificationRatio := nodeResources.AmplificationRatios[corev1.ResourceCPU]
 	if cpuAmplificationRatio <= 1 {
 		return nil
 	}
@@ -373,20 +640,44 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 		return framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 	node := nodeInfo.Node()
+	if extension.IsNodeVirtualTopology(node.Labels) {
+		return nil
+	}
 	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(node.Name)
-	numaTopologyPolicy := getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy)
-
-	if skipTheNode(state, numaTopologyPolicy) {
+	if resourcemanager.GetBrokenTopologyReason(node, &topologyOptions) != "" {
+		// the Pod runs in the CPU Shared Pool as the node falls back to the scheduling without CPU binding
 		return nil
 	}
+	numaTopologyPolicy, err := mergeNUMATopologyPolicy(getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy), state.podNUMATopologyPolicy)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
 
-	if state.requestCPUBind {
-		if topologyOptions.CPUTopology == nil {
-			return framework.NewStatus(framework.Error, ErrNotFoundCPUTopology)
+	if skipTheNode(state, numaTopologyPolicy) {
+		// the soft binding is best-effort, the Pod just runs in the CPU Shared Pool without valid CPU topology
+		// or on the node draining for maintenance
+		if !state.requestSoftCPUBind || topologyOptions.CPUTopology == nil || !topologyOptions.CPUTopology.IsValid() ||
+			extension.IsNodeCoordinatedDraining(node) {
+			return nil
 		}
-		if !topologyOptions.CPUTopology.IsValid() {
+	}
+
+	if state.requestCPUBind && (topologyOptions.CPUTopology == nil || !topologyOptions.CPUTopology.IsValid()) {
+		if !state.bestEffortCPUBind {
+			if topologyOptions.CPUTopology == nil {
+				return framework.NewStatus(framework.Error, ErrNotFoundCPUTopology)
+			}
 			return framework.NewStatus(framework.Error, ErrInvalidCPUTopology)
 		}
+		// the best-effort bound Pod is allocated without CPU binding on the node without valid CPU topology
+		state.allocation = &resourcemanager.PodAllocation{
+			UID:             pod.UID,
+			Namespace:       pod.Namespace,
+			Name:            pod.Name,
+			QoSClass:        extension.GetPodQoSClassRaw(pod),
+			CPUBindDegraded: true,
+		}
+		return nil
 	}
 
 	store := topologymanager.GetStore(cycleState)
@@ -396,9 +687,14 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 		return framework.AsStatus(err)
 	}
 	result, err := p.resourceManager.Allocate(node, pod, resourceOptions)
+	// the failures are only recorded for the node selected, while the Allocate in Filter and Score tries every node
+	if resourceOptions.CPUBindFailureReason != "" {
+		resourcemanager.RecordCPUBindFailure(nodeName, resourceOptions.CPUBindFailureReason)
+	}
 	if err != nil {
 		return framework.AsStatus(err)
 	}
+	p.resourceManager.RecordAllocation(node, pod, resourceOptions, result)
 	p.resourceManager.Update(nodeName, result)
 	state.allocation = result
 	return nil
@@ -431,14 +727,17 @@ func (p *Plugin) preBindObject(ctx context.Context, cycleState *framework.CycleS
 		return nil
 	}
 
-	if state.requestCPUBind {
+	// the CPU bind policies of the containers conflict with the policies of the Pod
+	if state.requestCPUBind && len(state.containerCPUBinds) == 0 {
 		if err := appendResourceSpecIfMissed(object, state); err != nil {
 			return framework.AsStatus(err)
 		}
 	}
 
 	resourceStatus := &extension.ResourceStatus{
-		CPUSet: state.allocation.CPUSet.String(),
+		CPUSet:          state.allocation.CPUSet.String(),
+		PreferredCPUSet: state.allocation.PreferredCPUSet.String(),
+		CPUBindDegraded: state.allocation.CPUBindDegraded,
 	}
 	for _, nodeRes := range state.allocation.NUMANodeResources {
 		resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, extension.NUMANodeResource{
@@ -446,19 +745,49 @@ func (p *Plugin) preBindObject(ctx context.Context, cycleState *framework.CycleS
 			Resources: nodeRes.Resources,
 		})
 	}
-	if err := extension.SetResourceStatus(object, resourceStatus); err != nil {
+	for _, containerCPUSet := range state.allocation.ContainerCPUSets {
+		resourceStatus.ContainerCPUSets = append(resourceStatus.ContainerCPUSets, extension.ContainerCPUSet{
+			Name:   containerCPUSet.Name,
+			CPUSet: containerCPUSet.CPUSet.String(),
+		})
+	}
+	if err := extension.SetResourceStatusWithFormat(object, resourceStatus, getResourceStatusFormat()); err != nil {
 		return framework.AsStatus(err)
 	}
 	return nil
 }
 
-func (p *Plugin) getResourceOptions(cycleState *framework.CycleState, state *preFilterState, node *corev1.Node, pod *corev1.Pod, affinity topologymanager.NUMATopologyHint, topologyOptions TopologyOptions) (*ResourceOptions, error) {
+// getResourceStatusFormat returns the format of the annotation resource-status written by the scheduler,
+// which is migrated to the V2 schema by the feature gates.
+func getResourceStatusFormat() extension.ResourceStatusFormat {
+	if !k8sfeature.DefaultFeatureGate.Enabled(features.ResourceStatusV2) {
+		return extension.ResourceStatusFormatV1
+	}
+	if k8sfeature.DefaultFeatureGate.Enabled(features.ResourceStatusCompression) {
+		return extension.ResourceStatusFormatV2Compressed
+	}
+	return extension.ResourceStatusFormatV2
+}
+
+func (p *Plugin) getResourceOptions(cycleState *framework.CycleState, state *preFilterState, node *corev1.Node, pod *corev1.Pod, affinity topologymanager.NUMATopologyHint, topologyOptions resourcemanager.TopologyOptions) (*resourcemanager.ResourceOptions, error) {
 	preferredCPUBindPolicy, err := p.getPreferredCPUBindPolicy(node, state.preferredCPUBindPolicy)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := amplifyNUMANodeResources(node, &topologyOptions); err != nil {
+	nodeResources, err := frameworkext.GetNodeResources(cycleState, node)
+	if err != nil {
+		return nil, err
+	}
+	resourcemanager.AmplifyNUMANodeResourcesByRatios(nodeResources.AmplificationRatios, &topologyOptions)
+	allowIsolatedCPUs := allowUseIsolatedCPUs(pod)
+	if !allowIsolatedCPUs {
+		if err := p.excludeFreeIsolatedCPUs(node.Name, &topologyOptions); err != nil {
+			return nil, err
+		}
+	}
+	drainedNUMANodes, err := resourcemanager.ExcludeDrainedNUMANodes(node, &topologyOptions)
+	if err != nil {
 		return nil, err
 	}
 
@@ -477,26 +806,69 @@ func (p *Plugin) getResourceOptions(cycleState *framework.CycleState, state *pre
 			}
 		}
 	}
-
-	requests := state.requests
-	if state.requestCPUBind && amplificationRatio > 1 {
-		requests = requests.DeepCopy()
-		extension.AmplifyResourceList(requests, topologyOptions.AmplificationRatios, corev1.ResourceCPU)
+	preferredCPUs := reservationReservedCPUs
+	if preemptibleCPUs, preemptibleResources := state.getPreemptibleResources(node.Name, topologyOptions); len(preemptibleResources) > 0 || !preemptibleCPUs.IsEmpty() {
+		preferredCPUs = preferredCPUs.Union(preemptibleCPUs)
+		for numaNode, res := range preemptibleResources {
+			reusableResources[numaNode] = quotav1.Add(reusableResources[numaNode], res)
+		}
 	}
 
-	options := &ResourceOptions{
-		requests:              requests,
-		originalRequests:      state.requests,
-		numCPUsNeeded:         state.numCPUsNeeded,
-		requestCPUBind:        state.requestCPUBind,
-		requiredCPUBindPolicy: state.requiredCPUBindPolicy != "",
-		cpuBindPolicy:         preferredCPUBindPolicy,
-		cpuExclusivePolicy:    state.preferredCPUExclusivePolicy,
-		preferredCPUs:         reservationReservedCPUs,
-		reusableResources:     reusableResources,
-		hint:                  affinity,
-		topologyOptions:       topologyOptions,
+	reservedFullCores := int(p.pluginArgs.ReservedFullCores)
+	if numCores, ok, err := extension.GetNodeReservedFullCores(node.Annotations); err != nil {
+		return nil, err
+	} else if ok {
+		reservedFullCores = numCores
 	}
+
+	var boundPodsSaturatedNUMANodes []int
+	if state.requestCPUBind && !state.useReservedCPUs && reservationReservedCPUs.IsEmpty() {
+		limit, err := p.getBoundPodsLimit(node)
+		if err != nil {
+			return nil, err
+		}
+		boundPodsSaturatedNUMANodes, _ = p.getSaturatedNUMANodes(node.Name, limit, topologyOptions.CPUTopology)
+	}
+
+	hintAllocateOrder := p.pluginArgs.NUMAHintAllocateOrder
+	if order := extension.GetNodeNUMAHintAllocateOrder(node.Labels); order != "" {
+		hintAllocateOrder = order
+	}
+
+	numCPUsNeeded := state.numCPUsNeededOnNode(topologyOptions.CPUTopology, preferredCPUBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs)
+	requests := state.requestsOnNode(numCPUsNeeded)
+	options := &resourcemanager.ResourceOptions{
+		Requests:              requests,
+		OriginalRequests:      requests,
+		NumCPUsNeeded:         numCPUsNeeded,
+		RequestCPUBind:        state.requestCPUBind,
+		RequestSoftCPUBind:    state.requestSoftCPUBind,
+		RequiredCPUBindPolicy: state.requiredCPUBindPolicy != "",
+		CPUBindPolicy:         preferredCPUBindPolicy,
+		CPUExclusivePolicy:    state.preferredCPUExclusivePolicy,
+		NUMAAllocateStrategy:  state.numaAllocateStrategy,
+		HintAllocateOrder:     hintAllocateOrder,
+		PreferredCPUs:         preferredCPUs,
+		ReusableResources:     reusableResources,
+		Hint:                  affinity,
+		TopologyOptions:       topologyOptions,
+		ReservedFullCores:     reservedFullCores,
+		AllowIsolatedCPUs:     allowIsolatedCPUs,
+	}
+	if state.intraNodeSpread != nil {
+		options.SpreadOccupiedCPUs = p.getSpreadOccupiedCPUs(state.intraNodeSpread, pod, node.Name, topologyOptions.CPUTopology)
+		options.RequiredIntraNodeSpread = state.intraNodeSpread.required
+	}
+	if state.bindToDeviceNUMA {
+		options.DeviceNUMANodes = resourcemanager.GetSRIOVDeviceNUMANodes(pod, topologyOptions.SRIOVDevices)
+	}
+	options.UseReservedCPUs = state.useReservedCPUs
+	options.BestEffortCPUBind = state.bestEffortCPUBind
+	options.ContainerCPUBinds = state.containerCPUBinds
+	options.BoundPodsSaturatedNUMANodes = boundPodsSaturatedNUMANodes
+	options.DrainedNUMANodes = drainedNUMANodes
+	options.BalanceSockets = p.pluginArgs.SocketBalanceWeight > 0
+	options.AmplifyCPUBindRequests()
 	return options, nil
 }
 
@@ -49,7 +49,10 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/v1beta2"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	"github.com/koordinator-sh/koordinator/pkg/util/topologyhint"
 )
 
 var _ framework.SharedLister = &testSharedLister{}
@@ -148,7 +151,36 @@ type pluginTestSuit struct {
 	nodeNUMAResourceArgs *schedulingconfig.NodeNUMAResourceArgs
 }
 
-func newPluginTestSuit(t *testing.T, pods []*corev1.Pod, nodes []*corev1.Node) *pluginTestSuit {
+func buildCPUTopologyForTest(numSockets, nodesPerSocket, coresPerNode, cpusPerCore int) *resourcemanager.CPUTopology {
+	topo := &resourcemanager.CPUTopology{
+		NumSockets: numSockets,
+		NumNodes:   nodesPerSocket * numSockets,
+		NumCores:   coresPerNode * nodesPerSocket * numSockets,
+		NumCPUs:    cpusPerCore * coresPerNode * nodesPerSocket * numSockets,
+		CPUDetails: make(map[int]resourcemanager.CPUInfo),
+	}
+	var nodeID, coreID, cpuID int
+	for s := 0; s < numSockets; s++ {
+		for n := 0; n < nodesPerSocket; n++ {
+			for c := 0; c < coresPerNode; c++ {
+				for p := 0; p < cpusPerCore; p++ {
+					topo.CPUDetails[cpuID] = resourcemanager.CPUInfo{
+						SocketID: s,
+						NodeID:   nodeID,
+						CoreID:   coreID,
+						CPUID:    cpuID,
+					}
+					cpuID++
+				}
+				coreID++
+			}
+			nodeID++
+		}
+	}
+	return topo
+}
+
+func newPluginTestSuit(t testing.TB, pods []*corev1.Pod, nodes []*corev1.Node) *pluginTestSuit {
 	var v1beta2args v1beta2.NodeNUMAResourceArgs
 	v1beta2.SetDefaults_NodeNUMAResourceArgs(&v1beta2args)
 	var nodeNUMAResourceArgs schedulingconfig.NodeNUMAResourceArgs
@@ -224,9 +256,93 @@ func TestPlugin_PreFilter(t *testing.T) {
 		name              string
 		pod               *corev1.Pod
 		defaultBindPolicy schedulingconfig.CPUBindPolicy
+		cpuBindOverhead   *resource.Quantity
 		want              *framework.Status
 		wantState         *preFilterState
 	}{
+		{
+			name: "cpu set with LSR Prod Pod and the bind overhead per container",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLSR),
+					},
+					Annotations: map[string]string{
+						extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy": "FullPCPUs"}`,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Name: "container-1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2"),
+								},
+							},
+						},
+						{
+							Name: "container-2",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2"),
+								},
+							},
+						},
+					},
+				},
+			},
+			cpuBindOverhead: resource.NewMilliQuantity(100, resource.DecimalSI),
+			wantState: &preFilterState{
+				requestCPUBind: true,
+				requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          5,
+				cpuOverheadInflated:    true,
+			},
+		},
+		{
+			name: "cpu set with LSR Prod Pod and the overhead of the kata RuntimeClass",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLSR),
+					},
+					Annotations: map[string]string{
+						extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy": "FullPCPUs"}`,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Priority:         pointer.Int32(extension.PriorityProdValueMax),
+					RuntimeClassName: pointer.String("kata"),
+					Overhead: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("250m"),
+					},
+					Containers: []corev1.Container{
+						{
+							Name: "container-1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("4"),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantState: &preFilterState{
+				requestCPUBind: true,
+				requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4250m"),
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          5,
+				cpuOverheadInflated:    true,
+			},
+		},
 		{
 			name: "cpu set with LSR Prod Pod",
 			pod: &corev1.Pod{
@@ -514,6 +630,70 @@ func TestPlugin_PreFilter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "soft cpu bind with LS Pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Annotations: map[string]string{
+						extension.AnnotationResourceSpec: `{"cpuBindMode": "Soft"}`,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container-1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2.5"),
+								},
+							},
+						},
+					},
+				},
+			},
+			defaultBindPolicy: schedulingconfig.CPUBindPolicySpreadByPCPUs,
+			wantState: &preFilterState{
+				requestSoftCPUBind: true,
+				requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2.5"),
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				numCPUsNeeded:          3,
+			},
+		},
+		{
+			name: "soft cpu bind with BE Pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSBE),
+					},
+					Annotations: map[string]string{
+						extension.AnnotationResourceSpec: `{"cpuBindMode": "Soft"}`,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container-1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2"),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantState: &preFilterState{
+				requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -521,6 +701,7 @@ func TestPlugin_PreFilter(t *testing.T) {
 			if tt.defaultBindPolicy != "" {
 				suit.nodeNUMAResourceArgs.DefaultCPUBindPolicy = tt.defaultBindPolicy
 			}
+			suit.nodeNUMAResourceArgs.CPUBindOverheadPerContainer = tt.cpuBindOverhead
 			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
 			assert.NotNil(t, p)
 			assert.Nil(t, err)
@@ -541,15 +722,101 @@ func TestPlugin_PreFilter(t *testing.T) {
 	}
 }
 
+func TestPreFilterState_numCPUsNeededOnNode(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	tests := []struct {
+		name      string
+		state     *preFilterState
+		fullPCPUs bool
+		want      int
+	}{
+		{
+			name:      "not inflated by the overhead",
+			state:     &preFilterState{numCPUsNeeded: 3},
+			fullPCPUs: true,
+			want:      3,
+		},
+		{
+			name:      "inflated by the overhead with SpreadByPCPUs",
+			state:     &preFilterState{numCPUsNeeded: 5, cpuOverheadInflated: true},
+			fullPCPUs: false,
+			want:      5,
+		},
+		{
+			name:      "inflated by the overhead with FullPCPUs",
+			state:     &preFilterState{numCPUsNeeded: 5, cpuOverheadInflated: true},
+			fullPCPUs: true,
+			want:      6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.state.numCPUsNeededOnNode(cpuTopology, tt.fullPCPUs))
+		})
+	}
+}
+
+func TestPreFilterState_requestsOnNode(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         *preFilterState
+		numCPUsNeeded int
+		want          corev1.ResourceList
+	}{
+		{
+			name: "not inflated by the overhead",
+			state: &preFilterState{
+				requestCPUBind: true,
+				requests:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			},
+			numCPUsNeeded: 4,
+			want:          corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+		{
+			name: "account the whole CPUs inflated by the Pod overhead",
+			state: &preFilterState{
+				requestCPUBind:      true,
+				cpuOverheadInflated: true,
+				requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4250m"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+			numCPUsNeeded: 6,
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(6000, resource.DecimalSI),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+		{
+			name: "keep the requests of the Pod not binding CPUs",
+			state: &preFilterState{
+				requestSoftCPUBind: true,
+				requests:           corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2500m")},
+			},
+			numCPUsNeeded: 3,
+			want:          corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2500m")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := tt.state.requests.DeepCopy()
+			assert.Equal(t, tt.want, tt.state.requestsOnNode(tt.numCPUsNeeded))
+			assert.Equal(t, requests, tt.state.requests, "the requests of the state must not be modified")
+		})
+	}
+}
+
 func TestPlugin_Filter(t *testing.T) {
 	tests := []struct {
 		name            string
 		nodeLabels      map[string]string
 		nodeAnnotations map[string]string
+		unschedulable   bool
 		kubeletPolicy   *extension.KubeletCPUManagerPolicy
-		cpuTopology     *CPUTopology
+		cpuTopology     *resourcemanager.CPUTopology
 		state           *preFilterState
-		allocationState *NodeAllocation
+		allocationState *resourcemanager.NodeAllocation
 		want            *framework.Status
 	}{
 		{
@@ -568,17 +835,48 @@ func TestPlugin_Filter(t *testing.T) {
 			state: &preFilterState{
 				requestCPUBind: true,
 			},
-			cpuTopology:     &CPUTopology{},
-			allocationState: NewNodeAllocation("test-node-1"),
+			cpuTopology:     &resourcemanager.CPUTopology{},
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidCPUTopology),
 		},
+		{
+			name: "succeed to schedule the best-effort bound pod without CPU topology",
+			state: &preFilterState{
+				requestCPUBind:    true,
+				bestEffortCPUBind: true,
+			},
+			want: nil,
+		},
+		{
+			name: "succeed to schedule the best-effort bound pod with invalid cpu topology",
+			state: &preFilterState{
+				requestCPUBind:    true,
+				bestEffortCPUBind: true,
+			},
+			cpuTopology:     &resourcemanager.CPUTopology{},
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            nil,
+		},
+		{
+			name: "failed to bind CPUs on the node draining for maintenance",
+			nodeLabels: map[string]string{
+				extension.LabelNodeCoordinatedDrain: "true",
+			},
+			unschedulable: true,
+			state: &preFilterState{
+				requestCPUBind: true,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNodeCoordinatedDraining),
+		},
 		{
 			name: "succeed with valid cpu topology",
 			state: &preFilterState{
 				requestCPUBind: true,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 			want:            nil,
 		},
 		{
@@ -588,6 +886,51 @@ func TestPlugin_Filter(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "succeed with preferred cpu bind policy on virtual topology node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeVirtualTopology: "true",
+			},
+			state: &preFilterState{
+				requestCPUBind:         true,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          4,
+			},
+			want: nil,
+		},
+		{
+			name: "failed with required cpu bind policy on virtual topology node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeVirtualTopology: "true",
+			},
+			state: &preFilterState{
+				requestCPUBind:         true,
+				requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          4,
+			},
+			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrVirtualTopologyRequiredCPUBind),
+		},
+		{
+			name: "failed with SingleNUMANode policy on virtual topology node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeVirtualTopology: "true",
+			},
+			state: &preFilterState{
+				podNUMATopologyPolicy: extension.NUMATopologyPolicySingleNUMANode,
+			},
+			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrVirtualTopologyNUMATopologyPolicy),
+		},
+		{
+			name: "succeed with BestEffort policy on virtual topology node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeVirtualTopology: "true",
+			},
+			state: &preFilterState{
+				podNUMATopologyPolicy: extension.NUMATopologyPolicyBestEffort,
+			},
+			want: nil,
+		},
 		{
 			name: "verify FullPCPUsOnly with SMTAlignmentError",
 			nodeLabels: map[string]string{
@@ -599,8 +942,8 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          5,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
-			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError, ErrNoFeasibleCPUBindPolicy),
 		},
 		{
 			name: "verify required FullPCPUs SMTAlignmentError",
@@ -611,8 +954,8 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          5,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
-			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError, "node(s) support CPU bind policies [SpreadByPCPUs]"),
 		},
 		{
 			name: "verify FullPCPUsOnly with preferred SpreadByPCPUs",
@@ -625,8 +968,8 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
-			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy, "node(s) support CPU bind policies [FullPCPUs]"),
 		},
 		{
 			name: "verify FullPCPUsOnly with required SpreadByPCPUs",
@@ -640,8 +983,8 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
-			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy, "node(s) support CPU bind policies [FullPCPUs]"),
 		},
 		{
 			name: "verify Kubelet FullPCPUsOnly with SMTAlignmentError",
@@ -651,14 +994,14 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          5,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 			kubeletPolicy: &extension.KubeletCPUManagerPolicy{
 				Policy: extension.KubeletCPUManagerPolicyStatic,
 				Options: map[string]string{
 					extension.KubeletCPUManagerPolicyFullPCPUsOnlyOption: "true",
 				},
 			},
-			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError),
+			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrSMTAlignmentError, ErrNoFeasibleCPUBindPolicy),
 		},
 		{
 			name: "verify Kubelet FullPCPUsOnly with RequiredFullPCPUsPolicy",
@@ -668,14 +1011,14 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 			kubeletPolicy: &extension.KubeletCPUManagerPolicy{
 				Policy: extension.KubeletCPUManagerPolicyStatic,
 				Options: map[string]string{
 					extension.KubeletCPUManagerPolicyFullPCPUsOnlyOption: "true",
 				},
 			},
-			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy),
+			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy, "node(s) support CPU bind policies [FullPCPUs]"),
 		},
 		{
 			name: "verify required FullPCPUs with none NUMA topology policy",
@@ -686,7 +1029,7 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 		},
 		{
 			name: "verify FullPCPUs with NUMA Topology Policy",
@@ -700,7 +1043,7 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 		},
 		{
 			name: "verify FullPCPUs with NUMA Topology Policy and amplification ratio",
@@ -717,7 +1060,35 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+		},
+		{
+			name: "verify FullPCPUs with Pod NUMA Topology Policy",
+			state: &preFilterState{
+				requestCPUBind:         true,
+				requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          4,
+				podNUMATopologyPolicy:  extension.NUMATopologyPolicySingleNUMANode,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+		},
+		{
+			name: "verify Pod NUMA Topology Policy not match node",
+			nodeLabels: map[string]string{
+				extension.LabelNUMATopologyPolicy: string(extension.NUMATopologyPolicyRestricted),
+			},
+			state: &preFilterState{
+				requestCPUBind:         true,
+				requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          4,
+				podNUMATopologyPolicy:  extension.NUMATopologyPolicySingleNUMANode,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNUMATopologyPolicyMismatch),
 		},
 		{
 			name: "verify FullPCPUs with None NUMA Topology Policy and amplification ratio",
@@ -734,7 +1105,7 @@ func TestPlugin_Filter(t *testing.T) {
 				numCPUsNeeded:          4,
 			},
 			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
-			allocationState: NewNodeAllocation("test-node-1"),
+			allocationState: resourcemanager.NewNodeAllocation("test-node-1"),
 		},
 	}
 	for _, tt := range tests {
@@ -760,6 +1131,7 @@ func TestPlugin_Filter(t *testing.T) {
 			for k, v := range tt.nodeAnnotations {
 				nodes[0].Annotations[k] = v
 			}
+			nodes[0].Spec.Unschedulable = tt.unschedulable
 
 			suit := newPluginTestSuit(t, nil, nodes)
 			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
@@ -768,24 +1140,21 @@ func TestPlugin_Filter(t *testing.T) {
 
 			plg := p.(*Plugin)
 			if tt.allocationState != nil {
-				topologyOptions := TopologyOptions{
+				topologyOptions := resourcemanager.TopologyOptions{
 					CPUTopology: tt.cpuTopology,
 					Policy:      tt.kubeletPolicy,
 				}
 				for i := 0; i < topologyOptions.CPUTopology.NumNodes; i++ {
-					topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, NUMANodeResource{
+					topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, resourcemanager.NUMANodeResource{
 						Node: i,
 						Resources: corev1.ResourceList{
 							corev1.ResourceCPU:    *resource.NewQuantity(int64(topologyOptions.CPUTopology.CPUsPerNode()), resource.DecimalSI),
 							corev1.ResourceMemory: *resource.NewQuantity(32*1024*1024*1024, resource.BinarySI),
 						}})
 				}
-				plg.topologyOptionsManager.UpdateTopologyOptions(tt.allocationState.nodeName, func(options *TopologyOptions) {
+				plg.topologyOptionsManager.UpdateTopologyOptions(tt.allocationState.NodeName(), func(options *resourcemanager.TopologyOptions) {
 					*options = topologyOptions
 				})
-
-				manager := plg.resourceManager.(*resourceManager)
-				manager.nodeAllocations[tt.allocationState.nodeName] = tt.allocationState
 			}
 
 			suit.start()
@@ -816,7 +1185,7 @@ func TestFilterWithAmplifiedCPUs(t *testing.T) {
 		name                      string
 		pod                       *corev1.Pod
 		existingPods              []*corev1.Pod
-		cpuTopology               *CPUTopology
+		cpuTopology               *resourcemanager.CPUTopology
 		nodeHasNRT                bool
 		nodeCPUAmplificationRatio extension.Ratio
 		wantStatus                *framework.Status
@@ -892,18 +1261,18 @@ func TestFilterWithAmplifiedCPUs(t *testing.T) {
 			pl := p.(*Plugin)
 
 			if tt.nodeHasNRT {
-				topologyOptions := TopologyOptions{
+				topologyOptions := resourcemanager.TopologyOptions{
 					CPUTopology: tt.cpuTopology,
 				}
 				for i := 0; i < tt.cpuTopology.NumNodes; i++ {
-					topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, NUMANodeResource{
+					topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, resourcemanager.NUMANodeResource{
 						Node: i,
 						Resources: corev1.ResourceList{
 							corev1.ResourceCPU:    resource.MustParse(fmt.Sprintf("%d", extension.Amplify(int64(tt.cpuTopology.CPUsPerNode()), tt.nodeCPUAmplificationRatio))),
 							corev1.ResourceMemory: resource.MustParse("20Gi"),
 						}})
 				}
-				pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+				pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
 					*options = topologyOptions
 				})
 			}
@@ -931,10 +1300,11 @@ func TestPlugin_Reserve(t *testing.T) {
 		state         *preFilterState
 		reservedCPUs  map[types.UID]cpuset.CPUSet
 		pod           *corev1.Pod
-		cpuTopology   *CPUTopology
+		cpuTopology   *resourcemanager.CPUTopology
 		allocatedCPUs []int
 		want          *framework.Status
 		wantCPUSet    cpuset.CPUSet
+		wantDegraded  bool
 		wantState     *preFilterState
 	}{
 		{
@@ -955,10 +1325,35 @@ func TestPlugin_Reserve(t *testing.T) {
 			state: &preFilterState{
 				requestCPUBind: true,
 			},
-			cpuTopology: &CPUTopology{},
+			cpuTopology: &resourcemanager.CPUTopology{},
 			pod:         &corev1.Pod{},
 			want:        framework.NewStatus(framework.Error, ErrInvalidCPUTopology),
 		},
+		{
+			name: "succeed to reserve the best-effort bound pod without CPU topology",
+			state: &preFilterState{
+				requestCPUBind:         true,
+				bestEffortCPUBind:      true,
+				numCPUsNeeded:          4,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			},
+			pod:          &corev1.Pod{},
+			want:         nil,
+			wantDegraded: true,
+		},
+		{
+			name: "succeed to reserve the best-effort bound pod with invalid cpu topology",
+			state: &preFilterState{
+				requestCPUBind:         true,
+				bestEffortCPUBind:      true,
+				numCPUsNeeded:          4,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			},
+			cpuTopology:  &resourcemanager.CPUTopology{},
+			pod:          &corev1.Pod{},
+			want:         nil,
+			wantDegraded: true,
+		},
 		{
 			name: "succeed with skip",
 			state: &preFilterState{
@@ -979,6 +1374,21 @@ func TestPlugin_Reserve(t *testing.T) {
 			want:        nil,
 			wantCPUSet:  cpuset.NewCPUSet(0, 1, 2, 3),
 		},
+		{
+			name: "skip allocation on virtual topology node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeVirtualTopology: "true",
+			},
+			state: &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          4,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			},
+			cpuTopology: buildCPUTopologyForTest(2, 1, 4, 2),
+			pod:         &corev1.Pod{},
+			want:        nil,
+			wantCPUSet:  cpuset.NewCPUSet(),
+		},
 		{
 			name: "allocated by node cpu bind policy",
 			nodeLabels: map[string]string{
@@ -1041,6 +1451,23 @@ func TestPlugin_Reserve(t *testing.T) {
 			want:          nil,
 			wantCPUSet:    cpuset.NewCPUSet(4, 5, 6, 7),
 		},
+		{
+			name: "succeed with pod numa least allocate strategy overriding node strategy",
+			nodeLabels: map[string]string{
+				extension.LabelNodeNUMAAllocateStrategy: string(extension.NodeNUMAAllocateStrategyMostAllocated),
+			},
+			state: &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          4,
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numaAllocateStrategy:   schedulingconfig.NUMALeastAllocated,
+			},
+			cpuTopology:   buildCPUTopologyForTest(2, 1, 8, 2),
+			allocatedCPUs: []int{0, 1, 2, 3},
+			pod:           &corev1.Pod{},
+			want:          nil,
+			wantCPUSet:    cpuset.NewCPUSet(16, 17, 18, 19),
+		},
 		{
 			name: "succeed allocate from reservation reserved cpus",
 			state: &preFilterState{
@@ -1083,24 +1510,29 @@ func TestPlugin_Reserve(t *testing.T) {
 			assert.Nil(t, err)
 
 			plg := p.(*Plugin)
-			nodeAllocation := NewNodeAllocation("test-node-1")
+			nodeName := "test-node-1"
 			if tt.cpuTopology != nil {
-				plg.topologyOptionsManager.UpdateTopologyOptions(nodeAllocation.nodeName, func(options *TopologyOptions) {
+				plg.topologyOptionsManager.UpdateTopologyOptions(nodeName, func(options *resourcemanager.TopologyOptions) {
 					options.CPUTopology = tt.cpuTopology
 				})
 				if len(tt.allocatedCPUs) > 0 {
-					nodeAllocation.addCPUs(tt.cpuTopology, uuid.NewUUID(), cpuset.NewCPUSet(tt.allocatedCPUs...), schedulingconfig.CPUExclusivePolicyNone)
+					plg.resourceManager.Update(nodeName, &resourcemanager.PodAllocation{
+						UID:                uuid.NewUUID(),
+						CPUSet:             cpuset.NewCPUSet(tt.allocatedCPUs...),
+						CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+					})
 				}
 				if len(tt.reservedCPUs) > 0 {
 					for reservationUID, cpus := range tt.reservedCPUs {
-						nodeAllocation.addCPUs(tt.cpuTopology, reservationUID, cpus, schedulingconfig.CPUExclusivePolicyNone)
+						plg.resourceManager.Update(nodeName, &resourcemanager.PodAllocation{
+							UID:                reservationUID,
+							CPUSet:             cpus,
+							CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+						})
 					}
 				}
 			}
 
-			cpuManager := plg.resourceManager.(*resourceManager)
-			cpuManager.nodeAllocations[nodeAllocation.nodeName] = nodeAllocation
-
 			suit.start()
 
 			cycleState := framework.NewCycleState()
@@ -1137,10 +1569,13 @@ func TestPlugin_Reserve(t *testing.T) {
 				return
 			}
 			var gotCPUs cpuset.CPUSet
+			var gotDegraded bool
 			if tt.state.allocation != nil {
 				gotCPUs = tt.state.allocation.CPUSet
+				gotDegraded = tt.state.allocation.CPUBindDegraded
 			}
 			assert.True(t, tt.wantCPUSet.Equals(gotCPUs))
+			assert.Equal(t, tt.wantDegraded, gotDegraded)
 		})
 	}
 }
@@ -1149,7 +1584,7 @@ func TestPlugin_Unreserve(t *testing.T) {
 	state := &preFilterState{
 		requestCPUBind: true,
 		numCPUsNeeded:  24,
-		allocation: &PodAllocation{
+		allocation: &resourcemanager.PodAllocation{
 			CPUSet: cpuset.NewCPUSet(0, 1, 2, 3),
 		},
 	}
@@ -1163,17 +1598,14 @@ func TestPlugin_Unreserve(t *testing.T) {
 
 	cycleState := framework.NewCycleState()
 	cycleState.Write(stateKey, state)
-	topologyOptionsManager := NewTopologyOptionsManager()
-	topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *TopologyOptions) {
+	topologyOptionsManager := resourcemanager.NewTopologyOptionsManager()
+	topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
 		options.CPUTopology = cpuTopology
 	})
 	plg := &Plugin{
-		resourceManager: &resourceManager{
-			topologyOptionsManager: topologyOptionsManager,
-			nodeAllocations:        map[string]*NodeAllocation{},
-		},
+		resourceManager: resourcemanager.NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, topologyOptionsManager),
 	}
-	plg.resourceManager.Update("test-node-1", &PodAllocation{
+	plg.resourceManager.Update("test-node-1", &resourcemanager.PodAllocation{
 		UID:                pod.UID,
 		CPUSet:             state.allocation.CPUSet,
 		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
@@ -1210,7 +1642,7 @@ func TestPlugin_PreBind(t *testing.T) {
 	state := &preFilterState{
 		requestCPUBind: true,
 		numCPUsNeeded:  4,
-		allocation: &PodAllocation{
+		allocation: &resourcemanager.PodAllocation{
 			CPUSet: cpuset.NewCPUSet(0, 1, 2, 3),
 		},
 	}
@@ -1228,6 +1660,50 @@ func TestPlugin_PreBind(t *testing.T) {
 	assert.Equal(t, expectResourceStatus, resourceStatus)
 }
 
+func TestPlugin_PreBindWithPreferredCPUSet(t *testing.T) {
+	suit := newPluginTestSuit(t, nil, nil)
+	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       uuid.NewUUID(),
+			Namespace: "default",
+			Name:      "test-pod-1",
+		},
+	}
+
+	_, status := suit.Handle.ClientSet().CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{})
+	assert.Nil(t, status)
+
+	suit.start()
+
+	plg := p.(*Plugin)
+
+	state := &preFilterState{
+		requestSoftCPUBind: true,
+		numCPUsNeeded:      2,
+		allocation: &resourcemanager.PodAllocation{
+			PreferredCPUSet: cpuset.NewCPUSet(4, 5),
+		},
+	}
+	cycleState := framework.NewCycleState()
+	cycleState.Write(stateKey, state)
+
+	s := plg.PreBind(context.TODO(), cycleState, pod, "test-node-1")
+	assert.True(t, s.IsSuccess())
+	resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+	assert.NoError(t, err)
+	expectResourceStatus := &extension.ResourceStatus{
+		PreferredCPUSet: "4-5",
+	}
+	assert.Equal(t, expectResourceStatus, resourceStatus)
+	resourceSpec, err := extension.GetResourceSpec(pod.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, &extension.ResourceSpec{}, resourceSpec)
+}
+
 func TestPlugin_PreBindWithCPUBindPolicyNone(t *testing.T) {
 	suit := newPluginTestSuit(t, nil, nil)
 	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
@@ -1253,7 +1729,7 @@ func TestPlugin_PreBindWithCPUBindPolicyNone(t *testing.T) {
 		requestCPUBind:         true,
 		numCPUsNeeded:          4,
 		preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
-		allocation: &PodAllocation{
+		allocation: &resourcemanager.PodAllocation{
 			CPUSet: cpuset.NewCPUSet(0, 1, 2, 3),
 		},
 	}
@@ -1301,7 +1777,7 @@ func TestPlugin_PreBindReservation(t *testing.T) {
 	state := &preFilterState{
 		requestCPUBind: true,
 		numCPUsNeeded:  4,
-		allocation: &PodAllocation{
+		allocation: &resourcemanager.PodAllocation{
 			CPUSet: cpuset.NewCPUSet(0, 1, 2, 3),
 		},
 	}
@@ -1344,11 +1820,11 @@ func TestRestoreReservation(t *testing.T) {
 			NodeName: "test-node",
 		},
 	}
-	pl.topologyOptionsManager.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+	pl.topologyOptionsManager.UpdateTopologyOptions("test-node", func(options *resourcemanager.TopologyOptions) {
 		options.CPUTopology = buildCPUTopologyForTest(1, 2, 8, 2)
 		options.MaxRefCount = 1
 	})
-	pl.resourceManager.Update("test-node", &PodAllocation{
+	pl.resourceManager.Update("test-node", &resourcemanager.PodAllocation{
 		UID:                reservation.UID,
 		CPUSet:             cpuset.NewCPUSet(6, 7, 8, 9),
 		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
@@ -1374,12 +1850,12 @@ func TestRestoreReservation(t *testing.T) {
 			NodeName: "test-node",
 		},
 	}
-	pl.resourceManager.Update("test-node", &PodAllocation{
+	pl.resourceManager.Update("test-node", &resourcemanager.PodAllocation{
 		UID:                podA.UID,
 		CPUSet:             cpuset.NewCPUSet(6, 7),
 		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
 	})
-	pl.resourceManager.Update("test-node", &PodAllocation{
+	pl.resourceManager.Update("test-node", &resourcemanager.PodAllocation{
 		UID:                podB.UID,
 		CPUSet:             cpuset.NewCPUSet(8, 9),
 		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
@@ -1521,3 +1997,161 @@ func Test_appendResourceSpecIfMissed(t *testing.T) {
 		})
 	}
 }
+
+func TestPluginBindToDeviceNUMA(t *testing.T) {
+	tests := []struct {
+		name         string
+		sriovDevices []extension.SRIOVDevice
+		wantFilter   *framework.Status
+		wantCPUSet   cpuset.CPUSet
+	}{
+		{
+			name: "bind to the NUMA node of SR-IOV devices",
+			sriovDevices: []extension.SRIOVDevice{
+				{BusID: "0000:3b:00.0", NUMANode: 1, NumVFs: 8, ResourceNames: []string{"intel.com/sriov"}},
+			},
+			wantCPUSet: cpuset.NewCPUSet(8, 9),
+		},
+		{
+			name: "bind to the NUMA node of the SR-IOV device requested by the pod",
+			sriovDevices: []extension.SRIOVDevice{
+				{BusID: "0000:3b:00.0", NUMANode: 0, NumVFs: 8, ResourceNames: []string{"intel.com/sriov_other"}},
+				{BusID: "0000:af:00.0", NUMANode: 1, NumVFs: 8, ResourceNames: []string{"intel.com/sriov"}},
+			},
+			wantCPUSet: cpuset.NewCPUSet(8, 9),
+		},
+		{
+			name: "SR-IOV devices not requested by the pod",
+			sriovDevices: []extension.SRIOVDevice{
+				{BusID: "0000:3b:00.0", NUMANode: 0, NumVFs: 8, ResourceNames: []string{"intel.com/sriov_other"}},
+				{BusID: "0000:af:00.0", NUMANode: 1, NumVFs: 8},
+			},
+			wantFilter: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundDeviceNUMANodes),
+		},
+		{
+			name:       "failed to find the NUMA nodes of SR-IOV devices",
+			wantFilter: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNotFoundDeviceNUMANodes),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-node-1",
+					Labels: map[string]string{},
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("16"),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			}
+			suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+			assert.NoError(t, err)
+			plg := p.(*Plugin)
+			plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+				options.SRIOVDevices = tt.sriovDevices
+			})
+			suit.start()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+					UID:       uuid.NewUUID(),
+					Annotations: map[string]string{
+						extension.AnnotationBindToDeviceNUMA: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "main",
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("2"),
+									"intel.com/sriov":  resource.MustParse("1"),
+								},
+							},
+						},
+					},
+				},
+			}
+			state := &preFilterState{
+				requestCPUBind:         true,
+				numCPUsNeeded:          2,
+				requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				bindToDeviceNUMA:       true,
+			}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(stateKey, state)
+
+			nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+			assert.NoError(t, err)
+			status := plg.Filter(context.TODO(), cycleState, pod, nodeInfo)
+			assert.Equal(t, tt.wantFilter, status)
+			if !status.IsSuccess() {
+				return
+			}
+
+			status = plg.Reserve(context.TODO(), cycleState, pod, node.Name)
+			assert.True(t, status.IsSuccess(), status)
+			assert.Equal(t, tt.wantCPUSet, state.allocation.CPUSet)
+		})
+	}
+}
+
+func TestResourceManagerAllocateWithPodOverhead(t *testing.T) {
+	tom := resourcemanager.NewTopologyOptionsManager()
+	tom.UpdateTopologyOptions("test-node", func(options *resourcemanager.TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+		options.NUMANodeResources = []resourcemanager.NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+			{Node: 1, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+		}
+	})
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	resourceManager := NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, tom)
+
+	// the kata Pod requests 4 CPUs with the 250m overhead, which are rounded up to 6 CPUs for FullPCPUs
+	state := &preFilterState{
+		requestCPUBind:      true,
+		cpuOverheadInflated: true,
+		requests:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4250m")},
+		numCPUsNeeded:       5,
+	}
+	topologyOptions := tom.GetTopologyOptions(node.Name)
+	numCPUsNeeded := state.numCPUsNeededOnNode(topologyOptions.CPUTopology, true)
+	assert.Equal(t, 6, numCPUsNeeded)
+	mask, _ := bitmask.NewBitMask(0)
+	options, err := resourcemanager.NewResourceOptions(node, state.requestsOnNode(numCPUsNeeded), topologyOptions,
+		resourcemanager.WithCPUBind(numCPUsNeeded, schedulingconfig.CPUBindPolicyFullPCPUs, false),
+		resourcemanager.WithNUMATopologyHint(topologyhint.NUMATopologyHint{NUMANodeAffinity: mask}),
+	)
+	assert.NoError(t, err)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "kata-pod"}}
+	allocation, err := resourceManager.Allocate(node, pod, options)
+	assert.NoError(t, err)
+	assert.Equal(t, cpuset.MustParse("0-5"), allocation.CPUSet)
+	assert.Equal(t, []resourcemanager.NUMANodeResource{
+		{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: *resource.NewMilliQuantity(6000, resource.DecimalSI)}},
+	}, allocation.NUMANodeResources)
+
+	// the 2 CPUs left on the NUMA Node can't hold 3 CPUs, which the requests of 4250m would leave room for
+	resourceManager.Update(node.Name, allocation)
+	options, err = resourcemanager.NewResourceOptions(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}, topologyOptions,
+		resourcemanager.WithNUMATopologyHint(topologyhint.NUMATopologyHint{NUMANodeAffinity: mask}),
+	)
+	assert.NoError(t, err)
+	_, err = resourceManager.Allocate(node, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "shared-pod"}}, options)
+	assert.Error(t, err)
+}
@@ -26,16 +26,17 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
 )
 
 type podEventHandler struct {
-	resourceManager ResourceManager
+	resourceManager resourcemanager.ResourceManager
 }
 
-func registerPodEventHandler(handle framework.Handle, resourceManager ResourceManager) {
+func registerPodEventHandler(handle framework.Handle, resourceManager resourcemanager.ResourceManager) {
 	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
 	eventHandler := &podEventHandler{
 		resourceManager: resourceManager,
@@ -113,24 +114,45 @@ func (c *podEventHandler) updatePod(oldPod, pod *corev1.Pod) {
 	if err != nil {
 		return
 	}
-	if len(resourceStatus.NUMANodeResources) == 0 && cpus.IsEmpty() {
+	preferredCPUs, err := cpuset.Parse(resourceStatus.PreferredCPUSet)
+	if err != nil {
+		return
+	}
+	if len(resourceStatus.NUMANodeResources) == 0 && cpus.IsEmpty() && preferredCPUs.IsEmpty() {
 		return
 	}
 
-	allocation := &PodAllocation{
+	allocation := &resourcemanager.PodAllocation{
 		UID:                pod.UID,
 		Namespace:          pod.Namespace,
 		Name:               pod.Name,
 		CPUSet:             cpus,
 		CPUExclusivePolicy: resourceSpec.PreferredCPUExclusivePolicy,
-		NUMANodeResources:  make([]NUMANodeResource, 0, len(resourceStatus.NUMANodeResources)),
+		NUMANodeResources:  make([]resourcemanager.NUMANodeResource, 0, len(resourceStatus.NUMANodeResources)),
+		PreferredCPUSet:    preferredCPUs,
+		QoSClass:           extension.GetPodQoSClassRaw(pod),
 	}
 	for _, numaNodeRes := range resourceStatus.NUMANodeResources {
-		allocation.NUMANodeResources = append(allocation.NUMANodeResources, NUMANodeResource{
+		allocation.NUMANodeResources = append(allocation.NUMANodeResources, resourcemanager.NUMANodeResource{
 			Node:      int(numaNodeRes.Node),
 			Resources: numaNodeRes.Resources,
 		})
 	}
+	for _, containerCPUSet := range resourceStatus.ContainerCPUSets {
+		containerCPUs, err := cpuset.Parse(containerCPUSet.CPUSet)
+		if err != nil {
+			return
+		}
+		allocation.ContainerCPUSets = append(allocation.ContainerCPUSets, resourcemanager.ContainerCPUSet{
+			Name:   containerCPUSet.Name,
+			CPUSet: containerCPUs,
+		})
+	}
+	allocation.SteadyStateNUMANodeResources = resourcemanager.GetSteadyStateNUMANodeResources(pod, allocation)
+	if allocation.SteadyStateNUMANodeResources != nil && isPodInitialized(pod) {
+		// the resources requested only by init containers are reclaimed once they completed
+		allocation.NUMANodeResources = allocation.SteadyStateNUMANodeResources
+	}
 
 	c.resourceManager.Update(pod.Spec.NodeName, allocation)
 }
@@ -21,10 +21,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
@@ -103,54 +107,152 @@ func TestPodEventHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cpuTopology := buildCPUTopologyForTest(2, 2, 4, 2)
-			topologyOptionsManager := NewTopologyOptionsManager()
-			topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *TopologyOptions) {
+			topologyOptionsManager := resourcemanager.NewTopologyOptionsManager()
+			topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
 				options.CPUTopology = cpuTopology
 			})
-			resourceManager := &resourceManager{
-				topologyOptionsManager: topologyOptionsManager,
-				nodeAllocations:        map[string]*NodeAllocation{},
-			}
+			resourceManager := resourcemanager.NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, topologyOptionsManager)
 			handler := &podEventHandler{
 				resourceManager: resourceManager,
 			}
 			handler.OnAdd(tt.pod)
 			handler.OnUpdate(tt.pod, tt.pod)
 
-			nodeAllocation := resourceManager.getOrCreateNodeAllocation("test-node-1")
-			_, ok := nodeAllocation.allocatedPods[tt.pod.UID]
+			_, ok := resourceManager.GetNodeAllocation("test-node-1").GetPodAllocation(tt.pod.UID)
 			if tt.wantAdd && !ok {
 				t.Errorf("expect add the Pod but not found")
 			} else if !tt.wantAdd && ok {
 				t.Errorf("expect not add the Pod but found")
 			}
 
-			cpusetBuilder := cpuset.NewCPUSetBuilder()
-			for _, v := range nodeAllocation.allocatedCPUs {
-				cpusetBuilder.Add(v.CPUID)
-			}
-			cpuset := cpusetBuilder.Result()
-			if tt.want.IsEmpty() && !cpuset.IsEmpty() {
+			cpus, _ := resourceManager.GetAllocatedCPUSet("test-node-1", tt.pod.UID)
+			if tt.want.IsEmpty() && !cpus.IsEmpty() {
 				t.Errorf("expect empty cpuset but got")
-			} else if !tt.want.IsEmpty() && cpuset.IsEmpty() {
+			} else if !tt.want.IsEmpty() && cpus.IsEmpty() {
 				t.Errorf("expect cpuset but got empty")
-			} else if !tt.want.Equals(cpuset) {
-				t.Errorf("expect cpuset equal, but failed, expect: %v, got: %v", tt.want, cpuset)
+			} else if !tt.want.Equals(cpus) {
+				t.Errorf("expect cpuset equal, but failed, expect: %v, got: %v", tt.want, cpus)
 			}
-			allocation := nodeAllocation.allocatedPods[tt.pod.UID]
-			cpuset = allocation.CPUSet
-			if tt.want.IsEmpty() && !cpuset.IsEmpty() {
+			allocation, _ := resourceManager.GetNodeAllocation("test-node-1").GetPodAllocation(tt.pod.UID)
+			cpus = allocation.CPUSet
+			if tt.want.IsEmpty() && !cpus.IsEmpty() {
 				t.Errorf("expect empty cpuset but got")
-			} else if !tt.want.IsEmpty() && cpuset.IsEmpty() {
+			} else if !tt.want.IsEmpty() && cpus.IsEmpty() {
 				t.Errorf("expect cpuset but got empty")
-			} else if !tt.want.Equals(cpuset) {
-				t.Errorf("expect cpuset equal, but failed, expect: %v, got: %v", tt.want, cpuset)
+			} else if !tt.want.Equals(cpus) {
+				t.Errorf("expect cpuset equal, but failed, expect: %v, got: %v", tt.want, cpus)
 			}
 
 			handler.OnDelete(tt.pod)
-			assert.Empty(t, nodeAllocation.allocatedPods)
-			assert.Empty(t, nodeAllocation.allocatedCPUs)
+			assert.Empty(t, resourceManager.GetNodeAllocation("test-node-1").ListPodAllocations())
+			_, allocatedCPUs, err := resourceManager.GetAvailableCPUs("test-node-1", cpuset.CPUSet{})
+			assert.NoError(t, err)
+			assert.Empty(t, allocatedCPUs)
 		})
 	}
 
 }
+
+func TestPodEventHandlerReclaimInitContainerResources(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:  uuid.NewUUID(),
+			Name: "test",
+			Annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"numaNodeResources":[{"node":0,"resources":{"cpu":"4","memory":"6Gi"}},{"node":1,"resources":{"cpu":"2","memory":"2Gi"}}]}`,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node-1",
+			InitContainers: []corev1.Container{
+				{
+					Name: "init",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("6"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("3"),
+							corev1.ResourceMemory: resource.MustParse("4Gi"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+
+	cpuTopology := buildCPUTopologyForTest(2, 2, 4, 2)
+	topologyOptionsManager := resourcemanager.NewTopologyOptionsManager()
+	topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
+		options.CPUTopology = cpuTopology
+	})
+	resourceManager := resourcemanager.NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, topologyOptionsManager)
+	handler := &podEventHandler{
+		resourceManager: resourceManager,
+	}
+	handler.OnAdd(pod)
+
+	nodeAllocation := resourceManager.GetNodeAllocation("test-node-1")
+	topologyOptions := resourcemanager.TopologyOptions{
+		CPUTopology:       cpuTopology,
+		NUMANodeResources: []resourcemanager.NUMANodeResource{{Node: 0}, {Node: 1}},
+	}
+	expectInitPhase := map[int]corev1.ResourceList{
+		0: {corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("6Gi")},
+		1: {corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	_, allocated := nodeAllocation.GetAvailableNUMANodeResources(topologyOptions, nil)
+	assert.Len(t, allocated, len(expectInitPhase))
+	for nodeID, res := range expectInitPhase {
+		assert.True(t, quotav1.Equals(res, allocated[nodeID]), "expected %v, got %v", res, allocated[nodeID])
+	}
+	expectSteadyState := []resourcemanager.NUMANodeResource{
+		{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3"), corev1.ResourceMemory: resource.MustParse("4Gi")}},
+	}
+	podAllocation, _ := nodeAllocation.GetPodAllocation(pod.UID)
+	assertNUMANodeResourcesEqual(t, expectSteadyState, podAllocation.SteadyStateNUMANodeResources)
+
+	initializedPod := pod.DeepCopy()
+	initializedPod.Status = corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+		},
+	}
+	handler.OnUpdate(pod, initializedPod)
+	expectSteadyStatePhase := map[int]corev1.ResourceList{
+		0: {corev1.ResourceCPU: resource.MustParse("3"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		1: {corev1.ResourceCPU: resource.MustParse("0"), corev1.ResourceMemory: resource.MustParse("0")},
+	}
+	_, allocated = nodeAllocation.GetAvailableNUMANodeResources(topologyOptions, nil)
+	for nodeID, res := range expectSteadyStatePhase {
+		assert.True(t, quotav1.Equals(res, allocated[nodeID]))
+	}
+	podAllocation, _ = nodeAllocation.GetPodAllocation(pod.UID)
+	assertNUMANodeResourcesEqual(t, expectSteadyState, podAllocation.NUMANodeResources)
+
+	handler.OnDelete(initializedPod)
+	assert.Empty(t, nodeAllocation.ListPodAllocations())
+}
+
+func assertNUMANodeResourcesEqual(t *testing.T, expected, actual []resourcemanager.NUMANodeResource) {
+	assert.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		if i >= len(actual) {
+			break
+		}
+		assert.Equal(t, expected[i].Node, actual[i].Node)
+		assert.True(t, quotav1.Equals(expected[i].Resources, actual[i].Resources), "expected %v, got %v", expected[i].Resources, actual[i].Resources)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
+)
+
+func (p *Plugin) AddPod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *corev1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	if reservationutil.IsReservePod(podInfoToAdd.Pod) {
+		return nil
+	}
+
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return status
+	}
+	if state.skip {
+		return nil
+	}
+
+	nodeName := podInfoToAdd.Pod.Spec.NodeName
+	preemptible := state.preemptibleAllocations[nodeName]
+	if _, ok := preemptible[podInfoToAdd.Pod.UID]; !ok {
+		return nil
+	}
+	delete(preemptible, podInfoToAdd.Pod.UID)
+	if len(preemptible) == 0 {
+		delete(state.preemptibleAllocations, nodeName)
+	}
+	return nil
+}
+
+func (p *Plugin) RemovePod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *corev1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	if reservationutil.IsReservePod(podInfoToRemove.Pod) {
+		return nil
+	}
+
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return status
+	}
+	if state.skip {
+		return nil
+	}
+
+	// The resources of the Pods allocated from the Reservations are returned to the Reservations
+	// rather than the node, so they are not reusable by the preemptor directly.
+	reservationAllocated, err := extension.GetReservationAllocated(podInfoToRemove.Pod)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if reservationAllocated != nil && reservationAllocated.UID != "" {
+		return nil
+	}
+
+	nodeName := podInfoToRemove.Pod.Spec.NodeName
+	allocation, ok := p.resourceManager.GetNodeAllocation(nodeName).GetPodAllocation(podInfoToRemove.Pod.UID)
+	if !ok || allocation.UseReservedCPUs {
+		return nil
+	}
+	if allocation.CPUSet.IsEmpty() && len(allocation.NUMANodeResources) == 0 {
+		return nil
+	}
+
+	preemptible := state.preemptibleAllocations[nodeName]
+	if preemptible == nil {
+		preemptible = map[types.UID]resourcemanager.PodAllocation{}
+		if state.preemptibleAllocations == nil {
+			state.preemptibleAllocations = map[string]map[types.UID]resourcemanager.PodAllocation{}
+		}
+		state.preemptibleAllocations[nodeName] = preemptible
+	}
+	preemptible[podInfoToRemove.Pod.UID] = allocation
+	return nil
+}
+
+// getPreemptibleResources returns the CPUs and the NUMA Node resources released by the victims
+// which are removed from the node in the preemption simulation.
+func (s *preFilterState) getPreemptibleResources(nodeName string, topologyOptions resourcemanager.TopologyOptions) (cpuset.CPUSet, map[int]corev1.ResourceList) {
+	preemptible := s.preemptibleAllocations[nodeName]
+	if len(preemptible) == 0 {
+		return cpuset.CPUSet{}, nil
+	}
+
+	amplificationRatio := topologyOptions.AmplificationRatios[corev1.ResourceCPU]
+	builder := cpuset.NewCPUSetBuilder()
+	resources := map[int]corev1.ResourceList{}
+	for _, allocation := range preemptible {
+		builder.Add(allocation.CPUSet.ToSliceNoSort()...)
+		for _, numaNodeRes := range allocation.NUMANodeResources {
+			res := numaNodeRes.Resources
+			if !allocation.CPUSet.IsEmpty() && topologyOptions.CPUTopology != nil {
+				// The cpuset-bound CPUs are accounted by the amplified CPUs of the NUMA Node.
+				numCPUs := topologyOptions.CPUTopology.CPUDetails.KeepOnly(allocation.CPUSet).CPUsInNUMANodes(numaNodeRes.Node).Size()
+				res = res.DeepCopy()
+				res[corev1.ResourceCPU] = *resource.NewMilliQuantity(extension.Amplify(int64(numCPUs*1000), amplificationRatio), resource.DecimalSI)
+			}
+			resources[numaNodeRes.Node] = quotav1.Add(resources[numaNodeRes.Node], res)
+		}
+	}
+	return builder.Result(), resources
+}
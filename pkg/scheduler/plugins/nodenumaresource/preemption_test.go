@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func TestPluginPreemptionTopologyHints(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+		},
+	}
+	suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NoError(t, err)
+	plg := p.(*Plugin)
+	plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+		for i := 0; i < 2; i++ {
+			options.NUMANodeResources = append(options.NUMANodeResources, resourcemanager.NUMANodeResource{
+				Node: i,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+			})
+		}
+	})
+
+	victim := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "victim",
+			UID:       uuid.NewUUID(),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node.Name,
+		},
+	}
+	plg.resourceManager.Update(node.Name, &resourcemanager.PodAllocation{
+		UID:                victim.UID,
+		Namespace:          victim.Namespace,
+		Name:               victim.Name,
+		CPUSet:             cpuset.NewCPUSet(0, 1, 2, 3, 4, 5, 6, 7),
+		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+		NUMANodeResources: []resourcemanager.NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+		},
+	})
+	plg.resourceManager.Update(node.Name, &resourcemanager.PodAllocation{
+		UID:                uuid.NewUUID(),
+		CPUSet:             cpuset.NewCPUSet(8, 9, 10, 11, 12, 13),
+		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+		NUMANodeResources: []resourcemanager.NUMANodeResource{
+			{Node: 1, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")}},
+		},
+	})
+	suit.start()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+			UID:       uuid.NewUUID(),
+		},
+	}
+	cycleState := framework.NewCycleState()
+	cycleState.Write(stateKey, &preFilterState{
+		requestCPUBind:         true,
+		numCPUsNeeded:          4,
+		requests:               corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		requiredCPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+		preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+	})
+
+	hasNUMANode0Hint := func(cycleState *framework.CycleState) bool {
+		hints, _ := plg.GetPodTopologyHints(context.TODO(), cycleState, pod, node.Name)
+		for _, hint := range hints[string(corev1.ResourceCPU)] {
+			if hint.NUMANodeAffinity.Count() == 1 && hint.NUMANodeAffinity.IsSet(0) {
+				return true
+			}
+		}
+		return false
+	}
+	assert.False(t, hasNUMANode0Hint(cycleState))
+
+	simulated := cycleState.Clone()
+	nodeInfo, err := suit.Handle.SnapshotSharedLister().NodeInfos().Get(node.Name)
+	assert.NoError(t, err)
+	status := plg.RemovePod(context.TODO(), simulated, pod, framework.NewPodInfo(victim), nodeInfo)
+	assert.True(t, status.IsSuccess())
+	assert.True(t, hasNUMANode0Hint(simulated))
+	assert.False(t, hasNUMANode0Hint(cycleState), "the original cycle state must not see the victims")
+
+	state, status := getPreFilterState(simulated)
+	assert.True(t, status.IsSuccess())
+	resourceOptions, err := plg.getResourceOptions(simulated, state, node, pod, topologymanager.NUMATopologyHint{}, plg.topologyOptionsManager.GetTopologyOptions(node.Name))
+	assert.NoError(t, err)
+	assert.Equal(t, cpuset.NewCPUSet(0, 1, 2, 3, 4, 5, 6, 7), resourceOptions.PreferredCPUs)
+
+	status = plg.AddPod(context.TODO(), simulated, pod, framework.NewPodInfo(victim), nodeInfo)
+	assert.True(t, status.IsSuccess())
+	assert.False(t, hasNUMANode0Hint(simulated))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// defaultAllocationHistorySize is the number of the latest CPUSet allocations kept for each node.
+const defaultAllocationHistorySize = 64
+
+// CPUSetAllocationRecord describes a CPUSet allocation decision made in the Reserve phase.
+// The records help to find out which allocations fragmented the CPUs of the node after the incidents.
+type CPUSetAllocationRecord struct {
+	Timestamp            metav1.Time                           `json:"timestamp"`
+	UID                  types.UID                             `json:"uid,omitempty"`
+	Namespace            string                                `json:"namespace,omitempty"`
+	Name                 string                                `json:"name,omitempty"`
+	CPUBindPolicy        schedulingconfig.CPUBindPolicy        `json:"cpuBindPolicy,omitempty"`
+	CPUExclusivePolicy   schedulingconfig.CPUExclusivePolicy   `json:"cpuExclusivePolicy,omitempty"`
+	NUMAAllocateStrategy schedulingconfig.NUMAAllocateStrategy `json:"numaAllocateStrategy,omitempty"`
+	NUMANodes            []int                                 `json:"numaNodes,omitempty"`
+	CPUSet               cpuset.CPUSet                         `json:"cpuset"`
+	// AlternativesConsidered is the number of the available CPUs on the node that the CPUSet was chosen from.
+	AlternativesConsidered int `json:"alternativesConsidered"`
+}
+
+func newCPUSetAllocationRecord(pod *corev1.Pod, options *ResourceOptions, numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy,
+	allocation *PodAllocation, numAvailableCPUs int) CPUSetAllocationRecord {
+	record := CPUSetAllocationRecord{
+		Timestamp:              metav1.Now(),
+		UID:                    pod.UID,
+		Namespace:              pod.Namespace,
+		Name:                   pod.Name,
+		CPUBindPolicy:          options.CPUBindPolicy,
+		CPUExclusivePolicy:     options.CPUExclusivePolicy,
+		NUMAAllocateStrategy:   numaAllocateStrategy,
+		CPUSet:                 allocation.CPUSet,
+		AlternativesConsidered: numAvailableCPUs,
+	}
+	for _, numaNode := range allocation.NUMANodeResources {
+		record.NUMANodes = append(record.NUMANodes, numaNode.Node)
+	}
+	return record
+}
+
+// allocationHistory is a bounded ring of the latest CPUSet allocation records of a node.
+type allocationHistory struct {
+	lock    sync.RWMutex
+	records []CPUSetAllocationRecord
+	// next is the index to write the next record
+	next int
+	full bool
+}
+
+func newAllocationHistory(size int) *allocationHistory {
+	return &allocationHistory{
+		records: make([]CPUSetAllocationRecord, size),
+	}
+}
+
+func (h *allocationHistory) add(record CPUSetAllocationRecord) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if len(h.records) == 0 {
+		return
+	}
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the records from the oldest to the latest.
+func (h *allocationHistory) list() []CPUSetAllocationRecord {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	if !h.full {
+		return append([]CPUSetAllocationRecord{}, h.records[:h.next]...)
+	}
+	result := make([]CPUSetAllocationRecord, 0, len(h.records))
+	result = append(result, h.records[h.next:]...)
+	return append(result, h.records[:h.next]...)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_allocationHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		numAdded  int
+		wantNames []string
+	}{
+		{
+			name:      "empty history",
+			size:      3,
+			numAdded:  0,
+			wantNames: []string{},
+		},
+		{
+			name:      "history not full",
+			size:      3,
+			numAdded:  2,
+			wantNames: []string{"pod-0", "pod-1"},
+		},
+		{
+			name:      "history just full",
+			size:      3,
+			numAdded:  3,
+			wantNames: []string{"pod-0", "pod-1", "pod-2"},
+		},
+		{
+			name:      "oldest records overwritten",
+			size:      3,
+			numAdded:  5,
+			wantNames: []string{"pod-2", "pod-3", "pod-4"},
+		},
+		{
+			name:      "zero size history",
+			size:      0,
+			numAdded:  2,
+			wantNames: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAllocationHistory(tt.size)
+			for i := 0; i < tt.numAdded; i++ {
+				h.add(CPUSetAllocationRecord{Name: "pod-" + strconv.Itoa(i)})
+			}
+			gotNames := []string{}
+			for _, record := range h.list() {
+				gotNames = append(gotNames, record.Name)
+			}
+			assert.Equal(t, tt.wantNames, gotNames)
+		})
+	}
+}
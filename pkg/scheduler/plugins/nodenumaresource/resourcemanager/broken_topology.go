@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+)
+
+const (
+	BrokenTopologyReasonNoNUMANodes        = "NoNUMANodes"
+	BrokenTopologyReasonInsufficientCPUs   = "InsufficientCPUs"
+	BrokenTopologyReasonMismatchedNUMANode = "MismatchedNUMANodes"
+)
+
+var brokenTopologyReasons = []string{
+	BrokenTopologyReasonNoNUMANodes,
+	BrokenTopologyReasonInsufficientCPUs,
+	BrokenTopologyReasonMismatchedNUMANode,
+}
+
+// ValidateNodeTopology checks whether the topology reported by the NodeResourceTopology is consistent with the node,
+// and returns the reason if it is broken. The node without the reported CPU topology is not considered as broken.
+func ValidateNodeTopology(node *corev1.Node, topologyOptions *TopologyOptions) string {
+	cpuTopology := topologyOptions.CPUTopology
+	if cpuTopology == nil || cpuTopology.NumCPUs == 0 {
+		return ""
+	}
+	if cpuTopology.NumNodes == 0 || len(topologyOptions.NUMANodeResources) == 0 {
+		return BrokenTopologyReasonNoNUMANodes
+	}
+
+	allocatable := node.Status.Allocatable
+	if rawAllocatable, err := extension.GetNodeRawAllocatable(node.Annotations); err == nil && rawAllocatable != nil {
+		allocatable = rawAllocatable
+	}
+	if quantity, ok := allocatable[corev1.ResourceCPU]; ok && quantity.MilliValue() > int64(cpuTopology.NumCPUs)*1000 {
+		return BrokenTopologyReasonInsufficientCPUs
+	}
+
+	numaNodes := cpuTopology.CPUDetails.NUMANodes()
+	for _, v := range topologyOptions.NUMANodeResources {
+		if !numaNodes.Contains(v.Node) {
+			return BrokenTopologyReasonMismatchedNUMANode
+		}
+	}
+	return ""
+}
+
+// GetBrokenTopologyReason returns the reason of the broken topology if the node should fall back to
+// the scheduling without topology-aware handling.
+func GetBrokenTopologyReason(node *corev1.Node, topologyOptions *TopologyOptions) string {
+	if !k8sfeature.DefaultFeatureGate.Enabled(features.BrokenNodeTopologyFallback) {
+		return ""
+	}
+	return ValidateNodeTopology(node, topologyOptions)
+}
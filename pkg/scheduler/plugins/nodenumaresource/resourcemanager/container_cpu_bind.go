@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"sort"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// ContainerCPUBind is a container bound to the exclusive CPUs individually in the multi-container Pod.
+type ContainerCPUBind struct {
+	Name    string
+	NumCPUs int
+}
+
+// ContainerCPUSet is the CPUs allocated to a container of the composite allocation.
+type ContainerCPUSet struct {
+	Name   string        `json:"name"`
+	CPUSet cpuset.CPUSet `json:"cpuset,omitempty"`
+}
+
+// splitContainerCPUSets splits the CPUs allocated to the Pod to the bound containers. The CPUs are ordered by the
+// NUMA Nodes and the physical cores, so the containers requiring the whole cores get the whole cores.
+func splitContainerCPUSets(cpus cpuset.CPUSet, containerCPUBinds []ContainerCPUBind, cpuTopology *CPUTopology) []ContainerCPUSet {
+	numCPUsNeeded := 0
+	for _, container := range containerCPUBinds {
+		numCPUsNeeded += container.NumCPUs
+	}
+	if cpus.Size() != numCPUsNeeded {
+		return nil
+	}
+
+	cpuIDs := cpus.ToSlice()
+	if cpuTopology != nil {
+		details := cpuTopology.CPUDetails
+		sort.SliceStable(cpuIDs, func(i, j int) bool {
+			a, b := details[cpuIDs[i]], details[cpuIDs[j]]
+			if a.NodeID != b.NodeID {
+				return a.NodeID < b.NodeID
+			}
+			if a.CoreID != b.CoreID {
+				return a.CoreID < b.CoreID
+			}
+			return cpuIDs[i] < cpuIDs[j]
+		})
+	}
+	result := make([]ContainerCPUSet, 0, len(containerCPUBinds))
+	for _, container := range containerCPUBinds {
+		result = append(result, ContainerCPUSet{
+			Name:   container.Name,
+			CPUSet: cpuset.NewCPUSet(cpuIDs[:container.NumCPUs]...),
+		})
+		cpuIDs = cpuIDs[container.NumCPUs:]
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func Test_splitContainerCPUSets(t *testing.T) {
+	// the sibling CPUs of a core are not adjacent, e.g. the CPU 0 and 2 are on the core 0
+	cpuTopology := &CPUTopology{
+		NumSockets: 1,
+		NumNodes:   1,
+		NumCores:   2,
+		NumCPUs:    4,
+		CPUDetails: CPUDetails{
+			0: {CPUID: 0, CoreID: 0},
+			1: {CPUID: 1, CoreID: 1},
+			2: {CPUID: 2, CoreID: 0},
+			3: {CPUID: 3, CoreID: 1},
+		},
+	}
+	tests := []struct {
+		name       string
+		cpus       cpuset.CPUSet
+		containers []ContainerCPUBind
+		want       []ContainerCPUSet
+	}{
+		{
+			name: "split by the physical cores",
+			cpus: cpuset.NewCPUSet(0, 1, 2, 3),
+			containers: []ContainerCPUBind{
+				{Name: "main", NumCPUs: 2},
+				{Name: "sidecar", NumCPUs: 2},
+			},
+			want: []ContainerCPUSet{
+				{Name: "main", CPUSet: cpuset.NewCPUSet(0, 2)},
+				{Name: "sidecar", CPUSet: cpuset.NewCPUSet(1, 3)},
+			},
+		},
+		{
+			name: "mismatched number of CPUs",
+			cpus: cpuset.NewCPUSet(0, 1),
+			containers: []ContainerCPUBind{
+				{Name: "main", NumCPUs: 4},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitContainerCPUSets(tt.cpus, tt.containers, cpuTopology))
+		})
+	}
+}
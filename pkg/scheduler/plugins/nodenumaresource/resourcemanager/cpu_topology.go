@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package nodenumaresource
+package resourcemanager
 
 import (
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
@@ -23,21 +23,25 @@ import (
 
 // CPUTopology contains details of node cpu
 type CPUTopology struct {
-	NumCPUs    int        `json:"numCPUs"`
-	NumCores   int        `json:"numCores"`
-	NumNodes   int        `json:"numNodes"`
-	NumSockets int        `json:"numSockets"`
+	NumCPUs    int `json:"numCPUs"`
+	NumCores   int `json:"numCores"`
+	NumNodes   int `json:"numNodes"`
+	NumSockets int `json:"numSockets"`
+	// NumL3s is the number of the L3 cache domains. It is zero if the L3 cache info is not reported.
+	NumL3s     int        `json:"numL3s,omitempty"`
 	CPUDetails CPUDetails `json:"cpuDetails"`
 }
 
 type CPUTopologyBuilder struct {
 	topologyTracker map[int] /*socket*/ map[int] /*node*/ map[int] /*core*/ struct{}
+	l3Tracker       map[int] /*l3*/ struct{}
 	topology        CPUTopology
 }
 
 func NewCPUTopologyBuilder() *CPUTopologyBuilder {
 	return &CPUTopologyBuilder{
 		topologyTracker: map[int]map[int]map[int]struct{}{},
+		l3Tracker:       map[int]struct{}{},
 	}
 }
 
@@ -69,6 +73,22 @@ func (b *CPUTopologyBuilder) AddCPUInfo(socketID, nodeID, coreID, cpuID int) *CP
 	return b
 }
 
+// AddL3Info sets the L3 cache ID of the CPU added by AddCPUInfo.
+// The L3 cache ID is encoded with the socket ID like the core ID, so that it is unique across the sockets.
+func (b *CPUTopologyBuilder) AddL3Info(cpuID, l3ID int) *CPUTopologyBuilder {
+	cpuInfo, ok := b.topology.CPUDetails[cpuID]
+	if !ok {
+		return b
+	}
+	cpuInfo.L3ID = cpuInfo.SocketID<<16 | l3ID
+	b.topology.CPUDetails[cpuID] = cpuInfo
+	if _, ok := b.l3Tracker[cpuInfo.L3ID]; !ok {
+		b.topology.NumL3s++
+		b.l3Tracker[cpuInfo.L3ID] = struct{}{}
+	}
+	return b
+}
+
 func (b *CPUTopologyBuilder) Result() *CPUTopology {
 	return &b.topology
 }
@@ -78,6 +98,11 @@ func (topo *CPUTopology) IsValid() bool {
 	return topo.NumSockets != 0 && topo.NumNodes != 0 && topo.NumCores != 0 && topo.NumCPUs != 0
 }
 
+// HasL3Info checks if the L3 cache domains of the CPUs are known
+func (topo *CPUTopology) HasL3Info() bool {
+	return topo.NumL3s != 0
+}
+
 // CPUsPerCore returns the number of logical CPUs are associated with each core.
 func (topo *CPUTopology) CPUsPerCore() int {
 	if topo.NumCores == 0 {
@@ -116,6 +141,7 @@ type CPUInfo struct {
 	CoreID          int                                 `json:"coreID"`
 	NodeID          int                                 `json:"nodeID"`
 	SocketID        int                                 `json:"socketID"`
+	L3ID            int                                 `json:"l3ID"`
 	RefCount        int                                 `json:"refCount"`
 	ExclusivePolicy schedulingconfig.CPUExclusivePolicy `json:"exclusivePolicy"`
 }
@@ -266,3 +292,25 @@ func (d CPUDetails) CPUsInCores(ids ...int) cpuset.CPUSet {
 	}
 	return b.Result()
 }
+
+// L3s returns the L3 cache IDs associated with the CPUs in this CPUDetails.
+func (d CPUDetails) L3s() cpuset.CPUSet {
+	b := cpuset.NewCPUSetBuilder()
+	for _, info := range d {
+		b.Add(info.L3ID)
+	}
+	return b.Result()
+}
+
+// CPUsInL3s returns the logical CPU IDs associated with the given L3 cache IDs in this CPUDetails.
+func (d CPUDetails) CPUsInL3s(ids ...int) cpuset.CPUSet {
+	b := cpuset.NewCPUSetBuilder()
+	for _, id := range ids {
+		for cpu, info := range d {
+			if info.L3ID == id {
+				b.Add(cpu)
+			}
+		}
+	}
+	return b.Result()
+}
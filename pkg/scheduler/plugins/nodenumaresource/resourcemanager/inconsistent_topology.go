@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+)
+
+// inconsistentTopologyResources are the resources whose NUMA Node allocatable is cross-checked with the node.
+var inconsistentTopologyResources = []corev1.ResourceName{
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+}
+
+// ValidateNUMANodeAllocatable cross-checks the sum of the NUMA Node allocatable reported by the NodeResourceTopology
+// with the node, and returns the inconsistent resources.
+// The NUMA Nodes should hold the allocatable of the node, and the reservations of the node explain the NUMA Node
+// allocatable exceeding the node allocatable only up to the node capacity. The node allocatable is compared before the
+// amplification. The node without the reported NUMA Node allocatable is not considered as inconsistent.
+func ValidateNUMANodeAllocatable(node *corev1.Node, topologyOptions *TopologyOptions) []corev1.ResourceName {
+	if len(topologyOptions.NUMANodeTotalAllocatable) == 0 {
+		return nil
+	}
+	// the raw allocatable only records the amplified resources
+	rawAllocatable, _ := extension.GetNodeRawAllocatable(node.Annotations)
+
+	var inconsistent []corev1.ResourceName
+	for _, resourceName := range inconsistentTopologyResources {
+		total, ok := topologyOptions.NUMANodeTotalAllocatable[resourceName]
+		if !ok {
+			continue
+		}
+		quantity, ok := rawAllocatable[resourceName]
+		if !ok {
+			quantity, ok = node.Status.Allocatable[resourceName]
+		}
+		if ok && total.Cmp(quantity) < 0 {
+			inconsistent = append(inconsistent, resourceName)
+			continue
+		}
+		if capacity, ok := node.Status.Capacity[resourceName]; ok && total.Cmp(capacity) > 0 {
+			inconsistent = append(inconsistent, resourceName)
+		}
+	}
+	return inconsistent
+}
+
+// GetInconsistentTopologyResources returns the inconsistent resources if the node should be quarantined from
+// the Pods requiring the CPU bind policy.
+func GetInconsistentTopologyResources(node *corev1.Node, topologyOptions *TopologyOptions) []corev1.ResourceName {
+	if !k8sfeature.DefaultFeatureGate.Enabled(features.InconsistentNodeTopologyQuarantine) {
+		return nil
+	}
+	return ValidateNUMANodeAllocatable(node, topologyOptions)
+}
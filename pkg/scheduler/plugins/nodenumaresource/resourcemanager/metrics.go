@@ -0,0 +1,364 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+const (
+	// SchedulerSubsystem - subsystem name used by koord-scheduler
+	SchedulerSubsystem = "scheduler"
+)
+
+const (
+	cpuBindFailureReasonInvalidCPUTopology    = "InvalidCPUTopology"
+	cpuBindFailureReasonInsufficientNUMANode  = "InsufficientNUMANodeResources"
+	cpuBindFailureReasonInsufficientCPUs      = "InsufficientCPUs"
+	cpuBindFailureReasonRequiredCPUBindPolicy = "RequiredCPUBindPolicyUnsatisfied"
+)
+
+var cpuBindFailureReasons = []string{
+	cpuBindFailureReasonInvalidCPUTopology,
+	cpuBindFailureReasonInsufficientNUMANode,
+	cpuBindFailureReasonInsufficientCPUs,
+	cpuBindFailureReasonRequiredCPUBindPolicy,
+}
+
+// qosLabelNone is the qos label of the Pods without a koordinator QoS class.
+const qosLabelNone = "None"
+
+var refCountQoSClasses = []extension.QoSClass{
+	extension.QoSLSE,
+	extension.QoSLSR,
+	extension.QoSLS,
+	extension.QoSBE,
+	extension.QoSSystem,
+	extension.QoSNone,
+}
+
+var errRequiredCPUBindPolicyUnsatisfied = errors.New("insufficient CPUs to satisfy required cpu bind policy")
+
+var (
+	NUMANodeLargestFreeFullCoreBlock = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "numa_node_largest_free_full_core_block",
+			Help:           "Number of physical cores in the largest block of contiguous free full cores, by the node, by the NUMA Node",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "numa_node"})
+
+	NUMANodeStrandedHyperThreads = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "numa_node_stranded_hyperthreads",
+			Help:           "Number of free logical CPUs whose sibling hyperthreads are allocated, by the node, by the NUMA Node",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "numa_node"})
+
+	CPUBindFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           registry.CPUBindFailures.Name,
+			Help:           "Number of failures to allocate NUMA resources or CPUs for Pods, by the node, by the reason",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "reason"})
+
+	BrokenNodeTopology = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "broken_node_topology",
+			Help:           "Whether the NodeResourceTopology of the node is inconsistent and falls back to the scheduling without CPU binding, by the node, by the reason",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "reason"})
+
+	InconsistentNodeTopology = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "inconsistent_node_topology",
+			Help:           "Whether the sum of the NUMA Node allocatable reported by the NodeResourceTopology is inconsistent with the node allocatable, by the node, by the resource",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "resource"})
+
+	NodeCPURefCountRatio = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "node_cpu_ref_count_ratio",
+			Help:           "Ratio of the CPU references of the cpuset bound Pods to the ref-count capacity (CPUs * MaxRefCount), by the node, by the QoS class",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "qos"})
+
+	NUMANodeCPURefCountRatio = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           registry.NUMANodeCPURefCountRatio.Name,
+			Help:           "Ratio of the CPU references of the cpuset bound Pods to the ref-count capacity (CPUs * MaxRefCount), by the node, by the NUMA Node, by the QoS class",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "numa_node", "qos"})
+
+	NUMANodeSaturatedCPUs = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           registry.NUMANodeSaturatedCPUs.Name,
+			Help:           "Number of CPUs whose reference count reaches the MaxRefCount and can't be shared anymore, by the node, by the NUMA Node",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "numa_node"})
+
+	HandoffConflictingAllocations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "handoff_conflicting_allocations_total",
+			Help:           "Number of Pods whose cpuset conflicts with the Pods bound earlier, detected after the leader handoff, by the node",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node"})
+
+	metricsList = []metrics.Registerable{
+		NUMANodeLargestFreeFullCoreBlock,
+		NUMANodeStrandedHyperThreads,
+		CPUBindFailures,
+		BrokenNodeTopology,
+		InconsistentNodeTopology,
+		NodeCPURefCountRatio,
+		NUMANodeCPURefCountRatio,
+		NUMANodeSaturatedCPUs,
+		HandoffConflictingAllocations,
+	}
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the allocation defragmentation metrics of the NodeNUMAResource plugin.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
+
+// numaNodeFragmentation describes how the free CPUs of a NUMA Node are fragmented.
+type numaNodeFragmentation struct {
+	largestFreeFullCoreBlock int
+	strandedHyperThreads     int
+}
+
+// calculateNUMANodeFragmentation walks the cores of each NUMA Node in the order of the core ID.
+// A core is a free full core if none of its CPUs are allocated or reserved, and the free CPUs of
+// a partially allocated core are stranded hyperthreads which can't be bound by FullPCPUs Pods.
+func calculateNUMANodeFragmentation(cpuTopology *CPUTopology, allocatedCPUs CPUDetails, reservedCPUs cpuset.CPUSet) map[int]numaNodeFragmentation {
+	result := map[int]numaNodeFragmentation{}
+	for _, numaNode := range cpuTopology.CPUDetails.NUMANodes().ToSliceNoSort() {
+		var fragmentation numaNodeFragmentation
+		block := 0
+		for _, core := range cpuTopology.CPUDetails.CoresInNUMANodes(numaNode).ToSlice() {
+			cpus := cpuTopology.CPUDetails.CPUsInCores(core)
+			free := 0
+			for _, cpuID := range cpus.ToSliceNoSort() {
+				if _, ok := allocatedCPUs[cpuID]; !ok && !reservedCPUs.Contains(cpuID) {
+					free++
+				}
+			}
+			if free == cpus.Size() {
+				block++
+				if block > fragmentation.largestFreeFullCoreBlock {
+					fragmentation.largestFreeFullCoreBlock = block
+				}
+				continue
+			}
+			block = 0
+			fragmentation.strandedHyperThreads += free
+		}
+		result[numaNode] = fragmentation
+	}
+	return result
+}
+
+func recordNUMANodeFragmentation(nodeName string, fragmentations map[int]numaNodeFragmentation) {
+	for numaNode, fragmentation := range fragmentations {
+		numaNodeID := strconv.Itoa(numaNode)
+		NUMANodeLargestFreeFullCoreBlock.WithLabelValues(nodeName, numaNodeID).Set(float64(fragmentation.largestFreeFullCoreBlock))
+		NUMANodeStrandedHyperThreads.WithLabelValues(nodeName, numaNodeID).Set(float64(fragmentation.strandedHyperThreads))
+	}
+}
+
+// cpuRefCountUsage describes how close the CPUs are to their ref-count ceilings. The references of each QoS class
+// are counted by the CPUs bound to its Pods, and the capacity is the number of unreserved CPUs times the MaxRefCount.
+type cpuRefCountUsage struct {
+	nodeRatios     map[extension.QoSClass]float64
+	numaNodeRatios map[int]map[extension.QoSClass]float64
+	saturatedCPUs  map[int]int
+}
+
+// calculateCPURefCountUsage calculates the CPU ref-count usage of the node. The Pods pinned on the reserved CPUs are
+// excluded, since they are not allocated from the shared capacity.
+// NOTE: the caller should hold the lock of the nodeAllocation.
+func calculateCPURefCountUsage(topologyOptions *TopologyOptions, nodeAllocation *NodeAllocation) *cpuRefCountUsage {
+	cpuTopology := topologyOptions.CPUTopology
+	maxRefCount := topologyOptions.MaxRefCount
+	if maxRefCount <= 0 {
+		maxRefCount = 1
+	}
+	usage := &cpuRefCountUsage{
+		nodeRatios:     map[extension.QoSClass]float64{},
+		numaNodeRatios: map[int]map[extension.QoSClass]float64{},
+		saturatedCPUs:  map[int]int{},
+	}
+
+	numaNodeCapacity := map[int]int{}
+	nodeCapacity := 0
+	for cpuID, cpuInfo := range cpuTopology.CPUDetails {
+		if topologyOptions.ReservedCPUs.Contains(cpuID) {
+			continue
+		}
+		numaNodeCapacity[cpuInfo.NodeID] += maxRefCount
+		nodeCapacity += maxRefCount
+	}
+
+	nodeRefs := map[extension.QoSClass]int{}
+	numaNodeRefs := map[int]map[extension.QoSClass]int{}
+	for _, allocation := range nodeAllocation.allocatedPods {
+		if allocation.UseReservedCPUs {
+			continue
+		}
+		for _, cpuID := range allocation.CPUSet.ToSliceNoSort() {
+			cpuInfo, ok := cpuTopology.CPUDetails[cpuID]
+			if !ok {
+				continue
+			}
+			nodeRefs[allocation.QoSClass]++
+			if numaNodeRefs[cpuInfo.NodeID] == nil {
+				numaNodeRefs[cpuInfo.NodeID] = map[extension.QoSClass]int{}
+			}
+			numaNodeRefs[cpuInfo.NodeID][allocation.QoSClass]++
+		}
+	}
+	for cpuID, cpuInfo := range nodeAllocation.allocatedCPUs {
+		if cpuInfo.RefCount >= maxRefCount && !topologyOptions.ReservedCPUs.Contains(cpuID) {
+			usage.saturatedCPUs[cpuInfo.NodeID]++
+		}
+	}
+
+	for _, qosClass := range refCountQoSClasses {
+		if nodeCapacity > 0 {
+			usage.nodeRatios[qosClass] = float64(nodeRefs[qosClass]) / float64(nodeCapacity)
+		}
+	}
+	for _, numaNode := range cpuTopology.CPUDetails.NUMANodes().ToSliceNoSort() {
+		ratios := map[extension.QoSClass]float64{}
+		for _, qosClass := range refCountQoSClasses {
+			if capacity := numaNodeCapacity[numaNode]; capacity > 0 {
+				ratios[qosClass] = float64(numaNodeRefs[numaNode][qosClass]) / float64(capacity)
+			}
+		}
+		usage.numaNodeRatios[numaNode] = ratios
+	}
+	return usage
+}
+
+func qosLabel(qosClass extension.QoSClass) string {
+	if qosClass == extension.QoSNone {
+		return qosLabelNone
+	}
+	return string(qosClass)
+}
+
+func recordCPURefCountUsage(nodeName string, usage *cpuRefCountUsage) {
+	for qosClass, ratio := range usage.nodeRatios {
+		NodeCPURefCountRatio.WithLabelValues(nodeName, qosLabel(qosClass)).Set(ratio)
+	}
+	for numaNode, ratios := range usage.numaNodeRatios {
+		numaNodeID := strconv.Itoa(numaNode)
+		for qosClass, ratio := range ratios {
+			NUMANodeCPURefCountRatio.WithLabelValues(nodeName, numaNodeID, qosLabel(qosClass)).Set(ratio)
+		}
+		NUMANodeSaturatedCPUs.WithLabelValues(nodeName, numaNodeID).Set(float64(usage.saturatedCPUs[numaNode]))
+	}
+}
+
+func RecordCPUBindFailure(nodeName string, reason string) {
+	CPUBindFailures.WithLabelValues(nodeName, reason).Inc()
+}
+
+// cpuBindFailureReason classifies the error returned by allocating CPUs.
+func cpuBindFailureReason(err error) string {
+	switch {
+	case err.Error() == ErrNotFoundCPUTopology || err.Error() == ErrInvalidCPUTopology:
+		return cpuBindFailureReasonInvalidCPUTopology
+	case errors.Is(err, errRequiredCPUBindPolicyUnsatisfied):
+		return cpuBindFailureReasonRequiredCPUBindPolicy
+	default:
+		return cpuBindFailureReasonInsufficientCPUs
+	}
+}
+
+func RecordBrokenNodeTopology(nodeName string, reason string) {
+	BrokenNodeTopology.WithLabelValues(nodeName, reason).Set(1)
+}
+
+// DeleteBrokenNodeTopologyMetrics resets the broken topology of the node, which is validated again by the next scheduling.
+func DeleteBrokenNodeTopologyMetrics(nodeName string) {
+	for _, reason := range brokenTopologyReasons {
+		BrokenNodeTopology.Delete(map[string]string{"node": nodeName, "reason": reason})
+	}
+}
+
+// RecordInconsistentNodeTopology marks the inconsistent resources of the node, and resets the others which are resolved.
+func RecordInconsistentNodeTopology(nodeName string, inconsistentResources []corev1.ResourceName) {
+	for _, resourceName := range inconsistentTopologyResources {
+		labels := map[string]string{"node": nodeName, "resource": string(resourceName)}
+		if quotav1.Contains(inconsistentResources, resourceName) {
+			InconsistentNodeTopology.With(labels).Set(1)
+		} else {
+			InconsistentNodeTopology.Delete(labels)
+		}
+	}
+}
+
+func DeleteInconsistentNodeTopologyMetrics(nodeName string) {
+	RecordInconsistentNodeTopology(nodeName, nil)
+}
+
+func deleteNodeMetrics(nodeName string, numaNodes cpuset.CPUSet) {
+	for _, numaNode := range numaNodes.ToSliceNoSort() {
+		labels := map[string]string{"node": nodeName, "numa_node": strconv.Itoa(numaNode)}
+		NUMANodeLargestFreeFullCoreBlock.Delete(labels)
+		NUMANodeStrandedHyperThreads.Delete(labels)
+		NUMANodeSaturatedCPUs.Delete(labels)
+		for _, qosClass := range refCountQoSClasses {
+			NUMANodeCPURefCountRatio.Delete(map[string]string{"node": nodeName, "numa_node": labels["numa_node"], "qos": qosLabel(qosClass)})
+		}
+	}
+	for _, qosClass := range refCountQoSClasses {
+		NodeCPURefCountRatio.Delete(map[string]string{"node": nodeName, "qos": qosLabel(qosClass)})
+	}
+	for _, reason := range cpuBindFailureReasons {
+		CPUBindFailures.Delete(map[string]string{"node": nodeName, "reason": reason})
+	}
+	DeleteBrokenNodeTopologyMetrics(nodeName)
+	DeleteInconsistentNodeTopologyMetrics(nodeName)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	"github.com/koordinator-sh/koordinator/pkg/util/metrics/registry"
+)
+
+func TestCalculateNUMANodeFragmentation(t *testing.T) {
+	tests := []struct {
+		name          string
+		allocatedCPUs cpuset.CPUSet
+		reservedCPUs  cpuset.CPUSet
+		want          map[int]numaNodeFragmentation
+	}{
+		{
+			name:          "all free",
+			allocatedCPUs: cpuset.NewCPUSet(),
+			reservedCPUs:  cpuset.NewCPUSet(),
+			want: map[int]numaNodeFragmentation{
+				0: {largestFreeFullCoreBlock: 4},
+				1: {largestFreeFullCoreBlock: 4},
+			},
+		},
+		{
+			name:          "partially allocated cores strand hyperthreads",
+			allocatedCPUs: cpuset.NewCPUSet(2, 9),
+			reservedCPUs:  cpuset.NewCPUSet(),
+			want: map[int]numaNodeFragmentation{
+				0: {largestFreeFullCoreBlock: 2, strandedHyperThreads: 1},
+				1: {largestFreeFullCoreBlock: 3, strandedHyperThreads: 1},
+			},
+		},
+		{
+			name:          "full cores allocated and reserved split the free blocks",
+			allocatedCPUs: cpuset.NewCPUSet(2, 3),
+			reservedCPUs:  cpuset.NewCPUSet(12, 13),
+			want: map[int]numaNodeFragmentation{
+				0: {largestFreeFullCoreBlock: 2},
+				1: {largestFreeFullCoreBlock: 2},
+			},
+		},
+		{
+			name:          "all allocated",
+			allocatedCPUs: cpuset.NewCPUSet(0, 1, 2, 3, 4, 5, 6, 7),
+			reservedCPUs:  cpuset.NewCPUSet(),
+			want: map[int]numaNodeFragmentation{
+				0: {},
+				1: {largestFreeFullCoreBlock: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpuTopology := buildCPUTopologyForTest(1, 2, 4, 2)
+			allocatedCPUs := NewCPUDetails()
+			for _, cpuID := range tt.allocatedCPUs.ToSliceNoSort() {
+				cpuInfo := cpuTopology.CPUDetails[cpuID]
+				cpuInfo.RefCount++
+				allocatedCPUs[cpuID] = cpuInfo
+			}
+			got := calculateNUMANodeFragmentation(cpuTopology, allocatedCPUs, tt.reservedCPUs)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCalculateCPURefCountUsage(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(1, 2, 4, 2)
+	nodeAllocation := NewNodeAllocation("test-node")
+	nodeAllocation.addPodAllocation(&PodAllocation{
+		UID:      "pod-1",
+		CPUSet:   cpuset.NewCPUSet(0, 1, 2, 3),
+		QoSClass: extension.QoSLS,
+	}, cpuTopology)
+	nodeAllocation.addPodAllocation(&PodAllocation{
+		UID:      "pod-2",
+		CPUSet:   cpuset.NewCPUSet(0, 1),
+		QoSClass: extension.QoSLS,
+	}, cpuTopology)
+	nodeAllocation.addPodAllocation(&PodAllocation{
+		UID:      "pod-3",
+		CPUSet:   cpuset.NewCPUSet(8, 9),
+		QoSClass: extension.QoSLSR,
+	}, cpuTopology)
+	nodeAllocation.addPodAllocation(&PodAllocation{
+		UID:             "pod-4",
+		CPUSet:          cpuset.NewCPUSet(15),
+		QoSClass:        extension.QoSSystem,
+		UseReservedCPUs: true,
+	}, cpuTopology)
+
+	got := calculateCPURefCountUsage(&TopologyOptions{
+		CPUTopology: cpuTopology,
+		MaxRefCount: 2,
+	}, nodeAllocation)
+	assert.Equal(t, 0.1875, got.nodeRatios[extension.QoSLS])
+	assert.Equal(t, 0.0625, got.nodeRatios[extension.QoSLSR])
+	assert.Equal(t, float64(0), got.nodeRatios[extension.QoSSystem])
+	assert.Equal(t, 0.375, got.numaNodeRatios[0][extension.QoSLS])
+	assert.Equal(t, float64(0), got.numaNodeRatios[0][extension.QoSLSR])
+	assert.Equal(t, 0.125, got.numaNodeRatios[1][extension.QoSLSR])
+	assert.Equal(t, map[int]int{0: 2}, got.saturatedCPUs)
+
+	// the reserved CPUs are out of the capacity
+	got = calculateCPURefCountUsage(&TopologyOptions{
+		CPUTopology:  cpuTopology,
+		MaxRefCount:  2,
+		ReservedCPUs: cpuset.NewCPUSet(12, 13, 14, 15),
+	}, nodeAllocation)
+	assert.Equal(t, 0.25, got.nodeRatios[extension.QoSLS])
+	assert.Equal(t, 0.25, got.numaNodeRatios[1][extension.QoSLSR])
+}
+
+func TestResourceManagerRecordMetrics(t *testing.T) {
+	RegisterMetrics()
+
+	tom := NewTopologyOptionsManager()
+	tom.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(1, 2, 4, 2)
+	})
+	resourceManager := NewResourceManager(nil, schedulingconfig.NUMALeastAllocated, tom)
+
+	resourceManager.Update("test-node", &PodAllocation{
+		UID:    "123456",
+		CPUSet: cpuset.NewCPUSet(2),
+	})
+	largestBlock, err := testutil.GetGaugeMetricValue(NUMANodeLargestFreeFullCoreBlock.WithLabelValues("test-node", "0"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), largestBlock)
+	stranded, err := testutil.GetGaugeMetricValue(NUMANodeStrandedHyperThreads.WithLabelValues("test-node", "0"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), stranded)
+
+	resourceManager.Release("test-node", "123456")
+	largestBlock, err = testutil.GetGaugeMetricValue(NUMANodeLargestFreeFullCoreBlock.WithLabelValues("test-node", "0"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4), largestBlock)
+	stranded, err = testutil.GetGaugeMetricValue(NUMANodeStrandedHyperThreads.WithLabelValues("test-node", "0"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), stranded)
+	refCountRatio, err := testutil.GetGaugeMetricValue(NodeCPURefCountRatio.WithLabelValues("test-node", qosLabelNone))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), refCountRatio)
+}
+
+func TestCPUBindFailureReason(t *testing.T) {
+	err := satisfiedRequiredCPUBindPolicy(schedulingconfig.CPUBindPolicyFullPCPUs, cpuset.NewCPUSet(0, 2), buildCPUTopologyForTest(1, 1, 4, 2))
+	assert.Error(t, err)
+	assert.Equal(t, cpuBindFailureReasonRequiredCPUBindPolicy, cpuBindFailureReason(err))
+	assert.Equal(t, "insufficient CPUs to satisfy required cpu bind policy FullPCPUs", err.Error())
+}
+
+func TestRegistryDefinitions(t *testing.T) {
+	assert.Equal(t, registry.CPUBindFailures.FQName(), CPUBindFailures.FQName())
+	assert.Equal(t, registry.NUMANodeCPURefCountRatio.FQName(), NUMANodeCPURefCountRatio.FQName())
+	assert.Equal(t, registry.NUMANodeSaturatedCPUs.FQName(), NUMANodeSaturatedCPUs.FQName())
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+type NodeAllocation struct {
+	lock               sync.RWMutex
+	nodeName           string
+	allocatedPods      map[types.UID]PodAllocation
+	allocatedCPUs      CPUDetails
+	allocatedResources map[int]*NUMANodeResource
+	// softAllocatedCPUs counts the preferred CPUs of the soft bound Pods.
+	// It is tracked apart from allocatedCPUs so that the soft bound Pods don't consume the hard bound capacity.
+	softAllocatedCPUs CPUDetails
+	// reservedAllocatedCPUs counts the reserved CPUs pinned by the System QoS Pods.
+	// It is tracked apart from allocatedCPUs so that the CPU availability of the workloads is unaffected.
+	reservedAllocatedCPUs CPUDetails
+	// freed marks the NodeAllocation deleted from the resource manager after the node is drained.
+	// The writers got it before the deletion should write to the new NodeAllocation of the node instead.
+	freed bool
+}
+
+type PodAllocation struct {
+	UID                types.UID                           `json:"uid,omitempty"`
+	Namespace          string                              `json:"namespace,omitempty"`
+	Name               string                              `json:"name,omitempty"`
+	CPUSet             cpuset.CPUSet                       `json:"cpuset,omitempty"`
+	CPUExclusivePolicy schedulingconfig.CPUExclusivePolicy `json:"cpuExclusivePolicy,omitempty"`
+	NUMANodeResources  []NUMANodeResource                  `json:"numaNodeResources,omitempty"`
+	// SteadyStateNUMANodeResources is the part of NUMANodeResources that the Pod keeps after its init containers
+	// completed. It is only set when the init containers request more than the app containers.
+	SteadyStateNUMANodeResources []NUMANodeResource `json:"steadyStateNUMANodeResources,omitempty"`
+	// PreferredCPUSet is the soft bound cpuset of the LS Pod in the Soft CPUBindMode.
+	PreferredCPUSet cpuset.CPUSet `json:"preferredCPUSet,omitempty"`
+	// UseReservedCPUs indicates that the CPUSet is allocated from the reserved CPUs of the node.
+	UseReservedCPUs bool `json:"useReservedCPUs,omitempty"`
+	// CPUBindDegraded indicates that the best-effort bound Pod is allocated without CPU binding.
+	CPUBindDegraded bool `json:"cpuBindDegraded,omitempty"`
+	// QoSClass is the QoS class of the Pod, which breaks down the CPU sharing metrics of the node.
+	QoSClass extension.QoSClass `json:"qosClass,omitempty"`
+	// ContainerCPUSets splits the CPUSet to the containers bound individually.
+	ContainerCPUSets []ContainerCPUSet `json:"containerCPUSets,omitempty"`
+}
+
+func NewNodeAllocation(nodeName string) *NodeAllocation {
+	return &NodeAllocation{
+		nodeName:              nodeName,
+		allocatedPods:         map[types.UID]PodAllocation{},
+		allocatedCPUs:         NewCPUDetails(),
+		allocatedResources:    map[int]*NUMANodeResource{},
+		softAllocatedCPUs:     NewCPUDetails(),
+		reservedAllocatedCPUs: NewCPUDetails(),
+	}
+}
+
+// NodeName returns the name of the node.
+func (n *NodeAllocation) NodeName() string {
+	return n.nodeName
+}
+
+// GetPodAllocation returns the allocation of the Pod on the node.
+func (n *NodeAllocation) GetPodAllocation(podUID types.UID) (PodAllocation, bool) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	allocation, ok := n.allocatedPods[podUID]
+	return allocation, ok
+}
+
+// ListPodAllocations returns the allocations of all Pods on the node.
+func (n *NodeAllocation) ListPodAllocations() []PodAllocation {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	if len(n.allocatedPods) == 0 {
+		return nil
+	}
+	allocations := make([]PodAllocation, 0, len(n.allocatedPods))
+	for _, v := range n.allocatedPods {
+		allocations = append(allocations, v)
+	}
+	return allocations
+}
+
+// GetAvailableCPUs returns the CPUs that can be allocated on the node, and the allocated CPUs.
+// The CPUs allocated to the preferredCPUs, e.g. the CPUs reserved by a Reservation, are available again.
+func (n *NodeAllocation) GetAvailableCPUs(cpuTopology *CPUTopology, maxRefCount int, reservedCPUs, preferredCPUs cpuset.CPUSet) (availableCPUs cpuset.CPUSet, allocateInfo CPUDetails) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.getAvailableCPUs(cpuTopology, maxRefCount, reservedCPUs, preferredCPUs)
+}
+
+// GetAvailableNUMANodeResources returns the available and the allocated resources of each NUMA Node.
+// The reusableResources, e.g. the resources reserved by a Reservation, are not counted as allocated.
+func (n *NodeAllocation) GetAvailableNUMANodeResources(topologyOptions TopologyOptions, reusableResources map[int]corev1.ResourceList) (totalAvailable, totalAllocated map[int]corev1.ResourceList) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.getAvailableNUMANodeResources(topologyOptions, reusableResources)
+}
+
+func (n *NodeAllocation) update(allocation *PodAllocation, cpuTopology *CPUTopology) {
+	n.release(allocation.UID)
+	n.addPodAllocation(allocation, cpuTopology)
+}
+
+func (n *NodeAllocation) getCPUs(podUID types.UID) (cpuset.CPUSet, bool) {
+	request, ok := n.allocatedPods[podUID]
+	return request.CPUSet, ok
+}
+
+func (n *NodeAllocation) addCPUs(cpuTopology *CPUTopology, podUID types.UID, cpuset cpuset.CPUSet, exclusivePolicy schedulingconfig.CPUExclusivePolicy) {
+	n.addPodAllocation(&PodAllocation{
+		UID:                podUID,
+		CPUSet:             cpuset,
+		CPUExclusivePolicy: exclusivePolicy,
+	}, cpuTopology)
+}
+
+func (n *NodeAllocation) addPodAllocation(request *PodAllocation, cpuTopology *CPUTopology) {
+	if _, ok := n.allocatedPods[request.UID]; ok {
+		return
+	}
+	n.allocatedPods[request.UID] = *request
+
+	allocatedCPUs := n.allocatedCPUs
+	if request.UseReservedCPUs {
+		allocatedCPUs = n.reservedAllocatedCPUs
+	}
+	for _, cpuID := range request.CPUSet.ToSliceNoSort() {
+		cpuInfo, ok := allocatedCPUs[cpuID]
+		if !ok {
+			cpuInfo = cpuTopology.CPUDetails[cpuID]
+		}
+		cpuInfo.ExclusivePolicy = request.CPUExclusivePolicy
+		cpuInfo.RefCount++
+		allocatedCPUs[cpuID] = cpuInfo
+	}
+
+	for _, cpuID := range request.PreferredCPUSet.ToSliceNoSort() {
+		cpuInfo, ok := n.softAllocatedCPUs[cpuID]
+		if !ok {
+			cpuInfo = cpuTopology.CPUDetails[cpuID]
+		}
+		cpuInfo.RefCount++
+		n.softAllocatedCPUs[cpuID] = cpuInfo
+	}
+
+	for nodeID, numaNodeRes := range request.NUMANodeResources {
+		res := n.allocatedResources[numaNodeRes.Node]
+		if res == nil {
+			res = &NUMANodeResource{
+				Node:      nodeID,
+				Resources: make(corev1.ResourceList),
+			}
+			n.allocatedResources[numaNodeRes.Node] = res
+		}
+		res.Resources = quotav1.Add(res.Resources, numaNodeRes.Resources)
+	}
+}
+
+func (n *NodeAllocation) release(podUID types.UID) {
+	request, ok := n.allocatedPods[podUID]
+	if !ok {
+		return
+	}
+	delete(n.allocatedPods, podUID)
+
+	allocatedCPUs := n.allocatedCPUs
+	if request.UseReservedCPUs {
+		allocatedCPUs = n.reservedAllocatedCPUs
+	}
+	for _, cpuID := range request.CPUSet.ToSliceNoSort() {
+		cpuInfo, ok := allocatedCPUs[cpuID]
+		if !ok {
+			continue
+		}
+		cpuInfo.RefCount--
+		if cpuInfo.RefCount == 0 {
+			delete(allocatedCPUs, cpuID)
+		} else {
+			allocatedCPUs[cpuID] = cpuInfo
+		}
+	}
+
+	for _, cpuID := range request.PreferredCPUSet.ToSliceNoSort() {
+		cpuInfo, ok := n.softAllocatedCPUs[cpuID]
+		if !ok {
+			continue
+		}
+		cpuInfo.RefCount--
+		if cpuInfo.RefCount == 0 {
+			delete(n.softAllocatedCPUs, cpuID)
+		} else {
+			n.softAllocatedCPUs[cpuID] = cpuInfo
+		}
+	}
+
+	for _, numaNodeRes := range request.NUMANodeResources {
+		res := n.allocatedResources[numaNodeRes.Node]
+		if res != nil {
+			res.Resources = quotav1.SubtractWithNonNegativeResult(res.Resources, numaNodeRes.Resources)
+		}
+	}
+}
+
+func (n *NodeAllocation) getAvailableCPUs(cpuTopology *CPUTopology, maxRefCount int, reservedCPUs, preferredCPUs cpuset.CPUSet) (availableCPUs cpuset.CPUSet, allocateInfo CPUDetails) {
+	allocateInfo = n.allocatedCPUs.Clone()
+	if !preferredCPUs.IsEmpty() {
+		for _, cpuID := range preferredCPUs.ToSliceNoSort() {
+			cpuInfo, ok := allocateInfo[cpuID]
+			if ok {
+				cpuInfo.RefCount--
+				if cpuInfo.RefCount == 0 {
+					delete(allocateInfo, cpuID)
+				} else {
+					allocateInfo[cpuID] = cpuInfo
+				}
+			}
+		}
+	}
+	allocated := allocateInfo.CPUs().Filter(func(cpuID int) bool {
+		return allocateInfo[cpuID].RefCount >= maxRefCount
+	})
+	availableCPUs = cpuTopology.CPUDetails.CPUs().Difference(allocated).Difference(reservedCPUs)
+	return
+}
+
+// getAvailableReservedCPUs returns the reserved CPUs which are not pinned by the System QoS Pods up to the maxRefCount.
+func (n *NodeAllocation) getAvailableReservedCPUs(maxRefCount int, systemReservedCPUs cpuset.CPUSet) (availableCPUs cpuset.CPUSet, allocateInfo CPUDetails) {
+	allocateInfo = n.reservedAllocatedCPUs.Clone()
+	allocated := allocateInfo.CPUs().Filter(func(cpuID int) bool {
+		return allocateInfo[cpuID].RefCount >= maxRefCount
+	})
+	availableCPUs = systemReservedCPUs.Difference(allocated)
+	return
+}
+
+func (n *NodeAllocation) getAvailableNUMANodeResources(topologyOptions TopologyOptions, reusableResources map[int]corev1.ResourceList) (totalAvailable, totalAllocated map[int]corev1.ResourceList) {
+	totalAvailable = make(map[int]corev1.ResourceList)
+	totalAllocated = make(map[int]corev1.ResourceList)
+	cpuAmplificationRatio := topologyOptions.AmplificationRatios[corev1.ResourceCPU]
+	for _, numaNodeRes := range topologyOptions.NUMANodeResources {
+		var allocatedRes corev1.ResourceList
+		allocated := n.allocatedResources[numaNodeRes.Node]
+		if allocated != nil {
+			allocatedRes = allocated.Resources
+			if cpuAmplificationRatio > 1 {
+				allocatedCPUSets := int64(n.allocatedCPUs.CPUsInNUMANodes(numaNodeRes.Node).Size() * 1000)
+				amplifiedCPUs := extension.Amplify(allocatedCPUSets, cpuAmplificationRatio)
+				quantity := allocatedRes[corev1.ResourceCPU]
+				allocatedRes = allocatedRes.DeepCopy()
+				allocatedRes[corev1.ResourceCPU] = *resource.NewMilliQuantity(quantity.MilliValue()-allocatedCPUSets+amplifiedCPUs, resource.DecimalSI)
+			}
+			allocatedRes = quotav1.SubtractWithNonNegativeResult(allocatedRes, reusableResources[numaNodeRes.Node])
+			totalAllocated[numaNodeRes.Node] = allocatedRes
+		}
+		totalAvailable[numaNodeRes.Node] = quotav1.SubtractWithNonNegativeResult(numaNodeRes.Resources, allocatedRes)
+	}
+	return totalAvailable, totalAllocated
+}
+
+// GetBoundPods counts the cpuset-bound Pods on the node and on each NUMA Node.
+// The System QoS Pods pinned on the reserved CPUs are not counted since they don't take the shared CPUs.
+func (n *NodeAllocation) GetBoundPods(cpuTopology *CPUTopology) (numPods int, numPodsByNUMANode map[int]int) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	numPodsByNUMANode = map[int]int{}
+	for _, allocation := range n.allocatedPods {
+		if allocation.CPUSet.IsEmpty() || allocation.UseReservedCPUs {
+			continue
+		}
+		numPods++
+		if cpuTopology == nil {
+			continue
+		}
+		for _, numaNode := range cpuTopology.CPUDetails.KeepOnly(allocation.CPUSet).NUMANodes().ToSliceNoSort() {
+			numPodsByNUMANode[numaNode]++
+		}
+	}
+	return numPods, numPodsByNUMANode
+}
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package nodenumaresource
+package resourcemanager
 
 import (
 	"testing"
@@ -119,6 +119,65 @@ func TestNodeAllocationStateReleaseCPUs(t *testing.T) {
 	}
 }
 
+func TestNodeAllocationSoftAllocatedCPUs(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	allocationState := NewNodeAllocation("test-node-1")
+
+	podUID := uuid.NewUUID()
+	allocationState.addPodAllocation(&PodAllocation{
+		UID:             podUID,
+		PreferredCPUSet: cpuset.MustParse("0-3"),
+	}, cpuTopology)
+	anotherPodUID := uuid.NewUUID()
+	allocationState.addPodAllocation(&PodAllocation{
+		UID:             anotherPodUID,
+		PreferredCPUSet: cpuset.MustParse("2-5"),
+	}, cpuTopology)
+
+	assert.Empty(t, allocationState.allocatedCPUs)
+	assert.Equal(t, cpuset.MustParse("0-5"), allocationState.softAllocatedCPUs.CPUs())
+	assert.Equal(t, 1, allocationState.softAllocatedCPUs[0].RefCount)
+	assert.Equal(t, 2, allocationState.softAllocatedCPUs[2].RefCount)
+
+	// the soft allocated CPUs are still available for hard binding
+	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 1, cpuset.NewCPUSet(), cpuset.NewCPUSet())
+	assert.Equal(t, cpuset.MustParse("0-15"), availableCPUs)
+
+	allocationState.release(podUID)
+	assert.Equal(t, cpuset.MustParse("2-5"), allocationState.softAllocatedCPUs.CPUs())
+	assert.Equal(t, 1, allocationState.softAllocatedCPUs[2].RefCount)
+
+	allocationState.release(anotherPodUID)
+	assert.Empty(t, allocationState.softAllocatedCPUs)
+}
+
+func TestNodeAllocationReservedAllocatedCPUs(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	allocationState := NewNodeAllocation("test-node-1")
+	systemReservedCPUs := cpuset.MustParse("0-1")
+
+	podUID := uuid.NewUUID()
+	allocationState.addPodAllocation(&PodAllocation{
+		UID:             podUID,
+		CPUSet:          cpuset.MustParse("0"),
+		UseReservedCPUs: true,
+	}, cpuTopology)
+
+	// the reserved CPUs pinned by the System QoS Pods don't affect the CPUs of the workloads
+	assert.Empty(t, allocationState.allocatedCPUs)
+	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 1, systemReservedCPUs, cpuset.NewCPUSet())
+	assert.Equal(t, cpuset.MustParse("2-15"), availableCPUs)
+
+	availableReservedCPUs, allocatedReservedCPUs := allocationState.getAvailableReservedCPUs(1, systemReservedCPUs)
+	assert.Equal(t, cpuset.MustParse("1"), availableReservedCPUs)
+	assert.Equal(t, 1, allocatedReservedCPUs[0].RefCount)
+
+	allocationState.release(podUID)
+	assert.Empty(t, allocationState.reservedAllocatedCPUs)
+	availableReservedCPUs, _ = allocationState.getAvailableReservedCPUs(1, systemReservedCPUs)
+	assert.Equal(t, systemReservedCPUs, availableReservedCPUs)
+}
+
 func Test_cpuAllocation_getAvailableCPUs(t *testing.T) {
 	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
 	for _, v := range cpuTopology.CPUDetails {
@@ -301,3 +360,16 @@ func Test_getAvailableNUMANodeResources(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeAllocation_GetBoundPods(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	nodeAllocation := NewNodeAllocation("test-node-1")
+	nodeAllocation.addPodAllocation(&PodAllocation{UID: uuid.NewUUID(), CPUSet: cpuset.NewCPUSet(0, 1)}, cpuTopology)
+	nodeAllocation.addPodAllocation(&PodAllocation{UID: uuid.NewUUID(), CPUSet: cpuset.NewCPUSet(6, 7, 8, 9)}, cpuTopology)
+	nodeAllocation.addPodAllocation(&PodAllocation{UID: uuid.NewUUID(), CPUSet: cpuset.NewCPUSet(15), UseReservedCPUs: true}, cpuTopology)
+	nodeAllocation.addPodAllocation(&PodAllocation{UID: uuid.NewUUID(), PreferredCPUSet: cpuset.NewCPUSet(10, 11)}, cpuTopology)
+
+	numPods, numPodsByNUMANode := nodeAllocation.GetBoundPods(cpuTopology)
+	assert.Equal(t, 2, numPods)
+	assert.Equal(t, map[int]int{0: 2, 1: 1}, numPodsByNUMANode)
+}
@@ -0,0 +1,975 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	"github.com/koordinator-sh/koordinator/pkg/util/topologyhint"
+)
+
+const (
+	ErrNotFoundCPUTopology = "node(s) CPU Topology not found"
+	ErrInvalidCPUTopology  = "node(s) invalid CPU Topology"
+)
+
+type ResourceManager interface {
+	GetTopologyHints(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions) (map[string][]topologyhint.NUMATopologyHint, error)
+	Allocate(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions) (*PodAllocation, error)
+
+	Update(nodeName string, allocation *PodAllocation)
+	Release(nodeName string, podUID types.UID)
+
+	GetNodeAllocation(nodeName string) *NodeAllocation
+	GetAllocatedCPUSet(nodeName string, podUID types.UID) (cpuset.CPUSet, bool)
+	GetAvailableCPUs(nodeName string, preferredCPUs cpuset.CPUSet) (availableCPUs cpuset.CPUSet, allocated CPUDetails, err error)
+
+	RecordAllocation(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions, allocation *PodAllocation)
+	GetAllocationHistory(nodeName string) []CPUSetAllocationRecord
+}
+
+type ResourceOptions struct {
+	NumCPUsNeeded         int
+	RequestCPUBind        bool
+	RequestSoftCPUBind    bool
+	Requests              corev1.ResourceList
+	OriginalRequests      corev1.ResourceList
+	RequiredCPUBindPolicy bool
+	CPUBindPolicy         schedulingconfig.CPUBindPolicy
+	CPUExclusivePolicy    schedulingconfig.CPUExclusivePolicy
+	NUMAAllocateStrategy  schedulingconfig.NUMAAllocateStrategy
+	HintAllocateOrder     schedulingconfig.NUMAHintAllocateOrder
+	PreferredCPUs         cpuset.CPUSet
+	ReusableResources     map[int]corev1.ResourceList
+	Hint                  topologyhint.NUMATopologyHint
+	TopologyOptions       TopologyOptions
+	ReservedFullCores     int
+	// AllowIsolatedCPUs indicates that the Pod can be pinned on the isolated CPUs.
+	AllowIsolatedCPUs bool
+	// SpreadOccupiedCPUs are the CPUs in the L3 cache domains occupied by the replicas of the Pod on the node.
+	SpreadOccupiedCPUs cpuset.CPUSet
+	// RequiredIntraNodeSpread indicates that the Pod must not be allocated the SpreadOccupiedCPUs.
+	RequiredIntraNodeSpread bool
+	// DeviceNUMANodes are the NUMA Nodes of the SR-IOV NICs that the CPUs of the Pod are bound to.
+	DeviceNUMANodes []int
+	// UseReservedCPUs indicates that the System QoS Pod is pinned on the reserved CPUs of the node.
+	UseReservedCPUs bool
+	// InterleaveMemory indicates that the memory of the Pod is interleaved across the allocated NUMA Nodes,
+	// so the Pod prefers spreading over the NUMA Nodes rather than the single NUMA Node affinity.
+	InterleaveMemory bool
+	// BestEffortCPUBind indicates that the Pod is allocated without CPU binding if the CPUs can't be bound.
+	BestEffortCPUBind bool
+	// BoundPodsSaturatedNUMANodes are the NUMA Nodes which have reached the limit of the cpuset-bound Pods.
+	BoundPodsSaturatedNUMANodes []int
+	// DrainedNUMANodes are the NUMA Nodes drained for maintenance, whose CPUs are not allocated.
+	DrainedNUMANodes []int
+	// BalanceSockets indicates that the CPUs are taken from the socket with the fewest allocated CPUs first.
+	BalanceSockets bool
+	// ContainerCPUBinds are the containers bound individually, which split the allocated CPUs.
+	ContainerCPUBinds []ContainerCPUBind
+	// CPUBindFailureReason is set by Allocate if the CPUs are not bound, and recorded once the Pod is reserved.
+	CPUBindFailureReason string
+}
+
+// numHeldBackFullCores returns the number of free physical cores that the Pod can't use.
+// Only the Pods requiring FullPCPUs can consume the reserved full cores.
+func (o *ResourceOptions) numHeldBackFullCores() int {
+	if o.RequestCPUBind && o.CPUBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs {
+		return 0
+	}
+	return o.ReservedFullCores
+}
+
+// isAmplifiedCPUBind indicates that the Pod binds CPUs on the node with the CPU amplification ratio.
+// In the mode, the Pod is pinned on the CPUs of the OriginalRequests, while the NUMA Nodes account
+// the amplified CPUs of them, which are the Requests.
+func (o *ResourceOptions) isAmplifiedCPUBind() bool {
+	return o.RequestCPUBind && o.TopologyOptions.AmplificationRatios[corev1.ResourceCPU] > 1
+}
+
+// AmplifyCPUBindRequests amplifies the CPU requests of the Pod in the amplified CPU bind mode.
+func (o *ResourceOptions) AmplifyCPUBindRequests() {
+	if !o.isAmplifiedCPUBind() {
+		return
+	}
+	o.Requests = o.OriginalRequests.DeepCopy()
+	extension.AmplifyResourceList(o.Requests, o.TopologyOptions.AmplificationRatios, corev1.ResourceCPU)
+}
+
+type resourceManager struct {
+	numaAllocateStrategy   schedulingconfig.NUMAAllocateStrategy
+	topologyOptionsManager TopologyOptionsManager
+	nodeLister             corelisters.NodeLister
+	lock                   sync.Mutex
+	nodeAllocations        map[string]*NodeAllocation
+	allocationHistories    map[string]*allocationHistory
+}
+
+// NodeInformer is the subset of the Node informer that the ResourceManager depends on.
+// It is satisfied by the Node informer of the client-go SharedInformerFactory.
+type NodeInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() corelisters.NodeLister
+}
+
+// NewResourceManager creates a ResourceManager, which can be reused outside the scheduler framework,
+// e.g. by custom schedulers or admission webhooks. The nodeInformer can be nil, in which case the
+// caller is responsible for releasing the allocations of the deleted nodes and the nodes drained
+// by the coordinated draining.
+func NewResourceManager(
+	nodeInformer NodeInformer,
+	defaultNUMAAllocateStrategy schedulingconfig.NUMAAllocateStrategy,
+	topologyOptionsManager TopologyOptionsManager,
+) ResourceManager {
+	manager := &resourceManager{
+		numaAllocateStrategy:   defaultNUMAAllocateStrategy,
+		topologyOptionsManager: topologyOptionsManager,
+		nodeAllocations:        map[string]*NodeAllocation{},
+		allocationHistories:    map[string]*allocationHistory{},
+	}
+	if nodeInformer == nil {
+		return manager
+	}
+	manager.nodeLister = nodeInformer.Lister()
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: manager.onNodeUpdate,
+		DeleteFunc: manager.onNodeDelete,
+	})
+	return manager
+}
+
+func (c *resourceManager) onNodeUpdate(oldObj, newObj interface{}) {
+	node, ok := newObj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	if extension.IsNodeCoordinatedDraining(node) {
+		c.freeNodeAllocationIfDrained(node.Name)
+	}
+}
+
+func (c *resourceManager) onNodeDelete(obj interface{}) {
+	var node *corev1.Node
+	switch t := obj.(type) {
+	case *corev1.Node:
+		node = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		node, ok = t.Obj.(*corev1.Node)
+		if !ok {
+			return
+		}
+	default:
+		break
+	}
+
+	if node == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.nodeAllocations, node.Name)
+	delete(c.allocationHistories, node.Name)
+
+	topologyOptions := c.topologyOptionsManager.GetTopologyOptions(node.Name)
+	if topologyOptions.CPUTopology != nil {
+		deleteNodeMetrics(node.Name, topologyOptions.CPUTopology.CPUDetails.NUMANodes())
+	}
+	// invalidate the topology promptly rather than waiting for the NodeResourceTopology to be garbage collected,
+	// so that a node recreated with the same name is not scheduled with the stale topology.
+	c.topologyOptionsManager.Delete(node.Name)
+}
+
+func (c *resourceManager) getOrCreateNodeAllocation(nodeName string) *NodeAllocation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	v := c.nodeAllocations[nodeName]
+	if v == nil {
+		v = NewNodeAllocation(nodeName)
+		c.nodeAllocations[nodeName] = v
+	}
+	return v
+}
+
+// lockNodeAllocation returns the NodeAllocation of the node locked for writing. It is got again if it is freed
+// by freeNodeAllocationIfDrained before it's locked, so that no write is lost in the freed NodeAllocation.
+func (c *resourceManager) lockNodeAllocation(nodeName string) *NodeAllocation {
+	for {
+		nodeAllocation := c.getOrCreateNodeAllocation(nodeName)
+		nodeAllocation.lock.Lock()
+		if !nodeAllocation.freed {
+			return nodeAllocation
+		}
+		nodeAllocation.lock.Unlock()
+	}
+}
+
+// RecordAllocation records the CPUSet allocation decision of the Pod into the allocation history of the node.
+// It should be called before the allocation is updated, so that the CPUs it was chosen from are still available.
+func (c *resourceManager) RecordAllocation(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions, allocation *PodAllocation) {
+	if allocation == nil || allocation.CPUSet.IsEmpty() {
+		return
+	}
+	availableCPUs, _, err := c.GetAvailableCPUs(node.Name, options.PreferredCPUs)
+	if err != nil {
+		klog.V(5).Infof("failed to get available CPUs of node %s for allocation history, err: %v", node.Name, err)
+	}
+	numaAllocateStrategy := GetNUMAAllocateStrategy(node, c.numaAllocateStrategy)
+	if options.NUMAAllocateStrategy != "" {
+		numaAllocateStrategy = options.NUMAAllocateStrategy
+	}
+	record := newCPUSetAllocationRecord(pod, options, numaAllocateStrategy, allocation, availableCPUs.Size())
+
+	c.lock.Lock()
+	history := c.allocationHistories[node.Name]
+	if history == nil {
+		history = newAllocationHistory(defaultAllocationHistorySize)
+		c.allocationHistories[node.Name] = history
+	}
+	c.lock.Unlock()
+	history.add(record)
+}
+
+// GetAllocationHistory returns the latest CPUSet allocation records of the node from the oldest to the latest.
+func (c *resourceManager) GetAllocationHistory(nodeName string) []CPUSetAllocationRecord {
+	c.lock.Lock()
+	history := c.allocationHistories[nodeName]
+	c.lock.Unlock()
+	if history == nil {
+		return nil
+	}
+	return history.list()
+}
+
+func (c *resourceManager) GetTopologyHints(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions) (map[string][]topologyhint.NUMATopologyHint, error) {
+	topologyOptions := options.TopologyOptions
+	if len(topologyOptions.NUMANodeResources) == 0 {
+		return nil, fmt.Errorf("insufficient resources on NUMA Node")
+	}
+
+	totalAvailable, _, err := c.getAvailableNUMANodeResources(node.Name, topologyOptions, options.ReusableResources)
+	if err != nil {
+		return nil, err
+	}
+	c.holdBackReservedFullCores(node.Name, options, totalAvailable)
+
+	nodes := make([]int, 0, len(topologyOptions.NUMANodeResources))
+	for _, v := range topologyOptions.NUMANodeResources {
+		if len(options.DeviceNUMANodes) > 0 && !containsNUMANode(options.DeviceNUMANodes, v.Node) {
+			continue
+		}
+		nodes = append(nodes, v.Node)
+	}
+	result := generateResourceHints(nodes, options.Requests, totalAvailable, options.InterleaveMemory)
+	hints := make(map[string][]topologyhint.NUMATopologyHint)
+	for k, v := range result {
+		hints[k] = v
+	}
+	return hints, nil
+}
+
+func (c *resourceManager) Allocate(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions) (*PodAllocation, error) {
+	allocation := &PodAllocation{
+		UID:                pod.UID,
+		Namespace:          pod.Namespace,
+		Name:               pod.Name,
+		CPUExclusivePolicy: options.CPUExclusivePolicy,
+		QoSClass:           extension.GetPodQoSClassRaw(pod),
+	}
+	if options.UseReservedCPUs {
+		cpus, err := c.allocateReservedCPUSet(node, options)
+		if err != nil {
+			options.CPUBindFailureReason = cpuBindFailureReason(err)
+			return nil, err
+		}
+		allocation.CPUSet = cpus
+		allocation.UseReservedCPUs = true
+		return allocation, nil
+	}
+	if options.Hint.NUMANodeAffinity != nil {
+		resources, err := c.allocateResourcesByHint(node, pod, options)
+		if err != nil {
+			options.CPUBindFailureReason = cpuBindFailureReasonInsufficientNUMANode
+			return nil, err
+		}
+		allocation.NUMANodeResources = resources
+	}
+	if options.RequestCPUBind {
+		cpus, err := c.allocateCPUSet(node, pod, allocation.NUMANodeResources, options)
+		if err != nil {
+			options.CPUBindFailureReason = cpuBindFailureReason(err)
+			if !options.BestEffortCPUBind {
+				return nil, err
+			}
+			// the best-effort bound Pod runs in the CPU Shared Pool
+			allocation.CPUBindDegraded = true
+		} else {
+			allocation.CPUSet = cpus
+			if len(options.ContainerCPUBinds) > 0 {
+				allocation.ContainerCPUSets = splitContainerCPUSets(cpus, options.ContainerCPUBinds, options.TopologyOptions.CPUTopology)
+			}
+		}
+	} else if options.RequestSoftCPUBind {
+		cpus, err := c.allocatePreferredCPUSet(node, allocation.NUMANodeResources, options)
+		if err != nil {
+			return nil, err
+		}
+		allocation.PreferredCPUSet = cpus
+	}
+	allocation.SteadyStateNUMANodeResources = GetSteadyStateNUMANodeResources(pod, allocation)
+	return allocation, nil
+}
+
+// GetSteadyStateNUMANodeResources returns the NUMA Node resources the Pod keeps after its init containers completed.
+// The bound CPUs are kept for the whole lifetime of the Pod, so the CPU requested by init containers is not reclaimed.
+func GetSteadyStateNUMANodeResources(pod *corev1.Pod, allocation *PodAllocation) []NUMANodeResource {
+	if len(allocation.NUMANodeResources) == 0 {
+		return nil
+	}
+	initOnlyRequests := getPodInitOnlyRequests(pod)
+	if !allocation.CPUSet.IsEmpty() {
+		delete(initOnlyRequests, corev1.ResourceCPU)
+	}
+	if quotav1.IsZero(initOnlyRequests) {
+		return nil
+	}
+	return reclaimNUMANodeResources(allocation.NUMANodeResources, initOnlyRequests)
+}
+
+func (c *resourceManager) allocateResourcesByHint(node *corev1.Node, pod *corev1.Pod, options *ResourceOptions) ([]NUMANodeResource, error) {
+	if len(options.TopologyOptions.NUMANodeResources) == 0 {
+		return nil, fmt.Errorf("insufficient resources on NUMA Node")
+	}
+	for _, numaNode := range options.Hint.NUMANodeAffinity.GetBits() {
+		if containsNUMANode(options.DrainedNUMANodes, numaNode) {
+			return nil, fmt.Errorf("NUMA Node %d is drained for maintenance", numaNode)
+		}
+	}
+
+	totalAvailable, _, err := c.getAvailableNUMANodeResources(node.Name, options.TopologyOptions, options.ReusableResources)
+	if err != nil {
+		return nil, err
+	}
+	c.holdBackReservedFullCores(node.Name, options, totalAvailable)
+
+	var requests corev1.ResourceList
+	if options.RequestCPUBind {
+		requests = options.OriginalRequests.DeepCopy()
+	} else {
+		requests = options.Requests.DeepCopy()
+	}
+
+	numaNodes := sortNUMANodesByHintAllocateOrder(options.Hint.NUMANodeAffinity.GetBits(), options.HintAllocateOrder,
+		options.TopologyOptions.NUMANodeResources, totalAvailable, requests)
+	if options.isAmplifiedCPUBind() {
+		// the original CPUs are split over the NUMA Nodes by the physical CPUs they can still bind
+		deamplifyAvailableCPUs(totalAvailable, options.TopologyOptions.AmplificationRatios[corev1.ResourceCPU])
+	}
+	intersectionResources := sets.NewString()
+	var result []NUMANodeResource
+	for _, numaNodeID := range numaNodes {
+		allocatable := totalAvailable[numaNodeID]
+		r := NUMANodeResource{
+			Node:      numaNodeID,
+			Resources: corev1.ResourceList{},
+		}
+		for resourceName, quantity := range requests {
+			if allocatableQuantity, ok := allocatable[resourceName]; ok {
+				intersectionResources.Insert(string(resourceName))
+				var allocated resource.Quantity
+				allocatable[resourceName], requests[resourceName], allocated = allocateRes(allocatableQuantity, quantity)
+				if !allocated.IsZero() {
+					r.Resources[resourceName] = allocated
+				}
+			}
+		}
+		if !quotav1.IsZero(r.Resources) {
+			result = append(result, r)
+		}
+		if quotav1.IsZero(requests) {
+			break
+		}
+	}
+
+	var reasons []string
+	for resourceName, quantity := range requests {
+		if intersectionResources.Has(string(resourceName)) {
+			if !quantity.IsZero() {
+				reasons = append(reasons, fmt.Sprintf("Insufficient NUMA %s", resourceName))
+			}
+		}
+	}
+	if len(reasons) > 0 {
+		return nil, errors.New(strings.Join(reasons, ", "))
+	}
+	return result, nil
+}
+
+// sortNUMANodesByHintAllocateOrder sorts the NUMA Nodes of the hint by the ratio of the available requested resources.
+// The NUMA Nodes keep the order of the NUMA Node ID if the ratios are equal.
+func sortNUMANodesByHintAllocateOrder(numaNodes []int, order schedulingconfig.NUMAHintAllocateOrder,
+	numaNodeResources []NUMANodeResource, totalAvailable map[int]corev1.ResourceList, requests corev1.ResourceList) []int {
+	if len(numaNodes) <= 1 ||
+		(order != schedulingconfig.NUMAHintAllocateOrderMostAllocatedFirst && order != schedulingconfig.NUMAHintAllocateOrderLeastAllocatedFirst) {
+		return numaNodes
+	}
+
+	freeRatios := make(map[int]float64, len(numaNodes))
+	for _, numaNodeResource := range numaNodeResources {
+		var sum float64
+		var count int
+		for resourceName := range requests {
+			total := numaNodeResource.Resources[resourceName]
+			if total.IsZero() {
+				continue
+			}
+			available := totalAvailable[numaNodeResource.Node][resourceName]
+			sum += float64(available.MilliValue()) / float64(total.MilliValue())
+			count++
+		}
+		if count > 0 {
+			freeRatios[numaNodeResource.Node] = sum / float64(count)
+		}
+	}
+
+	sorted := make([]int, len(numaNodes))
+	copy(sorted, numaNodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if order == schedulingconfig.NUMAHintAllocateOrderMostAllocatedFirst {
+			return freeRatios[sorted[i]] < freeRatios[sorted[j]]
+		}
+		return freeRatios[sorted[i]] > freeRatios[sorted[j]]
+	})
+	return sorted
+}
+
+// deamplifyAvailableCPUs converts the available amplified CPUs of the NUMA Nodes to the whole physical CPUs,
+// each of which costs the amplified CPUs accounted for a bound CPU.
+func deamplifyAvailableCPUs(totalAvailable map[int]corev1.ResourceList, ratio extension.Ratio) {
+	for numaNode, available := range totalAvailable {
+		quantity, ok := available[corev1.ResourceCPU]
+		if !ok {
+			continue
+		}
+		numCPUs := deamplifyCPUs(quantity.MilliValue(), ratio)
+		available[corev1.ResourceCPU] = *resource.NewQuantity(numCPUs, resource.DecimalSI)
+		totalAvailable[numaNode] = available
+	}
+}
+
+// deamplifyCPUs returns the max number of the physical CPUs whose amplified milli CPUs are not more than the given.
+func deamplifyCPUs(amplifiedMilliCPU int64, ratio extension.Ratio) int64 {
+	if amplifiedMilliCPU <= 0 {
+		return 0
+	}
+	numCPUs := int64(float64(amplifiedMilliCPU) / float64(ratio) / 1000)
+	// correct the rounding error of the float division against the ceiling of extension.Amplify
+	for extension.Amplify((numCPUs+1)*1000, ratio) <= amplifiedMilliCPU {
+		numCPUs++
+	}
+	for numCPUs > 0 && extension.Amplify(numCPUs*1000, ratio) > amplifiedMilliCPU {
+		numCPUs--
+	}
+	return numCPUs
+}
+
+func allocateRes(available, request resource.Quantity) (resource.Quantity, resource.Quantity, resource.Quantity) {
+	switch available.Cmp(request) {
+	case 1:
+		available = available.DeepCopy()
+		available.Sub(request)
+		allocated := request.DeepCopy()
+		request.Sub(request)
+		return available, request, allocated
+	case -1:
+		request = request.DeepCopy()
+		request.Sub(available)
+		allocated := available.DeepCopy()
+		available.Sub(available)
+		return available, request, allocated
+	default:
+		request = request.DeepCopy()
+		request.Sub(request)
+		return request, request, available.DeepCopy()
+	}
+}
+
+func (c *resourceManager) allocateCPUSet(node *corev1.Node, pod *corev1.Pod, allocatedNUMANodes []NUMANodeResource, options *ResourceOptions) (cpuset.CPUSet, error) {
+	empty := cpuset.CPUSet{}
+	availableCPUs, allocatedCPUs, err := c.GetAvailableCPUs(node.Name, options.PreferredCPUs)
+	if err != nil {
+		return empty, err
+	}
+
+	topologyOptions := &options.TopologyOptions
+	if numCores := options.numHeldBackFullCores(); numCores > 0 {
+		reservedCPUs := selectReservedFullCores(topologyOptions.CPUTopology, availableCPUs, allocatedCPUs, numCores)
+		availableCPUs = availableCPUs.Difference(reservedCPUs)
+	}
+	// the isolated CPUs are only pinned by the LSR and LSE Pods
+	if !options.AllowIsolatedCPUs && !topologyOptions.IsolatedCPUs.IsEmpty() {
+		availableCPUs = availableCPUs.Difference(topologyOptions.IsolatedCPUs)
+	}
+	if options.RequiredCPUBindPolicy {
+		cpuDetails := topologyOptions.CPUTopology.CPUDetails.KeepOnly(availableCPUs)
+		availableCPUs = filterAvailableCPUsByRequiredCPUBindPolicy(options.CPUBindPolicy, availableCPUs, cpuDetails, topologyOptions.CPUTopology.CPUsPerCore())
+	}
+
+	if len(options.BoundPodsSaturatedNUMANodes) > 0 {
+		availableCPUs = availableCPUs.Difference(topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(options.BoundPodsSaturatedNUMANodes...))
+	}
+	if len(options.DrainedNUMANodes) > 0 {
+		availableCPUs = availableCPUs.Difference(topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(options.DrainedNUMANodes...))
+	}
+	if len(options.DeviceNUMANodes) > 0 {
+		availableCPUs = availableCPUs.Intersection(topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(options.DeviceNUMANodes...))
+	}
+
+	if !options.SpreadOccupiedCPUs.IsEmpty() {
+		// take the CPUs in the L3 cache domains apart from the replicas first
+		result, err := c.takeSocketBalancedCPUSet(node, availableCPUs.Difference(options.SpreadOccupiedCPUs), allocatedCPUs, allocatedNUMANodes, options)
+		if err == nil || options.RequiredIntraNodeSpread {
+			return result, err
+		}
+		klog.V(5).Infof("failed to spread Pod %s/%s across L3 domains on node %s, fallback to the other CPUs, err: %v",
+			pod.Namespace, pod.Name, node.Name, err)
+	}
+	return c.takeSocketBalancedCPUSet(node, availableCPUs, allocatedCPUs, allocatedNUMANodes, options)
+}
+
+// allocateReservedCPUSet allocates the CPUs of the System QoS Pod from the reserved CPUs of the node,
+// which are accounted apart from the CPUs allocated to the workloads.
+func (c *resourceManager) allocateReservedCPUSet(node *corev1.Node, options *ResourceOptions) (cpuset.CPUSet, error) {
+	topologyOptions := &options.TopologyOptions
+	if topologyOptions.CPUTopology == nil {
+		return cpuset.CPUSet{}, errors.New(ErrNotFoundCPUTopology)
+	}
+	if !topologyOptions.CPUTopology.IsValid() {
+		return cpuset.CPUSet{}, errors.New(ErrInvalidCPUTopology)
+	}
+	nodeAllocation := c.getOrCreateNodeAllocation(node.Name)
+	nodeAllocation.lock.RLock()
+	availableCPUs, allocatedCPUs := nodeAllocation.getAvailableReservedCPUs(topologyOptions.MaxRefCount, topologyOptions.SystemReservedCPUs)
+	nodeAllocation.lock.RUnlock()
+	if availableCPUs.Size() < options.NumCPUsNeeded {
+		return cpuset.CPUSet{}, fmt.Errorf("not enough reserved cpus available to satisfy request")
+	}
+	return c.takeCPUSet(node, availableCPUs, allocatedCPUs, nil, options)
+}
+
+func (c *resourceManager) takeCPUSet(node *corev1.Node, availableCPUs cpuset.CPUSet, allocatedCPUs CPUDetails, allocatedNUMANodes []NUMANodeResource, options *ResourceOptions) (cpuset.CPUSet, error) {
+	empty := cpuset.CPUSet{}
+	if availableCPUs.Size() < options.NumCPUsNeeded {
+		return empty, fmt.Errorf("not enough cpus available to satisfy request")
+	}
+
+	topologyOptions := &options.TopologyOptions
+	result := cpuset.CPUSet{}
+	numaAllocateStrategy := GetNUMAAllocateStrategy(node, c.numaAllocateStrategy)
+	if options.NUMAAllocateStrategy != "" {
+		numaAllocateStrategy = options.NUMAAllocateStrategy
+	}
+	numCPUsNeeded := options.NumCPUsNeeded
+	if len(allocatedNUMANodes) > 0 {
+		for _, numaNode := range allocatedNUMANodes {
+			cpusInNUMANode := topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(numaNode.Node)
+			availableCPUsInNUMANode := availableCPUs.Intersection(cpusInNUMANode)
+
+			numCPUs := availableCPUsInNUMANode.Size()
+			quantity := numaNode.Resources[corev1.ResourceCPU]
+			nodeNumCPUsNeeded := int(quantity.MilliValue() / 1000)
+			if nodeNumCPUsNeeded < numCPUs {
+				numCPUs = nodeNumCPUsNeeded
+			}
+			// the NUMA Node resources also cover the containers running in the CPU Shared Pool
+			if remaining := numCPUsNeeded - result.Size(); len(options.ContainerCPUBinds) > 0 && remaining < numCPUs {
+				numCPUs = remaining
+			}
+
+			cpus, err := takePreferredCPUs(
+				topologyOptions.CPUTopology,
+				topologyOptions.MaxRefCount,
+				availableCPUsInNUMANode,
+				options.PreferredCPUs,
+				allocatedCPUs,
+				numCPUs,
+				options.CPUBindPolicy,
+				options.CPUExclusivePolicy,
+				numaAllocateStrategy,
+			)
+			if err != nil {
+				return empty, err
+			}
+
+			result = result.Union(cpus)
+		}
+		numCPUsNeeded -= result.Size()
+		if numCPUsNeeded != 0 {
+			return empty, fmt.Errorf("not enough cpus available to satisfy request")
+		}
+	}
+
+	if numCPUsNeeded > 0 {
+		availableCPUs = availableCPUs.Difference(result)
+		remainingCPUs, err := takePreferredCPUs(
+			topologyOptions.CPUTopology,
+			topologyOptions.MaxRefCount,
+			availableCPUs,
+			options.PreferredCPUs,
+			allocatedCPUs,
+			numCPUsNeeded,
+			options.CPUBindPolicy,
+			options.CPUExclusivePolicy,
+			numaAllocateStrategy,
+		)
+		if err != nil {
+			return empty, err
+		}
+		result = result.Union(remainingCPUs)
+	}
+
+	if options.RequiredCPUBindPolicy {
+		err := satisfiedRequiredCPUBindPolicy(options.CPUBindPolicy, result, topologyOptions.CPUTopology)
+		if err != nil {
+			return empty, err
+		}
+	}
+
+	return result, nil
+}
+
+// allocatePreferredCPUSet picks the preferred CPUs of the soft bound Pod from the CPUs that are not bound by other Pods.
+// The preferred CPUs can be shared by any number of soft bound Pods, so the least shared CPUs are picked first.
+// The soft binding is best-effort, fewer CPUs are picked if the available CPUs are not enough.
+func (c *resourceManager) allocatePreferredCPUSet(node *corev1.Node, allocatedNUMANodes []NUMANodeResource, options *ResourceOptions) (cpuset.CPUSet, error) {
+	empty := cpuset.CPUSet{}
+	availableCPUs, _, err := c.GetAvailableCPUs(node.Name, cpuset.CPUSet{})
+	if err != nil {
+		return empty, err
+	}
+
+	topologyOptions := &options.TopologyOptions
+	availableCPUs = availableCPUs.Difference(topologyOptions.IsolatedCPUs)
+	if len(options.DrainedNUMANodes) > 0 {
+		availableCPUs = availableCPUs.Difference(topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(options.DrainedNUMANodes...))
+	}
+	if len(allocatedNUMANodes) > 0 {
+		numaNodes := make([]int, 0, len(allocatedNUMANodes))
+		for _, numaNode := range allocatedNUMANodes {
+			numaNodes = append(numaNodes, numaNode.Node)
+		}
+		availableCPUs = availableCPUs.Intersection(topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(numaNodes...))
+	}
+
+	numCPUsNeeded := options.NumCPUsNeeded
+	if availableCPUs.Size() < numCPUsNeeded {
+		numCPUsNeeded = availableCPUs.Size()
+	}
+	if numCPUsNeeded == 0 {
+		return empty, nil
+	}
+
+	numaAllocateStrategy := GetNUMAAllocateStrategy(node, c.numaAllocateStrategy)
+	if options.NUMAAllocateStrategy != "" {
+		numaAllocateStrategy = options.NUMAAllocateStrategy
+	}
+	softAllocatedCPUs := c.getSoftAllocatedCPUs(node.Name)
+	// the preferred CPUs have no upper limit of the reference count, it only orders the CPUs by the count.
+	return takeCPUs(
+		topologyOptions.CPUTopology,
+		math.MaxInt32,
+		availableCPUs,
+		softAllocatedCPUs,
+		numCPUsNeeded,
+		options.CPUBindPolicy,
+		schedulingconfig.CPUExclusivePolicyNone,
+		numaAllocateStrategy,
+	)
+}
+
+func (c *resourceManager) getSoftAllocatedCPUs(nodeName string) CPUDetails {
+	nodeAllocation := c.getOrCreateNodeAllocation(nodeName)
+	nodeAllocation.lock.RLock()
+	defer nodeAllocation.lock.RUnlock()
+	return nodeAllocation.softAllocatedCPUs.Clone()
+}
+
+func (c *resourceManager) Update(nodeName string, allocation *PodAllocation) {
+	topologyOptions := c.topologyOptionsManager.GetTopologyOptions(nodeName)
+	if topologyOptions.CPUTopology == nil || !topologyOptions.CPUTopology.IsValid() {
+		return
+	}
+
+	// the allocations restored from the Pods are not marked, and only the System QoS Pods can be pinned on the reserved CPUs
+	if !allocation.CPUSet.IsEmpty() && allocation.CPUSet.IsSubsetOf(topologyOptions.SystemReservedCPUs) {
+		allocation.UseReservedCPUs = true
+	}
+
+	nodeAllocation := c.lockNodeAllocation(nodeName)
+	defer nodeAllocation.lock.Unlock()
+
+	nodeAllocation.update(allocation, topologyOptions.CPUTopology)
+	recordNUMANodeFragmentation(nodeName, calculateNUMANodeFragmentation(topologyOptions.CPUTopology, nodeAllocation.allocatedCPUs, topologyOptions.ReservedCPUs))
+	recordCPURefCountUsage(nodeName, calculateCPURefCountUsage(&topologyOptions, nodeAllocation))
+}
+
+func (c *resourceManager) Release(nodeName string, podUID types.UID) {
+	c.release(nodeName, podUID)
+	c.freeNodeAllocationIfDrained(nodeName)
+}
+
+func (c *resourceManager) release(nodeName string, podUID types.UID) {
+	nodeAllocation := c.lockNodeAllocation(nodeName)
+	defer nodeAllocation.lock.Unlock()
+	nodeAllocation.release(podUID)
+
+	topologyOptions := c.topologyOptionsManager.GetTopologyOptions(nodeName)
+	if topologyOptions.CPUTopology != nil && topologyOptions.CPUTopology.IsValid() {
+		recordNUMANodeFragmentation(nodeName, calculateNUMANodeFragmentation(topologyOptions.CPUTopology, nodeAllocation.allocatedCPUs, topologyOptions.ReservedCPUs))
+		recordCPURefCountUsage(nodeName, calculateCPURefCountUsage(&topologyOptions, nodeAllocation))
+	}
+}
+
+// freeNodeAllocationIfDrained frees the NUMA state of the node after it is drained in the coordinated mode for
+// maintenance, so that no stale allocation is carried over when the node comes back.
+func (c *resourceManager) freeNodeAllocationIfDrained(nodeName string) {
+	if c.nodeLister == nil {
+		return
+	}
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil || !extension.IsNodeCoordinatedDraining(node) {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	nodeAllocation := c.nodeAllocations[nodeName]
+	if nodeAllocation == nil {
+		return
+	}
+	// hold the node lock until the allocation is deleted, so that no pod can be added between the check and the delete,
+	// and mark it freed for the writers already holding it, which lock the new allocation of the node instead
+	nodeAllocation.lock.Lock()
+	defer nodeAllocation.lock.Unlock()
+	if len(nodeAllocation.allocatedPods) > 0 {
+		return
+	}
+	nodeAllocation.freed = true
+	delete(c.nodeAllocations, nodeName)
+	topologyOptions := c.topologyOptionsManager.GetTopologyOptions(nodeName)
+	if topologyOptions.CPUTopology != nil {
+		deleteNodeMetrics(nodeName, topologyOptions.CPUTopology.CPUDetails.NUMANodes())
+	}
+	klog.V(4).Infof("free the NUMA state of node %s drained for maintenance", nodeName)
+}
+
+func (c *resourceManager) GetAllocatedCPUSet(nodeName string, podUID types.UID) (cpuset.CPUSet, bool) {
+	nodeAllocation := c.getOrCreateNodeAllocation(nodeName)
+	nodeAllocation.lock.RLock()
+	defer nodeAllocation.lock.RUnlock()
+
+	return nodeAllocation.getCPUs(podUID)
+}
+
+func (c *resourceManager) GetAvailableCPUs(nodeName string, preferredCPUs cpuset.CPUSet) (availableCPUs cpuset.CPUSet, allocated CPUDetails, err error) {
+	topologyOptions := c.topologyOptionsManager.GetTopologyOptions(nodeName)
+	if topologyOptions.CPUTopology == nil {
+		return cpuset.NewCPUSet(), nil, errors.New(ErrNotFoundCPUTopology)
+	}
+	if !topologyOptions.CPUTopology.IsValid() {
+		return cpuset.NewCPUSet(), nil, errors.New(ErrInvalidCPUTopology)
+	}
+
+	allocation := c.getOrCreateNodeAllocation(nodeName)
+	allocation.lock.RLock()
+	defer allocation.lock.RUnlock()
+	availableCPUs, allocated = allocation.getAvailableCPUs(topologyOptions.CPUTopology, topologyOptions.MaxRefCount, topologyOptions.ReservedCPUs, preferredCPUs)
+	return availableCPUs, allocated, nil
+}
+
+func (c *resourceManager) GetNodeAllocation(nodeName string) *NodeAllocation {
+	return c.getOrCreateNodeAllocation(nodeName)
+}
+
+func (c *resourceManager) getAvailableNUMANodeResources(nodeName string, topologyOptions TopologyOptions, reusableResources map[int]corev1.ResourceList) (totalAvailable, totalAllocated map[int]corev1.ResourceList, err error) {
+	nodeAllocation := c.getOrCreateNodeAllocation(nodeName)
+	nodeAllocation.lock.RLock()
+	defer nodeAllocation.lock.RUnlock()
+	totalAvailable, totalAllocated = nodeAllocation.getAvailableNUMANodeResources(topologyOptions, reusableResources)
+	return totalAvailable, totalAllocated, nil
+}
+
+// holdBackReservedFullCores deducts the CPUs of the reserved full cores from the available NUMA Node resources.
+func (c *resourceManager) holdBackReservedFullCores(nodeName string, options *ResourceOptions, totalAvailable map[int]corev1.ResourceList) {
+	numCores := options.numHeldBackFullCores()
+	if numCores <= 0 {
+		return
+	}
+	availableCPUs, allocatedCPUs, err := c.GetAvailableCPUs(nodeName, options.PreferredCPUs)
+	if err != nil {
+		return
+	}
+	cpuTopology := options.TopologyOptions.CPUTopology
+	reservedCPUs := cpuTopology.CPUDetails.KeepOnly(selectReservedFullCores(cpuTopology, availableCPUs, allocatedCPUs, numCores))
+	amplificationRatio := options.TopologyOptions.AmplificationRatios[corev1.ResourceCPU]
+	for _, numaNode := range reservedCPUs.NUMANodes().ToSliceNoSort() {
+		available, ok := totalAvailable[numaNode]
+		if !ok {
+			continue
+		}
+		cpu := extension.Amplify(int64(reservedCPUs.CPUsInNUMANodes(numaNode).Size()*1000), amplificationRatio)
+		totalAvailable[numaNode] = quotav1.SubtractWithNonNegativeResult(available, corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewMilliQuantity(cpu, resource.DecimalSI),
+		})
+	}
+}
+
+// selectReservedFullCores selects up to numCores physical cores whose logical CPUs are all available
+// and not allocated yet. The cores with larger IDs are preferred to keep the selection stable.
+func selectReservedFullCores(cpuTopology *CPUTopology, availableCPUs cpuset.CPUSet, allocatedCPUs CPUDetails, numCores int) cpuset.CPUSet {
+	if numCores <= 0 || cpuTopology == nil || !cpuTopology.IsValid() {
+		return cpuset.NewCPUSet()
+	}
+	details := cpuTopology.CPUDetails
+	cores := details.KeepOnly(availableCPUs).Cores().ToSlice()
+	builder := cpuset.NewCPUSetBuilder()
+	for i := len(cores) - 1; i >= 0 && numCores > 0; i-- {
+		cpus := details.CPUsInCores(cores[i])
+		if !cpus.IsSubsetOf(availableCPUs) {
+			continue
+		}
+		if allocatedCPUs.KeepOnly(cpus).CPUs().Size() > 0 {
+			continue
+		}
+		builder.Add(cpus.ToSliceNoSort()...)
+		numCores--
+	}
+	return builder.Result()
+}
+
+func generateResourceHints(numaNodes []int, podRequests corev1.ResourceList, totalAvailable map[int]corev1.ResourceList, preferAllNUMANodes bool) map[string][]topologyhint.NUMATopologyHint {
+	// Initialize minAffinitySize to include all NUMA Cells.
+	minAffinitySize := len(numaNodes)
+
+	hints := map[string][]topologyhint.NUMATopologyHint{}
+	bitmask.IterateBitMasks(numaNodes, func(mask bitmask.BitMask) {
+		maskBits := mask.GetBits()
+
+		available := make(corev1.ResourceList)
+		for _, nodeID := range maskBits {
+			available = quotav1.Add(available, totalAvailable[nodeID])
+		}
+		if satisfied, _ := quotav1.LessThanOrEqual(podRequests, available); !satisfied {
+			return
+		}
+
+		// set the minimum amount of NUMA nodes that can satisfy the resources requests
+		if mask.Count() < minAffinitySize {
+			minAffinitySize = mask.Count()
+		}
+
+		for resourceName := range podRequests {
+			if _, ok := available[resourceName]; !ok {
+				continue
+			}
+			if _, ok := hints[string(resourceName)]; !ok {
+				hints[string(resourceName)] = []topologyhint.NUMATopologyHint{}
+			}
+			hints[string(resourceName)] = append(hints[string(resourceName)], topologyhint.NUMATopologyHint{
+				NUMANodeAffinity: mask,
+				Preferred:        false,
+			})
+		}
+	})
+
+	// update hints preferred according to multiNUMAGroups, in case when it wasn't provided, the default
+	// behavior to prefer the minimal amount of NUMA nodes will be used
+	preferredAffinitySize := minAffinitySize
+	if preferAllNUMANodes {
+		// the Pod interleaving the memory prefers spreading over all the NUMA nodes
+		preferredAffinitySize = len(numaNodes)
+	}
+	for resourceName := range podRequests {
+		for i, hint := range hints[string(resourceName)] {
+			hints[string(resourceName)][i].Preferred = len(hint.NUMANodeAffinity.GetBits()) == preferredAffinitySize
+		}
+	}
+
+	return hints
+}
+
+func filterAvailableCPUsByRequiredCPUBindPolicy(policy schedulingconfig.CPUBindPolicy, availableCPUs cpuset.CPUSet, cpuDetails CPUDetails, cpusPerCore int) cpuset.CPUSet {
+	if policy == schedulingconfig.CPUBindPolicyFullPCPUs {
+		cpuDetails.KeepOnly(availableCPUs)
+		cpus := cpuDetails.CPUsInCores(cpuDetails.Cores().ToSliceNoSort()...)
+		if cpus.Size()%cpusPerCore != 0 {
+			return availableCPUs
+		}
+		return cpus
+	}
+	return availableCPUs
+}
+
+func satisfiedRequiredCPUBindPolicy(policy schedulingconfig.CPUBindPolicy, cpus cpuset.CPUSet, topology *CPUTopology) error {
+	satisfied := true
+	if policy == schedulingconfig.CPUBindPolicyFullPCPUs {
+		satisfied = determineFullPCPUs(cpus, topology.CPUDetails, topology.CPUsPerCore())
+	} else if policy == schedulingconfig.CPUBindPolicySpreadByPCPUs {
+		satisfied = determineSpreadByPCPUs(cpus, topology.CPUDetails)
+	}
+	if !satisfied {
+		return fmt.Errorf("%w %s", errRequiredCPUBindPolicyUnsatisfied, policy)
+	}
+	return nil
+}
+
+func determineFullPCPUs(cpus cpuset.CPUSet, details CPUDetails, cpusPerCore int) bool {
+	details = details.KeepOnly(cpus)
+	return details.Cores().Size()*cpusPerCore == cpus.Size()
+}
+
+func determineSpreadByPCPUs(cpus cpuset.CPUSet, details CPUDetails) bool {
+	details = details.KeepOnly(cpus)
+	return details.Cores().Size() == cpus.Size()
+}
+
+func containsNUMANode(numaNodes []int, numaNode int) bool {
+	for _, v := range numaNodes {
+		if v == numaNode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,2055 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	"github.com/koordinator-sh/koordinator/pkg/util/topologyhint"
+)
+
+func TestResourceManagerAllocate(t *testing.T) {
+	tests := []struct {
+		name                string
+		pod                 *corev1.Pod
+		options             *ResourceOptions
+		amplificationRatios map[corev1.ResourceName]apiext.Ratio
+		isolatedCPUs        cpuset.CPUSet
+		allocated           *PodAllocation
+		want                *PodAllocation
+		wantErr             bool
+	}{
+		{
+			name: "allocate with non-existing resources in NUMA",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:  4,
+				RequestCPUBind: false,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:       resource.MustParse("4"),
+					apiext.ResourceGPUMemory: resource.MustParse("10Gi"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with insufficient resources",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:  4,
+				RequestCPUBind: false,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("54"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "allocate memory bandwidth across NUMA nodes",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:  4,
+				RequestCPUBind: false,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:             resource.MustParse("4"),
+					apiext.ResourceMemoryBandwidth: resource.MustParse("30000"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0, 1)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:             resource.MustParse("4"),
+							apiext.ResourceMemoryBandwidth: resource.MustParse("20000"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							apiext.ResourceMemoryBandwidth: resource.MustParse("10000"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with insufficient memory bandwidth",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:  4,
+				RequestCPUBind: false,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:             resource.MustParse("4"),
+					apiext.ResourceMemoryBandwidth: resource.MustParse("30000"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "allocate with required CPUBindPolicyFullPCPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0-3"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicyFullPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("4-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0-3"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(4, resource.DecimalSI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate with required CPUBindPolicyFullPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("1,3,5,7-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "allocate with required CPUBindPolicySpreadByPCPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0,2,4,6"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate CPUBindPolicySpreadByPCPUs with reserved full cores",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:     4,
+				RequestCPUBind:    true,
+				CPUBindPolicy:     schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				ReservedFullCores: 50,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0-3"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate CPUBindPolicySpreadByPCPUs with reserved full cores",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:     6,
+				RequestCPUBind:    true,
+				CPUBindPolicy:     schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				ReservedFullCores: 50,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("6"),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "allocate CPUBindPolicyFullPCPUs with reserved full cores",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				ReservedFullCores:     52,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0-3"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate CPUBindPolicyFullPCPUs without isolated cpus",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:  4,
+				RequestCPUBind: true,
+				CPUBindPolicy:  schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			isolatedCPUs: cpuset.MustParse("0-3"),
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("52-55"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate required CPUBindPolicyFullPCPUs without isolated cpus",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			isolatedCPUs: cpuset.MustParse("0-3"),
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("52-55"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate CPUBindPolicyFullPCPUs with isolated cpus",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:     4,
+				RequestCPUBind:    true,
+				AllowIsolatedCPUs: true,
+				CPUBindPolicy:     schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			isolatedCPUs: cpuset.MustParse("0-3"),
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0-3"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicySpreadByPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("1,3,5,7-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0,2,4,6"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(4, resource.DecimalSI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate with required CPUBindPolicySpreadByPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("4-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "allocate with required CPUBindPolicySpreadByPCPUs and amplified requests",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("6"),
+				},
+				OriginalRequests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			amplificationRatios: map[corev1.ResourceName]apiext.Ratio{
+				corev1.ResourceCPU: 1.5,
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0,2,4,6"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicySpreadByPCPUs and allocated and amplified requests",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("6"),
+				},
+				OriginalRequests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			amplificationRatios: map[corev1.ResourceName]apiext.Ratio{
+				corev1.ResourceCPU: 1.5,
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("1,3,5,7-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0,2,4,6"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate with CPU Share and allocated and amplified ratios",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        false,
+				RequiredCPUBindPolicy: false,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				OriginalRequests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			amplificationRatios: map[corev1.ResourceName]apiext.Ratio{
+				corev1.ResourceCPU: 1.5,
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("0-49,52-101"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("50"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("50"),
+						},
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "allocate by numa hint on mixed cpuset/share node",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         8,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("8"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0, 1)
+						return mask
+					}(),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("0-43,53-96"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+				},
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("44-47,98-101"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(4, resource.DecimalSI),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(4, resource.DecimalSI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with init containers requesting more than app containers",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("8"),
+									corev1.ResourceMemory: resource.MustParse("16Gi"),
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("4"),
+									corev1.ResourceMemory: resource.MustParse("8Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			options: &ResourceOptions{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("8"),
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+					},
+				},
+				SteadyStateNUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    *resource.NewQuantity(4, resource.DecimalSI),
+							corev1.ResourceMemory: *resource.NewQuantity(8*1024*1024*1024, resource.BinarySI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate multi-NUMA-node hint by the order of NUMA Node ID",
+			pod:  &corev1.Pod{},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("20"),
+						},
+					},
+				},
+			},
+			options: &ResourceOptions{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("60"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0, 1)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(8, resource.DecimalSI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate multi-NUMA-node hint with MostAllocatedFirst",
+			pod:  &corev1.Pod{},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("20"),
+						},
+					},
+				},
+			},
+			options: &ResourceOptions{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("60"),
+				},
+				HintAllocateOrder: schedulingconfig.NUMAHintAllocateOrderMostAllocatedFirst,
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0, 1)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(32, resource.DecimalSI),
+						},
+					},
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(28, resource.DecimalSI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate multi-NUMA-node hint with LeastAllocatedFirst",
+			pod:  &corev1.Pod{},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("20"),
+						},
+					},
+				},
+			},
+			options: &ResourceOptions{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("60"),
+				},
+				HintAllocateOrder: schedulingconfig.NUMAHintAllocateOrderLeastAllocatedFirst,
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0, 1)
+						return mask
+					}(),
+				},
+			},
+			want: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(8, resource.DecimalSI),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate preferred CPUs for soft bound Pod",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:      2,
+				RequestSoftCPUBind: true,
+				CPUBindPolicy:      schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1500m"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("52-101"),
+			},
+			want: &PodAllocation{
+				PreferredCPUSet: cpuset.MustParse("0-1"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate preferred CPUs for soft bound Pod with soft allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:      2,
+				RequestSoftCPUBind: true,
+				CPUBindPolicy:      schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:             "123456",
+				Name:            "test-xxx",
+				Namespace:       "default",
+				PreferredCPUSet: cpuset.MustParse("0-1"),
+			},
+			want: &PodAllocation{
+				PreferredCPUSet: cpuset.MustParse("2-3"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate preferred CPUs for soft bound Pod with insufficient CPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:      4,
+				RequestSoftCPUBind: true,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("0-101"),
+			},
+			want: &PodAllocation{
+				PreferredCPUSet: cpuset.MustParse("102-103"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicyFullPCPUs and soft allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				Hint: topologyhint.NUMATopologyHint{
+					NUMANodeAffinity: func() bitmask.BitMask {
+						mask, _ := bitmask.NewBitMask(0)
+						return mask
+					}(),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:             "123456",
+				Name:            "test-xxx",
+				Namespace:       "default",
+				PreferredCPUSet: cpuset.MustParse("0-51"),
+			},
+			want: &PodAllocation{
+				CPUSet: cpuset.MustParse("0-3"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "degrade the best-effort CPU binding with insufficient CPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:     4,
+				RequestCPUBind:    true,
+				BestEffortCPUBind: true,
+				CPUBindPolicy:     schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("0-103"),
+			},
+			want: &PodAllocation{
+				CPUBindDegraded: true,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tom := NewTopologyOptionsManager()
+			tom.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 26, 2)
+				options.IsolatedCPUs = tt.isolatedCPUs
+				options.NUMANodeResources = []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:             resource.MustParse("52"),
+							corev1.ResourceMemory:          resource.MustParse("128Gi"),
+							apiext.ResourceMemoryBandwidth: resource.MustParse("20000"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:             resource.MustParse("52"),
+							corev1.ResourceMemory:          resource.MustParse("128Gi"),
+							apiext.ResourceMemoryBandwidth: resource.MustParse("20000"),
+						},
+					},
+				}
+			})
+			tt.options.TopologyOptions = tom.GetTopologyOptions("test-node")
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("104"),
+						corev1.ResourceMemory: resource.MustParse("256Gi"),
+					},
+				},
+			}
+			apiext.SetNodeResourceAmplificationRatios(node, tt.amplificationRatios)
+			resourceManager := NewResourceManager(nil, schedulingconfig.NUMALeastAllocated, tom)
+			if tt.allocated != nil {
+				resourceManager.Update(node.Name, tt.allocated)
+			}
+			if tt.options.OriginalRequests == nil {
+				tt.options.OriginalRequests = tt.options.Requests.DeepCopy()
+			}
+			assert.NoError(t, AmplifyNUMANodeResources(node, &tt.options.TopologyOptions))
+
+			got, err := resourceManager.Allocate(node, tt.pod, tt.options)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("wantErr %v but got %v", tt.wantErr, err != nil)
+			}
+			assert.Equal(t, tt.want, got)
+			if got != nil && got.CPUBindDegraded {
+				assert.Equal(t, cpuBindFailureReasonInsufficientCPUs, tt.options.CPUBindFailureReason)
+			}
+		})
+	}
+}
+
+func TestResourceManagerGetTopologyHint(t *testing.T) {
+	tests := []struct {
+		name                string
+		pod                 *corev1.Pod
+		options             *ResourceOptions
+		amplificationRatios map[corev1.ResourceName]apiext.Ratio
+		numaNodeResources   []NUMANodeResource
+		allocated           *PodAllocation
+		want                map[string][]topologyhint.NUMATopologyHint
+		wantErr             bool
+	}{
+		{
+			name: "allocate with required CPUBindPolicyFullPCPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "interleaved memory prefers all NUMA Nodes",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				InterleaveMemory: true,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: false,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+				},
+				string(corev1.ResourceMemory): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: false,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicyFullPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("4-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate with required CPUBindPolicyFullPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicyFullPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("1,3,5,7-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicySpreadByPCPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allocate with required CPUBindPolicySpreadByPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("1,3,5,7-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate with required CPUBindPolicySpreadByPCPUs and allocated",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        true,
+				RequiredCPUBindPolicy: true,
+				CPUBindPolicy:         schedulingconfig.CPUBindPolicySpreadByPCPUs,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("4-104"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("48"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("52"),
+						},
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "failed to allocate with CPU Share and allocated and amplified ratios",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				NumCPUsNeeded:         4,
+				RequestCPUBind:        false,
+				RequiredCPUBindPolicy: false,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+				OriginalRequests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			amplificationRatios: map[corev1.ResourceName]apiext.Ratio{
+				corev1.ResourceCPU: 1.5,
+			},
+			allocated: &PodAllocation{
+				UID:       "123456",
+				Name:      "test-xxx",
+				Namespace: "default",
+				CPUSet:    cpuset.MustParse("0-49,52-101"),
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("50"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("50"),
+						},
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+				},
+			},
+		},
+		{
+			name: "hold back reserved full cores",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				ReservedFullCores: 13,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("40"),
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "hints only NUMA nodes having both CPUs and GPUs",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:       resource.MustParse("16"),
+					apiext.ResourceNvidiaGPU: resource.MustParse("2"),
+				},
+			},
+			numaNodeResources: []NUMANodeResource{
+				{
+					Node: 0,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:       resource.MustParse("52"),
+						corev1.ResourceMemory:    resource.MustParse("128Gi"),
+						apiext.ResourceNvidiaGPU: resource.MustParse("0"),
+					},
+				},
+				{
+					Node: 1,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:       resource.MustParse("52"),
+						corev1.ResourceMemory:    resource.MustParse("128Gi"),
+						apiext.ResourceNvidiaGPU: resource.MustParse("2"),
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+				string(apiext.ResourceNvidiaGPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "hints NUMA nodes of both DRAM and slow memory",
+			pod:  &corev1.Pod{},
+			options: &ResourceOptions{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:        resource.MustParse("4"),
+					apiext.ResourceDRAM:       resource.MustParse("8Gi"),
+					apiext.ResourceSlowMemory: resource.MustParse("16Gi"),
+				},
+			},
+			numaNodeResources: []NUMANodeResource{
+				{
+					Node: 0,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:        resource.MustParse("52"),
+						corev1.ResourceMemory:     resource.MustParse("128Gi"),
+						apiext.ResourceDRAM:       resource.MustParse("128Gi"),
+						apiext.ResourceSlowMemory: resource.MustParse("0"),
+					},
+				},
+				{
+					Node: 1,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:        resource.MustParse("52"),
+						corev1.ResourceMemory:     resource.MustParse("128Gi"),
+						apiext.ResourceDRAM:       resource.MustParse("128Gi"),
+						apiext.ResourceSlowMemory: resource.MustParse("0"),
+					},
+				},
+				{
+					Node: 2,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:        resource.MustParse("0"),
+						corev1.ResourceMemory:     resource.MustParse("512Gi"),
+						apiext.ResourceDRAM:       resource.MustParse("0"),
+						apiext.ResourceSlowMemory: resource.MustParse("512Gi"),
+					},
+				},
+			},
+			want: map[string][]topologyhint.NUMATopologyHint{
+				string(corev1.ResourceCPU): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 2)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1, 2)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1, 2)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+				string(apiext.ResourceDRAM): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 2)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1, 2)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1, 2)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+				string(apiext.ResourceSlowMemory): {
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 2)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(1, 2)
+							return mask
+						}(),
+						Preferred: true,
+					},
+					{
+						NUMANodeAffinity: func() bitmask.BitMask {
+							mask, _ := bitmask.NewBitMask(0, 1, 2)
+							return mask
+						}(),
+						Preferred: false,
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tom := NewTopologyOptionsManager()
+			tom.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 26, 2)
+				options.NUMANodeResources = []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("52"),
+							corev1.ResourceMemory: resource.MustParse("128Gi"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("52"),
+							corev1.ResourceMemory: resource.MustParse("128Gi"),
+						},
+					},
+				}
+				if tt.numaNodeResources != nil {
+					options.NUMANodeResources = tt.numaNodeResources
+				}
+			})
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("104"),
+						corev1.ResourceMemory: resource.MustParse("256Gi"),
+					},
+				},
+			}
+			apiext.SetNodeResourceAmplificationRatios(node, tt.amplificationRatios)
+
+			resourceManager := NewResourceManager(nil, schedulingconfig.NUMALeastAllocated, tom)
+			if tt.allocated != nil {
+				resourceManager.Update(node.Name, tt.allocated)
+			}
+			tt.options.TopologyOptions = tom.GetTopologyOptions(node.Name)
+
+			if tt.options.OriginalRequests == nil {
+				tt.options.OriginalRequests = tt.options.Requests.DeepCopy()
+			}
+			assert.NoError(t, AmplifyNUMANodeResources(node, &tt.options.TopologyOptions))
+
+			got, err := resourceManager.GetTopologyHints(node, tt.pod, tt.options)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("wantErr %v but got %v", tt.wantErr, err != nil)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResourceManagerFreeDrainedNodeAllocation(t *testing.T) {
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "draining-node",
+				Labels: map[string]string{
+					apiext.LabelNodeCoordinatedDrain: "true",
+				},
+			},
+			Spec: corev1.NodeSpec{
+				Unschedulable: true,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "normal-node",
+			},
+		},
+	}
+	objects := make([]runtime.Object, 0, len(nodes))
+	for _, node := range nodes {
+		objects = append(objects, node)
+	}
+	informerFactory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(objects...), 0)
+	tom := NewTopologyOptionsManager()
+	for _, node := range nodes {
+		tom.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+			options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+		})
+	}
+	resourceManager := NewResourceManager(informerFactory.Core().V1().Nodes(), schedulingconfig.NUMALeastAllocated, tom).(*resourceManager)
+	informerFactory.Start(nil)
+	informerFactory.WaitForCacheSync(nil)
+
+	for _, node := range nodes {
+		resourceManager.Update(node.Name, &PodAllocation{
+			UID:                types.UID("123456"),
+			CPUSet:             cpuset.NewCPUSet(0, 1, 2, 3),
+			CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+		})
+	}
+	assert.NotNil(t, resourceManager.nodeAllocations["draining-node"])
+	assert.NotNil(t, resourceManager.nodeAllocations["normal-node"])
+
+	for _, node := range nodes {
+		resourceManager.Release(node.Name, types.UID("123456"))
+	}
+	assert.Nil(t, resourceManager.nodeAllocations["draining-node"])
+	assert.NotNil(t, resourceManager.nodeAllocations["normal-node"])
+}
+
+func TestResourceManagerFreeDrainedNodeAllocationConcurrently(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "draining-node",
+			Labels: map[string]string{
+				apiext.LabelNodeCoordinatedDrain: "true",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+		},
+	}
+	informerFactory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(node), 0)
+	tom := NewTopologyOptionsManager()
+	tom.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+	})
+	resourceManager := NewResourceManager(informerFactory.Core().V1().Nodes(), schedulingconfig.NUMALeastAllocated, tom).(*resourceManager)
+	informerFactory.Start(nil)
+	informerFactory.WaitForCacheSync(nil)
+
+	// the released pods free the allocation of the node while the kept pods are updated concurrently
+	const numPods = 100
+	var wg sync.WaitGroup
+	for i := 0; i < numPods; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			podUID := types.UID(fmt.Sprintf("released-pod-%d", i))
+			resourceManager.Update(node.Name, &PodAllocation{
+				UID:                podUID,
+				CPUSet:             cpuset.NewCPUSet(0, 1),
+				CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+			})
+			resourceManager.Release(node.Name, podUID)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			resourceManager.Update(node.Name, &PodAllocation{
+				UID:                types.UID(fmt.Sprintf("kept-pod-%d", i)),
+				CPUSet:             cpuset.NewCPUSet(2, 3),
+				CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	nodeAllocation := resourceManager.nodeAllocations[node.Name]
+	assert.NotNil(t, nodeAllocation)
+	assert.Len(t, nodeAllocation.allocatedPods, numPods)
+	for i := 0; i < numPods; i++ {
+		_, ok := nodeAllocation.allocatedPods[types.UID(fmt.Sprintf("kept-pod-%d", i))]
+		assert.True(t, ok)
+	}
+}
+
+func TestResourceManagerAllocateWithDrainedNUMANodes(t *testing.T) {
+	tom := NewTopologyOptionsManager()
+	tom.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+		options.NUMANodeResources = []NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+			{Node: 1, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+		}
+	})
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				apiext.AnnotationNodeDrainedNUMANodes: "[0]",
+			},
+		},
+	}
+	resourceManager := NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, tom)
+	topologyOptions := tom.GetTopologyOptions(node.Name)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "test-pod"}}
+	requests := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+
+	// only the NUMA Nodes in service are hinted
+	options, err := NewResourceOptions(node, requests, topologyOptions,
+		WithCPUBind(4, schedulingconfig.CPUBindPolicyFullPCPUs, false))
+	assert.NoError(t, err)
+	hints, err := resourceManager.GetTopologyHints(node, pod, options)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hints[string(corev1.ResourceCPU)])
+	for _, hint := range hints[string(corev1.ResourceCPU)] {
+		assert.Equal(t, []int{1}, hint.NUMANodeAffinity.GetBits())
+	}
+
+	// the CPUs are bound out of the drained NUMA Node without the NUMA hint
+	allocation, err := resourceManager.Allocate(node, pod, options)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, allocation.CPUSet.Size())
+	assert.True(t, allocation.CPUSet.IsSubsetOf(topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(1)))
+
+	// the drained NUMA Node can't be allocated by the hint
+	mask, _ := bitmask.NewBitMask(0)
+	options, err = NewResourceOptions(node, requests, topologyOptions,
+		WithNUMATopologyHint(topologyhint.NUMATopologyHint{NUMANodeAffinity: mask}))
+	assert.NoError(t, err)
+	_, err = resourceManager.Allocate(node, pod, options)
+	assert.Error(t, err)
+}
+
+func TestDeamplifyCPUs(t *testing.T) {
+	tests := []struct {
+		amplifiedMilliCPU int64
+		ratio             apiext.Ratio
+		want              int64
+	}{
+		{amplifiedMilliCPU: 0, ratio: 1.5, want: 0},
+		{amplifiedMilliCPU: -1000, ratio: 1.5, want: 0},
+		{amplifiedMilliCPU: 1499, ratio: 1.5, want: 0},
+		{amplifiedMilliCPU: 1500, ratio: 1.5, want: 1},
+		{amplifiedMilliCPU: 6000, ratio: 1.5, want: 4},
+		{amplifiedMilliCPU: 6999, ratio: 1.5, want: 4},
+		{amplifiedMilliCPU: 8000, ratio: 2, want: 4},
+		{amplifiedMilliCPU: 12000, ratio: 3, want: 4},
+		// extension.Amplify(12000, 1.1) is 13201 for the float error
+		{amplifiedMilliCPU: 13200, ratio: 1.1, want: 11},
+		{amplifiedMilliCPU: 13201, ratio: 1.1, want: 12},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%v", tt.amplifiedMilliCPU, tt.ratio), func(t *testing.T) {
+			got := deamplifyCPUs(tt.amplifiedMilliCPU, tt.ratio)
+			assert.Equal(t, tt.want, got)
+			if got > 0 {
+				assert.LessOrEqual(t, apiext.Amplify(got*1000, tt.ratio), tt.amplifiedMilliCPU)
+			}
+			assert.Greater(t, apiext.Amplify((got+1)*1000, tt.ratio), tt.amplifiedMilliCPU)
+		})
+	}
+}
+
+func TestResourceManagerAllocateAmplifiedCPUBind(t *testing.T) {
+	newResourceManagerWithSharedPod := func(t *testing.T, ratio apiext.Ratio) (*corev1.Node, *TopologyOptions, *resourceManager) {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-node",
+			},
+		}
+		apiext.SetNodeResourceAmplificationRatios(node, map[corev1.ResourceName]apiext.Ratio{
+			corev1.ResourceCPU: ratio,
+		})
+		tom := NewTopologyOptionsManager()
+		tom.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+			options.CPUTopology = buildCPUTopologyForTest(2, 1, 8, 2)
+			options.NUMANodeResources = []NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}},
+				{Node: 1, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}},
+			}
+		})
+		resourceManager := NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, tom).(*resourceManager)
+		// the Pod in the CPU Shared Pool takes the amplified CPUs of 4 physical CPUs on the NUMA Node 0
+		resourceManager.Update(node.Name, &PodAllocation{
+			UID: "shared-pod",
+			NUMANodeResources: []NUMANodeResource{
+				{
+					Node: 0,
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU: *resource.NewMilliQuantity(apiext.Amplify(4000, ratio), resource.DecimalSI),
+					},
+				},
+			},
+		})
+		topologyOptions := tom.GetTopologyOptions(node.Name)
+		return node, &topologyOptions, resourceManager
+	}
+	newHint := func(numaNodes ...int) topologyhint.NUMATopologyHint {
+		mask, _ := bitmask.NewBitMask(numaNodes...)
+		return topologyhint.NUMATopologyHint{NUMANodeAffinity: mask}
+	}
+
+	t.Run("split the original CPUs by the physical CPUs of NUMA Nodes", func(t *testing.T) {
+		node, topologyOptions, resourceManager := newResourceManagerWithSharedPod(t, 1.5)
+		options, err := NewResourceOptions(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}, *topologyOptions,
+			WithCPUBind(16, schedulingconfig.CPUBindPolicyFullPCPUs, false),
+			WithNUMATopologyHint(newHint(0, 1)),
+		)
+		assert.NoError(t, err)
+		assert.True(t, options.isAmplifiedCPUBind())
+		assert.Equal(t, int64(24000), options.Requests.Cpu().MilliValue())
+
+		allocation, err := resourceManager.Allocate(node, &corev1.Pod{}, options)
+		assert.NoError(t, err)
+		assert.Equal(t, 16, allocation.CPUSet.Size())
+		// the NUMA Node 0 has 24 - 6 = 18 amplified CPUs, which only bind 12 physical CPUs
+		assert.Equal(t, []NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("12")}},
+			{Node: 1, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+		}, allocation.NUMANodeResources)
+	})
+
+	for _, ratio := range []apiext.Ratio{1.5, 2, 3} {
+		for _, cpuBindPolicy := range []schedulingconfig.CPUBindPolicy{schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUBindPolicySpreadByPCPUs} {
+			for _, numCPUs := range []int{2, 4, 8, 12, 16, 24} {
+				name := fmt.Sprintf("ratio %v, %s, %d CPUs", ratio, cpuBindPolicy, numCPUs)
+				t.Run(name, func(t *testing.T) {
+					node, topologyOptions, resourceManager := newResourceManagerWithSharedPod(t, ratio)
+					hint := newHint(0)
+					if numCPUs > 8 {
+						hint = newHint(0, 1)
+					}
+					requests := corev1.ResourceList{corev1.ResourceCPU: *resource.NewQuantity(int64(numCPUs), resource.DecimalSI)}
+					options, err := NewResourceOptions(node, requests, *topologyOptions,
+						WithCPUBind(numCPUs, cpuBindPolicy, false),
+						WithNUMATopologyHint(hint),
+					)
+					assert.NoError(t, err)
+					assert.Equal(t, apiext.Amplify(int64(numCPUs*1000), ratio), options.Requests.Cpu().MilliValue())
+					assert.Equal(t, int64(numCPUs*1000), options.OriginalRequests.Cpu().MilliValue())
+
+					pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "bound-pod"}}
+					allocation, err := resourceManager.Allocate(node, pod, options)
+					assert.NoError(t, err)
+					if err != nil {
+						return
+					}
+
+					// the Pod is pinned on the CPUs of the original requests
+					assert.Equal(t, numCPUs, allocation.CPUSet.Size())
+					var allocatedCPUs int64
+					for _, numaNode := range allocation.NUMANodeResources {
+						quantity := numaNode.Resources[corev1.ResourceCPU]
+						allocatedCPUs += quantity.MilliValue()
+						cpusInNUMANode := topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(numaNode.Node)
+						assert.Equal(t, quantity.MilliValue(), int64(allocation.CPUSet.Intersection(cpusInNUMANode).Size()*1000),
+							"the CPUs allocated on NUMA Node %d must be bound there", numaNode.Node)
+					}
+					assert.Equal(t, int64(numCPUs*1000), allocatedCPUs)
+
+					// the NUMA Nodes account the amplified CPUs of the bound CPUs
+					resourceManager.Update(node.Name, allocation)
+					amplifiedOptions := options.TopologyOptions
+					_, totalAllocated, err := resourceManager.getAvailableNUMANodeResources(node.Name, amplifiedOptions, nil)
+					assert.NoError(t, err)
+					for _, numaNode := range amplifiedOptions.NUMANodeResources {
+						cpusInNUMANode := topologyOptions.CPUTopology.CPUDetails.CPUsInNUMANodes(numaNode.Node)
+						wantAllocated := apiext.Amplify(int64(allocation.CPUSet.Intersection(cpusInNUMANode).Size()*1000), ratio)
+						if numaNode.Node == 0 {
+							wantAllocated += apiext.Amplify(4000, ratio)
+						}
+						allocated := totalAllocated[numaNode.Node][corev1.ResourceCPU]
+						assert.Equal(t, wantAllocated, allocated.MilliValue(), "NUMA Node %d", numaNode.Node)
+						assert.LessOrEqual(t, allocated.MilliValue(), numaNode.Resources.Cpu().MilliValue(),
+							"NUMA Node %d is overcommitted", numaNode.Node)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	"github.com/koordinator-sh/koordinator/pkg/util/topologyhint"
+)
+
+// ResourceOption configures the ResourceOptions built by NewResourceOptions.
+type ResourceOption func(options *ResourceOptions)
+
+// WithCPUBind requests the CPUs of the Pod to be bound with the CPUBindPolicy.
+// If required is true, the Pod can only be allocated the CPUs satisfying the CPUBindPolicy.
+func WithCPUBind(numCPUsNeeded int, cpuBindPolicy schedulingconfig.CPUBindPolicy, required bool) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.RequestCPUBind = true
+		options.NumCPUsNeeded = numCPUsNeeded
+		options.CPUBindPolicy = cpuBindPolicy
+		options.RequiredCPUBindPolicy = required
+	}
+}
+
+// WithCPUExclusivePolicy sets the preferred CPUExclusivePolicy of the Pod.
+func WithCPUExclusivePolicy(cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.CPUExclusivePolicy = cpuExclusivePolicy
+	}
+}
+
+// WithNUMAAllocateStrategy overrides the NUMAAllocateStrategy of the node.
+func WithNUMAAllocateStrategy(numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.NUMAAllocateStrategy = numaAllocateStrategy
+	}
+}
+
+// WithNUMAHintAllocateOrder sets the order in which the NUMA hints are tried.
+func WithNUMAHintAllocateOrder(hintAllocateOrder schedulingconfig.NUMAHintAllocateOrder) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.HintAllocateOrder = hintAllocateOrder
+	}
+}
+
+// WithNUMATopologyHint restricts the allocation to the NUMA Nodes of the hint.
+func WithNUMATopologyHint(hint topologyhint.NUMATopologyHint) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.Hint = hint
+	}
+}
+
+// WithPreferredCPUs sets the CPUs that are preferred to be allocated, e.g. the CPUs reserved by a Reservation.
+func WithPreferredCPUs(preferredCPUs cpuset.CPUSet) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.PreferredCPUs = preferredCPUs
+	}
+}
+
+// WithReservedFullCores holds back the number of free physical cores for the Pods requiring FullPCPUs.
+func WithReservedFullCores(reservedFullCores int) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.ReservedFullCores = reservedFullCores
+	}
+}
+
+// WithAllowIsolatedCPUs allows the Pod to be pinned on the isolated CPUs.
+func WithAllowIsolatedCPUs(allowIsolatedCPUs bool) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.AllowIsolatedCPUs = allowIsolatedCPUs
+	}
+}
+
+// WithBalanceSockets takes the CPUs of the Pod from the socket with the fewest allocated CPUs first.
+func WithBalanceSockets(balanceSockets bool) ResourceOption {
+	return func(options *ResourceOptions) {
+		options.BalanceSockets = balanceSockets
+	}
+}
+
+// NewResourceOptions builds the ResourceOptions used by the ResourceManager to generate topology hints
+// and allocate resources for the Pod on the node, so that the binding logic can be reused by the callers
+// outside the NodeNUMAResource plugin. The topologyOptions are amplified by the ratios of the node, and the NUMA Nodes
+// drained for maintenance are excluded.
+func NewResourceOptions(node *corev1.Node, requests corev1.ResourceList, topologyOptions TopologyOptions, opts ...ResourceOption) (*ResourceOptions, error) {
+	if err := AmplifyNUMANodeResources(node, &topologyOptions); err != nil {
+		return nil, err
+	}
+	drainedNUMANodes, err := ExcludeDrainedNUMANodes(node, &topologyOptions)
+	if err != nil {
+		return nil, err
+	}
+	options := &ResourceOptions{
+		Requests:         requests,
+		OriginalRequests: requests,
+		TopologyOptions:  topologyOptions,
+		DrainedNUMANodes: drainedNUMANodes,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.AmplifyCPUBindRequests()
+	return options, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+)
+
+func TestNewResourceOptions(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	apiext.SetNodeResourceAmplificationRatios(node, map[corev1.ResourceName]apiext.Ratio{
+		corev1.ResourceCPU: 2,
+	})
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("4"),
+	}
+	topologyOptions := TopologyOptions{
+		CPUTopology: buildCPUTopologyForTest(2, 1, 4, 2),
+	}
+
+	options, err := NewResourceOptions(node, requests, topologyOptions,
+		WithCPUBind(4, schedulingconfig.CPUBindPolicyFullPCPUs, true),
+		WithNUMAAllocateStrategy(schedulingconfig.NUMAMostAllocated),
+		WithReservedFullCores(1),
+	)
+	assert.NoError(t, err)
+	assert.True(t, options.RequestCPUBind)
+	assert.True(t, options.RequiredCPUBindPolicy)
+	assert.Equal(t, 4, options.NumCPUsNeeded)
+	assert.Equal(t, schedulingconfig.CPUBindPolicyFullPCPUs, options.CPUBindPolicy)
+	assert.Equal(t, schedulingconfig.NUMAMostAllocated, options.NUMAAllocateStrategy)
+	assert.Equal(t, 0, options.numHeldBackFullCores())
+	assert.Equal(t, map[corev1.ResourceName]apiext.Ratio{corev1.ResourceCPU: 2}, options.TopologyOptions.AmplificationRatios)
+	assert.Equal(t, resource.MustParse("4"), options.OriginalRequests[corev1.ResourceCPU])
+	assert.Equal(t, int64(8000), options.Requests.Cpu().MilliValue())
+	assert.Nil(t, topologyOptions.AmplificationRatios, "the topologyOptions of the caller must not be modified")
+}
+
+func TestResourceManagerWithoutNodeInformer(t *testing.T) {
+	tom := NewTopologyOptionsManager()
+	tom.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+	})
+	resourceManager := NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, tom)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: "123456",
+		},
+	}
+	options, err := NewResourceOptions(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}, tom.GetTopologyOptions(node.Name),
+		WithCPUBind(4, schedulingconfig.CPUBindPolicyFullPCPUs, true),
+	)
+	assert.NoError(t, err)
+	allocation, err := resourceManager.Allocate(node, pod, options)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, allocation.CPUSet.Size())
+
+	resourceManager.Update(node.Name, allocation)
+	allocatedCPUs, ok := resourceManager.GetAllocatedCPUSet(node.Name, pod.UID)
+	assert.True(t, ok)
+	assert.Equal(t, allocation.CPUSet, allocatedCPUs)
+	resourceManager.Release(node.Name, pod.UID)
+	_, ok = resourceManager.GetAllocatedCPUSet(node.Name, pod.UID)
+	assert.False(t, ok)
+}
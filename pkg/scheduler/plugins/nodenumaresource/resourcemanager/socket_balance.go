@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// getSocketAllocatedRatios returns the percentage of the CPUs allocated to the cpuset-bound Pods in each socket,
+// including the CPUs of the Pod to be placed.
+func getSocketAllocatedRatios(cpuTopology *CPUTopology, allocatedCPUs CPUDetails, podCPUs cpuset.CPUSet) map[int]int64 {
+	allocated := allocatedCPUs.CPUs().Union(podCPUs)
+	ratios := make(map[int]int64, cpuTopology.NumSockets)
+	for _, socket := range cpuTopology.CPUDetails.Sockets().ToSliceNoSort() {
+		cpusInSocket := cpuTopology.CPUDetails.CPUsInSockets(socket)
+		if cpusInSocket.IsEmpty() {
+			continue
+		}
+		ratios[socket] = int64(allocated.Intersection(cpusInSocket).Size()) * 100 / int64(cpusInSocket.Size())
+	}
+	return ratios
+}
+
+// sortSocketsByAllocatedCPUs returns the sockets ordered by the percentage of the allocated CPUs ascending.
+func sortSocketsByAllocatedCPUs(cpuTopology *CPUTopology, allocatedCPUs CPUDetails) []int {
+	ratios := getSocketAllocatedRatios(cpuTopology, allocatedCPUs, cpuset.NewCPUSet())
+	sockets := cpuTopology.CPUDetails.Sockets().ToSlice()
+	sort.SliceStable(sockets, func(i, j int) bool {
+		return ratios[sockets[i]] < ratios[sockets[j]]
+	})
+	return sockets
+}
+
+// takeSocketBalancedCPUSet takes the CPUs of the Pod within the socket with the fewest allocated CPUs first,
+// so that the exclusive Pods are spread across the sockets rather than piled up on one of them.
+// The CPUs are taken across the sockets if no single socket satisfies the Pod.
+func (c *resourceManager) takeSocketBalancedCPUSet(node *corev1.Node, availableCPUs cpuset.CPUSet, allocatedCPUs CPUDetails, allocatedNUMANodes []NUMANodeResource, options *ResourceOptions) (cpuset.CPUSet, error) {
+	cpuTopology := options.TopologyOptions.CPUTopology
+	// the sockets of the allocated NUMA Nodes are decided by the NUMA hint
+	if options.BalanceSockets && len(allocatedNUMANodes) == 0 && cpuTopology.NumSockets > 1 {
+		for _, socket := range sortSocketsByAllocatedCPUs(cpuTopology, allocatedCPUs) {
+			availableCPUsInSocket := availableCPUs.Intersection(cpuTopology.CPUDetails.CPUsInSockets(socket))
+			if availableCPUsInSocket.Size() < options.NumCPUsNeeded {
+				continue
+			}
+			result, err := c.takeCPUSet(node, availableCPUsInSocket, allocatedCPUs, nil, options)
+			if err == nil {
+				return result, nil
+			}
+			klog.V(5).Infof("failed to take cpus in socket %d on node %s, err: %v", socket, node.Name, err)
+		}
+	}
+	return c.takeCPUSet(node, availableCPUs, allocatedCPUs, allocatedNUMANodes, options)
+}
+
+// MaxScore is the maximum score of the scoring helpers, which is consistent with the MaxScore.
+const MaxScore int64 = 100
+
+// SocketBalanceScore scores how evenly the CPUs of the cpuset-bound Pods are allocated across the sockets
+// after the Pod is placed. The score is the maximum if the percentages of the allocated CPUs are the same.
+func SocketBalanceScore(cpuTopology *CPUTopology, allocatedCPUs CPUDetails, podCPUs cpuset.CPUSet) int64 {
+	ratios := getSocketAllocatedRatios(cpuTopology, allocatedCPUs, podCPUs)
+	if len(ratios) <= 1 {
+		return MaxScore
+	}
+	minRatio, maxRatio := int64(100), int64(0)
+	for _, ratio := range ratios {
+		if ratio < minRatio {
+			minRatio = ratio
+		}
+		if ratio > maxRatio {
+			maxRatio = ratio
+		}
+	}
+	return MaxScore - (maxRatio-minRatio)*MaxScore/100
+}
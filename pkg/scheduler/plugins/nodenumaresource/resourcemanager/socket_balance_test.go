@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func TestSocketBalanceScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		cpuTopology   *CPUTopology
+		allocatedCPUs cpuset.CPUSet
+		podCPUs       cpuset.CPUSet
+		want          int64
+	}{
+		{
+			name:          "single socket",
+			cpuTopology:   buildCPUTopologyForTest(1, 2, 4, 2),
+			allocatedCPUs: cpuset.NewCPUSet(0, 1, 2, 3),
+			podCPUs:       cpuset.NewCPUSet(4, 5, 6, 7),
+			want:          100,
+		},
+		{
+			name:        "first allocation on the node",
+			cpuTopology: buildCPUTopologyForTest(2, 1, 4, 2),
+			podCPUs:     cpuset.NewCPUSet(0, 1, 2, 3),
+			want:        50,
+		},
+		{
+			name:          "allocated on the other socket",
+			cpuTopology:   buildCPUTopologyForTest(2, 1, 4, 2),
+			allocatedCPUs: cpuset.NewCPUSet(0, 1, 2, 3),
+			podCPUs:       cpuset.NewCPUSet(8, 9, 10, 11),
+			want:          100,
+		},
+		{
+			name:          "allocated on the same socket",
+			cpuTopology:   buildCPUTopologyForTest(2, 1, 4, 2),
+			allocatedCPUs: cpuset.NewCPUSet(0, 1, 2, 3),
+			podCPUs:       cpuset.NewCPUSet(4, 5, 6, 7),
+			want:          0,
+		},
+		{
+			name:          "allocated across the sockets",
+			cpuTopology:   buildCPUTopologyForTest(2, 1, 4, 2),
+			allocatedCPUs: cpuset.NewCPUSet(0, 1, 2, 3),
+			podCPUs:       cpuset.NewCPUSet(4, 5, 8, 9),
+			want:          50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocatedCPUs := tt.cpuTopology.CPUDetails.KeepOnly(tt.allocatedCPUs)
+			got := SocketBalanceScore(tt.cpuTopology, allocatedCPUs, tt.podCPUs)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResourceManagerAllocateWithBalanceSockets(t *testing.T) {
+	tests := []struct {
+		name           string
+		balanceSockets bool
+		wantSockets    []int
+	}{
+		{
+			name:        "pack the exclusive Pods on one socket",
+			wantSockets: []int{0, 0},
+		},
+		{
+			name:           "spread the exclusive Pods across the sockets",
+			balanceSockets: true,
+			wantSockets:    []int{0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tom := NewTopologyOptionsManager()
+			tom.UpdateTopologyOptions("test-node", func(options *TopologyOptions) {
+				options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+			})
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+			resourceManager := NewResourceManager(nil, schedulingconfig.NUMAMostAllocated, tom)
+			topologyOptions := tom.GetTopologyOptions(node.Name)
+			requests := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+
+			for i, wantSocket := range tt.wantSockets {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(fmt.Sprintf("test-pod-%d", i))}}
+				options, err := NewResourceOptions(node, requests, topologyOptions,
+					WithCPUBind(4, schedulingconfig.CPUBindPolicyFullPCPUs, false),
+					WithCPUExclusivePolicy(schedulingconfig.CPUExclusivePolicyPCPULevel),
+					WithBalanceSockets(tt.balanceSockets))
+				assert.NoError(t, err)
+				allocation, err := resourceManager.Allocate(node, pod, options)
+				assert.NoError(t, err)
+				assert.Equal(t, 4, allocation.CPUSet.Size())
+				assert.True(t, allocation.CPUSet.IsSubsetOf(topologyOptions.CPUTopology.CPUDetails.CPUsInSockets(wantSocket)))
+				resourceManager.Update(node.Name, allocation)
+			}
+		})
+	}
+}
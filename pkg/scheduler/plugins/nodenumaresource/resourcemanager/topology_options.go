@@ -0,0 +1,413 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// TopologyOptionsManager manages the System Topology and resource assignments options.
+type TopologyOptionsManager interface {
+	GetTopologyOptions(nodeName string) TopologyOptions
+	UpdateTopologyOptions(nodeName string, updateFn func(options *TopologyOptions))
+	Delete(nodeName string)
+}
+
+type TopologyOptions struct {
+	CPUTopology         *CPUTopology                            `json:"cpuTopology"`
+	ReservedCPUs        cpuset.CPUSet                           `json:"reservedCPUs"`
+	IsolatedCPUs        cpuset.CPUSet                           `json:"isolatedCPUs,omitempty"`
+	MaxRefCount         int                                     `json:"maxRefCount"`
+	Policy              *extension.KubeletCPUManagerPolicy      `json:"policy,omitempty"`
+	NUMATopologyPolicy  extension.NUMATopologyPolicy            `json:"numaTopologyPolicy"`
+	NUMANodeResources   []NUMANodeResource                      `json:"numaNodeResources"`
+	AmplificationRatios map[corev1.ResourceName]extension.Ratio `json:"amplificationRatios,omitempty"`
+	// SRIOVDevices are the SR-IOV NICs on the node with their NUMA Nodes.
+	SRIOVDevices []extension.SRIOVDevice `json:"sriovDevices,omitempty"`
+	// SystemReservedCPUs are the CPUs reserved by the kubelet and the node reservation, and exclusively by the System QoS.
+	// Only the System QoS Pods selected by the ReservedCPUsPodSelector can be pinned on them.
+	SystemReservedCPUs cpuset.CPUSet `json:"systemReservedCPUs,omitempty"`
+	// NUMANodeTotalAllocatable is the sum of the allocatable reported by the NUMA Node zones, including the memory
+	// pinned by the kubelet Memory Manager. It is used to validate the NodeResourceTopology against the node.
+	NUMANodeTotalAllocatable corev1.ResourceList `json:"numaNodeTotalAllocatable,omitempty"`
+}
+
+type NUMANodeResource struct {
+	Node      int                 `json:"node"`
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+}
+
+type topologyManager struct {
+	lock            sync.Mutex
+	topologyOptions map[string]TopologyOptions
+}
+
+func NewTopologyOptionsManager() TopologyOptionsManager {
+	manager := &topologyManager{
+		topologyOptions: map[string]TopologyOptions{},
+	}
+	return manager
+}
+
+func (m *topologyManager) GetTopologyOptions(nodeName string) TopologyOptions {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.topologyOptions[nodeName]
+}
+
+func (m *topologyManager) UpdateTopologyOptions(nodeName string, updateFn func(options *TopologyOptions)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	options := m.topologyOptions[nodeName]
+	updateFn(&options)
+	if options.MaxRefCount == 0 {
+		options.MaxRefCount = 1
+	}
+	m.topologyOptions[nodeName] = options
+}
+
+func (m *topologyManager) Delete(nodeName string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.topologyOptions, nodeName)
+}
+
+func NewTopologyOptions(nrt *nrtv1alpha1.NodeResourceTopology) TopologyOptions {
+	podCPUAllocs, err := extension.GetPodCPUAllocs(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetPodCPUAllocs from new NodeResourceTopology %s, err: %v", nrt.Name, err)
+	}
+
+	kubeletPolicy, err := extension.GetKubeletCPUManagerPolicy(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetKubeletCPUManagerPolicy from NodeResourceTopology %s, err: %v", nrt.Name, err)
+	}
+	var kubeletReservedCPUs cpuset.CPUSet
+	if kubeletPolicy != nil {
+		kubeletReservedCPUs, err = cpuset.Parse(kubeletPolicy.ReservedCPUs)
+		if err != nil {
+			klog.Errorf("Failed to Parse kubelet reserved CPUs %s, err: %v", kubeletPolicy.ReservedCPUs, err)
+		}
+	}
+
+	// remove cpus reserved by node.annotation.
+	reservedCPUsString, _ := extension.GetReservedCPUs(nrt.Annotations)
+	nodeReservationReservedCPUs, err := cpuset.Parse(reservedCPUsString)
+	if err != nil {
+		klog.Errorf("Failed to parse nodeResourceResource reservedCPUs, name: %v, err: %v", nrt.Name, err)
+	}
+	reportedCPUTopology, err := extension.GetCPUTopology(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetCPUTopology, name: %s, err: %v", nrt.Name, err)
+	}
+
+	// reservedCPUs = cpus(all) - cpus(guaranteed) - cpus(kubeletReserved) - cpus(nodeReservationReserved) - cpus(systemQOSReserved) - cpus(housekeeping)
+	cpuTopology := convertCPUTopology(reportedCPUTopology)
+	reservedCPUs := getPodAllocsCPUSet(podCPUAllocs)
+	reservedCPUs = reservedCPUs.Union(kubeletReservedCPUs)
+	reservedCPUs = reservedCPUs.Union(nodeReservationReservedCPUs)
+	systemReservedCPUs := kubeletReservedCPUs.Union(nodeReservationReservedCPUs)
+	systemQOSResource, err := extension.GetSystemQOSResource(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetSystemQOSResource, name: %v, err: %v", nrt.Name, err)
+	} else if systemQOSResource != nil && systemQOSResource.IsCPUSetExclusive() {
+		cpus, err := cpuset.Parse(systemQOSResource.CPUSet)
+		if err != nil {
+			klog.Errorf("Failed to parse systemQOSResource.CPUSet, name: %s, err: %v", nrt.Name, err)
+		} else {
+			reservedCPUs = reservedCPUs.Union(cpus)
+			systemReservedCPUs = systemReservedCPUs.Union(cpus)
+		}
+	}
+	housekeepingCPUs, err := extension.GetHousekeepingCPUs(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetHousekeepingCPUs, name: %v, err: %v", nrt.Name, err)
+	} else if housekeepingCPUs != nil {
+		cpus, err := cpuset.Parse(housekeepingCPUs.CPUSet)
+		if err != nil {
+			klog.Errorf("Failed to parse housekeepingCPUs.CPUSet, name: %s, err: %v", nrt.Name, err)
+		} else {
+			reservedCPUs = reservedCPUs.Union(cpus)
+		}
+	}
+
+	// isolatedCPUs = cpus(isolcpus), which can only be pinned by the LSR and LSE Pods
+	var isolatedCPUs cpuset.CPUSet
+	kernelCPUIsolation, err := extension.GetKernelCPUIsolation(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetKernelCPUIsolation, name: %s, err: %v", nrt.Name, err)
+	} else if kernelCPUIsolation != nil {
+		isolatedCPUs, err = cpuset.Parse(kernelCPUIsolation.IsolCPUs)
+		if err != nil {
+			klog.Errorf("Failed to parse isolated CPUs %s, name: %s, err: %v", kernelCPUIsolation.IsolCPUs, nrt.Name, err)
+		}
+	}
+
+	policy := convertToNUMATopologyPolicy(nrt)
+	numaNodeResources := extractNUMANodeResources(nrt)
+	numaNodeTotalAllocatable := sumNUMANodeResources(numaNodeResources)
+	// numaNodeResources = resources(zone) - memory(pinned by kubelet Memory Manager)
+	podMemoryAllocs, err := extension.GetPodMemoryAllocs(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetPodMemoryAllocs, name: %s, err: %v", nrt.Name, err)
+	} else {
+		subtractKubeletPinnedMemory(numaNodeResources, podMemoryAllocs)
+	}
+
+	amplificationRatios, err := extension.GetNodeResourceAmplificationRatios(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetNodeResourceAmplificationRatios, name: %s, err: %v", nrt.Name, err)
+	}
+
+	sriovDevices, err := extension.GetSRIOVDevices(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetSRIOVDevices, name: %s, err: %v", nrt.Name, err)
+	}
+
+	return TopologyOptions{
+		CPUTopology:              cpuTopology,
+		ReservedCPUs:             reservedCPUs,
+		SystemReservedCPUs:       systemReservedCPUs,
+		IsolatedCPUs:             isolatedCPUs,
+		Policy:                   kubeletPolicy,
+		MaxRefCount:              1,
+		NUMATopologyPolicy:       policy,
+		NUMANodeResources:        numaNodeResources,
+		AmplificationRatios:      amplificationRatios,
+		SRIOVDevices:             sriovDevices,
+		NUMANodeTotalAllocatable: numaNodeTotalAllocatable,
+	}
+}
+
+func sumNUMANodeResources(numaNodeResources []NUMANodeResource) corev1.ResourceList {
+	if len(numaNodeResources) == 0 {
+		return nil
+	}
+	total := corev1.ResourceList{}
+	for _, numaNodeResource := range numaNodeResources {
+		total = quotav1.Add(total, numaNodeResource.Resources)
+	}
+	return total
+}
+
+// GetSRIOVDeviceNUMANodes returns the sorted NUMA Nodes of the SR-IOV NICs whose VFs can be allocated to the Pod,
+// i.e. the NICs whose device plugin resources are requested by the Pod.
+func GetSRIOVDeviceNUMANodes(pod *corev1.Pod, devices []extension.SRIOVDevice) []int {
+	numaNodes := cpuset.NewCPUSetBuilder()
+	for _, device := range devices {
+		for _, resourceName := range device.ResourceNames {
+			if isPodRequestingResource(pod, corev1.ResourceName(resourceName)) {
+				numaNodes.Add(int(device.NUMANode))
+				break
+			}
+		}
+	}
+	if result := numaNodes.Result(); !result.IsEmpty() {
+		return result.ToSlice()
+	}
+	return nil
+}
+
+// isPodRequestingResource checks whether any container of the Pod requests the resource.
+func isPodRequestingResource(pod *corev1.Pod, resourceName corev1.ResourceName) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			if quantity, ok := containers[i].Resources.Limits[resourceName]; ok && !quantity.IsZero() {
+				return true
+			}
+			if quantity, ok := containers[i].Resources.Requests[resourceName]; ok && !quantity.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getPodAllocsCPUSet(podCPUAllocs extension.PodCPUAllocs) cpuset.CPUSet {
+	if len(podCPUAllocs) == 0 {
+		return cpuset.CPUSet{}
+	}
+	builder := cpuset.NewCPUSetBuilder()
+	for _, v := range podCPUAllocs {
+		if !v.ManagedByKubelet || v.UID == "" || v.CPUSet == "" {
+			continue
+		}
+		cpuset, err := cpuset.Parse(v.CPUSet)
+		if err != nil || cpuset.IsEmpty() {
+			continue
+		}
+		builder.Add(cpuset.ToSliceNoSort()...)
+	}
+	return builder.Result()
+}
+
+// subtractKubeletPinnedMemory subtracts the memory pinned by the kubelet Memory Manager from the NUMA Node resources,
+// since the Pods are not accounted by the scheduler.
+func subtractKubeletPinnedMemory(numaNodeResources []NUMANodeResource, podMemoryAllocs extension.PodMemoryAllocs) {
+	if len(podMemoryAllocs) == 0 {
+		return
+	}
+	pinned := map[int]corev1.ResourceList{}
+	for _, v := range podMemoryAllocs {
+		if !v.ManagedByKubelet || v.UID == "" {
+			continue
+		}
+		for _, numaNodeResource := range v.NUMANodeResources {
+			node := int(numaNodeResource.Node)
+			if pinned[node] == nil {
+				pinned[node] = corev1.ResourceList{}
+			}
+			for resourceName, quantity := range numaNodeResource.Resources {
+				total := pinned[node][resourceName]
+				total.Add(quantity)
+				pinned[node][resourceName] = total
+			}
+		}
+	}
+	for _, numaNodeResource := range numaNodeResources {
+		for resourceName, quantity := range pinned[numaNodeResource.Node] {
+			allocatable, ok := numaNodeResource.Resources[resourceName]
+			if !ok {
+				continue
+			}
+			allocatable.Sub(quantity)
+			if allocatable.Sign() < 0 {
+				allocatable = *resource.NewQuantity(0, allocatable.Format)
+			}
+			numaNodeResource.Resources[resourceName] = allocatable
+		}
+	}
+}
+
+func convertCPUTopology(reportedCPUTopology *extension.CPUTopology) *CPUTopology {
+	builder := NewCPUTopologyBuilder()
+	for _, info := range reportedCPUTopology.Detail {
+		builder.AddCPUInfo(int(info.Socket), int(info.Node), int(info.Core), int(info.ID))
+		if info.L3 != nil {
+			builder.AddL3Info(int(info.ID), int(*info.L3))
+		}
+	}
+	return builder.Result()
+}
+
+func extractNUMANodeResources(nrt *nrtv1alpha1.NodeResourceTopology) []NUMANodeResource {
+	numaNodeResources := make([]NUMANodeResource, 0, len(nrt.Zones))
+	for i := range nrt.Zones {
+		zone := &nrt.Zones[i]
+		if zone.Type != "Node" {
+			continue
+		}
+		parts := strings.Split(zone.Name, "node-")
+		if len(parts) != 2 {
+			continue
+		}
+		nodeID, err := strconv.Atoi(parts[1])
+		if err != nil {
+			klog.ErrorS(err, "Failed to parse zone name of NodeResourceTopology", "node", nrt.Name, "zoneName", zone.Name)
+			continue
+		}
+		resources := make(corev1.ResourceList)
+		for _, res := range zone.Resources {
+			resName := corev1.ResourceName(res.Name)
+			resources[resName] = res.Allocatable
+		}
+		numaNodeResources = append(numaNodeResources, NUMANodeResource{
+			Node:      nodeID,
+			Resources: resources,
+		})
+	}
+	sort.Slice(numaNodeResources, func(i, j int) bool {
+		return numaNodeResources[i].Node < numaNodeResources[j].Node
+	})
+	fillMissingZoneResources(numaNodeResources)
+	return numaNodeResources
+}
+
+// fillMissingZoneResources sets zero for the resources which are reported by some NUMA Nodes but missing on others.
+// The zone resources such as GPUs and NICs are usually attached to a part of the NUMA Nodes, so that the NUMA Nodes
+// without the devices can not satisfy the device requests when generating the topology hints.
+func fillMissingZoneResources(numaNodeResources []NUMANodeResource) {
+	resourceNames := sets.NewString()
+	for _, v := range numaNodeResources {
+		for resourceName := range v.Resources {
+			resourceNames.Insert(string(resourceName))
+		}
+	}
+	for _, v := range numaNodeResources {
+		for _, resourceName := range resourceNames.UnsortedList() {
+			if _, ok := v.Resources[corev1.ResourceName(resourceName)]; !ok {
+				v.Resources[corev1.ResourceName(resourceName)] = *resource.NewQuantity(0, resource.DecimalSI)
+			}
+		}
+	}
+}
+
+func convertToNUMATopologyPolicy(nrt *nrtv1alpha1.NodeResourceTopology) extension.NUMATopologyPolicy {
+	for _, policy := range nrt.TopologyPolicies {
+		switch nrtv1alpha1.TopologyManagerPolicy(policy) {
+		case nrtv1alpha1.BestEffort:
+			return extension.NUMATopologyPolicyBestEffort
+		case nrtv1alpha1.Restricted:
+			return extension.NUMATopologyPolicyRestricted
+		case nrtv1alpha1.SingleNUMANodePodLevel:
+			return extension.NUMATopologyPolicySingleNUMANode
+		}
+	}
+	return extension.NUMATopologyPolicyNone
+}
+
+func (opts *TopologyOptions) GetNUMANodes() []int {
+	if len(opts.NUMANodeResources) == 0 {
+		return nil
+	}
+	nodes := make([]int, 0, len(opts.NUMANodeResources))
+	for _, v := range opts.NUMANodeResources {
+		nodes = append(nodes, v.Node)
+	}
+	return nodes
+}
+
+// ExcludeDrainedNUMANodes removes the NUMA Nodes drained for maintenance from the NUMA resources of the node.
+// It returns the drained NUMA Nodes, whose CPUs can't be allocated either.
+func ExcludeDrainedNUMANodes(node *corev1.Node, topologyOptions *TopologyOptions) ([]int, error) {
+	drainedNUMANodes, err := extension.GetNodeDrainedNUMANodes(node.Annotations)
+	if err != nil || len(drainedNUMANodes) == 0 {
+		return nil, err
+	}
+	numaNodeResources := make([]NUMANodeResource, 0, len(topologyOptions.NUMANodeResources))
+	for _, v := range topologyOptions.NUMANodeResources {
+		if containsNUMANode(drainedNUMANodes, v.Node) {
+			continue
+		}
+		numaNodeResources = append(numaNodeResources, v)
+	}
+	topologyOptions.NUMANodeResources = numaNodeResources
+	return drainedNUMANodes, nil
+}
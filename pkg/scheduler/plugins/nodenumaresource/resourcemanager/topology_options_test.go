@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	"testing"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestExtractNUMANodeResources(t *testing.T) {
+	nrt := &nrtv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+		Zones: nrtv1alpha1.ZoneList{
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: nrtv1alpha1.ResourceInfoList{
+					{Name: "cpu", Allocatable: resource.MustParse("16")},
+					{Name: "memory", Allocatable: resource.MustParse("32Gi")},
+				},
+			},
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: nrtv1alpha1.ResourceInfoList{
+					{Name: "cpu", Allocatable: resource.MustParse("16")},
+					{Name: "memory", Allocatable: resource.MustParse("32Gi")},
+					{Name: string(extension.ResourceNvidiaGPU), Allocatable: resource.MustParse("2")},
+					{Name: string(extension.ResourceRDMA), Allocatable: resource.MustParse("100")},
+				},
+			},
+			{
+				Name: "socket-0",
+				Type: "Socket",
+			},
+		},
+	}
+	expected := []NUMANodeResource{
+		{
+			Node: 0,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:          resource.MustParse("16"),
+				corev1.ResourceMemory:       resource.MustParse("32Gi"),
+				extension.ResourceNvidiaGPU: resource.MustParse("2"),
+				extension.ResourceRDMA:      resource.MustParse("100"),
+			},
+		},
+		{
+			Node: 1,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:          resource.MustParse("16"),
+				corev1.ResourceMemory:       resource.MustParse("32Gi"),
+				extension.ResourceNvidiaGPU: *resource.NewQuantity(0, resource.DecimalSI),
+				extension.ResourceRDMA:      *resource.NewQuantity(0, resource.DecimalSI),
+			},
+		},
+	}
+	assert.Equal(t, expected, extractNUMANodeResources(nrt))
+}
+
+func TestSubtractKubeletPinnedMemory(t *testing.T) {
+	numaNodeResources := []NUMANodeResource{
+		{
+			Node: 0,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		},
+		{
+			Node: 1,
+			Resources: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+	}
+	podMemoryAllocs := extension.PodMemoryAllocs{
+		{
+			UID: "pod-1",
+			NUMANodeResources: []extension.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}},
+				{Node: 1, Resources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}},
+			},
+			ManagedByKubelet: true,
+		},
+		{
+			UID: "pod-2",
+			NUMANodeResources: []extension.NUMANodeResource{
+				{Node: 1, Resources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}},
+			},
+			ManagedByKubelet: true,
+		},
+		{
+			UID: "pod-3",
+			NUMANodeResources: []extension.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")}},
+			},
+		},
+	}
+	subtractKubeletPinnedMemory(numaNodeResources, podMemoryAllocs)
+
+	expected := map[int]corev1.ResourceList{
+		0: {
+			corev1.ResourceCPU:    resource.MustParse("16"),
+			corev1.ResourceMemory: resource.MustParse("30Gi"),
+		},
+		1: {
+			corev1.ResourceCPU:    resource.MustParse("16"),
+			corev1.ResourceMemory: resource.MustParse("0"),
+		},
+	}
+	for _, v := range numaNodeResources {
+		for resourceName, quantity := range expected[v.Node] {
+			got := v.Resources[resourceName]
+			assert.Equal(t, 0, quantity.Cmp(got), "node %d, resource %s, got %s", v.Node, resourceName, got.String())
+		}
+	}
+}
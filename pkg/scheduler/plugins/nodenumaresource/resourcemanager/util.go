@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcemanager
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+)
+
+func GetNUMAAllocateStrategy(node *corev1.Node, defaultNUMAtAllocateStrategy schedulingconfig.NUMAAllocateStrategy) schedulingconfig.NUMAAllocateStrategy {
+	numaAllocateStrategy := defaultNUMAtAllocateStrategy
+	if val := schedulingconfig.NUMAAllocateStrategy(node.Labels[extension.LabelNodeNUMAAllocateStrategy]); val != "" {
+		numaAllocateStrategy = val
+	}
+	return numaAllocateStrategy
+}
+
+// AmplifyNUMANodeResources amplifies the resources per NUMA Node.
+// NOTE(joseph): After the NodeResource controller supports amplifying by ratios, should remove the function.
+func AmplifyNUMANodeResources(node *corev1.Node, topologyOptions *TopologyOptions) error {
+	if topologyOptions.AmplificationRatios != nil {
+		return nil
+	}
+	amplificationRatios, err := extension.GetNodeResourceAmplificationRatios(node.Annotations)
+	if err != nil {
+		return err
+	}
+	AmplifyNUMANodeResourcesByRatios(amplificationRatios, topologyOptions)
+	return nil
+}
+
+// AmplifyNUMANodeResourcesByRatios amplifies the resources per NUMA Node by the given ratios,
+// e.g. the ratios of the NodeResources shared in the scheduling cycle.
+func AmplifyNUMANodeResourcesByRatios(amplificationRatios map[corev1.ResourceName]extension.Ratio, topologyOptions *TopologyOptions) {
+	if topologyOptions.AmplificationRatios != nil {
+		return
+	}
+	topologyOptions.AmplificationRatios = amplificationRatios
+
+	numaNodeResources := make([]NUMANodeResource, 0, len(topologyOptions.NUMANodeResources))
+	for _, v := range topologyOptions.NUMANodeResources {
+		numaNode := NUMANodeResource{
+			Node:      v.Node,
+			Resources: v.Resources.DeepCopy(),
+		}
+		extension.AmplifyResourceList(numaNode.Resources, amplificationRatios)
+		numaNodeResources = append(numaNodeResources, numaNode)
+	}
+	topologyOptions.NUMANodeResources = numaNodeResources
+}
+
+// getPodInitOnlyRequests returns the resources that the Pod requests only while its init containers are running,
+// i.e. the part of the effective requests exceeding the sum of the app containers' requests.
+func getPodInitOnlyRequests(pod *corev1.Pod) corev1.ResourceList {
+	if len(pod.Spec.InitContainers) == 0 {
+		return nil
+	}
+	requests, _ := resourceapi.PodRequestsAndLimits(pod)
+	steadyStateRequests := corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		steadyStateRequests = quotav1.Add(steadyStateRequests, pod.Spec.Containers[i].Resources.Requests)
+	}
+	if pod.Spec.Overhead != nil {
+		steadyStateRequests = quotav1.Add(steadyStateRequests, pod.Spec.Overhead)
+	}
+	initOnlyRequests := quotav1.SubtractWithNonNegativeResult(requests, steadyStateRequests)
+	if quotav1.IsZero(initOnlyRequests) {
+		return nil
+	}
+	return quotav1.RemoveZeros(initOnlyRequests)
+}
+
+// reclaimNUMANodeResources returns a copy of the NUMA Node resources with the reclaimed resources subtracted.
+// The resources are released from the last NUMA Node backwards, which keeps the steady-state allocation
+// on the NUMA Nodes preferred by the hint.
+func reclaimNUMANodeResources(numaNodeResources []NUMANodeResource, reclaimed corev1.ResourceList) []NUMANodeResource {
+	reclaimed = reclaimed.DeepCopy()
+	result := make([]NUMANodeResource, len(numaNodeResources))
+	for i := len(numaNodeResources) - 1; i >= 0; i-- {
+		resources := numaNodeResources[i].Resources.DeepCopy()
+		for resourceName, quantity := range reclaimed {
+			allocated, ok := resources[resourceName]
+			if !ok || quantity.IsZero() {
+				continue
+			}
+			resources[resourceName], reclaimed[resourceName], _ = allocateRes(allocated, quantity)
+		}
+		result[i] = NUMANodeResource{
+			Node:      numaNodeResources[i].Node,
+			Resources: quotav1.RemoveZeros(resources),
+		}
+	}
+	steadyState := result[:0]
+	for _, v := range result {
+		if !quotav1.IsZero(v.Resources) {
+			steadyState = append(steadyState, v)
+		}
+	}
+	return steadyState
+}
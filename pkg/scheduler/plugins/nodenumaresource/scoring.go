@@ -29,21 +29,22 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 // resourceStrategyTypeMap maps strategy to scorer implementation
 var resourceStrategyTypeMap = map[schedulingconfig.ScoringStrategyType]scorer{
-	schedulingconfig.LeastAllocated: func(args *schedulingconfig.NodeNUMAResourceArgs) *resourceAllocationScorer {
-		resToWeightMap := resourcesToWeightMap(args.ScoringStrategy.Resources)
+	schedulingconfig.LeastAllocated: func(strategy *schedulingconfig.ScoringStrategy) *resourceAllocationScorer {
+		resToWeightMap := resourcesToWeightMap(strategy.Resources)
 		return &resourceAllocationScorer{
 			Name:                string(schedconfig.LeastAllocated),
 			scorer:              leastResourceScorer(resToWeightMap),
 			resourceToWeightMap: resToWeightMap,
 		}
 	},
-	schedulingconfig.MostAllocated: func(args *schedulingconfig.NodeNUMAResourceArgs) *resourceAllocationScorer {
-		resToWeightMap := resourcesToWeightMap(args.ScoringStrategy.Resources)
+	schedulingconfig.MostAllocated: func(strategy *schedulingconfig.ScoringStrategy) *resourceAllocationScorer {
+		resToWeightMap := resourcesToWeightMap(strategy.Resources)
 		return &resourceAllocationScorer{
 			Name:                string(schedconfig.MostAllocated),
 			scorer:              mostResourceScorer(resToWeightMap),
@@ -63,8 +64,17 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 	node := nodeInfo.Node()
+	if extension.IsNodeVirtualTopology(node.Labels) {
+		return 0, nil
+	}
 	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(node.Name)
-	numaTopologyPolicy := getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy)
+	if resourcemanager.GetBrokenTopologyReason(node, &topologyOptions) != "" {
+		return 0, nil
+	}
+	numaTopologyPolicy, err := mergeNUMATopologyPolicy(getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy), state.podNUMATopologyPolicy)
+	if err != nil {
+		return 0, nil
+	}
 
 	if skipTheNode(state, numaTopologyPolicy) {
 		if state.skip {
@@ -88,11 +98,43 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 		return 0, nil
 	}
 
+	podRequests := framework.NewResource(resourceOptions.Requests)
 	allocatable, requested := p.calculateAllocatableAndRequested(node.Name, nodeInfo, podAllocation, resourceOptions)
-	return p.scorer.score(requested, allocatable, framework.NewResource(resourceOptions.requests))
+	if p.numaScorer == nil || len(podAllocation.NUMANodeResources) == 0 {
+		score, status := p.scorer.score(requested, allocatable, podRequests)
+		if !status.IsSuccess() {
+			return 0, status
+		}
+		score = composeNUMACacheLocalityScore(score, node, pod, podAllocation, topologyOptions.CPUTopology)
+		return p.composeSocketBalanceScore(score, node.Name, podAllocation, resourceOptions), nil
+	}
+
+	// compose the score of the allocated NUMA Nodes with the score of the full node resources
+	numaScore, status := p.numaScorer.score(requested, allocatable, podRequests)
+	if !status.IsSuccess() {
+		return 0, status
+	}
+	nodePodAllocation := *podAllocation
+	nodePodAllocation.NUMANodeResources = nil
+	allocatable, requested = p.calculateAllocatableAndRequested(node.Name, nodeInfo, &nodePodAllocation, resourceOptions)
+	nodeScore, status := p.scorer.score(requested, allocatable, podRequests)
+	if !status.IsSuccess() {
+		return 0, status
+	}
+	score := composeScores(nodeScore, p.scorer.weight, numaScore, p.numaScorer.weight)
+	score = composeNUMACacheLocalityScore(score, node, pod, podAllocation, topologyOptions.CPUTopology)
+	return p.composeSocketBalanceScore(score, node.Name, podAllocation, resourceOptions), nil
+}
+
+// composeScores returns the weighted average of the node score and the NUMA score.
+func composeScores(nodeScore, nodeWeight, numaScore, numaWeight int64) int64 {
+	if nodeWeight+numaWeight <= 0 {
+		return nodeScore
+	}
+	return (nodeScore*nodeWeight + numaScore*numaWeight) / (nodeWeight + numaWeight)
 }
 
-func (p *Plugin) scoreWithAmplifiedCPUs(cycleState *framework.CycleState, state *preFilterState, pod *corev1.Pod, nodeInfo *framework.NodeInfo, topologyOptions TopologyOptions) (int64, *framework.Status) {
+func (p *Plugin) scoreWithAmplifiedCPUs(cycleState *framework.CycleState, state *preFilterState, pod *corev1.Pod, nodeInfo *framework.NodeInfo, topologyOptions resourcemanager.TopologyOptions) (int64, *framework.Status) {
 	node := nodeInfo.Node()
 	resourceOptions, err := p.getResourceOptions(cycleState, state, node, pod, topologymanager.NUMATopologyHint{}, topologyOptions)
 	if err != nil {
@@ -100,42 +142,39 @@ func (p *Plugin) scoreWithAmplifiedCPUs(cycleState *framework.CycleState, state
 	}
 
 	quantity := state.requests[corev1.ResourceCPU]
-	cpuAmplificationRatio := resourceOptions.topologyOptions.AmplificationRatios[corev1.ResourceCPU]
+	cpuAmplificationRatio := resourceOptions.TopologyOptions.AmplificationRatios[corev1.ResourceCPU]
 	if quantity.IsZero() || cpuAmplificationRatio <= 1 {
-		return p.scorer.score(nodeInfo.Requested, nodeInfo.Allocatable, framework.NewResource(resourceOptions.requests))
+		return p.scorer.score(nodeInfo.Requested, nodeInfo.Allocatable, framework.NewResource(resourceOptions.Requests))
 	}
 
-	_, allocated, err := p.resourceManager.GetAvailableCPUs(node.Name, resourceOptions.preferredCPUs)
+	_, allocated, err := p.resourceManager.GetAvailableCPUs(node.Name, resourceOptions.PreferredCPUs)
 	if err != nil {
 		if err.Error() != ErrNotFoundCPUTopology {
 			return 0, nil
 		}
-		return p.scorer.score(nodeInfo.Requested, nodeInfo.Allocatable, framework.NewResource(resourceOptions.requests))
+		return p.scorer.score(nodeInfo.Requested, nodeInfo.Allocatable, framework.NewResource(resourceOptions.Requests))
 	}
 	allocatedMilliCPU := int64(allocated.CPUs().Size() * 1000)
 	requested := nodeInfo.Requested.Clone()
 	requested.MilliCPU -= allocatedMilliCPU
 	requested.MilliCPU += extension.Amplify(allocatedMilliCPU, cpuAmplificationRatio)
-	return p.scorer.score(requested, nodeInfo.Allocatable, framework.NewResource(resourceOptions.requests))
+	return p.scorer.score(requested, nodeInfo.Allocatable, framework.NewResource(resourceOptions.Requests))
 }
 
 func (p *Plugin) calculateAllocatableAndRequested(
 	nodeName string,
 	nodeInfo *framework.NodeInfo,
-	podAllocation *PodAllocation,
-	resourceOptions *ResourceOptions,
+	podAllocation *resourcemanager.PodAllocation,
+	resourceOptions *resourcemanager.ResourceOptions,
 ) (allocatable, requested *framework.Resource) {
 	nodeAllocation := p.resourceManager.GetNodeAllocation(nodeName)
-	nodeAllocation.lock.RLock()
-	defer nodeAllocation.lock.RUnlock()
-
-	topologyOptions := resourceOptions.topologyOptions
+	topologyOptions := resourceOptions.TopologyOptions
 
 	if len(podAllocation.NUMANodeResources) > 0 {
 		totalAllocatable := corev1.ResourceList{}
 		totalRequested := corev1.ResourceList{}
 
-		_, allocatedByNode := nodeAllocation.getAvailableNUMANodeResources(topologyOptions, resourceOptions.reusableResources)
+		_, allocatedByNode := nodeAllocation.GetAvailableNUMANodeResources(topologyOptions, resourceOptions.ReusableResources)
 		for _, v := range podAllocation.NUMANodeResources {
 			if allocated := allocatedByNode[v.Node]; len(allocated) > 0 {
 				util.AddResourceList(totalRequested, allocated)
@@ -159,8 +198,8 @@ func (p *Plugin) calculateAllocatableAndRequested(
 	}
 
 	if !podAllocation.CPUSet.IsEmpty() {
-		preferred := resourceOptions.preferredCPUs.Difference(podAllocation.CPUSet)
-		_, allocatedCPUSets := nodeAllocation.getAvailableCPUs(topologyOptions.CPUTopology, topologyOptions.MaxRefCount, topologyOptions.ReservedCPUs, preferred)
+		preferred := resourceOptions.PreferredCPUs.Difference(podAllocation.CPUSet)
+		_, allocatedCPUSets := nodeAllocation.GetAvailableCPUs(topologyOptions.CPUTopology, topologyOptions.MaxRefCount, topologyOptions.ReservedCPUs, preferred)
 		cpus := allocatedCPUSets.CPUs().Size()
 		requested.MilliCPU = extension.Amplify(int64(cpus*1000), topologyOptions.AmplificationRatios[corev1.ResourceCPU])
 	}
@@ -175,13 +214,29 @@ func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
 type resourceToWeightMap map[corev1.ResourceName]int64
 
 // scorer is decorator for resourceAllocationScorer
-type scorer func(args *schedulingconfig.NodeNUMAResourceArgs) *resourceAllocationScorer
+type scorer func(strategy *schedulingconfig.ScoringStrategy) *resourceAllocationScorer
+
+// newResourceAllocationScorer builds the resourceAllocationScorer of the strategy.
+func newResourceAllocationScorer(strategy *schedulingconfig.ScoringStrategy) (*resourceAllocationScorer, error) {
+	scorePlugin, exists := resourceStrategyTypeMap[strategy.Type]
+	if !exists {
+		return nil, fmt.Errorf("scoring strategy %s is not supported", strategy.Type)
+	}
+	r := scorePlugin(strategy)
+	r.weight = strategy.Weight
+	if r.weight <= 0 {
+		r.weight = 1
+	}
+	return r, nil
+}
 
 // resourceAllocationScorer contains information to calculate resource allocation score.
 type resourceAllocationScorer struct {
 	Name                string
 	scorer              func(requested, allocatable resourceToValueMap) int64
 	resourceToWeightMap resourceToWeightMap
+	// weight is the weight of the score when composed with the other scorers
+	weight int64
 }
 
 // resourceToValueMap is keyed with resource name and valued with quantity.
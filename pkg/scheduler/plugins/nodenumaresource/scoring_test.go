@@ -34,6 +34,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	schedulerconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
@@ -53,6 +54,7 @@ func TestNUMANodeScore(t *testing.T) {
 		existingPods   []*corev1.Pod
 		expectedScores framework.NodeScoreList
 		strategy       *schedulerconfig.ScoringStrategy
+		numaStrategy   *schedulerconfig.ScoringStrategy
 	}{
 		{
 			name: "single numa nodes score",
@@ -297,6 +299,78 @@ func TestNUMANodeScore(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "compose LeastAllocated node score with MostAllocated numa nodes score",
+			nodes: []*corev1.Node{
+				st.MakeNode().Name("test-node-1").
+					Capacity(map[corev1.ResourceName]string{"cpu": "104", "memory": "256Gi"}).
+					Label(apiext.LabelNUMATopologyPolicy, string(apiext.NUMATopologyPolicyRestricted)).
+					Obj(),
+				st.MakeNode().Name("test-node-2").
+					Capacity(map[corev1.ResourceName]string{"cpu": "104", "memory": "256Gi"}).
+					Label(apiext.LabelNUMATopologyPolicy, string(apiext.NUMATopologyPolicyRestricted)).
+					Obj(),
+				st.MakeNode().Name("test-node-3").
+					Capacity(map[corev1.ResourceName]string{"cpu": "104", "memory": "256Gi"}).
+					Label(apiext.LabelNUMATopologyPolicy, string(apiext.NUMATopologyPolicyRestricted)).
+					Obj(),
+			},
+			numaNodeCounts: map[string]int{
+				"test-node-1": 2,
+				"test-node-2": 2,
+				"test-node-3": 2,
+			},
+			requestedPod: st.MakePod().Req(map[corev1.ResourceName]string{"cpu": "4", "memory": "40Gi"}).Obj(),
+			existingPods: []*corev1.Pod{
+				st.MakePod().Node("test-node-1").Req(map[corev1.ResourceName]string{"cpu": "4", "memory": "8Gi"}).Obj(),
+				st.MakePod().Node("test-node-2").Req(map[corev1.ResourceName]string{"cpu": "8", "memory": "32Gi"}).Obj(),
+				st.MakePod().Node("test-node-3").Req(map[corev1.ResourceName]string{"cpu": "32", "memory": "40Gi"}).Obj(),
+			},
+			strategy: &schedulerconfig.ScoringStrategy{
+				Type: schedulerconfig.LeastAllocated,
+				Resources: []config.ResourceSpec{
+					{
+						Name:   string(corev1.ResourceCPU),
+						Weight: 1,
+					},
+					{
+						Name:   string(corev1.ResourceMemory),
+						Weight: 1,
+					},
+				},
+				Weight: 1,
+			},
+			numaStrategy: &schedulerconfig.ScoringStrategy{
+				Type: schedulerconfig.MostAllocated,
+				Resources: []config.ResourceSpec{
+					{
+						Name:   string(corev1.ResourceCPU),
+						Weight: 1,
+					},
+					{
+						Name:   string(corev1.ResourceMemory),
+						Weight: 1,
+					},
+				},
+				Weight: 1,
+			},
+			// the node scores are all 90 since the existing pods are not in the snapshot,
+			// and the numa nodes scores are 26, 39 and 65
+			expectedScores: []framework.NodeScore{
+				{
+					Name:  "test-node-1",
+					Score: 58,
+				},
+				{
+					Name:  "test-node-2",
+					Score: 64,
+				},
+				{
+					Name:  "test-node-3",
+					Score: 77,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -305,6 +379,7 @@ func TestNUMANodeScore(t *testing.T) {
 			if tt.strategy != nil {
 				suit.nodeNUMAResourceArgs.ScoringStrategy = tt.strategy
 			}
+			suit.nodeNUMAResourceArgs.NUMAScoringStrategy = tt.numaStrategy
 			p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
 			assert.NoError(t, err)
 			pl := p.(*Plugin)
@@ -314,11 +389,11 @@ func TestNUMANodeScore(t *testing.T) {
 				if count > 0 {
 					cpusPerNUMANode := node.Status.Allocatable.Cpu().MilliValue() / int64(count)
 					memoryPerNUMANode := node.Status.Allocatable.Memory().Value() / int64(count)
-					pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+					pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
 						cores := node.Status.Allocatable.Cpu().MilliValue() / 1000 / 2 / int64(count)
 						options.CPUTopology = buildCPUTopologyForTest(count, 1, int(cores), 2)
 						for i := 0; i < count; i++ {
-							options.NUMANodeResources = append(options.NUMANodeResources, NUMANodeResource{
+							options.NUMANodeResources = append(options.NUMANodeResources, resourcemanager.NUMANodeResource{
 								Node: i,
 								Resources: corev1.ResourceList{
 									corev1.ResourceCPU:    *resource.NewMilliQuantity(cpusPerNUMANode, resource.DecimalSI),
@@ -337,12 +412,12 @@ func TestNUMANodeScore(t *testing.T) {
 						builder.Add(i)
 					}
 				}
-				pl.resourceManager.Update(v.Spec.NodeName, &PodAllocation{
+				pl.resourceManager.Update(v.Spec.NodeName, &resourcemanager.PodAllocation{
 					UID:       v.UID,
 					Namespace: v.Namespace,
 					Name:      v.Name,
 					CPUSet:    builder.Result(),
-					NUMANodeResources: []NUMANodeResource{
+					NUMANodeResources: []resourcemanager.NUMANodeResource{
 						{
 							Node:      0,
 							Resources: v.Spec.Containers[0].Resources.Requests,
@@ -376,7 +451,7 @@ func TestPlugin_Score(t *testing.T) {
 		nodeLabels  map[string]string
 		state       *preFilterState
 		pod         *corev1.Pod
-		cpuTopology *CPUTopology
+		cpuTopology *resourcemanager.CPUTopology
 		want        *framework.Status
 		wantScore   int64
 	}{
@@ -399,7 +474,7 @@ func TestPlugin_Score(t *testing.T) {
 			state: &preFilterState{
 				requestCPUBind: true,
 			},
-			cpuTopology: &CPUTopology{},
+			cpuTopology: &resourcemanager.CPUTopology{},
 			pod:         &corev1.Pod{},
 			want:        nil,
 			wantScore:   0,
@@ -556,16 +631,12 @@ func TestPlugin_Score(t *testing.T) {
 			assert.Nil(t, err)
 
 			plg := p.(*Plugin)
-			allocateState := NewNodeAllocation("test-node-1")
 			if tt.cpuTopology != nil {
-				plg.topologyOptionsManager.UpdateTopologyOptions(allocateState.nodeName, func(options *TopologyOptions) {
+				plg.topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
 					options.CPUTopology = tt.cpuTopology
 				})
 			}
 
-			cpuManager := plg.resourceManager.(*resourceManager)
-			cpuManager.nodeAllocations[allocateState.nodeName] = allocateState
-
 			suit.start()
 
 			cycleState := framework.NewCycleState()
@@ -601,7 +672,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 		requestedPod  *corev1.Pod
 		nodes         []*corev1.Node
 		existingPods  []*corev1.Pod
-		cpuTopologies map[string]*CPUTopology
+		cpuTopologies map[string]*resourcemanager.CPUTopology
 		nodeHasNRT    []string
 		nodeRatios    map[string]extension.Ratio
 		wantScoreList framework.NodeScoreList
@@ -631,7 +702,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 			},
 			nodeRatios: map[string]apiext.Ratio{"node1": 1.0, "node2": 2.0},
 			nodeHasNRT: []string{"node1", "node2"},
-			cpuTopologies: map[string]*CPUTopology{
+			cpuTopologies: map[string]*resourcemanager.CPUTopology{
 				"node1": buildCPUTopologyForTest(2, 1, 8, 2),
 				"node2": buildCPUTopologyForTest(2, 1, 8, 2),
 			},
@@ -656,7 +727,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 			},
 			nodeRatios: map[string]apiext.Ratio{"node1": 1.0, "node2": 2.0},
 			nodeHasNRT: []string{"node1", "node2"},
-			cpuTopologies: map[string]*CPUTopology{
+			cpuTopologies: map[string]*resourcemanager.CPUTopology{
 				"node1": buildCPUTopologyForTest(2, 1, 8, 2),
 				"node2": buildCPUTopologyForTest(2, 1, 8, 2),
 			},
@@ -681,7 +752,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 			},
 			nodeRatios: map[string]apiext.Ratio{"node1": 1.0, "node2": 2.0},
 			nodeHasNRT: []string{"node1", "node2"},
-			cpuTopologies: map[string]*CPUTopology{
+			cpuTopologies: map[string]*resourcemanager.CPUTopology{
 				"node1": buildCPUTopologyForTest(2, 1, 8, 2),
 				"node2": buildCPUTopologyForTest(2, 1, 8, 2),
 			},
@@ -726,7 +797,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 			},
 			nodeRatios: map[string]apiext.Ratio{"node1": 1.0, "node2": 2.0},
 			nodeHasNRT: []string{"node1", "node2"},
-			cpuTopologies: map[string]*CPUTopology{
+			cpuTopologies: map[string]*resourcemanager.CPUTopology{
 				"node1": buildCPUTopologyForTest(2, 1, 8, 2),
 				"node2": buildCPUTopologyForTest(2, 1, 8, 2),
 			},
@@ -751,7 +822,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 			},
 			nodeRatios: map[string]apiext.Ratio{"node1": 1.0, "node2": 2.0},
 			nodeHasNRT: []string{"node1", "node2"},
-			cpuTopologies: map[string]*CPUTopology{
+			cpuTopologies: map[string]*resourcemanager.CPUTopology{
 				"node1": buildCPUTopologyForTest(2, 1, 8, 2),
 				"node2": buildCPUTopologyForTest(2, 1, 8, 2),
 			},
@@ -776,7 +847,7 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 			},
 			nodeRatios: map[string]apiext.Ratio{"node1": 1.0, "node2": 2.0},
 			nodeHasNRT: []string{"node1", "node2"},
-			cpuTopologies: map[string]*CPUTopology{
+			cpuTopologies: map[string]*resourcemanager.CPUTopology{
 				"node1": buildCPUTopologyForTest(2, 1, 8, 2),
 				"node2": buildCPUTopologyForTest(2, 1, 8, 2),
 			},
@@ -813,18 +884,18 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 				if ratio == 0 {
 					ratio = 1
 				}
-				topologyOptions := TopologyOptions{
+				topologyOptions := resourcemanager.TopologyOptions{
 					CPUTopology: cpuTopology,
 				}
 				for i := 0; i < cpuTopology.NumNodes; i++ {
-					topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, NUMANodeResource{
+					topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, resourcemanager.NUMANodeResource{
 						Node: i,
 						Resources: corev1.ResourceList{
 							corev1.ResourceCPU:    resource.MustParse(fmt.Sprintf("%d", extension.Amplify(int64(cpuTopology.CPUsPerNode()), ratio))),
 							corev1.ResourceMemory: resource.MustParse("20Gi"),
 						}})
 				}
-				pl.topologyOptionsManager.UpdateTopologyOptions(nodeName, func(options *TopologyOptions) {
+				pl.topologyOptionsManager.UpdateTopologyOptions(nodeName, func(options *resourcemanager.TopologyOptions) {
 					*options = topologyOptions
 				})
 			}
@@ -850,3 +921,42 @@ func TestScoreWithAmplifiedCPUs(t *testing.T) {
 		})
 	}
 }
+
+func TestComposeScores(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodeScore  int64
+		nodeWeight int64
+		numaScore  int64
+		numaWeight int64
+		want       int64
+	}{
+		{
+			name:       "equal weights",
+			nodeScore:  90,
+			nodeWeight: 1,
+			numaScore:  30,
+			numaWeight: 1,
+			want:       60,
+		},
+		{
+			name:       "prefer numa packing",
+			nodeScore:  90,
+			nodeWeight: 1,
+			numaScore:  30,
+			numaWeight: 2,
+			want:       50,
+		},
+		{
+			name:      "no weights",
+			nodeScore: 90,
+			numaScore: 30,
+			want:      90,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, composeScores(tt.nodeScore, tt.nodeWeight, tt.numaScore, tt.numaWeight))
+		})
+	}
+}
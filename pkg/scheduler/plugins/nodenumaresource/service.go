@@ -20,21 +20,43 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 var _ services.APIServiceProvider = &Plugin{}
 
 type NodeResponse struct {
-	Name                       string `json:"name,omitempty"`
-	TopologyOptions            `json:",inline"`
-	RemainingNUMANodeResources []NUMANodeResource `json:"remainingNUMANodeResources"`
-	AllocatedNUMANodeResources []NUMANodeResource `json:"allocatedNUMANodeResources"`
-	AvailableCPUs              cpuset.CPUSet      `json:"availableCPUs"`
-	AllocatedCPUs              CPUDetails         `json:"allocatedCPUs"`
-	AllocatedPods              []PodAllocation    `json:"allocatedPods"`
+	Name                            string `json:"name,omitempty"`
+	resourcemanager.TopologyOptions `json:",inline"`
+	RemainingNUMANodeResources      []resourcemanager.NUMANodeResource `json:"remainingNUMANodeResources"`
+	AllocatedNUMANodeResources      []resourcemanager.NUMANodeResource `json:"allocatedNUMANodeResources"`
+	AvailableCPUs                   cpuset.CPUSet                      `json:"availableCPUs"`
+	AllocatedCPUs                   resourcemanager.CPUDetails         `json:"allocatedCPUs"`
+	AllocatedPods                   []resourcemanager.PodAllocation    `json:"allocatedPods"`
+}
+
+// TopologyOptionsResponse describes the effective topology options that the scheduler holds for the node.
+// It helps to debug the mismatches between the NodeResourceTopology reported by koordlet and the scheduler state.
+type TopologyOptionsResponse struct {
+	Name                            string `json:"name,omitempty"`
+	resourcemanager.TopologyOptions `json:",inline"`
+	// OriginalNUMANodeResources are the NUMA Node resources before amplification.
+	OriginalNUMANodeResources []resourcemanager.NUMANodeResource `json:"originalNUMANodeResources,omitempty"`
+	ValidCPUTopology          bool                               `json:"validCPUTopology"`
+	// BrokenTopologyReason is the reason why the topology is inconsistent with the node.
+	BrokenTopologyReason  string                                 `json:"brokenTopologyReason,omitempty"`
+	InconsistentResources []corev1.ResourceName                  `json:"inconsistentResources,omitempty"`
+	VirtualTopology       bool                                   `json:"virtualTopology,omitempty"`
+	NodeCPUBindPolicy     extension.NodeCPUBindPolicy            `json:"nodeCPUBindPolicy,omitempty"`
+	NUMAAllocateStrategy  schedulingconfig.NUMAAllocateStrategy  `json:"numaAllocateStrategy,omitempty"`
+	NUMAHintAllocateOrder schedulingconfig.NUMAHintAllocateOrder `json:"numaHintAllocateOrder,omitempty"`
+	ReservedFullCores     int                                    `json:"reservedFullCores"`
 }
 
 func (p *Plugin) RegisterEndpoints(group *gin.RouterGroup) {
@@ -53,7 +75,7 @@ func (p *Plugin) RegisterEndpoints(group *gin.RouterGroup) {
 			return
 		}
 		topologyOptions.NUMATopologyPolicy = getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy)
-		if err := amplifyNUMANodeResources(node, &topologyOptions); err != nil {
+		if err := resourcemanager.AmplifyNUMANodeResources(node, &topologyOptions); err != nil {
 			services.ResponseErrorMessage(c, http.StatusInternalServerError, "failed to amplify NUMANode Resources, err: %v", err)
 			return
 		}
@@ -66,33 +88,82 @@ func (p *Plugin) RegisterEndpoints(group *gin.RouterGroup) {
 		resp := dumpNodeAllocation(nodeAllocation, topologyOptions)
 		c.JSON(http.StatusOK, resp)
 	})
+	group.GET("/allocationHistory/:nodeName", func(c *gin.Context) {
+		nodeName := c.Param("nodeName")
+		records := p.resourceManager.GetAllocationHistory(nodeName)
+		if records == nil {
+			records = []resourcemanager.CPUSetAllocationRecord{}
+		}
+		c.JSON(http.StatusOK, records)
+	})
+	group.GET("/topologyOptions/:nodeName", func(c *gin.Context) {
+		nodeName := c.Param("nodeName")
+		nodeLister := p.handle.SharedInformerFactory().Core().V1().Nodes().Lister()
+		node, err := nodeLister.Get(nodeName)
+		if err != nil {
+			services.ResponseErrorMessage(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		resp, err := p.dumpTopologyOptions(node)
+		if err != nil {
+			services.ResponseErrorMessage(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+}
+
+// dumpTopologyOptions returns the topology options with the node level overrides applied as the scheduling does.
+// Unlike the node endpoint, it also returns the invalid topology options.
+func (p *Plugin) dumpTopologyOptions(node *corev1.Node) (*TopologyOptionsResponse, error) {
+	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(node.Name)
+	resp := &TopologyOptionsResponse{
+		Name:                      node.Name,
+		OriginalNUMANodeResources: topologyOptions.NUMANodeResources,
+		ValidCPUTopology:          topologyOptions.CPUTopology != nil && topologyOptions.CPUTopology.IsValid(),
+		BrokenTopologyReason:      resourcemanager.ValidateNodeTopology(node, &topologyOptions),
+		InconsistentResources:     resourcemanager.ValidateNUMANodeAllocatable(node, &topologyOptions),
+		VirtualTopology:           extension.IsNodeVirtualTopology(node.Labels),
+		NodeCPUBindPolicy:         extension.GetNodeCPUBindPolicy(node.Labels, topologyOptions.Policy),
+		NUMAAllocateStrategy:      resourcemanager.GetNUMAAllocateStrategy(node, GetDefaultNUMAAllocateStrategy(p.pluginArgs)),
+		NUMAHintAllocateOrder:     p.pluginArgs.NUMAHintAllocateOrder,
+		ReservedFullCores:         int(p.pluginArgs.ReservedFullCores),
+	}
+	if order := extension.GetNodeNUMAHintAllocateOrder(node.Labels); order != "" {
+		resp.NUMAHintAllocateOrder = order
+	}
+	if numCores, ok, err := extension.GetNodeReservedFullCores(node.Annotations); err != nil {
+		return nil, err
+	} else if ok {
+		resp.ReservedFullCores = numCores
+	}
+
+	topologyOptions.NUMATopologyPolicy = getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy)
+	if err := resourcemanager.AmplifyNUMANodeResources(node, &topologyOptions); err != nil {
+		return nil, err
+	}
+	resp.TopologyOptions = topologyOptions
+	return resp, nil
 }
 
-func dumpNodeAllocation(nodeAllocation *NodeAllocation, topologyOptions TopologyOptions) *NodeResponse {
+func dumpNodeAllocation(nodeAllocation *resourcemanager.NodeAllocation, topologyOptions resourcemanager.TopologyOptions) *NodeResponse {
 	resp := &NodeResponse{
-		Name:            nodeAllocation.nodeName,
+		Name:            nodeAllocation.NodeName(),
 		TopologyOptions: topologyOptions,
 	}
 
-	nodeAllocation.lock.RLock()
-	defer nodeAllocation.lock.RUnlock()
-	if len(nodeAllocation.allocatedPods) != 0 {
-		podAllocations := make([]PodAllocation, 0, len(nodeAllocation.allocatedPods))
-		for _, v := range nodeAllocation.allocatedPods {
-			podAllocations = append(podAllocations, v)
-		}
-		resp.AllocatedPods = podAllocations
-	}
-	resp.AvailableCPUs, resp.AllocatedCPUs = nodeAllocation.getAvailableCPUs(topologyOptions.CPUTopology, topologyOptions.MaxRefCount, topologyOptions.ReservedCPUs, cpuset.CPUSet{})
-	availableResources, allocatedResource := nodeAllocation.getAvailableNUMANodeResources(topologyOptions, nil)
+	resp.AllocatedPods = nodeAllocation.ListPodAllocations()
+	resp.AvailableCPUs, resp.AllocatedCPUs = nodeAllocation.GetAvailableCPUs(topologyOptions.CPUTopology, topologyOptions.MaxRefCount, topologyOptions.ReservedCPUs, cpuset.CPUSet{})
+	availableResources, allocatedResource := nodeAllocation.GetAvailableNUMANodeResources(topologyOptions, nil)
 	for nodeID, v := range availableResources {
-		resp.RemainingNUMANodeResources = append(resp.RemainingNUMANodeResources, NUMANodeResource{
+		resp.RemainingNUMANodeResources = append(resp.RemainingNUMANodeResources, resourcemanager.NUMANodeResource{
 			Node:      nodeID,
 			Resources: v.DeepCopy(),
 		})
 	}
 	for nodeID, v := range allocatedResource {
-		resp.AllocatedNUMANodeResources = append(resp.AllocatedNUMANodeResources, NUMANodeResource{
+		resp.AllocatedNUMANodeResources = append(resp.AllocatedNUMANodeResources, resourcemanager.NUMANodeResource{
 			Node:      nodeID,
 			Resources: v.DeepCopy(),
 		})
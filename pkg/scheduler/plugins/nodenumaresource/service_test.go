@@ -32,6 +32,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
@@ -53,7 +54,7 @@ func TestEndpointsQueryNode(t *testing.T) {
 	p.handle.SharedInformerFactory().Start(nil)
 	p.handle.SharedInformerFactory().WaitForCacheSync(nil)
 
-	topologyOptions := TopologyOptions{
+	topologyOptions := resourcemanager.TopologyOptions{
 		CPUTopology:  buildCPUTopologyForTest(2, 1, 4, 2),
 		ReservedCPUs: cpuset.MustParse("0-1"),
 		MaxRefCount:  1,
@@ -65,23 +66,23 @@ func TestEndpointsQueryNode(t *testing.T) {
 		},
 	}
 	for i := 0; i < topologyOptions.CPUTopology.NumNodes; i++ {
-		topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, NUMANodeResource{
+		topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, resourcemanager.NUMANodeResource{
 			Node: i,
 			Resources: corev1.ResourceList{
 				corev1.ResourceCPU:    *resource.NewQuantity(int64(topologyOptions.CPUTopology.CPUsPerNode()), resource.DecimalSI),
 				corev1.ResourceMemory: *resource.NewQuantity(32*1024*1024*1024, resource.BinarySI),
 			}})
 	}
-	p.topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *TopologyOptions) {
+	p.topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
 		*options = topologyOptions
 	})
 
 	podUID := uuid.NewUUID()
-	p.resourceManager.Update("test-node-1", &PodAllocation{
+	p.resourceManager.Update("test-node-1", &resourcemanager.PodAllocation{
 		UID:                podUID,
 		CPUSet:             cpuset.MustParse("2,3,4,5"),
 		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
-		NUMANodeResources: []NUMANodeResource{
+		NUMANodeResources: []resourcemanager.NUMANodeResource{
 			{
 				Node: 0,
 				Resources: corev1.ResourceList{
@@ -109,7 +110,7 @@ func TestEndpointsQueryNode(t *testing.T) {
 
 	topologyOptions.NUMANodeResources = nil
 	for i := 0; i < topologyOptions.CPUTopology.NumNodes; i++ {
-		topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, NUMANodeResource{
+		topologyOptions.NUMANodeResources = append(topologyOptions.NUMANodeResources, resourcemanager.NUMANodeResource{
 			Node: i,
 			Resources: corev1.ResourceList{
 				corev1.ResourceCPU:    resource.MustParse("12"),
@@ -119,26 +120,28 @@ func TestEndpointsQueryNode(t *testing.T) {
 	topologyOptions.AmplificationRatios = map[corev1.ResourceName]extension.Ratio{
 		corev1.ResourceCPU: 1.5,
 	}
+	topologyOptions.IsolatedCPUs = cpuset.NewCPUSet()
 
 	expectedResponse := &NodeResponse{
 		Name:            "test-node-1",
 		TopologyOptions: topologyOptions,
 		AvailableCPUs:   cpuset.MustParse("6-15"),
-		AllocatedCPUs:   CPUDetails{},
-		AllocatedPods: []PodAllocation{
+		AllocatedCPUs:   resourcemanager.CPUDetails{},
+		AllocatedPods: []resourcemanager.PodAllocation{
 			{
 				UID:                podUID,
 				CPUSet:             cpuset.MustParse("2,3,4,5"),
 				CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyNone,
-				NUMANodeResources: []NUMANodeResource{
+				NUMANodeResources: []resourcemanager.NUMANodeResource{
 					{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
 				},
+				PreferredCPUSet: cpuset.NewCPUSet(),
 			},
 		},
-		AllocatedNUMANodeResources: []NUMANodeResource{
+		AllocatedNUMANodeResources: []resourcemanager.NUMANodeResource{
 			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")}},
 		},
-		RemainingNUMANodeResources: []NUMANodeResource{
+		RemainingNUMANodeResources: []resourcemanager.NUMANodeResource{
 			{
 				Node: 0,
 				Resources: corev1.ResourceList{
@@ -162,3 +165,143 @@ func TestEndpointsQueryNode(t *testing.T) {
 	}
 	assert.Equal(t, expectedResponse, response)
 }
+
+func TestEndpointsQueryTopologyOptions(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+			Labels: map[string]string{
+				extension.LabelNodeNUMAAllocateStrategy:  string(extension.NUMALeastAllocated),
+				extension.LabelNodeNUMAHintAllocateOrder: string(extension.NUMAHintAllocateOrderMostAllocatedFirst),
+				extension.LabelNUMATopologyPolicy:        string(extension.NUMATopologyPolicySingleNUMANode),
+			},
+			Annotations: map[string]string{
+				extension.AnnotationNodeReservedFullCores: "2",
+			},
+		},
+	}
+	extension.SetNodeResourceAmplificationRatios(node, map[corev1.ResourceName]extension.Ratio{
+		corev1.ResourceCPU: 2,
+	})
+	suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+	plugin, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NoError(t, err)
+	assert.NotNil(t, plugin)
+	p := plugin.(*Plugin)
+	_ = p.handle.SharedInformerFactory().Core().V1().Nodes().Informer()
+	p.handle.SharedInformerFactory().Start(nil)
+	p.handle.SharedInformerFactory().WaitForCacheSync(nil)
+
+	// the CPU topology is not reported, but the NUMA Node resources are still dumped
+	p.topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
+		options.NUMANodeResources = []resourcemanager.NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU: *resource.NewQuantity(8, resource.DecimalSI),
+				},
+			},
+		}
+	})
+
+	engine := gin.Default()
+	p.RegisterEndpoints(engine.Group("/"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/topologyOptions/test-node-1", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	response := &TopologyOptionsResponse{}
+	err = json.NewDecoder(w.Result().Body).Decode(response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test-node-1", response.Name)
+	assert.False(t, response.ValidCPUTopology)
+	assert.Equal(t, 1, response.MaxRefCount)
+	assert.Equal(t, extension.NUMATopologyPolicySingleNUMANode, response.NUMATopologyPolicy)
+	assert.Equal(t, schedulingconfig.NUMALeastAllocated, response.NUMAAllocateStrategy)
+	assert.Equal(t, schedulingconfig.NUMAHintAllocateOrderMostAllocatedFirst, response.NUMAHintAllocateOrder)
+	assert.Equal(t, 2, response.ReservedFullCores)
+	assert.Equal(t, map[corev1.ResourceName]extension.Ratio{corev1.ResourceCPU: 2}, response.AmplificationRatios)
+	assert.Len(t, response.NUMANodeResources, 1)
+	assert.Equal(t, int64(16), response.NUMANodeResources[0].Resources.Cpu().Value())
+	assert.Len(t, response.OriginalNUMANodeResources, 1)
+	assert.Equal(t, int64(8), response.OriginalNUMANodeResources[0].Resources.Cpu().Value())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/topologyOptions/not-found-node", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+}
+
+func TestEndpointsQueryAllocationHistory(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+			Labels: map[string]string{
+				extension.LabelNodeNUMAAllocateStrategy: string(schedulingconfig.NUMAMostAllocated),
+			},
+		},
+	}
+	suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+	plugin, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NoError(t, err)
+	p := plugin.(*Plugin)
+
+	p.topologyOptionsManager.UpdateTopologyOptions("test-node-1", func(options *resourcemanager.TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 4, 2)
+		options.MaxRefCount = 1
+	})
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod-1",
+			UID:       uuid.NewUUID(),
+		},
+	}
+	options := &resourcemanager.ResourceOptions{
+		CPUBindPolicy:      schedulingconfig.CPUBindPolicyFullPCPUs,
+		CPUExclusivePolicy: schedulingconfig.CPUExclusivePolicyPCPULevel,
+	}
+	allocation := &resourcemanager.PodAllocation{
+		UID:    pod.UID,
+		CPUSet: cpuset.MustParse("0-3"),
+		NUMANodeResources: []resourcemanager.NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+		},
+	}
+	p.resourceManager.RecordAllocation(node, pod, options, allocation)
+	p.resourceManager.Update("test-node-1", allocation)
+	// the allocation without CPUSet is not recorded
+	p.resourceManager.RecordAllocation(node, pod, options, &resourcemanager.PodAllocation{UID: uuid.NewUUID()})
+
+	engine := gin.Default()
+	p.RegisterEndpoints(engine.Group("/"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/allocationHistory/test-node-1", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var records []resourcemanager.CPUSetAllocationRecord
+	err = json.NewDecoder(w.Result().Body).Decode(&records)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.False(t, records[0].Timestamp.IsZero())
+	records[0].Timestamp = metav1.Time{}
+	expectedRecord := resourcemanager.CPUSetAllocationRecord{
+		UID:                    pod.UID,
+		Namespace:              "default",
+		Name:                   "test-pod-1",
+		CPUBindPolicy:          schedulingconfig.CPUBindPolicyFullPCPUs,
+		CPUExclusivePolicy:     schedulingconfig.CPUExclusivePolicyPCPULevel,
+		NUMAAllocateStrategy:   schedulingconfig.NUMAMostAllocated,
+		NUMANodes:              []int{0},
+		CPUSet:                 cpuset.MustParse("0-3"),
+		AlternativesConsidered: 16,
+	}
+	assert.Equal(t, expectedRecord, records[0])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/allocationHistory/test-node-2", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
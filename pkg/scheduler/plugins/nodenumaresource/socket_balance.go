@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+)
+
+// composeSocketBalanceScore composes the score with the socket balance score by the SocketBalanceWeight.
+// The score is unchanged if the Pod binds no CPUs or the node has only one socket.
+func (p *Plugin) composeSocketBalanceScore(score int64, nodeName string, podAllocation *resourcemanager.PodAllocation, options *resourcemanager.ResourceOptions) int64 {
+	weight := int64(p.pluginArgs.SocketBalanceWeight)
+	cpuTopology := options.TopologyOptions.CPUTopology
+	if weight <= 0 || podAllocation.CPUSet.IsEmpty() || cpuTopology == nil || cpuTopology.NumSockets <= 1 {
+		return score
+	}
+	_, allocatedCPUs, err := p.resourceManager.GetAvailableCPUs(nodeName, options.PreferredCPUs)
+	if err != nil {
+		return score
+	}
+	balanceScore := resourcemanager.SocketBalanceScore(cpuTopology, allocatedCPUs, podAllocation.CPUSet)
+	return (score*(100-weight) + balanceScore*weight) / 100
+}
@@ -23,20 +23,25 @@ import (
 	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
 	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
 	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 )
 
 type nodeResourceTopologyEventHandler struct {
-	topologyManager TopologyOptionsManager
+	topologyManager resourcemanager.TopologyOptionsManager
+	nodeLister      corelisters.NodeLister
 }
 
-func registerNodeResourceTopologyEventHandler(informerFactory nrtinformers.SharedInformerFactory, topologyManager TopologyOptionsManager) error {
+func registerNodeResourceTopologyEventHandler(informerFactory nrtinformers.SharedInformerFactory, nodeLister corelisters.NodeLister, topologyManager resourcemanager.TopologyOptionsManager) error {
 	nodeResTopologyInformer := informerFactory.Topology().V1alpha1().NodeResourceTopologies().Informer()
 	eventHandler := &nodeResourceTopologyEventHandler{
 		topologyManager: topologyManager,
+		nodeLister:      nodeLister,
 	}
 	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), informerFactory, nodeResTopologyInformer, eventHandler)
 	return nil
@@ -98,16 +103,47 @@ func (m *nodeResourceTopologyEventHandler) OnDelete(obj interface{}) {
 	if nodeResTopology == nil {
 		return
 	}
+	m.deleteNodeResourceTopology(nodeResTopology)
+}
+
+func (m *nodeResourceTopologyEventHandler) deleteNodeResourceTopology(nodeResTopology *nrtv1alpha1.NodeResourceTopology) {
 	m.topologyManager.Delete(nodeResTopology.Name)
+	resourcemanager.DeleteBrokenNodeTopologyMetrics(nodeResTopology.Name)
+	resourcemanager.DeleteInconsistentNodeTopologyMetrics(nodeResTopology.Name)
 }
 
 func (m *nodeResourceTopologyEventHandler) updateNodeResourceTopology(oldNodeResTopology, newNodeResTopology *nrtv1alpha1.NodeResourceTopology) {
-	topologyOpts := NewTopologyOptions(newNodeResTopology)
+	if !newNodeResTopology.DeletionTimestamp.IsZero() {
+		// the topology being garbage collected along with its node is no longer trusted
+		m.deleteNodeResourceTopology(newNodeResTopology)
+		return
+	}
+	topologyOpts := resourcemanager.NewTopologyOptions(newNodeResTopology)
 
 	nodeName := newNodeResTopology.Name
-	m.topologyManager.UpdateTopologyOptions(nodeName, func(options *TopologyOptions) {
+	m.topologyManager.UpdateTopologyOptions(nodeName, func(options *resourcemanager.TopologyOptions) {
 		// Give other plugins a chance to customize a different MaxRefCount
 		topologyOpts.MaxRefCount = options.MaxRefCount
 		*options = topologyOpts
 	})
+	resourcemanager.DeleteBrokenNodeTopologyMetrics(nodeName)
+	m.validateNUMANodeAllocatable(nodeName, &topologyOpts)
+}
+
+// validateNUMANodeAllocatable cross-checks the updated NodeResourceTopology with the node and marks the inconsistent
+// resources. The node not synced yet is validated again by the scheduling.
+func (m *nodeResourceTopologyEventHandler) validateNUMANodeAllocatable(nodeName string, topologyOptions *resourcemanager.TopologyOptions) {
+	if m.nodeLister == nil {
+		return
+	}
+	node, err := m.nodeLister.Get(nodeName)
+	if err != nil {
+		return
+	}
+	inconsistentResources := resourcemanager.ValidateNUMANodeAllocatable(node, topologyOptions)
+	if len(inconsistentResources) > 0 {
+		klog.Warningf("NodeResourceTopology %s is inconsistent with the node allocatable, resources: %v, NUMA Node allocatable: %v",
+			nodeName, inconsistentResources, topologyOptions.NUMANodeTotalAllocatable)
+	}
+	resourcemanager.RecordInconsistentNodeTopology(nodeName, inconsistentResources)
 }
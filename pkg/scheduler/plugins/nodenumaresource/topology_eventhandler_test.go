@@ -24,13 +24,84 @@ import (
 
 	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
+func TestNodeResourceTopologyEventHandlerInvalidateDeleting(t *testing.T) {
+	topologyManager := resourcemanager.NewTopologyOptionsManager()
+	handler := &nodeResourceTopologyEventHandler{topologyManager: topologyManager}
+
+	nrt := &nrtv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+	}
+	handler.OnAdd(nrt)
+	assert.Equal(t, 1, topologyManager.GetTopologyOptions("test-node-1").MaxRefCount)
+
+	deleting := nrt.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	handler.OnUpdate(nrt, deleting)
+	assert.Equal(t, resourcemanager.TopologyOptions{}, topologyManager.GetTopologyOptions("test-node-1"))
+}
+
+func TestNodeResourceTopologyEventHandlerValidateNUMANodeAllocatable(t *testing.T) {
+	resourcemanager.RegisterMetrics()
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+		},
+	}
+	assert.NoError(t, nodeIndexer.Add(node))
+	handler := &nodeResourceTopologyEventHandler{
+		topologyManager: resourcemanager.NewTopologyOptionsManager(),
+		nodeLister:      corelisters.NewNodeLister(nodeIndexer),
+	}
+	resourcemanager.DeleteInconsistentNodeTopologyMetrics(node.Name)
+
+	buildZone := func(name string, cpu, memory string) nrtv1alpha1.Zone {
+		return nrtv1alpha1.Zone{
+			Name: name,
+			Type: "Node",
+			Resources: nrtv1alpha1.ResourceInfoList{
+				{Name: string(corev1.ResourceCPU), Allocatable: resource.MustParse(cpu)},
+				{Name: string(corev1.ResourceMemory), Allocatable: resource.MustParse(memory)},
+			},
+		}
+	}
+	nrt := &nrtv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		Zones: nrtv1alpha1.ZoneList{
+			buildZone("node-0", "8", "32Gi"),
+			buildZone("node-1", "4", "32Gi"),
+		},
+	}
+	handler.OnAdd(nrt)
+	inconsistent, err := testutil.GetGaugeMetricValue(resourcemanager.InconsistentNodeTopology.WithLabelValues(node.Name, string(corev1.ResourceCPU)))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), inconsistent)
+
+	resolved := nrt.DeepCopy()
+	resolved.Zones[1] = buildZone("node-1", "8", "32Gi")
+	handler.OnUpdate(nrt, resolved)
+	// the resolved resource is reset
+	assert.False(t, resourcemanager.InconsistentNodeTopology.Delete(map[string]string{"node": node.Name, "resource": string(corev1.ResourceCPU)}))
+}
+
 func TestTopologyOptionsManager(t *testing.T) {
 	suit := newPluginTestSuit(t, nil, nil)
 
@@ -82,6 +153,12 @@ func TestTopologyOptionsManager(t *testing.T) {
 	nodeReservationData, err := json.Marshal(nodeReservation)
 	assert.NoError(t, err)
 
+	kernelCPUIsolation := &extension.KernelCPUIsolation{
+		IsolCPUs: "14-15",
+	}
+	kernelCPUIsolationData, err := json.Marshal(kernelCPUIsolation)
+	assert.NoError(t, err)
+
 	nodeName := "test-node-1"
 	topology := &nrtv1alpha1.NodeResourceTopology{
 		ObjectMeta: metav1.ObjectMeta{
@@ -92,6 +169,8 @@ func TestTopologyOptionsManager(t *testing.T) {
 				extension.AnnotationNodeCPUAllocs:           string(podAllocsData),
 				extension.AnnotationNodeSystemQOSResource:   string(systemQOSResourceData),
 				extension.AnnotationNodeReservation:         string(nodeReservationData),
+				extension.AnnotationNodeKernelCPUIsolation:  string(kernelCPUIsolationData),
+				extension.AnnotationNodeHousekeepingCPUs:    `{"cpuset":"8-9"}`,
 			},
 		},
 	}
@@ -99,7 +178,7 @@ func TestTopologyOptionsManager(t *testing.T) {
 	_, err = suit.NRTClientset.TopologyV1alpha1().NodeResourceTopologies().Create(context.TODO(), topology, metav1.CreateOptions{})
 	assert.NoError(t, err)
 
-	topologyOptionsManager := NewTopologyOptionsManager()
+	topologyOptionsManager := resourcemanager.NewTopologyOptionsManager()
 	assert.NotNil(t, topologyOptionsManager)
 
 	extendHandle := &frameworkHandleExtender{
@@ -108,7 +187,7 @@ func TestTopologyOptionsManager(t *testing.T) {
 	}
 	nrtInformerFactory, err := initNRTInformerFactory(extendHandle)
 	assert.NoError(t, err)
-	err = registerNodeResourceTopologyEventHandler(nrtInformerFactory, topologyOptionsManager)
+	err = registerNodeResourceTopologyEventHandler(nrtInformerFactory, nil, topologyOptionsManager)
 	assert.NoError(t, err)
 
 	suit.start()
@@ -127,8 +206,10 @@ func TestTopologyOptionsManager(t *testing.T) {
 
 	assert.Equal(t, 1, topologyOptions.MaxRefCount)
 
-	expectReservedCPUs := cpuset.MustParse("0-7")
+	expectReservedCPUs := cpuset.MustParse("0-9")
 	assert.Equal(t, expectReservedCPUs, topologyOptions.ReservedCPUs)
+	assert.Equal(t, cpuset.MustParse("14-15"), topologyOptions.IsolatedCPUs)
+	assert.Equal(t, "0-1,4-7", topologyOptions.SystemReservedCPUs.String())
 
 	delete(topology.Annotations, extension.AnnotationNodeCPUAllocs)
 	_, err = suit.NRTClientset.TopologyV1alpha1().NodeResourceTopologies().Update(context.TODO(), topology, metav1.UpdateOptions{})
@@ -136,11 +217,11 @@ func TestTopologyOptionsManager(t *testing.T) {
 
 	time.Sleep(100 * time.Millisecond)
 	topologyOptions = topologyOptionsManager.GetTopologyOptions(nodeName)
-	assert.Equal(t, "0-1,4-7", topologyOptions.ReservedCPUs.String())
+	assert.Equal(t, "0-1,4-9", topologyOptions.ReservedCPUs.String())
 
 	err = suit.NRTClientset.TopologyV1alpha1().NodeResourceTopologies().Delete(context.TODO(), topology.Name, metav1.DeleteOptions{})
 	assert.NoError(t, err)
 	time.Sleep(100 * time.Millisecond)
 	topologyOptions = topologyOptionsManager.GetTopologyOptions(nodeName)
-	assert.Equal(t, TopologyOptions{}, topologyOptions)
+	assert.Equal(t, resourcemanager.TopologyOptions{}, topologyOptions)
 }
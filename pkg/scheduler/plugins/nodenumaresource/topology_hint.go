@@ -20,18 +20,21 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
-func (p *Plugin) FilterByNUMANode(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string, policyType apiext.NUMATopologyPolicy, topologyOptions TopologyOptions) *framework.Status {
+func (p *Plugin) FilterByNUMANode(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string, policyType apiext.NUMATopologyPolicy, topologyOptions resourcemanager.TopologyOptions) *framework.Status {
 	if policyType == apiext.NUMATopologyPolicyNone {
 		return nil
 	}
-	numaNodes := topologyOptions.getNUMANodes()
+	numaNodes := topologyOptions.GetNUMANodes()
 	if len(numaNodes) == 0 {
 		return framework.NewStatus(framework.UnschedulableAndUnresolvable, "node(s) missing NUMA resources")
 	}
@@ -43,6 +46,10 @@ func (p *Plugin) GetPodTopologyHints(ctx context.Context, cycleState *framework.
 	if !status.IsSuccess() {
 		return nil, status
 	}
+	if state.useReservedCPUs {
+		// the reserved CPUs don't consume the NUMA resources of the workloads
+		return nil, nil
+	}
 	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
 	if err != nil {
 		return nil, framework.AsStatus(err)
@@ -54,6 +61,17 @@ func (p *Plugin) GetPodTopologyHints(ctx context.Context, cycleState *framework.
 	if err != nil {
 		return nil, framework.AsStatus(err)
 	}
+	if state.interleaveMemory {
+		numaTopologyPolicy, err := mergeNUMATopologyPolicy(getNUMATopologyPolicy(node.Labels, topologyOptions.NUMATopologyPolicy), state.podNUMATopologyPolicy)
+		if err != nil {
+			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNUMATopologyPolicyMismatch)
+		}
+		resourceOptions.InterleaveMemory = preferSpreadingNUMANodes(numaTopologyPolicy)
+	}
+	// the topology manager cancels the context when the provider times out, so skip generating the hints then
+	if err := ctx.Err(); err != nil {
+		return nil, framework.AsStatus(err)
+	}
 	hints, err := p.resourceManager.GetTopologyHints(node, pod, resourceOptions)
 	if err != nil {
 		return nil, framework.NewStatus(framework.Unschedulable, "node(s) Insufficient NUMA Node resources")
@@ -84,3 +102,73 @@ func (p *Plugin) Allocate(ctx context.Context, cycleState *framework.CycleState,
 	}
 	return nil
 }
+
+// GetAvailableNUMANodeResources returns the available resources of each NUMA Node on the node.
+// The CPU is amplified if the node has the CPU amplification ratio, and the free isolated CPUs are not available.
+// The NUMA Nodes drained for maintenance are not returned.
+func (p *Plugin) GetAvailableNUMANodeResources(nodeName string) (map[int]corev1.ResourceList, error) {
+	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(nodeName)
+	if len(topologyOptions.NUMANodeResources) == 0 {
+		return nil, nil
+	}
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if err := resourcemanager.AmplifyNUMANodeResources(nodeInfo.Node(), &topologyOptions); err != nil {
+		return nil, err
+	}
+	if err := p.excludeFreeIsolatedCPUs(nodeName, &topologyOptions); err != nil {
+		return nil, err
+	}
+	if _, err := resourcemanager.ExcludeDrainedNUMANodes(nodeInfo.Node(), &topologyOptions); err != nil {
+		return nil, err
+	}
+
+	totalAvailable, _ := p.resourceManager.GetNodeAllocation(nodeName).GetAvailableNUMANodeResources(topologyOptions, nil)
+	return totalAvailable, nil
+}
+
+// excludeFreeIsolatedCPUs removes the free isolated CPUs from the CPU of the NUMA Nodes, since the isolated CPUs can
+// only be pinned by the LSR and LSE Pods. The isolated CPUs pinned already are excluded by their allocations.
+func (p *Plugin) excludeFreeIsolatedCPUs(nodeName string, topologyOptions *resourcemanager.TopologyOptions) error {
+	if topologyOptions.IsolatedCPUs.IsEmpty() || topologyOptions.CPUTopology == nil || !topologyOptions.CPUTopology.IsValid() {
+		return nil
+	}
+	availableCPUs, _, err := p.resourceManager.GetAvailableCPUs(nodeName, cpuset.CPUSet{})
+	if err != nil {
+		return err
+	}
+	freeIsolatedCPUs := topologyOptions.CPUTopology.CPUDetails.KeepOnly(availableCPUs.Intersection(topologyOptions.IsolatedCPUs))
+	if freeIsolatedCPUs.CPUs().IsEmpty() {
+		return nil
+	}
+
+	amplificationRatio := topologyOptions.AmplificationRatios[corev1.ResourceCPU]
+	numaNodeResources := make([]resourcemanager.NUMANodeResource, 0, len(topologyOptions.NUMANodeResources))
+	for _, v := range topologyOptions.NUMANodeResources {
+		numaNode := resourcemanager.NUMANodeResource{
+			Node:      v.Node,
+			Resources: v.Resources.DeepCopy(),
+		}
+		if numCPUs := freeIsolatedCPUs.CPUsInNUMANodes(v.Node).Size(); numCPUs > 0 {
+			cpu := numaNode.Resources[corev1.ResourceCPU]
+			cpu.Sub(*resource.NewMilliQuantity(apiext.Amplify(int64(numCPUs*1000), amplificationRatio), resource.DecimalSI))
+			if cpu.Sign() < 0 {
+				cpu = *resource.NewMilliQuantity(0, resource.DecimalSI)
+			}
+			numaNode.Resources[corev1.ResourceCPU] = cpu
+		}
+		numaNodeResources = append(numaNodeResources, numaNode)
+	}
+	topologyOptions.NUMANodeResources = numaNodeResources
+	return nil
+}
+
+// preferSpreadingNUMANodes returns whether the hints spanning all the NUMA Nodes are preferred for the Pod interleaving
+// the memory under the NUMA Topology Policy. The SingleNUMANode policy only admits the hints of a single NUMA Node,
+// and the Restricted policy only admits the preferred hints merged with the other providers, so the interleaved Pod
+// keeps the minimal NUMA Nodes preferred under them to stay schedulable.
+func preferSpreadingNUMANodes(policy apiext.NUMATopologyPolicy) bool {
+	return policy == apiext.NUMATopologyPolicyNone || policy == apiext.NUMATopologyPolicyBestEffort
+}
@@ -27,6 +27,9 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 func TestReserveByNUMANode(t *testing.T) {
@@ -50,9 +53,9 @@ func TestReserveByNUMANode(t *testing.T) {
 	assert.NoError(t, err)
 	pl := p.(*Plugin)
 
-	pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+	pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
 		options.CPUTopology = buildCPUTopologyForTest(2, 1, 26, 2)
-		options.NUMANodeResources = []NUMANodeResource{
+		options.NUMANodeResources = []resourcemanager.NUMANodeResource{
 			{
 				Node: 0,
 				Resources: corev1.ResourceList{
@@ -102,11 +105,11 @@ func TestReserveByNUMANode(t *testing.T) {
 	assert.True(t, status.IsSuccess())
 	state, status := getPreFilterState(cycleState)
 	assert.True(t, status.IsSuccess())
-	expectPodAllocation := &PodAllocation{
+	expectPodAllocation := &resourcemanager.PodAllocation{
 		UID:       pod.UID,
 		Namespace: pod.Namespace,
 		Name:      pod.Name,
-		NUMANodeResources: []NUMANodeResource{
+		NUMANodeResources: []resourcemanager.NUMANodeResource{
 			{
 				Node: 0,
 				Resources: corev1.ResourceList{
@@ -118,3 +121,121 @@ func TestReserveByNUMANode(t *testing.T) {
 	}
 	assert.Equal(t, expectPodAllocation, state.allocation)
 }
+
+func TestExcludeFreeIsolatedCPUs(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("104"),
+				corev1.ResourceMemory: resource.MustParse("256Gi"),
+			},
+		},
+	}
+	suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NoError(t, err)
+	pl := p.(*Plugin)
+
+	pl.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *resourcemanager.TopologyOptions) {
+		options.CPUTopology = buildCPUTopologyForTest(2, 1, 26, 2)
+		options.IsolatedCPUs = cpuset.MustParse("0-3,52-53")
+		options.NUMANodeResources = []resourcemanager.NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("52"),
+					corev1.ResourceMemory: resource.MustParse("128Gi"),
+				},
+			},
+			{
+				Node: 1,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("52"),
+					corev1.ResourceMemory: resource.MustParse("128Gi"),
+				},
+			},
+		}
+	})
+	// the LSR Pod pinned on the isolated CPUs 2-3
+	pl.resourceManager.Update(node.Name, &resourcemanager.PodAllocation{
+		UID:    "lsr-pod",
+		CPUSet: cpuset.MustParse("2-3"),
+		NUMANodeResources: []resourcemanager.NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+		},
+	})
+
+	totalAvailable, err := pl.GetAvailableNUMANodeResources(node.Name)
+	assert.NoError(t, err)
+	expectedAvailable := map[int]corev1.ResourceList{
+		0: {
+			corev1.ResourceCPU:    resource.MustParse("48"),
+			corev1.ResourceMemory: resource.MustParse("128Gi"),
+		},
+		1: {
+			corev1.ResourceCPU:    resource.MustParse("50"),
+			corev1.ResourceMemory: resource.MustParse("128Gi"),
+		},
+	}
+	for numaNode, expected := range expectedAvailable {
+		for resourceName, quantity := range expected {
+			got := totalAvailable[numaNode][resourceName]
+			assert.True(t, quantity.Equal(got), "NUMA Node %d resource %s, expected %s, got %s",
+				numaNode, resourceName, quantity.String(), got.String())
+		}
+	}
+
+	tests := []struct {
+		name        string
+		qosClass    apiext.QoSClass
+		expectedCPU []string
+	}{
+		{
+			name:        "LS Pod can not use the isolated CPUs",
+			qosClass:    apiext.QoSLS,
+			expectedCPU: []string{"50", "50"},
+		},
+		{
+			name:        "LSR Pod can use the isolated CPUs",
+			qosClass:    apiext.QoSLSR,
+			expectedCPU: []string{"52", "52"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test",
+					Labels: map[string]string{apiext.LabelPodQoS: string(tt.qosClass)},
+				},
+			}
+			state := &preFilterState{
+				requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			}
+			topologyOptions := pl.topologyOptionsManager.GetTopologyOptions(node.Name)
+			options, err := pl.getResourceOptions(framework.NewCycleState(), state, node, pod, topologymanager.NUMATopologyHint{}, topologyOptions)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.qosClass == apiext.QoSLSR, options.AllowIsolatedCPUs)
+			for i, numaNode := range options.TopologyOptions.NUMANodeResources {
+				expected := resource.MustParse(tt.expectedCPU[i])
+				got := numaNode.Resources[corev1.ResourceCPU]
+				assert.True(t, expected.Equal(got), "NUMA Node %d, expected %s, got %s", numaNode.Node, expected.String(), got.String())
+			}
+		})
+	}
+}
+
+func TestPreferSpreadingNUMANodes(t *testing.T) {
+	assert.True(t, preferSpreadingNUMANodes(apiext.NUMATopologyPolicyNone))
+	assert.True(t, preferSpreadingNUMANodes(apiext.NUMATopologyPolicyBestEffort))
+	assert.False(t, preferSpreadingNUMANodes(apiext.NUMATopologyPolicyRestricted))
+	assert.False(t, preferSpreadingNUMANodes(apiext.NUMATopologyPolicySingleNUMANode))
+}
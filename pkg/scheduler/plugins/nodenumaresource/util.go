@@ -17,10 +17,14 @@ limitations under the License.
 package nodenumaresource
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
 )
 
 func GetDefaultNUMAAllocateStrategy(pluginArgs *schedulingconfig.NodeNUMAResourceArgs) schedulingconfig.NUMAAllocateStrategy {
@@ -31,14 +35,6 @@ func GetDefaultNUMAAllocateStrategy(pluginArgs *schedulingconfig.NodeNUMAResourc
 	return numaAllocateStrategy
 }
 
-func GetNUMAAllocateStrategy(node *corev1.Node, defaultNUMAtAllocateStrategy schedulingconfig.NUMAAllocateStrategy) schedulingconfig.NUMAAllocateStrategy {
-	numaAllocateStrategy := defaultNUMAtAllocateStrategy
-	if val := schedulingconfig.NUMAAllocateStrategy(node.Labels[extension.LabelNodeNUMAAllocateStrategy]); val != "" {
-		numaAllocateStrategy = val
-	}
-	return numaAllocateStrategy
-}
-
 func AllowUseCPUSet(pod *corev1.Pod) bool {
 	if pod == nil {
 		return false
@@ -48,6 +44,12 @@ func AllowUseCPUSet(pod *corev1.Pod) bool {
 	return (qosClass == extension.QoSLSE || qosClass == extension.QoSLSR) && priorityClass == extension.PriorityProd
 }
 
+// allowUseIsolatedCPUs checks whether the Pod can be pinned on the isolated CPUs, which are out of the shared pool.
+func allowUseIsolatedCPUs(pod *corev1.Pod) bool {
+	qosClass := extension.GetPodQoSClassRaw(pod)
+	return qosClass == extension.QoSLSE || qosClass == extension.QoSLSR
+}
+
 func getNUMATopologyPolicy(nodeLabels map[string]string, kubeletTopologyManagerPolicy extension.NUMATopologyPolicy) extension.NUMATopologyPolicy {
 	policyType := extension.GetNodeNUMATopologyPolicy(nodeLabels)
 	if policyType != extension.NUMATopologyPolicyNone {
@@ -56,31 +58,70 @@ func getNUMATopologyPolicy(nodeLabels map[string]string, kubeletTopologyManagerP
 	return kubeletTopologyManagerPolicy
 }
 
-func skipTheNode(state *preFilterState, numaTopologyPolicy extension.NUMATopologyPolicy) bool {
-	return state.skip || (!state.requestCPUBind && numaTopologyPolicy == extension.NUMATopologyPolicyNone)
+// mergeNUMATopologyPolicy returns the NUMA topology policy that the Pod should follow on the node.
+// The Pod can only specify the policy when the node does not have one or they are the same.
+func mergeNUMATopologyPolicy(nodePolicy, podPolicy extension.NUMATopologyPolicy) (extension.NUMATopologyPolicy, error) {
+	if nodePolicy != extension.NUMATopologyPolicyNone && podPolicy != extension.NUMATopologyPolicyNone && nodePolicy != podPolicy {
+		return extension.NUMATopologyPolicyNone, fmt.Errorf("NUMA topology policy of pod %q not match node %q", podPolicy, nodePolicy)
+	}
+	if podPolicy != extension.NUMATopologyPolicyNone {
+		return podPolicy, nil
+	}
+	return nodePolicy, nil
 }
 
-// amplifyNUMANodeResources amplifies the resources per NUMA Node.
-// NOTE(joseph): After the NodeResource controller supports amplifying by ratios, should remove the function.
-func amplifyNUMANodeResources(node *corev1.Node, topologyOptions *TopologyOptions) error {
-	if topologyOptions.AmplificationRatios != nil {
+// filterVirtualTopologyNode checks whether the pod can be placed on the node without real topology.
+// The CPUs are not bound on such node, so the pods requiring CPU bind policy or strict NUMA alignment are rejected.
+func filterVirtualTopologyNode(state *preFilterState) *framework.Status {
+	if state.skip {
 		return nil
 	}
-	amplificationRatios, err := extension.GetNodeResourceAmplificationRatios(node.Annotations)
-	if err != nil {
-		return err
+	if state.requiredCPUBindPolicy != "" {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrVirtualTopologyRequiredCPUBind)
 	}
-	topologyOptions.AmplificationRatios = amplificationRatios
+	if state.podNUMATopologyPolicy == extension.NUMATopologyPolicyRestricted ||
+		state.podNUMATopologyPolicy == extension.NUMATopologyPolicySingleNUMANode {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrVirtualTopologyNUMATopologyPolicy)
+	}
+	return nil
+}
 
-	numaNodeResources := make([]NUMANodeResource, 0, len(topologyOptions.NUMANodeResources))
-	for _, v := range topologyOptions.NUMANodeResources {
-		numaNode := NUMANodeResource{
-			Node:      v.Node,
-			Resources: v.Resources.DeepCopy(),
+// getFeasibleCPUBindPolicies returns the CPU bind policies that the node can satisfy for the requested CPUs.
+// The FullPCPUs policy requires the CPUs to be aligned with the physical cores, and the SpreadByPCPUs policy is
+// meaningless when the SMT is disabled or forbidden when the node requires FullPCPUsOnly.
+func getFeasibleCPUBindPolicies(nodeRequiredFullPCPUsOnly bool, topology *resourcemanager.CPUTopology, numCPUsNeeded int) []schedulingconfig.CPUBindPolicy {
+	if topology == nil || !topology.IsValid() {
+		return nil
+	}
+	var policies []schedulingconfig.CPUBindPolicy
+	cpusPerCore := topology.CPUsPerCore()
+	if numCPUsNeeded%cpusPerCore == 0 {
+		policies = append(policies, schedulingconfig.CPUBindPolicyFullPCPUs)
+	}
+	if !nodeRequiredFullPCPUsOnly && cpusPerCore > 1 {
+		policies = append(policies, schedulingconfig.CPUBindPolicySpreadByPCPUs)
+	}
+	return policies
+}
+
+// feasibleCPUBindPoliciesReason returns the reason to enumerate the feasible CPU bind policies of the node.
+func feasibleCPUBindPoliciesReason(policies []schedulingconfig.CPUBindPolicy) string {
+	if len(policies) == 0 {
+		return ErrNoFeasibleCPUBindPolicy
+	}
+	return fmt.Sprintf("node(s) support CPU bind policies %v", policies)
+}
+
+func skipTheNode(state *preFilterState, numaTopologyPolicy extension.NUMATopologyPolicy) bool {
+	return state.skip || (!state.requestCPUBind && numaTopologyPolicy == extension.NUMATopologyPolicyNone)
+}
+
+// isPodInitialized checks whether all init containers of the Pod have completed successfully.
+func isPodInitialized(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodInitialized {
+			return condition.Status == corev1.ConditionTrue
 		}
-		extension.AmplifyResourceList(numaNode.Resources, amplificationRatios)
-		numaNodeResources = append(numaNodeResources, numaNode)
 	}
-	topologyOptions.NUMANodeResources = numaNodeResources
-	return nil
+	return false
 }
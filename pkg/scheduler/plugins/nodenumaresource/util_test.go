@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource/resourcemanager"
+)
+
+func TestGetFeasibleCPUBindPolicies(t *testing.T) {
+	tests := []struct {
+		name                      string
+		nodeRequiredFullPCPUsOnly bool
+		topology                  *resourcemanager.CPUTopology
+		numCPUsNeeded             int
+		want                      []schedulingconfig.CPUBindPolicy
+		wantReason                string
+	}{
+		{
+			name:          "invalid topology",
+			topology:      &resourcemanager.CPUTopology{},
+			numCPUsNeeded: 4,
+			want:          nil,
+			wantReason:    ErrNoFeasibleCPUBindPolicy,
+		},
+		{
+			name:          "aligned cpus with SMT enabled",
+			topology:      buildCPUTopologyForTest(2, 1, 4, 2),
+			numCPUsNeeded: 4,
+			want:          []schedulingconfig.CPUBindPolicy{schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUBindPolicySpreadByPCPUs},
+			wantReason:    "node(s) support CPU bind policies [FullPCPUs SpreadByPCPUs]",
+		},
+		{
+			name:          "unaligned cpus with SMT enabled",
+			topology:      buildCPUTopologyForTest(2, 1, 4, 2),
+			numCPUsNeeded: 5,
+			want:          []schedulingconfig.CPUBindPolicy{schedulingconfig.CPUBindPolicySpreadByPCPUs},
+			wantReason:    "node(s) support CPU bind policies [SpreadByPCPUs]",
+		},
+		{
+			name:          "SpreadByPCPUs is meaningless with SMT disabled",
+			topology:      buildCPUTopologyForTest(2, 1, 4, 1),
+			numCPUsNeeded: 5,
+			want:          []schedulingconfig.CPUBindPolicy{schedulingconfig.CPUBindPolicyFullPCPUs},
+			wantReason:    "node(s) support CPU bind policies [FullPCPUs]",
+		},
+		{
+			name:                      "unaligned cpus on FullPCPUsOnly node",
+			nodeRequiredFullPCPUsOnly: true,
+			topology:                  buildCPUTopologyForTest(2, 1, 4, 2),
+			numCPUsNeeded:             5,
+			want:                      nil,
+			wantReason:                ErrNoFeasibleCPUBindPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getFeasibleCPUBindPolicies(tt.nodeRequiredFullPCPUsOnly, tt.topology, tt.numCPUsNeeded)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantReason, feasibleCPUBindPoliciesReason(got))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterhint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+const Name = "clusterschedulinghint"
+
+// hintSyncInterval is the interval to refresh the hints since the summarized nodes and pods are not watched.
+const hintSyncInterval = 30 * time.Second
+
+// ClusterSchedulingHintReconciler summarizes the free resources, the colocation headroom and the NUMA/GPU
+// fragmentation of the selected nodes into the status of ClusterSchedulingHint, which is consumed by a global
+// scheduler or queue (e.g. for federation) to choose the cluster without watching the nodes and pods.
+type ClusterSchedulingHintReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=devices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=clusterschedulinghints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=clusterschedulinghints/status,verbs=get;update;patch
+
+func (r *ClusterSchedulingHintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	hint := &schedulingv1alpha1.ClusterSchedulingHint{}
+	if err := r.Client.Get(ctx, req.NamespacedName, hint); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("failed to find cluster scheduling hint %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	snapshot, err := r.getSnapshot(ctx, hint)
+	if err != nil {
+		klog.Errorf("failed to get snapshot for cluster scheduling hint %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	status := summarize(&hint.Spec, snapshot)
+	status.ObservedGeneration = hint.Generation
+	status.LastUpdateTime = hint.Status.LastUpdateTime
+	if equality.Semantic.DeepEqual(&hint.Status, status) {
+		return ctrl.Result{RequeueAfter: hintSyncInterval}, nil
+	}
+	status.LastUpdateTime = &metav1.Time{Time: time.Now()}
+	hint.Status = *status
+	if err := r.Client.Status().Update(ctx, hint); err != nil {
+		klog.Errorf("failed to update cluster scheduling hint %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	klog.V(4).Infof("clusterschedulinghint-controller updated hint %s, nodes %d", hint.Name, status.Nodes)
+	return ctrl.Result{RequeueAfter: hintSyncInterval}, nil
+}
+
+func (r *ClusterSchedulingHintReconciler) getSnapshot(ctx context.Context, hint *schedulingv1alpha1.ClusterSchedulingHint) (*clusterSnapshot, error) {
+	selector := labels.Everything()
+	if hint.Spec.NodeSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(hint.Spec.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse node selector, err: %w", err)
+		}
+	}
+	nodeList := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodeList, &client.ListOptions{LabelSelector: selector}, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list nodes, err: %w", err)
+	}
+	snapshot := &clusterSnapshot{
+		nodes:    nodeList.Items,
+		pods:     map[string][]*corev1.Pod{},
+		nrts:     map[string]*topologyv1alpha1.NodeResourceTopology{},
+		devices:  map[string]*schedulingv1alpha1.Device{},
+		selected: map[string]bool{},
+	}
+	for i := range nodeList.Items {
+		snapshot.selected[nodeList.Items[i].Name] = true
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list pods, err: %w", err)
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName == "" || !snapshot.selected[pod.Spec.NodeName] || util.IsPodTerminated(pod) {
+			continue
+		}
+		snapshot.pods[pod.Spec.NodeName] = append(snapshot.pods[pod.Spec.NodeName], pod)
+	}
+
+	nrtList := &topologyv1alpha1.NodeResourceTopologyList{}
+	if err := r.Client.List(ctx, nrtList, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list node resource topologies, err: %w", err)
+	}
+	for i := range nrtList.Items {
+		snapshot.nrts[nrtList.Items[i].Name] = &nrtList.Items[i]
+	}
+
+	deviceList := &schedulingv1alpha1.DeviceList{}
+	if err := r.Client.List(ctx, deviceList, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list devices, err: %w", err)
+	}
+	for i := range deviceList.Items {
+		snapshot.devices[deviceList.Items[i].Name] = &deviceList.Items[i]
+	}
+	return snapshot, nil
+}
+
+func Add(mgr ctrl.Manager) error {
+	if err := topologyv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return fmt.Errorf("failed to add scheme for NodeResourceTopology, err: %w", err)
+	}
+	if err := topologyv1alpha1.AddToScheme(clientgoscheme.Scheme); err != nil {
+		return fmt.Errorf("failed to add client go scheme for NodeResourceTopology, err: %w", err)
+	}
+	reconciler := ClusterSchedulingHintReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+func (r *ClusterSchedulingHintReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&schedulingv1alpha1.ClusterSchedulingHint{}).
+		Named(Name).
+		Complete(r)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterhint
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestNode(name string, labels map[string]string, allocatable corev1.ResourceList) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status:     corev1.NodeStatus{Allocatable: allocatable},
+	}
+}
+
+func newTestPod(name, nodeName string, requests corev1.ResourceList, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{
+				{
+					Name:      "main",
+					Resources: corev1.ResourceRequirements{Requests: requests},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newTestZone(name string, cpu string) topologyv1alpha1.Zone {
+	return topologyv1alpha1.Zone{
+		Name: name,
+		Type: "Node",
+		Resources: topologyv1alpha1.ResourceInfoList{
+			{
+				Name:        string(corev1.ResourceCPU),
+				Capacity:    resource.MustParse(cpu),
+				Allocatable: resource.MustParse(cpu),
+				Available:   resource.MustParse(cpu),
+			},
+		},
+	}
+}
+
+func newTestGPU(minor int32, health bool) schedulingv1alpha1.DeviceInfo {
+	return schedulingv1alpha1.DeviceInfo{
+		Type:   schedulingv1alpha1.GPU,
+		Minor:  pointer.Int32(minor),
+		Health: health,
+	}
+}
+
+func TestClusterSchedulingHintReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = schedulingv1alpha1.AddToScheme(scheme)
+	_ = topologyv1alpha1.AddToScheme(scheme)
+
+	poolLabels := map[string]string{"pool": "test"}
+	terminatedPod := newTestPod("pod-3", "node-1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}, nil)
+	terminatedPod.Status.Phase = corev1.PodSucceeded
+	objects := []client.Object{
+		&schedulingv1alpha1.ClusterSchedulingHint{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-hint", Generation: 1},
+			Spec: schedulingv1alpha1.ClusterSchedulingHintSpec{
+				NodeSelector: &metav1.LabelSelector{MatchLabels: poolLabels},
+				Flavors: []schedulingv1alpha1.SchedulingHintFlavor{
+					{
+						Name: "4c8g",
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("4"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+					},
+				},
+			},
+		},
+		newTestNode("node-1", poolLabels, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("32"),
+			corev1.ResourceMemory: resource.MustParse("128Gi"),
+			extension.BatchCPU:    resource.MustParse("10000"),
+			extension.BatchMemory: resource.MustParse("20Gi"),
+		}),
+		newTestNode("node-2", poolLabels, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("16"),
+			corev1.ResourceMemory: resource.MustParse("64Gi"),
+		}),
+		newTestNode("node-3", nil, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("64"),
+			corev1.ResourceMemory: resource.MustParse("256Gi"),
+		}),
+		newTestPod("pod-1", "node-1", corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}, map[string]string{
+			extension.AnnotationResourceStatus:  `{"numaNodeResources":[{"node":0,"resources":{"cpu":"4"}}]}`,
+			extension.AnnotationDeviceAllocated: `{"gpu":[{"minor":0,"resources":{"koordinator.sh/gpu-core":"100"}}]}`,
+		}),
+		newTestPod("pod-2", "node-2", corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("8"),
+			corev1.ResourceMemory: resource.MustParse("32Gi"),
+		}, nil),
+		terminatedPod,
+		newTestPod("pod-4", "node-1", corev1.ResourceList{
+			extension.BatchCPU:    resource.MustParse("2000"),
+			extension.BatchMemory: resource.MustParse("4Gi"),
+		}, nil),
+		newTestPod("pod-5", "node-3", corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("8"),
+		}, nil),
+		&topologyv1alpha1.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Zones: topologyv1alpha1.ZoneList{
+				newTestZone("node-0", "16"),
+				newTestZone("node-1", "16"),
+			},
+		},
+		&schedulingv1alpha1.Device{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec: schedulingv1alpha1.DeviceSpec{
+				Devices: []schedulingv1alpha1.DeviceInfo{
+					newTestGPU(0, true),
+					newTestGPU(1, true),
+					newTestGPU(2, true),
+					newTestGPU(3, true),
+					newTestGPU(4, false),
+				},
+			},
+		},
+	}
+	r := &ClusterSchedulingHintReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-hint"}}
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, hintSyncInterval, result.RequeueAfter)
+
+	hint := &schedulingv1alpha1.ClusterSchedulingHint{}
+	assert.NoError(t, r.Client.Get(context.TODO(), req.NamespacedName, hint))
+	assert.NotNil(t, hint.Status.LastUpdateTime)
+	assert.Equal(t, int64(1), hint.Status.ObservedGeneration)
+	assert.Equal(t, int32(2), hint.Status.Nodes)
+	assert.True(t, resource.MustParse("36").Equal(hint.Status.Free[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("152Gi").Equal(hint.Status.Free[corev1.ResourceMemory]))
+	assert.Len(t, hint.Status.ColocationHeadroom, 2)
+	assert.True(t, resource.MustParse("8000").Equal(hint.Status.ColocationHeadroom[extension.BatchCPU]))
+	assert.True(t, resource.MustParse("16Gi").Equal(hint.Status.ColocationHeadroom[extension.BatchMemory]))
+
+	assert.NotNil(t, hint.Status.NUMA)
+	assert.Equal(t, int32(2), hint.Status.NUMA.NUMANodes)
+	assert.True(t, resource.MustParse("28").Equal(hint.Status.NUMA.FreeCPUs))
+	assert.True(t, resource.MustParse("16").Equal(hint.Status.NUMA.LargestFreeCPUs))
+	assert.Equal(t, int64(42), hint.Status.NUMA.FragmentationPercent)
+
+	expectedGPU := &schedulingv1alpha1.GPUFragmentation{
+		TotalGPUs:            4,
+		FreeGPUs:             3,
+		LargestFreeGPUs:      3,
+		FragmentationPercent: 100,
+	}
+	assert.Equal(t, expectedGPU, hint.Status.GPU)
+
+	expectedFlavors := []schedulingv1alpha1.SchedulingHintFlavorStatus{
+		{Name: "4c8g", LargestFit: 7, TotalFit: 9},
+	}
+	assert.Equal(t, expectedFlavors, hint.Status.Flavors)
+
+	// the unchanged status is not updated again
+	lastUpdateTime := hint.Status.LastUpdateTime.DeepCopy()
+	resourceVersion := hint.ResourceVersion
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(context.TODO(), req.NamespacedName, hint))
+	assert.Equal(t, resourceVersion, hint.ResourceVersion)
+	assert.True(t, lastUpdateTime.Equal(hint.Status.LastUpdateTime))
+}
+
+func Test_getFlavorFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		request corev1.ResourceList
+		free    corev1.ResourceList
+		want    int64
+	}{
+		{
+			name:    "empty request",
+			request: corev1.ResourceList{},
+			free:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			want:    0,
+		},
+		{
+			name:    "resource missing",
+			request: corev1.ResourceList{extension.ResourceGPUCore: resource.MustParse("100")},
+			free:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			want:    0,
+		},
+		{
+			name: "fit by the scarce resource",
+			request: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			free: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("5Gi"),
+			},
+			want: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getFlavorFit(tt.request, tt.free))
+		})
+	}
+}
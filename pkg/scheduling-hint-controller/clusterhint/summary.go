@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterhint
+
+import (
+	"math"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var colocationResources = []corev1.ResourceName{extension.BatchCPU, extension.BatchMemory}
+
+type clusterSnapshot struct {
+	nodes    []corev1.Node
+	selected map[string]bool
+	// pods are the non-terminated pods grouped by the node name
+	pods    map[string][]*corev1.Pod
+	nrts    map[string]*topologyv1alpha1.NodeResourceTopology
+	devices map[string]*schedulingv1alpha1.Device
+}
+
+// summarize calculates the status of the hint according to the snapshot of the selected nodes.
+func summarize(spec *schedulingv1alpha1.ClusterSchedulingHintSpec, snapshot *clusterSnapshot) *schedulingv1alpha1.ClusterSchedulingHintStatus {
+	status := &schedulingv1alpha1.ClusterSchedulingHintStatus{
+		Nodes: int32(len(snapshot.nodes)),
+		Free:  corev1.ResourceList{},
+	}
+	flavors := make([]schedulingv1alpha1.SchedulingHintFlavorStatus, len(spec.Flavors))
+	for i := range spec.Flavors {
+		flavors[i].Name = spec.Flavors[i].Name
+	}
+	numa := &numaSummary{}
+	gpu := &gpuSummary{}
+
+	for i := range snapshot.nodes {
+		node := &snapshot.nodes[i]
+		pods := snapshot.pods[node.Name]
+		free := getNodeFree(node, pods)
+		status.Free = quotav1.Add(status.Free, free)
+		for j := range spec.Flavors {
+			fit := getFlavorFit(spec.Flavors[j].Resources, free)
+			flavors[j].TotalFit += fit
+			if fit > flavors[j].LargestFit {
+				flavors[j].LargestFit = fit
+			}
+		}
+		if nrt := snapshot.nrts[node.Name]; nrt != nil {
+			numa.addNode(nrt, pods)
+		}
+		if device := snapshot.devices[node.Name]; device != nil {
+			gpu.addNode(device, pods)
+		}
+	}
+
+	status.ColocationHeadroom = quotav1.Mask(status.Free, colocationResources)
+	if len(flavors) > 0 {
+		status.Flavors = flavors
+	}
+	if numa.numaNodes > 0 {
+		status.NUMA = numa.fragmentation()
+	}
+	if gpu.totalGPUs > 0 {
+		status.GPU = gpu.fragmentation()
+	}
+	return status
+}
+
+// getNodeFree returns the allocatable resources of the node which are not requested by the pods.
+func getNodeFree(node *corev1.Node, pods []*corev1.Pod) corev1.ResourceList {
+	requested := corev1.ResourceList{}
+	for _, pod := range pods {
+		requested = quotav1.Add(requested, util.GetPodRequest(pod))
+	}
+	return quotav1.SubtractWithNonNegativeResult(node.Status.Allocatable, requested)
+}
+
+// getFlavorFit returns how many instances of the flavor fit into the free resources.
+func getFlavorFit(request, free corev1.ResourceList) int64 {
+	fit := int64(math.MaxInt64)
+	for resourceName, quantity := range request {
+		if quantity.IsZero() {
+			continue
+		}
+		freeQuantity := free[resourceName]
+		if n := freeQuantity.MilliValue() / quantity.MilliValue(); n < fit {
+			fit = n
+		}
+	}
+	if fit == math.MaxInt64 {
+		return 0
+	}
+	return fit
+}
+
+type numaSummary struct {
+	numaNodes int32
+	// the free CPUs are in milli
+	freeCPUs          int64
+	largestFreeCPUs   int64
+	fullyFreeNodeCPUs int64
+}
+
+// addNode accumulates the free CPUs of the NUMA nodes reported by the NodeResourceTopology, which are not allocated to
+// the pods constrained to the NUMA nodes.
+func (s *numaSummary) addNode(nrt *topologyv1alpha1.NodeResourceTopology, pods []*corev1.Pod) {
+	allocated := map[string]int64{}
+	for _, pod := range pods {
+		resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+		if err != nil {
+			klog.V(5).Infof("failed to get resource status of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for _, numaNodeResource := range resourceStatus.NUMANodeResources {
+			cpu := numaNodeResource.Resources[corev1.ResourceCPU]
+			allocated[util.GenNodeZoneName(int(numaNodeResource.Node))] += cpu.MilliValue()
+		}
+	}
+
+	var zones topologyv1alpha1.ZoneList
+	for _, zone := range nrt.Zones {
+		if zone.Type == util.NodeZoneType {
+			zones = append(zones, zone)
+		}
+	}
+	for zoneName, resourceList := range util.ZoneListToZoneResourceList(zones) {
+		cpu := resourceList[corev1.ResourceCPU]
+		free := cpu.MilliValue() - allocated[zoneName]
+		if free < 0 {
+			free = 0
+		}
+		s.numaNodes++
+		s.freeCPUs += free
+		if free > s.largestFreeCPUs {
+			s.largestFreeCPUs = free
+		}
+		if allocated[zoneName] <= 0 {
+			s.fullyFreeNodeCPUs += free
+		}
+	}
+}
+
+func (s *numaSummary) fragmentation() *schedulingv1alpha1.NUMAFragmentation {
+	return &schedulingv1alpha1.NUMAFragmentation{
+		NUMANodes:            s.numaNodes,
+		FreeCPUs:             *resource.NewMilliQuantity(s.freeCPUs, resource.DecimalSI),
+		LargestFreeCPUs:      *resource.NewMilliQuantity(s.largestFreeCPUs, resource.DecimalSI),
+		FragmentationPercent: getFragmentationPercent(s.freeCPUs, s.fullyFreeNodeCPUs),
+	}
+}
+
+type gpuSummary struct {
+	totalGPUs         int32
+	freeGPUs          int32
+	largestFreeGPUs   int32
+	fullyFreeNodeGPUs int32
+}
+
+// addNode accumulates the healthy GPUs reported by the Device, which are not allocated to the pods.
+func (s *gpuSummary) addNode(device *schedulingv1alpha1.Device, pods []*corev1.Pod) {
+	allocated := map[int32]bool{}
+	for _, pod := range pods {
+		allocations, err := extension.GetDeviceAllocations(pod.Annotations)
+		if err != nil {
+			klog.V(5).Infof("failed to get device allocations of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for _, allocation := range allocations[schedulingv1alpha1.GPU] {
+			allocated[allocation.Minor] = true
+		}
+	}
+
+	var total, free int32
+	for _, info := range device.Spec.Devices {
+		if info.Type != schedulingv1alpha1.GPU || !info.Health || info.Minor == nil {
+			continue
+		}
+		total++
+		if !allocated[*info.Minor] {
+			free++
+		}
+	}
+	s.totalGPUs += total
+	s.freeGPUs += free
+	if free > s.largestFreeGPUs {
+		s.largestFreeGPUs = free
+	}
+	if free == total {
+		s.fullyFreeNodeGPUs += free
+	}
+}
+
+func (s *gpuSummary) fragmentation() *schedulingv1alpha1.GPUFragmentation {
+	return &schedulingv1alpha1.GPUFragmentation{
+		TotalGPUs:            s.totalGPUs,
+		FreeGPUs:             s.freeGPUs,
+		LargestFreeGPUs:      s.largestFreeGPUs,
+		FragmentationPercent: getFragmentationPercent(int64(s.freeGPUs), int64(s.fullyFreeNodeGPUs)),
+	}
+}
+
+// getFragmentationPercent returns the percentage of the free resources not in the fully free units.
+func getFragmentationPercent(free, fullyFree int64) int64 {
+	if free <= 0 {
+		return 0
+	}
+	return (free - fullyFree) * 100 / free
+}
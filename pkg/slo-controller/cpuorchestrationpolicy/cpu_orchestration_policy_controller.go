@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuorchestrationpolicy
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+const Name = "cpuorchestrationpolicy"
+
+// CPUOrchestrationPolicyReconciler reports the Pods adopting a CPUOrchestrationPolicy
+type CPUOrchestrationPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.koordinator.sh,resources=cpuorchestrationpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.koordinator.sh,resources=cpuorchestrationpolicies/status,verbs=get;update;patch
+
+func (r *CPUOrchestrationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = log.FromContext(ctx, "cpu-orchestration-policy-reconciler", req.NamespacedName)
+
+	policy := &configv1alpha1.CPUOrchestrationPolicy{}
+	if err := r.Client.Get(ctx, req.NamespacedName, policy); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("failed to find CPUOrchestrationPolicy %v, error: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.MatchingLabels{extension.LabelCPUOrchestrationPolicy: policy.Name}, utilclient.DisableDeepCopy); err != nil {
+		klog.Errorf("failed to list pods adopting CPUOrchestrationPolicy %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	var adoptedPods int32
+	for i := range podList.Items {
+		if !util.IsPodTerminated(&podList.Items[i]) {
+			adoptedPods++
+		}
+	}
+
+	if policy.Status.AdoptedPods == adoptedPods && policy.Status.ObservedGeneration == policy.Generation {
+		return ctrl.Result{}, nil
+	}
+	policy = policy.DeepCopy()
+	policy.Status.AdoptedPods = adoptedPods
+	policy.Status.ObservedGeneration = policy.Generation
+	now := metav1.NewTime(time.Now())
+	policy.Status.LastUpdateTime = &now
+	if err := r.Client.Status().Update(ctx, policy); err != nil {
+		klog.Errorf("failed to update status of CPUOrchestrationPolicy %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	klog.V(4).Infof("cpuorchestrationpolicy-controller succeeded to update CPUOrchestrationPolicy %v, adoptedPods: %d", req.NamespacedName, adoptedPods)
+	return ctrl.Result{}, nil
+}
+
+func Add(mgr ctrl.Manager) error {
+	reconciler := &CPUOrchestrationPolicyReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CPUOrchestrationPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&configv1alpha1.CPUOrchestrationPolicy{}).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, &EnqueueRequestForPod{}).
+		Named(Name).
+		Complete(r)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuorchestrationpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestCPUOrchestrationPolicyReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	configv1alpha1.AddToScheme(scheme)
+
+	policy := &configv1alpha1.CPUOrchestrationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-policy",
+			Generation: 2,
+		},
+		Spec: configv1alpha1.CPUOrchestrationPolicySpec{
+			CPUBindPolicy: string(extension.CPUBindPolicyFullPCPUs),
+		},
+	}
+	newPod := func(namespace, name, policyName string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					extension.LabelCPUOrchestrationPolicy: policyName,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+			},
+		}
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		policy,
+		newPod("default", "pod-1", "test-policy", corev1.PodRunning),
+		newPod("test-ns", "pod-2", "test-policy", corev1.PodPending),
+		newPod("default", "pod-3", "test-policy", corev1.PodSucceeded),
+		newPod("default", "pod-4", "other-policy", corev1.PodRunning),
+	).Build()
+	r := &CPUOrchestrationPolicyReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-policy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+
+	got := &configv1alpha1.CPUOrchestrationPolicy{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "test-policy"}, got))
+	assert.Equal(t, int32(2), got.Status.AdoptedPods)
+	assert.Equal(t, int64(2), got.Status.ObservedGeneration)
+	assert.NotNil(t, got.Status.LastUpdateTime)
+
+	result, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "not-exists"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+}
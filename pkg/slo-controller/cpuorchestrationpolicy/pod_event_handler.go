@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuorchestrationpolicy
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+var _ handler.EventHandler = &EnqueueRequestForPod{}
+
+// EnqueueRequestForPod enqueues the CPUOrchestrationPolicy referenced by the Pod.
+type EnqueueRequestForPod struct{}
+
+func (p *EnqueueRequestForPod) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	enqueuePolicy(e.Object, q)
+}
+
+func (p *EnqueueRequestForPod) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	enqueuePolicy(e.ObjectNew, q)
+	if e.ObjectOld.GetLabels()[extension.LabelCPUOrchestrationPolicy] != e.ObjectNew.GetLabels()[extension.LabelCPUOrchestrationPolicy] {
+		enqueuePolicy(e.ObjectOld, q)
+	}
+}
+
+func (p *EnqueueRequestForPod) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	enqueuePolicy(e.Object, q)
+}
+
+func (p *EnqueueRequestForPod) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {}
+
+func enqueuePolicy(obj client.Object, q workqueue.RateLimitingInterface) {
+	if obj == nil {
+		return
+	}
+	policyName := obj.GetLabels()[extension.LabelCPUOrchestrationPolicy]
+	if policyName == "" {
+		return
+	}
+	q.Add(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name: policyName,
+		},
+	})
+}
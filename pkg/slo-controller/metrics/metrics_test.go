@@ -70,3 +70,13 @@ func TestNodeResourceCollectors(t *testing.T) {
 	RecordNodeExtendedResourceAllocatableInternal(testNode, string(extension.BatchCPU), UnitInteger, 30000)
 	RecordNodeExtendedResourceAllocatableInternal(testNode, string(extension.BatchMemory), UnitInteger, 60<<30)
 }
+
+func TestShardingCollectors(t *testing.T) {
+	testShard := "test-shard"
+	testController := "test-controller"
+	RecordShardMembers(3)
+	RecordShardOwnedNodes(testShard, 100)
+	RecordShardRebalanceCount(testShard)
+	RecordShardPendingNodes(testController, testShard, 10)
+	RecordShardReconcileLagSeconds(testController, testShard, 1.5)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	ShardKey      = "shard"
+	ControllerKey = "controller"
+)
+
+func init() {
+	MustRegister(ShardingCollectors...)
+}
+
+var (
+	ShardMembers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: SLOControllerSubsystem,
+		Name:      "shard_members",
+		Help:      "the number of the alive shards partitioning the node reconciliation",
+	})
+
+	ShardOwnedNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: SLOControllerSubsystem,
+		Name:      "shard_owned_nodes",
+		Help:      "the number of the nodes owned by the shard",
+	}, []string{ShardKey})
+
+	ShardRebalanceCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: SLOControllerSubsystem,
+		Name:      "shard_rebalance_count",
+		Help:      "the count of rebalancing the nodes after the shard members changed",
+	}, []string{ShardKey})
+
+	ShardPendingNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: SLOControllerSubsystem,
+		Name:      "shard_pending_nodes",
+		Help:      "the number of the nodes owned by the shard which are not reconciled since the last rebalance",
+	}, []string{ControllerKey, ShardKey})
+
+	ShardReconcileLagSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: SLOControllerSubsystem,
+		Name:      "shard_reconcile_lag_seconds",
+		Help:      "the lag from the rebalance to the first reconciliation of the nodes newly owned by the shard",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{ControllerKey, ShardKey})
+
+	ShardingCollectors = []prometheus.Collector{
+		ShardMembers,
+		ShardOwnedNodes,
+		ShardRebalanceCount,
+		ShardPendingNodes,
+		ShardReconcileLagSeconds,
+	}
+)
+
+func RecordShardMembers(members int) {
+	ShardMembers.Set(float64(members))
+}
+
+func RecordShardOwnedNodes(shard string, nodes int) {
+	ShardOwnedNodes.With(prometheus.Labels{ShardKey: shard}).Set(float64(nodes))
+}
+
+func RecordShardRebalanceCount(shard string) {
+	ShardRebalanceCount.With(prometheus.Labels{ShardKey: shard}).Inc()
+}
+
+func RecordShardPendingNodes(controller, shard string, nodes int) {
+	ShardPendingNodes.With(prometheus.Labels{ControllerKey: controller, ShardKey: shard}).Set(float64(nodes))
+}
+
+func RecordShardReconcileLagSeconds(controller, shard string, seconds float64) {
+	ShardReconcileLagSeconds.With(prometheus.Labels{ControllerKey: controller, ShardKey: shard}).Observe(seconds)
+}
@@ -38,10 +38,13 @@ func getNodeMetricCollectPolicy(strategy *configuration.ColocationStrategy) (*sl
 	}
 
 	collectPolicy := &slov1alpha1.NodeMetricCollectPolicy{
-		AggregateDurationSeconds: strategy.MetricAggregateDurationSeconds,
-		ReportIntervalSeconds:    strategy.MetricReportIntervalSeconds,
-		NodeAggregatePolicy:      strategy.MetricAggregatePolicy,
-		NodeMemoryCollectPolicy:  strategy.MetricMemoryCollectPolicy,
+		AggregateDurationSeconds:        strategy.MetricAggregateDurationSeconds,
+		ReportIntervalSeconds:           strategy.MetricReportIntervalSeconds,
+		NodeAggregatePolicy:             strategy.MetricAggregatePolicy,
+		NodeMemoryCollectPolicy:         strategy.MetricMemoryCollectPolicy,
+		HealthIndexWeights:              strategy.MetricHealthIndexWeights,
+		ColdMemoryReportIntervalSeconds: strategy.MetricColdMemoryReportIntervalSeconds,
+		CPUStealAnomalyThresholdPercent: strategy.MetricCPUStealAnomalyThresholdPercent,
 	}
 	return collectPolicy, nil
 }
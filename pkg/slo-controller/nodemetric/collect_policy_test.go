@@ -61,6 +61,22 @@ func Test_getNodeMetricCollectPolicy(t *testing.T) {
 				NodeMemoryCollectPolicy:  &defaultNodeMemoryCollectPolicy,
 			},
 		},
+		{
+			name: "config cold memory report",
+			config: &configuration.ColocationStrategy{
+				Enable:                                pointer.Bool(true),
+				MetricAggregateDurationSeconds:        pointer.Int64(60),
+				MetricReportIntervalSeconds:           pointer.Int64(180),
+				MetricMemoryCollectPolicy:             &defaultNodeMemoryCollectPolicy,
+				MetricColdMemoryReportIntervalSeconds: pointer.Int64(300),
+			},
+			want: &slov1alpha1.NodeMetricCollectPolicy{
+				AggregateDurationSeconds:        pointer.Int64(60),
+				ReportIntervalSeconds:           pointer.Int64(180),
+				NodeMemoryCollectPolicy:         &defaultNodeMemoryCollectPolicy,
+				ColdMemoryReportIntervalSeconds: pointer.Int64(300),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -29,6 +29,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -36,6 +37,7 @@ import (
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/sharding"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
 
@@ -47,6 +49,8 @@ type NodeMetricReconciler struct {
 	Scheme   *runtime.Scheme
 	cfgCache config.ColocationCfgCache
 	Recorder record.EventRecorder
+	// sharder is set if the nodes are partitioned across the replicas
+	sharder *sharding.Sharder
 }
 
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
@@ -67,6 +71,14 @@ func (r *NodeMetricReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	if r.sharder != nil {
+		// the node is reconciled by its owner replica
+		if !r.sharder.IsOwner(req.Name) {
+			return ctrl.Result{}, nil
+		}
+		defer r.sharder.Reconciled(Name, req.Name)
+	}
+
 	node, nodeMetric := &corev1.Node{}, &slov1alpha1.NodeMetric{}
 	nodeExist, nodeMetricExist := true, true
 	nodeName, nodeMetricName := req.Name, req.Name
@@ -171,10 +183,13 @@ func getDefaultSpec() *slov1alpha1.NodeMetricSpec {
 	defaultColocationCfg := sloconfig.NewDefaultColocationCfg()
 	return &slov1alpha1.NodeMetricSpec{
 		CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
-			AggregateDurationSeconds: defaultColocationCfg.MetricAggregateDurationSeconds,
-			ReportIntervalSeconds:    defaultColocationCfg.MetricReportIntervalSeconds,
-			NodeAggregatePolicy:      defaultColocationCfg.MetricAggregatePolicy,
-			NodeMemoryCollectPolicy:  defaultColocationCfg.MetricMemoryCollectPolicy,
+			AggregateDurationSeconds:        defaultColocationCfg.MetricAggregateDurationSeconds,
+			ReportIntervalSeconds:           defaultColocationCfg.MetricReportIntervalSeconds,
+			NodeAggregatePolicy:             defaultColocationCfg.MetricAggregatePolicy,
+			NodeMemoryCollectPolicy:         defaultColocationCfg.MetricMemoryCollectPolicy,
+			HealthIndexWeights:              defaultColocationCfg.MetricHealthIndexWeights,
+			ColdMemoryReportIntervalSeconds: defaultColocationCfg.MetricColdMemoryReportIntervalSeconds,
+			CPUStealAnomalyThresholdPercent: defaultColocationCfg.MetricCPUStealAnomalyThresholdPercent,
 		},
 	}
 }
@@ -185,6 +200,13 @@ func Add(mgr ctrl.Manager) error {
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("nodemetric-controller"),
 	}
+	if sharding.Enabled {
+		sharder, err := sharding.GetOrCreate(mgr)
+		if err != nil {
+			return err
+		}
+		reconciler.sharder = sharder
+	}
 	return reconciler.SetupWithManager(mgr)
 }
 
@@ -192,6 +214,9 @@ func Add(mgr ctrl.Manager) error {
 func (r *NodeMetricReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	handler := config.NewColocationHandlerForConfigMapEvent(r.Client, *sloconfig.NewDefaultColocationCfg(), r.Recorder)
 	r.cfgCache = handler
+	if r.sharder != nil {
+		return r.setupShardedController(mgr, handler)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&slov1alpha1.NodeMetric{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&source.Kind{Type: &corev1.Node{}}, &EnqueueRequestForNode{}).
@@ -199,3 +224,19 @@ func (r *NodeMetricReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Named(Name).
 		Complete(r)
 }
+
+// setupShardedController sets up the controller running on all replicas with the same watches.
+func (r *NodeMetricReconciler) setupShardedController(mgr ctrl.Manager, cfgHandler handler.EventHandler) error {
+	c, err := sharding.NewController(mgr, r.sharder, Name, r)
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &slov1alpha1.NodeMetric{}}, &handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{}); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.Node{}}, &EnqueueRequestForNode{}); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, cfgHandler)
+}
@@ -37,9 +37,11 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/configuration"
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
 const PluginName = "BatchResource"
@@ -204,6 +206,12 @@ func (p *Plugin) calculate(strategy *configuration.ColocationStrategy, node *cor
 	klog.V(6).InfoS("batch resource got unknown priority pods used", "node", node.Name,
 		"cpu", podUnknownPriorityUsed.Cpu().String(), "memory", podUnknownPriorityUsed.Memory().String())
 
+	// The host applications are excluded from the system usage, so count the high-priority ones into the HP used.
+	hostAppHPUsed := getHostAppHPUsed(nodeMetric)
+	podHPUsed = quotav1.Add(podHPUsed, hostAppHPUsed)
+	klog.V(6).InfoS("batch resource got high-priority host applications used", "node", node.Name,
+		"cpu", hostAppHPUsed.Cpu().String(), "memory", hostAppHPUsed.Memory().String())
+
 	nodeCapacity := getNodeCapacity(node)
 	nodeReservation := getNodeReservation(strategy, nodeCapacity)
 
@@ -263,7 +271,7 @@ func (p *Plugin) calculateOnNUMALevel(strategy *configuration.ColocationStrategy
 	// separate zone resources
 	// assert the zone is mapped into NUMA levels
 	// FIXME: Since NUMA-level metrics are not reported, we use an approximation here:
-	//        node reservation, system usage and unknown pods usage are the same in each zones.
+	//        node reservation, system usage, host applications usage and unknown pods usage are the same in each zones.
 	zoneNum := len(nrt.Zones)
 	zoneIdxMap := map[int]string{}
 	nodeMetric := resourceMetrics.NodeMetric
@@ -350,6 +358,12 @@ func (p *Plugin) calculateOnNUMALevel(strategy *configuration.ColocationStrategy
 	}
 	podHPZoneUsed = addZoneResourceList(podHPZoneUsed, podUnknownPriorityZoneUsed, zoneNum)
 
+	// Like the system usage, the high-priority host applications are regarded to use the same in each zone.
+	hostAppHPUsed := getHostAppHPUsed(nodeMetric)
+	for i := range podHPZoneUsed {
+		podHPZoneUsed[i] = quotav1.Add(podHPZoneUsed[i], divideResourceList(hostAppHPUsed, float64(zoneNum)))
+	}
+
 	batchZoneCPU := map[string]resource.Quantity{}
 	batchZoneMemory := map[string]resource.Quantity{}
 	var cpuMsg, memMsg string
@@ -380,9 +394,32 @@ func (p *Plugin) isDegradeNeeded(strategy *configuration.ColocationStrategy, nod
 		return true
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeSLOColocationReadiness) && !isColocationReady(nodeMetric) {
+		klog.V(3).Infof("NodeMetric %v fails the colocation readiness checks, need degradation", nodeMetric.Name)
+		return true
+	}
+
 	return false
 }
 
+// isColocationReady checks whether the node passes the colocation readiness checks which the batch overcommit
+// depends on. The node is considered ready if the readiness is not reported.
+func isColocationReady(nodeMetric *slov1alpha1.NodeMetric) bool {
+	if nodeMetric.Status.ColocationReadiness == nil {
+		return true
+	}
+	for _, check := range nodeMetric.Status.ColocationReadiness.Checks {
+		if check.Passed {
+			continue
+		}
+		if check.Name == slov1alpha1.ColocationReadinessCheckKernelVersion ||
+			check.Name == slov1alpha1.ColocationReadinessCheckCgroupControllers {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *Plugin) degradeCalculate(node *corev1.Node, message string) []framework.ResourceItem {
 	return p.Reset(node, message)
 }
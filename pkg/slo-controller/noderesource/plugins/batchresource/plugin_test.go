@@ -40,8 +40,10 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/configuration"
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
 func makeResourceList(cpu, memory string) corev1.ResourceList {
@@ -3086,6 +3088,59 @@ func TestPlugin_isDegradeNeeded(t *testing.T) {
 	}
 }
 
+func TestPlugin_isDegradeNeededByColocationReadiness(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.NodeSLOColocationReadiness, true)()
+	strategy := &configuration.ColocationStrategy{
+		Enable:             pointer.Bool(true),
+		DegradeTimeMinutes: pointer.Int64(10),
+	}
+	newNodeMetric := func(checks ...slov1alpha1.ColocationReadinessCheck) *slov1alpha1.NodeMetric {
+		nodeMetric := &slov1alpha1.NodeMetric{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-node0"},
+			Status: slov1alpha1.NodeMetricStatus{
+				UpdateTime: &metav1.Time{Time: time.Now()},
+			},
+		}
+		if len(checks) > 0 {
+			nodeMetric.Status.ColocationReadiness = &slov1alpha1.ColocationReadiness{Checks: checks}
+		}
+		return nodeMetric
+	}
+	tests := []struct {
+		name       string
+		nodeMetric *slov1alpha1.NodeMetric
+		want       bool
+	}{
+		{
+			name:       "readiness not reported",
+			nodeMetric: newNodeMetric(),
+			want:       false,
+		},
+		{
+			name: "only the sched features check failed",
+			nodeMetric: newNodeMetric(
+				slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckKernelVersion, Passed: true},
+				slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckSchedFeatures, Passed: false},
+			),
+			want: false,
+		},
+		{
+			name: "the cgroup controllers check failed",
+			nodeMetric: newNodeMetric(
+				slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckKernelVersion, Passed: true},
+				slov1alpha1.ColocationReadinessCheck{Name: slov1alpha1.ColocationReadinessCheckCgroupControllers, Passed: false},
+			),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{}
+			assert.Equal(t, tt.want, p.isDegradeNeeded(strategy, tt.nodeMetric, &corev1.Node{}))
+		})
+	}
+}
+
 func testingCorrectResourceItems(t *testing.T, want, got []framework.ResourceItem) {
 	assert.Equal(t, len(want), len(got))
 	for i := range want {
@@ -105,6 +105,24 @@ func getPodMetricUsage(info *slov1alpha1.PodMetricInfo) corev1.ResourceList {
 	return getResourceListForCPUAndMemory(info.PodUsage.ResourceList)
 }
 
+// getHostAppHPUsed returns the usage of the high-priority host applications reported in NodeMetric.
+// The host applications of Batch or Free priority are not counted like the LP pods, and the BE host applications are
+// not counted since koordlet keeps their usage in the system usage.
+func getHostAppHPUsed(nodeMetric *slov1alpha1.NodeMetric) corev1.ResourceList {
+	hostAppHPUsed := util.NewZeroResourceList()
+	for _, hostAppMetric := range nodeMetric.Status.HostApplicationMetric {
+		if hostAppMetric == nil {
+			continue
+		}
+		if hostAppMetric.Priority == extension.PriorityBatch || hostAppMetric.Priority == extension.PriorityFree ||
+			hostAppMetric.QoS == extension.QoSBE {
+			continue
+		}
+		hostAppHPUsed = quotav1.Add(hostAppHPUsed, getResourceListForCPUAndMemory(hostAppMetric.Usage.ResourceList))
+	}
+	return hostAppHPUsed
+}
+
 // getPodNUMARequestAndUsage returns the pod request and usage on each NUMA nodes.
 // It averages the metrics over all sharepools when the pod does not allocate any sharepool or use all sharepools.
 func getPodNUMARequestAndUsage(pod *corev1.Pod, podRequest, podUsage corev1.ResourceList, numaNum int) ([]corev1.ResourceList, []corev1.ResourceList) {
@@ -64,6 +64,67 @@ func Test_getPodMetricUsage(t *testing.T) {
 	}
 }
 
+func Test_getHostAppHPUsed(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodeMetric *slov1alpha1.NodeMetric
+		want       corev1.ResourceList
+	}{
+		{
+			name:       "no host application",
+			nodeMetric: &slov1alpha1.NodeMetric{},
+			want:       makeResourceList("0", "0"),
+		},
+		{
+			name: "count the high-priority host applications only",
+			nodeMetric: &slov1alpha1.NodeMetric{
+				Status: slov1alpha1.NodeMetricStatus{
+					HostApplicationMetric: []*slov1alpha1.HostApplicationMetricInfo{
+						{
+							Name: "test-prod-app",
+							Usage: slov1alpha1.ResourceMap{
+								ResourceList: makeResourceList("2", "4Gi"),
+							},
+							Priority: extension.PriorityProd,
+							QoS:      extension.QoSLS,
+						},
+						{
+							Name: "test-none-app",
+							Usage: slov1alpha1.ResourceMap{
+								ResourceList: makeResourceList("1", "1Gi"),
+							},
+						},
+						{
+							Name: "test-batch-app",
+							Usage: slov1alpha1.ResourceMap{
+								ResourceList: makeResourceList("4", "8Gi"),
+							},
+							Priority: extension.PriorityBatch,
+							QoS:      extension.QoSBE,
+						},
+						{
+							Name: "test-prod-be-app",
+							Usage: slov1alpha1.ResourceMap{
+								ResourceList: makeResourceList("2", "2Gi"),
+							},
+							Priority: extension.PriorityProd,
+							QoS:      extension.QoSBE,
+						},
+						nil,
+					},
+				},
+			},
+			want: makeResourceList("3", "5Gi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getHostAppHPUsed(tt.nodeMetric)
+			testingCorrectResourceList(t, &tt.want, &got)
+		})
+	}
+}
+
 func Test_getResourceListForCPUAndMemory(t *testing.T) {
 	type args struct {
 		rl corev1.ResourceList
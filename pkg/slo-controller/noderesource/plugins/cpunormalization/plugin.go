@@ -100,6 +100,9 @@ func (p *Plugin) NeedSyncMeta(_ *configuration.ColocationStrategy, oldNode, newN
 		return true, "new ratio is nil"
 	}
 	if math.Abs(ratioNew-ratioOld) < ratioDiffEpsilon {
+		if isCPUAmplificationRatioChanged(oldNode, newNode) {
+			return true, "cpu amplification ratio is different"
+		}
 		return false, "ratios are close"
 	}
 
@@ -119,6 +122,16 @@ func (p *Plugin) Execute(_ *configuration.ColocationStrategy, node *corev1.Node,
 	node.Annotations[extension.AnnotationCPUNormalizationRatio] = ratioStr
 	klog.V(6).Infof("prepare node cpu normalization ratio to set, node %s, ratio %s", node.Name, ratioStr)
 
+	// the normalized cores are exposed via the cpu amplification ratio, so the node allocatable and the
+	// NUMA resources can be amplified accordingly; the ratio 1.00 de-amplifies them
+	ratio, err := strconv.ParseFloat(ratioStr, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse cpu normalization ratio %s, err: %w", ratioStr, err)
+	}
+	if _, err = extension.SetNodeResourceAmplificationRatio(node, corev1.ResourceCPU, extension.Ratio(ratio)); err != nil {
+		return fmt.Errorf("failed to set cpu amplification ratio, err: %w", err)
+	}
+
 	return nil
 }
 
@@ -197,6 +210,15 @@ func (p *Plugin) Calculate(_ *configuration.ColocationStrategy, node *corev1.Nod
 	}, nil
 }
 
+func isCPUAmplificationRatioChanged(oldNode, newNode *corev1.Node) bool {
+	ratioOld, errOld := extension.GetNodeResourceAmplificationRatio(oldNode.Annotations, corev1.ResourceCPU)
+	ratioNew, errNew := extension.GetNodeResourceAmplificationRatio(newNode.Annotations, corev1.ResourceCPU)
+	if errOld != nil || errNew != nil {
+		return errOld != nil && errNew == nil
+	}
+	return math.Abs(float64(ratioNew-ratioOld)) >= ratioDiffEpsilon
+}
+
 func isCPUBasicInfoChanged(infoOld, infoNew *extension.CPUBasicInfo) (bool, string) {
 	if infoOld == nil && infoNew == nil {
 		return false, "infos are nil"
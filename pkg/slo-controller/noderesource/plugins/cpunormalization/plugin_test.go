@@ -180,6 +180,23 @@ func TestPluginNeedSyncMeta(t *testing.T) {
 			want:  true,
 			want1: "ratio is different",
 		},
+		{
+			name: "need sync when cpu amplification ratio is different",
+			args: args{
+				oldNode: testNode,
+				newNode: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node",
+						Annotations: map[string]string{
+							extension.AnnotationCPUNormalizationRatio:          "1.10",
+							extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.10}`,
+						},
+					},
+				},
+			},
+			want:  true,
+			want1: "cpu amplification ratio is different",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -239,7 +256,8 @@ func TestPluginExecute(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "test-node",
 					Annotations: map[string]string{
-						extension.AnnotationCPUNormalizationRatio: "1.10",
+						extension.AnnotationCPUNormalizationRatio:          "1.10",
+						extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.10}`,
 					},
 				},
 			},
@@ -267,7 +285,61 @@ func TestPluginExecute(t *testing.T) {
 					Name: "test-node",
 					Annotations: map[string]string{
 						"xxx": "yyy",
-						extension.AnnotationCPUNormalizationRatio: "1.20",
+						extension.AnnotationCPUNormalizationRatio:          "1.20",
+						extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+					},
+				},
+			},
+		},
+		{
+			name: "reset amplification ratio along with the normalization ratio",
+			args: args{
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node",
+						Annotations: map[string]string{
+							extension.AnnotationCPUNormalizationRatio:          "1.20",
+							extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20,"memory":1.50}`,
+						},
+					},
+				},
+				nr: &framework.NodeResource{
+					Annotations: map[string]string{
+						extension.AnnotationCPUNormalizationRatio: defaultRatioStr,
+					},
+				},
+			},
+			wantErr: false,
+			wantField: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						extension.AnnotationCPUNormalizationRatio:          defaultRatioStr,
+						extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.00,"memory":1.50}`,
+					},
+				},
+			},
+		},
+		{
+			name: "failed to parse ratio",
+			args: args{
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node",
+					},
+				},
+				nr: &framework.NodeResource{
+					Annotations: map[string]string{
+						extension.AnnotationCPUNormalizationRatio: "xxx",
+					},
+				},
+			},
+			wantErr: true,
+			wantField: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node",
+					Annotations: map[string]string{
+						extension.AnnotationCPUNormalizationRatio: "xxx",
 					},
 				},
 			},
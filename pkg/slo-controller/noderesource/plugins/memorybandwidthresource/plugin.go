@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorybandwidthresource
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+)
+
+const PluginName = "MemoryBandwidthResource"
+
+// ResourceName is the extended resource name of the memory bandwidth to update.
+var ResourceName = extension.ResourceMemoryBandwidth
+
+// Plugin updates the node allocatable of the memory bandwidth with the sum of the measured maximum memory bandwidth
+// of the NUMA nodes, so that the pods requesting the memory bandwidth can be admitted on the node.
+type Plugin struct{}
+
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+func (p *Plugin) NeedSync(_ *configuration.ColocationStrategy, oldNode, newNode *corev1.Node) (bool, string) {
+	oldQuantity, oldExist := oldNode.Status.Allocatable[ResourceName]
+	newQuantity, newExist := newNode.Status.Allocatable[ResourceName]
+	if oldExist != newExist || !oldQuantity.Equal(newQuantity) {
+		klog.V(4).Infof("node %v memory bandwidth resource changed from %v to %v, need sync",
+			newNode.Name, oldQuantity.String(), newQuantity.String())
+		return true, "memory bandwidth resource changed"
+	}
+
+	return false, ""
+}
+
+func (p *Plugin) Execute(_ *configuration.ColocationStrategy, node *corev1.Node, nr *framework.NodeResource) error {
+	if q := nr.Resources[ResourceName]; nr.Resets[ResourceName] || q == nil {
+		delete(node.Status.Capacity, ResourceName)
+		delete(node.Status.Allocatable, ResourceName)
+	} else {
+		node.Status.Capacity[ResourceName] = *q
+		node.Status.Allocatable[ResourceName] = *q
+	}
+	return nil
+}
+
+func (p *Plugin) Reset(_ *corev1.Node, message string) []framework.ResourceItem {
+	return []framework.ResourceItem{
+		{
+			Name:    ResourceName,
+			Message: message,
+			Reset:   true,
+		},
+	}
+}
+
+// Calculate sums the measured maximum memory bandwidth of the NUMA nodes from the node annotation.
+func (p *Plugin) Calculate(_ *configuration.ColocationStrategy, node *corev1.Node, _ *corev1.PodList,
+	_ *framework.ResourceMetrics) ([]framework.ResourceItem, error) {
+	if node == nil {
+		return nil, fmt.Errorf("missing essential arguments")
+	}
+
+	memoryBandwidth, err := extension.GetNodeMemoryBandwidth(node.Annotations)
+	if err != nil {
+		klog.V(4).InfoS("failed to parse node memory bandwidth, reset node resources", "node", node.Name, "err", err)
+		return p.Reset(node, fmt.Sprintf("reset node memory bandwidth resource, parse annotation failed, err: %s", err)), nil
+	}
+	total := memoryBandwidth.Total()
+	if total <= 0 {
+		return p.Reset(node, "reset node memory bandwidth resource, memory bandwidth is not measured"), nil
+	}
+
+	klog.V(6).Infof("calculated memory bandwidth allocatable for node %s, bandwidth(MB/s) %v", node.Name, total)
+	return []framework.ResourceItem{
+		{
+			Name:     ResourceName,
+			Quantity: resource.NewQuantity(total, resource.DecimalSI),
+			Message:  fmt.Sprintf("memoryBandwidthAllocatable[MB/s]:%v = sum(numaMemoryBandwidth:%v)", total, memoryBandwidth),
+		},
+	}, nil
+}
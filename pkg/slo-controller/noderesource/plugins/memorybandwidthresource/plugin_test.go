@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorybandwidthresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+)
+
+func TestPlugin(t *testing.T) {
+	p := &Plugin{}
+	assert.Equal(t, PluginName, p.Name())
+}
+
+func TestPluginNeedSync(t *testing.T) {
+	testNode := getTestNode(nil, nil)
+	testNodeBandwidth := getTestNode(nil, corev1.ResourceList{
+		ResourceName: resource.MustParse("20000"),
+	})
+	testNodeBandwidthChanged := getTestNode(nil, corev1.ResourceList{
+		ResourceName: resource.MustParse("40000"),
+	})
+	p := &Plugin{}
+	got, _ := p.NeedSync(nil, testNode, testNode)
+	assert.False(t, got)
+	got, _ = p.NeedSync(nil, testNodeBandwidth, testNodeBandwidth.DeepCopy())
+	assert.False(t, got)
+	got, _ = p.NeedSync(nil, testNode, testNodeBandwidth)
+	assert.True(t, got)
+	got, _ = p.NeedSync(nil, testNodeBandwidth, testNodeBandwidthChanged)
+	assert.True(t, got)
+	got, _ = p.NeedSync(nil, testNodeBandwidth, testNode)
+	assert.True(t, got)
+}
+
+func TestPluginExecute(t *testing.T) {
+	p := &Plugin{}
+	node := getTestNode(nil, nil)
+	err := p.Execute(nil, node, &framework.NodeResource{
+		Resources: map[corev1.ResourceName]*resource.Quantity{
+			ResourceName: resource.NewQuantity(20000, resource.DecimalSI),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *resource.NewQuantity(20000, resource.DecimalSI), node.Status.Allocatable[ResourceName])
+	assert.Equal(t, *resource.NewQuantity(20000, resource.DecimalSI), node.Status.Capacity[ResourceName])
+
+	err = p.Execute(nil, node, &framework.NodeResource{
+		Resets: map[corev1.ResourceName]bool{
+			ResourceName: true,
+		},
+	})
+	assert.NoError(t, err)
+	_, ok := node.Status.Allocatable[ResourceName]
+	assert.False(t, ok)
+	_, ok = node.Status.Capacity[ResourceName]
+	assert.False(t, ok)
+}
+
+func TestPluginCalculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    *corev1.Node
+		want    []framework.ResourceItem
+		wantErr bool
+	}{
+		{
+			name:    "missing node",
+			wantErr: true,
+		},
+		{
+			name: "reset when memory bandwidth is not measured",
+			node: getTestNode(nil, nil),
+			want: []framework.ResourceItem{
+				{
+					Name:    ResourceName,
+					Message: "reset node memory bandwidth resource, memory bandwidth is not measured",
+					Reset:   true,
+				},
+			},
+		},
+		{
+			name: "reset when memory bandwidth is invalid",
+			node: getTestNode(map[string]string{
+				extension.AnnotationNodeMemoryBandwidth: "invalid",
+			}, nil),
+			want: []framework.ResourceItem{
+				{
+					Name:    ResourceName,
+					Message: "reset node memory bandwidth resource, parse annotation failed, err: invalid character 'i' looking for beginning of value",
+					Reset:   true,
+				},
+			},
+		},
+		{
+			name: "calculate memory bandwidth",
+			node: getTestNode(map[string]string{
+				extension.AnnotationNodeMemoryBandwidth: `{"0": 20000, "1": 18000}`,
+			}, nil),
+			want: []framework.ResourceItem{
+				{
+					Name:     ResourceName,
+					Quantity: resource.NewQuantity(38000, resource.DecimalSI),
+					Message:  "memoryBandwidthAllocatable[MB/s]:38000 = sum(numaMemoryBandwidth:map[0:20000 1:18000])",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{}
+			got, err := p.Calculate(nil, tt.node, nil, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func getTestNode(annotations map[string]string, resourceList corev1.ResourceList) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-node",
+			Annotations: annotations,
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("400Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("380Gi"),
+			},
+		},
+	}
+	for resourceName, q := range resourceList {
+		node.Status.Capacity[resourceName] = q
+		node.Status.Allocatable[resourceName] = q
+	}
+	return node
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytierresource
+
+import (
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.EventHandler = &nrtHandler{}
+
+type nrtHandler struct{}
+
+func (h *nrtHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	nrt, ok := evt.Object.(*topologyv1alpha1.NodeResourceTopology)
+	if !ok {
+		return
+	}
+
+	if len(getZoneMemoryTiers(nrt)) <= 0 {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name: nrt.Name,
+		},
+	})
+}
+
+func (h *nrtHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	nrtOld, okOld := evt.ObjectOld.(*topologyv1alpha1.NodeResourceTopology)
+	nrtNew, okNew := evt.ObjectNew.(*topologyv1alpha1.NodeResourceTopology)
+	if !okOld || !okNew {
+		return
+	}
+
+	if nrtOld.ResourceVersion == nrtNew.ResourceVersion {
+		return
+	}
+
+	if quotav1.Equals(getZoneMemoryTiers(nrtOld), getZoneMemoryTiers(nrtNew)) {
+		return
+	}
+
+	q.Add(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name: nrtNew.Name,
+		},
+	})
+}
+
+func (h *nrtHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h *nrtHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
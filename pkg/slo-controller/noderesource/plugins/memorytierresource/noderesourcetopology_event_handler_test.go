@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytierresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestNRTHandler(t *testing.T) {
+	nrtNoTier := getTestNRT(map[string]corev1.ResourceList{
+		"node-0": {
+			corev1.ResourceMemory: resource.MustParse("64Gi"),
+		},
+	})
+	nrtNoTier.ResourceVersion = "1"
+	nrtTier := getTestNRT(map[string]corev1.ResourceList{
+		"node-0": {
+			corev1.ResourceMemory:  resource.MustParse("64Gi"),
+			extension.ResourceDRAM: resource.MustParse("64Gi"),
+		},
+	})
+	nrtTier.ResourceVersion = "2"
+	nrtTierUpdated := nrtTier.DeepCopy()
+	nrtTierUpdated.ResourceVersion = "3"
+
+	h := &nrtHandler{}
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	h.Create(event.CreateEvent{Object: nrtNoTier}, q)
+	assert.Equal(t, 0, q.Len())
+	h.Create(event.CreateEvent{Object: nrtTier}, q)
+	assert.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	q.Done(item)
+	q.Forget(item)
+
+	h.Update(event.UpdateEvent{ObjectOld: nrtTier, ObjectNew: nrtTierUpdated}, q)
+	assert.Equal(t, 0, q.Len())
+	h.Update(event.UpdateEvent{ObjectOld: nrtNoTier, ObjectNew: nrtTier}, q)
+	assert.Equal(t, 1, q.Len())
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytierresource
+
+import (
+	"context"
+	"fmt"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+)
+
+const PluginName = "MemoryTierResource"
+
+// ResourceNames are the extended resource names of the memory tiers to update.
+var ResourceNames = []corev1.ResourceName{extension.ResourceDRAM, extension.ResourceSlowMemory}
+
+var client ctrlclient.Client
+
+// Plugin updates the node allocatable of the memory tiers with the sum of the DRAM and the slow memory reported on the
+// NUMA nodes in the NodeResourceTopology, so that the pods requesting the memory tiers can be admitted on the node.
+type Plugin struct{}
+
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+// +kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch
+
+func (p *Plugin) Setup(opt *framework.Option) error {
+	client = opt.Client
+
+	if err := topologyv1alpha1.AddToScheme(opt.Scheme); err != nil {
+		return fmt.Errorf("failed to add scheme for NodeResourceTopology, err: %w", err)
+	}
+	if err := topologyv1alpha1.AddToScheme(clientgoscheme.Scheme); err != nil {
+		return fmt.Errorf("failed to add client go scheme for NodeResourceTopology, err: %w", err)
+	}
+	opt.Builder = opt.Builder.Watches(&source.Kind{Type: &topologyv1alpha1.NodeResourceTopology{}}, &nrtHandler{})
+
+	return nil
+}
+
+func (p *Plugin) NeedSync(_ *configuration.ColocationStrategy, oldNode, newNode *corev1.Node) (bool, string) {
+	for _, resourceName := range ResourceNames {
+		oldQuantity, oldExist := oldNode.Status.Allocatable[resourceName]
+		newQuantity, newExist := newNode.Status.Allocatable[resourceName]
+		if oldExist != newExist || !oldQuantity.Equal(newQuantity) {
+			klog.V(4).Infof("node %v memory tier resource %v changed from %v to %v, need sync",
+				newNode.Name, resourceName, oldQuantity.String(), newQuantity.String())
+			return true, "memory tier resource changed"
+		}
+	}
+
+	return false, ""
+}
+
+func (p *Plugin) Execute(_ *configuration.ColocationStrategy, node *corev1.Node, nr *framework.NodeResource) error {
+	for _, resourceName := range ResourceNames {
+		if q := nr.Resources[resourceName]; nr.Resets[resourceName] || q == nil {
+			delete(node.Status.Capacity, resourceName)
+			delete(node.Status.Allocatable, resourceName)
+		} else {
+			node.Status.Capacity[resourceName] = *q
+			node.Status.Allocatable[resourceName] = *q
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) Reset(_ *corev1.Node, message string) []framework.ResourceItem {
+	items := make([]framework.ResourceItem, 0, len(ResourceNames))
+	for _, resourceName := range ResourceNames {
+		items = append(items, framework.ResourceItem{
+			Name:    resourceName,
+			Message: message,
+			Reset:   true,
+		})
+	}
+	return items
+}
+
+// Calculate sums the DRAM and the slow memory of the NUMA nodes from the NodeResourceTopology.
+func (p *Plugin) Calculate(_ *configuration.ColocationStrategy, node *corev1.Node, _ *corev1.PodList,
+	_ *framework.ResourceMetrics) ([]framework.ResourceItem, error) {
+	if node == nil {
+		return nil, fmt.Errorf("missing essential arguments")
+	}
+
+	nrt := &topologyv1alpha1.NodeResourceTopology{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, nrt)
+	if errors.IsNotFound(err) {
+		klog.V(5).InfoS("NodeResourceTopology not found, reset node memory tier resources", "node", node.Name)
+		return p.Reset(node, "reset node memory tier resources, NodeResourceTopology not found"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NodeResourceTopology in memory tier calculation, err: %w", err)
+	}
+
+	totals := getZoneMemoryTiers(nrt)
+	items := make([]framework.ResourceItem, 0, len(ResourceNames))
+	for _, resourceName := range ResourceNames {
+		total := totals[resourceName]
+		if total.IsZero() {
+			items = append(items, framework.ResourceItem{
+				Name:    resourceName,
+				Message: fmt.Sprintf("reset node memory tier resource %s, not reported on NUMA nodes", resourceName),
+				Reset:   true,
+			})
+			continue
+		}
+		items = append(items, framework.ResourceItem{
+			Name:     resourceName,
+			Quantity: &total,
+			Message:  fmt.Sprintf("%sAllocatable:%v = sum(numaNodeAllocatable)", resourceName, total.String()),
+		})
+	}
+	klog.V(6).Infof("calculated memory tier allocatable for node %s, resources %v", node.Name, totals)
+	return items, nil
+}
+
+// getZoneMemoryTiers returns the sum of the memory tiers reported on the NUMA node zones.
+func getZoneMemoryTiers(nrt *topologyv1alpha1.NodeResourceTopology) corev1.ResourceList {
+	totals := corev1.ResourceList{}
+	for _, zone := range nrt.Zones {
+		for _, res := range zone.Resources {
+			resourceName := corev1.ResourceName(res.Name)
+			if !extension.IsMemoryTierResource(resourceName) {
+				continue
+			}
+			total := totals[resourceName]
+			total.Add(res.Allocatable)
+			totals[resourceName] = total
+		}
+	}
+	return totals
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytierresource
+
+import (
+	"testing"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+)
+
+func TestPlugin(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(testScheme)
+	assert.NoError(t, err)
+	err = topologyv1alpha1.AddToScheme(testScheme)
+	assert.NoError(t, err)
+
+	p := &Plugin{}
+	assert.Equal(t, PluginName, p.Name())
+	err = p.Setup(&framework.Option{
+		Scheme:   testScheme,
+		Client:   fake.NewClientBuilder().WithScheme(testScheme).Build(),
+		Builder:  &builder.Builder{},
+		Recorder: &record.FakeRecorder{},
+	})
+	assert.NoError(t, err)
+}
+
+func TestPluginNeedSync(t *testing.T) {
+	testNode := getTestNode(nil)
+	testNodeTiers := getTestNode(corev1.ResourceList{
+		extension.ResourceDRAM:       resource.MustParse("128Gi"),
+		extension.ResourceSlowMemory: resource.MustParse("512Gi"),
+	})
+	testNodeTiersChanged := getTestNode(corev1.ResourceList{
+		extension.ResourceDRAM:       resource.MustParse("128Gi"),
+		extension.ResourceSlowMemory: resource.MustParse("256Gi"),
+	})
+	p := &Plugin{}
+	got, _ := p.NeedSync(nil, testNode, testNode)
+	assert.False(t, got)
+	got, _ = p.NeedSync(nil, testNodeTiers, testNodeTiers.DeepCopy())
+	assert.False(t, got)
+	got, _ = p.NeedSync(nil, testNode, testNodeTiers)
+	assert.True(t, got)
+	got, _ = p.NeedSync(nil, testNodeTiers, testNodeTiersChanged)
+	assert.True(t, got)
+	got, _ = p.NeedSync(nil, testNodeTiers, testNode)
+	assert.True(t, got)
+}
+
+func TestPluginExecute(t *testing.T) {
+	p := &Plugin{}
+	node := getTestNode(nil)
+	err := p.Execute(nil, node, &framework.NodeResource{
+		Resources: map[corev1.ResourceName]*resource.Quantity{
+			extension.ResourceDRAM:       resource.NewQuantity(1024, resource.BinarySI),
+			extension.ResourceSlowMemory: resource.NewQuantity(4096, resource.BinarySI),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *resource.NewQuantity(1024, resource.BinarySI), node.Status.Allocatable[extension.ResourceDRAM])
+	assert.Equal(t, *resource.NewQuantity(4096, resource.BinarySI), node.Status.Capacity[extension.ResourceSlowMemory])
+
+	err = p.Execute(nil, node, &framework.NodeResource{
+		Resources: map[corev1.ResourceName]*resource.Quantity{
+			extension.ResourceDRAM: resource.NewQuantity(1024, resource.BinarySI),
+		},
+		Resets: map[corev1.ResourceName]bool{
+			extension.ResourceSlowMemory: true,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *resource.NewQuantity(1024, resource.BinarySI), node.Status.Allocatable[extension.ResourceDRAM])
+	_, ok := node.Status.Allocatable[extension.ResourceSlowMemory]
+	assert.False(t, ok)
+	_, ok = node.Status.Capacity[extension.ResourceSlowMemory]
+	assert.False(t, ok)
+}
+
+func TestPluginCalculate(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(testScheme)
+	assert.NoError(t, err)
+	err = topologyv1alpha1.AddToScheme(testScheme)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		node    *corev1.Node
+		nrt     *topologyv1alpha1.NodeResourceTopology
+		want    []framework.ResourceItem
+		wantErr bool
+	}{
+		{
+			name:    "missing node",
+			wantErr: true,
+		},
+		{
+			name: "reset when NodeResourceTopology not found",
+			node: getTestNode(nil),
+			want: []framework.ResourceItem{
+				{
+					Name:    extension.ResourceDRAM,
+					Message: "reset node memory tier resources, NodeResourceTopology not found",
+					Reset:   true,
+				},
+				{
+					Name:    extension.ResourceSlowMemory,
+					Message: "reset node memory tier resources, NodeResourceTopology not found",
+					Reset:   true,
+				},
+			},
+		},
+		{
+			name: "reset slow memory when not reported",
+			node: getTestNode(nil),
+			nrt: getTestNRT(map[string]corev1.ResourceList{
+				"node-0": {
+					corev1.ResourceMemory:  resource.MustParse("64Gi"),
+					extension.ResourceDRAM: resource.MustParse("64Gi"),
+				},
+				"node-1": {
+					corev1.ResourceMemory:  resource.MustParse("64Gi"),
+					extension.ResourceDRAM: resource.MustParse("64Gi"),
+				},
+			}),
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.ResourceDRAM,
+					Quantity: resource.NewQuantity(128<<30, resource.BinarySI),
+					Message:  "koordinator.sh/dramAllocatable:128Gi = sum(numaNodeAllocatable)",
+				},
+				{
+					Name:    extension.ResourceSlowMemory,
+					Message: "reset node memory tier resource koordinator.sh/slow-memory, not reported on NUMA nodes",
+					Reset:   true,
+				},
+			},
+		},
+		{
+			name: "calculate dram and slow memory",
+			node: getTestNode(nil),
+			nrt: getTestNRT(map[string]corev1.ResourceList{
+				"node-0": {
+					corev1.ResourceMemory:  resource.MustParse("64Gi"),
+					extension.ResourceDRAM: resource.MustParse("64Gi"),
+				},
+				"node-1": {
+					corev1.ResourceMemory:  resource.MustParse("64Gi"),
+					extension.ResourceDRAM: resource.MustParse("64Gi"),
+				},
+				"node-2": {
+					corev1.ResourceMemory:        resource.MustParse("256Gi"),
+					extension.ResourceSlowMemory: resource.MustParse("256Gi"),
+				},
+			}),
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.ResourceDRAM,
+					Quantity: resource.NewQuantity(128<<30, resource.BinarySI),
+					Message:  "koordinator.sh/dramAllocatable:128Gi = sum(numaNodeAllocatable)",
+				},
+				{
+					Name:     extension.ResourceSlowMemory,
+					Quantity: resource.NewQuantity(256<<30, resource.BinarySI),
+					Message:  "koordinator.sh/slow-memoryAllocatable:256Gi = sum(numaNodeAllocatable)",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientBuilder := fake.NewClientBuilder().WithScheme(testScheme)
+			if tt.nrt != nil {
+				clientBuilder = clientBuilder.WithObjects(tt.nrt)
+			}
+			client = clientBuilder.Build()
+			defer func() {
+				client = nil
+			}()
+
+			p := &Plugin{}
+			got, err := p.Calculate(nil, tt.node, nil, nil)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, len(tt.want), len(got))
+			for i := range tt.want {
+				assert.Equal(t, tt.want[i].Name, got[i].Name)
+				assert.Equal(t, tt.want[i].Message, got[i].Message)
+				assert.Equal(t, tt.want[i].Reset, got[i].Reset)
+				if tt.want[i].Quantity == nil {
+					assert.Nil(t, got[i].Quantity)
+				} else {
+					assert.True(t, tt.want[i].Quantity.Equal(*got[i].Quantity))
+				}
+			}
+		})
+	}
+}
+
+func getTestNode(resourceList corev1.ResourceList) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("400Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("380Gi"),
+			},
+		},
+	}
+	for resourceName, q := range resourceList {
+		node.Status.Capacity[resourceName] = q
+		node.Status.Allocatable[resourceName] = q
+	}
+	return node
+}
+
+func getTestNRT(zoneResources map[string]corev1.ResourceList) *topologyv1alpha1.NodeResourceTopology {
+	nrt := &topologyv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	for zoneName, resourceList := range zoneResources {
+		zone := topologyv1alpha1.Zone{
+			Name: zoneName,
+			Type: "Node",
+		}
+		for resourceName, q := range resourceList {
+			zone.Resources = append(zone.Resources, topologyv1alpha1.ResourceInfo{
+				Name:        string(resourceName),
+				Capacity:    q,
+				Allocatable: q,
+				Available:   q,
+			})
+		}
+		nrt.Zones = append(nrt.Zones, zone)
+	}
+	return nrt
+}
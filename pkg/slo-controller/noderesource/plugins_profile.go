@@ -20,6 +20,8 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/batchresource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/cpunormalization"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/memorybandwidthresource"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/memorytierresource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/midresource"
 )
 
@@ -30,6 +32,8 @@ func init() {
 	addPluginOption(&midresource.Plugin{}, true)
 	addPluginOption(&batchresource.Plugin{}, true)
 	addPluginOption(&cpunormalization.Plugin{}, true)
+	addPluginOption(&memorybandwidthresource.Plugin{}, false)
+	addPluginOption(&memorytierresource.Plugin{}, false)
 }
 
 func addPlugins(filter framework.FilterFn) {
@@ -46,17 +50,22 @@ var (
 	setupPlugins = []framework.SetupPlugin{
 		&cpunormalization.Plugin{},
 		&batchresource.Plugin{},
+		&memorytierresource.Plugin{},
 	}
 	// NodePreparePlugin implements node resource preparing for the calculated results.
 	nodePreparePlugins = []framework.NodePreparePlugin{
 		&cpunormalization.Plugin{}, // should be first
 		&midresource.Plugin{},
 		&batchresource.Plugin{},
+		&memorybandwidthresource.Plugin{},
+		&memorytierresource.Plugin{},
 	}
 	// NodeSyncPlugin implements the check of resource updating.
 	nodeSyncPlugins = []framework.NodeSyncPlugin{
 		&midresource.Plugin{},
 		&batchresource.Plugin{},
+		&memorybandwidthresource.Plugin{},
+		&memorytierresource.Plugin{},
 	}
 	// NodeMetaSyncPlugin implements the check of node meta updating.
 	nodeMetaSyncPlugins = []framework.NodeMetaSyncPlugin{
@@ -67,5 +76,7 @@ var (
 		&cpunormalization.Plugin{},
 		&midresource.Plugin{},
 		&batchresource.Plugin{},
+		&memorybandwidthresource.Plugin{},
+		&memorytierresource.Plugin{},
 	}
 )
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"reflect"
+
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// holdBackStrategiesByReadiness disables the strategies of the NodeSLO spec which depend on the failed checks of the
+// node colocation readiness. It returns the names of the failed checks.
+//   - KernelVersion, CgroupControllers: all resource qos strategies. The resource threshold strategy is kept to
+//     protect the LS pods by the suppression and the eviction, while the batch overcommit is held back by the
+//     noderesource controller.
+//   - SchedFeatures: the cpu qos (group identity).
+//   - MemcgQoS: the memory qos.
+func holdBackStrategiesByReadiness(spec *slov1alpha1.NodeSLOSpec, readiness *slov1alpha1.ColocationReadiness) []string {
+	if spec == nil || readiness == nil {
+		return nil
+	}
+	var failed []string
+	holdBackAll, holdBackCPUQOS, holdBackMemoryQOS := false, false, false
+	for _, check := range readiness.Checks {
+		if check.Passed {
+			continue
+		}
+		failed = append(failed, check.Name)
+		switch check.Name {
+		case slov1alpha1.ColocationReadinessCheckKernelVersion, slov1alpha1.ColocationReadinessCheckCgroupControllers:
+			holdBackAll = true
+		case slov1alpha1.ColocationReadinessCheckSchedFeatures:
+			holdBackCPUQOS = true
+		case slov1alpha1.ColocationReadinessCheckMemcgQoS:
+			holdBackMemoryQOS = true
+		}
+	}
+	if holdBackAll {
+		holdBackCPUQOS, holdBackMemoryQOS = true, true
+	}
+	if spec.ResourceQOSStrategy == nil {
+		return failed
+	}
+	for _, qos := range []*slov1alpha1.ResourceQOS{
+		spec.ResourceQOSStrategy.LSRClass,
+		spec.ResourceQOSStrategy.LSClass,
+		spec.ResourceQOSStrategy.BEClass,
+		spec.ResourceQOSStrategy.SystemClass,
+		spec.ResourceQOSStrategy.CgroupRoot,
+	} {
+		if qos == nil {
+			continue
+		}
+		if holdBackCPUQOS && qos.CPUQOS != nil {
+			qos.CPUQOS.Enable = pointer.Bool(false)
+		}
+		if holdBackMemoryQOS && qos.MemoryQOS != nil {
+			qos.MemoryQOS.Enable = pointer.Bool(false)
+		}
+		if holdBackAll && qos.BlkIOQOS != nil {
+			qos.BlkIOQOS.Enable = pointer.Bool(false)
+		}
+		if holdBackAll && qos.ResctrlQOS != nil {
+			qos.ResctrlQOS.Enable = pointer.Bool(false)
+		}
+	}
+	return failed
+}
+
+// colocationReadinessChangedPredicate only accepts the NodeMetric events whose colocation readiness changes, since
+// the NodeMetric status is updated periodically.
+var colocationReadinessChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNodeMetric, ok := e.ObjectOld.(*slov1alpha1.NodeMetric)
+		if !ok {
+			return false
+		}
+		newNodeMetric, ok := e.ObjectNew.(*slov1alpha1.NodeMetric)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldNodeMetric.Status.ColocationReadiness, newNodeMetric.Status.ColocationReadiness)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+}
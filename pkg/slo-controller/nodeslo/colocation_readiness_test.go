@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func Test_holdBackStrategiesByReadiness(t *testing.T) {
+	newSpec := func() *slov1alpha1.NodeSLOSpec {
+		return &slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				Enable: pointer.Bool(true),
+			},
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				LSClass: &slov1alpha1.ResourceQOS{
+					CPUQOS:    &slov1alpha1.CPUQOSCfg{Enable: pointer.Bool(true)},
+					MemoryQOS: &slov1alpha1.MemoryQOSCfg{Enable: pointer.Bool(true)},
+				},
+				BEClass: &slov1alpha1.ResourceQOS{
+					CPUQOS:     &slov1alpha1.CPUQOSCfg{Enable: pointer.Bool(true)},
+					MemoryQOS:  &slov1alpha1.MemoryQOSCfg{Enable: pointer.Bool(true)},
+					ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{Enable: pointer.Bool(true)},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		readiness  *slov1alpha1.ColocationReadiness
+		wantFailed []string
+		wantSpec   func() *slov1alpha1.NodeSLOSpec
+	}{
+		{
+			name:      "readiness not reported",
+			readiness: nil,
+			wantSpec:  newSpec,
+		},
+		{
+			name: "all checks passed",
+			readiness: &slov1alpha1.ColocationReadiness{
+				Score: 100,
+				Checks: []slov1alpha1.ColocationReadinessCheck{
+					{Name: slov1alpha1.ColocationReadinessCheckKernelVersion, Passed: true},
+					{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS, Passed: true},
+				},
+			},
+			wantSpec: newSpec,
+		},
+		{
+			name: "hold back the memory qos",
+			readiness: &slov1alpha1.ColocationReadiness{
+				Score: 50,
+				Checks: []slov1alpha1.ColocationReadinessCheck{
+					{Name: slov1alpha1.ColocationReadinessCheckSchedFeatures, Passed: true},
+					{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS, Passed: false},
+				},
+			},
+			wantFailed: []string{slov1alpha1.ColocationReadinessCheckMemcgQoS},
+			wantSpec: func() *slov1alpha1.NodeSLOSpec {
+				spec := newSpec()
+				spec.ResourceQOSStrategy.LSClass.MemoryQOS.Enable = pointer.Bool(false)
+				spec.ResourceQOSStrategy.BEClass.MemoryQOS.Enable = pointer.Bool(false)
+				return spec
+			},
+		},
+		{
+			name: "hold back the cpu qos",
+			readiness: &slov1alpha1.ColocationReadiness{
+				Score: 50,
+				Checks: []slov1alpha1.ColocationReadinessCheck{
+					{Name: slov1alpha1.ColocationReadinessCheckSchedFeatures, Passed: false},
+					{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS, Passed: true},
+				},
+			},
+			wantFailed: []string{slov1alpha1.ColocationReadinessCheckSchedFeatures},
+			wantSpec: func() *slov1alpha1.NodeSLOSpec {
+				spec := newSpec()
+				spec.ResourceQOSStrategy.LSClass.CPUQOS.Enable = pointer.Bool(false)
+				spec.ResourceQOSStrategy.BEClass.CPUQOS.Enable = pointer.Bool(false)
+				return spec
+			},
+		},
+		{
+			name: "hold back all qos strategies on an old kernel",
+			readiness: &slov1alpha1.ColocationReadiness{
+				Score: 75,
+				Checks: []slov1alpha1.ColocationReadinessCheck{
+					{Name: slov1alpha1.ColocationReadinessCheckKernelVersion, Passed: false},
+					{Name: slov1alpha1.ColocationReadinessCheckSchedFeatures, Passed: true},
+					{Name: slov1alpha1.ColocationReadinessCheckMemcgQoS, Passed: true},
+					{Name: slov1alpha1.ColocationReadinessCheckCgroupControllers, Passed: true},
+				},
+			},
+			wantFailed: []string{slov1alpha1.ColocationReadinessCheckKernelVersion},
+			wantSpec: func() *slov1alpha1.NodeSLOSpec {
+				spec := newSpec()
+				spec.ResourceQOSStrategy.LSClass.CPUQOS.Enable = pointer.Bool(false)
+				spec.ResourceQOSStrategy.LSClass.MemoryQOS.Enable = pointer.Bool(false)
+				spec.ResourceQOSStrategy.BEClass.CPUQOS.Enable = pointer.Bool(false)
+				spec.ResourceQOSStrategy.BEClass.MemoryQOS.Enable = pointer.Bool(false)
+				spec.ResourceQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(false)
+				return spec
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newSpec()
+			gotFailed := holdBackStrategiesByReadiness(spec, tt.readiness)
+			assert.Equal(t, tt.wantFailed, gotFailed)
+			assert.Equal(t, tt.wantSpec(), spec)
+		})
+	}
+}
@@ -23,19 +23,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/sharding"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
 
 const Name = "nodeslo"
@@ -46,6 +52,8 @@ type NodeSLOReconciler struct {
 	sloCfgCache SLOCfgCache
 	Scheme      *runtime.Scheme
 	Recorder    record.EventRecorder
+	// sharder is set if the nodes are partitioned across the replicas
+	sharder *sharding.Sharder
 }
 
 func (r *NodeSLOReconciler) initNodeSLO(node *corev1.Node, nodeSLO *slov1alpha1.NodeSLO) error {
@@ -61,6 +69,7 @@ func (r *NodeSLOReconciler) initNodeSLO(node *corev1.Node, nodeSLO *slov1alpha1.
 	nodeSLO.Spec = *spec
 	nodeSLO.SetName(node.GetName())
 	nodeSLO.SetNamespace(node.GetNamespace())
+	sloconfig.SetNodeSLOSchemaVersion(nodeSLO)
 
 	return nil
 }
@@ -117,13 +126,34 @@ func (r *NodeSLOReconciler) getNodeSLOSpec(node *corev1.Node, oldSpec *slov1alph
 
 	nodeSLOSpec.Extensions = getExtensionsConfigSpec(node, oldSpec, &sloCfg.ExtensionCfgMerged)
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeSLOColocationReadiness) {
+		r.holdBackByColocationReadiness(node, nodeSLOSpec)
+	}
+
 	return nodeSLOSpec, nil
 }
 
+// holdBackByColocationReadiness disables the strategies unsupported by the node according to the colocation readiness
+// reported by the koordlet. The strategies are kept if the readiness is not reported yet.
+func (r *NodeSLOReconciler) holdBackByColocationReadiness(node *corev1.Node, nodeSLOSpec *slov1alpha1.NodeSLOSpec) {
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, nodeMetric)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Warningf("getNodeSLOSpec(): failed to get nodeMetric for node %s, error: %v", node.Name, err)
+		}
+		return
+	}
+	if failed := holdBackStrategiesByReadiness(nodeSLOSpec, nodeMetric.Status.ColocationReadiness); len(failed) > 0 {
+		klog.V(4).Infof("getNodeSLOSpec(): hold back strategies for node %s, failed readiness checks %v", node.Name, failed)
+	}
+}
+
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
 
 func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// reconcile for 2 things:
@@ -139,6 +169,14 @@ func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, nil
 	}
 
+	if r.sharder != nil {
+		// the node is reconciled by its owner replica
+		if !r.sharder.IsOwner(req.Name) {
+			return ctrl.Result{}, nil
+		}
+		defer r.sharder.Reconciled(Name, req.Name)
+	}
+
 	// get the node
 	nodeExist := true
 	nodeName := req.Name
@@ -205,7 +243,9 @@ func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			klog.Errorf("failed to get nodeSLO %v, spec: %v", nodeSLOName, err)
 			return ctrl.Result{Requeue: true}, err
 		}
-		if !reflect.DeepEqual(nodeSLOSpec, &nodeSLO.Spec) {
+		// mark the schema version for the NodeSLO written by the older slo-controller
+		versionChanged := sloconfig.SetNodeSLOSchemaVersion(nodeSLO)
+		if versionChanged || !reflect.DeepEqual(nodeSLOSpec, &nodeSLO.Spec) {
 			nodeSLO.Spec = *nodeSLOSpec
 			err = r.Client.Update(context.TODO(), nodeSLO)
 			if err != nil {
@@ -227,18 +267,62 @@ func Add(mgr ctrl.Manager) error {
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("nodeslo-controller"),
 	}
+	if sharding.Enabled {
+		sharder, err := sharding.GetOrCreate(mgr)
+		if err != nil {
+			return err
+		}
+		reconciler.sharder = sharder
+	}
 	return reconciler.SetupWithManager(mgr)
 }
 
 func (r *NodeSLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	configMapCacheHandler := NewSLOCfgHandlerForConfigMapEvent(r.Client, DefaultSLOCfg(), r.Recorder)
 	r.sloCfgCache = configMapCacheHandler
-	return ctrl.NewControllerManagedBy(mgr).
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeSLOThresholdSimulation) {
+		if err := mgr.AddMetricsExtraHandler(ThresholdSimulationPath, NewThresholdSimulationHandler(r.Client)); err != nil {
+			return err
+		}
+	}
+	if r.sharder != nil {
+		return r.setupShardedController(mgr, configMapCacheHandler)
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&slov1alpha1.NodeSLO{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&source.Kind{Type: &corev1.Node{}}, &nodemetric.EnqueueRequestForNode{
 			Client: r.Client,
 		}).
-		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, configMapCacheHandler).
-		Named(Name).
-		Complete(r)
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, configMapCacheHandler)
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeSLOColocationReadiness) {
+		// the NodeMetric has the same name as the node and the NodeSLO
+		b = b.Watches(&source.Kind{Type: &slov1alpha1.NodeMetric{}}, &handler.EnqueueRequestForObject{},
+			builder.WithPredicates(colocationReadinessChangedPredicate))
+	}
+	return b.Named(Name).Complete(r)
+}
+
+// setupShardedController sets up the controller running on all replicas with the same watches.
+func (r *NodeSLOReconciler) setupShardedController(mgr ctrl.Manager, cfgHandler handler.EventHandler) error {
+	c, err := sharding.NewController(mgr, r.sharder, Name, r)
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &slov1alpha1.NodeSLO{}}, &handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{}); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.Node{}}, &nodemetric.EnqueueRequestForNode{
+		Client: r.Client,
+	}); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, cfgHandler); err != nil {
+		return err
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeSLOColocationReadiness) {
+		return c.Watch(&source.Kind{Type: &slov1alpha1.NodeMetric{}}, &handler.EnqueueRequestForObject{},
+			colocationReadinessChangedPredicate)
+	}
+	return nil
 }
@@ -129,6 +129,11 @@ func calculateResourceThresholdCfgMerged(oldCfg configuration.ResourceThresholdC
 		return oldCfg, err
 	}
 
+	return mergeResourceThresholdCfg(mergedCfg), nil
+}
+
+// mergeResourceThresholdCfg merges the cluster strategy with the default, and the node strategies with the cluster strategy.
+func mergeResourceThresholdCfg(mergedCfg configuration.ResourceThresholdCfg) configuration.ResourceThresholdCfg {
 	// merge ClusterStrategy
 	clusterMerged := DefaultSLOCfg().ThresholdCfgMerged.ClusterStrategy.DeepCopy()
 	if mergedCfg.ClusterStrategy != nil {
@@ -149,7 +154,7 @@ func calculateResourceThresholdCfgMerged(oldCfg configuration.ResourceThresholdC
 
 	}
 
-	return mergedCfg, nil
+	return mergedCfg
 }
 
 func calculateResourceQOSCfgMerged(oldCfg configuration.ResourceQOSCfg, configMap *corev1.ConfigMap) (configuration.ResourceQOSCfg, error) {
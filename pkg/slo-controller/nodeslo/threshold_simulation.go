@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	// ThresholdSimulationPath is the path of the what-if API served on the metrics address of the koord-manager.
+	ThresholdSimulationPath = "/slo/threshold-simulation"
+
+	// clusterStrategyPoolName is the node pool of the nodes matching no node strategy.
+	clusterStrategyPoolName = "cluster"
+
+	// maxThresholdSimulationRequestBytes is the size limit of the what-if request body.
+	maxThresholdSimulationRequestBytes = 1 << 20
+)
+
+// usagePercentiles are the aggregation types of the NodeMetric history in the ascending order.
+var usagePercentiles = []struct {
+	aggregationType apiext.AggregationType
+	percentile      int64
+}{
+	{aggregationType: apiext.P50, percentile: 50},
+	{aggregationType: apiext.P90, percentile: 90},
+	{aggregationType: apiext.P95, percentile: 95},
+	{aggregationType: apiext.P99, percentile: 99},
+}
+
+// ThresholdSimulationRequest is the proposed NodeSLO thresholds.
+type ThresholdSimulationRequest struct {
+	// ResourceThreshold has the same format as the resource-threshold-config of the slo-controller-config.
+	ResourceThreshold configuration.ResourceThresholdCfg `json:"resourceThreshold"`
+}
+
+// ThresholdSimulationResponse is the estimation of the proposed thresholds per node pool.
+type ThresholdSimulationResponse struct {
+	NodePools []NodePoolThresholdSimulation `json:"nodePools"`
+}
+
+// NodePoolThresholdSimulation is the estimation on the nodes selected by a node strategy.
+type NodePoolThresholdSimulation struct {
+	// Name is the name of the node strategy, or "cluster" for the nodes matching no node strategy.
+	Name string `json:"name"`
+	// Nodes is the number of the nodes in the pool.
+	Nodes int `json:"nodes"`
+	// SimulatedNodes is the number of the nodes which have the aggregated usages in the NodeMetric.
+	SimulatedNodes int `json:"simulatedNodes"`
+	// CPUSuppress is the estimation of the BE CPU suppression.
+	CPUSuppress ThresholdTriggerEstimation `json:"cpuSuppress"`
+	// MemoryEvict is the estimation of the BE memory eviction.
+	MemoryEvict ThresholdTriggerEstimation `json:"memoryEvict"`
+}
+
+// ThresholdTriggerEstimation estimates how often a threshold would have triggered.
+type ThresholdTriggerEstimation struct {
+	// Enabled indicates whether the strategy is enabled in the pool. The triggers are estimated even if it is disabled.
+	Enabled bool `json:"enabled"`
+	// ThresholdPercent is the threshold of the pool.
+	ThresholdPercent int64 `json:"thresholdPercent,omitempty"`
+	// TriggeredNodes is the number of the nodes on which the threshold would have triggered.
+	TriggeredNodes int `json:"triggeredNodes"`
+	// AvgTriggeredPercent is the average percentage of the time exceeding the threshold over the simulated nodes.
+	// It is estimated by the usage percentiles, so it is a lower bound, e.g. 5 means the p95 usage exceeds the
+	// threshold while the p90 usage does not.
+	AvgTriggeredPercent int64 `json:"avgTriggeredPercent"`
+}
+
+// thresholdSimulationHandler serves the what-if API for the NodeSLO threshold changes. It replays the aggregated
+// usages in the NodeMetrics with the proposed thresholds, so the operators can tune the thresholds before rollout.
+type thresholdSimulationHandler struct {
+	client client.Client
+}
+
+func NewThresholdSimulationHandler(c client.Client) http.Handler {
+	return &thresholdSimulationHandler{client: c}
+}
+
+func (h *thresholdSimulationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	request := &ThresholdSimulationRequest{}
+	r.Body = http.MaxBytesReader(w, r.Body, maxThresholdSimulationRequestBytes)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request, err: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.client.List(r.Context(), nodeList); err != nil {
+		http.Error(w, fmt.Sprintf("failed to list nodes, err: %v", err), http.StatusInternalServerError)
+		return
+	}
+	nodeMetrics, err := h.getNodeMetrics(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list node metrics, err: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := simulateResourceThreshold(nodeList.Items, nodeMetrics, mergeResourceThresholdCfg(request.ResourceThreshold))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		klog.V(4).Infof("failed to write threshold simulation response, err: %v", err)
+	}
+}
+
+func (h *thresholdSimulationHandler) getNodeMetrics(ctx context.Context) (map[string]*slov1alpha1.NodeMetric, error) {
+	nodeMetricList := &slov1alpha1.NodeMetricList{}
+	if err := h.client.List(ctx, nodeMetricList); err != nil {
+		return nil, err
+	}
+	nodeMetrics := make(map[string]*slov1alpha1.NodeMetric, len(nodeMetricList.Items))
+	for i := range nodeMetricList.Items {
+		nodeMetrics[nodeMetricList.Items[i].Name] = &nodeMetricList.Items[i]
+	}
+	return nodeMetrics, nil
+}
+
+// simulateResourceThreshold estimates the triggers of the merged thresholds on the nodes grouped by the node pools.
+// The pools are listed in the order of the node strategies, and the cluster pool is the last.
+func simulateResourceThreshold(nodes []corev1.Node, nodeMetrics map[string]*slov1alpha1.NodeMetric, cfg configuration.ResourceThresholdCfg) *ThresholdSimulationResponse {
+	pools := make([]NodePoolThresholdSimulation, len(cfg.NodeStrategies)+1)
+	strategies := make([]*slov1alpha1.ResourceThresholdStrategy, len(cfg.NodeStrategies)+1)
+	for i := range cfg.NodeStrategies {
+		pools[i].Name = cfg.NodeStrategies[i].Name
+		if pools[i].Name == "" {
+			pools[i].Name = fmt.Sprintf("nodeStrategy-%d", i)
+		}
+		strategies[i] = cfg.NodeStrategies[i].ResourceThresholdStrategy
+	}
+	clusterIndex := len(cfg.NodeStrategies)
+	pools[clusterIndex].Name = clusterStrategyPoolName
+	strategies[clusterIndex] = cfg.ClusterStrategy
+
+	cpuTriggeredPercents := make([]int64, len(pools))
+	memoryTriggeredPercents := make([]int64, len(pools))
+	for i := range nodes {
+		node := &nodes[i]
+		index := matchResourceThresholdStrategy(node, cfg)
+		pool := &pools[index]
+		pool.Nodes++
+
+		usages := getLongestAggregatedNodeUsage(nodeMetrics[node.Name])
+		if usages == nil {
+			continue
+		}
+		pool.SimulatedNodes++
+		strategy := strategies[index]
+		if percent := estimateTriggeredPercent(usages, node.Status.Capacity, corev1.ResourceCPU, strategy.CPUSuppressThresholdPercent); percent > 0 {
+			pool.CPUSuppress.TriggeredNodes++
+			cpuTriggeredPercents[index] += percent
+		}
+		if percent := estimateTriggeredPercent(usages, node.Status.Capacity, corev1.ResourceMemory, strategy.MemoryEvictThresholdPercent); percent > 0 {
+			pool.MemoryEvict.TriggeredNodes++
+			memoryTriggeredPercents[index] += percent
+		}
+	}
+
+	for i := range pools {
+		pool := &pools[i]
+		enabled := strategies[i] != nil && strategies[i].Enable != nil && *strategies[i].Enable
+		pool.CPUSuppress.Enabled = enabled
+		pool.MemoryEvict.Enabled = enabled
+		if strategies[i] != nil && strategies[i].CPUSuppressThresholdPercent != nil {
+			pool.CPUSuppress.ThresholdPercent = *strategies[i].CPUSuppressThresholdPercent
+		}
+		if strategies[i] != nil && strategies[i].MemoryEvictThresholdPercent != nil {
+			pool.MemoryEvict.ThresholdPercent = *strategies[i].MemoryEvictThresholdPercent
+		}
+		if pool.SimulatedNodes > 0 {
+			pool.CPUSuppress.AvgTriggeredPercent = cpuTriggeredPercents[i] / int64(pool.SimulatedNodes)
+			pool.MemoryEvict.AvgTriggeredPercent = memoryTriggeredPercents[i] / int64(pool.SimulatedNodes)
+		}
+	}
+	return &ThresholdSimulationResponse{NodePools: pools}
+}
+
+// matchResourceThresholdStrategy returns the index of the first node strategy selecting the node,
+// or the number of the node strategies if the node uses the cluster strategy.
+func matchResourceThresholdStrategy(node *corev1.Node, cfg configuration.ResourceThresholdCfg) int {
+	nodeLabels := labels.Set(node.Labels)
+	for i, nodeStrategy := range cfg.NodeStrategies {
+		selector, err := metav1.LabelSelectorAsSelector(nodeStrategy.NodeSelector)
+		if err != nil {
+			klog.V(5).Infof("failed to parse node selector %v for threshold simulation, err: %v", nodeStrategy.NodeSelector, err)
+			continue
+		}
+		if selector.Matches(nodeLabels) {
+			return i
+		}
+	}
+	return len(cfg.NodeStrategies)
+}
+
+// getLongestAggregatedNodeUsage returns the node usage percentiles aggregated over the longest window.
+func getLongestAggregatedNodeUsage(nodeMetric *slov1alpha1.NodeMetric) map[apiext.AggregationType]slov1alpha1.ResourceMap {
+	if nodeMetric == nil || nodeMetric.Status.NodeMetric == nil {
+		return nil
+	}
+	var longest *slov1alpha1.AggregatedUsage
+	for i := range nodeMetric.Status.NodeMetric.AggregatedNodeUsages {
+		aggregated := &nodeMetric.Status.NodeMetric.AggregatedNodeUsages[i]
+		if len(aggregated.Usage) <= 0 {
+			continue
+		}
+		if longest == nil || aggregated.Duration.Duration > longest.Duration.Duration {
+			longest = aggregated
+		}
+	}
+	if longest == nil {
+		return nil
+	}
+	return longest.Usage
+}
+
+// estimateTriggeredPercent estimates the percentage of the time the usage exceeds the threshold percent of the
+// node capacity, by the lowest usage percentile above the threshold. The capacity is used since the koordlet checks
+// the thresholds against the node capacity as well.
+func estimateTriggeredPercent(usages map[apiext.AggregationType]slov1alpha1.ResourceMap, capacity corev1.ResourceList,
+	resourceName corev1.ResourceName, thresholdPercent *int64) int64 {
+	if thresholdPercent == nil {
+		return 0
+	}
+	total, ok := capacity[resourceName]
+	if !ok || total.IsZero() {
+		return 0
+	}
+	threshold := total.MilliValue() * *thresholdPercent / 100
+	for _, p := range usagePercentiles {
+		usage, ok := usages[p.aggregationType].ResourceList[resourceName]
+		if !ok {
+			continue
+		}
+		if usage.MilliValue() > threshold {
+			return 100 - p.percentile
+		}
+	}
+	return 0
+}
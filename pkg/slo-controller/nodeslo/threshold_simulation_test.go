@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func testNodeMetricWithUsages(name string, usages map[apiext.AggregationType]corev1.ResourceList) *slov1alpha1.NodeMetric {
+	aggregated := map[apiext.AggregationType]slov1alpha1.ResourceMap{}
+	for aggregationType, usage := range usages {
+		aggregated[aggregationType] = slov1alpha1.ResourceMap{ResourceList: usage}
+	}
+	return &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: slov1alpha1.NodeMetricStatus{
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				AggregatedNodeUsages: []slov1alpha1.AggregatedUsage{
+					{
+						Duration: metav1.Duration{Duration: 5 * time.Minute},
+						Usage: map[apiext.AggregationType]slov1alpha1.ResourceMap{
+							apiext.P50: {ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+						},
+					},
+					{
+						Duration: metav1.Duration{Duration: 30 * time.Minute},
+						Usage:    aggregated,
+					},
+				},
+			},
+		},
+	}
+}
+
+func testNodeWithLabels(name string, nodeLabels map[string]string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10"),
+				corev1.ResourceMemory: resource.MustParse("100Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("80Gi"),
+			},
+		},
+	}
+}
+
+func Test_estimateTriggeredPercent(t *testing.T) {
+	usages := map[apiext.AggregationType]slov1alpha1.ResourceMap{
+		apiext.P50: {ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+		apiext.P90: {ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")}},
+		apiext.P95: {ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("7")}},
+		apiext.P99: {ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("9")}},
+	}
+	capacity := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")}
+	tests := []struct {
+		name             string
+		thresholdPercent *int64
+		want             int64
+	}{
+		{
+			name: "threshold not set",
+			want: 0,
+		},
+		{
+			name:             "never exceeded",
+			thresholdPercent: pointer.Int64(95),
+			want:             0,
+		},
+		{
+			name:             "p99 exceeded",
+			thresholdPercent: pointer.Int64(80),
+			want:             1,
+		},
+		{
+			name:             "p90 exceeded",
+			thresholdPercent: pointer.Int64(55),
+			want:             10,
+		},
+		{
+			name:             "p50 exceeded",
+			thresholdPercent: pointer.Int64(30),
+			want:             50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, estimateTriggeredPercent(usages, capacity, corev1.ResourceCPU, tt.thresholdPercent))
+		})
+	}
+}
+
+func Test_simulateResourceThreshold(t *testing.T) {
+	nodes := []corev1.Node{
+		testNodeWithLabels("node-0", map[string]string{"pool": "online"}),
+		testNodeWithLabels("node-1", map[string]string{"pool": "online"}),
+		testNodeWithLabels("node-2", nil),
+		testNodeWithLabels("node-3", nil),
+	}
+	nodeMetrics := map[string]*slov1alpha1.NodeMetric{
+		"node-0": testNodeMetricWithUsages("node-0", map[apiext.AggregationType]corev1.ResourceList{
+			apiext.P50: {corev1.ResourceCPU: resource.MustParse("3"), corev1.ResourceMemory: resource.MustParse("50Gi")},
+			apiext.P95: {corev1.ResourceCPU: resource.MustParse("6"), corev1.ResourceMemory: resource.MustParse("60Gi")},
+		}),
+		"node-1": testNodeMetricWithUsages("node-1", map[apiext.AggregationType]corev1.ResourceList{
+			apiext.P50: {corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("40Gi")},
+			apiext.P95: {corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("45Gi")},
+		}),
+		"node-2": testNodeMetricWithUsages("node-2", map[apiext.AggregationType]corev1.ResourceList{
+			apiext.P50: {corev1.ResourceCPU: resource.MustParse("7"), corev1.ResourceMemory: resource.MustParse("80Gi")},
+			apiext.P95: {corev1.ResourceCPU: resource.MustParse("9"), corev1.ResourceMemory: resource.MustParse("90Gi")},
+		}),
+	}
+	cfg := mergeResourceThresholdCfg(configuration.ResourceThresholdCfg{
+		ClusterStrategy: &slov1alpha1.ResourceThresholdStrategy{
+			Enable:                      pointer.Bool(true),
+			CPUSuppressThresholdPercent: pointer.Int64(65),
+			MemoryEvictThresholdPercent: pointer.Int64(70),
+		},
+		NodeStrategies: []configuration.NodeResourceThresholdStrategy{
+			{
+				NodeCfgProfile: configuration.NodeCfgProfile{
+					Name: "online",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"pool": "online"},
+					},
+				},
+				ResourceThresholdStrategy: &slov1alpha1.ResourceThresholdStrategy{
+					CPUSuppressThresholdPercent: pointer.Int64(50),
+				},
+			},
+		},
+	})
+
+	got := simulateResourceThreshold(nodes, nodeMetrics, cfg)
+	want := &ThresholdSimulationResponse{
+		NodePools: []NodePoolThresholdSimulation{
+			{
+				Name:           "online",
+				Nodes:          2,
+				SimulatedNodes: 2,
+				CPUSuppress: ThresholdTriggerEstimation{
+					Enabled:             true,
+					ThresholdPercent:    50,
+					TriggeredNodes:      1,
+					AvgTriggeredPercent: 2,
+				},
+				MemoryEvict: ThresholdTriggerEstimation{
+					Enabled:          true,
+					ThresholdPercent: 70,
+				},
+			},
+			{
+				Name:           "cluster",
+				Nodes:          2,
+				SimulatedNodes: 1,
+				CPUSuppress: ThresholdTriggerEstimation{
+					Enabled:             true,
+					ThresholdPercent:    65,
+					TriggeredNodes:      1,
+					AvgTriggeredPercent: 50,
+				},
+				MemoryEvict: ThresholdTriggerEstimation{
+					Enabled:             true,
+					ThresholdPercent:    70,
+					TriggeredNodes:      1,
+					AvgTriggeredPercent: 50,
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestThresholdSimulationHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	slov1alpha1.AddToScheme(scheme)
+	node := testNodeWithLabels("node-0", nil)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&node,
+		testNodeMetricWithUsages("node-0", map[apiext.AggregationType]corev1.ResourceList{
+			apiext.P90: {corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("50Gi")},
+		})).Build()
+	handler := NewThresholdSimulationHandler(fakeClient)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ThresholdSimulationPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, ThresholdSimulationPath, bytes.NewBufferString("{")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	oversized := `{"resourceThreshold":{"nodeStrategies":[],"padding":"` + strings.Repeat("x", maxThresholdSimulationRequestBytes) + `"}}`
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, ThresholdSimulationPath, bytes.NewBufferString(oversized)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, err := json.Marshal(&ThresholdSimulationRequest{
+		ResourceThreshold: configuration.ResourceThresholdCfg{
+			ClusterStrategy: &slov1alpha1.ResourceThresholdStrategy{
+				CPUSuppressThresholdPercent: pointer.Int64(70),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, ThresholdSimulationPath, bytes.NewReader(body)).WithContext(context.TODO()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	got := &ThresholdSimulationResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), got))
+	assert.Len(t, got.NodePools, 1)
+	assert.Equal(t, 1, got.NodePools[0].SimulatedNodes)
+	assert.Equal(t, int64(70), got.NodePools[0].CPUSuppress.ThresholdPercent)
+	assert.Equal(t, 1, got.NodePools[0].CPUSuppress.TriggeredNodes)
+	assert.Equal(t, int64(10), got.NodePools[0].CPUSuppress.AvgTriggeredPercent)
+}
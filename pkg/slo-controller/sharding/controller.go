@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NewController creates a controller which runs on all replicas instead of the leader only, and requeues the
+// nodes newly owned by the replica on the rebalance of the sharder. The caller adds the other watches, and the
+// reconciler should skip the nodes not owned by the replica.
+func NewController(mgr manager.Manager, s *Sharder, name string, r reconcile.Reconciler) (controller.Controller, error) {
+	c, err := controller.NewUnmanaged(name, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Watch(&source.Channel{Source: s.Events(name)}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(&shardedController{Controller: c}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type shardedController struct {
+	controller.Controller
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, the sharded controller runs on all replicas.
+func (c *shardedController) NeedLeaderElection() bool {
+	return false
+}
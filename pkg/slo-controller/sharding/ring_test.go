@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRing(t *testing.T) {
+	empty := NewRing(nil, 10)
+	assert.Equal(t, "", empty.Get("test-node"))
+
+	ring := NewRing([]string{"shard-c", "shard-a", "shard-b"}, 100)
+	assert.Equal(t, []string{"shard-a", "shard-b", "shard-c"}, ring.Members())
+
+	counts := map[string]int{}
+	owners := map[string]string{}
+	for i := 0; i < 3000; i++ {
+		node := "node-" + strconv.Itoa(i)
+		owner := ring.Get(node)
+		owners[node] = owner
+		counts[owner]++
+	}
+	for _, member := range ring.Members() {
+		// the virtual nodes keep the partitions roughly even
+		assert.Greater(t, counts[member], 500, member)
+	}
+
+	// the same members build the same ring regardless of the order
+	assert.Equal(t, owners["node-0"], NewRing([]string{"shard-b", "shard-c", "shard-a"}, 100).Get("node-0"))
+
+	// only the nodes of the removed member are moved
+	shrunk := NewRing([]string{"shard-a", "shard-b"}, 100)
+	for node, owner := range owners {
+		if owner != "shard-c" {
+			assert.Equal(t, owner, shrunk.Get(node), node)
+		} else {
+			assert.NotEqual(t, "shard-c", shrunk.Get(node), node)
+		}
+	}
+}
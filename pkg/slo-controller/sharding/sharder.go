@@ -0,0 +1,353 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metrics"
+)
+
+const (
+	// LabelShardGroup is the label of the shard leases, whose value is the group of the sharded controllers.
+	LabelShardGroup = extension.DomainPrefix + "shard-group"
+
+	shardGroup      = "slo-controller"
+	leaseNamePrefix = shardGroup + "-shard-"
+)
+
+var (
+	// Enabled indicates whether the node reconciliation of the slo-controller is partitioned across the replicas.
+	// The sharded controllers run on every replica instead of the leader only.
+	Enabled = false
+	// Namespace is the namespace of the shard leases.
+	Namespace = "koordinator-system"
+	// VirtualNodes is the number of the virtual nodes of each shard on the hashing ring.
+	VirtualNodes = 100
+	// LeaseDuration is the duration after which a shard is removed from the ring if it stops renewing its lease.
+	LeaseDuration = 15 * time.Second
+	// RenewInterval is the interval to renew the shard lease and to resync the members of the ring.
+	RenewInterval = 5 * time.Second
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&Enabled, "enable-node-sharding", Enabled,
+		"Whether to partition the NodeMetric and NodeSLO reconciliation across the replicas by consistent hashing.")
+	fs.StringVar(&Namespace, "node-sharding-namespace", Namespace, "The namespace of the node sharding leases.")
+	fs.IntVar(&VirtualNodes, "node-sharding-virtual-nodes", VirtualNodes,
+		"The number of the virtual nodes of each replica on the node sharding ring.")
+	fs.DurationVar(&LeaseDuration, "node-sharding-lease-duration", LeaseDuration,
+		"The duration after which a replica is removed from the node sharding ring if it stops renewing its lease.")
+	fs.DurationVar(&RenewInterval, "node-sharding-renew-interval", RenewInterval,
+		"The interval to renew the node sharding lease and to resync the members of the ring.")
+}
+
+// Sharder partitions the nodes across the replicas of the manager. Each replica holds a lease as its membership of
+// the ring, and owns the nodes hashed to it. When the members change, the newly owned nodes are requeued to the
+// sharded controllers, and the time until they are reconciled is observed as the reconcile lag of the shard.
+type Sharder struct {
+	client   client.Client
+	reader   client.Reader
+	identity string
+	now      func() time.Time
+
+	lock    sync.RWMutex
+	ring    *Ring
+	owned   map[string]struct{}
+	pending map[string]map[string]time.Time
+	events  map[string]chan event.GenericEvent
+}
+
+func NewSharder(c client.Client, reader client.Reader, identity string) *Sharder {
+	return &Sharder{
+		client:   c,
+		reader:   reader,
+		identity: identity,
+		now:      time.Now,
+		owned:    map[string]struct{}{},
+		pending:  map[string]map[string]time.Time{},
+		events:   map[string]chan event.GenericEvent{},
+	}
+}
+
+var (
+	sharderLock sync.Mutex
+	sharders    = map[manager.Manager]*Sharder{}
+)
+
+// GetOrCreate returns the sharder of the manager, which is shared by all sharded controllers of the manager.
+// The sharder is added to the manager on creation and runs on all replicas.
+func GetOrCreate(mgr manager.Manager) (*Sharder, error) {
+	sharderLock.Lock()
+	defer sharderLock.Unlock()
+	if s, ok := sharders[mgr]; ok {
+		return s, nil
+	}
+	identity, err := newIdentity()
+	if err != nil {
+		return nil, err
+	}
+	s := NewSharder(mgr.GetClient(), mgr.GetAPIReader(), identity)
+	if err := mgr.Add(s); err != nil {
+		return nil, err
+	}
+	sharders[mgr] = s
+	klog.Infof("node sharding enabled, identity %s", identity)
+	return s, nil
+}
+
+func newIdentity() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get hostname for the node sharding, err: %v", err)
+	}
+	return strings.ToLower(hostname), nil
+}
+
+// Identity returns the shard name of the replica.
+func (s *Sharder) Identity() string {
+	return s.identity
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, the sharder runs on all replicas.
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the lease of the replica and resyncs the ring until the context is done.
+func (s *Sharder) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, s.sync, RenewInterval)
+	// release the lease so the other replicas take over the nodes without waiting for the expiration
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: s.leaseName()}}
+	if err := s.client.Delete(context.TODO(), lease); err != nil && !apierrors.IsNotFound(err) {
+		klog.Warningf("failed to release the node sharding lease %s, err: %v", s.leaseName(), err)
+	}
+	return nil
+}
+
+// Events returns the channel of the node events requeued to the controller on the rebalance.
+func (s *Sharder) Events(controller string) <-chan event.GenericEvent {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.eventsLocked(controller)
+}
+
+func (s *Sharder) eventsLocked(controller string) chan event.GenericEvent {
+	ch, ok := s.events[controller]
+	if !ok {
+		ch = make(chan event.GenericEvent, 1024)
+		s.events[controller] = ch
+		s.pending[controller] = map[string]time.Time{}
+	}
+	return ch
+}
+
+// IsOwner returns whether the node is owned by the replica. No node is owned until the ring is synced.
+func (s *Sharder) IsOwner(nodeName string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.ring == nil {
+		return false
+	}
+	return s.ring.Get(nodeName) == s.identity
+}
+
+// Reconciled marks the node reconciled by the controller, and observes the lag if the node is requeued by the
+// last rebalance.
+func (s *Sharder) Reconciled(controller, nodeName string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	pending := s.pending[controller]
+	rebalanceTime, ok := pending[nodeName]
+	if !ok {
+		return
+	}
+	delete(pending, nodeName)
+	metrics.RecordShardReconcileLagSeconds(controller, s.identity, s.now().Sub(rebalanceTime).Seconds())
+	metrics.RecordShardPendingNodes(controller, s.identity, len(pending))
+}
+
+func (s *Sharder) sync(ctx context.Context) {
+	if err := s.renew(ctx); err != nil {
+		klog.Warningf("failed to renew the node sharding lease %s, err: %v", s.leaseName(), err)
+	}
+	members, err := s.listMembers(ctx)
+	if err != nil {
+		klog.Warningf("failed to list the node sharding members, err: %v", err)
+		return
+	}
+	if !s.membersChanged(members) {
+		return
+	}
+	klog.Infof("node sharding members changed to %v, rebalance the nodes", members)
+	if err := s.rebalance(ctx, NewRing(members, VirtualNodes)); err != nil {
+		klog.Warningf("failed to rebalance the nodes of the shard %s, err: %v", s.identity, err)
+	}
+}
+
+func (s *Sharder) leaseName() string {
+	return leaseNamePrefix + s.identity
+}
+
+func (s *Sharder) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(s.now())
+	lease := &coordinationv1.Lease{}
+	err := s.reader.Get(ctx, client.ObjectKey{Namespace: Namespace, Name: s.leaseName()}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: Namespace,
+				Name:      s.leaseName(),
+				Labels:    map[string]string{LabelShardGroup: shardGroup},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(s.identity),
+				LeaseDurationSeconds: pointer.Int32(int32(LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		return s.client.Create(ctx, lease)
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = pointer.String(s.identity)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	return s.client.Update(ctx, lease)
+}
+
+// listMembers returns the holders of the shard leases which are not expired.
+func (s *Sharder) listMembers(ctx context.Context) ([]string, error) {
+	leaseList := &coordinationv1.LeaseList{}
+	if err := s.reader.List(ctx, leaseList, client.InNamespace(Namespace),
+		client.MatchingLabels{LabelShardGroup: shardGroup}); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	var members []string
+	for i := range leaseList.Items {
+		spec := &leaseList.Items[i].Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expireTime := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if !expireTime.After(now) {
+			continue
+		}
+		members = append(members, *spec.HolderIdentity)
+	}
+	return members, nil
+}
+
+func (s *Sharder) membersChanged(members []string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.ring == nil {
+		return true
+	}
+	oldMembers := s.ring.Members()
+	if len(oldMembers) != len(members) {
+		return true
+	}
+	newMembers := NewRing(members, 1).Members()
+	for i := range oldMembers {
+		if oldMembers[i] != newMembers[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// rebalance replaces the ring, and requeues the nodes newly owned by the replica to the sharded controllers.
+// The nodes keeping the owner are not requeued since they have been reconciled by the replica.
+func (s *Sharder) rebalance(ctx context.Context, ring *Ring) error {
+	nodeList := &corev1.NodeList{}
+	if err := s.client.List(ctx, nodeList); err != nil {
+		return err
+	}
+	rebalanceTime := s.now()
+	owned := map[string]struct{}{}
+	var added []*corev1.Node
+	s.lock.Lock()
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if ring.Get(node.Name) != s.identity {
+			continue
+		}
+		owned[node.Name] = struct{}{}
+		if _, ok := s.owned[node.Name]; !ok {
+			added = append(added, node)
+		}
+	}
+	s.ring = ring
+	s.owned = owned
+	channels := map[string]chan event.GenericEvent{}
+	for controller, ch := range s.events {
+		pending := s.pending[controller]
+		for nodeName := range pending {
+			if _, ok := owned[nodeName]; !ok {
+				delete(pending, nodeName)
+			}
+		}
+		for _, node := range added {
+			pending[node.Name] = rebalanceTime
+		}
+		metrics.RecordShardPendingNodes(controller, s.identity, len(pending))
+		channels[controller] = ch
+	}
+	s.lock.Unlock()
+
+	metrics.RecordShardMembers(len(ring.Members()))
+	metrics.RecordShardOwnedNodes(s.identity, len(owned))
+	metrics.RecordShardRebalanceCount(s.identity)
+
+	// requeue asynchronously so a slow controller does not block the lease renewal
+	for _, ch := range channels {
+		go requeue(ctx, ch, added)
+	}
+	return nil
+}
+
+func requeue(ctx context.Context, ch chan<- event.GenericEvent, nodes []*corev1.Node) {
+	for _, node := range nodes {
+		select {
+		case ch <- event.GenericEvent{Object: node}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSharder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := 0; i < 20; i++ {
+		builder = builder.WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + strconv.Itoa(i)}})
+	}
+	c := builder.Build()
+	ctx := context.TODO()
+	now := time.Now()
+
+	shardA := NewSharder(c, c, "shard-a")
+	shardA.now = func() time.Time { return now }
+	shardB := NewSharder(c, c, "shard-b")
+	shardB.now = func() time.Time { return now }
+	eventsA := shardA.Events("test-controller")
+	eventsB := shardB.Events("test-controller")
+
+	// no node is owned before the ring is synced
+	assert.False(t, shardA.IsOwner("node-0"))
+
+	// the only member owns all nodes
+	shardA.sync(ctx)
+	assert.Len(t, receiveEvents(t, eventsA, 20), 20)
+	for i := 0; i < 20; i++ {
+		assert.True(t, shardA.IsOwner("node-"+strconv.Itoa(i)))
+	}
+
+	// the new member takes over a part of the nodes, and the nodes are partitioned once both members are synced
+	shardB.sync(ctx)
+	shardA.sync(ctx)
+	nodesB := receiveEvents(t, eventsB, 20)
+	assert.NotEmpty(t, nodesB)
+	assert.Less(t, len(nodesB), 20)
+	for i := 0; i < 20; i++ {
+		node := "node-" + strconv.Itoa(i)
+		assert.NotEqual(t, shardA.IsOwner(node), shardB.IsOwner(node), node)
+		assert.Equal(t, nodesB[node], shardB.IsOwner(node), node)
+	}
+	assert.Len(t, receiveEvents(t, eventsA, 20), 0, "the kept nodes should not be requeued")
+	// the nodes moved away are no longer pending on the previous owner
+	assert.Len(t, shardA.pending["test-controller"], 20-len(nodesB))
+	assert.Len(t, shardB.pending["test-controller"], len(nodesB))
+	for node := range nodesB {
+		shardB.Reconciled("test-controller", node)
+	}
+	assert.Len(t, shardB.pending["test-controller"], 0)
+
+	// the nodes of the expired member are taken over by the others
+	now = now.Add(LeaseDuration + time.Second)
+	shardA.sync(ctx)
+	assert.Equal(t, nodesB, receiveEvents(t, eventsA, 20))
+	for i := 0; i < 20; i++ {
+		assert.True(t, shardA.IsOwner("node-"+strconv.Itoa(i)))
+	}
+	assert.Len(t, shardA.pending["test-controller"], 20)
+	shardA.Reconciled("test-controller", "node-0")
+	assert.Len(t, shardA.pending["test-controller"], 19)
+}
+
+// receiveEvents returns the names of the nodes requeued to the channel until no more event arrives.
+func receiveEvents(t *testing.T, ch <-chan event.GenericEvent, max int) map[string]bool {
+	nodes := map[string]bool{}
+	for len(nodes) < max {
+		select {
+		case e := <-ch:
+			nodes[e.Object.GetName()] = true
+		case <-time.After(100 * time.Millisecond):
+			return nodes
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// Mode indicates how strictly the annotations are parsed.
+type Mode string
+
+const (
+	// ModeLenient decodes the annotations into the typed structs and ignores the unknown fields and values, so the
+	// annotations written by the components of other versions are still accepted.
+	ModeLenient Mode = "Lenient"
+	// ModeStrict validates the annotations against their schemas and the semantic constraints before decoding.
+	ModeStrict Mode = "Strict"
+)
+
+var (
+	// LenientParser is used by the components consuming the annotations, e.g. koord-scheduler and koordlet.
+	LenientParser = NewParser(ModeLenient)
+	// StrictParser is used by the components admitting the annotations, e.g. koord-manager webhook.
+	StrictParser = NewParser(ModeStrict)
+)
+
+// Parser parses the extension annotations in one place, so that the scheduler, the koordlet and the webhook do not
+// drift on how an annotation is interpreted.
+type Parser struct {
+	mode Mode
+}
+
+func NewParser(mode Mode) *Parser {
+	return &Parser{mode: mode}
+}
+
+func (p *Parser) Mode() Mode {
+	return p.mode
+}
+
+// ParseResourceSpec parses the ResourceSpec from the annotation AnnotationResourceSpec.
+func (p *Parser) ParseResourceSpec(annotations map[string]string) (*extension.ResourceSpec, error) {
+	if err := p.validateSchema(annotations, extension.AnnotationResourceSpec); err != nil {
+		return nil, err
+	}
+	return extension.GetResourceSpec(annotations)
+}
+
+// ParseResourceStatus parses the ResourceStatus from the annotation AnnotationResourceStatus.
+func (p *Parser) ParseResourceStatus(annotations map[string]string) (*extension.ResourceStatus, error) {
+	if err := p.validateSchema(annotations, extension.AnnotationResourceStatus); err != nil {
+		return nil, err
+	}
+	status, err := extension.GetResourceStatus(annotations)
+	if err != nil || p.mode != ModeStrict {
+		return status, err
+	}
+
+	if _, err = cpuset.Parse(status.CPUSet); err != nil {
+		return nil, fmt.Errorf("invalid cpuset %s in annotation %s, err: %w", status.CPUSet, extension.AnnotationResourceStatus, err)
+	}
+	if _, err = cpuset.Parse(status.PreferredCPUSet); err != nil {
+		return nil, fmt.Errorf("invalid preferredCPUSet %s in annotation %s, err: %w", status.PreferredCPUSet, extension.AnnotationResourceStatus, err)
+	}
+	containers := map[string]bool{}
+	for _, containerCPUSet := range status.ContainerCPUSets {
+		if containers[containerCPUSet.Name] {
+			return nil, fmt.Errorf("duplicate container %s in annotation %s", containerCPUSet.Name, extension.AnnotationResourceStatus)
+		}
+		containers[containerCPUSet.Name] = true
+		if _, err = cpuset.Parse(containerCPUSet.CPUSet); err != nil {
+			return nil, fmt.Errorf("invalid cpuset %s of container %s in annotation %s, err: %w", containerCPUSet.CPUSet, containerCPUSet.Name, extension.AnnotationResourceStatus, err)
+		}
+	}
+	numaNodes := map[int32]bool{}
+	for _, numaNodeResource := range status.NUMANodeResources {
+		if numaNodes[numaNodeResource.Node] {
+			return nil, fmt.Errorf("duplicate NUMA node %d in annotation %s", numaNodeResource.Node, extension.AnnotationResourceStatus)
+		}
+		numaNodes[numaNodeResource.Node] = true
+		if err = validateResourceList(numaNodeResource.Resources); err != nil {
+			return nil, fmt.Errorf("invalid resources of NUMA node %d in annotation %s, err: %w", numaNodeResource.Node, extension.AnnotationResourceStatus, err)
+		}
+	}
+	return status, nil
+}
+
+// ParseNUMATopologySpec parses the NUMATopologySpec from the annotation AnnotationNUMATopologySpec.
+func (p *Parser) ParseNUMATopologySpec(annotations map[string]string) (*extension.NUMATopologySpec, error) {
+	if err := p.validateSchema(annotations, extension.AnnotationNUMATopologySpec); err != nil {
+		return nil, err
+	}
+	return extension.GetNUMATopologySpec(annotations)
+}
+
+// ParseDeviceAllocations parses the DeviceAllocations from the annotation AnnotationDeviceAllocated.
+func (p *Parser) ParseDeviceAllocations(annotations map[string]string) (extension.DeviceAllocations, error) {
+	if err := p.validateSchema(annotations, extension.AnnotationDeviceAllocated); err != nil {
+		return nil, err
+	}
+	allocations, err := extension.GetDeviceAllocations(annotations)
+	if err != nil || p.mode != ModeStrict {
+		return allocations, err
+	}
+
+	for deviceType, deviceAllocations := range allocations {
+		if !knownDeviceTypes[deviceType] {
+			return nil, fmt.Errorf("unknown device type %s in annotation %s", deviceType, extension.AnnotationDeviceAllocated)
+		}
+		minors := map[int32]bool{}
+		for _, allocation := range deviceAllocations {
+			if allocation == nil {
+				return nil, fmt.Errorf("nil %s allocation in annotation %s", deviceType, extension.AnnotationDeviceAllocated)
+			}
+			if minors[allocation.Minor] {
+				return nil, fmt.Errorf("duplicate %s minor %d in annotation %s", deviceType, allocation.Minor, extension.AnnotationDeviceAllocated)
+			}
+			minors[allocation.Minor] = true
+			if err = validateResourceList(allocation.Resources); err != nil {
+				return nil, fmt.Errorf("invalid resources of %s minor %d in annotation %s, err: %w", deviceType, allocation.Minor, extension.AnnotationDeviceAllocated, err)
+			}
+		}
+	}
+	return allocations, nil
+}
+
+// ParseNodeResourceAmplificationRatios parses the resource amplification ratios from the annotation
+// AnnotationNodeResourceAmplificationRatio.
+func (p *Parser) ParseNodeResourceAmplificationRatios(annotations map[string]string) (map[corev1.ResourceName]extension.Ratio, error) {
+	if err := p.validateSchema(annotations, extension.AnnotationNodeResourceAmplificationRatio); err != nil {
+		return nil, err
+	}
+	return extension.GetNodeResourceAmplificationRatios(annotations)
+}
+
+// Validate strictly validates all the known extension annotations of an object.
+func Validate(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for key, parse := range strictParseFns {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		if err := parse(annotations); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+var strictParseFns = map[string]func(annotations map[string]string) error{
+	extension.AnnotationResourceSpec: func(annotations map[string]string) error {
+		_, err := StrictParser.ParseResourceSpec(annotations)
+		return err
+	},
+	extension.AnnotationResourceStatus: func(annotations map[string]string) error {
+		_, err := StrictParser.ParseResourceStatus(annotations)
+		return err
+	},
+	extension.AnnotationNUMATopologySpec: func(annotations map[string]string) error {
+		_, err := StrictParser.ParseNUMATopologySpec(annotations)
+		return err
+	},
+	extension.AnnotationDeviceAllocated: func(annotations map[string]string) error {
+		_, err := StrictParser.ParseDeviceAllocations(annotations)
+		return err
+	},
+	extension.AnnotationNodeResourceAmplificationRatio: func(annotations map[string]string) error {
+		_, err := StrictParser.ParseNodeResourceAmplificationRatios(annotations)
+		return err
+	},
+}
+
+func (p *Parser) validateSchema(annotations map[string]string, key string) error {
+	if p.mode != ModeStrict {
+		return nil
+	}
+	data, ok := annotations[key]
+	if !ok {
+		return nil
+	}
+	schema := GetSchema(key)
+	if schema == nil {
+		return fmt.Errorf("no schema for annotation %s", key)
+	}
+
+	raw := []byte(data)
+	if key == extension.AnnotationResourceStatus {
+		// the ResourceStatus may be compressed or in the compact V2 schema
+		var isV2 bool
+		var err error
+		raw, isV2, err = extension.DecodeResourceStatusData(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode annotation %s, err: %w", key, err)
+		}
+		if isV2 {
+			schema = ResourceStatusV2Schema
+		}
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return fmt.Errorf("failed to unmarshal annotation %s, err: %w", key, err)
+	}
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(obj)
+	if result.HasErrors() {
+		return fmt.Errorf("invalid annotation %s, err: %w", key, result.AsError())
+	}
+	return nil
+}
+
+func validateResourceList(resources corev1.ResourceList) error {
+	for name, quantity := range resources {
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("negative quantity %s of resource %s", quantity.String(), name)
+		}
+	}
+	return nil
+}
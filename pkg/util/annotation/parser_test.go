@@ -0,0 +1,414 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestParseResourceSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		want          *extension.ResourceSpec
+		wantErr       bool
+		wantStrictErr bool
+	}{
+		{
+			name: "no annotation",
+			want: &extension.ResourceSpec{},
+		},
+		{
+			name: "valid resource spec",
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy":"FullPCPUs","preferredCPUExclusivePolicy":"PCPULevel"}`,
+			},
+			want: &extension.ResourceSpec{
+				PreferredCPUBindPolicy:      extension.CPUBindPolicyFullPCPUs,
+				PreferredCPUExclusivePolicy: extension.CPUExclusivePolicyPCPULevel,
+			},
+		},
+		{
+			name: "invalid json",
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy":`,
+			},
+			wantErr:       true,
+			wantStrictErr: true,
+		},
+		{
+			name: "unknown bind policy is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy":"Unknown"}`,
+			},
+			want: &extension.ResourceSpec{
+				PreferredCPUBindPolicy: "Unknown",
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "valid container resource specs",
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec: `{"containers":[{"name":"main","requiredCPUBindPolicy":"FullPCPUs"},{"name":"sidecar"}]}`,
+			},
+			want: &extension.ResourceSpec{
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+					{Name: "sidecar"},
+				},
+			},
+		},
+		{
+			name: "constrained burst container is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec: `{"containers":[{"name":"main","requiredCPUBindPolicy":"ConstrainedBurst"}]}`,
+			},
+			want: &extension.ResourceSpec{
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyConstrainedBurst},
+				},
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "unknown field is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy":"FullPCPUs","unknownField":"x"}`,
+			},
+			want: &extension.ResourceSpec{
+				PreferredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+			},
+			wantStrictErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := LenientParser.ParseResourceSpec(tt.annotations)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+
+			got, gotErr = StrictParser.ParseResourceSpec(tt.annotations)
+			assert.Equal(t, tt.wantStrictErr, gotErr != nil, gotErr)
+			if !tt.wantStrictErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseResourceStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		want          *extension.ResourceStatus
+		wantErr       bool
+		wantStrictErr bool
+	}{
+		{
+			name: "no annotation",
+			want: &extension.ResourceStatus{},
+		},
+		{
+			name: "valid resource status",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"cpuset":"0-3","numaNodeResources":[{"node":0,"resources":{"cpu":"4","memory":"8Gi"}}]}`,
+			},
+			want: &extension.ResourceStatus{
+				CPUSet: "0-3",
+				NUMANodeResources: []extension.NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("4"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "valid container cpusets",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"cpuset":"0-3","containerCPUSets":[{"name":"main","cpuset":"0-3"}]}`,
+			},
+			want: &extension.ResourceStatus{
+				CPUSet: "0-3",
+				ContainerCPUSets: []extension.ContainerCPUSet{
+					{Name: "main", CPUSet: "0-3"},
+				},
+			},
+		},
+		{
+			name: "duplicate container is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"cpuset":"0-3","containerCPUSets":[{"name":"main","cpuset":"0-1"},{"name":"main","cpuset":"2-3"}]}`,
+			},
+			want: &extension.ResourceStatus{
+				CPUSet: "0-3",
+				ContainerCPUSets: []extension.ContainerCPUSet{
+					{Name: "main", CPUSet: "0-1"},
+					{Name: "main", CPUSet: "2-3"},
+				},
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "invalid cpuset is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"cpuset":"0-x"}`,
+			},
+			want: &extension.ResourceStatus{
+				CPUSet: "0-x",
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "negative NUMA node is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"numaNodeResources":[{"node":-1}]}`,
+			},
+			want: &extension.ResourceStatus{
+				NUMANodeResources: []extension.NUMANodeResource{
+					{
+						Node: -1,
+					},
+				},
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "duplicate NUMA node is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"numaNodeResources":[{"node":0},{"node":0}]}`,
+			},
+			want: &extension.ResourceStatus{
+				NUMANodeResources: []extension.NUMANodeResource{
+					{
+						Node: 0,
+					},
+					{
+						Node: 0,
+					},
+				},
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "valid resource status in v2 schema",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"v":2,"c":"0-3","n":[{"i":0,"r":{"cpu":"4"}}]}`,
+			},
+			want: &extension.ResourceStatus{
+				CPUSet: "0-3",
+				NUMANodeResources: []extension.NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "valid compressed resource status",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: func() string {
+					data, _ := extension.MarshalResourceStatus(&extension.ResourceStatus{CPUSet: "0-3"}, extension.ResourceStatusFormatV2Compressed)
+					return data
+				}(),
+			},
+			want: &extension.ResourceStatus{
+				CPUSet: "0-3",
+			},
+		},
+		{
+			name: "negative NUMA node in v2 schema is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"v":2,"n":[{"i":-1}]}`,
+			},
+			want: &extension.ResourceStatus{
+				NUMANodeResources: []extension.NUMANodeResource{
+					{
+						Node: -1,
+					},
+				},
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "unsupported version",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"v":3,"c":"0-3"}`,
+			},
+			wantErr:       true,
+			wantStrictErr: true,
+		},
+		{
+			name: "invalid quantity",
+			annotations: map[string]string{
+				extension.AnnotationResourceStatus: `{"numaNodeResources":[{"node":0,"resources":{"cpu":"abc"}}]}`,
+			},
+			wantErr:       true,
+			wantStrictErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := LenientParser.ParseResourceStatus(tt.annotations)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+
+			got, gotErr = StrictParser.ParseResourceStatus(tt.annotations)
+			assert.Equal(t, tt.wantStrictErr, gotErr != nil, gotErr)
+			if !tt.wantStrictErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseNUMATopologySpec(t *testing.T) {
+	got, err := StrictParser.ParseNUMATopologySpec(map[string]string{
+		extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"SingleNUMANode"}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &extension.NUMATopologySpec{NUMATopologyPolicy: extension.NUMATopologyPolicySingleNUMANode}, got)
+
+	annotations := map[string]string{
+		extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"Unknown"}`,
+	}
+	_, err = StrictParser.ParseNUMATopologySpec(annotations)
+	assert.Error(t, err)
+	got, err = LenientParser.ParseNUMATopologySpec(annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, &extension.NUMATopologySpec{NUMATopologyPolicy: "Unknown"}, got)
+}
+
+func TestParseDeviceAllocations(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantErr       bool
+		wantStrictErr bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "valid device allocations",
+			annotations: map[string]string{
+				extension.AnnotationDeviceAllocated: `{"gpu":[{"minor":0,"resources":{"koordinator.sh/gpu-core":"100","koordinator.sh/gpu-memory":"16Gi"}}]}`,
+			},
+		},
+		{
+			name: "unknown device type is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationDeviceAllocated: `{"npu":[{"minor":0}]}`,
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "duplicate minor is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationDeviceAllocated: `{"gpu":[{"minor":0},{"minor":0}]}`,
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "missing minor is only rejected in strict mode",
+			annotations: map[string]string{
+				extension.AnnotationDeviceAllocated: `{"gpu":[{"resources":{"koordinator.sh/gpu-core":"100"}}]}`,
+			},
+			wantStrictErr: true,
+		},
+		{
+			name: "invalid json",
+			annotations: map[string]string{
+				extension.AnnotationDeviceAllocated: `[]`,
+			},
+			wantErr:       true,
+			wantStrictErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantErr := extension.GetDeviceAllocations(tt.annotations)
+			got, gotErr := LenientParser.ParseDeviceAllocations(tt.annotations)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, wantErr, gotErr)
+			assert.Equal(t, want, got)
+
+			_, gotErr = StrictParser.ParseDeviceAllocations(tt.annotations)
+			assert.Equal(t, tt.wantStrictErr, gotErr != nil, gotErr)
+		})
+	}
+}
+
+func TestParseNodeResourceAmplificationRatios(t *testing.T) {
+	got, err := StrictParser.ParseNodeResourceAmplificationRatios(map[string]string{
+		extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.5}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[corev1.ResourceName]extension.Ratio{corev1.ResourceCPU: 1.5}, got)
+
+	annotations := map[string]string{
+		extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":0.5}`,
+	}
+	_, err = StrictParser.ParseNodeResourceAmplificationRatios(annotations)
+	assert.Error(t, err)
+	got, err = LenientParser.ParseNodeResourceAmplificationRatios(annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, map[corev1.ResourceName]extension.Ratio{corev1.ResourceCPU: 0.5}, got)
+}
+
+func TestValidate(t *testing.T) {
+	fldPath := field.NewPath("metadata", "annotations")
+	assert.Empty(t, Validate(nil, fldPath))
+	assert.Empty(t, Validate(map[string]string{
+		"unknown-annotation":               "unknown",
+		extension.AnnotationResourceSpec:   `{"preferredCPUBindPolicy":"SpreadByPCPUs"}`,
+		extension.AnnotationResourceStatus: `{"cpuset":"0-3"}`,
+	}, fldPath))
+
+	errs := Validate(map[string]string{
+		extension.AnnotationResourceSpec:     `{"preferredCPUBindPolicy":"Unknown"}`,
+		extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"SingleNUMANode"}`,
+	}, fldPath)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, fldPath.Key(extension.AnnotationResourceSpec).String(), errs[0].Field)
+}
+
+func TestGetSchema(t *testing.T) {
+	for _, key := range KnownAnnotations() {
+		assert.NotNil(t, GetSchema(key), key)
+		_, ok := strictParseFns[key]
+		assert.True(t, ok, key)
+	}
+	assert.Nil(t, GetSchema("unknown-annotation"))
+}
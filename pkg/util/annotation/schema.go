@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotation
+
+import (
+	"fmt"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+// The OpenAPI schemas of the extension annotations. The strict parser validates the annotation values against them,
+// so the unknown fields, the unknown enum values and the type mismatches are rejected instead of being ignored.
+var (
+	ResourceSpecSchema = objectSchema(map[string]spec.Schema{
+		"requiredCPUBindPolicy": *enumSchema(extension.CPUBindPolicyDefault, extension.CPUBindPolicyFullPCPUs,
+			extension.CPUBindPolicySpreadByPCPUs, extension.CPUBindPolicyConstrainedBurst),
+		"preferredCPUBindPolicy": *enumSchema(extension.CPUBindPolicyDefault, extension.CPUBindPolicyFullPCPUs,
+			extension.CPUBindPolicySpreadByPCPUs, extension.CPUBindPolicyConstrainedBurst),
+		"preferredCPUExclusivePolicy": *enumSchema(extension.CPUExclusivePolicyNone, extension.CPUExclusivePolicyPCPULevel,
+			extension.CPUExclusivePolicyNUMANodeLevel),
+		"preferredNUMAAllocateStrategy": *enumSchema(extension.NUMAMostAllocated, extension.NUMALeastAllocated,
+			extension.NUMADistributeEvenly),
+		"irqSteeringPolicy": *enumSchema(extension.IRQSteeringPolicyNone, extension.IRQSteeringPolicyIsolated),
+		"cpuBindMode":       *enumSchema(extension.CPUBindModeHard, extension.CPUBindModeSoft, extension.CPUBindModeBestEffort),
+		"memoryPolicy":      *enumSchema(extension.MemoryPolicyDefault, extension.MemoryPolicyInterleave),
+		"containers": *spec.ArrayProperty(objectSchema(map[string]spec.Schema{
+			"name": *spec.StringProperty().WithMinLength(1),
+			"requiredCPUBindPolicy": *enumSchema(extension.CPUBindPolicyDefault, extension.CPUBindPolicyFullPCPUs,
+				extension.CPUBindPolicySpreadByPCPUs),
+		}).WithRequired("name")),
+	}).WithDescription("ResourceSpec describes extra attributes of the resource requirements.")
+
+	ResourceStatusSchema = objectSchema(map[string]spec.Schema{
+		"cpuset":          *spec.StringProperty(),
+		"preferredCPUSet": *spec.StringProperty(),
+		"numaNodeResources": *spec.ArrayProperty(objectSchema(map[string]spec.Schema{
+			"node":      *spec.Int32Property().WithMinimum(0, false),
+			"resources": *resourceListSchema(),
+		}).WithRequired("node")),
+		"cpuBindDegraded": *spec.BooleanProperty(),
+		"containerCPUSets": *spec.ArrayProperty(objectSchema(map[string]spec.Schema{
+			"name":   *spec.StringProperty().WithMinLength(1),
+			"cpuset": *spec.StringProperty(),
+		}).WithRequired("name")),
+	}).WithDescription("ResourceStatus describes resource allocation result, such as how to bind CPU.")
+
+	ResourceStatusV2Schema = objectSchema(map[string]spec.Schema{
+		"v": *spec.Int64Property().WithMinimum(extension.ResourceStatusVersionV2, false).
+			WithMaximum(extension.ResourceStatusVersionV2, false),
+		"c": *spec.StringProperty(),
+		"p": *spec.StringProperty(),
+		"n": *spec.ArrayProperty(objectSchema(map[string]spec.Schema{
+			"i": *spec.Int32Property().WithMinimum(0, false),
+			"r": *resourceListSchema(),
+		}).WithRequired("i")),
+		"d": *spec.BooleanProperty(),
+		"cc": *spec.ArrayProperty(objectSchema(map[string]spec.Schema{
+			"n": *spec.StringProperty().WithMinLength(1),
+			"c": *spec.StringProperty(),
+		}).WithRequired("n")),
+	}).WithRequired("v").WithDescription("ResourceStatus in the compact V2 schema.")
+
+	NUMATopologySpecSchema = objectSchema(map[string]spec.Schema{
+		"numaTopologyPolicy": *enumSchema(extension.NUMATopologyPolicyNone, extension.NUMATopologyPolicyBestEffort,
+			extension.NUMATopologyPolicyRestricted, extension.NUMATopologyPolicySingleNUMANode),
+	}).WithDescription("NUMATopologySpec describes the NUMA topology requirements of the Pod.")
+
+	DeviceAllocationsSchema = spec.MapProperty(spec.ArrayProperty(objectSchema(map[string]spec.Schema{
+		"minor":     *spec.Int32Property().WithMinimum(0, false),
+		"resources": *resourceListSchema(),
+		"extension": {},
+	}).WithRequired("minor"))).WithDescription("DeviceAllocations describes the devices allocated to the Pod by device types.")
+
+	NodeResourceAmplificationRatioSchema = spec.MapProperty(spec.Float64Property().WithMinimum(1, false)).
+						WithDescription("The resource amplification ratios of the node, which should be no less than 1.")
+)
+
+// knownDeviceTypes are the valid keys of the DeviceAllocations.
+var knownDeviceTypes = map[schedulingv1alpha1.DeviceType]bool{
+	schedulingv1alpha1.GPU:  true,
+	schedulingv1alpha1.FPGA: true,
+	schedulingv1alpha1.RDMA: true,
+}
+
+var schemas = map[string]*spec.Schema{
+	extension.AnnotationResourceSpec:                   ResourceSpecSchema,
+	extension.AnnotationResourceStatus:                 ResourceStatusSchema,
+	extension.AnnotationNUMATopologySpec:               NUMATopologySpecSchema,
+	extension.AnnotationDeviceAllocated:                DeviceAllocationsSchema,
+	extension.AnnotationNodeResourceAmplificationRatio: NodeResourceAmplificationRatioSchema,
+}
+
+// GetSchema returns the OpenAPI schema of the annotation. It returns nil if the annotation is unknown.
+func GetSchema(key string) *spec.Schema {
+	return schemas[key]
+}
+
+// KnownAnnotations returns the annotation keys which have a schema.
+func KnownAnnotations() []string {
+	keys := make([]string, 0, len(schemas))
+	for key := range schemas {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func objectSchema(properties map[string]spec.Schema) *spec.Schema {
+	return &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:                 []string{"object"},
+			Properties:           properties,
+			AdditionalProperties: &spec.SchemaOrBool{Allows: false},
+		},
+	}
+}
+
+func enumSchema(values ...interface{}) *spec.Schema {
+	// the empty value means unset
+	enums := []interface{}{""}
+	for _, v := range values {
+		// the enum values are compared with the unmarshalled strings, so convert the string-based types
+		enums = append(enums, fmt.Sprint(v))
+	}
+	return spec.StringProperty().WithEnum(enums...)
+}
+
+func resourceListSchema() *spec.Schema {
+	// the quantity is marshalled as a string, and the number is also accepted by the unmarshalling
+	return spec.MapProperty(&spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"string", "number"},
+		},
+	})
+}
@@ -29,6 +29,11 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 )
 
+const (
+	// IndexPodByNodeName indexes the pods by the node they are bound to.
+	IndexPodByNodeName = "spec.nodeName"
+)
+
 var registerOnce sync.Once
 
 type fieldIndexDescriptor struct {
@@ -43,7 +48,7 @@ var indexDescriptors = []fieldIndexDescriptor{
 	{
 		description: "index pod by spec.NodeName",
 		obj:         &corev1.Pod{},
-		field:       "spec.nodeName",
+		field:       IndexPodByNodeName,
 		indexerFunc: func(obj client.Object) []string {
 			pod, ok := obj.(*corev1.Pod)
 			if !ok {
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry keeps the names of the koordinator metrics which the recording and the alerting rules are built on.
+// The components define these metrics with the names here, so the generated rules stay in sync with the code.
+package registry
+
+const (
+	KoordletSubsystem  = "koordlet"
+	SchedulerSubsystem = "scheduler"
+)
+
+type MetricType string
+
+const (
+	Counter   MetricType = "counter"
+	Gauge     MetricType = "gauge"
+	Histogram MetricType = "histogram"
+)
+
+// Definition describes a metric exposed by a koordinator component.
+type Definition struct {
+	Subsystem string
+	Name      string
+	Type      MetricType
+	Labels    []string
+}
+
+// FQName returns the fully-qualified name of the metric as exposed to Prometheus.
+func (d *Definition) FQName() string {
+	return d.Subsystem + "_" + d.Name
+}
+
+var (
+	// BESuppressCPUCores is the number of cores which the BE pods are suppressed to, by the suppress policy.
+	BESuppressCPUCores = &Definition{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_suppress_cpu_cores",
+		Type:      Gauge,
+		Labels:    []string{"node", "type"},
+	}
+	// BESuppressLSUsedCPUCores is the number of cores used by the LS pods considered by the BE suppression.
+	BESuppressLSUsedCPUCores = &Definition{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_suppress_ls_used_cpu_cores",
+		Type:      Gauge,
+		Labels:    []string{"node"},
+	}
+	// BEMemoryThrottleLevel is the step of the memory.high throttling applied on the BE pods.
+	BEMemoryThrottleLevel = &Definition{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_memory_throttle_level",
+		Type:      Gauge,
+		Labels:    []string{"node"},
+	}
+
+	// CPUBindFailures is the number of failures to allocate the NUMA resources or the CPUs for the pods.
+	CPUBindFailures = &Definition{
+		Subsystem: SchedulerSubsystem,
+		Name:      "cpu_bind_failures_total",
+		Type:      Counter,
+		Labels:    []string{"node", "reason"},
+	}
+	// NUMANodeCPURefCountRatio is the ratio of the CPU references to the ref-count capacity of the NUMA Node.
+	NUMANodeCPURefCountRatio = &Definition{
+		Subsystem: SchedulerSubsystem,
+		Name:      "numa_node_cpu_ref_count_ratio",
+		Type:      Gauge,
+		Labels:    []string{"node", "numa_node", "qos"},
+	}
+	// NUMANodeSaturatedCPUs is the number of CPUs reaching the MaxRefCount on the NUMA Node.
+	NUMANodeSaturatedCPUs = &Definition{
+		Subsystem: SchedulerSubsystem,
+		Name:      "numa_node_saturated_cpus",
+		Type:      Gauge,
+		Labels:    []string{"node", "numa_node"},
+	}
+	// ElasticQuotaRejections is the number of pods rejected by the ElasticQuota plugin for the insufficient quota.
+	ElasticQuotaRejections = &Definition{
+		Subsystem: SchedulerSubsystem,
+		Name:      "elastic_quota_rejections_total",
+		Type:      Counter,
+		Labels:    []string{"quota", "reason"},
+	}
+	// NodePoolSchedulingOutcomes is the number of the scheduling attempts in the node pools, by the outcome.
+	NodePoolSchedulingOutcomes = &Definition{
+		Subsystem: SchedulerSubsystem,
+		Name:      "node_pool_scheduling_outcomes_total",
+		Type:      Counter,
+		Labels:    []string{"pool", "outcome"},
+	}
+	// NodePoolPodPendingDuration is the duration from the creation to the binding of the pods in the node pools.
+	NodePoolPodPendingDuration = &Definition{
+		Subsystem: SchedulerSubsystem,
+		Name:      "node_pool_pod_pending_duration_seconds",
+		Type:      Histogram,
+		Labels:    []string{"pool"},
+	}
+)
+
+// Definitions returns all the registered metric definitions.
+func Definitions() []*Definition {
+	return []*Definition{
+		BESuppressCPUCores,
+		BESuppressLSUsedCPUCores,
+		BEMemoryThrottleLevel,
+		CPUBindFailures,
+		NUMANodeCPURefCountRatio,
+		NUMANodeSaturatedCPUs,
+		ElasticQuotaRejections,
+		NodePoolSchedulingOutcomes,
+		NodePoolPodPendingDuration,
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleGroups is the Prometheus rule file.
+type RuleGroups struct {
+	Groups []RuleGroup `json:"groups"`
+}
+
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a recording rule if the Record is set, or an alerting rule if the Alert is set.
+type Rule struct {
+	Record      string            `json:"record,omitempty"`
+	Alert       string            `json:"alert,omitempty"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+const (
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+// RecordName returns the name of the recording rule in the level:metric:operations convention. The "_total" suffix
+// of the counters is dropped after the rate operation.
+func RecordName(d *Definition, labels []string, operation string) string {
+	metric := d.FQName()
+	if d.Type == Counter {
+		metric = strings.TrimSuffix(metric, "_total")
+	}
+	return fmt.Sprintf("%s:%s:%s", strings.Join(labels, "_"), metric, operation)
+}
+
+// GenerateRuleGroups generates the recording and the alerting rules of the koordinator metrics.
+func GenerateRuleGroups() *RuleGroups {
+	return &RuleGroups{
+		Groups: []RuleGroup{
+			numaRuleGroup(),
+			suppressionRuleGroup(),
+			schedulingRuleGroup(),
+			nodePoolRuleGroup(),
+		},
+	}
+}
+
+func numaRuleGroup() RuleGroup {
+	// the ratios of the QoS classes add up to the ratio of the NUMA Node
+	numaRatio := fmt.Sprintf("sum by (node, numa_node) (%s)", NUMANodeCPURefCountRatio.FQName())
+	imbalance := RecordName(NUMANodeCPURefCountRatio, []string{"node"}, "imbalance")
+	saturated := RecordName(NUMANodeSaturatedCPUs, []string{"node"}, "sum")
+	return RuleGroup{
+		Name: "koordinator.numa.rules",
+		Rules: []Rule{
+			{
+				Record: imbalance,
+				Expr:   fmt.Sprintf("max by (node) (%s) - min by (node) (%s)", numaRatio, numaRatio),
+			},
+			{
+				Record: saturated,
+				Expr:   fmt.Sprintf("sum by (node) (%s)", NUMANodeSaturatedCPUs.FQName()),
+			},
+			{
+				Alert: "KoordNUMANodeImbalanced",
+				Expr:  fmt.Sprintf("%s > 0.5", imbalance),
+				For:   "30m",
+				Labels: map[string]string{
+					"severity": severityWarning,
+				},
+				Annotations: map[string]string{
+					"summary":     "The CPU references are imbalanced across the NUMA Nodes.",
+					"description": "The CPU ref-count ratios of the NUMA Nodes on node {{ $labels.node }} differ by {{ $value | humanizePercentage }}.",
+				},
+			},
+		},
+	}
+}
+
+func suppressionRuleGroup() RuleGroup {
+	suppressChanges := RecordName(BESuppressCPUCores, []string{"node", "type"}, "changes15m")
+	throttleLevel := RecordName(BEMemoryThrottleLevel, []string{"node"}, "max")
+	return RuleGroup{
+		Name: "koordinator.suppression.rules",
+		Rules: []Rule{
+			{
+				Record: suppressChanges,
+				Expr:   fmt.Sprintf("sum by (node, type) (changes(%s[15m]))", BESuppressCPUCores.FQName()),
+			},
+			{
+				Record: RecordName(BESuppressLSUsedCPUCores, []string{"node"}, "max"),
+				Expr:   fmt.Sprintf("max by (node) (%s)", BESuppressLSUsedCPUCores.FQName()),
+			},
+			{
+				Record: throttleLevel,
+				Expr:   fmt.Sprintf("max by (node) (%s)", BEMemoryThrottleLevel.FQName()),
+			},
+			{
+				Alert: "KoordBEMemoryThrottled",
+				Expr:  fmt.Sprintf("%s > 0", throttleLevel),
+				For:   "30m",
+				Labels: map[string]string{
+					"severity": severityInfo,
+				},
+				Annotations: map[string]string{
+					"summary":     "The BE pods are memory throttled for a long time.",
+					"description": "The memory.high of the BE pods on node {{ $labels.node }} is throttled at level {{ $value }}.",
+				},
+			},
+		},
+	}
+}
+
+func schedulingRuleGroup() RuleGroup {
+	bindFailures := RecordName(CPUBindFailures, []string{"reason"}, "rate5m")
+	quotaRejections := RecordName(ElasticQuotaRejections, []string{"quota", "reason"}, "rate5m")
+	return RuleGroup{
+		Name: "koordinator.scheduling.rules",
+		Rules: []Rule{
+			{
+				Record: bindFailures,
+				Expr:   fmt.Sprintf("sum by (reason) (rate(%s[5m]))", CPUBindFailures.FQName()),
+			},
+			{
+				Record: quotaRejections,
+				Expr:   fmt.Sprintf("sum by (quota, reason) (rate(%s[5m]))", ElasticQuotaRejections.FQName()),
+			},
+			{
+				Alert: "KoordCPUBindFailuresHigh",
+				Expr:  fmt.Sprintf("%s > 0.1", bindFailures),
+				For:   "15m",
+				Labels: map[string]string{
+					"severity": severityWarning,
+				},
+				Annotations: map[string]string{
+					"summary":     "The scheduler fails to bind the CPUs for the pods.",
+					"description": "The CPU bind failures for the reason {{ $labels.reason }} are {{ $value | humanize }} per second.",
+				},
+			},
+			{
+				Alert: "KoordElasticQuotaRejectionsHigh",
+				Expr:  fmt.Sprintf("%s > 0.5", quotaRejections),
+				For:   "15m",
+				Labels: map[string]string{
+					"severity": severityInfo,
+				},
+				Annotations: map[string]string{
+					"summary":     "The pods are rejected by the elastic quota.",
+					"description": "The pods of quota {{ $labels.quota }} are rejected for the reason {{ $labels.reason }} at {{ $value | humanize }} per second.",
+				},
+			},
+		},
+	}
+}
+
+func nodePoolRuleGroup() RuleGroup {
+	outcomes := RecordName(NodePoolSchedulingOutcomes, []string{"pool", "outcome"}, "rate5m")
+	return RuleGroup{
+		Name: "koordinator.node-pool.rules",
+		Rules: []Rule{
+			{
+				Record: outcomes,
+				Expr:   fmt.Sprintf("sum by (pool, outcome) (rate(%s[5m]))", NodePoolSchedulingOutcomes.FQName()),
+			},
+			{
+				// the share of each failure outcome in the failed attempts tells the constraint dominating the pool
+				Record: RecordName(NodePoolSchedulingOutcomes, []string{"pool", "outcome"}, "failure_ratio5m"),
+				Expr:   fmt.Sprintf(`%s{outcome!="success"} / ignoring (outcome) group_left sum by (pool) (%s{outcome!="success"})`, outcomes, outcomes),
+			},
+			{
+				Record: RecordName(NodePoolPodPendingDuration, []string{"pool"}, "p99"),
+				Expr:   fmt.Sprintf("histogram_quantile(0.99, sum by (pool, le) (rate(%s_bucket[5m])))", NodePoolPodPendingDuration.FQName()),
+			},
+		},
+	}
+}
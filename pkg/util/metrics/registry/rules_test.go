@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordName(t *testing.T) {
+	assert.Equal(t, "reason:scheduler_cpu_bind_failures:rate5m", RecordName(CPUBindFailures, []string{"reason"}, "rate5m"))
+	assert.Equal(t, "node:koordlet_be_memory_throttle_level:max", RecordName(BEMemoryThrottleLevel, []string{"node"}, "max"))
+}
+
+func TestGenerateRuleGroups(t *testing.T) {
+	ruleGroups := GenerateRuleGroups()
+	assert.NotEmpty(t, ruleGroups.Groups)
+
+	names := map[string]bool{}
+	var exprs []string
+	for _, group := range ruleGroups.Groups {
+		assert.NotEmpty(t, group.Rules, group.Name)
+		for _, rule := range group.Rules {
+			name := rule.Record + rule.Alert
+			assert.True(t, (rule.Record == "") != (rule.Alert == ""), "rule %s must be either a recording or an alerting rule", name)
+			assert.False(t, names[name], "duplicate rule %s", name)
+			names[name] = true
+
+			_, err := parser.ParseExpr(rule.Expr)
+			assert.NoError(t, err, "invalid expr of rule %s", name)
+			if rule.Alert != "" {
+				assert.NotEmpty(t, rule.Labels["severity"], name)
+				assert.NotEmpty(t, rule.Annotations["summary"], name)
+			}
+			exprs = append(exprs, rule.Expr)
+		}
+	}
+
+	// every registered metric should be covered by the rules
+	allExprs := strings.Join(exprs, "\n")
+	for _, d := range Definitions() {
+		assert.Contains(t, allExprs, d.FQName())
+	}
+}
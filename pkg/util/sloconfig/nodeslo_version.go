@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sloconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	sigsjson "sigs.k8s.io/json"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	// NodeSLOSchemaVersionV1 is the schema of the NodeSLO spec before the schema version is introduced.
+	NodeSLOSchemaVersionV1 = 1
+	// NodeSLOSchemaVersionV2 restricts the cpuSuppressPolicy of the resourceUsedThresholdWithBE to the known policies,
+	// while the unknown policy of V1 falls back to the cpuset policy.
+	NodeSLOSchemaVersionV2 = 2
+
+	// CurrentNodeSLOSchemaVersion is the schema version of the NodeSLO spec written and understood by this version.
+	// Bump it and register a conversion from the previous version when a strategy field is renamed or restructured.
+	CurrentNodeSLOSchemaVersion = NodeSLOSchemaVersionV2
+)
+
+// NodeSLOSpecConversionFunc converts the unstructured NodeSLO spec of a schema version to the next version in place.
+// It returns the warnings of the values changed by the conversion.
+type NodeSLOSpecConversionFunc func(spec map[string]interface{}) ([]string, error)
+
+// nodeSLOSpecConversions are the conversions indexed by the schema version they convert from.
+var nodeSLOSpecConversions = map[int]NodeSLOSpecConversionFunc{
+	NodeSLOSchemaVersionV1: convertNodeSLOSpecV1ToV2,
+}
+
+// GetNodeSLOSchemaVersion returns the schema version of the NodeSLO spec. The NodeSLO without the annotation is
+// regarded as the first version.
+func GetNodeSLOSchemaVersion(nodeSLO *slov1alpha1.NodeSLO) (int, error) {
+	if nodeSLO == nil || nodeSLO.Annotations == nil {
+		return NodeSLOSchemaVersionV1, nil
+	}
+	versionStr, ok := nodeSLO.Annotations[slov1alpha1.AnnotationNodeSLOSchemaVersion]
+	if !ok {
+		return NodeSLOSchemaVersionV1, nil
+	}
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version < NodeSLOSchemaVersionV1 {
+		return 0, fmt.Errorf("invalid NodeSLO schema version %q", versionStr)
+	}
+	return version, nil
+}
+
+// SetNodeSLOSchemaVersion marks the NodeSLO spec with the current schema version. It returns whether the annotation
+// is changed.
+func SetNodeSLOSchemaVersion(nodeSLO *slov1alpha1.NodeSLO) bool {
+	versionStr := strconv.Itoa(CurrentNodeSLOSchemaVersion)
+	if nodeSLO.Annotations[slov1alpha1.AnnotationNodeSLOSchemaVersion] == versionStr {
+		return false
+	}
+	if nodeSLO.Annotations == nil {
+		nodeSLO.Annotations = map[string]string{}
+	}
+	nodeSLO.Annotations[slov1alpha1.AnnotationNodeSLOSchemaVersion] = versionStr
+	return true
+}
+
+// ConvertNodeSLOSpec decodes the raw NodeSLO spec written in the given schema version into the current version.
+// The spec of an older version is converted by the registered conversions one version by one. The spec of a newer
+// version, e.g. written by the upgraded slo-controller during the rolling upgrade, is decoded leniently since the
+// fields added later are unknown to this version. The unknown fields are returned as the warnings instead of the
+// errors, and the missing fields are defaulted when merging with the default NodeSLO spec.
+func ConvertNodeSLOSpec(rawSpec []byte, version int) (*slov1alpha1.NodeSLOSpec, []string, error) {
+	var warnings []string
+	if version > CurrentNodeSLOSchemaVersion {
+		warnings = append(warnings, fmt.Sprintf("schema version %d is newer than the supported version %d",
+			version, CurrentNodeSLOSchemaVersion))
+	} else if version < CurrentNodeSLOSchemaVersion {
+		converted, conversionWarnings, err := convertNodeSLOSpec(rawSpec, version)
+		warnings = append(warnings, conversionWarnings...)
+		if err != nil {
+			return nil, warnings, err
+		}
+		rawSpec = converted
+	}
+
+	spec := &slov1alpha1.NodeSLOSpec{}
+	strictErrs, err := sigsjson.UnmarshalStrict(rawSpec, spec)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("failed to decode NodeSLO spec, err: %w", err)
+	}
+	for _, strictErr := range strictErrs {
+		warnings = append(warnings, strictErr.Error())
+	}
+	return spec, warnings, nil
+}
+
+func convertNodeSLOSpec(rawSpec []byte, version int) ([]byte, []string, error) {
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(rawSpec, &spec); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode NodeSLO spec of schema version %d, err: %w", version, err)
+	}
+	var warnings []string
+	for v := version; v < CurrentNodeSLOSchemaVersion; v++ {
+		fn, ok := nodeSLOSpecConversions[v]
+		if !ok {
+			continue
+		}
+		conversionWarnings, err := fn(spec)
+		warnings = append(warnings, conversionWarnings...)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("failed to convert NodeSLO spec from schema version %d, err: %w", v, err)
+		}
+	}
+	converted, err := json.Marshal(spec)
+	return converted, warnings, err
+}
+
+// convertNodeSLOSpecV1ToV2 replaces the unknown cpuSuppressPolicy with the cpuset policy which V1 falls back to.
+func convertNodeSLOSpecV1ToV2(spec map[string]interface{}) ([]string, error) {
+	threshold, ok := spec["resourceUsedThresholdWithBE"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	policy, ok := threshold["cpuSuppressPolicy"]
+	if !ok {
+		return nil, nil
+	}
+	switch policy {
+	case string(slov1alpha1.CPUSetPolicy), string(slov1alpha1.CPUCfsQuotaPolicy):
+		return nil, nil
+	}
+	threshold["cpuSuppressPolicy"] = string(slov1alpha1.CPUSetPolicy)
+	return []string{fmt.Sprintf("unknown cpuSuppressPolicy %v is converted to %s", policy, slov1alpha1.CPUSetPolicy)}, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sloconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestGetNodeSLOSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     *slov1alpha1.NodeSLO
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "no annotation",
+			arg:  &slov1alpha1.NodeSLO{},
+			want: NodeSLOSchemaVersionV1,
+		},
+		{
+			name: "valid version",
+			arg: &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{slov1alpha1.AnnotationNodeSLOSchemaVersion: "2"},
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "invalid version",
+			arg: &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{slov1alpha1.AnnotationNodeSLOSchemaVersion: "v2"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version too small",
+			arg: &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{slov1alpha1.AnnotationNodeSLOSchemaVersion: "0"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNodeSLOSchemaVersion(tt.arg)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetNodeSLOSchemaVersion(t *testing.T) {
+	nodeSLO := &slov1alpha1.NodeSLO{}
+	assert.True(t, SetNodeSLOSchemaVersion(nodeSLO))
+	got, err := GetNodeSLOSchemaVersion(nodeSLO)
+	assert.NoError(t, err)
+	assert.Equal(t, CurrentNodeSLOSchemaVersion, got)
+	assert.False(t, SetNodeSLOSchemaVersion(nodeSLO))
+}
+
+func TestConvertNodeSLOSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		rawSpec      string
+		version      int
+		want         *slov1alpha1.NodeSLOSpec
+		wantWarnings int
+		wantErr      bool
+	}{
+		{
+			name:    "current version",
+			rawSpec: `{"cpuBurstStrategy":{"cpuBurstPercent":100}}`,
+			version: CurrentNodeSLOSchemaVersion,
+			want: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{CPUBurstPercent: pointer.Int64(100)},
+				},
+			},
+		},
+		{
+			name:    "unknown fields of current version",
+			rawSpec: `{"cpuBurstStrategy":{"cpuBurstPercent":100,"unknownField":1}}`,
+			version: CurrentNodeSLOSchemaVersion,
+			want: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{CPUBurstPercent: pointer.Int64(100)},
+				},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:    "newer version with new strategy",
+			rawSpec: `{"cpuBurstStrategy":{"cpuBurstPercent":100},"newStrategy":{"enable":true}}`,
+			version: CurrentNodeSLOSchemaVersion + 1,
+			want: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{CPUBurstPercent: pointer.Int64(100)},
+				},
+			},
+			wantWarnings: 2,
+		},
+		{
+			name:    "invalid spec",
+			rawSpec: `{"cpuBurstStrategy":{"cpuBurstPercent":"100"}}`,
+			version: CurrentNodeSLOSchemaVersion,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := ConvertNodeSLOSpec([]byte(tt.rawSpec), tt.version)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantWarnings, len(warnings), warnings)
+		})
+	}
+}
+
+func TestConvertNodeSLOSpecFromV1(t *testing.T) {
+	tests := []struct {
+		name         string
+		rawSpec      string
+		want         *slov1alpha1.NodeSLOSpec
+		wantWarnings int
+	}{
+		{
+			name:    "no resource threshold",
+			rawSpec: `{"cpuBurstStrategy":{"cpuBurstPercent":100}}`,
+			want: &slov1alpha1.NodeSLOSpec{
+				CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+					CPUBurstConfig: slov1alpha1.CPUBurstConfig{CPUBurstPercent: pointer.Int64(100)},
+				},
+			},
+		},
+		{
+			name:    "known cpu suppress policy kept",
+			rawSpec: `{"resourceUsedThresholdWithBE":{"enable":true,"cpuSuppressPolicy":"cfsQuota"}}`,
+			want: &slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					Enable:            pointer.Bool(true),
+					CPUSuppressPolicy: slov1alpha1.CPUCfsQuotaPolicy,
+				},
+			},
+		},
+		{
+			name:    "unknown cpu suppress policy converted to cpuset",
+			rawSpec: `{"resourceUsedThresholdWithBE":{"enable":true,"cpuSuppressPolicy":"cfsquota"}}`,
+			want: &slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					Enable:            pointer.Bool(true),
+					CPUSuppressPolicy: slov1alpha1.CPUSetPolicy,
+				},
+			},
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := ConvertNodeSLOSpec([]byte(tt.rawSpec), NodeSLOSchemaVersionV1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantWarnings, len(warnings), warnings)
+		})
+	}
+}
@@ -39,3 +39,39 @@ func Test_GetInstance(t *testing.T) {
 	assert.True(t, info["ResourceThresholdStrategy.CPUSuppressThresholdPercent"] != "", info["ResourceThresholdStrategy.CPUSuppressThresholdPercent"])
 	assert.NoError(t, err)
 }
+
+func Test_ValidateCPUSuppressPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  slov1alpha1.CPUSuppressPolicy
+		wantErr bool
+	}{
+		{
+			name:   "empty policy",
+			policy: "",
+		},
+		{
+			name:   "cpuset policy",
+			policy: slov1alpha1.CPUSetPolicy,
+		},
+		{
+			name:   "cfsQuota policy",
+			policy: slov1alpha1.CPUCfsQuotaPolicy,
+		},
+		{
+			name:    "unknown policy",
+			policy:  "unknown",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := &slov1alpha1.ResourceThresholdStrategy{
+				CPUSuppressPolicy: tt.policy,
+			}
+			info, err := GetValidatorInstance().StructWithTrans(strategy)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantErr, info["ResourceThresholdStrategy.CPUSuppressPolicy"] != "", info)
+		})
+	}
+}
@@ -131,6 +131,27 @@ func PatchPod(ctx context.Context, clientset clientset.Interface, oldPod, newPod
 	return patched, nil
 }
 
+// PatchPodStatus patches the status subresource of the pod, e.g. the pod conditions reported by the node agent.
+func PatchPodStatus(ctx context.Context, clientset clientset.Interface, oldPod, newPod *corev1.Pod) (*corev1.Pod, error) {
+	patchBytes, err := GeneratePodPatch(oldPod, newPod)
+	if err != nil {
+		klog.V(5).InfoS("failed to generate pod status patch", "pod", klog.KObj(oldPod), "err", err)
+		return nil, err
+	}
+	if string(patchBytes) == "{}" { // nothing to patch
+		return oldPod, nil
+	}
+
+	patched, err := clientset.CoreV1().Pods(oldPod.Namespace).
+		Patch(ctx, oldPod.Name, apimachinerytypes.StrategicMergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+	if err != nil {
+		klog.V(5).InfoS("failed to patch pod status", "pod", klog.KObj(oldPod), "patch", string(patchBytes), "err", err)
+		return nil, err
+	}
+	klog.V(6).InfoS("successfully patch pod status", "pod", klog.KObj(oldPod), "patch", string(patchBytes))
+	return patched, nil
+}
+
 func GenerateReservationPatch(oldReservation, newReservation *schedulingv1alpha1.Reservation) ([]byte, error) {
 	oldData, err := json.Marshal(oldReservation)
 	if err != nil {
@@ -19,10 +19,14 @@ package webhook
 import (
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+	"github.com/koordinator-sh/koordinator/pkg/webhook/node/mutating"
 	"github.com/koordinator-sh/koordinator/pkg/webhook/node/validating"
 )
 
 func init() {
+	addHandlersWithGate(mutating.HandlerMap, func() (enabled bool) {
+		return utilfeature.DefaultFeatureGate.Enabled(features.NodeMutatingWebhook)
+	})
 
 	addHandlersWithGate(validating.HandlerMap, func() (enabled bool) {
 		return utilfeature.DefaultFeatureGate.Enabled(features.NodeValidatingWebhook)
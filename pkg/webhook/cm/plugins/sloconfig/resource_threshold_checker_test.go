@@ -269,6 +269,40 @@ func Test_ResourceThreshold_ConfigContentsValid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "cluster CPUSuppressPolicy invalid",
+			args: args{
+				cfg: configuration.ResourceThresholdCfg{
+					ClusterStrategy: &slov1alpha1.ResourceThresholdStrategy{
+						Enable:            pointer.Bool(true),
+						CPUSuppressPolicy: "unknown",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "node CPUSuppressPolicy cfsQuota valid",
+			args: args{
+				cfg: configuration.ResourceThresholdCfg{
+					ClusterStrategy: &slov1alpha1.ResourceThresholdStrategy{
+						Enable:            pointer.Bool(true),
+						CPUSuppressPolicy: slov1alpha1.CPUSetPolicy,
+					},
+					NodeStrategies: []configuration.NodeResourceThresholdStrategy{
+						{
+							NodeCfgProfile: configuration.NodeCfgProfile{
+								Name: "xxx-yyy",
+							},
+							ResourceThresholdStrategy: &slov1alpha1.ResourceThresholdStrategy{
+								CPUSuppressPolicy: slov1alpha1.CPUCfsQuotaPolicy,
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "all is nil",
 			args: args{
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var (
+	supportedCPUBindPolicies = sets.NewString(
+		string(extension.CPUBindPolicyDefault),
+		string(extension.CPUBindPolicyFullPCPUs),
+		string(extension.CPUBindPolicySpreadByPCPUs),
+		string(extension.CPUBindPolicyConstrainedBurst),
+	)
+	supportedCPUExclusivePolicies = sets.NewString(
+		string(extension.CPUExclusivePolicyNone),
+		string(extension.CPUExclusivePolicyPCPULevel),
+		string(extension.CPUExclusivePolicyNUMANodeLevel),
+	)
+	supportedNUMATopologyPolicies = sets.NewString(
+		string(extension.NUMATopologyPolicyBestEffort),
+		string(extension.NUMATopologyPolicyRestricted),
+		string(extension.NUMATopologyPolicySingleNUMANode),
+	)
+	supportedNUMAAllocateStrategies = sets.NewString(
+		string(extension.NUMAMostAllocated),
+		string(extension.NUMALeastAllocated),
+	)
+	supportedIRQSteeringPolicies = sets.NewString(
+		string(extension.IRQSteeringPolicyNone),
+		string(extension.IRQSteeringPolicyIsolated),
+	)
+	// cpuBindingPolicies are the bind policies allocating dedicated logical CPUs to the Pod.
+	cpuBindingPolicies = sets.NewString(
+		string(extension.CPUBindPolicyDefault),
+		string(extension.CPUBindPolicyFullPCPUs),
+		string(extension.CPUBindPolicySpreadByPCPUs),
+	)
+)
+
+type CPUOrchestrationPolicyValidatingHandler struct {
+	Client client.Client
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &CPUOrchestrationPolicyValidatingHandler{}
+
+func shouldIgnoreIfNotCPUOrchestrationPolicies(req admission.Request) bool {
+	// Ignore all calls to sub resources or resources other than cpuorchestrationpolicies.
+	if len(req.AdmissionRequest.SubResource) != 0 ||
+		req.AdmissionRequest.Resource.Resource != "cpuorchestrationpolicies" {
+		return true
+	}
+	return false
+}
+
+func (h *CPUOrchestrationPolicyValidatingHandler) Handle(ctx context.Context, request admission.Request) (resp admission.Response) {
+	if shouldIgnoreIfNotCPUOrchestrationPolicies(request) {
+		return admission.Allowed("")
+	}
+
+	obj := &configv1alpha1.CPUOrchestrationPolicy{}
+	if err := h.Decoder.Decode(request, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	defer func() {
+		if !resp.Allowed {
+			klog.Warningf("Webhook finish validating CPUOrchestrationPolicy %s, allowed: %v, result: %v",
+				obj.Name, resp.Allowed, util.DumpJSON(resp.Result))
+		}
+	}()
+
+	if err := ValidateCPUOrchestrationPolicySpec(&obj.Spec, field.NewPath("spec")).ToAggregate(); err != nil {
+		return admission.ValidationResponse(false, err.Error())
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+// ValidateCPUOrchestrationPolicySpec validates the values and the combinations of the policies.
+func ValidateCPUOrchestrationPolicySpec(spec *configv1alpha1.CPUOrchestrationPolicySpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateSupportedValue(spec.CPUBindPolicy, supportedCPUBindPolicies, fldPath.Child("cpuBindPolicy"))...)
+	allErrs = append(allErrs, validateSupportedValue(spec.CPUExclusivePolicy, supportedCPUExclusivePolicies, fldPath.Child("cpuExclusivePolicy"))...)
+	allErrs = append(allErrs, validateSupportedValue(spec.NUMATopologyPolicy, supportedNUMATopologyPolicies, fldPath.Child("numaTopologyPolicy"))...)
+	allErrs = append(allErrs, validateSupportedValue(spec.NUMAAllocateStrategy, supportedNUMAAllocateStrategies, fldPath.Child("numaAllocateStrategy"))...)
+	allErrs = append(allErrs, validateSupportedValue(spec.IRQSteeringPolicy, supportedIRQSteeringPolicies, fldPath.Child("irqSteeringPolicy"))...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	bindCPUs := cpuBindingPolicies.Has(spec.CPUBindPolicy)
+	if spec.RequiredCPUBindPolicy && !bindCPUs {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requiredCPUBindPolicy"), spec.RequiredCPUBindPolicy,
+			fmt.Sprintf("cpuBindPolicy must be one of %v", cpuBindingPolicies.List())))
+	}
+	if spec.CPUExclusivePolicy != "" && spec.CPUExclusivePolicy != string(extension.CPUExclusivePolicyNone) && !bindCPUs {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cpuExclusivePolicy"), spec.CPUExclusivePolicy,
+			fmt.Sprintf("cpuBindPolicy must be one of %v", cpuBindingPolicies.List())))
+	}
+	if spec.IRQSteeringPolicy == string(extension.IRQSteeringPolicyIsolated) && !bindCPUs {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("irqSteeringPolicy"), spec.IRQSteeringPolicy,
+			fmt.Sprintf("cpuBindPolicy must be one of %v", cpuBindingPolicies.List())))
+	}
+	return allErrs
+}
+
+func validateSupportedValue(value string, supported sets.String, fldPath *field.Path) field.ErrorList {
+	if value == "" || supported.Has(value) {
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, value, supported.List())}
+}
+
+var _ inject.Client = &CPUOrchestrationPolicyValidatingHandler{}
+
+// InjectClient injects the client into the CPUOrchestrationPolicyValidatingHandler
+func (h *CPUOrchestrationPolicyValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &CPUOrchestrationPolicyValidatingHandler{}
+
+// InjectDecoder injects the decoder into the CPUOrchestrationPolicyValidatingHandler
+func (h *CPUOrchestrationPolicyValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
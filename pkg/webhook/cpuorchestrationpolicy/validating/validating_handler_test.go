@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+)
+
+func makeTestHandler() *CPUOrchestrationPolicyValidatingHandler {
+	client := fake.NewClientBuilder().Build()
+	sche := client.Scheme()
+	configv1alpha1.AddToScheme(sche)
+	decoder, _ := admission.NewDecoder(sche)
+	handler := &CPUOrchestrationPolicyValidatingHandler{}
+	handler.InjectClient(client)
+	handler.InjectDecoder(decoder)
+	return handler
+}
+
+func TestCPUOrchestrationPolicyValidatingHandler_Handle(t *testing.T) {
+	handler := makeTestHandler()
+	tests := []struct {
+		name        string
+		resource    string
+		spec        configv1alpha1.CPUOrchestrationPolicySpec
+		wantAllowed bool
+		wantReason  string
+	}{
+		{
+			name:        "not a CPUOrchestrationPolicy",
+			resource:    "pods",
+			wantAllowed: true,
+		},
+		{
+			name:     "valid policy",
+			resource: "cpuorchestrationpolicies",
+			spec: configv1alpha1.CPUOrchestrationPolicySpec{
+				CPUBindPolicy:         "FullPCPUs",
+				RequiredCPUBindPolicy: true,
+				CPUExclusivePolicy:    "PCPULevel",
+				NUMATopologyPolicy:    "SingleNUMANode",
+				NUMAAllocateStrategy:  "LeastAllocated",
+				IRQSteeringPolicy:     "Isolated",
+			},
+			wantAllowed: true,
+		},
+		{
+			name:     "unsupported bind policy",
+			resource: "cpuorchestrationpolicies",
+			spec: configv1alpha1.CPUOrchestrationPolicySpec{
+				CPUBindPolicy: "Unknown",
+			},
+			wantAllowed: false,
+			wantReason:  `spec.cpuBindPolicy: Unsupported value: "Unknown": supported values: "ConstrainedBurst", "Default", "FullPCPUs", "SpreadByPCPUs"`,
+		},
+		{
+			name:     "required bind policy without binding CPUs",
+			resource: "cpuorchestrationpolicies",
+			spec: configv1alpha1.CPUOrchestrationPolicySpec{
+				CPUBindPolicy:         "ConstrainedBurst",
+				RequiredCPUBindPolicy: true,
+			},
+			wantAllowed: false,
+			wantReason:  `spec.requiredCPUBindPolicy: Invalid value: true: cpuBindPolicy must be one of [Default FullPCPUs SpreadByPCPUs]`,
+		},
+		{
+			name:     "exclusive policy and IRQ steering without binding CPUs",
+			resource: "cpuorchestrationpolicies",
+			spec: configv1alpha1.CPUOrchestrationPolicySpec{
+				CPUExclusivePolicy: "NUMANodeLevel",
+				IRQSteeringPolicy:  "Isolated",
+			},
+			wantAllowed: false,
+			wantReason:  `[spec.cpuExclusivePolicy: Invalid value: "NUMANodeLevel": cpuBindPolicy must be one of [Default FullPCPUs SpreadByPCPUs], spec.irqSteeringPolicy: Invalid value: "Isolated": cpuBindPolicy must be one of [Default FullPCPUs SpreadByPCPUs]]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &configv1alpha1.CPUOrchestrationPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-policy",
+				},
+				Spec: tt.spec,
+			}
+			raw, err := json.Marshal(policy)
+			assert.NoError(t, err)
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource: metav1.GroupVersionResource{
+						Group:    configv1alpha1.GroupVersion.Group,
+						Version:  configv1alpha1.GroupVersion.Version,
+						Resource: tt.resource,
+					},
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			}
+			resp := handler.Handle(context.TODO(), req)
+			assert.Equal(t, tt.wantAllowed, resp.Allowed)
+			if !tt.wantAllowed {
+				assert.Equal(t, tt.wantReason, string(resp.Result.Reason))
+			}
+		})
+	}
+}
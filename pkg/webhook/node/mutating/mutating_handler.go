@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/pkg/webhook/node/plugins"
+	"github.com/koordinator-sh/koordinator/pkg/webhook/node/plugins/resourceamplification"
+)
+
+// NodeMutatingHandler handles Node
+type NodeMutatingHandler struct {
+	Client client.Client
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+func NewNodeMutatingHandler() *NodeMutatingHandler {
+	handler := &NodeMutatingHandler{}
+	return handler
+}
+
+var _ admission.Handler = &NodeMutatingHandler{}
+
+func shouldIgnoreIfNotNode(req admission.Request) bool {
+	// Ignore all calls to resources other than nodes, the status sub resource is handled.
+	if req.AdmissionRequest.Resource.Resource != "nodes" {
+		return true
+	}
+	if len(req.AdmissionRequest.SubResource) != 0 && req.AdmissionRequest.SubResource != "status" {
+		return true
+	}
+	return false
+}
+
+// Handle handles admission requests.
+func (h *NodeMutatingHandler) Handle(ctx context.Context, req admission.Request) (resp admission.Response) {
+	if shouldIgnoreIfNotNode(req) {
+		return admission.Allowed("")
+	}
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	obj, oldObj := &corev1.Node{}, &corev1.Node{}
+	if err := h.Decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update {
+		if err := h.Decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	clone := obj.DeepCopy()
+
+	for _, plugin := range h.getPlugins() {
+		if err := plugin.Admit(ctx, req, obj, oldObj); err != nil {
+			klog.Errorf("Failed to mutating Node %s by plugin %s, err: %v", obj.Name, plugin.Name(), err)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	if reflect.DeepEqual(obj, clone) {
+		return admission.Allowed("")
+	}
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		klog.Errorf("Failed to marshal mutated Node %s, err: %v", obj.Name, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.AdmissionRequest.Object.Raw, marshaled)
+}
+
+func (h *NodeMutatingHandler) getPlugins() []plugins.NodePlugin {
+	return []plugins.NodePlugin{resourceamplification.NewPlugin()}
+}
+
+var _ inject.Client = &NodeMutatingHandler{}
+
+// InjectClient injects the client into the NodeMutatingHandler
+func (h *NodeMutatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &NodeMutatingHandler{}
+
+// InjectDecoder injects the decoder into the NodeMutatingHandler
+func (h *NodeMutatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func makeTestHandler() *NodeMutatingHandler {
+	client := fake.NewClientBuilder().Build()
+	decoder, _ := admission.NewDecoder(client.Scheme())
+	handler := NewNodeMutatingHandler()
+	handler.InjectClient(client)
+	handler.InjectDecoder(decoder)
+	return handler
+}
+
+func gvr(resource string) metav1.GroupVersionResource {
+	return metav1.GroupVersionResource{
+		Group:    corev1.SchemeGroupVersion.Group,
+		Version:  corev1.SchemeGroupVersion.Version,
+		Resource: resource,
+	}
+}
+
+func TestNodeMutatingHandler_Handle(t *testing.T) {
+	handler := makeTestHandler()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.50}`,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("10"),
+			},
+		},
+	}
+	nodeRaw, err := json.Marshal(node)
+	assert.NoError(t, err)
+	nodeWithoutRatio := node.DeepCopy()
+	nodeWithoutRatio.Annotations = nil
+	nodeWithoutRatioRaw, err := json.Marshal(nodeWithoutRatio)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		request     admission.Request
+		allowed     bool
+		code        int32
+		wantPatched bool
+	}{
+		{
+			name: "not a node",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("configmaps"),
+					Operation: admissionv1.Create,
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "ignore delete",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("nodes"),
+					Operation: admissionv1.Delete,
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "node with empty object",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("nodes"),
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{},
+				},
+			},
+			allowed: false,
+			code:    http.StatusBadRequest,
+		},
+		{
+			name: "node without ratio",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("nodes"),
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: nodeWithoutRatioRaw},
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "amplify node status",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:    gvr("nodes"),
+					SubResource: "status",
+					Operation:   admissionv1.Update,
+					Object:      runtime.RawExtension{Raw: nodeRaw},
+					OldObject:   runtime.RawExtension{Raw: nodeWithoutRatioRaw},
+				},
+			},
+			allowed:     true,
+			wantPatched: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handler.Handle(context.TODO(), tt.request)
+			assert.Equal(t, tt.allowed, response.Allowed)
+			if !tt.allowed {
+				assert.Equal(t, tt.code, response.Result.Code)
+			}
+			assert.Equal(t, tt.wantPatched, len(response.Patches) > 0)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceamplification
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrladmission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	PluginName = "NodeResourceAmplification"
+)
+
+// NodeResourceAmplificationPlugin amplifies the node allocatable according to the resource amplification ratios,
+// and keeps the un-amplified allocatable in the node annotation.
+type NodeResourceAmplificationPlugin struct{}
+
+func NewPlugin() *NodeResourceAmplificationPlugin {
+	return &NodeResourceAmplificationPlugin{}
+}
+
+func (p *NodeResourceAmplificationPlugin) Name() string {
+	return PluginName
+}
+
+func (p *NodeResourceAmplificationPlugin) Validate(ctx context.Context, req ctrladmission.Request, node, oldNode *corev1.Node) error {
+	return nil
+}
+
+func (p *NodeResourceAmplificationPlugin) Admit(ctx context.Context, req ctrladmission.Request, node, oldNode *corev1.Node) error {
+	switch req.AdmissionRequest.Operation {
+	case admissionv1.Create:
+		oldNode = nil
+	case admissionv1.Update:
+		// the status changes are dropped when updating the node object, so only mutate the status updates
+		if req.AdmissionRequest.SubResource != "status" {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	ratios, err := extension.GetNodeResourceAmplificationRatios(node.Annotations)
+	if err != nil {
+		klog.V(4).Infof("skip amplifying node %s, failed to get amplification ratios, err: %v", node.Name, err)
+		return nil
+	}
+	lastRawAllocatable, err := getLastRawAllocatable(node, oldNode)
+	if err != nil {
+		klog.V(4).Infof("skip amplifying node %s, failed to get raw allocatable, err: %v", node.Name, err)
+		return nil
+	}
+	if len(ratios) == 0 && len(lastRawAllocatable) == 0 {
+		return nil
+	}
+
+	amplifyNodeAllocatable(node, oldNode, ratios, lastRawAllocatable)
+	klog.V(5).Infof("amplify node %s allocatable with ratios %v, allocatable %v", node.Name, ratios, node.Status.Allocatable)
+	return nil
+}
+
+func getLastRawAllocatable(node, oldNode *corev1.Node) (corev1.ResourceList, error) {
+	if oldNode != nil {
+		return extension.GetNodeRawAllocatable(oldNode.Annotations)
+	}
+	return extension.GetNodeRawAllocatable(node.Annotations)
+}
+
+// amplifyNodeAllocatable amplifies the raw allocatable of the resources whose ratio is greater than 1, and restores
+// the raw allocatable of the resources no longer amplified.
+// The allocatable is amplified already if it is unchanged from the old node, so the last raw allocatable is used.
+// Otherwise, it is reported by the kubelet as the raw allocatable.
+func amplifyNodeAllocatable(node, oldNode *corev1.Node, ratios map[corev1.ResourceName]extension.Ratio, lastRawAllocatable corev1.ResourceList) {
+	resourceNames := map[corev1.ResourceName]struct{}{}
+	for name := range ratios {
+		resourceNames[name] = struct{}{}
+	}
+	for name := range lastRawAllocatable {
+		resourceNames[name] = struct{}{}
+	}
+
+	rawAllocatable := corev1.ResourceList{}
+	for name := range resourceNames {
+		quantity, ok := node.Status.Allocatable[name]
+		if !ok {
+			continue
+		}
+		raw := quantity.DeepCopy()
+		if lastRaw, ok := lastRawAllocatable[name]; ok && oldNode != nil {
+			if oldQuantity, ok := oldNode.Status.Allocatable[name]; ok && oldQuantity.Cmp(quantity) == 0 {
+				raw = lastRaw.DeepCopy()
+			}
+		}
+
+		if ratios[name] <= 1 {
+			node.Status.Allocatable[name] = raw
+			continue
+		}
+		rawAllocatable[name] = raw
+		amplified := corev1.ResourceList{name: raw.DeepCopy()}
+		extension.AmplifyResourceList(amplified, ratios, name)
+		node.Status.Allocatable[name] = amplified[name]
+	}
+
+	if len(rawAllocatable) == 0 {
+		delete(node.Annotations, extension.AnnotationNodeRawAllocatable)
+		return
+	}
+	extension.SetNodeRawAllocatable(node, rawAllocatable)
+}
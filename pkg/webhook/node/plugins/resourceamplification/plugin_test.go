@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceamplification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrladmission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func makeNode(annotations map[string]string, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-node",
+			Annotations: annotations,
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func makeRequest(op admissionv1.Operation, subResource string) ctrladmission.Request {
+	return ctrladmission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation:   op,
+			SubResource: subResource,
+		},
+	}
+}
+
+func TestNodeResourceAmplificationPlugin_Admit(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      ctrladmission.Request
+		node     *corev1.Node
+		oldNode  *corev1.Node
+		wantNode *corev1.Node
+	}{
+		{
+			name:     "skip node without ratios",
+			req:      makeRequest(admissionv1.Create, ""),
+			node:     makeNode(nil, "10", "20Gi"),
+			wantNode: makeNode(nil, "10", "20Gi"),
+		},
+		{
+			name: "skip invalid ratios",
+			req:  makeRequest(admissionv1.Create, ""),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{]`,
+			}, "10", "20Gi"),
+			wantNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{]`,
+			}, "10", "20Gi"),
+		},
+		{
+			name: "amplify cpu when node created",
+			req:  makeRequest(admissionv1.Create, ""),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+			}, "10", "20Gi"),
+			wantNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+		},
+		{
+			name: "skip updating the node object",
+			req:  makeRequest(admissionv1.Update, ""),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+			}, "10", "20Gi"),
+			oldNode: makeNode(nil, "10", "20Gi"),
+			wantNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+			}, "10", "20Gi"),
+		},
+		{
+			name: "amplify the raw allocatable reported by kubelet",
+			req:  makeRequest(admissionv1.Update, "status"),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "11", "20Gi"),
+			oldNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			wantNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.20}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"11"}`,
+			}, "13200m", "20Gi"),
+		},
+		{
+			name: "re-amplify the unchanged allocatable with the new ratio",
+			req:  makeRequest(admissionv1.Update, "status"),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.50}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			oldNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.50}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			wantNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.50}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "15", "20Gi"),
+		},
+		{
+			name: "de-amplify when the ratio is reset",
+			req:  makeRequest(admissionv1.Update, "status"),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.00}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			oldNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.00}`,
+				extension.AnnotationNodeRawAllocatable:             `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			wantNode: makeNode(map[string]string{
+				extension.AnnotationNodeResourceAmplificationRatio: `{"cpu":1.00}`,
+			}, "10", "20Gi"),
+		},
+		{
+			name: "de-amplify when the ratio is removed",
+			req:  makeRequest(admissionv1.Update, "status"),
+			node: makeNode(map[string]string{
+				extension.AnnotationNodeRawAllocatable: `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			oldNode: makeNode(map[string]string{
+				extension.AnnotationNodeRawAllocatable: `{"cpu":"10"}`,
+			}, "12", "20Gi"),
+			wantNode: makeNode(map[string]string{}, "10", "20Gi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlugin()
+			err := p.Admit(context.TODO(), tt.req, tt.node, tt.oldNode)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNode.Annotations, tt.node.Annotations)
+			for name, want := range tt.wantNode.Status.Allocatable {
+				got := tt.node.Status.Allocatable[name]
+				assert.Equal(t, 0, want.Cmp(got), "resource %s, want %s, got %s", name, want.String(), got.String())
+			}
+			assert.NoError(t, p.Validate(context.TODO(), tt.req, tt.node, tt.oldNode))
+		})
+	}
+}
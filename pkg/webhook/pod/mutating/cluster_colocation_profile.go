@@ -68,17 +68,8 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 	var matchedProfiles []*configv1alpha1.ClusterColocationProfile
 	for i := range profileList.Items {
 		profile := &profileList.Items[i]
-		if profile.Spec.NamespaceSelector != nil {
-			matched, err := h.matchNamespaceSelector(ctx, pod.Namespace, profile.Spec.NamespaceSelector)
-			if !matched && err == nil {
-				continue
-			}
-		}
-		if profile.Spec.Selector != nil {
-			matched, err := h.matchObjectSelector(pod, nil, profile.Spec.Selector)
-			if !matched && err == nil {
-				continue
-			}
+		if matched, _ := h.matchColocationProfile(ctx, pod, profile); !matched {
+			continue
 		}
 		matchedProfiles = append(matchedProfiles, profile)
 	}
@@ -110,6 +101,24 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 	return h.mutatePodResourceSpec(pod)
 }
 
+// matchColocationProfile checks whether the profile selects the Pod, and returns the reason if not.
+// The profile is considered matched if its selectors fail to evaluate.
+func (h *PodMutatingHandler) matchColocationProfile(ctx context.Context, pod *corev1.Pod, profile *configv1alpha1.ClusterColocationProfile) (bool, string) {
+	if profile.Spec.NamespaceSelector != nil {
+		matched, err := h.matchNamespaceSelector(ctx, pod.Namespace, profile.Spec.NamespaceSelector)
+		if !matched && err == nil {
+			return false, "namespaceSelector does not match the namespace of the Pod"
+		}
+	}
+	if profile.Spec.Selector != nil {
+		matched, err := h.matchObjectSelector(pod, nil, profile.Spec.Selector)
+		if !matched && err == nil {
+			return false, "selector does not match the labels of the Pod"
+		}
+	}
+	return true, ""
+}
+
 func (h *PodMutatingHandler) matchNamespaceSelector(ctx context.Context, namespaceName string, namespaceSelector *metav1.LabelSelector) (bool, error) {
 	selector, err := util.GetFastLabelSelector(namespaceSelector)
 	if err != nil {
@@ -143,17 +152,21 @@ func (h *PodMutatingHandler) matchObjectSelector(pod, oldPod *corev1.Pod, object
 }
 
 func shouldSkipProfile(profile *configv1alpha1.ClusterColocationProfile) (bool, error) {
-	percent := 100
-	if profile.Spec.Probability != nil {
-		var err error
-		percent, err = intstr.GetScaledValueFromIntOrPercent(profile.Spec.Probability, 100, false)
-		if err != nil {
-			return false, err
-		}
+	percent, err := getProfileProbability(profile)
+	if err != nil {
+		return false, err
 	}
 	return percent == 0 || (percent != 100 && randIntnFn(100) > percent), nil
 }
 
+// getProfileProbability returns the percentage of the Pods which the profile mutates.
+func getProfileProbability(profile *configv1alpha1.ClusterColocationProfile) (int, error) {
+	if profile.Spec.Probability == nil {
+		return 100, nil
+	}
+	return intstr.GetScaledValueFromIntOrPercent(profile.Spec.Probability, 100, false)
+}
+
 func (h *PodMutatingHandler) doMutateByColocationProfile(ctx context.Context, pod *corev1.Pod, profile *configv1alpha1.ClusterColocationProfile) error {
 	if len(profile.Spec.Labels) > 0 {
 		if pod.Labels == nil {
@@ -201,6 +214,19 @@ func (h *PodMutatingHandler) doMutateByColocationProfile(ctx context.Context, po
 		pod.Labels[extension.LabelPodPriority] = fmt.Sprintf("%d", *profile.Spec.KoordinatorPriority)
 	}
 
+	if profile.Spec.NUMATopologyPolicy != "" {
+		numaTopologySpec, err := extension.GetNUMATopologySpec(pod.Annotations)
+		if err != nil {
+			return err
+		}
+		if numaTopologySpec.NUMATopologyPolicy == extension.NUMATopologyPolicyNone {
+			numaTopologySpec.NUMATopologyPolicy = extension.NUMATopologyPolicy(profile.Spec.NUMATopologyPolicy)
+			if err := extension.SetNUMATopologySpec(pod, numaTopologySpec); err != nil {
+				return err
+			}
+		}
+	}
+
 	if profile.Spec.Patch.Raw != nil {
 		cloneBytes, _ := json.Marshal(pod)
 		modified, err := strategicpatch.StrategicMergePatch(cloneBytes, profile.Spec.Patch.Raw, &corev1.Pod{})
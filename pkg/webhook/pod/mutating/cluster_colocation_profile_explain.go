@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+// ColocationProfileExplanation explains how the ClusterColocationProfiles mutate a Pod on creation.
+type ColocationProfileExplanation struct {
+	// Profiles are the mutations of the profiles in the order they are applied.
+	Profiles []ColocationProfileMutation `json:"profiles"`
+	// ResourceSpecPatch is the mutation of the resource requirements by the priority class after the profiles applied.
+	ResourceSpecPatch json.RawMessage `json:"resourceSpecPatch,omitempty"`
+	// Pod is the Pod after all the mutations.
+	Pod *corev1.Pod `json:"pod"`
+}
+
+// ColocationProfileMutation is the mutation of a ClusterColocationProfile on the Pod.
+type ColocationProfileMutation struct {
+	Profile string `json:"profile"`
+	Matched bool   `json:"matched"`
+	// Skipped is true if the profile matches the Pod but never mutates it, e.g. the probability is 0.
+	Skipped bool `json:"skipped,omitempty"`
+	// Reason describes why the profile is not matched or skipped.
+	Reason string `json:"reason,omitempty"`
+	// Probability is the percentage of the Pods which the profile mutates, if not all.
+	// The dry-run always applies the profile in this case.
+	Probability *int `json:"probability,omitempty"`
+	// Patch is the strategic merge patch applied by the profile.
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+// explainClusterColocationProfiles mutates the Pod by the ClusterColocationProfiles the same as on the Pod creation,
+// and records the mutations of each profile.
+func (h *PodMutatingHandler) explainClusterColocationProfiles(ctx context.Context, pod *corev1.Pod) (*ColocationProfileExplanation, error) {
+	profileList := &configv1alpha1.ClusterColocationProfileList{}
+	if err := h.Client.List(ctx, profileList, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+
+	explanation := &ColocationProfileExplanation{}
+	hasMatchedProfiles := false
+	skipUpdateResourceFromProfile := false
+	for i := range profileList.Items {
+		profile := &profileList.Items[i]
+		mutation := ColocationProfileMutation{
+			Profile: profile.Name,
+		}
+		matched, reason := h.matchColocationProfile(ctx, pod, profile)
+		if !matched {
+			mutation.Reason = reason
+			explanation.Profiles = append(explanation.Profiles, mutation)
+			continue
+		}
+		mutation.Matched = true
+		hasMatchedProfiles = true
+		if extension.ShouldSkipUpdateResource(profile) {
+			skipUpdateResourceFromProfile = true
+		}
+
+		percent, err := getProfileProbability(profile)
+		if err != nil {
+			return nil, err
+		}
+		if percent == 0 {
+			mutation.Skipped = true
+			mutation.Reason = "probability is 0"
+			explanation.Profiles = append(explanation.Profiles, mutation)
+			continue
+		}
+		if percent != 100 {
+			mutation.Probability = &percent
+		}
+
+		mutation.Patch, err = explainPodMutation(pod, func() error {
+			return h.doMutateByColocationProfile(ctx, pod, profile)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to mutate by profile %s, err: %w", profile.Name, err)
+		}
+		explanation.Profiles = append(explanation.Profiles, mutation)
+	}
+
+	if hasMatchedProfiles && !skipUpdateResourceFromProfile &&
+		!utilfeature.DefaultFeatureGate.Enabled(features.ColocationProfileSkipMutatingResources) {
+		patch, err := explainPodMutation(pod, func() error {
+			return h.mutatePodResourceSpec(pod)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to mutate resource spec, err: %w", err)
+		}
+		explanation.ResourceSpecPatch = patch
+	}
+	explanation.Pod = pod
+	return explanation, nil
+}
+
+// explainPodMutation runs the mutation on the Pod, and returns the strategic merge patch of the changes.
+// It returns nil if the mutation changes nothing.
+func explainPodMutation(pod *corev1.Pod, mutate func() error) (json.RawMessage, error) {
+	original, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	if err = mutate(); err != nil {
+		return nil, err
+	}
+	modified, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(original, modified, &corev1.Pod{})
+	if err != nil {
+		return nil, err
+	}
+	if string(patch) == "{}" {
+		return nil, nil
+	}
+	return patch, nil
+}
+
+// ColocationProfileExplainHandler serves the dry-run of the ClusterColocationProfiles. It takes the Pod in the
+// request body, and responds the mutations of each profile in order without creating the Pod.
+// The namespace of the Pod can be specified in the query parameter `namespace` if the Pod does not set it.
+type ColocationProfileExplainHandler struct {
+	Client client.Client
+}
+
+var _ http.Handler = &ColocationProfileExplainHandler{}
+
+func (h *ColocationProfileExplainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	pod := &corev1.Pod{}
+	if err := json.NewDecoder(r.Body).Decode(pod); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode the Pod, err: %v", err), http.StatusBadRequest)
+		return
+	}
+	if pod.Namespace == "" {
+		pod.Namespace = r.URL.Query().Get("namespace")
+	}
+	if pod.Namespace == "" {
+		http.Error(w, "the namespace of the Pod is required", http.StatusBadRequest)
+		return
+	}
+
+	mutatingHandler := &PodMutatingHandler{Client: h.Client}
+	explanation, err := mutatingHandler.explainClusterColocationProfiles(r.Context(), pod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(explanation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+var _ inject.Client = &ColocationProfileExplainHandler{}
+
+// InjectClient injects the client into the ColocationProfileExplainHandler
+func (h *ColocationProfileExplainHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newExplainTestClient(t *testing.T) client.Client {
+	c := fake.NewClientBuilder().Build()
+	zero := intstr.FromInt(0)
+	objs := []client.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "default",
+				Labels: map[string]string{
+					"enable-koordinator-colocation": "true",
+				},
+			},
+		},
+		&schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "koordinator-batch",
+			},
+			Value: extension.PriorityBatchValueMax,
+		},
+		&configv1alpha1.ClusterColocationProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "colocation-batch",
+			},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"enable-koordinator-colocation": "true",
+					},
+				},
+				QoSClass:          string(extension.QoSBE),
+				PriorityClassName: "koordinator-batch",
+			},
+		},
+		&configv1alpha1.ClusterColocationProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "other-namespaces",
+			},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"team": "other",
+					},
+				},
+				QoSClass: string(extension.QoSLS),
+			},
+		},
+		&configv1alpha1.ClusterColocationProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "never",
+			},
+			Spec: configv1alpha1.ClusterColocationProfileSpec{
+				Probability: &zero,
+				QoSClass:    string(extension.QoSLSR),
+			},
+		},
+	}
+	for _, obj := range objs {
+		assert.NoError(t, c.Create(context.TODO(), obj))
+	}
+	return c
+}
+
+func newExplainTestPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExplainClusterColocationProfiles(t *testing.T) {
+	handler := &PodMutatingHandler{Client: newExplainTestClient(t)}
+	explanation, err := handler.explainClusterColocationProfiles(context.TODO(), newExplainTestPod())
+	assert.NoError(t, err)
+	assert.Len(t, explanation.Profiles, 3)
+
+	mutations := map[string]ColocationProfileMutation{}
+	for _, mutation := range explanation.Profiles {
+		mutations[mutation.Profile] = mutation
+	}
+	batch := mutations["colocation-batch"]
+	assert.True(t, batch.Matched)
+	assert.False(t, batch.Skipped)
+	assert.Nil(t, batch.Probability)
+	assert.Contains(t, string(batch.Patch), `"koordinator.sh/qosClass":"BE"`)
+	assert.Contains(t, string(batch.Patch), `"priorityClassName":"koordinator-batch"`)
+
+	other := mutations["other-namespaces"]
+	assert.False(t, other.Matched)
+	assert.Contains(t, other.Reason, "namespaceSelector")
+	assert.Nil(t, other.Patch)
+
+	never := mutations["never"]
+	assert.True(t, never.Matched)
+	assert.True(t, never.Skipped)
+	assert.Nil(t, never.Patch)
+
+	assert.Contains(t, string(explanation.ResourceSpecPatch), string(extension.BatchCPU))
+	assert.Equal(t, string(extension.QoSBE), explanation.Pod.Labels[extension.LabelPodQoS])
+	assert.Equal(t, "koordinator-batch", explanation.Pod.Spec.PriorityClassName)
+}
+
+func TestColocationProfileExplainHandler(t *testing.T) {
+	handler := &ColocationProfileExplainHandler{}
+	assert.NoError(t, handler.InjectClient(newExplainTestClient(t)))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/explainColocationProfile", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	pod := newExplainTestPod()
+	pod.Namespace = ""
+	data, err := json.Marshal(pod)
+	assert.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/explainColocationProfile", bytes.NewReader(data)))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/explainColocationProfile?namespace=default", bytes.NewReader(data)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	explanation := &ColocationProfileExplanation{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), explanation))
+	assert.Len(t, explanation.Profiles, 3)
+	assert.Equal(t, string(extension.QoSBE), explanation.Pod.Labels[extension.LabelPodQoS])
+}
@@ -1211,6 +1211,66 @@ func TestClusterColocationProfileMutatingPod(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "mutating pod, set default NUMA topology policy",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+			},
+			profile: &configv1alpha1.ClusterColocationProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-profile",
+				},
+				Spec: configv1alpha1.ClusterColocationProfileSpec{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"enable-koordinator-colocation": "true",
+						},
+					},
+					NUMATopologyPolicy: string(extension.NUMATopologyPolicySingleNUMANode),
+				},
+			},
+			expected: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+					Annotations: map[string]string{
+						extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"SingleNUMANode"}`,
+					},
+				},
+			},
+		},
+		{
+			name: "mutating pod, keep NUMA topology policy specified by pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+					Annotations: map[string]string{
+						extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"Restricted"}`,
+					},
+				},
+			},
+			profile: &configv1alpha1.ClusterColocationProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-profile",
+				},
+				Spec: configv1alpha1.ClusterColocationProfileSpec{
+					NUMATopologyPolicy: string(extension.NUMATopologyPolicySingleNUMANode),
+				},
+			},
+			expected: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+					Annotations: map[string]string{
+						extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"Restricted"}`,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
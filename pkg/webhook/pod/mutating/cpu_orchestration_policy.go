@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// +kubebuilder:rbac:groups=config.koordinator.sh,resources=cpuorchestrationpolicies,verbs=get;list;watch
+
+func (h *PodMutatingHandler) cpuOrchestrationPolicyMutatingPod(ctx context.Context, req admission.Request, pod *corev1.Pod) error {
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	policyName := pod.Labels[extension.LabelCPUOrchestrationPolicy]
+	if policyName == "" {
+		return nil
+	}
+	policy := &configv1alpha1.CPUOrchestrationPolicy{}
+	if err := h.Client.Get(ctx, types.NamespacedName{Name: policyName}, policy); err != nil {
+		if errors.IsNotFound(err) {
+			// the pods referencing a deleted policy are admitted unmutated rather than blocked
+			klog.Warningf("CPUOrchestrationPolicy %s of Pod %s/%s not found, skip mutating", policyName, pod.Namespace, pod.Name)
+			return nil
+		}
+		return fmt.Errorf("failed to get CPUOrchestrationPolicy %s, err: %w", policyName, err)
+	}
+	return mutatePodByCPUOrchestrationPolicy(pod, &policy.Spec)
+}
+
+// mutatePodByCPUOrchestrationPolicy injects the policy into the annotations of the Pod.
+// The policies specified by the Pod itself take precedence over the CPUOrchestrationPolicy.
+func mutatePodByCPUOrchestrationPolicy(pod *corev1.Pod, spec *configv1alpha1.CPUOrchestrationPolicySpec) error {
+	resourceSpec, err := extension.GetResourceSpec(pod.Annotations)
+	if err != nil {
+		return err
+	}
+	resourceSpecChanged := false
+	if spec.CPUBindPolicy != "" && resourceSpec.RequiredCPUBindPolicy == "" && resourceSpec.PreferredCPUBindPolicy == "" {
+		if spec.RequiredCPUBindPolicy {
+			resourceSpec.RequiredCPUBindPolicy = extension.CPUBindPolicy(spec.CPUBindPolicy)
+		} else {
+			resourceSpec.PreferredCPUBindPolicy = extension.CPUBindPolicy(spec.CPUBindPolicy)
+		}
+		resourceSpecChanged = true
+	}
+	if spec.CPUExclusivePolicy != "" && resourceSpec.PreferredCPUExclusivePolicy == "" {
+		resourceSpec.PreferredCPUExclusivePolicy = extension.CPUExclusivePolicy(spec.CPUExclusivePolicy)
+		resourceSpecChanged = true
+	}
+	if spec.NUMAAllocateStrategy != "" && resourceSpec.PreferredNUMAAllocateStrategy == "" {
+		resourceSpec.PreferredNUMAAllocateStrategy = extension.NUMAAllocateStrategy(spec.NUMAAllocateStrategy)
+		resourceSpecChanged = true
+	}
+	if spec.IRQSteeringPolicy != "" && resourceSpec.IRQSteeringPolicy == "" {
+		resourceSpec.IRQSteeringPolicy = extension.IRQSteeringPolicy(spec.IRQSteeringPolicy)
+		resourceSpecChanged = true
+	}
+	if resourceSpecChanged {
+		if err := extension.SetResourceSpec(pod, resourceSpec); err != nil {
+			return err
+		}
+	}
+
+	if spec.NUMATopologyPolicy != "" {
+		numaTopologySpec, err := extension.GetNUMATopologySpec(pod.Annotations)
+		if err != nil {
+			return err
+		}
+		if numaTopologySpec.NUMATopologyPolicy == extension.NUMATopologyPolicyNone {
+			numaTopologySpec.NUMATopologyPolicy = extension.NUMATopologyPolicy(spec.NUMATopologyPolicy)
+			if err := extension.SetNUMATopologySpec(pod, numaTopologySpec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestCPUOrchestrationPolicyMutatingPod(t *testing.T) {
+	policy := &configv1alpha1.CPUOrchestrationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "latency-sensitive",
+		},
+		Spec: configv1alpha1.CPUOrchestrationPolicySpec{
+			CPUBindPolicy:         string(extension.CPUBindPolicyFullPCPUs),
+			RequiredCPUBindPolicy: true,
+			CPUExclusivePolicy:    string(extension.CPUExclusivePolicyPCPULevel),
+			NUMATopologyPolicy:    string(extension.NUMATopologyPolicySingleNUMANode),
+			NUMAAllocateStrategy:  string(extension.NUMALeastAllocated),
+			IRQSteeringPolicy:     string(extension.IRQSteeringPolicyIsolated),
+		},
+	}
+	tests := []struct {
+		name                 string
+		operation            admissionv1.Operation
+		labels               map[string]string
+		annotations          map[string]string
+		wantResourceSpec     *extension.ResourceSpec
+		wantNUMATopologySpec *extension.NUMATopologySpec
+	}{
+		{
+			name:                 "pod without policy",
+			operation:            admissionv1.Create,
+			wantResourceSpec:     &extension.ResourceSpec{},
+			wantNUMATopologySpec: &extension.NUMATopologySpec{},
+		},
+		{
+			name:      "pod references policy",
+			operation: admissionv1.Create,
+			labels: map[string]string{
+				extension.LabelCPUOrchestrationPolicy: "latency-sensitive",
+			},
+			wantResourceSpec: &extension.ResourceSpec{
+				RequiredCPUBindPolicy:         extension.CPUBindPolicyFullPCPUs,
+				PreferredCPUExclusivePolicy:   extension.CPUExclusivePolicyPCPULevel,
+				PreferredNUMAAllocateStrategy: extension.NUMALeastAllocated,
+				IRQSteeringPolicy:             extension.IRQSteeringPolicyIsolated,
+			},
+			wantNUMATopologySpec: &extension.NUMATopologySpec{
+				NUMATopologyPolicy: extension.NUMATopologyPolicySingleNUMANode,
+			},
+		},
+		{
+			name:      "pod specified policies take precedence",
+			operation: admissionv1.Create,
+			labels: map[string]string{
+				extension.LabelCPUOrchestrationPolicy: "latency-sensitive",
+			},
+			annotations: map[string]string{
+				extension.AnnotationResourceSpec:     `{"preferredCPUBindPolicy":"SpreadByPCPUs"}`,
+				extension.AnnotationNUMATopologySpec: `{"numaTopologyPolicy":"Restricted"}`,
+			},
+			wantResourceSpec: &extension.ResourceSpec{
+				PreferredCPUBindPolicy:        extension.CPUBindPolicySpreadByPCPUs,
+				PreferredCPUExclusivePolicy:   extension.CPUExclusivePolicyPCPULevel,
+				PreferredNUMAAllocateStrategy: extension.NUMALeastAllocated,
+				IRQSteeringPolicy:             extension.IRQSteeringPolicyIsolated,
+			},
+			wantNUMATopologySpec: &extension.NUMATopologySpec{
+				NUMATopologyPolicy: extension.NUMATopologyPolicyRestricted,
+			},
+		},
+		{
+			name:      "pod references missing policy",
+			operation: admissionv1.Create,
+			labels: map[string]string{
+				extension.LabelCPUOrchestrationPolicy: "not-exists",
+			},
+			wantResourceSpec:     &extension.ResourceSpec{},
+			wantNUMATopologySpec: &extension.NUMATopologySpec{},
+		},
+		{
+			name:      "ignore pod update",
+			operation: admissionv1.Update,
+			labels: map[string]string{
+				extension.LabelCPUOrchestrationPolicy: "latency-sensitive",
+			},
+			wantResourceSpec:     &extension.ResourceSpec{},
+			wantNUMATopologySpec: &extension.NUMATopologySpec{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithObjects(policy.DeepCopy()).Build()
+			handler := &PodMutatingHandler{
+				Client: client,
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "test-pod",
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+			}
+			podRaw, _ := json.Marshal(pod)
+			req := newAdmission(tt.operation, runtime.RawExtension{Raw: podRaw}, runtime.RawExtension{}, "")
+			err := handler.cpuOrchestrationPolicyMutatingPod(context.TODO(), req, pod)
+			assert.NoError(t, err)
+			resourceSpec, err := extension.GetResourceSpec(pod.Annotations)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantResourceSpec, resourceSpec)
+			numaTopologySpec, err := extension.GetNUMATopologySpec(pod.Annotations)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNUMATopologySpec, numaTopologySpec)
+		})
+	}
+}
@@ -103,6 +103,11 @@ func (h *PodMutatingHandler) handleCreate(ctx context.Context, req admission.Req
 		return err
 	}
 
+	if err := h.cpuOrchestrationPolicyMutatingPod(ctx, req, obj); err != nil {
+		klog.Errorf("Failed to mutating Pod %s/%s by CPUOrchestrationPolicy, err: %v", obj.Namespace, obj.Name, err)
+		return err
+	}
+
 	if err := h.extendedResourceSpecMutatingPod(ctx, req, obj); err != nil {
 		klog.Errorf("Failed to mutating Pod %s/%s by ExtendedResourceSpec, err: %v", obj.Namespace, obj.Name, err)
 		return err
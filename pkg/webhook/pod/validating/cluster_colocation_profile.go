@@ -52,6 +52,7 @@ func (h *PodValidatingHandler) clusterColocationProfileValidatingPod(ctx context
 		allErrs = append(allErrs, validateImmutableQoSClass(oldPod, newPod)...)
 		allErrs = append(allErrs, validateImmutablePriorityClass(oldPod, newPod)...)
 		allErrs = append(allErrs, validateImmutablePriority(oldPod, newPod)...)
+		allErrs = append(allErrs, validateImmutableCPUOrchestrationPolicy(oldPod, newPod)...)
 	}
 
 	allErrs = append(allErrs, validateRequiredQoSClass(newPod)...)
@@ -101,6 +102,12 @@ func validateImmutablePriority(oldPod, newPod *corev1.Pod) field.ErrorList {
 	return validation.ValidateImmutableField(newPriority, oldPriority, field.NewPath("labels", extension.LabelPodPriority))
 }
 
+func validateImmutableCPUOrchestrationPolicy(oldPod, newPod *corev1.Pod) field.ErrorList {
+	oldPolicy := oldPod.Labels[extension.LabelCPUOrchestrationPolicy]
+	newPolicy := newPod.Labels[extension.LabelCPUOrchestrationPolicy]
+	return validation.ValidateImmutableField(newPolicy, oldPolicy, field.NewPath("labels", extension.LabelCPUOrchestrationPolicy))
+}
+
 func forbidSpecialQoSClassAndPriorityClass(pod *corev1.Pod, qoSClass extension.QoSClass, priorityClasses ...extension.PriorityClass) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if extension.GetPodQoSClassRaw(pod) == qoSClass {
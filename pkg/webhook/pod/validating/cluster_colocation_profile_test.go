@@ -224,6 +224,26 @@ func TestClusterColocationProfileValidatingPod(t *testing.T) {
 			wantAllowed: false,
 			wantReason:  `labels.koordinator.sh/priority: Invalid value: "": field is immutable`,
 		},
+		{
+			name:      "validate immutable CPU orchestration policy",
+			operation: admissionv1.Update,
+			newPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						extension.LabelCPUOrchestrationPolicy: "policy-b",
+					},
+				},
+			},
+			oldPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						extension.LabelCPUOrchestrationPolicy: "policy-a",
+					},
+				},
+			},
+			wantAllowed: false,
+			wantReason:  `labels.koordinator.sh/cpu-orchestration-policy: Invalid value: "policy-b": field is immutable`,
+		},
 		{
 			name:      "allowed QoS and priorityClass combination: BE And NonProd",
 			operation: admissionv1.Create,
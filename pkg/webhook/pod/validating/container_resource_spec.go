@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// validateContainerResourceSpecs validates the CPU bind policies of the containers in the ResourceSpec against the
+// Pod, which conflict with the CPU bind policies of the Pod and require the containers bound to request integer CPUs.
+func validateContainerResourceSpecs(pod *corev1.Pod, resourceSpec *extension.ResourceSpec, fldPath *field.Path) field.ErrorList {
+	if len(resourceSpec.Containers) == 0 {
+		return nil
+	}
+	var allErrs field.ErrorList
+	containersPath := fldPath.Child("containers")
+	if qosClass := extension.GetPodQoSClassRaw(pod); qosClass != extension.QoSLSE && qosClass != extension.QoSLSR {
+		allErrs = append(allErrs, field.Forbidden(containersPath, "only LSE and LSR pods can bind the containers individually"))
+	}
+	if resourceSpec.RequiredCPUBindPolicy != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("requiredCPUBindPolicy"), "conflicts with the cpu bind policies of the containers"))
+	}
+	if resourceSpec.PreferredCPUBindPolicy != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("preferredCPUBindPolicy"), "conflicts with the cpu bind policies of the containers"))
+	}
+	if resourceSpec.CPUBindMode != "" && resourceSpec.CPUBindMode != extension.CPUBindModeHard {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("cpuBindMode"), "conflicts with the cpu bind policies of the containers"))
+	}
+
+	containers := map[string]*corev1.Container{}
+	for i := range pod.Spec.Containers {
+		containers[pod.Spec.Containers[i].Name] = &pod.Spec.Containers[i]
+	}
+	seen := map[string]bool{}
+	var cpuBindPolicy extension.CPUBindPolicy
+	for i, containerSpec := range resourceSpec.Containers {
+		idxPath := containersPath.Index(i)
+		if seen[containerSpec.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), containerSpec.Name))
+			continue
+		}
+		seen[containerSpec.Name] = true
+		container := containers[containerSpec.Name]
+		if container == nil {
+			allErrs = append(allErrs, field.NotFound(idxPath.Child("name"), containerSpec.Name))
+			continue
+		}
+
+		policy := containerSpec.RequiredCPUBindPolicy
+		if policy == "" {
+			continue
+		}
+		if policy == extension.CPUBindPolicyConstrainedBurst {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("requiredCPUBindPolicy"), policy,
+				[]string{string(extension.CPUBindPolicyDefault), string(extension.CPUBindPolicyFullPCPUs), string(extension.CPUBindPolicySpreadByPCPUs)}))
+			continue
+		}
+		if cpuBindPolicy != "" && cpuBindPolicy != policy {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("requiredCPUBindPolicy"), policy, "the containers must require the same cpu bind policy"))
+		}
+		cpuBindPolicy = policy
+		if requestedCPU := container.Resources.Requests.Cpu().MilliValue(); requestedCPU <= 0 || requestedCPU%1000 != 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), containerSpec.Name, "the requested CPUs of the container must be positive integer"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func Test_validateContainerResourceSpecs(t *testing.T) {
+	newPod := func(qosClass extension.QoSClass) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(qosClass),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "main",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+						},
+					},
+					{
+						Name: "sidecar",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		pod          *corev1.Pod
+		resourceSpec *extension.ResourceSpec
+		wantErrs     int
+	}{
+		{
+			name: "no container specs",
+			pod:  newPod(extension.QoSLS),
+			resourceSpec: &extension.ResourceSpec{
+				PreferredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+			},
+		},
+		{
+			name: "bind one container and share the other",
+			pod:  newPod(extension.QoSLSR),
+			resourceSpec: &extension.ResourceSpec{
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+					{Name: "sidecar"},
+				},
+			},
+		},
+		{
+			name: "non-LSR pod",
+			pod:  newPod(extension.QoSLS),
+			resourceSpec: &extension.ResourceSpec{
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+				},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "conflict with the pod policies",
+			pod:  newPod(extension.QoSLSR),
+			resourceSpec: &extension.ResourceSpec{
+				RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+				CPUBindMode:           extension.CPUBindModeBestEffort,
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+				},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "unknown and duplicate containers",
+			pod:  newPod(extension.QoSLSR),
+			resourceSpec: &extension.ResourceSpec{
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+					{Name: "main"},
+					{Name: "unknown"},
+				},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "mixed policies and non-integer CPUs",
+			pod:  newPod(extension.QoSLSR),
+			resourceSpec: &extension.ResourceSpec{
+				Containers: []extension.ContainerResourceSpec{
+					{Name: "main", RequiredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs},
+					{Name: "sidecar", RequiredCPUBindPolicy: extension.CPUBindPolicySpreadByPCPUs},
+				},
+			},
+			wantErrs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateContainerResourceSpecs(tt.pod, tt.resourceSpec, field.NewPath("spec"))
+			assert.Len(t, errs, tt.wantErrs, errs)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/annotation"
+)
+
+func (h *PodValidatingHandler) extensionAnnotationValidatingPod(ctx context.Context, req admission.Request) (bool, string, error) {
+	newPod := &corev1.Pod{}
+	oldPod := &corev1.Pod{}
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.Decoder.DecodeRaw(req.Object, newPod); err != nil {
+			return false, "", err
+		}
+	case admissionv1.Update:
+		if err := h.Decoder.DecodeRaw(req.OldObject, oldPod); err != nil {
+			return false, "", err
+		}
+		if err := h.Decoder.DecodeRaw(req.Object, newPod); err != nil {
+			return false, "", err
+		}
+	default:
+		return true, "", nil
+	}
+
+	allErrs := validateExtensionAnnotations(oldPod, newPod)
+	if err := allErrs.ToAggregate(); err != nil {
+		return false, err.Error(), nil
+	}
+	return true, "", nil
+}
+
+// validateExtensionAnnotations validates the added or changed extension annotations strictly, so the pods created
+// before the validation are not blocked from updating.
+func validateExtensionAnnotations(oldPod, newPod *corev1.Pod) field.ErrorList {
+	changed := map[string]string{}
+	for key, value := range newPod.Annotations {
+		if oldValue, ok := oldPod.Annotations[key]; ok && oldValue == value {
+			continue
+		}
+		changed[key] = value
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	fldPath := field.NewPath("metadata", "annotations")
+	allErrs := annotation.Validate(changed, fldPath)
+	if _, ok := changed[extension.AnnotationResourceSpec]; ok && len(allErrs) == 0 {
+		resourceSpec, err := extension.GetResourceSpec(newPod.Annotations)
+		if err == nil {
+			allErrs = append(allErrs, validateContainerResourceSpecs(newPod, resourceSpec, fldPath.Key(extension.AnnotationResourceSpec))...)
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func TestExtensionAnnotationValidatingPod(t *testing.T) {
+	defer feature.SetFeatureGateDuringTest(t, feature.DefaultMutableFeatureGate, features.ExtensionAnnotationValidatingWebhook, true)()
+
+	validAnnotations := map[string]string{
+		extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy":"FullPCPUs"}`,
+	}
+	invalidAnnotations := map[string]string{
+		extension.AnnotationResourceSpec: `{"preferredCPUBindPolicy":"Unknown"}`,
+	}
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		oldPod    *corev1.Pod
+		newPod    *corev1.Pod
+		allowed   bool
+	}{
+		{
+			name:      "create pod with valid annotations",
+			operation: admissionv1.Create,
+			newPod:    makeAnnotatedPod(validAnnotations),
+			allowed:   true,
+		},
+		{
+			name:      "create pod with invalid annotations",
+			operation: admissionv1.Create,
+			newPod:    makeAnnotatedPod(invalidAnnotations),
+			allowed:   false,
+		},
+		{
+			name:      "update pod to invalid annotations",
+			operation: admissionv1.Update,
+			oldPod:    makeAnnotatedPod(validAnnotations),
+			newPod:    makeAnnotatedPod(invalidAnnotations),
+			allowed:   false,
+		},
+		{
+			name:      "update pod with unchanged invalid annotations",
+			operation: admissionv1.Update,
+			oldPod:    makeAnnotatedPod(invalidAnnotations),
+			newPod: makeAnnotatedPod(map[string]string{
+				extension.AnnotationResourceSpec:   `{"preferredCPUBindPolicy":"Unknown"}`,
+				extension.AnnotationResourceStatus: `{"cpuset":"0-3"}`,
+			}),
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeTestHandler()
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("pods"),
+					Operation: tt.operation,
+					Object: runtime.RawExtension{
+						Raw: mustMarshal(t, tt.newPod),
+					},
+				},
+			}
+			if tt.oldPod != nil {
+				req.OldObject = runtime.RawExtension{
+					Raw: mustMarshal(t, tt.oldPod),
+				}
+			}
+
+			allowed, reason, err := handler.extensionAnnotationValidatingPod(context.TODO(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.allowed, allowed, reason)
+
+			response := handler.Handle(context.TODO(), req)
+			assert.Equal(t, tt.allowed, response.Allowed)
+		})
+	}
+}
+
+func makeAnnotatedPod(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-pod",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+	}
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	data, err := json.Marshal(obj)
+	assert.NoError(t, err)
+	return data
+}
@@ -26,6 +26,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 	"github.com/koordinator-sh/koordinator/pkg/webhook/elasticquota"
 )
 
@@ -59,6 +61,9 @@ func (h *PodValidatingHandler) validatingPodFn(ctx context.Context, req admissio
 	}
 
 	allowed, reason, err = h.clusterColocationProfileValidatingPod(ctx, req)
+	if err == nil && allowed && utilfeature.DefaultFeatureGate.Enabled(features.ExtensionAnnotationValidatingWebhook) {
+		allowed, reason, err = h.extensionAnnotationValidatingPod(ctx, req)
+	}
 	if err == nil {
 		plugin := elasticquota.NewPlugin(h.Decoder, h.Client)
 		if err = plugin.ValidatePod(ctx, req); err != nil {
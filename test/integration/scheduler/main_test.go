@@ -0,0 +1,286 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	topologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/koordinator-sh/koordinator/cmd/koord-scheduler/app"
+	"github.com/koordinator-sh/koordinator/cmd/koord-scheduler/app/options"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/defaultprebind"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/deviceshare"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/reservation"
+
+	// Ensure scheme package is initialized.
+	_ "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/scheme"
+)
+
+const (
+	testSchedulerName = "koord-scheduler"
+
+	schedulerConfigTemplate = `apiVersion: kubescheduler.config.k8s.io/v1beta2
+kind: KubeSchedulerConfiguration
+leaderElection:
+  leaderElect: false
+clientConnection:
+  kubeconfig: %s
+podInitialBackoffSeconds: 1
+podMaxBackoffSeconds: 1
+profiles:
+  - schedulerName: %s
+    plugins:
+      preFilter:
+        enabled:
+          - name: Reservation
+          - name: NodeNUMAResource
+          - name: DeviceShare
+      filter:
+        enabled:
+          - name: NodeNUMAResource
+          - name: DeviceShare
+          - name: Reservation
+      postFilter:
+        disabled:
+          - name: "*"
+        enabled:
+          - name: Reservation
+          - name: DefaultPreemption
+      preScore:
+        enabled:
+          - name: Reservation
+      score:
+        enabled:
+          - name: NodeNUMAResource
+            weight: 1
+          - name: DeviceShare
+            weight: 1
+          - name: Reservation
+            weight: 5000
+      reserve:
+        enabled:
+          - name: NodeNUMAResource
+          - name: DeviceShare
+      preBind:
+        enabled:
+          - name: NodeNUMAResource
+          - name: DeviceShare
+          - name: Reservation
+          - name: %s
+          - name: DefaultPreBind
+      bind:
+        disabled:
+          - name: "*"
+        enabled:
+          - name: Reservation
+          - name: DefaultBinder
+`
+)
+
+var (
+	kubeClient     kubernetes.Interface
+	koordClient    koordclientset.Interface
+	topologyClient topologyclientset.Interface
+
+	testConfig    *rest.Config
+	testDir       string
+	testScheduler *schedulerInstance
+)
+
+// schedulerInstance is a running koord-scheduler, which can be stopped to simulate a restart.
+type schedulerInstance struct {
+	cancel               context.CancelFunc
+	done                 chan struct{}
+	sched                *scheduler.Scheduler
+	koordInformerFactory koordinatorinformers.SharedInformerFactory
+	numaPlugin           *nodenumaresource.Plugin
+}
+
+// stop stops the scheduling loop and the informers of the scheduler. The plugin controllers
+// can not be stopped, but they work on the stopped informers and do nothing then.
+func (s *schedulerInstance) stop() {
+	s.cancel()
+	<-s.done
+}
+
+// TestMain starts a kube-apiserver and etcd with envtest, installs the koordinator CRDs
+// and runs a koord-scheduler against them, which is shared by all tests in this package.
+// The tests are skipped if the envtest binaries are not available (KUBEBUILDER_ASSETS is not set).
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		klog.Info("KUBEBUILDER_ASSETS is not set, skip the scheduler integration tests")
+		os.Exit(0)
+	}
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		klog.Errorf("failed to start test environment, err: %v", err)
+		return 1
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			klog.Errorf("failed to stop test environment, err: %v", err)
+		}
+	}()
+
+	kubeClient = kubernetes.NewForConfigOrDie(cfg)
+	koordClient = koordclientset.NewForConfigOrDie(cfg)
+	topologyClient = topologyclientset.NewForConfigOrDie(cfg)
+
+	tmpDir, err := os.MkdirTemp("", "koord-scheduler-integration")
+	if err != nil {
+		klog.Errorf("failed to create temp dir, err: %v", err)
+		return 1
+	}
+	defer os.RemoveAll(tmpDir)
+	testConfig, testDir = cfg, tmpDir
+
+	testScheduler, err = startScheduler(cfg, tmpDir)
+	if err != nil {
+		klog.Errorf("failed to start koord-scheduler, err: %v", err)
+		return 1
+	}
+	defer func() {
+		testScheduler.stop()
+	}()
+	if err := createPriorityClasses(context.TODO()); err != nil {
+		klog.Errorf("failed to create priority classes, err: %v", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// restartScheduler stops the running koord-scheduler and starts a new one, which restores its
+// state from the API server like a restarted scheduler process.
+func restartScheduler(t *testing.T) {
+	testScheduler.stop()
+	s, err := startScheduler(testConfig, testDir)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	testScheduler = s
+}
+
+func startScheduler(cfg *rest.Config, dir string) (*schedulerInstance, error) {
+	kubeConfigPath := filepath.Join(dir, "kubeconfig")
+	if err := writeKubeConfig(cfg, kubeConfigPath); err != nil {
+		return nil, err
+	}
+	configPath := filepath.Join(dir, "scheduler-config.yaml")
+	config := fmt.Sprintf(schedulerConfigTemplate, kubeConfigPath, testSchedulerName, failPreBindPluginName)
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		return nil, err
+	}
+
+	opts := options.NewOptions()
+	opts.ConfigFile = configPath
+	// disable the secure serving and hence the delegated authn/authz
+	opts.SecureServing.BindPort = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &schedulerInstance{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	newNUMAPlugin := func(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		p, err := nodenumaresource.New(args, handle)
+		if err != nil {
+			return nil, err
+		}
+		s.numaPlugin = p.(*nodenumaresource.Plugin)
+		return p, nil
+	}
+	cc, sched, extenderFactory, err := app.Setup(ctx, opts,
+		app.WithPlugin(nodenumaresource.Name, newNUMAPlugin),
+		app.WithPlugin(reservation.Name, reservation.New),
+		app.WithPlugin(deviceshare.Name, deviceshare.New),
+		app.WithPlugin(defaultprebind.Name, defaultprebind.New),
+		app.WithPlugin(failPreBindPluginName, newFailPreBindPlugin),
+	)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s.sched = sched
+	s.koordInformerFactory = cc.KoordinatorSharedInformerFactory
+
+	cc.EventBroadcaster.StartRecordingToSink(ctx.Done())
+
+	cc.InformerFactory.Start(ctx.Done())
+	if cc.DynInformerFactory != nil {
+		cc.DynInformerFactory.Start(ctx.Done())
+	}
+	cc.KoordinatorSharedInformerFactory.Start(ctx.Done())
+
+	cc.InformerFactory.WaitForCacheSync(ctx.Done())
+	if cc.DynInformerFactory != nil {
+		cc.DynInformerFactory.WaitForCacheSync(ctx.Done())
+	}
+	cc.KoordinatorSharedInformerFactory.WaitForCacheSync(ctx.Done())
+
+	go extenderFactory.Run()
+	go func() {
+		defer close(s.done)
+		sched.Run(ctx)
+	}()
+	return s, nil
+}
+
+func writeKubeConfig(cfg *rest.Config, path string) error {
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Clusters["envtest"] = &clientcmdapi.Cluster{
+		Server:                   cfg.Host,
+		CertificateAuthorityData: cfg.CAData,
+	}
+	kubeConfig.AuthInfos["envtest"] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: cfg.CertData,
+		ClientKeyData:         cfg.KeyData,
+		Token:                 cfg.BearerToken,
+	}
+	kubeConfig.Contexts["envtest"] = &clientcmdapi.Context{
+		Cluster:  "envtest",
+		AuthInfo: "envtest",
+	}
+	kubeConfig.CurrentContext = "envtest"
+	return clientcmd.WriteToFile(*kubeConfig, path)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// assertCPUSetAllocated checks the pod is bound to the expected number of CPUs in a single NUMA node.
+func assertCPUSetAllocated(t *testing.T, pod *corev1.Pod, expectedCPUs int) {
+	resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+	assert.NoError(t, err)
+	cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedCPUs, cpus.Size())
+
+	if assert.Len(t, resourceStatus.NUMANodeResources, 1) {
+		numaNode := int(resourceStatus.NUMANodeResources[0].Node)
+		cpusPerNUMANode := testNodeCPUs / testNodeNUMANodes
+		numaNodeCPUs := cpuset.NewCPUSet()
+		for i := numaNode * cpusPerNUMANode; i < (numaNode+1)*cpusPerNUMANode; i++ {
+			numaNodeCPUs = numaNodeCPUs.Union(cpuset.NewCPUSet(i))
+		}
+		assert.True(t, cpus.IsSubsetOf(numaNodeCPUs), "cpuset %s is not in NUMA node %d", cpus, numaNode)
+	}
+}
+
+// assertGPUAllocated checks the pod is allocated the only GPU of the test node.
+func assertGPUAllocated(t *testing.T, pod *corev1.Pod) {
+	allocations, err := extension.GetDeviceAllocations(pod.Annotations)
+	assert.NoError(t, err)
+	if assert.Len(t, allocations[schedulingv1alpha1.GPU], 1) {
+		assert.Equal(t, int32(0), allocations[schedulingv1alpha1.GPU][0].Minor)
+	}
+}
+
+func TestScheduleNUMAAndDevice(t *testing.T) {
+	nodeName := setupTestNode(t)
+
+	pod := newTestPod("numa-device", nodeName, gpuRequests("4", "4Gi"),
+		withPriorityClass(priorityClassProd), withQoSClass(extension.QoSLSR))
+	createPod(t, pod)
+
+	scheduledPod := waitForPodScheduled(t, pod.Namespace, pod.Name)
+	assert.Equal(t, nodeName, scheduledPod.Spec.NodeName)
+	assertCPUSetAllocated(t, scheduledPod, 4)
+	assertGPUAllocated(t, scheduledPod)
+
+	// the only GPU is allocated, the following pod cannot be scheduled
+	pendingPod := newTestPod("numa-device-pending", nodeName, gpuRequests("1", "1Gi"))
+	createPod(t, pendingPod)
+	waitForPodUnschedulable(t, pendingPod.Namespace, pendingPod.Name)
+}
+
+func TestUnreserveOnPreBindFailure(t *testing.T) {
+	nodeName := setupTestNode(t)
+
+	// the pod requests all CPUs of a NUMA node and the only GPU, so the retry after
+	// the PreBind failure succeeds only if the Unreserve releases the allocated resources.
+	pod := newTestPod("unreserve", nodeName, gpuRequests("8", "4Gi"),
+		withPriorityClass(priorityClassProd), withQoSClass(extension.QoSLSR),
+		withLabels(map[string]string{labelFailPreBindOnce: "true"}))
+	created := createPod(t, pod)
+
+	scheduledPod := waitForPodScheduled(t, pod.Namespace, pod.Name)
+	assert.True(t, failPreBindPluginInstance.hasFailed(created.UID), "PreBind failure is not injected")
+	assert.Equal(t, nodeName, scheduledPod.Spec.NodeName)
+	assertCPUSetAllocated(t, scheduledPod, 8)
+	assertGPUAllocated(t, scheduledPod)
+}
+
+func TestReservationRestore(t *testing.T) {
+	nodeName := setupTestNode(t)
+
+	ownerLabels := map[string]string{"app": "reservation-owner"}
+	r := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "reservation-" + nodeName,
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+				},
+				Spec: newTestPod("", nodeName, gpuRequests("2", "2Gi")).Spec,
+			},
+			Owners: []schedulingv1alpha1.ReservationOwner{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: ownerLabels,
+					},
+				},
+			},
+			TTL:          &metav1.Duration{Duration: time.Hour},
+			AllocateOnce: pointer.Bool(true),
+		},
+	}
+	createReservation(t, r)
+
+	available := waitForReservationAvailable(t, r.Name)
+	assert.Equal(t, nodeName, available.Status.NodeName)
+
+	// the restarted scheduler restores the reservation from the API server
+	restartScheduler(t)
+	waitForNodeObserved(t, nodeName)
+
+	// the GPU is held by the reservation, the pod which is not the owner cannot be scheduled
+	nonOwnerPod := newTestPod("reservation-non-owner", nodeName, gpuRequests("1", "1Gi"))
+	createPod(t, nonOwnerPod)
+	waitForPodUnschedulable(t, nonOwnerPod.Namespace, nonOwnerPod.Name)
+
+	ownerPod := newTestPod("reservation-owner", nodeName, gpuRequests("2", "2Gi"), withLabels(ownerLabels))
+	createPod(t, ownerPod)
+
+	scheduledPod := waitForPodScheduled(t, ownerPod.Namespace, ownerPod.Name)
+	assert.Equal(t, nodeName, scheduledPod.Spec.NodeName)
+	reservationAllocated, err := extension.GetReservationAllocated(scheduledPod)
+	assert.NoError(t, err)
+	if assert.NotNil(t, reservationAllocated) {
+		assert.Equal(t, r.Name, reservationAllocated.Name)
+		assert.Equal(t, available.UID, reservationAllocated.UID)
+	}
+	assertGPUAllocated(t, scheduledPod)
+}
+
+func TestPreemption(t *testing.T) {
+	nodeName := setupTestNode(t)
+
+	victim := newTestPod("victim", nodeName, gpuRequests("1", "1Gi"), withPriorityClass(priorityClassBatch))
+	createPod(t, victim)
+	waitForPodScheduled(t, victim.Namespace, victim.Name)
+
+	preemptor := newTestPod("preemptor", nodeName, gpuRequests("4", "4Gi"),
+		withPriorityClass(priorityClassProd), withQoSClass(extension.QoSLSR))
+	createPod(t, preemptor)
+
+	waitForPodEvicted(t, victim.Namespace, victim.Name)
+	scheduledPod := waitForPodScheduled(t, preemptor.Namespace, preemptor.Name)
+	assert.Equal(t, nodeName, scheduledPod.Spec.NodeName)
+	assertCPUSetAllocated(t, scheduledPod, 4)
+	assertGPUAllocated(t, scheduledPod)
+}
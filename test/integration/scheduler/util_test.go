@@ -0,0 +1,409 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+const (
+	testNamespace = "default"
+
+	// testNodeCPUs is the number of CPUs of the test node, which are evenly distributed in two NUMA nodes.
+	testNodeCPUs      = 16
+	testNodeNUMANodes = 2
+
+	priorityClassProd  = "koord-prod"
+	priorityClassBatch = "koord-batch"
+
+	labelTestNode = "integration.koordinator.sh/node"
+
+	// labelFailPreBindOnce marks the pods whose first PreBind should fail.
+	labelFailPreBindOnce  = "integration.koordinator.sh/fail-prebind-once"
+	failPreBindPluginName = "IntegrationFailPreBindOnce"
+
+	pollInterval = 100 * time.Millisecond
+	pollTimeout  = 30 * time.Second
+)
+
+var failPreBindPluginInstance = &failPreBindPlugin{}
+
+var _ framework.PreBindPlugin = &failPreBindPlugin{}
+
+// failPreBindPlugin fails the first PreBind of the pods labeled with labelFailPreBindOnce,
+// which makes the scheduler run the Unreserve of all the reserve plugins.
+type failPreBindPlugin struct {
+	failed sync.Map
+}
+
+func newFailPreBindPlugin(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+	return failPreBindPluginInstance, nil
+}
+
+func (p *failPreBindPlugin) Name() string {
+	return failPreBindPluginName
+}
+
+func (p *failPreBindPlugin) PreBind(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	if pod.Labels[labelFailPreBindOnce] != "true" {
+		return nil
+	}
+	if _, loaded := p.failed.LoadOrStore(pod.UID, struct{}{}); loaded {
+		return nil
+	}
+	return framework.NewStatus(framework.Error, "injected PreBind failure")
+}
+
+func (p *failPreBindPlugin) hasFailed(uid types.UID) bool {
+	_, ok := p.failed.Load(uid)
+	return ok
+}
+
+func createPriorityClasses(ctx context.Context) error {
+	priorityClasses := map[string]int32{
+		priorityClassProd:  extension.PriorityProdValueMin,
+		priorityClassBatch: extension.PriorityBatchValueMin,
+	}
+	for name, value := range priorityClasses {
+		priorityClass := &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Value:      value,
+		}
+		_, err := kubeClient.SchedulingV1().PriorityClasses().Create(ctx, priorityClass, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// testNodeName returns a node name which is unique for each test.
+func testNodeName(t *testing.T) string {
+	return "node-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
+}
+
+// setupTestNode creates a node with 2 NUMA nodes and 1 GPU, which is reported by the
+// Node, NodeResourceTopology and Device objects. All of them are deleted when the test finishes.
+func setupTestNode(t *testing.T) string {
+	ctx := context.TODO()
+	nodeName := testNodeName(t)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+			Labels: map[string]string{
+				corev1.LabelHostname:              nodeName,
+				labelTestNode:                     nodeName,
+				extension.LabelNUMATopologyPolicy: string(extension.NUMATopologyPolicySingleNUMANode),
+			},
+		},
+	}
+	node, err := kubeClient.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = kubeClient.CoreV1().Nodes().Delete(context.TODO(), nodeName, metav1.DeleteOptions{})
+	})
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:               *resource.NewQuantity(testNodeCPUs, resource.DecimalSI),
+		corev1.ResourceMemory:            resource.MustParse("32Gi"),
+		corev1.ResourcePods:              resource.MustParse("110"),
+		extension.ResourceGPUCore:        resource.MustParse("100"),
+		extension.ResourceGPUMemoryRatio: resource.MustParse("100"),
+		extension.ResourceGPUMemory:      resource.MustParse("16Gi"),
+	}
+	node.Status = corev1.NodeStatus{
+		Capacity:    allocatable,
+		Allocatable: allocatable,
+		Conditions: []corev1.NodeCondition{
+			{
+				Type:   corev1.NodeReady,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+	_, err = kubeClient.CoreV1().Nodes().UpdateStatus(ctx, node, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	cpuTopology := &extension.CPUTopology{}
+	cpusPerNUMANode := testNodeCPUs / testNodeNUMANodes
+	for i := 0; i < testNodeCPUs; i++ {
+		cpuTopology.Detail = append(cpuTopology.Detail, extension.CPUInfo{
+			ID:     int32(i),
+			Core:   int32(i / 2),
+			Socket: 0,
+			Node:   int32(i / cpusPerNUMANode),
+		})
+	}
+	cpuTopologyData, err := json.Marshal(cpuTopology)
+	assert.NoError(t, err)
+	nrt := &nrtv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+			Annotations: map[string]string{
+				extension.AnnotationNodeCPUTopology: string(cpuTopologyData),
+			},
+		},
+		TopologyPolicies: []string{string(nrtv1alpha1.None)},
+	}
+	for i := 0; i < testNodeNUMANodes; i++ {
+		nrt.Zones = append(nrt.Zones, nrtv1alpha1.Zone{
+			Name: fmt.Sprintf("node-%d", i),
+			Type: "Node",
+			Resources: nrtv1alpha1.ResourceInfoList{
+				newResourceInfo(string(corev1.ResourceCPU), *resource.NewQuantity(int64(cpusPerNUMANode), resource.DecimalSI)),
+				newResourceInfo(string(corev1.ResourceMemory), resource.MustParse("16Gi")),
+			},
+		})
+	}
+	_, err = topologyClient.TopologyV1alpha1().NodeResourceTopologies().Create(ctx, nrt, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = topologyClient.TopologyV1alpha1().NodeResourceTopologies().Delete(context.TODO(), nodeName, metav1.DeleteOptions{})
+	})
+
+	device := &schedulingv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+		},
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{
+					Type:   schedulingv1alpha1.GPU,
+					UUID:   "GPU-" + nodeName + "-0",
+					Minor:  pointer.Int32(0),
+					Health: true,
+					Resources: corev1.ResourceList{
+						extension.ResourceGPUCore:        resource.MustParse("100"),
+						extension.ResourceGPUMemoryRatio: resource.MustParse("100"),
+						extension.ResourceGPUMemory:      resource.MustParse("16Gi"),
+					},
+					Topology: &schedulingv1alpha1.DeviceTopology{
+						SocketID: 0,
+						NodeID:   0,
+						PCIEID:   0,
+					},
+				},
+			},
+		},
+	}
+	_, err = koordClient.SchedulingV1alpha1().Devices().Create(ctx, device, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = koordClient.SchedulingV1alpha1().Devices().Delete(context.TODO(), nodeName, metav1.DeleteOptions{})
+	})
+
+	waitForNodeObserved(t, nodeName)
+	return nodeName
+}
+
+// waitForNodeObserved waits for the scheduler to observe the node with its NUMA topology and devices.
+func waitForNodeObserved(t *testing.T, nodeName string) {
+	err := wait.PollImmediate(pollInterval, pollTimeout, func() (bool, error) {
+		nodeInfo := testScheduler.sched.Cache.Dump().Nodes[nodeName]
+		if nodeInfo == nil || nodeInfo.Node() == nil || nodeInfo.Allocatable.MilliCPU == 0 {
+			return false, nil
+		}
+		topologyOptions := testScheduler.numaPlugin.GetTopologyOptionsManager().GetTopologyOptions(nodeName)
+		if len(topologyOptions.NUMANodeResources) != testNodeNUMANodes {
+			return false, nil
+		}
+		_, err := testScheduler.koordInformerFactory.Scheduling().V1alpha1().Devices().Lister().Get(nodeName)
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	assert.NoError(t, err, "node %s is not observed by the scheduler", nodeName)
+}
+
+func newResourceInfo(name string, quantity resource.Quantity) nrtv1alpha1.ResourceInfo {
+	return nrtv1alpha1.ResourceInfo{
+		Name:        name,
+		Capacity:    quantity,
+		Allocatable: quantity,
+		Available:   quantity,
+	}
+}
+
+type podOption func(pod *corev1.Pod)
+
+func withLabels(labels map[string]string) podOption {
+	return func(pod *corev1.Pod) {
+		for k, v := range labels {
+			pod.Labels[k] = v
+		}
+	}
+}
+
+func withPriorityClass(priorityClassName string) podOption {
+	return func(pod *corev1.Pod) {
+		pod.Spec.PriorityClassName = priorityClassName
+	}
+}
+
+func withQoSClass(qosClass extension.QoSClass) podOption {
+	return func(pod *corev1.Pod) {
+		pod.Labels[extension.LabelPodQoS] = string(qosClass)
+	}
+}
+
+func gpuRequests(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:               resource.MustParse(cpu),
+		corev1.ResourceMemory:            resource.MustParse(memory),
+		extension.ResourceGPUCore:        resource.MustParse("100"),
+		extension.ResourceGPUMemoryRatio: resource.MustParse("100"),
+	}
+}
+
+func newTestPod(name, nodeName string, requests corev1.ResourceList, opts ...podOption) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+			Labels:    map[string]string{},
+		},
+		Spec: corev1.PodSpec{
+			SchedulerName:                 testSchedulerName,
+			NodeSelector:                  map[string]string{labelTestNode: nodeName},
+			TerminationGracePeriodSeconds: pointer.Int64(0),
+			Containers: []corev1.Container{
+				{
+					Name:  "main",
+					Image: "busybox",
+					Resources: corev1.ResourceRequirements{
+						Limits:   requests,
+						Requests: requests,
+					},
+				},
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(pod)
+	}
+	return pod
+}
+
+func createPod(t *testing.T, pod *corev1.Pod) *corev1.Pod {
+	created, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{
+			GracePeriodSeconds: pointer.Int64(0),
+		})
+	})
+	return created
+}
+
+func createReservation(t *testing.T, r *schedulingv1alpha1.Reservation) *schedulingv1alpha1.Reservation {
+	created, err := koordClient.SchedulingV1alpha1().Reservations().Create(context.TODO(), r, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = koordClient.SchedulingV1alpha1().Reservations().Delete(context.TODO(), r.Name, metav1.DeleteOptions{})
+	})
+	return created
+}
+
+func waitForPodScheduled(t *testing.T, namespace, name string) *corev1.Pod {
+	var pod *corev1.Pod
+	err := wait.PollImmediate(pollInterval, pollTimeout, func() (bool, error) {
+		var err error
+		pod, err = kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return pod.Spec.NodeName != "", nil
+	})
+	assert.NoError(t, err, "pod %s/%s is not scheduled", namespace, name)
+	return pod
+}
+
+func waitForPodUnschedulable(t *testing.T, namespace, name string) {
+	err := wait.PollImmediate(pollInterval, pollTimeout, func() (bool, error) {
+		pod, err := kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse &&
+				cond.Reason == corev1.PodReasonUnschedulable {
+				return pod.Spec.NodeName == "", nil
+			}
+		}
+		return false, nil
+	})
+	assert.NoError(t, err, "pod %s/%s is not marked unschedulable", namespace, name)
+}
+
+// waitForPodEvicted waits for the pod to be deleted or terminating. A terminating pod is
+// force deleted since there is no kubelet to finish the graceful termination.
+func waitForPodEvicted(t *testing.T, namespace, name string) {
+	err := wait.PollImmediate(pollInterval, pollTimeout, func() (bool, error) {
+		pod, err := kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if pod.DeletionTimestamp == nil {
+			return false, nil
+		}
+		err = kubeClient.CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{
+			GracePeriodSeconds: pointer.Int64(0),
+		})
+		return err == nil || errors.IsNotFound(err), nil
+	})
+	assert.NoError(t, err, "pod %s/%s is not evicted", namespace, name)
+}
+
+func waitForReservationAvailable(t *testing.T, name string) *schedulingv1alpha1.Reservation {
+	var r *schedulingv1alpha1.Reservation
+	err := wait.PollImmediate(pollInterval, pollTimeout, func() (bool, error) {
+		var err error
+		r, err = koordClient.SchedulingV1alpha1().Reservations().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return r.Status.Phase == schedulingv1alpha1.ReservationAvailable && r.Status.NodeName != "", nil
+	})
+	assert.NoError(t, err, "reservation %s is not available", name)
+	return r
+}